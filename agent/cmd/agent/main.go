@@ -17,6 +17,7 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/auth"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/cleanup"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/crash"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/jobs"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/metrics"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/version"
@@ -32,6 +33,7 @@ type agentConfig struct {
 	listenInterface    string // Network interface to bind to
 	heartbeatInterval  int    // Interval between heartbeats in seconds
 	claimCode          string // Unique code for agent registration
+	bootstrapToken     string // Token of an agent pre-registered by provisioning automation
 	debug              bool   // Enable debug logging
 	hashcatExtraParams string // Extra parameters to pass to hashcat (e.g., "-O -w 3")
 	configDir          string // Configuration directory for certificates and credentials
@@ -114,6 +116,11 @@ func loadConfig(cfg agentConfig) agentConfig {
 		cfg.claimCode = envMap["KH_CLAIM_CODE"]
 	}
 	
+	// Bootstrap token (read only; never written back to .env)
+	if cfg.bootstrapToken == "" && envFileExists {
+		cfg.bootstrapToken = envMap["KH_BOOTSTRAP_TOKEN"]
+	}
+
 	// Debug setting
 	if !cfg.debug && envFileExists {
 		cfg.debug = envMap["DEBUG"] == "true"
@@ -148,6 +155,11 @@ func loadConfig(cfg agentConfig) agentConfig {
 		cfg.dataDir = filepath.Join(cwd, "data")
 	}
 
+	// Hashes and plaintexts are masked in logs unless LOG_REDACT_HASHES is false
+	if _, set := os.LookupEnv("LOG_REDACT_HASHES"); !set && envMap["LOG_REDACT_HASHES"] != "" {
+		os.Setenv("LOG_REDACT_HASHES", envMap["LOG_REDACT_HASHES"])
+	}
+
 	// Reinitialize debug after loading configuration
 	if cfg.debug {
 		os.Setenv("DEBUG", "true")
@@ -282,9 +294,17 @@ KH_DOWNLOAD_TIMEOUT=%s        # Timeout for large file downloads
 # Extra parameters to pass to hashcat (e.g., "-O -w 3" for optimized kernels and high workload)
 HASHCAT_EXTRA_PARAMS=%s
 
+# Hashcat Sandbox (Linux only, all optional)
+# KH_HASHCAT_USER=hashcat         # Run hashcat as this unprivileged user, needs the agent to run as root
+# KH_HASHCAT_SANDBOX=bwrap        # Run hashcat under bubblewrap with only the data directory writable
+# KH_HASHCAT_MEMORY_MAX_MB=16384  # Memory cap of each hashcat run
+# KH_HASHCAT_CPU_MAX=4            # CPUs each hashcat run may use
+# KH_HASHCAT_CGROUP=/sys/fs/cgroup/krakenhashes-hashcat  # cgroup v2 directory the caps are applied in
+
 # Logging Configuration
 DEBUG=%s
 LOG_LEVEL=%s
+LOG_REDACT_HASHES=%s  # Mask hashes and cracked plaintexts in logs, false logs them in full
 `, 
 		time.Now().Format(time.RFC3339),
 		finalEnv["KH_HOST"],
@@ -299,13 +319,28 @@ LOG_LEVEL=%s
 		getEnvOrDefault(finalEnv, "KH_DOWNLOAD_TIMEOUT", "1h"),
 		finalEnv["HASHCAT_EXTRA_PARAMS"],
 		finalEnv["DEBUG"],
-		getEnvOrDefault(finalEnv, "LOG_LEVEL", "DEBUG"))
+		getEnvOrDefault(finalEnv, "LOG_LEVEL", "DEBUG"),
+		getEnvOrDefault(finalEnv, "LOG_REDACT_HASHES", "true"))
 
 	if err := os.WriteFile(".env", []byte(env), 0644); err != nil {
 		log.Printf("Warning: Could not save configuration to .env file: %v", err)
 	}
 }
 
+// registerAgent registers the agent with its bootstrap token if one was given,
+// otherwise with its claim code
+func registerAgent(cfg agentConfig, urlConfig *config.URLConfig) error {
+	if cfg.bootstrapToken != "" {
+		debug.Info("Starting bootstrap process with bootstrap token")
+		console.Status("Bootstrapping pre-registered agent...")
+		return agent.BootstrapAgent(cfg.bootstrapToken, urlConfig)
+	}
+
+	debug.Info("Starting registration process with claim code")
+	console.Status("Registering agent with claim code...")
+	return agent.RegisterAgent(cfg.claimCode, urlConfig)
+}
+
 // formatClaimCode formats the claim code line, commenting it out if already used
 func formatClaimCode(claimCode string) string {
 	if claimCode == "" {
@@ -395,6 +430,7 @@ func main() {
 	flag.StringVar(&cfg.listenInterface, "interface", "", "Network interface to listen on (optional)")
 	flag.IntVar(&cfg.heartbeatInterval, "heartbeat", 0, "Heartbeat interval in seconds (default: 5)")
 	flag.StringVar(&cfg.claimCode, "claim", "", "Agent claim code (required only for first-time registration)")
+	flag.StringVar(&cfg.bootstrapToken, "bootstrap-token", "", "Bootstrap token of a pre-registered agent (alternative to --claim; ignored once credentials exist)")
 	flag.BoolVar(&cfg.debug, "debug", false, "Enable debug logging (default: false)")
 	flag.StringVar(&cfg.hashcatExtraParams, "hashcat-params", "", "Extra parameters to pass to hashcat (e.g., '-O -w 3')")
 	flag.StringVar(&cfg.configDir, "config-dir", "", "Configuration directory for certificates and credentials")
@@ -555,6 +591,11 @@ func main() {
 	}
 	debug.Info("Data directories initialized successfully at %s", dataDirs.Binaries)
 
+	// Collect crash reports, including one left by the previous run
+	if err := crash.Init(filepath.Join(config.GetConfigDir(), "crash"), version.GetVersion()); err != nil {
+		debug.Warning("Crash reporting disabled: %v", err)
+	}
+
 	// Create metrics collector
 	collector, err := metrics.New(metrics.Config{
 		CollectionInterval: time.Duration(cfg.heartbeatInterval) * time.Second,
@@ -591,16 +632,13 @@ func main() {
 				os.Exit(1)
 			}
 			console.Success("Certificates renewed successfully")
-		} else if cfg.claimCode == "" {
+		} else if cfg.claimCode == "" && cfg.bootstrapToken == "" {
 			debug.Error("Claim code required for first-time registration")
-			console.Error("No existing credentials found. Please provide a claim code with --claim flag for first-time registration")
+			console.Error("No existing credentials found. Please provide a claim code with --claim flag (or a bootstrap token with --bootstrap-token) for first-time registration")
 			os.Exit(1)
 		} else {
-			debug.Info("Starting registration process with claim code")
-			console.Status("Registering agent with claim code...")
-
 			// Attempt registration
-			if err := agent.RegisterAgent(cfg.claimCode, urlConfig); err != nil {
+			if err := registerAgent(cfg, urlConfig); err != nil {
 				debug.Error("Failed to register agent: %v", err)
 				console.Error("Failed to register agent: %v", err)
 				os.Exit(1)
@@ -623,16 +661,14 @@ func main() {
 		}
 	} else if agentID == "" || cert == "" {
 		debug.Error("Loaded credentials are empty - Agent ID: %v, Certificate: %v", agentID != "", cert != "")
-		if cfg.claimCode == "" {
+		if cfg.claimCode == "" && cfg.bootstrapToken == "" {
 			debug.Error("Claim code required for first-time registration")
-			console.Error("Invalid credentials found. Please provide a claim code with --claim flag to re-register")
+			console.Error("Invalid credentials found. Please provide a claim code with --claim flag (or a bootstrap token with --bootstrap-token) to re-register")
 			os.Exit(1)
 		}
-		debug.Info("Starting registration process with claim code")
-		console.Status("Re-registering agent with claim code...")
 
 		// Attempt registration
-		if err := agent.RegisterAgent(cfg.claimCode, urlConfig); err != nil {
+		if err := registerAgent(cfg, urlConfig); err != nil {
 			debug.Error("Failed to register agent: %v", err)
 			console.Error("Failed to register agent: %v", err)
 			os.Exit(1)
@@ -733,6 +769,12 @@ func main() {
 		}
 		jobManager.SetOutputCallback(outputCallback)
 		debug.Info("Output callback configured to send hashcat output to backend")
+
+		// Re-downloads of mismatched task files are reported as file syncs
+		jobManager.SetFileRepairReporter(conn)
+
+		// Send crash reports through the connection, starting with pending ones
+		crash.SetSender(conn.SendCrashReport)
 		
 		lastError = nil
 		break
@@ -748,7 +790,12 @@ func main() {
 	cleanupService := cleanup.NewCleanupService(dataDirs)
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
 	cleanupService.Start(cleanupCtx)
-	debug.Info("File cleanup service started with 3-day retention policy")
+	debug.Info("File cleanup service started")
+
+	// The backend may manage the retention period
+	conn.OnConfigUpdate(func(config agent.ManagedConfig) {
+		cleanupService.SetRetentionDays(config.FileRetentionDays)
+	})
 
 	console.Success("Heartbeat active (interval: %ds)", cfg.heartbeatInterval)
 	console.Info("Agent running, press Ctrl+C to exit")
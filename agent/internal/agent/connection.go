@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
@@ -21,8 +22,10 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/auth"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/buffer"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/crash"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/hardware"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/hardware/types"
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/httpclient"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/jobs"
 	filesync "github.com/ZerkerEOD/krakenhashes/agent/internal/sync"
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/version"
@@ -53,6 +56,7 @@ const (
 	WSTypeBenchmarkResult  WSMessageType = "benchmark_result"
 	WSTypeHashcatOutput    WSMessageType = "hashcat_output"
 	WSTypeForceCleanup     WSMessageType = "force_cleanup"
+	WSTypeConfigUpdate     WSMessageType = "config_update"
 	WSTypeCurrentTaskStatus WSMessageType = "current_task_status"
 	
 	// Device detection message types
@@ -65,6 +69,16 @@ const (
 	
 	// Shutdown message type
 	WSTypeAgentShutdown    WSMessageType = "agent_shutdown"
+
+	// Diagnostic bundle of an agent or hashcat crash
+	WSTypeCrashReport      WSMessageType = "crash_report"
+
+	// The backend is restarting and tells the agent when to reconnect
+	WSTypeServerDraining WSMessageType = "server_draining"
+
+	// The backend replaces the agent's API key and certificate while connected
+	WSTypeCredentialRotation    WSMessageType = "credential_rotation"
+	WSTypeCredentialRotationAck WSMessageType = "credential_rotation_ack"
 )
 
 // WSMessage represents a WebSocket message
@@ -77,7 +91,9 @@ type WSMessage struct {
 
 // FileSyncRequestPayload represents a request for the agent to report its current files
 type FileSyncRequestPayload struct {
-	FileTypes []string `json:"file_types"` // "wordlist", "rule", "binary"
+	RequestID string   `json:"request_id"`
+	FileTypes []string `json:"file_types"`      // "wordlist", "rule", "binary"
+	Audit     bool     `json:"audit,omitempty"` // Integrity audit requested by an admin
 }
 
 // FileInfo represents information about a file for synchronization
@@ -85,13 +101,25 @@ type FileInfo = filesync.FileInfo
 
 // FileSyncResponsePayload represents the agent's response with its current files
 type FileSyncResponsePayload struct {
-	AgentID int        `json:"agent_id"`
-	Files   []FileInfo `json:"files"`
+	RequestID string     `json:"request_id,omitempty"`
+	AgentID   int        `json:"agent_id"`
+	Files     []FileInfo `json:"files"`
 }
 
-// FileSyncCommandPayload represents a command to download specific files
+// FileSyncCommandPayload represents a command to download or delete specific files
 type FileSyncCommandPayload struct {
-	Files []FileInfo `json:"files"`
+	// Action is "delete" for files deleted on the backend, anything else downloads
+	Action string     `json:"action"`
+	Files  []FileInfo `json:"files"`
+}
+
+// HeartbeatPayload carries lightweight utilization data with each heartbeat
+type HeartbeatPayload struct {
+	AgentID        int      `json:"agent_id"`
+	CurrentTaskID  string   `json:"current_task_id,omitempty"`
+	GPUUtilization *float64 `json:"gpu_utilization,omitempty"` // Average across enabled devices (0-100)
+	QueueDepth     int      `json:"queue_depth"`               // Messages waiting to be sent
+	ReconnectCount int      `json:"reconnect_count"`           // Reconnects since the agent started
 }
 
 // CurrentTaskStatusPayload represents the agent's current task status
@@ -104,6 +132,12 @@ type CurrentTaskStatusPayload struct {
 	Status            string `json:"status,omitempty"`
 }
 
+// ServerDrainingPayload is sent by a backend that is restarting. Running tasks
+// continue and their results are buffered until the agent reconnects.
+type ServerDrainingPayload struct {
+	ReconnectAfterSeconds int `json:"reconnect_after_seconds"`
+}
+
 // BenchmarkRequest represents a request to test speed for a specific job configuration
 type BenchmarkRequest struct {
 	RequestID       string             `json:"request_id"`
@@ -120,6 +154,7 @@ type BenchmarkRequest struct {
 	TestDuration    int                `json:"test_duration"`    // How long to run test (seconds)
 	TimeoutDuration int                `json:"timeout_duration"` // Maximum time to wait for speedtest (seconds)
 	ExtraParameters string             `json:"extra_parameters,omitempty"` // Agent-specific hashcat parameters
+	ExtraArgs       []string           `json:"extra_args,omitempty"`       // ExtraParameters as argv tokens
 	EnabledDevices  []int              `json:"enabled_devices,omitempty"`  // List of enabled device IDs
 }
 
@@ -204,13 +239,16 @@ const (
 	defaultPongWait   = 60 * time.Second
 	defaultPingPeriod = 54 * time.Second
 	maxMessageSize    = 512 * 1024 // 512KB
+
+	defaultHeartbeatPeriod = 5 * time.Second
 )
 
 // Connection timing configuration
 var (
-	writeWait  time.Duration
-	pongWait   time.Duration
-	pingPeriod time.Duration
+	writeWait       time.Duration
+	pongWait        time.Duration
+	pingPeriod      time.Duration
+	heartbeatPeriod time.Duration
 )
 
 // BackendConfig represents the configuration received from the backend
@@ -261,7 +299,11 @@ func fetchBackendConfig(urlConfig *config.URLConfig) (*BackendConfig, error) {
 		Timeout:   10 * time.Second,
 	}
 	
-	resp, err := client.Get(url)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := httpclient.New(client, httpclient.DefaultPolicy).Do(req)
 	if err != nil {
 		debug.Error("Failed to fetch backend configuration: %v", err)
 		return nil, fmt.Errorf("failed to fetch backend configuration: %w", err)
@@ -314,6 +356,11 @@ func initTimingConfig(backendConfig *BackendConfig) {
 			pingPeriod = defaultPingPeriod
 		}
 		
+		heartbeatPeriod = defaultHeartbeatPeriod
+		if backendConfig.HeartbeatInterval > 0 {
+			heartbeatPeriod = time.Duration(backendConfig.HeartbeatInterval) * time.Second
+		}
+		
 		debug.Info("Using backend WebSocket configuration")
 	} else {
 		// Fall back to defaults if no backend config
@@ -321,12 +368,14 @@ func initTimingConfig(backendConfig *BackendConfig) {
 		writeWait = defaultWriteWait
 		pongWait = defaultPongWait
 		pingPeriod = defaultPingPeriod
+		heartbeatPeriod = defaultHeartbeatPeriod
 	}
 	
 	debug.Info("WebSocket timing configuration initialized:")
 	debug.Info("- Write Wait: %v", writeWait)
 	debug.Info("- Pong Wait: %v", pongWait)
 	debug.Info("- Ping Period: %v", pingPeriod)
+	debug.Info("- Heartbeat Period: %v", heartbeatPeriod)
 }
 
 // Connection represents a WebSocket connection to the server
@@ -378,6 +427,9 @@ type Connection struct {
 
 	// Message buffer for handling disconnections
 	messageBuffer *buffer.MessageBuffer
+
+	// Encrypted journal of cracks made while disconnected
+	crackJournal *buffer.CrackJournal
 	
 	// Agent ID for buffer identification
 	agentID int
@@ -385,6 +437,19 @@ type Connection struct {
 	// Device detection tracking
 	devicesDetected bool
 	deviceMutex     sync.Mutex
+
+	// Number of successful reconnects, reported with each heartbeat
+	reconnectCount atomic.Int32
+
+	// Delay before reconnecting, announced by a restarting backend
+	reconnectDelay atomic.Int64
+
+	// Settings managed from the backend, see managed_config.go
+	managedConfig    ManagedConfig
+	configReceived   bool
+	configHandlers   []func(ManagedConfig)
+	configMutex      sync.Mutex
+	heartbeatChanged chan time.Duration
 }
 
 // JobManager interface defines the methods required for job management
@@ -494,7 +559,7 @@ func RenewCertificates(urlConfig *config.URLConfig) error {
 		Timeout: 30 * time.Second,
 	}
 	
-	resp, err := client.Do(req)
+	resp, err := httpclient.New(client, httpclient.DefaultPolicy).Do(req)
 	if err != nil {
 		debug.Error("Failed to request certificate renewal: %v", err)
 		return fmt.Errorf("failed to request certificate renewal: %w", err)
@@ -647,6 +712,8 @@ func NewConnection(urlConfig *config.URLConfig) (*Connection, error) {
 		done:       make(chan struct{}),
 		tlsConfig:  tlsConfig,
 		syncStatus: "pending",
+
+		heartbeatChanged: make(chan time.Duration, 1),
 	}
 
 	// Download manager will be initialized when file sync is set up
@@ -694,6 +761,12 @@ func (c *Connection) connect() error {
 		} else {
 			c.messageBuffer = mb
 			debug.Info("Message buffer initialized for agent %d", c.agentID)
+
+			if journal, err := buffer.NewCrackJournal(cfg.DataDirectory, config.GetConfigDir()); err != nil {
+				debug.Error("Failed to open crack journal: %v", err)
+			} else {
+				c.crackJournal = journal
+			}
 			
 			// Send any buffered messages from previous sessions
 			c.sendBufferedMessages()
@@ -836,13 +909,22 @@ func (c *Connection) maintainConnection() {
 		default:
 			if !c.isConnected.Load() {
 				debug.Info("Connection state: disconnected")
-				debug.Info("Reconnection attempt %d - Waiting %v before retry", attempt, backoff)
-				if attempt == 1 {
-					console.Warning("Connection lost, reconnecting...")
-				} else if attempt % 5 == 0 {
-					console.Warning("Still trying to reconnect (attempt %d)...", attempt)
+				if delay := time.Duration(c.reconnectDelay.Swap(0)); delay > 0 {
+					// The backend is restarting; spread the reconnects of
+					// its agents over a quarter of the delay
+					delay += time.Duration(rand.Int63n(int64(delay)/4 + 1))
+					debug.Info("Backend is restarting - Waiting %v before reconnecting", delay)
+					console.Warning("Backend is restarting, reconnecting in %v...", delay.Round(time.Second))
+					time.Sleep(delay)
+				} else {
+					debug.Info("Reconnection attempt %d - Waiting %v before retry", attempt, backoff)
+					if attempt == 1 {
+						console.Warning("Connection lost, reconnecting...")
+					} else if attempt % 5 == 0 {
+						console.Warning("Still trying to reconnect (attempt %d)...", attempt)
+					}
+					time.Sleep(backoff)
 				}
-				time.Sleep(backoff)
 
 				if err := c.connect(); err != nil {
 					debug.Error("Reconnection attempt %d failed: %v", attempt, err)
@@ -858,6 +940,7 @@ func (c *Connection) maintainConnection() {
 					console.Success("Reconnected to backend successfully")
 					backoff = 1 * time.Second
 					attempt = 1
+					c.reconnectCount.Add(1)
 					
 					// Reinitialize channels before starting pumps
 					c.reinitializeChannels()
@@ -866,8 +949,15 @@ func (c *Connection) maintainConnection() {
 					go c.readPump()
 					go c.writePump()
 					
-					// Send current task status after reconnection
-					go c.sendCurrentTaskStatus()
+					// Deliver results buffered while disconnected, then
+					// send the current task status
+					go func() {
+						c.sendBufferedMessages()
+						c.sendCurrentTaskStatus()
+					}()
+
+					// Report crashes that happened while disconnected
+					go crash.Flush()
 				}
 			} else {
 				// debug.Debug("Connection state: connected") // Commented out to reduce log spam
@@ -879,6 +969,7 @@ func (c *Connection) maintainConnection() {
 
 // readPump pumps messages from the WebSocket connection to the hub
 func (c *Connection) readPump() {
+	defer crash.Recover()
 	defer func() {
 		debug.Info("ReadPump closing, marking connection as disconnected")
 		c.isConnected.Store(false)
@@ -943,13 +1034,16 @@ func (c *Connection) readPump() {
 		switch msg.Type {
 		case WSTypeHeartbeat:
 			// Send heartbeat response
-			response := WSMessage{
-				Type:      WSTypeHeartbeat,
-				Timestamp: time.Now(),
+			response, err := c.createHeartbeatMessage()
+			if err != nil {
+				debug.Error("Failed to create heartbeat response: %v", err)
+				break
 			}
+			c.writeMux.Lock()
 			if err := c.ws.WriteJSON(response); err != nil {
 				debug.Error("Failed to send heartbeat response: %v", err)
 			}
+			c.writeMux.Unlock()
 		case WSTypeMetrics:
 			// Server requested metrics update
 			// TODO: Implement metrics collection and sending
@@ -1005,6 +1099,11 @@ func (c *Connection) readPump() {
 				continue
 			}
 
+			if commandPayload.Action == "delete" {
+				c.removeDeletedFiles(commandPayload.Files)
+				continue
+			}
+
 			// Show console message about file sync
 			if len(commandPayload.Files) > 0 {
 				console.Status("Starting file synchronization (%d files)...", len(commandPayload.Files))
@@ -1032,7 +1131,7 @@ func (c *Connection) readPump() {
 			// Ensure download manager is initialized even if fileSync already exists
 			if c.downloadManager == nil && c.fileSync != nil {
 				debug.Info("Initializing download manager with existing file sync")
-				c.downloadManager = filesync.NewDownloadManager(c.fileSync, 3)
+				c.downloadManager = filesync.NewDownloadManager(c.fileSync, c.downloadConcurrency())
 				go c.monitorDownloadProgress()
 			}
 
@@ -1134,7 +1233,7 @@ func (c *Connection) readPump() {
 			// Ensure download manager is initialized even if fileSync already exists
 			if c.downloadManager == nil && c.fileSync != nil {
 				debug.Info("Initializing download manager with existing file sync")
-				c.downloadManager = filesync.NewDownloadManager(c.fileSync, 3)
+				c.downloadManager = filesync.NewDownloadManager(c.fileSync, c.downloadConcurrency())
 				go c.monitorDownloadProgress()
 			}
 
@@ -1202,6 +1301,13 @@ func (c *Connection) readPump() {
 				debug.Info("Successfully completed force cleanup")
 			}
 
+		case WSTypeConfigUpdate:
+			// Server sent the agent's configuration, on connect and whenever an
+			// administrator changes the agent's managed settings
+			if err := c.handleConfigUpdate(msg.Payload); err != nil {
+				debug.Error("Failed to apply config update: %v", err)
+			}
+
 		case WSTypeBenchmarkRequest:
 			// Server requested a benchmark (now with full job configuration for real-world speed test)
 			debug.Info("Received benchmark request")
@@ -1303,9 +1409,9 @@ func (c *Connection) readPump() {
 
 				// Check if hashlist exists locally before running benchmark
 				if benchmarkPayload.HashlistID > 0 {
-					hashlistFileName := fmt.Sprintf("%d.hash", benchmarkPayload.HashlistID)
+					hashlistFileName := filesync.HashlistFileName(benchmarkPayload.HashlistID, benchmarkPayload.HashlistPath)
 					dataDirs, _ := config.GetDataDirs()
-					localPath := filepath.Join(dataDirs.Hashlists, hashlistFileName)
+					localPath := filepath.Join(dataDirs.Hashlists, filepath.FromSlash(hashlistFileName))
 					
 					if _, err := os.Stat(localPath); os.IsNotExist(err) {
 						debug.Info("Hashlist %d not found locally for benchmark, downloading...", benchmarkPayload.HashlistID)
@@ -1390,6 +1496,7 @@ func (c *Connection) readPump() {
 					BinaryPath:      benchmarkPayload.BinaryPath,
 					ReportInterval:  5, // Default status interval
 					ExtraParameters: benchmarkPayload.ExtraParameters, // Agent-specific parameters
+					ExtraArgs:       benchmarkPayload.ExtraArgs,
 					EnabledDevices:  benchmarkPayload.EnabledDevices,   // Device list
 				}
 
@@ -1516,6 +1623,23 @@ func (c *Connection) readPump() {
 			// Server acknowledged buffered messages
 			debug.Info("Received buffer acknowledgment")
 			c.handleBufferAck(msg.Payload)
+
+		case WSTypeServerDraining:
+			// The backend is restarting and will close the connection; tasks
+			// keep running and results are buffered until we reconnect
+			var draining ServerDrainingPayload
+			if err := json.Unmarshal(msg.Payload, &draining); err != nil {
+				debug.Error("Failed to unmarshal server draining payload: %v", err)
+				break
+			}
+			delay := time.Duration(draining.ReconnectAfterSeconds) * time.Second
+			c.reconnectDelay.Store(int64(delay))
+			debug.Info("Backend is restarting, reconnecting after %v", delay)
+
+		case WSTypeCredentialRotation:
+			// Server rotated the agent's credentials, store them and confirm
+			debug.Info("Received credential rotation")
+			c.handleCredentialRotation(msg.Payload)
 			
 		default:
 			debug.Warning("Received unknown message type: %s", msg.Type)
@@ -1527,6 +1651,11 @@ func (c *Connection) readPump() {
 func (c *Connection) handleFileSyncAsync(requestPayload FileSyncRequestPayload) {
 	debug.Info("Starting async file sync operation")
 	startTime := time.Now()
+	if requestPayload.Audit {
+		// Every scan rehashes the files, the backend compares them for the audit
+		debug.Info("File sync %s is an integrity audit", requestPayload.RequestID)
+		console.Status("Auditing cached files...")
+	}
 
 	// Create a context with timeout for the entire operation
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -1616,8 +1745,9 @@ func (c *Connection) handleFileSyncAsync(requestPayload FileSyncRequestPayload)
 
 	// Prepare response
 	responsePayload := FileSyncResponsePayload{
-		AgentID: agentID,
-		Files:   allFiles,
+		RequestID: requestPayload.RequestID,
+		AgentID:   agentID,
+		Files:     allFiles,
 	}
 
 	// Marshal response payload
@@ -1654,10 +1784,12 @@ func (c *Connection) writePump() {
 	ticker := time.NewTicker(pingPeriod)
 	// Add a status update ticker that runs every minute
 	statusTicker := time.NewTicker(1 * time.Minute)
+	heartbeatTicker := time.NewTicker(heartbeatPeriod)
 	defer func() {
 		debug.Info("WritePump closing, marking connection as disconnected")
 		ticker.Stop()
 		statusTicker.Stop()
+		heartbeatTicker.Stop()
 		c.isConnected.Store(false)
 		c.Close()
 	}()
@@ -1667,6 +1799,7 @@ func (c *Connection) writePump() {
 	debug.Info("- Pong Wait: %v", pongWait)
 	debug.Info("- Ping Period: %v", pingPeriod)
 	debug.Info("- Status Update Period: 1m")
+	debug.Info("- Heartbeat Period: %v", heartbeatPeriod)
 
 	// Send initial status update
 	if statusMsg, err := c.createAgentStatusMessage(); err != nil {
@@ -1740,6 +1873,17 @@ func (c *Connection) writePump() {
 				}
 			}
 
+		case period := <-c.heartbeatChanged:
+			heartbeatTicker.Reset(period)
+			debug.Info("Heartbeat period changed to %v", period)
+
+		case <-heartbeatTicker.C:
+			if heartbeatMsg, err := c.createHeartbeatMessage(); err != nil {
+				debug.Error("Failed to create heartbeat: %v", err)
+			} else if !c.safeSendMessage(heartbeatMsg, 1000) {
+				debug.Warning("Failed to queue heartbeat: channel blocked or closed")
+			}
+
 		case <-c.done:
 			debug.Info("WritePump received done signal")
 			return
@@ -1749,10 +1893,6 @@ func (c *Connection) writePump() {
 
 // SendJobProgress sends job progress update to the server
 func (c *Connection) SendJobProgress(progress *jobs.JobProgress) error {
-	if !c.isConnected.Load() {
-		return fmt.Errorf("not connected")
-	}
-
 	// Marshal progress payload to JSON
 	progressJSON, err := json.Marshal(progress)
 	if err != nil {
@@ -1767,8 +1907,20 @@ func (c *Connection) SendJobProgress(progress *jobs.JobProgress) error {
 		Timestamp: time.Now(),
 	}
 
-	// Send via safeSendMessage with panic recovery
+	// Send via safeSendMessage with panic recovery. While disconnected, e.g.
+	// during a backend restart, cracks and final statuses are buffered.
 	if !c.safeSendMessage(msg, 5000) {
+		if c.messageBuffer != nil && c.shouldBufferMessage(msg) {
+			if err := c.bufferMessage(msg); err != nil {
+				debug.Error("Failed to buffer job progress update: %v", err)
+			} else {
+				debug.Info("Buffered job progress update for task %s for later delivery", progress.TaskID)
+				return nil
+			}
+		}
+		if !c.isConnected.Load() {
+			return fmt.Errorf("not connected")
+		}
 		debug.Error("Failed to queue job progress update: channel blocked or closed")
 		return fmt.Errorf("failed to queue job progress update: channel blocked or closed")
 	}
@@ -1813,6 +1965,26 @@ func (c *Connection) SendHashcatOutput(taskID string, output string, isError boo
 	return nil
 }
 
+// SendCrashReport sends a crash bundle to the server, reporting whether it was queued
+func (c *Connection) SendCrashReport(bundle *crash.Bundle) bool {
+	if !c.isConnected.Load() {
+		return false
+	}
+
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		debug.Error("Failed to marshal crash report: %v", err)
+		return false
+	}
+
+	msg := &WSMessage{
+		Type:      WSTypeCrashReport,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	}
+	return c.safeSendMessage(msg, 5000)
+}
+
 // getDetailedOSInfo returns detailed OS information
 func getDetailedOSInfo() map[string]interface{} {
 	hostname, _ := os.Hostname()
@@ -1882,6 +2054,16 @@ func (c *Connection) createAgentStatusMessage() (*WSMessage, error) {
 		"os_info": osInfo,
 	}
 
+	// Let the backend notice driver and binary changes that invalidate benchmarks
+	if c.hwMonitor != nil {
+		if driverVersion := c.hwMonitor.DriverVersion(); driverVersion != "" {
+			statusPayload["driver_version"] = driverVersion
+		}
+		if binaryVersion := c.hwMonitor.BinaryVersion(); binaryVersion != "" {
+			statusPayload["binary_version"] = binaryVersion
+		}
+	}
+
 	// Marshal status payload to JSON
 	statusJSON, err := json.Marshal(statusPayload)
 	if err != nil {
@@ -1899,6 +2081,35 @@ func (c *Connection) createAgentStatusMessage() (*WSMessage, error) {
 	return msg, nil
 }
 
+// createHeartbeatMessage creates a heartbeat message with current utilization data
+func (c *Connection) createHeartbeatMessage() (*WSMessage, error) {
+	payload := HeartbeatPayload{
+		AgentID:        c.agentID,
+		QueueDepth:     len(c.outbound),
+		ReconnectCount: int(c.reconnectCount.Load()),
+	}
+	if c.messageBuffer != nil {
+		payload.QueueDepth += c.messageBuffer.Count()
+	}
+	if jm, ok := c.jobManager.(*jobs.JobManager); ok {
+		if hasTask, taskID, _, _ := jm.GetCurrentTaskStatus(); hasTask {
+			payload.CurrentTaskID = taskID
+		}
+		payload.GPUUtilization = jm.GetGPUUtilization()
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal heartbeat: %w", err)
+	}
+
+	return &WSMessage{
+		Type:      WSTypeHeartbeat,
+		Payload:   payloadJSON,
+		Timestamp: time.Now(),
+	}, nil
+}
+
 // Close closes the WebSocket connection
 func (c *Connection) Close() {
 	c.closeOnce.Do(func() {
@@ -2221,6 +2432,20 @@ func (c *Connection) GetHardwareMonitor() *hardware.Monitor {
 	return c.hwMonitor
 }
 
+// removeDeletedFiles deletes the local copies of wordlists and rules that were
+// deleted on the backend
+func (c *Connection) removeDeletedFiles(files []FileInfo) {
+	if c.fileSync == nil {
+		debug.Warning("File sync not initialized, cannot remove %d deleted files", len(files))
+		return
+	}
+	for i := range files {
+		if err := c.fileSync.RemoveFile(&files[i]); err != nil {
+			debug.Error("Failed to remove deleted %s %s: %v", files[i].FileType, files[i].Name, err)
+		}
+	}
+}
+
 // checkAndExtractBinaryArchives checks all binary directories for .7z files without extracted executables
 // initializeFileSync initializes the file sync and download manager
 func (c *Connection) initializeFileSync(apiKey, agentID string) error {
@@ -2237,7 +2462,7 @@ func (c *Connection) initializeFileSync(apiKey, agentID string) error {
 	}
 
 	// Initialize download manager with file sync
-	c.downloadManager = filesync.NewDownloadManager(c.fileSync, 3)
+	c.downloadManager = filesync.NewDownloadManager(c.fileSync, c.downloadConcurrency())
 
 	// Start monitoring download progress
 	go c.monitorDownloadProgress()
@@ -2323,6 +2548,26 @@ func (c *Connection) sendSyncCompleted() {
 	}
 }
 
+// FileRepairStarted reports the re-download of a mismatched task file as a
+// file sync, the backend assigns no new work until it completes
+func (c *Connection) FileRepairStarted(path string) {
+	debug.Info("Repairing task file %s", path)
+	c.sendSyncStarted(1)
+}
+
+// FileRepairFinished reports the end of a task file re-download
+func (c *Connection) FileRepairFinished(path string, err error) {
+	if err != nil {
+		c.sendSyncFailed(fmt.Errorf("failed to repair %s: %w", path, err))
+		return
+	}
+
+	c.syncMutex.Lock()
+	c.filesDownloaded = 1
+	c.syncMutex.Unlock()
+	c.sendSyncCompleted()
+}
+
 // sendSyncFailed sends sync failed message to backend
 func (c *Connection) sendSyncFailed(err error) {
 	c.syncMutex.Lock()
@@ -2469,6 +2714,7 @@ func (c *Connection) DetectAndSendDevices() error {
 	}
 	
 	debug.Info("Successfully sent device detection result with %d devices", len(result.Devices))
+	crash.SetDevices(result.Devices)
 
 	// Mark devices as detected
 	c.deviceMutex.Lock()
@@ -2508,6 +2754,9 @@ func (c *Connection) shouldBufferMessage(msg *WSMessage) bool {
 	switch msg.Type {
 	case WSTypeJobProgress, WSTypeHashcatOutput, WSTypeBenchmarkResult:
 		// Check if message contains crack information
+		if msg.Type == WSTypeJobProgress && buffer.HasFinalStatus(msg.Payload) {
+			return true
+		}
 		if msg.Type == WSTypeJobProgress || msg.Type == WSTypeHashcatOutput {
 			return buffer.HasCrackedHashes(msg.Payload)
 		}
@@ -2522,20 +2771,50 @@ func (c *Connection) bufferMessage(msg *WSMessage) error {
 	if c.messageBuffer == nil {
 		return fmt.Errorf("message buffer not initialized")
 	}
+
+	// Cracks are also journaled, so they are not lost if the buffer is
+	if c.crackJournal != nil && msg.Type == WSTypeJobProgress {
+		if err := c.crackJournal.Append(msg.Payload); err != nil {
+			debug.Error("Failed to journal cracks: %v", err)
+		}
+	}
 	
 	return c.messageBuffer.Add(buffer.MessageType(msg.Type), msg.Payload)
 }
 
 // sendBufferedMessages sends all buffered messages to the server
 func (c *Connection) sendBufferedMessages() {
-	if c.messageBuffer == nil || c.messageBuffer.Count() == 0 {
+	if c.messageBuffer == nil {
 		return
 	}
 	
-	debug.Info("Sending %d buffered messages", c.messageBuffer.Count())
-	
 	// Get all buffered messages
 	messages := c.messageBuffer.GetAll()
+
+	// Replay the crack journal alongside, the backend skips hashes that are
+	// already cracked so cracks delivered by both are counted once
+	if c.crackJournal != nil {
+		for _, entry := range c.crackJournal.Entries() {
+			payload, err := json.Marshal(entry)
+			if err != nil {
+				debug.Error("Failed to marshal crack journal entry %s: %v", entry.ID, err)
+				continue
+			}
+			messages = append(messages, buffer.BufferedMessage{
+				ID:        entry.ID,
+				Type:      buffer.MessageTypeCrackJournal,
+				Payload:   payload,
+				Timestamp: entry.Timestamp,
+				AgentID:   c.agentID,
+			})
+		}
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+
+	debug.Info("Sending %d buffered messages", len(messages))
 	
 	// Create payload with all buffered messages
 	payload, err := json.Marshal(map[string]interface{}{
@@ -2574,6 +2853,12 @@ func (c *Connection) handleBufferAck(payload json.RawMessage) {
 		return
 	}
 	
+	if c.crackJournal != nil {
+		if err := c.crackJournal.Remove(ack.MessageIDs); err != nil {
+			debug.Error("Failed to remove acknowledged entries from crack journal: %v", err)
+		}
+	}
+
 	if c.messageBuffer == nil {
 		return
 	}
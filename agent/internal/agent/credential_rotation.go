@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/auth"
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
+)
+
+// CredentialRotationPayload carries the API key, and possibly the client
+// certificate, the backend rotated the agent to
+type CredentialRotationPayload struct {
+	RotationID         string `json:"rotation_id"`
+	APIKey             string `json:"api_key"`
+	ClientCertificate  string `json:"client_certificate,omitempty"`
+	ClientKey          string `json:"client_key,omitempty"`
+	GracePeriodSeconds int    `json:"grace_period_seconds"`
+}
+
+// CredentialRotationAckPayload tells the backend whether the new credentials
+// were stored. Until it is received the old API key stays valid.
+type CredentialRotationAckPayload struct {
+	RotationID string `json:"rotation_id"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+}
+
+// handleCredentialRotation stores the credentials of a credential_rotation
+// message and switches to them without reconnecting, so running tasks are
+// not interrupted. The connection itself was authenticated already and stays
+// up; downloads and later reconnects use the new credentials.
+func (c *Connection) handleCredentialRotation(payload json.RawMessage) {
+	var rotation CredentialRotationPayload
+	if err := json.Unmarshal(payload, &rotation); err != nil {
+		debug.Error("Failed to unmarshal credential rotation payload: %v", err)
+		return
+	}
+
+	ack := CredentialRotationAckPayload{RotationID: rotation.RotationID, Success: true}
+	if err := c.applyCredentialRotation(&rotation); err != nil {
+		debug.Error("Failed to apply credential rotation %s: %v", rotation.RotationID, err)
+		ack.Success = false
+		ack.Error = err.Error()
+	} else {
+		debug.Info("Applied credential rotation %s, the previous API key expires %ds after confirmation",
+			rotation.RotationID, rotation.GracePeriodSeconds)
+	}
+
+	raw, err := json.Marshal(ack)
+	if err != nil {
+		debug.Error("Failed to marshal credential rotation ack: %v", err)
+		return
+	}
+	msg := &WSMessage{Type: WSTypeCredentialRotationAck, Payload: raw, Timestamp: time.Now()}
+	if !c.safeSendMessage(msg, 5000) {
+		// The backend completes the rotation when the new key is first used
+		debug.Warning("Failed to acknowledge credential rotation %s", rotation.RotationID)
+	}
+}
+
+// applyCredentialRotation stores the certificate before the API key: both
+// certificates are issued by the same CA, so if storing the key fails the
+// agent is left with credentials that still work
+func (c *Connection) applyCredentialRotation(rotation *CredentialRotationPayload) error {
+	if rotation.APIKey == "" {
+		return errors.New("credential rotation carries no API key")
+	}
+	configDir := config.GetConfigDir()
+	_, agentID, err := auth.LoadAgentKey(configDir)
+	if err != nil {
+		return fmt.Errorf("failed to load current credentials: %w", err)
+	}
+
+	if rotation.ClientCertificate != "" {
+		cert, err := tls.X509KeyPair([]byte(rotation.ClientCertificate), []byte(rotation.ClientKey))
+		if err != nil {
+			return fmt.Errorf("invalid client certificate: %w", err)
+		}
+		if err := writeCredentialFile(filepath.Join(configDir, "client.crt"), []byte(rotation.ClientCertificate), 0644); err != nil {
+			return err
+		}
+		if err := writeCredentialFile(filepath.Join(configDir, "client.key"), []byte(rotation.ClientKey), 0600); err != nil {
+			return err
+		}
+		if c.tlsConfig != nil {
+			c.tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+	}
+
+	if err := auth.SaveAgentKey(configDir, rotation.APIKey, agentID); err != nil {
+		return err
+	}
+	if c.fileSync != nil {
+		c.fileSync.SetAPIKey(rotation.APIKey)
+	}
+	return nil
+}
+
+// writeCredentialFile replaces a credential file in one step
+func writeCredentialFile(path string, data []byte, perm os.FileMode) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
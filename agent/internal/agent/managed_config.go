@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
+)
+
+// defaultMaxConcurrentDownloads is used until the backend sends a limit
+const defaultMaxConcurrentDownloads = 3
+
+// ManagedConfig holds the agent settings managed from the backend. A zero
+// value keeps the agent's own setting.
+type ManagedConfig struct {
+	HeartbeatIntervalSeconds int `json:"heartbeat_interval_seconds,omitempty"`
+	MaxConcurrentDownloads   int `json:"max_concurrent_downloads,omitempty"`
+	FileRetentionDays        int `json:"file_retention_days,omitempty"`
+}
+
+// ConfigUpdatePayload is the payload of a config_update message. Older
+// backends only send download_settings.
+type ConfigUpdatePayload struct {
+	AgentConfig *ManagedConfig `json:"agent_config,omitempty"`
+}
+
+// OnConfigUpdate registers a function applying managed settings that live
+// outside the connection. It is called with the settings already received, if
+// any, and again with every update.
+func (c *Connection) OnConfigUpdate(apply func(ManagedConfig)) {
+	c.configMutex.Lock()
+	c.configHandlers = append(c.configHandlers, apply)
+	config, received := c.managedConfig, c.configReceived
+	c.configMutex.Unlock()
+
+	if received {
+		apply(config)
+	}
+}
+
+// handleConfigUpdate applies the settings of a config_update message
+func (c *Connection) handleConfigUpdate(payload json.RawMessage) error {
+	var update ConfigUpdatePayload
+	if err := json.Unmarshal(payload, &update); err != nil {
+		return fmt.Errorf("failed to parse config update: %w", err)
+	}
+	if update.AgentConfig == nil {
+		debug.Debug("Config update carries no managed agent settings")
+		return nil
+	}
+	config := *update.AgentConfig
+
+	c.configMutex.Lock()
+	c.managedConfig = config
+	c.configReceived = true
+	handlers := append([]func(ManagedConfig){}, c.configHandlers...)
+	c.configMutex.Unlock()
+
+	debug.Info("Applying managed settings: heartbeat %ds, %d concurrent downloads, %d day file retention (0 keeps the local setting)",
+		config.HeartbeatIntervalSeconds, config.MaxConcurrentDownloads, config.FileRetentionDays)
+
+	if config.HeartbeatIntervalSeconds > 0 {
+		c.setHeartbeatPeriod(time.Duration(config.HeartbeatIntervalSeconds) * time.Second)
+	}
+	if config.MaxConcurrentDownloads > 0 && c.downloadManager != nil {
+		c.downloadManager.SetMaxConcurrent(config.MaxConcurrentDownloads)
+	}
+	for _, apply := range handlers {
+		apply(config)
+	}
+	return nil
+}
+
+// setHeartbeatPeriod changes the heartbeat interval of the running write pump
+// and of the ones started after a reconnect
+func (c *Connection) setHeartbeatPeriod(period time.Duration) {
+	if period == heartbeatPeriod {
+		return
+	}
+	heartbeatPeriod = period
+	// Drop a change the write pump has not picked up yet, this one replaces it
+	select {
+	case <-c.heartbeatChanged:
+	default:
+	}
+	c.heartbeatChanged <- period
+}
+
+// downloadConcurrency returns the number of files to download at once
+func (c *Connection) downloadConcurrency() int {
+	c.configMutex.Lock()
+	defer c.configMutex.Unlock()
+	if c.managedConfig.MaxConcurrentDownloads > 0 {
+		return c.managedConfig.MaxConcurrentDownloads
+	}
+	return defaultMaxConcurrentDownloads
+}
@@ -78,6 +78,13 @@ type RegistrationRequest struct {
 	Version   string `json:"version"` // Agent version
 }
 
+// BootstrapRequest represents the data sent to bootstrap a pre-registered agent
+type BootstrapRequest struct {
+	BootstrapToken string `json:"bootstrap_token"`
+	Hostname       string `json:"hostname"`
+	Version        string `json:"version"`
+}
+
 // RegistrationResponse represents the server's response to registration
 type RegistrationResponse struct {
 	AgentID       int               `json:"agent_id"`
@@ -287,6 +294,12 @@ func downloadCACertificate(urlConfig *config.URLConfig) error {
 
 // sendRegistrationRequest sends the registration request to the server
 func sendRegistrationRequest(urlConfig *config.URLConfig, req *RegistrationRequest) (*http.Response, error) {
+	return postRegistration(urlConfig, urlConfig.GetRegistrationURL(), req)
+}
+
+// postRegistration downloads the CA certificate and posts a registration or
+// bootstrap request to url over TLS
+func postRegistration(urlConfig *config.URLConfig, url string, req interface{}) (*http.Response, error) {
 	// Download CA certificate first
 	debug.Info("Downloading CA certificate before registration")
 	console.Status("Downloading CA certificate...")
@@ -314,7 +327,6 @@ func sendRegistrationRequest(urlConfig *config.URLConfig, req *RegistrationReque
 	}
 
 	// Create request
-	url := urlConfig.GetRegistrationURL()
 	debug.Info("Sending registration request to %s", url)
 	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
 	if err != nil {
@@ -382,6 +394,38 @@ func RegisterAgent(claimCode string, urlConfig *config.URLConfig) error {
 	}
 	defer resp.Body.Close()
 
+	return completeRegistration(resp)
+}
+
+// BootstrapAgent fetches the credentials of an agent that was pre-registered
+// on the server, using its bootstrap token instead of a claim code. The token
+// always maps to the same agent, so bootstrapping again is safe.
+func BootstrapAgent(bootstrapToken string, urlConfig *config.URLConfig) error {
+	debug.Info("Starting agent bootstrap process")
+
+	hostname, err := getHostname()
+	if err != nil {
+		hostname = "unknown"
+		debug.Warning("Could not get hostname, using 'unknown': %v", err)
+	}
+
+	resp, err := postRegistration(urlConfig, urlConfig.GetBootstrapURL(), &BootstrapRequest{
+		BootstrapToken: bootstrapToken,
+		Hostname:       hostname,
+		Version:        version.GetVersion(),
+	})
+	if err != nil {
+		debug.Error("Bootstrap request failed: %v", err)
+		return fmt.Errorf("bootstrap request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	return completeRegistration(resp)
+}
+
+// completeRegistration stores the credentials returned by a successful
+// registration or bootstrap request
+func completeRegistration(resp *http.Response) error {
 	// Parse response
 	debug.Debug("Parsing registration response")
 	var regResp RegistrationResponse
@@ -423,8 +467,7 @@ func RegisterAgent(claimCode string, urlConfig *config.URLConfig) error {
 	// Initialize data directories (just create them, don't populate yet)
 	debug.Info("Initializing data directories")
 	console.Status("Initializing data directories...")
-	_, err = config.GetDataDirs()
-	if err != nil {
+	if _, err := config.GetDataDirs(); err != nil {
 		debug.Error("Failed to initialize data directories: %v", err)
 		console.Error("Failed to initialize data directories: %v", err)
 		return fmt.Errorf("failed to initialize data directories: %w", err)
@@ -23,9 +23,15 @@ func SaveAgentKey(configDir, apiKey, agentID string) error {
 	keyPath := filepath.Join(configDir, KeyFile)
 	data := []byte(fmt.Sprintf("AGENT_ID=%s\nAPI_KEY=%s\n", agentID, apiKey))
 
-	if err := os.WriteFile(keyPath, data, FilePerms); err != nil {
+	// Replace the file in one step, a rotated key must not be left half written
+	tmpPath := keyPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, FilePerms); err != nil {
 		return fmt.Errorf("failed to write key file: %w", err)
 	}
+	if err := os.Rename(tmpPath, keyPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace key file: %w", err)
+	}
 
 	debug.Info("Saved agent key file to: %s", keyPath)
 	return nil
@@ -0,0 +1,294 @@
+package buffer
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// MessageTypeCrackJournal is the type under which journal entries are
+// replayed to the backend on reconnect
+const MessageTypeCrackJournal MessageType = "crack_journal"
+
+const (
+	crackJournalFile    = "crack_journal.enc"
+	crackJournalKeyFile = "crack_journal.key"
+)
+
+// CrackJournalEntry holds the cracks of one progress update that could not
+// be delivered
+type CrackJournalEntry struct {
+	ID            string          `json:"id"`
+	TaskID        string          `json:"task_id"`
+	CrackedHashes json.RawMessage `json:"cracked_hashes"`
+	Timestamp     time.Time       `json:"timestamp"`
+}
+
+// CrackJournal is an encrypted, append-only record of cracks made while
+// disconnected. It is kept apart from the message buffer so cracks survive
+// even if the buffer file is lost or corrupted. Each line is one entry
+// sealed with AES-256-GCM, so a damaged line only loses that entry.
+type CrackJournal struct {
+	mu       sync.Mutex
+	filePath string
+	aead     cipher.AEAD
+}
+
+// NewCrackJournal opens the journal in dataDir. The encryption key is kept in
+// keyDir, next to the agent's API key, and created on first use.
+func NewCrackJournal(dataDir, keyDir string) (*CrackJournal, error) {
+	key, err := loadOrCreateJournalKey(filepath.Join(keyDir, crackJournalKeyFile))
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create journal cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create journal cipher: %w", err)
+	}
+
+	return &CrackJournal{
+		filePath: filepath.Join(dataDir, crackJournalFile),
+		aead:     aead,
+	}, nil
+}
+
+// loadOrCreateJournalKey reads the 32 byte journal key, generating it if the
+// key file does not exist yet
+func loadOrCreateJournalKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("invalid journal key length %d in %s", len(key), path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read journal key: %w", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate journal key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create journal key directory: %w", err)
+	}
+	if err := os.WriteFile(path, key, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write journal key: %w", err)
+	}
+	return key, nil
+}
+
+// Append records the cracks of a job progress payload. Payloads without
+// cracks are ignored.
+func (j *CrackJournal) Append(payload json.RawMessage) error {
+	var progress struct {
+		TaskID        string          `json:"task_id"`
+		CrackedHashes json.RawMessage `json:"cracked_hashes"`
+	}
+	if err := json.Unmarshal(payload, &progress); err != nil {
+		return fmt.Errorf("failed to parse job progress: %w", err)
+	}
+	if len(progress.CrackedHashes) == 0 || bytes.Equal(progress.CrackedHashes, []byte("null")) || bytes.Equal(progress.CrackedHashes, []byte("[]")) {
+		return nil
+	}
+
+	entry := CrackJournalEntry{
+		ID:            uuid.New().String(),
+		TaskID:        progress.TaskID,
+		CrackedHashes: progress.CrackedHashes,
+		Timestamp:     time.Now().UTC(),
+	}
+	line, err := j.seal(entry)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(j.filePath), 0700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	f, err := os.OpenFile(j.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open crack journal: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write crack journal: %w", err)
+	}
+	// The journal is the copy of last resort, make sure it reaches the disk
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync crack journal: %w", err)
+	}
+
+	debug.Info("Journaled cracks of task %s as entry %s", entry.TaskID, entry.ID)
+	return nil
+}
+
+// Entries returns the journaled entries. Lines that cannot be decrypted are
+// skipped with a warning.
+func (j *CrackJournal) Entries() []CrackJournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries, _ := j.readLocked()
+	return entries
+}
+
+// Count returns the number of journaled entries
+func (j *CrackJournal) Count() int {
+	return len(j.Entries())
+}
+
+// Remove drops the entries with the given IDs once the backend has
+// acknowledged them. Unreadable lines are kept for later inspection.
+func (j *CrackJournal) Remove(ids []string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	idMap := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		idMap[id] = true
+	}
+
+	entries, unreadable := j.readLocked()
+	kept := make([][]byte, 0, len(entries)+len(unreadable))
+	kept = append(kept, unreadable...)
+	removed := 0
+	for _, entry := range entries {
+		if idMap[entry.ID] {
+			removed++
+			continue
+		}
+		line, err := j.seal(entry)
+		if err != nil {
+			return err
+		}
+		kept = append(kept, line)
+	}
+	if removed == 0 {
+		return nil
+	}
+
+	if len(kept) == 0 {
+		if err := os.Remove(j.filePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove crack journal: %w", err)
+		}
+		debug.Info("Removed %d entries from crack journal (0 remaining)", removed)
+		return nil
+	}
+
+	// Write to temp file first for atomicity
+	tempFile := j.filePath + ".tmp"
+	data := append(bytes.Join(kept, []byte("\n")), '\n')
+	if err := os.WriteFile(tempFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write temp crack journal: %w", err)
+	}
+	if err := os.Rename(tempFile, j.filePath); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to rename crack journal: %w", err)
+	}
+
+	debug.Info("Removed %d entries from crack journal (%d remaining)", removed, len(kept))
+	return nil
+}
+
+// readLocked decrypts the journal, returning the entries and the raw lines
+// that could not be read (caller must hold lock)
+func (j *CrackJournal) readLocked() ([]CrackJournalEntry, [][]byte) {
+	f, err := os.Open(j.filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			debug.Error("Failed to open crack journal: %v", err)
+		}
+		return nil, nil
+	}
+	defer f.Close()
+
+	var entries []CrackJournalEntry
+	var unreadable [][]byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := j.open(line)
+		if err != nil {
+			debug.Warning("Skipping unreadable crack journal line: %v", err)
+			unreadable = append(unreadable, append([]byte(nil), line...))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		debug.Error("Failed to read crack journal: %v", err)
+	}
+	return entries, unreadable
+}
+
+// seal encrypts an entry into one base64 encoded journal line
+func (j *CrackJournal) seal(entry CrackJournalEntry) ([]byte, error) {
+	plain, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal journal entry: %w", err)
+	}
+
+	nonce := make([]byte, j.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := j.aead.Seal(nonce, nonce, plain, nil)
+
+	line := make([]byte, base64.StdEncoding.EncodedLen(len(sealed)))
+	base64.StdEncoding.Encode(line, sealed)
+	return line, nil
+}
+
+// open decrypts one journal line
+func (j *CrackJournal) open(line []byte) (CrackJournalEntry, error) {
+	var entry CrackJournalEntry
+
+	sealed := make([]byte, base64.StdEncoding.DecodedLen(len(line)))
+	n, err := base64.StdEncoding.Decode(sealed, line)
+	if err != nil {
+		return entry, fmt.Errorf("invalid encoding: %w", err)
+	}
+	sealed = sealed[:n]
+
+	nonceSize := j.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return entry, fmt.Errorf("line too short")
+	}
+	plain, err := j.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return entry, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	if err := json.Unmarshal(plain, &entry); err != nil {
+		return entry, fmt.Errorf("failed to unmarshal: %w", err)
+	}
+	return entry, nil
+}
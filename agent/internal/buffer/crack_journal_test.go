@@ -0,0 +1,89 @@
+package buffer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCrackJournal(t *testing.T) {
+	dataDir := t.TempDir()
+	keyDir := t.TempDir()
+
+	journal, err := NewCrackJournal(dataDir, keyDir)
+	if err != nil {
+		t.Fatalf("Failed to create crack journal: %v", err)
+	}
+
+	cracked := json.RawMessage(`{"task_id":"task-1","cracked_hashes":[{"hash":"8846f7eaee8fb117ad06bdd830b7586c","plain":"password"}]}`)
+	if err := journal.Append(cracked); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+	// Progress without cracks is not journaled
+	if err := journal.Append(json.RawMessage(`{"task_id":"task-1","cracked_hashes":null}`)); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+	if err := journal.Append(json.RawMessage(`{"task_id":"task-2","cracked_hashes":[{"hash":"a","plain":"b"}]}`)); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+
+	// The plains are not stored in the clear
+	data, err := os.ReadFile(filepath.Join(dataDir, crackJournalFile))
+	if err != nil {
+		t.Fatalf("Failed to read journal file: %v", err)
+	}
+	if bytes.Contains(data, []byte("password")) {
+		t.Error("Journal contains a plaintext password")
+	}
+
+	// A reopened journal reads the same entries
+	reopened, err := NewCrackJournal(dataDir, keyDir)
+	if err != nil {
+		t.Fatalf("Failed to reopen crack journal: %v", err)
+	}
+	entries := reopened.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].TaskID != "task-1" || !bytes.Contains(entries[0].CrackedHashes, []byte("password")) {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+
+	// A corrupted line only loses that entry
+	f, err := os.OpenFile(filepath.Join(dataDir, crackJournalFile), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("Failed to open journal file: %v", err)
+	}
+	f.WriteString("not-a-journal-line\n")
+	f.Close()
+	if count := reopened.Count(); count != 2 {
+		t.Errorf("Expected 2 readable entries, got %d", count)
+	}
+
+	if err := reopened.Remove([]string{entries[0].ID}); err != nil {
+		t.Fatalf("Failed to remove entry: %v", err)
+	}
+	remaining := reopened.Entries()
+	if len(remaining) != 1 || remaining[0].ID != entries[1].ID {
+		t.Errorf("Unexpected entries after removal: %+v", remaining)
+	}
+
+	// Removing an entry twice is harmless
+	if err := reopened.Remove([]string{entries[0].ID}); err != nil {
+		t.Fatalf("Failed to remove entry again: %v", err)
+	}
+	if count := reopened.Count(); count != 1 {
+		t.Errorf("Expected 1 entry, got %d", count)
+	}
+
+	// A journal opened with another key cannot read the entries
+	other, err := NewCrackJournal(dataDir, t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create crack journal: %v", err)
+	}
+	if count := other.Count(); count != 0 {
+		t.Errorf("Expected no readable entries with another key, got %d", count)
+	}
+}
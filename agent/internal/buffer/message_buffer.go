@@ -236,8 +236,8 @@ func IsCriticalMessage(msgType string) bool {
 // HasCrackedHashes checks if a job progress message contains crack information
 func HasCrackedHashes(payload json.RawMessage) bool {
 	var progress struct {
-		CrackedCount int      `json:"cracked_count"`
-		CrackedHashes []string `json:"cracked_hashes"`
+		CrackedCount  int               `json:"cracked_count"`
+		CrackedHashes []json.RawMessage `json:"cracked_hashes"` // Plain strings or crack objects
 	}
 	
 	if err := json.Unmarshal(payload, &progress); err != nil {
@@ -245,4 +245,18 @@ func HasCrackedHashes(payload json.RawMessage) bool {
 	}
 	
 	return progress.CrackedCount > 0 || len(progress.CrackedHashes) > 0
-}
\ No newline at end of file
+}
+
+// HasFinalStatus checks if a job progress message ends its task, so the
+// backend learns the outcome even if it was sent while disconnected
+func HasFinalStatus(payload json.RawMessage) bool {
+	var progress struct {
+		Status string `json:"status"`
+	}
+
+	if err := json.Unmarshal(payload, &progress); err != nil {
+		return false
+	}
+
+	return progress.Status == "completed" || progress.Status == "failed"
+}
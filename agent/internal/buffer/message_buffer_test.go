@@ -160,6 +160,31 @@ func TestMessageBuffer(t *testing.T) {
 		if HasCrackedHashes(withoutCracks) {
 			t.Errorf("Should not detect cracks in message")
 		}
+
+		// Agents send crack details as objects
+		withCrackObjects := json.RawMessage(`{
+			"task_id": "test",
+			"cracked_hashes": [{"hash": "hash1", "plain": "password1"}]
+		}`)
+
+		if !HasCrackedHashes(withCrackObjects) {
+			t.Errorf("Should detect crack objects in message")
+		}
+	})
+
+	// Test final status detection
+	t.Run("FinalStatusDetection", func(t *testing.T) {
+		if !HasFinalStatus(json.RawMessage(`{"task_id": "test", "status": "completed"}`)) {
+			t.Errorf("Should detect completed status")
+		}
+
+		if !HasFinalStatus(json.RawMessage(`{"task_id": "test", "status": "failed"}`)) {
+			t.Errorf("Should detect failed status")
+		}
+
+		if HasFinalStatus(json.RawMessage(`{"task_id": "test", "status": "running"}`)) {
+			t.Errorf("Should not detect running status as final")
+		}
 	})
 }
 
@@ -75,6 +75,28 @@ func (cs *CleanupService) Stop() {
 	debug.Info("Cleanup service stopped")
 }
 
+// SetRetentionDays changes how many days files are kept, it applies from the
+// next cleanup run
+func (cs *CleanupService) SetRetentionDays(days int) {
+	if days <= 0 {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if days != cs.retentionDays {
+		cs.retentionDays = days
+		debug.Info("Cleanup retention set to %d days", days)
+	}
+}
+
+// cutoffTime returns the modification time before which files are removed
+func (cs *CleanupService) cutoffTime() time.Time {
+	cs.mu.Lock()
+	days := cs.retentionDays
+	cs.mu.Unlock()
+	return time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+}
+
 // performCleanup executes the actual cleanup process
 func (cs *CleanupService) performCleanup(ctx context.Context) {
 	cs.mu.Lock()
@@ -153,7 +175,7 @@ func (cs *CleanupService) cleanupChunkIDFiles() (int, int64) {
 
 	deleted := 0
 	totalSize := int64(0)
-	cutoffTime := time.Now().Add(-time.Duration(cs.retentionDays) * 24 * time.Hour)
+	cutoffTime := cs.cutoffTime()
 
 	// Look for chunk ID files (typically named like "chunkid_*" or "*.chunkid")
 	err := filepath.Walk(rulesDir, func(path string, info os.FileInfo, err error) error {
@@ -222,7 +244,7 @@ func (cs *CleanupService) cleanupChunkIDFiles() (int, int64) {
 func (cs *CleanupService) cleanupDirectory(dir string, extensions []string, fileType string) (int, int64) {
 	deleted := 0
 	totalSize := int64(0)
-	cutoffTime := time.Now().Add(-time.Duration(cs.retentionDays) * 24 * time.Hour)
+	cutoffTime := cs.cutoffTime()
 
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -280,7 +302,7 @@ func (cs *CleanupService) cleanupDirectory(dir string, extensions []string, file
 func (cs *CleanupService) cleanupDirectoryWithPattern(dir string, pattern string, fileType string) (int, int64) {
 	deleted := 0
 	totalSize := int64(0)
-	cutoffTime := time.Now().Add(-time.Duration(cs.retentionDays) * 24 * time.Hour)
+	cutoffTime := cs.cutoffTime()
 
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
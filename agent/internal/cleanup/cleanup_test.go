@@ -235,6 +235,32 @@ func TestPerformCleanup(t *testing.T) {
 	assert.True(t, os.IsNotExist(err), "Old chunk should be deleted")
 }
 
+// TestSetRetentionDays tests that a retention period set by the backend applies to the next cleanup
+func TestSetRetentionDays(t *testing.T) {
+	tempDir := t.TempDir()
+	hashlistDir := filepath.Join(tempDir, "hashlists")
+	require.NoError(t, os.MkdirAll(hashlistDir, 0755))
+
+	// Four days old, past the default three day retention
+	hashlist := filepath.Join(hashlistDir, "recent.hash")
+	require.NoError(t, ioutil.WriteFile(hashlist, []byte("hashes"), 0644))
+	fourDaysAgo := time.Now().Add(-4 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(hashlist, fourDaysAgo, fourDaysAgo))
+
+	service := NewCleanupService(&config.DataDirs{Hashlists: hashlistDir})
+	service.SetRetentionDays(7)
+	service.SetRetentionDays(0) // Ignored, keeps 7 days
+
+	deleted, _ := service.cleanupHashlists()
+	assert.Equal(t, 0, deleted)
+	_, err := os.Stat(hashlist)
+	assert.NoError(t, err, "Hashlist within the longer retention should be kept")
+
+	service.SetRetentionDays(1)
+	deleted, _ = service.cleanupHashlists()
+	assert.Equal(t, 1, deleted)
+}
+
 // TestFormatBytes tests the byte formatting function
 func TestFormatBytes(t *testing.T) {
 	testCases := []struct {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
@@ -18,10 +19,71 @@ const (
 	DefaultDataDir = "data"
 )
 
+// DefaultHashcatCgroup is the cgroup v2 directory hashcat runs are capped in
+// when KH_HASHCAT_CGROUP is not set
+const DefaultHashcatCgroup = "/sys/fs/cgroup/krakenhashes-hashcat"
+
 // Config represents the agent configuration
 type Config struct {
 	DataDirectory      string
 	HashcatExtraParams string // Extra parameters to pass to hashcat (e.g., "-O -w 3")
+	HashcatSandbox     HashcatSandbox
+}
+
+// HashcatSandbox describes how hashcat runs are confined on Linux, reducing
+// the harm malicious wordlists, rules or hashlists and runaway memory use can
+// do on shared machines. The zero value runs hashcat as the agent itself.
+type HashcatSandbox struct {
+	User        string  // Unprivileged user hashcat runs as (KH_HASHCAT_USER)
+	MemoryMaxMB int     // Memory cap of each hashcat run (KH_HASHCAT_MEMORY_MAX_MB)
+	CPUMax      float64 // CPUs each hashcat run may use (KH_HASHCAT_CPU_MAX)
+	Cgroup      string  // cgroup v2 directory the runs' cgroups are created in (KH_HASHCAT_CGROUP)
+	Bubblewrap  bool    // Run hashcat under bwrap with only the data directory writable (KH_HASHCAT_SANDBOX=bwrap)
+}
+
+// Enabled reports whether hashcat runs are confined at all
+func (s HashcatSandbox) Enabled() bool {
+	return s.User != "" || s.Bubblewrap || s.HasLimits()
+}
+
+// HasLimits reports whether hashcat runs get a cgroup with resource caps
+func (s HashcatSandbox) HasLimits() bool {
+	return s.MemoryMaxMB > 0 || s.CPUMax > 0
+}
+
+// LoadHashcatSandbox reads the hashcat sandbox from the environment. Invalid
+// values are logged and left out.
+func LoadHashcatSandbox() HashcatSandbox {
+	sandbox := HashcatSandbox{
+		User:   strings.TrimSpace(os.Getenv("KH_HASHCAT_USER")),
+		Cgroup: strings.TrimSpace(os.Getenv("KH_HASHCAT_CGROUP")),
+	}
+	if sandbox.Cgroup == "" {
+		sandbox.Cgroup = DefaultHashcatCgroup
+	}
+
+	if value := strings.TrimSpace(os.Getenv("KH_HASHCAT_MEMORY_MAX_MB")); value != "" {
+		if mb, err := strconv.Atoi(value); err == nil && mb > 0 {
+			sandbox.MemoryMaxMB = mb
+		} else {
+			debug.Error("Ignoring invalid KH_HASHCAT_MEMORY_MAX_MB %q, expected a positive number of megabytes", value)
+		}
+	}
+	if value := strings.TrimSpace(os.Getenv("KH_HASHCAT_CPU_MAX")); value != "" {
+		if cpus, err := strconv.ParseFloat(value, 64); err == nil && cpus > 0 {
+			sandbox.CPUMax = cpus
+		} else {
+			debug.Error("Ignoring invalid KH_HASHCAT_CPU_MAX %q, expected a positive number of CPUs", value)
+		}
+	}
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("KH_HASHCAT_SANDBOX"))); mode {
+	case "", "none":
+	case "bwrap":
+		sandbox.Bubblewrap = true
+	default:
+		debug.Error("Ignoring unknown KH_HASHCAT_SANDBOX %q, expected bwrap or none", mode)
+	}
+	return sandbox
 }
 
 // NewConfig creates a new agent configuration
@@ -32,6 +94,7 @@ func NewConfig() *Config {
 		return &Config{
 			DataDirectory:      "data",
 			HashcatExtraParams: os.Getenv("HASHCAT_EXTRA_PARAMS"),
+			HashcatSandbox:     LoadHashcatSandbox(),
 		}
 	}
 	
@@ -41,6 +104,7 @@ func NewConfig() *Config {
 	return &Config{
 		DataDirectory:      baseDataDir,
 		HashcatExtraParams: os.Getenv("HASHCAT_EXTRA_PARAMS"),
+		HashcatSandbox:     LoadHashcatSandbox(),
 	}
 }
 
@@ -57,6 +57,36 @@ func TestNewConfig(t *testing.T) {
 	}
 }
 
+func TestLoadHashcatSandbox(t *testing.T) {
+	sandbox := LoadHashcatSandbox()
+	assert.False(t, sandbox.Enabled())
+	assert.Equal(t, DefaultHashcatCgroup, sandbox.Cgroup)
+
+	t.Setenv("KH_HASHCAT_USER", "hashcat")
+	t.Setenv("KH_HASHCAT_MEMORY_MAX_MB", "8192")
+	t.Setenv("KH_HASHCAT_CPU_MAX", "1.5")
+	t.Setenv("KH_HASHCAT_CGROUP", "/sys/fs/cgroup/agents/hashcat")
+	t.Setenv("KH_HASHCAT_SANDBOX", "bwrap")
+	sandbox = LoadHashcatSandbox()
+	assert.Equal(t, HashcatSandbox{
+		User:        "hashcat",
+		MemoryMaxMB: 8192,
+		CPUMax:      1.5,
+		Cgroup:      "/sys/fs/cgroup/agents/hashcat",
+		Bubblewrap:  true,
+	}, sandbox)
+	assert.True(t, sandbox.HasLimits())
+
+	// Invalid values are left out
+	t.Setenv("KH_HASHCAT_MEMORY_MAX_MB", "8G")
+	t.Setenv("KH_HASHCAT_CPU_MAX", "-1")
+	t.Setenv("KH_HASHCAT_SANDBOX", "docker")
+	sandbox = LoadHashcatSandbox()
+	assert.False(t, sandbox.HasLimits())
+	assert.False(t, sandbox.Bubblewrap)
+	assert.True(t, sandbox.Enabled())
+}
+
 func TestGetDataDirs(t *testing.T) {
 	tests := []struct {
 		name            string
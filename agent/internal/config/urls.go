@@ -75,6 +75,11 @@ func (c *URLConfig) GetRegistrationURL() string {
 	return fmt.Sprintf("%s/api/agent/register", c.BaseURL)
 }
 
+// GetBootstrapURL returns the URL for bootstrapping a pre-registered agent
+func (c *URLConfig) GetBootstrapURL() string {
+	return fmt.Sprintf("%s/api/agent/bootstrap", c.BaseURL)
+}
+
 // GetAPIBaseURL returns the base URL for API endpoints
 func (c *URLConfig) GetAPIBaseURL() string {
 	return fmt.Sprintf("%s/api", c.BaseURL)
@@ -0,0 +1,395 @@
+// Package crash collects a diagnostic bundle when the agent panics or hashcat
+// crashes. Bundles are written to disk first and sent to the backend once a
+// connection is available, so a crash that took the agent down is reported
+// when it starts again instead of the agent just going stale.
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	logdebug "github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
+)
+
+// Kinds of crash a bundle describes
+const (
+	KindAgentPanic   = "agent_panic"   // A panic recovered in an agent goroutine
+	KindAgentFatal   = "agent_fatal"   // A crash found in the crash output of the previous run
+	KindHashcatCrash = "hashcat_crash" // Hashcat was killed by a fault signal
+)
+
+const (
+	bundleSuffix   = ".bundle.json"
+	crashOutput    = "crash-output.log"
+	reportedMarker = "crash-reported"
+	taskFile       = "task.json"
+	maxStackBytes  = 128 * 1024
+	maxOutputBytes = 64 * 1024
+	maxPending     = 20
+)
+
+// Bundle is everything known about a crash that helps diagnosing it
+type Bundle struct {
+	ID             string            `json:"id"`
+	Kind           string            `json:"kind"`
+	Summary        string            `json:"summary"`
+	OccurredAt     time.Time         `json:"occurred_at"`
+	AgentVersion   string            `json:"agent_version"`
+	OS             string            `json:"os"`
+	Arch           string            `json:"arch"`
+	GoVersion      string            `json:"go_version"`
+	Environment    map[string]string `json:"environment"`
+	Devices        json.RawMessage   `json:"devices,omitempty"`
+	TaskID         string            `json:"task_id,omitempty"`
+	TaskAssignment json.RawMessage   `json:"task_assignment,omitempty"`
+	HashcatOutput  string            `json:"hashcat_output,omitempty"`
+	LogTail        string            `json:"log_tail,omitempty"`
+	StackTrace     string            `json:"stack_trace,omitempty"`
+}
+
+// reporter holds the state bundles are built from
+type reporter struct {
+	dir     string
+	version string
+
+	mu      sync.Mutex
+	task    json.RawMessage
+	taskID  string
+	devices json.RawMessage
+	sender  func(*Bundle) bool
+
+	flushMu sync.Mutex // Keeps concurrent flushes from sending a bundle twice
+}
+
+var (
+	stdMu sync.Mutex
+	std   *reporter
+)
+
+// Init starts collecting crash bundles in dir. A crash recorded by the Go
+// runtime during the previous run is turned into a bundle, and the runtime's
+// crash output is redirected to dir for the next one.
+func Init(dir, version string) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create crash directory: %w", err)
+	}
+
+	r := &reporter{dir: dir, version: version}
+	r.collectPreviousCrash()
+
+	f, err := os.OpenFile(filepath.Join(dir, crashOutput), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	if err != nil {
+		return fmt.Errorf("failed to open crash output: %w", err)
+	}
+	if err := debug.SetCrashOutput(f, debug.CrashOptions{}); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to set crash output: %w", err)
+	}
+	f.Close()
+
+	stdMu.Lock()
+	std = r
+	stdMu.Unlock()
+	return nil
+}
+
+// current returns the reporter set up by Init, or nil
+func current() *reporter {
+	stdMu.Lock()
+	defer stdMu.Unlock()
+	return std
+}
+
+// SetTask records the assignment of the task being run, so a crash while it
+// runs, even one that takes the agent down, is reported with it
+func SetTask(taskID string, assignment interface{}) {
+	r := current()
+	if r == nil {
+		return
+	}
+	data, err := json.Marshal(assignment)
+	if err != nil {
+		logdebug.Warning("Failed to record task %s for crash reports: %v", taskID, err)
+		return
+	}
+
+	r.mu.Lock()
+	r.task = data
+	r.taskID = taskID
+	r.mu.Unlock()
+
+	record, _ := json.Marshal(map[string]interface{}{"task_id": taskID, "assignment": json.RawMessage(data)})
+	if err := os.WriteFile(filepath.Join(r.dir, taskFile), record, 0640); err != nil {
+		logdebug.Warning("Failed to persist task %s for crash reports: %v", taskID, err)
+	}
+}
+
+// ClearTask forgets the task recorded by SetTask once it has finished
+func ClearTask(taskID string) {
+	r := current()
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.taskID != taskID {
+		return
+	}
+	r.task = nil
+	r.taskID = ""
+	os.Remove(filepath.Join(r.dir, taskFile))
+}
+
+// SetDevices records the detected devices for crash reports
+func SetDevices(devices interface{}) {
+	r := current()
+	if r == nil {
+		return
+	}
+	data, err := json.Marshal(devices)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	r.devices = data
+	r.mu.Unlock()
+}
+
+// SetSender sets how bundles reach the backend and sends the pending ones.
+// send reports whether the bundle was handed over, sent bundles are deleted.
+func SetSender(send func(*Bundle) bool) {
+	r := current()
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.sender = send
+	r.mu.Unlock()
+	Flush()
+}
+
+// Flush sends the pending bundles through the sender set by SetSender
+func Flush() {
+	r := current()
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	send := r.sender
+	r.mu.Unlock()
+	if send == nil {
+		return
+	}
+
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+	for _, path := range r.pendingPaths() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var bundle Bundle
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			logdebug.Warning("Discarding unreadable crash bundle %s: %v", path, err)
+			os.Remove(path)
+			continue
+		}
+		if !send(&bundle) {
+			return
+		}
+		os.Remove(path)
+		logdebug.Info("Sent crash report %s (%s)", bundle.ID, bundle.Kind)
+	}
+}
+
+// Recover reports a panic of the goroutine it is deferred in and panics again,
+// so the agent still crashes as it would have. Use it as `defer crash.Recover()`.
+func Recover() {
+	p := recover()
+	if p == nil {
+		return
+	}
+	if r := current(); r != nil {
+		bundle := r.newBundle(KindAgentPanic, fmt.Sprintf("panic: %v", p))
+		bundle.StackTrace = truncateStart(string(debug.Stack()), maxStackBytes)
+		r.save(bundle)
+		// The runtime writes this panic to the crash output too, it is
+		// already reported with more context
+		os.WriteFile(filepath.Join(r.dir, reportedMarker), nil, 0640)
+	}
+	panic(p)
+}
+
+// ReportHashcatCrash reports hashcat dying of a fault while running taskID,
+// with the end of its output, and tries to send it right away
+func ReportHashcatCrash(taskID, reason, output string) {
+	r := current()
+	if r == nil {
+		return
+	}
+	bundle := r.newBundle(KindHashcatCrash, fmt.Sprintf("hashcat crashed: %s", reason))
+	bundle.HashcatOutput = truncateStart(output, maxOutputBytes)
+	if bundle.TaskID == "" {
+		bundle.TaskID = taskID
+	}
+	r.save(bundle)
+	go Flush()
+}
+
+// newBundle builds a bundle with the agent's current state
+func (r *reporter) newBundle(kind, summary string) *Bundle {
+	now := time.Now().UTC()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Bundle{
+		ID:             fmt.Sprintf("%s-%d", kind, now.UnixNano()),
+		Kind:           kind,
+		Summary:        summary,
+		OccurredAt:     now,
+		AgentVersion:   r.version,
+		OS:             runtime.GOOS,
+		Arch:           runtime.GOARCH,
+		GoVersion:      runtime.Version(),
+		Environment:    environment(os.Environ()),
+		Devices:        r.devices,
+		TaskID:         r.taskID,
+		TaskAssignment: r.task,
+		LogTail:        strings.Join(logdebug.RecentLines(), "\n"),
+	}
+}
+
+// save writes a bundle to the crash directory until it is sent
+func (r *reporter) save(bundle *Bundle) {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		logdebug.Error("Failed to encode crash bundle: %v", err)
+		return
+	}
+	path := filepath.Join(r.dir, bundle.ID+bundleSuffix)
+	if err := os.WriteFile(path, data, 0640); err != nil {
+		logdebug.Error("Failed to save crash bundle: %v", err)
+		return
+	}
+	logdebug.Error("Saved crash report %s: %s", bundle.ID, bundle.Summary)
+
+	// Keep the directory bounded if the backend is unreachable for long
+	paths := r.pendingPaths()
+	for len(paths) > maxPending {
+		os.Remove(paths[0])
+		paths = paths[1:]
+	}
+}
+
+// pendingPaths returns the saved bundles, oldest first
+func (r *reporter) pendingPaths() []string {
+	paths, _ := filepath.Glob(filepath.Join(r.dir, "*"+bundleSuffix))
+	sort.Slice(paths, func(i, j int) bool {
+		return bundleTime(paths[i]) < bundleTime(paths[j])
+	})
+	return paths
+}
+
+// bundleTime returns the creation time encoded in a bundle file name
+func bundleTime(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), bundleSuffix)
+	if i := strings.LastIndexByte(name, '-'); i >= 0 {
+		return fmt.Sprintf("%020s", name[i+1:])
+	}
+	return name
+}
+
+// collectPreviousCrash turns the runtime crash output of the previous run,
+// with the task it was running, into a bundle
+func (r *reporter) collectPreviousCrash() {
+	path := filepath.Join(r.dir, crashOutput)
+	data, err := os.ReadFile(path)
+	if os.Remove(filepath.Join(r.dir, reportedMarker)) == nil {
+		os.Remove(filepath.Join(r.dir, taskFile))
+		return
+	}
+	if err != nil || len(strings.TrimSpace(string(data))) == 0 {
+		return
+	}
+
+	occurredAt := time.Now().UTC()
+	if info, err := os.Stat(path); err == nil {
+		occurredAt = info.ModTime().UTC()
+	}
+
+	bundle := r.newBundle(KindAgentFatal, firstLine(string(data)))
+	bundle.ID = fmt.Sprintf("%s-%d", KindAgentFatal, occurredAt.UnixNano())
+	bundle.OccurredAt = occurredAt
+	bundle.StackTrace = truncateStart(string(data), maxStackBytes)
+	// The log lines of the crashed run are gone, these are of this one
+	bundle.LogTail = ""
+
+	if record, err := os.ReadFile(filepath.Join(r.dir, taskFile)); err == nil {
+		var task struct {
+			TaskID     string          `json:"task_id"`
+			Assignment json.RawMessage `json:"assignment"`
+		}
+		if json.Unmarshal(record, &task) == nil {
+			bundle.TaskID = task.TaskID
+			bundle.TaskAssignment = task.Assignment
+		}
+		os.Remove(filepath.Join(r.dir, taskFile))
+	}
+
+	r.save(bundle)
+}
+
+// environment returns the agent's own settings from env, with secrets masked
+func environment(env []string) map[string]string {
+	result := make(map[string]string)
+	for _, kv := range env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !relevantEnv(key) {
+			continue
+		}
+		upper := strings.ToUpper(key)
+		for _, secret := range []string{"KEY", "TOKEN", "SECRET", "PASSWORD", "CLAIM"} {
+			if strings.Contains(upper, secret) {
+				value = "[redacted]"
+				break
+			}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// relevantEnv reports whether an environment variable affects the agent or hashcat
+func relevantEnv(key string) bool {
+	switch key {
+	case "DEBUG", "LOG_LEVEL", "LOG_REDACT_HASHES", "USE_TLS", "HASHCAT_EXTRA_PARAMS",
+		"CUDA_VISIBLE_DEVICES", "HIP_VISIBLE_DEVICES", "GPU_DEVICE_ORDINAL":
+		return true
+	}
+	return strings.HasPrefix(key, "KH_")
+}
+
+// truncateStart keeps the last max bytes of s
+func truncateStart(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[len(s)-max:]
+}
+
+// firstLine returns the first non-empty line of s
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
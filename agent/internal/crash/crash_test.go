@@ -0,0 +1,100 @@
+package crash
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useReporter installs a reporter on dir for the duration of a test
+func useReporter(t *testing.T, dir string) *reporter {
+	r := &reporter{dir: dir, version: "test"}
+	stdMu.Lock()
+	std = r
+	stdMu.Unlock()
+	t.Cleanup(func() {
+		stdMu.Lock()
+		std = nil
+		stdMu.Unlock()
+	})
+	return r
+}
+
+func TestRecoverSavesBundleAndPanicsAgain(t *testing.T) {
+	dir := t.TempDir()
+	r := useReporter(t, dir)
+	SetTask("task-1", map[string]string{"task_id": "task-1"})
+
+	assert.PanicsWithValue(t, "boom", func() {
+		defer Recover()
+		panic("boom")
+	})
+
+	paths := r.pendingPaths()
+	require.Len(t, paths, 1)
+	data, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	var bundle Bundle
+	require.NoError(t, json.Unmarshal(data, &bundle))
+	assert.Equal(t, KindAgentPanic, bundle.Kind)
+	assert.Equal(t, "panic: boom", bundle.Summary)
+	assert.Equal(t, "task-1", bundle.TaskID)
+	assert.Contains(t, bundle.StackTrace, "TestRecoverSavesBundleAndPanicsAgain")
+
+	// The runtime's output of the same panic is not reported again
+	require.NoError(t, os.WriteFile(filepath.Join(dir, crashOutput), []byte("panic: boom"), 0640))
+	r.collectPreviousCrash()
+	assert.Len(t, r.pendingPaths(), 1)
+}
+
+func TestCollectPreviousCrash(t *testing.T) {
+	dir := t.TempDir()
+	r := useReporter(t, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, crashOutput), []byte("\nfatal error: concurrent map writes\n\ngoroutine 1 [running]:"), 0640))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, taskFile), []byte(`{"task_id":"task-2","assignment":{"attack_mode":0}}`), 0640))
+
+	r.collectPreviousCrash()
+
+	var sent []*Bundle
+	SetSender(func(b *Bundle) bool {
+		sent = append(sent, b)
+		return true
+	})
+	require.Len(t, sent, 1)
+	assert.Equal(t, KindAgentFatal, sent[0].Kind)
+	assert.Equal(t, "fatal error: concurrent map writes", sent[0].Summary)
+	assert.Equal(t, "task-2", sent[0].TaskID)
+	assert.JSONEq(t, `{"attack_mode":0}`, string(sent[0].TaskAssignment))
+	assert.Empty(t, r.pendingPaths(), "sent bundles are deleted")
+	assert.NoFileExists(t, filepath.Join(dir, taskFile))
+}
+
+func TestFlushKeepsUnsentBundles(t *testing.T) {
+	dir := t.TempDir()
+	r := useReporter(t, dir)
+	ReportHashcatCrash("task-3", "signal: segmentation fault", "Session..........: hashcat")
+
+	SetSender(func(*Bundle) bool { return false })
+	assert.Len(t, r.pendingPaths(), 1)
+}
+
+func TestEnvironment(t *testing.T) {
+	env := environment([]string{
+		"KH_HOST=backend.local",
+		"KH_CLAIM_CODE=ABCD",
+		"KH_API_KEY=secret",
+		"LOG_LEVEL=DEBUG",
+		"HOME=/root",
+		"AWS_SECRET_ACCESS_KEY=secret",
+	})
+	assert.Equal(t, map[string]string{
+		"KH_HOST":       "backend.local",
+		"KH_CLAIM_CODE": "[redacted]",
+		"KH_API_KEY":    "[redacted]",
+		"LOG_LEVEL":     "DEBUG",
+	}, env)
+}
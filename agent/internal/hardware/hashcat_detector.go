@@ -81,16 +81,56 @@ func (d *HashcatDetector) DetectDevices() (*types.DeviceDetectionResult, error)
 	debug.Info("Detected %d devices (filtered from %d total)", len(filteredDevices), len(devices))
 	
 	return &types.DeviceDetectionResult{
-		Devices: filteredDevices,
+		Devices:        filteredDevices,
+		DriverVersions: DriverVersions(devices),
 	}, nil
 }
 
+// DriverVersions returns the distinct driver versions of the devices, sorted
+func DriverVersions(devices []types.Device) []string {
+	seen := make(map[string]bool)
+	var versions []string
+	for _, device := range devices {
+		if device.DriverVersion != "" && !seen[device.DriverVersion] {
+			seen[device.DriverVersion] = true
+			versions = append(versions, device.DriverVersion)
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
 // findLatestHashcatBinary finds the most recent hashcat binary in the binaries directory
 func (d *HashcatDetector) findLatestHashcatBinary() (string, error) {
 	binariesDir := filepath.Join(d.dataDirectory, "binaries")
 	
-	// Look for the latest version directory
-	entries, err := os.ReadDir(binariesDir)
+	latestDir, err := d.latestBinaryDir()
+	if err != nil {
+		return "", err
+	}
+	
+	// Determine binary extension based on OS
+	var binaryName string
+	if runtime.GOOS == "windows" {
+		binaryName = "hashcat.exe"
+	} else {
+		binaryName = "hashcat.bin"
+	}
+	
+	binaryPath := filepath.Join(binariesDir, latestDir, binaryName)
+	
+	// Check if binary exists
+	if _, err := os.Stat(binaryPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("hashcat binary not found at %s", binaryPath)
+	}
+	
+	return binaryPath, nil
+}
+
+// latestBinaryDir returns the name of the newest binary version directory,
+// which is the backend's ID of the binary version
+func (d *HashcatDetector) latestBinaryDir() (string, error) {
+	entries, err := os.ReadDir(filepath.Join(d.dataDirectory, "binaries"))
 	if err != nil {
 		return "", fmt.Errorf("failed to read binaries directory: %w", err)
 	}
@@ -112,23 +152,14 @@ func (d *HashcatDetector) findLatestHashcatBinary() (string, error) {
 	if latestDir == "" {
 		return "", fmt.Errorf("no hashcat binary versions found")
 	}
-	
-	// Determine binary extension based on OS
-	var binaryName string
-	if runtime.GOOS == "windows" {
-		binaryName = "hashcat.exe"
-	} else {
-		binaryName = "hashcat.bin"
-	}
-	
-	binaryPath := filepath.Join(binariesDir, latestDir, binaryName)
-	
-	// Check if binary exists
-	if _, err := os.Stat(binaryPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("hashcat binary not found at %s", binaryPath)
-	}
-	
-	return binaryPath, nil
+	return latestDir, nil
+}
+
+// LatestBinaryVersion returns the ID of the hashcat binary version the agent
+// runs, or an empty string when no binary was downloaded yet
+func (d *HashcatDetector) LatestBinaryVersion() string {
+	version, _ := d.latestBinaryDir()
+	return version
 }
 
 // HasHashcatBinary checks if any hashcat binary is available
@@ -157,6 +188,7 @@ func (d *HashcatDetector) ParseHashcatOutput(output string) ([]types.Device, err
 	memoryTotalRe := regexp.MustCompile(`^\s*Memory\.Total\.+:\s+(\d+)\s+MB`)
 	memoryFreeRe := regexp.MustCompile(`^\s*Memory\.Free\.+:\s+(\d+)\s+MB`)
 	pciAddrRe := regexp.MustCompile(`^\s*PCI\.Addr\.(BDF|BDFe)\.+:\s+(.+)`)
+	driverVersionRe := regexp.MustCompile(`^\s*Driver\.Version\.+:\s+(.+)`)
 	
 	// First pass: collect alias information
 	tempScanner := bufio.NewScanner(strings.NewReader(output))
@@ -237,6 +269,8 @@ func (d *HashcatDetector) ParseHashcatOutput(output string) ([]types.Device, err
 				currentDevice.MemoryFree, _ = strconv.ParseInt(matches[1], 10, 64)
 			} else if matches := pciAddrRe.FindStringSubmatch(line); matches != nil {
 				currentDevice.PCIAddress = strings.TrimSpace(matches[2])
+			} else if matches := driverVersionRe.FindStringSubmatch(line); matches != nil {
+				currentDevice.DriverVersion = strings.TrimSpace(matches[1])
 			}
 		}
 	}
@@ -471,6 +471,37 @@ func TestHashcatDetector_DetectDevices_Integration(t *testing.T) {
 	assert.Error(t, err) // Expected to fail without real hashcat
 }
 
+func TestHashcatDetector_DriverVersions(t *testing.T) {
+	detector := &HashcatDetector{}
+	devices, err := detector.ParseHashcatOutput(createSampleHashcatOutput())
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+	assert.Empty(t, devices[0].DriverVersion)
+	assert.Equal(t, "535.129.03", devices[1].DriverVersion)
+
+	// Only the OpenCL alias of the CUDA device reports the driver
+	assert.Equal(t, []string{"535.129.03"}, DriverVersions(devices))
+
+	assert.Equal(t, []string{"23.40.2", "535.129.03"}, DriverVersions([]types.Device{
+		{ID: 1, DriverVersion: "535.129.03"},
+		{ID: 2, DriverVersion: "23.40.2"},
+		{ID: 3, DriverVersion: "535.129.03"},
+		{ID: 4},
+	}))
+	assert.Empty(t, DriverVersions(nil))
+}
+
+func TestHashcatDetector_LatestBinaryVersion(t *testing.T) {
+	dataDir := t.TempDir()
+	detector := NewHashcatDetector(dataDir)
+	assert.Empty(t, detector.LatestBinaryVersion())
+
+	for _, dir := range []string{"3", "12", "notes"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "binaries", dir), 0755))
+	}
+	assert.Equal(t, "12", detector.LatestBinaryVersion())
+}
+
 func BenchmarkParseHashcatOutput(b *testing.B) {
 	detector := &HashcatDetector{}
 	output := createSampleHashcatOutput()
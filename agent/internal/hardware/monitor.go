@@ -2,6 +2,7 @@ package hardware
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/hardware/types"
@@ -12,6 +13,7 @@ import (
 type Monitor struct {
 	mu             sync.RWMutex
 	devices        []types.Device
+	driverVersions []string
 	hashcatDetector *HashcatDetector
 	dataDirectory   string
 }
@@ -47,6 +49,7 @@ func (m *Monitor) DetectDevices() (*types.DeviceDetectionResult, error) {
 	// Store devices in monitor
 	m.mu.Lock()
 	m.devices = result.Devices
+	m.driverVersions = result.DriverVersions
 	m.mu.Unlock()
 	
 	return result, nil
@@ -57,6 +60,20 @@ func (m *Monitor) HasBinary() bool {
 	return m.hashcatDetector.HasHashcatBinary()
 }
 
+// DriverVersion returns the driver versions found by the last device
+// detection, comma separated, or an empty string before detection
+func (m *Monitor) DriverVersion() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	
+	return strings.Join(m.driverVersions, ", ")
+}
+
+// BinaryVersion returns the ID of the hashcat binary version in use
+func (m *Monitor) BinaryVersion() string {
+	return m.hashcatDetector.LatestBinaryVersion()
+}
+
 // GetDevices returns the currently detected devices
 func (m *Monitor) GetDevices() []types.Device {
 	m.mu.RLock()
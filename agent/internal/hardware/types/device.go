@@ -13,6 +13,7 @@ type Device struct {
 	MemoryTotal int64  `json:"memory_total,omitempty"` // MB
 	MemoryFree  int64  `json:"memory_free,omitempty"`  // MB
 	PCIAddress  string `json:"pci_address,omitempty"`
+	DriverVersion string `json:"driver_version,omitempty"` // Only reported by some backends, e.g. OpenCL
 	
 	// Backend information
 	Backend     string `json:"backend,omitempty"`      // "HIP", "OpenCL", "CUDA", etc.
@@ -23,7 +24,10 @@ type Device struct {
 // DeviceDetectionResult represents the result of device detection
 type DeviceDetectionResult struct {
 	Devices []Device `json:"devices"`
-	Error   string   `json:"error,omitempty"`
+	// DriverVersions are the distinct driver versions of all devices,
+	// including aliases that were filtered out
+	DriverVersions []string `json:"driver_versions,omitempty"`
+	Error          string   `json:"error,omitempty"`
 }
 
 // DeviceUpdate represents a device update request
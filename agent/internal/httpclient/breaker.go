@@ -0,0 +1,156 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
+)
+
+// ErrCircuitOpen is returned while the breaker rejects requests to the backend
+var ErrCircuitOpen = errors.New("backend circuit breaker is open")
+
+// Breaker defaults: consecutive failures that open the breaker and how long
+// it stays open before a probe request is let through
+const (
+	DefaultFailureThreshold = 5
+	DefaultCooldown         = 30 * time.Second
+)
+
+// backendBreaker is shared by every client of the backend
+var backendBreaker = NewBreaker(DefaultFailureThreshold, DefaultCooldown)
+
+// BackendBreaker returns the breaker of the backend's requests
+func BackendBreaker() *Breaker {
+	return backendBreaker
+}
+
+type breakerState int
+
+const (
+	stateClosed   breakerState = iota // Requests pass
+	stateOpen                         // Requests are rejected until the cooldown ends
+	stateHalfOpen                     // One probe request decides whether to close or reopen
+)
+
+// Breaker is a circuit breaker. It opens after a number of consecutive
+// failures, rejects requests for a cooldown, then lets a single probe through:
+// its success closes the breaker, its failure opens it again.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu         sync.Mutex
+	state      breakerState
+	failures   int
+	openedAt   time.Time
+	probeSince time.Time // When the current half-open probe was let through
+}
+
+// NewBreaker creates a closed breaker
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = DefaultFailureThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	return &Breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Allow reports whether a request may be sent, returning ErrCircuitOpen if
+// not. A request that was allowed must be followed by Success, Failure or
+// Release.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	switch b.state {
+	case stateOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = stateHalfOpen
+		b.probeSince = now
+		debug.Info("Backend circuit breaker half-open, sending a probe request")
+		return nil
+	case stateHalfOpen:
+		// A probe that never reported back does not block the breaker forever
+		if now.Sub(b.probeSince) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.probeSince = now
+		return nil
+	}
+	return nil
+}
+
+// Success records a request the backend answered, closing the breaker
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != stateClosed {
+		s := Snapshot()
+		debug.Info("Backend circuit breaker closed, backend reachable again (%d requests rejected while open)", s.ShortCircuits)
+	}
+	b.state = stateClosed
+	b.failures = 0
+}
+
+// Failure records a failed request. The breaker opens once the threshold of
+// consecutive failures is reached, or right away when a probe fails.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == stateHalfOpen || (b.state == stateClosed && b.failures >= b.threshold) {
+		b.state = stateOpen
+		b.openedAt = time.Now()
+		stats.breakerOpens.Add(1)
+		debug.Warning("Backend circuit breaker open after %d consecutive failures, pausing requests for %v", b.failures, b.cooldown)
+	}
+}
+
+// Release gives back an allowed request whose outcome says nothing about the
+// backend, such as one cancelled by its caller
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == stateHalfOpen {
+		b.probeSince = time.Time{}
+	}
+}
+
+// Wait blocks until the breaker lets a probe through again, or ctx is done
+func (b *Breaker) Wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		var delay time.Duration
+		switch b.state {
+		case stateOpen:
+			delay = b.cooldown - time.Since(b.openedAt)
+		case stateHalfOpen:
+			delay = b.cooldown - time.Since(b.probeSince)
+		}
+		b.mu.Unlock()
+		if delay <= 0 {
+			return nil
+		}
+
+		// Spread the callers out so they do not all probe at once
+		delay += DefaultPolicy.Backoff(0)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package httpclient sends the agent's HTTP requests to the backend with
+// retries and a circuit breaker. Transient failures are retried with capped
+// exponential backoff and full jitter, so agents do not retry in lockstep,
+// and once the backend keeps failing the breaker rejects requests until it
+// has had time to come back, instead of every download and sync hammering it.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Policy controls how often and how long a request is retried
+type Policy struct {
+	MaxRetries int           // Retries after the first attempt, 0 only applies the breaker
+	BaseDelay  time.Duration // Backoff ceiling of the first retry, doubled for each retry
+	MaxDelay   time.Duration // Cap of the backoff and of a Retry-After from the backend
+}
+
+// DefaultPolicy suits small API requests such as file lists and configuration
+var DefaultPolicy = Policy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// Backoff returns the delay before the given retry, counted from 0: a random
+// duration up to BaseDelay*2^retry, capped at MaxDelay
+func (p Policy) Backoff(retry int) time.Duration {
+	ceiling := p.MaxDelay
+	if retry < 32 {
+		if d := p.BaseDelay << uint(retry); d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// Stats are counters of the requests sent through this package since the
+// agent started
+type Stats struct {
+	Requests      int64 `json:"requests"`       // Attempts sent to the backend, retries included
+	Retries       int64 `json:"retries"`        // Attempts repeated after a transient failure
+	Failures      int64 `json:"failures"`       // Attempts that failed with a network error or 5xx status
+	ShortCircuits int64 `json:"short_circuits"` // Requests rejected while the breaker was open
+	BreakerOpens  int64 `json:"breaker_opens"`  // Times the breaker opened
+}
+
+var stats struct {
+	requests, retries, failures, shortCircuits, breakerOpens atomic.Int64
+}
+
+// Snapshot returns the current counters
+func Snapshot() Stats {
+	return Stats{
+		Requests:      stats.requests.Load(),
+		Retries:       stats.retries.Load(),
+		Failures:      stats.failures.Load(),
+		ShortCircuits: stats.shortCircuits.Load(),
+		BreakerOpens:  stats.breakerOpens.Load(),
+	}
+}
+
+// Client sends requests through an *http.Client with retries and the
+// backend's circuit breaker
+type Client struct {
+	client  *http.Client
+	policy  Policy
+	breaker *Breaker
+}
+
+// New wraps an *http.Client. All clients share the backend's breaker, so a
+// backend restart trips it once for every caller.
+func New(client *http.Client, policy Policy) *Client {
+	return &Client{client: client, policy: policy, breaker: backendBreaker}
+}
+
+// Do sends the request, retrying network errors and 408, 429, 502, 503 and
+// 504 responses. Requests with a body are only retried if it can be
+// recreated (http.NewRequest sets GetBody for in-memory bodies). Returns an
+// error wrapping ErrCircuitOpen while the breaker rejects requests.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := c.breaker.Allow(); err != nil {
+			stats.shortCircuits.Add(1)
+			return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Redacted(), err)
+		}
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				c.breaker.Release()
+				return nil, fmt.Errorf("failed to recreate request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		stats.requests.Add(1)
+		resp, err := c.client.Do(req)
+		switch {
+		case ctx.Err() != nil:
+			// Cancelled by the caller, says nothing about the backend
+			c.breaker.Release()
+			return resp, err
+		case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+			stats.failures.Add(1)
+			c.breaker.Failure()
+		default:
+			c.breaker.Success()
+		}
+
+		if !retryable(resp, err) || attempt >= c.policy.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		delay := c.policy.Backoff(attempt)
+		if after := retryAfter(resp); after > 0 {
+			delay = min(after, c.policy.MaxDelay)
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		stats.retries.Add(1)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// retryable reports whether a request failed for a reason worth retrying
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the delay of a Retry-After header given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/agent/pkg/console"
+	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
+)
+
+// ErrorCodeBinaryMismatch is the error code of a task that was refused
+// because the hashcat binary does not match the one the backend recorded
+const ErrorCodeBinaryMismatch = "binary_mismatch"
+
+// BinaryAttestation is the agent's check of its hashcat binary before a task,
+// reported to the backend with the first progress update
+type BinaryAttestation struct {
+	Executable string `json:"executable"`         // File name of the executable checked, e.g. hashcat.bin
+	SHA256     string `json:"sha256"`             // Hash of the local copy
+	Expected   string `json:"expected,omitempty"` // Hash the backend sent, empty if it sent none
+	Verified   bool   `json:"verified"`           // Whether the hashes matched
+}
+
+// BinaryMismatchError is returned when the SHA-256 hash of the hashcat binary
+// differs from the one the backend sent
+type BinaryMismatchError struct {
+	Attestation *BinaryAttestation
+}
+
+func (e *BinaryMismatchError) Error() string {
+	return fmt.Sprintf("hashcat binary hash mismatch for %s: expected %s, got %s",
+		e.Attestation.Executable, e.Attestation.Expected, e.Attestation.SHA256)
+}
+
+// attestHashcatBinary hashes the hashcat binary the task runs and checks it
+// against the hashes the backend sent. The binary is hashed for every task
+// rather than cached by modification time, which is trivial to forge. A
+// backend that sent no hash gets an unverified attestation and the task runs.
+func (jm *JobManager) attestHashcatBinary(assignment *JobTaskAssignment) (*BinaryAttestation, error) {
+	binaryPath, err := jm.executor.resolveHashcatBinary(assignment.BinaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve hashcat binary: %w", err)
+	}
+
+	file, err := os.Open(binaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hashcat binary: %w", err)
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return nil, fmt.Errorf("failed to hash hashcat binary: %w", err)
+	}
+
+	name := filepath.Base(binaryPath)
+	attestation := &BinaryAttestation{
+		Executable: name,
+		SHA256:     hex.EncodeToString(h.Sum(nil)),
+		Expected:   assignment.BinaryHashes[name],
+	}
+	if attestation.Expected == "" {
+		debug.Warning("Task %s: no recorded hash for hashcat binary %s, running it unverified", assignment.TaskID, name)
+		return attestation, nil
+	}
+
+	attestation.Verified = strings.EqualFold(attestation.SHA256, attestation.Expected)
+	if !attestation.Verified {
+		return attestation, &BinaryMismatchError{Attestation: attestation}
+	}
+	return attestation, nil
+}
+
+// handleBinaryMismatch refuses the task with a typed error, so the backend
+// dispatches it to another agent and alerts admins
+func (jm *JobManager) handleBinaryMismatch(assignment *JobTaskAssignment, mismatch *BinaryMismatchError) {
+	console.Error("Task %s refused: %v", assignment.TaskID, mismatch)
+
+	jm.mutex.RLock()
+	callback := jm.progressCallback
+	jm.mutex.RUnlock()
+
+	if callback != nil {
+		callback(&JobProgress{
+			TaskID:            assignment.TaskID,
+			Status:            "failed",
+			ErrorMessage:      mismatch.Error(),
+			ErrorCode:         ErrorCodeBinaryMismatch,
+			BinaryAttestation: mismatch.Attestation,
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttestHashcatBinary(t *testing.T) {
+	dataDir := t.TempDir()
+	content := "#!/bin/sh\necho hashcat\n"
+	for _, name := range []string{"hashcat.bin", "hashcat"} {
+		path := filepath.Join(dataDir, "binaries", "3", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0755))
+	}
+	sum := sha256.Sum256([]byte(content))
+	actual := hex.EncodeToString(sum[:])
+
+	jm := NewJobManager(&config.Config{DataDirectory: dataDir}, nil, nil)
+
+	tests := []struct {
+		name     string
+		hashes   map[string]string
+		verified bool
+		mismatch bool
+	}{
+		{"matching hash", map[string]string{"hashcat.bin": actual, "hashcat": actual}, true, false},
+		{"no recorded hash", nil, false, false},
+		{"modified binary", map[string]string{"hashcat.bin": "00ff", "hashcat": "00ff"}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attestation, err := jm.attestHashcatBinary(&JobTaskAssignment{
+				TaskID:       "task-1",
+				BinaryPath:   "binaries/3",
+				BinaryHashes: tt.hashes,
+			})
+
+			var mismatch *BinaryMismatchError
+			assert.Equal(t, tt.mismatch, errors.As(err, &mismatch))
+			if !tt.mismatch {
+				require.NoError(t, err)
+			}
+			require.NotNil(t, attestation)
+			assert.Equal(t, actual, attestation.SHA256)
+			assert.Equal(t, tt.verified, attestation.Verified)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package jobs
+
+import "strings"
+
+// extraParamAliases maps long hashcat options to their short form so that the
+// same option given in either form is recognised when merging parameters
+var extraParamAliases = map[string]string{
+	"--workload-profile":        "-w",
+	"--optimized-kernel-enable": "-O",
+	"--slow-candidates":         "-S",
+	"--kernel-accel":            "-n",
+	"--kernel-loops":            "-u",
+	"--kernel-threads":          "-T",
+	"--opencl-device-types":     "-D",
+}
+
+// extraParamOption returns the normalized option name a token sets, e.g. "-w"
+// for "-w3" or "--workload-profile=3", or "" when the token is a value
+func extraParamOption(token string) string {
+	if !strings.HasPrefix(token, "-") || len(token) < 2 {
+		return ""
+	}
+	name := token[:2]
+	if strings.HasPrefix(token, "--") {
+		name = token
+		if i := strings.Index(token, "="); i >= 0 {
+			name = token[:i]
+		}
+	}
+	if alias, ok := extraParamAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// groupExtraArgs splits argv tokens into options, each with the values that
+// follow it. Leading values without an option form a group of their own.
+func groupExtraArgs(args []string) [][]string {
+	var groups [][]string
+	for _, token := range args {
+		if extraParamOption(token) != "" || len(groups) == 0 {
+			groups = append(groups, []string{token})
+			continue
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], token)
+	}
+	return groups
+}
+
+// mergeExtraParams merges the job's extra parameters over the agent's. An
+// option set by the job replaces the same option from the agent, all other
+// agent options are kept in their original order ahead of the job's.
+func mergeExtraParams(agentParams, jobParams string) []string {
+	return mergeExtraArgs(strings.Fields(agentParams), strings.Fields(jobParams))
+}
+
+// mergeExtraArgs is mergeExtraParams for parameters already split into argv
+// tokens
+func mergeExtraArgs(agentArgs, jobArgs []string) []string {
+	jobGroups := groupExtraArgs(jobArgs)
+	jobOptions := make(map[string]bool, len(jobGroups))
+	for _, group := range jobGroups {
+		jobOptions[extraParamOption(group[0])] = true
+	}
+
+	var args []string
+	for _, group := range groupExtraArgs(agentArgs) {
+		if option := extraParamOption(group[0]); option != "" && jobOptions[option] {
+			continue
+		}
+		args = append(args, group...)
+	}
+	for _, group := range jobGroups {
+		args = append(args, group...)
+	}
+	return args
+}
@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeExtraParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		agent    string
+		job      string
+		expected []string
+	}{
+		{"agent only", "-w 3 -O", "", []string{"-w", "3", "-O"}},
+		{"job only", "", "-S --bitmap-max=24", []string{"-S", "--bitmap-max=24"}},
+		{"job replaces the same option", "-w 3 -O", "-w 4", []string{"-O", "-w", "4"}},
+		{"long and short forms match", "--workload-profile=3 --hwmon-disable", "-w4", []string{"--hwmon-disable", "-w4"}},
+		{"different options are kept", "-O", "-S --bitmap-max 24", []string{"-O", "-S", "--bitmap-max", "24"}},
+		{"custom charsets are options", "-1 ?l?d -w 3", "-w 4", []string{"-1", "?l?d", "-w", "4"}},
+		{"neither", "", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mergeExtraParams(tt.agent, tt.job))
+		})
+	}
+}
+
+func TestMergeExtraArgs(t *testing.T) {
+	// Argv tokens are kept whole, a value may contain spaces
+	assert.Equal(t,
+		[]string{"-O", "--encoding-to", "utf 8", "-w", "4"},
+		mergeExtraArgs([]string{"-w", "3", "-O", "--encoding-to", "utf 8"}, []string{"-w", "4"}))
+	assert.Empty(t, mergeExtraArgs(nil, nil))
+}
@@ -0,0 +1,193 @@
+package jobs
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	filesync "github.com/ZerkerEOD/krakenhashes/agent/internal/sync"
+	"github.com/ZerkerEOD/krakenhashes/agent/pkg/console"
+	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
+)
+
+// ErrorCodeFileMismatch is the error code of a task that failed because one
+// of its files does not match the server's copy
+const ErrorCodeFileMismatch = "file_mismatch"
+
+// FileMismatchError is returned when a wordlist or rule file of a task is
+// missing or its MD5 hash differs from the one the backend sent
+type FileMismatchError struct {
+	Path     string
+	Expected string
+	Actual   string
+}
+
+func (e *FileMismatchError) Error() string {
+	return fmt.Sprintf("file hash mismatch for %s: expected %s, got %s", e.Path, e.Expected, e.Actual)
+}
+
+// FileRepairReporter is told when a task file is re-downloaded after a hash
+// mismatch, so the backend holds back new work until the file is repaired
+type FileRepairReporter interface {
+	FileRepairStarted(path string)
+	FileRepairFinished(path string, err error)
+}
+
+// fileHashCache remembers the MD5 hashes of files by size and modification
+// time, so large wordlists are not read again for every chunk
+type fileHashCache struct {
+	mu      sync.Mutex
+	entries map[string]fileHashEntry
+}
+
+type fileHashEntry struct {
+	size    int64
+	modTime time.Time
+	hash    string
+}
+
+func newFileHashCache() *fileHashCache {
+	return &fileHashCache{entries: make(map[string]fileHashEntry)}
+}
+
+// hash returns the MD5 hash of the file at path
+func (c *fileHashCache) hash(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	c.mu.Unlock()
+	if ok && entry.size == info.Size() && entry.modTime.Equal(info.ModTime()) {
+		return entry.hash, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	c.mu.Lock()
+	c.entries[path] = fileHashEntry{size: info.Size(), modTime: info.ModTime(), hash: sum}
+	c.mu.Unlock()
+	return sum, nil
+}
+
+// SetFileRepairReporter sets who is told about file repairs
+func (jm *JobManager) SetFileRepairReporter(reporter FileRepairReporter) {
+	jm.mutex.Lock()
+	defer jm.mutex.Unlock()
+	jm.repairReporter = reporter
+}
+
+// verifyTaskFiles checks the task's wordlists and rules against the hashes
+// the backend sent, so a stale or corrupted file fails the task before
+// hashcat runs on it
+func (jm *JobManager) verifyTaskFiles(assignment *JobTaskAssignment) error {
+	paths := make([]string, 0, len(assignment.FileHashes))
+	for path := range assignment.FileHashes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		expected := assignment.FileHashes[path]
+		if expected == "" {
+			continue
+		}
+
+		actual, err := jm.fileHashes.hash(filepath.Join(jm.config.DataDirectory, path))
+		if errors.Is(err, os.ErrNotExist) {
+			actual = "missing file"
+		} else if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+		if !strings.EqualFold(actual, expected) {
+			return &FileMismatchError{Path: path, Expected: expected, Actual: actual}
+		}
+	}
+	return nil
+}
+
+// handleFileMismatch fails the task with a typed error, so the backend
+// dispatches it again, and re-downloads the mismatched file
+func (jm *JobManager) handleFileMismatch(assignment *JobTaskAssignment, mismatch *FileMismatchError) {
+	console.Warning("Task %s: %v, downloading the file again", assignment.TaskID, mismatch)
+
+	jm.mutex.RLock()
+	reporter := jm.repairReporter
+	callback := jm.progressCallback
+	jm.mutex.RUnlock()
+
+	// Tell the backend about the repair first, so it does not send the task
+	// straight back to this agent
+	if reporter != nil {
+		reporter.FileRepairStarted(mismatch.Path)
+	}
+	if callback != nil {
+		callback(&JobProgress{
+			TaskID:       assignment.TaskID,
+			Status:       "failed",
+			ErrorMessage: mismatch.Error(),
+			ErrorCode:    ErrorCodeFileMismatch,
+		})
+	}
+
+	go func() {
+		err := jm.repairFile(context.Background(), mismatch)
+		if err != nil {
+			debug.Error("Failed to repair %s: %v", mismatch.Path, err)
+			console.Error("Failed to download %s again: %v", mismatch.Path, err)
+		} else {
+			console.Success("Downloaded %s again", mismatch.Path)
+		}
+		if reporter != nil {
+			reporter.FileRepairFinished(mismatch.Path, err)
+		}
+	}()
+}
+
+// repairFile downloads a mismatched wordlist or rule file again, the download
+// replaces the local copy once its hash is verified
+func (jm *JobManager) repairFile(ctx context.Context, mismatch *FileMismatchError) error {
+	jm.mutex.RLock()
+	fileSync := jm.fileSync
+	jm.mutex.RUnlock()
+	if fileSync == nil {
+		return fmt.Errorf("file sync not initialized")
+	}
+
+	fileType, name, ok := strings.Cut(mismatch.Path, "/")
+	switch {
+	case ok && fileType == "wordlists":
+		fileType = "wordlist"
+	case ok && fileType == "rules":
+		fileType = "rule"
+	default:
+		return fmt.Errorf("cannot repair %s, only wordlists and rules are repaired", mismatch.Path)
+	}
+
+	return fileSync.DownloadFileFromInfo(ctx, &filesync.FileInfo{
+		Name:     name,
+		FileType: fileType,
+		MD5Hash:  mismatch.Expected,
+	})
+}
@@ -0,0 +1,157 @@
+package jobs
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func md5Hex(content string) string {
+	sum := md5.Sum([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeDataFile(t *testing.T, dataDir, path, content string) {
+	t.Helper()
+	full := filepath.Join(dataDir, path)
+	require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+	require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+}
+
+type recordingRepairReporter struct {
+	mu       sync.Mutex
+	started  []string
+	finished []error
+	done     chan struct{}
+}
+
+func (r *recordingRepairReporter) FileRepairStarted(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started = append(r.started, path)
+}
+
+func (r *recordingRepairReporter) FileRepairFinished(path string, err error) {
+	r.mu.Lock()
+	r.finished = append(r.finished, err)
+	r.mu.Unlock()
+	close(r.done)
+}
+
+func TestVerifyTaskFiles(t *testing.T) {
+	dataDir := t.TempDir()
+	writeDataFile(t, dataDir, "wordlists/general/words.txt", "password\nletmein\n")
+	writeDataFile(t, dataDir, "rules/hashcat/best.rule", ":\nc\n")
+
+	jm := NewJobManager(&config.Config{DataDirectory: dataDir}, nil, nil)
+
+	tests := []struct {
+		name     string
+		hashes   map[string]string
+		mismatch string
+		actual   string
+	}{
+		{
+			name: "matching files",
+			hashes: map[string]string{
+				"wordlists/general/words.txt": md5Hex("password\nletmein\n"),
+				"rules/hashcat/best.rule":     md5Hex(":\nc\n"),
+			},
+		},
+		{
+			name:   "no hashes",
+			hashes: nil,
+		},
+		{
+			name:   "hash not known to the server",
+			hashes: map[string]string{"wordlists/general/words.txt": ""},
+		},
+		{
+			name: "modified file",
+			hashes: map[string]string{
+				"wordlists/general/words.txt": md5Hex("password\n"),
+				"rules/hashcat/best.rule":     md5Hex(":\nc\n"),
+			},
+			mismatch: "wordlists/general/words.txt",
+			actual:   md5Hex("password\nletmein\n"),
+		},
+		{
+			name:     "missing file",
+			hashes:   map[string]string{"rules/hashcat/missing.rule": md5Hex("u\n")},
+			mismatch: "rules/hashcat/missing.rule",
+			actual:   "missing file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := jm.verifyTaskFiles(&JobTaskAssignment{TaskID: "task-1", FileHashes: tt.hashes})
+			if tt.mismatch == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var mismatch *FileMismatchError
+			require.True(t, errors.As(err, &mismatch), "got %v", err)
+			assert.Equal(t, tt.mismatch, mismatch.Path)
+			assert.Equal(t, tt.hashes[tt.mismatch], mismatch.Expected)
+			assert.Equal(t, tt.actual, mismatch.Actual)
+		})
+	}
+}
+
+func TestVerifyTaskFilesNoticesChanges(t *testing.T) {
+	dataDir := t.TempDir()
+	writeDataFile(t, dataDir, "wordlists/words.txt", "password\n")
+	jm := NewJobManager(&config.Config{DataDirectory: dataDir}, nil, nil)
+	assignment := &JobTaskAssignment{FileHashes: map[string]string{"wordlists/words.txt": md5Hex("password\n")}}
+
+	require.NoError(t, jm.verifyTaskFiles(assignment))
+
+	// A cached hash is not reused once the file changed
+	writeDataFile(t, dataDir, "wordlists/words.txt", "passw0rd\n")
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(dataDir, "wordlists/words.txt"), later, later))
+
+	var mismatch *FileMismatchError
+	assert.True(t, errors.As(jm.verifyTaskFiles(assignment), &mismatch))
+}
+
+func TestHandleFileMismatch(t *testing.T) {
+	var progress []*JobProgress
+	jm := NewJobManager(&config.Config{DataDirectory: t.TempDir()}, func(p *JobProgress) {
+		progress = append(progress, p)
+	}, nil)
+	reporter := &recordingRepairReporter{done: make(chan struct{})}
+	jm.SetFileRepairReporter(reporter)
+
+	mismatch := &FileMismatchError{Path: "wordlists/general/words.txt", Expected: "aa", Actual: "bb"}
+	jm.handleFileMismatch(&JobTaskAssignment{TaskID: "task-1"}, mismatch)
+
+	require.Len(t, progress, 1)
+	assert.Equal(t, "task-1", progress[0].TaskID)
+	assert.Equal(t, "failed", progress[0].Status)
+	assert.Equal(t, ErrorCodeFileMismatch, progress[0].ErrorCode)
+	assert.Equal(t, mismatch.Error(), progress[0].ErrorMessage)
+
+	select {
+	case <-reporter.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the repair did not finish")
+	}
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	assert.Equal(t, []string{"wordlists/general/words.txt"}, reporter.started)
+	// Without a file sync the download cannot run, the failure is reported
+	require.Len(t, reporter.finished, 1)
+	assert.Error(t, reporter.finished[0])
+}
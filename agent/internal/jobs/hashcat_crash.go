@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// Windows exception codes a crashed hashcat exits with
+var windowsCrashCodes = map[uint32]string{
+	0xC0000005: "access violation",
+	0xC000001D: "illegal instruction",
+	0xC0000094: "integer division by zero",
+	0xC00000FD: "stack overflow",
+	0xC0000409: "stack buffer overrun",
+}
+
+// hashcatCrashReason reports whether hashcat died of a fault rather than
+// exiting with one of its own exit codes, and describes the fault
+func hashcatCrashReason(exitErr *exec.ExitError) (string, bool) {
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		switch status.Signal() {
+		case syscall.SIGSEGV, syscall.SIGBUS, syscall.SIGILL, syscall.SIGFPE, syscall.SIGABRT:
+			return fmt.Sprintf("signal: %v", status.Signal()), true
+		}
+		return "", false
+	}
+	if reason, ok := windowsCrashCodes[uint32(exitErr.ExitCode())]; ok {
+		return fmt.Sprintf("exception 0x%X (%s)", uint32(exitErr.ExitCode()), reason), true
+	}
+	return "", false
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashcatCrashReason(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	run := func(script string) *exec.ExitError {
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(exec.Command("sh", "-c", script).Run(), &exitErr))
+		return exitErr
+	}
+
+	reason, crashed := hashcatCrashReason(run("kill -SEGV $$"))
+	assert.True(t, crashed)
+	assert.Equal(t, "signal: segmentation fault", reason)
+
+	_, crashed = hashcatCrashReason(run("exit 1"))
+	assert.False(t, crashed, "exhausted is a normal exit")
+
+	_, crashed = hashcatCrashReason(run("kill -TERM $$"))
+	assert.False(t, crashed, "being stopped is not a crash")
+}
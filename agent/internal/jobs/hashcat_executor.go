@@ -3,6 +3,7 @@ package jobs
 import (
 	"bufio"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,6 +19,8 @@ import (
 	"syscall"
 	"time"
 	
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/crash"
 	"github.com/ZerkerEOD/krakenhashes/agent/pkg/console"
 	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
 )
@@ -59,6 +62,22 @@ type JobTaskAssignment struct {
 	OutputFormat    string      `json:"output_format"`    // Hashcat output format
 	ExtraParameters string      `json:"extra_parameters,omitempty"` // Agent-specific hashcat parameters
 	EnabledDevices  []int       `json:"enabled_devices,omitempty"`  // List of enabled device IDs
+	JobExtraParameters string   `json:"job_extra_parameters,omitempty"` // Job-specific hashcat parameters, override the agent's
+	ExtraArgs       []string    `json:"extra_args,omitempty"`      // ExtraParameters as argv tokens, preferred over the string
+	JobArgs         []string    `json:"job_args,omitempty"`        // JobExtraParameters as argv tokens, preferred over the string
+	FileHashes      map[string]string `json:"file_hashes,omitempty"` // MD5 hashes of the wordlists and rules on the server, by path
+	RuleChunks      []RuleChunkFile   `json:"rule_chunks,omitempty"` // Registered rule chunk files of a rule-split task
+	BinaryHashes    map[string]string `json:"binary_hashes,omitempty"` // SHA-256 hashes of the hashcat executables on the server, by file name
+
+	binaryAttestation *BinaryAttestation // Check of the hashcat binary, reported with the first progress update
+}
+
+// RuleChunkFile describes a rule chunk file registered on the backend
+type RuleChunkFile struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"` // Path below the rules directory, e.g. chunks/job_<id>/chunk_0_1000.rule
+	MD5Hash string `json:"md5_hash"`
+	Size    int64  `json:"size"`
 }
 
 // DeviceMetric represents metrics for a single device
@@ -75,6 +94,7 @@ type DeviceMetric struct {
 type JobProgress struct {
 	TaskID                 string         `json:"task_id"`
 	KeyspaceProcessed      int64          `json:"keyspace_processed"`                   // Restore point (position in wordlist)
+	RestorePoint           *int64         `json:"restore_point,omitempty"`              // Restore point for checkpointing, only set when hashcat reported one
 	EffectiveProgress      int64          `json:"effective_progress"`                   // Actual effective progress (words × rules processed)
 	ProgressPercent        float64        `json:"progress_percent"`                     // Actual progress percentage (0-100)
 	TotalEffectiveKeyspace *int64         `json:"total_effective_keyspace,omitempty"`   // Only sent on first update - hashcat progress[1]
@@ -87,8 +107,13 @@ type JobProgress struct {
 	CrackedHashes          []CrackedHash  `json:"cracked_hashes"`                       // Detailed crack information
 	Status                 string         `json:"status,omitempty"`                     // Task status (running, completed, failed)
 	ErrorMessage           string         `json:"error_message,omitempty"`              // Error message if status is failed
+	ErrorCode              string         `json:"error_code,omitempty"`                 // Typed cause of a failure detected by the agent, e.g. file_mismatch
 	DeviceMetrics          []DeviceMetric `json:"device_metrics,omitempty"`              // Per-device metrics
 	AllHashesCracked       bool           `json:"all_hashes_cracked,omitempty"`         // Flag indicating all hashes in hashlist were cracked (exit code 6)
+	ExitCode               *int           `json:"exit_code,omitempty"`                  // Hashcat exit code, only on the final update
+	FinalStatus            json.RawMessage `json:"final_status,omitempty"`              // Last hashcat JSON status line, only on the final update
+	OutputTail             string         `json:"output_tail,omitempty"`                // Last hashcat output lines without cracks, only on the final update
+	BinaryAttestation      *BinaryAttestation `json:"binary_attestation,omitempty"`     // Check of the hashcat binary, only on the first update or a binary_mismatch failure
 }
 
 // CrackedHash represents a cracked hash with all available information
@@ -125,6 +150,9 @@ type HashcatExecutor struct {
 	// Agent's default extra parameters for hashcat
 	agentExtraParams string
 
+	// How hashcat runs are confined, unconfined by default
+	sandbox config.HashcatSandbox
+
 	// Crack batching - reduces message flood when many hashes crack simultaneously
 	crackBatchMutex    sync.Mutex
 	crackBatchBuffers  map[string][]CrackedHash // Buffer per task ID
@@ -155,9 +183,16 @@ type HashcatProcess struct {
 
 	// Error tracking
 	AlreadyRunningError bool
+	StderrTail          []string   // Last stderr lines, attached to failure reports for classification
+	ExitCode            *int       // Set once hashcat has exited
+	LastStatusJSON      string     // Most recent JSON status line
+	Output              outputTail // Recent output, sent with the final update for post-mortems
 	mutex              sync.Mutex
 }
 
+// maxStderrTailLines caps how many stderr lines are kept for failure reports
+const maxStderrTailLines = 20
+
 
 // NewHashcatExecutor creates a new hashcat executor
 func NewHashcatExecutor(dataDirectory string) *HashcatExecutor {
@@ -420,16 +455,24 @@ func (e *HashcatExecutor) buildHashcatCommandWithOptions(assignment *JobTaskAssi
 	}
 	// If no devices specified, hashcat will use all available devices
 	
-	// Add extra parameters - prefer task-specific over agent defaults
-	extraParams := assignment.ExtraParameters
-	if extraParams == "" && e.agentExtraParams != "" {
-		extraParams = e.agentExtraParams
+	// Add extra parameters - prefer task-specific over agent defaults. The
+	// argv forms are passed through as they are; the strings are only split
+	// for backends that do not send them.
+	extraArgs := assignment.ExtraArgs
+	if extraArgs == nil {
+		extraArgs = strings.Fields(assignment.ExtraParameters)
+	}
+	if len(extraArgs) == 0 && e.agentExtraParams != "" {
+		extraArgs = strings.Fields(e.agentExtraParams)
+	}
+	jobArgs := assignment.JobArgs
+	if jobArgs == nil {
+		jobArgs = strings.Fields(assignment.JobExtraParameters)
 	}
 	
-	if extraParams != "" {
-		debug.Info("Adding extra parameters: %s", extraParams)
-		// Split the extra parameters by space and append them
-		extraParamsList := strings.Fields(extraParams)
+	// The job's own parameters replace the same options from the agent
+	if extraParamsList := mergeExtraArgs(extraArgs, jobArgs); len(extraParamsList) > 0 {
+		debug.Info("Adding extra parameters: %s", strings.Join(extraParamsList, " "))
 		args = append(args, extraParamsList...)
 	}
 	
@@ -497,19 +540,21 @@ func (e *HashcatExecutor) buildHashcatCommandWithOptions(assignment *JobTaskAssi
 
 	case int(AttackModeBruteForce): // Mask attack
 		if assignment.Mask != "" {
-			args = append(args, assignment.Mask)
+			args = append(args, maskArgs(assignment.Mask)...)
 		}
 
 	case int(AttackModeHybridWordlistMask): // Hybrid Wordlist + Mask
 		if len(assignment.WordlistPaths) > 0 && assignment.Mask != "" {
 			wordlistPath := filepath.Join(e.dataDirectory, assignment.WordlistPaths[0])
-			args = append(args, wordlistPath, assignment.Mask)
+			args = append(args, wordlistPath)
+			args = append(args, maskArgs(assignment.Mask)...)
 		}
 
 	case int(AttackModeHybridMaskWordlist): // Hybrid Mask + Wordlist
 		if assignment.Mask != "" && len(assignment.WordlistPaths) > 0 {
 			wordlistPath := filepath.Join(e.dataDirectory, assignment.WordlistPaths[0])
-			args = append(args, assignment.Mask, wordlistPath)
+			args = append(args, maskArgs(assignment.Mask)...)
+			args = append(args, wordlistPath)
 		}
 
 	default:
@@ -537,7 +582,10 @@ func (e *HashcatExecutor) buildHashcatCommandWithOptions(assignment *JobTaskAssi
 
 // runHashcatProcess executes and monitors a hashcat process
 func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *HashcatProcess, stdoutPipe, stderrPipe io.ReadCloser) {
+	defer crash.Recover()
+	crash.SetTask(process.TaskID, process.Assignment)
 	defer func() {
+		crash.ClearTask(process.TaskID)
 		e.mutex.Lock()
 		delete(e.activeProcesses, process.TaskID)
 		e.mutex.Unlock()
@@ -583,7 +631,7 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 		for scanner.Scan() {
 			line := scanner.Text()
 			lineCount++
-			debug.Debug("[Hashcat stdout raw] %s", line)
+			debug.Debug("[Hashcat stdout raw] %s", debug.CrackLine(line))
 
 			// Store original line for outputCallback
 			originalLine := line
@@ -597,7 +645,7 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 					// This is a crack line - skip outputCallback and add to batch
 					e.addCrackToBatch(process, cracked)
 					debug.Info("[Hashcat cracked] Hash: %s, Plain: %s",
-						cracked.Hash, cracked.Plain)
+						debug.Hash(cracked.Hash), debug.Plain(cracked.Plain))
 					// Skip the rest of processing for this line
 					continue
 				}
@@ -618,7 +666,7 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 						// Add crack to batch instead of sending immediately
 						e.addCrackToBatch(process, cracked)
 						debug.Info("[Hashcat cracked] Hash: %s, Plain: %s",
-							cracked.Hash, cracked.Plain)
+							debug.Hash(cracked.Hash), debug.Plain(cracked.Plain))
 						// For combined lines, still send the JSON part via outputCallback
 					}
 				}
@@ -632,6 +680,11 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 			if e.outputCallback != nil {
 				e.outputCallback(process.TaskID, originalLine, false)
 			}
+
+			// Keep the output for the task artifacts, without the crack part of a combined line
+			process.mutex.Lock()
+			process.Output.add(line)
+			process.mutex.Unlock()
 			
 			// Check if this is a JSON status line
 			if strings.HasPrefix(line, "{") && strings.Contains(line, "\"status\"") {
@@ -643,6 +696,10 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 				
 				var status map[string]interface{}
 				if err := json.Unmarshal([]byte(fixedLine), &status); err == nil {
+					process.mutex.Lock()
+					process.LastStatusJSON = fixedLine
+					process.mutex.Unlock()
+
 					// Check if this is a final status update and detect if all hashes are cracked
 					var allHashesCracked bool
 					if statusCode, ok := status["status"].(float64); ok {
@@ -665,8 +722,10 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 					if progressArr, ok := status["progress"].([]interface{}); ok && len(progressArr) >= 2 {
 						// Extract restore point for resume capability (position in wordlist)
 						var keyspaceProcessed int64
+						var checkpoint *int64
 						if restorePoint, ok := status["restore_point"].(float64); ok {
 							keyspaceProcessed = int64(restorePoint)
+							checkpoint = &keyspaceProcessed
 						}
 
 						// Extract progress values for percentage calculation
@@ -690,12 +749,18 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 						progress := &JobProgress{
 							TaskID:            process.TaskID,
 							KeyspaceProcessed: keyspaceProcessed,  // Restore point (word position)
+							RestorePoint:      checkpoint,         // Lets the backend resume the chunk here after a crash
 							EffectiveProgress: currentProgress,     // Actual effective progress
 							ProgressPercent:   progressPercent,     // Actual progress percentage
 							IsFirstUpdate:     isFirstUpdate,       // Flag indicating first update
 							AllHashesCracked:  allHashesCracked,    // Flag when status code 6 detected
 						}
 
+						// The task start report carries the hashcat binary check
+						if isFirstUpdate && process.Assignment != nil {
+							progress.BinaryAttestation = process.Assignment.binaryAttestation
+						}
+
 						// Always include total effective keyspace from hashcat
 						if totalProgress > 0 {
 							progress.TotalEffectiveKeyspace = &totalProgress  // Hashcat's progress[1]
@@ -775,7 +840,7 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 			} else {
 				// Not JSON - could be informational output
 				// (Crack lines are already handled at the beginning of the loop)
-				debug.Debug("[Hashcat stdout] %s", line)
+				debug.Debug("[Hashcat stdout] %s", debug.CrackLine(line))
 			}
 		}
 		
@@ -827,6 +892,17 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 				}
 			}
 			
+			// Keep the most recent lines so failures carry hashcat's own explanation
+			if strings.TrimSpace(line) != "" {
+				process.mutex.Lock()
+				process.StderrTail = append(process.StderrTail, line)
+				if len(process.StderrTail) > maxStderrTailLines {
+					process.StderrTail = process.StderrTail[len(process.StderrTail)-maxStderrTailLines:]
+				}
+				process.Output.add("stderr: " + line)
+				process.mutex.Unlock()
+			}
+			
 			// Send error output via websocket if callback is set
 			if e.outputCallback != nil {
 				e.outputCallback(process.TaskID, line, true)
@@ -861,8 +937,16 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 	debug.Info("Starting hashcat process for task %s", process.TaskID)
 	debug.Info("Command: %s", process.Cmd.Path)
 	debug.Info("Args: %v", process.Cmd.Args)
+
+	cleanupSandbox, err := e.sandboxCommand(process.Cmd, "hashcat-"+process.TaskID)
+	if err != nil {
+		debug.Error("Failed to sandbox hashcat process: %v", err)
+		e.sendErrorProgress(process, fmt.Sprintf("Failed to sandbox hashcat: %v", err))
+		return
+	}
+	defer cleanupSandbox()
 	
-	err := process.Cmd.Start()
+	err = process.Cmd.Start()
 	if err != nil {
 		debug.Error("Failed to start hashcat process: %v", err)
 		e.sendErrorProgress(process, fmt.Sprintf("Failed to start hashcat: %v", err))
@@ -919,6 +1003,15 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 			if exitErr, ok := err.(*exec.ExitError); ok {
 				exitCode := exitErr.ExitCode()
 				debug.Info("Hashcat exited with code: %d for task %s", exitCode, process.TaskID)
+				process.mutex.Lock()
+				process.ExitCode = &exitCode
+				output := process.Output.String()
+				process.mutex.Unlock()
+
+				if reason, crashed := hashcatCrashReason(exitErr); crashed {
+					debug.Error("Hashcat crashed for task %s: %s", process.TaskID, reason)
+					crash.ReportHashcatCrash(process.TaskID, reason, output)
+				}
 				
 				// Hashcat exit codes:
 				// 0 = OK/cracked
@@ -1022,6 +1115,10 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 		} else {
 			// Process completed successfully with exit code 0
 			debug.Info("Hashcat completed successfully with exit code 0 (OK/cracked) for task %s", process.TaskID)
+			exitCode := 0
+			process.mutex.Lock()
+			process.ExitCode = &exitCode
+			process.mutex.Unlock()
 			// Use the last progress percentage if available, otherwise 100%
 			progressPercent := 100.0
 			var effectiveProgress int64
@@ -1049,6 +1146,9 @@ func (e *HashcatExecutor) runHashcatProcess(ctx context.Context, process *Hashca
 func (e *HashcatExecutor) sendProgressUpdate(process *HashcatProcess, progress *JobProgress, status string) {
 	// Set the status in the progress update
 	progress.Status = status
+	if status != "running" {
+		e.attachArtifacts(process, progress)
+	}
 	
 	select {
 	case process.ProgressChannel <- progress:
@@ -1059,8 +1159,29 @@ func (e *HashcatExecutor) sendProgressUpdate(process *HashcatProcess, progress *
 	}
 }
 
-// sendErrorProgress sends an error progress update
+// attachArtifacts adds the exit code, the last status and the recent output
+// of the process to its final progress update, so the backend can keep them
+// for post-mortems after the agent's own logs are gone
+func (e *HashcatExecutor) attachArtifacts(process *HashcatProcess, progress *JobProgress) {
+	process.mutex.Lock()
+	defer process.mutex.Unlock()
+
+	progress.ExitCode = process.ExitCode
+	if process.LastStatusJSON != "" {
+		progress.FinalStatus = json.RawMessage(process.LastStatusJSON)
+	}
+	progress.OutputTail = process.Output.String()
+}
+
+// sendErrorProgress sends an error progress update. The captured stderr tail
+// is appended so the backend can classify the failure.
 func (e *HashcatExecutor) sendErrorProgress(process *HashcatProcess, errorMsg string) {
+	process.mutex.Lock()
+	if len(process.StderrTail) > 0 {
+		errorMsg = errorMsg + "\n" + strings.Join(process.StderrTail, "\n")
+	}
+	process.mutex.Unlock()
+
 	progress := &JobProgress{
 		TaskID:       process.TaskID,
 		Status:       "failed",
@@ -1296,6 +1417,12 @@ func (e *HashcatExecutor) RunSpeedTest(ctx context.Context, assignment *JobTaskA
 		return 0, nil, 0, fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
+	cleanupSandbox, err := e.sandboxCommand(cmd, fmt.Sprintf("speedtest-%d", time.Now().UnixNano()))
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("failed to sandbox hashcat: %w", err)
+	}
+	defer cleanupSandbox()
+
 	// Start the command
 	if err := cmd.Start(); err != nil {
 		return 0, nil, 0, fmt.Errorf("failed to start hashcat: %w", err)
@@ -1316,7 +1443,7 @@ func (e *HashcatExecutor) RunSpeedTest(ctx context.Context, assignment *JobTaskA
 			default:
 				line := scanner.Text()
 				if strings.TrimSpace(line) != "" {
-					debug.Debug("[Speed test stdout raw] %s", line)
+					debug.Debug("[Speed test stdout raw] %s", debug.CrackLine(line))
 					// Sometimes hashcat outputs crack result and JSON on same line
 					// First check if line contains both crack and JSON
 					if strings.Contains(line, ":") && strings.Contains(line, "{") && strings.Contains(line, "\"status\"") {
@@ -1671,10 +1798,11 @@ func (e *HashcatExecutor) parseCrackedHash(line string, hashlistContent []string
 					cracked := &CrackedHash{
 						Hash:     knownHash,  // Use original hash from hashlist (lowercase as stored in DB)
 						Plain:    password,   // Password with original case
+						HexPlain: hexPlain(password),
 						FullLine: line,       // Keep the full line for reference
 					}
 
-					debug.Debug("[Crack Parser] Matched hash: %s, Password: %s", knownHash, password)
+					debug.Debug("[Crack Parser] Matched hash: %s, Password: %s", debug.Hash(knownHash), debug.Plain(password))
 					return cracked
 				}
 			}
@@ -1690,16 +1818,31 @@ func (e *HashcatExecutor) parseCrackedHash(line string, hashlistContent []string
 			cracked := &CrackedHash{
 				Hash:     parts[0],
 				Plain:    parts[1],
+				HexPlain: hexPlain(parts[1]),
 				FullLine: line,
 			}
 
 			// Only return if it looks like a valid hash
 			if len(cracked.Hash) >= 16 && !strings.Contains(cracked.Hash, " ") {
-				debug.Warning("[Crack Parser] Using fallback parsing for: %s", line)
+				debug.Warning("[Crack Parser] Using fallback parsing for: %s", debug.CrackLine(line))
 				return cracked
 			}
 		}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// hexPlain returns the hex encoding of the bytes of a cracked password. Hashcat
+// prints passwords that are not printable text as $HEX[...], which is decoded
+// here, and the backend relies on the hex form since JSON cannot carry bytes
+// that are not valid UTF-8.
+func hexPlain(plain string) string {
+	if strings.HasPrefix(plain, "$HEX[") && strings.HasSuffix(plain, "]") {
+		inner := plain[len("$HEX[") : len(plain)-1]
+		if _, err := hex.DecodeString(inner); err == nil {
+			return strings.ToLower(inner)
+		}
+	}
+	return hex.EncodeToString([]byte(plain))
+}
@@ -91,4 +91,21 @@ func TestParseCrackedHash(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestHexPlain(t *testing.T) {
+	tests := []struct {
+		plain string
+		want  string
+	}{
+		{"Admin@1234", "41646d696e4031323334"},
+		{"$HEX[70E47373]", "70e47373"},
+		{"$HEX[zz]", "244845585b7a7a5d"},
+		{"pä", "70c3a4"},
+	}
+	for _, tt := range tests {
+		if got := hexPlain(tt.plain); got != tt.want {
+			t.Errorf("hexPlain(%q) = %s, want %s", tt.plain, got, tt.want)
+		}
+	}
+}
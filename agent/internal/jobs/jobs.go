@@ -3,6 +3,7 @@ package jobs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/crash"
 	filesync "github.com/ZerkerEOD/krakenhashes/agent/internal/sync"
 	"github.com/ZerkerEOD/krakenhashes/agent/pkg/console"
 	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
@@ -25,6 +27,8 @@ type JobManager struct {
 	outputCallback   func(taskID string, output string, isError bool) // Callback for sending output via websocket
 	fileSync         *filesync.FileSync
 	hwMonitor        HardwareMonitor // Interface for hardware monitor
+	repairReporter   FileRepairReporter
+	fileHashes       *fileHashCache
 	
 	// Job state
 	mutex           sync.RWMutex
@@ -63,6 +67,11 @@ func NewJobManager(cfg *config.Config, progressCallback func(*JobProgress), hwMo
 	
 	// Set the agent's hashcat extra parameters
 	executor.SetAgentExtraParams(cfg.HashcatExtraParams)
+	executor.SetSandbox(cfg.HashcatSandbox)
+	if cfg.HashcatSandbox.Enabled() {
+		debug.Info("Hashcat runs are sandboxed: user=%q bwrap=%v memory_max_mb=%d cpu_max=%g",
+			cfg.HashcatSandbox.User, cfg.HashcatSandbox.Bubblewrap, cfg.HashcatSandbox.MemoryMaxMB, cfg.HashcatSandbox.CPUMax)
+	}
 	
 	// Set device flags callback if hardware monitor is available
 	if hwMonitor != nil {
@@ -78,6 +87,7 @@ func NewJobManager(cfg *config.Config, progressCallback func(*JobProgress), hwMo
 		hwMonitor:        hwMonitor,
 		activeJobs:       make(map[string]*JobExecution),
 		benchmarkCache:   make(map[string]*BenchmarkResult),
+		fileHashes:       newFileHashCache(),
 	}
 }
 
@@ -118,6 +128,32 @@ func (jm *JobManager) GetCurrentTaskStatus() (hasTask bool, taskID string, jobID
 	return false, "", "", 0
 }
 
+// GetGPUUtilization returns the average device utilization reported by the
+// running task's latest progress update, or nil when nothing is running
+func (jm *JobManager) GetGPUUtilization() *float64 {
+	jm.mutex.RLock()
+	defer jm.mutex.RUnlock()
+	
+	for _, execution := range jm.activeJobs {
+		progress := execution.LastProgress
+		if progress == nil {
+			return nil
+		}
+		if len(progress.DeviceMetrics) == 0 {
+			return progress.Utilization
+		}
+		
+		var total float64
+		for _, device := range progress.DeviceMetrics {
+			total += device.Util
+		}
+		avg := total / float64(len(progress.DeviceMetrics))
+		return &avg
+	}
+	
+	return nil
+}
+
 // SetProgressCallback sets the progress callback function
 func (jm *JobManager) SetProgressCallback(callback func(*JobProgress)) {
 	jm.mutex.Lock()
@@ -159,6 +195,29 @@ func (jm *JobManager) ProcessJobAssignment(ctx context.Context, assignmentData [
 		return fmt.Errorf("failed to ensure rule chunks: %w", err)
 	}
 
+	// Fail fast on wordlists and rules that differ from the server's copy
+	err = jm.verifyTaskFiles(&assignment)
+	if err != nil {
+		var mismatch *FileMismatchError
+		if errors.As(err, &mismatch) {
+			jm.handleFileMismatch(&assignment, mismatch)
+		}
+		return fmt.Errorf("failed to verify task files: %w", err)
+	}
+
+	// Refuse to run a hashcat binary that differs from the server's
+	attestation, err := jm.attestHashcatBinary(&assignment)
+	if err != nil {
+		var mismatch *BinaryMismatchError
+		if errors.As(err, &mismatch) {
+			jm.handleBinaryMismatch(&assignment, mismatch)
+			return fmt.Errorf("failed to attest hashcat binary: %w", err)
+		}
+		// A missing binary fails the task when hashcat is started
+		debug.Warning("Task %s: could not attest hashcat binary: %v", assignment.TaskID, err)
+	}
+	assignment.binaryAttestation = attestation
+
 	// Run benchmark if needed
 	err = jm.ensureBenchmark(ctx, &assignment)
 	if err != nil {
@@ -199,8 +258,8 @@ func (jm *JobManager) ensureHashlist(ctx context.Context, assignment *JobTaskAss
 	}
 
 	// Build the expected local path
-	hashlistFileName := fmt.Sprintf("%d.hash", assignment.HashlistID)
-	localPath := filepath.Join(jm.config.DataDirectory, "hashlists", hashlistFileName)
+	hashlistFileName := filesync.HashlistFileName(assignment.HashlistID, assignment.HashlistPath)
+	localPath := filepath.Join(jm.config.DataDirectory, "hashlists", filepath.FromSlash(hashlistFileName))
 	
 	debug.Info("Ensuring hashlist %d is available", assignment.HashlistID)
 	debug.Info("Expected local path: %s", localPath)
@@ -245,102 +304,69 @@ func (jm *JobManager) ensureHashlist(ctx context.Context, assignment *JobTaskAss
 	return nil
 }
 
-// ensureRuleChunks downloads rule chunk files if the job uses rule splitting
+// ensureRuleChunks downloads the rule chunk files of a rule-split task.
+// Registered chunks come with their MD5 hash, so a local copy is reused only
+// when it matches and downloads are verified like other synced files.
 func (jm *JobManager) ensureRuleChunks(ctx context.Context, assignment *JobTaskAssignment) error {
 	if jm.fileSync == nil {
 		debug.Warning("File sync not initialized, skipping rule chunk download")
 		return nil
 	}
-	
-	// Check if this job has rule chunks (rule paths that contain "chunks/")
-	hasRuleChunks := false
-	for _, rulePath := range assignment.RulePaths {
-		if strings.Contains(rulePath, "rules/chunks/") {
-			hasRuleChunks = true
-			break
+
+	chunks := assignment.RuleChunks
+	if len(chunks) == 0 {
+		// Backends that do not register chunks only send their paths
+		for _, rulePath := range assignment.RulePaths {
+			if strings.HasPrefix(rulePath, "rules/chunks/") {
+				chunks = append(chunks, RuleChunkFile{Name: strings.TrimPrefix(rulePath, "rules/")})
+			}
 		}
 	}
-	
-	if !hasRuleChunks {
-		// No rule chunks to download
+	if len(chunks) == 0 {
 		return nil
 	}
-	
+
 	debug.Info("Job uses rule chunks, ensuring they are downloaded")
-	
-	// Process each rule chunk
-	for _, rulePath := range assignment.RulePaths {
-		if !strings.HasPrefix(rulePath, "rules/chunks/") {
-			continue // Skip non-chunk rules
-		}
-		
-		// Extract the chunk filename and job directory
-		// Format: rules/chunks/job_<ID>/chunk_<N>.rule
-		parts := strings.Split(rulePath, "/")
-		if len(parts) < 3 {
-			debug.Error("Invalid rule chunk path format: %s", rulePath)
-			continue
-		}
-		
-		var jobDir string
-		var chunkFile string
-		
-		// Check if path includes job directory
-		if len(parts) == 4 && strings.HasPrefix(parts[2], "job_") {
-			// Format: rules/chunks/job_<ID>/chunk_<N>.rule
-			jobDir = parts[2]
-			chunkFile = parts[3]
-		} else if len(parts) == 3 {
-			// Format: rules/chunks/chunk_<N>.rule (legacy)
-			chunkFile = parts[2]
-		}
-		
-		// Check if chunk already exists locally
-		localPath := filepath.Join(jm.config.DataDirectory, rulePath)
+
+	for _, chunk := range chunks {
+		localPath := filepath.Join(jm.config.DataDirectory, "rules", filepath.FromSlash(chunk.Name))
 		if _, err := os.Stat(localPath); err == nil {
-			debug.Info("Rule chunk already exists locally: %s", localPath)
-			continue
-		}
-		
-		// Create directory structure if needed
-		localDir := filepath.Dir(localPath)
-		if err := os.MkdirAll(localDir, 0755); err != nil {
-			debug.Error("Failed to create rule chunk directory %s: %v", localDir, err)
-			return fmt.Errorf("failed to create rule chunk directory: %w", err)
-		}
-		
-		// Prepare file info for download
-		// The backend serves chunks at /api/files/rule/chunks/<filename> or /api/files/rule/chunks/<jobDir>/<filename>
-		var fileInfo *filesync.FileInfo
-		if jobDir != "" {
-			fileInfo = &filesync.FileInfo{
-				Name:     fmt.Sprintf("%s/%s", jobDir, chunkFile),
-				FileType: "rule",
-				Category: "chunks",
+			if chunk.MD5Hash == "" {
+				debug.Info("Rule chunk already exists locally: %s", localPath)
+				continue
 			}
-		} else {
-			fileInfo = &filesync.FileInfo{
-				Name:     chunkFile,
-				FileType: "rule",
-				Category: "chunks",
+			hash, err := jm.fileHashes.hash(localPath)
+			if err == nil && strings.EqualFold(hash, chunk.MD5Hash) {
+				debug.Info("Rule chunk already exists locally with a matching hash: %s", localPath)
+				continue
 			}
+			debug.Warning("Rule chunk %s differs from the server's copy, downloading it again", chunk.Name)
 		}
-		
-		debug.Info("Downloading rule chunk: %s", fileInfo.Name)
+
+		// The name keeps the chunks/job_<id>/ directories, so the chunk is
+		// stored below the rules directory where hashcat is pointed at it
+		fileInfo := &filesync.FileInfo{
+			ID:       int(chunk.ID),
+			Name:     chunk.Name,
+			FileType: "rule",
+			MD5Hash:  chunk.MD5Hash,
+			Size:     chunk.Size,
+		}
+
+		debug.Info("Downloading rule chunk: %s", chunk.Name)
 		if err := jm.fileSync.DownloadFileFromInfo(ctx, fileInfo); err != nil {
-			debug.Error("Failed to download rule chunk %s: %v", fileInfo.Name, err)
-			return fmt.Errorf("failed to download rule chunk %s: %w", fileInfo.Name, err)
+			debug.Error("Failed to download rule chunk %s: %v", chunk.Name, err)
+			return fmt.Errorf("failed to download rule chunk %s: %w", chunk.Name, err)
 		}
-		
-		// Verify the file was created
-		if fileInfo, err := os.Stat(localPath); err == nil {
-			debug.Info("Successfully downloaded rule chunk: %s (size: %d bytes)", chunkFile, fileInfo.Size())
+
+		if info, err := os.Stat(localPath); err == nil {
+			debug.Info("Successfully downloaded rule chunk: %s (size: %d bytes)", chunk.Name, info.Size())
 		} else {
 			debug.Error("Rule chunk file not found after download: %s", localPath)
 			return fmt.Errorf("rule chunk file not found after download")
 		}
 	}
-	
+
 	return nil
 }
 
@@ -354,6 +380,7 @@ func (jm *JobManager) ensureBenchmark(ctx context.Context, assignment *JobTaskAs
 
 // monitorJobProgress monitors job progress and sends updates
 func (jm *JobManager) monitorJobProgress(ctx context.Context, jobExecution *JobExecution) {
+	defer crash.Recover()
 	defer func() {
 		jm.mutex.Lock()
 		delete(jm.activeJobs, jobExecution.Assignment.TaskID)
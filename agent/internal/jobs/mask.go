@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maskArgs turns a mask in hcmask line format, custom charsets separated by
+// commas before the mask as in "?l?d,?1?1?1", into hashcat's -1 to -4
+// options followed by the mask. Escaped commas ("\,") are kept literally.
+func maskArgs(line string) []string {
+	var fields []string
+	var current strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case line[i] == ',':
+			fields = append(fields, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(line[i])
+		}
+	}
+	fields = append(fields, current.String())
+
+	// The backend validates masks, anything else is passed on for hashcat to
+	// report
+	if len(fields) > 5 {
+		return []string{line}
+	}
+
+	args := make([]string, 0, 2*len(fields)-1)
+	for i, charset := range fields[:len(fields)-1] {
+		args = append(args, fmt.Sprintf("-%d", i+1), charset)
+	}
+	return append(args, fields[len(fields)-1])
+}
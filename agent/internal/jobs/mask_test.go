@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskArgs(t *testing.T) {
+	tests := []struct {
+		line string
+		args []string
+	}{
+		{line: "?d?d?d?d", args: []string{"?d?d?d?d"}},
+		{line: "?l?d,?1?1?1", args: []string{"-1", "?l?d", "?1?1?1"}},
+		{line: "?l,?u,?1?2", args: []string{"-1", "?l", "-2", "?u", "?1?2"}},
+		{line: "\\,.,pass?1", args: []string{"-1", ",.", "pass?1"}},
+		{line: "a,b,c,d,e,?1", args: []string{"a,b,c,d,e,?1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			assert.Equal(t, tt.args, maskArgs(tt.line))
+		})
+	}
+}
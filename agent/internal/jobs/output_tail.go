@@ -0,0 +1,32 @@
+package jobs
+
+import "strings"
+
+// maxOutputTailBytes caps how much hashcat output is kept per task for the
+// backend's task artifacts. The backend may keep less.
+const maxOutputTailBytes = 64 * 1024
+
+// outputTail keeps the most recent lines of a process' output within
+// maxOutputTailBytes
+type outputTail struct {
+	lines []string
+	size  int
+}
+
+// add appends a line, dropping the oldest lines once the tail is over its cap
+func (t *outputTail) add(line string) {
+	if len(line) > maxOutputTailBytes {
+		line = line[len(line)-maxOutputTailBytes:]
+	}
+	t.lines = append(t.lines, line)
+	t.size += len(line) + 1
+	for t.size > maxOutputTailBytes && len(t.lines) > 1 {
+		t.size -= len(t.lines[0]) + 1
+		t.lines = t.lines[1:]
+	}
+}
+
+// String returns the kept lines joined by newlines
+func (t *outputTail) String() string {
+	return strings.Join(t.lines, "\n")
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputTail(t *testing.T) {
+	t.Run("keeps all lines under the cap", func(t *testing.T) {
+		var tail outputTail
+		tail.add("Session..........: hashcat")
+		tail.add("stderr: Approaching final keyspace")
+		assert.Equal(t, "Session..........: hashcat\nstderr: Approaching final keyspace", tail.String())
+	})
+
+	t.Run("drops the oldest lines over the cap", func(t *testing.T) {
+		var tail outputTail
+		line := strings.Repeat("x", 1023)
+		for i := 0; i < 100; i++ {
+			tail.add(line)
+		}
+		tail.add("last")
+		assert.LessOrEqual(t, len(tail.String()), maxOutputTailBytes)
+		assert.True(t, strings.HasSuffix(tail.String(), "\nlast"))
+	})
+
+	t.Run("keeps the end of an oversized line", func(t *testing.T) {
+		var tail outputTail
+		tail.add(strings.Repeat("a", maxOutputTailBytes) + "end")
+		assert.Len(t, tail.String(), maxOutputTailBytes)
+		assert.True(t, strings.HasSuffix(tail.String(), "end"))
+	})
+}
@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/config"
+)
+
+// sandboxHomeDir is the directory within the data directory sandboxed hashcat
+// runs use as their home, where hashcat keeps its kernel cache
+const sandboxHomeDir = "hashcat-home"
+
+// SetSandbox sets how hashcat runs are confined
+func (e *HashcatExecutor) SetSandbox(sandbox config.HashcatSandbox) {
+	e.sandbox = sandbox
+}
+
+// sandboxEnv returns the environment of a sandboxed hashcat run: the agent's
+// own without its KH_ settings, which include credentials, and with home set
+// to the sandbox home
+func sandboxEnv(env []string, home string) []string {
+	result := make([]string, 0, len(env)+1)
+	for _, entry := range env {
+		if strings.HasPrefix(entry, "KH_") || strings.HasPrefix(entry, "HOME=") {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return append(result, "HOME="+home)
+}
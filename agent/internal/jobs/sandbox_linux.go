@@ -0,0 +1,242 @@
+//go:build linux
+
+package jobs
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
+)
+
+// cgroupPeriod is the cpu.max period CPU caps are expressed in, in microseconds
+const cgroupPeriod = 100000
+
+// bubblewrapSystemDirs are mounted read-only in the bubblewrap sandbox so
+// hashcat finds its libraries and GPU runtimes. Missing ones are skipped.
+var bubblewrapSystemDirs = []string{
+	"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64",
+	"/etc/ld.so.cache", "/etc/ld.so.conf", "/etc/ld.so.conf.d", "/etc/alternatives",
+	"/etc/OpenCL", "/opt/rocm", "/opt/intel",
+}
+
+// bubblewrapDataDirs are the data subdirectories a sandboxed hashcat run reads
+// its inputs from, mounted read-only
+var bubblewrapDataDirs = []string{"hashlists", "wordlists", "rules"}
+
+// bubblewrapOutputDir is the data subdirectory a sandboxed hashcat run may
+// write to besides its home
+const bubblewrapOutputDir = "output"
+
+// sandboxCommand confines a hashcat command as configured before it is
+// started: as the sandbox user, under bubblewrap and in a cgroup of its own
+// with the resource caps. The returned function removes the run's cgroup once
+// the process has exited.
+func (e *HashcatExecutor) sandboxCommand(cmd *exec.Cmd, runName string) (func(), error) {
+	sandbox := e.sandbox
+	if !sandbox.Enabled() {
+		return func() {}, nil
+	}
+
+	dataDirectory, err := filepath.Abs(e.dataDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve data directory: %w", err)
+	}
+	home := filepath.Join(dataDirectory, sandboxHomeDir)
+	if err := os.MkdirAll(home, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create hashcat sandbox home: %w", err)
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	if sandbox.User != "" {
+		credential, err := lookupCredential(sandbox.User)
+		if err != nil {
+			return nil, err
+		}
+		// Kernels compiled by hashcat are cached in its home
+		if err := os.Chown(home, int(credential.Uid), int(credential.Gid)); err != nil {
+			return nil, fmt.Errorf("failed to hand the hashcat sandbox home to %s: %w", sandbox.User, err)
+		}
+		cmd.SysProcAttr.Credential = credential
+	}
+	cmd.Env = sandboxEnv(os.Environ(), home)
+
+	if sandbox.Bubblewrap {
+		if err := wrapInBubblewrap(cmd, dataDirectory, home, config.GetConfigDir()); err != nil {
+			return nil, err
+		}
+	}
+
+	if !sandbox.HasLimits() {
+		return func() {}, nil
+	}
+	cgroup, err := createRunCgroup(sandbox, runName)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := os.Open(cgroup)
+	if err != nil {
+		removeRunCgroup(cgroup)
+		return nil, fmt.Errorf("failed to open hashcat cgroup: %w", err)
+	}
+	// The process starts inside the cgroup, so its caps apply from the start
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(fd.Fd())
+	return func() {
+		fd.Close()
+		removeRunCgroup(cgroup)
+	}, nil
+}
+
+// lookupCredential returns the IDs hashcat runs with as an unprivileged user
+func lookupCredential(name string) (*syscall.Credential, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, fmt.Errorf("hashcat sandbox user: %w", err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("hashcat sandbox user %s has an invalid uid %q", name, u.Uid)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("hashcat sandbox user %s has an invalid gid %q", name, u.Gid)
+	}
+	if uid == 0 {
+		return nil, fmt.Errorf("hashcat sandbox user %s is root, use an unprivileged user", name)
+	}
+
+	credential := &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}
+	groupIDs, err := u.GroupIds()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups of hashcat sandbox user %s: %w", name, err)
+	}
+	for _, groupID := range groupIDs {
+		if id, err := strconv.ParseUint(groupID, 10, 32); err == nil {
+			credential.Groups = append(credential.Groups, uint32(id))
+		}
+	}
+	return credential, nil
+}
+
+// wrapInBubblewrap runs the command under bwrap, see bubblewrapArgs
+func wrapInBubblewrap(cmd *exec.Cmd, dataDirectory, home, configDirectory string) error {
+	bwrap, err := exec.LookPath("bwrap")
+	if err != nil {
+		return fmt.Errorf("KH_HASHCAT_SANDBOX=bwrap needs bubblewrap installed: %w", err)
+	}
+
+	cmd.Args = bubblewrapArgs(cmd, dataDirectory, home, configDirectory)
+	cmd.Path = bwrap
+	return nil
+}
+
+// bubblewrapArgs returns the bwrap command line confining the command. It sees
+// the system libraries and GPU runtimes, its binary's directory, the hashlists,
+// wordlists and rules read-only, and can write only to its home, the output
+// directory and a private /tmp. The agent's config directory, which holds its
+// credentials, is masked by an empty tmpfs. It has no network or view of the
+// host's other processes.
+func bubblewrapArgs(cmd *exec.Cmd, dataDirectory, home, configDirectory string) []string {
+	args := []string{"bwrap", "--die-with-parent", "--new-session",
+		"--unshare-pid", "--unshare-ipc", "--unshare-uts", "--unshare-net"}
+	for _, dir := range bubblewrapSystemDirs {
+		args = append(args, "--ro-bind-try", dir, dir)
+	}
+	args = append(args,
+		"--dev-bind", "/dev", "/dev",
+		"--ro-bind", "/sys", "/sys",
+		"--proc", "/proc",
+		"--tmpfs", "/tmp",
+	)
+
+	// hashcat writes its kernel cache next to a portable install
+	binaryDirectory := filepath.Dir(cmd.Path)
+	args = append(args, "--bind", binaryDirectory, binaryDirectory)
+	for _, dir := range bubblewrapDataDirs {
+		path := filepath.Join(dataDirectory, dir)
+		args = append(args, "--ro-bind-try", path, path)
+	}
+	output := filepath.Join(dataDirectory, bubblewrapOutputDir)
+	args = append(args,
+		"--bind-try", output, output,
+		"--bind", home, home,
+		"--tmpfs", configDirectory,
+		"--setenv", "HOME", home,
+	)
+	if cmd.Dir != "" {
+		args = append(args, "--chdir", cmd.Dir)
+	}
+	args = append(args, "--", cmd.Path)
+	return append(args, cmd.Args[1:]...)
+}
+
+// createRunCgroup creates the cgroup of one hashcat run below the configured
+// cgroup, with the memory and CPU caps
+func createRunCgroup(sandbox config.HashcatSandbox, runName string) (string, error) {
+	parent := sandbox.Cgroup
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", fmt.Errorf("failed to create hashcat cgroup %s: %w", parent, err)
+	}
+
+	var controllers []string
+	if sandbox.MemoryMaxMB > 0 {
+		controllers = append(controllers, "+memory")
+	}
+	if sandbox.CPUMax > 0 {
+		controllers = append(controllers, "+cpu")
+	}
+	if err := os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte(strings.Join(controllers, " ")), 0); err != nil {
+		return "", fmt.Errorf("failed to enable cgroup controllers in %s: %w", parent, err)
+	}
+
+	cgroup := filepath.Join(parent, runName)
+	// A cgroup left behind by a crashed agent is reused
+	if err := os.Mkdir(cgroup, 0755); err != nil && !os.IsExist(err) {
+		return "", fmt.Errorf("failed to create hashcat cgroup %s: %w", cgroup, err)
+	}
+
+	if sandbox.MemoryMaxMB > 0 {
+		limit := strconv.FormatInt(int64(sandbox.MemoryMaxMB)*1024*1024, 10)
+		if err := os.WriteFile(filepath.Join(cgroup, "memory.max"), []byte(limit), 0); err != nil {
+			removeRunCgroup(cgroup)
+			return "", fmt.Errorf("failed to set hashcat memory cap: %w", err)
+		}
+	}
+	if sandbox.CPUMax > 0 {
+		quota := fmt.Sprintf("%d %d", int64(sandbox.CPUMax*cgroupPeriod), cgroupPeriod)
+		if err := os.WriteFile(filepath.Join(cgroup, "cpu.max"), []byte(quota), 0); err != nil {
+			removeRunCgroup(cgroup)
+			return "", fmt.Errorf("failed to set hashcat CPU cap: %w", err)
+		}
+	}
+	return cgroup, nil
+}
+
+// removeRunCgroup kills whatever is left in a run's cgroup and removes it
+func removeRunCgroup(cgroup string) {
+	// cgroup.kill is missing before Linux 5.14, the process was killed already then
+	_ = os.WriteFile(filepath.Join(cgroup, "cgroup.kill"), []byte("1"), 0)
+	for attempt := 0; attempt < 10; attempt++ {
+		err := syscall.Rmdir(cgroup)
+		if err == nil || os.IsNotExist(err) {
+			return
+		}
+		if err != syscall.EBUSY {
+			debug.Warning("Failed to remove hashcat cgroup %s: %v", cgroup, err)
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	debug.Warning("Hashcat cgroup %s is still in use, leaving it behind", cgroup)
+}
@@ -0,0 +1,64 @@
+//go:build linux
+
+package jobs
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxCommandUnconfinedByDefault(t *testing.T) {
+	executor := &HashcatExecutor{dataDirectory: t.TempDir()}
+	cmd := exec.Command("/data/binaries/1/hashcat.bin", "-m", "0")
+
+	cleanup, err := executor.sandboxCommand(cmd, "hashcat-test")
+	require.NoError(t, err)
+	cleanup()
+
+	assert.Equal(t, "/data/binaries/1/hashcat.bin", cmd.Path)
+	assert.Nil(t, cmd.SysProcAttr)
+	assert.Nil(t, cmd.Env)
+}
+
+func TestWrapInBubblewrap(t *testing.T) {
+	if _, err := exec.LookPath("bwrap"); err != nil {
+		t.Skip("bubblewrap is not installed")
+	}
+	cmd := exec.Command("/data/binaries/1/hashcat.bin", "-m", "0", "hashes.hash")
+	cmd.Dir = "/data/binaries/1"
+
+	require.NoError(t, wrapInBubblewrap(cmd, "/data", "/data/hashcat-home", "/config"))
+
+	assert.Equal(t, "bwrap", cmd.Args[0])
+	assert.Contains(t, cmd.Args, "--unshare-net")
+	assert.Equal(t, []string{"--", "/data/binaries/1/hashcat.bin", "-m", "0", "hashes.hash"}, cmd.Args[len(cmd.Args)-5:])
+	assert.Subset(t, cmd.Args, []string{"--bind", "/data/binaries/1", "--chdir"})
+}
+
+func TestBubblewrapArgsHideConfigDirectory(t *testing.T) {
+	cmd := exec.Command("/opt/kh/data/binaries/1/hashcat.bin", "-m", "0")
+	configDirectory := "/opt/kh/config"
+
+	args := bubblewrapArgs(cmd, "/opt/kh/data", "/opt/kh/data/hashcat-home", configDirectory)
+
+	var masked bool
+	for i := 0; i+1 < len(args); i++ {
+		switch args[i] {
+		case "--bind", "--bind-try", "--ro-bind", "--ro-bind-try", "--dev-bind":
+			source := args[i+1]
+			assert.False(t, source == configDirectory || strings.HasPrefix(configDirectory, source+"/"),
+				"%s %s exposes the config directory", args[i], source)
+			assert.NotEqual(t, "/opt/kh/data", source, "the whole data directory is mounted")
+			i += 2
+		case "--tmpfs":
+			masked = masked || args[i+1] == configDirectory
+			i++
+		}
+	}
+	assert.True(t, masked, "the config directory is not masked")
+	assert.Subset(t, args, []string{"/opt/kh/data/hashlists", "/opt/kh/data/wordlists", "/opt/kh/data/rules", "/opt/kh/data/binaries/1"})
+}
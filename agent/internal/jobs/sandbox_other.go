@@ -0,0 +1,18 @@
+//go:build !linux
+
+package jobs
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// sandboxCommand refuses to start hashcat when a sandbox is configured, since
+// confining hashcat is only supported on Linux
+func (e *HashcatExecutor) sandboxCommand(cmd *exec.Cmd, runName string) (func(), error) {
+	if e.sandbox.Enabled() {
+		return nil, fmt.Errorf("the hashcat sandbox is not supported on %s, unset the KH_HASHCAT_ settings to run hashcat unconfined", runtime.GOOS)
+	}
+	return func() {}, nil
+}
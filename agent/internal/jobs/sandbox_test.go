@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandboxEnv(t *testing.T) {
+	env := sandboxEnv([]string{
+		"PATH=/usr/bin",
+		"HOME=/root",
+		"KH_CLAIM_CODE=secret",
+		"CUDA_VISIBLE_DEVICES=0",
+	}, "/data/hashcat-home")
+
+	assert.Equal(t, []string{"PATH=/usr/bin", "CUDA_VISIBLE_DEVICES=0", "HOME=/data/hashcat-home"}, env)
+}
@@ -69,6 +69,23 @@ func NewDownloadManager(fileSync *FileSync, maxConcurrent int) *DownloadManager
 	}
 }
 
+// SetMaxConcurrent changes how many files download at once. Downloads already
+// running finish under the old limit.
+func (dm *DownloadManager) SetMaxConcurrent(maxConcurrent int) {
+	if maxConcurrent <= 0 {
+		return
+	}
+
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if maxConcurrent == dm.maxConcurrent {
+		return
+	}
+	dm.maxConcurrent = maxConcurrent
+	dm.semaphore = make(chan struct{}, maxConcurrent)
+	debug.Info("Download concurrency set to %d", maxConcurrent)
+}
+
 // GetProgressChannel returns the channel for receiving download progress updates
 func (dm *DownloadManager) GetProgressChannel() <-chan DownloadProgress {
 	return dm.progressChan
@@ -162,10 +179,14 @@ func (dm *DownloadManager) QueueDownload(ctx context.Context, fileInfo FileInfo)
 func (dm *DownloadManager) downloadWorker(ctx context.Context, key string, task *DownloadTask) {
 	defer dm.wg.Done()
 
-	// Acquire semaphore to limit concurrent downloads
+	// Acquire semaphore to limit concurrent downloads, releasing the same one
+	// even if the limit changes meanwhile
+	dm.mu.RLock()
+	semaphore := dm.semaphore
+	dm.mu.RUnlock()
 	select {
-	case dm.semaphore <- struct{}{}:
-		defer func() { <-dm.semaphore }()
+	case semaphore <- struct{}{}:
+		defer func() { <-semaphore }()
 	case <-ctx.Done():
 		dm.updateTaskStatus(key, DownloadStatusFailed, fmt.Errorf("context cancelled"))
 		return
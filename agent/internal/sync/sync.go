@@ -7,11 +7,11 @@ import (
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"math"
-	"math/rand"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/agent/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/agent/internal/httpclient"
 	"github.com/ZerkerEOD/krakenhashes/agent/pkg/console"
 	"github.com/ZerkerEOD/krakenhashes/agent/pkg/debug"
 
@@ -27,6 +28,13 @@ import (
 	"github.com/bodgit/sevenzip"
 )
 
+// downloadPolicy backs off between download attempts. Attempts are counted by
+// DownloadFileWithInfoRetry, the request itself is not retried.
+var downloadPolicy = httpclient.Policy{
+	BaseDelay: 2 * time.Second,
+	MaxDelay:  2 * time.Minute,
+}
+
 // FileSync handles synchronization of files between the agent and backend
 type FileSync struct {
 	client     *http.Client
@@ -34,8 +42,9 @@ type FileSync struct {
 	dataDirs   *config.DataDirs
 	sem        chan struct{} // Semaphore for limiting concurrent downloads
 	maxRetries int           // Maximum number of retries for downloads
-	apiKey     string        // API key for authentication
+	apiKey     string        // API key for authentication, see SetAPIKey
 	agentID    string        // Agent ID for authentication
+	credMu     sync.RWMutex  // Guards apiKey
 
 	// Progress tracking
 	progressCallback func(fileName string, bytesReceived, totalBytes int64)
@@ -59,6 +68,10 @@ type FileInfo struct {
 	// For rules: "hashcat", "john", "custom"
 	ID        int   `json:"id,omitempty"`        // ID in the backend database
 	Timestamp int64 `json:"timestamp,omitempty"` // Last modified time
+	// MoveFrom is the name of an identical file the agent already has, set by
+	// the backend when a wordlist or rule was moved so it is renamed locally
+	// instead of downloaded again
+	MoveFrom string `json:"move_from,omitempty"`
 }
 
 // progressReader wraps an io.Reader and reports progress
@@ -127,6 +140,21 @@ func (pr *progressReader) Read(p []byte) (int, error) {
 	return n, err
 }
 
+// SetAPIKey replaces the API key downloads authenticate with, after the
+// backend rotated the agent's credentials
+func (fs *FileSync) SetAPIKey(apiKey string) {
+	fs.credMu.Lock()
+	defer fs.credMu.Unlock()
+	fs.apiKey = apiKey
+}
+
+// APIKey returns the API key downloads authenticate with
+func (fs *FileSync) APIKey() string {
+	fs.credMu.RLock()
+	defer fs.credMu.RUnlock()
+	return fs.apiKey
+}
+
 // NewFileSync creates a new file synchronization handler
 func NewFileSync(urlConfig *config.URLConfig, dataDirs *config.DataDirs, apiKey, agentID string) (*FileSync, error) {
 	maxDownloads, _ := strconv.Atoi(getEnvOrDefault("KH_MAX_CONCURRENT_DOWNLOADS", "3"))
@@ -452,6 +480,26 @@ func (fs *FileSync) ScanAllDirectories(fileTypes []string) (map[string][]FileInf
 	return result, nil
 }
 
+// HashlistFileName returns the file a task's hashlist path names within the
+// hashlists directory. Jobs targeting only the remaining hashes of a hashlist
+// use a snapshot under remaining/ in place of the hashlist's own file.
+func HashlistFileName(hashlistID int64, hashlistPath string) string {
+	name := strings.TrimPrefix(filepath.ToSlash(hashlistPath), "hashlists/")
+	if name == "" || name != filepath.ToSlash(filepath.Clean(name)) || strings.HasPrefix(name, "../") || filepath.IsAbs(name) {
+		return fmt.Sprintf("%d.hash", hashlistID)
+	}
+	return name
+}
+
+// hashlistDownloadQuery selects a hashlist file other than the hashlist's own
+// on the download endpoint
+func hashlistDownloadQuery(fileInfo *FileInfo) string {
+	if fileInfo.Name == "" || fileInfo.Name == fmt.Sprintf("%d.hash", fileInfo.ID) {
+		return ""
+	}
+	return "?file=" + url.QueryEscape(fileInfo.Name)
+}
+
 // DownloadFileFromInfo downloads a file using information from the FileInfo struct
 // This ensures we can use the ID field for creating proper directory structures for binaries
 func (fs *FileSync) DownloadFileFromInfo(ctx context.Context, fileInfo *FileInfo) error {
@@ -525,22 +573,16 @@ func (fs *FileSync) DownloadFileWithInfoRetry(ctx context.Context, fileInfo *Fil
 		// We need to preserve this structure for proper organization
 		targetDir = fs.dataDirs.Rules
 		
-		// If we have an explicit category field, it takes precedence
-		if fileInfo.Category != "" {
-			// Check if the name already starts with the category to avoid double directories
-			if strings.HasPrefix(fileInfo.Name, fileInfo.Category+"/") {
-				// Name already includes the category prefix, use as-is
-				finalPath = filepath.Join(targetDir, fileInfo.Name)
-				debug.Info("Rule download - Name already has category prefix: %s -> %s", fileInfo.Name, finalPath)
-			} else {
-				// Use category field and append the name (which may include subdirectories)
-				finalPath = filepath.Join(targetDir, fileInfo.Category, fileInfo.Name)
-				debug.Info("Rule download - Using category field: %s/%s -> %s", fileInfo.Category, fileInfo.Name, finalPath)
-			}
-		} else if strings.Contains(fileInfo.Name, "/") {
-			// Name includes category path, use it as-is
+		// A name with a directory is the path below the rules directory, which
+		// need not start with the category once rules are moved between directories
+		if strings.Contains(fileInfo.Name, "/") {
+			// Name includes its directory, use it as-is
 			finalPath = filepath.Join(targetDir, fileInfo.Name)
-			debug.Info("Rule download - Name includes category: %s -> %s", fileInfo.Name, finalPath)
+			debug.Info("Rule download - Name includes directory: %s -> %s", fileInfo.Name, finalPath)
+		} else if fileInfo.Category != "" {
+			// Use category field as the directory
+			finalPath = filepath.Join(targetDir, fileInfo.Category, fileInfo.Name)
+			debug.Info("Rule download - Using category field: %s/%s -> %s", fileInfo.Category, fileInfo.Name, finalPath)
 		} else {
 			// No category, save to root rules directory
 			finalPath = filepath.Join(targetDir, fileInfo.Name)
@@ -574,6 +616,11 @@ func (fs *FileSync) DownloadFileWithInfoRetry(ctx context.Context, fileInfo *Fil
 		return fmt.Errorf("unsupported file type: %s", fileInfo.FileType)
 	}
 
+	// A file that was only moved on the backend is renamed locally
+	if fileInfo.MoveFrom != "" && fs.moveExistingFile(targetDir, finalPath, fileInfo) {
+		return nil
+	}
+
 	// Acquire semaphore slot
 	select {
 	case fs.sem <- struct{}{}:
@@ -609,7 +656,7 @@ func (fs *FileSync) DownloadFileWithInfoRetry(ctx context.Context, fileInfo *Fil
 	var url string
 	if fileInfo.FileType == "hashlist" && fileInfo.ID > 0 {
 		// Hashlists use a different endpoint that requires the ID
-		url = fmt.Sprintf("%s/api/agent/hashlists/%d/download", fs.urlConfig.BaseURL, fileInfo.ID)
+		url = fmt.Sprintf("%s/api/agent/hashlists/%d/download", fs.urlConfig.BaseURL, fileInfo.ID) + hashlistDownloadQuery(fileInfo)
 	} else {
 		// Other file types use the generic file endpoint
 		// If we have a category, include it in the URL path
@@ -632,11 +679,12 @@ func (fs *FileSync) DownloadFileWithInfoRetry(ctx context.Context, fileInfo *Fil
 	}
 
 	// Add authentication headers
-	req.Header.Set("X-API-Key", fs.apiKey)
+	req.Header.Set("X-API-Key", fs.APIKey())
 	req.Header.Set("X-Agent-ID", fs.agentID)
 
-	// Send request
-	resp, err := fs.client.Do(req)
+	// Send request. Failures are retried below, so the download itself only
+	// goes through the breaker.
+	resp, err := httpclient.New(fs.client, downloadPolicy).Do(req)
 	if err != nil {
 		debug.Error("Failed to download file %s: %v", url, err)
 		return fs.retryOrFailInfo(ctx, fileInfo, retryCount,
@@ -727,6 +775,71 @@ func (fs *FileSync) DownloadFileWithInfoRetry(ctx context.Context, fileInfo *Fil
 	return nil
 }
 
+// moveExistingFile renames the local copy named by fileInfo.MoveFrom to
+// finalPath. It reports false if the copy is missing, does not look like the
+// expected file or cannot be renamed, the caller then downloads the file.
+func (fs *FileSync) moveExistingFile(targetDir, finalPath string, fileInfo *FileInfo) bool {
+	if fileInfo.FileType != "wordlist" && fileInfo.FileType != "rule" {
+		return false
+	}
+	if !filepath.IsLocal(filepath.FromSlash(fileInfo.MoveFrom)) {
+		debug.Warning("Ignoring move of %s from outside the %s directory: %s", fileInfo.Name, fileInfo.FileType, fileInfo.MoveFrom)
+		return false
+	}
+
+	sourcePath := filepath.Join(targetDir, filepath.FromSlash(fileInfo.MoveFrom))
+	info, err := os.Stat(sourcePath)
+	if err != nil || info.IsDir() || (fileInfo.Size > 0 && info.Size() != fileInfo.Size) {
+		debug.Info("Local copy %s cannot be moved to %s, downloading instead", sourcePath, finalPath)
+		return false
+	}
+	if _, err := os.Stat(finalPath); err == nil {
+		return false
+	}
+
+	if err := os.MkdirAll(filepath.Dir(finalPath), 0750); err != nil {
+		debug.Error("Failed to create parent directory %s: %v", filepath.Dir(finalPath), err)
+		return false
+	}
+	if err := os.Rename(sourcePath, finalPath); err != nil {
+		debug.Error("Failed to move %s to %s: %v", sourcePath, finalPath, err)
+		return false
+	}
+
+	debug.Info("Moved %s %s to %s instead of downloading it", fileInfo.FileType, sourcePath, finalPath)
+	return true
+}
+
+// RemoveFile deletes the local copy of a wordlist or rule that was deleted on
+// the backend. The path is resolved the same way as for a download. A copy
+// that is already gone is not an error.
+func (fs *FileSync) RemoveFile(fileInfo *FileInfo) error {
+	var targetDir string
+	switch fileInfo.FileType {
+	case "wordlist":
+		targetDir = fs.dataDirs.Wordlists
+	case "rule":
+		targetDir = fs.dataDirs.Rules
+	default:
+		return fmt.Errorf("cannot remove %s files", fileInfo.FileType)
+	}
+
+	relPath := filepath.FromSlash(fileInfo.Name)
+	if !strings.Contains(fileInfo.Name, "/") && fileInfo.Category != "" {
+		relPath = filepath.Join(fileInfo.Category, relPath)
+	}
+	if !filepath.IsLocal(relPath) {
+		return fmt.Errorf("refusing to remove %s outside the %s directory", fileInfo.Name, fileInfo.FileType)
+	}
+
+	path := filepath.Join(targetDir, relPath)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	debug.Info("Removed deleted %s %s", fileInfo.FileType, path)
+	return nil
+}
+
 // retryOrFailInfo handles retries for the FileInfo based download
 func (fs *FileSync) retryOrFailInfo(ctx context.Context, fileInfo *FileInfo, retryCount int, err error) error {
 	if retryCount >= fs.maxRetries {
@@ -734,10 +847,18 @@ func (fs *FileSync) retryOrFailInfo(ctx context.Context, fileInfo *FileInfo, ret
 		return fmt.Errorf("download failed after %d retries: %w", retryCount+1, err)
 	}
 
-	// Exponential backoff with jitter
-	backoff := time.Duration(math.Pow(2, float64(retryCount))) * time.Second
-	jitter := time.Duration(rand.Intn(1000)) * time.Millisecond
-	delay := backoff + jitter
+	// While the backend is unreachable, wait for it without using up retries,
+	// so a backend restart does not fail every pending download
+	if errors.Is(err, httpclient.ErrCircuitOpen) {
+		debug.Warning("Backend unavailable, download of %s waits for it: %v", fileInfo.Name, err)
+		if err := httpclient.BackendBreaker().Wait(ctx); err != nil {
+			return err
+		}
+		return fs.DownloadFileWithInfoRetry(ctx, fileInfo, retryCount)
+	}
+
+	// Exponential backoff with full jitter, so agents do not retry in lockstep
+	delay := downloadPolicy.Backoff(retryCount)
 
 	debug.Warning("Retrying download of %s in %v (attempt %d/%d): %v",
 		fileInfo.Name, delay, retryCount+2, fs.maxRetries+1, err)
@@ -762,7 +883,7 @@ func (fs *FileSync) SyncDirectory(ctx context.Context, fileType string) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := fs.client.Do(req)
+	resp, err := httpclient.New(fs.client, httpclient.DefaultPolicy).Do(req)
 	if err != nil {
 		debug.Error("Failed to fetch file list from %s: %v", url, err)
 		return fmt.Errorf("failed to fetch file list: %w", err)
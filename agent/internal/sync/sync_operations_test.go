@@ -298,6 +298,53 @@ func TestFileSync_DownloadFileWithInfoRetry(t *testing.T) {
 	assert.Equal(t, 3, attempts) // Should have tried 3 times
 }
 
+func TestFileSync_DownloadFileMovesExistingCopy(t *testing.T) {
+	// Any request means the file was downloaded instead of moved
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected download request: %s", r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	fs := &FileSync{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		urlConfig: &config.URLConfig{BaseURL: server.URL},
+		dataDirs: &config.DataDirs{
+			Wordlists: filepath.Join(tempDir, "wordlists"),
+			Rules:     filepath.Join(tempDir, "rules"),
+		},
+		apiKey:  "test-key",
+		agentID: "123",
+	}
+
+	content := []byte("password\n123456\n")
+	oldPath := filepath.Join(fs.dataDirs.Rules, "hashcat", "best64.rule")
+	require.NoError(t, os.MkdirAll(filepath.Dir(oldPath), 0755))
+	require.NoError(t, os.WriteFile(oldPath, content, 0644))
+
+	fileInfo := FileInfo{
+		Name:     "custom/favourites/best64.rule",
+		FileType: "rule",
+		Category: "hashcat",
+		ID:       1,
+		Size:     int64(len(content)),
+		MD5Hash:  calculateMD5Hash(content),
+		MoveFrom: "hashcat/best64.rule",
+	}
+
+	err := fs.DownloadFileWithInfoRetry(context.Background(), &fileInfo, 0)
+	require.NoError(t, err)
+	assert.NoFileExists(t, oldPath)
+	moved, err := os.ReadFile(filepath.Join(fs.dataDirs.Rules, "custom", "favourites", "best64.rule"))
+	require.NoError(t, err)
+	assert.Equal(t, content, moved)
+
+	// A move from outside the rules directory is never followed
+	fileInfo.MoveFrom = "../wordlists/x.txt"
+	assert.False(t, fs.moveExistingFile(fs.dataDirs.Rules, filepath.Join(fs.dataDirs.Rules, "y.rule"), &fileInfo))
+}
+
 func TestFileSync_SyncDirectory(t *testing.T) {
 	// Create mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -436,4 +483,34 @@ J2WzLy2qc0DteM8vBUHIu6x5bmiTNe/E9Z1nU1wENWezQzGBtQNFEiN6Ot+lUzma
 sF+I2hqpbVPj3qYGxDGkJeFrF5d9dC1vwqTSFwmJP1F6xiVPAjPZCPCK3cd0qnSW
 uxFPb0pPFHJPdCNhHQfjeKfwEOQdX7KdMPBdAX8N6cEisU4R5LoGwfOJVPW6xDH0
 gL4HgWOvn6zG9SrXrZH+
------END CERTIFICATE-----`
\ No newline at end of file
+-----END CERTIFICATE-----`
+func TestFileSync_RemoveFile(t *testing.T) {
+	tempDir := t.TempDir()
+	dataDirs := &config.DataDirs{
+		Wordlists: filepath.Join(tempDir, "wordlists"),
+		Rules:     filepath.Join(tempDir, "rules"),
+	}
+	fs := &FileSync{dataDirs: dataDirs}
+
+	wordlist := filepath.Join(dataDirs.Wordlists, "general", "rockyou.txt")
+	rule := filepath.Join(dataDirs.Rules, "hashcat", "best64.rule")
+	for _, path := range []string{wordlist, rule} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("test content"), 0644))
+	}
+
+	// Name with its directory
+	require.NoError(t, fs.RemoveFile(&FileInfo{Name: "general/rockyou.txt", FileType: "wordlist"}))
+	assert.NoFileExists(t, wordlist)
+
+	// Directory from the category
+	require.NoError(t, fs.RemoveFile(&FileInfo{Name: "best64.rule", FileType: "rule", Category: "hashcat"}))
+	assert.NoFileExists(t, rule)
+
+	// Already gone
+	assert.NoError(t, fs.RemoveFile(&FileInfo{Name: "general/rockyou.txt", FileType: "wordlist"}))
+
+	// Outside the data directory or not a wordlist or rule
+	assert.Error(t, fs.RemoveFile(&FileInfo{Name: "../rules/hashcat/best64.rule", FileType: "wordlist"}))
+	assert.Error(t, fs.RemoveFile(&FileInfo{Name: "hashcat.7z", FileType: "binary"}))
+}
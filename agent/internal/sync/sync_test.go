@@ -320,4 +320,20 @@ func TestFileSyncSemaphore(t *testing.T) {
 	
 	// Verify concurrency was limited
 	assert.LessOrEqual(t, maxConcurrent, int32(2), "Concurrent operations should be limited by semaphore")
-}
\ No newline at end of file
+}
+
+func TestHashlistFileName(t *testing.T) {
+	assert.Equal(t, "12.hash", HashlistFileName(12, "hashlists/12.hash"))
+	assert.Equal(t, "12.hash", HashlistFileName(12, ""))
+	assert.Equal(t, "remaining/12/0b7e3f2c-4d1a-4a8e-9c55-6f1b2d3e4a5b.hash",
+		HashlistFileName(12, "hashlists/remaining/12/0b7e3f2c-4d1a-4a8e-9c55-6f1b2d3e4a5b.hash"))
+
+	// Paths leaving the hashlists directory fall back to the hashlist's own file
+	assert.Equal(t, "12.hash", HashlistFileName(12, "hashlists/../config/agent.key"))
+	assert.Equal(t, "12.hash", HashlistFileName(12, "/etc/passwd"))
+}
+
+func TestHashlistDownloadQuery(t *testing.T) {
+	assert.Empty(t, hashlistDownloadQuery(&FileInfo{ID: 12, Name: "12.hash"}))
+	assert.Equal(t, "?file=remaining%2F12%2Fa.hash", hashlistDownloadQuery(&FileInfo{ID: 12, Name: "remaining/12/a.hash"}))
+}
@@ -6,9 +6,13 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 )
 
+// maxRecentLines is how many of the latest log lines are kept for crash reports
+const maxRecentLines = 200
+
 // LogLevel represents the severity of a log message
 type LogLevel int
 
@@ -37,6 +41,8 @@ var (
 		"WARNING": LevelWarning,
 		"ERROR":   LevelError,
 	}
+	recentMu    sync.Mutex
+	recentLines []string
 )
 
 func init() {
@@ -72,11 +78,11 @@ func Log(level LogLevel, format string, v ...interface{}) {
 	pc, file, line, _ := runtime.Caller(2)
 	funcName := runtime.FuncForPC(pc).Name()
 
-	// Format the message
-	message := fmt.Sprintf(format, v...)
+	// Format the message, masking any hashes in it
+	message := redactMessage(fmt.Sprintf(format, v...))
 	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
 
-	logger.Printf("[%s] [%s] [%s:%d] [%s] %s\n",
+	entry := fmt.Sprintf("[%s] [%s] [%s:%d] [%s] %s",
 		levelNames[level],
 		timestamp,
 		file,
@@ -84,6 +90,25 @@ func Log(level LogLevel, format string, v ...interface{}) {
 		funcName,
 		message,
 	)
+	logger.Println(entry)
+	remember(entry)
+}
+
+// remember keeps entry among the most recent log lines
+func remember(entry string) {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	recentLines = append(recentLines, entry)
+	if len(recentLines) > maxRecentLines {
+		recentLines = recentLines[len(recentLines)-maxRecentLines:]
+	}
+}
+
+// RecentLines returns a copy of the most recent log lines, oldest first
+func RecentLines() []string {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	return append([]string(nil), recentLines...)
 }
 
 // Debug logs a debug level message
@@ -120,6 +145,8 @@ func Reinitialize() {
 		CurrentLevel = LevelInfo // Default to INFO if not specified
 	}
 
+	Redact = redactFromEnv()
+
 	// Only log initialization if debugging is enabled
 	if IsEnabled {
 		Info("Debug logging reinitialized - Enabled: %v, Level: %s", IsEnabled, levelNames[CurrentLevel])
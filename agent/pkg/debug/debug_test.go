@@ -2,6 +2,7 @@ package debug
 
 import (
 	"bytes"
+	"fmt"
 	"log"
 	"os"
 	"strings"
@@ -388,6 +389,31 @@ func TestLogOutput(t *testing.T) {
 	assert.Regexp(t, `\[\S+:\d+\]`, output) // File:line
 }
 
+func TestRecentLines(t *testing.T) {
+	originalDebug := IsEnabled
+	originalLevel := CurrentLevel
+	originalLogger := logger
+	defer func() {
+		IsEnabled = originalDebug
+		CurrentLevel = originalLevel
+		logger = originalLogger
+	}()
+
+	logger = log.New(&bytes.Buffer{}, "", 0)
+	IsEnabled = true
+	CurrentLevel = LevelInfo
+
+	Debug("filtered message")
+	for i := 0; i < maxRecentLines+5; i++ {
+		Info("recent message %d", i)
+	}
+
+	lines := RecentLines()
+	assert.Len(t, lines, maxRecentLines)
+	assert.Contains(t, lines[0], "recent message 5")
+	assert.Contains(t, lines[len(lines)-1], fmt.Sprintf("recent message %d", maxRecentLines+4))
+}
+
 func TestConcurrentLogging(t *testing.T) {
 	// Save original values
 	originalDebug := IsEnabled
@@ -0,0 +1,93 @@
+package debug
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// hashPrefixLen is how many characters of a redacted hash are kept
+const hashPrefixLen = 8
+
+var (
+	// Redact controls whether hashes and plaintexts are masked in log output.
+	// It is on unless LOG_REDACT_HASHES is false.
+	Redact = redactFromEnv()
+
+	// fingerprintKey makes fingerprints stable within one run, so log lines
+	// about the same hash can be matched, without allowing a plaintext to be
+	// confirmed by hashing guesses
+	fingerprintKey = newFingerprintKey()
+
+	// hashPattern matches what looks like a hash in a log message: long hex
+	// digests and crypt(3) style hashes such as $2y$10$... or $krb5tgs$...
+	hashPattern = regexp.MustCompile(`\b[0-9a-fA-F]{32,}\b|\$[0-9a-zA-Z]{1,16}\$[^\s,;'"\]\)}]+`)
+)
+
+// redactFromEnv reads the LOG_REDACT_HASHES environment variable
+func redactFromEnv() bool {
+	value := strings.ToLower(os.Getenv("LOG_REDACT_HASHES"))
+	return value != "false" && value != "0"
+}
+
+// newFingerprintKey returns a random key for the fingerprints of this run
+func newFingerprintKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		// A fixed key still hides the values, only across runs
+		return []byte("krakenhashes-log-fingerprint")
+	}
+	return key
+}
+
+// fingerprint returns a short keyed digest of value
+func fingerprint(value string) string {
+	mac := hmac.New(sha256.New, fingerprintKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:8]
+}
+
+// Hash masks a hash for logging, keeping a prefix and a fingerprint
+func Hash(value string) string {
+	if !Redact || value == "" {
+		return value
+	}
+	if len(value) <= hashPrefixLen {
+		return "#" + fingerprint(value)
+	}
+	return value[:hashPrefixLen] + "…#" + fingerprint(value)
+}
+
+// Plain masks a cracked plaintext for logging, only a fingerprint is kept
+func Plain(value string) string {
+	if !Redact || value == "" {
+		return value
+	}
+	return "[redacted #" + fingerprint(value) + "]"
+}
+
+// redactMessage masks anything in a formatted log message that looks like a
+// hash. Plaintexts cannot be recognized and must be wrapped with Plain.
+func redactMessage(message string) string {
+	if !Redact {
+		return message
+	}
+	return hashPattern.ReplaceAllStringFunc(message, Hash)
+}
+
+// CrackLine masks the plaintext of a line of hashcat output that may be a
+// crack, everything after the last colon of a line that is not JSON
+func CrackLine(line string) string {
+	if !Redact || strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return line
+	}
+	i := strings.LastIndex(line, ":")
+	if i < 0 {
+		return line
+	}
+	return redactMessage(line[:i+1]) + Plain(line[i+1:])
+}
@@ -0,0 +1,40 @@
+package debug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	original := Redact
+	defer func() { Redact = original }()
+
+	Redact = true
+	ntlm := "8846f7eaee8fb117ad06bdd830b7586c"
+
+	masked := Hash(ntlm)
+	assert.True(t, strings.HasPrefix(masked, "8846f7ea…#"))
+	assert.NotContains(t, masked, ntlm)
+	assert.Equal(t, masked, Hash(ntlm), "fingerprints are stable within a run")
+	assert.NotEqual(t, masked, Hash("31d6cfe0d16ae931b73c59d7e0c089c0"))
+
+	assert.NotContains(t, Plain("password"), "password")
+	assert.True(t, strings.HasPrefix(Plain("password"), "[redacted #"))
+
+	message := redactMessage("Cracked " + ntlm + " and $2y$10$abcdefghijklmnopqrstuv in task 42")
+	assert.NotContains(t, message, ntlm)
+	assert.NotContains(t, message, "abcdefghijklmnopqrstuv")
+	assert.Contains(t, message, "in task 42")
+
+	line := CrackLine(ntlm + ":password")
+	assert.NotContains(t, line, "password")
+	assert.NotContains(t, line, ntlm)
+	assert.Equal(t, `{"status":3}`, CrackLine(`{"status":3}`))
+
+	Redact = false
+	assert.Equal(t, ntlm, Hash(ntlm))
+	assert.Equal(t, "password", Plain("password"))
+	assert.Equal(t, ntlm+":password", CrackLine(ntlm+":password"))
+}
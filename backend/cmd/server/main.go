@@ -13,13 +13,18 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/database"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/agent"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/tls"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/routes"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/rule"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	clientsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/client"
+	cloudburstsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/cloudburst"
 	retentionsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/retention"
+	telemetrysvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/telemetry"
+	trashsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/trash"
 	tlsprovider "github.com/ZerkerEOD/krakenhashes/backend/internal/tls"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/version"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/wordlist"
@@ -86,6 +91,11 @@ func main() {
 	debug.Reinitialize()
 	debug.Info("Debug logging initialized with environment settings")
 
+	// Database migration management commands run instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(os.Args[2:]))
+	}
+
 	// Load version information
 	debug.Info("Loading version information...")
 	// Try different paths for versions.json
@@ -163,6 +173,16 @@ func main() {
 	}
 	defer sqlDB.Close()
 
+	// Report, analytics and export queries go to the read replica when one is configured
+	replicaDB, err := database.ConnectReplica()
+	if err != nil {
+		debug.Error("Read replica unavailable, report queries will use the primary: %v", err)
+	} else if replicaDB != nil {
+		defer replicaDB.Close()
+		db.SetReplica(replicaDB)
+		go db.MonitorReplica(context.Background(), 30*time.Second)
+	}
+
 	// Create DB wrapper for repositories
 	dbWrapper := &db.DB{DB: sqlDB}
 
@@ -184,6 +204,8 @@ func main() {
 
 	analyticsRepo := repository.NewAnalyticsRepository(dbWrapper)
 	retentionService := retentionsvc.NewRetentionService(dbWrapper, hashlistRepo, hashRepo, clientRepo, clientSettingsRepo, analyticsRepo)
+	clientService := clientsvc.NewClientService(clientRepo, hashlistRepo, clientSettingsRepo, retentionService)
+	trashService := trashsvc.NewTrashService(repository.NewTrashRepository(dbWrapper), hashlistRepo, jobExecutionRepo, clientRepo, retentionService, clientService)
 
 	// Initialize wordlist and rule managers for monitoring
 	wordlistStore := wordlist.NewStore(sqlDB)
@@ -214,7 +236,8 @@ func main() {
 	debug.Info("AppConfig.DataDir: %s", appConfig.DataDir)
 
 	binaryConfig := binary.Config{
-		DataDir: binaryDataDir,
+		DataDir:   binaryDataDir,
+		Airgapped: appConfig.Airgapped,
 	}
 	binaryManager, err := binary.NewManager(binaryStore, binaryConfig)
 	if err != nil {
@@ -258,7 +281,8 @@ func main() {
 		for {
 			select {
 			case <-ticker.C:
-				if err := agentCleanupService.CleanupStaleAgents(context.Background(), 90*time.Second); err != nil {
+				heartbeatTimeout := services.AgentHeartbeatTimeout(context.Background(), systemSettingsRepo)
+				if err := agentCleanupService.CleanupStaleAgents(context.Background(), heartbeatTimeout); err != nil {
 					debug.Error("Failed to cleanup stale agents: %v", err)
 				}
 			}
@@ -269,7 +293,12 @@ func main() {
 	debug.Info("Creating job cleanup service...")
 	jobCleanupService := services.NewJobCleanupService(jobExecutionRepo, jobTaskRepo, systemSettingsRepo, agentRepo)
 	debug.Info("Job cleanup service created, starting cleanup of stale tasks from previous runs...")
-	cleanupErr := jobCleanupService.CleanupStaleTasksOnStartup(context.Background())
+	handoverService := services.NewServerHandoverService(repository.NewServerHandoverRepository(dbWrapper), jobTaskRepo, systemSettingsRepo)
+	handover, err := handoverService.Resume(context.Background())
+	if err != nil {
+		debug.Error("Failed to resume server handover: %v", err)
+	}
+	cleanupErr := jobCleanupService.CleanupStaleTasksOnStartup(context.Background(), handover)
 	if cleanupErr != nil {
 		debug.Error("Failed to cleanup stale tasks: %v", cleanupErr)
 		// Don't exit - this is not fatal
@@ -280,6 +309,31 @@ func main() {
 	// Start periodic stale task monitor
 	go jobCleanupService.MonitorStaleTasksPeriodically(context.Background(), 5*time.Minute)
 
+	// Start job execution archival (no-op until job_archive_after_days is set)
+	jobArchiveService := services.NewJobArchiveService(repository.NewJobArchiveRepository(dbWrapper), systemSettingsRepo)
+	go jobArchiveService.StartArchiveScheduler(context.Background())
+
+	// Evaluate staged binary rollouts, promoting or rolling back their canaries
+	binaryRolloutService := services.NewBinaryRolloutService(repository.NewBinaryRolloutRepository(dbWrapper), binaryManager, systemSettingsRepo)
+	go binaryRolloutService.StartScheduler(context.Background())
+
+	// Purge system events past system_event_retention_days
+	systemEventService := services.NewSystemEventService(repository.NewSystemEventRepository(dbWrapper), systemSettingsRepo)
+	go systemEventService.StartPurgeScheduler(context.Background())
+
+	// Start anonymized statistics publishing (no-op until telemetry_enabled is set)
+	telemetryService := telemetrysvc.NewTelemetryService(repository.NewTelemetryRepository(dbWrapper), systemSettingsRepo, appConfig.Airgapped)
+	go telemetryService.StartScheduler(context.Background())
+
+	// Start cloud burst provisioning (no-op until cloud_burst_enabled is set)
+	cloudBurstService := cloudburstsvc.NewService(
+		repository.NewCloudBurstRepository(dbWrapper),
+		systemSettingsRepo,
+		services.NewClaimVoucherService(repository.NewClaimVoucherRepository(dbWrapper)),
+		cloudburstsvc.NewCommandProvider(appConfig.CloudBurstLaunchCommand, appConfig.CloudBurstTerminateCommand),
+	)
+	go cloudBurstService.StartScheduler(context.Background())
+
 	// Use the system user (uuid.Nil) for the monitor service
 	systemUserID := uuid.Nil
 	debug.Info("Using system user ID for monitor service: %s", systemUserID.String())
@@ -311,11 +365,23 @@ func main() {
 		if err := retentionService.PurgeOldAnalyticsReports(context.Background()); err != nil {
 			debug.Error("Scheduled analytics report retention purge failed: %v", err)
 		}
+		if _, err := trashService.PurgeExpired(context.Background()); err != nil {
+			debug.Error("Scheduled trash purge failed: %v", err)
+		}
 	})
 	if err != nil {
 		debug.Error("Failed to add retention purge job to scheduler: %v", err)
 		// Decide if this is fatal? For now, log and continue.
 	}
+	// Staged uploads expire after hours rather than months
+	_, err = cr.AddFunc("@hourly", func() {
+		if err := retentionService.PurgeExpiredStagedHashlists(context.Background()); err != nil {
+			debug.Error("Scheduled staged hashlist purge failed: %v", err)
+		}
+	})
+	if err != nil {
+		debug.Error("Failed to add staged hashlist purge job to scheduler: %v", err)
+	}
 	cr.Start()
 	debug.Info("Data retention purge scheduler started.")
 
@@ -329,6 +395,12 @@ func main() {
 		if err := retentionService.PurgeOldAnalyticsReports(context.Background()); err != nil {
 			debug.Error("Initial analytics report retention purge failed: %v", err)
 		}
+		if _, err := trashService.PurgeExpired(context.Background()); err != nil {
+			debug.Error("Initial trash purge failed: %v", err)
+		}
+		if err := retentionService.PurgeExpiredStagedHashlists(context.Background()); err != nil {
+			debug.Error("Initial staged hashlist purge failed: %v", err)
+		}
 	}()
 
 	// Initialize and start token cleanup service
@@ -389,6 +461,20 @@ func main() {
 	debug.Info("Setting up routes")
 	routes.SetupRoutes(httpsRouter, sqlDB, tlsProvider, agentService, wordlistManager, ruleManager, binaryManager, potfileService, analyticsQueueService)
 
+	// Start the internal event bus once the services that subscribe to it exist
+	debug.Info("Starting event bus")
+	eventBus := events.NewBus(dbWrapper, database.ConnectionString())
+	notificationService := services.NewNotificationService(sqlDB)
+	notificationService.SubscribeEvents(eventBus)
+	services.NewPrivilegedAccountService(dbWrapper).SubscribeEvents(eventBus)
+	if routes.JobIntegrationManager != nil {
+		routes.JobIntegrationManager.SubscribeEvents(eventBus)
+	}
+	eventBusCtx, eventBusCancel := context.WithCancel(context.Background())
+	defer eventBusCancel()
+	eventBus.Start(eventBusCtx)
+	notificationService.StartDigestScheduler(eventBusCtx)
+
 	// Setup CA certificate route on HTTP router
 	debug.Info("Setting up CA certificate route")
 	tlsHandler := tls.NewHandler(tlsProvider)
@@ -445,10 +531,10 @@ func main() {
 	defer monitorService.Stop()
 
 	// Start the job scheduler if it was initialized
+	jobSchedulerCtx, jobSchedulerCancel := context.WithCancel(context.Background())
+	defer jobSchedulerCancel()
 	if routes.JobIntegrationManager != nil {
 		debug.Info("Starting job scheduler")
-		jobSchedulerCtx, jobSchedulerCancel := context.WithCancel(context.Background())
-		defer jobSchedulerCancel()
 		routes.JobIntegrationManager.StartScheduler(jobSchedulerCtx)
 		debug.Info("Job scheduler started successfully")
 	} else {
@@ -470,6 +556,14 @@ func main() {
 		debug.Info("Received signal: %v", sig)
 		debug.Info("Shutting down server...")
 
+		// Hand the agents over to the next start: stop assigning work, record
+		// the tasks in flight and tell agents when to reconnect
+		jobSchedulerCancel()
+		if _, err := handoverService.Record(context.Background(), appConfig.HandoverReconnectAfter); err != nil {
+			debug.Error("Failed to record server handover: %v", err)
+		}
+		routes.DrainAgents(appConfig.HandoverReconnectAfter)
+
 		// Create a deadline for graceful shutdown
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/database"
+	"github.com/golang-migrate/migrate/v4"
+)
+
+const migrateUsage = `Usage: krakenhashes migrate <command>
+
+Commands:
+  status     Show the current schema version and any pending migrations
+  up         Apply all pending migrations
+  down N     Roll back the last N migrations
+  force V    Set the schema version to V and clear the dirty flag without
+             running any migration (use -1 for "no migrations applied")`
+
+// runMigrateCommand handles the migrate subcommands and returns the process exit code
+func runMigrateCommand(args []string) int {
+	// Number of arguments each command takes, including the command itself
+	argCounts := map[string]int{"status": 1, "up": 1, "down": 2, "force": 2}
+	if len(args) == 0 || argCounts[args[0]] != len(args) {
+		if len(args) > 0 && argCounts[args[0]] == 0 {
+			fmt.Fprintf(os.Stderr, "Unknown migrate command: %s\n\n", args[0])
+		}
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "status":
+		return migrateStatus()
+	case "up":
+		err = database.RunMigrations()
+	case "down":
+		steps, convErr := strconv.Atoi(args[1])
+		if convErr != nil || steps < 1 {
+			fmt.Fprintf(os.Stderr, "Invalid number of migrations to roll back: %s\n", args[1])
+			return 2
+		}
+		err = database.MigrateDown(steps)
+	case "force":
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil || version < -1 {
+			fmt.Fprintf(os.Stderr, "Invalid migration version: %s\n", args[1])
+			return 2
+		}
+		err = database.ForceMigrationVersion(version)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Migration %s failed: %v\n", args[0], err)
+		var dirtyErr migrate.ErrDirty
+		if errors.As(err, &dirtyErr) {
+			if status, statusErr := database.GetMigrationStatus(); statusErr == nil {
+				fmt.Fprintf(os.Stderr, "\n%s\n", database.DirtyRecoveryHint(status))
+			}
+		}
+		return 1
+	}
+
+	fmt.Printf("Migration %s completed\n", args[0])
+	return migrateStatus()
+}
+
+// migrateStatus prints the schema status and returns 1 if the schema is dirty
+func migrateStatus() int {
+	status, err := database.GetMigrationStatus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get migration status: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Migrations:      %s\n", status.Path)
+	if status.Version == 0 {
+		fmt.Println("Schema version:  none")
+	} else {
+		fmt.Printf("Schema version:  %d\n", status.Version)
+	}
+	fmt.Printf("Latest version:  %d\n", status.Latest)
+	fmt.Printf("Pending:         %d\n", len(status.Pending))
+	for _, v := range status.Pending {
+		fmt.Printf("  %06d\n", v)
+	}
+
+	if status.Dirty {
+		fmt.Printf("\nDIRTY\n%s\n", database.DirtyRecoveryHint(status))
+		return 1
+	}
+	return 0
+}
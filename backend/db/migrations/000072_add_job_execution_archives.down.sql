@@ -0,0 +1,2 @@
+DELETE FROM system_settings WHERE key = 'job_archive_after_days';
+DROP TABLE IF EXISTS job_execution_archives;
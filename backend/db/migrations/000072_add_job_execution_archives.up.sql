@@ -0,0 +1,33 @@
+-- Archive table for old job executions. Each row keeps the summary columns
+-- queryable while the full execution, its tasks and job performance metrics
+-- are stored as a gzip-compressed JSON payload that can be restored later.
+CREATE TABLE IF NOT EXISTS job_execution_archives (
+    id UUID PRIMARY KEY, -- Original job_executions.id
+    name VARCHAR(255),
+    hashlist_id BIGINT, -- No FK: the hashlist may be purged after archival
+    status VARCHAR(50) NOT NULL,
+    attack_mode INTEGER,
+    hash_type INTEGER,
+    priority INTEGER,
+    created_by UUID,
+    total_keyspace BIGINT,
+    processed_keyspace BIGINT,
+    task_count INTEGER NOT NULL DEFAULT 0,
+    crack_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    payload BYTEA NOT NULL,
+    payload_size_bytes BIGINT NOT NULL DEFAULT 0,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    archived_by UUID REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_execution_archives_hashlist ON job_execution_archives(hashlist_id);
+CREATE INDEX IF NOT EXISTS idx_job_execution_archives_completed ON job_execution_archives(completed_at);
+CREATE INDEX IF NOT EXISTS idx_job_execution_archives_archived ON job_execution_archives(archived_at);
+
+-- Number of days after completion before a job execution is archived (0 = never)
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES ('job_archive_after_days', '0', 'Archive finished job executions and their tasks after this many days (0 = disabled)', 'integer')
+ON CONFLICT (key) DO NOTHING;
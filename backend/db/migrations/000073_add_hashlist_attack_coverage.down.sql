@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS record_job_execution_attack_coverage ON job_executions;
+DROP FUNCTION IF EXISTS record_hashlist_attack_coverage();
+DROP TABLE IF EXISTS hashlist_attack_coverage;
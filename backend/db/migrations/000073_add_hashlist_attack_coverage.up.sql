@@ -0,0 +1,106 @@
+-- Per-hashlist attack coverage. One row per distinct attack (mode, hash type,
+-- wordlists, rules, mask and extra args) that has finished against a hashlist,
+-- so users can see what has already been tried and avoid exact reruns.
+-- Rows are maintained by a trigger on job_executions and survive job archival.
+CREATE TABLE IF NOT EXISTS hashlist_attack_coverage (
+    id BIGSERIAL PRIMARY KEY,
+    hashlist_id BIGINT NOT NULL REFERENCES hashlists(id) ON DELETE CASCADE,
+    attack_mode INTEGER NOT NULL,
+    hash_type INTEGER NOT NULL,
+    wordlist_ids JSONB NOT NULL DEFAULT '[]',
+    rule_ids JSONB NOT NULL DEFAULT '[]',
+    mask VARCHAR(255) NOT NULL DEFAULT '',
+    additional_args TEXT NOT NULL DEFAULT '',
+    run_count INTEGER NOT NULL DEFAULT 0,
+    completed_count INTEGER NOT NULL DEFAULT 0,
+    best_progress_percent NUMERIC(6,3) NOT NULL DEFAULT 0,
+    keyspace BIGINT,
+    crack_count INTEGER NOT NULL DEFAULT 0,
+    last_job_execution_id UUID, -- No FK: the job may be archived
+    last_status VARCHAR(50) NOT NULL,
+    first_run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_hashlist_attack UNIQUE (hashlist_id, attack_mode, hash_type, wordlist_ids, rule_ids, mask, additional_args)
+);
+
+CREATE INDEX IF NOT EXISTS idx_hashlist_attack_coverage_hashlist ON hashlist_attack_coverage(hashlist_id);
+
+-- Record an attack on the hashlist whenever a job execution reaches a terminal status
+CREATE OR REPLACE FUNCTION record_hashlist_attack_coverage()
+RETURNS TRIGGER AS $$
+DECLARE
+    job_cracks INTEGER;
+    job_progress NUMERIC;
+BEGIN
+    IF NEW.status NOT IN ('completed', 'failed', 'cancelled') OR OLD.status = NEW.status THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT COALESCE(SUM(crack_count), 0) INTO job_cracks
+    FROM job_tasks WHERE job_execution_id = NEW.id;
+
+    IF NEW.status = 'completed' THEN
+        job_progress := 100;
+    ELSE
+        job_progress := LEAST(GREATEST(COALESCE(NEW.overall_progress_percent, 0), 0), 100);
+    END IF;
+
+    INSERT INTO hashlist_attack_coverage (
+        hashlist_id, attack_mode, hash_type, wordlist_ids, rule_ids, mask, additional_args,
+        run_count, completed_count, best_progress_percent, keyspace, crack_count,
+        last_job_execution_id, last_status, first_run_at, last_run_at
+    ) VALUES (
+        NEW.hashlist_id, NEW.attack_mode,
+        COALESCE(NEW.hash_type, (SELECT hash_type_id FROM hashlists WHERE id = NEW.hashlist_id)),
+        COALESCE(NEW.wordlist_ids, '[]'), COALESCE(NEW.rule_ids, '[]'),
+        COALESCE(NEW.mask, ''), COALESCE(NEW.additional_args, ''),
+        1, CASE WHEN NEW.status = 'completed' THEN 1 ELSE 0 END, job_progress,
+        COALESCE(NEW.effective_keyspace, NEW.total_keyspace), job_cracks,
+        NEW.id, NEW.status, COALESCE(NEW.started_at, NEW.created_at), COALESCE(NEW.completed_at, NOW())
+    )
+    ON CONFLICT (hashlist_id, attack_mode, hash_type, wordlist_ids, rule_ids, mask, additional_args) DO UPDATE SET
+        run_count = hashlist_attack_coverage.run_count + 1,
+        completed_count = hashlist_attack_coverage.completed_count + EXCLUDED.completed_count,
+        best_progress_percent = GREATEST(hashlist_attack_coverage.best_progress_percent, EXCLUDED.best_progress_percent),
+        keyspace = COALESCE(EXCLUDED.keyspace, hashlist_attack_coverage.keyspace),
+        crack_count = hashlist_attack_coverage.crack_count + EXCLUDED.crack_count,
+        last_job_execution_id = EXCLUDED.last_job_execution_id,
+        last_status = EXCLUDED.last_status,
+        last_run_at = EXCLUDED.last_run_at;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_job_execution_attack_coverage
+AFTER UPDATE OF status ON job_executions
+FOR EACH ROW
+EXECUTE FUNCTION record_hashlist_attack_coverage();
+
+-- Backfill coverage from job executions that have already finished
+INSERT INTO hashlist_attack_coverage (
+    hashlist_id, attack_mode, hash_type, wordlist_ids, rule_ids, mask, additional_args,
+    run_count, completed_count, best_progress_percent, keyspace, crack_count,
+    last_job_execution_id, last_status, first_run_at, last_run_at
+)
+SELECT
+    je.hashlist_id, je.attack_mode, COALESCE(je.hash_type, h.hash_type_id),
+    COALESCE(je.wordlist_ids, '[]'), COALESCE(je.rule_ids, '[]'),
+    COALESCE(je.mask, ''), COALESCE(je.additional_args, ''),
+    COUNT(*),
+    COUNT(*) FILTER (WHERE je.status = 'completed'),
+    MAX(CASE WHEN je.status = 'completed' THEN 100
+             ELSE LEAST(GREATEST(COALESCE(je.overall_progress_percent, 0), 0), 100) END),
+    MAX(COALESCE(je.effective_keyspace, je.total_keyspace)),
+    COALESCE(SUM((SELECT COALESCE(SUM(jt.crack_count), 0) FROM job_tasks jt WHERE jt.job_execution_id = je.id)), 0),
+    (ARRAY_AGG(je.id ORDER BY COALESCE(je.completed_at, je.updated_at) DESC))[1],
+    (ARRAY_AGG(je.status ORDER BY COALESCE(je.completed_at, je.updated_at) DESC))[1],
+    MIN(COALESCE(je.started_at, je.created_at)),
+    MAX(COALESCE(je.completed_at, je.updated_at))
+FROM job_executions je
+JOIN hashlists h ON h.id = je.hashlist_id
+WHERE je.status IN ('completed', 'failed', 'cancelled')
+GROUP BY je.hashlist_id, je.attack_mode, COALESCE(je.hash_type, h.hash_type_id),
+    COALESCE(je.wordlist_ids, '[]'), COALESCE(je.rule_ids, '[]'),
+    COALESCE(je.mask, ''), COALESCE(je.additional_args, '')
+ON CONFLICT DO NOTHING;
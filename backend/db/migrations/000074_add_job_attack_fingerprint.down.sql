@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_job_executions_hashlist_fingerprint;
+ALTER TABLE job_executions DROP COLUMN IF EXISTS attack_fingerprint;
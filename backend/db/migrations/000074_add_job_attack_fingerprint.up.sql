@@ -0,0 +1,29 @@
+-- Canonical attack fingerprint used to detect duplicate jobs at creation time.
+-- SHA-256 over attack mode, hash type, ordered wordlist and rule IDs, mask,
+-- whitespace-normalized additional args and binary version. Must stay in sync
+-- with models.ComputeAttackFingerprint.
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS attack_fingerprint VARCHAR(64);
+
+COMMENT ON COLUMN job_executions.attack_fingerprint IS 'SHA-256 of the canonical attack definition, used for duplicate job detection';
+
+CREATE INDEX IF NOT EXISTS idx_job_executions_hashlist_fingerprint
+    ON job_executions(hashlist_id, attack_fingerprint)
+    WHERE attack_fingerprint IS NOT NULL;
+
+-- Backfill fingerprints for existing jobs
+UPDATE job_executions je
+SET attack_fingerprint = encode(sha256(convert_to(concat_ws('|',
+        je.attack_mode,
+        COALESCE(je.hash_type, h.hash_type_id),
+        COALESCE((SELECT string_agg(e.id, ',' ORDER BY e.ord)
+            FROM jsonb_array_elements_text(COALESCE(je.wordlist_ids, '[]')) WITH ORDINALITY AS e(id, ord)), ''),
+        COALESCE((SELECT string_agg(e.id, ',' ORDER BY e.ord)
+            FROM jsonb_array_elements_text(COALESCE(je.rule_ids, '[]')) WITH ORDINALITY AS e(id, ord)), ''),
+        COALESCE(je.mask, ''),
+        btrim(regexp_replace(COALESCE(je.additional_args, ''), '\s+', ' ', 'g')),
+        COALESCE(je.binary_version_id, 0)
+    ), 'UTF8')), 'hex')
+FROM hashlists h
+WHERE h.id = je.hashlist_id
+  AND je.attack_fingerprint IS NULL;
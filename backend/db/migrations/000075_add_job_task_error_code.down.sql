@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_job_tasks_error_code;
+ALTER TABLE job_tasks DROP COLUMN IF EXISTS error_code;
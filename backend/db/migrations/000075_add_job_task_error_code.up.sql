@@ -0,0 +1,7 @@
+-- Typed classification of hashcat failures reported by agents (see models.ClassifyTaskError)
+ALTER TABLE job_tasks
+    ADD COLUMN IF NOT EXISTS error_code VARCHAR(50);
+
+COMMENT ON COLUMN job_tasks.error_code IS 'Classified hashcat failure, e.g. kernel_build_failed, no_devices, token_length_exception';
+
+CREATE INDEX IF NOT EXISTS idx_job_tasks_error_code ON job_tasks(error_code) WHERE error_code IS NOT NULL;
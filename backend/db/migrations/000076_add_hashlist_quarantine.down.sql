@@ -0,0 +1,6 @@
+UPDATE hashlists SET status = 'ready' WHERE status = 'ready_with_errors';
+ALTER TABLE hashlists DROP CONSTRAINT IF EXISTS hashlists_status_check;
+ALTER TABLE hashlists ADD CONSTRAINT hashlists_status_check
+    CHECK (status IN ('uploading', 'processing', 'ready', 'error'));
+
+DROP TABLE IF EXISTS hashlist_quarantine;
@@ -0,0 +1,17 @@
+-- Lines rejected while parsing a hashlist are quarantined instead of silently
+-- dropped, so users can download, fix and reprocess them.
+CREATE TABLE IF NOT EXISTS hashlist_quarantine (
+    id BIGSERIAL PRIMARY KEY,
+    hashlist_id BIGINT NOT NULL REFERENCES hashlists(id) ON DELETE CASCADE,
+    line_number INTEGER NOT NULL,
+    line_content TEXT NOT NULL,
+    error_reason TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_hashlist_quarantine_hashlist ON hashlist_quarantine(hashlist_id, line_number);
+
+-- Allow the ready_with_errors status used when lines were quarantined
+ALTER TABLE hashlists DROP CONSTRAINT IF EXISTS hashlists_status_check;
+ALTER TABLE hashlists ADD CONSTRAINT hashlists_status_check
+    CHECK (status IN ('uploading', 'processing', 'ready', 'ready_with_errors', 'error'));
@@ -0,0 +1,10 @@
+-- Items still in the trash become visible again once the columns are gone
+DELETE FROM client_settings WHERE key = 'trash_retention_days';
+
+DROP INDEX IF EXISTS idx_clients_deleted_at;
+DROP INDEX IF EXISTS idx_job_executions_deleted_at;
+DROP INDEX IF EXISTS idx_hashlists_deleted_at;
+
+ALTER TABLE clients DROP COLUMN IF EXISTS deleted_by, DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE job_executions DROP COLUMN IF EXISTS deleted_by, DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE hashlists DROP COLUMN IF EXISTS deleted_by, DROP COLUMN IF EXISTS deleted_at;
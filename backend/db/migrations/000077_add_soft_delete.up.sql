@@ -0,0 +1,22 @@
+-- Soft deletion: deleted hashlists, jobs and clients are kept in the trash
+-- until the trash retention window expires, and can be restored by an admin.
+ALTER TABLE hashlists
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE clients
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_hashlists_deleted_at ON hashlists(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_job_executions_deleted_at ON job_executions(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_clients_deleted_at ON clients(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Number of days deleted items stay in the trash before being purged (0 = delete immediately)
+INSERT INTO client_settings (key, value, description)
+VALUES ('trash_retention_days', '30', 'Number of days deleted hashlists, jobs and clients are kept in the trash before being permanently purged. 0 means delete immediately.')
+ON CONFLICT (key) DO NOTHING;
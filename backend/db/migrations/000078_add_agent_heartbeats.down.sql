@@ -0,0 +1,3 @@
+DELETE FROM system_settings WHERE key = 'agent_heartbeat_history_size';
+
+DROP TABLE IF EXISTS agent_heartbeats;
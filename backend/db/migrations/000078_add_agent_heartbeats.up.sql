@@ -0,0 +1,22 @@
+-- Recent heartbeat samples per agent, used for utilization sparklines and
+-- spotting agents whose connection keeps dropping
+CREATE TABLE IF NOT EXISTS agent_heartbeats (
+    id BIGSERIAL PRIMARY KEY,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    current_task_id UUID,
+    gpu_utilization DOUBLE PRECISION,
+    queue_depth INTEGER NOT NULL DEFAULT 0,
+    reconnect_count INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_heartbeats_agent_received ON agent_heartbeats(agent_id, received_at DESC);
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES (
+    'agent_heartbeat_history_size',
+    '120',
+    'Number of recent heartbeats kept per agent for the agent detail view',
+    'integer'
+)
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,9 @@
+DELETE FROM system_settings WHERE key IN (
+    'speculative_dispatch_enabled',
+    'speculative_straggler_factor',
+    'speculative_min_job_progress'
+);
+
+DROP INDEX IF EXISTS idx_job_tasks_speculative_of;
+
+ALTER TABLE job_tasks DROP COLUMN IF EXISTS speculative_of;
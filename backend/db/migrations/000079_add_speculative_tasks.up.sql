@@ -0,0 +1,13 @@
+-- A speculative task re-runs the keyspace of a straggling task on another
+-- agent. Whichever finishes first wins and the other is cancelled.
+ALTER TABLE job_tasks
+    ADD COLUMN IF NOT EXISTS speculative_of UUID REFERENCES job_tasks(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_job_tasks_speculative_of ON job_tasks(speculative_of) WHERE speculative_of IS NOT NULL;
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('speculative_dispatch_enabled', 'false', 'Re-run straggling chunks on idle agents when a job is nearly done', 'boolean'),
+    ('speculative_straggler_factor', '3', 'A running chunk is a straggler once it has taken this many times its expected duration', 'float'),
+    ('speculative_min_job_progress', '90', 'Minimum job progress percentage before straggling chunks are re-dispatched', 'float')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS update_agent_power_budgets_updated_at ON agent_power_budgets;
+DROP TRIGGER IF EXISTS update_agent_power_windows_updated_at ON agent_power_windows;
+DROP TABLE IF EXISTS agent_power_budgets;
+DROP TABLE IF EXISTS agent_power_windows;
@@ -0,0 +1,45 @@
+-- Add low-power windows and daily power budgets to the agent scheduling system
+
+-- Create agent_power_windows table; during a window the agent only runs jobs at or below max_priority
+CREATE TABLE agent_power_windows (
+    id SERIAL PRIMARY KEY,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    day_of_week INTEGER NOT NULL CHECK (day_of_week >= 0 AND day_of_week <= 6),
+    start_time TIME NOT NULL,  -- Stored in UTC
+    end_time TIME NOT NULL,    -- Stored in UTC
+    timezone VARCHAR(50) NOT NULL DEFAULT 'UTC',  -- Store the original timezone for reference
+    max_priority INTEGER NOT NULL CHECK (max_priority >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_agent_power_window_day UNIQUE (agent_id, day_of_week),
+    CONSTRAINT valid_power_window_range CHECK (end_time != start_time)
+);
+
+CREATE INDEX idx_agent_power_windows_agent_id ON agent_power_windows(agent_id);
+
+COMMENT ON TABLE agent_power_windows IS 'Low-power windows during which an agent only accepts jobs up to a priority';
+COMMENT ON COLUMN agent_power_windows.max_priority IS 'Highest job priority the agent accepts during the window';
+
+-- Create agent_power_budgets table; energy use is estimated from task run time at the configured draw
+CREATE TABLE agent_power_budgets (
+    agent_id INTEGER PRIMARY KEY REFERENCES agents(id) ON DELETE CASCADE,
+    power_draw_watts INTEGER NOT NULL CHECK (power_draw_watts > 0),
+    daily_budget_wh INTEGER NOT NULL CHECK (daily_budget_wh > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE agent_power_budgets IS 'Daily energy budget per agent, reset at midnight UTC';
+COMMENT ON COLUMN agent_power_budgets.power_draw_watts IS 'Estimated draw of the agent while running a task';
+
+-- Create triggers to update updated_at timestamp
+CREATE TRIGGER update_agent_power_windows_updated_at
+    BEFORE UPDATE ON agent_power_windows
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_agent_power_budgets_updated_at
+    BEFORE UPDATE ON agent_power_budgets
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+DELETE FROM system_settings WHERE key = 'chunk_dedup_enabled';
+
+DROP INDEX IF EXISTS idx_job_tasks_reused_from;
+
+ALTER TABLE job_tasks DROP COLUMN IF EXISTS reused_from;
@@ -0,0 +1,10 @@
+-- A reused task takes over the results of an identical completed chunk from
+-- another job with the same attack on the same hashlist instead of running it again.
+ALTER TABLE job_tasks
+    ADD COLUMN IF NOT EXISTS reused_from UUID REFERENCES job_tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_job_tasks_reused_from ON job_tasks(reused_from) WHERE reused_from IS NOT NULL;
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES ('chunk_dedup_enabled', 'true', 'Reuse completed chunks from other jobs with an identical attack on the same hashlist', 'boolean')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,4 @@
+DELETE FROM system_settings WHERE key = 'support_session_max_minutes';
+
+DROP TABLE IF EXISTS support_session_events;
+DROP TABLE IF EXISTS support_sessions;
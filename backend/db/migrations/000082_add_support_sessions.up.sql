@@ -0,0 +1,36 @@
+-- Support sessions let an admin view the system as another user for a limited
+-- time. Usernames are copied so the audit trail survives deleting either user.
+CREATE TABLE IF NOT EXISTS support_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    admin_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    admin_username VARCHAR(255) NOT NULL,
+    target_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    target_username VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL,
+    ip_address TEXT,
+    user_agent TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_support_sessions_admin ON support_sessions(admin_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_support_sessions_target ON support_sessions(target_user_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_support_sessions_started ON support_sessions(started_at DESC);
+
+-- Every request made during a support session, including those that were refused
+CREATE TABLE IF NOT EXISTS support_session_events (
+    id BIGSERIAL PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES support_sessions(id) ON DELETE CASCADE,
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('started', 'request', 'denied', 'ended')),
+    method VARCHAR(10),
+    path TEXT,
+    detail TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_support_session_events_session ON support_session_events(session_id, created_at);
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES ('support_session_max_minutes', '60', 'Longest an admin support session may last, in minutes', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_agents_bootstrap_token;
+
+ALTER TABLE agents DROP COLUMN IF EXISTS bootstrap_token;
@@ -0,0 +1,7 @@
+-- A bootstrap token lets a pre-registered agent fetch its credentials on first
+-- start. The same token always returns the same agent, so provisioning can be
+-- re-run safely.
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS bootstrap_token VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_agents_bootstrap_token ON agents(bootstrap_token) WHERE bootstrap_token IS NOT NULL;
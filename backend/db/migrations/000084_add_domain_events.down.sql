@@ -0,0 +1,3 @@
+DELETE FROM system_settings WHERE key = 'domain_event_retention_days';
+
+DROP TABLE IF EXISTS domain_events;
@@ -0,0 +1,22 @@
+-- Outbox for the internal event bus. Publishers insert an event (and NOTIFY
+-- kh_domain_events) in the same transaction as the change it describes; each
+-- event is claimed and handled by exactly one backend.
+CREATE TABLE IF NOT EXISTS domain_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    available_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    claimed_by VARCHAR(100),
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_events_pending ON domain_events(available_at, id) WHERE processed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_domain_events_processed ON domain_events(processed_at) WHERE processed_at IS NOT NULL;
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES ('domain_event_retention_days', '7', 'Days to keep handled internal events before they are deleted', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,5 @@
+DELETE FROM system_settings WHERE key = 'chunk_overlap_candidates';
+
+ALTER TABLE job_tasks DROP COLUMN IF EXISTS chunk_overlap;
+
+ALTER TABLE job_executions DROP COLUMN IF EXISTS chunk_overlap;
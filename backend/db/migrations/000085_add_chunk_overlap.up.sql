@@ -0,0 +1,17 @@
+-- Chunk overlap: a keyspace chunk is dispatched starting a number of candidates
+-- before its real start so candidates lost at --skip/--limit boundaries (e.g. when
+-- rules reorder candidates) are still tried. Cracks from the overlap are
+-- deduplicated server side because already cracked hashes are skipped.
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS chunk_overlap BIGINT CHECK (chunk_overlap >= 0);
+
+COMMENT ON COLUMN job_executions.chunk_overlap IS 'Per-job chunk overlap in candidates, NULL uses the chunk_overlap_candidates setting';
+
+ALTER TABLE job_tasks
+    ADD COLUMN IF NOT EXISTS chunk_overlap BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN job_tasks.chunk_overlap IS 'Candidates before keyspace_start that were dispatched with this task';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES ('chunk_overlap_candidates', '0', 'Candidates each keyspace chunk re-processes before its start to cover boundary losses (0 disables)', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,5 @@
+DELETE FROM system_settings WHERE key IN ('device_memory_admission_enabled', 'device_memory_reserve_mb');
+
+ALTER TABLE agent_devices
+    DROP COLUMN IF EXISTS memory_free_mb,
+    DROP COLUMN IF EXISTS memory_total_mb;
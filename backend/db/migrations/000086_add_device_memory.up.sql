@@ -0,0 +1,14 @@
+-- Device memory reported by hashcat during device detection, used to refuse or
+-- resize task assignments that would not fit on an agent's devices
+ALTER TABLE agent_devices
+    ADD COLUMN IF NOT EXISTS memory_total_mb BIGINT,
+    ADD COLUMN IF NOT EXISTS memory_free_mb BIGINT;
+
+COMMENT ON COLUMN agent_devices.memory_total_mb IS 'Total device memory in MB at detection, NULL if not reported';
+COMMENT ON COLUMN agent_devices.memory_free_mb IS 'Free device memory in MB at detection, NULL if not reported';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('device_memory_admission_enabled', 'true', 'Refuse or resize task assignments whose estimated memory needs exceed the agent''s device memory', 'boolean'),
+    ('device_memory_reserve_mb', '512', 'Device memory in MB kept free for hashcat kernels and candidate buffers when checking whether an attack fits', 'integer')
+ON CONFLICT (key) DO NOTHING;
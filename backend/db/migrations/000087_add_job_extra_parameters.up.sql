@@ -0,0 +1,7 @@
+-- Per-job extra hashcat parameters, e.g. --bitmap-max or -S, merged over the
+-- agent's own extra parameters. Options the agent manages itself are refused
+-- when the parameters are set.
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS extra_parameters TEXT;
+
+COMMENT ON COLUMN job_executions.extra_parameters IS 'Extra hashcat parameters for this job, taking precedence over the agent extra parameters';
@@ -0,0 +1 @@
+ALTER TABLE analytics_reports DROP COLUMN IF EXISTS password_policy;
@@ -0,0 +1,6 @@
+-- Password policy to check cracked plaintexts against in an analytics report,
+-- so the report can state how many cracked passwords the policy still allows
+ALTER TABLE analytics_reports
+    ADD COLUMN IF NOT EXISTS password_policy JSONB;
+
+COMMENT ON COLUMN analytics_reports.password_policy IS 'Client password policy the cracked passwords are checked against (NULL skips the compliance check)';
@@ -0,0 +1,5 @@
+DELETE FROM system_settings WHERE key IN ('breach_check_mode', 'breach_check_api_url', 'breach_corpus_path');
+
+DROP TABLE IF EXISTS hashlist_breach_checks;
+
+ALTER TABLE hashes DROP COLUMN IF EXISTS breach_count;
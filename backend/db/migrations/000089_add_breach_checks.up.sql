@@ -0,0 +1,30 @@
+-- Breach corpus correlation: cracked plaintexts are looked up in a known
+-- breach corpus (Have I Been Pwned k-anonymity API or a local corpus file)
+ALTER TABLE hashes
+    ADD COLUMN IF NOT EXISTS breach_count INTEGER;
+
+COMMENT ON COLUMN hashes.breach_count IS 'Times the cracked password appears in the breach corpus, 0 if absent, NULL if not checked';
+
+CREATE TABLE IF NOT EXISTS hashlist_breach_checks (
+    hashlist_id BIGINT PRIMARY KEY REFERENCES hashlists(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    source VARCHAR(20) NOT NULL,
+    checked_count INTEGER NOT NULL DEFAULT 0,
+    breached_count INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    error_message TEXT,
+    CONSTRAINT valid_breach_check_status CHECK (status IN ('running', 'completed', 'failed')),
+    CONSTRAINT valid_breach_check_source CHECK (source IN ('online', 'offline'))
+);
+
+COMMENT ON TABLE hashlist_breach_checks IS 'Latest breach corpus check of each hashlist';
+COMMENT ON COLUMN hashlist_breach_checks.checked_count IS 'Cracked hashes that were looked up';
+COMMENT ON COLUMN hashlist_breach_checks.breached_count IS 'Cracked hashes whose password is in the breach corpus';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('breach_check_mode', 'disabled', 'Breach corpus lookups for cracked passwords: disabled, online (k-anonymity API) or offline (local corpus file)', 'string'),
+    ('breach_check_api_url', 'https://api.pwnedpasswords.com/range/', 'Base URL of the k-anonymity range API used in online mode', 'string'),
+    ('breach_corpus_path', '', 'Path to a local Pwned Passwords corpus ordered by hash (NTLM or SHA-1) used in offline mode', 'string')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1 @@
+DELETE FROM system_settings WHERE key = 'bundle_trusted_keys';
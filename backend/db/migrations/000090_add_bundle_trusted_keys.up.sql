@@ -0,0 +1,5 @@
+-- Deployment bundles: public keys of servers whose signed bundles may be imported
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('bundle_trusted_keys', '', 'Comma separated base64 ed25519 public keys of servers whose deployment bundles may be imported', 'string')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1 @@
+DELETE FROM system_settings WHERE key IN ('max_running_jobs_per_user', 'max_running_jobs_per_client');
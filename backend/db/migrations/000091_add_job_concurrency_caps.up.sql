@@ -0,0 +1,6 @@
+-- Job concurrency caps: limit how many jobs one user or one client can run at once
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('max_running_jobs_per_user', '0', 'Maximum number of jobs created by one user that may run at the same time (0 = unlimited)', 'integer'),
+    ('max_running_jobs_per_client', '0', 'Maximum number of jobs on one client''s hashlists that may run at the same time (0 = unlimited)', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_hash_types_aliases;
+
+ALTER TABLE hash_types
+    DROP COLUMN IF EXISTS is_custom,
+    DROP COLUMN IF EXISTS validation_regex,
+    DROP COLUMN IF EXISTS aliases;
@@ -0,0 +1,26 @@
+-- Hash type aliases, validation patterns and admin defined custom hash modes
+ALTER TABLE hash_types
+    ADD COLUMN aliases TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN validation_regex TEXT,
+    ADD COLUMN is_custom BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_hash_types_aliases ON hash_types USING GIN (aliases);
+
+COMMENT ON COLUMN hash_types.aliases IS 'Lowercase alternative names the hash type can be referred to by';
+COMMENT ON COLUMN hash_types.validation_regex IS 'Pattern every hash of this type must match, checked when a hashlist is loaded';
+COMMENT ON COLUMN hash_types.is_custom IS 'Added by an administrator rather than shipped with the backend';
+
+UPDATE hash_types SET aliases = '{md5}' WHERE id = 0;
+UPDATE hash_types SET aliases = '{sha1}' WHERE id = 100;
+UPDATE hash_types SET aliases = '{md5crypt}' WHERE id = 500;
+UPDATE hash_types SET aliases = '{ntlm,nt}' WHERE id = 1000;
+UPDATE hash_types SET aliases = '{sha256}' WHERE id = 1400;
+UPDATE hash_types SET aliases = '{sha512}' WHERE id = 1700;
+UPDATE hash_types SET aliases = '{sha512crypt}' WHERE id = 1800;
+UPDATE hash_types SET aliases = '{lm}' WHERE id = 3000;
+UPDATE hash_types SET aliases = '{bcrypt}' WHERE id = 3200;
+UPDATE hash_types SET aliases = '{netntlmv1}' WHERE id = 5500;
+UPDATE hash_types SET aliases = '{netntlmv2}' WHERE id = 5600;
+UPDATE hash_types SET aliases = '{kerberoast,tgs-rep}' WHERE id = 13100;
+UPDATE hash_types SET aliases = '{asreproast,as-rep}' WHERE id = 18200;
+UPDATE hash_types SET aliases = '{wpa,wpa2}' WHERE id = 22000;
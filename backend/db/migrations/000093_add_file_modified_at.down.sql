@@ -0,0 +1,2 @@
+ALTER TABLE rules DROP COLUMN IF EXISTS file_modified_at;
+ALTER TABLE wordlists DROP COLUMN IF EXISTS file_modified_at;
@@ -0,0 +1,7 @@
+-- Modification time of wordlist and rule files as last hashed by the directory
+-- monitor, together with file_size it lets unchanged files skip rehashing
+ALTER TABLE wordlists ADD COLUMN IF NOT EXISTS file_modified_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS file_modified_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN wordlists.file_modified_at IS 'File modification time when the directory monitor last hashed the file';
+COMMENT ON COLUMN rules.file_modified_at IS 'File modification time when the directory monitor last hashed the file';
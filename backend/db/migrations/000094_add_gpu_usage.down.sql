@@ -0,0 +1,3 @@
+DELETE FROM system_settings WHERE key = 'gpu_hour_cost';
+
+DROP TABLE IF EXISTS task_gpu_usage;
@@ -0,0 +1,24 @@
+-- GPU time consumed per task and device, accumulated from progress updates.
+-- The job name and client are copied so cost reports survive deleting the job.
+CREATE TABLE IF NOT EXISTS task_gpu_usage (
+    id BIGSERIAL PRIMARY KEY,
+    task_id UUID NOT NULL,
+    job_execution_id UUID REFERENCES job_executions(id) ON DELETE SET NULL,
+    job_name VARCHAR(255) NOT NULL DEFAULT '',
+    client_id UUID REFERENCES clients(id) ON DELETE SET NULL,
+    agent_id INTEGER REFERENCES agents(id) ON DELETE SET NULL,
+    device_id INTEGER NOT NULL,
+    device_name VARCHAR(255) NOT NULL DEFAULT '',
+    gpu_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+    first_reported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_reported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (task_id, agent_id, device_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_gpu_usage_job ON task_gpu_usage(job_execution_id);
+CREATE INDEX IF NOT EXISTS idx_task_gpu_usage_client ON task_gpu_usage(client_id, last_reported_at);
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('gpu_hour_cost', '0', 'Cost of one GPU-hour used to estimate job and client cost reports (0 = report GPU-hours only)', 'float')
+ON CONFLICT (key) DO NOTHING;
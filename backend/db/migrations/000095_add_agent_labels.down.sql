@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_agents_labels;
+
+ALTER TABLE agents DROP COLUMN IF EXISTS labels;
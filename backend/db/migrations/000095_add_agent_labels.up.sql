@@ -0,0 +1,4 @@
+-- Free-form labels used to select groups of agents for bulk operations
+ALTER TABLE agents ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_agents_labels ON agents USING GIN (labels);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_hashlists_source_upload_id;
+ALTER TABLE hashlists DROP COLUMN IF EXISTS source_upload_id;
+DROP TABLE IF EXISTS hashlist_source_uploads;
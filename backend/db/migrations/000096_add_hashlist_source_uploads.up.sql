@@ -0,0 +1,13 @@
+-- Uploads that were split into one hashlist per hash type
+CREATE TABLE IF NOT EXISTS hashlist_source_uploads (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    file_name VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id UUID REFERENCES clients(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE hashlists
+    ADD COLUMN IF NOT EXISTS source_upload_id UUID REFERENCES hashlist_source_uploads(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_hashlists_source_upload_id ON hashlists(source_upload_id) WHERE source_upload_id IS NOT NULL;
@@ -0,0 +1,19 @@
+DROP INDEX IF EXISTS idx_hashlists_tags;
+DROP INDEX IF EXISTS idx_hashlists_search_vector;
+DROP INDEX IF EXISTS idx_job_executions_tags;
+DROP INDEX IF EXISTS idx_job_executions_search_vector;
+
+DROP TRIGGER IF EXISTS update_hashlists_search_vector ON hashlists;
+DROP TRIGGER IF EXISTS update_job_executions_search_vector ON job_executions;
+DROP FUNCTION IF EXISTS update_search_vector_column();
+DROP FUNCTION IF EXISTS name_notes_tags_search_vector(TEXT, TEXT, TEXT[]);
+
+ALTER TABLE hashlists
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS notes;
+
+ALTER TABLE job_executions
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS notes;
@@ -0,0 +1,54 @@
+-- Free-form notes and tags on jobs and hashlists, searchable together with their names
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+ALTER TABLE hashlists
+    ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+-- Names weigh most, then tags, then notes
+CREATE OR REPLACE FUNCTION name_notes_tags_search_vector(name TEXT, notes TEXT, tags TEXT[])
+RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+           setweight(to_tsvector('english', array_to_string(tags, ' ')), 'B') ||
+           setweight(to_tsvector('english', COALESCE(notes, '')), 'C');
+$$ LANGUAGE sql STABLE;
+
+-- A trigger rather than a generated column, so archived jobs can still be
+-- restored with INSERT ... SELECT *
+CREATE OR REPLACE FUNCTION update_search_vector_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector = name_notes_tags_search_vector(NEW.name, NEW.notes, NEW.tags);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS update_job_executions_search_vector ON job_executions;
+CREATE TRIGGER update_job_executions_search_vector
+    BEFORE INSERT OR UPDATE OF name, notes, tags ON job_executions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_search_vector_column();
+
+DROP TRIGGER IF EXISTS update_hashlists_search_vector ON hashlists;
+CREATE TRIGGER update_hashlists_search_vector
+    BEFORE INSERT OR UPDATE OF name, notes, tags ON hashlists
+    FOR EACH ROW
+    EXECUTE FUNCTION update_search_vector_column();
+
+-- Fill in existing rows without touching their updated_at
+ALTER TABLE job_executions DISABLE TRIGGER trigger_update_job_executions_updated_at;
+UPDATE job_executions SET search_vector = name_notes_tags_search_vector(name, notes, tags);
+ALTER TABLE job_executions ENABLE TRIGGER trigger_update_job_executions_updated_at;
+
+ALTER TABLE hashlists DISABLE TRIGGER update_hashlists_updated_at;
+UPDATE hashlists SET search_vector = name_notes_tags_search_vector(name, notes, tags);
+ALTER TABLE hashlists ENABLE TRIGGER update_hashlists_updated_at;
+
+CREATE INDEX IF NOT EXISTS idx_job_executions_search_vector ON job_executions USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_job_executions_tags ON job_executions USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_hashlists_search_vector ON hashlists USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_hashlists_tags ON hashlists USING GIN (tags);
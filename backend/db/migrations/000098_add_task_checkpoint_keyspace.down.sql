@@ -0,0 +1,3 @@
+ALTER TABLE job_tasks
+    DROP COLUMN IF EXISTS resume_offset,
+    DROP COLUMN IF EXISTS checkpoint_keyspace;
@@ -0,0 +1,9 @@
+-- Task checkpoints: agents report hashcat's restore point with their progress.
+-- Every candidate before it has been fully processed, so a chunk that is
+-- reassigned after its agent crashed resumes there instead of at its start.
+ALTER TABLE job_tasks
+    ADD COLUMN IF NOT EXISTS checkpoint_keyspace BIGINT,
+    ADD COLUMN IF NOT EXISTS resume_offset BIGINT NOT NULL DEFAULT 0 CHECK (resume_offset >= 0);
+
+COMMENT ON COLUMN job_tasks.checkpoint_keyspace IS 'Absolute keyspace position of the last reported hashcat restore point, NULL before the first checkpoint';
+COMMENT ON COLUMN job_tasks.resume_offset IS 'Candidates after keyspace_start that the current dispatch skips because they were checkpointed';
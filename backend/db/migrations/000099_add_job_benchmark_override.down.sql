@@ -0,0 +1,3 @@
+ALTER TABLE job_executions
+    DROP COLUMN IF EXISTS benchmark_duration_seconds,
+    DROP COLUMN IF EXISTS skip_benchmark;
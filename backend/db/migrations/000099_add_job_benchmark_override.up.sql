@@ -0,0 +1,8 @@
+-- Benchmark override: short engagements can skip the forced benchmark before a
+-- job's first task, accepting the estimated keyspace, or time-box it.
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS skip_benchmark BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS benchmark_duration_seconds INTEGER CHECK (benchmark_duration_seconds BETWEEN 10 AND 600);
+
+COMMENT ON COLUMN job_executions.skip_benchmark IS 'Skip the forced benchmark before the first task and chunk on the estimated keyspace';
+COMMENT ON COLUMN job_executions.benchmark_duration_seconds IS 'Time limit of benchmarks run for this job, NULL uses the speedtest_timeout_seconds setting';
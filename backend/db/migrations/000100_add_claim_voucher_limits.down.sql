@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_claim_voucher_usage_agent;
+
+ALTER TABLE claim_voucher_usage
+    DROP COLUMN IF EXISTS agent_id;
+
+DELETE FROM claim_voucher_usage WHERE attempted_by_id IS NULL;
+
+ALTER TABLE claim_voucher_usage
+    ALTER COLUMN attempted_by_id SET NOT NULL;
+
+ALTER TABLE claim_vouchers
+    DROP COLUMN IF EXISTS labels,
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS use_count,
+    DROP COLUMN IF EXISTS max_uses;
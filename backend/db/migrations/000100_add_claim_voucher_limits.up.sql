@@ -0,0 +1,21 @@
+-- Claim voucher limits: continuous vouchers can be capped to a number of
+-- registrations and expire, and tag every agent they register with labels.
+ALTER TABLE claim_vouchers
+    ADD COLUMN IF NOT EXISTS max_uses INTEGER CHECK (max_uses > 0),
+    ADD COLUMN IF NOT EXISTS use_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+
+-- Registrations are recorded against the agent they created; agents register
+-- without a user session
+ALTER TABLE claim_voucher_usage
+    ALTER COLUMN attempted_by_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS agent_id INTEGER REFERENCES agents(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_claim_voucher_usage_agent ON claim_voucher_usage(agent_id);
+
+COMMENT ON COLUMN claim_vouchers.max_uses IS 'Number of agents a continuous voucher may register, NULL is unlimited';
+COMMENT ON COLUMN claim_vouchers.use_count IS 'Number of agents registered with the voucher';
+COMMENT ON COLUMN claim_vouchers.expires_at IS 'Time after which the voucher can no longer be redeemed, NULL never expires';
+COMMENT ON COLUMN claim_vouchers.labels IS 'Labels applied to every agent registered with the voucher';
+COMMENT ON COLUMN claim_voucher_usage.agent_id IS 'Agent registered by this use of the voucher';
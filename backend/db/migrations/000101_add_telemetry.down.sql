@@ -0,0 +1,12 @@
+DELETE FROM system_settings WHERE key IN (
+    'telemetry_enabled',
+    'telemetry_endpoint',
+    'telemetry_token',
+    'telemetry_interval_hours',
+    'telemetry_instance_label',
+    'telemetry_instance_id',
+    'telemetry_last_sent_at',
+    'telemetry_collector_token'
+);
+
+DROP TABLE IF EXISTS telemetry_reports;
@@ -0,0 +1,32 @@
+-- Opt-in anonymized statistics: an instance can publish aggregate cracking
+-- statistics to a collector, and any instance can act as the collector that
+-- compares the clusters reporting to it. Reports never contain hashes,
+-- plaintexts, usernames, client or hashlist names.
+CREATE TABLE IF NOT EXISTS telemetry_reports (
+    id BIGSERIAL PRIMARY KEY,
+    instance_id UUID NOT NULL,
+    instance_label VARCHAR(100),
+    schema_version INTEGER NOT NULL,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    report JSONB NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_telemetry_reports_instance ON telemetry_reports(instance_id, received_at DESC);
+
+COMMENT ON TABLE telemetry_reports IS 'Anonymized statistics received from instances publishing to this collector';
+COMMENT ON COLUMN telemetry_reports.instance_id IS 'Random identifier of the reporting instance, from its telemetry_instance_id setting';
+COMMENT ON COLUMN telemetry_reports.report IS 'The report as received: hash type and attack mode aggregates';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('telemetry_enabled', 'false', 'Publish anonymized cracking statistics to the telemetry_endpoint collector', 'boolean'),
+    ('telemetry_endpoint', '', 'URL of the collector reports are published to, e.g. https://dashboard.example.com/api/telemetry/reports', 'string'),
+    ('telemetry_token', '', 'Bearer token sent with published reports, must match the collector''s telemetry_collector_token', 'string'),
+    ('telemetry_interval_hours', '24', 'Hours between published reports', 'integer'),
+    ('telemetry_instance_label', '', 'Optional name shown for this instance on the collector, e.g. a cluster name', 'string'),
+    ('telemetry_instance_id', gen_random_uuid()::text, 'Random identifier of this instance in published reports', 'string'),
+    ('telemetry_last_sent_at', '', 'End of the period covered by the last published report', 'string'),
+    ('telemetry_collector_token', '', 'Accept reports from other instances at /api/telemetry/reports with this bearer token, empty disables the collector', 'string')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS custom_charsets;
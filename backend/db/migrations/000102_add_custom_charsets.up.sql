@@ -0,0 +1,23 @@
+-- Library of reusable custom charsets for mask attacks. A job's mask carries
+-- the charsets it uses in hcmask format, so library entries are copied into
+-- the mask and later edits do not change existing jobs.
+CREATE TABLE IF NOT EXISTS custom_charsets (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    charset VARCHAR(512) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE custom_charsets IS 'Reusable custom charsets for the ?1 to ?4 placeholders of masks';
+COMMENT ON COLUMN custom_charsets.charset IS 'Charset in hashcat syntax, built-in placeholders and literal characters, e.g. ?l?d or !@#$';
+
+INSERT INTO custom_charsets (name, charset, description)
+VALUES
+    ('common-specials', '!@#$%&*?', 'Special characters most often used in passwords'),
+    ('lower-digits', '?l?d', 'Lower case letters and digits'),
+    ('upper-digits', '?u?d', 'Upper case letters and digits'),
+    ('vowels', 'aeiou', 'Lower case vowels')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,18 @@
+-- Rule chunks generated for rule-split jobs are registered like other synced
+-- files, so agents download them with a known MD5 hash and cleanup removes
+-- the record together with the file.
+CREATE TABLE IF NOT EXISTS rule_chunk_files (
+    id BIGSERIAL PRIMARY KEY,
+    job_execution_id UUID NOT NULL REFERENCES job_executions(id) ON DELETE CASCADE,
+    name VARCHAR(512) NOT NULL UNIQUE,
+    rule_start_index INTEGER NOT NULL,
+    rule_end_index INTEGER NOT NULL,
+    md5_hash VARCHAR(32) NOT NULL,
+    file_size BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rule_chunk_files_job ON rule_chunk_files(job_execution_id);
+
+COMMENT ON TABLE rule_chunk_files IS 'Rule chunk files generated for rule-split jobs and downloaded by agents';
+COMMENT ON COLUMN rule_chunk_files.name IS 'Path below the agent rules directory, e.g. chunks/job_<id>/chunk_0_1000.rule';
@@ -0,0 +1,6 @@
+DELETE FROM system_settings WHERE key = 'background_job_idle_minutes';
+
+DROP INDEX IF EXISTS idx_job_executions_background;
+
+ALTER TABLE job_executions
+    DROP COLUMN IF EXISTS is_background;
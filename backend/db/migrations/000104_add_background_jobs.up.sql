@@ -0,0 +1,14 @@
+-- Background jobs: a priority class for long-running research attacks that
+-- only runs on agents that have been idle for a while and yields them as soon
+-- as any normal job has work for them.
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS is_background BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN job_executions.is_background IS 'Run only on idle agents and give way to any normal job';
+
+CREATE INDEX IF NOT EXISTS idx_job_executions_background ON job_executions(is_background) WHERE is_background = true;
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('background_job_idle_minutes', '10', 'Minutes an agent must have been without normal work before it runs background jobs', 'integer')
+ON CONFLICT (key) DO NOTHING;
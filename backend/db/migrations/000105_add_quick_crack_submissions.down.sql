@@ -0,0 +1,3 @@
+DELETE FROM system_settings WHERE key = 'quick_crack_workflow_id';
+
+DROP TABLE IF EXISTS quick_crack_submissions;
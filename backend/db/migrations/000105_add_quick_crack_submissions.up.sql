@@ -0,0 +1,29 @@
+-- Quick crack: operators submit a handful of hashes without creating a
+-- hashlist and jobs themselves. Each submission gets a hashlist of its own and
+-- the jobs of the quick attack workflow; results are polled or sent to a webhook.
+CREATE TABLE IF NOT EXISTS quick_crack_submissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hashlist_id BIGINT NOT NULL REFERENCES hashlists(id) ON DELETE CASCADE,
+    hash_type_id INTEGER NOT NULL,
+    workflow_id UUID REFERENCES job_workflows(id) ON DELETE SET NULL,
+    job_ids UUID[] NOT NULL DEFAULT '{}',
+    webhook_url TEXT,
+    webhook_attempts INTEGER NOT NULL DEFAULT 0,
+    webhook_last_error TEXT,
+    webhook_sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_quick_crack_submissions_user ON quick_crack_submissions(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_quick_crack_submissions_webhook_pending ON quick_crack_submissions(created_at)
+    WHERE webhook_url IS NOT NULL AND webhook_sent_at IS NULL;
+
+COMMENT ON TABLE quick_crack_submissions IS 'Ad hoc hashes submitted for a quick crack attempt';
+COMMENT ON COLUMN quick_crack_submissions.job_ids IS 'Jobs created from the quick attack workflow';
+COMMENT ON COLUMN quick_crack_submissions.webhook_sent_at IS 'When the results were delivered to the webhook, or delivery was given up';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('quick_crack_workflow_id', '', 'Job workflow run against hashes submitted for a quick crack, empty disables quick crack unless a request names a workflow', 'string')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE hashes DROP COLUMN IF EXISTS password_raw;
@@ -0,0 +1,6 @@
+-- Keep the exact bytes of cracked passwords that are not valid text, such as
+-- legacy code page or $HEX[...] plains, next to their readable form
+ALTER TABLE hashes
+    ADD COLUMN IF NOT EXISTS password_raw BYTEA;
+
+COMMENT ON COLUMN hashes.password_raw IS 'Raw bytes of the cracked password when the password column cannot hold them exactly, NULL otherwise';
@@ -0,0 +1,3 @@
+ALTER TABLE agents
+    DROP COLUMN IF EXISTS workload_profile,
+    DROP COLUMN IF EXISTS workload_class;
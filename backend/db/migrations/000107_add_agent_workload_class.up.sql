@@ -0,0 +1,9 @@
+-- How an agent's machine is used: shared workstations run at a low hashcat
+-- workload profile and only inside their schedule, dedicated rigs run at a high one
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS workload_class VARCHAR(20) NOT NULL DEFAULT 'default'
+        CHECK (workload_class IN ('default', 'shared', 'dedicated')),
+    ADD COLUMN IF NOT EXISTS workload_profile SMALLINT
+        CHECK (workload_profile BETWEEN 1 AND 4);
+
+COMMENT ON COLUMN agents.workload_profile IS 'hashcat -w level within the range of the workload class, NULL for the class default';
@@ -0,0 +1,3 @@
+DELETE FROM system_settings WHERE key IN ('task_artifact_retention_days', 'task_artifact_output_kb');
+
+DROP TABLE IF EXISTS task_artifacts;
@@ -0,0 +1,24 @@
+-- Final hashcat status, exit code and output tail of each finished task run,
+-- kept for post-mortems after the agent's own logs have rotated. A task that
+-- is retried gets one row per run.
+CREATE TABLE IF NOT EXISTS task_artifacts (
+    id BIGSERIAL PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES job_tasks(id) ON DELETE CASCADE,
+    job_execution_id UUID NOT NULL REFERENCES job_executions(id) ON DELETE CASCADE,
+    agent_id INTEGER REFERENCES agents(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL,
+    exit_code INTEGER,
+    error_message TEXT,
+    final_status JSONB,
+    output_tail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_artifacts_task ON task_artifacts(task_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_task_artifacts_created ON task_artifacts(created_at);
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('task_artifact_retention_days', '30', 'Days to keep the final hashcat status and output of finished tasks (0 = keep forever)', 'integer'),
+    ('task_artifact_output_kb', '32', 'Kilobytes of hashcat output kept per finished task (0 = keep none)', 'integer')
+ON CONFLICT (key) DO NOTHING;
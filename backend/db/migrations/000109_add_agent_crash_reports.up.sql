@@ -0,0 +1,19 @@
+-- Diagnostic bundles agents send after they panicked or hashcat crashed,
+-- kept until an administrator has reviewed them. report_id is the agent's own
+-- ID of the bundle, so a bundle sent twice is stored once.
+CREATE TABLE IF NOT EXISTS agent_crash_reports (
+    id BIGSERIAL PRIMARY KEY,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    report_id VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    task_id UUID,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    bundle JSONB NOT NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (agent_id, report_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_crash_reports_unreviewed ON agent_crash_reports(occurred_at DESC) WHERE reviewed_at IS NULL;
@@ -0,0 +1,7 @@
+DELETE FROM system_settings WHERE key = 'hashlist_fully_cracked_action';
+
+UPDATE job_executions SET status = 'cancelled' WHERE status = 'superseded';
+
+ALTER TABLE job_executions DROP CONSTRAINT IF EXISTS valid_status;
+ALTER TABLE job_executions ADD CONSTRAINT valid_status
+CHECK (status IN ('pending', 'running', 'paused', 'completed', 'failed', 'cancelled', 'interrupted'));
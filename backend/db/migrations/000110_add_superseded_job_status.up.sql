@@ -0,0 +1,9 @@
+-- Jobs that never started on a hashlist that became fully cracked are marked
+-- superseded instead of being deleted, so their owners can see what happened
+ALTER TABLE job_executions DROP CONSTRAINT IF EXISTS valid_status;
+ALTER TABLE job_executions ADD CONSTRAINT valid_status
+CHECK (status IN ('pending', 'running', 'paused', 'completed', 'failed', 'cancelled', 'interrupted', 'superseded'));
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES ('hashlist_fully_cracked_action', 'supersede', 'What happens to the jobs of a hashlist once every hash is cracked: supersede (complete started jobs, mark queued jobs superseded), delete (complete started jobs, delete queued jobs) or none (leave the jobs alone)', 'string')
+ON CONFLICT (key) DO NOTHING;
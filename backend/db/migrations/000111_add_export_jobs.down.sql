@@ -0,0 +1,3 @@
+DELETE FROM system_settings WHERE key IN ('export_retention_hours', 'export_link_minutes');
+
+DROP TABLE IF EXISTS export_jobs;
@@ -0,0 +1,35 @@
+-- Exports too large to build within an HTTP request. A job is created, built
+-- in the background while its progress is polled, and its artifact is then
+-- downloaded through an expiring signed link until it is deleted.
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    processed BIGINT NOT NULL DEFAULT 0,
+    total BIGINT,
+    file_name VARCHAR(255),
+    file_path TEXT,
+    file_size BIGINT,
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT valid_export_status CHECK (status IN ('pending', 'running', 'completed', 'failed', 'expired'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_jobs_user ON export_jobs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_expires ON export_jobs(expires_at) WHERE status = 'completed';
+
+COMMENT ON TABLE export_jobs IS 'Exports built in the background and downloaded through signed links';
+COMMENT ON COLUMN export_jobs.processed IS 'Records written so far';
+COMMENT ON COLUMN export_jobs.total IS 'Records the export will contain, when known';
+COMMENT ON COLUMN export_jobs.expires_at IS 'When the artifact is deleted';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('export_retention_hours', '24', 'Hours a finished export is kept for download before it is deleted', 'integer'),
+    ('export_link_minutes', '60', 'Minutes a signed export download link stays valid', 'integer')
+ON CONFLICT (key) DO NOTHING;
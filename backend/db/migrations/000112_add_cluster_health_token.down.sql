@@ -0,0 +1 @@
+DELETE FROM system_settings WHERE key = 'cluster_health_token';
@@ -0,0 +1,6 @@
+-- Token external monitoring authenticates with to scrape the cluster health
+-- summary at /api/cluster/health
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('cluster_health_token', '', 'Allow monitoring systems to read /api/cluster/health with this bearer token, empty disables unauthenticated scraping', 'string')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,9 @@
+DELETE FROM client_settings WHERE key = 'hashlist_staging_expiry_hours';
+DELETE FROM system_settings WHERE key = 'require_hashlist_staging';
+
+DROP TABLE IF EXISTS hashlist_staging_previews;
+
+DELETE FROM hashlists WHERE status = 'staged';
+ALTER TABLE hashlists DROP CONSTRAINT IF EXISTS hashlists_status_check;
+ALTER TABLE hashlists ADD CONSTRAINT hashlists_status_check
+    CHECK (status IN ('uploading', 'processing', 'ready', 'ready_with_errors', 'error'));
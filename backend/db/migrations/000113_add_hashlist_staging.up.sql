@@ -0,0 +1,20 @@
+-- Uploads can be staged: the file is stored and parsed for a preview, but its
+-- hashes are only loaded once the user commits the hashlist
+ALTER TABLE hashlists DROP CONSTRAINT IF EXISTS hashlists_status_check;
+ALTER TABLE hashlists ADD CONSTRAINT hashlists_status_check
+    CHECK (status IN ('uploading', 'staged', 'processing', 'ready', 'ready_with_errors', 'error'));
+
+-- Parse statistics of a staged upload, removed when it is committed
+CREATE TABLE IF NOT EXISTS hashlist_staging_previews (
+    hashlist_id BIGINT PRIMARY KEY REFERENCES hashlists(id) ON DELETE CASCADE,
+    preview JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES ('require_hashlist_staging', 'false', 'Stage every hashlist upload for review before its hashes are loaded, also for API clients that do not ask for staging', 'boolean')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO client_settings (key, value, description)
+VALUES ('hashlist_staging_expiry_hours', '72', 'Hours a staged hashlist upload waits for review before it is discarded. 0 keeps staged uploads until they are committed or discarded.')
+ON CONFLICT (key) DO NOTHING;
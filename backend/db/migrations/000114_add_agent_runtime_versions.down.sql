@@ -0,0 +1,2 @@
+ALTER TABLE agents DROP COLUMN IF EXISTS binary_version;
+ALTER TABLE agents DROP COLUMN IF EXISTS driver_version;
@@ -0,0 +1,4 @@
+-- GPU driver and hashcat binary versions last reported by each agent. A change
+-- invalidates the agent's benchmarks so it is re-benchmarked before new work.
+ALTER TABLE agents ADD COLUMN IF NOT EXISTS driver_version TEXT;
+ALTER TABLE agents ADD COLUMN IF NOT EXISTS binary_version TEXT;
@@ -0,0 +1 @@
+ALTER TABLE preset_jobs DROP COLUMN IF EXISTS needs_review;
@@ -0,0 +1,3 @@
+-- Set when a wordlist or rule a preset job used was force-deleted and removed
+-- from the preset. Cleared when the preset is next saved.
+ALTER TABLE preset_jobs ADD COLUMN IF NOT EXISTS needs_review BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_job_executions_workflow_run_id;
+
+ALTER TABLE job_executions
+    DROP COLUMN IF EXISTS workflow_step,
+    DROP COLUMN IF EXISTS workflow_run_id;
+
+ALTER TABLE job_workflows
+    DROP COLUMN IF EXISTS priority_boost,
+    DROP COLUMN IF EXISTS inherit_priority;
@@ -0,0 +1,16 @@
+-- Follow-up steps of a workflow inherit the priority and max agents of the step before them
+ALTER TABLE job_workflows
+    ADD COLUMN IF NOT EXISTS inherit_priority BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN IF NOT EXISTS priority_boost INTEGER NOT NULL DEFAULT 0;
+
+-- Jobs created together from one workflow share a run ID so the scheduler treats them as a chain
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS workflow_run_id UUID,
+    ADD COLUMN IF NOT EXISTS workflow_step INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_job_executions_workflow_run_id ON job_executions(workflow_run_id) WHERE workflow_run_id IS NOT NULL;
+
+COMMENT ON COLUMN job_workflows.inherit_priority IS 'Whether each follow-up step inherits the priority and max agents of the step before it';
+COMMENT ON COLUMN job_workflows.priority_boost IS 'Added to the inherited priority of each follow-up step, capped at max_job_priority';
+COMMENT ON COLUMN job_executions.workflow_run_id IS 'Shared by the jobs created from one workflow submission';
+COMMENT ON COLUMN job_executions.workflow_step IS 'Position of the job within its workflow run, starting at 1';
@@ -0,0 +1,3 @@
+ALTER TABLE job_executions
+    DROP COLUMN IF EXISTS excluded_agent_ids,
+    DROP COLUMN IF EXISTS pinned_agent_ids;
@@ -0,0 +1,7 @@
+-- A job pinned to agents only runs on them, a job never runs on the agents it excludes
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS pinned_agent_ids INTEGER[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS excluded_agent_ids INTEGER[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN job_executions.pinned_agent_ids IS 'Agents the job may run on, empty allows every agent';
+COMMENT ON COLUMN job_executions.excluded_agent_ids IS 'Agents the job never runs on';
@@ -0,0 +1,14 @@
+DELETE FROM system_settings WHERE key IN (
+    'cloud_burst_enabled',
+    'cloud_burst_max_instances',
+    'cloud_burst_hourly_cost',
+    'cloud_burst_monthly_budget',
+    'cloud_burst_idle_minutes',
+    'cloud_burst_registration_timeout_minutes',
+    'cloud_burst_cloud_init_template'
+);
+
+ALTER TABLE job_executions
+    DROP COLUMN IF EXISTS allow_cloud_burst;
+
+DROP TABLE IF EXISTS cloud_burst_instances;
@@ -0,0 +1,44 @@
+-- Cloud burst: when jobs that opt in have work and no agent is free, the
+-- backend can launch temporary cloud GPU instances that register as agents
+-- with a single-use claim voucher and are torn down once the queue drains.
+CREATE TABLE IF NOT EXISTS cloud_burst_instances (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    provider_instance_id VARCHAR(255),
+    claim_code VARCHAR(50) NOT NULL,
+    agent_id INTEGER REFERENCES agents(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'provisioning'
+        CHECK (status IN ('provisioning', 'running', 'terminated', 'failed')),
+    hourly_cost NUMERIC(10, 4) NOT NULL DEFAULT 0,
+    error_message TEXT,
+    launched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    registered_at TIMESTAMP WITH TIME ZONE,
+    last_busy_at TIMESTAMP WITH TIME ZONE,
+    terminated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_cloud_burst_instances_status ON cloud_burst_instances(status);
+
+COMMENT ON TABLE cloud_burst_instances IS 'Temporary cloud agents launched while opted-in jobs had work and no agent was free';
+COMMENT ON COLUMN cloud_burst_instances.provider_instance_id IS 'Instance ID printed by the launch command, passed to the terminate command';
+COMMENT ON COLUMN cloud_burst_instances.hourly_cost IS 'Value of cloud_burst_hourly_cost when the instance was launched, used for the monthly budget';
+COMMENT ON COLUMN cloud_burst_instances.last_busy_at IS 'Last time the instance''s agent had a task, idle instances are terminated';
+
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS allow_cloud_burst BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN job_executions.allow_cloud_burst IS 'Whether the job may launch and run on cloud burst agents';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('cloud_burst_enabled', 'false', 'Launch temporary cloud agents for opted-in jobs when no agent is free, needs KH_CLOUD_BURST_LAUNCH_COMMAND', 'boolean'),
+    ('cloud_burst_max_instances', '2', 'Maximum number of cloud burst instances running at once', 'integer'),
+    ('cloud_burst_hourly_cost', '0', 'Cost of one cloud burst instance per hour, used for the monthly budget', 'float'),
+    ('cloud_burst_monthly_budget', '0', 'Maximum cloud burst spend per calendar month, running instances are terminated when it is reached (0 = no cap)', 'float'),
+    ('cloud_burst_idle_minutes', '10', 'Terminate a cloud burst instance once its agent has been idle this long and no opted-in job has work', 'integer'),
+    ('cloud_burst_registration_timeout_minutes', '20', 'Terminate a cloud burst instance whose agent has not registered within this many minutes', 'integer'),
+    ('cloud_burst_cloud_init_template', '#cloud-config
+runcmd:
+  - [/opt/krakenhashes/krakenhashes-agent, --host, "krakenhashes.example.com:31337", --claim, "{{.ClaimCode}}"]
+', 'Cloud-init user data passed to the launch command, {{.ClaimCode}} and {{.Name}} are filled in per instance', 'string')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,14 @@
+DELETE FROM system_settings WHERE key IN (
+    'agent_performance_window_tasks',
+    'agent_performance_min_tasks',
+    'agent_performance_threshold_percent'
+);
+
+ALTER TABLE agent_benchmarks
+    DROP COLUMN IF EXISTS device_speeds,
+    DROP COLUMN IF EXISTS measured_speed;
+
+ALTER TABLE job_tasks
+    DROP COLUMN IF EXISTS expected_speed,
+    DROP COLUMN IF EXISTS speed_samples,
+    DROP COLUMN IF EXISTS observed_speed;
@@ -0,0 +1,29 @@
+-- Effective speed tracking: every task keeps the mean hash rate its progress
+-- updates reported next to the benchmark speed it was expected to run at, so
+-- agents running persistently below their benchmarks can be flagged.
+ALTER TABLE job_tasks
+    ADD COLUMN IF NOT EXISTS observed_speed BIGINT,
+    ADD COLUMN IF NOT EXISTS speed_samples INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS expected_speed BIGINT;
+
+COMMENT ON COLUMN job_tasks.observed_speed IS 'Mean hash rate of the task''s progress updates, in H/s';
+COMMENT ON COLUMN job_tasks.speed_samples IS 'Number of progress updates observed_speed averages';
+COMMENT ON COLUMN job_tasks.expected_speed IS 'Benchmark speed of the agent for the job''s attack mode and hash type when the task started running';
+
+-- speed follows the average speed of completed tasks, measured_speed and
+-- device_speeds only change when the agent runs a benchmark
+ALTER TABLE agent_benchmarks
+    ADD COLUMN IF NOT EXISTS measured_speed BIGINT,
+    ADD COLUMN IF NOT EXISTS device_speeds JSONB NOT NULL DEFAULT '{}';
+
+UPDATE agent_benchmarks SET measured_speed = speed WHERE measured_speed IS NULL;
+
+COMMENT ON COLUMN agent_benchmarks.measured_speed IS 'Speed reported by the last benchmark run, unlike speed not updated from task averages';
+COMMENT ON COLUMN agent_benchmarks.device_speeds IS 'Per-device speeds of the last benchmark run, keyed by hashcat device ID';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('agent_performance_window_tasks', '20', 'Number of recent completed tasks an agent''s performance score averages over', 'integer'),
+    ('agent_performance_min_tasks', '5', 'Completed tasks needed before an agent or device can be flagged as underperforming', 'integer'),
+    ('agent_performance_threshold_percent', '80', 'Flag agents and devices whose recent tasks ran below this percentage of their benchmark speed', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,11 @@
+DELETE FROM system_settings WHERE key = 'max_hashes_per_hashlist';
+
+DROP INDEX IF EXISTS idx_job_executions_split_group_id;
+ALTER TABLE job_executions
+    DROP COLUMN IF EXISTS split_group_id;
+
+DELETE FROM hashlists WHERE parent_hashlist_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_hashlists_parent_hashlist_id;
+ALTER TABLE hashlists
+    DROP COLUMN IF EXISTS part_number,
+    DROP COLUMN IF EXISTS parent_hashlist_id;
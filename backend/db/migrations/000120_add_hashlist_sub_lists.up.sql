@@ -0,0 +1,25 @@
+-- Hashlists above max_hashes_per_hashlist are split into linked sub-lists so
+-- hashcat never loads more hashes than agents can hold. The parent keeps all
+-- hashes for reports; each sub-list links a share of them and is what jobs run
+-- against.
+ALTER TABLE hashlists
+    ADD COLUMN IF NOT EXISTS parent_hashlist_id BIGINT REFERENCES hashlists(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS part_number INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_hashlists_parent_hashlist_id ON hashlists(parent_hashlist_id) WHERE parent_hashlist_id IS NOT NULL;
+
+COMMENT ON COLUMN hashlists.parent_hashlist_id IS 'Hashlist this sub-list was split from';
+COMMENT ON COLUMN hashlists.part_number IS 'Position of the sub-list among the sub-lists of its parent, starting at 1';
+
+-- The jobs created for one attack against the sub-lists of a hashlist
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS split_group_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_job_executions_split_group_id ON job_executions(split_group_id) WHERE split_group_id IS NOT NULL;
+
+COMMENT ON COLUMN job_executions.split_group_id IS 'Shared by the jobs running one attack against the sub-lists of a split hashlist';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('max_hashes_per_hashlist', '0', 'Hashlists with more unique hashes are split into sub-lists of at most this many hashes (0 = no limit)', 'integer')
+ON CONFLICT (key) DO NOTHING;
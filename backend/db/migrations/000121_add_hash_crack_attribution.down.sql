@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_hashes_cracked_by_task_id;
+DROP INDEX IF EXISTS idx_hashes_cracked_at;
+
+ALTER TABLE hashes
+    DROP COLUMN IF EXISTS cracked_by_task_id,
+    DROP COLUMN IF EXISTS cracked_at;
@@ -0,0 +1,15 @@
+-- Crack attribution: every hash records when it was cracked and by which task,
+-- so clients can fetch only the cracks since their last poll along with the
+-- attack that found them. last_updated cannot serve as the cursor since any
+-- update to the row moves it.
+ALTER TABLE hashes
+    ADD COLUMN IF NOT EXISTS cracked_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS cracked_by_task_id UUID REFERENCES job_tasks(id) ON DELETE SET NULL;
+
+UPDATE hashes SET cracked_at = last_updated WHERE is_cracked = true AND cracked_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_hashes_cracked_at ON hashes(cracked_at, id) WHERE cracked_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_hashes_cracked_by_task_id ON hashes(cracked_by_task_id) WHERE cracked_by_task_id IS NOT NULL;
+
+COMMENT ON COLUMN hashes.cracked_at IS 'When the hash was cracked, set once and not moved by later updates';
+COMMENT ON COLUMN hashes.cracked_by_task_id IS 'Task whose agent reported the crack, NULL for cracks found at upload or when the task was deleted';
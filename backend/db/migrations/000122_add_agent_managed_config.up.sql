@@ -0,0 +1,7 @@
+-- Server-managed agent settings: per-agent overrides of settings the agent
+-- used to read from its .env, pushed to the agent in its config_update message
+-- when it connects and whenever they change.
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS managed_config JSONB NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN agents.managed_config IS 'Agent settings managed from the backend (heartbeat_interval_seconds, max_concurrent_downloads, file_retention_days); absent keys use the global default or the agent''s own setting';
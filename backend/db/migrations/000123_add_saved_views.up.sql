@@ -0,0 +1,21 @@
+-- Saved views: named filter and sort settings for the job, task and hash
+-- lists, kept private or shared with a team so recurring triage queries do
+-- not have to be rebuilt. The query holds the list endpoint's query
+-- parameters and is expanded by passing ?view=<id> to the endpoint.
+CREATE TABLE IF NOT EXISTS saved_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    team_id UUID REFERENCES teams(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    resource VARCHAR(20) NOT NULL CHECK (resource IN ('jobs', 'tasks', 'hashes')),
+    query JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT saved_views_user_resource_name_unique UNIQUE (user_id, resource, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_views_team_id ON saved_views(team_id) WHERE team_id IS NOT NULL;
+
+COMMENT ON COLUMN saved_views.team_id IS 'Team the view is shared with, NULL for a view private to its owner';
+COMMENT ON COLUMN saved_views.query IS 'Query parameters of the list endpoint, e.g. {"status": "failed", "since": "7d"}';
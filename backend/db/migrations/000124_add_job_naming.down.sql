@@ -0,0 +1,5 @@
+DELETE FROM system_settings WHERE key = 'job_name_template';
+
+DROP INDEX IF EXISTS idx_job_executions_lower_name;
+
+DROP TABLE IF EXISTS job_name_sequences;
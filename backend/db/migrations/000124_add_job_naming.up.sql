@@ -0,0 +1,16 @@
+-- Deterministic job naming: generated names follow the job_name_template
+-- setting, with {seq} counting the jobs of each client, and job names are
+-- unique within a client so logs, reports and exports can refer to them.
+CREATE TABLE IF NOT EXISTS job_name_sequences (
+    scope TEXT PRIMARY KEY,
+    last_value INTEGER NOT NULL DEFAULT 0
+);
+
+COMMENT ON TABLE job_name_sequences IS 'Last {seq} of generated job names, per client ID or "none" for hashlists without a client';
+
+CREATE INDEX IF NOT EXISTS idx_job_executions_lower_name ON job_executions(LOWER(name));
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('job_name_template', '{client}-{hashlist}-{attack}-{seq}', 'Template of generated job names, using {client}, {hashlist}, {attack}, {hash_type}, {seq} and {date}', 'string')
+ON CONFLICT (key) DO NOTHING;
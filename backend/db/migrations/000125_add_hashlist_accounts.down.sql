@@ -0,0 +1,2 @@
+DELETE FROM system_settings WHERE key = 'privileged_groups';
+DROP TABLE IF EXISTS hashlist_accounts;
@@ -0,0 +1,25 @@
+-- Account metadata of a hashlist (group memberships from the ingest source,
+-- e.g. an NTDS dump), used to flag cracked privileged accounts
+CREATE TABLE IF NOT EXISTS hashlist_accounts (
+    id BIGSERIAL PRIMARY KEY,
+    hashlist_id BIGINT NOT NULL REFERENCES hashlists(id) ON DELETE CASCADE,
+    username TEXT NOT NULL,
+    domain TEXT NOT NULL DEFAULT '',
+    groups TEXT[] NOT NULL DEFAULT '{}',
+    privileged BOOLEAN NOT NULL DEFAULT FALSE,
+    cracked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE hashlist_accounts IS 'Group memberships of the accounts of a hashlist, given at ingest';
+COMMENT ON COLUMN hashlist_accounts.cracked_at IS 'When a cracked privileged account was flagged, NULL until then';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_hashlist_accounts_account
+    ON hashlist_accounts(hashlist_id, LOWER(domain), LOWER(username));
+CREATE INDEX IF NOT EXISTS idx_hashlist_accounts_unflagged
+    ON hashlist_accounts(hashlist_id) WHERE privileged AND cracked_at IS NULL;
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('privileged_groups', 'Domain Admins,Enterprise Admins,Schema Admins,Administrators,Account Operators,Backup Operators,Server Operators,Print Operators', 'Comma-separated groups whose members are privileged accounts, alerted on when cracked', 'string')
+ON CONFLICT (key) DO NOTHING;
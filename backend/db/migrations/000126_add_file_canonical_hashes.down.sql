@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS rule_aliases;
+DROP TABLE IF EXISTS wordlist_aliases;
+DROP INDEX IF EXISTS idx_rules_canonical_hash;
+DROP INDEX IF EXISTS idx_wordlists_canonical_hash;
+ALTER TABLE rules DROP COLUMN IF EXISTS canonical_hash;
+ALTER TABLE wordlists DROP COLUMN IF EXISTS canonical_hash;
@@ -0,0 +1,28 @@
+-- Canonical hashes of wordlists and rules (MD5 of the content without a BOM,
+-- with LF line endings), used to detect re-uploads of a file that only
+-- differ in encoding details
+ALTER TABLE wordlists ADD COLUMN IF NOT EXISTS canonical_hash VARCHAR(32);
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS canonical_hash VARCHAR(32);
+
+CREATE INDEX IF NOT EXISTS idx_wordlists_canonical_hash ON wordlists(canonical_hash) WHERE canonical_hash IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_rules_canonical_hash ON rules(canonical_hash) WHERE canonical_hash IS NOT NULL;
+
+-- Names a duplicate upload was stored under, pointing at the existing file
+CREATE TABLE IF NOT EXISTS wordlist_aliases (
+    id SERIAL PRIMARY KEY,
+    wordlist_id INTEGER NOT NULL REFERENCES wordlists(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_by UUID NOT NULL REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS rule_aliases (
+    id SERIAL PRIMARY KEY,
+    rule_id INTEGER NOT NULL REFERENCES rules(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_by UUID NOT NULL REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wordlist_aliases_unique ON wordlist_aliases(wordlist_id, alias);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rule_aliases_unique ON rule_aliases(rule_id, alias);
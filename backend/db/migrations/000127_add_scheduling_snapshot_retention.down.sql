@@ -0,0 +1 @@
+DELETE FROM system_settings WHERE key = 'scheduling_snapshot_retention_hours';
@@ -0,0 +1,6 @@
+-- Scheduling cycle snapshots are kept in memory for debugging why jobs are
+-- or are not picked up by agents
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('scheduling_snapshot_retention_hours', '6', 'Hours of scheduling cycle snapshots kept in memory for debugging job scheduling', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,15 @@
+-- In-flight tasks recorded when the backend shuts down gracefully, so the
+-- next start keeps them running while their agents reconnect instead of
+-- marking every task reconnect_pending
+CREATE TABLE IF NOT EXISTS server_handovers (
+    id BIGSERIAL PRIMARY KEY,
+    drained_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reconnect_after_seconds INTEGER NOT NULL,
+    tasks JSONB NOT NULL DEFAULT '[]',
+    resumed_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMENT ON TABLE server_handovers IS 'Tasks in flight when the backend was drained for a restart';
+COMMENT ON COLUMN server_handovers.resumed_at IS 'When a backend start picked up the handover, NULL until then';
+
+CREATE INDEX IF NOT EXISTS idx_server_handovers_pending ON server_handovers(drained_at) WHERE resumed_at IS NULL;
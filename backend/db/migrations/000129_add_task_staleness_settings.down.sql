@@ -0,0 +1,5 @@
+DELETE FROM system_settings WHERE key IN (
+    'slow_hash_task_heartbeat_timeout_minutes',
+    'task_heartbeat_max_grace_doublings',
+    'agent_heartbeat_timeout_seconds'
+);
@@ -0,0 +1,9 @@
+-- Tune when running tasks are reclaimed: a longer timeout for slow hash types,
+-- extra grace for agents that are slow to report status, and the heartbeat
+-- timeout after which an agent is marked inactive
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('slow_hash_task_heartbeat_timeout_minutes', '15', 'Timeout in minutes for tasks of slow hash types without progress before they are reset to pending', 'integer'),
+    ('task_heartbeat_max_grace_doublings', '3', 'How many times the task timeout is doubled for agents that recently had a task go quiet while still connected (0 disables)', 'integer'),
+    ('agent_heartbeat_timeout_seconds', '90', 'Seconds without a heartbeat after which an agent is marked inactive', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,2 @@
+ALTER TABLE job_executions DROP COLUMN IF EXISTS mask_increment;
+ALTER TABLE preset_jobs DROP COLUMN IF EXISTS mask_increment;
@@ -0,0 +1,4 @@
+-- The --increment bounds of a mask attack, and for jobs the hashcat keyspace
+-- of each mask length run. NULL runs the mask at its full length only.
+ALTER TABLE preset_jobs ADD COLUMN IF NOT EXISTS mask_increment JSONB;
+ALTER TABLE job_executions ADD COLUMN IF NOT EXISTS mask_increment JSONB;
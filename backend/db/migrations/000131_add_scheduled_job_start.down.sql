@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_job_executions_scheduled;
+
+UPDATE job_executions SET status = 'pending' WHERE status = 'scheduled';
+
+ALTER TABLE job_executions
+    DROP COLUMN IF EXISTS depends_on_job_id,
+    DROP COLUMN IF EXISTS start_at;
+
+ALTER TABLE job_executions DROP CONSTRAINT IF EXISTS valid_status;
+ALTER TABLE job_executions ADD CONSTRAINT valid_status
+CHECK (status IN ('pending', 'running', 'paused', 'completed', 'failed', 'cancelled', 'interrupted', 'superseded'));
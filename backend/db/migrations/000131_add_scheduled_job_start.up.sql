@@ -0,0 +1,11 @@
+-- Jobs created with a start time or a dependency on another job wait in the
+-- scheduled status until the scheduler releases them
+ALTER TABLE job_executions DROP CONSTRAINT IF EXISTS valid_status;
+ALTER TABLE job_executions ADD CONSTRAINT valid_status
+CHECK (status IN ('pending', 'running', 'paused', 'completed', 'failed', 'cancelled', 'interrupted', 'superseded', 'scheduled'));
+
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS start_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS depends_on_job_id UUID REFERENCES job_executions(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_job_executions_scheduled ON job_executions(status) WHERE status = 'scheduled';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_users_notification_digest;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS valid_notification_digest;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS last_digest_sent_at,
+    DROP COLUMN IF EXISTS notification_digest;
+
+-- Postgres cannot drop an enum value, 'notification_digest' stays in
+-- email_template_type unused
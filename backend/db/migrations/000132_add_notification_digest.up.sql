@@ -0,0 +1,13 @@
+-- Users can receive an hourly or daily digest email instead of one email per
+-- completed job. last_digest_sent_at is where the next digest starts.
+ALTER TYPE email_template_type ADD VALUE IF NOT EXISTS 'notification_digest';
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS notification_digest VARCHAR(10) NOT NULL DEFAULT 'off',
+    ADD COLUMN IF NOT EXISTS last_digest_sent_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS valid_notification_digest;
+ALTER TABLE users ADD CONSTRAINT valid_notification_digest
+CHECK (notification_digest IN ('off', 'hourly', 'daily'));
+
+CREATE INDEX IF NOT EXISTS idx_users_notification_digest ON users(last_digest_sent_at) WHERE notification_digest <> 'off';
@@ -0,0 +1 @@
+DELETE FROM email_templates WHERE template_type = 'notification_digest';
@@ -0,0 +1,68 @@
+-- Email template of the notification digest. It is added apart from the
+-- email_template_type value in migration 132, a new enum value can only be
+-- used once the migration adding it has committed. The sections are rendered
+-- by the backend as plain text lines.
+INSERT INTO email_templates (template_type, name, subject, html_content, text_content, created_at, updated_at)
+SELECT 'notification_digest', 'Notification Digest', 'KrakenHashes {{ .Period }} digest: {{ .JobCount }} jobs, {{ .CrackCount }} cracks',
+    '<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        .header {
+            background-color: #000000;
+            padding: 20px;
+            text-align: center;
+            width: 100%;
+        }
+        .header h1 {
+            color: #FF0000;
+            font-family: Arial, sans-serif;
+            margin: 0;
+        }
+        .content {
+            padding: 20px;
+            font-family: Arial, sans-serif;
+        }
+        .stats {
+            background-color: #f5f5f5;
+            padding: 15px;
+            border-radius: 5px;
+            margin: 15px 0;
+            white-space: pre-line;
+        }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <h1>KrakenHashes</h1>
+    </div>
+    <div class="content">
+        <h2>Your {{ .Period }} Digest</h2>
+        <p>Activity from {{ .Since }} to {{ .Until }}.</p>
+        <h3>Completed Jobs ({{ .JobCount }})</h3>
+        <div class="stats">{{ .JobsSummary }}</div>
+        <h3>New Cracks ({{ .CrackCount }})</h3>
+        <div class="stats">{{ .CracksSummary }}</div>
+        <h3>Agent Incidents ({{ .IncidentCount }})</h3>
+        <div class="stats">{{ .IncidentsSummary }}</div>
+        <p>View detailed results in your dashboard.</p>
+    </div>
+</body>
+</html>',
+    'KRAKENHASHES {{ .Period }} DIGEST
+
+Activity from {{ .Since }} to {{ .Until }}.
+
+Completed Jobs ({{ .JobCount }}):
+{{ .JobsSummary }}
+
+New Cracks ({{ .CrackCount }}):
+{{ .CracksSummary }}
+
+Agent Incidents ({{ .IncidentCount }}):
+{{ .IncidentsSummary }}
+
+View detailed results in your dashboard.',
+    NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM email_templates WHERE template_type = 'notification_digest');
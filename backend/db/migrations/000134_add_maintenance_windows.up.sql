@@ -0,0 +1,21 @@
+-- Maintenance windows, the audit trail of system-wide maintenance mode. While
+-- a window is open no new tasks are dispatched and no jobs can be created;
+-- running chunks finish, or are stopped once the drain timeout has passed.
+-- Usernames are copied so the audit trail survives deleting the admins.
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id BIGSERIAL PRIMARY KEY,
+    message TEXT NOT NULL,
+    drain_timeout_seconds INTEGER NOT NULL DEFAULT 0 CHECK (drain_timeout_seconds >= 0),
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    started_by_username VARCHAR(255) NOT NULL,
+    force_stopped_at TIMESTAMP WITH TIME ZONE,
+    stopped_tasks INTEGER NOT NULL DEFAULT 0,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    ended_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ended_by_username VARCHAR(255)
+);
+
+-- At most one window is open at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_maintenance_windows_open ON maintenance_windows ((ended_at IS NULL)) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_started_at ON maintenance_windows (started_at DESC);
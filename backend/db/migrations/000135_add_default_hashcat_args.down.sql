@@ -0,0 +1 @@
+DELETE FROM system_settings WHERE key = 'default_hashcat_args';
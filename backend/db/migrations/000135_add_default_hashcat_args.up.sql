@@ -0,0 +1,7 @@
+-- Default hashcat arguments added to every job matching a rule's attack mode,
+-- hash type class and rule count, as a JSON array. The job's own arguments
+-- are merged over them.
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('default_hashcat_args', '[]', 'Rules of default hashcat arguments per attack mode and hash type class, as JSON: [{"attack_mode": 0, "hash_class": "fast", "min_rules": 0, "args": "-O"}]', 'string')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,10 @@
+DELETE FROM system_settings WHERE key IN (
+    'chunk_verification_enabled',
+    'chunk_verification_sample_percent'
+);
+
+DROP TABLE IF EXISTS chunk_verifications;
+
+DROP INDEX IF EXISTS idx_job_tasks_verification_of;
+
+ALTER TABLE job_tasks DROP COLUMN IF EXISTS verification_of;
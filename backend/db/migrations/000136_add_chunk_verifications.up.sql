@@ -0,0 +1,34 @@
+-- A verification task re-runs the keyspace of a completed task on another
+-- agent to check that the original agent really did the work.
+ALTER TABLE job_tasks
+    ADD COLUMN IF NOT EXISTS verification_of UUID REFERENCES job_tasks(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_job_tasks_verification_of ON job_tasks(verification_of) WHERE verification_of IS NOT NULL;
+
+-- One row per sampled chunk. A mismatch means the verifier recovered hashes
+-- in a keyspace the original agent reported as fully processed.
+CREATE TABLE IF NOT EXISTS chunk_verifications (
+    id BIGSERIAL PRIMARY KEY,
+    task_id UUID NOT NULL UNIQUE REFERENCES job_tasks(id) ON DELETE CASCADE,
+    job_execution_id UUID NOT NULL REFERENCES job_executions(id) ON DELETE CASCADE,
+    agent_id INTEGER REFERENCES agents(id) ON DELETE SET NULL,
+    agent_name VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'passed', 'mismatch', 'skipped')),
+    verifier_task_id UUID REFERENCES job_tasks(id) ON DELETE SET NULL,
+    verifier_agent_id INTEGER REFERENCES agents(id) ON DELETE SET NULL,
+    original_crack_count INTEGER,
+    verifier_crack_count INTEGER,
+    agent_quarantined BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_chunk_verifications_status ON chunk_verifications(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_chunk_verifications_agent ON chunk_verifications(agent_id);
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('chunk_verification_enabled', 'false', 'Re-run a random sample of completed chunks on another agent to detect agents skipping work', 'boolean'),
+    ('chunk_verification_sample_percent', '2', 'Percentage of completed chunks re-run for verification', 'float')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,3 @@
+ALTER TABLE job_tasks DROP COLUMN IF EXISTS binary_attestation;
+
+ALTER TABLE binary_versions DROP COLUMN IF EXISTS executable_hashes;
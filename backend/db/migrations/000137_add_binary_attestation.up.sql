@@ -0,0 +1,8 @@
+-- SHA-256 hashes of the hashcat executables in each binary archive, by file
+-- name, that agents check their local copy against before every task
+ALTER TABLE binary_versions
+    ADD COLUMN IF NOT EXISTS executable_hashes JSONB NOT NULL DEFAULT '{}';
+
+-- What the agent found when it checked its hashcat binary for the task
+ALTER TABLE job_tasks
+    ADD COLUMN IF NOT EXISTS binary_attestation JSONB;
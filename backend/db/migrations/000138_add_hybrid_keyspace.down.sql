@@ -0,0 +1,3 @@
+ALTER TABLE job_executions
+    DROP COLUMN IF EXISTS hybrid_mask_keyspace,
+    DROP COLUMN IF EXISTS hybrid_wordlist_keyspace;
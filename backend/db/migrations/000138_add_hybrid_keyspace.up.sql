@@ -0,0 +1,5 @@
+-- Breakdown of a hybrid attack's (-a 6/7) keyspace into its wordlist and
+-- mask sides, hashcat's own keyspace only counts one of them
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS hybrid_wordlist_keyspace BIGINT,
+    ADD COLUMN IF NOT EXISTS hybrid_mask_keyspace BIGINT;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_job_executions_campaign_id;
+ALTER TABLE job_executions
+    DROP COLUMN IF EXISTS campaign_id;
+
+DROP TABLE IF EXISTS job_campaigns;
@@ -0,0 +1,18 @@
+-- A campaign is one job submission run against several hashlists of the same
+-- hash type. Each hashlist gets its own job executions, the campaign ties
+-- them together for a combined view of progress and results.
+CREATE TABLE IF NOT EXISTS job_campaigns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    hash_type_id INTEGER NOT NULL REFERENCES hash_types(id),
+    hashlist_ids BIGINT[] NOT NULL DEFAULT '{}',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS campaign_id UUID REFERENCES job_campaigns(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_job_executions_campaign_id ON job_executions(campaign_id) WHERE campaign_id IS NOT NULL;
+
+COMMENT ON COLUMN job_executions.campaign_id IS 'Campaign the job was created for, one job submission run against several hashlists';
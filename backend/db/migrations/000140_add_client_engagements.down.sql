@@ -0,0 +1,7 @@
+ALTER TABLE clients
+    DROP COLUMN IF EXISTS closed_at,
+    DROP COLUMN IF EXISTS scope_notes,
+    DROP COLUMN IF EXISTS engagement_end_date,
+    DROP COLUMN IF EXISTS engagement_start_date,
+    DROP COLUMN IF EXISTS engagement_status,
+    DROP COLUMN IF EXISTS contacts;
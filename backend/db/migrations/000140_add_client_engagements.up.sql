@@ -0,0 +1,14 @@
+-- Engagement metadata of a client. A closed engagement blocks new jobs
+-- against the client's hashlists, and its hashlists are retained for the
+-- retention period after the engagement closed.
+ALTER TABLE clients
+    ADD COLUMN IF NOT EXISTS contacts JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS engagement_status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (engagement_status IN ('active', 'closed')),
+    ADD COLUMN IF NOT EXISTS engagement_start_date DATE,
+    ADD COLUMN IF NOT EXISTS engagement_end_date DATE,
+    ADD COLUMN IF NOT EXISTS scope_notes TEXT,
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN clients.contacts IS 'Contacts of the client: name, role, email, phone and whether they are the primary contact';
+COMMENT ON COLUMN clients.closed_at IS 'When the engagement was closed, NULL while it is active';
@@ -0,0 +1,3 @@
+ALTER TABLE preset_jobs DROP COLUMN IF EXISTS wordlist_collection_id;
+
+DROP TABLE IF EXISTS wordlist_collections;
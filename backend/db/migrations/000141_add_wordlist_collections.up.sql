@@ -0,0 +1,20 @@
+-- A wordlist collection is a named, ordered set of wordlists that preset jobs
+-- can reference instead of a single wordlist. Collections are expanded when a
+-- job is created, one job per wordlist, so editing a collection changes the
+-- jobs created from then on without touching the presets.
+CREATE TABLE IF NOT EXISTS wordlist_collections (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    wordlist_ids INTEGER[] NOT NULL DEFAULT '{}',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON COLUMN wordlist_collections.wordlist_ids IS 'Wordlists of the collection in the order their jobs are created, deleted wordlists are skipped';
+
+ALTER TABLE preset_jobs
+    ADD COLUMN IF NOT EXISTS wordlist_collection_id INTEGER REFERENCES wordlist_collections(id) ON DELETE RESTRICT;
+
+COMMENT ON COLUMN preset_jobs.wordlist_collection_id IS 'Collection expanded into one job per wordlist at job creation, used instead of wordlist_ids';
@@ -0,0 +1,7 @@
+DELETE FROM system_settings WHERE key = 'binary_rollout_check_interval_minutes';
+
+ALTER TABLE job_tasks
+    DROP COLUMN IF EXISTS binary_version_id;
+
+DROP INDEX IF EXISTS idx_binary_rollouts_canary;
+DROP TABLE IF EXISTS binary_rollouts;
@@ -0,0 +1,38 @@
+-- Staged rollout of a new binary version. Tasks of jobs using the previous
+-- version run the new one on agents carrying the canary label while the
+-- rollout observes them, it is then promoted to the default for the whole
+-- fleet or rolled back depending on how the canaries fared.
+CREATE TABLE IF NOT EXISTS binary_rollouts (
+    id SERIAL PRIMARY KEY,
+    binary_version_id INTEGER NOT NULL REFERENCES binary_versions(id) ON DELETE CASCADE,
+    previous_version_id INTEGER NOT NULL REFERENCES binary_versions(id) ON DELETE CASCADE,
+    canary_label TEXT NOT NULL,
+    observation_hours INTEGER NOT NULL CHECK (observation_hours > 0),
+    min_tasks INTEGER NOT NULL DEFAULT 10 CHECK (min_tasks > 0),
+    max_failure_rate_increase DOUBLE PRECISION NOT NULL DEFAULT 5,
+    max_speed_regression DOUBLE PRECISION NOT NULL DEFAULT 10,
+    status VARCHAR(20) NOT NULL DEFAULT 'canary'
+        CHECK (status IN ('canary', 'promoted', 'rolled_back')),
+    metrics JSONB,
+    decision_reason TEXT,
+    decided_automatically BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Only one rollout observes canaries at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_binary_rollouts_canary ON binary_rollouts((status)) WHERE status = 'canary';
+
+COMMENT ON COLUMN binary_rollouts.max_failure_rate_increase IS 'Percentage points the canary task failure rate may exceed the previous version''s before rolling back';
+COMMENT ON COLUMN binary_rollouts.max_speed_regression IS 'Percent the canaries may run slower than on the previous version before rolling back';
+COMMENT ON COLUMN binary_rollouts.metrics IS 'Comparison of the canaries with the previous version at the last evaluation';
+
+-- Binary version a task was dispatched with, differs from its job's during a rollout
+ALTER TABLE job_tasks
+    ADD COLUMN IF NOT EXISTS binary_version_id INTEGER REFERENCES binary_versions(id) ON DELETE SET NULL;
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('binary_rollout_check_interval_minutes', '15', 'How often active binary rollouts compare their canaries with the previous version', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,28 @@
+-- Operational events such as agents connecting, file syncs finishing and
+-- scheduler warnings. Unlike the audit log they record what the system did
+-- rather than what users did, so incidents can be reconstructed without the
+-- backend log files.
+CREATE TABLE IF NOT EXISTS system_events (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    severity VARCHAR(10) NOT NULL CHECK (severity IN ('info', 'warning', 'error')),
+    category VARCHAR(50) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    message TEXT NOT NULL,
+    entity_type VARCHAR(50),
+    entity_id TEXT,
+    details JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_events_occurred_at ON system_events(occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_system_events_severity ON system_events(severity, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_system_events_category ON system_events(category, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_system_events_entity ON system_events(entity_type, entity_id, occurred_at DESC);
+
+COMMENT ON COLUMN system_events.entity_type IS 'Kind of record the event is about, e.g. agent or job';
+COMMENT ON COLUMN system_events.entity_id IS 'ID of the record the event is about, as text since agents and jobs use different key types';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('system_event_retention_days', '30', 'Days operational system events are kept before being purged', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,7 @@
+-- A job created with "only remaining hashes" runs against a snapshot of the
+-- hashes of its hashlist still uncracked at creation, like hashcat --left,
+-- rather than the full hashlist file. NULL runs the full hashlist.
+ALTER TABLE job_executions
+    ADD COLUMN IF NOT EXISTS remaining_hash_count INTEGER CHECK (remaining_hash_count > 0);
+
+COMMENT ON COLUMN job_executions.remaining_hash_count IS 'Hashes in the job''s snapshot of uncracked hashes, NULL when the job runs the full hashlist';
@@ -0,0 +1 @@
+DELETE FROM system_settings WHERE key IN ('hashcat_exec_max_concurrent', 'hashcat_exec_max_queued');
@@ -0,0 +1,6 @@
+-- Bound the hashcat processes the backend runs itself, such as keyspace calculations
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('hashcat_exec_max_concurrent', '2', 'Hashcat processes the backend runs at once for keyspace calculations, further runs wait for a slot', 'integer'),
+    ('hashcat_exec_max_queued', '100', 'Hashcat runs allowed to wait for a slot before further ones are rejected', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS record_job_execution_throughput ON job_executions;
+DROP FUNCTION IF EXISTS record_job_throughput();
+DROP TABLE IF EXISTS job_throughput_history;
@@ -0,0 +1,107 @@
+-- Throughput of every completed job: candidates tried, wall time and the agent
+-- time spent on it, so turnaround can be estimated from past jobs of a hash
+-- type. Rows are maintained by a trigger on job_executions and survive job
+-- archival and deletion.
+CREATE TABLE IF NOT EXISTS job_throughput_history (
+    job_execution_id UUID PRIMARY KEY, -- No FK: the job may be archived or deleted
+    hash_type INTEGER NOT NULL,
+    attack_mode INTEGER NOT NULL,
+    hash_count INTEGER NOT NULL DEFAULT 0,
+    keyspace BIGINT NOT NULL CHECK (keyspace > 0),
+    wall_seconds BIGINT NOT NULL CHECK (wall_seconds > 0),
+    task_seconds BIGINT NOT NULL DEFAULT 0,
+    agent_count INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_throughput_history_hash_type ON job_throughput_history(hash_type, completed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_job_throughput_history_completed_at ON job_throughput_history(completed_at DESC);
+
+COMMENT ON COLUMN job_throughput_history.hash_count IS 'Hashes the job ran against, many salts slow salted hash types down';
+COMMENT ON COLUMN job_throughput_history.keyspace IS 'Candidates the job''s own tasks processed, chunks reused from other jobs left out';
+COMMENT ON COLUMN job_throughput_history.wall_seconds IS 'Seconds from the job''s start to its completion, including time waiting for agents';
+COMMENT ON COLUMN job_throughput_history.task_seconds IS 'Seconds agents spent running the job''s tasks, summed over agents';
+
+-- Record the job's throughput when it completes
+CREATE OR REPLACE FUNCTION record_job_throughput()
+RETURNS TRIGGER AS $$
+DECLARE
+    job_keyspace BIGINT;
+    job_task_seconds BIGINT;
+    job_agents INTEGER;
+    job_started TIMESTAMP WITH TIME ZONE;
+    job_completed TIMESTAMP WITH TIME ZONE;
+BEGIN
+    IF NEW.status <> 'completed' OR OLD.status = NEW.status THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT
+        COALESCE(SUM(COALESCE(NULLIF(jt.effective_keyspace_processed, 0), jt.keyspace_processed)), 0),
+        COALESCE(SUM(EXTRACT(EPOCH FROM (jt.completed_at - jt.started_at)))::BIGINT, 0),
+        COUNT(DISTINCT jt.agent_id)
+    INTO job_keyspace, job_task_seconds, job_agents
+    FROM job_tasks jt
+    WHERE jt.job_execution_id = NEW.id
+        AND jt.reused_from IS NULL
+        AND jt.completed_at >= jt.started_at;
+
+    job_started := COALESCE(NEW.started_at, NEW.created_at);
+    job_completed := COALESCE(NEW.completed_at, NOW());
+    IF job_keyspace <= 0 OR EXTRACT(EPOCH FROM (job_completed - job_started)) < 1 THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO job_throughput_history (
+        job_execution_id, hash_type, attack_mode, hash_count, keyspace,
+        wall_seconds, task_seconds, agent_count, started_at, completed_at
+    ) VALUES (
+        NEW.id,
+        COALESCE(NEW.hash_type, (SELECT hash_type_id FROM hashlists WHERE id = NEW.hashlist_id)),
+        NEW.attack_mode,
+        COALESCE(NEW.remaining_hash_count, (SELECT total_hashes FROM hashlists WHERE id = NEW.hashlist_id), 0),
+        job_keyspace,
+        EXTRACT(EPOCH FROM (job_completed - job_started))::BIGINT,
+        job_task_seconds, job_agents, job_started, job_completed
+    )
+    ON CONFLICT (job_execution_id) DO NOTHING;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_job_execution_throughput
+AFTER UPDATE OF status ON job_executions
+FOR EACH ROW
+EXECUTE FUNCTION record_job_throughput();
+
+-- Backfill from job executions that have already completed
+INSERT INTO job_throughput_history (
+    job_execution_id, hash_type, attack_mode, hash_count, keyspace,
+    wall_seconds, task_seconds, agent_count, started_at, completed_at
+)
+SELECT
+    je.id, COALESCE(je.hash_type, h.hash_type_id), je.attack_mode,
+    COALESCE(je.remaining_hash_count, h.total_hashes, 0),
+    t.keyspace,
+    EXTRACT(EPOCH FROM (je.completed_at - COALESCE(je.started_at, je.created_at)))::BIGINT,
+    t.task_seconds, t.agents,
+    COALESCE(je.started_at, je.created_at), je.completed_at
+FROM job_executions je
+JOIN hashlists h ON h.id = je.hashlist_id
+CROSS JOIN LATERAL (
+    SELECT
+        COALESCE(SUM(COALESCE(NULLIF(jt.effective_keyspace_processed, 0), jt.keyspace_processed)), 0) AS keyspace,
+        COALESCE(SUM(EXTRACT(EPOCH FROM (jt.completed_at - jt.started_at)))::BIGINT, 0) AS task_seconds,
+        COUNT(DISTINCT jt.agent_id) AS agents
+    FROM job_tasks jt
+    WHERE jt.job_execution_id = je.id
+        AND jt.reused_from IS NULL
+        AND jt.completed_at >= jt.started_at
+) t
+WHERE je.status = 'completed'
+    AND je.completed_at IS NOT NULL
+    AND t.keyspace > 0
+    AND EXTRACT(EPOCH FROM (je.completed_at - COALESCE(je.started_at, je.created_at))) >= 1
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+DELETE FROM system_settings WHERE key IN ('agent_credential_rotation_days', 'agent_credential_rotation_grace_minutes');
+
+ALTER TABLE agents
+    DROP COLUMN IF EXISTS previous_api_key_expires_at,
+    DROP COLUMN IF EXISTS previous_api_key,
+    DROP COLUMN IF EXISTS pending_api_key;
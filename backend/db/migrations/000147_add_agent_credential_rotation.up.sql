@@ -0,0 +1,18 @@
+-- Rotate agent API keys over the WebSocket without reconnecting. The backend
+-- sends a connected agent a pending key, which authenticates as soon as it is
+-- issued in case the agent's confirmation is lost. On confirmation it becomes
+-- the agent's key and the replaced key keeps working until the grace period
+-- ends, so requests already under way finish.
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS pending_api_key VARCHAR(64) UNIQUE,
+    ADD COLUMN IF NOT EXISTS previous_api_key VARCHAR(64) UNIQUE,
+    ADD COLUMN IF NOT EXISTS previous_api_key_expires_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN agents.pending_api_key IS 'API key sent to the agent by a credential rotation it has not confirmed yet';
+COMMENT ON COLUMN agents.previous_api_key IS 'API key replaced by the last credential rotation, accepted until previous_api_key_expires_at';
+
+INSERT INTO system_settings (key, value, description, data_type)
+VALUES
+    ('agent_credential_rotation_days', '0', 'Rotate the API key and certificate of connected agents whose key is older than this many days, 0 to rotate only on request', 'integer'),
+    ('agent_credential_rotation_grace_minutes', '60', 'Minutes an agent''s replaced API key keeps working after a credential rotation', 'integer')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,7 @@
+-- Notification digests are claimed with a lease instead of a row lock held
+-- while the email is sent. The claim is committed before sending and cleared
+-- afterwards; a claim left behind by a backend that stopped expires.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS digest_claimed_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN users.digest_claimed_at IS 'When a backend claimed the user''s due notification digest, NULL when none is being sent';
@@ -3,6 +3,7 @@ package binary
 import (
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -94,6 +95,55 @@ func (m *manager) AddVersion(ctx context.Context, version *BinaryVersion) error
 		return fmt.Errorf("failed to download binary after %d attempts: %w", maxDownloadAttempts, lastErr)
 	}
 
+	return m.completeVersion(ctx, version)
+}
+
+// ImportVersion implements Manager.ImportVersion
+func (m *manager) ImportVersion(ctx context.Context, version *BinaryVersion, archivePath string) error {
+	expectedHash := version.MD5Hash
+	version.VerificationStatus = VerificationStatusPending
+
+	if err := m.store.CreateVersion(ctx, version); err != nil {
+		return fmt.Errorf("failed to create version record: %w", err)
+	}
+
+	filePath := m.getBinaryPath(version)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0750); err != nil {
+		return fmt.Errorf("failed to create binary directory: %w", err)
+	}
+	if err := os.Rename(archivePath, filePath); err != nil {
+		// The archive may be on another filesystem
+		if err := m.copyRecursive(archivePath, filePath); err != nil {
+			version.VerificationStatus = VerificationStatusFailed
+			if updateErr := m.store.UpdateVersion(ctx, version); updateErr != nil {
+				debug.Error("failed to update version status for version %d: %v", version.ID, updateErr)
+			}
+			return fmt.Errorf("failed to store imported binary: %w", err)
+		}
+		os.Remove(archivePath)
+	}
+
+	if err := m.completeVersion(ctx, version); err != nil {
+		return err
+	}
+	if expectedHash != "" && version.MD5Hash != expectedHash {
+		version.VerificationStatus = VerificationStatusFailed
+		if err := m.store.UpdateVersion(ctx, version); err != nil {
+			debug.Error("failed to update version status for version %d: %v", version.ID, err)
+		}
+		return fmt.Errorf("imported binary hash mismatch: expected %s, got %s", expectedHash, version.MD5Hash)
+	}
+	return nil
+}
+
+// GetArchivePath implements Manager.GetArchivePath
+func (m *manager) GetArchivePath(version *BinaryVersion) string {
+	return m.getBinaryPath(version)
+}
+
+// completeVersion records the size and hash of a stored binary archive,
+// verifies it and extracts it for server-side use
+func (m *manager) completeVersion(ctx context.Context, version *BinaryVersion) error {
 	// Get file info to set the file size
 	filePath := m.getBinaryPath(version)
 	fileInfo, err := os.Stat(filePath)
@@ -138,6 +188,9 @@ func (m *manager) AddVersion(ctx context.Context, version *BinaryVersion) error
 		// It can be extracted on-demand when needed
 	} else {
 		debug.Info("Successfully extracted binary version %d", version.ID)
+		if _, err := m.ExecutableHashes(ctx, version.ID); err != nil {
+			debug.Warning("Failed to record executable hashes of binary version %d: %v", version.ID, err)
+		}
 	}
 
 	debug.Info("Successfully added and verified binary version %d with hash %s", version.ID, calculatedHash)
@@ -256,6 +309,10 @@ func (m *manager) DownloadBinary(ctx context.Context, version *BinaryVersion) er
 		return nil
 	}
 
+	if m.config.Airgapped {
+		return fmt.Errorf("binary downloads are disabled in air-gapped mode, import the binary with a deployment bundle")
+	}
+
 	// Create HTTP client with timeout
 	client := &http.Client{
 		Timeout: 30 * time.Minute, // Long timeout for large files
@@ -461,6 +518,35 @@ func (m *manager) SetDefaultVersion(ctx context.Context, id int64) error {
 	return nil
 }
 
+// PromoteVersion implements Manager.PromoteVersion
+func (m *manager) PromoteVersion(ctx context.Context, fromID, toID int64) error {
+	if err := m.SetDefaultVersion(ctx, toID); err != nil {
+		return err
+	}
+	if err := m.store.UpdateReferencesToDefault(ctx, fromID, toID); err != nil {
+		return fmt.Errorf("failed to move references to version %d: %w", toID, err)
+	}
+
+	version, err := m.store.GetVersion(ctx, toID)
+	if err != nil {
+		return fmt.Errorf("failed to get version: %w", err)
+	}
+	auditLog := &BinaryAuditLog{
+		BinaryVersionID: toID,
+		Action:          "promote",
+		PerformedBy:     version.CreatedBy,
+		Details: map[string]any{
+			"replaced_version_id": fromID,
+		},
+	}
+	if err := m.store.CreateAuditLog(ctx, auditLog); err != nil {
+		debug.Warning("Failed to create audit log: %v", err)
+	}
+
+	debug.Info("Promoted binary version %d in place of %d", toID, fromID)
+	return nil
+}
+
 // GetLatestActive implements Manager.GetLatestActive
 func (m *manager) GetLatestActive(ctx context.Context, binaryType BinaryType) (*BinaryVersion, error) {
 	return m.store.GetLatestActive(ctx, binaryType)
@@ -566,6 +652,66 @@ func (m *manager) GetLocalBinaryPath(ctx context.Context, id int64) (string, err
 	return hashcatPath, nil
 }
 
+// hashcatExecutableNames are the names the hashcat executable has in the
+// release archives, one per platform
+var hashcatExecutableNames = []string{"hashcat.bin", "hashcat.exe", "hashcat"}
+
+// ExecutableHashes implements Manager.ExecutableHashes. The hashes are
+// computed from the extracted archive the first time and recorded.
+func (m *manager) ExecutableHashes(ctx context.Context, id int64) (map[string]string, error) {
+	hashes, err := m.store.GetExecutableHashes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(hashes) > 0 {
+		return hashes, nil
+	}
+
+	if _, err := m.GetLocalBinaryPath(ctx, id); err != nil {
+		return nil, err
+	}
+	version, err := m.store.GetVersion(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version: %w", err)
+	}
+	localDir := m.getLocalBinaryDir(version)
+
+	for _, name := range hashcatExecutableNames {
+		sum, err := sha256File(filepath.Join(localDir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %w", name, err)
+		}
+		hashes[name] = sum
+	}
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("no hashcat executable found in %s", localDir)
+	}
+
+	if err := m.store.SetExecutableHashes(ctx, id, hashes); err != nil {
+		return nil, err
+	}
+	debug.Info("Recorded hashes of %d executables of binary version %d", len(hashes), id)
+	return hashes, nil
+}
+
+// sha256File returns the hex SHA-256 hash of a file
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // getLocalBinaryDir returns the local extraction directory for a binary version
 func (m *manager) getLocalBinaryDir(version *BinaryVersion) string {
 	return filepath.Join(m.config.DataDir, "local", fmt.Sprintf("%d", version.ID))
@@ -291,6 +291,44 @@ func (s *store) CountActiveBinaries(ctx context.Context, binaryType BinaryType)
 	return count, nil
 }
 
+// GetExecutableHashes implements Store.GetExecutableHashes
+func (s *store) GetExecutableHashes(ctx context.Context, id int64) (map[string]string, error) {
+	var raw []byte
+	err := s.db.QueryRowContext(ctx, queries.GetBinaryExecutableHashes, id).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("binary version not found: %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executable hashes: %w", err)
+	}
+
+	hashes := map[string]string{}
+	if err := json.Unmarshal(raw, &hashes); err != nil {
+		return nil, fmt.Errorf("failed to decode executable hashes: %w", err)
+	}
+	return hashes, nil
+}
+
+// SetExecutableHashes implements Store.SetExecutableHashes
+func (s *store) SetExecutableHashes(ctx context.Context, id int64, hashes map[string]string) error {
+	raw, err := json.Marshal(hashes)
+	if err != nil {
+		return fmt.Errorf("failed to encode executable hashes: %w", err)
+	}
+	result, err := s.db.ExecContext(ctx, queries.SetBinaryExecutableHashes, id, raw)
+	if err != nil {
+		return fmt.Errorf("failed to set executable hashes: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("binary version not found: %d", id)
+	}
+	return nil
+}
+
 // UpdateReferencesToDefault implements Store.UpdateReferencesToDefault
 func (s *store) UpdateReferencesToDefault(ctx context.Context, oldID, newID int64) error {
 	// Start a transaction to ensure atomicity
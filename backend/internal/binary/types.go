@@ -79,12 +79,23 @@ type Manager interface {
 	// DownloadBinary downloads a binary from its source URL
 	DownloadBinary(ctx context.Context, version *BinaryVersion) error
 
+	// ImportVersion adds a new binary version from a local archive instead of
+	// downloading it. The archive is moved into the binary store.
+	ImportVersion(ctx context.Context, version *BinaryVersion, archivePath string) error
+
+	// GetArchivePath returns where the archive of a binary version is stored
+	GetArchivePath(version *BinaryVersion) string
+
 	// DeleteVersion marks a binary version as inactive with fallback protection
 	DeleteVersion(ctx context.Context, id int64) error
 
 	// SetDefaultVersion sets a binary version as the default for its type
 	SetDefaultVersion(ctx context.Context, id int64) error
 
+	// PromoteVersion makes a version the default and moves preset jobs and
+	// jobs that have not started from the version it replaces onto it
+	PromoteVersion(ctx context.Context, fromID, toID int64) error
+
 	// GetLatestActive returns the latest active version for a binary type
 	GetLatestActive(ctx context.Context, binaryType BinaryType) (*BinaryVersion, error)
 
@@ -93,6 +104,10 @@ type Manager interface {
 
 	// GetLocalBinaryPath returns the path to the extracted binary for server-side execution
 	GetLocalBinaryPath(ctx context.Context, id int64) (string, error)
+
+	// ExecutableHashes returns the SHA-256 hashes of the hashcat executables in
+	// a version's archive by file name, which agents check their copy against
+	ExecutableHashes(ctx context.Context, id int64) (map[string]string, error)
 }
 
 // Store defines the interface for binary version storage operations
@@ -129,9 +144,17 @@ type Store interface {
 
 	// CreateAuditLog creates an audit log entry
 	CreateAuditLog(ctx context.Context, log *BinaryAuditLog) error
+
+	// GetExecutableHashes returns the recorded SHA-256 hashes of a version's
+	// executables by file name, empty if none were recorded yet
+	GetExecutableHashes(ctx context.Context, id int64) (map[string]string, error)
+
+	// SetExecutableHashes records the SHA-256 hashes of a version's executables
+	SetExecutableHashes(ctx context.Context, id int64, hashes map[string]string) error
 }
 
 // Config holds configuration for the binary manager
 type Config struct {
-	DataDir string // Base directory for storing binaries
+	DataDir   string // Base directory for storing binaries
+	Airgapped bool   // Refuse to download binaries, they must be imported
 }
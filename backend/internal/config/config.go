@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/env"
@@ -21,6 +22,14 @@ type Config struct {
 	HashlistBatchSize int    // Max number of hashes to process in one DB batch
 	MaxUploadSize     int64  // Max size for file uploads in bytes
 	HashUploadDir     string // Directory within DataDir to store hashlist uploads
+	Airgapped         bool   // No outbound Internet access, assets arrive in deployment bundles
+
+	// How long agents wait before reconnecting when the server restarts
+	HandoverReconnectAfter time.Duration
+
+	// Shell commands launching and terminating cloud burst instances
+	CloudBurstLaunchCommand    string
+	CloudBurstTerminateCommand string
 }
 
 // NewConfig creates a new Config instance with values from environment variables
@@ -192,6 +201,20 @@ func NewConfig() *Config {
 	}
 	debug.Info("Using Hash Upload directory: %s", hashUploadDir)
 
+	airgapped := env.GetBool("KH_AIRGAPPED")
+	if airgapped {
+		debug.Info("Air-gapped mode enabled, outbound downloads and lookups are disabled")
+	}
+
+	handoverReconnectAfter := 30 * time.Second
+	if secondsStr := os.Getenv("KH_HANDOVER_RECONNECT_SECONDS"); secondsStr != "" {
+		if seconds, err := strconv.Atoi(secondsStr); err == nil && seconds > 0 {
+			handoverReconnectAfter = time.Duration(seconds) * time.Second
+		} else {
+			debug.Warning("Invalid KH_HANDOVER_RECONNECT_SECONDS value '%s', using default: %v", secondsStr, handoverReconnectAfter)
+		}
+	}
+
 	return &Config{
 		Host:              host,
 		HTTPPort:          httpPort,
@@ -201,6 +224,12 @@ func NewConfig() *Config {
 		HashlistBatchSize: hashlistBatchSize,
 		MaxUploadSize:     maxUploadSize,
 		HashUploadDir:     hashUploadDir,
+		Airgapped:         airgapped,
+
+		HandoverReconnectAfter: handoverReconnectAfter,
+
+		CloudBurstLaunchCommand:    os.Getenv("KH_CLOUD_BURST_LAUNCH_COMMAND"),
+		CloudBurstTerminateCommand: os.Getenv("KH_CLOUD_BURST_TERMINATE_COMMAND"),
 	}
 }
 
@@ -32,14 +32,12 @@ func Connect() (*sql.DB, error) {
 	dbHost := os.Getenv("DB_HOST")
 	dbPort := os.Getenv("DB_PORT")
 	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
 	dbName := os.Getenv("DB_NAME")
 
 	debug.Debug("Database configuration - Host: %s, Port: %s, User: %s, Database: %s",
 		dbHost, dbPort, dbUser, dbName)
 
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		dbHost, dbPort, dbUser, dbPassword, dbName)
+	connStr := ConnectionString()
 
 	debug.Debug("Connection string created (without password): host=%s port=%s user=%s dbname=%s sslmode=disable",
 		dbHost, dbPort, dbUser, dbName)
@@ -62,6 +60,50 @@ func Connect() (*sql.DB, error) {
 	return db, nil
 }
 
+// ConnectionString returns the Postgres connection string built from the DB_* environment variables
+func ConnectionString() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"))
+}
+
+// ConnectReplica opens the optional read replica configured by DB_REPLICA_HOST.
+// Port, user, password and database name default to the primary's. It returns
+// nil without an error when no replica is configured.
+func ConnectReplica() (*sql.DB, error) {
+	host := os.Getenv("DB_REPLICA_HOST")
+	if host == "" {
+		return nil, nil
+	}
+
+	debug.Info("Attempting read replica connection to %s", host)
+	replicaDB, err := sql.Open("postgres", ReplicaConnectionString())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open read replica: %w", err)
+	}
+
+	if err := replicaDB.Ping(); err != nil {
+		replicaDB.Close()
+		return nil, fmt.Errorf("failed to ping read replica: %w", err)
+	}
+
+	debug.Info("Successfully connected to read replica")
+	return replicaDB, nil
+}
+
+// ReplicaConnectionString returns the read replica's connection string built
+// from the DB_REPLICA_* environment variables, falling back to DB_*
+func ReplicaConnectionString() string {
+	env := func(key, fallback string) string {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+		return os.Getenv(fallback)
+	}
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		os.Getenv("DB_REPLICA_HOST"), env("DB_REPLICA_PORT", "DB_PORT"), env("DB_REPLICA_USER", "DB_USER"),
+		env("DB_REPLICA_PASSWORD", "DB_PASSWORD"), env("DB_REPLICA_NAME", "DB_NAME"))
+}
+
 /*
  * RunMigrations executes all pending database migrations from the db/migrations directory.
  * Migrations are run in order based on their timestamp prefix.
@@ -72,38 +114,12 @@ func Connect() (*sql.DB, error) {
  */
 func RunMigrations() error {
 	debug.Info("Starting database migrations")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME")
-
-	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		dbUser, dbPassword, dbHost, dbPort, dbName)
-
-	// Try different migration paths
-	migrationPaths := []string{
-		"file:///usr/local/share/krakenhashes/migrations", // Docker container path
-		"file://db/migrations",                            // Local development path
-	}
-
-	var m *migrate.Migrate
-	var err error
-
-	for _, path := range migrationPaths {
-		debug.Debug("Trying migrations path: %s", path)
-		m, err = migrate.New(path, connStr)
-		if err == nil {
-			debug.Info("Found migrations at: %s", path)
-			break
-		}
-		debug.Debug("Migration path not found: %s", err)
-	}
 
+	m, _, err := newMigrator()
 	if err != nil {
-		debug.Error("Failed to create migration instance: %v", err)
 		return err
 	}
+	defer m.Close()
 
 	if err := m.Up(); err != nil {
 		if err == migrate.ErrNoChange {
@@ -113,7 +129,7 @@ func RunMigrations() error {
 			debug.Error("Migration failed because the database is in a dirty state for version %d.", e.Version)
 			debug.Error("This usually means a previous migration attempt failed partway through.")
 			debug.Error("Check the logs from the *first* time migration %d failed for the specific SQL error.", e.Version)
-			debug.Error("You may need to manually clean the database and reset the migration version. Original error: %v", err)
+			debug.Error("Run 'krakenhashes migrate status' for recovery steps. Original error: %v", err)
 			return fmt.Errorf("dirty migration version %d: %w", e.Version, err)
 		} else {
 			// Handle other migration errors
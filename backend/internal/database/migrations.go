@@ -0,0 +1,236 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+// migrationPaths are the locations searched for migration files, in order
+var migrationPaths = []string{
+	"file:///usr/local/share/krakenhashes/migrations", // Docker container path
+	"file://db/migrations",                            // Local development path
+}
+
+// MigrationStatus describes the schema version of the database compared with
+// the migrations shipped with this build
+type MigrationStatus struct {
+	Version  uint   // Current schema version, 0 if no migration has been applied
+	Dirty    bool   // A migration failed partway through
+	Latest   uint   // Highest available migration version
+	Pending  []uint // Available migrations newer than Version
+	Previous int    // Version before Version, -1 if Version is the first migration
+	Path     string // Where the migrations were loaded from
+}
+
+// migrationsURL builds the connection URL used by the migration library
+func migrationsURL() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"))
+}
+
+// newMigrator creates a migration instance from the first migration path that
+// exists and returns it together with that path
+func newMigrator() (*migrate.Migrate, string, error) {
+	connStr := migrationsURL()
+
+	var lastErr error
+	for _, path := range migrationPaths {
+		debug.Debug("Trying migrations path: %s", path)
+		m, err := migrate.New(path, connStr)
+		if err == nil {
+			debug.Info("Found migrations at: %s", path)
+			return m, path, nil
+		}
+		debug.Debug("Migration path not found: %s", err)
+		lastErr = err
+	}
+
+	debug.Error("Failed to create migration instance: %v", lastErr)
+	return nil, "", lastErr
+}
+
+// availableVersions lists the migration versions found at path in ascending order
+func availableVersions(path string) ([]uint, error) {
+	src, err := source.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations at %s: %w", path, err)
+	}
+	defer src.Close()
+
+	var versions []uint
+	v, err := src.First()
+	for err == nil {
+		versions = append(versions, v)
+		v, err = src.Next(v)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read migrations at %s: %w", path, err)
+	}
+	return versions, nil
+}
+
+/*
+ * GetMigrationStatus reports the current schema version, whether it is dirty
+ * and which migrations are still pending.
+ *
+ * Returns:
+ *   - *MigrationStatus: Schema status compared with the available migrations
+ *   - error: Any error encountered opening the database or migrations
+ */
+func GetMigrationStatus() (*MigrationStatus, error) {
+	m, path, err := newMigrator()
+	if err != nil {
+		return nil, err
+	}
+	defer m.Close()
+
+	status := &MigrationStatus{Path: path, Previous: -1}
+	status.Version, status.Dirty, err = m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	versions, err := availableVersions(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		switch {
+		case v < status.Version:
+			status.Previous = int(v)
+		case v > status.Version:
+			status.Pending = append(status.Pending, v)
+		}
+		status.Latest = v
+	}
+
+	return status, nil
+}
+
+/*
+ * DirtyRecoveryHint explains how to recover from a migration that failed
+ * partway through, using the migrate subcommands of the server binary.
+ *
+ * Parameters:
+ *   - status: Migration status of a dirty database
+ *
+ * Returns:
+ *   - string: Step-by-step recovery instructions
+ */
+func DirtyRecoveryHint(status *MigrationStatus) string {
+	previous := fmt.Sprintf("%d", status.Previous)
+	return fmt.Sprintf(`Migration %d failed partway through and the schema is marked dirty.
+No further migrations can run until the schema version is corrected.
+
+  1. Find the original error in the logs from the first time migration %d failed.
+  2. Compare the database with db/migrations/%06d_*.up.sql to see which statements were applied.
+  3. If none of it was applied (or you have reverted what was), mark the previous version as current:
+       krakenhashes migrate force %s
+     then apply the migration again:
+       krakenhashes migrate up
+  4. If all of it was applied (for example after finishing it by hand), mark it as complete:
+       krakenhashes migrate force %d`,
+		status.Version, status.Version, status.Version, previous, status.Version)
+}
+
+/*
+ * MigrateDown rolls back the given number of applied migrations.
+ *
+ * Parameters:
+ *   - steps: Number of migrations to roll back, must be at least 1
+ *
+ * Returns:
+ *   - error: migrate.ErrDirty if the schema is dirty, or any other migration error
+ */
+func MigrateDown(steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("number of migrations to roll back must be at least 1")
+	}
+
+	m, path, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	current, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("no migrations have been applied")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if dirty {
+		return migrate.ErrDirty{Version: int(current)}
+	}
+
+	// The library rolls back as far as it can before reporting a short limit,
+	// so refuse up front rather than leave a partial rollback behind
+	versions, err := availableVersions(path)
+	if err != nil {
+		return err
+	}
+	applied := 0
+	for _, v := range versions {
+		if v <= current {
+			applied++
+		}
+	}
+	if steps > applied {
+		return fmt.Errorf("cannot roll back %d migration(s): only %d have been applied", steps, applied)
+	}
+
+	debug.Info("Rolling back %d database migration(s) from version %d", steps, current)
+	if err := m.Steps(-steps); err != nil {
+		return err
+	}
+	debug.Info("Rolled back %d database migration(s)", steps)
+	return nil
+}
+
+/*
+ * ForceMigrationVersion sets the schema version and clears the dirty flag
+ * without running any migration. Used to recover from a failed migration.
+ *
+ * Parameters:
+ *   - version: Version to record, or -1 to record that no migration has been applied
+ *
+ * Returns:
+ *   - error: If the version does not exist or the version could not be set
+ */
+func ForceMigrationVersion(version int) error {
+	m, path, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if version != -1 {
+		versions, err := availableVersions(path)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, v := range versions {
+			if int(v) == version {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("migration version %d does not exist in %s", version, path)
+		}
+	}
+
+	debug.Warning("Forcing database schema version to %d", version)
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("failed to force schema version %d: %w", version, err)
+	}
+	return nil
+}
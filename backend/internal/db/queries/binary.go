@@ -91,6 +91,16 @@ const (
 		AND is_active = true 
 		AND verification_status = 'verified'`
 
+	GetBinaryExecutableHashes = `
+		SELECT executable_hashes
+		FROM binary_versions
+		WHERE id = $1`
+
+	SetBinaryExecutableHashes = `
+		UPDATE binary_versions
+		SET executable_hashes = $2
+		WHERE id = $1`
+
 	UpdatePresetJobsBinaryReference = `
 		UPDATE preset_jobs 
 		SET binary_version_id = $2 
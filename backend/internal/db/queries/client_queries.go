@@ -2,41 +2,79 @@ package queries
 
 // --- Client Query Constants ---
 
+// SetClientEngagementStatusQuery opens or closes a client's engagement, stamping when it closed
+const SetClientEngagementStatusQuery = `
+UPDATE clients
+SET engagement_status = $2,
+    closed_at = CASE WHEN $2 = 'closed' THEN COALESCE(closed_at, NOW()) END,
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
 const CreateClientQuery = `
-INSERT INTO clients (id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO clients (id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at,
+    contacts, engagement_status, engagement_start_date, engagement_end_date, scope_notes, closed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, CASE WHEN $10 = 'closed' THEN NOW() END)
+RETURNING closed_at
 `
 
 const GetClientByIDQuery = `
-SELECT id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at
+SELECT id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at,
+    contacts, engagement_status, engagement_start_date, engagement_end_date, scope_notes, closed_at
+FROM clients
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+// GetClientByIDIncludingDeletedQuery also matches clients that are in the trash
+const GetClientByIDIncludingDeletedQuery = `
+SELECT id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at,
+    contacts, engagement_status, engagement_start_date, engagement_end_date, scope_notes, closed_at
 FROM clients
 WHERE id = $1
 `
 
 const ListClientsQuery = `
-SELECT id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at
+SELECT id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at,
+    contacts, engagement_status, engagement_start_date, engagement_end_date, scope_notes, closed_at
 FROM clients
+WHERE deleted_at IS NULL
 ORDER BY name ASC
 `
 
 const UpdateClientQuery = `
 UPDATE clients
-SET name = $1, description = $2, contact_info = $3, data_retention_months = $4, exclude_from_potfile = $5, updated_at = $6
+SET name = $1, description = $2, contact_info = $3, data_retention_months = $4, exclude_from_potfile = $5, updated_at = $6,
+    contacts = $8, engagement_status = $9, engagement_start_date = $10, engagement_end_date = $11, scope_notes = $12,
+    closed_at = CASE WHEN $9 = 'closed' THEN COALESCE(closed_at, NOW()) END
 WHERE id = $7
 `
 
 const DeleteClientQuery = `DELETE FROM clients WHERE id = $1`
 
+const SoftDeleteClientQuery = `
+UPDATE clients
+SET deleted_at = NOW(), deleted_by = $2
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+const RestoreClientQuery = `
+UPDATE clients
+SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
 const GetClientByNameQuery = `
-SELECT id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at
+SELECT id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at,
+    contacts, engagement_status, engagement_start_date, engagement_end_date, scope_notes, closed_at
 FROM clients
-WHERE name = $1
+WHERE name = $1 AND deleted_at IS NULL
 `
 
 const SearchClientsQuery = `
-SELECT id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at
+SELECT id, name, description, contact_info, data_retention_months, exclude_from_potfile, created_at, updated_at,
+    contacts, engagement_status, engagement_start_date, engagement_end_date, scope_notes, closed_at
 FROM clients
-WHERE name ILIKE $1 OR description ILIKE $1
+WHERE (name ILIKE $1 OR description ILIKE $1) AND deleted_at IS NULL
 ORDER BY name ASC
 LIMIT 50
 `
@@ -52,11 +90,18 @@ SELECT
     c.exclude_from_potfile,
     c.created_at,
     c.updated_at,
+    c.contacts,
+    c.engagement_status,
+    c.engagement_start_date,
+    c.engagement_end_date,
+    c.scope_notes,
+    c.closed_at,
     COUNT(DISTINCT h.id) FILTER (WHERE h.is_cracked = true) as cracked_count
 FROM clients c
-LEFT JOIN hashlists hl ON hl.client_id = c.id
+LEFT JOIN hashlists hl ON hl.client_id = c.id AND hl.deleted_at IS NULL
 LEFT JOIN hashlist_hashes hh ON hh.hashlist_id = hl.id
 LEFT JOIN hashes h ON h.id = hh.hash_id
-GROUP BY c.id, c.name, c.description, c.contact_info, c.data_retention_months, c.exclude_from_potfile, c.created_at, c.updated_at
+WHERE c.deleted_at IS NULL
+GROUP BY c.id
 ORDER BY c.name ASC
 `
@@ -18,7 +18,8 @@ const (
 			a.updated_at, a.api_key, a.api_key_created_at,
 			a.api_key_last_used, a.metadata, a.owner_id, a.extra_parameters, a.is_enabled,
 			a.consecutive_failures, a.scheduling_enabled, a.schedule_timezone,
-			a.sync_status, a.sync_started_at, a.sync_completed_at, a.files_to_sync, a.files_synced, a.sync_error,
+			a.sync_status, a.sync_started_at, a.sync_completed_at, a.files_to_sync, a.files_synced, a.sync_error, a.labels,
+			a.workload_class, a.workload_profile, a.managed_config,
 			u.id, u.username, u.email, u.role
 		FROM agents a
 		LEFT JOIN users u ON a.created_by_id = u.id
@@ -31,7 +32,8 @@ const (
 			a.updated_at, a.api_key, a.api_key_created_at,
 			a.api_key_last_used, a.metadata, a.owner_id, a.extra_parameters, a.is_enabled,
 			a.consecutive_failures, a.scheduling_enabled, a.schedule_timezone,
-			a.sync_status, a.sync_started_at, a.sync_completed_at, a.files_to_sync, a.files_synced, a.sync_error,
+			a.sync_status, a.sync_started_at, a.sync_completed_at, a.files_to_sync, a.files_synced, a.sync_error, a.labels,
+			a.workload_class, a.workload_profile, a.managed_config,
 			u.id, u.username, u.email, u.role
 		FROM agents a
 		LEFT JOIN users u ON a.created_by_id = u.id
@@ -48,16 +50,14 @@ const (
 			hardware = $7,
 			os_info = $8,
 			updated_at = $9,
-			api_key = $10,
-			api_key_created_at = $11,
-			api_key_last_used = $12,
-			metadata = $13,
-			sync_status = $14,
-			sync_completed_at = $15,
-			sync_started_at = $16,
-			sync_error = $17,
-			files_to_sync = $18,
-			files_synced = $19
+			api_key_last_used = $10,
+			metadata = $11,
+			sync_status = $12,
+			sync_completed_at = $13,
+			sync_started_at = $14,
+			sync_error = $15,
+			files_to_sync = $16,
+			files_synced = $17
 		WHERE id = $1`
 
 	UpdateAgentStatus = `
@@ -89,7 +89,9 @@ const (
 			u.id, u.username, u.email, u.role
 		FROM agents a
 		LEFT JOIN users u ON a.created_by_id = u.id
-		WHERE a.api_key = $1`
+		WHERE a.api_key = $1
+			OR a.pending_api_key = $1
+			OR (a.previous_api_key = $1 AND a.previous_api_key_expires_at > NOW())`
 )
 
 // User queries
@@ -178,14 +180,16 @@ const (
 	CreateClaimVoucher = `
 		INSERT INTO claim_vouchers (
 			code, is_active, is_continuous,
-			created_by_id, created_at, updated_at
+			created_by_id, created_at, updated_at,
+			max_uses, expires_at, labels
 		) VALUES (
-			$1, $2, $3, $4, $5, $6
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
 		) RETURNING code`
 
 	GetClaimVoucherByCode = `
 		SELECT 
 			v.code, v.is_active, v.is_continuous,
+			v.max_uses, v.use_count, v.expires_at, v.labels,
 			v.created_by_id, v.used_by_agent_id, v.used_at, v.created_at, v.updated_at,
 			u1.id, u1.username, u1.email, u1.role,
 			a.id, a.name, a.status
@@ -197,6 +201,7 @@ const (
 	ListActiveVouchers = `
 		SELECT 
 			v.code, v.is_active, v.is_continuous,
+			v.max_uses, v.use_count, v.expires_at, v.labels,
 			v.created_by_id, v.used_by_agent_id, v.used_at, v.created_at, v.updated_at,
 			u1.id, u1.username, u1.email, u1.role,
 			a.id, a.name, a.status
@@ -204,21 +209,55 @@ const (
 		LEFT JOIN users u1 ON v.created_by_id = u1.id
 		LEFT JOIN agents a ON v.used_by_agent_id = a.id
 		WHERE v.is_active = true
+		AND (v.expires_at IS NULL OR v.expires_at > NOW())
 		ORDER BY v.created_at DESC`
 
-	UseClaimVoucherByAgent = `
+	DeactivateClaimVoucher = `
+		UPDATE claim_vouchers SET
+			is_active = false,
+			updated_at = NOW()
+		WHERE code = $1`
+
+	// RedeemClaimVoucher counts one registration against a voucher that is
+	// still redeemable and deactivates it once single-use or its cap is reached
+	RedeemClaimVoucher = `
 		UPDATE claim_vouchers SET
+			use_count = use_count + 1,
 			used_by_agent_id = $2,
 			used_at = $3,
-			updated_at = $3
+			updated_at = $3,
+			is_active = CASE
+				WHEN is_continuous = false THEN false
+				WHEN max_uses IS NOT NULL AND use_count + 1 >= max_uses THEN false
+				ELSE is_active
+			END
 		WHERE code = $1 AND is_active = true
+		AND (expires_at IS NULL OR expires_at > $3)
+		AND (max_uses IS NULL OR use_count < max_uses)
 		AND (is_continuous = true OR used_by_agent_id IS NULL)`
 
-	DeactivateClaimVoucher = `
-		UPDATE claim_vouchers SET
-			is_active = false,
-			updated_at = NOW()
-		WHERE code = $1`
+	CreateClaimVoucherUsage = `
+		INSERT INTO claim_voucher_usage (
+			voucher_code, agent_id, attempted_at, success, error_message
+		) VALUES (
+			$1, $2, $3, $4, $5
+		)`
+
+	ListClaimVoucherUsage = `
+		SELECT
+			u.id, u.voucher_code, u.attempted_by_id, u.agent_id, u.attempted_at,
+			u.success, u.ip_address, u.user_agent, u.error_message,
+			a.name, a.status, a.labels
+		FROM claim_voucher_usage u
+		LEFT JOIN agents a ON u.agent_id = a.id
+		WHERE u.voucher_code = $1
+		ORDER BY u.attempted_at DESC, u.id DESC`
+
+	ClaimVoucherRedeemedByAgent = `
+		SELECT EXISTS (
+			SELECT 1 FROM claim_voucher_usage
+			WHERE voucher_code = $1 AND agent_id = $2 AND success = true
+		)`
 )
 
 // Email Queries
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// The optional read replica is process wide, since repositories wrap the
+// primary pool in a new DB in many places
+var (
+	replica        atomic.Pointer[sql.DB]
+	replicaHealthy atomic.Bool
+)
+
+// SetReplica registers a read replica for heavy report queries, nil removes it
+func SetReplica(replicaDB *sql.DB) {
+	replica.Store(replicaDB)
+	replicaHealthy.Store(replicaDB != nil)
+}
+
+// Reader returns the pool for read-only report, analytics and export queries.
+// That is the replica when one is configured and reachable, the primary
+// otherwise. Its data may lag slightly behind the primary, so queries that
+// must see a write made just before still use the DB itself.
+func (db *DB) Reader() *sql.DB {
+	if r := replica.Load(); r != nil && replicaHealthy.Load() {
+		return r
+	}
+	return db.DB
+}
+
+// MonitorReplica pings the replica every interval until ctx is done. Reads
+// fall back to the primary while the replica cannot be reached.
+func MonitorReplica(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r := replica.Load()
+			if r == nil {
+				continue
+			}
+
+			pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			err := r.PingContext(pingCtx)
+			cancel()
+
+			healthy := err == nil
+			if replicaHealthy.Swap(healthy) != healthy {
+				if healthy {
+					debug.Info("Read replica is reachable again, report queries use the replica")
+				} else {
+					debug.Warning("Read replica is unreachable, report queries use the primary: %v", err)
+				}
+			}
+		}
+	}
+}
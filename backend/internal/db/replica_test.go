@@ -0,0 +1,35 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	primary, err := sql.Open("postgres", "host=primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer primary.Close()
+	replicaDB, err := sql.Open("postgres", "host=replica")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replicaDB.Close()
+	defer SetReplica(nil)
+
+	database := &DB{DB: primary}
+	if database.Reader() != primary {
+		t.Error("Reader() without a replica should return the primary")
+	}
+
+	SetReplica(replicaDB)
+	if database.Reader() != replicaDB {
+		t.Error("Reader() should return the replica")
+	}
+
+	replicaHealthy.Store(false)
+	if database.Reader() != primary {
+		t.Error("Reader() should fall back to the primary while the replica is unreachable")
+	}
+}
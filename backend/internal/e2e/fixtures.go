@@ -0,0 +1,217 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/binary"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/wordlist"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// hashTypeMD5 is the hashcat mode of the hashes the fixtures create
+const hashTypeMD5 = 0
+
+// CreateAgent creates an enabled agent with an API key. Connect a fake agent
+// for it with Agent.
+func (h *Harness) CreateAgent(name string) *models.Agent {
+	h.t.Helper()
+
+	now := time.Now()
+	agent := &models.Agent{
+		Name:            name,
+		Status:          models.AgentStatusInactive,
+		LastHeartbeat:   now,
+		Version:         "e2e",
+		OSInfo:          []byte(`{}`),
+		CreatedByID:     h.User.ID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		APIKey:          sql.NullString{String: uuid.NewString(), Valid: true},
+		APIKeyCreatedAt: sql.NullTime{Time: now, Valid: true},
+		Metadata:        map[string]string{},
+	}
+	require.NoError(h.t, repository.NewAgentRepository(h.DB).Create(context.Background(), agent))
+	return agent
+}
+
+// CreateBinary creates the hashcat binary version jobs run with. Fake agents
+// never download it.
+func (h *Harness) CreateBinary() int {
+	h.t.Helper()
+
+	version := &binary.BinaryVersion{
+		BinaryType:         binary.BinaryTypeHashcat,
+		CompressionType:    binary.CompressionType7z,
+		SourceURL:          "https://example.com/hashcat.7z",
+		FileName:           "hashcat.7z",
+		MD5Hash:            md5Hex("hashcat"),
+		FileSize:           1,
+		CreatedBy:          h.User.ID,
+		IsActive:           true,
+		VerificationStatus: binary.VerificationStatusVerified,
+		IsDefault:          true,
+	}
+	require.NoError(h.t, binary.NewStore(h.DB.DB).CreateVersion(context.Background(), version))
+	return int(version.ID)
+}
+
+// CreateWordlist writes a wordlist of count words and creates it
+func (h *Harness) CreateWordlist(name string, count int) int {
+	h.t.Helper()
+
+	var content strings.Builder
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&content, "word%d\n", i)
+	}
+
+	fileName := filepath.Join("general", name)
+	path := filepath.Join(h.DataDir, "wordlists", fileName)
+	require.NoError(h.t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(h.t, os.WriteFile(path, []byte(content.String()), 0644))
+
+	list := &models.Wordlist{
+		Name:               name,
+		WordlistType:       "general",
+		Format:             "txt",
+		FileName:           fileName,
+		MD5Hash:            md5Hex(content.String()),
+		FileSize:           int64(content.Len()),
+		WordCount:          int64(count),
+		CreatedBy:          h.User.ID,
+		VerificationStatus: "verified",
+	}
+	require.NoError(h.t, wordlist.NewStore(h.DB.DB).CreateWordlist(context.Background(), list))
+	return list.ID
+}
+
+// CreateHashlist creates a ready MD5 hashlist of the hashes of plains and
+// writes its file where the backend syncs it to agents from
+func (h *Harness) CreateHashlist(name string, plains []string) *models.HashList {
+	h.t.Helper()
+	ctx := context.Background()
+
+	now := time.Now()
+	list := &models.HashList{
+		Name:       name,
+		UserID:     h.User.ID,
+		HashTypeID: hashTypeMD5,
+		Status:     models.HashListStatusReady,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	hashlistRepo := repository.NewHashListRepository(h.DB)
+	require.NoError(h.t, hashlistRepo.Create(ctx, list))
+
+	hashes := make([]*models.Hash, len(plains))
+	associations := make([]*models.HashListHash, len(plains))
+	var content strings.Builder
+	for i, plain := range plains {
+		value := md5Hex(plain)
+		hashes[i] = &models.Hash{ID: uuid.New(), HashValue: value, OriginalHash: value, HashTypeID: hashTypeMD5, LastUpdated: now}
+		associations[i] = &models.HashListHash{HashlistID: list.ID, HashID: hashes[i].ID}
+		content.WriteString(value + "\n")
+	}
+	hashRepo := repository.NewHashRepository(h.DB)
+	_, err := hashRepo.CreateBatch(ctx, hashes)
+	require.NoError(h.t, err)
+	require.NoError(h.t, hashRepo.AddBatchToHashList(ctx, associations))
+
+	path := filepath.Join(h.DataDir, "hashlists", strconv.FormatInt(list.ID, 10)+".hash")
+	require.NoError(h.t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(h.t, os.WriteFile(path, []byte(content.String()), 0644))
+	require.NoError(h.t, hashlistRepo.UpdateStatsAndStatusWithPath(ctx, list.ID, len(plains), 0, models.HashListStatusReady, "", path))
+
+	list.FilePath = path
+	list.TotalHashes = len(plains)
+	return list
+}
+
+// JobOptions describes a straight attack job
+type JobOptions struct {
+	Name       string
+	HashlistID int64
+	WordlistID int
+	BinaryID   int
+	// Keyspace is the number of words in the wordlist
+	Keyspace int64
+	Priority int
+	// MaxAgents limits the agents working on the job, 0 for no limit
+	MaxAgents int
+	// ChunkSeconds is the chunk duration, chunks are Speed × ChunkSeconds words
+	ChunkSeconds int
+}
+
+// CreateJob creates a pending straight attack job with an accurate keyspace,
+// as it is after the keyspace of a new job was calculated
+func (h *Harness) CreateJob(opts JobOptions) uuid.UUID {
+	h.t.Helper()
+	ctx := context.Background()
+
+	keyspace := opts.Keyspace
+	job := &models.JobExecution{
+		Name:             opts.Name,
+		HashlistID:       opts.HashlistID,
+		Status:           models.JobExecutionStatusPending,
+		Priority:         opts.Priority,
+		MaxAgents:        opts.MaxAgents,
+		AttackMode:       models.AttackModeStraight,
+		TotalKeyspace:    &keyspace,
+		CreatedBy:        &h.User.ID,
+		WordlistIDs:      models.IDArray{strconv.Itoa(opts.WordlistID)},
+		RuleIDs:          models.IDArray{},
+		BinaryVersionID:  opts.BinaryID,
+		HashType:         hashTypeMD5,
+		ChunkSizeSeconds: opts.ChunkSeconds,
+	}
+	require.NoError(h.t, repository.NewJobExecutionRepository(h.DB).Create(ctx, job))
+
+	_, err := h.DB.ExecContext(ctx, `
+		UPDATE job_executions
+		SET base_keyspace = $2, effective_keyspace = $2, multiplication_factor = 1, is_accurate_keyspace = true
+		WHERE id = $1`, job.ID, keyspace)
+	require.NoError(h.t, err)
+	return job.ID
+}
+
+// CrackedCount returns how many hashes of a hashlist are cracked
+func (h *Harness) CrackedCount(hashlistID int64) int {
+	h.t.Helper()
+
+	var count int
+	err := h.DB.QueryRow(`
+		SELECT COUNT(*) FROM hashes h
+		JOIN hashlist_hashes hh ON hh.hash_id = h.id
+		WHERE hh.hashlist_id = $1 AND h.is_cracked = true`, hashlistID).Scan(&count)
+	require.NoError(h.t, err)
+	return count
+}
+
+// Crack returns the crack a fake agent reports for plain
+func Crack(plain string) models.CrackedHash {
+	hash := md5Hex(plain)
+	return models.CrackedHash{
+		Hash:     hash,
+		Plain:    plain,
+		HexPlain: hex.EncodeToString([]byte(plain)),
+		FullLine: hash + ":" + plain,
+	}
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
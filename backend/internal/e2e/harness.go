@@ -0,0 +1,240 @@
+//go:build e2e
+
+// Package e2e runs the backend's job pipeline against fake agents and a real
+// PostgreSQL database. The tests are built with the e2e tag, `make test-e2e`
+// starts a disposable database and runs them.
+package e2e
+
+import (
+	"context"
+	cryptotls "crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/binary"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/database"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/integration"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/routes"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/rule"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/testutil"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/testutil/fakeagent"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/wordlist"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+// pollInterval is how often Eventually re-checks its condition and asks the
+// scheduler for a cycle
+const pollInterval = 100 * time.Millisecond
+
+// tables holds what the tests create beyond the tables testutil.SetupTestDB
+// cleans up, children before parents
+var tables = []string{
+	"domain_events",
+	"job_tasks",
+	"job_executions",
+	"agent_benchmarks",
+	"agent_devices",
+	"agent_hashlists",
+	"wordlists",
+	"rules",
+	"binary_versions",
+}
+
+// Harness runs the agent WebSocket endpoint and the job scheduler of a
+// backend against a test database
+type Harness struct {
+	t       *testing.T
+	DB      *db.DB
+	DataDir string
+	User    *models.User
+	Jobs    *integration.JobIntegrationManager
+
+	server   *httptest.Server
+	settings *repository.SystemSettingsRepository
+	jobRepo  *repository.JobExecutionRepository
+	taskRepo *repository.JobTaskRepository
+}
+
+// New starts a backend for one test. Everything it starts is stopped and the
+// data it creates is removed when the test ends.
+func New(t *testing.T) *Harness {
+	t.Helper()
+
+	testDB := testutil.SetupTestDB(t)
+	sqlDB := testDB.DB
+	cfg := &config.Config{DataDir: t.TempDir()}
+
+	h := &Harness{
+		t:        t,
+		DB:       testDB,
+		DataDir:  cfg.DataDir,
+		settings: repository.NewSystemSettingsRepository(testDB),
+		jobRepo:  repository.NewJobExecutionRepository(testDB),
+		taskRepo: repository.NewJobTaskRepository(testDB),
+	}
+	t.Cleanup(h.truncate)
+
+	h.User = testutil.CreateTestUser(t, testDB, "e2e", "e2e@example.com", "E2e-Password-123!", "admin")
+
+	// The same wiring as cmd/server, minus what the job pipeline does not use
+	agentRepo := repository.NewAgentRepository(testDB)
+	jobTaskRepo := repository.NewJobTaskRepository(testDB)
+	jobExecutionRepo := repository.NewJobExecutionRepository(testDB)
+	agentService := services.NewAgentService(
+		agentRepo,
+		repository.NewClaimVoucherRepository(testDB),
+		repository.NewFileRepository(testDB, cfg.DataDir),
+		repository.NewAgentDeviceRepository(testDB),
+		jobTaskRepo,
+		jobExecutionRepo,
+	)
+
+	wordlistStore := wordlist.NewStore(sqlDB)
+	wordlistManager := wordlist.NewManager(wordlistStore, filepath.Join(cfg.DataDir, "wordlists"), 0, []string{"txt"}, []string{"text/plain"}, jobExecutionRepo)
+	ruleManager := rule.NewManager(rule.NewStore(sqlDB), filepath.Join(cfg.DataDir, "rules"), 0, []string{"rule"}, []string{"text/plain"}, jobExecutionRepo)
+	binaryManager, err := binary.NewManager(binary.NewStore(sqlDB), binary.Config{DataDir: filepath.Join(cfg.DataDir, "binaries"), Airgapped: true})
+	require.NoError(t, err)
+
+	presetJobRepo := repository.NewPresetJobRepository(sqlDB)
+	potfileService := services.NewPotfileService(
+		testDB,
+		cfg.DataDir,
+		h.settings,
+		presetJobRepo,
+		wordlistStore,
+		repository.NewHashRepository(testDB),
+		services.NewJobUpdateService(presetJobRepo, jobExecutionRepo, jobTaskRepo),
+	)
+
+	router := mux.NewRouter()
+	routes.SetupWebSocketWithJobRoutes(router, agentService, &tlsProvider{}, sqlDB, cfg, wordlistManager, ruleManager, binaryManager, potfileService)
+	require.NotNil(t, routes.JobIntegrationManager, "job integration was not set up")
+	h.Jobs = routes.JobIntegrationManager
+
+	// The agent endpoint requires TLS whenever the backend has a TLS config
+	h.server = httptest.NewUnstartedServer(router)
+	h.server.StartTLS()
+	t.Cleanup(h.server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	bus := events.NewBus(testDB, database.ConnectionString())
+	h.Jobs.SubscribeEvents(bus)
+	bus.Start(ctx)
+	h.Jobs.StartScheduler(ctx)
+
+	return h
+}
+
+// Agent creates an agent in the database and a fake agent for it, which is
+// connected to the backend
+func (h *Harness) Agent(name string, cfg fakeagent.Config) *fakeagent.Agent {
+	h.t.Helper()
+
+	agent := h.CreateAgent(name)
+	cfg.URL = "wss" + strings.TrimPrefix(h.server.URL, "https") + "/ws/agent"
+	cfg.AgentID = agent.ID
+	cfg.APIKey = agent.APIKey.String
+	cfg.TLSConfig = &cryptotls.Config{InsecureSkipVerify: true}
+
+	fake := fakeagent.New(cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(h.t, fake.Connect(ctx))
+	h.t.Cleanup(fake.Disconnect)
+	return fake
+}
+
+// Setting changes a system setting for the duration of the test
+func (h *Harness) Setting(key, value string) {
+	h.t.Helper()
+
+	ctx := context.Background()
+	previous, err := h.settings.GetSetting(ctx, key)
+	require.NoError(h.t, err, "setting %s", key)
+	require.NoError(h.t, h.settings.SetSetting(ctx, key, &value))
+
+	h.t.Cleanup(func() {
+		if err := h.settings.SetSetting(context.Background(), key, previous.Value); err != nil {
+			h.t.Logf("Failed to restore setting %s: %v", key, err)
+		}
+	})
+}
+
+// Eventually waits for cond, asking the scheduler for a cycle while waiting
+// so tests do not depend on the scheduling interval
+func (h *Harness) Eventually(cond func() bool, timeout time.Duration, msg string) {
+	h.t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			h.t.Fatalf("timed out after %s waiting until %s", timeout, msg)
+		}
+		h.Jobs.RequestSchedule()
+		time.Sleep(pollInterval)
+	}
+}
+
+// Job returns a job execution
+func (h *Harness) Job(id uuid.UUID) *models.JobExecution {
+	h.t.Helper()
+
+	job, err := h.jobRepo.GetByID(context.Background(), id)
+	require.NoError(h.t, err)
+	return job
+}
+
+// Tasks returns the tasks of a job execution
+func (h *Harness) Tasks(id uuid.UUID) []models.JobTask {
+	h.t.Helper()
+
+	tasks, err := h.taskRepo.GetTasksByJobExecution(context.Background(), id)
+	require.NoError(h.t, err)
+	return tasks
+}
+
+// truncate removes what the test created beyond what SetupTestDB cleans up
+func (h *Harness) truncate() {
+	for _, table := range tables {
+		if _, err := h.DB.Exec("TRUNCATE TABLE " + table + " CASCADE"); err != nil && !strings.Contains(err.Error(), "does not exist") {
+			h.t.Logf("Failed to truncate %s: %v", table, err)
+		}
+	}
+}
+
+// tlsProvider hands the WebSocket routes an empty TLS config, the httptest
+// server terminates TLS itself
+type tlsProvider struct{}
+
+func (p *tlsProvider) Initialize() error { return nil }
+
+func (p *tlsProvider) GetTLSConfig() (*cryptotls.Config, error) {
+	return &cryptotls.Config{MinVersion: cryptotls.VersionTLS12}, nil
+}
+
+func (p *tlsProvider) GetCACertPool() (*x509.CertPool, error) { return x509.NewCertPool(), nil }
+
+func (p *tlsProvider) ExportCACertificate() ([]byte, error) {
+	return nil, errors.New("not supported by the e2e TLS provider")
+}
+
+func (p *tlsProvider) GetClientCertificate() ([]byte, []byte, error) {
+	return nil, nil, errors.New("not supported by the e2e TLS provider")
+}
+
+func (p *tlsProvider) Cleanup() error { return nil }
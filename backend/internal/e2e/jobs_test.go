@@ -0,0 +1,172 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	wsservice "github.com/ZerkerEOD/krakenhashes/backend/internal/services/websocket"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/testutil/fakeagent"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jobTimeout bounds how long a test job may take to finish
+const jobTimeout = time.Minute
+
+// setupJob creates a hashlist of plains and a straight attack job over a
+// wordlist of keyspace words, chunked into chunks of 1000 words for agents
+// running at 100 H/s
+func setupJob(h *Harness, plains []string, keyspace int64) (uuid.UUID, *models.HashList) {
+	hashlist := h.CreateHashlist("e2e hashlist", plains)
+	jobID := h.CreateJob(JobOptions{
+		Name:         "e2e job",
+		HashlistID:   hashlist.ID,
+		WordlistID:   h.CreateWordlist("e2e.txt", int(keyspace)),
+		BinaryID:     h.CreateBinary(),
+		Keyspace:     keyspace,
+		Priority:     10,
+		ChunkSeconds: 10,
+	})
+	return jobID, hashlist
+}
+
+func waitForJob(h *Harness, jobID uuid.UUID) *models.JobExecution {
+	h.Eventually(func() bool {
+		status := h.Job(jobID).Status
+		return status == models.JobExecutionStatusCompleted || status == models.JobExecutionStatusFailed
+	}, jobTimeout, "the job finishes")
+	return h.Job(jobID)
+}
+
+// assertCovered checks the completed tasks cover the keyspace without gaps or overlaps
+func assertCovered(t *testing.T, tasks []models.JobTask, keyspace int64) {
+	t.Helper()
+
+	var completed []models.JobTask
+	for _, task := range tasks {
+		if task.Status == models.JobTaskStatusCompleted {
+			completed = append(completed, task)
+		}
+	}
+	require.NotEmpty(t, completed)
+	sort.Slice(completed, func(i, j int) bool { return completed[i].KeyspaceStart < completed[j].KeyspaceStart })
+
+	next := int64(0)
+	for _, task := range completed {
+		assert.Equal(t, next, task.KeyspaceStart, "chunk %d starts where the previous one ended", task.ChunkNumber)
+		next = task.KeyspaceEnd
+	}
+	assert.Equal(t, keyspace, next, "chunks cover the keyspace")
+}
+
+func TestJobRunsInChunks(t *testing.T) {
+	h := New(t)
+	h.Setting("max_concurrent_jobs_per_agent", "1")
+	jobID, _ := setupJob(h, []string{"secret"}, 3500)
+
+	agent := h.Agent("agent-1", fakeagent.Config{Speed: 100})
+
+	job := waitForJob(h, jobID)
+	assert.Equal(t, models.JobExecutionStatusCompleted, job.Status)
+
+	tasks := h.Tasks(jobID)
+	assert.GreaterOrEqual(t, len(tasks), 3, "the keyspace is split into chunks")
+	assertCovered(t, tasks, 3500)
+	assert.Equal(t, 1, agent.Received(wsservice.TypeBenchmarkRequest), "the benchmark is reused for later chunks")
+	assert.Equal(t, len(tasks), agent.Received(wsservice.TypeTaskAssignment))
+}
+
+func TestChunksAreSpreadAcrossAgents(t *testing.T) {
+	h := New(t)
+	h.Setting("max_concurrent_jobs_per_agent", "1")
+	jobID, _ := setupJob(h, []string{"secret"}, 8000)
+
+	// Slow progress keeps the first agent busy while the second one connects
+	config := fakeagent.Config{Speed: 100, ProgressSteps: 10, ProgressInterval: 200 * time.Millisecond}
+	first := h.Agent("agent-1", config)
+	second := h.Agent("agent-2", config)
+
+	job := waitForJob(h, jobID)
+	assert.Equal(t, models.JobExecutionStatusCompleted, job.Status)
+	assertCovered(t, h.Tasks(jobID), 8000)
+	assert.NotEmpty(t, first.Tasks(), "agent-1 ran chunks")
+	assert.NotEmpty(t, second.Tasks(), "agent-2 ran chunks")
+}
+
+func TestCracksAreIngested(t *testing.T) {
+	h := New(t)
+	plains := []string{"password", "letmein", "dragon", "monkey", "sunshine"}
+	jobID, hashlist := setupJob(h, plains, 2000)
+
+	h.Agent("agent-1", fakeagent.Config{
+		Speed:  100,
+		Cracks: []models.CrackedHash{Crack("password"), Crack("dragon"), Crack("sunshine")},
+	})
+
+	job := waitForJob(h, jobID)
+	assert.Equal(t, models.JobExecutionStatusCompleted, job.Status)
+	h.Eventually(func() bool { return h.CrackedCount(hashlist.ID) == 3 }, jobTimeout, "the cracks are stored")
+
+	var crackCount int
+	for _, task := range h.Tasks(jobID) {
+		crackCount += task.CrackCount
+	}
+	assert.Equal(t, 3, crackCount)
+}
+
+func TestTaskResumesAfterReconnect(t *testing.T) {
+	h := New(t)
+	h.Setting("max_concurrent_jobs_per_agent", "1")
+	jobID, _ := setupJob(h, []string{"secret"}, 1000)
+
+	agent := h.Agent("agent-1", fakeagent.Config{Speed: 100, ProgressSteps: 20, ProgressInterval: 100 * time.Millisecond})
+	h.Eventually(func() bool {
+		tasks := agent.Tasks()
+		return len(tasks) == 1 && tasks[0].Processed > 0
+	}, jobTimeout, "the agent is running a task")
+
+	agent.Disconnect()
+	taskID := agent.Tasks()[0].Assignment.TaskID
+	time.Sleep(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, agent.Connect(ctx))
+
+	job := waitForJob(h, jobID)
+	assert.Equal(t, models.JobExecutionStatusCompleted, job.Status)
+	tasks := h.Tasks(jobID)
+	require.Len(t, tasks, 1, "the interrupted task was resumed, not reassigned")
+	assert.Equal(t, taskID, tasks[0].ID.String())
+	assertCovered(t, tasks, 1000)
+}
+
+func TestLostTaskIsReassigned(t *testing.T) {
+	h := New(t)
+	h.Setting("max_concurrent_jobs_per_agent", "1")
+	jobID, _ := setupJob(h, []string{"secret"}, 1000)
+
+	agent := h.Agent("agent-1", fakeagent.Config{Speed: 100, ProgressSteps: 20, ProgressInterval: 100 * time.Millisecond})
+	h.Eventually(func() bool {
+		tasks := agent.Tasks()
+		return len(tasks) == 1 && tasks[0].Processed > 0
+	}, jobTimeout, "the agent is running a task")
+
+	// The agent restarts and no longer knows about its task
+	agent.Disconnect()
+	agent.Forget()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, agent.Connect(ctx))
+
+	job := waitForJob(h, jobID)
+	assert.Equal(t, models.JobExecutionStatusCompleted, job.Status)
+	assert.GreaterOrEqual(t, len(agent.Tasks()), 2, "the lost chunk was assigned again")
+	assertCovered(t, h.Tasks(jobID), 1000)
+}
@@ -0,0 +1,256 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	// batchSize is the number of events claimed at a time
+	batchSize = 100
+	// pollInterval catches events whose notification was missed, and is the
+	// only delivery mechanism while the listener is disconnected
+	pollInterval = 15 * time.Second
+	// handlerTimeout bounds how long the subscribers of one event may run
+	handlerTimeout = 2 * time.Minute
+	// claimTimeout is how long a claimed event stays claimed before another
+	// backend may take it over, e.g. after the claiming backend crashed
+	claimTimeout = 5 * time.Minute
+	// maxAttempts is how many times an event is handled before giving up
+	maxAttempts = 5
+	// retryBackoff is multiplied by the attempt number to delay a retry
+	retryBackoff = 30 * time.Second
+	// defaultRetentionDays applies when domain_event_retention_days is missing or invalid
+	defaultRetentionDays = 7
+)
+
+// Handler reacts to an event. Handlers must be idempotent: if any subscriber
+// of an event fails, all of its subscribers run again on the retry.
+type Handler func(ctx context.Context, event *Event) error
+
+type subscription struct {
+	name    string
+	handler Handler
+}
+
+// Bus dispatches published events to the subscribers registered on this backend
+type Bus struct {
+	db                 *db.DB
+	connInfo           string
+	instance           string
+	systemSettingsRepo *repository.SystemSettingsRepository
+
+	mu            sync.RWMutex
+	subscriptions map[Type][]subscription
+}
+
+// NewBus creates an event bus. connInfo is the Postgres connection string used
+// for the dedicated LISTEN connection.
+func NewBus(database *db.DB, connInfo string) *Bus {
+	hostname, _ := os.Hostname()
+	return &Bus{
+		db:                 database,
+		connInfo:           connInfo,
+		instance:           fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8]),
+		systemSettingsRepo: repository.NewSystemSettingsRepository(database),
+		subscriptions:      make(map[Type][]subscription),
+	}
+}
+
+// Subscribe registers a handler for an event type. name identifies the
+// subscriber in logs.
+func (b *Bus) Subscribe(eventType Type, name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions[eventType] = append(b.subscriptions[eventType], subscription{name: name, handler: handler})
+	debug.Debug("Event bus: %s subscribed to %s", name, eventType)
+}
+
+// Start begins dispatching events until ctx is cancelled
+func (b *Bus) Start(ctx context.Context) {
+	wake := make(chan struct{}, 1)
+	go b.listen(ctx, wake)
+	go b.run(ctx, wake)
+	debug.Info("Event bus started as %s", b.instance)
+}
+
+// listen wakes the dispatcher whenever an event is published. A nil
+// notification follows a reconnect, when notifications may have been missed.
+func (b *Bus) listen(ctx context.Context, wake chan<- struct{}) {
+	listener := pq.NewListener(b.connInfo, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			debug.Warning("Event bus listener: %v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(Channel); err != nil {
+		debug.Warning("Event bus could not listen on %s, relying on polling every %s: %v", Channel, pollInterval, err)
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-listener.Notify:
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (b *Bus) run(ctx context.Context, wake <-chan struct{}) {
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	purge := time.NewTicker(time.Hour)
+	defer purge.Stop()
+
+	b.dispatchPending(ctx)
+	b.purgeHandled(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			debug.Info("Event bus stopped")
+			return
+		case <-wake:
+			b.dispatchPending(ctx)
+		case <-poll.C:
+			b.dispatchPending(ctx)
+		case <-purge.C:
+			b.purgeHandled(ctx)
+		}
+	}
+}
+
+// dispatchPending handles claimable events until none are left
+func (b *Bus) dispatchPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimed, err := b.claim(ctx)
+		if err != nil {
+			debug.Error("Event bus failed to claim events: %v", err)
+			return
+		}
+		for i := range claimed {
+			b.handle(ctx, &claimed[i])
+		}
+		if len(claimed) < batchSize {
+			return
+		}
+	}
+}
+
+// claim takes a batch of events that are due and not being handled by another backend
+func (b *Bus) claim(ctx context.Context) ([]Event, error) {
+	query := `
+		UPDATE domain_events
+		SET claimed_by = $1, claimed_at = NOW(), attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM domain_events
+			WHERE processed_at IS NULL
+			  AND available_at <= NOW()
+			  AND (claimed_at IS NULL OR claimed_at < NOW() - make_interval(secs => $2))
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, payload, created_at, attempts`
+
+	rows, err := b.db.QueryContext(ctx, query, b.instance, claimTimeout.Seconds(), batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claimed []Event
+	for rows.Next() {
+		var event Event
+		var eventType string
+		if err := rows.Scan(&event.ID, &eventType, &event.Payload, &event.CreatedAt, &event.Attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		event.Type = Type(eventType)
+		claimed = append(claimed, event)
+	}
+	return claimed, rows.Err()
+}
+
+// handle runs an event's subscribers and records the outcome
+func (b *Bus) handle(ctx context.Context, event *Event) {
+	b.mu.RLock()
+	subs := b.subscriptions[event.Type]
+	b.mu.RUnlock()
+
+	handlerCtx, cancel := context.WithTimeout(ctx, handlerTimeout)
+	defer cancel()
+
+	var failure error
+	for _, sub := range subs {
+		if err := callHandler(handlerCtx, sub.handler, event); err != nil {
+			debug.Error("Event bus: %s failed to handle %s event %d (attempt %d): %v", sub.name, event.Type, event.ID, event.Attempts, err)
+			failure = fmt.Errorf("%s: %w", sub.name, err)
+		}
+	}
+
+	var err error
+	switch {
+	case failure == nil:
+		_, err = b.db.ExecContext(ctx,
+			`UPDATE domain_events SET processed_at = NOW(), last_error = NULL WHERE id = $1`, event.ID)
+	case event.Attempts >= maxAttempts:
+		debug.Warning("Event bus: giving up on %s event %d after %d attempts", event.Type, event.ID, event.Attempts)
+		_, err = b.db.ExecContext(ctx,
+			`UPDATE domain_events SET processed_at = NOW(), last_error = $2 WHERE id = $1`, event.ID, failure.Error())
+	default:
+		retryAt := time.Now().Add(time.Duration(event.Attempts) * retryBackoff)
+		_, err = b.db.ExecContext(ctx,
+			`UPDATE domain_events SET claimed_by = NULL, claimed_at = NULL, available_at = $2, last_error = $3 WHERE id = $1`,
+			event.ID, retryAt, failure.Error())
+	}
+	if err != nil {
+		debug.Error("Event bus failed to record outcome of event %d: %v", event.ID, err)
+	}
+}
+
+// callHandler runs a handler, turning a panic into an error
+func callHandler(ctx context.Context, handler Handler, event *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
+
+// purgeHandled deletes handled events older than the retention period
+func (b *Bus) purgeHandled(ctx context.Context) {
+	days := defaultRetentionDays
+	if setting, err := b.systemSettingsRepo.GetSetting(ctx, "domain_event_retention_days"); err == nil && setting.Value != nil {
+		if v, err := strconv.Atoi(*setting.Value); err == nil && v > 0 {
+			days = v
+		}
+	}
+
+	result, err := b.db.ExecContext(ctx,
+		`DELETE FROM domain_events WHERE processed_at < NOW() - make_interval(days => $1)`, days)
+	if err != nil {
+		debug.Error("Event bus failed to purge handled events: %v", err)
+		return
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		debug.Info("Event bus purged %d handled events older than %d days", n, days)
+	}
+}
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBus(t *testing.T) (*Bus, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	return &Bus{
+		db:            &db.DB{DB: mockDB},
+		instance:      "test",
+		subscriptions: make(map[Type][]subscription),
+	}, mock
+}
+
+func TestPublish(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	taskID := uuid.New()
+	mock.ExpectExec("INSERT INTO domain_events").
+		WithArgs(string(TaskCompleted), sqlmock.AnyArg(), Channel).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = Publish(context.Background(), mockDB, TaskCompleted, TaskCompletedPayload{TaskID: taskID})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEventDecode(t *testing.T) {
+	event := &Event{ID: 1, Type: AgentOffline, Payload: []byte(`{"agent_id":7,"reason":"disconnected"}`)}
+
+	var payload AgentOfflinePayload
+	require.NoError(t, event.Decode(&payload))
+	assert.Equal(t, 7, payload.AgentID)
+	assert.Equal(t, "disconnected", payload.Reason)
+
+	event.Payload = []byte(`not json`)
+	assert.Error(t, event.Decode(&payload))
+}
+
+func TestBusHandle(t *testing.T) {
+	t.Run("success marks the event processed", func(t *testing.T) {
+		bus, mock := newTestBus(t)
+		var handled []string
+		bus.Subscribe(JobCompleted, "first", func(ctx context.Context, event *Event) error {
+			handled = append(handled, "first")
+			return nil
+		})
+		bus.Subscribe(JobCompleted, "second", func(ctx context.Context, event *Event) error {
+			handled = append(handled, "second")
+			return nil
+		})
+
+		mock.ExpectExec("UPDATE domain_events SET processed_at = NOW\\(\\), last_error = NULL").
+			WithArgs(int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		bus.handle(context.Background(), &Event{ID: 1, Type: JobCompleted, Attempts: 1})
+		assert.Equal(t, []string{"first", "second"}, handled)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure releases the event for a retry", func(t *testing.T) {
+		bus, mock := newTestBus(t)
+		bus.Subscribe(HashCracked, "failing", func(ctx context.Context, event *Event) error {
+			return errors.New("boom")
+		})
+
+		mock.ExpectExec("UPDATE domain_events SET claimed_by = NULL, claimed_at = NULL, available_at = \\$2, last_error = \\$3").
+			WithArgs(int64(2), sqlmock.AnyArg(), "failing: boom").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		bus.handle(context.Background(), &Event{ID: 2, Type: HashCracked, Attempts: 1})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("last attempt gives up and records the error", func(t *testing.T) {
+		bus, mock := newTestBus(t)
+		bus.Subscribe(HashCracked, "panicking", func(ctx context.Context, event *Event) error {
+			panic("unexpected")
+		})
+
+		mock.ExpectExec("UPDATE domain_events SET processed_at = NOW\\(\\), last_error = \\$2").
+			WithArgs(int64(3), "panicking: panic: unexpected").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		bus.handle(context.Background(), &Event{ID: 3, Type: HashCracked, Attempts: maxAttempts})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("events without subscribers are marked processed", func(t *testing.T) {
+		bus, mock := newTestBus(t)
+
+		mock.ExpectExec("UPDATE domain_events SET processed_at = NOW\\(\\), last_error = NULL").
+			WithArgs(int64(4)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		bus.handle(context.Background(), &Event{ID: 4, Type: AgentOffline, Attempts: 1})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
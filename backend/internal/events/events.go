@@ -0,0 +1,152 @@
+// Package events provides the internal event bus. Services publish domain
+// events (a task completed, hashes were cracked, an agent went offline) to an
+// outbox table and other services react to them through subscriptions,
+// instead of calling each other inline. Events are delivered with Postgres
+// LISTEN/NOTIFY and each one is handled by exactly one backend, so the bus
+// works unchanged with several backend replicas.
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type names a kind of domain event
+type Type string
+
+const (
+	// TaskCompleted is published when an agent finishes a job task
+	TaskCompleted Type = "task_completed"
+	// JobCompleted is published when a job execution completes
+	JobCompleted Type = "job_completed"
+	// HashCracked is published when a batch of cracked hashes has been stored
+	HashCracked Type = "hash_cracked"
+	// AgentOffline is published when a connected agent goes offline
+	AgentOffline Type = "agent_offline"
+	// JobSuperseded is published when a queued job is dropped because its
+	// hashlist was fully cracked before it started
+	JobSuperseded Type = "job_superseded"
+	// PrivilegedAccountCracked is published when the hash of an account in a
+	// privileged group is cracked for the first time
+	PrivilegedAccountCracked Type = "privileged_account_cracked"
+	// ChunkVerificationFailed is published when a chunk re-run on another
+	// agent recovers hashes its original agent missed
+	ChunkVerificationFailed Type = "chunk_verification_failed"
+	// BinaryIntegrityFailed is published when an agent's hashcat binary does
+	// not match the hash recorded by the binary manager
+	BinaryIntegrityFailed Type = "binary_integrity_failed"
+)
+
+// Channel is the Postgres NOTIFY channel used to wake up event dispatchers
+const Channel = "kh_domain_events"
+
+// Event is a published domain event
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      Type            `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	Attempts  int             `json:"attempts"`
+}
+
+// Decode unmarshals the event payload into v
+func (e *Event) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("failed to decode %s event %d: %w", e.Type, e.ID, err)
+	}
+	return nil
+}
+
+// TaskCompletedPayload is the payload of a TaskCompleted event
+type TaskCompletedPayload struct {
+	TaskID         uuid.UUID `json:"task_id"`
+	JobExecutionID uuid.UUID `json:"job_execution_id"`
+	AgentID        *int      `json:"agent_id,omitempty"`
+}
+
+// JobCompletedPayload is the payload of a JobCompleted event
+type JobCompletedPayload struct {
+	JobExecutionID uuid.UUID  `json:"job_execution_id"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty"`
+}
+
+// HashCrackedPayload is the payload of a HashCracked event
+type HashCrackedPayload struct {
+	TaskID         uuid.UUID `json:"task_id"`
+	JobExecutionID uuid.UUID `json:"job_execution_id"`
+	HashlistID     int64     `json:"hashlist_id"`
+	Count          int       `json:"count"`
+}
+
+// JobSupersededPayload is the payload of a JobSuperseded event
+type JobSupersededPayload struct {
+	JobExecutionID uuid.UUID  `json:"job_execution_id"`
+	HashlistID     int64      `json:"hashlist_id"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty"`
+}
+
+// PrivilegedAccountCrackedPayload is the payload of a PrivilegedAccountCracked event
+type PrivilegedAccountCrackedPayload struct {
+	HashlistID   int64     `json:"hashlist_id"`
+	HashlistName string    `json:"hashlist_name"`
+	OwnerID      uuid.UUID `json:"owner_id"`
+	Accounts     []string  `json:"accounts"` // DOMAIN\user of the cracked accounts
+}
+
+// ChunkVerificationFailedPayload is the payload of a ChunkVerificationFailed event
+type ChunkVerificationFailedPayload struct {
+	VerificationID   int64     `json:"verification_id"`
+	TaskID           uuid.UUID `json:"task_id"`
+	JobExecutionID   uuid.UUID `json:"job_execution_id"`
+	AgentID          *int      `json:"agent_id,omitempty"`
+	AgentName        string    `json:"agent_name"`
+	VerifierAgentID  *int      `json:"verifier_agent_id,omitempty"`
+	MissedCracks     int       `json:"missed_cracks"`
+	AgentQuarantined bool      `json:"agent_quarantined"`
+}
+
+// BinaryIntegrityFailedPayload is the payload of a BinaryIntegrityFailed event
+type BinaryIntegrityFailedPayload struct {
+	TaskID         uuid.UUID `json:"task_id"`
+	JobExecutionID uuid.UUID `json:"job_execution_id"`
+	AgentID        int       `json:"agent_id"`
+	AgentName      string    `json:"agent_name"`
+	Executable     string    `json:"executable"`
+	Expected       string    `json:"expected"`
+	Actual         string    `json:"actual"`
+}
+
+// AgentOfflinePayload is the payload of an AgentOffline event
+type AgentOfflinePayload struct {
+	AgentID int    `json:"agent_id"`
+	Reason  string `json:"reason"`
+}
+
+// Execer is satisfied by *sql.DB, *sql.Tx and *db.DB
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Publish records an event in the outbox and notifies listening backends.
+// When exec is a transaction the event is only delivered if it commits.
+func Publish(ctx context.Context, exec Execer, eventType Type, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	query := `
+		WITH inserted AS (
+			INSERT INTO domain_events (event_type, payload) VALUES ($1, $2) RETURNING id
+		)
+		SELECT pg_notify($3, id::text) FROM inserted`
+	if _, err := exec.ExecContext(ctx, query, string(eventType), data, Channel); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", eventType, err)
+	}
+	return nil
+}
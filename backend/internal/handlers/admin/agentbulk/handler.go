@@ -0,0 +1,245 @@
+package agentbulk
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles admin requests that act on many agents at once
+type Handler struct {
+	service *services.AgentBulkService
+}
+
+// NewHandler creates a new bulk agent handler
+func NewHandler(service *services.AgentBulkService) *Handler {
+	return &Handler{service: service}
+}
+
+// Apply handles POST /admin/agents/bulk
+func (h *Handler) Apply(w http.ResponseWriter, r *http.Request) {
+	var req models.BulkAgentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := h.service.Apply(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBulkRequest) {
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		debug.Error("Failed to apply bulk agent action %s: %v", req.Action, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to apply bulk agent action")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// UpdateLabelsRequest is the body of PUT /admin/agents/{id}/labels
+type UpdateLabelsRequest struct {
+	Labels []string `json:"labels"`
+}
+
+// UpdateLabels handles PUT /admin/agents/{id}/labels, replacing the agent's labels
+func (h *Handler) UpdateLabels(w http.ResponseWriter, r *http.Request) {
+	agentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid agent ID")
+		return
+	}
+
+	var req UpdateLabelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	labels, err := h.service.UpdateLabels(r.Context(), agentID, req.Labels)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidBulkRequest):
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, sql.ErrNoRows):
+			httputil.RespondWithError(w, http.StatusNotFound, "Agent not found")
+		default:
+			debug.Error("Failed to update labels of agent %d: %v", agentID, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to update agent labels")
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"id":     agentID,
+		"labels": labels,
+	})
+}
+
+// UpdateWorkloadRequest is the body of PUT /admin/agents/{id}/workload
+type UpdateWorkloadRequest struct {
+	WorkloadClass   models.WorkloadClass `json:"workload_class"`
+	WorkloadProfile *int                 `json:"workload_profile"`
+}
+
+// UpdateWorkload handles PUT /admin/agents/{id}/workload, setting whether the
+// agent is a shared workstation or a dedicated rig
+func (h *Handler) UpdateWorkload(w http.ResponseWriter, r *http.Request) {
+	agentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid agent ID")
+		return
+	}
+
+	var req UpdateWorkloadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.UpdateWorkload(r.Context(), agentID, req.WorkloadClass, req.WorkloadProfile); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidBulkRequest):
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, sql.ErrNoRows):
+			httputil.RespondWithError(w, http.StatusNotFound, "Agent not found")
+		default:
+			debug.Error("Failed to update workload of agent %d: %v", agentID, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to update agent workload")
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"id":               agentID,
+		"workload_class":   req.WorkloadClass,
+		"workload_profile": req.WorkloadProfile,
+	})
+}
+
+// UpdateConfig handles PUT /admin/agents/{id}/config, replacing the settings
+// pushed to the agent. The body is the full set; omitted fields use the
+// defaults.
+func (h *Handler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
+	agentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid agent ID")
+		return
+	}
+
+	var config models.AgentManagedConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.UpdateManagedConfig(r.Context(), agentID, config); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidBulkRequest):
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, sql.ErrNoRows):
+			httputil.RespondWithError(w, http.StatusNotFound, "Agent not found")
+		default:
+			debug.Error("Failed to update config of agent %d: %v", agentID, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to update agent configuration")
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"id":             agentID,
+		"managed_config": config,
+	})
+}
+
+// StartFileAudit handles POST /admin/agents/{id}/file-audit, making the agent
+// rehash every cached file. Drifted files are corrected once it reports.
+func (h *Handler) StartFileAudit(w http.ResponseWriter, r *http.Request) {
+	agentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid agent ID")
+		return
+	}
+
+	audit, err := h.service.StartFileAudit(r.Context(), agentID)
+	if err != nil {
+		if errors.Is(err, services.ErrAgentNotConnected) {
+			httputil.RespondWithError(w, http.StatusConflict, "Agent is not connected")
+			return
+		}
+		debug.Error("Failed to start file audit of agent %d: %v", agentID, err)
+		httputil.RespondWithError(w, http.StatusServiceUnavailable, "Failed to start file audit")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusAccepted, audit)
+}
+
+// GetFileAudit handles GET /admin/agents/{id}/file-audit, returning the
+// agent's latest file audit report
+func (h *Handler) GetFileAudit(w http.ResponseWriter, r *http.Request) {
+	agentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid agent ID")
+		return
+	}
+
+	audit := h.service.FileAudit(agentID)
+	if audit == nil {
+		httputil.RespondWithError(w, http.StatusNotFound, "No file audit of this agent")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, audit)
+}
+
+// RotateCredentials handles POST /admin/agents/{id}/credential-rotation,
+// sending a connected agent a new API key and client certificate without it
+// reconnecting. The rotation is confirmed once the agent stored them.
+func (h *Handler) RotateCredentials(w http.ResponseWriter, r *http.Request) {
+	agentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid agent ID")
+		return
+	}
+
+	rotation, err := h.service.RotateCredentials(r.Context(), agentID)
+	if err != nil {
+		if errors.Is(err, services.ErrAgentNotConnected) {
+			httputil.RespondWithError(w, http.StatusConflict, "Agent is not connected")
+			return
+		}
+		debug.Error("Failed to rotate credentials of agent %d: %v", agentID, err)
+		httputil.RespondWithError(w, http.StatusServiceUnavailable, "Failed to rotate credentials")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusAccepted, rotation)
+}
+
+// GetCredentialRotation handles GET /admin/agents/{id}/credential-rotation,
+// returning the agent's latest credential rotation
+func (h *Handler) GetCredentialRotation(w http.ResponseWriter, r *http.Request) {
+	agentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid agent ID")
+		return
+	}
+
+	rotation := h.service.CredentialRotation(agentID)
+	if rotation == nil {
+		httputil.RespondWithError(w, http.StatusNotFound, "No credential rotation of this agent")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, rotation)
+}
@@ -0,0 +1,168 @@
+package binaryrollouts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Defaults of a rollout request leaving out its limits
+const (
+	defaultObservationHours       = 24
+	defaultMinTasks               = 10
+	defaultMaxFailureRateIncrease = 5
+	defaultMaxSpeedRegression     = 10
+)
+
+// Handler handles admin requests for staged binary rollouts
+type Handler struct {
+	service *services.BinaryRolloutService
+}
+
+// NewHandler creates a new binary rollout handler
+func NewHandler(service *services.BinaryRolloutService) *Handler {
+	return &Handler{service: service}
+}
+
+// RolloutRequest is the body of POST /admin/binary-rollouts. Left out, the
+// previous version is the current default and the limits use their defaults.
+type RolloutRequest struct {
+	BinaryVersionID        int      `json:"binary_version_id"`
+	PreviousVersionID      int      `json:"previous_version_id,omitempty"`
+	CanaryLabel            string   `json:"canary_label"`
+	ObservationHours       int      `json:"observation_hours,omitempty"`
+	MinTasks               int      `json:"min_tasks,omitempty"`
+	MaxFailureRateIncrease *float64 `json:"max_failure_rate_increase,omitempty"`
+	MaxSpeedRegression     *float64 `json:"max_speed_regression,omitempty"`
+}
+
+// DecisionRequest is the optional body of the promote and rollback endpoints
+type DecisionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ListRollouts handles GET /admin/binary-rollouts
+func (h *Handler) ListRollouts(w http.ResponseWriter, r *http.Request) {
+	rollouts, err := h.service.ListRollouts(r.Context())
+	if err != nil {
+		h.respondWithError(w, err, "list")
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, rollouts)
+}
+
+// GetRollout handles GET /admin/binary-rollouts/{id}
+func (h *Handler) GetRollout(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid binary rollout ID")
+		return
+	}
+
+	rollout, err := h.service.GetRollout(r.Context(), id)
+	if err != nil {
+		h.respondWithError(w, err, "get")
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// StartRollout handles POST /admin/binary-rollouts
+func (h *Handler) StartRollout(w http.ResponseWriter, r *http.Request) {
+	var req RolloutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rollout := &models.BinaryRollout{
+		BinaryVersionID:        req.BinaryVersionID,
+		PreviousVersionID:      req.PreviousVersionID,
+		CanaryLabel:            req.CanaryLabel,
+		ObservationHours:       req.ObservationHours,
+		MinTasks:               req.MinTasks,
+		MaxFailureRateIncrease: defaultMaxFailureRateIncrease,
+		MaxSpeedRegression:     defaultMaxSpeedRegression,
+	}
+	if rollout.ObservationHours == 0 {
+		rollout.ObservationHours = defaultObservationHours
+	}
+	if rollout.MinTasks == 0 {
+		rollout.MinTasks = defaultMinTasks
+	}
+	if req.MaxFailureRateIncrease != nil {
+		rollout.MaxFailureRateIncrease = *req.MaxFailureRateIncrease
+	}
+	if req.MaxSpeedRegression != nil {
+		rollout.MaxSpeedRegression = *req.MaxSpeedRegression
+	}
+	if userIDStr, ok := r.Context().Value("user_id").(string); ok {
+		if userID, err := uuid.Parse(userIDStr); err == nil {
+			rollout.CreatedBy = &userID
+		}
+	}
+
+	if err := h.service.StartRollout(r.Context(), rollout); err != nil {
+		h.respondWithError(w, err, "start")
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusCreated, rollout)
+}
+
+// PromoteRollout handles POST /admin/binary-rollouts/{id}/promote
+func (h *Handler) PromoteRollout(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, "promote", h.service.PromoteRollout)
+}
+
+// RollBackRollout handles POST /admin/binary-rollouts/{id}/rollback
+func (h *Handler) RollBackRollout(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, "roll back", h.service.RollBackRollout)
+}
+
+func (h *Handler) decide(w http.ResponseWriter, r *http.Request, action string,
+	decide func(ctx context.Context, id int, reason string) (*models.BinaryRollout, error)) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid binary rollout ID")
+		return
+	}
+
+	var req DecisionRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	rollout, err := decide(r.Context(), id, req.Reason)
+	if err != nil {
+		h.respondWithError(w, err, action)
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+func (h *Handler) respondWithError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidBinaryRollout):
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrDuplicateRecord):
+		httputil.RespondWithError(w, http.StatusConflict, "Another binary rollout is in progress")
+	case errors.Is(err, repository.ErrNotFound):
+		httputil.RespondWithError(w, http.StatusNotFound, "Binary rollout not found")
+	default:
+		debug.Error("Failed to %s binary rollout: %v", action, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to "+action+" binary rollout")
+	}
+}
@@ -0,0 +1,81 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	bundlesvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/bundle"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+)
+
+// Handler handles admin requests for deployment bundles
+type Handler struct {
+	service *bundlesvc.BundleService
+}
+
+// NewHandler creates a new bundle handler
+func NewHandler(service *bundlesvc.BundleService) *Handler {
+	return &Handler{service: service}
+}
+
+// GetPublicKey handles GET /admin/bundle/public-key
+func (h *Handler) GetPublicKey(w http.ResponseWriter, r *http.Request) {
+	key, err := h.service.PublicKey()
+	if err != nil {
+		debug.Error("Failed to load bundle signing key: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to load bundle signing key")
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]string{"public_key": key})
+}
+
+// Export handles POST /admin/bundle/export. The body selects the content,
+// an empty body exports everything. The bundle is streamed as a tar file.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	var opts bundlesvc.ExportOptions
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil && !errors.Is(err, io.EOF) {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	filename := fmt.Sprintf("krakenhashes-bundle-%s.tar", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+
+	// Once streaming has started the status can no longer change, the
+	// importing server rejects a truncated bundle since its manifest is last
+	if err := h.service.Export(r.Context(), w, opts); err != nil {
+		debug.Error("Failed to export bundle: %v", err)
+	}
+}
+
+// Import handles POST /admin/bundle/import with the bundle as the request body
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(fmt.Sprint(r.Context().Value("user_id")))
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	result, err := h.service.Import(r.Context(), r.Body, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, bundlesvc.ErrImportRunning):
+			httputil.RespondWithError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, bundlesvc.ErrUntrustedKey):
+			httputil.RespondWithError(w, http.StatusForbidden, "Bundle is not signed by a trusted key, add the exporting server's public key to bundle_trusted_keys")
+		default:
+			debug.Error("Failed to import bundle: %v", err)
+			httputil.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Failed to import bundle: %v", err))
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, result)
+}
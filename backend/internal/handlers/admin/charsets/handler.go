@@ -0,0 +1,113 @@
+package charsets
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatmask"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles admin requests for the custom charset library
+type Handler struct {
+	service *services.MaskService
+}
+
+// NewHandler creates a new custom charset handler
+func NewHandler(service *services.MaskService) *Handler {
+	return &Handler{service: service}
+}
+
+// CharsetRequest is the body of POST /admin/charsets and PUT /admin/charsets/{id}
+type CharsetRequest struct {
+	Name        string `json:"name"`
+	Charset     string `json:"charset"`
+	Description string `json:"description"`
+}
+
+// CreateCharset handles POST /admin/charsets
+func (h *Handler) CreateCharset(w http.ResponseWriter, r *http.Request) {
+	var req CharsetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	charset := &models.CustomCharset{Name: req.Name, Charset: req.Charset, Description: req.Description}
+	if userIDStr, ok := r.Context().Value("user_id").(string); ok {
+		if userID, err := uuid.Parse(userIDStr); err == nil {
+			charset.CreatedBy = &userID
+		}
+	}
+
+	if err := h.service.CreateCharset(r.Context(), charset); err != nil {
+		h.respondWithError(w, err, "create")
+		return
+	}
+
+	debug.Info("Created custom charset %q", charset.Name)
+	httputil.RespondWithJSON(w, http.StatusCreated, charset)
+}
+
+// UpdateCharset handles PUT /admin/charsets/{id}
+func (h *Handler) UpdateCharset(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid charset ID")
+		return
+	}
+
+	var req CharsetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	charset := &models.CustomCharset{ID: id, Name: req.Name, Charset: req.Charset, Description: req.Description}
+	if err := h.service.UpdateCharset(r.Context(), charset); err != nil {
+		h.respondWithError(w, err, "update")
+		return
+	}
+
+	debug.Info("Updated custom charset %d", id)
+	httputil.RespondWithJSON(w, http.StatusOK, charset)
+}
+
+// DeleteCharset handles DELETE /admin/charsets/{id}
+func (h *Handler) DeleteCharset(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid charset ID")
+		return
+	}
+
+	if err := h.service.DeleteCharset(r.Context(), id); err != nil {
+		h.respondWithError(w, err, "delete")
+		return
+	}
+
+	debug.Info("Deleted custom charset %d", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) respondWithError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCustomCharset), errors.Is(err, hashcatmask.ErrInvalidCharset):
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrDuplicateRecord):
+		httputil.RespondWithError(w, http.StatusConflict, "A custom charset with this name already exists")
+	case errors.Is(err, repository.ErrNotFound):
+		httputil.RespondWithError(w, http.StatusNotFound, "Custom charset not found")
+	default:
+		debug.Error("Failed to %s custom charset: %v", action, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to "+action+" custom charset")
+	}
+}
@@ -0,0 +1,55 @@
+package chunkverification
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+)
+
+// Handler lists the results of chunk verification
+type Handler struct {
+	repo *repository.ChunkVerificationRepository
+}
+
+// NewHandler creates a new chunk verification handler
+func NewHandler(repo *repository.ChunkVerificationRepository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// List handles GET /admin/chunk-verifications, the most recent sampled
+// chunks, optionally filtered with ?status=
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if val := r.URL.Query().Get("limit"); val != "" {
+		parsed, err := strconv.Atoi(val)
+		if err != nil || parsed < 1 || parsed > 500 {
+			httputil.RespondWithError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = parsed
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.ChunkVerificationPending, models.ChunkVerificationRunning, models.ChunkVerificationPassed,
+		models.ChunkVerificationMismatch, models.ChunkVerificationSkipped:
+	default:
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid status")
+		return
+	}
+
+	verifications, err := h.repo.List(r.Context(), status, limit)
+	if err != nil {
+		debug.Error("Failed to list chunk verifications: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list chunk verifications")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"verifications": verifications,
+	})
+}
@@ -4,10 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
-	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/client"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/trash"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
 	"github.com/google/uuid"
@@ -16,15 +19,19 @@ import (
 
 // ClientHandler handles API requests for admin client management.
 type ClientHandler struct {
-	clientRepo *repository.ClientRepository
-	clientSvc  *client.ClientService
+	clientRepo   *repository.ClientRepository
+	usageRepo    *repository.GPUUsageRepository
+	settingsRepo *repository.SystemSettingsRepository
+	trashSvc     *trash.TrashService
 }
 
 // NewClientHandler creates a new handler instance.
-func NewClientHandler(cr *repository.ClientRepository, cs *client.ClientService) *ClientHandler {
+func NewClientHandler(cr *repository.ClientRepository, ur *repository.GPUUsageRepository, sr *repository.SystemSettingsRepository, ts *trash.TrashService) *ClientHandler {
 	return &ClientHandler{
-		clientRepo: cr,
-		clientSvc:  cs,
+		clientRepo:   cr,
+		usageRepo:    ur,
+		settingsRepo: sr,
+		trashSvc:     ts,
 	}
 }
 
@@ -38,13 +45,62 @@ func NewClientHandler(cr *repository.ClientRepository, cs *client.ClientService)
 // @Router /admin/clients [get]
 // @Security ApiKeyAuth
 func (h *ClientHandler) ListClients(w http.ResponseWriter, r *http.Request) {
+	listQuery := httputil.ParseListQuery(r, 50, 500)
+
 	clients, err := h.clientRepo.ListWithCrackedCounts(r.Context())
 	if err != nil {
 		debug.Error("Failed to list clients with cracked counts: %v", err)
 		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve clients")
 		return
 	}
-	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"data": clients})
+
+	if name := listQuery.Filter(r, "name"); name != "" {
+		filtered := make([]models.Client, 0, len(clients))
+		for _, c := range clients {
+			if strings.Contains(strings.ToLower(c.Name), strings.ToLower(name)) {
+				filtered = append(filtered, c)
+			}
+		}
+		clients = filtered
+	}
+
+	if less, ok := clientSortFuncs[listQuery.Sort]; ok {
+		sort.SliceStable(clients, func(i, j int) bool {
+			if listQuery.SortDesc {
+				return less(clients[j], clients[i])
+			}
+			return less(clients[i], clients[j])
+		})
+	}
+
+	total := len(clients)
+	if listQuery.Paginated {
+		start, end := listQuery.PageBounds(total)
+		clients = clients[start:end]
+	}
+
+	data, err := httputil.SelectFields(clients, listQuery.Fields)
+	if err != nil {
+		debug.Error("Failed to select client fields: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve clients")
+		return
+	}
+
+	response := map[string]interface{}{"data": data}
+	if listQuery.Paginated {
+		response["pagination"] = httputil.NewPagination(listQuery, total)
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// clientSortFuncs maps the sort fields accepted by ListClients to comparison functions
+var clientSortFuncs = map[string]func(a, b models.Client) bool{
+	"name":       func(a, b models.Client) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) },
+	"created_at": func(a, b models.Client) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	"updated_at": func(a, b models.Client) bool { return a.UpdatedAt.Before(b.UpdatedAt) },
+	"cracked_count": func(a, b models.Client) bool {
+		return a.CrackedCount != nil && (b.CrackedCount == nil || *a.CrackedCount < *b.CrackedCount)
+	},
 }
 
 // CreateClient godoc
@@ -77,6 +133,10 @@ func (h *ClientHandler) CreateClient(w http.ResponseWriter, r *http.Request) {
 		httputil.RespondWithError(w, http.StatusBadRequest, "Data retention must be non-negative")
 		return
 	}
+	if err := newClient.ClientEngagement.Normalize(); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Set server-side fields
 	newClient.ID = uuid.New() // Generate new ID
@@ -170,6 +230,12 @@ func (h *ClientHandler) UpdateClient(w http.ResponseWriter, r *http.Request) {
 		httputil.RespondWithError(w, http.StatusBadRequest, "Data retention must be non-negative")
 		return
 	}
+	// Requests without an engagement status leave the engagement unchanged
+	keepEngagement := updates.EngagementStatus == ""
+	if err := updates.ClientEngagement.Normalize(); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Get existing client to preserve fields not being updated
 	// Note: Repo Update only changes specified fields in its query, but returning the full updated object is good practice.
@@ -191,6 +257,9 @@ func (h *ClientHandler) UpdateClient(w http.ResponseWriter, r *http.Request) {
 	client.ContactInfo = updates.ContactInfo
 	client.DataRetentionMonths = updates.DataRetentionMonths // Will be handled correctly by repo (sets NULL if pointer is nil)
 	client.ExcludeFromPotfile = updates.ExcludeFromPotfile
+	if !keepEngagement {
+		client.ClientEngagement = updates.ClientEngagement // Closing the engagement stops new jobs for the client
+	}
 	// UpdatedAt will be set by repository
 
 	err = h.clientRepo.Update(r.Context(), client)
@@ -215,7 +284,7 @@ func (h *ClientHandler) UpdateClient(w http.ResponseWriter, r *http.Request) {
 
 // DeleteClient godoc
 // @Summary Delete a client
-// @Description Removes a client and handles associated hashlists based on retention policy.
+// @Description Moves a client to the trash. Associated hashlists are handled based on retention policy once the client is purged.
 // @Tags Admin Clients
 // @Produce json
 // @Param id path string true "Client ID (UUID)"
@@ -233,8 +302,15 @@ func (h *ClientHandler) DeleteClient(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Use the ClientService for complex deletion logic
-	err = h.clientSvc.DeleteClient(r.Context(), clientID)
+	var deletedBy *uuid.UUID
+	if userIDStr, ok := r.Context().Value("user_id").(string); ok {
+		if userID, err := uuid.Parse(userIDStr); err == nil {
+			deletedBy = &userID
+		}
+	}
+
+	// Moves the client to the trash; the retention-aware deletion runs when it is purged
+	err = h.trashSvc.DeleteClient(r.Context(), clientID, deletedBy)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			httputil.RespondWithError(w, http.StatusNotFound, "Client not found")
@@ -248,3 +324,131 @@ func (h *ClientHandler) DeleteClient(w http.ResponseWriter, r *http.Request) {
 	debug.Info("Admin deleted client: %s", clientID)
 	httputil.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Client deleted successfully"})
 }
+
+// SetEngagementStatus godoc
+// @Summary Close or reopen a client's engagement
+// @Description Sets the engagement status of a client. While the engagement is closed no jobs can be created against the client's hashlists, and its hashlists are retained for the retention period counted from when it closed.
+// @Tags Admin Clients
+// @Accept json
+// @Produce json
+// @Param id path string true "Client ID (UUID)"
+// @Param body body object true "{\"status\": \"active\" | \"closed\"}"
+// @Success 200 {object} httputil.SuccessResponse{data=models.Client}
+// @Failure 400 {object} httputil.ErrorResponse // Invalid ID or status
+// @Failure 404 {object} httputil.ErrorResponse
+// @Failure 500 {object} httputil.ErrorResponse
+// @Router /clients/{id}/engagement [put]
+// @Security ApiKeyAuth
+func (h *ClientHandler) SetEngagementStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clientID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid client ID format")
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := httputil.ParseJSONBody(r, &req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if req.Status != models.ClientEngagementActive && req.Status != models.ClientEngagementClosed {
+		httputil.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Status must be %q or %q", models.ClientEngagementActive, models.ClientEngagementClosed))
+		return
+	}
+
+	if err := h.clientRepo.SetEngagementStatus(r.Context(), clientID, req.Status); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusNotFound, "Client not found")
+		} else {
+			debug.Error("Failed to set engagement status of client %s: %v", clientID, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to update client engagement")
+		}
+		return
+	}
+
+	client, err := h.clientRepo.GetByID(r.Context(), clientID)
+	if err != nil {
+		debug.Error("Failed to get client %s after setting its engagement status: %v", clientID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve client")
+		return
+	}
+
+	debug.Info("Client %s (ID: %s) engagement set to %s", client.Name, client.ID, req.Status)
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"data": client})
+}
+
+// GetClientCost godoc
+// @Summary Get a client's GPU cost report
+// @Description Returns the GPU-hours spent on each of the client's jobs and their estimated cost at the configured gpu_hour_cost. The optional from and to query parameters (RFC 3339) limit the report to jobs with usage in that range.
+// @Tags Admin Clients
+// @Produce json
+// @Param id path string true "Client ID (UUID)"
+// @Param from query string false "Start of the range (RFC 3339)"
+// @Param to query string false "End of the range (RFC 3339)"
+// @Success 200 {object} httputil.SuccessResponse{data=models.ClientCostReport}
+// @Failure 400 {object} httputil.ErrorResponse // Invalid ID or range
+// @Failure 404 {object} httputil.ErrorResponse
+// @Failure 500 {object} httputil.ErrorResponse
+// @Router /clients/{id}/cost [get]
+// @Security ApiKeyAuth
+func (h *ClientHandler) GetClientCost(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clientID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid client ID format")
+		return
+	}
+
+	from, err := parseTimeParam(r, "from")
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	to, err := parseTimeParam(r, "to")
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	client, err := h.clientRepo.GetByIDIncludingDeleted(r.Context(), clientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusNotFound, "Client not found")
+		} else {
+			debug.Error("Failed to get client %s: %v", clientID, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve client")
+		}
+		return
+	}
+
+	jobs, err := h.usageRepo.GetClientJobUsage(r.Context(), clientID, from, to)
+	if err != nil {
+		debug.Error("Failed to get GPU usage for client %s: %v", clientID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve client GPU usage")
+		return
+	}
+
+	gpuHourCost, err := h.settingsRepo.GetGPUHourCost(r.Context())
+	if err != nil {
+		debug.Error("Failed to get gpu_hour_cost setting: %v", err)
+	}
+
+	report := models.NewClientCostReport(client, from, to, jobs, gpuHourCost)
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"data": report})
+}
+
+// parseTimeParam parses an optional RFC 3339 query parameter, nil if it is absent
+func parseTimeParam(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s time, expected RFC 3339", name)
+	}
+	return &parsed, nil
+}
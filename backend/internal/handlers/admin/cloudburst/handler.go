@@ -0,0 +1,77 @@
+package cloudburst
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	cloudburstsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/cloudburst"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles listing and terminating cloud burst instances
+type Handler struct {
+	service *cloudburstsvc.Service
+}
+
+// NewHandler creates a new cloud burst handler
+func NewHandler(service *cloudburstsvc.Service) *Handler {
+	return &Handler{service: service}
+}
+
+// instancesResponse lists the recent instances with this month's spend
+type instancesResponse struct {
+	Instances     []models.CloudBurstInstance `json:"instances"`
+	MonthSpend    float64                     `json:"month_spend"`
+	MonthlyBudget float64                     `json:"monthly_budget"`
+}
+
+// ListInstances handles GET /admin/cloud-burst/instances
+func (h *Handler) ListInstances(w http.ResponseWriter, r *http.Request) {
+	instances, err := h.service.ListInstances(r.Context())
+	if err != nil {
+		debug.Error("Failed to list cloud burst instances: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list cloud burst instances")
+		return
+	}
+	spend, budget, err := h.service.MonthSpend(r.Context())
+	if err != nil {
+		debug.Error("Failed to get cloud burst spend: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get cloud burst spend")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, instancesResponse{
+		Instances:     instances,
+		MonthSpend:    spend,
+		MonthlyBudget: budget,
+	})
+}
+
+// Terminate handles POST /admin/cloud-burst/instances/{id}/terminate
+func (h *Handler) Terminate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
+	if err := h.service.Terminate(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			httputil.RespondWithError(w, http.StatusNotFound, "Cloud burst instance not found")
+		case errors.Is(err, cloudburstsvc.ErrNotActive), errors.Is(err, cloudburstsvc.ErrNoProvider):
+			httputil.RespondWithError(w, http.StatusConflict, err.Error())
+		default:
+			debug.Error("Failed to terminate cloud burst instance %d: %v", id, err)
+			httputil.RespondWithError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,55 @@
+package clusterhealth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	clusterhealthsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/clusterhealth"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+)
+
+// Handler serves the cluster health summary to admins and monitoring systems
+type Handler struct {
+	service *clusterhealthsvc.ClusterHealthService
+}
+
+// NewHandler creates a new cluster health handler
+func NewHandler(service *clusterhealthsvc.ClusterHealthService) *Handler {
+	return &Handler{service: service}
+}
+
+// Get handles GET /admin/cluster/health
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	health, err := h.service.Get(r.Context())
+	if err != nil {
+		debug.Error("Failed to build cluster health: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to build cluster health")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, health)
+}
+
+// Scrape handles GET /cluster/health for monitoring systems. It is
+// authenticated with the cluster_health_token bearer token instead of a user
+// session.
+func (h *Handler) Scrape(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	health, err := h.service.Scrape(r.Context(), token)
+	if err != nil {
+		switch {
+		case errors.Is(err, clusterhealthsvc.ErrScrapeDisabled):
+			httputil.RespondWithError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, clusterhealthsvc.ErrUnauthorized):
+			httputil.RespondWithError(w, http.StatusUnauthorized, err.Error())
+		default:
+			debug.Error("Failed to build cluster health: %v", err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to build cluster health")
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, health)
+}
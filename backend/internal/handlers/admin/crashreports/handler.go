@@ -0,0 +1,103 @@
+package crashreports
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles admin review of the crash reports agents send
+type Handler struct {
+	service *services.AgentService
+}
+
+// NewHandler creates a new crash report handler
+func NewHandler(service *services.AgentService) *Handler {
+	return &Handler{service: service}
+}
+
+// List handles GET /admin/agent-crash-reports. Only unreviewed reports are
+// returned unless ?all=true is given.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	unreviewedOnly := r.URL.Query().Get("all") != "true"
+
+	limit := 100
+	if val := r.URL.Query().Get("limit"); val != "" {
+		parsed, err := strconv.Atoi(val)
+		if err != nil || parsed < 1 || parsed > 500 {
+			httputil.RespondWithError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = parsed
+	}
+
+	reports, err := h.service.ListCrashReports(r.Context(), unreviewedOnly, limit)
+	if err != nil {
+		debug.Error("Failed to list agent crash reports: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list crash reports")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"reports": reports,
+	})
+}
+
+// Get handles GET /admin/agent-crash-reports/{id}, returning the full bundle
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid crash report ID")
+		return
+	}
+
+	report, err := h.service.GetCrashReport(r.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		httputil.RespondWithError(w, http.StatusNotFound, "Crash report not found")
+		return
+	}
+	if err != nil {
+		debug.Error("Failed to get agent crash report %d: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get crash report")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, report)
+}
+
+// MarkReviewed handles POST /admin/agent-crash-reports/{id}/review
+func (h *Handler) MarkReviewed(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid crash report ID")
+		return
+	}
+
+	adminIDStr, _ := r.Context().Value("user_id").(string)
+	adminID, err := uuid.Parse(adminIDStr)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	err = h.service.MarkCrashReportReviewed(r.Context(), id, adminID)
+	if errors.Is(err, repository.ErrNotFound) {
+		httputil.RespondWithError(w, http.StatusNotFound, "Crash report not found")
+		return
+	}
+	if err != nil {
+		debug.Error("Failed to mark agent crash report %d reviewed: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to mark crash report reviewed")
+		return
+	}
+
+	debug.Info("Admin %s reviewed agent crash report %d", adminID, id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,25 @@
+package hashcatexecs
+
+import (
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+)
+
+// Handler shows admins the hashcat runs the backend makes itself, such as
+// keyspace calculations
+type Handler struct {
+	limiter *services.HashcatExecLimiter
+}
+
+// NewHandler creates a new hashcat run status handler
+func NewHandler(limiter *services.HashcatExecLimiter) *Handler {
+	return &Handler{limiter: limiter}
+}
+
+// GetStatus handles GET /admin/hashcat-execs, listing the runs holding a slot
+// and those waiting for one with their place in the queue
+func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	httputil.RespondWithJSON(w, http.StatusOK, h.limiter.Status())
+}
@@ -0,0 +1,153 @@
+package jobarchive
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles admin requests for archiving and restoring job executions
+type Handler struct {
+	archiveService *services.JobArchiveService
+}
+
+// NewHandler creates a new job archive handler
+func NewHandler(archiveService *services.JobArchiveService) *Handler {
+	return &Handler{archiveService: archiveService}
+}
+
+// ListArchives handles GET /admin/job-archives
+func (h *Handler) ListArchives(w http.ResponseWriter, r *http.Request) {
+	listQuery := httputil.ParseListQuery(r, 50, 500)
+
+	var hashlistID *int64
+	if v := listQuery.Filter(r, "hashlist_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid hashlist_id")
+			return
+		}
+		hashlistID = &id
+	}
+
+	archives, total, err := h.archiveService.ListArchives(r.Context(), hashlistID, listQuery.Limit(), listQuery.Offset())
+	if err != nil {
+		debug.Error("Failed to list job archives: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list job archives")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data":       archives,
+		"pagination": httputil.NewPagination(listQuery, total),
+	})
+}
+
+// GetArchive handles GET /admin/job-archives/{id}
+func (h *Handler) GetArchive(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	archive, err := h.archiveService.GetArchive(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusNotFound, "Archived job not found")
+			return
+		}
+		debug.Error("Failed to get job archive %s: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get archived job")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, archive)
+}
+
+// GetStats handles GET /admin/job-archives/stats
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.archiveService.GetStats(r.Context())
+	if err != nil {
+		debug.Error("Failed to get job archive stats: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get archive statistics")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, stats)
+}
+
+// ArchiveJob handles POST /admin/jobs/{id}/archive
+func (h *Handler) ArchiveJob(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	var archivedBy *uuid.UUID
+	if adminIDStr, ok := r.Context().Value("user_id").(string); ok {
+		if adminID, err := uuid.Parse(adminIDStr); err == nil {
+			archivedBy = &adminID
+		}
+	}
+
+	archive, err := h.archiveService.ArchiveJob(r.Context(), id, archivedBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			httputil.RespondWithError(w, http.StatusNotFound, "Job not found")
+		case errors.Is(err, repository.ErrJobNotArchivable):
+			httputil.RespondWithError(w, http.StatusConflict, "Only completed, failed or cancelled jobs can be archived")
+		default:
+			debug.Error("Failed to archive job %s: %v", id, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to archive job")
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, archive)
+}
+
+// RestoreJob handles POST /admin/job-archives/{id}/restore
+func (h *Handler) RestoreJob(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	if err := h.archiveService.RestoreJob(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			httputil.RespondWithError(w, http.StatusNotFound, "Archived job not found")
+		case errors.Is(err, repository.ErrArchiveHashlistMissing):
+			httputil.RespondWithError(w, http.StatusConflict, "The hashlist for this job has been deleted or moved to the trash")
+		default:
+			debug.Error("Failed to restore job %s: %v", id, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to restore job")
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Job restored"})
+}
+
+// RunArchive handles POST /admin/job-archives/run, triggering an archive pass immediately
+func (h *Handler) RunArchive(w http.ResponseWriter, r *http.Request) {
+	archived, err := h.archiveService.RunArchive(r.Context())
+	if err != nil {
+		debug.Error("Manual job archive pass failed: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to run job archival")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]int{"archived": archived})
+}
@@ -0,0 +1,71 @@
+package jobparams
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatargs"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles admin requests for per-job hashcat parameters
+type Handler struct {
+	jobExecRepo *repository.JobExecutionRepository
+}
+
+// NewHandler creates a new job parameters handler
+func NewHandler(jobExecRepo *repository.JobExecutionRepository) *Handler {
+	return &Handler{jobExecRepo: jobExecRepo}
+}
+
+// UpdateExtraParametersRequest is the body of PUT /admin/jobs/{id}/extra-parameters
+type UpdateExtraParametersRequest struct {
+	ExtraParameters string `json:"extra_parameters"`
+}
+
+// UpdateExtraParameters handles PUT /admin/jobs/{id}/extra-parameters. The
+// parameters apply to tasks dispatched after the update; an empty value clears them.
+func (h *Handler) UpdateExtraParameters(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	var req UpdateExtraParametersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var extraParameters *string
+	if params := strings.Join(strings.Fields(req.ExtraParameters), " "); params != "" {
+		if err := hashcatargs.Validate(params); err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		extraParameters = &params
+	}
+
+	if err := h.jobExecRepo.UpdateExtraParameters(r.Context(), id, extraParameters); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusNotFound, "Job not found")
+			return
+		}
+		debug.Error("Failed to update extra parameters of job %s: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to update extra parameters")
+		return
+	}
+
+	debug.Info("Updated extra parameters of job %s", id)
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"id":               id,
+		"extra_parameters": extraParameters,
+	})
+}
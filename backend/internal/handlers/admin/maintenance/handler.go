@@ -0,0 +1,134 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+)
+
+// Handler handles system-wide maintenance mode
+type Handler struct {
+	service *services.MaintenanceService
+}
+
+// NewHandler creates a new maintenance handler
+func NewHandler(service *services.MaintenanceService) *Handler {
+	return &Handler{service: service}
+}
+
+// Status handles GET /maintenance, telling every user whether maintenance
+// mode is active and the banner message to show
+func (h *Handler) Status(w http.ResponseWriter, r *http.Request) {
+	window, err := h.service.Active(r.Context())
+	if err != nil {
+		debug.Error("Failed to get maintenance status: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get maintenance status")
+		return
+	}
+
+	status := models.MaintenanceStatus{Active: window != nil}
+	if window != nil {
+		// Users see the banner, not who started the window
+		status.Window = &models.MaintenanceWindow{
+			ID:                  window.ID,
+			Message:             window.Message,
+			DrainTimeoutSeconds: window.DrainTimeoutSeconds,
+			StartedAt:           window.StartedAt,
+			ForceStoppedAt:      window.ForceStoppedAt,
+		}
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, status)
+}
+
+// List handles GET /admin/maintenance, the audit trail of maintenance windows
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if val := r.URL.Query().Get("limit"); val != "" {
+		parsed, err := strconv.Atoi(val)
+		if err != nil || parsed < 1 || parsed > 500 {
+			httputil.RespondWithError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = parsed
+	}
+
+	windows, err := h.service.List(r.Context(), limit)
+	if err != nil {
+		debug.Error("Failed to list maintenance windows: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list maintenance windows")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"windows": windows,
+	})
+}
+
+// Start handles POST /admin/maintenance, entering maintenance mode
+func (h *Handler) Start(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := adminFromRequest(r)
+	if !ok {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.StartMaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	window, err := h.service.Start(r.Context(), req, adminID)
+	if errors.Is(err, services.ErrMaintenanceActive) {
+		httputil.RespondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		debug.Error("Failed to start maintenance: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to start maintenance")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusCreated, window)
+}
+
+// End handles DELETE /admin/maintenance, leaving maintenance mode
+func (h *Handler) End(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := adminFromRequest(r)
+	if !ok {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	window, err := h.service.End(r.Context(), adminID)
+	if errors.Is(err, repository.ErrNotFound) {
+		httputil.RespondWithError(w, http.StatusNotFound, "Maintenance mode is not active")
+		return
+	}
+	if err != nil {
+		debug.Error("Failed to end maintenance: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to end maintenance")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, window)
+}
+
+// adminFromRequest returns the ID of the admin making the request
+func adminFromRequest(r *http.Request) (uuid.UUID, bool) {
+	adminIDStr, _ := r.Context().Value("user_id").(string)
+	adminID, err := uuid.Parse(adminIDStr)
+	return adminID, err == nil
+}
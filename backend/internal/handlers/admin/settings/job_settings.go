@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
@@ -37,6 +38,11 @@ type JobExecutionSettings struct {
 	JobsPerPageDefault               int    `json:"jobs_per_page_default"`
 	SpeedtestTimeoutSeconds          int    `json:"speedtest_timeout_seconds"`
 	ReconnectGracePeriodMinutes      int    `json:"reconnect_grace_period_minutes"`
+	// Task staleness settings
+	TaskHeartbeatTimeoutMinutes         int `json:"task_heartbeat_timeout_minutes"`
+	SlowHashTaskHeartbeatTimeoutMinutes int `json:"slow_hash_task_heartbeat_timeout_minutes"`
+	TaskHeartbeatMaxGraceDoublings      int `json:"task_heartbeat_max_grace_doublings"`
+	AgentHeartbeatTimeoutSeconds        int `json:"agent_heartbeat_timeout_seconds"`
 	// Rule splitting settings
 	RuleSplitEnabled   bool    `json:"rule_split_enabled"`
 	RuleSplitThreshold float64 `json:"rule_split_threshold"`
@@ -45,6 +51,10 @@ type JobExecutionSettings struct {
 	RuleChunkTempDir   string  `json:"rule_chunk_temp_dir"`
 	// Potfile settings
 	PotfileEnabled bool `json:"potfile_enabled"`
+	// Job naming settings
+	JobNameTemplate string `json:"job_name_template"`
+	// Default hashcat arguments rules
+	DefaultHashcatArgs []models.DefaultHashcatArgsRule `json:"default_hashcat_args"`
 }
 
 // GetJobExecutionSettings returns all job execution settings
@@ -67,6 +77,11 @@ func (h *JobSettingsHandler) GetJobExecutionSettings(w http.ResponseWriter, r *h
 		"jobs_per_page_default",
 		"speedtest_timeout_seconds",
 		"reconnect_grace_period_minutes",
+		// Task staleness settings
+		"task_heartbeat_timeout_minutes",
+		"slow_hash_task_heartbeat_timeout_minutes",
+		"task_heartbeat_max_grace_doublings",
+		"agent_heartbeat_timeout_seconds",
 		// Rule splitting settings
 		"rule_split_enabled",
 		"rule_split_threshold",
@@ -75,6 +90,10 @@ func (h *JobSettingsHandler) GetJobExecutionSettings(w http.ResponseWriter, r *h
 		"rule_chunk_temp_dir",
 		// Potfile settings
 		"potfile_enabled",
+		// Job naming settings
+		models.JobNameTemplateSetting,
+		// Default hashcat arguments rules
+		models.DefaultHashcatArgsSetting,
 	}
 
 	settings := JobExecutionSettings{
@@ -92,6 +111,11 @@ func (h *JobSettingsHandler) GetJobExecutionSettings(w http.ResponseWriter, r *h
 		JobsPerPageDefault:               25,
 		SpeedtestTimeoutSeconds:          30,
 		ReconnectGracePeriodMinutes:      5, // 5 minutes default
+		// Task staleness defaults
+		TaskHeartbeatTimeoutMinutes:         5,
+		SlowHashTaskHeartbeatTimeoutMinutes: 15,
+		TaskHeartbeatMaxGraceDoublings:      3,
+		AgentHeartbeatTimeoutSeconds:        90,
 		// Rule splitting defaults
 		RuleSplitEnabled:   true,
 		RuleSplitThreshold: 2.0,
@@ -100,6 +124,10 @@ func (h *JobSettingsHandler) GetJobExecutionSettings(w http.ResponseWriter, r *h
 		RuleChunkTempDir:   "/data/krakenhashes/temp/rule_chunks",
 		// Potfile defaults
 		PotfileEnabled: true,
+		// Job naming defaults
+		JobNameTemplate: models.DefaultJobNameTemplate,
+		// Default hashcat arguments defaults
+		DefaultHashcatArgs: []models.DefaultHashcatArgsRule{},
 	}
 
 	// Retrieve each setting
@@ -164,6 +192,22 @@ func (h *JobSettingsHandler) GetJobExecutionSettings(w http.ResponseWriter, r *h
 				if val, err := strconv.Atoi(*setting.Value); err == nil {
 					settings.ReconnectGracePeriodMinutes = val
 				}
+			case "task_heartbeat_timeout_minutes":
+				if val, err := strconv.Atoi(*setting.Value); err == nil {
+					settings.TaskHeartbeatTimeoutMinutes = val
+				}
+			case "slow_hash_task_heartbeat_timeout_minutes":
+				if val, err := strconv.Atoi(*setting.Value); err == nil {
+					settings.SlowHashTaskHeartbeatTimeoutMinutes = val
+				}
+			case "task_heartbeat_max_grace_doublings":
+				if val, err := strconv.Atoi(*setting.Value); err == nil {
+					settings.TaskHeartbeatMaxGraceDoublings = val
+				}
+			case "agent_heartbeat_timeout_seconds":
+				if val, err := strconv.Atoi(*setting.Value); err == nil {
+					settings.AgentHeartbeatTimeoutSeconds = val
+				}
 			case "rule_split_enabled":
 				settings.RuleSplitEnabled = *setting.Value == "true"
 			case "rule_split_threshold":
@@ -182,6 +226,12 @@ func (h *JobSettingsHandler) GetJobExecutionSettings(w http.ResponseWriter, r *h
 				settings.RuleChunkTempDir = *setting.Value
 			case "potfile_enabled":
 				settings.PotfileEnabled = *setting.Value == "true"
+			case models.JobNameTemplateSetting:
+				settings.JobNameTemplate = *setting.Value
+			case models.DefaultHashcatArgsSetting:
+				if rules, err := models.ParseDefaultHashcatArgs(*setting.Value); err == nil {
+					settings.DefaultHashcatArgs = rules
+				}
 			}
 		}
 	}
@@ -200,6 +250,36 @@ func (h *JobSettingsHandler) UpdateJobExecutionSettings(w http.ResponseWriter, r
 		return
 	}
 
+	if settings.JobNameTemplate == "" {
+		settings.JobNameTemplate = models.DefaultJobNameTemplate
+	}
+	if err := models.ValidateJobNameTemplate(settings.JobNameTemplate); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if settings.TaskHeartbeatTimeoutMinutes < 1 || settings.SlowHashTaskHeartbeatTimeoutMinutes < 1 || settings.AgentHeartbeatTimeoutSeconds < 1 {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Heartbeat timeouts must be positive")
+		return
+	}
+	if settings.TaskHeartbeatMaxGraceDoublings < 0 || settings.TaskHeartbeatMaxGraceDoublings > 10 {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Max grace doublings must be between 0 and 10")
+		return
+	}
+
+	if settings.DefaultHashcatArgs == nil {
+		settings.DefaultHashcatArgs = []models.DefaultHashcatArgsRule{}
+	}
+	if err := models.ValidateDefaultHashcatArgs(settings.DefaultHashcatArgs); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defaultHashcatArgs, err := json.Marshal(settings.DefaultHashcatArgs)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to encode default hashcat arguments")
+		return
+	}
+
 	// Update each setting
 	updates := map[string]string{
 		"default_chunk_duration":              strconv.Itoa(settings.DefaultChunkDuration),
@@ -215,6 +295,11 @@ func (h *JobSettingsHandler) UpdateJobExecutionSettings(w http.ResponseWriter, r
 		"jobs_per_page_default":               strconv.Itoa(settings.JobsPerPageDefault),
 		"speedtest_timeout_seconds":           strconv.Itoa(settings.SpeedtestTimeoutSeconds),
 		"reconnect_grace_period_minutes":      strconv.Itoa(settings.ReconnectGracePeriodMinutes),
+		// Task staleness settings
+		"task_heartbeat_timeout_minutes":           strconv.Itoa(settings.TaskHeartbeatTimeoutMinutes),
+		"slow_hash_task_heartbeat_timeout_minutes": strconv.Itoa(settings.SlowHashTaskHeartbeatTimeoutMinutes),
+		"task_heartbeat_max_grace_doublings":       strconv.Itoa(settings.TaskHeartbeatMaxGraceDoublings),
+		"agent_heartbeat_timeout_seconds":          strconv.Itoa(settings.AgentHeartbeatTimeoutSeconds),
 		// Rule splitting settings
 		"rule_split_enabled":    strconv.FormatBool(settings.RuleSplitEnabled),
 		"rule_split_threshold":  strconv.FormatFloat(settings.RuleSplitThreshold, 'f', 1, 64),
@@ -223,6 +308,10 @@ func (h *JobSettingsHandler) UpdateJobExecutionSettings(w http.ResponseWriter, r
 		"rule_chunk_temp_dir":   settings.RuleChunkTempDir,
 		// Potfile settings
 		"potfile_enabled": strconv.FormatBool(settings.PotfileEnabled),
+		// Job naming settings
+		models.JobNameTemplateSetting: settings.JobNameTemplate,
+		// Default hashcat arguments rules
+		models.DefaultHashcatArgsSetting: string(defaultHashcatArgs),
 	}
 
 	for key, value := range updates {
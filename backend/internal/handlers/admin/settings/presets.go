@@ -0,0 +1,59 @@
+package settings
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/settingspresets"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/gorilla/mux"
+)
+
+// SettingsPresetHandler handles the tuning presets for deployment sizes
+type SettingsPresetHandler struct {
+	service *settingspresets.SettingsPresetService
+}
+
+// NewSettingsPresetHandler creates a new settings preset handler
+func NewSettingsPresetHandler(service *settingspresets.SettingsPresetService) *SettingsPresetHandler {
+	return &SettingsPresetHandler{service: service}
+}
+
+// ListPresets handles GET /api/admin/settings/presets
+func (h *SettingsPresetHandler) ListPresets(w http.ResponseWriter, r *http.Request) {
+	httputil.RespondWithJSON(w, http.StatusOK, h.service.List())
+}
+
+// PreviewPreset handles GET /api/admin/settings/presets/{id}/preview, what
+// applying the preset would change
+func (h *SettingsPresetHandler) PreviewPreset(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	changes, err := h.service.Preview(r.Context(), id)
+	if err != nil {
+		h.respondWithError(w, id, err)
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"changes": changes})
+}
+
+// ApplyPreset handles POST /api/admin/settings/presets/{id}/apply
+func (h *SettingsPresetHandler) ApplyPreset(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	changes, err := h.service.Apply(r.Context(), id)
+	if err != nil {
+		h.respondWithError(w, id, err)
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"changes": changes})
+}
+
+// respondWithError maps a preset service error to a response
+func (h *SettingsPresetHandler) respondWithError(w http.ResponseWriter, id string, err error) {
+	if errors.Is(err, settingspresets.ErrNotFound) {
+		httputil.RespondWithError(w, http.StatusNotFound, "Settings preset not found")
+		return
+	}
+	debug.Error("Settings preset %s failed: %v", id, err)
+	httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to process settings preset")
+}
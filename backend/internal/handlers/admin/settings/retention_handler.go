@@ -80,3 +80,123 @@ func (h *RetentionSettingsHandler) UpdateDefaultRetention(w http.ResponseWriter,
 	debug.Info("Default client data retention updated to %d months", months)
 	httputil.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Default retention setting updated successfully"})
 }
+
+// GetTrashRetention godoc
+// @Summary Get trash retention setting
+// @Description Retrieves how many days deleted hashlists, jobs and clients are kept in the trash before being purged.
+// @Tags Admin Settings
+// @Produce json
+// @Success 200 {object} httputil.SuccessResponse{data=models.ClientSetting}
+// @Failure 500 {object} httputil.ErrorResponse
+// @Router /admin/settings/trash [get]
+// @Security ApiKeyAuth
+func (h *RetentionSettingsHandler) GetTrashRetention(w http.ResponseWriter, r *http.Request) {
+	setting, err := h.repo.GetSetting(r.Context(), "trash_retention_days")
+	if err != nil {
+		debug.Error("Failed to get trash retention setting: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve trash retention setting")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"data": setting})
+}
+
+// UpdateTrashRetention godoc
+// @Summary Update trash retention setting
+// @Description Sets how many days deleted items stay in the trash. 0 makes deletions permanent immediately.
+// @Tags Admin Settings
+// @Accept json
+// @Produce json
+// @Param setting body models.ClientSetting true "Setting object with the new value in days (as string)"
+// @Success 200 {object} httputil.SuccessResponse
+// @Failure 400 {object} httputil.ErrorResponse
+// @Failure 500 {object} httputil.ErrorResponse
+// @Router /admin/settings/trash [put]
+// @Security ApiKeyAuth
+func (h *RetentionSettingsHandler) UpdateTrashRetention(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Value string `json:"value"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	days, err := strconv.Atoi(payload.Value)
+	if err != nil || days < 0 {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid trash retention value: must be a non-negative integer string")
+		return
+	}
+
+	valueStr := strconv.Itoa(days)
+	err = h.repo.SetSetting(r.Context(), "trash_retention_days", &valueStr)
+	if err != nil {
+		debug.Error("Failed to update trash retention setting: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to update trash retention setting")
+		return
+	}
+
+	debug.Info("Trash retention updated to %d days", days)
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Trash retention setting updated successfully"})
+}
+
+// GetHashlistStagingExpiry godoc
+// @Summary Get hashlist staging expiry setting
+// @Description Retrieves how many hours a staged hashlist upload waits for review before it is discarded.
+// @Tags Admin Settings
+// @Produce json
+// @Success 200 {object} httputil.SuccessResponse{data=models.ClientSetting}
+// @Failure 500 {object} httputil.ErrorResponse
+// @Router /admin/settings/hashlist-staging [get]
+// @Security ApiKeyAuth
+func (h *RetentionSettingsHandler) GetHashlistStagingExpiry(w http.ResponseWriter, r *http.Request) {
+	setting, err := h.repo.GetSetting(r.Context(), "hashlist_staging_expiry_hours")
+	if err != nil {
+		debug.Error("Failed to get hashlist staging expiry setting: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve hashlist staging expiry setting")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"data": setting})
+}
+
+// UpdateHashlistStagingExpiry godoc
+// @Summary Update hashlist staging expiry setting
+// @Description Sets how many hours staged hashlist uploads are kept. 0 keeps them until they are committed or discarded.
+// @Tags Admin Settings
+// @Accept json
+// @Produce json
+// @Param setting body models.ClientSetting true "Setting object with the new value in hours (as string)"
+// @Success 200 {object} httputil.SuccessResponse
+// @Failure 400 {object} httputil.ErrorResponse
+// @Failure 500 {object} httputil.ErrorResponse
+// @Router /admin/settings/hashlist-staging [put]
+// @Security ApiKeyAuth
+func (h *RetentionSettingsHandler) UpdateHashlistStagingExpiry(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Value string `json:"value"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	hours, err := strconv.Atoi(payload.Value)
+	if err != nil || hours < 0 {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid hashlist staging expiry value: must be a non-negative integer string")
+		return
+	}
+
+	valueStr := strconv.Itoa(hours)
+	err = h.repo.SetSetting(r.Context(), "hashlist_staging_expiry_hours", &valueStr)
+	if err != nil {
+		debug.Error("Failed to update hashlist staging expiry setting: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to update hashlist staging expiry setting")
+		return
+	}
+
+	debug.Info("Hashlist staging expiry updated to %d hours", hours)
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Hashlist staging expiry setting updated successfully"})
+}
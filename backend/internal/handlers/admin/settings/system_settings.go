@@ -184,6 +184,13 @@ func (h *SystemSettingsHandler) UpdateSetting(w http.ResponseWriter, r *http.Req
 	
 	debug.Info("Updating setting %s to value: %s", settingKey, request.Value)
 
+	if settingKey == models.JobNameTemplateSetting {
+		if err := models.ValidateJobNameTemplate(request.Value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Update the setting
 	err := h.systemSettingsRepo.UpdateSetting(r.Context(), settingKey, request.Value)
 	if err != nil {
@@ -0,0 +1,272 @@
+package support
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// defaultMaxSessionMinutes applies when support_session_max_minutes is missing or invalid
+const defaultMaxSessionMinutes = 60
+
+// Handler handles admin requests for starting, ending and auditing support sessions
+type Handler struct {
+	sessionRepo        *repository.SupportSessionRepository
+	userRepo           *repository.UserRepository
+	systemSettingsRepo *repository.SystemSettingsRepository
+}
+
+// NewHandler creates a new support session handler
+func NewHandler(sessionRepo *repository.SupportSessionRepository, userRepo *repository.UserRepository, systemSettingsRepo *repository.SystemSettingsRepository) *Handler {
+	return &Handler{
+		sessionRepo:        sessionRepo,
+		userRepo:           userRepo,
+		systemSettingsRepo: systemSettingsRepo,
+	}
+}
+
+// StartSessionRequest is the body of POST /admin/support-sessions
+type StartSessionRequest struct {
+	UserID          string `json:"userId"`
+	Reason          string `json:"reason"`
+	DurationMinutes int    `json:"durationMinutes"` // Defaults to the configured maximum
+}
+
+// sessionResponse adds the derived status to a support session
+type sessionResponse struct {
+	*models.SupportSession
+	Status string `json:"status"`
+}
+
+func newSessionResponse(session *models.SupportSession) sessionResponse {
+	return sessionResponse{SupportSession: session, Status: session.Status()}
+}
+
+// StartSession handles POST /admin/support-sessions
+func (h *Handler) StartSession(w http.ResponseWriter, r *http.Request) {
+	admin, ok := h.currentAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req StartSessionRequest
+	if err := httputil.ParseJSONBody(r, &req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		httputil.RespondWithError(w, http.StatusBadRequest, "A reason is required to start a support session")
+		return
+	}
+
+	maxMinutes := h.maxSessionMinutes(r)
+	if req.DurationMinutes == 0 {
+		req.DurationMinutes = maxMinutes
+	}
+	if req.DurationMinutes < 1 || req.DurationMinutes > maxMinutes {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Duration must be between 1 and "+strconv.Itoa(maxMinutes)+" minutes")
+		return
+	}
+
+	targetID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	target, err := h.userRepo.GetByID(r.Context(), targetID)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	// Support sessions never grant more than the admin already has
+	switch {
+	case target.ID == admin.ID:
+		httputil.RespondWithError(w, http.StatusBadRequest, "You cannot start a support session as yourself")
+		return
+	case target.Role != "user":
+		httputil.RespondWithError(w, http.StatusForbidden, "Support sessions can only be started for regular users")
+		return
+	case !target.AccountEnabled:
+		httputil.RespondWithError(w, http.StatusConflict, "User account is disabled")
+		return
+	}
+
+	ipAddress := clientIP(r)
+	userAgent := r.UserAgent()
+	session := &models.SupportSession{
+		AdminID:        &admin.ID,
+		AdminUsername:  admin.Username,
+		TargetUserID:   &target.ID,
+		TargetUsername: target.Username,
+		Reason:         req.Reason,
+		IPAddress:      &ipAddress,
+		UserAgent:      &userAgent,
+		ExpiresAt:      time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute),
+	}
+	if err := h.sessionRepo.Create(r.Context(), session); err != nil {
+		debug.Error("Failed to start support session: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to start support session")
+		return
+	}
+
+	debug.Info("Admin %s (%s) started support session %s as user %s (%s) until %s: %s",
+		admin.Username, admin.ID, session.ID, target.Username, target.ID, session.ExpiresAt.Format(time.RFC3339), session.Reason)
+
+	httputil.RespondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"data":   newSessionResponse(session),
+		"header": models.SupportSessionHeader,
+	})
+}
+
+// EndSession handles POST /admin/support-sessions/{id}/end
+func (h *Handler) EndSession(w http.ResponseWriter, r *http.Request) {
+	admin, ok := h.currentAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	sessionID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	session, err := h.sessionRepo.GetByID(r.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusNotFound, "Support session not found")
+			return
+		}
+		debug.Error("Failed to get support session %s: %v", sessionID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to end support session")
+		return
+	}
+	if !session.IsActive() {
+		httputil.RespondWithError(w, http.StatusConflict, "Support session is already "+session.Status())
+		return
+	}
+
+	if err := h.sessionRepo.End(r.Context(), sessionID, "ended by "+admin.Username); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusConflict, "Support session has already ended")
+			return
+		}
+		debug.Error("Failed to end support session %s: %v", sessionID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to end support session")
+		return
+	}
+
+	debug.Info("Admin %s (%s) ended support session %s", admin.Username, admin.ID, sessionID)
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Support session ended"})
+}
+
+// ListSessions handles GET /admin/support-sessions
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	listQuery := httputil.ParseListQuery(r, 50, 500)
+
+	sessions, total, err := h.sessionRepo.List(r.Context(), listQuery.Limit(), listQuery.Offset())
+	if err != nil {
+		debug.Error("Failed to list support sessions: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list support sessions")
+		return
+	}
+
+	data := make([]sessionResponse, len(sessions))
+	for i := range sessions {
+		data[i] = newSessionResponse(&sessions[i])
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data":       data,
+		"pagination": httputil.NewPagination(listQuery, total),
+	})
+}
+
+// GetSession handles GET /admin/support-sessions/{id}, returning the session and its audit trail
+func (h *Handler) GetSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	session, err := h.sessionRepo.GetByID(r.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusNotFound, "Support session not found")
+			return
+		}
+		debug.Error("Failed to get support session %s: %v", sessionID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get support session")
+		return
+	}
+
+	events, err := h.sessionRepo.GetEvents(r.Context(), sessionID)
+	if err != nil {
+		debug.Error("Failed to get events for support session %s: %v", sessionID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get support session")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data":   newSessionResponse(session),
+		"events": events,
+	})
+}
+
+// currentAdmin loads the admin making the request
+func (h *Handler) currentAdmin(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	adminIDStr, _ := r.Context().Value("user_id").(string)
+	adminID, err := uuid.Parse(adminIDStr)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	admin, err := h.userRepo.GetByID(r.Context(), adminID)
+	if err != nil {
+		debug.Error("Failed to get admin %s: %v", adminID, err)
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+	return admin, true
+}
+
+// maxSessionMinutes returns the longest a support session may last
+func (h *Handler) maxSessionMinutes(r *http.Request) int {
+	setting, err := h.systemSettingsRepo.GetSetting(r.Context(), "support_session_max_minutes")
+	if err != nil || setting.Value == nil {
+		return defaultMaxSessionMinutes
+	}
+	minutes, err := strconv.Atoi(*setting.Value)
+	if err != nil || minutes < 1 {
+		return defaultMaxSessionMinutes
+	}
+	return minutes
+}
+
+// clientIP returns the address the request came from, preferring proxy headers
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return realIP
+	}
+	if idx := strings.LastIndex(r.RemoteAddr, ":"); idx != -1 {
+		return r.RemoteAddr[:idx]
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,79 @@
+package systemevents
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+)
+
+// sortColumns whitelists the fields events can be sorted by
+var sortColumns = map[string]string{
+	"occurred_at": "occurred_at",
+	"severity":    "severity",
+	"category":    "category",
+	"event_type":  "event_type",
+}
+
+// Handler handles admin requests for the system event log
+type Handler struct {
+	service *services.SystemEventService
+}
+
+// NewHandler creates a new system event handler
+func NewHandler(service *services.SystemEventService) *Handler {
+	return &Handler{service: service}
+}
+
+// ListEvents handles GET /admin/system-events. Events can be filtered by
+// severity, category, event_type, entity_type and entity_id, searched with
+// q, and bounded by since and until, each an age such as 24h or an RFC3339 time.
+func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	listQuery := httputil.ParseListQuery(r, 50, 500)
+
+	filter := repository.SystemEventFilter{
+		Severity:   listQuery.Filter(r, "severity"),
+		Category:   listQuery.Filter(r, "category"),
+		EventType:  listQuery.Filter(r, "event_type"),
+		EntityType: listQuery.Filter(r, "entity_type"),
+		EntityID:   listQuery.Filter(r, "entity_id"),
+		Search:     listQuery.Filter(r, "q"),
+	}
+	if filter.Severity != "" && !models.IsValidSystemEventSeverity(filter.Severity) {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid severity, use info, warning or error")
+		return
+	}
+	now := time.Now()
+	if value := listQuery.Filter(r, "since"); value != "" {
+		since, err := httputil.ParseSince(value, now)
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid since: "+err.Error())
+			return
+		}
+		filter.Since = &since
+	}
+	if value := listQuery.Filter(r, "until"); value != "" {
+		until, err := httputil.ParseSince(value, now)
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid until: "+err.Error())
+			return
+		}
+		filter.Until = &until
+	}
+
+	events, total, err := h.service.ListEvents(r.Context(), filter, listQuery.OrderBy(sortColumns), listQuery.Limit(), listQuery.Offset())
+	if err != nil {
+		debug.Error("Failed to list system events: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list system events")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data":       events,
+		"pagination": httputil.NewPagination(listQuery, total),
+	})
+}
@@ -0,0 +1,131 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	telemetrysvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/telemetry"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// maxReportSize bounds the body of a received report
+const maxReportSize = 1 << 20
+
+// Handler handles publishing anonymized statistics and, on a collector,
+// receiving and comparing the reports of other instances
+type Handler struct {
+	service *telemetrysvc.TelemetryService
+}
+
+// NewHandler creates a new telemetry handler
+func NewHandler(service *telemetrysvc.TelemetryService) *Handler {
+	return &Handler{service: service}
+}
+
+// Preview handles GET /admin/telemetry/preview, returning the report that
+// would be published now so admins can see exactly what leaves the instance
+func (h *Handler) Preview(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.BuildReport(r.Context())
+	if err != nil {
+		debug.Error("Failed to build telemetry report: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to build telemetry report")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, report)
+}
+
+// Publish handles POST /admin/telemetry/publish, sending a report immediately
+func (h *Handler) Publish(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.Publish(r.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, telemetrysvc.ErrDisabled):
+			httputil.RespondWithError(w, http.StatusBadRequest, "Telemetry is disabled, set telemetry_enabled to true")
+		case errors.Is(err, telemetrysvc.ErrAirgapped):
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		default:
+			debug.Error("Failed to publish telemetry report: %v", err)
+			httputil.RespondWithError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, report)
+}
+
+// ListInstances handles GET /admin/telemetry/instances, returning the latest
+// report of every instance publishing to this collector
+func (h *Handler) ListInstances(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.service.ListInstances(r.Context())
+	if err != nil {
+		debug.Error("Failed to list telemetry instances: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list telemetry instances")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, reports)
+}
+
+// ListInstanceReports handles GET /admin/telemetry/instances/{id}/reports
+func (h *Handler) ListInstanceReports(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
+	limit := 30
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > 365 {
+			httputil.RespondWithError(w, http.StatusBadRequest, "limit must be between 1 and 365")
+			return
+		}
+	}
+
+	reports, err := h.service.ListInstanceReports(r.Context(), instanceID, limit)
+	if err != nil {
+		debug.Error("Failed to list telemetry reports of instance %s: %v", instanceID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list telemetry reports")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, reports)
+}
+
+// ReceiveReport handles POST /telemetry/reports from other instances. It is
+// authenticated with the collector's bearer token instead of a user session.
+func (h *Handler) ReceiveReport(w http.ResponseWriter, r *http.Request) {
+	var report models.TelemetryReport
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportSize)).Decode(&report); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	id, err := h.service.Receive(r.Context(), token, &report)
+	if err != nil {
+		switch {
+		case errors.Is(err, telemetrysvc.ErrCollectorDisabled):
+			httputil.RespondWithError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, telemetrysvc.ErrUnauthorized):
+			httputil.RespondWithError(w, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, telemetrysvc.ErrInvalidReport):
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		default:
+			debug.Error("Failed to store telemetry report: %v", err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to store telemetry report")
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusAccepted, map[string]int64{"id": id})
+}
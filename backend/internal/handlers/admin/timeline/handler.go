@@ -0,0 +1,127 @@
+package timeline
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+)
+
+// Period shown when from and to are left out, and the longest one allowed
+const (
+	defaultTimelinePeriod = 24 * time.Hour
+	maxTimelinePeriod     = 31 * 24 * time.Hour
+)
+
+// Handler handles admin requests for agent and job timelines
+type Handler struct {
+	repo *repository.TimelineRepository
+}
+
+// NewHandler creates a new timeline handler
+func NewHandler(repo *repository.TimelineRepository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// GetTimeline handles GET /admin/timeline, a Gantt chart of which tasks each
+// agent ran and what it did in between. from and to bound the period, each an
+// age such as 12h or an RFC3339 time, and default to the last day. agent_id
+// shows a single agent; job_id shows the agents that ran tasks of the job,
+// over the job's lifetime unless from or to are given.
+func (h *Handler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	now := time.Now()
+
+	var agentID *int
+	if value := query.Get("agent_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid agent_id")
+			return
+		}
+		agentID = &id
+	}
+
+	to, from := now, now.Add(-defaultTimelinePeriod)
+	var jobID *uuid.UUID
+	if value := query.Get("job_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid job_id")
+			return
+		}
+		jobID = &id
+
+		start, end, err := h.repo.GetJobPeriod(r.Context(), id)
+		if errors.Is(err, repository.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusNotFound, "Job not found")
+			return
+		}
+		if err != nil {
+			debug.Error("Failed to get period of job %s: %v", id, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get timeline")
+			return
+		}
+		from = start
+		if end != nil {
+			to = *end
+		}
+	}
+
+	for param, target := range map[string]*time.Time{"from": &from, "to": &to} {
+		if value := query.Get(param); value != "" {
+			t, err := httputil.ParseSince(value, now)
+			if err != nil {
+				httputil.RespondWithError(w, http.StatusBadRequest, "Invalid "+param+": "+err.Error())
+				return
+			}
+			*target = t
+		}
+	}
+	if to.After(now) {
+		to = now
+	}
+	if !to.After(from) {
+		httputil.RespondWithError(w, http.StatusBadRequest, "from must be before to")
+		return
+	}
+	if to.Sub(from) > maxTimelinePeriod {
+		httputil.RespondWithError(w, http.StatusBadRequest, "The timeline period cannot exceed 31 days")
+		return
+	}
+
+	timeline, err := h.buildTimeline(r, from, to, agentID, jobID)
+	if err != nil {
+		debug.Error("Failed to build timeline: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get timeline")
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, timeline)
+}
+
+func (h *Handler) buildTimeline(r *http.Request, from, to time.Time, agentID *int, jobID *uuid.UUID) (*models.Timeline, error) {
+	agents, err := h.repo.ListAgents(r.Context(), agentID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	agentIDs := make([]int, len(agents))
+	for i, agent := range agents {
+		agentIDs[i] = agent.ID
+	}
+
+	runs, err := h.repo.ListTaskRuns(r.Context(), agentIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := h.repo.ListActivities(r.Context(), agentIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return models.BuildTimeline(from, to, agents, runs, activities), nil
+}
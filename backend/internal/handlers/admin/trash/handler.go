@@ -0,0 +1,83 @@
+package trash
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	trashsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/trash"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles admin requests for browsing, restoring and purging the trash
+type Handler struct {
+	trashService *trashsvc.TrashService
+}
+
+// NewHandler creates a new trash handler
+func NewHandler(trashService *trashsvc.TrashService) *Handler {
+	return &Handler{trashService: trashService}
+}
+
+// ListTrash handles GET /admin/trash
+func (h *Handler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	listQuery := httputil.ParseListQuery(r, 50, 500)
+
+	itemType := models.TrashItemType(listQuery.Filter(r, "type"))
+	if itemType != "" && !itemType.IsValid() {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid type: must be hashlist, job or client")
+		return
+	}
+
+	items, total, err := h.trashService.List(r.Context(), itemType, listQuery.Limit(), listQuery.Offset())
+	if err != nil {
+		debug.Error("Failed to list trash: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list trash")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"data":       items,
+		"pagination": httputil.NewPagination(listQuery, total),
+	})
+}
+
+// RestoreItem handles POST /admin/trash/{type}/{id}/restore
+func (h *Handler) RestoreItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemType := models.TrashItemType(vars["type"])
+	if !itemType.IsValid() {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid type: must be hashlist, job or client")
+		return
+	}
+
+	if err := h.trashService.Restore(r.Context(), itemType, vars["id"]); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			httputil.RespondWithError(w, http.StatusNotFound, "Item not found in trash")
+		case errors.Is(err, repository.ErrTrashParentDeleted):
+			httputil.RespondWithError(w, http.StatusConflict, "The hashlist for this job is in the trash; restore it first")
+		default:
+			debug.Error("Failed to restore %s %s from trash: %v", itemType, vars["id"], err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to restore item")
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Item restored"})
+}
+
+// PurgeExpired handles POST /admin/trash/purge, purging expired items immediately
+func (h *Handler) PurgeExpired(w http.ResponseWriter, r *http.Request) {
+	purged, err := h.trashService.PurgeExpired(r.Context())
+	if err != nil {
+		debug.Error("Manual trash purge failed: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to purge trash")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]int{"purged": purged})
+}
@@ -0,0 +1,142 @@
+package wordlistcollections
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+// Handler handles admin requests for wordlist collections
+type Handler struct {
+	service *services.WordlistCollectionService
+}
+
+// NewHandler creates a new wordlist collection handler
+func NewHandler(service *services.WordlistCollectionService) *Handler {
+	return &Handler{service: service}
+}
+
+// CollectionRequest is the body of POST /admin/wordlist-collections and
+// PUT /admin/wordlist-collections/{id}
+type CollectionRequest struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	WordlistIDs []int64 `json:"wordlist_ids"`
+}
+
+// ListCollections handles GET /admin/wordlist-collections
+func (h *Handler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	collections, err := h.service.ListCollections(r.Context())
+	if err != nil {
+		h.respondWithError(w, err, "list")
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, collections)
+}
+
+// GetCollection handles GET /admin/wordlist-collections/{id}
+func (h *Handler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid wordlist collection ID")
+		return
+	}
+
+	collection, err := h.service.GetCollection(r.Context(), id)
+	if err != nil {
+		h.respondWithError(w, err, "get")
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, collection)
+}
+
+// CreateCollection handles POST /admin/wordlist-collections
+func (h *Handler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	var req CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	collection := &models.WordlistCollection{Name: req.Name, Description: req.Description, WordlistIDs: pq.Int64Array(req.WordlistIDs)}
+	if userIDStr, ok := r.Context().Value("user_id").(string); ok {
+		if userID, err := uuid.Parse(userIDStr); err == nil {
+			collection.CreatedBy = &userID
+		}
+	}
+
+	if err := h.service.CreateCollection(r.Context(), collection); err != nil {
+		h.respondWithError(w, err, "create")
+		return
+	}
+
+	debug.Info("Created wordlist collection %q with %d wordlists", collection.Name, len(collection.WordlistIDs))
+	httputil.RespondWithJSON(w, http.StatusCreated, collection)
+}
+
+// UpdateCollection handles PUT /admin/wordlist-collections/{id}
+func (h *Handler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid wordlist collection ID")
+		return
+	}
+
+	var req CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	collection := &models.WordlistCollection{ID: id, Name: req.Name, Description: req.Description, WordlistIDs: pq.Int64Array(req.WordlistIDs)}
+	if err := h.service.UpdateCollection(r.Context(), collection); err != nil {
+		h.respondWithError(w, err, "update")
+		return
+	}
+
+	debug.Info("Updated wordlist collection %d with %d wordlists", id, len(collection.WordlistIDs))
+	httputil.RespondWithJSON(w, http.StatusOK, collection)
+}
+
+// DeleteCollection handles DELETE /admin/wordlist-collections/{id}
+func (h *Handler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid wordlist collection ID")
+		return
+	}
+
+	if err := h.service.DeleteCollection(r.Context(), id); err != nil {
+		h.respondWithError(w, err, "delete")
+		return
+	}
+
+	debug.Info("Deleted wordlist collection %d", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) respondWithError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWordlistCollection):
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrDuplicateRecord):
+		httputil.RespondWithError(w, http.StatusConflict, "A wordlist collection with this name already exists")
+	case errors.Is(err, models.ErrResourceReferenced):
+		httputil.RespondWithError(w, http.StatusConflict, "The wordlist collection is used by preset jobs")
+	case errors.Is(err, repository.ErrNotFound):
+		httputil.RespondWithError(w, http.StatusNotFound, "Wordlist collection not found")
+	default:
+		debug.Error("Failed to %s wordlist collection: %v", action, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to "+action+" wordlist collection")
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"database/sql"
 	"encoding/json"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
 	"github.com/gorilla/mux"
 )
 
@@ -19,16 +23,31 @@ func NewAgentHandler(service *services.AgentService) *AgentHandler {
 	return &AgentHandler{service: service}
 }
 
+// agentSortFuncs maps the sort fields accepted by ListAgents to comparison functions
+var agentSortFuncs = map[string]func(a, b models.Agent) bool{
+	"id":             func(a, b models.Agent) bool { return a.ID < b.ID },
+	"name":           func(a, b models.Agent) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) },
+	"status":         func(a, b models.Agent) bool { return a.Status < b.Status },
+	"version":        func(a, b models.Agent) bool { return a.Version < b.Version },
+	"last_heartbeat": func(a, b models.Agent) bool { return a.LastHeartbeat.Before(b.LastHeartbeat) },
+	"created_at":     func(a, b models.Agent) bool { return a.CreatedAt.Before(b.CreatedAt) },
+}
+
 // ListAgents handles listing all agents
+// Supports the common list syntax (filter[...], sort, page/page_size, fields).
+// Without page parameters the full list is returned as a bare array for
+// backwards compatibility.
 func (h *AgentHandler) ListAgents(w http.ResponseWriter, r *http.Request) {
 	debug.Info("Listing agents")
 
+	listQuery := httputil.ParseListQuery(r, 50, 500)
+
 	// Parse query parameters into filters
 	filters := make(map[string]interface{})
-	if status := r.URL.Query().Get("status"); status != "" {
+	if status := listQuery.Filter(r, "status"); status != "" {
 		filters["status"] = status
 	}
-	if team := r.URL.Query().Get("team"); team != "" {
+	if team := listQuery.Filter(r, "team"); team != "" {
 		filters["team"] = team
 	}
 
@@ -39,9 +58,49 @@ func (h *AgentHandler) ListAgents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if name := listQuery.Filter(r, "name"); name != "" {
+		filtered := make([]models.Agent, 0, len(agents))
+		for _, agent := range agents {
+			if strings.Contains(strings.ToLower(agent.Name), strings.ToLower(name)) {
+				filtered = append(filtered, agent)
+			}
+		}
+		agents = filtered
+	}
+
+	if less, ok := agentSortFuncs[listQuery.Sort]; ok {
+		sort.SliceStable(agents, func(i, j int) bool {
+			if listQuery.SortDesc {
+				return less(agents[j], agents[i])
+			}
+			return less(agents[i], agents[j])
+		})
+	}
+
+	total := len(agents)
+	if listQuery.Paginated {
+		start, end := listQuery.PageBounds(total)
+		agents = agents[start:end]
+	}
+
+	data, err := httputil.SelectFields(agents, listQuery.Fields)
+	if err != nil {
+		debug.Error("failed to select agent fields: %v", err)
+		http.Error(w, "Failed to encode agents", http.StatusInternalServerError)
+		return
+	}
+
+	var response interface{} = data
+	if listQuery.Paginated {
+		response = map[string]interface{}{
+			"data":       data,
+			"pagination": httputil.NewPagination(listQuery, total),
+		}
+	}
+
 	// Set content type and encode response
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(agents); err != nil {
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		debug.Error("failed to encode agents: %v", err)
 		http.Error(w, "Failed to encode agents", http.StatusInternalServerError)
 		return
@@ -309,6 +368,30 @@ func (h *AgentHandler) GetAgentMetrics(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetAgentHeartbeats returns the agent's recent heartbeat history and flapping status
+func (h *AgentHandler) GetAgentHeartbeats(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	agentID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid agent ID", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.service.GetHeartbeatHistory(r.Context(), agentID)
+	if err != nil {
+		debug.Error("Failed to get heartbeat history for agent %d: %v", agentID, err)
+		http.Error(w, "Failed to get heartbeat history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		debug.Error("Failed to encode heartbeat history: %v", err)
+		http.Error(w, "Failed to encode heartbeat history", http.StatusInternalServerError)
+		return
+	}
+}
+
 // UpdateAgent updates agent settings
 func (h *AgentHandler) UpdateAgent(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
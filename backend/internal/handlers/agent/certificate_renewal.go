@@ -57,16 +57,10 @@ func (h *CertificateRenewalHandler) HandleCertificateRenewal(w http.ResponseWrit
 		return
 	}
 
-	// Get agent by ID
-	agent, err := h.agentRepo.GetByID(r.Context(), agentID)
-	if err != nil {
-		debug.Error("Agent not found: %v", err)
-		http.Error(w, "Agent not found", http.StatusNotFound)
-		return
-	}
-
-	// Validate API key
-	if agent.APIKey.String != apiKey {
+	// Validate API key, a key from an unconfirmed or recent credential
+	// rotation is accepted as well
+	agent, err := h.agentRepo.GetByAPIKey(r.Context(), apiKey)
+	if err != nil || agent.ID != agentID {
 		debug.Error("Invalid API key for agent %d", agentID)
 		http.Error(w, "Invalid API key", http.StatusUnauthorized)
 		return
@@ -264,4 +264,191 @@ func (h *SchedulingHandler) BulkUpdateSchedules(w http.ResponseWriter, r *http.R
 		"agentId":   agentID,
 		"schedules": updatedSchedules,
 	})
-}
\ No newline at end of file
+}
+// GetAgentPowerSettings handles GET /api/agents/{id}/power
+func (h *SchedulingHandler) GetAgentPowerSettings(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	agentID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid agent ID", http.StatusBadRequest)
+		return
+	}
+
+	// Check if agent exists
+	if _, err := h.agentRepo.GetByID(r.Context(), agentID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Agent not found", http.StatusNotFound)
+			return
+		}
+		debug.Error("Failed to get agent: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	windows, err := h.scheduleRepo.GetPowerWindowsByAgent(r.Context(), agentID)
+	if err != nil {
+		debug.Error("Failed to get power windows: %v", err)
+		http.Error(w, "Failed to get power windows", http.StatusInternalServerError)
+		return
+	}
+
+	budget, err := h.scheduleRepo.GetPowerBudget(r.Context(), agentID)
+	if err != nil {
+		debug.Error("Failed to get power budget: %v", err)
+		http.Error(w, "Failed to get power budget", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"agentId":      agentID,
+		"powerWindows": windows,
+		"powerBudget":  budget,
+	})
+}
+
+// UpdateAgentPowerWindow handles POST /api/agents/{id}/power-windows
+func (h *SchedulingHandler) UpdateAgentPowerWindow(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	agentID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid agent ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parse request body
+	var dto models.AgentPowerWindowDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Parse times from DTO
+	startTime, err := models.ParseTimeOnly(dto.StartTimeUTC)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid start time: %v", err), http.StatusBadRequest)
+		return
+	}
+	endTime, err := models.ParseTimeOnly(dto.EndTimeUTC)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid end time: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if dto.Timezone == "" {
+		dto.Timezone = "UTC"
+	}
+
+	window := &models.AgentPowerWindow{
+		AgentID:     agentID,
+		DayOfWeek:   dto.DayOfWeek,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		Timezone:    dto.Timezone,
+		MaxPriority: dto.MaxPriority,
+		IsActive:    dto.IsActive,
+	}
+
+	if err := window.ValidateWindow(); err != nil {
+		http.Error(w, "Invalid power window", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.scheduleRepo.UpsertPowerWindow(r.Context(), window); err != nil {
+		debug.Error("Failed to update power window: %v", err)
+		http.Error(w, "Failed to update power window", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(window)
+}
+
+// DeleteAgentPowerWindow handles DELETE /api/agents/{id}/power-windows/{day}
+func (h *SchedulingHandler) DeleteAgentPowerWindow(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	agentID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid agent ID", http.StatusBadRequest)
+		return
+	}
+
+	dayOfWeek, err := strconv.Atoi(vars["day"])
+	if err != nil || dayOfWeek < 0 || dayOfWeek > 6 {
+		http.Error(w, "Invalid day of week", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.scheduleRepo.DeletePowerWindow(r.Context(), agentID, dayOfWeek); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Power window not found", http.StatusNotFound)
+			return
+		}
+		debug.Error("Failed to delete power window: %v", err)
+		http.Error(w, "Failed to delete power window", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateAgentPowerBudget handles PUT /api/agents/{id}/power-budget
+func (h *SchedulingHandler) UpdateAgentPowerBudget(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	agentID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid agent ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		PowerDrawWatts int `json:"powerDrawWatts"`
+		DailyBudgetWh  int `json:"dailyBudgetWh"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.PowerDrawWatts <= 0 || req.DailyBudgetWh <= 0 {
+		http.Error(w, "Power draw and daily budget must be positive", http.StatusBadRequest)
+		return
+	}
+
+	budget := &models.AgentPowerBudget{
+		AgentID:        agentID,
+		PowerDrawWatts: req.PowerDrawWatts,
+		DailyBudgetWh:  req.DailyBudgetWh,
+	}
+	if err := h.scheduleRepo.SetPowerBudget(r.Context(), budget); err != nil {
+		debug.Error("Failed to update power budget: %v", err)
+		http.Error(w, "Failed to update power budget", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
+
+// DeleteAgentPowerBudget handles DELETE /api/agents/{id}/power-budget
+func (h *SchedulingHandler) DeleteAgentPowerBudget(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	agentID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid agent ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.scheduleRepo.DeletePowerBudget(r.Context(), agentID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Power budget not found", http.StatusNotFound)
+			return
+		}
+		debug.Error("Failed to delete power budget: %v", err)
+		http.Error(w, "Failed to delete power budget", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
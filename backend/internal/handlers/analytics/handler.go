@@ -51,6 +51,13 @@ func (h *Handler) CreateReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.PasswordPolicy != nil {
+		if err := req.PasswordPolicy.Validate(); err != nil {
+			http.Error(w, "Invalid password policy: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Get user ID from context (set by auth middleware)
 	userIDStr, ok := r.Context().Value("user_id").(string)
 	if !ok {
@@ -83,6 +90,7 @@ func (h *Handler) CreateReport(w http.ResponseWriter, r *http.Request) {
 		EndDate:        req.EndDate,
 		Status:         "queued",
 		CustomPatterns: req.CustomPatterns,
+		PasswordPolicy: req.PasswordPolicy,
 		QueuePosition:  &queuePos,
 		CreatedAt:      time.Now(),
 	}
@@ -0,0 +1,170 @@
+package exports
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/export"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles export jobs and the download of their artifacts
+type Handler struct {
+	service *export.ExportService
+}
+
+// NewHandler creates a new export handler
+func NewHandler(service *export.ExportService) *Handler {
+	return &Handler{service: service}
+}
+
+// Create handles POST /api/exports, starting an export in the background
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := exportUserID(r)
+	if !ok {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.ExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	job, err := h.service.Create(r.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, export.ErrInvalidExport) {
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		debug.Error("Failed to create export: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to create export")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusAccepted, job)
+}
+
+// List handles GET /api/exports, the latest exports of the user
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := exportUserID(r)
+	if !ok {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	jobs, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		debug.Error("Failed to list exports: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list exports")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, jobs)
+}
+
+// Get handles GET /api/exports/{id}, the progress of an export and, once it
+// is completed, a signed link to download it
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := exportUserID(r)
+	if !ok {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	job, err := h.service.Get(r.Context(), userID, id)
+	if err != nil {
+		if errors.Is(err, export.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusNotFound, "Export not found")
+			return
+		}
+		debug.Error("Failed to get export %s: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get export")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, job)
+}
+
+// Delete handles DELETE /api/exports/{id}, removing a finished export and its artifact
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := exportUserID(r)
+	if !ok {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), userID, id); err != nil {
+		switch {
+		case errors.Is(err, export.ErrNotFound):
+			httputil.RespondWithError(w, http.StatusNotFound, "Export not found")
+		case errors.Is(err, export.ErrNotFinished):
+			httputil.RespondWithError(w, http.StatusConflict, "Export is still being built")
+		default:
+			debug.Error("Failed to delete export %s: %v", id, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to delete export")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Download handles GET /api/exports/{id}/download. It is authenticated by the
+// signature and expiry in the link rather than a session, so the link can be
+// handed to a browser or a download tool.
+func (h *Handler) Download(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	query := r.URL.Query()
+	job, err := h.service.OpenDownload(r.Context(), id, query.Get("expires"), query.Get("signature"))
+	if err != nil {
+		if errors.Is(err, export.ErrInvalidLink) {
+			httputil.RespondWithError(w, http.StatusForbidden, "Invalid or expired download link")
+			return
+		}
+		debug.Error("Failed to open export %s: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to download export")
+		return
+	}
+
+	fileName := "export.txt"
+	if job.FileName != nil && *job.FileName != "" {
+		fileName = strings.ReplaceAll(*job.FileName, "\"", "")
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	http.ServeFile(w, r, job.FilePath)
+}
+
+// exportUserID returns the authenticated user
+func exportUserID(r *http.Request) (uuid.UUID, bool) {
+	userIDStr, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	return userID, err == nil
+}
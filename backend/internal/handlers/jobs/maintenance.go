@@ -0,0 +1,17 @@
+package jobs
+
+import (
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+)
+
+// respondMaintenance refuses a request made during maintenance mode. The code
+// lets the frontend show the error as the maintenance banner.
+func respondMaintenance(w http.ResponseWriter, err error) {
+	httputil.RespondWithJSON(w, http.StatusServiceUnavailable, map[string]string{
+		"error": err.Error(),
+		"code":  models.MaintenanceCode,
+	})
+}
@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+)
+
+// MaskHandler handles mask validation for the mask builder
+type MaskHandler struct {
+	service *services.MaskService
+}
+
+// NewMaskHandler creates a new mask handler
+func NewMaskHandler(service *services.MaskService) *MaskHandler {
+	return &MaskHandler{service: service}
+}
+
+// ValidateMask handles POST /api/masks/validate. An invalid mask is answered
+// with valid set to false and the reason, so the builder can show it inline.
+func (h *MaskHandler) ValidateMask(w http.ResponseWriter, r *http.Request) {
+	var req models.MaskValidationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.Validate(r.Context(), &req)
+	if err != nil {
+		debug.Error("Failed to validate mask: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to validate mask")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, result)
+}
+
+// ListCharsets handles GET /api/charsets, the custom charset library
+func (h *MaskHandler) ListCharsets(w http.ResponseWriter, r *http.Request) {
+	charsets, err := h.service.ListCharsets(r.Context())
+	if err != nil {
+		debug.Error("Failed to list custom charsets: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list custom charsets")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, charsets)
+}
@@ -0,0 +1,99 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/quickcrack"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// QuickCrackHandler handles quick crack attempts on ad hoc hashes
+type QuickCrackHandler struct {
+	service *quickcrack.QuickCrackService
+}
+
+// NewQuickCrackHandler creates a new quick crack handler
+func NewQuickCrackHandler(service *quickcrack.QuickCrackService) *QuickCrackHandler {
+	return &QuickCrackHandler{service: service}
+}
+
+// Submit handles POST /api/quick-crack, queueing the quick attack workflow
+// against a handful of hashes
+func (h *QuickCrackHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	userID, ok := quickCrackUserID(r)
+	if !ok {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.QuickCrackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.Submit(r.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, quickcrack.ErrInvalidSubmission):
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, quickcrack.ErrNotConfigured):
+			httputil.RespondWithError(w, http.StatusConflict, "No quick attack workflow is configured, set quick_crack_workflow_id or pass workflow_id")
+		case errors.Is(err, services.ErrMaintenanceMode):
+			respondMaintenance(w, err)
+		case errors.Is(err, services.ErrClientEngagementClosed):
+			httputil.RespondWithError(w, http.StatusConflict, err.Error())
+		default:
+			debug.Error("Failed to submit quick crack: %v", err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to submit quick crack")
+		}
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusAccepted, result)
+}
+
+// Get handles GET /api/quick-crack/{id}, the state and results of a submission
+func (h *QuickCrackHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := quickCrackUserID(r)
+	if !ok {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid quick crack ID")
+		return
+	}
+
+	result, err := h.service.Get(r.Context(), userID, id)
+	if err != nil {
+		if errors.Is(err, quickcrack.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusNotFound, "Quick crack not found")
+			return
+		}
+		debug.Error("Failed to get quick crack %s: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get quick crack")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, result)
+}
+
+// quickCrackUserID returns the authenticated user
+func quickCrackUserID(r *http.Request) (uuid.UUID, bool) {
+	userIDStr, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	return userID, err == nil
+}
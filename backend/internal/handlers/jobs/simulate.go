@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+)
+
+// SimulationHandler handles capacity planning simulations of hypothetical jobs
+type SimulationHandler struct {
+	service *services.JobSimulationService
+}
+
+// NewSimulationHandler creates a new job simulation handler
+func NewSimulationHandler(service *services.JobSimulationService) *SimulationHandler {
+	return &SimulationHandler{service: service}
+}
+
+// Simulate handles POST /api/jobs/simulate, predicting the runtime, chunks
+// and per-agent workload of a job without creating it
+func (h *SimulationHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	var req models.JobSimulationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.Simulate(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSimulation) {
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		debug.Error("Failed to simulate job: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to simulate job")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,104 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/jobstream"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+)
+
+// streamKeepAlive is how often a comment is sent on an idle stream so proxies
+// do not close it
+const streamKeepAlive = 25 * time.Second
+
+// JobStreamHandler streams job progress to the UI over server-sent events
+type JobStreamHandler struct {
+	hub *jobstream.Hub
+}
+
+// NewJobStreamHandler creates a new job stream handler
+func NewJobStreamHandler(hub *jobstream.Hub) *JobStreamHandler {
+	return &JobStreamHandler{hub: hub}
+}
+
+// Stream handles GET /api/jobs/stream?job_ids=<id>,<id>, a server-sent event
+// stream of the progress of those jobs. A "progress" event carries what changed
+// in the jobs during the last second. When it has "resync" set, updates were
+// dropped because the client fell behind and the jobs should be fetched again.
+func (h *JobStreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	jobIDs, err := parseStreamJobIDs(r.URL.Query().Get("job_ids"))
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+
+	sub := h.hub.Subscribe(jobIDs)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "event: ready\ndata: {\"jobs\":%d}\n\n", len(jobIDs))
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case msg := <-sub.Messages():
+			data, err := json.Marshal(msg)
+			if err != nil {
+				debug.Error("Failed to encode job progress: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: progress\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// parseStreamJobIDs parses a comma separated list of job IDs
+func parseStreamJobIDs(value string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := uuid.Parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job ID %q", part)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("job_ids is required")
+	}
+	if len(ids) > jobstream.MaxJobsPerSubscription {
+		return nil, fmt.Errorf("at most %d jobs can be streamed at once", jobstream.MaxJobsPerSubscription)
+	}
+	return ids, nil
+}
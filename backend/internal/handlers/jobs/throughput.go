@@ -0,0 +1,84 @@
+package jobs
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+)
+
+// ThroughputHandler handles the throughput history of completed jobs, used to
+// quote turnaround times from past jobs of a hash type
+type ThroughputHandler struct {
+	repo *repository.JobThroughputRepository
+}
+
+// NewThroughputHandler creates a new job throughput handler
+func NewThroughputHandler(repo *repository.JobThroughputRepository) *ThroughputHandler {
+	return &ThroughputHandler{repo: repo}
+}
+
+// GetThroughput handles GET /api/jobs/throughput. Optional parameters:
+// hash_type and attack_mode filter the jobs, since bounds their completion
+// ("90d" or an RFC3339 time), group_by=attack_mode and bucket=day|week|month
+// split the totals, and keyspace adds an estimate of how long a job of that
+// many candidates would take.
+func (h *ThroughputHandler) GetThroughput(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var filter repository.JobThroughputFilter
+
+	for param, target := range map[string]**int{"hash_type": &filter.HashType, "attack_mode": &filter.AttackMode} {
+		if value := query.Get(param); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				httputil.RespondWithError(w, http.StatusBadRequest, "Invalid "+param)
+				return
+			}
+			*target = &n
+		}
+	}
+	if value := query.Get("since"); value != "" {
+		since, err := httputil.ParseSince(value, time.Now())
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.Since = &since
+	}
+	switch query.Get("group_by") {
+	case "":
+	case "attack_mode":
+		filter.ByAttackMode = true
+	default:
+		httputil.RespondWithError(w, http.StatusBadRequest, "group_by must be attack_mode")
+		return
+	}
+	if filter.Bucket = query.Get("bucket"); filter.Bucket != "" && !models.IsValidThroughputBucket(filter.Bucket) {
+		httputil.RespondWithError(w, http.StatusBadRequest, "bucket must be day, week or month")
+		return
+	}
+	var keyspace int64
+	if value := query.Get("keyspace"); value != "" {
+		var err error
+		if keyspace, err = strconv.ParseInt(value, 10, 64); err != nil || keyspace <= 0 {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid keyspace")
+			return
+		}
+	}
+
+	summaries, err := h.repo.Summarize(r.Context(), filter)
+	if err != nil {
+		debug.Error("Failed to get job throughput: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get job throughput")
+		return
+	}
+	for i := range summaries {
+		summaries[i].CalculateSpeeds(keyspace)
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, summaries)
+}
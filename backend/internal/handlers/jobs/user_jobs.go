@@ -3,6 +3,7 @@ package jobs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -13,8 +14,11 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/rule"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/trash"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/wordlist"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatmask"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
@@ -33,7 +37,12 @@ type UserJobsHandler struct {
 	binaryStore         binary.Store
 	jobExecutionService *services.JobExecutionService
 	systemSettingsRepo  *repository.SystemSettingsRepository
+	gpuUsageRepo        *repository.GPUUsageRepository
+	taskArtifactRepo    *repository.TaskArtifactRepository
+	campaignRepo        *repository.JobCampaignRepository
+	trashService        *trash.TrashService
 	wsHandler           WSHandler
+	schedulingSnapshots *services.SchedulingSnapshotStore
 }
 
 // WSHandler interface for WebSocket operations
@@ -46,6 +55,12 @@ func (h *UserJobsHandler) SetWSHandler(wsHandler WSHandler) {
 	h.wsHandler = wsHandler
 }
 
+// SetSchedulingSnapshots sets the scheduler's snapshot store once the
+// scheduling service is created
+func (h *UserJobsHandler) SetSchedulingSnapshots(store *services.SchedulingSnapshotStore) {
+	h.schedulingSnapshots = store
+}
+
 // NewUserJobsHandler creates a new user jobs handler
 func NewUserJobsHandler(
 	jobExecRepo *repository.JobExecutionRepository,
@@ -60,6 +75,10 @@ func NewUserJobsHandler(
 	binaryStore binary.Store,
 	jobExecutionService *services.JobExecutionService,
 	systemSettingsRepo *repository.SystemSettingsRepository,
+	gpuUsageRepo *repository.GPUUsageRepository,
+	taskArtifactRepo *repository.TaskArtifactRepository,
+	campaignRepo *repository.JobCampaignRepository,
+	trashService *trash.TrashService,
 ) *UserJobsHandler {
 	return &UserJobsHandler{
 		jobExecRepo:         jobExecRepo,
@@ -74,6 +93,10 @@ func NewUserJobsHandler(
 		binaryStore:         binaryStore,
 		jobExecutionService: jobExecutionService,
 		systemSettingsRepo:  systemSettingsRepo,
+		gpuUsageRepo:        gpuUsageRepo,
+		taskArtifactRepo:    taskArtifactRepo,
+		campaignRepo:        campaignRepo,
+		trashService:        trashService,
 		wsHandler:           nil, // Will be set later via SetWSHandler
 	}
 }
@@ -106,25 +129,31 @@ type JobSummary struct {
 	OverallProgressPercent float64 `json:"overall_progress_percent"`
 }
 
+// jobSortColumns maps the sort fields accepted by the job list endpoints to SQL columns
+var jobSortColumns = map[string]string{
+	"name":         "je.name",
+	"status":       "je.status",
+	"priority":     "je.priority",
+	"created_at":   "je.created_at",
+	"updated_at":   "je.updated_at",
+	"started_at":   "je.started_at",
+	"completed_at": "je.completed_at",
+	"progress":     "je.overall_progress_percent",
+	"hashlist":     "h.name",
+}
+
 // ListJobs handles GET /api/jobs with pagination and filtering
 func (h *UserJobsHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Parse query parameters
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page < 1 {
-		page = 1
-	}
-
-	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 25
-	}
+	listQuery := httputil.ParseListQuery(r, 25, 100)
+	page, pageSize := listQuery.Page, listQuery.PageSize
 
 	// Parse filters
-	status := r.URL.Query().Get("status")
-	priorityStr := r.URL.Query().Get("priority")
-	search := r.URL.Query().Get("search")
+	status := listQuery.Filter(r, "status")
+	priorityStr := listQuery.Filter(r, "priority")
+	search := listQuery.Filter(r, "search")
 
 	var priority *int
 	if priorityStr != "" {
@@ -139,6 +168,7 @@ func (h *UserJobsHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
 		Status:   &status,
 		Priority: priority,
 		Search:   &search,
+		OrderBy:  listQuery.OrderBy(jobSortColumns),
 	}
 
 	// Get jobs with filters and user information
@@ -313,9 +343,16 @@ func (h *UserJobsHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
 		summaries = append(summaries, summary)
 	}
 
+	selected, err := httputil.SelectFields(summaries, listQuery.Fields)
+	if err != nil {
+		debug.Error("Failed to apply field selection: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	// Prepare response
 	response := map[string]interface{}{
-		"jobs": summaries,
+		"jobs": selected,
 		"pagination": map[string]interface{}{
 			"page":        page,
 			"page_size":   pageSize,
@@ -332,6 +369,267 @@ func (h *UserJobsHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// taskSortColumns maps the sort fields accepted by ListJobTasks to SQL columns
+var taskSortColumns = map[string]string{
+	"status":       "status",
+	"agent_id":     "agent_id",
+	"chunk_number": "chunk_number",
+	"crack_count":  "crack_count",
+	"progress":     "progress_percent",
+	"created_at":   "created_at",
+	"started_at":   "started_at",
+	"completed_at": "completed_at",
+	"updated_at":   "updated_at",
+}
+
+// ListJobTasks handles GET /api/jobs/{id}/tasks with filtering, sorting and pagination
+func (h *UserJobsHandler) ListJobTasks(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+	h.listTasks(w, r, &jobID)
+}
+
+// ListTasks handles GET /api/tasks, the tasks of all jobs filtered by
+// job_id, status, agent_id and since (an age such as 7d or an RFC3339 time)
+func (h *UserJobsHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	h.listTasks(w, r, nil)
+}
+
+func (h *UserJobsHandler) listTasks(w http.ResponseWriter, r *http.Request, jobID *uuid.UUID) {
+	listQuery := httputil.ParseListQuery(r, 50, 500)
+	filter := repository.TaskListFilter{
+		JobExecutionID: jobID,
+		Status:         listQuery.Filter(r, "status"),
+	}
+	if value := listQuery.Filter(r, "job_id"); value != "" && jobID == nil {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid job_id")
+			return
+		}
+		filter.JobExecutionID = &id
+	}
+	if value := listQuery.Filter(r, "agent_id"); value != "" {
+		agentID, err := strconv.Atoi(value)
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid agent_id")
+			return
+		}
+		filter.AgentID = &agentID
+	}
+	if value := listQuery.Filter(r, "since"); value != "" {
+		since, err := httputil.ParseSince(value, time.Now())
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.Since = &since
+	}
+
+	tasks, total, err := h.jobTaskRepo.ListTasks(r.Context(), filter, listQuery.OrderBy(taskSortColumns),
+		listQuery.Limit(), listQuery.Offset())
+	if err != nil {
+		debug.Error("Failed to list tasks: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data, err := httputil.SelectFields(tasks, listQuery.Fields)
+	if err != nil {
+		debug.Error("Failed to apply field selection: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"tasks":      data,
+		"pagination": httputil.NewPagination(listQuery, total),
+	})
+}
+
+// GetJobCost returns the GPU-hours each agent device spent on a job and their
+// estimated cost at the configured gpu_hour_cost
+func (h *UserJobsHandler) GetJobCost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	jobID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobExecRepo.GetByID(ctx, jobID)
+	if err != nil {
+		debug.Error("Failed to get job %s: %v", jobID, err)
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	devices, err := h.gpuUsageRepo.GetJobDeviceUsage(ctx, jobID)
+	if err != nil {
+		debug.Error("Failed to get GPU usage for job %s: %v", jobID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	gpuHourCost, err := h.systemSettingsRepo.GetGPUHourCost(ctx)
+	if err != nil {
+		debug.Error("Failed to get gpu_hour_cost setting: %v", err)
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, models.NewJobCostReport(job.ID, job.Name, devices, gpuHourCost))
+}
+
+// GetCampaign handles GET /api/jobs/campaigns/{id}, aggregating the jobs a
+// campaign ran against each of its hashlists and the hashes cracked in them
+func (h *UserJobsHandler) GetCampaign(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	campaignID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid campaign ID", http.StatusBadRequest)
+		return
+	}
+
+	campaign, err := h.campaignRepo.GetByID(ctx, campaignID)
+	if err == repository.ErrNotFound {
+		http.Error(w, "Campaign not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		debug.Error("Failed to get campaign %s: %v", campaignID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	jobs, err := h.campaignRepo.ListJobs(ctx, campaignID)
+	if err != nil {
+		debug.Error("Failed to get jobs of campaign %s: %v", campaignID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	hashlists, err := h.campaignRepo.ListHashlists(ctx, campaign)
+	if err != nil {
+		debug.Error("Failed to get hashlists of campaign %s: %v", campaignID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, models.SummarizeCampaign(*campaign, jobs, hashlists))
+}
+
+// GetJobScheduling handles GET /api/jobs/{id}/scheduling, returning what the
+// most recent scheduling cycles decided about the job, newest first
+func (h *UserJobsHandler) GetJobScheduling(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := 20
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > 200 {
+			http.Error(w, "limit must be between 1 and 200", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if _, err := h.jobExecRepo.GetByID(r.Context(), jobID); err != nil {
+		debug.Error("Failed to get job %s: %v", jobID, err)
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	if h.schedulingSnapshots == nil {
+		http.Error(w, "Job scheduler is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"job_id":  jobID,
+		"entries": h.schedulingSnapshots.ForJob(jobID, limit),
+	})
+}
+
+// UpdateJobAnnotations handles PUT /api/jobs/{id}/annotations, replacing the
+// job's notes and tags
+func (h *UserJobsHandler) UpdateJobAnnotations(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	var annotations models.Annotations
+	if err := json.NewDecoder(r.Body).Decode(&annotations); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := annotations.Normalize(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.jobExecRepo.UpdateAnnotations(r.Context(), jobID, annotations); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		debug.Error("Failed to update annotations of job %s: %v", jobID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, annotations)
+}
+
+// RenameJobRequest is the body of PUT /api/jobs/{id}/name
+type RenameJobRequest struct {
+	Name string `json:"name"`
+}
+
+// RenameJob handles PUT /api/jobs/{id}/name. Job names are unique within a
+// client, ignoring case.
+func (h *UserJobsHandler) RenameJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	var req RenameJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	name, err := models.NormalizeJobName(req.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.jobExecRepo.Rename(r.Context(), jobID, name); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			http.Error(w, "Job not found", http.StatusNotFound)
+		case errors.Is(err, repository.ErrDuplicateRecord):
+			http.Error(w, "Another job of this client is already named "+name, http.StatusConflict)
+		default:
+			debug.Error("Failed to rename job %s: %v", jobID, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	debug.Info("Job %s renamed to %q", jobID, name)
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"id": jobID, "name": name})
+}
+
 // getJobName generates a display name for a job
 func getJobName(job models.JobExecution, hashlist *models.HashList) string {
 	// Job name should always be set during creation now
@@ -342,8 +640,9 @@ func getJobName(job models.JobExecution, hashlist *models.HashList) string {
 	return hashlist.Name
 }
 
-// generateJobName creates a job name based on the provided parameters
-func generateJobName(client *models.Client, presetName string, hashlistName string, hashTypeID int, customName string) string {
+// generateJobName names a job after the custom name given by the user, or
+// else fills in the job name template with the client, hashlist and attack
+func (h *UserJobsHandler) generateJobName(ctx context.Context, client *models.Client, hashlist *models.HashList, attack, presetName, customName string) string {
 	if customName != "" && presetName != "" {
 		// User provided custom name with preset job
 		return fmt.Sprintf("%s - %s", customName, presetName)
@@ -354,19 +653,40 @@ func generateJobName(client *models.Client, presetName string, hashlistName stri
 		return customName
 	}
 
-	// Default naming format
-	clientName := "Unknown"
-	if client != nil && client.Name != "" {
-		clientName = client.Name
+	template, err := h.systemSettingsRepo.GetJobNameTemplate(ctx)
+	if err != nil {
+		debug.Error("Failed to get job name template: %v", err)
+		template = models.DefaultJobNameTemplate
 	}
 
-	if presetName != "" {
-		// Preset job: [client]-[presetname]-[hashmode]
-		return fmt.Sprintf("%s-%s-%d", clientName, presetName, hashTypeID)
+	fields := models.JobNameFields{
+		Hashlist: hashlist.Name,
+		Attack:   attack,
+		HashType: hashlist.HashTypeID,
+		Created:  time.Now(),
+	}
+	if client != nil {
+		fields.Client = client.Name
 	}
+	if models.UsesJobSequence(template) {
+		seq, err := h.jobExecRepo.NextJobNameSequence(ctx, hashlist.ClientID)
+		if err != nil {
+			debug.Error("Failed to get job name sequence for hashlist %d: %v", hashlist.ID, err)
+		}
+		fields.Sequence = seq
+	}
+	return models.RenderJobName(template, fields)
+}
 
-	// Custom job without name: [client]-[hashlist]-[hashmode]
-	return fmt.Sprintf("%s-%s-%d", clientName, hashlistName, hashTypeID)
+// uniqueJobName adds a " (n)" suffix to a job name already taken by another
+// job of the hashlist's client
+func (h *UserJobsHandler) uniqueJobName(ctx context.Context, hashlist *models.HashList, name string) string {
+	unique, err := h.jobExecRepo.UniqueJobName(ctx, hashlist.ClientID, name)
+	if err != nil {
+		debug.Warning("Failed to check job name %q: %v", name, err)
+		return name
+	}
+	return unique
 }
 
 // CreateJobFromHashlist handles POST /api/hashlists/{id}/create-job
@@ -394,6 +714,17 @@ func (h *UserJobsHandler) CreateJobFromHashlist(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// No jobs are created during maintenance
+	if err := h.jobExecutionService.CheckMaintenance(ctx); err != nil {
+		if errors.Is(err, services.ErrMaintenanceMode) {
+			respondMaintenance(w, err)
+			return
+		}
+		debug.Error("Failed to check maintenance mode: %v", err)
+		http.Error(w, "Failed to create job", http.StatusInternalServerError)
+		return
+	}
+
 	// Parse the request body to determine job type
 	var rawReq json.RawMessage
 	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil {
@@ -403,12 +734,44 @@ func (h *UserJobsHandler) CreateJobFromHashlist(w http.ResponseWriter, r *http.R
 
 	// Determine the job type
 	var jobType struct {
-		Type string `json:"type"`
+		Type           string `json:"type"`
+		AllowDuplicate bool   `json:"allow_duplicate"` // Create even if the same attack already ran or is queued
+		Background     bool   `json:"is_background"`   // Only run on idle agents, giving way to normal jobs
+		CloudBurst     bool   `json:"allow_cloud_burst"` // May launch and run on temporary cloud agents
+		// Run against a snapshot of the hashes still uncracked, like hashcat --left
+		RemainingHashesOnly bool `json:"remaining_hashes_only"`
+		CustomJobName  string `json:"custom_job_name"`
+		// Run the same attacks against these hashlists too, as one campaign
+		CampaignHashlistIDs []int64 `json:"campaign_hashlist_ids"`
+		CampaignName        string  `json:"campaign_name"`
+		models.BenchmarkOverride
+		models.AgentPlacement
+		models.JobStartCondition
 	}
 	if err := json.Unmarshal(rawReq, &jobType); err != nil {
 		http.Error(w, "Invalid request format", http.StatusBadRequest)
 		return
 	}
+	if err := jobType.BenchmarkOverride.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := jobType.AgentPlacement.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if jobType.DependsOnJobID != nil {
+		dependency, err := h.jobExecRepo.GetByID(ctx, *jobType.DependsOnJobID)
+		if err != nil && err != repository.ErrNotFound {
+			debug.Error("Failed to get dependency job %s: %v", *jobType.DependsOnJobID, err)
+			http.Error(w, "Failed to get dependency job", http.StatusInternalServerError)
+			return
+		}
+		if err := jobType.JobStartCondition.Validate(dependency); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	// Verify the hashlist exists and get its details
 	hashlist, err := h.hashlistRepo.GetByID(ctx, hashlistID)
@@ -417,164 +780,336 @@ func (h *UserJobsHandler) CreateJobFromHashlist(w http.ResponseWriter, r *http.R
 		http.Error(w, "Hashlist not found", http.StatusNotFound)
 		return
 	}
-	
-	// Get client info if available
-	var client *models.Client
-	if hashlist.ClientID != uuid.Nil {
-		client, err = h.clientRepo.GetByID(ctx, hashlist.ClientID)
-		if err != nil {
-			debug.Warning("Failed to get client %s: %v", hashlist.ClientID, err)
-			// Don't fail, just continue without client info
-			client = nil
-		}
+	if hashlist.Status == models.HashListStatusStaged {
+		http.Error(w, "Hashlist is staged, commit it before creating jobs", http.StatusConflict)
+		return
 	}
 
-	var createdJobs []string
-
-	switch jobType.Type {
-	case "preset":
-		// Handle preset jobs
-		var req struct {
-			Type          string   `json:"type"`
-			PresetJobIDs  []string `json:"preset_job_ids"`
-			CustomJobName string   `json:"custom_job_name"`
+	// Hashlists of the same hash type a campaign also runs the attacks against
+	hashlists := []*models.HashList{hashlist}
+	seen := map[int64]bool{hashlist.ID: true}
+	for _, id := range jobType.CampaignHashlistIDs {
+		if seen[id] {
+			continue
 		}
-		if err := json.Unmarshal(rawReq, &req); err != nil {
-			http.Error(w, "Invalid preset job request", http.StatusBadRequest)
+		seen[id] = true
+		other, err := h.hashlistRepo.GetByID(ctx, id)
+		if err != nil {
+			debug.Error("Failed to get campaign hashlist %d: %v", id, err)
+			http.Error(w, fmt.Sprintf("Hashlist %d not found", id), http.StatusNotFound)
 			return
 		}
+		if other.HashTypeID != hashlist.HashTypeID {
+			http.Error(w, fmt.Sprintf("Hashlist %d has a different hash type, a campaign only runs against hashlists of one hash type", id), http.StatusBadRequest)
+			return
+		}
+		if other.Status == models.HashListStatusStaged {
+			http.Error(w, fmt.Sprintf("Hashlist %d is staged, commit it before creating jobs", id), http.StatusConflict)
+			return
+		}
+		hashlists = append(hashlists, other)
+	}
 
-		// Create a job execution for each selected preset job
-		for _, presetJobIDStr := range req.PresetJobIDs {
-			presetJobID, err := uuid.Parse(presetJobIDStr)
-			if err != nil {
-				debug.Error("Invalid preset job ID: %s", presetJobIDStr)
-				continue
+	// Only the remaining hashes of a fully cracked hashlist leave nothing to attack
+	if jobType.RemainingHashesOnly {
+		for _, target := range hashlists {
+			if target.TotalHashes > 0 && target.CrackedHashes >= target.TotalHashes {
+				http.Error(w, fmt.Sprintf("Hashlist %d has no uncracked hashes remaining", target.ID), http.StatusBadRequest)
+				return
 			}
+		}
+	}
 
-			// Get the preset job to verify it exists and get its name
-			presetJob, err := h.presetJobRepo.GetByID(ctx, presetJobID)
-			if err != nil {
-				debug.Error("Failed to get preset job %s: %v", presetJobID, err)
-				continue
+	// No jobs are created against hashlists of clients whose engagement is closed
+	for _, target := range hashlists {
+		if err := h.jobExecutionService.CheckClientEngagement(ctx, target); err != nil {
+			if errors.Is(err, services.ErrClientEngagementClosed) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
 			}
-			
-			// Generate job name
-			jobName := generateJobName(client, presetJob.Name, hashlist.Name, hashlist.HashTypeID, req.CustomJobName)
+			debug.Error("Failed to check client engagement of hashlist %d: %v", target.ID, err)
+			http.Error(w, "Failed to create job", http.StatusInternalServerError)
+			return
+		}
+	}
 
-			// Use CreateJobExecution to create job with keyspace calculation
-			jobExecution, err := h.jobExecutionService.CreateJobExecution(ctx, presetJobID, hashlistID, &userID, jobName)
+	var createdJobs []string
+	var duplicates []duplicateAttack
+
+	// A campaign runs the attacks against each of its hashlists in turn
+	for _, hashlist := range hashlists {
+		// Get client info if available
+		var client *models.Client
+		if hashlist.ClientID != uuid.Nil {
+			client, err = h.clientRepo.GetByID(ctx, hashlist.ClientID)
 			if err != nil {
-				debug.Error("Failed to create job execution for preset %s: %v", presetJobID, err)
-				continue
+				debug.Warning("Failed to get client %s: %v", hashlist.ClientID, err)
+				// Don't fail, just continue without client info
+				client = nil
 			}
-
-			createdJobs = append(createdJobs, jobExecution.ID.String())
 		}
 
-	case "workflow":
-		// Handle workflows
-		var req struct {
-			Type          string   `json:"type"`
-			WorkflowIDs   []string `json:"workflow_ids"`
-			CustomJobName string   `json:"custom_job_name"`
-		}
-		if err := json.Unmarshal(rawReq, &req); err != nil {
-			http.Error(w, "Invalid workflow request", http.StatusBadRequest)
+		// Jobs on a split hashlist run against each of its sub-lists
+		targets, err := h.jobTargets(ctx, hashlist)
+		if err != nil {
+			debug.Error("Failed to get sub-lists of hashlist %d: %v", hashlist.ID, err)
+			http.Error(w, "Failed to create job", http.StatusInternalServerError)
 			return
 		}
 
-		// For each workflow, create jobs for all its steps
-		for _, workflowIDStr := range req.WorkflowIDs {
-			workflowID, err := uuid.Parse(workflowIDStr)
-			if err != nil {
-				debug.Error("Invalid workflow ID: %s", workflowIDStr)
-				continue
+		switch jobType.Type {
+		case "preset":
+			// Handle preset jobs
+			var req struct {
+				Type          string   `json:"type"`
+				PresetJobIDs  []string `json:"preset_job_ids"`
+				CustomJobName string   `json:"custom_job_name"`
+			}
+			if err := json.Unmarshal(rawReq, &req); err != nil {
+				http.Error(w, "Invalid preset job request", http.StatusBadRequest)
+				return
 			}
 
-			// Get workflow steps
-			steps, err := h.workflowRepo.GetWorkflowSteps(ctx, workflowID)
-			if err != nil {
-				debug.Error("Failed to get workflow steps for %s: %v", workflowID, err)
-				continue
+			// Create a job execution for each selected preset job
+			for _, presetJobIDStr := range req.PresetJobIDs {
+				presetJobID, err := uuid.Parse(presetJobIDStr)
+				if err != nil {
+					debug.Error("Invalid preset job ID: %s", presetJobIDStr)
+					continue
+				}
+
+				// Get the preset job to verify it exists and get its name
+				presetJob, err := h.presetJobRepo.GetByID(ctx, presetJobID)
+				if err != nil {
+					debug.Error("Failed to get preset job %s: %v", presetJobID, err)
+					continue
+				}
+
+				fingerprint := models.ComputeAttackFingerprint(presetJob.AttackMode, hashlist.HashTypeID, presetJob.WordlistIDs,
+					presetJob.RuleIDs, presetJob.MaskIncrement.FingerprintMask(presetJob.Mask), presetJob.AdditionalArgs, presetJob.BinaryVersionID)
+				if existing := h.findDuplicateJobs(ctx, targets[0].ID, fingerprint); len(existing) > 0 {
+					duplicates = append(duplicates, duplicateAttack{Attack: presetJob.Name, ExistingJobs: existing})
+					if !jobType.AllowDuplicate {
+						continue
+					}
+				}
+
+				// Generate job name
+				jobName := h.generateJobName(ctx, client, hashlist, presetJob.Name, presetJob.Name, req.CustomJobName)
+
+				// A preset running a wordlist collection creates a job per
+				// wordlist, the jobs of each wordlist form one split group
+				attackJobs := make(map[int][]uuid.UUID)
+				for i, target := range targets {
+					// Use CreateJobExecutions to create jobs with keyspace calculation
+					jobExecutions, err := h.jobExecutionService.CreateJobExecutions(ctx, presetJobID, target.ID, &userID, h.uniqueJobName(ctx, hashlist, targetJobName(jobName, i, len(targets))))
+					if err != nil {
+						debug.Error("Failed to create job execution for preset %s: %v", presetJobID, err)
+					}
+
+					for wordlist, jobExecution := range jobExecutions {
+						attackJobs[wordlist] = append(attackJobs[wordlist], jobExecution.ID)
+						createdJobs = append(createdJobs, jobExecution.ID.String())
+					}
+				}
+				for _, ids := range attackJobs {
+					h.linkSplitGroup(ctx, ids)
+				}
+			}
+
+		case "workflow":
+			// Handle workflows
+			var req struct {
+				Type          string   `json:"type"`
+				WorkflowIDs   []string `json:"workflow_ids"`
+				CustomJobName string   `json:"custom_job_name"`
+			}
+			if err := json.Unmarshal(rawReq, &req); err != nil {
+				http.Error(w, "Invalid workflow request", http.StatusBadRequest)
+				return
 			}
 
-			// Create a job for each step in order
-			for _, step := range steps {
-				// Verify the preset job exists and get its name
-				presetJob, err := h.presetJobRepo.GetByID(ctx, step.PresetJobID)
+			// For each workflow, create jobs for all its steps
+			for _, workflowIDStr := range req.WorkflowIDs {
+				workflowID, err := uuid.Parse(workflowIDStr)
 				if err != nil {
-					debug.Error("Failed to get preset job %s for workflow step: %v", step.PresetJobID, err)
+					debug.Error("Invalid workflow ID: %s", workflowIDStr)
 					continue
 				}
-				
-				// Generate job name for workflow step
-				jobName := generateJobName(client, presetJob.Name, hashlist.Name, hashlist.HashTypeID, req.CustomJobName)
 
-				// Use CreateJobExecution to create job with keyspace calculation
-				jobExecution, err := h.jobExecutionService.CreateJobExecution(ctx, step.PresetJobID, hashlistID, &userID, jobName)
+				// Get the workflow with its steps
+				workflow, err := h.workflowRepo.GetWorkflowByID(ctx, workflowID)
 				if err != nil {
-					debug.Error("Failed to create job execution for workflow step: %v", err)
+					debug.Error("Failed to get workflow %s: %v", workflowID, err)
 					continue
 				}
 
-				createdJobs = append(createdJobs, jobExecution.ID.String())
+				// Verify the preset job of each step exists and check it for duplicates
+				presetJobs := make([]*models.PresetJob, len(workflow.Steps))
+				var workflowDuplicates []duplicateAttack
+				for stepIndex, step := range workflow.Steps {
+					presetJob, err := h.presetJobRepo.GetByID(ctx, step.PresetJobID)
+					if err != nil {
+						debug.Error("Failed to get preset job %s for workflow step: %v", step.PresetJobID, err)
+						continue
+					}
+					presetJobs[stepIndex] = presetJob
+
+					fingerprint := models.ComputeAttackFingerprint(presetJob.AttackMode, hashlist.HashTypeID, presetJob.WordlistIDs,
+						presetJob.RuleIDs, presetJob.MaskIncrement.FingerprintMask(presetJob.Mask), presetJob.AdditionalArgs, presetJob.BinaryVersionID)
+					if existing := h.findDuplicateJobs(ctx, targets[0].ID, fingerprint); len(existing) > 0 {
+						workflowDuplicates = append(workflowDuplicates, duplicateAttack{Attack: presetJob.Name, Workflow: workflow.Name, ExistingJobs: existing})
+					}
+				}
+				duplicates = append(duplicates, workflowDuplicates...)
+				// Skipping only the duplicate steps would break the chain, so the
+				// whole workflow is skipped
+				if len(workflowDuplicates) > 0 && !jobType.AllowDuplicate {
+					continue
+				}
+
+				// Create a job for each step in order, one chain per sub-list
+				workflowJobs := make([][]*models.JobExecution, len(targets))
+				stepJobs := make(map[[2]int][]uuid.UUID) // By step and wordlist of a collection
+				for stepIndex, step := range workflow.Steps {
+					presetJob := presetJobs[stepIndex]
+					if presetJob == nil {
+						continue
+					}
+
+					// Generate job name for workflow step
+					jobName := h.generateJobName(ctx, client, hashlist, presetJob.Name, presetJob.Name, req.CustomJobName)
+
+					for i, target := range targets {
+						// Use CreateJobExecutions to create jobs with keyspace calculation
+						jobExecutions, err := h.jobExecutionService.CreateJobExecutions(ctx, step.PresetJobID, target.ID, &userID, h.uniqueJobName(ctx, hashlist, targetJobName(jobName, i, len(targets))))
+						if err != nil {
+							debug.Error("Failed to create job execution for workflow step: %v", err)
+						}
+
+						for wordlist, jobExecution := range jobExecutions {
+							workflowJobs[i] = append(workflowJobs[i], jobExecution)
+							stepJobs[[2]int{stepIndex, wordlist}] = append(stepJobs[[2]int{stepIndex, wordlist}], jobExecution.ID)
+							createdJobs = append(createdJobs, jobExecution.ID.String())
+						}
+					}
+				}
+
+				// Chain the step jobs so follow-ups inherit the priority of the step before them
+				for _, chain := range workflowJobs {
+					if err := h.jobExecutionService.LinkWorkflowRun(ctx, workflow, chain); err != nil {
+						debug.Error("Failed to link jobs of workflow %s: %v", workflowID, err)
+					}
+				}
+				for _, ids := range stepJobs {
+					h.linkSplitGroup(ctx, ids)
+				}
 			}
-		}
 
-	case "custom":
-		// Handle custom job
-		var req struct {
-			Type          string `json:"type"`
-			CustomJobName string `json:"custom_job_name"`
-			CustomJob struct {
-				Name                      string   `json:"name"`
-				AttackMode                int      `json:"attack_mode"`
-				WordlistIDs               []string `json:"wordlist_ids"`
-				RuleIDs                   []string `json:"rule_ids"`
-				Mask                      string   `json:"mask"`
-				Priority                  int      `json:"priority"`
-				MaxAgents                 int      `json:"max_agents"`
-				BinaryVersionID           int      `json:"binary_version_id"`
-				AllowHighPriorityOverride bool     `json:"allow_high_priority_override"`
-				ChunkSizeSeconds          int      `json:"chunk_size_seconds"`
-			} `json:"custom_job"`
-		}
-		if err := json.Unmarshal(rawReq, &req); err != nil {
-			http.Error(w, "Invalid custom job request", http.StatusBadRequest)
-			return
-		}
+		case "custom":
+			// Handle custom job
+			var req struct {
+				Type          string `json:"type"`
+				CustomJobName string `json:"custom_job_name"`
+				CustomJob struct {
+					Name                      string                `json:"name"`
+					AttackMode                int                   `json:"attack_mode"`
+					WordlistIDs               []string              `json:"wordlist_ids"`
+					RuleIDs                   []string              `json:"rule_ids"`
+					Mask                      string                `json:"mask"`
+					MaskIncrement             *models.MaskIncrement `json:"mask_increment"`
+					Priority                  int                   `json:"priority"`
+					MaxAgents                 int                   `json:"max_agents"`
+					BinaryVersionID           int                   `json:"binary_version_id"`
+					AllowHighPriorityOverride bool                  `json:"allow_high_priority_override"`
+					ChunkSizeSeconds          int                   `json:"chunk_size_seconds"`
+				} `json:"custom_job"`
+			}
+			if err := json.Unmarshal(rawReq, &req); err != nil {
+				http.Error(w, "Invalid custom job request", http.StatusBadRequest)
+				return
+			}
 
-		// Create custom job configuration (NO preset job creation)
-		config := services.CustomJobConfig{
-			Name:                      req.CustomJob.Name,
-			AttackMode:                models.AttackMode(req.CustomJob.AttackMode),
-			WordlistIDs:               models.IDArray(req.CustomJob.WordlistIDs),
-			RuleIDs:                   models.IDArray(req.CustomJob.RuleIDs),
-			Mask:                      req.CustomJob.Mask,
-			Priority:                  req.CustomJob.Priority,
-			MaxAgents:                 req.CustomJob.MaxAgents,
-			BinaryVersionID:           req.CustomJob.BinaryVersionID,
-			AllowHighPriorityOverride: req.CustomJob.AllowHighPriorityOverride,
-			ChunkSizeSeconds:          req.CustomJob.ChunkSizeSeconds,
-		}
+			// Masks are checked up front, hashcat would only reject them on the agent
+			if req.CustomJob.Mask != "" {
+				if _, err := hashcatmask.Validate(req.CustomJob.Mask); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+			if req.CustomJob.MaskIncrement != nil {
+				if models.AttackMode(req.CustomJob.AttackMode) != models.AttackModeBruteForce {
+					http.Error(w, "Mask increments are only supported in brute force attack mode", http.StatusBadRequest)
+					return
+				}
+				if _, err := req.CustomJob.MaskIncrement.Resolve(req.CustomJob.Mask); err != nil {
+					http.Error(w, "Invalid mask increment: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+
+			// Create custom job configuration (NO preset job creation)
+			config := services.CustomJobConfig{
+				Name:                      req.CustomJob.Name,
+				AttackMode:                models.AttackMode(req.CustomJob.AttackMode),
+				WordlistIDs:               models.IDArray(req.CustomJob.WordlistIDs),
+				RuleIDs:                   models.IDArray(req.CustomJob.RuleIDs),
+				Mask:                      req.CustomJob.Mask,
+				MaskIncrement:             req.CustomJob.MaskIncrement,
+				Priority:                  req.CustomJob.Priority,
+				MaxAgents:                 req.CustomJob.MaxAgents,
+				BinaryVersionID:           req.CustomJob.BinaryVersionID,
+				AllowHighPriorityOverride: req.CustomJob.AllowHighPriorityOverride,
+				ChunkSizeSeconds:          req.CustomJob.ChunkSizeSeconds,
+			}
+
+			fingerprint := models.ComputeAttackFingerprint(config.AttackMode, hashlist.HashTypeID, config.WordlistIDs,
+				config.RuleIDs, config.MaskIncrement.FingerprintMask(config.Mask), nil, config.BinaryVersionID)
+			if existing := h.findDuplicateJobs(ctx, targets[0].ID, fingerprint); len(existing) > 0 {
+				duplicates = append(duplicates, duplicateAttack{Attack: config.Name, ExistingJobs: existing})
+				if !jobType.AllowDuplicate {
+					break
+				}
+			}
 
-		// Generate job name for custom job
-		// For custom jobs, prefer the top-level custom_job_name, fall back to the job's own name
-		jobName := generateJobName(client, "", hashlist.Name, hashlist.HashTypeID, req.CustomJobName)
+			// Generate job name for custom job
+			// For custom jobs, prefer the top-level custom_job_name; the template names
+			// the attack after the job's own name or its attack mode
+			attack := config.Name
+			if attack == "" {
+				attack = models.AttackModeName(config.AttackMode)
+			}
+			jobName := h.generateJobName(ctx, client, hashlist, attack, "", req.CustomJobName)
 		
-		// Create job execution directly without saving preset
-		jobExecution, err := h.jobExecutionService.CreateCustomJobExecution(ctx, config, hashlistID, &userID, jobName)
-		if err != nil {
-			debug.Error("Failed to create custom job execution: %v", err)
-			http.Error(w, "Failed to create job", http.StatusInternalServerError)
+			var attackJobs []uuid.UUID
+			for i, target := range targets {
+				// Create job execution directly without saving preset
+				jobExecution, err := h.jobExecutionService.CreateCustomJobExecution(ctx, config, target.ID, &userID, h.uniqueJobName(ctx, hashlist, targetJobName(jobName, i, len(targets))))
+				if err != nil {
+					debug.Error("Failed to create custom job execution: %v", err)
+					http.Error(w, "Failed to create job", http.StatusInternalServerError)
+					return
+				}
+
+				attackJobs = append(attackJobs, jobExecution.ID)
+				createdJobs = append(createdJobs, jobExecution.ID.String())
+			}
+			h.linkSplitGroup(ctx, attackJobs)
+
+		default:
+			http.Error(w, "Invalid job type", http.StatusBadRequest)
 			return
 		}
+	}
 
-		createdJobs = append(createdJobs, jobExecution.ID.String())
-
-	default:
-		http.Error(w, "Invalid job type", http.StatusBadRequest)
+	if len(createdJobs) == 0 && len(duplicates) > 0 && !jobType.AllowDuplicate {
+		// Every requested attack already ran or is queued against this hashlist
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":      "An identical attack has already completed or is queued for this hashlist. Set allow_duplicate to create it anyway.",
+			"duplicates": duplicates,
+		})
 		return
 	}
 
@@ -583,17 +1118,176 @@ func (h *UserJobsHandler) CreateJobFromHashlist(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Apply the start condition, background class, benchmark override and agent placement before the scheduler picks the jobs up
+	if jobType.JobStartCondition.Held(time.Now()) {
+		for _, id := range createdJobs {
+			jobID, err := uuid.Parse(id)
+			if err != nil {
+				continue
+			}
+			if err := h.jobExecRepo.UpdateStartCondition(ctx, jobID, jobType.JobStartCondition); err != nil {
+				debug.Error("Failed to schedule start of job %s: %v", jobID, err)
+			}
+		}
+	}
+	if jobType.RemainingHashesOnly {
+		for _, id := range createdJobs {
+			jobID, err := uuid.Parse(id)
+			if err != nil {
+				continue
+			}
+			// A job whose remaining hashes could not be written still runs the full hashlist
+			if _, err := h.jobExecutionService.TargetRemainingHashes(ctx, jobID); err != nil {
+				debug.Error("Failed to target remaining hashes of job %s: %v", jobID, err)
+			}
+		}
+	}
+	if jobType.Background {
+		for _, id := range createdJobs {
+			jobID, err := uuid.Parse(id)
+			if err != nil {
+				continue
+			}
+			if err := h.jobExecRepo.UpdateBackground(ctx, jobID, true); err != nil {
+				debug.Error("Failed to make job %s a background job: %v", jobID, err)
+			}
+		}
+	}
+	if jobType.Skip || jobType.DurationSeconds != nil {
+		for _, id := range createdJobs {
+			jobID, err := uuid.Parse(id)
+			if err != nil {
+				continue
+			}
+			if err := h.jobExecRepo.UpdateBenchmarkOverride(ctx, jobID, jobType.BenchmarkOverride); err != nil {
+				debug.Error("Failed to set benchmark override of job %s: %v", jobID, err)
+			}
+		}
+	}
+	if jobType.CloudBurst {
+		for _, id := range createdJobs {
+			jobID, err := uuid.Parse(id)
+			if err != nil {
+				continue
+			}
+			if err := h.jobExecRepo.UpdateAllowCloudBurst(ctx, jobID, true); err != nil {
+				debug.Error("Failed to allow cloud burst for job %s: %v", jobID, err)
+			}
+		}
+	}
+	if jobType.AgentPlacement.Restricted() {
+		for _, id := range createdJobs {
+			jobID, err := uuid.Parse(id)
+			if err != nil {
+				continue
+			}
+			if err := h.jobExecRepo.UpdateAgentPlacement(ctx, jobID, jobType.AgentPlacement); err != nil {
+				debug.Error("Failed to set agent placement of job %s: %v", jobID, err)
+			}
+		}
+	}
+
 	// Return the created jobs
 	response := map[string]interface{}{
 		"ids":     createdJobs,
 		"message": fmt.Sprintf("%d job(s) created successfully", len(createdJobs)),
 	}
+	if len(hashlists) > 1 {
+		if campaignID := h.createCampaign(ctx, hashlists, createdJobs, jobType.CampaignName, jobType.CustomJobName, userID); campaignID != nil {
+			response["campaign_id"] = campaignID.String()
+		}
+	}
+	if len(duplicates) > 0 {
+		// Duplicates are skipped unless allow_duplicate was set, in which case they are a warning
+		response["duplicates"] = duplicates
+		response["duplicates_skipped"] = !jobType.AllowDuplicate
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
 }
 
+// jobTargets returns the hashlists jobs created on a hashlist run against: its
+// sub-lists if it was split, otherwise the hashlist itself
+func (h *UserJobsHandler) jobTargets(ctx context.Context, hashlist *models.HashList) ([]models.HashList, error) {
+	subLists, err := h.hashlistRepo.ListSubLists(ctx, hashlist.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(subLists) == 0 {
+		return []models.HashList{*hashlist}, nil
+	}
+	return subLists, nil
+}
+
+// targetJobName names the job of one sub-list after the attack's job name
+func targetJobName(jobName string, index, total int) string {
+	if total <= 1 {
+		return jobName
+	}
+	return models.SubListName(jobName, index+1, total)
+}
+
+// linkSplitGroup links the jobs running one attack against the sub-lists of a
+// split hashlist so they are reported as one
+func (h *UserJobsHandler) linkSplitGroup(ctx context.Context, jobIDs []uuid.UUID) {
+	if len(jobIDs) <= 1 {
+		return
+	}
+	if err := h.jobExecRepo.SetSplitGroup(ctx, jobIDs, uuid.New()); err != nil {
+		debug.Error("Failed to link jobs of split hashlist: %v", err)
+	}
+}
+
+// createCampaign links the jobs created against several hashlists in one
+// submission as a campaign, returning nil if it could not be stored
+func (h *UserJobsHandler) createCampaign(ctx context.Context, hashlists []*models.HashList, createdJobs []string, name, jobName string, userID uuid.UUID) *uuid.UUID {
+	if name == "" {
+		name = models.CampaignName(jobName, len(hashlists))
+	}
+	campaign := &models.JobCampaign{
+		Name:       name,
+		HashTypeID: hashlists[0].HashTypeID,
+		CreatedBy:  &userID,
+	}
+	for _, hashlist := range hashlists {
+		campaign.HashlistIDs = append(campaign.HashlistIDs, hashlist.ID)
+	}
+
+	var jobIDs []uuid.UUID
+	for _, id := range createdJobs {
+		if jobID, err := uuid.Parse(id); err == nil {
+			jobIDs = append(jobIDs, jobID)
+		}
+	}
+	if err := h.campaignRepo.Create(ctx, campaign, jobIDs); err != nil {
+		debug.Error("Failed to create campaign of %d hashlists: %v", len(hashlists), err)
+		return nil
+	}
+	return &campaign.ID
+}
+
+// duplicateAttack reports an attack in a create-job request that matches
+// existing jobs on the same hashlist
+type duplicateAttack struct {
+	Attack       string                `json:"attack"`
+	Workflow     string                `json:"workflow,omitempty"` // Set for workflow steps, whose whole workflow is skipped
+	ExistingJobs []models.DuplicateJob `json:"existing_jobs"`
+}
+
+// findDuplicateJobs returns completed or queued jobs on the hashlist with the
+// given attack fingerprint. Lookup failures are logged and treated as no match
+// so they never block job creation.
+func (h *UserJobsHandler) findDuplicateJobs(ctx context.Context, hashlistID int64, fingerprint string) []models.DuplicateJob {
+	existing, err := h.jobExecRepo.FindDuplicateAttacks(ctx, hashlistID, fingerprint)
+	if err != nil {
+		debug.Warning("Failed to check for duplicate jobs on hashlist %d: %v", hashlistID, err)
+		return nil
+	}
+	return existing
+}
+
 // GetJobDetail handles GET /api/jobs/{id}
 func (h *UserJobsHandler) GetJobDetail(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -756,6 +1450,10 @@ func (h *UserJobsHandler) GetJobDetail(w http.ResponseWriter, r *http.Request) {
 		if task.AverageSpeed != nil {
 			taskSummary["average_speed"] = *task.AverageSpeed
 		}
+		if task.ErrorCode != nil {
+			taskSummary["error_code"] = *task.ErrorCode
+			taskSummary["remediation_hint"] = task.RemediationHint
+		}
 
 		taskSummaries = append(taskSummaries, taskSummary)
 	}
@@ -781,6 +1479,17 @@ func (h *UserJobsHandler) GetJobDetail(w http.ResponseWriter, r *http.Request) {
 		"priority":                  job.Priority,
 		"max_agents":                job.MaxAgents,
 		"chunk_size_seconds":        job.ChunkSizeSeconds,
+		"chunk_overlap":             job.ChunkOverlap,
+		"skip_benchmark":            job.SkipBenchmark,
+		"benchmark_duration_seconds": job.BenchmarkDurationSeconds,
+		"is_background":             job.IsBackground,
+		"pinned_agent_ids":          job.PinnedAgentIDs,
+		"excluded_agent_ids":        job.ExcludedAgentIDs,
+		"allow_cloud_burst":         job.AllowCloudBurst,
+		"remaining_hash_count":      job.RemainingHashCount,
+		"start_at":                  job.StartAt,
+		"depends_on_job_id":         job.DependsOnJobID,
+		"extra_parameters":          job.ExtraParameters,
 		"attack_mode":               job.AttackMode,
 		"hash_type":                 formattedHashType,
 		"total_keyspace":            job.TotalKeyspace,
@@ -802,6 +1511,24 @@ func (h *UserJobsHandler) GetJobDetail(w http.ResponseWriter, r *http.Request) {
 		"total_tasks": totalTasks,
 	}
 
+	// The attack runs as one job per sub-list of a split hashlist, report all of them
+	if job.SplitGroupID != nil {
+		parts, err := h.jobExecRepo.GetSplitGroupParts(ctx, *job.SplitGroupID)
+		if err != nil {
+			debug.Warning("Failed to get split group of job %s: %v", jobID, err)
+		} else {
+			response["split_group"] = models.SummarizeSplitGroup(*job.SplitGroupID, parts)
+		}
+	}
+	if hashlist.ParentHashlistID != nil {
+		response["parent_hashlist_id"] = *hashlist.ParentHashlistID
+	}
+	if campaignID, err := h.campaignRepo.GetCampaignIDOfJob(ctx, jobID); err != nil {
+		debug.Warning("Failed to get campaign of job %s: %v", jobID, err)
+	} else if campaignID != nil {
+		response["campaign_id"] = campaignID.String()
+	}
+
 	if job.StartedAt != nil {
 		response["started_at"] = job.StartedAt.Format(time.RFC3339)
 	}
@@ -810,6 +1537,32 @@ func (h *UserJobsHandler) GetJobDetail(w http.ResponseWriter, r *http.Request) {
 	}
 	if job.ErrorMessage != nil {
 		response["error_message"] = *job.ErrorMessage
+		errorCode := models.ClassifyTaskError(*job.ErrorMessage)
+		response["error_code"] = errorCode
+		response["remediation_hint"] = errorCode.RemediationHint()
+	}
+
+	// Roll the failed runs up by cause so repeated failures are explained in one place
+	if failures, err := h.taskArtifactRepo.ListJobFailures(ctx, jobID); err == nil {
+		if summary := models.SummarizeTaskFailures(failures); summary != nil {
+			response["failure_summary"] = summary
+		}
+	} else {
+		debug.Warning("Failed to get failures of job %s: %v", jobID, err)
+	}
+
+	if annotations, err := h.jobExecRepo.GetAnnotations(ctx, jobID); err == nil {
+		response["notes"] = annotations.Notes
+		response["tags"] = annotations.Tags
+	} else {
+		debug.Warning("Failed to get annotations of job %s: %v", jobID, err)
+	}
+
+	// Hybrid attacks report the wordlist and mask sides of the keyspace separately
+	if hybrid, err := h.jobExecRepo.GetHybridKeyspace(ctx, jobID); err != nil {
+		debug.Warning("Failed to get hybrid keyspace of job %s: %v", jobID, err)
+	} else if hybrid != nil {
+		response["hybrid_keyspace"] = hybrid
 	}
 
 	// Add preset job details if available
@@ -845,6 +1598,13 @@ func (h *UserJobsHandler) UpdateJob(w http.ResponseWriter, r *http.Request) {
 		Priority         *int `json:"priority"`
 		MaxAgents        *int `json:"max_agents"`
 		ChunkSizeSeconds *int `json:"chunk_size_seconds"`
+		ChunkOverlap     *int64 `json:"chunk_overlap"` // -1 reverts to the system setting
+		SkipBenchmark     *bool  `json:"skip_benchmark"`
+		BenchmarkDuration *int   `json:"benchmark_duration_seconds"` // 0 reverts to the system setting
+		Background        *bool  `json:"is_background"`
+		PinnedAgentIDs    *models.AgentIDList `json:"pinned_agent_ids"`   // Empty unpins the job
+		ExcludedAgentIDs  *models.AgentIDList `json:"excluded_agent_ids"` // Empty excludes no agent
+		AllowCloudBurst   *bool               `json:"allow_cloud_burst"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
@@ -906,6 +1666,96 @@ func (h *UserJobsHandler) UpdateJob(w http.ResponseWriter, r *http.Request) {
 		updatedFields = append(updatedFields, "chunk size")
 	}
 
+	if update.ChunkOverlap != nil {
+		if *update.ChunkOverlap < -1 {
+			http.Error(w, "Chunk overlap must be 0 or more candidates, or -1 to use the system setting", http.StatusBadRequest)
+			return
+		}
+
+		var chunkOverlap *int64
+		if *update.ChunkOverlap >= 0 {
+			chunkOverlap = update.ChunkOverlap
+		}
+		if err := h.jobExecRepo.UpdateChunkOverlap(ctx, jobID, chunkOverlap); err != nil {
+			debug.Error("Failed to update job chunk overlap: %v", err)
+			http.Error(w, "Failed to update chunk overlap", http.StatusInternalServerError)
+			return
+		}
+		updatedFields = append(updatedFields, "chunk overlap")
+	}
+
+	if update.SkipBenchmark != nil || update.BenchmarkDuration != nil {
+		override, err := h.jobExecRepo.GetBenchmarkOverride(ctx, jobID)
+		if err != nil {
+			debug.Error("Failed to get job benchmark override: %v", err)
+			http.Error(w, "Failed to update benchmark override", http.StatusInternalServerError)
+			return
+		}
+		if update.SkipBenchmark != nil {
+			override.Skip = *update.SkipBenchmark
+		}
+		if update.BenchmarkDuration != nil {
+			override.DurationSeconds = update.BenchmarkDuration
+			if *update.BenchmarkDuration == 0 {
+				override.DurationSeconds = nil
+			}
+		}
+		if err := override.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.jobExecRepo.UpdateBenchmarkOverride(ctx, jobID, *override); err != nil {
+			debug.Error("Failed to update job benchmark override: %v", err)
+			http.Error(w, "Failed to update benchmark override", http.StatusInternalServerError)
+			return
+		}
+		updatedFields = append(updatedFields, "benchmark override")
+	}
+
+	if update.Background != nil {
+		if err := h.jobExecRepo.UpdateBackground(ctx, jobID, *update.Background); err != nil {
+			debug.Error("Failed to update job background class: %v", err)
+			http.Error(w, "Failed to update background class", http.StatusInternalServerError)
+			return
+		}
+		updatedFields = append(updatedFields, "background class")
+	}
+
+	if update.PinnedAgentIDs != nil || update.ExcludedAgentIDs != nil {
+		job, err := h.jobExecRepo.GetByID(ctx, jobID)
+		if err != nil {
+			debug.Error("Failed to get job agent placement: %v", err)
+			http.Error(w, "Failed to update agent placement", http.StatusInternalServerError)
+			return
+		}
+		placement := job.AgentPlacement
+		if update.PinnedAgentIDs != nil {
+			placement.PinnedAgentIDs = *update.PinnedAgentIDs
+		}
+		if update.ExcludedAgentIDs != nil {
+			placement.ExcludedAgentIDs = *update.ExcludedAgentIDs
+		}
+		if err := placement.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.jobExecRepo.UpdateAgentPlacement(ctx, jobID, placement); err != nil {
+			debug.Error("Failed to update job agent placement: %v", err)
+			http.Error(w, "Failed to update agent placement", http.StatusInternalServerError)
+			return
+		}
+		updatedFields = append(updatedFields, "agent placement")
+	}
+
+	if update.AllowCloudBurst != nil {
+		if err := h.jobExecRepo.UpdateAllowCloudBurst(ctx, jobID, *update.AllowCloudBurst); err != nil {
+			debug.Error("Failed to update job cloud burst opt-in: %v", err)
+			http.Error(w, "Failed to update cloud burst opt-in", http.StatusInternalServerError)
+			return
+		}
+		updatedFields = append(updatedFields, "cloud burst opt-in")
+	}
+
 	responseMessage := "Job updated successfully"
 	if len(updatedFields) > 0 && update.ChunkSizeSeconds != nil {
 		responseMessage = "Job updated successfully. Chunk size changes will take effect on next task creation."
@@ -1029,6 +1879,102 @@ func (h *UserJobsHandler) RetryTask(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// jobTaskFromRequest parses the job and task IDs of a task route and checks
+// that the task belongs to the job, writing the error response if not
+func (h *UserJobsHandler) jobTaskFromRequest(w http.ResponseWriter, r *http.Request) (*models.JobTask, bool) {
+	vars := mux.Vars(r)
+
+	jobID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	taskID, err := uuid.Parse(vars["taskId"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	task, err := h.jobTaskRepo.GetByID(r.Context(), taskID)
+	if err != nil || task.JobExecutionID != jobID {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	return task, true
+}
+
+// ListTaskArtifacts returns the artifacts kept for each finished run of a task
+func (h *UserJobsHandler) ListTaskArtifacts(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.jobTaskFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	artifacts, err := h.taskArtifactRepo.ListByTask(r.Context(), task.ID)
+	if err != nil {
+		debug.Error("Failed to list artifacts of task %s: %v", task.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"artifacts": artifacts,
+	})
+}
+
+// DownloadTaskArtifact downloads the output tail (output.log) or the final
+// hashcat status (status.json) of a task run
+func (h *UserJobsHandler) DownloadTaskArtifact(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.jobTaskFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	artifactID, err := strconv.ParseInt(vars["artifactId"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid artifact ID", http.StatusBadRequest)
+		return
+	}
+
+	var contentType string
+	switch vars["file"] {
+	case models.TaskArtifactOutputFile:
+		contentType = "text/plain; charset=utf-8"
+	case models.TaskArtifactStatusFile:
+		contentType = "application/json"
+	default:
+		http.Error(w, "Unknown artifact file", http.StatusNotFound)
+		return
+	}
+
+	artifact, err := h.taskArtifactRepo.GetByID(r.Context(), task.ID, artifactID)
+	if errors.Is(err, repository.ErrNotFound) {
+		http.Error(w, "Artifact not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		debug.Error("Failed to get artifact %d of task %s: %v", artifactID, task.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	content := []byte(artifact.OutputTail)
+	if vars["file"] == models.TaskArtifactStatusFile {
+		if len(artifact.FinalStatus) == 0 {
+			http.Error(w, "No status was reported for this run", http.StatusNotFound)
+			return
+		}
+		content = artifact.FinalStatus
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"task-%s-%d-%s\"", task.ID, artifact.ID, vars["file"]))
+	w.Write(content)
+}
+
 // stopAgentTasks sends stop signals to all agents working on tasks for a job
 func (h *UserJobsHandler) stopAgentTasks(ctx context.Context, jobID uuid.UUID) error {
 	// Get all tasks for this job
@@ -1099,8 +2045,12 @@ func (h *UserJobsHandler) DeleteJob(w http.ResponseWriter, r *http.Request) {
 		// Continue with deletion even if we couldn't stop all tasks
 	}
 
-	// Delete job execution record (cascade deletes tasks)
-	if err := h.jobExecRepo.Delete(ctx, jobID); err != nil {
+	// Move the job to the trash (or delete it outright when the trash is disabled)
+	if err := h.trashService.DeleteJob(ctx, jobID, deletingUserID(ctx)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		}
 		debug.Error("Failed to delete job %s: %v", jobID, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
@@ -1113,12 +2063,25 @@ func (h *UserJobsHandler) DeleteJob(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// deletingUserID returns the requesting user's ID for recording who deleted a job
+func deletingUserID(ctx context.Context) *uuid.UUID {
+	userIDStr, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil
+	}
+	return &userID
+}
+
 // DeleteFinishedJobs handles DELETE /api/jobs/finished
 func (h *UserJobsHandler) DeleteFinishedJobs(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Delete all completed jobs
-	deletedCount, err := h.jobExecRepo.DeleteFinished(ctx)
+	// Move all completed jobs to the trash
+	deletedCount, err := h.trashService.DeleteFinishedJobs(ctx, deletingUserID(ctx))
 	if err != nil {
 		debug.Error("Failed to delete finished jobs: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -1308,20 +2271,13 @@ func (h *UserJobsHandler) ListUserJobs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse query parameters
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page < 1 {
-		page = 1
-	}
-
-	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
-	if pageSize < 1 || pageSize > 200 {
-		pageSize = 25
-	}
+	listQuery := httputil.ParseListQuery(r, 25, 200)
+	page, pageSize := listQuery.Page, listQuery.PageSize
 
 	// Parse filters
-	status := r.URL.Query().Get("status")
-	priorityStr := r.URL.Query().Get("priority")
-	search := r.URL.Query().Get("search")
+	status := listQuery.Filter(r, "status")
+	priorityStr := listQuery.Filter(r, "priority")
+	search := listQuery.Filter(r, "search")
 
 	var priority *int
 	if priorityStr != "" {
@@ -1337,6 +2293,7 @@ func (h *UserJobsHandler) ListUserJobs(w http.ResponseWriter, r *http.Request) {
 		Priority: priority,
 		Search:   &search,
 		UserID:   &userID,
+		OrderBy:  listQuery.OrderBy(jobSortColumns),
 	}
 
 	// Get jobs with filters and user information
@@ -1507,9 +2464,16 @@ func (h *UserJobsHandler) ListUserJobs(w http.ResponseWriter, r *http.Request) {
 		summaries = append(summaries, summary)
 	}
 
+	selected, err := httputil.SelectFields(summaries, listQuery.Fields)
+	if err != nil {
+		debug.Error("Failed to apply field selection: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	// Create response
 	response := map[string]interface{}{
-		"jobs":          summaries,
+		"jobs":          selected,
 		"total":         total,
 		"page":          page,
 		"page_size":     pageSize,
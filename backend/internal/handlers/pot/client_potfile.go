@@ -0,0 +1,105 @@
+package pot
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/plaintext"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// HandleDownloadClientPotfile handles GET /api/pot/client/{id}/download/potfile,
+// streaming a hashcat potfile of every hash cracked in the client's hashlists.
+// Each hash and password pair appears once, with passwords in $HEX[...]
+// notation when they are not printable text. ?from= and ?to= (RFC 3339 or
+// YYYY-MM-DD, to being inclusive for a date) limit it to hashes cracked in
+// that range.
+func (h *Handler) HandleDownloadClientPotfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clientID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid client ID")
+		return
+	}
+
+	from, err := parsePotfileTime(r.URL.Query().Get("from"), false)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid from date")
+		return
+	}
+	to, err := parsePotfileTime(r.URL.Query().Get("to"), true)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid to date")
+		return
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		httputil.RespondWithError(w, http.StatusBadRequest, "from must be before to")
+		return
+	}
+
+	client, err := h.clientRepo.GetByID(ctx, clientID)
+	if errors.Is(err, repository.ErrNotFound) {
+		httputil.RespondWithError(w, http.StatusNotFound, "Client not found")
+		return
+	}
+	if err != nil {
+		debug.Error("Failed to get client %s: %v", clientID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve client")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.potfile\"", sanitizeFilename(client.Name)))
+
+	out := bufio.NewWriter(w)
+	count, written := 0, 0
+	err = h.hashRepo.StreamClientPotfile(ctx, clientID, from, to, func(hashValue string, plain []byte) error {
+		count++
+		n, err := fmt.Fprintf(out, "%s:%s\n", hashValue, plaintext.Hashcat(plain))
+		written += n
+		return err
+	})
+	if err != nil {
+		debug.Error("Failed to export potfile for client %s after %d entries: %v", clientID, count, err)
+		// Once part of the file reached the client the status can no longer
+		// change, so the download just ends short
+		if out.Buffered() == written {
+			w.Header().Del("Content-Disposition")
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to export potfile")
+		}
+		return
+	}
+	if err := out.Flush(); err != nil {
+		debug.Error("Failed to write potfile for client %s: %v", clientID, err)
+		return
+	}
+
+	debug.Info("Exported potfile with %d entries for client %s", count, clientID)
+}
+
+// parsePotfileTime parses an RFC 3339 time or a YYYY-MM-DD date. An end date
+// is moved to the start of the next day so the whole day is included.
+func parsePotfileTime(value string, end bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
@@ -0,0 +1,43 @@
+package pot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePotfileTime(t *testing.T) {
+	t.Run("empty means no bound", func(t *testing.T) {
+		got, err := parsePotfileTime("", false)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("RFC 3339 is taken as is", func(t *testing.T) {
+		got, err := parsePotfileTime("2026-03-01T12:30:00Z", true)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC), got.UTC())
+	})
+
+	t.Run("start date is the start of the day", func(t *testing.T) {
+		got, err := parsePotfileTime("2026-03-01", false)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *got)
+	})
+
+	t.Run("end date includes the whole day", func(t *testing.T) {
+		got, err := parsePotfileTime("2026-03-01", true)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), *got)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := parsePotfileTime("last week", false)
+		assert.Error(t, err)
+	})
+}
@@ -1,6 +1,7 @@
 package pot
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/plaintext"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
@@ -39,6 +41,7 @@ type CrackedHashResponse struct {
 	ID           uuid.UUID `json:"id"`
 	OriginalHash string    `json:"original_hash"`
 	Password     string    `json:"password"`
+	PasswordHex  string    `json:"password_hex"` // Exact bytes of the password
 	HashTypeID   int       `json:"hash_type_id"`
 	Username     *string   `json:"username,omitempty"`
 }
@@ -250,6 +253,7 @@ func (h *Handler) formatPotResponse(hashes []*models.Hash, totalCount int64, lim
 			ID:           hash.ID,
 			OriginalHash: displayHash,
 			Password:     hash.Password,
+			PasswordHex:  hex.EncodeToString(hash.PlainBytes()),
 			HashTypeID:   hash.HashTypeID,
 			Username:     hash.Username,
 		})
@@ -281,7 +285,7 @@ func (h *Handler) HandleDownloadHashPass(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	
-	h.writeHashPassFormat(w, hashes, "master")
+	h.writeHashPassFormat(w, r, hashes, "master")
 }
 
 func (h *Handler) HandleDownloadUserPass(w http.ResponseWriter, r *http.Request) {
@@ -299,7 +303,7 @@ func (h *Handler) HandleDownloadUserPass(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	
-	h.writeUserPassFormat(w, hashes, "master")
+	h.writeUserPassFormat(w, r, hashes, "master")
 }
 
 func (h *Handler) HandleDownloadUser(w http.ResponseWriter, r *http.Request) {
@@ -317,7 +321,7 @@ func (h *Handler) HandleDownloadUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	h.writeUserFormat(w, hashes, "master")
+	h.writeUserFormat(w, r, hashes, "master")
 }
 
 func (h *Handler) HandleDownloadPass(w http.ResponseWriter, r *http.Request) {
@@ -335,7 +339,7 @@ func (h *Handler) HandleDownloadPass(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	h.writePassFormat(w, hashes, "master")
+	h.writePassFormat(w, r, hashes, "master")
 }
 
 // Download handlers for hashlist-specific cracked hashes
@@ -372,7 +376,7 @@ func (h *Handler) HandleDownloadHashPassByHashlist(w http.ResponseWriter, r *htt
 		return
 	}
 	
-	h.writeHashPassFormat(w, hashes, sanitizeFilename(hashlist.Name))
+	h.writeHashPassFormat(w, r, hashes, sanitizeFilename(hashlist.Name))
 }
 
 func (h *Handler) HandleDownloadUserPassByHashlist(w http.ResponseWriter, r *http.Request) {
@@ -406,7 +410,7 @@ func (h *Handler) HandleDownloadUserPassByHashlist(w http.ResponseWriter, r *htt
 		return
 	}
 	
-	h.writeUserPassFormat(w, hashes, sanitizeFilename(hashlist.Name))
+	h.writeUserPassFormat(w, r, hashes, sanitizeFilename(hashlist.Name))
 }
 
 func (h *Handler) HandleDownloadUserByHashlist(w http.ResponseWriter, r *http.Request) {
@@ -440,7 +444,7 @@ func (h *Handler) HandleDownloadUserByHashlist(w http.ResponseWriter, r *http.Re
 		return
 	}
 	
-	h.writeUserFormat(w, hashes, sanitizeFilename(hashlist.Name))
+	h.writeUserFormat(w, r, hashes, sanitizeFilename(hashlist.Name))
 }
 
 func (h *Handler) HandleDownloadPassByHashlist(w http.ResponseWriter, r *http.Request) {
@@ -474,7 +478,7 @@ func (h *Handler) HandleDownloadPassByHashlist(w http.ResponseWriter, r *http.Re
 		return
 	}
 	
-	h.writePassFormat(w, hashes, sanitizeFilename(hashlist.Name))
+	h.writePassFormat(w, r, hashes, sanitizeFilename(hashlist.Name))
 }
 
 // Download handlers for client-specific cracked hashes
@@ -511,7 +515,7 @@ func (h *Handler) HandleDownloadHashPassByClient(w http.ResponseWriter, r *http.
 		return
 	}
 	
-	h.writeHashPassFormat(w, hashes, sanitizeFilename(client.Name))
+	h.writeHashPassFormat(w, r, hashes, sanitizeFilename(client.Name))
 }
 
 func (h *Handler) HandleDownloadUserPassByClient(w http.ResponseWriter, r *http.Request) {
@@ -545,7 +549,7 @@ func (h *Handler) HandleDownloadUserPassByClient(w http.ResponseWriter, r *http.
 		return
 	}
 	
-	h.writeUserPassFormat(w, hashes, sanitizeFilename(client.Name))
+	h.writeUserPassFormat(w, r, hashes, sanitizeFilename(client.Name))
 }
 
 func (h *Handler) HandleDownloadUserByClient(w http.ResponseWriter, r *http.Request) {
@@ -579,7 +583,7 @@ func (h *Handler) HandleDownloadUserByClient(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	
-	h.writeUserFormat(w, hashes, sanitizeFilename(client.Name))
+	h.writeUserFormat(w, r, hashes, sanitizeFilename(client.Name))
 }
 
 func (h *Handler) HandleDownloadPassByClient(w http.ResponseWriter, r *http.Request) {
@@ -613,7 +617,7 @@ func (h *Handler) HandleDownloadPassByClient(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	
-	h.writePassFormat(w, hashes, sanitizeFilename(client.Name))
+	h.writePassFormat(w, r, hashes, sanitizeFilename(client.Name))
 }
 
 // Job-specific download handlers
@@ -650,7 +654,7 @@ func (h *Handler) HandleDownloadHashPassByJob(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	h.writeHashPassFormat(w, hashes, sanitizeFilename(job.Name))
+	h.writeHashPassFormat(w, r, hashes, sanitizeFilename(job.Name))
 }
 
 func (h *Handler) HandleDownloadUserPassByJob(w http.ResponseWriter, r *http.Request) {
@@ -685,7 +689,7 @@ func (h *Handler) HandleDownloadUserPassByJob(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	h.writeUserPassFormat(w, hashes, sanitizeFilename(job.Name))
+	h.writeUserPassFormat(w, r, hashes, sanitizeFilename(job.Name))
 }
 
 func (h *Handler) HandleDownloadUserByJob(w http.ResponseWriter, r *http.Request) {
@@ -720,7 +724,7 @@ func (h *Handler) HandleDownloadUserByJob(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	h.writeUserFormat(w, hashes, sanitizeFilename(job.Name))
+	h.writeUserFormat(w, r, hashes, sanitizeFilename(job.Name))
 }
 
 func (h *Handler) HandleDownloadPassByJob(w http.ResponseWriter, r *http.Request) {
@@ -755,12 +759,12 @@ func (h *Handler) HandleDownloadPassByJob(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	h.writePassFormat(w, hashes, sanitizeFilename(job.Name))
+	h.writePassFormat(w, r, hashes, sanitizeFilename(job.Name))
 }
 
 // Helper functions for writing different formats
 
-func (h *Handler) writeHashPassFormat(w http.ResponseWriter, hashes []*models.Hash, context string) {
+func (h *Handler) writeHashPassFormat(w http.ResponseWriter, r *http.Request, hashes []*models.Hash, context string) {
 	w.Header().Set("Content-Type", "text/plain")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-h-p.lst\"", context))
 	
@@ -773,22 +777,22 @@ func (h *Handler) writeHashPassFormat(w http.ResponseWriter, hashes []*models.Ha
 			displayHash = hash.OriginalHash
 		}
 		
-		fmt.Fprintf(w, "%s:%s\n", displayHash, hash.Password)
+		fmt.Fprintf(w, "%s:%s\n", displayHash, exportPassword(r, hash))
 	}
 }
 
-func (h *Handler) writeUserPassFormat(w http.ResponseWriter, hashes []*models.Hash, context string) {
+func (h *Handler) writeUserPassFormat(w http.ResponseWriter, r *http.Request, hashes []*models.Hash, context string) {
 	w.Header().Set("Content-Type", "text/plain")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-u-p.lst\"", context))
 	
 	for _, hash := range hashes {
 		if hash.Username != nil && *hash.Username != "" {
-			fmt.Fprintf(w, "%s:%s\n", *hash.Username, hash.Password)
+			fmt.Fprintf(w, "%s:%s\n", *hash.Username, exportPassword(r, hash))
 		}
 	}
 }
 
-func (h *Handler) writeUserFormat(w http.ResponseWriter, hashes []*models.Hash, context string) {
+func (h *Handler) writeUserFormat(w http.ResponseWriter, r *http.Request, hashes []*models.Hash, context string) {
 	w.Header().Set("Content-Type", "text/plain")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-u.lst\"", context))
 	
@@ -799,15 +803,25 @@ func (h *Handler) writeUserFormat(w http.ResponseWriter, hashes []*models.Hash,
 	}
 }
 
-func (h *Handler) writePassFormat(w http.ResponseWriter, hashes []*models.Hash, context string) {
+func (h *Handler) writePassFormat(w http.ResponseWriter, r *http.Request, hashes []*models.Hash, context string) {
 	w.Header().Set("Content-Type", "text/plain")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-p.lst\"", context))
 	
 	for _, hash := range hashes {
-		fmt.Fprintf(w, "%s\n", hash.Password)
+		fmt.Fprintf(w, "%s\n", exportPassword(r, hash))
 	}
 }
 
+// exportPassword returns the password to write in a download. With
+// ?plain=hashcat, passwords that are not printable text are written with their
+// exact bytes in $HEX[...] notation, like hashcat writes its own potfile.
+func exportPassword(r *http.Request, hash *models.Hash) string {
+	if r.URL.Query().Get("plain") == "hashcat" {
+		return plaintext.Hashcat(hash.PlainBytes())
+	}
+	return hash.Password
+}
+
 // sanitizeFilename removes or replaces characters that are problematic in filenames
 func sanitizeFilename(name string) string {
 	// Replace spaces with underscores
@@ -0,0 +1,120 @@
+package pot
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+)
+
+// maxLookupHashes caps how many hashes one lookup may ask about
+const maxLookupHashes = 1000
+
+// CrackLookupRequest is the body of a crack lookup
+type CrackLookupRequest struct {
+	Hashes     []string `json:"hashes"`
+	HashTypeID *int     `json:"hash_type_id"` // Only match hashes of this type when set
+}
+
+// HandleLookup handles POST /api/pot/lookup, reporting whether each hash was
+// ever cracked on this instance along with its plaintext and where it came from.
+// Cracks that only exist in hashlists excluded from the potfile, directly or
+// through their client, are only visible to the owners of those hashlists.
+func (h *Handler) HandleLookup(w http.ResponseWriter, r *http.Request) {
+	userIDStr, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CrackLookupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	hashes := make([]string, 0, len(req.Hashes))
+	for _, hash := range req.Hashes {
+		if hash = strings.TrimSpace(hash); hash != "" {
+			hashes = append(hashes, hash)
+		}
+	}
+	if len(hashes) == 0 {
+		httputil.RespondWithError(w, http.StatusBadRequest, "'hashes' array cannot be empty")
+		return
+	}
+	if len(hashes) > maxLookupHashes {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Too many hashes requested (max 1000)")
+		return
+	}
+
+	found, err := h.hashRepo.GetCrackProvenance(r.Context(), hashes, req.HashTypeID)
+	if err != nil {
+		debug.Error("Failed to look up cracked hashes: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to look up cracked hashes")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, buildCrackLookup(hashes, found, userID))
+}
+
+// buildCrackLookup answers each requested hash from the cracked hashes found
+// for them, dropping the sources userID may not see. A hash whose every source
+// is hidden is reported as not cracked so its existence does not leak.
+// Requested hashes matching several hash types get one result per type.
+func buildCrackLookup(requested []string, found []repository.CrackProvenance, userID uuid.UUID) []models.CrackLookupResult {
+	results := make([]models.CrackLookupResult, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, value := range requested {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+
+		matched := false
+		for _, p := range found {
+			if p.Hash.HashValue != value && p.Hash.OriginalHash != value {
+				continue
+			}
+			sources := make([]models.CrackSource, 0, len(p.Sources))
+			for _, source := range p.Sources {
+				if !source.Restricted || source.OwnerID == userID {
+					sources = append(sources, source)
+				}
+			}
+			if len(sources) == 0 {
+				continue
+			}
+
+			matched = true
+			password := p.Hash.Password
+			hashTypeID := p.Hash.HashTypeID
+			crackedAt := p.Hash.LastUpdated
+			results = append(results, models.CrackLookupResult{
+				Hash:       value,
+				Cracked:    true,
+				Password:   &password,
+				HashTypeID: &hashTypeID,
+				CrackedAt:  &crackedAt,
+				Sources:    sources,
+			})
+		}
+		if !matched {
+			results = append(results, models.CrackLookupResult{
+				Hash:    value,
+				Sources: []models.CrackSource{},
+			})
+		}
+	}
+	return results
+}
@@ -0,0 +1,63 @@
+package pot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCrackLookup(t *testing.T) {
+	owner := uuid.New()
+	other := uuid.New()
+	crackedAt := time.Now()
+
+	found := []repository.CrackProvenance{
+		{
+			Hash: models.Hash{ID: uuid.New(), HashValue: "aaa", OriginalHash: "admin:aaa", HashTypeID: 1000, Password: "Summer2024", LastUpdated: crackedAt},
+			Sources: []models.CrackSource{
+				{HashlistID: 1, OwnerID: owner},
+				{HashlistID: 2, OwnerID: owner, Restricted: true},
+			},
+		},
+		{
+			Hash:    models.Hash{ID: uuid.New(), HashValue: "bbb", OriginalHash: "bbb", HashTypeID: 0, Password: "secret"},
+			Sources: []models.CrackSource{{HashlistID: 3, OwnerID: owner, Restricted: true}},
+		},
+	}
+
+	t.Run("restricted sources are hidden from other users", func(t *testing.T) {
+		results := buildCrackLookup([]string{"aaa", "bbb", "ccc"}, found, other)
+		require.Len(t, results, 3)
+
+		assert.True(t, results[0].Cracked)
+		require.NotNil(t, results[0].Password)
+		assert.Equal(t, "Summer2024", *results[0].Password)
+		require.Len(t, results[0].Sources, 1)
+		assert.Equal(t, int64(1), results[0].Sources[0].HashlistID)
+
+		assert.False(t, results[1].Cracked, "crack only known from a restricted hashlist")
+		assert.Nil(t, results[1].Password)
+
+		assert.False(t, results[2].Cracked)
+		assert.Empty(t, results[2].Sources)
+	})
+
+	t.Run("owners see their restricted sources", func(t *testing.T) {
+		results := buildCrackLookup([]string{"bbb", "aaa"}, found, owner)
+		require.Len(t, results, 2)
+		assert.True(t, results[0].Cracked)
+		assert.Len(t, results[1].Sources, 2)
+	})
+
+	t.Run("matches the original hash line and skips duplicates", func(t *testing.T) {
+		results := buildCrackLookup([]string{"admin:aaa", "admin:aaa"}, found, other)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Cracked)
+		assert.Equal(t, "admin:aaa", results[0].Hash)
+	})
+}
@@ -1,14 +1,21 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/tls"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 )
 
 // RegistrationRequest represents the data sent by the agent during registration
@@ -124,3 +131,209 @@ func (h *RegistrationHandler) HandleRegistration(w http.ResponseWriter, r *http.
 
 	debug.Info("Successfully completed registration for agent %d", agent.ID)
 }
+
+// BootstrapRequest represents the data sent by a pre-registered agent on first start
+type BootstrapRequest struct {
+	BootstrapToken string `json:"bootstrap_token"`
+	Hostname       string `json:"hostname"`
+	Version        string `json:"version,omitempty"`
+}
+
+// PreregistrationRequest represents a request to provision agents ahead of time
+type PreregistrationRequest struct {
+	Mode  string   `json:"mode"`  // "credentials" (default) or "claim_codes"
+	Names []string `json:"names"` // Agent names, required for credentials mode
+	Count int      `json:"count"` // Number of claim codes, for claim_codes mode
+}
+
+// PreregisteredAgent is one agent in a pre-registration response. The embedded
+// credentials can be written to the agent's config directory directly, or the
+// agent can fetch them itself with its bootstrap token.
+type PreregisteredAgent struct {
+	Name           string `json:"name"`
+	BootstrapToken string `json:"bootstrap_token"`
+	Created        bool   `json:"created"` // False if the agent was pre-registered by an earlier request
+	RegistrationResponse
+}
+
+// PreregistrationResponse represents the result of a pre-registration request
+type PreregistrationResponse struct {
+	Agents     []PreregisteredAgent `json:"agents,omitempty"`
+	ClaimCodes []string             `json:"claim_codes,omitempty"`
+}
+
+// credentials builds the registration response that hands an agent its API key and certificates
+func (h *RegistrationHandler) credentials(agent *models.Agent) (*RegistrationResponse, error) {
+	certPEM, keyPEM, err := h.tlsProvider.GetClientCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client certificate: %w", err)
+	}
+
+	caCertPEM, err := h.tlsProvider.ExportCACertificate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CA certificate: %w", err)
+	}
+
+	return &RegistrationResponse{
+		AgentID: agent.ID,
+		APIKey:  agent.APIKey.String,
+		Endpoints: map[string]string{
+			"websocket": h.config.GetWSEndpoint(),
+			"api":       h.config.GetAPIEndpoint(),
+		},
+		Certificate:   string(certPEM),
+		PrivateKey:    string(keyPEM),
+		CACertificate: string(caCertPEM),
+	}, nil
+}
+
+// HandleBootstrap handles POST /agent/bootstrap, issuing credentials to a
+// pre-registered agent that presents its bootstrap token
+func (h *RegistrationHandler) HandleBootstrap(w http.ResponseWriter, r *http.Request) {
+	var req BootstrapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		debug.Error("Failed to decode bootstrap request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	agent, err := h.agentService.BootstrapAgent(r.Context(), req.BootstrapToken, req.Hostname, req.Version)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBootstrapToken) {
+			debug.Warning("Agent bootstrap from %s rejected: invalid token", r.RemoteAddr)
+			http.Error(w, "Invalid bootstrap token", http.StatusUnauthorized)
+			return
+		}
+		debug.Error("Failed to bootstrap agent: %v", err)
+		http.Error(w, "Failed to bootstrap agent", http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := h.credentials(agent)
+	if err != nil {
+		debug.Error("Failed to issue credentials to agent %d: %v", agent.ID, err)
+		http.Error(w, "Failed to issue credentials", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandlePreregistration handles POST /agents/preregister. In credentials mode
+// it creates the named agents and returns each one's credentials and bootstrap
+// token; names that were already pre-registered by the caller are returned
+// unchanged. In claim_codes mode it returns count single-use claim codes.
+func (h *RegistrationHandler) HandlePreregistration(w http.ResponseWriter, r *http.Request) {
+	userIDStr, _ := r.Context().Value("user_id").(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req PreregistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var resp PreregistrationResponse
+	switch req.Mode {
+	case "claim_codes":
+		if req.Count < 1 || req.Count > services.MaxPreregisteredAgents {
+			http.Error(w, fmt.Sprintf("count must be between 1 and %d", services.MaxPreregisteredAgents), http.StatusBadRequest)
+			return
+		}
+		resp.ClaimCodes, err = h.agentService.CreateClaimCodes(r.Context(), userID, req.Count)
+		if err != nil {
+			debug.Error("Failed to create claim codes: %v", err)
+			http.Error(w, "Failed to create claim codes", http.StatusInternalServerError)
+			return
+		}
+		debug.Info("User %s pre-registered %d claim codes", userID, len(resp.ClaimCodes))
+
+	case "", "credentials":
+		if len(req.Names) < 1 || len(req.Names) > services.MaxPreregisteredAgents {
+			http.Error(w, fmt.Sprintf("names must list between 1 and %d agents", services.MaxPreregisteredAgents), http.StatusBadRequest)
+			return
+		}
+		seen := make(map[string]bool, len(req.Names))
+		for _, name := range req.Names {
+			if strings.TrimSpace(name) == "" || seen[name] {
+				http.Error(w, "Agent names must be unique and non-empty", http.StatusBadRequest)
+				return
+			}
+			seen[name] = true
+		}
+
+		for _, name := range req.Names {
+			agent, token, created, err := h.agentService.PreregisterAgent(r.Context(), userID, name)
+			if err != nil {
+				if errors.Is(err, services.ErrAgentNameTaken) {
+					http.Error(w, fmt.Sprintf("Agent name %q is already in use", name), http.StatusConflict)
+					return
+				}
+				debug.Error("Failed to pre-register agent %s: %v", name, err)
+				http.Error(w, "Failed to pre-register agent", http.StatusInternalServerError)
+				return
+			}
+
+			creds, err := h.credentials(agent)
+			if err != nil {
+				debug.Error("Failed to issue credentials to agent %d: %v", agent.ID, err)
+				http.Error(w, "Failed to issue credentials", http.StatusInternalServerError)
+				return
+			}
+
+			resp.Agents = append(resp.Agents, PreregisteredAgent{
+				Name:                 agent.Name,
+				BootstrapToken:       token,
+				Created:              created,
+				RegistrationResponse: *creds,
+			})
+		}
+
+	default:
+		http.Error(w, "mode must be credentials or claim_codes", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RevokeBootstrapToken handles DELETE /agents/{id}/bootstrap-token. Only the
+// agent's owner or an administrator may revoke it.
+func (h *RegistrationHandler) RevokeBootstrapToken(w http.ResponseWriter, r *http.Request) {
+	userIDStr, _ := r.Context().Value("user_id").(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	role, _ := r.Context().Value("user_role").(string)
+
+	agentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid agent ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.agentService.RevokeBootstrapToken(r.Context(), agentID, userID, role == "admin"); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Agent not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, services.ErrNotAgentOwner) {
+			http.Error(w, "Only the agent's owner or an administrator can revoke its bootstrap token", http.StatusForbidden)
+			return
+		}
+		debug.Error("Failed to revoke bootstrap token of agent %d: %v", agentID, err)
+		http.Error(w, "Failed to revoke bootstrap token", http.StatusInternalServerError)
+		return
+	}
+
+	debug.Info("Revoked bootstrap token of agent %d", agentID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,8 +2,10 @@ package rule
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -20,6 +22,7 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/fsutil"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/textnorm"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/mazrean/formstream"
@@ -27,8 +30,20 @@ import (
 
 // Handler handles rule management HTTP requests
 type Handler struct {
-	manager rule.Manager
-	config  *config.Config
+	manager    rule.Manager
+	config     *config.Config
+	agentFiles AgentFileRemover
+}
+
+// AgentFileRemover removes a deleted file from the caches of connected agents
+type AgentFileRemover interface {
+	RemoveFileFromAgents(fileType, name, category string)
+}
+
+// deleteConflictResponse is returned when a rule in use cannot be deleted
+type deleteConflictResponse struct {
+	Error      string                 `json:"error"`
+	References *models.FileReferences `json:"references,omitempty"`
 }
 
 // NewHandler creates a new rule management handler
@@ -39,6 +54,11 @@ func NewHandler(manager rule.Manager, cfg *config.Config) *Handler {
 	}
 }
 
+// SetAgentFileRemover sets what removes deleted rules from agent caches
+func (h *Handler) SetAgentFileRemover(remover AgentFileRemover) {
+	h.agentFiles = remover
+}
+
 // Request/Response types
 type AddRuleRequest struct {
 	Name        string   `json:"name"`
@@ -236,6 +256,8 @@ func (h *Handler) HandleAddRule(w http.ResponseWriter, r *http.Request) {
 		ruleName, description, ruleType, tagsStr string
 		fileName                                 string
 		md5Hash                                  string
+		canonicalHash                            string
+		onDuplicate                              = models.OnDuplicateAlias
 		fileSize                                 int64
 		ruleCount                                int64
 		destPath                                 string
@@ -283,6 +305,19 @@ func (h *Handler) HandleAddRule(w http.ResponseWriter, r *http.Request) {
 		return nil
 	})
 
+	parser.Register("on_duplicate", func(r io.Reader, header formstream.Header) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		onDuplicate = strings.TrimSpace(string(data))
+		if onDuplicate != models.OnDuplicateAlias && onDuplicate != models.OnDuplicateKeep {
+			return fmt.Errorf("invalid on_duplicate %q, must be %q or %q", onDuplicate, models.OnDuplicateAlias, models.OnDuplicateKeep)
+		}
+		debug.Info("HandleAddRule: Received on_duplicate: %s", onDuplicate)
+		return nil
+	})
+
 	// Register handler for file streaming
 	parser.Register("file", func(r io.Reader, header formstream.Header) error {
 		fileName = header.FileName()
@@ -300,8 +335,9 @@ func (h *Handler) HandleAddRule(w http.ResponseWriter, r *http.Request) {
 
 		// Create a tee reader to count rules while streaming
 		hasher := md5.New()
+		canonical := textnorm.NewHasher(true)
 		lineCounter := &ruleCounter{}
-		writer := io.MultiWriter(tempFile, hasher, lineCounter)
+		writer := io.MultiWriter(tempFile, hasher, canonical, lineCounter)
 
 		// Stream file to temp location and calculate MD5 simultaneously
 		bytesWritten, err := io.CopyBuffer(writer, r, make([]byte, 32*1024))
@@ -313,6 +349,7 @@ func (h *Handler) HandleAddRule(w http.ResponseWriter, r *http.Request) {
 
 		// Store results for later use
 		md5Hash = fmt.Sprintf("%x", hasher.Sum(nil))
+		canonicalHash = canonical.Sum()
 		fileSize = bytesWritten
 		ruleCount = lineCounter.count
 		destPath = tempPath // Store temp path for now
@@ -437,6 +474,7 @@ func (h *Handler) HandleAddRule(w http.ResponseWriter, r *http.Request) {
 		debug.Info("HandleAddRule: Duplicate rule detected with MD5 hash: %s", md5Hash)
 		// Remove the uploaded file since it's a duplicate
 		os.Remove(destPath)
+		h.aliasDuplicate(ctx, existingRule, fileName, userID)
 		httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
 			"id":        existingRule.ID,
 			"name":      existingRule.Name,
@@ -447,6 +485,31 @@ func (h *Handler) HandleAddRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A file only differing in comments, blank lines, BOM or line endings is
+	// aliased too, unless the uploader asked to keep a separate copy
+	if onDuplicate == models.OnDuplicateAlias {
+		existingRule, err = h.manager.GetRuleByCanonicalHash(ctx, canonicalHash)
+		if err != nil {
+			debug.Error("Failed to check for near-duplicate rule: %v", err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to check for duplicate rule")
+			return
+		}
+		if existingRule != nil {
+			debug.Info("HandleAddRule: Near-duplicate of rule %d detected with canonical hash: %s", existingRule.ID, canonicalHash)
+			os.Remove(destPath)
+			h.aliasDuplicate(ctx, existingRule, fileName, userID)
+			httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+				"id":             existingRule.ID,
+				"name":           existingRule.Name,
+				"message":        "Rule already exists with only comment, whitespace or line ending differences, the upload was aliased to it",
+				"duplicate":      true,
+				"near_duplicate": true,
+				"success":        true,
+			})
+			return
+		}
+	}
+
 	debug.Info("HandleAddRule: No duplicate rule found, proceeding with database entry")
 
 	// Check if a file with the same name already exists
@@ -518,6 +581,11 @@ func (h *Handler) HandleAddRule(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if err := h.manager.UpdateRuleCanonicalHash(ctx, ruleObj.ID, canonicalHash); err != nil {
+		// Only costs duplicate detection of later uploads
+		debug.Warning("HandleAddRule: Failed to record canonical hash of rule %d: %v", ruleObj.ID, err)
+	}
+
 	// Perform verification (if needed)
 	verifyReq := &models.RuleVerifyRequest{
 		Status:    "verified",
@@ -534,6 +602,18 @@ func (h *Handler) HandleAddRule(w http.ResponseWriter, r *http.Request) {
 	httputil.RespondWithJSON(w, http.StatusCreated, ruleObj)
 }
 
+// aliasDuplicate records the name of an upload that was dropped as a duplicate
+// of an existing rule, unless it is the existing file's own name
+func (h *Handler) aliasDuplicate(ctx context.Context, existing *models.Rule, fileName string, userID uuid.UUID) {
+	alias := fsutil.SanitizeFilename(fileName)
+	if alias == "" || alias == filepath.Base(existing.FileName) {
+		return
+	}
+	if err := h.manager.AddRuleAlias(ctx, existing.ID, alias, userID); err != nil {
+		debug.Warning("Failed to alias %s to rule %d: %v", alias, existing.ID, err)
+	}
+}
+
 // HandleUpdateRule handles updating a rule
 func (h *Handler) HandleUpdateRule(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -606,20 +686,62 @@ func (h *Handler) HandleDeleteRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// force also removes the rule from the preset jobs using it
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+
 	// Delete rule
-	if err := h.manager.DeleteRule(ctx, id); err != nil {
-		if err == models.ErrResourceInUse {
-			httputil.RespondWithError(w, http.StatusConflict, "Cannot delete rule: it is currently being used by active jobs")
-			return
+	deleted, err := h.manager.DeleteRule(ctx, id, force)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrResourceInUse):
+			h.respondDeleteConflict(w, r, id, "Cannot delete rule: it is currently being used by active jobs")
+		case errors.Is(err, models.ErrResourceReferenced):
+			h.respondDeleteConflict(w, r, id, "Cannot delete rule: it is used by preset jobs, delete with force to remove it from them")
+		default:
+			debug.Error("Failed to delete rule %d: %v", id, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to delete rule")
 		}
-		debug.Error("Failed to delete rule %d: %v", id, err)
-		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to delete rule")
 		return
 	}
 
+	if h.agentFiles != nil {
+		h.agentFiles.RemoveFileFromAgents("rule", deleted.FileName, deleted.RuleType)
+	}
+
 	httputil.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Rule deleted successfully"})
 }
 
+// respondDeleteConflict responds to a refused delete with what uses the rule
+func (h *Handler) respondDeleteConflict(w http.ResponseWriter, r *http.Request, id int, message string) {
+	refs, err := h.manager.GetRuleReferences(r.Context(), id)
+	if err != nil {
+		debug.Error("Failed to get references of rule %d: %v", id, err)
+	}
+	httputil.RespondWithJSON(w, http.StatusConflict, deleteConflictResponse{Error: message, References: refs})
+}
+
+// HandleGetRuleReferences lists the preset jobs and active jobs using a rule
+func (h *Handler) HandleGetRuleReferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get rule ID from URL
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+
+	refs, err := h.manager.GetRuleReferences(ctx, id)
+	if err != nil {
+		debug.Error("Failed to get references of rule %d: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get rule references")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, refs)
+}
+
 // HandleVerifyRule handles verifying a rule
 func (h *Handler) HandleVerifyRule(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -875,3 +997,95 @@ func convertRuleToResponse(rule *models.Rule) RuleResponse {
 
 	return response
 }
+
+// HandleMoveRule handles requests to move a rule file into another directory
+func (h *Handler) HandleMoveRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get rule ID from URL
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+
+	var req models.FileMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rule, err := h.manager.MoveRule(ctx, id, req.Directory)
+	if err != nil {
+		respondWithMoveError(w, "Failed to move rule", err)
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, convertRuleToResponse(rule))
+}
+
+// HandleListDirectories handles requests to list the rule directories
+func (h *Handler) HandleListDirectories(w http.ResponseWriter, r *http.Request) {
+	dirs, err := h.manager.ListDirectories(r.Context())
+	if err != nil {
+		debug.Error("Failed to list rule directories: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list directories")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, dirs)
+}
+
+// HandleCreateDirectory handles requests to create a rule directory
+func (h *Handler) HandleCreateDirectory(w http.ResponseWriter, r *http.Request) {
+	var req models.DirectoryCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	dir, err := h.manager.CreateDirectory(r.Context(), req.Path)
+	if err != nil {
+		respondWithMoveError(w, "Failed to create directory", err)
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusCreated, models.ResourceDirectory{Path: dir})
+}
+
+// HandleMoveDirectory handles requests to rename or move a rule directory
+// with all rules inside it
+func (h *Handler) HandleMoveDirectory(w http.ResponseWriter, r *http.Request) {
+	var req models.DirectoryMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	moved, err := h.manager.MoveDirectory(r.Context(), req.From, req.To)
+	if err != nil {
+		respondWithMoveError(w, "Failed to move directory", err)
+		return
+	}
+
+	dir, _ := fsutil.CleanRelativeDir(req.To)
+	httputil.RespondWithJSON(w, http.StatusOK, models.DirectoryMoveResponse{Path: dir, FilesMoved: moved})
+}
+
+// respondWithMoveError maps the errors of directory and move operations to a response
+func respondWithMoveError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidInput):
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrNotFound):
+		httputil.RespondWithError(w, http.StatusNotFound, "Not found")
+	case errors.Is(err, models.ErrAlreadyExists):
+		httputil.RespondWithError(w, http.StatusConflict, "The destination already exists")
+	case errors.Is(err, models.ErrResourceInUse):
+		httputil.RespondWithError(w, http.StatusConflict, "A rule in use by active jobs cannot be moved")
+	default:
+		debug.Error("%s: %v", message, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,77 @@
+package search
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// Handler handles searches across jobs and hashlists
+type Handler struct {
+	searchRepo *repository.SearchRepository
+}
+
+// NewHandler creates a new search handler
+func NewHandler(searchRepo *repository.SearchRepository) *Handler {
+	return &Handler{searchRepo: searchRepo}
+}
+
+// Search handles GET /search?q=...&tags=a,b&type=job|hashlist&limit=N
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	params := repository.SearchParams{
+		Query: strings.TrimSpace(query.Get("q")),
+		Type:  query.Get("type"),
+		Limit: defaultSearchLimit,
+	}
+	if params.Type != "" && params.Type != models.SearchResultJob && params.Type != models.SearchResultHashlist {
+		httputil.RespondWithError(w, http.StatusBadRequest, "type must be job or hashlist")
+		return
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		if limit > maxSearchLimit {
+			limit = maxSearchLimit
+		}
+		params.Limit = limit
+	}
+	if tagsStr := query.Get("tags"); tagsStr != "" {
+		tags, err := models.NormalizeTags(strings.Split(tagsStr, ","))
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.Tags = tags
+	}
+	if params.Query == "" && len(params.Tags) == 0 {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Provide a search query or tags")
+		return
+	}
+
+	results, err := h.searchRepo.Search(r.Context(), params)
+	if err != nil {
+		debug.Error("Search for %q failed: %v", params.Query, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Search failed")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"results": results,
+		"count":   len(results),
+	})
+}
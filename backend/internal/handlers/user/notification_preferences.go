@@ -84,6 +84,12 @@ func (h *NotificationPreferencesHandler) UpdateNotificationPreferences(w http.Re
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if prefs.NotificationDigest != "" {
+		if err := models.ValidateNotificationDigest(prefs.NotificationDigest); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	// Update notification preferences
 	if err := h.notificationService.UpdateUserNotificationPreferences(r.Context(), uid, &prefs); err != nil {
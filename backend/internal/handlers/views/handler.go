@@ -0,0 +1,255 @@
+package views
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles saved views: named filters for the job, task and hash
+// lists that are private to their owner or shared with a team
+type Handler struct {
+	viewRepo *repository.SavedViewRepository
+}
+
+// NewHandler creates a new saved view handler
+func NewHandler(viewRepo *repository.SavedViewRepository) *Handler {
+	return &Handler{viewRepo: viewRepo}
+}
+
+// SavedViewRequest is the body of POST /views and PUT /views/{id}
+type SavedViewRequest struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Resource    string                `json:"resource"`
+	Query       models.SavedViewQuery `json:"query"`
+	TeamID      *uuid.UUID            `json:"team_id"` // Share with this team, null keeps the view private
+}
+
+// ListViews handles GET /views?resource=jobs|tasks|hashes, the caller's own
+// views and those shared with their teams
+func (h *Handler) ListViews(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	views, err := h.viewRepo.ListVisible(r.Context(), userID, r.URL.Query().Get("resource"))
+	if err != nil {
+		debug.Error("Failed to list saved views for user %s: %v", userID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list saved views")
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"views": views})
+}
+
+// GetView handles GET /views/{id}
+func (h *Handler) GetView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+	viewID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid view ID")
+		return
+	}
+
+	view, err := h.viewRepo.GetVisible(r.Context(), viewID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		httputil.RespondWithError(w, http.StatusNotFound, "Saved view not found")
+		return
+	}
+	if err != nil {
+		debug.Error("Failed to get saved view %s: %v", viewID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get saved view")
+		return
+	}
+	httputil.RespondWithJSON(w, http.StatusOK, view)
+}
+
+// CreateView handles POST /views
+func (h *Handler) CreateView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+	view, ok := h.viewFromRequest(w, r, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.viewRepo.Create(r.Context(), view); err != nil {
+		h.respondSaveError(w, err)
+		return
+	}
+	h.respondWithView(w, r, http.StatusCreated, view)
+}
+
+// UpdateView handles PUT /views/{id}. Only the owner can change a view.
+func (h *Handler) UpdateView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+	viewID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid view ID")
+		return
+	}
+	view, ok := h.viewFromRequest(w, r, userID)
+	if !ok {
+		return
+	}
+	view.ID = viewID
+
+	if err := h.viewRepo.Update(r.Context(), view); err != nil {
+		h.respondSaveError(w, err)
+		return
+	}
+	h.respondWithView(w, r, http.StatusOK, view)
+}
+
+// DeleteView handles DELETE /views/{id}. Only the owner can delete a view.
+func (h *Handler) DeleteView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+	viewID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid view ID")
+		return
+	}
+
+	err = h.viewRepo.Delete(r.Context(), viewID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		httputil.RespondWithError(w, http.StatusNotFound, "Saved view not found")
+		return
+	}
+	if err != nil {
+		debug.Error("Failed to delete saved view %s: %v", viewID, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to delete saved view")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Apply wraps a list endpoint of the resource so ?view=<id> expands to the
+// view's query parameters. Parameters given in the request take precedence
+// over those of the view.
+func (h *Handler) Apply(resource string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		values := r.URL.Query()
+		viewIDStr := values.Get("view")
+		if viewIDStr == "" {
+			next(w, r)
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+		viewID, err := uuid.Parse(viewIDStr)
+		if err != nil {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Invalid view ID")
+			return
+		}
+		view, err := h.viewRepo.GetVisible(r.Context(), viewID, userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			httputil.RespondWithError(w, http.StatusNotFound, "Saved view not found")
+			return
+		}
+		if err != nil {
+			debug.Error("Failed to get saved view %s: %v", viewID, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get saved view")
+			return
+		}
+		if view.Resource != resource {
+			httputil.RespondWithError(w, http.StatusBadRequest, "Saved view "+view.Name+" is for "+view.Resource+", not "+resource)
+			return
+		}
+
+		values.Del("view")
+		view.Query.Apply(values)
+		r.URL.RawQuery = values.Encode()
+		next(w, r)
+	}
+}
+
+// viewFromRequest decodes and validates a view sent by its owner
+func (h *Handler) viewFromRequest(w http.ResponseWriter, r *http.Request, userID uuid.UUID) (*models.SavedView, bool) {
+	var req SavedViewRequest
+	if err := httputil.ParseJSONBody(r, &req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return nil, false
+	}
+
+	view := &models.SavedView{
+		UserID:      userID,
+		TeamID:      req.TeamID,
+		Name:        req.Name,
+		Description: req.Description,
+		Resource:    req.Resource,
+		Query:       req.Query,
+	}
+	if err := view.Normalize(); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	// A view can only be shared with a team its owner belongs to
+	if view.TeamID != nil {
+		member, err := h.viewRepo.IsTeamMember(r.Context(), userID, *view.TeamID)
+		if err != nil {
+			debug.Error("Failed to check team membership of user %s: %v", userID, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to save view")
+			return nil, false
+		}
+		if !member {
+			httputil.RespondWithError(w, http.StatusForbidden, "You can only share views with your own teams")
+			return nil, false
+		}
+	}
+	return view, true
+}
+
+func (h *Handler) respondSaveError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		httputil.RespondWithError(w, http.StatusNotFound, "Saved view not found")
+	case errors.Is(err, repository.ErrDuplicateRecord):
+		httputil.RespondWithError(w, http.StatusConflict, "You already have a view with this name")
+	default:
+		debug.Error("Failed to save view: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to save view")
+	}
+}
+
+// respondWithView reloads a saved view to include its owner and team names
+func (h *Handler) respondWithView(w http.ResponseWriter, r *http.Request, status int, view *models.SavedView) {
+	saved, err := h.viewRepo.GetVisible(r.Context(), view.ID, view.UserID)
+	if err != nil {
+		debug.Warning("Failed to reload saved view %s: %v", view.ID, err)
+		saved = view
+	}
+	httputil.RespondWithJSON(w, status, saved)
+}
+
+// currentUserID returns the ID of the user making the request
+func currentUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userIDStr, _ := r.Context().Value("user_id").(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
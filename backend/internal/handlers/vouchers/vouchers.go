@@ -2,9 +2,11 @@ package vouchers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/gorilla/mux"
@@ -12,9 +14,11 @@ import (
 
 // GenerateVoucherRequest represents the request to generate a voucher
 type GenerateVoucherRequest struct {
-	UserID       string `json:"userId"`
-	ExpiresIn    int64  `json:"expiresIn"` // Duration in seconds
-	IsContinuous bool   `json:"isContinuous"`
+	UserID       string   `json:"userId"`
+	ExpiresIn    int64    `json:"expiresIn"` // Duration in seconds, 0 never expires
+	IsContinuous bool     `json:"isContinuous"`
+	MaxUses      *int     `json:"maxUses,omitempty"` // Registrations allowed for continuous vouchers
+	Labels       []string `json:"labels,omitempty"`  // Labels applied to registered agents
 }
 
 type VoucherHandler struct {
@@ -46,9 +50,13 @@ func (h *VoucherHandler) GenerateVoucher(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Create voucher
-	voucher, err := h.service.CreateTempVoucher(r.Context(), userID, time.Duration(req.ExpiresIn)*time.Second, req.IsContinuous)
+	voucher, err := h.service.CreateTempVoucher(r.Context(), userID, time.Duration(req.ExpiresIn)*time.Second, req.IsContinuous, req.MaxUses, req.Labels)
 	if err != nil {
 		debug.Error("failed to create voucher: %v", err)
+		if errors.Is(err, services.ErrInvalidVoucherRequest) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -77,6 +85,32 @@ func (h *VoucherHandler) ListVouchers(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(vouchers)
 }
 
+// ListVoucherUsage handles listing the registrations made with a voucher
+func (h *VoucherHandler) ListVoucherUsage(w http.ResponseWriter, r *http.Request) {
+	code := mux.Vars(r)["code"]
+	if code == "" {
+		debug.Error("missing voucher code")
+		http.Error(w, "Missing voucher code", http.StatusBadRequest)
+		return
+	}
+
+	debug.Info("Listing usage of voucher: %s", code)
+
+	usage, err := h.service.ListVoucherUsage(r.Context(), code)
+	if err != nil {
+		debug.Error("failed to list voucher usage: %v", err)
+		if errors.Is(err, repository.ErrNotFound) {
+			http.Error(w, "Voucher not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
+
 // DeactivateVoucher handles voucher deactivation
 func (h *VoucherHandler) DeactivateVoucher(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
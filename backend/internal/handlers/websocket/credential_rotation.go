@@ -0,0 +1,207 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	wsservice "github.com/ZerkerEOD/krakenhashes/backend/internal/services/websocket"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// credentialRotationTimeout is how long a rotation may wait for the agent's
+// confirmation before another one can be started
+const credentialRotationTimeout = 10 * time.Minute
+
+// credentialRotationCheckInterval is how often agents are checked for API
+// keys older than agent_credential_rotation_days
+const credentialRotationCheckInterval = time.Hour
+
+// CertificateIssuer issues client certificates for agents
+type CertificateIssuer interface {
+	GetClientCertificate() ([]byte, []byte, error)
+}
+
+// SetCertificateIssuer makes credential rotations send agents a new client
+// certificate along with their new API key
+func (h *Handler) SetCertificateIssuer(issuer CertificateIssuer) {
+	h.certificates = issuer
+}
+
+// RotateAgentCredentials sends a connected agent a new API key, and a new
+// client certificate if an issuer is set, over its WebSocket connection. The
+// connection and the agent's tasks carry on; the returned rotation is
+// confirmed once the agent stored the credentials. A rotation still waiting
+// for its confirmation is returned instead of starting another, unless it
+// timed out.
+func (h *Handler) RotateAgentCredentials(agentID int) (*models.AgentCredentialRotation, error) {
+	h.mu.RLock()
+	client, ok := h.clients[agentID]
+	h.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: agent %d", services.ErrAgentNotConnected, agentID)
+	}
+
+	h.rotationMu.Lock()
+	defer h.rotationMu.Unlock()
+	if h.credentialRotations == nil {
+		h.credentialRotations = make(map[int]*models.AgentCredentialRotation)
+	}
+	if running := h.credentialRotations[agentID]; running != nil && running.Status == models.AgentCredentialRotationSent && time.Since(running.StartedAt) < credentialRotationTimeout {
+		rotation := *running
+		return &rotation, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rotation, err := h.agentService.BeginCredentialRotation(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	payload := wsservice.CredentialRotationPayload{
+		RotationID:         rotation.RotationID,
+		APIKey:             rotation.APIKey(),
+		GracePeriodSeconds: int(h.agentService.CredentialRotationGrace(ctx) / time.Second),
+	}
+	if h.certificates != nil {
+		if certPEM, keyPEM, err := h.certificates.GetClientCertificate(); err != nil {
+			debug.Warning("Rotating agent %d credentials without a new certificate: %v", agentID, err)
+		} else {
+			payload.ClientCertificate, payload.ClientKey = string(certPEM), string(keyPEM)
+			rotation.Certificate = true
+		}
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credential rotation: %w", err)
+	}
+	if !client.enqueue(&wsservice.Message{Type: wsservice.TypeCredentialRotation, Payload: raw}) {
+		h.failCredentialRotation(ctx, rotation, "send queue full")
+	}
+	h.credentialRotations[agentID] = rotation
+
+	debug.Info("Started credential rotation %s of agent %d", rotation.RotationID, agentID)
+	started := *rotation
+	return &started, nil
+}
+
+// CredentialRotation returns the latest credential rotation of an agent, or
+// nil if it was never rotated since the backend started
+func (h *Handler) CredentialRotation(agentID int) *models.AgentCredentialRotation {
+	h.rotationMu.Lock()
+	defer h.rotationMu.Unlock()
+
+	if rotation := h.credentialRotations[agentID]; rotation != nil {
+		report := *rotation
+		return &report
+	}
+	return nil
+}
+
+// handleCredentialRotationAck completes a rotation once the agent stored its
+// new credentials, or withdraws the new key if it could not
+func (h *Handler) handleCredentialRotationAck(client *Client, msg *wsservice.Message) {
+	var ack wsservice.CredentialRotationAckPayload
+	if err := json.Unmarshal(msg.Payload, &ack); err != nil {
+		debug.Error("Agent %d: Failed to unmarshal credential rotation ack: %v", client.agent.ID, err)
+		return
+	}
+
+	h.rotationMu.Lock()
+	defer h.rotationMu.Unlock()
+
+	rotation := h.credentialRotations[client.agent.ID]
+	if rotation == nil || rotation.RotationID != ack.RotationID || rotation.Status != models.AgentCredentialRotationSent {
+		debug.Warning("Agent %d: Ignoring acknowledgment of unknown credential rotation %s", client.agent.ID, ack.RotationID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if !ack.Success {
+		h.failCredentialRotation(ctx, rotation, ack.Error)
+		return
+	}
+
+	expiresAt, err := h.agentService.ConfirmCredentialRotation(ctx, rotation)
+	now := time.Now()
+	rotation.CompletedAt = &now
+	if err != nil {
+		rotation.Status = models.AgentCredentialRotationFailed
+		rotation.Error = err.Error()
+		debug.Error("Agent %d: Failed to confirm credential rotation %s: %v", client.agent.ID, rotation.RotationID, err)
+		return
+	}
+	rotation.Status = models.AgentCredentialRotationConfirmed
+	if !expiresAt.IsZero() {
+		rotation.PreviousKeyExpiresAt = &expiresAt
+	}
+	// Later updates of the connected agent must not write the old key back
+	client.agent.APIKey.String, client.agent.APIKey.Valid = rotation.APIKey(), true
+	debug.Info("Agent %d confirmed credential rotation %s", client.agent.ID, rotation.RotationID)
+}
+
+// failCredentialRotation withdraws the rotation's key, the agent keeps its
+// current credentials. The caller holds rotationMu.
+func (h *Handler) failCredentialRotation(ctx context.Context, rotation *models.AgentCredentialRotation, reason string) {
+	now := time.Now()
+	rotation.Status = models.AgentCredentialRotationFailed
+	rotation.Error = reason
+	rotation.CompletedAt = &now
+	if err := h.agentService.CancelCredentialRotation(ctx, rotation, reason); err != nil {
+		debug.Error("Failed to withdraw credential rotation %s of agent %d: %v", rotation.RotationID, rotation.AgentID, err)
+	}
+	debug.Warning("Credential rotation %s of agent %d failed: %s", rotation.RotationID, rotation.AgentID, reason)
+}
+
+// StartCredentialRotationScheduler rotates the credentials of connected
+// agents whose API key is older than agent_credential_rotation_days, checking
+// hourly. Agents that are offline are rotated once they connect again.
+func (h *Handler) StartCredentialRotationScheduler(ctx context.Context) {
+	ticker := time.NewTicker(credentialRotationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			debug.Info("Credential rotation scheduler stopped")
+			return
+		case <-ticker.C:
+			h.rotateExpiredCredentials(ctx)
+		}
+	}
+}
+
+// rotateExpiredCredentials starts a rotation for each connected agent whose
+// API key reached the configured age
+func (h *Handler) rotateExpiredCredentials(ctx context.Context) {
+	age := h.agentService.CredentialRotationAge(ctx)
+	if age <= 0 {
+		return
+	}
+
+	agentIDs, err := h.agentService.ListAgentsWithKeysIssuedBefore(ctx, time.Now().Add(-age))
+	if err != nil {
+		debug.Error("Failed to list agents due for credential rotation: %v", err)
+		return
+	}
+	connected := make(map[int]bool)
+	for _, agentID := range h.GetConnectedAgents() {
+		connected[agentID] = true
+	}
+	for _, agentID := range agentIDs {
+		if !connected[agentID] {
+			continue
+		}
+		if _, err := h.RotateAgentCredentials(agentID); err != nil {
+			debug.Error("Failed to rotate credentials of agent %d: %v", agentID, err)
+		}
+	}
+}
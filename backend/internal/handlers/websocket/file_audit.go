@@ -0,0 +1,199 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	wsservice "github.com/ZerkerEOD/krakenhashes/backend/internal/services/websocket"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// auditFileTypes are the file types covered by an agent file audit
+var auditFileTypes = []string{"wordlist", "rule", "binary"}
+
+// fileAuditTimeout is how long an audit may wait for the agent's report
+// before another one can be started
+const fileAuditTimeout = 30 * time.Minute
+
+// AuditAgentFiles asks a connected agent to rehash every cached file and
+// report them, so that drifted files are corrected. The returned audit is
+// completed once the agent answers; an audit that is still scanning is
+// returned instead of starting another, unless it timed out.
+func (h *Handler) AuditAgentFiles(agentID int) (*models.AgentFileAudit, error) {
+	h.mu.RLock()
+	client, ok := h.clients[agentID]
+	h.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("agent %d not connected", agentID)
+	}
+
+	h.auditMu.Lock()
+	if h.fileAudits == nil {
+		h.fileAudits = make(map[int]*models.AgentFileAudit)
+	}
+	if running := h.fileAudits[agentID]; running != nil && running.Status == models.AgentFileAuditScanning && time.Since(running.StartedAt) < fileAuditTimeout {
+		audit := *running
+		h.auditMu.Unlock()
+		return &audit, nil
+	}
+	audit := &models.AgentFileAudit{
+		RequestID: fmt.Sprintf("audit-%d-%d", agentID, time.Now().UnixNano()),
+		AgentID:   agentID,
+		Status:    models.AgentFileAuditScanning,
+		StartedAt: time.Now(),
+	}
+	h.fileAudits[agentID] = audit
+	started := *audit
+	h.auditMu.Unlock()
+
+	debug.Info("Starting file audit %s of agent %d", audit.RequestID, agentID)
+	go h.sendFileSyncRequest(client, wsservice.FileSyncRequestPayload{
+		RequestID: audit.RequestID,
+		FileTypes: auditFileTypes,
+		Audit:     true,
+	})
+	return &started, nil
+}
+
+// FileAudit returns the latest file audit of an agent, or nil if it was never
+// audited since the backend started
+func (h *Handler) FileAudit(agentID int) *models.AgentFileAudit {
+	h.auditMu.Lock()
+	defer h.auditMu.Unlock()
+
+	if audit := h.fileAudits[agentID]; audit != nil {
+		report := *audit
+		return &report
+	}
+	return nil
+}
+
+// pendingFileAudit returns the running audit a sync response answers, if any
+func (h *Handler) pendingFileAudit(agentID int, requestID string) *models.AgentFileAudit {
+	if requestID == "" {
+		return nil
+	}
+
+	h.auditMu.Lock()
+	defer h.auditMu.Unlock()
+
+	audit := h.fileAudits[agentID]
+	if audit == nil || audit.RequestID != requestID || audit.Status != models.AgentFileAuditScanning {
+		return nil
+	}
+	return audit
+}
+
+// completeFileAudit compares the files an agent reported for an audit with
+// the backend's, then tells the agent to download what is missing or
+// mismatched and to delete what the backend does not know
+func (h *Handler) completeFileAudit(client *Client, audit *models.AgentFileAudit, agentFiles []wsservice.FileInfo) {
+	backendFiles, err := h.getBackendFiles(context.Background(), auditFileTypes, "")
+
+	h.auditMu.Lock()
+	now := time.Now()
+	audit.CompletedAt = &now
+	if err != nil {
+		audit.Status = models.AgentFileAuditFailed
+		audit.Error = fmt.Sprintf("failed to get backend files: %v", err)
+		h.auditMu.Unlock()
+		debug.Error("File audit %s of agent %d failed: %v", audit.RequestID, client.agent.ID, err)
+		return
+	}
+	downloads, deletions := auditFiles(audit, backendFiles, agentFiles)
+	audit.Status = models.AgentFileAuditCompleted
+	h.auditMu.Unlock()
+
+	debug.Info("File audit %s of agent %d: %d files checked, %d mismatched, %d missing, %d extra",
+		audit.RequestID, client.agent.ID, audit.FilesChecked, len(audit.Mismatched), len(audit.Missing), len(audit.Extra))
+
+	if len(deletions) > 0 {
+		h.sendFileSyncCommand(client, "delete", deletions)
+	}
+	if len(downloads) > 0 {
+		h.sendFileSyncCommand(client, "download", downloads)
+		return
+	}
+
+	// Nothing to download, the agent is up to date
+	if h.agentService != nil {
+		if err := h.agentService.UpdateAgentSyncStatus(context.Background(), client.agent.ID, models.AgentSyncStatusCompleted, ""); err != nil {
+			debug.Error("Failed to update sync status for agent %d: %v", client.agent.ID, err)
+		}
+	}
+}
+
+// auditFiles fills the audit's findings from the agent's freshly hashed files
+// and returns the corrective downloads and deletions. Only extra wordlists
+// and rules are deleted, other extra files are just reported.
+func auditFiles(audit *models.AgentFileAudit, backendFiles, agentFiles []wsservice.FileInfo) (downloads, deletions []wsservice.FileInfo) {
+	audit.FilesChecked = len(agentFiles)
+	audit.Mismatched = []models.AgentFileAuditItem{}
+	audit.Missing = []models.AgentFileAuditItem{}
+	audit.Extra = []models.AgentFileAuditItem{}
+
+	agentFileMap := make(map[string]wsservice.FileInfo, len(agentFiles))
+	for _, file := range agentFiles {
+		agentFileMap[file.FileType+":"+file.Name] = file
+	}
+
+	known := make(map[string]bool, len(backendFiles))
+	for _, file := range backendFiles {
+		key := file.FileType + ":" + file.Name
+		known[key] = true
+
+		agentFile, exists := agentFileMap[key]
+		switch {
+		case !exists:
+			audit.Missing = append(audit.Missing, models.AgentFileAuditItem{
+				FileType:    file.FileType,
+				Name:        file.Name,
+				ExpectedMD5: file.MD5Hash,
+				Size:        file.Size,
+			})
+		case agentFile.MD5Hash != file.MD5Hash:
+			audit.Mismatched = append(audit.Mismatched, models.AgentFileAuditItem{
+				FileType:    file.FileType,
+				Name:        file.Name,
+				ExpectedMD5: file.MD5Hash,
+				ActualMD5:   agentFile.MD5Hash,
+				Size:        agentFile.Size,
+			})
+		default:
+			continue
+		}
+		downloads = append(downloads, file)
+	}
+
+	// Extra files identical to a missing one are moved rather than deleted
+	downloads = matchMovedFiles(downloads, backendFiles, agentFiles)
+	moved := make(map[string]bool)
+	for _, file := range downloads {
+		if file.MoveFrom != "" {
+			moved[file.FileType+":"+file.MoveFrom] = true
+		}
+	}
+
+	for _, file := range agentFiles {
+		key := file.FileType + ":" + file.Name
+		if known[key] || moved[key] {
+			continue
+		}
+		audit.Extra = append(audit.Extra, models.AgentFileAuditItem{
+			FileType:  file.FileType,
+			Name:      file.Name,
+			ActualMD5: file.MD5Hash,
+			Size:      file.Size,
+		})
+		if file.FileType == "wordlist" || file.FileType == "rule" {
+			deletions = append(deletions, wsservice.FileInfo{Name: file.Name, FileType: file.FileType})
+		}
+	}
+
+	audit.Downloads = len(downloads)
+	audit.Deletions = len(deletions)
+	return downloads, deletions
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
@@ -59,10 +60,12 @@ func initTimingConfig() {
 	writeWait = getEnvDuration("KH_WRITE_WAIT", defaultWriteWait)
 	pongWait = getEnvDuration("KH_PONG_WAIT", defaultPongWait)
 	pingPeriod = getEnvDuration("KH_PING_PERIOD", defaultPingPeriod)
+	slowConsumerWindow = getEnvDuration("KH_WS_SLOW_CONSUMER_WINDOW", defaultSlowConsumerWindow)
 	debug.Info("WebSocket timing configuration initialized:")
 	debug.Info("- Write Wait: %v", writeWait)
 	debug.Info("- Pong Wait: %v", pongWait)
 	debug.Info("- Ping Period: %v", pingPeriod)
+	debug.Info("- Slow Consumer Window: %v", slowConsumerWindow)
 }
 
 var upgrader = websocket.Upgrader{
@@ -86,6 +89,17 @@ type Handler struct {
 	tlsConfig          *tls.Config
 	clients            map[int]*Client
 	mu                 sync.RWMutex
+	counters           hubCounters
+	draining           atomic.Bool // Set once the server shuts down, see Drain
+
+	// Latest file audit of each agent, see AuditAgentFiles
+	fileAudits map[int]*models.AgentFileAudit
+	auditMu    sync.Mutex
+
+	// Latest credential rotation of each agent, see RotateAgentCredentials
+	credentialRotations map[int]*models.AgentCredentialRotation
+	rotationMu          sync.Mutex
+	certificates        CertificateIssuer
 }
 
 // Client represents a connected agent
@@ -93,9 +107,19 @@ type Client struct {
 	handler *Handler
 	conn    *websocket.Conn
 	agent   *models.Agent
-	send    chan *wsservice.Message
+	send    chan *wsservice.Message // Bulk traffic such as file sync
+	control chan *wsservice.Message // Job control, always written first
 	ctx     context.Context
 	cancel  context.CancelFunc
+	stats   clientStats
+}
+
+// queue returns the outbound channel for a message type
+func (c *Client) queue(msgType wsservice.MessageType) chan *wsservice.Message {
+	if msgType.IsControl() {
+		return c.control
+	}
+	return c.send
 }
 
 // NewHandler creates a new WebSocket handler
@@ -104,13 +128,15 @@ func NewHandler(wsService *wsservice.Service, agentService *services.AgentServic
 	initTimingConfig()
 
 	return &Handler{
-		wsService:          wsService,
-		agentService:       agentService,
-		systemSettingsRepo: systemSettingsRepo,
-		jobTaskRepo:        jobTaskRepo,
-		jobExecRepo:        jobExecRepo,
-		tlsConfig:          tlsConfig,
-		clients:            make(map[int]*Client),
+		wsService:           wsService,
+		agentService:        agentService,
+		systemSettingsRepo:  systemSettingsRepo,
+		jobTaskRepo:         jobTaskRepo,
+		jobExecRepo:         jobExecRepo,
+		tlsConfig:           tlsConfig,
+		clients:             make(map[int]*Client),
+		fileAudits:          make(map[int]*models.AgentFileAudit),
+		credentialRotations: make(map[int]*models.AgentCredentialRotation),
 	}
 }
 
@@ -124,6 +150,12 @@ func (h *Handler) ServeWS(w http.ResponseWriter, r *http.Request) {
 	debug.Info("New WebSocket connection attempt received from %s", r.RemoteAddr)
 	debug.Debug("Request headers: %v", r.Header)
 
+	if h.draining.Load() {
+		debug.Info("Refusing WebSocket connection from %s, server is restarting", r.RemoteAddr)
+		http.Error(w, "Server is restarting", http.StatusServiceUnavailable)
+		return
+	}
+
 	if h.tlsConfig != nil {
 		if r.TLS == nil {
 			debug.Error("TLS connection required but not provided from %s", r.RemoteAddr)
@@ -185,8 +217,10 @@ func (h *Handler) ServeWS(w http.ResponseWriter, r *http.Request) {
 		conn:    conn,
 		agent:   agent,
 		send:    make(chan *wsservice.Message, 256),
+		control: make(chan *wsservice.Message, 64),
 		ctx:     ctx,
 		cancel:  cancel,
+		stats:   clientStats{connectedAt: time.Now()},
 	}
 
 	// If this is the agent's first connection and it has a claim code in metadata, mark it as used
@@ -211,6 +245,9 @@ func (h *Handler) ServeWS(w http.ResponseWriter, r *http.Request) {
 	}
 
 	debug.Info("Agent %d fully registered and ready", agent.ID)
+	event := models.NewAgentSystemEvent(models.SystemEventInfo, models.SystemEventCategoryAgent, models.SystemEventAgentConnected,
+		agent.ID, fmt.Sprintf("Agent %d connected from %s", agent.ID, r.RemoteAddr))
+	h.agentService.RecordSystemEvent(ctx, event)
 
 	// Reset sync status when agent connects (only if not currently executing a task)
 	// This ensures the agent will sync files on each connection
@@ -292,8 +329,12 @@ func (c *Client) readPump() {
 			}
 		}
 
-		// Update agent status to inactive when connection is closed
-		if err := c.handler.agentService.UpdateAgentStatus(c.ctx, c.agent.ID, models.AgentStatusInactive, nil); err != nil {
+		// Update agent status to inactive when connection is closed. A
+		// drained agent reconnects to the next start, so it is not reported
+		// as offline.
+		if c.handler.draining.Load() {
+			debug.Info("Agent %d: Connection closed for server restart", c.agent.ID)
+		} else if err := c.handler.agentService.MarkAgentOffline(c.ctx, c.agent.ID, "disconnected"); err != nil {
 			debug.Error("Failed to update agent status to inactive: %v", err)
 		} else {
 			debug.Info("Successfully updated agent %d status to inactive", c.agent.ID)
@@ -411,6 +452,9 @@ func (c *Client) readPump() {
 		case wsservice.TypeDownloadFailed:
 			c.handler.handleDownloadFailed(c, &msg)
 
+		case wsservice.TypeCredentialRotationAck:
+			c.handler.handleCredentialRotationAck(c, &msg)
+
 		default:
 			// Handle other message types
 		}
@@ -432,42 +476,33 @@ func (c *Client) writePump() {
 	debug.Info("Agent %d: - Ping Period: %v", c.agent.ID, pingPeriod)
 
 	for {
+		// Drain pending control messages before considering bulk traffic
 		select {
-		case message, ok := <-c.send:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-			if !ok {
-				debug.Info("Agent %d: Send channel closed", c.agent.ID)
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+		case message := <-c.control:
+			if !c.writeMessage(message) {
 				return
 			}
+			continue
+		default:
+		}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				debug.Error("Agent %d: Failed to get next writer: %v", c.agent.ID, err)
+		select {
+		case message := <-c.control:
+			if !c.writeMessage(message) {
 				return
 			}
 
-			data, err := json.Marshal(message)
-			if err != nil {
-				debug.Error("Agent %d: Failed to marshal message: %v", c.agent.ID, err)
-				continue
-			}
-
-			debug.Info("Agent %d: Sending message type: %s", c.agent.ID, message.Type)
-			debug.Debug("Message details - Length: %d bytes", len(data))
-
-			if _, err := w.Write(data); err != nil {
-				debug.Error("Agent %d: Failed to write message: %v", c.agent.ID, err)
+		case message, ok := <-c.send:
+			if !ok {
+				c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+				debug.Info("Agent %d: Send channel closed", c.agent.ID)
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
-
-			if err := w.Close(); err != nil {
-				debug.Error("Agent %d: Failed to close writer: %v", c.agent.ID, err)
+			if !c.writeMessage(message) {
 				return
 			}
 
-			debug.Info("Agent %d: Successfully sent message type: %s", c.agent.ID, message.Type)
-
 		case <-ticker.C:
 			debug.Info("Agent %d: Sending ping", c.agent.ID)
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
@@ -484,6 +519,56 @@ func (c *Client) writePump() {
 	}
 }
 
+// writeMessage writes a single message to the connection, returning false if
+// the connection is no longer usable
+func (c *Client) writeMessage(message *wsservice.Message) bool {
+	data, err := json.Marshal(message)
+	if err != nil {
+		debug.Error("Agent %d: Failed to marshal message: %v", c.agent.ID, err)
+		return true
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+
+	w, err := c.conn.NextWriter(websocket.TextMessage)
+	if err != nil {
+		debug.Error("Agent %d: Failed to get next writer: %v", c.agent.ID, err)
+		return false
+	}
+
+	debug.Info("Agent %d: Sending message type: %s", c.agent.ID, message.Type)
+	debug.Debug("Message details - Length: %d bytes", len(data))
+
+	if _, err := w.Write(data); err != nil {
+		debug.Error("Agent %d: Failed to write message: %v", c.agent.ID, err)
+		return false
+	}
+
+	if err := w.Close(); err != nil {
+		debug.Error("Agent %d: Failed to close writer: %v", c.agent.ID, err)
+		return false
+	}
+
+	c.recordSent()
+	debug.Info("Agent %d: Successfully sent message type: %s", c.agent.ID, message.Type)
+
+	if message.Type == wsservice.TypeServerDraining {
+		// The agent knows when to reconnect, close for the server restart
+		c.closeForRestart()
+		return false
+	}
+	return true
+}
+
+// closeForRestart closes the connection with the service restart code
+func (c *Client) closeForRestart() {
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseServiceRestart, "server restarting")
+	if err := c.conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(writeWait)); err != nil {
+		debug.Debug("Agent %d: Failed to send close message: %v", c.agent.ID, err)
+	}
+	c.cancel()
+}
+
 // SendMessage sends a message to a specific agent
 func (h *Handler) SendMessage(agentID int, msg *wsservice.Message) error {
 	h.mu.RLock()
@@ -494,12 +579,10 @@ func (h *Handler) SendMessage(agentID int, msg *wsservice.Message) error {
 		return fmt.Errorf("agent %d not connected", agentID)
 	}
 
-	select {
-	case client.send <- msg:
-		return nil
-	default:
+	if !client.enqueue(msg) {
 		return fmt.Errorf("agent %d send buffer full", agentID)
 	}
+	return nil
 }
 
 // Broadcast sends a message to all connected agents
@@ -508,9 +591,7 @@ func (h *Handler) Broadcast(msg *wsservice.Message) {
 	defer h.mu.RUnlock()
 
 	for _, client := range h.clients {
-		select {
-		case client.send <- msg:
-		default:
+		if !client.enqueue(msg) {
 			debug.Error("failed to broadcast to agent %d: send buffer full", client.agent.ID)
 		}
 	}
@@ -526,7 +607,12 @@ func (h *Handler) unregisterClient(c *Client) {
 	}
 	h.mu.Unlock()
 	
-	// Mark agent's tasks as reconnect_pending
+	// Mark agent's tasks as reconnect_pending, unless the server is draining:
+	// the tasks keep running and are handed over to the next start
+	if h.draining.Load() {
+		debug.Info("Agent %d: Keeping tasks running for the server handover", c.agent.ID)
+		return
+	}
 	if h.wsService != nil && h.wsService.GetJobHandler() != nil {
 		debug.Info("Agent %d: Marking tasks as reconnect_pending due to disconnection", c.agent.ID)
 		if err := h.wsService.HandleAgentDisconnection(c.ctx, c.agent.ID); err != nil {
@@ -535,6 +621,54 @@ func (h *Handler) unregisterClient(c *Client) {
 	}
 }
 
+// Drain tells every connected agent that the server is restarting and to
+// reconnect after reconnectAfter, and the connections close once the notice
+// is written. Their tasks are left running for the next start to pick up, and
+// new connections are refused until the process exits. Connections that did
+// not get the notice within timeout are closed anyway.
+func (h *Handler) Drain(reconnectAfter, timeout time.Duration) {
+	h.draining.Store(true)
+
+	payload, err := json.Marshal(wsservice.ServerDrainingPayload{
+		ReconnectAfterSeconds: int(reconnectAfter / time.Second),
+	})
+	if err != nil {
+		debug.Error("Failed to marshal server draining payload: %v", err)
+		return
+	}
+	msg := &wsservice.Message{Type: wsservice.TypeServerDraining, Payload: payload}
+
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	debug.Info("Draining %d agent connections, agents reconnect after %v", len(clients), reconnectAfter)
+	for _, client := range clients {
+		if !client.enqueue(msg) {
+			debug.Warning("Agent %d: Control queue full, closing without a draining notice", client.agent.ID)
+		}
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for _, client := range clients {
+		select {
+		case <-client.ctx.Done():
+		case <-deadline.C:
+			// Later clients are closed right away, the timer fired once
+			deadline.Reset(0)
+		}
+		if client.ctx.Err() == nil {
+			debug.Warning("Agent %d: Draining notice not written in time, closing", client.agent.ID)
+			client.closeForRestart()
+		}
+		client.conn.Close()
+	}
+}
+
 // GetConnectedAgents returns a list of connected agent IDs
 func (h *Handler) GetConnectedAgents() []int {
 	h.mu.RLock()
@@ -547,14 +681,35 @@ func (h *Handler) GetConnectedAgents() []int {
 	return agents
 }
 
-// sendInitialConfiguration sends initial configuration to the agent including download settings
+// sendInitialConfiguration sends the agent its configuration: the download
+// settings and the agent settings managed from the backend
 func (h *Handler) sendInitialConfiguration(client *Client) {
 	debug.Info("Sending initial configuration to agent %d", client.agent.ID)
+	h.sendConfiguration(client)
+}
 
-	// Get agent download settings from repository
+// PushAgentConfig sends a connected agent its current configuration after its
+// settings changed. An agent that is not connected gets it when it connects.
+func (h *Handler) PushAgentConfig(agentID int) error {
+	h.mu.RLock()
+	client, ok := h.clients[agentID]
+	h.mu.RUnlock()
+
+	if !ok {
+		debug.Info("Agent %d not connected, configuration will be sent when it connects", agentID)
+		return nil
+	}
+
+	go h.sendConfiguration(client)
+	return nil
+}
+
+// sendConfiguration builds and queues the config_update message for an agent
+func (h *Handler) sendConfiguration(client *Client) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Get agent download settings from repository
 	settings, err := h.systemSettingsRepo.GetAgentDownloadSettings(ctx)
 	if err != nil {
 		debug.Error("Failed to get agent download settings: %v", err)
@@ -568,9 +723,18 @@ func (h *Handler) sendInitialConfiguration(client *Client) {
 		}
 	}
 
+	// Reload the agent, its managed settings may have changed since it connected
+	managedConfig := client.agent.ManagedConfig
+	if agent, err := h.agentService.GetByID(ctx, client.agent.ID); err != nil {
+		debug.Warning("Failed to reload agent %d for its configuration: %v", client.agent.ID, err)
+	} else {
+		managedConfig = agent.ManagedConfig
+	}
+
 	// Create configuration payload
 	configPayload := map[string]interface{}{
 		"download_settings": settings,
+		"agent_config":      managedConfig.Effective(*settings),
 	}
 
 	payloadBytes, err := json.Marshal(configPayload)
@@ -587,13 +751,27 @@ func (h *Handler) sendInitialConfiguration(client *Client) {
 
 	// Send configuration to agent
 	select {
-	case client.send <- msg:
-		debug.Info("Sent initial configuration to agent %d with download settings", client.agent.ID)
+	case client.queue(msg.Type) <- msg:
+		debug.Info("Sent configuration to agent %d with download settings and managed agent settings", client.agent.ID)
 	case <-client.ctx.Done():
 		debug.Warning("Failed to send configuration: agent %d disconnected", client.agent.ID)
 	}
 }
 
+// TriggerFileSync asks a connected agent to synchronize its files again
+func (h *Handler) TriggerFileSync(agentID int) error {
+	h.mu.RLock()
+	client, ok := h.clients[agentID]
+	h.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("agent %d not connected", agentID)
+	}
+
+	go h.initiateFileSync(client)
+	return nil
+}
+
 // initiateFileSync starts the file synchronization process with an agent
 func (h *Handler) initiateFileSync(client *Client) {
 	debug.Info("Initiating file sync with agent %d", client.agent.ID)
@@ -602,11 +780,14 @@ func (h *Handler) initiateFileSync(client *Client) {
 	requestID := fmt.Sprintf("sync-%d-%d", client.agent.ID, time.Now().UnixNano())
 
 	// Create sync request payload
-	payload := wsservice.FileSyncRequestPayload{
+	h.sendFileSyncRequest(client, wsservice.FileSyncRequestPayload{
 		RequestID: requestID,
 		FileTypes: []string{"wordlist", "rule", "binary"},
-	}
+	})
+}
 
+// sendFileSyncRequest asks the agent to report its current files
+func (h *Handler) sendFileSyncRequest(client *Client, payload wsservice.FileSyncRequestPayload) {
 	// Marshal payload
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
@@ -622,7 +803,7 @@ func (h *Handler) initiateFileSync(client *Client) {
 
 	// Send message to agent
 	select {
-	case client.send <- msg:
+	case client.queue(msg.Type) <- msg:
 		debug.Info("Sent file sync request to agent %d", client.agent.ID)
 	case <-client.ctx.Done():
 		debug.Warning("Failed to send file sync request: agent %d disconnected", client.agent.ID)
@@ -658,6 +839,11 @@ func (h *Handler) handleSyncResponse(client *Client, msg *wsservice.Message) {
 
 	debug.Info("Received file sync response from agent %d: %d files", client.agent.ID, len(payload.Files))
 
+	if audit := h.pendingFileAudit(client.agent.ID, payload.RequestID); audit != nil {
+		h.completeFileAudit(client, audit, payload.Files)
+		return
+	}
+
 	// Determine which files need to be synced
 	filesToSync, err := h.determineFilesToSync(client.agent.ID, payload.Files)
 	if err != nil {
@@ -682,11 +868,16 @@ func (h *Handler) handleSyncResponse(client *Client, msg *wsservice.Message) {
 		return
 	}
 
+	h.sendFileSyncCommand(client, "download", filesToSync)
+}
+
+// sendFileSyncCommand tells the agent to download or delete files
+func (h *Handler) sendFileSyncCommand(client *Client, action string, files []wsservice.FileInfo) {
 	// Create sync command payload
 	commandPayload := wsservice.FileSyncCommandPayload{
 		RequestID: fmt.Sprintf("sync-cmd-%d-%d", client.agent.ID, time.Now().UnixNano()),
-		Action:    "download",
-		Files:     filesToSync,
+		Action:    action,
+		Files:     files,
 	}
 
 	// Marshal payload
@@ -704,13 +895,37 @@ func (h *Handler) handleSyncResponse(client *Client, msg *wsservice.Message) {
 
 	// Send message to agent
 	select {
-	case client.send <- command:
-		debug.Info("Sent file sync command to agent %d to download %d files", client.agent.ID, len(filesToSync))
+	case client.queue(command.Type) <- command:
+		debug.Info("Sent file sync command to agent %d to %s %d files", client.agent.ID, action, len(files))
 	case <-client.ctx.Done():
 		debug.Warning("Failed to send file sync command: agent %d disconnected", client.agent.ID)
 	}
 }
 
+// RemoveFileFromAgents tells connected agents to delete their copy of a
+// wordlist or rule that was deleted. Agents that are offline keep theirs.
+func (h *Handler) RemoveFileFromAgents(fileType, name, category string) {
+	payload, err := json.Marshal(wsservice.FileSyncCommandPayload{
+		RequestID: fmt.Sprintf("sync-delete-%d", time.Now().UnixNano()),
+		Action:    "delete",
+		Files: []wsservice.FileInfo{{
+			Name:     name,
+			FileType: fileType,
+			Category: category,
+		}},
+	})
+	if err != nil {
+		debug.Error("Failed to marshal file delete command payload: %v", err)
+		return
+	}
+
+	h.Broadcast(&wsservice.Message{
+		Type:    wsservice.TypeSyncCommand,
+		Payload: payload,
+	})
+	debug.Info("Sent delete of %s %s to connected agents", fileType, name)
+}
+
 // handleSyncStatus processes a file sync status update from an agent
 func (h *Handler) handleSyncStatus(client *Client, msg *wsservice.Message) {
 	var payload wsservice.FileSyncStatusPayload
@@ -757,7 +972,39 @@ func (h *Handler) determineFilesToSync(agentID int, agentFiles []wsservice.FileI
 		}
 	}
 
-	return filesToSync, nil
+	return matchMovedFiles(filesToSync, backendFiles, agentFiles), nil
+}
+
+// matchMovedFiles sets MoveFrom on wordlists and rules the agent is missing
+// when it has an identical file under a name the backend no longer knows, so
+// a file moved between directories is renamed on the agent rather than
+// downloaded again. Each agent file is used for at most one move.
+func matchMovedFiles(filesToSync, backendFiles, agentFiles []wsservice.FileInfo) []wsservice.FileInfo {
+	known := make(map[string]bool, len(backendFiles))
+	for _, file := range backendFiles {
+		known[file.FileType+":"+file.Name] = true
+	}
+
+	orphans := make(map[string][]string)
+	for _, file := range agentFiles {
+		if file.FileType != "wordlist" && file.FileType != "rule" {
+			continue
+		}
+		if file.MD5Hash == "" || known[file.FileType+":"+file.Name] {
+			continue
+		}
+		key := file.FileType + ":" + file.MD5Hash
+		orphans[key] = append(orphans[key], file.Name)
+	}
+
+	for i := range filesToSync {
+		key := filesToSync[i].FileType + ":" + filesToSync[i].MD5Hash
+		if names := orphans[key]; len(names) > 0 {
+			filesToSync[i].MoveFrom = names[0]
+			orphans[key] = names[1:]
+		}
+	}
+	return filesToSync
 }
 
 // getBackendFiles retrieves files from the backend database based on file types
@@ -944,31 +1191,34 @@ func (h *Handler) handleBufferedMessages(client *Client, msg *wsservice.Message)
 		
 		// Process the message based on its type
 		switch reconstructedMsg.Type {
-		case wsservice.TypeJobProgress:
+		case wsservice.TypeJobProgress, wsservice.TypeBenchmarkResult:
 			// Check if message contains crack information
 			if containsCracks(bufferedMsg.Payload) {
 				debug.Info("Agent %d: Buffered message contains crack information", client.agent.ID)
 			}
-			
-			// Forward to WebSocket service for processing
-			if h.wsService != nil {
-				// The WebSocket service will handle forwarding to the appropriate integration
-				debug.Info("Agent %d: Processing buffered job progress through WebSocket service", client.agent.ID)
-				// Note: The actual job progress processing happens through the integration layer
-				// which is registered with the WebSocket service
+
+			// Process it as if it had been sent live, e.g. the final progress
+			// of a task that finished while the server restarted. A message
+			// that fails is still acknowledged, resending it would fail again.
+			if err := h.wsService.HandleMessage(client.ctx, client.agent, &reconstructedMsg); err != nil {
+				debug.Error("Agent %d: Failed to process buffered message %s: %v", client.agent.ID, bufferedMsg.ID, err)
 			}
 			
+		case wsservice.TypeCrackJournal:
+			// Cracks the agent journaled while disconnected. Unlike other
+			// messages a failed entry is not acknowledged, the agent keeps it
+			// and replays it on the next connection.
+			if err := h.wsService.HandleCrackJournal(client.ctx, client.agent, bufferedMsg.Payload); err != nil {
+				debug.Error("Agent %d: Failed to reconcile crack journal entry %s: %v", client.agent.ID, bufferedMsg.ID, err)
+				continue
+			}
+
 		case wsservice.TypeHashcatOutput:
 			// Log hashcat output which may contain cracks
 			debug.Info("Agent %d: Processing buffered hashcat output", client.agent.ID)
 			// The hashcat output is typically logged for debugging
 			// Actual crack processing happens through job progress messages
 			
-		case wsservice.TypeBenchmarkResult:
-			// Process benchmark result
-			debug.Info("Agent %d: Processing buffered benchmark result", client.agent.ID)
-			// Similar to job progress, benchmark results are processed through integration
-			
 		default:
 			debug.Warning("Agent %d: Unsupported buffered message type: %s", client.agent.ID, bufferedMsg.Type)
 			continue
@@ -997,7 +1247,7 @@ func (h *Handler) handleBufferedMessages(client *Client, msg *wsservice.Message)
 		Payload: ackData,
 	}
 	
-	client.send <- &ackMsg
+	client.queue(ackMsg.Type) <- &ackMsg
 	debug.Info("Agent %d: Sent buffer acknowledgment for %d messages", client.agent.ID, len(processedIDs))
 }
 
@@ -1052,7 +1302,7 @@ func (h *Handler) handleCurrentTaskStatus(client *Client, msg *wsservice.Message
 					Payload: json.RawMessage(`{"task_id":"` + status.TaskID + `"}`),
 				}
 				select {
-				case client.send <- &stopMsg:
+				case client.queue(stopMsg.Type) <- &stopMsg:
 					debug.Info("Agent %d: Sent job stop for non-existent task %s", client.agent.ID, status.TaskID)
 				case <-client.ctx.Done():
 				}
@@ -1067,7 +1317,7 @@ func (h *Handler) handleCurrentTaskStatus(client *Client, msg *wsservice.Message
 					Payload: json.RawMessage(`{"task_id":"` + status.TaskID + `"}`),
 				}
 				select {
-				case client.send <- &stopMsg:
+				case client.queue(stopMsg.Type) <- &stopMsg:
 					debug.Info("Agent %d: Sent job stop for unassigned task %s", client.agent.ID, status.TaskID)
 				case <-client.ctx.Done():
 				}
@@ -1100,7 +1350,7 @@ func (h *Handler) handleCurrentTaskStatus(client *Client, msg *wsservice.Message
 						Payload: json.RawMessage(`{"task_id":"` + status.TaskID + `"}`),
 					}
 					select {
-					case client.send <- &stopMsg:
+					case client.queue(stopMsg.Type) <- &stopMsg:
 						debug.Info("Agent %d: Sent job stop for unrecoverable task %s", client.agent.ID, status.TaskID)
 					case <-client.ctx.Done():
 						debug.Warning("Agent %d: Failed to send job stop (disconnected)", client.agent.ID)
@@ -1223,7 +1473,7 @@ func (h *Handler) handleAgentShutdown(client *Client, msg *wsservice.Message) {
 	}
 	
 	// Mark agent as inactive
-	if err := h.agentService.UpdateAgentStatus(client.ctx, client.agent.ID, models.AgentStatusInactive, nil); err != nil {
+	if err := h.agentService.MarkAgentOffline(client.ctx, client.agent.ID, "shutdown"); err != nil {
 		debug.Error("Agent %d: Failed to update status to inactive: %v", client.agent.ID, err)
 	} else {
 		debug.Info("Agent %d: Marked as inactive due to graceful shutdown", client.agent.ID)
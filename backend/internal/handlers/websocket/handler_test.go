@@ -0,0 +1,107 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	wsservice "github.com/ZerkerEOD/krakenhashes/backend/internal/services/websocket"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainHandsAgentsOver(t *testing.T) {
+	writeWait, pingPeriod = time.Second, time.Minute
+	h := &Handler{clients: make(map[int]*Client)}
+
+	connected := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		client := &Client{
+			handler: h,
+			conn:    conn,
+			agent:   &models.Agent{ID: 3},
+			send:    make(chan *wsservice.Message, 1),
+			control: make(chan *wsservice.Message, 1),
+			ctx:     ctx,
+			cancel:  cancel,
+			stats:   clientStats{connectedAt: time.Now()},
+		}
+		h.mu.Lock()
+		h.clients[3] = client
+		h.mu.Unlock()
+		go client.writePump()
+		close(connected)
+	}))
+	defer server.Close()
+
+	agent, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer agent.Close()
+	<-connected
+
+	h.Drain(45*time.Second, time.Second)
+
+	// The agent is told when to reconnect, then the connection is closed
+	var msg wsservice.Message
+	require.NoError(t, agent.ReadJSON(&msg))
+	assert.Equal(t, wsservice.TypeServerDraining, msg.Type)
+	var payload wsservice.ServerDrainingPayload
+	require.NoError(t, json.Unmarshal(msg.Payload, &payload))
+	assert.Equal(t, 45, payload.ReconnectAfterSeconds)
+
+	_, _, err = agent.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseServiceRestart), "unexpected close: %v", err)
+
+	// New connections are refused until the process exits
+	recorder := httptest.NewRecorder()
+	h.ServeWS(recorder, httptest.NewRequest(http.MethodGet, "/ws/agent", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestAuditFiles(t *testing.T) {
+	backendFiles := []wsservice.FileInfo{
+		{Name: "general/rockyou.txt", MD5Hash: "aaa", FileType: "wordlist"},
+		{Name: "best64.rule", MD5Hash: "bbb", FileType: "rule"},
+		{Name: "crackstation.txt", MD5Hash: "ccc", FileType: "wordlist"},
+		{Name: "hashcat-6.2.6.7z", MD5Hash: "ddd", FileType: "binary", ID: 1},
+	}
+	agentFiles := []wsservice.FileInfo{
+		{Name: "general/rockyou.txt", MD5Hash: "aaa", FileType: "wordlist"},
+		{Name: "best64.rule", MD5Hash: "zzz", FileType: "rule", Size: 10},
+		{Name: "old/crackstation.txt", MD5Hash: "ccc", FileType: "wordlist"},
+		{Name: "stale.txt", MD5Hash: "eee", FileType: "wordlist"},
+		{Name: "hashcat-6.2.5.7z", MD5Hash: "fff", FileType: "binary", ID: 2},
+	}
+
+	audit := &models.AgentFileAudit{}
+	downloads, deletions := auditFiles(audit, backendFiles, agentFiles)
+
+	assert.Equal(t, 5, audit.FilesChecked)
+	require.Len(t, audit.Mismatched, 1)
+	assert.Equal(t, models.AgentFileAuditItem{FileType: "rule", Name: "best64.rule", ExpectedMD5: "bbb", ActualMD5: "zzz", Size: 10}, audit.Mismatched[0])
+	require.Len(t, audit.Missing, 2)
+	assert.Equal(t, "crackstation.txt", audit.Missing[0].Name)
+	assert.Equal(t, "hashcat-6.2.6.7z", audit.Missing[1].Name)
+
+	// A moved wordlist is renamed, not reported as extra and deleted
+	require.Len(t, downloads, 3)
+	assert.Equal(t, "old/crackstation.txt", downloads[1].MoveFrom)
+	require.Len(t, audit.Extra, 2)
+	assert.Equal(t, "stale.txt", audit.Extra[0].Name)
+	assert.Equal(t, "hashcat-6.2.5.7z", audit.Extra[1].Name)
+
+	// Extra binaries are only reported
+	assert.Equal(t, []wsservice.FileInfo{{Name: "stale.txt", FileType: "wordlist"}}, deletions)
+	assert.Equal(t, 3, audit.Downloads)
+	assert.Equal(t, 1, audit.Deletions)
+	assert.False(t, audit.Clean())
+}
@@ -0,0 +1,133 @@
+package websocket
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	wsservice "github.com/ZerkerEOD/krakenhashes/backend/internal/services/websocket"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// Slow consumer handling. A message for an agent whose queue is full is
+// dropped, as the agent does with its own outbound queue. An agent that drops
+// more than slowConsumerDropLimit messages within slowConsumerWindow is
+// disconnected, it reconnects and reports its state again instead of silently
+// missing job control.
+const (
+	defaultSlowConsumerWindow = time.Minute
+	slowConsumerDropLimit     = 20
+)
+
+// slowConsumerWindow is read from KH_WS_SLOW_CONSUMER_WINDOW
+var slowConsumerWindow time.Duration
+
+// hubCounters are the message counters of all agents since the backend started
+type hubCounters struct {
+	sent                    atomic.Int64
+	dropped                 atomic.Int64
+	slowConsumerDisconnects atomic.Int64
+}
+
+// clientStats are the message counters of one connection
+type clientStats struct {
+	connectedAt time.Time
+	sent        atomic.Int64
+	dropped     atomic.Int64
+	slow        atomic.Bool
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowDrops int
+}
+
+// enqueue queues a message for the agent without blocking. A full queue drops
+// the message and counts it against the agent.
+func (c *Client) enqueue(msg *wsservice.Message) bool {
+	queue := c.queue(msg.Type)
+	select {
+	case queue <- msg:
+		warnQueueFill(c.agent.ID, queue, msg.Type)
+		return true
+	default:
+		c.recordDrop(msg.Type)
+		return false
+	}
+}
+
+// warnQueueFill logs when a queue is close to full
+func warnQueueFill(agentID int, queue chan *wsservice.Message, msgType wsservice.MessageType) {
+	depth, capacity := len(queue), cap(queue)
+	if capacity == 0 {
+		return
+	}
+	fullness := float64(depth) / float64(capacity) * 100
+	if fullness >= 90 {
+		debug.Error("Agent %d: Send queue critically full: %d/%d (%.1f%%) - message type: %s", agentID, depth, capacity, fullness, msgType)
+	} else if fullness >= 75 {
+		debug.Warning("Agent %d: Send queue high: %d/%d (%.1f%%) - message type: %s", agentID, depth, capacity, fullness, msgType)
+	}
+}
+
+// recordSent counts a message written to the connection
+func (c *Client) recordSent() {
+	c.stats.sent.Add(1)
+	c.handler.counters.sent.Add(1)
+}
+
+// recordDrop counts a dropped message and disconnects the agent once it drops
+// too many within the slow consumer window
+func (c *Client) recordDrop(msgType wsservice.MessageType) {
+	c.stats.dropped.Add(1)
+	c.handler.counters.dropped.Add(1)
+	debug.Warning("Agent %d: Send queue full, dropping message of type %s", c.agent.ID, msgType)
+
+	c.stats.mu.Lock()
+	now := time.Now()
+	if now.Sub(c.stats.windowStart) > slowConsumerWindow {
+		c.stats.windowStart = now
+		c.stats.windowDrops = 0
+	}
+	c.stats.windowDrops++
+	tooSlow := c.stats.windowDrops > slowConsumerDropLimit
+	c.stats.mu.Unlock()
+
+	if tooSlow && c.stats.slow.CompareAndSwap(false, true) {
+		c.handler.counters.slowConsumerDisconnects.Add(1)
+		debug.Error("Agent %d: Dropped more than %d messages within %v, disconnecting slow consumer",
+			c.agent.ID, slowConsumerDropLimit, slowConsumerWindow)
+		// The write pump closes the connection, the read pump then unregisters the agent
+		c.cancel()
+	}
+}
+
+// HubMetrics returns the send queues of the connected agents and the message
+// counters since the backend started
+func (h *Handler) HubMetrics() models.WebSocketHubMetrics {
+	h.mu.RLock()
+	agents := make([]models.WebSocketAgentMetrics, 0, len(h.clients))
+	for _, client := range h.clients {
+		agents = append(agents, models.WebSocketAgentMetrics{
+			AgentID:              client.agent.ID,
+			ConnectedAt:          client.stats.connectedAt,
+			ControlQueueDepth:    len(client.control),
+			ControlQueueCapacity: cap(client.control),
+			SendQueueDepth:       len(client.send),
+			SendQueueCapacity:    cap(client.send),
+			MessagesSent:         client.stats.sent.Load(),
+			MessagesDropped:      client.stats.dropped.Load(),
+		})
+	}
+	h.mu.RUnlock()
+	sort.Slice(agents, func(i, j int) bool { return agents[i].AgentID < agents[j].AgentID })
+
+	return models.WebSocketHubMetrics{
+		ConnectedAgents:         len(agents),
+		MessagesSent:            h.counters.sent.Load(),
+		MessagesDropped:         h.counters.dropped.Load(),
+		SlowConsumerDisconnects: h.counters.slowConsumerDisconnects.Load(),
+		Agents:                  agents,
+	}
+}
@@ -0,0 +1,79 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	wsservice "github.com/ZerkerEOD/krakenhashes/backend/internal/services/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(h *Handler, agentID int) *Client {
+	ctx, cancel := context.WithCancel(context.Background())
+	client := &Client{
+		handler: h,
+		agent:   &models.Agent{ID: agentID},
+		send:    make(chan *wsservice.Message, 2),
+		control: make(chan *wsservice.Message, 1),
+		ctx:     ctx,
+		cancel:  cancel,
+		stats:   clientStats{connectedAt: time.Now()},
+	}
+	h.clients[agentID] = client
+	return client
+}
+
+func TestEnqueueDisconnectsSlowConsumer(t *testing.T) {
+	slowConsumerWindow = time.Minute
+	h := &Handler{clients: make(map[int]*Client)}
+	client := newTestClient(h, 7)
+
+	msg := &wsservice.Message{Type: wsservice.TypeSyncCommand}
+	require.True(t, client.enqueue(msg))
+	require.True(t, client.enqueue(msg))
+
+	// The queue is full, messages are dropped until the limit is passed
+	for i := 0; i < slowConsumerDropLimit; i++ {
+		assert.False(t, client.enqueue(msg))
+	}
+	assert.NoError(t, client.ctx.Err(), "agent disconnected before passing the drop limit")
+
+	assert.False(t, client.enqueue(msg))
+	assert.Error(t, client.ctx.Err(), "slow consumer was not disconnected")
+
+	metrics := h.HubMetrics()
+	assert.Equal(t, 1, metrics.ConnectedAgents)
+	assert.Equal(t, int64(slowConsumerDropLimit+1), metrics.MessagesDropped)
+	assert.Equal(t, int64(1), metrics.SlowConsumerDisconnects)
+	require.Len(t, metrics.Agents, 1)
+	assert.Equal(t, 2, metrics.Agents[0].SendQueueDepth)
+	assert.Equal(t, 2, metrics.Agents[0].SendQueueCapacity)
+	assert.Equal(t, int64(slowConsumerDropLimit+1), metrics.Agents[0].MessagesDropped)
+
+	// Further drops do not count another disconnect
+	client.enqueue(msg)
+	assert.Equal(t, int64(1), h.HubMetrics().SlowConsumerDisconnects)
+}
+
+func TestEnqueueDropWindowResets(t *testing.T) {
+	slowConsumerWindow = time.Minute
+	h := &Handler{clients: make(map[int]*Client)}
+	client := newTestClient(h, 1)
+
+	// Control messages have their own queue
+	require.True(t, client.enqueue(&wsservice.Message{Type: wsservice.TypeJobStop}))
+
+	msg := &wsservice.Message{Type: wsservice.TypeJobStop}
+	for i := 0; i < slowConsumerDropLimit; i++ {
+		client.enqueue(msg)
+	}
+	// Drops older than the window no longer count
+	client.stats.windowStart = time.Now().Add(-2 * slowConsumerWindow)
+	client.enqueue(msg)
+	assert.NoError(t, client.ctx.Err())
+	assert.Equal(t, 0, h.HubMetrics().Agents[0].SendQueueDepth)
+	assert.Equal(t, 1, h.HubMetrics().Agents[0].ControlQueueDepth)
+}
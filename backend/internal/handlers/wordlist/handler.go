@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -18,6 +19,7 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/fsutil"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/textnorm"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/mazrean/formstream"
@@ -27,6 +29,7 @@ import (
 type Handler struct {
 	manager        wordlist.Manager
 	potfileService PotfileService
+	agentFiles     AgentFileRemover
 }
 
 // PotfileService interface for potfile operations
@@ -34,6 +37,17 @@ type PotfileService interface {
 	UpdatePotfileMetadata(ctx context.Context) error
 }
 
+// AgentFileRemover removes a deleted file from the caches of connected agents
+type AgentFileRemover interface {
+	RemoveFileFromAgents(fileType, name, category string)
+}
+
+// deleteConflictResponse is returned when a wordlist in use cannot be deleted
+type deleteConflictResponse struct {
+	Error      string                 `json:"error"`
+	References *models.FileReferences `json:"references,omitempty"`
+}
+
 // NewHandler creates a new wordlist handler
 func NewHandler(manager wordlist.Manager, potfileService PotfileService) *Handler {
 	return &Handler{
@@ -42,6 +56,11 @@ func NewHandler(manager wordlist.Manager, potfileService PotfileService) *Handle
 	}
 }
 
+// SetAgentFileRemover sets what removes deleted wordlists from agent caches
+func (h *Handler) SetAgentFileRemover(remover AgentFileRemover) {
+	h.agentFiles = remover
+}
+
 // HandleListWordlists handles requests to list wordlists
 func (h *Handler) HandleListWordlists(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -148,7 +167,11 @@ func (h *Handler) HandleAddWordlist(w http.ResponseWriter, r *http.Request) {
 		destPath string
 		fileNamePath string
 		md5Hash string
+		canonicalHash string
 		fileSize int64
+		normalize bool
+		onDuplicate = models.OnDuplicateAlias
+		normalization *textnorm.Report
 	)
 
 	// Register handlers for form fields
@@ -202,6 +225,30 @@ func (h *Handler) HandleAddWordlist(w http.ResponseWriter, r *http.Request) {
 		return nil
 	})
 
+	// Must come before the file, which is normalized while it is streamed
+	parser.Register("normalize_encoding", func(r io.Reader, header formstream.Header) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		normalize, _ = strconv.ParseBool(string(data))
+		debug.Info("HandleAddWordlist: Received normalize_encoding: %v", normalize)
+		return nil
+	})
+
+	parser.Register("on_duplicate", func(r io.Reader, header formstream.Header) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		onDuplicate = strings.TrimSpace(string(data))
+		if onDuplicate != models.OnDuplicateAlias && onDuplicate != models.OnDuplicateKeep {
+			return fmt.Errorf("invalid on_duplicate %q, must be %q or %q", onDuplicate, models.OnDuplicateAlias, models.OnDuplicateKeep)
+		}
+		debug.Info("HandleAddWordlist: Received on_duplicate: %s", onDuplicate)
+		return nil
+	})
+
 	// Register handler for file streaming
 	parser.Register("file", func(r io.Reader, header formstream.Header) error {
 		fileName = header.FileName()
@@ -256,9 +303,22 @@ func (h *Handler) HandleAddWordlist(w http.ResponseWriter, r *http.Request) {
 			return fmt.Errorf("failed to create file: %w", err)
 		}
 
-		// Stream file and calculate MD5 simultaneously
+		// Convert plain wordlists to UTF-8 with LF line endings when asked to
+		var normalizer *textnorm.Reader
+		if normalize && dbFormat == "plaintext" {
+			normalizer = textnorm.NewReader(r)
+			r = normalizer
+		}
+
+		// Stream file and calculate MD5 simultaneously, along with the
+		// canonical hash of plain wordlists
 		hasher := md5.New()
 		writer := io.MultiWriter(destFile, hasher)
+		var canonical *textnorm.Hasher
+		if dbFormat == "plaintext" {
+			canonical = textnorm.NewHasher(false)
+			writer = io.MultiWriter(destFile, hasher, canonical)
+		}
 		
 		// Use 32KB buffer for streaming to minimize memory usage
 		bytesWritten, err := io.CopyBuffer(writer, r, make([]byte, 32*1024))
@@ -271,8 +331,17 @@ func (h *Handler) HandleAddWordlist(w http.ResponseWriter, r *http.Request) {
 
 		// Calculate final MD5
 		md5Hash = fmt.Sprintf("%x", hasher.Sum(nil))
+		if canonical != nil {
+			canonicalHash = canonical.Sum()
+		}
 		fileSize = bytesWritten
 		debug.Info("HandleAddWordlist: File streamed successfully: %d bytes, MD5: %s", bytesWritten, md5Hash)
+		if normalizer != nil {
+			report := normalizer.Report()
+			normalization = &report
+			debug.Info("HandleAddWordlist: Normalized %s from %s, %d of %d lines changed",
+				fileName, report.SourceEncoding, report.ChangedLines, report.Lines)
+		}
 
 		// Close the file
 		if err := destFile.Close(); err != nil {
@@ -327,6 +396,7 @@ func (h *Handler) HandleAddWordlist(w http.ResponseWriter, r *http.Request) {
 		debug.Info("HandleAddWordlist: Duplicate wordlist detected with MD5 hash: %s", md5Hash)
 		// Remove the uploaded file since it's a duplicate
 		os.Remove(destPath)
+		h.aliasDuplicate(ctx, existingWordlist, fileName, userID)
 		httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
 			"id":        existingWordlist.ID,
 			"name":      existingWordlist.Name,
@@ -337,6 +407,31 @@ func (h *Handler) HandleAddWordlist(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A file only differing in BOM or line endings is aliased too, unless the
+	// uploader asked to keep a separate copy
+	if canonicalHash != "" && onDuplicate == models.OnDuplicateAlias {
+		existingWordlist, err = h.manager.GetWordlistByCanonicalHash(ctx, canonicalHash)
+		if err != nil {
+			debug.Error("Failed to check for near-duplicate wordlist: %v", err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to check for duplicate wordlist")
+			return
+		}
+		if existingWordlist != nil {
+			debug.Info("HandleAddWordlist: Near-duplicate of wordlist %d detected with canonical hash: %s", existingWordlist.ID, canonicalHash)
+			os.Remove(destPath)
+			h.aliasDuplicate(ctx, existingWordlist, fileName, userID)
+			httputil.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+				"id":             existingWordlist.ID,
+				"name":           existingWordlist.Name,
+				"message":        "Wordlist already exists with only line ending or byte order mark differences, the upload was aliased to it",
+				"duplicate":      true,
+				"near_duplicate": true,
+				"success":        true,
+			})
+			return
+		}
+	}
+
 	debug.Info("HandleAddWordlist: No duplicate wordlist found, proceeding with database entry")
 
 	// Check if a file with the same name already exists
@@ -412,6 +507,11 @@ func (h *Handler) HandleAddWordlist(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if err := h.manager.UpdateWordlistCanonicalHash(ctx, wordlistObj.ID, canonicalHash); err != nil {
+		// Only costs duplicate detection of later uploads
+		debug.Warning("HandleAddWordlist: Failed to record canonical hash of wordlist %d: %v", wordlistObj.ID, err)
+	}
+
 	// Mark upload as successful to prevent cleanup
 
 	// Automatically trigger verification process
@@ -462,7 +562,7 @@ func (h *Handler) HandleAddWordlist(w http.ResponseWriter, r *http.Request) {
 
 		// Return success response immediately with pending status
 		wordlistObj.VerificationStatus = "pending"
-		httputil.RespondWithJSON(w, http.StatusCreated, wordlistObj)
+		httputil.RespondWithJSON(w, http.StatusCreated, uploadResponse{wordlistObj, normalization})
 		return
 	}
 
@@ -492,7 +592,25 @@ func (h *Handler) HandleAddWordlist(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Return success response
-	httputil.RespondWithJSON(w, http.StatusCreated, wordlistObj)
+	httputil.RespondWithJSON(w, http.StatusCreated, uploadResponse{wordlistObj, normalization})
+}
+
+// uploadResponse is an uploaded wordlist with what normalizing its encoding changed
+type uploadResponse struct {
+	*models.Wordlist
+	Normalization *textnorm.Report `json:"normalization,omitempty"`
+}
+
+// aliasDuplicate records the name of an upload that was dropped as a duplicate
+// of an existing wordlist, unless it is the existing file's own name
+func (h *Handler) aliasDuplicate(ctx context.Context, existing *models.Wordlist, fileName string, userID uuid.UUID) {
+	alias := fsutil.SanitizeFilename(fileName)
+	if alias == "" || alias == filepath.Base(existing.FileName) {
+		return
+	}
+	if err := h.manager.AddWordlistAlias(ctx, existing.ID, alias, userID); err != nil {
+		debug.Warning("Failed to alias %s to wordlist %d: %v", alias, existing.ID, err)
+	}
 }
 
 // HandleUpdateWordlist handles requests to update a wordlist
@@ -553,20 +671,62 @@ func (h *Handler) HandleDeleteWordlist(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// force also removes the wordlist from the preset jobs using it
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+
 	// Delete wordlist
-	if err := h.manager.DeleteWordlist(ctx, id); err != nil {
-		if err == models.ErrResourceInUse {
-			httputil.RespondWithError(w, http.StatusConflict, "Cannot delete wordlist: it is currently being used by active jobs")
-			return
+	deleted, err := h.manager.DeleteWordlist(ctx, id, force)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrResourceInUse):
+			h.respondDeleteConflict(w, r, id, "Cannot delete wordlist: it is currently being used by active jobs")
+		case errors.Is(err, models.ErrResourceReferenced):
+			h.respondDeleteConflict(w, r, id, "Cannot delete wordlist: it is used by preset jobs, delete with force to remove it from them")
+		default:
+			debug.Error("Failed to delete wordlist %d: %v", id, err)
+			httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to delete wordlist")
 		}
-		debug.Error("Failed to delete wordlist %d: %v", id, err)
-		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to delete wordlist")
 		return
 	}
 
+	if h.agentFiles != nil {
+		h.agentFiles.RemoveFileFromAgents("wordlist", deleted.FileName, deleted.WordlistType)
+	}
+
 	httputil.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Wordlist deleted"})
 }
 
+// respondDeleteConflict responds to a refused delete with what uses the wordlist
+func (h *Handler) respondDeleteConflict(w http.ResponseWriter, r *http.Request, id int, message string) {
+	refs, err := h.manager.GetWordlistReferences(r.Context(), id)
+	if err != nil {
+		debug.Error("Failed to get references of wordlist %d: %v", id, err)
+	}
+	httputil.RespondWithJSON(w, http.StatusConflict, deleteConflictResponse{Error: message, References: refs})
+}
+
+// HandleGetWordlistReferences lists the preset jobs and active jobs using a wordlist
+func (h *Handler) HandleGetWordlistReferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get wordlist ID from URL
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid wordlist ID")
+		return
+	}
+
+	refs, err := h.manager.GetWordlistReferences(ctx, id)
+	if err != nil {
+		debug.Error("Failed to get references of wordlist %d: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get wordlist references")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, refs)
+}
+
 // HandleVerifyWordlist handles requests to verify a wordlist
 func (h *Handler) HandleVerifyWordlist(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -720,6 +880,68 @@ func (h *Handler) HandleDownloadWordlist(w http.ResponseWriter, r *http.Request)
 	debug.Info("File served successfully")
 }
 
+// HandlePreviewWordlist handles requests to preview the first or random lines of a wordlist
+func (h *Handler) HandlePreviewWordlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get wordlist ID from URL
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid wordlist ID")
+		return
+	}
+
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = models.WordlistPreviewHead
+	}
+	if mode != models.WordlistPreviewHead && mode != models.WordlistPreviewRandom {
+		httputil.RespondWithError(w, http.StatusBadRequest, "mode must be head or random")
+		return
+	}
+
+	lines := 20
+	if val := r.URL.Query().Get("lines"); val != "" {
+		parsed, err := strconv.Atoi(val)
+		if err != nil || parsed < 1 || parsed > wordlist.MaxPreviewLines {
+			httputil.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("lines must be between 1 and %d", wordlist.MaxPreviewLines))
+			return
+		}
+		lines = parsed
+	}
+
+	// Get wordlist
+	wl, err := h.manager.GetWordlist(ctx, id)
+	if err != nil {
+		debug.Error("Failed to get wordlist %d: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to get wordlist")
+		return
+	}
+
+	if wl == nil {
+		httputil.RespondWithError(w, http.StatusNotFound, "Wordlist not found")
+		return
+	}
+
+	filePath := h.manager.GetWordlistPath(wl.FileName, wl.WordlistType)
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		debug.Error("Wordlist file not found at path: %s", filePath)
+		httputil.RespondWithError(w, http.StatusNotFound, "Wordlist file not found")
+		return
+	}
+
+	preview, err := h.manager.PreviewWordlistFile(filePath, mode, lines)
+	if err != nil {
+		debug.Error("Failed to preview wordlist %d: %v", id, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to preview wordlist")
+		return
+	}
+	preview.WordlistID = wl.ID
+
+	httputil.RespondWithJSON(w, http.StatusOK, preview)
+}
+
 // HandleRefreshWordlist handles requests to refresh wordlist metadata (MD5, word count, file size)
 func (h *Handler) HandleRefreshWordlist(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -802,6 +1024,16 @@ func (h *Handler) HandleRefreshWordlist(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
+		// Record the canonical hash of plain wordlists, which were imported
+		// before it was computed or changed on disk since
+		if wordlist.Format == "plaintext" {
+			if canonicalHash, err := textnorm.HashFile(filePath, false); err != nil {
+				debug.Warning("Failed to calculate canonical hash of wordlist %d: %v", id, err)
+			} else if err := h.manager.UpdateWordlistCanonicalHash(ctx, id, canonicalHash); err != nil {
+				debug.Warning("Failed to record canonical hash of wordlist %d: %v", id, err)
+			}
+		}
+
 		debug.Info("Successfully refreshed metadata for wordlist %d - MD5: %s, Size: %d, Words: %d",
 			id, md5Hash, fileInfo.Size(), wordCount)
 	}
@@ -820,3 +1052,95 @@ func (h *Handler) HandleRefreshWordlist(w http.ResponseWriter, r *http.Request)
 		"wordlist": updatedWordlist,
 	})
 }
+
+// HandleMoveWordlist handles requests to move a wordlist file into another directory
+func (h *Handler) HandleMoveWordlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Get wordlist ID from URL
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid wordlist ID")
+		return
+	}
+
+	var req models.FileMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	wordlist, err := h.manager.MoveWordlist(ctx, id, req.Directory)
+	if err != nil {
+		respondWithMoveError(w, "Failed to move wordlist", err)
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, wordlist)
+}
+
+// HandleListDirectories handles requests to list the wordlist directories
+func (h *Handler) HandleListDirectories(w http.ResponseWriter, r *http.Request) {
+	dirs, err := h.manager.ListDirectories(r.Context())
+	if err != nil {
+		debug.Error("Failed to list wordlist directories: %v", err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, "Failed to list directories")
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusOK, dirs)
+}
+
+// HandleCreateDirectory handles requests to create a wordlist directory
+func (h *Handler) HandleCreateDirectory(w http.ResponseWriter, r *http.Request) {
+	var req models.DirectoryCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	dir, err := h.manager.CreateDirectory(r.Context(), req.Path)
+	if err != nil {
+		respondWithMoveError(w, "Failed to create directory", err)
+		return
+	}
+
+	httputil.RespondWithJSON(w, http.StatusCreated, models.ResourceDirectory{Path: dir})
+}
+
+// HandleMoveDirectory handles requests to rename or move a wordlist directory
+// with all wordlists inside it
+func (h *Handler) HandleMoveDirectory(w http.ResponseWriter, r *http.Request) {
+	var req models.DirectoryMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	moved, err := h.manager.MoveDirectory(r.Context(), req.From, req.To)
+	if err != nil {
+		respondWithMoveError(w, "Failed to move directory", err)
+		return
+	}
+
+	dir, _ := fsutil.CleanRelativeDir(req.To)
+	httputil.RespondWithJSON(w, http.StatusOK, models.DirectoryMoveResponse{Path: dir, FilesMoved: moved})
+}
+
+// respondWithMoveError maps the errors of directory and move operations to a response
+func respondWithMoveError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidInput):
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrNotFound):
+		httputil.RespondWithError(w, http.StatusNotFound, "Not found")
+	case errors.Is(err, models.ErrAlreadyExists):
+		httputil.RespondWithError(w, http.StatusConflict, "The destination already exists")
+	case errors.Is(err, models.ErrResourceInUse):
+		httputil.RespondWithError(w, http.StatusConflict, "A wordlist in use by active jobs or the pot-file cannot be moved")
+	default:
+		debug.Error("%s: %v", message, err)
+		httputil.RespondWithError(w, http.StatusInternalServerError, message)
+	}
+}
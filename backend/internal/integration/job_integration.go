@@ -8,10 +8,12 @@ import (
 	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/binary"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/rule"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/jobstream"
 	wsservice "github.com/ZerkerEOD/krakenhashes/backend/internal/services/websocket"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/wordlist"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
@@ -22,6 +24,8 @@ import (
 type JobIntegrationManager struct {
 	wsIntegration        *JobWebSocketIntegration
 	jobSchedulingService *services.JobSchedulingService
+	hashlistCompletion   *services.HashlistCompletionService
+	progressHub          *jobstream.Hub
 	wsHandler            interface {
 		SendMessage(agentID int, msg *wsservice.Message) error
 		GetConnectedAgents() []int
@@ -78,10 +82,12 @@ func NewJobIntegrationManager(
 
 	// Set the WebSocket integration in the scheduling service
 	jobSchedulingService.SetWebSocketIntegration(wsIntegration)
+	jobSchedulingService.SetHashlistCompletionService(hashlistCompletionService)
 
 	return &JobIntegrationManager{
 		wsIntegration:        wsIntegration,
 		jobSchedulingService: jobSchedulingService,
+		hashlistCompletion:   hashlistCompletionService,
 		wsHandler:            wsHandler,
 	}
 }
@@ -106,6 +112,16 @@ func (m *JobIntegrationManager) ProcessBenchmarkResult(ctx context.Context, agen
 	return m.wsIntegration.HandleBenchmarkResult(ctx, agentID, &result)
 }
 
+// ProcessCrackJournal handles crack journal entries replayed by agents
+func (m *JobIntegrationManager) ProcessCrackJournal(ctx context.Context, agentID int, payload json.RawMessage) error {
+	var entry wsservice.CrackJournalPayload
+	if err := json.Unmarshal(payload, &entry); err != nil {
+		return fmt.Errorf("failed to unmarshal crack journal entry: %w", err)
+	}
+
+	return m.wsIntegration.HandleCrackJournal(ctx, agentID, &entry)
+}
+
 // RecoverTask attempts to recover a task that was in reconnect_pending state (implements interfaces.JobHandler)
 func (m *JobIntegrationManager) RecoverTask(ctx context.Context, taskID string, agentID int, keyspaceProcessed int64) error {
 	return m.wsIntegration.RecoverTask(ctx, taskID, agentID, keyspaceProcessed)
@@ -128,6 +144,28 @@ func (m *JobIntegrationManager) StartScheduler(ctx context.Context) {
 	go m.jobSchedulingService.StartScheduler(ctx, 30*time.Second)
 }
 
+// RequestSchedule asks the running scheduler for a scheduling cycle now
+func (m *JobIntegrationManager) RequestSchedule() {
+	m.jobSchedulingService.RequestSchedule()
+}
+
+// SetProgressHub sets the hub job progress is streamed to the UI through
+func (m *JobIntegrationManager) SetProgressHub(hub *jobstream.Hub) {
+	m.progressHub = hub
+	m.wsIntegration.SetProgressHub(hub)
+}
+
+// SubscribeEvents registers the job services' event handlers on the event bus
+func (m *JobIntegrationManager) SubscribeEvents(bus *events.Bus) {
+	m.jobSchedulingService.SubscribeEvents(bus)
+	if m.hashlistCompletion != nil {
+		m.hashlistCompletion.SubscribeEvents(bus)
+	}
+	if m.progressHub != nil {
+		m.progressHub.SubscribeEvents(bus)
+	}
+}
+
 // StopJob stops a running job
 func (m *JobIntegrationManager) StopJob(ctx context.Context, jobExecutionID uuid.UUID, reason string) error {
 	debug.Log("Stop job requested", map[string]interface{}{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"sync"
@@ -11,13 +12,17 @@ import (
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/binary"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/rule"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/jobstream"
 	wsservice "github.com/ZerkerEOD/krakenhashes/backend/internal/services/websocket"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/wordlist"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatargs"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/plaintext"
 	"github.com/google/uuid"
 	"strconv"
 	"strings"
@@ -46,10 +51,15 @@ type JobWebSocketIntegration struct {
 	wordlistManager         wordlist.Manager
 	ruleManager             rule.Manager
 	binaryManager           binary.Manager
+	progressHub             *jobstream.Hub
 
 	// Progress tracking
 	progressMutex   sync.RWMutex
 	taskProgressMap map[string]*models.JobProgress // TaskID -> Progress
+
+	// Benchmark tracking, so the event log records how long benchmarks took
+	benchmarkMutex    sync.Mutex
+	benchmarkRequests map[int]time.Time // AgentID -> when its last benchmark was requested
 }
 
 // NewJobWebSocketIntegration creates a new job WebSocket integration service
@@ -97,9 +107,15 @@ func NewJobWebSocketIntegration(
 		ruleManager:               ruleManager,
 		binaryManager:             binaryManager,
 		taskProgressMap:           make(map[string]*models.JobProgress),
+		benchmarkRequests:         make(map[int]time.Time),
 	}
 }
 
+// SetProgressHub sets the hub task progress is streamed to the UI through
+func (s *JobWebSocketIntegration) SetProgressHub(hub *jobstream.Hub) {
+	s.progressHub = hub
+}
+
 // SendJobAssignment sends a job task assignment to an agent via WebSocket
 func (s *JobWebSocketIntegration) SendJobAssignment(ctx context.Context, task *models.JobTask, jobExecution *models.JobExecution) error {
 	debug.Log("Sending job assignment to agent", map[string]interface{}{
@@ -125,6 +141,7 @@ func (s *JobWebSocketIntegration) SendJobAssignment(ctx context.Context, task *m
 
 	// Build wordlist and rule paths using job execution's self-contained configuration
 	var wordlistPaths []string
+	fileHashes := make(map[string]string)
 	for _, wordlistIDStr := range jobExecution.WordlistIDs {
 		// Convert string ID to int
 		wordlistID, err := strconv.Atoi(wordlistIDStr)
@@ -144,31 +161,51 @@ func (s *JobWebSocketIntegration) SendJobAssignment(ctx context.Context, task *m
 		// Use the actual file path from the database
 		wordlistPath := fmt.Sprintf("wordlists/%s", wordlist.FileName)
 		wordlistPaths = append(wordlistPaths, wordlistPath)
+		fileHashes[wordlistPath] = wordlist.MD5Hash
 	}
 
 	var rulePaths []string
+	var ruleChunks []wsservice.RuleChunkFile
 	// Check if this is a rule split task with a chunk file
 	if task.IsRuleSplitTask && task.RuleChunkPath != nil && *task.RuleChunkPath != "" {
-		// Extract job directory from the chunk path
-		pathParts := strings.Split(*task.RuleChunkPath, string(filepath.Separator))
-		var jobDirName string
-		chunkFilename := filepath.Base(*task.RuleChunkPath)
-
-		// Find the job directory name
-		for i, part := range pathParts {
-			if strings.HasPrefix(part, "job_") && i < len(pathParts)-1 {
-				jobDirName = part
-				break
-			}
+		chunkFile, err := s.jobExecutionService.RuleChunkFile(ctx, *task.RuleChunkPath)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("failed to get rule chunk of task %s: %w", task.ID, err)
 		}
 
-		// Create the rule path with job directory
 		var rulePath string
-		if jobDirName != "" {
-			rulePath = fmt.Sprintf("rules/chunks/%s/%s", jobDirName, chunkFilename)
+		if chunkFile != nil {
+			// Registered chunks are downloaded and verified like other synced files
+			rulePath = "rules/" + chunkFile.Name
+			fileHashes[rulePath] = chunkFile.MD5Hash
+			ruleChunks = append(ruleChunks, wsservice.RuleChunkFile{
+				ID:      chunkFile.ID,
+				Name:    chunkFile.Name,
+				MD5Hash: chunkFile.MD5Hash,
+				Size:    chunkFile.FileSize,
+			})
 		} else {
-			// Fallback to just chunk filename
-			rulePath = fmt.Sprintf("rules/chunks/%s", chunkFilename)
+			// Chunks created before chunks were registered are found by their
+			// job directory and file name
+			pathParts := strings.Split(*task.RuleChunkPath, string(filepath.Separator))
+			var jobDirName string
+			chunkFilename := filepath.Base(*task.RuleChunkPath)
+
+			// Find the job directory name
+			for i, part := range pathParts {
+				if strings.HasPrefix(part, "job_") && i < len(pathParts)-1 {
+					jobDirName = part
+					break
+				}
+			}
+
+			// Create the rule path with job directory
+			if jobDirName != "" {
+				rulePath = fmt.Sprintf("rules/chunks/%s/%s", jobDirName, chunkFilename)
+			} else {
+				// Fallback to just chunk filename
+				rulePath = fmt.Sprintf("rules/chunks/%s", chunkFilename)
+			}
 		}
 		rulePaths = append(rulePaths, rulePath)
 
@@ -176,7 +213,7 @@ func (s *JobWebSocketIntegration) SendJobAssignment(ctx context.Context, task *m
 			"task_id":    task.ID,
 			"chunk_path": *task.RuleChunkPath,
 			"agent_path": rulePath,
-			"job_dir":    jobDirName,
+			"registered": chunkFile != nil,
 		})
 	} else {
 		// Standard rule processing
@@ -199,16 +236,22 @@ func (s *JobWebSocketIntegration) SendJobAssignment(ctx context.Context, task *m
 			// Use the actual file path from the database
 			rulePath := fmt.Sprintf("rules/%s", rule.FileName)
 			rulePaths = append(rulePaths, rulePath)
+			fileHashes[rulePath] = rule.MD5Hash
 		}
 	}
 
-	// Get binary path from binary version
-	binaryVersion, err := s.binaryManager.GetVersion(ctx, int64(jobExecution.BinaryVersionID))
+	// Get binary path from binary version, canaries of a binary rollout run
+	// its new version in place of the job's
+	binaryVersionID := s.jobExecutionService.TaskBinaryVersion(ctx, agent, jobExecution.BinaryVersionID)
+	binaryVersion, err := s.binaryManager.GetVersion(ctx, int64(binaryVersionID))
 	if err != nil {
-		return fmt.Errorf("failed to get binary version %d: %w", jobExecution.BinaryVersionID, err)
+		return fmt.Errorf("failed to get binary version %d: %w", binaryVersionID, err)
 	}
 	if binaryVersion == nil {
-		return fmt.Errorf("binary version %d not found", jobExecution.BinaryVersionID)
+		return fmt.Errorf("binary version %d not found", binaryVersionID)
+	}
+	if err := s.jobTaskRepo.SetBinaryVersion(ctx, task.ID, binaryVersionID); err != nil {
+		return fmt.Errorf("failed to record task binary version: %w", err)
 	}
 
 	// Use the actual binary path - the ID is used as the directory name
@@ -244,25 +287,89 @@ func (s *JobWebSocketIntegration) SendJobAssignment(ctx context.Context, task *m
 		}
 	}
 
+	// Resume a chunk from its checkpoint when an earlier run got part way
+	checkpoint, err := s.jobTaskRepo.GetCheckpoint(ctx, task.ID)
+	if err != nil {
+		debug.Warning("Failed to get checkpoint of task %s: %v", task.ID, err)
+	}
+	resumeOffset := services.ResolveResumeOffset(task, checkpoint)
+	if resumeOffset != task.ResumeOffset {
+		if err := s.jobTaskRepo.SetResumeOffset(ctx, task.ID, resumeOffset); err != nil {
+			return fmt.Errorf("failed to record task resume offset: %w", err)
+		}
+		task.ResumeOffset = resumeOffset
+	}
+	if resumeOffset > 0 {
+		debug.Info("Resuming task %s from checkpoint %d, %d candidates into its chunk", task.ID, *checkpoint, resumeOffset)
+	}
+
+	// Start keyspace chunks early by the configured overlap, progress is
+	// corrected for it when the agent reports back. A restore point is where
+	// hashcat itself resumes, so resumed chunks need no overlap.
+	overlap := int64(0)
+	if resumeOffset == 0 {
+		overlap = s.jobExecutionService.ResolveChunkOverlap(ctx, task)
+	}
+
+	// A task of an --increment mask runs the mask cut to the length its chunk
+	// falls in, with the range rebased to the start of that length
+	mask := jobExecution.Mask
+	var stepStart int64
+	if jobExecution.MaskIncrement != nil {
+		step, ok := jobExecution.MaskIncrement.StepAt(task.KeyspaceStart)
+		if !ok {
+			return fmt.Errorf("task %s starts outside the keyspace of its mask increments", task.ID)
+		}
+		if mask, err = step.StepMask(jobExecution.Mask); err != nil {
+			return fmt.Errorf("failed to build mask of length %d: %w", step.Length, err)
+		}
+		stepStart = step.Start
+		overlap = min(overlap, task.KeyspaceStart-step.Start)
+	}
+	if overlap != task.ChunkOverlap {
+		if err := s.jobTaskRepo.SetChunkOverlap(ctx, task.ID, overlap); err != nil {
+			return fmt.Errorf("failed to record task chunk overlap: %w", err)
+		}
+		task.ChunkOverlap = overlap
+	}
+
 	// Create task assignment payload
 	assignment := wsservice.TaskAssignmentPayload{
 		TaskID:          task.ID.String(),
 		JobExecutionID:  jobExecution.ID.String(),
 		HashlistID:      jobExecution.HashlistID,
-		HashlistPath:    fmt.Sprintf("hashlists/%d.hash", jobExecution.HashlistID),
+		HashlistPath:    s.jobExecutionService.JobHashlistPath(ctx, jobExecution),
 		AttackMode:      int(jobExecution.AttackMode),
 		HashType:        hashlist.HashTypeID,
-		KeyspaceStart:   task.KeyspaceStart,
-		KeyspaceEnd:     task.KeyspaceEnd,
+		KeyspaceStart:   task.KeyspaceStart + task.ResumeOffset - task.ChunkOverlap - stepStart,
+		KeyspaceEnd:     task.KeyspaceEnd - stepStart,
 		WordlistPaths:   wordlistPaths,
 		RulePaths:       rulePaths,
-		Mask:            jobExecution.Mask,
+		Mask:            mask,
 		BinaryPath:      binaryPath,
 		ChunkDuration:   task.ChunkDuration,
 		ReportInterval:  reportInterval,
-		OutputFormat:    "3",                   // hash:plain format
-		ExtraParameters: agent.ExtraParameters, // Agent-specific hashcat parameters
-		EnabledDevices:  enabledDeviceIDs,      // Only populated if some devices are disabled
+		OutputFormat:    "3",                         // hash:plain format
+		ExtraParameters: agentExtraParameters(agent), // Agent-specific hashcat parameters
+		EnabledDevices:  enabledDeviceIDs,            // Only populated if some devices are disabled
+		FileHashes:      fileHashes,
+		RuleChunks:      ruleChunks,
+	}
+	if binaryHashes, err := s.binaryManager.ExecutableHashes(ctx, binaryVersion.ID); err != nil {
+		// The agent reports the binary as unattested rather than failing the task
+		debug.Warning("Failed to get executable hashes of binary version %d: %v", binaryVersion.ID, err)
+	} else {
+		assignment.BinaryHashes = binaryHashes
+	}
+	assignment.ExtraArgs = strings.Fields(assignment.ExtraParameters)
+	jobArgs := s.jobExecutionService.JobHashcatArgs(ctx, jobExecution)
+	// Jobs may not raise the workload of a shared workstation
+	if agent.WorkloadClass == models.WorkloadClassShared {
+		jobArgs = hashcatargs.WithoutWorkloadProfile(jobArgs)
+	}
+	if len(jobArgs) > 0 {
+		assignment.JobArgs = jobArgs
+		assignment.JobExtraParameters = strings.Join(jobArgs, " ")
 	}
 
 	// Marshal payload
@@ -501,13 +608,15 @@ func (s *JobWebSocketIntegration) RequestAgentBenchmark(ctx context.Context, age
 		rulePaths = append(rulePaths, rulePath)
 	}
 
-	// Get binary path from binary version
-	binaryVersion, err := s.binaryManager.GetVersion(ctx, int64(jobExecution.BinaryVersionID))
+	// Get binary path from binary version, benchmarking canaries of a binary
+	// rollout with the version their tasks will run
+	binaryVersionID := s.jobExecutionService.TaskBinaryVersion(ctx, agent, jobExecution.BinaryVersionID)
+	binaryVersion, err := s.binaryManager.GetVersion(ctx, int64(binaryVersionID))
 	if err != nil {
-		return fmt.Errorf("failed to get binary version %d: %w", jobExecution.BinaryVersionID, err)
+		return fmt.Errorf("failed to get binary version %d: %w", binaryVersionID, err)
 	}
 	if binaryVersion == nil {
-		return fmt.Errorf("binary version %d not found", jobExecution.BinaryVersionID)
+		return fmt.Errorf("binary version %d not found", binaryVersionID)
 	}
 
 	// Use the actual binary path - the ID is used as the directory name
@@ -558,6 +667,23 @@ func (s *JobWebSocketIntegration) RequestAgentBenchmark(ctx context.Context, age
 		}
 	}
 
+	// A job can time-box its benchmarks
+	database := &db.DB{DB: s.db}
+	override, err := repository.NewJobExecutionRepository(database).GetBenchmarkOverride(ctx, jobExecution.ID)
+	if err != nil {
+		debug.Warning("Failed to get benchmark override for job %s: %v", jobExecution.ID, err)
+		override = &models.BenchmarkOverride{}
+	}
+	testDuration, speedtestTimeout := override.SpeedTestDurations(speedtestTimeout)
+
+	// An --increment mask is benchmarked at its longest length
+	mask := jobExecution.Mask
+	if jobExecution.MaskIncrement != nil {
+		if mask, err = (models.MaskIncrementStep{Length: jobExecution.MaskIncrement.Max}).StepMask(jobExecution.Mask); err != nil {
+			return fmt.Errorf("failed to build benchmark mask: %w", err)
+		}
+	}
+
 	// Create enhanced benchmark request payload with job-specific configuration
 	benchmarkReq := wsservice.BenchmarkRequestPayload{
 		RequestID:       requestID,
@@ -567,15 +693,16 @@ func (s *JobWebSocketIntegration) RequestAgentBenchmark(ctx context.Context, age
 		AttackMode:      int(jobExecution.AttackMode),
 		BinaryPath:      binaryPath,
 		HashlistID:      jobExecution.HashlistID,
-		HashlistPath:    fmt.Sprintf("hashlists/%d.hash", jobExecution.HashlistID),
+		HashlistPath:    s.jobExecutionService.JobHashlistPath(ctx, jobExecution),
 		WordlistPaths:   wordlistPaths,
 		RulePaths:       rulePaths,
-		Mask:            jobExecution.Mask,
-		TestDuration:    30,                    // 30-second benchmark for accuracy
-		TimeoutDuration: speedtestTimeout,      // Configurable timeout for speedtest
-		ExtraParameters: agent.ExtraParameters, // Agent-specific hashcat parameters
-		EnabledDevices:  enabledDeviceIDs,      // Only populated if some devices are disabled
+		Mask:            mask,
+		TestDuration:    testDuration,                // 30-second benchmark for accuracy unless the job time-boxes it
+		TimeoutDuration: speedtestTimeout,            // Configurable timeout for speedtest
+		ExtraParameters: agentExtraParameters(agent), // Agent-specific hashcat parameters
+		EnabledDevices:  enabledDeviceIDs,            // Only populated if some devices are disabled
 	}
+	benchmarkReq.ExtraArgs = strings.Fields(benchmarkReq.ExtraParameters)
 
 	// Marshal payload
 	payloadBytes, err := json.Marshal(benchmarkReq)
@@ -595,6 +722,10 @@ func (s *JobWebSocketIntegration) RequestAgentBenchmark(ctx context.Context, age
 		return fmt.Errorf("failed to send benchmark request via WebSocket: %w", err)
 	}
 
+	s.benchmarkMutex.Lock()
+	s.benchmarkRequests[agent.ID] = time.Now()
+	s.benchmarkMutex.Unlock()
+
 	debug.Log("Enhanced benchmark request sent successfully", map[string]interface{}{
 		"agent_id":   agentID,
 		"request_id": requestID,
@@ -633,6 +764,23 @@ func (s *JobWebSocketIntegration) HandleJobProgress(ctx context.Context, agentID
 		return fmt.Errorf("task not assigned to this agent")
 	}
 
+	// A task that lost a speculative race was cancelled, late updates from its agent are stale
+	if s.jobSchedulingService.IsSpeculativeLoser(ctx, task) {
+		debug.Info("Ignoring progress for task %s, its chunk was completed by a speculative peer", progress.TaskID)
+		return nil
+	}
+
+	// Report progress through the task's own keyspace, not the overlap before it,
+	// and including what an earlier run processed before the checkpoint
+	services.RemoveChunkOverlap(task, progress)
+	services.ApplyResumeOffset(task, progress)
+
+	if checkpoint, ok := services.TaskCheckpoint(task, progress); ok {
+		if err := s.jobTaskRepo.UpdateCheckpoint(ctx, task.ID, checkpoint); err != nil {
+			debug.Warning("Failed to record checkpoint of task %s: %v", task.ID, err)
+		}
+	}
+
 	// Update task status to running if it's still assigned
 	if task.Status == models.JobTaskStatusAssigned {
 		// Use StartTask to update both status and started_at timestamp
@@ -657,8 +805,12 @@ func (s *JobWebSocketIntegration) HandleJobProgress(ctx context.Context, agentID
 		}
 	}
 
+	s.recordGPUUsage(ctx, task, agentID, progress)
+
 	// Update task effective keyspace from hashcat progress[1] if we haven't already
-	if progress.TotalEffectiveKeyspace != nil && *progress.TotalEffectiveKeyspace > 0 && !task.IsActualKeyspace {
+	// Speculative and verification copies cover a chunk that was already accounted for by the original task
+	if progress.TotalEffectiveKeyspace != nil && *progress.TotalEffectiveKeyspace > 0 && !task.IsActualKeyspace &&
+		task.SpeculativeOf == nil && task.VerificationOf == nil {
 		// IMPORTANT: progress.TotalEffectiveKeyspace is the CHUNK's actual keyspace size (not cumulative!)
 		// It represents the total keyspace for this specific chunk's rules
 		chunkActualKeyspace := *progress.TotalEffectiveKeyspace
@@ -719,6 +871,45 @@ func (s *JobWebSocketIntegration) HandleJobProgress(ctx context.Context, agentID
 	s.taskProgressMap[progress.TaskID.String()] = progress
 	s.progressMutex.Unlock()
 
+	s.streamProgress(task, agentID, progress)
+
+	if progress.Status != "" && progress.Status != "running" {
+		s.recordTaskArtifact(ctx, task, agentID, progress)
+	}
+
+	if progress.BinaryAttestation != nil {
+		if err := s.jobTaskRepo.SetBinaryAttestation(ctx, progress.TaskID, progress.BinaryAttestation); err != nil {
+			debug.Error("Failed to record binary attestation of task %s: %v", progress.TaskID, err)
+		}
+	}
+
+	// An agent with a modified hashcat binary never ran the task, the chunk is
+	// dispatched to another agent and this one is disabled
+	if progress.Status == "failed" && progress.ErrorCode == string(models.TaskErrorBinaryMismatch) {
+		s.handleBinaryMismatch(ctx, task, agentID, progress)
+		return nil
+	}
+
+	// A failed verification re-run is given up, it must not fail the job
+	if progress.Status == "failed" && task.VerificationOf != nil {
+		if err := s.jobTaskRepo.UpdateTaskError(ctx, progress.TaskID, progress.ErrorMessage); err != nil {
+			debug.Error("Failed to update task error: %v", err)
+		}
+		if err := s.jobSchedulingService.AbandonVerificationTask(ctx, task); err != nil {
+			debug.Error("Failed to skip chunk verification of task %s: %v", progress.TaskID, err)
+		}
+		s.clearAgentBusy(ctx, task)
+		return nil
+	}
+
+	// A stale or corrupted file is re-downloaded by the agent, the chunk is
+	// dispatched again instead of failing the job
+	if progress.Status == "failed" && progress.ErrorCode == string(models.TaskErrorFileMismatch) {
+		if s.retryAfterFileMismatch(ctx, task, agentID, progress.ErrorMessage) {
+			return nil
+		}
+	}
+
 	// Check if this is a failure update
 	if progress.Status == "failed" && progress.ErrorMessage != "" {
 		debug.Log("Task failed with error", map[string]interface{}{
@@ -751,6 +942,8 @@ func (s *JobWebSocketIntegration) HandleJobProgress(ctx context.Context, agentID
 		jobExecRepo := repository.NewJobExecutionRepository(database)
 		if err := jobExecRepo.UpdateStatus(ctx, task.JobExecutionID, models.JobExecutionStatusFailed); err != nil {
 			debug.Error("Failed to update job execution status: %v", err)
+		} else if s.progressHub != nil {
+			s.progressHub.PublishJobStatus(task.JobExecutionID, models.JobExecutionStatusFailed)
 		}
 		if err := jobExecRepo.UpdateErrorMessage(ctx, task.JobExecutionID, progress.ErrorMessage); err != nil {
 			debug.Error("Failed to update job execution error message: %v", err)
@@ -811,6 +1004,25 @@ func (s *JobWebSocketIntegration) HandleJobProgress(ctx context.Context, agentID
 				"task_id": progress.TaskID,
 				"error":   err.Error(),
 			})
+		} else {
+			s.publishTaskCompleted(ctx, task)
+		}
+
+		// First result wins, stop any speculative peer still working on this chunk
+		if err := s.jobSchedulingService.ResolveSpeculativeTask(ctx, task); err != nil {
+			debug.Error("Failed to resolve speculative peers of task %s: %v", progress.TaskID, err)
+		}
+
+		// A verification re-run only checks the original agent, its job is unaffected
+		if task.VerificationOf != nil {
+			if err := s.jobSchedulingService.ResolveVerificationTask(ctx, task); err != nil {
+				debug.Error("Failed to resolve chunk verification of task %s: %v", progress.TaskID, err)
+			}
+			s.clearAgentBusy(ctx, task)
+			return nil
+		}
+		if err := s.jobSchedulingService.SampleCompletedTask(ctx, task); err != nil {
+			debug.Error("Failed to sample task %s for verification: %v", progress.TaskID, err)
 		}
 
 		// Clear agent busy status
@@ -885,6 +1097,25 @@ func (s *JobWebSocketIntegration) HandleJobProgress(ctx context.Context, agentID
 				"task_id": progress.TaskID,
 				"error":   err.Error(),
 			})
+		} else {
+			s.publishTaskCompleted(ctx, task)
+		}
+
+		// First result wins, stop any speculative peer still working on this chunk
+		if err := s.jobSchedulingService.ResolveSpeculativeTask(ctx, task); err != nil {
+			debug.Error("Failed to resolve speculative peers of task %s: %v", progress.TaskID, err)
+		}
+
+		// A verification re-run only checks the original agent, its job is unaffected
+		if task.VerificationOf != nil {
+			if err := s.jobSchedulingService.ResolveVerificationTask(ctx, task); err != nil {
+				debug.Error("Failed to resolve chunk verification of task %s: %v", progress.TaskID, err)
+			}
+			s.clearAgentBusy(ctx, task)
+			return nil
+		}
+		if err := s.jobSchedulingService.SampleCompletedTask(ctx, task); err != nil {
+			debug.Error("Failed to sample task %s for verification: %v", progress.TaskID, err)
 		}
 
 		// Clear agent busy status
@@ -922,6 +1153,88 @@ func (s *JobWebSocketIntegration) HandleJobProgress(ctx context.Context, agentID
 	return nil
 }
 
+// clearAgentBusy marks the agent of a finished task as free for new work
+func (s *JobWebSocketIntegration) clearAgentBusy(ctx context.Context, task *models.JobTask) {
+	if task.AgentID == nil {
+		return
+	}
+	agent, err := s.agentRepo.GetByID(ctx, *task.AgentID)
+	if err == nil && agent.Metadata != nil {
+		agent.Metadata["busy_status"] = "false"
+		delete(agent.Metadata, "current_task_id")
+		delete(agent.Metadata, "current_job_id")
+		if err := s.agentRepo.UpdateMetadata(ctx, agent.ID, agent.Metadata); err != nil {
+			debug.Error("Failed to clear busy status for agent %d: %v", agent.ID, err)
+		}
+	}
+}
+
+// handleBinaryMismatch takes a task back from an agent whose hashcat binary
+// failed attestation and quarantines the agent
+func (s *JobWebSocketIntegration) handleBinaryMismatch(ctx context.Context, task *models.JobTask, agentID int, progress *models.JobProgress) {
+	debug.Warning("Agent %d refused task %s: %s", agentID, task.ID, progress.ErrorMessage)
+
+	if task.VerificationOf != nil {
+		if err := s.jobTaskRepo.UpdateTaskError(ctx, task.ID, progress.ErrorMessage); err != nil {
+			debug.Error("Failed to update task error: %v", err)
+		}
+		if err := s.jobSchedulingService.AbandonVerificationTask(ctx, task); err != nil {
+			debug.Error("Failed to skip chunk verification of task %s: %v", task.ID, err)
+		}
+	} else if err := s.jobTaskRepo.ResetTaskForRetry(ctx, task.ID); err != nil {
+		debug.Error("Failed to reset task %s after binary mismatch: %v", task.ID, err)
+	}
+
+	if err := s.jobSchedulingService.QuarantineForBinaryMismatch(ctx, task, agentID, progress.BinaryAttestation); err != nil {
+		debug.Error("Failed to quarantine agent %d after binary mismatch: %v", agentID, err)
+	}
+	s.clearAgentBusy(ctx, task)
+}
+
+// retryAfterFileMismatch puts a task whose agent found a wordlist or rule file
+// that does not match the server back in the queue, and holds the agent back
+// from new work while it re-downloads the file. It returns false when the
+// task is out of retries and should fail as usual.
+func (s *JobWebSocketIntegration) retryAfterFileMismatch(ctx context.Context, task *models.JobTask, agentID int, message string) bool {
+	maxRetries := 3
+	if setting, err := s.systemSettingsRepo.GetSetting(ctx, "max_chunk_retry_attempts"); err == nil && setting.Value != nil {
+		if retries, err := strconv.Atoi(*setting.Value); err == nil {
+			maxRetries = retries
+		}
+	}
+	if task.RetryCount >= maxRetries {
+		debug.Warning("Task %s hit a file mismatch after %d retries, failing it: %s", task.ID, task.RetryCount, message)
+		return false
+	}
+
+	if err := s.jobTaskRepo.ResetTaskForRetry(ctx, task.ID); err != nil {
+		debug.Error("Failed to reset task %s after file mismatch: %v", task.ID, err)
+		return false
+	}
+	debug.Warning("Agent %d reported a file mismatch for task %s, re-dispatching it: %s", agentID, task.ID, message)
+
+	// The agent reports the end of the repair as a completed file sync, the
+	// scheduler skips it until then
+	agent, err := s.agentRepo.GetByID(ctx, agentID)
+	if err != nil {
+		debug.Error("Failed to get agent %d after file mismatch: %v", agentID, err)
+		return true
+	}
+	if agent.Metadata != nil {
+		agent.Metadata["busy_status"] = "false"
+		delete(agent.Metadata, "current_task_id")
+		delete(agent.Metadata, "current_job_id")
+	}
+	agent.SyncStatus = models.AgentSyncStatusInProgress
+	agent.SyncStartedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	agent.SyncCompletedAt = sql.NullTime{Valid: false}
+	agent.UpdatedAt = time.Now()
+	if err := s.agentRepo.Update(ctx, agent); err != nil {
+		debug.Error("Failed to hold agent %d back during file repair: %v", agentID, err)
+	}
+	return true
+}
+
 // HandleBenchmarkResult processes benchmark results from agents
 func (s *JobWebSocketIntegration) HandleBenchmarkResult(ctx context.Context, agentID int, result *wsservice.BenchmarkResultPayload) error {
 	debug.Log("Processing benchmark result from agent", map[string]interface{}{
@@ -931,6 +1244,7 @@ func (s *JobWebSocketIntegration) HandleBenchmarkResult(ctx context.Context, age
 		"speed":       result.Speed,
 		"success":     result.Success,
 	})
+	s.recordBenchmark(ctx, agentID, result)
 
 	if !result.Success {
 		debug.Log("Benchmark failed", map[string]interface{}{
@@ -953,6 +1267,12 @@ func (s *JobWebSocketIntegration) HandleBenchmarkResult(ctx context.Context, age
 		HashType:   result.HashType,
 		Speed:      result.Speed,
 	}
+	if len(result.DeviceSpeeds) > 0 {
+		benchmark.DeviceSpeeds = make(map[int]int64, len(result.DeviceSpeeds))
+		for _, device := range result.DeviceSpeeds {
+			benchmark.DeviceSpeeds[device.DeviceID] = device.Speed
+		}
+	}
 
 	err = s.benchmarkRepo.CreateOrUpdateAgentBenchmark(ctx, benchmark)
 	if err != nil {
@@ -1045,6 +1365,9 @@ func (s *JobWebSocketIntegration) HandleBenchmarkResult(ctx context.Context, age
 				debug.Error("Failed to update job keyspace info: %v", err)
 				return fmt.Errorf("failed to update job keyspace info: %w", err)
 			}
+		} else if jobExec.MaskIncrement != nil {
+			// The benchmark ran one mask length, not the total of all lengths
+			debug.Info("Agent %d benchmark ran the longest mask of increment job %s, not validating its total", agentID, jobExec.ID)
 		} else {
 			// Subsequent benchmark - validate consistency (should match job total)
 			diff := result.TotalEffectiveKeyspace - *jobExec.EffectiveKeyspace
@@ -1100,6 +1423,74 @@ func (s *JobWebSocketIntegration) HandleBenchmarkResult(ctx context.Context, age
 	return nil
 }
 
+// recordBenchmark adds a finished benchmark to the system event log, with
+// when it was requested so agent timelines can show the time it took
+func (s *JobWebSocketIntegration) recordBenchmark(ctx context.Context, agentID int, result *wsservice.BenchmarkResultPayload) {
+	s.benchmarkMutex.Lock()
+	requestedAt, requested := s.benchmarkRequests[agentID]
+	delete(s.benchmarkRequests, agentID)
+	s.benchmarkMutex.Unlock()
+
+	var event *models.SystemEvent
+	if result.Success {
+		event = models.NewAgentSystemEvent(models.SystemEventInfo, models.SystemEventCategoryBenchmark, models.SystemEventBenchmarkCompleted,
+			agentID, fmt.Sprintf("Agent %d benchmarked hash type %d in attack mode %d", agentID, result.HashType, result.AttackMode))
+	} else {
+		event = models.NewAgentSystemEvent(models.SystemEventWarning, models.SystemEventCategoryBenchmark, models.SystemEventBenchmarkFailed,
+			agentID, fmt.Sprintf("Agent %d failed to benchmark hash type %d in attack mode %d: %s", agentID, result.HashType, result.AttackMode, result.Error))
+	}
+	event.Details = map[string]interface{}{
+		"job_id":      result.JobExecutionID,
+		"hash_type":   result.HashType,
+		"attack_mode": result.AttackMode,
+		"speed":       result.Speed,
+	}
+	if requested {
+		event.Details["started_at"] = requestedAt.Format(time.RFC3339)
+	}
+	s.jobExecutionService.RecordSystemEvent(ctx, event)
+}
+
+// publishTaskCompleted announces a completed task to the event bus
+func (s *JobWebSocketIntegration) publishTaskCompleted(ctx context.Context, task *models.JobTask) {
+	err := events.Publish(ctx, s.db, events.TaskCompleted, events.TaskCompletedPayload{
+		TaskID:         task.ID,
+		JobExecutionID: task.JobExecutionID,
+		AgentID:        task.AgentID,
+	})
+	if err != nil {
+		debug.Error("Failed to publish task completed event for task %s: %v", task.ID, err)
+	}
+}
+
+// agentExtraParameters returns the agent's extra hashcat parameters with the
+// workload profile of its workload class, unless they set one themselves
+func agentExtraParameters(agent *models.Agent) string {
+	return hashcatargs.WithWorkloadProfile(agent.ExtraParameters, agent.HashcatWorkloadProfile())
+}
+
+// HandleCrackJournal reconciles cracks an agent journaled while disconnected.
+// Hashes that are already cracked, e.g. because the buffered progress message
+// carrying them was delivered too, are skipped, so replaying is idempotent.
+func (s *JobWebSocketIntegration) HandleCrackJournal(ctx context.Context, agentID int, entry *wsservice.CrackJournalPayload) error {
+	if len(entry.CrackedHashes) == 0 {
+		return nil
+	}
+
+	err := s.processCrackedHashes(ctx, entry.TaskID, entry.CrackedHashes)
+	if errors.Is(err, repository.ErrNotFound) {
+		// The task was deleted with its job, nothing left to reconcile
+		debug.Warning("Dropping crack journal entry %s of agent %d, task %s no longer exists", entry.ID, agentID, entry.TaskID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	debug.Info("Reconciled %d journaled cracks of agent %d for task %s", len(entry.CrackedHashes), agentID, entry.TaskID)
+	return nil
+}
+
 // processCrackedHashes processes cracked hashes from a job progress update
 func (s *JobWebSocketIntegration) processCrackedHashes(ctx context.Context, taskID uuid.UUID, crackedHashes []models.CrackedHash) error {
 	// Get task details
@@ -1127,7 +1518,10 @@ func (s *JobWebSocketIntegration) processCrackedHashes(ctx context.Context, task
 	// Process each cracked hash
 	for _, crackedEntry := range crackedHashes {
 		hashValue := crackedEntry.Hash
-		password := crackedEntry.Plain
+		// Agents send the exact bytes as hex, the plain may be in $HEX[...]
+		// notation or mangled by JSON encoding when it is not valid UTF-8
+		plainBytes := plaintext.Decode(crackedEntry.Plain, crackedEntry.HexPlain)
+		password, passwordRaw := plaintext.ForStorage(plainBytes)
 		crackPos := crackedEntry.CrackPos
 
 		// Find the hash in the database
@@ -1148,7 +1542,8 @@ func (s *JobWebSocketIntegration) processCrackedHashes(ctx context.Context, task
 		// This ensures that Administrator, Administrator1, Administrator2 all get marked as cracked
 		hashesUpdated := 0
 		for _, hash := range hashes {
-			// Check if hash is already cracked to prevent double counting
+			// Check if hash is already cracked to prevent double counting, this also
+			// drops cracks repeated in the overlap between neighbouring chunks
 			if hash.IsCracked {
 				debug.Log("Hash already cracked, skipping", map[string]interface{}{
 					"hash_id":     hash.ID,
@@ -1159,7 +1554,7 @@ func (s *JobWebSocketIntegration) processCrackedHashes(ctx context.Context, task
 			}
 
 			// Update crack status
-			err = s.hashRepo.UpdateCrackStatus(tx, hash.ID, password, crackedAt, nil)
+			err = s.hashRepo.UpdateCrackStatus(tx, hash.ID, password, passwordRaw, crackedAt, nil, &taskID)
 			if err != nil {
 				debug.Log("Failed to update crack status", map[string]interface{}{
 					"hash_id": hash.ID,
@@ -1213,10 +1608,10 @@ func (s *JobWebSocketIntegration) processCrackedHashes(ctx context.Context, task
 							debug.Info("Hashlist %d is excluded from potfile, skipping password staging", jobExecution.HashlistID)
 						} else {
 							// All checks passed (global enabled, client not excluded, hashlist not excluded) - stage the password
-							if err := s.potfileService.StagePassword(ctx, password, hashValue); err != nil {
+							if err := s.potfileService.StagePassword(ctx, plaintext.Hashcat(plainBytes), hashValue); err != nil {
 								debug.Warning("Failed to stage password for pot-file: %v", err)
 							} else {
-								debug.Info("Successfully staged password for pot-file: hash=%s", hashValue)
+								debug.Info("Successfully staged password for pot-file: hash=%s", debug.Hash(hashValue))
 							}
 						}
 					}
@@ -1251,6 +1646,18 @@ func (s *JobWebSocketIntegration) processCrackedHashes(ctx context.Context, task
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	if crackedCount > 0 {
+		err = events.Publish(ctx, s.db, events.HashCracked, events.HashCrackedPayload{
+			TaskID:         taskID,
+			JobExecutionID: task.JobExecutionID,
+			HashlistID:     jobExecution.HashlistID,
+			Count:          crackedCount,
+		})
+		if err != nil {
+			debug.Error("Failed to publish hash cracked event for task %s: %v", taskID, err)
+		}
+	}
+
 	// Update hashlist file to remove cracked hashes
 	// Convert CrackedHash array to string array for backward compatibility
 	var crackedHashStrings []string
@@ -1270,6 +1677,80 @@ func (s *JobWebSocketIntegration) processCrackedHashes(ctx context.Context, task
 	return nil
 }
 
+// recordGPUUsage accounts the time since the previous progress update to each
+// device working on the task. Agents that report no device metrics are
+// counted as a single device 0.
+func (s *JobWebSocketIntegration) recordGPUUsage(ctx context.Context, task *models.JobTask, agentID int, progress *models.JobProgress) {
+	devices := progress.DeviceMetrics
+	if len(devices) == 0 {
+		devices = []models.DeviceMetric{{DeviceID: 0}}
+	}
+
+	// Allow a few missed updates before a gap stops counting as work
+	reportInterval := 5
+	if val, err := s.jobExecutionService.GetSystemSetting(ctx, "progress_reporting_interval"); err == nil && val > 0 {
+		reportInterval = val
+	}
+	maxGap := time.Duration(3*reportInterval) * time.Second
+
+	usageRepo := repository.NewGPUUsageRepository(&db.DB{DB: s.db})
+	if err := usageRepo.RecordDeviceUsage(ctx, task, agentID, devices, maxGap); err != nil {
+		debug.Error("Failed to record GPU usage for task %s: %v", task.ID, err)
+	}
+}
+
+// recordTaskArtifact keeps the exit code, the last hashcat status and the end
+// of the output sent with a task's final update, trimmed to
+// task_artifact_output_kb
+func (s *JobWebSocketIntegration) recordTaskArtifact(ctx context.Context, task *models.JobTask, agentID int, progress *models.JobProgress) {
+	outputKB := 32
+	if val, err := s.jobExecutionService.GetSystemSetting(ctx, "task_artifact_output_kb"); err == nil && val >= 0 {
+		outputKB = val
+	}
+
+	artifact := &models.TaskArtifact{
+		TaskID:         task.ID,
+		JobExecutionID: task.JobExecutionID,
+		AgentID:        &agentID,
+		Status:         progress.Status,
+		ExitCode:       progress.ExitCode,
+		FinalStatus:    progress.FinalStatus,
+		OutputTail:     models.TailBytes(progress.OutputTail, outputKB*1024),
+	}
+	if progress.ErrorMessage != "" {
+		artifact.ErrorMessage = &progress.ErrorMessage
+	}
+	if len(artifact.FinalStatus) > 0 && !json.Valid(artifact.FinalStatus) {
+		artifact.FinalStatus = nil
+	}
+
+	artifactRepo := repository.NewTaskArtifactRepository(&db.DB{DB: s.db})
+	if err := artifactRepo.Create(ctx, artifact); err != nil {
+		debug.Error("Failed to record artifacts of task %s: %v", task.ID, err)
+	}
+}
+
+// streamProgress publishes a progress update of a task to the UI
+func (s *JobWebSocketIntegration) streamProgress(task *models.JobTask, agentID int, progress *models.JobProgress) {
+	if s.progressHub == nil {
+		return
+	}
+	status := progress.Status
+	if status == "" {
+		status = string(models.JobTaskStatusRunning)
+	}
+	s.progressHub.PublishTask(task.JobExecutionID, jobstream.TaskProgress{
+		TaskID:            task.ID,
+		AgentID:           &agentID,
+		Status:            status,
+		KeyspaceProcessed: progress.KeyspaceProcessed,
+		EffectiveProgress: progress.EffectiveProgress,
+		ProgressPercent:   progress.ProgressPercent,
+		HashRate:          progress.HashRate,
+		TimeRemaining:     progress.TimeRemaining,
+	}, progress.CrackedCount)
+}
+
 // GetTaskProgress returns the current progress for a task
 func (s *JobWebSocketIntegration) GetTaskProgress(taskID string) *models.JobProgress {
 	s.progressMutex.RLock()
@@ -1367,7 +1848,12 @@ func (s *JobWebSocketIntegration) RecoverTask(ctx context.Context, taskID string
 	task.Status = models.JobTaskStatusRunning
 	task.DetailedStatus = "running" // Ensure detailed_status matches the status for constraint
 	if keyspaceProcessed > 0 {
-		task.KeyspaceProcessed = keyspaceProcessed
+		// The agent counts from the dispatched start, which includes any chunk
+		// overlap and skips what was processed before the task's checkpoint
+		task.KeyspaceProcessed = keyspaceProcessed - task.ChunkOverlap + task.ResumeOffset
+		if task.KeyspaceProcessed < 0 {
+			task.KeyspaceProcessed = 0
+		}
 	}
 	
 	err = s.jobTaskRepo.Update(ctx, task)
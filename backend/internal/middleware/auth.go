@@ -145,11 +145,23 @@ func RequireAuth(database *db.DB) func(http.Handler) http.Handler {
 				return
 			}
 
+			// An outer RequireAuth has already switched this request into a support session
+			if r.Context().Value("support_session_id") != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			// Add user ID and role to request context
 			ctx := context.WithValue(r.Context(), "user_id", userID)
 			ctx = context.WithValue(ctx, "user_role", role) // Add role to context
 			r = r.WithContext(ctx)
 
+			// Admins in a support session act as the session's target user
+			var ok bool
+			if r, ok = applySupportSession(w, r, database, userID, role); !ok {
+				return
+			}
+
 			if isSSERequest {
 				debug.Info("[AUTH] SSE: Authentication successful for user: %s with role: %s", userID, role)
 				debug.Debug("[AUTH] SSE: Proceeding to SSE handler")
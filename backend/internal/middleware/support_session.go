@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// applySupportSession switches an authenticated admin request to the target
+// user of the support session named in the X-Support-Session header. Support
+// sessions are read-only and every request is written to the session's audit
+// trail. Admin routes always run as the admin, so the session can be managed
+// from inside it. Returns false if the request was refused and answered.
+func applySupportSession(w http.ResponseWriter, r *http.Request, database *db.DB, userID, role string) (*http.Request, bool) {
+	header := r.Header.Get(models.SupportSessionHeader)
+	if header == "" || strings.HasPrefix(r.URL.Path, "/api/admin/") {
+		return r, true
+	}
+	if role != "admin" {
+		debug.Warning("[AUTH] Non-admin user %s sent a support session header", userID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return r, false
+	}
+
+	sessionID, err := uuid.Parse(header)
+	if err != nil {
+		http.Error(w, "Invalid support session", http.StatusBadRequest)
+		return r, false
+	}
+	adminID, err := uuid.Parse(userID)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return r, false
+	}
+
+	repo := repository.NewSupportSessionRepository(database)
+	session, err := repo.GetActiveForAdmin(r.Context(), sessionID, adminID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			debug.Warning("[AUTH] Admin %s used support session %s which is not active", userID, sessionID)
+			http.Error(w, "Support session is not active", http.StatusForbidden)
+		} else {
+			debug.Error("[AUTH] Failed to get support session %s: %v", sessionID, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return r, false
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		if err := repo.LogRequest(r.Context(), session.ID, models.SupportSessionEventDenied, r.Method, r.URL.Path, "support sessions are read-only"); err != nil {
+			debug.Error("[AUTH] %v", err)
+		}
+		http.Error(w, "Support sessions are read-only", http.StatusForbidden)
+		return r, false
+	}
+
+	// A request that cannot be audited is not allowed through
+	if err := repo.LogRequest(r.Context(), session.ID, models.SupportSessionEventRequest, r.Method, r.URL.Path, r.URL.RawQuery); err != nil {
+		debug.Error("[AUTH] %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return r, false
+	}
+
+	debug.Debug("[AUTH] Admin %s acting as user %s in support session %s", userID, session.TargetUserID, session.ID)
+
+	ctx := context.WithValue(r.Context(), "user_id", session.TargetUserID.String())
+	ctx = context.WithValue(ctx, "user_role", session.TargetRole)
+	ctx = context.WithValue(ctx, "impersonator_id", userID)
+	ctx = context.WithValue(ctx, "support_session_id", session.ID.String())
+	return r.WithContext(ctx), true
+}
@@ -36,36 +36,41 @@ type AgentWithTask struct {
 
 // Agent represents a registered agent in the system
 type Agent struct {
-	ID                  int               `json:"id"`
-	Name                string            `json:"name"`
-	Status              string            `json:"status"`
-	LastError           sql.NullString    `json:"lastError"`
-	LastSeen            time.Time         `json:"lastSeen"`
-	LastHeartbeat       time.Time         `json:"lastHeartbeat"`
-	Version             string            `json:"version"`
-	Hardware            Hardware          `json:"hardware"`
-	OSInfo              json.RawMessage   `json:"os_info"`
-	CreatedByID         uuid.UUID         `json:"createdById"`
-	CreatedBy           *User             `json:"createdBy,omitempty"`
-	Teams               []Team            `json:"teams,omitempty"`
-	CreatedAt           time.Time         `json:"createdAt"`
-	UpdatedAt           time.Time         `json:"updatedAt"`
-	APIKey              sql.NullString    `json:"-"`
-	APIKeyCreatedAt     sql.NullTime      `json:"-"`
-	APIKeyLastUsed      sql.NullTime      `json:"-"`
-	Metadata            map[string]string `json:"metadata,omitempty"`
-	OwnerID             *uuid.UUID        `json:"ownerId,omitempty"`
-	ExtraParameters     string            `json:"extraParameters"`
-	IsEnabled           bool              `json:"isEnabled"`
-	ConsecutiveFailures int               `json:"consecutiveFailures"` // Track consecutive task failures
-	SchedulingEnabled   bool              `json:"schedulingEnabled"`
-	ScheduleTimezone    string            `json:"scheduleTimezone"`
-	SyncStatus          string            `json:"syncStatus"`
-	SyncCompletedAt     sql.NullTime      `json:"syncCompletedAt"`
-	SyncStartedAt       sql.NullTime      `json:"syncStartedAt"`
-	SyncError           sql.NullString    `json:"syncError"`
-	FilesToSync         int               `json:"filesToSync"`
-	FilesSynced         int               `json:"filesSynced"`
+	ID                  int                `json:"id"`
+	Name                string             `json:"name"`
+	Status              string             `json:"status"`
+	LastError           sql.NullString     `json:"lastError"`
+	LastSeen            time.Time          `json:"lastSeen"`
+	LastHeartbeat       time.Time          `json:"lastHeartbeat"`
+	Version             string             `json:"version"`
+	Hardware            Hardware           `json:"hardware"`
+	OSInfo              json.RawMessage    `json:"os_info"`
+	CreatedByID         uuid.UUID          `json:"createdById"`
+	CreatedBy           *User              `json:"createdBy,omitempty"`
+	Teams               []Team             `json:"teams,omitempty"`
+	CreatedAt           time.Time          `json:"createdAt"`
+	UpdatedAt           time.Time          `json:"updatedAt"`
+	APIKey              sql.NullString     `json:"-"`
+	APIKeyCreatedAt     sql.NullTime       `json:"-"`
+	APIKeyLastUsed      sql.NullTime       `json:"-"`
+	Metadata            map[string]string  `json:"metadata,omitempty"`
+	OwnerID             *uuid.UUID         `json:"ownerId,omitempty"`
+	ExtraParameters     string             `json:"extraParameters"`
+	IsEnabled           bool               `json:"isEnabled"`
+	ConsecutiveFailures int                `json:"consecutiveFailures"` // Track consecutive task failures
+	SchedulingEnabled   bool               `json:"schedulingEnabled"`
+	ScheduleTimezone    string             `json:"scheduleTimezone"`
+	SyncStatus          string             `json:"syncStatus"`
+	SyncCompletedAt     sql.NullTime       `json:"syncCompletedAt"`
+	SyncStartedAt       sql.NullTime       `json:"syncStartedAt"`
+	SyncError           sql.NullString     `json:"syncError"`
+	FilesToSync         int                `json:"filesToSync"`
+	FilesSynced         int                `json:"filesSynced"`
+	Labels              []string           `json:"labels"`
+	WorkloadClass       WorkloadClass      `json:"workloadClass"`
+	WorkloadProfile     *int               `json:"workloadProfile,omitempty"` // hashcat -w level, nil for the class default
+	ManagedConfig       AgentManagedConfig `json:"managedConfig"`             // Settings pushed to the agent over its WebSocket
+	Performance         *AgentPerformance  `json:"performance,omitempty"`     // Recent task speed against benchmarks, not stored
 }
 
 // Hardware represents the hardware configuration of an agent
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// BulkAgentAction is an operation applied to many agents at once
+type BulkAgentAction string
+
+const (
+	BulkAgentActionEnable             BulkAgentAction = "enable"
+	BulkAgentActionDisable            BulkAgentAction = "disable"
+	BulkAgentActionDrain              BulkAgentAction = "drain"
+	BulkAgentActionSetSchedule        BulkAgentAction = "set_schedule"
+	BulkAgentActionFileSync           BulkAgentAction = "file_sync"
+	BulkAgentActionFileAudit          BulkAgentAction = "file_audit"
+	BulkAgentActionForceCleanup       BulkAgentAction = "force_cleanup"
+	BulkAgentActionRotateCredentials  BulkAgentAction = "rotate_credentials"
+	BulkAgentActionSetExtraParameters BulkAgentAction = "set_extra_parameters"
+	BulkAgentActionAddLabels          BulkAgentAction = "add_labels"
+	BulkAgentActionRemoveLabels       BulkAgentAction = "remove_labels"
+	BulkAgentActionSetWorkload        BulkAgentAction = "set_workload"
+	BulkAgentActionSetConfig          BulkAgentAction = "set_config"
+)
+
+// Outcome of a bulk action on one agent
+const (
+	BulkAgentStatusOK       = "ok"
+	BulkAgentStatusDraining = "draining"
+	BulkAgentStatusFailed   = "failed"
+)
+
+// maxAgentLabelLength is the longest label an agent may carry
+const maxAgentLabelLength = 64
+
+var agentLabelPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:=-]*$`)
+
+// AgentSelector picks agents by ID and/or label. With both set an agent must
+// match both, with several labels it must carry all of them.
+type AgentSelector struct {
+	AgentIDs []int    `json:"agent_ids,omitempty"`
+	Labels   []string `json:"labels,omitempty"`
+}
+
+// Empty reports whether the selector matches no criteria at all
+func (s AgentSelector) Empty() bool {
+	return len(s.AgentIDs) == 0 && len(s.Labels) == 0
+}
+
+// Matches reports whether the agent is selected
+func (s AgentSelector) Matches(agent *Agent) bool {
+	if len(s.AgentIDs) > 0 {
+		found := false
+		for _, id := range s.AgentIDs {
+			if id == agent.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, label := range s.Labels {
+		if !agent.HasLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasLabel reports whether the agent carries the label
+func (a *Agent) HasLabel(label string) bool {
+	for _, l := range a.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// BulkAgentRequest is the body of POST /admin/agents/bulk. Only the fields of
+// the chosen action are used.
+type BulkAgentRequest struct {
+	AgentSelector
+	Action BulkAgentAction `json:"action"`
+
+	// set_schedule: replaces each agent's weekly schedule
+	Schedules         []AgentScheduleDTO `json:"schedules,omitempty"`
+	SchedulingEnabled *bool              `json:"scheduling_enabled,omitempty"`
+	Timezone          string             `json:"timezone,omitempty"`
+
+	// set_extra_parameters: an empty value clears them
+	ExtraParameters string `json:"extra_parameters,omitempty"`
+
+	// add_labels, remove_labels
+	ChangeLabels []string `json:"change_labels,omitempty"`
+
+	// set_workload: a nil profile uses the class default
+	WorkloadClass   WorkloadClass `json:"workload_class,omitempty"`
+	WorkloadProfile *int          `json:"workload_profile,omitempty"`
+
+	// set_config: replaces each agent's managed settings, unset fields use the defaults
+	Config *AgentManagedConfig `json:"config,omitempty"`
+}
+
+// BulkAgentResult is the outcome of a bulk action on one agent
+type BulkAgentResult struct {
+	AgentID int    `json:"agent_id"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// BulkAgentResponse summarizes a bulk action
+type BulkAgentResponse struct {
+	Action    BulkAgentAction   `json:"action"`
+	Matched   int               `json:"matched"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BulkAgentResult `json:"results"`
+}
+
+// Add records the outcome for one agent
+func (r *BulkAgentResponse) Add(result BulkAgentResult) {
+	if result.Status == BulkAgentStatusFailed {
+		r.Failed++
+	} else {
+		r.Succeeded++
+	}
+	r.Results = append(r.Results, result)
+}
+
+// NormalizeAgentLabels lowercases and trims labels, drops duplicates and
+// blanks, sorts them and rejects labels with unsupported characters
+func NormalizeAgentLabels(labels []string) ([]string, error) {
+	seen := make(map[string]bool, len(labels))
+	normalized := []string{}
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" || seen[label] {
+			continue
+		}
+		if len(label) > maxAgentLabelLength || !agentLabelPattern.MatchString(label) {
+			return nil, fmt.Errorf("invalid label %q: use up to %d lowercase letters, digits, '.', '_', ':', '=' or '-'", label, maxAgentLabelLength)
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeAgentLabels(t *testing.T) {
+	labels, err := NormalizeAgentLabels([]string{" GPU-4090 ", "site:lab", "", "gpu-4090", "cloud", "team=red1"})
+	if err != nil {
+		t.Fatalf("NormalizeAgentLabels() error = %v", err)
+	}
+	if want := []string{"cloud", "gpu-4090", "site:lab", "team=red1"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("NormalizeAgentLabels() = %v, want %v", labels, want)
+	}
+
+	for _, invalid := range []string{"two words", "-leading", "semi;colon", "=red"} {
+		if _, err := NormalizeAgentLabels([]string{invalid}); err == nil {
+			t.Errorf("NormalizeAgentLabels(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestAgentSelectorMatches(t *testing.T) {
+	agent := &Agent{ID: 7, Labels: []string{"cloud", "gpu-4090"}}
+
+	tests := []struct {
+		name     string
+		selector AgentSelector
+		want     bool
+	}{
+		{"by id", AgentSelector{AgentIDs: []int{3, 7}}, true},
+		{"other id", AgentSelector{AgentIDs: []int{3}}, false},
+		{"all labels", AgentSelector{Labels: []string{"cloud", "gpu-4090"}}, true},
+		{"missing label", AgentSelector{Labels: []string{"cloud", "on-prem"}}, false},
+		{"id and label", AgentSelector{AgentIDs: []int{7}, Labels: []string{"cloud"}}, true},
+		{"id but not label", AgentSelector{AgentIDs: []int{7}, Labels: []string{"on-prem"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.selector.Matches(agent); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
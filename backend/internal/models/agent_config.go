@@ -0,0 +1,87 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// AgentManagedConfig holds agent-side settings managed from the backend and
+// pushed to the agent over its WebSocket, so a fleet can be retuned without
+// editing each machine's .env. A nil field leaves the setting to the global
+// default or the agent itself.
+type AgentManagedConfig struct {
+	HeartbeatIntervalSeconds *int `json:"heartbeat_interval_seconds,omitempty"`
+	MaxConcurrentDownloads   *int `json:"max_concurrent_downloads,omitempty"`
+	FileRetentionDays        *int `json:"file_retention_days,omitempty"` // Days before unused hashlists and rule chunks are removed from the data directory
+}
+
+// Limits of the managed agent settings
+const (
+	maxAgentHeartbeatIntervalSeconds = 300
+	maxAgentConcurrentDownloads      = 20
+	maxAgentFileRetentionDays        = 365
+)
+
+// Validate checks each set value is within its limits
+func (c AgentManagedConfig) Validate() error {
+	checks := []struct {
+		name  string
+		value *int
+		max   int
+	}{
+		{"heartbeat_interval_seconds", c.HeartbeatIntervalSeconds, maxAgentHeartbeatIntervalSeconds},
+		{"max_concurrent_downloads", c.MaxConcurrentDownloads, maxAgentConcurrentDownloads},
+		{"file_retention_days", c.FileRetentionDays, maxAgentFileRetentionDays},
+	}
+	for _, check := range checks {
+		if check.value != nil && (*check.value < 1 || *check.value > check.max) {
+			return fmt.Errorf("%s must be between 1 and %d", check.name, check.max)
+		}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer
+func (c AgentManagedConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner
+func (c *AgentManagedConfig) Scan(value interface{}) error {
+	*c = AgentManagedConfig{}
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("unsupported type for AgentManagedConfig: %T", value)
+	}
+}
+
+// AgentConfigUpdate is the agent_config section of the config_update message:
+// the settings the agent runs with. A zero value keeps the agent's own setting.
+type AgentConfigUpdate struct {
+	HeartbeatIntervalSeconds int `json:"heartbeat_interval_seconds,omitempty"`
+	MaxConcurrentDownloads   int `json:"max_concurrent_downloads,omitempty"`
+	FileRetentionDays        int `json:"file_retention_days,omitempty"`
+}
+
+// Effective resolves the settings to push to the agent, its own values taking
+// precedence over the global download settings
+func (c AgentManagedConfig) Effective(downloads AgentDownloadSettings) AgentConfigUpdate {
+	update := AgentConfigUpdate{MaxConcurrentDownloads: downloads.MaxConcurrentDownloads}
+	if c.HeartbeatIntervalSeconds != nil {
+		update.HeartbeatIntervalSeconds = *c.HeartbeatIntervalSeconds
+	}
+	if c.MaxConcurrentDownloads != nil {
+		update.MaxConcurrentDownloads = *c.MaxConcurrentDownloads
+	}
+	if c.FileRetentionDays != nil {
+		update.FileRetentionDays = *c.FileRetentionDays
+	}
+	return update
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentManagedConfigValidate(t *testing.T) {
+	value := func(v int) *int { return &v }
+
+	assert.NoError(t, AgentManagedConfig{}.Validate())
+	assert.NoError(t, AgentManagedConfig{HeartbeatIntervalSeconds: value(30), MaxConcurrentDownloads: value(1), FileRetentionDays: value(365)}.Validate())
+
+	assert.Error(t, AgentManagedConfig{HeartbeatIntervalSeconds: value(0)}.Validate())
+	assert.Error(t, AgentManagedConfig{MaxConcurrentDownloads: value(21)}.Validate())
+	assert.Error(t, AgentManagedConfig{FileRetentionDays: value(-1)}.Validate())
+}
+
+func TestAgentManagedConfigEffective(t *testing.T) {
+	value := func(v int) *int { return &v }
+	downloads := AgentDownloadSettings{MaxConcurrentDownloads: 3}
+
+	assert.Equal(t, AgentConfigUpdate{MaxConcurrentDownloads: 3}, AgentManagedConfig{}.Effective(downloads))
+	assert.Equal(t,
+		AgentConfigUpdate{HeartbeatIntervalSeconds: 15, MaxConcurrentDownloads: 8, FileRetentionDays: 7},
+		AgentManagedConfig{HeartbeatIntervalSeconds: value(15), MaxConcurrentDownloads: value(8), FileRetentionDays: value(7)}.Effective(downloads))
+}
+
+func TestAgentManagedConfigScan(t *testing.T) {
+	var config AgentManagedConfig
+	require.NoError(t, config.Scan([]byte(`{"file_retention_days": 10}`)))
+	require.NotNil(t, config.FileRetentionDays)
+	assert.Equal(t, 10, *config.FileRetentionDays)
+	assert.Nil(t, config.MaxConcurrentDownloads)
+
+	require.NoError(t, config.Scan(nil))
+	assert.Equal(t, AgentManagedConfig{}, config)
+}
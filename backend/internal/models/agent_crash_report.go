@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of crash an agent reports
+const (
+	CrashKindAgentPanic   = "agent_panic"   // A panic in an agent goroutine
+	CrashKindAgentFatal   = "agent_fatal"   // A runtime crash found when the agent started again
+	CrashKindHashcatCrash = "hashcat_crash" // Hashcat was killed by a fault signal
+)
+
+// AgentCrashReport is a diagnostic bundle an agent sent after a crash,
+// waiting for an administrator to review it
+type AgentCrashReport struct {
+	ID         int64           `json:"id"`
+	AgentID    int             `json:"agent_id"`
+	AgentName  string          `json:"agent_name"`
+	ReportID   string          `json:"report_id"`
+	Kind       string          `json:"kind"`
+	Summary    string          `json:"summary"`
+	TaskID     *uuid.UUID      `json:"task_id,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Bundle     json.RawMessage `json:"bundle,omitempty"` // Only included when a single report is fetched
+	ReviewedAt *time.Time      `json:"reviewed_at,omitempty"`
+	ReviewedBy *uuid.UUID      `json:"reviewed_by,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// CrashReportBundle is the part of a bundle the backend reads, the rest is
+// kept as sent
+type CrashReportBundle struct {
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	Summary    string    `json:"summary"`
+	OccurredAt time.Time `json:"occurred_at"`
+	TaskID     string    `json:"task_id,omitempty"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Agent credential rotation states
+const (
+	AgentCredentialRotationSent      = "sent"
+	AgentCredentialRotationConfirmed = "confirmed"
+	AgentCredentialRotationFailed    = "failed"
+)
+
+// AgentCredentialRotation is the status of replacing a connected agent's API
+// key and client certificate without it reconnecting. The new key works as
+// soon as it is sent; once the agent confirms it stored it, the old key keeps
+// working until PreviousKeyExpiresAt.
+type AgentCredentialRotation struct {
+	RotationID           string     `json:"rotation_id"`
+	AgentID              int        `json:"agent_id"`
+	Status               string     `json:"status"`
+	Certificate          bool       `json:"certificate"` // A new client certificate was sent along
+	StartedAt            time.Time  `json:"started_at"`
+	CompletedAt          *time.Time `json:"completed_at,omitempty"`
+	PreviousKeyExpiresAt *time.Time `json:"previous_key_expires_at,omitempty"`
+	Error                string     `json:"error,omitempty"`
+
+	apiKey string // Sent to the agent, never reported
+}
+
+// NewAgentCredentialRotation starts tracking the rotation of an agent's
+// credentials to apiKey
+func NewAgentCredentialRotation(agentID int, apiKey string) *AgentCredentialRotation {
+	now := time.Now()
+	return &AgentCredentialRotation{
+		RotationID: fmt.Sprintf("rotation-%d-%d", agentID, now.UnixNano()),
+		AgentID:    agentID,
+		Status:     AgentCredentialRotationSent,
+		StartedAt:  now,
+		apiKey:     apiKey,
+	}
+}
+
+// APIKey returns the API key the rotation sent
+func (r *AgentCredentialRotation) APIKey() string {
+	return r.apiKey
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentCredentialRotationHidesKey(t *testing.T) {
+	rotation := NewAgentCredentialRotation(7, "0123456789abcdef")
+	assert.Equal(t, "0123456789abcdef", rotation.APIKey())
+	assert.Equal(t, AgentCredentialRotationSent, rotation.Status)
+	assert.Contains(t, rotation.RotationID, "rotation-7-")
+
+	// Rotations are reported to administrators, the key never is
+	raw, err := json.Marshal(rotation)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "0123456789abcdef")
+
+	// Copies handed out by the WebSocket handler keep the key
+	copied := *rotation
+	assert.Equal(t, rotation.APIKey(), copied.APIKey())
+}
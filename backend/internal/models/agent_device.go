@@ -6,14 +6,16 @@ import (
 
 // AgentDevice represents a compute device on an agent
 type AgentDevice struct {
-	ID         int       `json:"id" db:"id"`
-	AgentID    int       `json:"agent_id" db:"agent_id"`
-	DeviceID   int       `json:"device_id" db:"device_id"`
-	DeviceName string    `json:"device_name" db:"device_name"`
-	DeviceType string    `json:"device_type" db:"device_type"` // "GPU" or "CPU"
-	Enabled    bool      `json:"enabled" db:"enabled"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	ID            int       `json:"id" db:"id"`
+	AgentID       int       `json:"agent_id" db:"agent_id"`
+	DeviceID      int       `json:"device_id" db:"device_id"`
+	DeviceName    string    `json:"device_name" db:"device_name"`
+	DeviceType    string    `json:"device_type" db:"device_type"` // "GPU" or "CPU"
+	Enabled       bool      `json:"enabled" db:"enabled"`
+	MemoryTotalMB *int64    `json:"memory_total_mb,omitempty" db:"memory_total_mb"` // Reported by hashcat at detection
+	MemoryFreeMB  *int64    `json:"memory_free_mb,omitempty" db:"memory_free_mb"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // DeviceDetectionResult represents the result from agent device detection
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Agent file audit statuses
+const (
+	AgentFileAuditScanning  = "scanning"
+	AgentFileAuditCompleted = "completed"
+	AgentFileAuditFailed    = "failed"
+)
+
+// AgentFileAudit is the report of an integrity audit of an agent's data
+// directory. The agent rehashes every cached wordlist, rule and binary, and
+// the backend corrects whatever differs from its own files.
+type AgentFileAudit struct {
+	RequestID    string               `json:"request_id"`
+	AgentID      int                  `json:"agent_id"`
+	Status       string               `json:"status"`
+	StartedAt    time.Time            `json:"started_at"`
+	CompletedAt  *time.Time           `json:"completed_at,omitempty"`
+	FilesChecked int                  `json:"files_checked"`
+	Mismatched   []AgentFileAuditItem `json:"mismatched"` // On the agent with the wrong content
+	Missing      []AgentFileAuditItem `json:"missing"`    // Known to the backend, absent on the agent
+	Extra        []AgentFileAuditItem `json:"extra"`      // On the agent, unknown to the backend
+	Downloads    int                  `json:"downloads"`  // Files the agent was told to download or move
+	Deletions    int                  `json:"deletions"`  // Extra files the agent was told to delete
+	Error        string               `json:"error,omitempty"`
+}
+
+// AgentFileAuditItem is one file found to differ during an audit
+type AgentFileAuditItem struct {
+	FileType    string `json:"file_type"`
+	Name        string `json:"name"`
+	ExpectedMD5 string `json:"expected_md5,omitempty"`
+	ActualMD5   string `json:"actual_md5,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
+
+// Clean reports whether the audit found the agent's files intact
+func (a *AgentFileAudit) Clean() bool {
+	return len(a.Mismatched) == 0 && len(a.Missing) == 0 && len(a.Extra) == 0
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FlappingReconnectThreshold is the number of reconnects within the stored
+// heartbeat history at which an agent is reported as flapping.
+const FlappingReconnectThreshold = 3
+
+// AgentHeartbeat is a single utilization sample reported by an agent heartbeat
+type AgentHeartbeat struct {
+	ID             int64      `json:"id"`
+	AgentID        int        `json:"agentId"`
+	ReceivedAt     time.Time  `json:"receivedAt"`
+	CurrentTaskID  *uuid.UUID `json:"currentTaskId,omitempty"`
+	GPUUtilization *float64   `json:"gpuUtilization,omitempty"` // Average across enabled devices (0-100)
+	QueueDepth     int        `json:"queueDepth"`               // Messages waiting to be sent by the agent
+	ReconnectCount int        `json:"reconnectCount"`           // Reconnects since the agent process started
+}
+
+// AgentHeartbeatHistory is the recent heartbeat history of an agent along with
+// connection stability indicators derived from it
+type AgentHeartbeatHistory struct {
+	AgentID    int              `json:"agentId"`
+	Heartbeats []AgentHeartbeat `json:"heartbeats"` // Oldest first
+	Reconnects int              `json:"reconnects"` // Reconnects observed within the history
+	Flapping   bool             `json:"flapping"`
+}
@@ -0,0 +1,47 @@
+package models
+
+// AgentPerformance compares the speed an agent's recent completed tasks ran at
+// with its benchmarks. A score of 1 means the tasks ran at benchmark speed.
+type AgentPerformance struct {
+	Score           float64             `json:"score"` // Mean ratio of observed to benchmark speed
+	Tasks           int                 `json:"tasks"` // Completed tasks the score averages over
+	Underperforming bool                `json:"underperforming"`
+	Devices         []DevicePerformance `json:"devices,omitempty"`
+}
+
+// DevicePerformance compares a device's hash rate during recent tasks with
+// its speed in the agent's benchmarks
+type DevicePerformance struct {
+	DeviceID        int     `json:"deviceId"`
+	DeviceName      string  `json:"deviceName"`
+	Score           float64 `json:"score"`
+	Tasks           int     `json:"tasks"`
+	Underperforming bool    `json:"underperforming"`
+}
+
+// AgentPerformanceSettings controls performance scoring
+type AgentPerformanceSettings struct {
+	WindowTasks      int // Recent completed tasks per agent the score averages over
+	MinTasks         int // Tasks needed before flagging
+	ThresholdPercent int // Flag scores below this percentage of benchmark speed
+}
+
+// GetDefaultAgentPerformanceSettings returns the performance scoring defaults
+func GetDefaultAgentPerformanceSettings() AgentPerformanceSettings {
+	return AgentPerformanceSettings{
+		WindowTasks:      20,
+		MinTasks:         5,
+		ThresholdPercent: 80,
+	}
+}
+
+// Flag marks the agent and its devices as underperforming when enough tasks
+// ran persistently below the threshold
+func (s AgentPerformanceSettings) Flag(p *AgentPerformance) {
+	threshold := float64(s.ThresholdPercent) / 100
+	p.Underperforming = p.Tasks >= s.MinTasks && p.Score < threshold
+	for i := range p.Devices {
+		d := &p.Devices[i]
+		d.Underperforming = d.Tasks >= s.MinTasks && d.Score < threshold
+	}
+}
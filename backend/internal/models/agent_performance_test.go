@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentPerformanceSettingsFlag(t *testing.T) {
+	settings := GetDefaultAgentPerformanceSettings()
+
+	p := &AgentPerformance{
+		Score: 0.6,
+		Tasks: 8,
+		Devices: []DevicePerformance{
+			{DeviceID: 1, Score: 0.98, Tasks: 8},
+			{DeviceID: 2, Score: 0.25, Tasks: 8},
+			{DeviceID: 3, Score: 0.25, Tasks: 2},
+		},
+	}
+	settings.Flag(p)
+	assert.True(t, p.Underperforming)
+	assert.False(t, p.Devices[0].Underperforming)
+	assert.True(t, p.Devices[1].Underperforming)
+	// Too few tasks to tell a slow device from a single slow task
+	assert.False(t, p.Devices[2].Underperforming)
+
+	// At the threshold is not below it
+	p = &AgentPerformance{Score: 0.8, Tasks: 20}
+	settings.Flag(p)
+	assert.False(t, p.Underperforming)
+
+	p = &AgentPerformance{Score: 0.1, Tasks: settings.MinTasks - 1}
+	settings.Flag(p)
+	assert.False(t, p.Underperforming)
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// AgentIDList is a list of agent IDs stored as an INTEGER[] column
+type AgentIDList []int
+
+// Value implements the driver.Valuer interface
+func (l AgentIDList) Value() (driver.Value, error) {
+	ids := make(pq.Int64Array, len(l))
+	for i, id := range l {
+		ids[i] = int64(id)
+	}
+	return ids.Value()
+}
+
+// Scan implements the sql.Scanner interface
+func (l *AgentIDList) Scan(value interface{}) error {
+	var ids pq.Int64Array
+	if err := ids.Scan(value); err != nil {
+		return err
+	}
+	*l = make(AgentIDList, len(ids))
+	for i, id := range ids {
+		(*l)[i] = int(id)
+	}
+	return nil
+}
+
+// contains reports whether the list holds the agent
+func (l AgentIDList) contains(agentID int) bool {
+	for _, id := range l {
+		if id == agentID {
+			return true
+		}
+	}
+	return false
+}
+
+// String lists the IDs for messages, e.g. "3, 5"
+func (l AgentIDList) String() string {
+	ids := make([]string, len(l))
+	for i, id := range l {
+		ids[i] = strconv.Itoa(id)
+	}
+	return strings.Join(ids, ", ")
+}
+
+// AgentPlacement restricts which agents may run a job. A job pinned to agents
+// only runs on those, a job never runs on the agents it excludes. Both empty
+// lets every agent run it.
+type AgentPlacement struct {
+	PinnedAgentIDs   AgentIDList `json:"pinned_agent_ids"`
+	ExcludedAgentIDs AgentIDList `json:"excluded_agent_ids"`
+}
+
+// Validate checks that no agent is both pinned and excluded
+func (p AgentPlacement) Validate() error {
+	for _, id := range p.PinnedAgentIDs {
+		if id <= 0 {
+			return fmt.Errorf("invalid agent ID %d", id)
+		}
+		if p.ExcludedAgentIDs.contains(id) {
+			return fmt.Errorf("agent %d cannot be both pinned and excluded", id)
+		}
+	}
+	for _, id := range p.ExcludedAgentIDs {
+		if id <= 0 {
+			return fmt.Errorf("invalid agent ID %d", id)
+		}
+	}
+	return nil
+}
+
+// Restricted reports whether the placement limits the agents at all
+func (p AgentPlacement) Restricted() bool {
+	return len(p.PinnedAgentIDs) > 0 || len(p.ExcludedAgentIDs) > 0
+}
+
+// Allows reports whether the agent may run the job
+func (p AgentPlacement) Allows(agentID int) bool {
+	if p.ExcludedAgentIDs.contains(agentID) {
+		return false
+	}
+	return len(p.PinnedAgentIDs) == 0 || p.PinnedAgentIDs.contains(agentID)
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentPlacementAllows(t *testing.T) {
+	unrestricted := AgentPlacement{}
+	assert.False(t, unrestricted.Restricted())
+	assert.True(t, unrestricted.Allows(1))
+
+	pinned := AgentPlacement{PinnedAgentIDs: AgentIDList{2, 3}}
+	assert.True(t, pinned.Restricted())
+	assert.True(t, pinned.Allows(2))
+	assert.False(t, pinned.Allows(1))
+
+	excluded := AgentPlacement{ExcludedAgentIDs: AgentIDList{4}}
+	assert.True(t, excluded.Allows(1))
+	assert.False(t, excluded.Allows(4))
+}
+
+func TestAgentPlacementValidate(t *testing.T) {
+	assert.NoError(t, AgentPlacement{PinnedAgentIDs: AgentIDList{1}, ExcludedAgentIDs: AgentIDList{2}}.Validate())
+	assert.Error(t, AgentPlacement{PinnedAgentIDs: AgentIDList{1}, ExcludedAgentIDs: AgentIDList{1}}.Validate())
+	assert.Error(t, AgentPlacement{PinnedAgentIDs: AgentIDList{0}}.Validate())
+	assert.Error(t, AgentPlacement{ExcludedAgentIDs: AgentIDList{-3}}.Validate())
+}
+
+func TestAgentIDListScanValue(t *testing.T) {
+	value, err := AgentIDList{3, 5}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "{3,5}", value)
+
+	var ids AgentIDList
+	require.NoError(t, ids.Scan([]byte("{7,11}")))
+	assert.Equal(t, AgentIDList{7, 11}, ids)
+	assert.Equal(t, "7, 11", ids.String())
+
+	require.NoError(t, ids.Scan([]byte("{}")))
+	assert.Empty(t, ids)
+}
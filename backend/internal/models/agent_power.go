@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// AgentPowerWindow represents a daily low-power window for an agent, during
+// which it only accepts jobs up to a maximum priority
+type AgentPowerWindow struct {
+	ID          int       `json:"id"`
+	AgentID     int       `json:"agentId"`
+	DayOfWeek   int       `json:"dayOfWeek"`   // 0-6 (Sunday-Saturday)
+	StartTime   TimeOnly  `json:"startTime"`   // HH:MM in UTC
+	EndTime     TimeOnly  `json:"endTime"`     // HH:MM in UTC
+	Timezone    string    `json:"timezone"`    // Original timezone for reference
+	MaxPriority int       `json:"maxPriority"` // Highest job priority accepted during the window
+	IsActive    bool      `json:"isActive"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// AgentPowerWindowDTO represents the data transfer object for power window updates from frontend
+type AgentPowerWindowDTO struct {
+	DayOfWeek    int    `json:"dayOfWeek"`
+	StartTimeUTC string `json:"startTimeUTC"` // HH:MM in UTC
+	EndTimeUTC   string `json:"endTimeUTC"`   // HH:MM in UTC
+	Timezone     string `json:"timezone"`     // User's timezone for reference
+	MaxPriority  int    `json:"maxPriority"`
+	IsActive     bool   `json:"isActive"`
+}
+
+// ValidateWindow checks if the power window is valid
+func (w *AgentPowerWindow) ValidateWindow() error {
+	if w.DayOfWeek < 0 || w.DayOfWeek > 6 {
+		return ErrInvalidInput
+	}
+
+	// Times must be different
+	if w.StartTime.Equal(w.EndTime) {
+		return ErrInvalidInput
+	}
+
+	if w.MaxPriority < 0 {
+		return ErrInvalidInput
+	}
+
+	return nil
+}
+
+// AgentPowerBudget represents a daily energy budget for an agent. Energy use is
+// estimated from the time the agent spent running tasks at its configured draw.
+type AgentPowerBudget struct {
+	AgentID        int       `json:"agentId"`
+	PowerDrawWatts int       `json:"powerDrawWatts"` // Estimated draw while running a task
+	DailyBudgetWh  int       `json:"dailyBudgetWh"`  // Resets at midnight UTC
+	UsedTodayWh    float64   `json:"usedTodayWh"`    // Computed, not stored
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// IsExhausted reports whether the agent has used up its budget for today
+func (b *AgentPowerBudget) IsExhausted() bool {
+	return b.UsedTodayWh >= float64(b.DailyBudgetWh)
+}
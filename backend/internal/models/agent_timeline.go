@@ -0,0 +1,238 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Timeline segment kinds. Gaps between tasks are labelled with the activity
+// that filled them, offline before benchmark before sync, and idle otherwise.
+const (
+	TimelineSegmentTask      = "task"
+	TimelineSegmentOffline   = "offline"
+	TimelineSegmentBenchmark = "benchmark"
+	TimelineSegmentSync      = "sync"
+	TimelineSegmentIdle      = "idle"
+)
+
+// timelineGapPriority orders the activities that can fill a gap between tasks
+var timelineGapPriority = map[string]int{
+	TimelineSegmentSync:      1,
+	TimelineSegmentBenchmark: 2,
+	TimelineSegmentOffline:   3,
+}
+
+// TimelineAgent is an agent shown on a timeline
+type TimelineAgent struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
+}
+
+// TimelineTaskRun is the time a task ran on an agent. End is nil while the
+// task is still running.
+type TimelineTaskRun struct {
+	AgentID int
+	TaskID  uuid.UUID
+	JobID   uuid.UUID
+	JobName string
+	Status  string
+	Start   time.Time
+	End     *time.Time
+}
+
+// TimelineActivity is a period an agent spent on something other than a task
+type TimelineActivity struct {
+	AgentID int
+	Kind    string
+	Start   time.Time
+	End     time.Time
+}
+
+// TimelineSegment is one bar of an agent's timeline. Task segments carry the
+// task and its job.
+type TimelineSegment struct {
+	Kind    string     `json:"kind"`
+	Start   time.Time  `json:"start"`
+	End     time.Time  `json:"end"`
+	TaskID  *uuid.UUID `json:"task_id,omitempty"`
+	JobID   *uuid.UUID `json:"job_id,omitempty"`
+	JobName string     `json:"job_name,omitempty"`
+	Status  string     `json:"status,omitempty"`
+}
+
+// AgentTimeline is the timeline of one agent. Seconds totals the time spent
+// per segment kind; Utilization is the percentage of the agent's time online
+// spent on tasks.
+type AgentTimeline struct {
+	AgentID     int                `json:"agent_id"`
+	AgentName   string             `json:"agent_name"`
+	Segments    []TimelineSegment  `json:"segments"`
+	Seconds     map[string]float64 `json:"seconds"`
+	Utilization float64            `json:"utilization"`
+}
+
+// Timeline is a Gantt chart of what agents did between From and To
+type Timeline struct {
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	Agents      []AgentTimeline    `json:"agents"`
+	Seconds     map[string]float64 `json:"seconds"`
+	Utilization float64            `json:"utilization"`
+}
+
+// BuildTimeline lays out the task runs and activities of the agents between
+// from and to. Tasks are shown as they ran, and the gaps between them are
+// labelled with the activity covering them. Time before an agent was created
+// counts as offline.
+func BuildTimeline(from, to time.Time, agents []TimelineAgent, runs []TimelineTaskRun, activities []TimelineActivity) *Timeline {
+	timeline := &Timeline{
+		From:    from,
+		To:      to,
+		Agents:  make([]AgentTimeline, 0, len(agents)),
+		Seconds: make(map[string]float64),
+	}
+
+	runsByAgent := make(map[int][]TimelineTaskRun)
+	for _, run := range runs {
+		runsByAgent[run.AgentID] = append(runsByAgent[run.AgentID], run)
+	}
+	activitiesByAgent := make(map[int][]TimelineActivity)
+	for _, activity := range activities {
+		activitiesByAgent[activity.AgentID] = append(activitiesByAgent[activity.AgentID], activity)
+	}
+
+	for _, agent := range agents {
+		agentActivities := activitiesByAgent[agent.ID]
+		if agent.CreatedAt.After(from) {
+			agentActivities = append(agentActivities, TimelineActivity{AgentID: agent.ID, Kind: TimelineSegmentOffline, Start: from, End: agent.CreatedAt})
+		}
+		agentTimeline := buildAgentTimeline(from, to, agent, runsByAgent[agent.ID], agentActivities)
+		for kind, seconds := range agentTimeline.Seconds {
+			timeline.Seconds[kind] += seconds
+		}
+		timeline.Agents = append(timeline.Agents, agentTimeline)
+	}
+	timeline.Utilization = timelineUtilization(timeline.Seconds)
+	return timeline
+}
+
+func buildAgentTimeline(from, to time.Time, agent TimelineAgent, runs []TimelineTaskRun, activities []TimelineActivity) AgentTimeline {
+	agentTimeline := AgentTimeline{
+		AgentID:   agent.ID,
+		AgentName: agent.Name,
+		Segments:  []TimelineSegment{},
+		Seconds:   make(map[string]float64),
+	}
+
+	// Task segments, clipped to the window
+	for _, run := range runs {
+		start, end := run.Start, to
+		if run.End != nil {
+			end = *run.End
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+		taskID, jobID := run.TaskID, run.JobID
+		agentTimeline.Segments = append(agentTimeline.Segments, TimelineSegment{
+			Kind:    TimelineSegmentTask,
+			Start:   start,
+			End:     end,
+			TaskID:  &taskID,
+			JobID:   &jobID,
+			JobName: run.JobName,
+			Status:  run.Status,
+		})
+	}
+	sort.Slice(agentTimeline.Segments, func(i, j int) bool {
+		return agentTimeline.Segments[i].Start.Before(agentTimeline.Segments[j].Start)
+	})
+
+	// Label the time not covered by any task run
+	var gaps []TimelineSegment
+	cursor := from
+	for _, segment := range agentTimeline.Segments {
+		if segment.Start.After(cursor) {
+			gaps = append(gaps, labelGap(cursor, segment.Start, activities)...)
+		}
+		busyFrom := segment.Start
+		if cursor.After(busyFrom) {
+			busyFrom = cursor
+		}
+		if segment.End.After(busyFrom) {
+			agentTimeline.Seconds[TimelineSegmentTask] += segment.End.Sub(busyFrom).Seconds()
+			cursor = segment.End
+		}
+	}
+	if to.After(cursor) {
+		gaps = append(gaps, labelGap(cursor, to, activities)...)
+	}
+	for _, gap := range gaps {
+		agentTimeline.Seconds[gap.Kind] += gap.End.Sub(gap.Start).Seconds()
+	}
+
+	agentTimeline.Segments = append(agentTimeline.Segments, gaps...)
+	sort.SliceStable(agentTimeline.Segments, func(i, j int) bool {
+		return agentTimeline.Segments[i].Start.Before(agentTimeline.Segments[j].Start)
+	})
+	agentTimeline.Utilization = timelineUtilization(agentTimeline.Seconds)
+	return agentTimeline
+}
+
+// labelGap splits the gap between start and end wherever the activity
+// covering it changes
+func labelGap(start, end time.Time, activities []TimelineActivity) []TimelineSegment {
+	bounds := []time.Time{start, end}
+	for _, activity := range activities {
+		for _, bound := range []time.Time{activity.Start, activity.End} {
+			if bound.After(start) && bound.Before(end) {
+				bounds = append(bounds, bound)
+			}
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+
+	var segments []TimelineSegment
+	for i := 1; i < len(bounds); i++ {
+		from, to := bounds[i-1], bounds[i]
+		if !to.After(from) {
+			continue
+		}
+		kind := TimelineSegmentIdle
+		for _, activity := range activities {
+			if !activity.Start.After(from) && !activity.End.Before(to) &&
+				timelineGapPriority[activity.Kind] > timelineGapPriority[kind] {
+				kind = activity.Kind
+			}
+		}
+		if last := len(segments) - 1; last >= 0 && segments[last].Kind == kind {
+			segments[last].End = to
+			continue
+		}
+		segments = append(segments, TimelineSegment{Kind: kind, Start: from, End: to})
+	}
+	return segments
+}
+
+// timelineUtilization returns the percentage of the time online spent on tasks
+func timelineUtilization(seconds map[string]float64) float64 {
+	online := 0.0
+	for kind, s := range seconds {
+		if kind != TimelineSegmentOffline {
+			online += s
+		}
+	}
+	if online == 0 {
+		return 0
+	}
+	return seconds[TimelineSegmentTask] / online * 100
+}
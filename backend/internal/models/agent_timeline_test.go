@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return from.Add(time.Duration(minutes) * time.Minute) }
+	end := func(minutes int) *time.Time { e := at(minutes); return &e }
+	jobID := uuid.New()
+
+	agents := []TimelineAgent{{ID: 1, Name: "rig-01", CreatedAt: at(-60)}}
+	runs := []TimelineTaskRun{
+		// Started before the window, clipped to it
+		{AgentID: 1, TaskID: uuid.New(), JobID: jobID, JobName: "ntlm", Status: "completed", Start: at(-10), End: end(20)},
+		{AgentID: 1, TaskID: uuid.New(), JobID: jobID, JobName: "ntlm", Status: "completed", Start: at(40), End: end(60)},
+		// Still running, ends with the window
+		{AgentID: 1, TaskID: uuid.New(), JobID: jobID, JobName: "ntlm", Status: "running", Start: at(90)},
+	}
+	activities := []TimelineActivity{
+		{AgentID: 1, Kind: TimelineSegmentSync, Start: at(20), End: at(30)},
+		// Offline wins over the sync it overlaps
+		{AgentID: 1, Kind: TimelineSegmentSync, Start: at(60), End: at(75)},
+		{AgentID: 1, Kind: TimelineSegmentOffline, Start: at(70), End: at(80)},
+		{AgentID: 1, Kind: TimelineSegmentBenchmark, Start: at(80), End: at(90)},
+	}
+
+	timeline := BuildTimeline(from, at(120), agents, runs, activities)
+	require.Len(t, timeline.Agents, 1)
+	agent := timeline.Agents[0]
+
+	var kinds []string
+	for _, segment := range agent.Segments {
+		kinds = append(kinds, segment.Kind)
+	}
+	assert.Equal(t, []string{
+		TimelineSegmentTask, TimelineSegmentSync, TimelineSegmentIdle, TimelineSegmentTask,
+		TimelineSegmentSync, TimelineSegmentOffline, TimelineSegmentBenchmark, TimelineSegmentTask,
+	}, kinds)
+	assert.Equal(t, from, agent.Segments[0].Start)
+	assert.Equal(t, at(120), agent.Segments[7].End)
+	require.NotNil(t, agent.Segments[0].JobID)
+	assert.Equal(t, jobID, *agent.Segments[0].JobID)
+
+	assert.Equal(t, float64(70*60), agent.Seconds[TimelineSegmentTask])
+	assert.Equal(t, float64(20*60), agent.Seconds[TimelineSegmentSync])
+	assert.Equal(t, float64(10*60), agent.Seconds[TimelineSegmentOffline])
+	assert.Equal(t, float64(10*60), agent.Seconds[TimelineSegmentBenchmark])
+	assert.Equal(t, float64(10*60), agent.Seconds[TimelineSegmentIdle])
+	// 70 of the 110 minutes online
+	assert.InDelta(t, 63.64, agent.Utilization, 0.01)
+	assert.Equal(t, agent.Utilization, timeline.Utilization)
+}
+
+func TestBuildTimelineAgentCreatedInWindow(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	agents := []TimelineAgent{{ID: 2, Name: "new", CreatedAt: from.Add(time.Hour)}}
+
+	timeline := BuildTimeline(from, from.Add(2*time.Hour), agents, nil, nil)
+	require.Len(t, timeline.Agents, 1)
+	segments := timeline.Agents[0].Segments
+	require.Len(t, segments, 2)
+	assert.Equal(t, TimelineSegmentOffline, segments[0].Kind)
+	assert.Equal(t, TimelineSegmentIdle, segments[1].Kind)
+	assert.Zero(t, timeline.Agents[0].Utilization)
+}
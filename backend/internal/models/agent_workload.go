@@ -0,0 +1,60 @@
+package models
+
+import "fmt"
+
+// WorkloadClass describes how an agent's machine is used, which sets the
+// hashcat workload profile (-w) its tasks run with
+type WorkloadClass string
+
+const (
+	// WorkloadClassDefault leaves the workload profile to hashcat and the agent's extra parameters
+	WorkloadClassDefault WorkloadClass = "default"
+	// WorkloadClassShared is a workstation someone works on. It runs at -w 1 or 2
+	// and only inside its schedule, so never during its user's business hours.
+	WorkloadClassShared WorkloadClass = "shared"
+	// WorkloadClassDedicated is a rig that only cracks. It runs at -w 3 or 4.
+	WorkloadClassDedicated WorkloadClass = "dedicated"
+)
+
+// profileRange returns the lowest, highest and default workload profile of the class
+func (c WorkloadClass) profileRange() (min, max, def int) {
+	switch c {
+	case WorkloadClassShared:
+		return 1, 2, 2
+	case WorkloadClassDedicated:
+		return 3, 4, 3
+	}
+	return 0, 0, 0
+}
+
+// ValidateWorkload checks a workload class and an optional profile within its range
+func ValidateWorkload(class WorkloadClass, profile *int) error {
+	switch class {
+	case WorkloadClassDefault:
+		if profile != nil {
+			return fmt.Errorf("workload_profile needs the shared or dedicated workload class")
+		}
+		return nil
+	case WorkloadClassShared, WorkloadClassDedicated:
+	default:
+		return fmt.Errorf("workload_class must be default, shared or dedicated")
+	}
+	min, max, _ := class.profileRange()
+	if profile != nil && (*profile < min || *profile > max) {
+		return fmt.Errorf("workload_profile must be between %d and %d for %s agents", min, max, class)
+	}
+	return nil
+}
+
+// HashcatWorkloadProfile returns the -w level the agent's tasks run with, or 0
+// when its class leaves it to hashcat
+func (a *Agent) HashcatWorkloadProfile() int {
+	min, max, def := a.WorkloadClass.profileRange()
+	if def == 0 {
+		return 0
+	}
+	if a.WorkloadProfile != nil && *a.WorkloadProfile >= min && *a.WorkloadProfile <= max {
+		return *a.WorkloadProfile
+	}
+	return def
+}
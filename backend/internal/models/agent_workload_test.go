@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWorkload(t *testing.T) {
+	level := func(l int) *int { return &l }
+
+	assert.NoError(t, ValidateWorkload(WorkloadClassDefault, nil))
+	assert.NoError(t, ValidateWorkload(WorkloadClassShared, nil))
+	assert.NoError(t, ValidateWorkload(WorkloadClassShared, level(1)))
+	assert.NoError(t, ValidateWorkload(WorkloadClassDedicated, level(4)))
+
+	assert.Error(t, ValidateWorkload(WorkloadClassDefault, level(3)))
+	assert.Error(t, ValidateWorkload(WorkloadClassShared, level(3)))
+	assert.Error(t, ValidateWorkload(WorkloadClassDedicated, level(2)))
+	assert.Error(t, ValidateWorkload("laptop", nil))
+	assert.Error(t, ValidateWorkload("", nil))
+}
+
+func TestHashcatWorkloadProfile(t *testing.T) {
+	level := func(l int) *int { return &l }
+
+	assert.Equal(t, 0, (&Agent{WorkloadClass: WorkloadClassDefault}).HashcatWorkloadProfile())
+	assert.Equal(t, 0, (&Agent{}).HashcatWorkloadProfile())
+	assert.Equal(t, 2, (&Agent{WorkloadClass: WorkloadClassShared}).HashcatWorkloadProfile())
+	assert.Equal(t, 1, (&Agent{WorkloadClass: WorkloadClassShared, WorkloadProfile: level(1)}).HashcatWorkloadProfile())
+	assert.Equal(t, 3, (&Agent{WorkloadClass: WorkloadClassDedicated}).HashcatWorkloadProfile())
+	assert.Equal(t, 4, (&Agent{WorkloadClass: WorkloadClassDedicated, WorkloadProfile: level(4)}).HashcatWorkloadProfile())
+	assert.Equal(t, 3, (&Agent{WorkloadClass: WorkloadClassDedicated, WorkloadProfile: level(1)}).HashcatWorkloadProfile(), "out of range profile falls back to the class default")
+}
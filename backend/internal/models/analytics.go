@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,57 +12,61 @@ import (
 
 // AnalyticsReport represents a password analytics report for a client engagement
 type AnalyticsReport struct {
-	ID             uuid.UUID      `json:"id"`
-	ClientID       uuid.UUID      `json:"client_id"`
-	UserID         uuid.UUID      `json:"user_id"`
-	StartDate      time.Time      `json:"start_date"`
-	EndDate        time.Time      `json:"end_date"`
-	Status         string         `json:"status"` // queued, processing, completed, failed
-	AnalyticsData  *AnalyticsData `json:"analytics_data"`
-	TotalHashlists int            `json:"total_hashlists"`
-	TotalHashes    int            `json:"total_hashes"`
-	TotalCracked   int            `json:"total_cracked"`
-	QueuePosition  *int           `json:"queue_position"`
-	CustomPatterns pq.StringArray `json:"custom_patterns" db:"custom_patterns"`
-	CreatedAt      time.Time      `json:"created_at"`
-	StartedAt      *time.Time     `json:"started_at"`
-	CompletedAt    *time.Time     `json:"completed_at"`
-	ErrorMessage   *string        `json:"error_message"`
+	ID             uuid.UUID       `json:"id"`
+	ClientID       uuid.UUID       `json:"client_id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	StartDate      time.Time       `json:"start_date"`
+	EndDate        time.Time       `json:"end_date"`
+	Status         string          `json:"status"` // queued, processing, completed, failed
+	AnalyticsData  *AnalyticsData  `json:"analytics_data"`
+	TotalHashlists int             `json:"total_hashlists"`
+	TotalHashes    int             `json:"total_hashes"`
+	TotalCracked   int             `json:"total_cracked"`
+	QueuePosition  *int            `json:"queue_position"`
+	CustomPatterns pq.StringArray  `json:"custom_patterns" db:"custom_patterns"`
+	PasswordPolicy *PasswordPolicy `json:"password_policy,omitempty" db:"password_policy"`
+	CreatedAt      time.Time       `json:"created_at"`
+	StartedAt      *time.Time      `json:"started_at"`
+	CompletedAt    *time.Time      `json:"completed_at"`
+	ErrorMessage   *string         `json:"error_message"`
 }
 
 // AnalyticsData contains all calculated analytics metrics
 type AnalyticsData struct {
-	Overview            OverviewStats       `json:"overview"`
-	LengthDistribution  LengthStats         `json:"length_distribution"`
-	ComplexityAnalysis  ComplexityStats     `json:"complexity_analysis"`
-	PositionalAnalysis  PositionalStats     `json:"positional_analysis"`
-	PatternDetection    PatternStats        `json:"pattern_detection"`
-	UsernameCorrelation UsernameStats       `json:"username_correlation"`
-	PasswordReuse       ReuseStats          `json:"password_reuse"`
-	TemporalPatterns    TemporalStats       `json:"temporal_patterns"`
-	MaskAnalysis        MaskStats           `json:"mask_analysis"`
-	CustomPatterns      CustomPatternStats  `json:"custom_patterns"`
-	StrengthMetrics     StrengthStats       `json:"strength_metrics"`
-	TopPasswords        []TopPassword       `json:"top_passwords"`
-	Recommendations     []Recommendation    `json:"recommendations"`
-	DomainAnalytics     []DomainAnalytics   `json:"domain_analytics"`
+	Overview            OverviewStats            `json:"overview"`
+	LengthDistribution  LengthStats              `json:"length_distribution"`
+	ComplexityAnalysis  ComplexityStats          `json:"complexity_analysis"`
+	PositionalAnalysis  PositionalStats          `json:"positional_analysis"`
+	PatternDetection    PatternStats             `json:"pattern_detection"`
+	UsernameCorrelation UsernameStats            `json:"username_correlation"`
+	PasswordReuse       ReuseStats               `json:"password_reuse"`
+	TemporalPatterns    TemporalStats            `json:"temporal_patterns"`
+	MaskAnalysis        MaskStats                `json:"mask_analysis"`
+	CustomPatterns      CustomPatternStats       `json:"custom_patterns"`
+	StrengthMetrics     StrengthStats            `json:"strength_metrics"`
+	TopPasswords        []TopPassword            `json:"top_passwords"`
+	Recommendations     []Recommendation         `json:"recommendations"`
+	DomainAnalytics     []DomainAnalytics        `json:"domain_analytics"`
+	PolicyCompliance    *PolicyComplianceStats   `json:"policy_compliance,omitempty"`   // Only when the report has a password policy
+	AccountCorrelation  *AccountCorrelationStats `json:"account_correlation,omitempty"` // Only when the report covers several hashlists
 }
 
 // DomainAnalytics contains complete analytics for a specific domain
 type DomainAnalytics struct {
-	Domain              string             `json:"domain"`
-	Overview            OverviewStats      `json:"overview"`
-	LengthDistribution  LengthStats        `json:"length_distribution"`
-	ComplexityAnalysis  ComplexityStats    `json:"complexity_analysis"`
-	PositionalAnalysis  PositionalStats    `json:"positional_analysis"`
-	PatternDetection    PatternStats       `json:"pattern_detection"`
-	UsernameCorrelation UsernameStats      `json:"username_correlation"`
-	PasswordReuse       ReuseStats         `json:"password_reuse"`
-	TemporalPatterns    TemporalStats      `json:"temporal_patterns"`
-	MaskAnalysis        MaskStats          `json:"mask_analysis"`
-	CustomPatterns      CustomPatternStats `json:"custom_patterns"`
-	StrengthMetrics     StrengthStats      `json:"strength_metrics"`
-	TopPasswords        []TopPassword      `json:"top_passwords"`
+	Domain              string                 `json:"domain"`
+	Overview            OverviewStats          `json:"overview"`
+	LengthDistribution  LengthStats            `json:"length_distribution"`
+	ComplexityAnalysis  ComplexityStats        `json:"complexity_analysis"`
+	PositionalAnalysis  PositionalStats        `json:"positional_analysis"`
+	PatternDetection    PatternStats           `json:"pattern_detection"`
+	UsernameCorrelation UsernameStats          `json:"username_correlation"`
+	PasswordReuse       ReuseStats             `json:"password_reuse"`
+	TemporalPatterns    TemporalStats          `json:"temporal_patterns"`
+	MaskAnalysis        MaskStats              `json:"mask_analysis"`
+	CustomPatterns      CustomPatternStats     `json:"custom_patterns"`
+	StrengthMetrics     StrengthStats          `json:"strength_metrics"`
+	TopPasswords        []TopPassword          `json:"top_passwords"`
+	PolicyCompliance    *PolicyComplianceStats `json:"policy_compliance,omitempty"`
 }
 
 // Scan implements sql.Scanner for AnalyticsData
@@ -179,6 +184,43 @@ type UserOccurrence struct {
 	HashlistCount int    `json:"hashlist_count"`  // How many different hashlists this user-password combo appears in
 }
 
+// AccountCorrelationStats links the accounts of a client's hashlists, such as
+// the same user in a workstation, a domain and an application dump. Accounts
+// are matched by username, ignoring case and domain.
+type AccountCorrelationStats struct {
+	SharedAccounts int                  `json:"shared_accounts"` // Usernames found in 2+ hashlists
+	ReusedAccounts int                  `json:"reused_accounts"` // Shared usernames with the same password in 2+ hashlists
+	Accounts       []AccountCorrelation `json:"accounts"`        // Shared usernames, those reusing a password first
+	ReuseChains    []ReuseChain         `json:"reuse_chains"`    // Passwords cracked in 2+ hashlists, widest first
+}
+
+// AccountCorrelation is a username found in several hashlists
+type AccountCorrelation struct {
+	Username       string              `json:"username"`
+	Occurrences    []AccountOccurrence `json:"occurrences"`
+	HashlistCount  int                 `json:"hashlist_count"`
+	PasswordReused bool                `json:"password_reused"` // The same password was cracked in 2+ of its hashlists
+}
+
+// AccountOccurrence is an account in one hashlist
+type AccountOccurrence struct {
+	HashlistID   int64  `json:"hashlist_id"`
+	HashlistName string `json:"hashlist_name"`
+	Username     string `json:"username"`
+	Domain       string `json:"domain"`
+	Cracked      bool   `json:"cracked"`
+	Password     string `json:"password,omitempty"`
+}
+
+// ReuseChain is a password cracked in several hashlists with the accounts
+// using it, the path an attacker follows from one system to the next
+type ReuseChain struct {
+	Password      string              `json:"password"`
+	Accounts      []AccountOccurrence `json:"accounts"`
+	HashlistCount int                 `json:"hashlist_count"`
+	AccountCount  int                 `json:"account_count"` // Distinct usernames
+}
+
 // TemporalStats contains temporal pattern analysis
 type TemporalStats struct {
 	ContainsYear   CategoryCount            `json:"contains_year"`
@@ -247,6 +289,58 @@ type TopPassword struct {
 	Percentage float64 `json:"percentage"`
 }
 
+// PasswordPolicy describes a client's password policy that cracked passwords
+// are checked against. Zero values disable the corresponding rule.
+type PasswordPolicy struct {
+	MinLength               int      `json:"min_length"`
+	MinCharacterClasses     int      `json:"min_character_classes"` // Of lowercase, uppercase, numbers and special
+	RequireLowercase        bool     `json:"require_lowercase"`
+	RequireUppercase        bool     `json:"require_uppercase"`
+	RequireNumbers          bool     `json:"require_numbers"`
+	RequireSpecial          bool     `json:"require_special"`
+	DisallowDictionaryWords bool     `json:"disallow_dictionary_words"`
+	DictionaryWords         []string `json:"dictionary_words,omitempty"` // Banned in addition to the built-in common words
+	DisallowUsername        bool     `json:"disallow_username"`
+}
+
+// Validate checks that the policy's limits are in range
+func (p *PasswordPolicy) Validate() error {
+	if p.MinLength < 0 || p.MinLength > 256 {
+		return fmt.Errorf("min_length must be between 0 and 256")
+	}
+	if p.MinCharacterClasses < 0 || p.MinCharacterClasses > 4 {
+		return fmt.Errorf("min_character_classes must be between 0 and 4")
+	}
+	return nil
+}
+
+// Scan implements sql.Scanner for PasswordPolicy
+func (p *PasswordPolicy) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for password policy", value)
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// Value implements driver.Valuer for PasswordPolicy
+func (p PasswordPolicy) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// PolicyComplianceStats contains how many cracked passwords comply with the
+// report's password policy. Compliant passwords were cracked even though the
+// policy allowed them, so the policy alone does not prevent weak passwords.
+type PolicyComplianceStats struct {
+	Policy       PasswordPolicy           `json:"policy"`
+	Compliant    CategoryCount            `json:"compliant"`
+	NonCompliant CategoryCount            `json:"non_compliant"`
+	Violations   map[string]CategoryCount `json:"violations"` // "too_short": {count, percentage}
+}
+
 // Recommendation represents an auto-generated recommendation
 type Recommendation struct {
 	Severity   string  `json:"severity"`   // CRITICAL, HIGH, MEDIUM, INFO
@@ -306,8 +400,9 @@ func (ct CharacterTypes) GetCharsetSize() int {
 
 // CreateAnalyticsReportRequest represents the request to create a new analytics report
 type CreateAnalyticsReportRequest struct {
-	ClientID       uuid.UUID `json:"client_id" binding:"required"`
-	StartDate      time.Time `json:"start_date" binding:"required"`
-	EndDate        time.Time `json:"end_date" binding:"required"`
-	CustomPatterns []string  `json:"custom_patterns"`
+	ClientID       uuid.UUID       `json:"client_id" binding:"required"`
+	StartDate      time.Time       `json:"start_date" binding:"required"`
+	EndDate        time.Time       `json:"end_date" binding:"required"`
+	CustomPatterns []string        `json:"custom_patterns"`
+	PasswordPolicy *PasswordPolicy `json:"password_policy"`
 }
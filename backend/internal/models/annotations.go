@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits on the notes and tags of jobs and hashlists
+const (
+	MaxNotesLength = 10000
+	MaxTags        = 32
+	MaxTagLength   = 64
+)
+
+// Annotations are the free-form notes and tags of a job or hashlist
+type Annotations struct {
+	Notes string   `json:"notes"`
+	Tags  []string `json:"tags"`
+}
+
+// Normalize trims the notes and normalizes the tags, rejecting values over
+// the limits
+func (a *Annotations) Normalize() error {
+	a.Notes = strings.TrimSpace(a.Notes)
+	if utf8.RuneCountInString(a.Notes) > MaxNotesLength {
+		return fmt.Errorf("notes exceed %d characters", MaxNotesLength)
+	}
+	tags, err := NormalizeTags(a.Tags)
+	if err != nil {
+		return err
+	}
+	a.Tags = tags
+	return nil
+}
+
+// NormalizeTags lowercases and trims tags, collapses inner whitespace, drops
+// duplicates and blanks and sorts them. Commas are rejected since tag filters
+// are given as a comma separated list.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf("tag %q exceeds %d characters", tag, MaxTagLength)
+		}
+		if strings.ContainsRune(tag, ',') || strings.IndexFunc(tag, unicode.IsControl) >= 0 {
+			return nil, fmt.Errorf("tag %q contains a comma or control character", tag)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", MaxTags)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// Kinds of search results
+const (
+	SearchResultJob      = "job"
+	SearchResultHashlist = "hashlist"
+)
+
+// SearchResult is a job or hashlist matching a search over names, notes and tags
+type SearchResult struct {
+	Type       string    `json:"type"`
+	ID         string    `json:"id"` // Job UUID or hashlist ID
+	Name       string    `json:"name"`
+	Notes      string    `json:"notes"`
+	Tags       []string  `json:"tags"`
+	Status     string    `json:"status"`
+	HashlistID int64     `json:"hashlist_id"` // The job's hashlist, or the hashlist itself
+	CreatedAt  time.Time `json:"created_at"`
+	Rank       float64   `json:"rank"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Domain  Admin ", "q3-2026", "", "domain admin", "ad"})
+	if err != nil {
+		t.Fatalf("NormalizeTags() error = %v", err)
+	}
+	if want := []string{"ad", "domain admin", "q3-2026"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("NormalizeTags() = %v, want %v", tags, want)
+	}
+
+	invalid := [][]string{
+		{"a,b"},
+		{"nul\x00byte"},
+		{strings.Repeat("x", MaxTagLength+1)},
+	}
+	for _, tags := range invalid {
+		if _, err := NormalizeTags(tags); err == nil {
+			t.Errorf("NormalizeTags(%q) should fail", tags)
+		}
+	}
+
+	tooMany := make([]string, MaxTags+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("t", i+1)
+	}
+	if _, err := NormalizeTags(tooMany); err == nil {
+		t.Errorf("NormalizeTags() with %d tags should fail", len(tooMany))
+	}
+}
+
+func TestAnnotationsNormalize(t *testing.T) {
+	a := Annotations{Notes: "  cracked the DA account  ", Tags: []string{"DA"}}
+	if err := a.Normalize(); err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	if a.Notes != "cracked the DA account" || !reflect.DeepEqual(a.Tags, []string{"da"}) {
+		t.Errorf("Normalize() = %+v", a)
+	}
+
+	long := Annotations{Notes: strings.Repeat("n", MaxNotesLength+1)}
+	if err := long.Normalize(); err == nil {
+		t.Error("Normalize() should reject notes over the limit")
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HashlistAttackCoverage records one distinct attack that has been run against
+// a hashlist. Runs of the same mode, hash type, wordlists, rules, mask and
+// additional arguments are folded into a single entry.
+type HashlistAttackCoverage struct {
+	ID                  int64      `json:"id" db:"id"`
+	HashlistID          int64      `json:"hashlist_id" db:"hashlist_id"`
+	AttackMode          AttackMode `json:"attack_mode" db:"attack_mode"`
+	HashType            int        `json:"hash_type" db:"hash_type"`
+	WordlistIDs         IDArray    `json:"wordlist_ids" db:"wordlist_ids"`
+	WordlistNames       []string   `json:"wordlist_names"`
+	RuleIDs             IDArray    `json:"rule_ids" db:"rule_ids"`
+	RuleNames           []string   `json:"rule_names"`
+	Mask                string     `json:"mask,omitempty" db:"mask"`
+	AdditionalArgs      string     `json:"additional_args,omitempty" db:"additional_args"`
+	RunCount            int        `json:"run_count" db:"run_count"`
+	CompletedCount      int        `json:"completed_count" db:"completed_count"`
+	BestProgressPercent float64    `json:"best_progress_percent" db:"best_progress_percent"`
+	Keyspace            *int64     `json:"keyspace" db:"keyspace"`
+	CrackCount          int        `json:"crack_count" db:"crack_count"`
+	LastJobExecutionID  *uuid.UUID `json:"last_job_execution_id" db:"last_job_execution_id"`
+	LastStatus          string     `json:"last_status" db:"last_status"`
+	FirstRunAt          time.Time  `json:"first_run_at" db:"first_run_at"`
+	LastRunAt           time.Time  `json:"last_run_at" db:"last_run_at"`
+}
+
+// FullyCovered reports whether at least one run of this attack completed its
+// entire keyspace.
+func (c HashlistAttackCoverage) FullyCovered() bool {
+	return c.CompletedCount > 0
+}
+
+// HashlistCoverageSummary aggregates the coverage entries of a hashlist.
+type HashlistCoverageSummary struct {
+	HashlistID       int64 `json:"hashlist_id"`
+	DistinctAttacks  int   `json:"distinct_attacks"`
+	CompletedAttacks int   `json:"completed_attacks"`
+	TotalRuns        int   `json:"total_runs"`
+	TotalCracks      int   `json:"total_cracks"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "fmt"
+
+// Limits of a per-job benchmark duration
+const (
+	MinBenchmarkDurationSeconds = 10
+	MaxBenchmarkDurationSeconds = 600
+
+	// defaultSpeedTestSeconds is how long a speed test runs when its time limit allows
+	defaultSpeedTestSeconds = 30
+)
+
+// BenchmarkOverride is a job's choice to skip or time-box the benchmarks run
+// before its tasks
+type BenchmarkOverride struct {
+	Skip            bool `json:"skip_benchmark"`
+	DurationSeconds *int `json:"benchmark_duration_seconds"` // Nil uses the speedtest_timeout_seconds setting
+}
+
+// Validate checks that a custom duration lies within the allowed limits
+func (o BenchmarkOverride) Validate() error {
+	if o.DurationSeconds != nil && (*o.DurationSeconds < MinBenchmarkDurationSeconds || *o.DurationSeconds > MaxBenchmarkDurationSeconds) {
+		return fmt.Errorf("benchmark duration must be between %d and %d seconds", MinBenchmarkDurationSeconds, MaxBenchmarkDurationSeconds)
+	}
+	return nil
+}
+
+// SpeedTestDurations returns how long a benchmark's speed test runs and how
+// long the agent may take for it in total. A custom duration is the time
+// limit; the speed test gets half of it, up to the usual 30 seconds, leaving
+// room for hashcat to start.
+func (o BenchmarkOverride) SpeedTestDurations(defaultTimeout int) (testSeconds, timeoutSeconds int) {
+	if o.DurationSeconds == nil {
+		return defaultSpeedTestSeconds, defaultTimeout
+	}
+	timeoutSeconds = *o.DurationSeconds
+	testSeconds = timeoutSeconds / 2
+	if testSeconds > defaultSpeedTestSeconds {
+		testSeconds = defaultSpeedTestSeconds
+	}
+	return testSeconds, timeoutSeconds
+}
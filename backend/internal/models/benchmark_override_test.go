@@ -0,0 +1,52 @@
+package models
+
+import "testing"
+
+func TestBenchmarkOverrideValidate(t *testing.T) {
+	duration := func(v int) *int { return &v }
+
+	tests := []struct {
+		name     string
+		override BenchmarkOverride
+		wantErr  bool
+	}{
+		{"no override", BenchmarkOverride{}, false},
+		{"skip", BenchmarkOverride{Skip: true}, false},
+		{"minimum", BenchmarkOverride{DurationSeconds: duration(10)}, false},
+		{"maximum", BenchmarkOverride{DurationSeconds: duration(600)}, false},
+		{"too short", BenchmarkOverride{DurationSeconds: duration(9)}, true},
+		{"too long", BenchmarkOverride{DurationSeconds: duration(601)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.override.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBenchmarkOverrideSpeedTestDurations(t *testing.T) {
+	duration := func(v int) *int { return &v }
+
+	tests := []struct {
+		name        string
+		override    BenchmarkOverride
+		wantTest    int
+		wantTimeout int
+	}{
+		{"system setting", BenchmarkOverride{}, 30, 180},
+		{"short limit halves the test", BenchmarkOverride{DurationSeconds: duration(20)}, 10, 20},
+		{"long limit keeps the usual test", BenchmarkOverride{DurationSeconds: duration(300)}, 30, 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test, timeout := tt.override.SpeedTestDurations(180)
+			if test != tt.wantTest || timeout != tt.wantTimeout {
+				t.Errorf("SpeedTestDurations() = %d, %d, want %d, %d", test, timeout, tt.wantTest, tt.wantTimeout)
+			}
+		})
+	}
+}
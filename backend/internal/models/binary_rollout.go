@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Binary rollout status constants
+const (
+	BinaryRolloutCanary     = "canary"
+	BinaryRolloutPromoted   = "promoted"
+	BinaryRolloutRolledBack = "rolled_back"
+)
+
+// BinaryRollout stages a new binary version: while it is in canary status,
+// tasks of jobs using the previous version run the new one on agents carrying
+// the canary label. After the observation period it is promoted to the
+// default for the whole fleet, or rolled back as soon as the canaries fail
+// or slow down more than allowed.
+type BinaryRollout struct {
+	ID                     int                   `json:"id"`
+	BinaryVersionID        int                   `json:"binary_version_id"`
+	PreviousVersionID      int                   `json:"previous_version_id"`
+	CanaryLabel            string                `json:"canary_label"`
+	ObservationHours       int                   `json:"observation_hours"`
+	MinTasks               int                   `json:"min_tasks"`                 // Finished canary tasks needed before deciding
+	MaxFailureRateIncrease float64               `json:"max_failure_rate_increase"` // Percentage points over the previous version's failure rate
+	MaxSpeedRegression     float64               `json:"max_speed_regression"`      // Percent slower than the previous version
+	Status                 string                `json:"status"`
+	Metrics                *BinaryRolloutMetrics `json:"metrics,omitempty"` // As of the last evaluation
+	DecisionReason         string                `json:"decision_reason,omitempty"`
+	DecidedAutomatically   bool                  `json:"decided_automatically"`
+	CreatedBy              *uuid.UUID            `json:"created_by,omitempty"`
+	StartedAt              time.Time             `json:"started_at"`
+	DecidedAt              *time.Time            `json:"decided_at,omitempty"`
+	BinaryVersionName      string                `json:"binary_version_name,omitempty"`
+	PreviousVersionName    string                `json:"previous_version_name,omitempty"`
+	CanaryAgents           int                   `json:"canary_agents"` // Agents carrying the canary label, not stored
+}
+
+// BinaryRolloutMetrics compares the tasks canary agents ran with the new
+// version against tasks run with the previous version since the rollout began
+type BinaryRolloutMetrics struct {
+	CanaryTasks      int       `json:"canary_tasks"` // Finished tasks run with the new version
+	CanaryFailures   int       `json:"canary_failures"`
+	BaselineTasks    int       `json:"baseline_tasks"` // Finished tasks run with the previous version
+	BaselineFailures int       `json:"baseline_failures"`
+	SpeedProfiles    int       `json:"speed_profiles"` // Agent, attack mode and hash type combinations run with both versions
+	SpeedRatio       float64   `json:"speed_ratio"`    // Mean ratio of new to previous version task speed, 0 without profiles
+	EvaluatedAt      time.Time `json:"evaluated_at"`
+}
+
+// CanaryFailureRate returns the percentage of canary tasks that failed
+func (m BinaryRolloutMetrics) CanaryFailureRate() float64 {
+	return failureRate(m.CanaryFailures, m.CanaryTasks)
+}
+
+// BaselineFailureRate returns the percentage of previous version tasks that failed
+func (m BinaryRolloutMetrics) BaselineFailureRate() float64 {
+	return failureRate(m.BaselineFailures, m.BaselineTasks)
+}
+
+func failureRate(failures, tasks int) float64 {
+	if tasks == 0 {
+		return 0
+	}
+	return float64(failures) * 100 / float64(tasks)
+}
+
+// ObservationEnds returns when the rollout has observed its canaries long enough
+func (r *BinaryRollout) ObservationEnds() time.Time {
+	return r.StartedAt.Add(time.Duration(r.ObservationHours) * time.Hour)
+}
+
+// Decide returns the status the rollout moves to given its latest metrics,
+// with the reason. A regression rolls back as soon as enough canary tasks
+// finished, promotion waits for the end of the observation period. The
+// status stays canary, with an empty reason while observing, when there is
+// nothing to decide yet.
+func (r *BinaryRollout) Decide(m BinaryRolloutMetrics, now time.Time) (string, string) {
+	enoughTasks := m.CanaryTasks >= r.MinTasks
+	if enoughTasks {
+		canaryRate, baselineRate := m.CanaryFailureRate(), m.BaselineFailureRate()
+		if canaryRate-baselineRate > r.MaxFailureRateIncrease {
+			return BinaryRolloutRolledBack, fmt.Sprintf("canary failure rate %.1f%% exceeds the previous version's %.1f%% by more than %.1f points",
+				canaryRate, baselineRate, r.MaxFailureRateIncrease)
+		}
+		if m.SpeedProfiles > 0 {
+			if regression := (1 - m.SpeedRatio) * 100; regression > r.MaxSpeedRegression {
+				return BinaryRolloutRolledBack, fmt.Sprintf("canaries ran %.1f%% slower than on the previous version, more than the %.1f%% allowed",
+					regression, r.MaxSpeedRegression)
+			}
+		}
+	}
+
+	if now.Before(r.ObservationEnds()) {
+		return BinaryRolloutCanary, ""
+	}
+	if !enoughTasks {
+		return BinaryRolloutCanary, fmt.Sprintf("observation period over but only %d of %d canary tasks finished", m.CanaryTasks, r.MinTasks)
+	}
+	return BinaryRolloutPromoted, fmt.Sprintf("%d canary tasks over %d hours within failure and speed limits", m.CanaryTasks, r.ObservationHours)
+}
+
+// Validate checks a rollout about to start
+func (r *BinaryRollout) Validate() error {
+	if r.BinaryVersionID == 0 {
+		return fmt.Errorf("binary version is required")
+	}
+	if r.CanaryLabel == "" {
+		return fmt.Errorf("canary label is required")
+	}
+	if r.ObservationHours <= 0 {
+		return fmt.Errorf("observation hours must be positive")
+	}
+	if r.MinTasks <= 0 {
+		return fmt.Errorf("minimum tasks must be positive")
+	}
+	if r.MaxFailureRateIncrease < 0 || r.MaxSpeedRegression < 0 {
+		return fmt.Errorf("failure rate and speed limits must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryRolloutDecide(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	rollout := BinaryRollout{
+		ObservationHours:       24,
+		MinTasks:               10,
+		MaxFailureRateIncrease: 5,
+		MaxSpeedRegression:     10,
+		StartedAt:              start,
+	}
+	during := start.Add(6 * time.Hour)
+	after := start.Add(25 * time.Hour)
+
+	healthy := BinaryRolloutMetrics{CanaryTasks: 20, CanaryFailures: 1, BaselineTasks: 100, BaselineFailures: 2, SpeedProfiles: 3, SpeedRatio: 0.97}
+
+	status, reason := rollout.Decide(healthy, during)
+	assert.Equal(t, BinaryRolloutCanary, status)
+	assert.Empty(t, reason)
+
+	status, _ = rollout.Decide(healthy, after)
+	assert.Equal(t, BinaryRolloutPromoted, status)
+
+	// Failures roll back without waiting for the observation period
+	failing := healthy
+	failing.CanaryFailures = 4
+	status, reason = rollout.Decide(failing, during)
+	assert.Equal(t, BinaryRolloutRolledBack, status)
+	assert.Contains(t, reason, "failure rate")
+
+	slow := healthy
+	slow.SpeedRatio = 0.85
+	status, reason = rollout.Decide(slow, during)
+	assert.Equal(t, BinaryRolloutRolledBack, status)
+	assert.Contains(t, reason, "slower")
+
+	// Too few tasks decide nothing, even after the observation period
+	few := BinaryRolloutMetrics{CanaryTasks: 3, CanaryFailures: 3}
+	status, _ = rollout.Decide(few, during)
+	assert.Equal(t, BinaryRolloutCanary, status)
+	status, reason = rollout.Decide(few, after)
+	assert.Equal(t, BinaryRolloutCanary, status)
+	assert.Contains(t, reason, "3 of 10")
+
+	// Without speed profiles only failures count
+	noSpeed := healthy
+	noSpeed.SpeedProfiles, noSpeed.SpeedRatio = 0, 0
+	status, _ = rollout.Decide(noSpeed, after)
+	assert.Equal(t, BinaryRolloutPromoted, status)
+}
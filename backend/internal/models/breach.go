@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Breach check statuses
+const (
+	BreachCheckRunning   = "running"
+	BreachCheckCompleted = "completed"
+	BreachCheckFailed    = "failed"
+)
+
+// Breach check sources, matching the breach_check_mode setting
+const (
+	BreachSourceOnline  = "online"
+	BreachSourceOffline = "offline"
+)
+
+// HashlistBreachCheck is the latest breach corpus check of a hashlist's
+// cracked passwords.
+type HashlistBreachCheck struct {
+	HashlistID    int64      `json:"hashlist_id" db:"hashlist_id"`
+	Status        string     `json:"status" db:"status"`
+	Source        string     `json:"source" db:"source"`
+	CheckedCount  int        `json:"checked_count" db:"checked_count"`
+	BreachedCount int        `json:"breached_count" db:"breached_count"`
+	StartedAt     time.Time  `json:"started_at" db:"started_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	ErrorMessage  *string    `json:"error_message,omitempty" db:"error_message"`
+}
+
+// BreachedHash is a cracked hash of a hashlist whose password appears in the
+// breach corpus. The password itself is not included.
+type BreachedHash struct {
+	HashID      uuid.UUID `json:"hash_id"`
+	Username    *string   `json:"username,omitempty"`
+	Domain      *string   `json:"domain,omitempty"`
+	BreachCount int       `json:"breach_count"`
+}
+
+// CrackedPassword is a cracked hash and its plaintext, used for lookups
+type CrackedPassword struct {
+	HashID   uuid.UUID
+	Password string
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Chunk verification statuses
+const (
+	ChunkVerificationPending  = "pending"  // Sampled, waiting for an idle agent
+	ChunkVerificationRunning  = "running"  // Re-run by the verifier agent
+	ChunkVerificationPassed   = "passed"   // The verifier recovered nothing the original missed
+	ChunkVerificationMismatch = "mismatch" // The verifier recovered hashes the original missed
+	ChunkVerificationSkipped  = "skipped"  // The job ended or the verifier failed before a result
+)
+
+// ChunkVerification is a completed chunk sampled to be re-run on a different
+// agent. Hashes cracked during a verifier run are hashes the original agent
+// should have recovered from the same keyspace, so any at all are a mismatch.
+type ChunkVerification struct {
+	ID                 int64      `json:"id" db:"id"`
+	TaskID             uuid.UUID  `json:"task_id" db:"task_id"`
+	JobExecutionID     uuid.UUID  `json:"job_execution_id" db:"job_execution_id"`
+	AgentID            *int       `json:"agent_id,omitempty" db:"agent_id"`
+	AgentName          string     `json:"agent_name" db:"agent_name"`
+	Status             string     `json:"status" db:"status"`
+	VerifierTaskID     *uuid.UUID `json:"verifier_task_id,omitempty" db:"verifier_task_id"`
+	VerifierAgentID    *int       `json:"verifier_agent_id,omitempty" db:"verifier_agent_id"`
+	OriginalCrackCount *int       `json:"original_crack_count,omitempty" db:"original_crack_count"`
+	VerifierCrackCount *int       `json:"verifier_crack_count,omitempty" db:"verifier_crack_count"`
+	AgentQuarantined   bool       `json:"agent_quarantined" db:"agent_quarantined"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// ChunkVerificationResult returns the status of a verification whose verifier
+// cracked verifierCracks new hashes
+func ChunkVerificationResult(verifierCracks int) string {
+	if verifierCracks > 0 {
+		return ChunkVerificationMismatch
+	}
+	return ChunkVerificationPassed
+}
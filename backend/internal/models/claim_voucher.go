@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"time"
 
@@ -50,11 +51,22 @@ func (nu NullUUID) Value() (driver.Value, error) {
 	return nu.UUID.String(), nil
 }
 
+// Claim voucher validation errors
+var (
+	ErrClaimVoucherInactive  = errors.New("claim code is not active")
+	ErrClaimVoucherExpired   = errors.New("claim code expired")
+	ErrClaimVoucherExhausted = errors.New("claim code has reached its usage limit")
+)
+
 // ClaimVoucher represents a claim voucher in the system
 type ClaimVoucher struct {
 	Code          string        `json:"code"`
 	IsActive      bool          `json:"is_active"`
 	IsContinuous  bool          `json:"is_continuous"`
+	MaxUses       *int          `json:"max_uses,omitempty"`
+	UseCount      int           `json:"use_count"`
+	ExpiresAt     *time.Time    `json:"expires_at,omitempty"`
+	Labels        []string      `json:"labels"`
 	CreatedByID   uuid.UUID     `json:"created_by_id"`
 	CreatedBy     *User         `json:"created_by,omitempty"`
 	UsedByAgentID sql.NullInt64 `json:"used_by_agent_id,omitempty"`
@@ -64,30 +76,46 @@ type ClaimVoucher struct {
 	UpdatedAt     time.Time     `json:"updated_at"`
 }
 
-// ClaimVoucherUsage tracks usage attempts of claim vouchers
+// ClaimVoucherUsage tracks usage attempts of claim vouchers. Agent
+// registrations have no user session, they record the agent they created.
 type ClaimVoucherUsage struct {
-	ID            uint           `json:"id"`
-	VoucherCode   string         `json:"voucherCode"`
-	AttemptedByID uuid.UUID      `json:"attemptedById"`
-	AttemptedBy   *User          `json:"attemptedBy,omitempty"`
-	AttemptedAt   time.Time      `json:"attemptedAt"`
-	Success       bool           `json:"success"`
-	IPAddress     string         `json:"ipAddress"`
-	UserAgent     string         `json:"userAgent"`
-	ErrorMessage  sql.NullString `json:"errorMessage,omitempty"`
+	ID            uint       `json:"id"`
+	VoucherCode   string     `json:"voucherCode"`
+	AttemptedByID *uuid.UUID `json:"attemptedById,omitempty"`
+	AttemptedBy   *User      `json:"attemptedBy,omitempty"`
+	AgentID       *int       `json:"agentId,omitempty"`
+	Agent         *Agent     `json:"agent,omitempty"`
+	AttemptedAt   time.Time  `json:"attemptedAt"`
+	Success       bool       `json:"success"`
+	IPAddress     string     `json:"ipAddress"`
+	UserAgent     string     `json:"userAgent"`
+	ErrorMessage  string     `json:"errorMessage,omitempty"`
 }
 
 // IsValid checks if the voucher is valid for use
 func (v *ClaimVoucher) IsValid() bool {
-	// Only check if the voucher is active
+	return v.Validate(time.Now()) == nil
+}
+
+// Validate returns why the voucher cannot register an agent at the given
+// time, or nil if it can
+func (v *ClaimVoucher) Validate(now time.Time) error {
 	if !v.IsActive {
-		return false
+		return ErrClaimVoucherInactive
+	}
+
+	if v.ExpiresAt != nil && !now.Before(*v.ExpiresAt) {
+		return ErrClaimVoucherExpired
+	}
+
+	if v.MaxUses != nil && v.UseCount >= *v.MaxUses {
+		return ErrClaimVoucherExhausted
 	}
 
 	// For single-use codes, check if they've been used
 	if !v.IsContinuous && v.UsedByAgentID.Valid {
-		return false
+		return ErrClaimVoucherExhausted
 	}
 
-	return true
+	return nil
 }
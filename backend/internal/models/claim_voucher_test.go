@@ -0,0 +1,38 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestClaimVoucherValidate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+	three := 3
+
+	tests := []struct {
+		name    string
+		voucher ClaimVoucher
+		want    error
+	}{
+		{"active continuous", ClaimVoucher{IsActive: true, IsContinuous: true}, nil},
+		{"inactive", ClaimVoucher{IsActive: false, IsContinuous: true}, ErrClaimVoucherInactive},
+		{"not yet expired", ClaimVoucher{IsActive: true, IsContinuous: true, ExpiresAt: &future}, nil},
+		{"expired", ClaimVoucher{IsActive: true, IsContinuous: true, ExpiresAt: &past}, ErrClaimVoucherExpired},
+		{"expires now", ClaimVoucher{IsActive: true, IsContinuous: true, ExpiresAt: &now}, ErrClaimVoucherExpired},
+		{"under cap", ClaimVoucher{IsActive: true, IsContinuous: true, MaxUses: &three, UseCount: 2}, nil},
+		{"cap reached", ClaimVoucher{IsActive: true, IsContinuous: true, MaxUses: &three, UseCount: 3}, ErrClaimVoucherExhausted},
+		{"single use unused", ClaimVoucher{IsActive: true}, nil},
+		{"single use used", ClaimVoucher{IsActive: true, UsedByAgentID: sql.NullInt64{Int64: 4, Valid: true}}, ErrClaimVoucherExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.voucher.Validate(now); got != tt.want {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Engagement statuses of a client
+const (
+	ClientEngagementActive = "active"
+	ClientEngagementClosed = "closed"
+)
+
+// ClientContact is a person to reach at a client
+type ClientContact struct {
+	Name    string `json:"name"`
+	Role    string `json:"role,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Phone   string `json:"phone,omitempty"`
+	Primary bool   `json:"primary"`
+}
+
+// ClientContacts is the contact list of a client, stored as JSONB
+type ClientContacts []ClientContact
+
+// Value implements driver.Valuer
+func (c ClientContacts) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner
+func (c *ClientContacts) Scan(value interface{}) error {
+	*c = ClientContacts{}
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("unsupported type for ClientContacts: %T", value)
+	}
+}
+
+// ClientEngagement is the engagement metadata of a client. Closing the
+// engagement stops new jobs against the client's hashlists.
+type ClientEngagement struct {
+	Contacts            ClientContacts `json:"contacts"`
+	EngagementStatus    string         `json:"engagementStatus"`
+	EngagementStartDate *time.Time     `json:"engagementStartDate,omitempty"`
+	EngagementEndDate   *time.Time     `json:"engagementEndDate,omitempty"`
+	ScopeNotes          *string        `json:"scopeNotes,omitempty"`
+	ClosedAt            *time.Time     `json:"closedAt,omitempty"` // Set by the server when the status changes to closed
+}
+
+// Normalize fills in the defaults of an engagement submitted by a user and
+// checks it. An empty status is active.
+func (e *ClientEngagement) Normalize() error {
+	if e.EngagementStatus == "" {
+		e.EngagementStatus = ClientEngagementActive
+	}
+	if e.EngagementStatus != ClientEngagementActive && e.EngagementStatus != ClientEngagementClosed {
+		return fmt.Errorf("engagement status must be %q or %q", ClientEngagementActive, ClientEngagementClosed)
+	}
+	if e.EngagementStartDate != nil && e.EngagementEndDate != nil && e.EngagementEndDate.Before(*e.EngagementStartDate) {
+		return errors.New("engagement end date is before its start date")
+	}
+
+	if e.Contacts == nil {
+		e.Contacts = ClientContacts{}
+	}
+	primaries := 0
+	for i := range e.Contacts {
+		contact := &e.Contacts[i]
+		contact.Name = strings.TrimSpace(contact.Name)
+		contact.Email = strings.TrimSpace(contact.Email)
+		if contact.Name == "" {
+			return fmt.Errorf("contact %d has no name", i+1)
+		}
+		if contact.Email != "" {
+			if _, err := mail.ParseAddress(contact.Email); err != nil {
+				return fmt.Errorf("contact %q has an invalid email address", contact.Name)
+			}
+		}
+		if contact.Primary {
+			primaries++
+		}
+	}
+	if primaries > 1 {
+		return errors.New("only one contact can be the primary contact")
+	}
+	return nil
+}
+
+// Closed reports whether the engagement is closed
+func (e ClientEngagement) Closed() bool {
+	return e.EngagementStatus == ClientEngagementClosed
+}
+
+// RetentionStart returns when the retention period of a hashlist created at
+// createdAt starts. Hashlists of a closed engagement are kept for the
+// retention period after it closed.
+func (e ClientEngagement) RetentionStart(createdAt time.Time) time.Time {
+	if e.Closed() && e.ClosedAt != nil && e.ClosedAt.After(createdAt) {
+		return *e.ClosedAt
+	}
+	return createdAt
+}
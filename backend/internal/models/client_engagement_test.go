@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientEngagementNormalize(t *testing.T) {
+	engagement := ClientEngagement{}
+	require.NoError(t, engagement.Normalize())
+	assert.Equal(t, ClientEngagementActive, engagement.EngagementStatus)
+	assert.NotNil(t, engagement.Contacts)
+
+	engagement = ClientEngagement{Contacts: ClientContacts{{Name: "  Alice ", Email: " alice@example.com ", Primary: true}}}
+	require.NoError(t, engagement.Normalize())
+	assert.Equal(t, "Alice", engagement.Contacts[0].Name)
+	assert.Equal(t, "alice@example.com", engagement.Contacts[0].Email)
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, -1, 0)
+	invalid := []ClientEngagement{
+		{EngagementStatus: "paused"},
+		{EngagementStartDate: &start, EngagementEndDate: &end},
+		{Contacts: ClientContacts{{Name: " "}}},
+		{Contacts: ClientContacts{{Name: "Bob", Email: "not an address"}}},
+		{Contacts: ClientContacts{{Name: "Alice", Primary: true}, {Name: "Bob", Primary: true}}},
+	}
+	for _, engagement := range invalid {
+		assert.Error(t, engagement.Normalize(), "%+v", engagement)
+	}
+}
+
+func TestClientEngagementRetentionStart(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	closed := created.AddDate(0, 2, 0)
+
+	active := ClientEngagement{EngagementStatus: ClientEngagementActive}
+	assert.Equal(t, created, active.RetentionStart(created))
+
+	// Retention of a closed engagement counts from when it closed
+	closedEngagement := ClientEngagement{EngagementStatus: ClientEngagementClosed, ClosedAt: &closed}
+	assert.True(t, closedEngagement.Closed())
+	assert.Equal(t, closed, closedEngagement.RetentionStart(created))
+
+	// Hashlists created after the close count from their creation
+	later := closed.AddDate(0, 1, 0)
+	assert.Equal(t, later, closedEngagement.RetentionStart(later))
+}
+
+func TestClientContactsScan(t *testing.T) {
+	var contacts ClientContacts
+	require.NoError(t, contacts.Scan([]byte(`[{"name":"Alice","email":"alice@example.com","primary":true}]`)))
+	assert.Equal(t, ClientContacts{{Name: "Alice", Email: "alice@example.com", Primary: true}}, contacts)
+
+	require.NoError(t, contacts.Scan(nil))
+	assert.Empty(t, contacts)
+
+	value, err := ClientContacts(nil).Value()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("[]"), value)
+}
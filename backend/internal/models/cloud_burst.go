@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// CloudBurstAgentLabel is applied to the agents cloud burst instances register.
+// Those agents only run jobs that allow cloud burst.
+const CloudBurstAgentLabel = "cloud-burst"
+
+// CloudBurstInstanceStatus is the lifecycle state of a cloud burst instance
+type CloudBurstInstanceStatus string
+
+const (
+	// CloudBurstProvisioning instances were launched and wait for their agent to register
+	CloudBurstProvisioning CloudBurstInstanceStatus = "provisioning"
+	// CloudBurstRunning instances have a registered agent
+	CloudBurstRunning CloudBurstInstanceStatus = "running"
+	// CloudBurstTerminated instances were torn down
+	CloudBurstTerminated CloudBurstInstanceStatus = "terminated"
+	// CloudBurstFailed instances failed to launch or register and were torn down
+	CloudBurstFailed CloudBurstInstanceStatus = "failed"
+)
+
+// CloudBurstInstance is a temporary cloud agent launched while jobs that
+// allow cloud burst had work and no agent was free
+type CloudBurstInstance struct {
+	ID                 int64                    `json:"id"`
+	Name               string                   `json:"name"`
+	ProviderInstanceID *string                  `json:"provider_instance_id"`
+	ClaimCode          string                   `json:"-"`
+	AgentID            *int                     `json:"agent_id"`
+	Status             CloudBurstInstanceStatus `json:"status"`
+	HourlyCost         float64                  `json:"hourly_cost"`
+	ErrorMessage       *string                  `json:"error_message"`
+	LaunchedAt         time.Time                `json:"launched_at"`
+	RegisteredAt       *time.Time               `json:"registered_at"`
+	LastBusyAt         *time.Time               `json:"last_busy_at"`
+	TerminatedAt       *time.Time               `json:"terminated_at"`
+}
+
+// Active reports whether the instance may still be running at the provider
+func (i *CloudBurstInstance) Active() bool {
+	return i.Status == CloudBurstProvisioning || i.Status == CloudBurstRunning
+}
+
+// IsCloudBurst reports whether the agent was registered by a cloud burst instance
+func (a *Agent) IsCloudBurst() bool {
+	for _, label := range a.Labels {
+		if label == CloudBurstAgentLabel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Hash type classes the cluster hash rate is grouped by
+const (
+	HashClassFast = "fast"
+	HashClassSlow = "slow"
+)
+
+// ClusterHealth summarizes the state of the whole cluster for dashboards and
+// external monitoring
+type ClusterHealth struct {
+	GeneratedAt time.Time `json:"generated_at"`
+
+	AgentsOnline int `json:"agents_online"`
+	AgentsTotal  int `json:"agents_total"`
+
+	// HashRates is the current speed of the running tasks per hash type class
+	HashRates []ClusterHashRate `json:"hash_rates"`
+
+	// QueueDepth is the number of pending jobs
+	QueueDepth int `json:"queue_depth"`
+	// OldestPendingJobAgeSeconds is 0 when no job is pending
+	OldestPendingJobAgeSeconds int64 `json:"oldest_pending_job_age_seconds"`
+
+	// Chunks that finished in the last hour and the share of them that failed
+	ChunksFinishedLastHour int     `json:"chunks_finished_last_hour"`
+	ChunksFailedLastHour   int     `json:"chunks_failed_last_hour"`
+	ChunkFailureRate       float64 `json:"chunk_failure_rate"`
+
+	// Storage is nil when the usage of the data directory cannot be read
+	Storage *ClusterStorage `json:"storage"`
+
+	// WebSocket is nil when the agent WebSocket hub is not running
+	WebSocket *WebSocketHubMetrics `json:"websocket"`
+}
+
+// ClusterHashRate is the combined speed of the running tasks of one hash type class
+type ClusterHashRate struct {
+	Class        string `json:"class"`
+	HashesPerSec int64  `json:"hashes_per_sec"`
+	RunningTasks int    `json:"running_tasks"`
+}
+
+// ClusterStorage is the usage of the filesystem holding the data directory
+type ClusterStorage struct {
+	Path        string  `json:"path"`
+	TotalBytes  uint64  `json:"total_bytes"`
+	UsedBytes   uint64  `json:"used_bytes"`
+	FreeBytes   uint64  `json:"free_bytes"`
+	UsedPercent float64 `json:"used_percent"`
+}
+
+// WebSocketHubMetrics is the state of the agent WebSocket connections. The
+// message counters cover all agents since the backend started.
+type WebSocketHubMetrics struct {
+	ConnectedAgents int   `json:"connected_agents"`
+	MessagesSent    int64 `json:"messages_sent"`
+	// MessagesDropped counts messages dropped because an agent's queue was full
+	MessagesDropped int64 `json:"messages_dropped"`
+	// SlowConsumerDisconnects counts agents disconnected for dropping too many messages
+	SlowConsumerDisconnects int64                   `json:"slow_consumer_disconnects"`
+	Agents                  []WebSocketAgentMetrics `json:"agents"`
+}
+
+// WebSocketAgentMetrics is the send queue state of one connected agent. The
+// control queue carries job control, the send queue everything else.
+type WebSocketAgentMetrics struct {
+	AgentID              int       `json:"agent_id"`
+	ConnectedAt          time.Time `json:"connected_at"`
+	ControlQueueDepth    int       `json:"control_queue_depth"`
+	ControlQueueCapacity int       `json:"control_queue_capacity"`
+	SendQueueDepth       int       `json:"send_queue_depth"`
+	SendQueueCapacity    int       `json:"send_queue_capacity"`
+	MessagesSent         int64     `json:"messages_sent"`
+	MessagesDropped      int64     `json:"messages_dropped"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CrackDelta is a hash of a hashlist cracked after a cursor, with the task and
+// attack that cracked it. The attribution fields are empty for hashes cracked
+// at upload from the pot-file or whose task was deleted since.
+type CrackDelta struct {
+	HashID         uuid.UUID   `json:"hash_id"`
+	HashValue      string      `json:"hash_value"`
+	OriginalHash   string      `json:"original_hash"`
+	Username       *string     `json:"username,omitempty"`
+	Domain         *string     `json:"domain,omitempty"`
+	Password       string      `json:"password"`
+	CrackedAt      time.Time   `json:"cracked_at"`
+	TaskID         *uuid.UUID  `json:"task_id,omitempty"`
+	JobExecutionID *uuid.UUID  `json:"job_execution_id,omitempty"`
+	JobName        *string     `json:"job_name,omitempty"`
+	AgentID        *int        `json:"agent_id,omitempty"`
+	AttackMode     *AttackMode `json:"attack_mode,omitempty"`
+	WordlistIDs    IDArray     `json:"wordlist_ids,omitempty"`
+	RuleIDs        IDArray     `json:"rule_ids,omitempty"`
+	Mask           *string     `json:"mask,omitempty"`
+}
+
+// CrackCursor marks a position in the order cracks are returned in. Hashes
+// cracked in the same microsecond are ordered by ID so no crack is returned twice.
+type CrackCursor struct {
+	CrackedAt time.Time
+	HashID    uuid.UUID
+}
+
+// CursorAfter returns the cursor following a crack
+func (d CrackDelta) CursorAfter() CrackCursor {
+	return CrackCursor{CrackedAt: d.CrackedAt, HashID: d.HashID}
+}
+
+// String encodes the cursor for the API. The timestamp is kept in microseconds,
+// the precision PostgreSQL stores it with.
+func (c CrackCursor) String() string {
+	return fmt.Sprintf("%d_%s", c.CrackedAt.UnixMicro(), c.HashID)
+}
+
+// ParseCrackCursor decodes a cursor returned by CrackCursor.String
+func ParseCrackCursor(s string) (CrackCursor, error) {
+	micros, id, ok := strings.Cut(s, "_")
+	if !ok {
+		return CrackCursor{}, fmt.Errorf("invalid crack cursor %q", s)
+	}
+	us, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return CrackCursor{}, fmt.Errorf("invalid crack cursor %q: %w", s, err)
+	}
+	hashID, err := uuid.Parse(id)
+	if err != nil {
+		return CrackCursor{}, fmt.Errorf("invalid crack cursor %q: %w", s, err)
+	}
+	return CrackCursor{CrackedAt: time.UnixMicro(us), HashID: hashID}, nil
+}
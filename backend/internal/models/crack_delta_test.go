@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrackCursorRoundTrip(t *testing.T) {
+	delta := CrackDelta{
+		HashID:    uuid.New(),
+		CrackedAt: time.Date(2026, 3, 1, 12, 30, 0, 123456789, time.UTC),
+	}
+
+	cursor, err := ParseCrackCursor(delta.CursorAfter().String())
+	require.NoError(t, err)
+	assert.Equal(t, delta.HashID, cursor.HashID)
+	// Truncated to the microseconds PostgreSQL keeps
+	assert.True(t, cursor.CrackedAt.Equal(delta.CrackedAt.Truncate(time.Microsecond)))
+}
+
+func TestParseCrackCursorInvalid(t *testing.T) {
+	for _, s := range []string{"", "1700000000", "abc_" + uuid.NewString(), "1700000000_not-a-uuid"} {
+		_, err := ParseCrackCursor(s)
+		assert.Error(t, err, s)
+	}
+}
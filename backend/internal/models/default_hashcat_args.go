@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatargs"
+)
+
+// DefaultHashcatArgsSetting is the system setting holding the default
+// hashcat arguments rules, a JSON array of DefaultHashcatArgsRule
+const DefaultHashcatArgsSetting = "default_hashcat_args"
+
+// DefaultHashcatArgsRule adds hashcat arguments to every job it matches. An
+// empty condition matches every job. Rules are applied in order, so a later
+// rule setting the same option wins, and the job's own arguments win over all
+// of them.
+type DefaultHashcatArgsRule struct {
+	AttackMode *AttackMode `json:"attack_mode,omitempty"` // Only jobs of this attack mode
+	HashClass  string      `json:"hash_class,omitempty"`  // Only fast or slow hash types
+	MinRules   int         `json:"min_rules,omitempty"`   // Only jobs applying at least this many rules
+	Args       string      `json:"args"`
+}
+
+// ParseDefaultHashcatArgs parses the value of the default_hashcat_args
+// setting. An empty value has no rules.
+func ParseDefaultHashcatArgs(value string) ([]DefaultHashcatArgsRule, error) {
+	rules := []DefaultHashcatArgsRule{}
+	if strings.TrimSpace(value) == "" {
+		return rules, nil
+	}
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, fmt.Errorf("invalid default hashcat arguments: %w", err)
+	}
+	return rules, nil
+}
+
+// ValidateDefaultHashcatArgs checks the rules' conditions and that their
+// arguments are allowed hashcat parameters
+func ValidateDefaultHashcatArgs(rules []DefaultHashcatArgsRule) error {
+	for i, rule := range rules {
+		if rule.AttackMode != nil {
+			switch *rule.AttackMode {
+			case AttackModeStraight, AttackModeCombination, AttackModeBruteForce,
+				AttackModeHybridWordlistMask, AttackModeHybridMaskWordlist, AttackModeAssociation:
+			default:
+				return fmt.Errorf("rule %d: invalid attack mode %d", i+1, *rule.AttackMode)
+			}
+		}
+		if rule.HashClass != "" && rule.HashClass != HashClassFast && rule.HashClass != HashClassSlow {
+			return fmt.Errorf("rule %d: hash class must be %q, %q or empty", i+1, HashClassFast, HashClassSlow)
+		}
+		if rule.MinRules < 0 {
+			return fmt.Errorf("rule %d: min_rules must not be negative", i+1)
+		}
+		if strings.TrimSpace(rule.Args) == "" {
+			return fmt.Errorf("rule %d: args are required", i+1)
+		}
+		if err := hashcatargs.Validate(rule.Args); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to a job. slow tells whether the
+// job's hash type is slow.
+func (r DefaultHashcatArgsRule) Matches(job *JobExecution, slow bool) bool {
+	if r.AttackMode != nil && *r.AttackMode != job.AttackMode {
+		return false
+	}
+	switch r.HashClass {
+	case HashClassFast:
+		if slow {
+			return false
+		}
+	case HashClassSlow:
+		if !slow {
+			return false
+		}
+	}
+	if r.MinRules > 0 && len(job.RuleIDs) == 0 {
+		return false
+	}
+	return r.MinRules <= 0 || job.MultiplicationFactor >= r.MinRules
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDefaultHashcatArgs(t *testing.T) {
+	rules, err := ParseDefaultHashcatArgs("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	rules, err = ParseDefaultHashcatArgs(`[{"attack_mode": 0, "hash_class": "fast", "args": "-O"}]`)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, AttackModeStraight, *rules[0].AttackMode)
+	assert.Equal(t, "-O", rules[0].Args)
+
+	_, err = ParseDefaultHashcatArgs(`{"args": "-O"}`)
+	assert.Error(t, err)
+}
+
+func TestValidateDefaultHashcatArgs(t *testing.T) {
+	straight := AttackModeStraight
+	invalid := AttackMode(2)
+
+	assert.NoError(t, ValidateDefaultHashcatArgs([]DefaultHashcatArgsRule{
+		{HashClass: HashClassFast, Args: "-O"},
+		{AttackMode: &straight, MinRules: 10000, Args: "--slow-candidates"},
+	}))
+	assert.Error(t, ValidateDefaultHashcatArgs([]DefaultHashcatArgsRule{{AttackMode: &invalid, Args: "-O"}}))
+	assert.Error(t, ValidateDefaultHashcatArgs([]DefaultHashcatArgsRule{{HashClass: "medium", Args: "-O"}}))
+	assert.Error(t, ValidateDefaultHashcatArgs([]DefaultHashcatArgsRule{{MinRules: -1, Args: "-O"}}))
+	assert.Error(t, ValidateDefaultHashcatArgs([]DefaultHashcatArgsRule{{Args: " "}}))
+	// Arguments must be allowed hashcat parameters
+	assert.Error(t, ValidateDefaultHashcatArgs([]DefaultHashcatArgsRule{{Args: "--outfile /tmp/x"}}))
+}
+
+func TestDefaultHashcatArgsRuleMatches(t *testing.T) {
+	straight := AttackModeStraight
+	withRules := &JobExecution{AttackMode: AttackModeStraight, RuleIDs: IDArray{"1"}, MultiplicationFactor: 50000}
+	bruteForce := &JobExecution{AttackMode: AttackModeBruteForce}
+
+	assert.True(t, DefaultHashcatArgsRule{Args: "-O"}.Matches(bruteForce, true))
+	assert.True(t, DefaultHashcatArgsRule{AttackMode: &straight}.Matches(withRules, false))
+	assert.False(t, DefaultHashcatArgsRule{AttackMode: &straight}.Matches(bruteForce, false))
+
+	assert.True(t, DefaultHashcatArgsRule{HashClass: HashClassFast}.Matches(bruteForce, false))
+	assert.False(t, DefaultHashcatArgsRule{HashClass: HashClassFast}.Matches(bruteForce, true))
+	assert.True(t, DefaultHashcatArgsRule{HashClass: HashClassSlow}.Matches(bruteForce, true))
+	assert.False(t, DefaultHashcatArgsRule{HashClass: HashClassSlow}.Matches(bruteForce, false))
+
+	assert.True(t, DefaultHashcatArgsRule{MinRules: 10000}.Matches(withRules, false))
+	assert.False(t, DefaultHashcatArgsRule{MinRules: 100000}.Matches(withRules, false))
+	// The multiplication factor of other attacks is not a rule count
+	assert.False(t, DefaultHashcatArgsRule{MinRules: 1}.Matches(&JobExecution{AttackMode: AttackModeCombination, MultiplicationFactor: 5000}, false))
+}
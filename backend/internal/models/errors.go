@@ -4,7 +4,9 @@ import "errors"
 
 // Common errors
 var (
-	ErrNotFound      = errors.New("record not found")
-	ErrInvalidInput  = errors.New("invalid input")
-	ErrResourceInUse = errors.New("resource is currently in use")
+	ErrNotFound           = errors.New("record not found")
+	ErrInvalidInput       = errors.New("invalid input")
+	ErrResourceInUse      = errors.New("resource is currently in use")
+	ErrAlreadyExists      = errors.New("resource already exists")
+	ErrResourceReferenced = errors.New("resource is referenced by preset jobs")
 )
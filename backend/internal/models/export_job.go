@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportStatus is the progress of an export job
+type ExportStatus string
+
+const (
+	// ExportStatusPending means the export waits for a free export slot
+	ExportStatusPending ExportStatus = "pending"
+	// ExportStatusRunning means the artifact is being written
+	ExportStatusRunning ExportStatus = "running"
+	// ExportStatusCompleted means the artifact can be downloaded
+	ExportStatusCompleted ExportStatus = "completed"
+	// ExportStatusFailed means the export could not be built
+	ExportStatusFailed ExportStatus = "failed"
+	// ExportStatusExpired means the artifact was deleted after its retention
+	ExportStatusExpired ExportStatus = "expired"
+)
+
+// IsFinal reports whether the export will not make progress anymore
+func (s ExportStatus) IsFinal() bool {
+	return s != ExportStatusPending && s != ExportStatusRunning
+}
+
+// Kinds of export
+const (
+	// ExportKindClientPotfile is the deduplicated potfile of a client's hashlists
+	ExportKindClientPotfile = "client_potfile"
+	// ExportKindHashlistCracked is the hash:password list of a hashlist's cracked hashes
+	ExportKindHashlistCracked = "hashlist_cracked"
+)
+
+// ExportJob is an export built in the background
+type ExportJob struct {
+	ID           uuid.UUID       `json:"id"`
+	UserID       uuid.UUID       `json:"user_id"`
+	Kind         string          `json:"kind"`
+	Params       json.RawMessage `json:"params"`
+	Status       ExportStatus    `json:"status"`
+	Processed    int64           `json:"processed"`
+	Total        *int64          `json:"total,omitempty"`
+	FileName     *string         `json:"file_name,omitempty"`
+	FilePath     string          `json:"-"`
+	FileSize     *int64          `json:"file_size,omitempty"`
+	ErrorMessage *string         `json:"error_message,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	StartedAt    *time.Time      `json:"started_at,omitempty"`
+	CompletedAt  *time.Time      `json:"completed_at,omitempty"`
+	ExpiresAt    *time.Time      `json:"expires_at,omitempty"`
+	// Signed link to the artifact, only set on completed exports
+	DownloadURL *string `json:"download_url,omitempty"`
+}
+
+// ExportRequest is the body of a request for an export
+type ExportRequest struct {
+	Kind   string          `json:"kind"`
+	Params json.RawMessage `json:"params"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "github.com/google/uuid"
+
 // FileType represents the type of file for synchronization
 type FileType string
 
@@ -34,3 +36,25 @@ type FileSyncResponsePayload struct {
 type FileSyncCommandPayload struct {
 	Files []FileInfo `json:"files"`
 }
+
+// FileReference is a preset job or job that uses a wordlist or rule
+type FileReference struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// Status is set for jobs only
+	Status string `json:"status,omitempty"`
+}
+
+// FileReferences lists what uses a wordlist or rule. Only jobs that have not
+// finished are listed, finished jobs keep their IDs for history.
+type FileReferences struct {
+	PresetJobs []FileReference `json:"preset_jobs"`
+	Jobs       []FileReference `json:"jobs"`
+}
+
+// What a wordlist or rule upload does when an existing file has the same
+// canonical hash, i.e. only differs in byte order mark or line endings
+const (
+	OnDuplicateAlias = "alias" // Drop the upload and alias its name to the existing file
+	OnDuplicateKeep  = "keep"  // Store the upload as a separate file
+)
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceGPUUsage is the GPU time one agent device spent on a job
+type DeviceGPUUsage struct {
+	AgentID    *int    `json:"agent_id,omitempty"`
+	AgentName  string  `json:"agent_name"`
+	DeviceID   int     `json:"device_id"`
+	DeviceName string  `json:"device_name"`
+	GPUSeconds float64 `json:"gpu_seconds"`
+	GPUHours   float64 `json:"gpu_hours"`
+	Cost       float64 `json:"cost"`
+}
+
+// JobGPUUsage is the GPU time spent on one job
+type JobGPUUsage struct {
+	JobExecutionID *uuid.UUID `json:"job_execution_id,omitempty"`
+	JobName        string     `json:"job_name"`
+	GPUSeconds     float64    `json:"gpu_seconds"`
+	GPUHours       float64    `json:"gpu_hours"`
+	Cost           float64    `json:"cost"`
+	FirstUsedAt    time.Time  `json:"first_used_at"`
+	LastUsedAt     time.Time  `json:"last_used_at"`
+}
+
+// JobCostReport breaks down the GPU time and estimated cost of a job per device
+type JobCostReport struct {
+	JobExecutionID uuid.UUID        `json:"job_execution_id"`
+	JobName        string           `json:"job_name"`
+	GPUHourCost    float64          `json:"gpu_hour_cost"`
+	GPUSeconds     float64          `json:"gpu_seconds"`
+	GPUHours       float64          `json:"gpu_hours"`
+	Cost           float64          `json:"cost"`
+	Devices        []DeviceGPUUsage `json:"devices"`
+}
+
+// ClientCostReport breaks down the GPU time and estimated cost of a client's
+// jobs in a time range
+type ClientCostReport struct {
+	ClientID    uuid.UUID     `json:"client_id"`
+	ClientName  string        `json:"client_name"`
+	Engagement  string        `json:"engagement_status"`
+	From        *time.Time    `json:"from,omitempty"`
+	To          *time.Time    `json:"to,omitempty"`
+	GPUHourCost float64       `json:"gpu_hour_cost"`
+	GPUSeconds  float64       `json:"gpu_seconds"`
+	GPUHours    float64       `json:"gpu_hours"`
+	Cost        float64       `json:"cost"`
+	Jobs        []JobGPUUsage `json:"jobs"`
+}
+
+// GPUCost converts GPU seconds to GPU hours and their cost at gpuHourCost per hour
+func GPUCost(gpuSeconds, gpuHourCost float64) (gpuHours, cost float64) {
+	gpuHours = gpuSeconds / 3600
+	return gpuHours, gpuHours * gpuHourCost
+}
+
+// NewJobCostReport totals the device usage of a job and prices it
+func NewJobCostReport(jobID uuid.UUID, jobName string, devices []DeviceGPUUsage, gpuHourCost float64) *JobCostReport {
+	report := &JobCostReport{
+		JobExecutionID: jobID,
+		JobName:        jobName,
+		GPUHourCost:    gpuHourCost,
+		Devices:        []DeviceGPUUsage{},
+	}
+	for _, device := range devices {
+		device.GPUHours, device.Cost = GPUCost(device.GPUSeconds, gpuHourCost)
+		report.GPUSeconds += device.GPUSeconds
+		report.Devices = append(report.Devices, device)
+	}
+	report.GPUHours, report.Cost = GPUCost(report.GPUSeconds, gpuHourCost)
+	return report
+}
+
+// NewClientCostReport totals the job usage of a client and prices it
+func NewClientCostReport(client *Client, from, to *time.Time, jobs []JobGPUUsage, gpuHourCost float64) *ClientCostReport {
+	report := &ClientCostReport{
+		ClientID:    client.ID,
+		ClientName:  client.Name,
+		Engagement:  client.EngagementStatus,
+		From:        from,
+		To:          to,
+		GPUHourCost: gpuHourCost,
+		Jobs:        []JobGPUUsage{},
+	}
+	for _, job := range jobs {
+		job.GPUHours, job.Cost = GPUCost(job.GPUSeconds, gpuHourCost)
+		report.GPUSeconds += job.GPUSeconds
+		report.Jobs = append(report.Jobs, job)
+	}
+	report.GPUHours, report.Cost = GPUCost(report.GPUSeconds, gpuHourCost)
+	return report
+}
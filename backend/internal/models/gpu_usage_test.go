@@ -0,0 +1,50 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestNewJobCostReport(t *testing.T) {
+	agentID := 3
+	devices := []DeviceGPUUsage{
+		{AgentID: &agentID, DeviceID: 1, GPUSeconds: 5400},
+		{AgentID: &agentID, DeviceID: 2, GPUSeconds: 1800},
+	}
+
+	report := NewJobCostReport(uuid.New(), "engagement", devices, 2.5)
+
+	if !almostEqual(report.GPUSeconds, 7200) || !almostEqual(report.GPUHours, 2) || !almostEqual(report.Cost, 5) {
+		t.Errorf("totals = %v s, %v h, %v, want 7200 s, 2 h, 5", report.GPUSeconds, report.GPUHours, report.Cost)
+	}
+	if !almostEqual(report.Devices[0].GPUHours, 1.5) || !almostEqual(report.Devices[0].Cost, 3.75) {
+		t.Errorf("device 1 = %v h, %v, want 1.5 h, 3.75", report.Devices[0].GPUHours, report.Devices[0].Cost)
+	}
+	if !almostEqual(report.Devices[1].Cost, 1.25) {
+		t.Errorf("device 2 cost = %v, want 1.25", report.Devices[1].Cost)
+	}
+}
+
+func TestNewClientCostReport(t *testing.T) {
+	client := &Client{ID: uuid.New(), Name: "Acme"}
+
+	report := NewClientCostReport(client, nil, nil, nil, 0)
+	if report.Jobs == nil || report.Cost != 0 || report.GPUHours != 0 {
+		t.Errorf("empty report = %+v, want no jobs and zero totals", report)
+	}
+
+	jobs := []JobGPUUsage{{JobName: "first", GPUSeconds: 3600}, {JobName: "deleted", GPUSeconds: 900}}
+	report = NewClientCostReport(client, nil, nil, jobs, 4)
+	if report.ClientName != "Acme" || !almostEqual(report.GPUHours, 1.25) || !almostEqual(report.Cost, 5) {
+		t.Errorf("report = %s, %v h, %v, want Acme, 1.25 h, 5", report.ClientName, report.GPUHours, report.Cost)
+	}
+	if !almostEqual(report.Jobs[1].Cost, 1) {
+		t.Errorf("deleted job cost = %v, want 1", report.Jobs[1].Cost)
+	}
+}
@@ -10,6 +10,7 @@ import (
 // HashListStatus represents the processing status of a hashlist.
 const (
 	HashListStatusUploading       = "uploading"  // Initial state upon upload start
+	HashListStatusStaged          = "staged"     // Uploaded and parsed for a preview, waiting for the user to commit it
 	HashListStatusProcessing      = "processing" // State while hashes are being processed and added to DB
 	HashListStatusReady           = "ready"      // State when processing is complete and list is usable
 	HashListStatusError           = "error"      // State if an error occurred during processing
@@ -31,10 +32,28 @@ type HashList struct {
 	Status             string         `json:"status"`                        // Processing status (uploading, processing, ready, error)
 	ErrorMessage       sql.NullString `json:"error_message"`                 // Use sql.NullString to handle NULL
 	ExcludeFromPotfile bool           `json:"exclude_from_potfile"`          // Flag to exclude cracked passwords from potfile
+	SourceUploadID     *uuid.UUID     `json:"source_upload_id,omitempty"`    // Upload this list was split from, if any
+	ParentHashlistID   *int64         `json:"parent_hashlist_id,omitempty"`  // Hashlist this sub-list was split from, if any
+	PartNumber         *int           `json:"part_number,omitempty"`         // Position among the parent's sub-lists, starting at 1
+	Notes              string         `json:"notes"`                         // Free-form notes
+	Tags               []string       `json:"tags"`                          // Normalized tags, see NormalizeTags
 	CreatedAt          time.Time      `json:"createdAt"`                     // Timestamp of creation - Use camelCase
 	UpdatedAt          time.Time      `json:"updatedAt"`                     // Timestamp of last update - Use camelCase
 }
 
+// HashlistSourceUpload is an upload that was split into one hashlist per
+// detected hash type. It lets reports group those hashlists again.
+type HashlistSourceUpload struct {
+	ID            uuid.UUID  `json:"id"`
+	FileName      string     `json:"file_name"`
+	UserID        uuid.UUID  `json:"user_id"`
+	ClientID      *uuid.UUID `json:"client_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Hashlists     []HashList `json:"hashlists"`
+	TotalHashes   int        `json:"total_hashes"`   // Sum over the hashlists
+	CrackedHashes int        `json:"cracked_hashes"` // Sum over the hashlists
+}
+
 // Hash represents a single hash entry in the system.
 type Hash struct {
 	ID           uuid.UUID `json:"id"`                 // Primary key
@@ -46,18 +65,30 @@ type Hash struct {
 	IsCracked    bool      `json:"is_cracked"`         // Flag indicating if the hash is cracked
 	Password     string    `json:"password"`           // The cracked password (if is_cracked is true)
 	LastUpdated  time.Time `json:"last_updated"`       // Timestamp of the last update (e.g., when cracked)
+	PasswordRaw  []byte    `json:"-"`                  // Raw bytes of the password when Password cannot hold them exactly
+}
+
+// PlainBytes returns the exact bytes of the cracked password
+func (h *Hash) PlainBytes() []byte {
+	if h.PasswordRaw != nil {
+		return h.PasswordRaw
+	}
+	return []byte(h.Password)
 }
 
 // HashType represents a type of hash algorithm recognized by the system.
 type HashType struct {
-	ID              int     `json:"id"`                         // Primary key (e.g., hashcat mode number)
-	Name            string  `json:"name"`                       // Common name (e.g., "MD5", "NTLM")
-	Description     *string `json:"description,omitempty"`      // Description of the hash type (pointer to handle NULL)
-	Example         *string `json:"example,omitempty"`          // Example hash format (pointer to handle NULL)
-	NeedsProcessing bool    `json:"needs_processing"`           // Flag if special processing is needed before cracking (e.g., NTLM)
-	ProcessingLogic *string `json:"processing_logic,omitempty"` // Description or identifier for the processing logic (pointer to handle NULL)
-	IsEnabled       bool    `json:"is_enabled"`                 // Whether this hash type is currently supported/enabled
-	Slow            bool    `json:"slow"`                       // Flag indicating if this is a slow hash algorithm (computationally expensive)
+	ID              int      `json:"id"`                         // Primary key (e.g., hashcat mode number)
+	Name            string   `json:"name"`                       // Common name (e.g., "MD5", "NTLM")
+	Description     *string  `json:"description,omitempty"`      // Description of the hash type (pointer to handle NULL)
+	Example         *string  `json:"example,omitempty"`          // Example hash format (pointer to handle NULL)
+	NeedsProcessing bool     `json:"needs_processing"`           // Flag if special processing is needed before cracking (e.g., NTLM)
+	ProcessingLogic *string  `json:"processing_logic,omitempty"` // Description or identifier for the processing logic (pointer to handle NULL)
+	IsEnabled       bool     `json:"is_enabled"`                 // Whether this hash type is currently supported/enabled
+	Slow            bool     `json:"slow"`                       // Flag indicating if this is a slow hash algorithm (computationally expensive)
+	Aliases         []string `json:"aliases"`                    // Lowercase alternative names (e.g., "ntlm", "kerberoast")
+	ValidationRegex *string  `json:"validation_regex,omitempty"` // Pattern every hash of this type must match (pointer to handle NULL)
+	IsCustom        bool     `json:"is_custom"`                  // Whether an administrator added this hash type
 }
 
 // Client represents a client or engagement associated with hashlists.
@@ -71,6 +102,7 @@ type Client struct {
 	CreatedAt           time.Time `json:"createdAt"`                     // Timestamp of creation
 	UpdatedAt           time.Time `json:"updatedAt"`                     // Timestamp of last update
 	CrackedCount        *int      `json:"cracked_count,omitempty"`       // Count of cracked hashes for this client (computed field)
+	ClientEngagement
 }
 
 // HashListHash represents the many-to-many relationship between hashlists and hashes.
@@ -91,3 +123,35 @@ type HashlistInfo struct {
 	ID   int64  `json:"id"`   // Hashlist ID (Changed from UUID)
 	Name string `json:"name"` // Hashlist Name
 }
+
+// CrackSource is a hashlist a cracked hash was found in, with the jobs that
+// were running against that hashlist when the hash was cracked.
+type CrackSource struct {
+	HashlistID   int64       `json:"hashlist_id"`
+	HashlistName string      `json:"hashlist_name"`
+	ClientID     *uuid.UUID  `json:"client_id,omitempty"`
+	ClientName   *string     `json:"client_name,omitempty"`
+	OwnerID      uuid.UUID   `json:"owner_id"`
+	JobIDs       []uuid.UUID `json:"job_ids"`
+	Restricted   bool        `json:"-"` // The hashlist or its client is excluded from the potfile
+}
+
+// CrackLookupResult is the answer to whether a hash was ever cracked on this instance.
+type CrackLookupResult struct {
+	Hash       string        `json:"hash"`
+	Cracked    bool          `json:"cracked"`
+	Password   *string       `json:"password,omitempty"`
+	HashTypeID *int          `json:"hash_type_id,omitempty"`
+	CrackedAt  *time.Time    `json:"cracked_at,omitempty"`
+	Sources    []CrackSource `json:"sources"`
+}
+
+// QuarantinedLine is an input line that was rejected while parsing a hashlist.
+type QuarantinedLine struct {
+	ID          int64     `json:"id"`           // Primary key
+	HashlistID  int64     `json:"hashlist_id"`  // FK to hashlists table
+	LineNumber  int       `json:"line_number"`  // Line number in the uploaded file, 0 when a corrected line cannot be matched to it
+	LineContent string    `json:"line_content"` // The rejected line as uploaded
+	ErrorReason string    `json:"error_reason"` // Why the line was rejected
+	CreatedAt   time.Time `json:"created_at"`   // When the line was quarantined
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PrivilegedGroupsSetting is the system setting listing the groups whose
+// members are privileged accounts
+const PrivilegedGroupsSetting = "privileged_groups"
+
+// DefaultPrivilegedGroups are the groups used when the setting is missing
+const DefaultPrivilegedGroups = "Domain Admins,Enterprise Admins,Schema Admins,Administrators,Account Operators,Backup Operators,Server Operators,Print Operators"
+
+// PrivilegedCrackedTag is added to a hashlist once one of its privileged
+// accounts is cracked
+const PrivilegedCrackedTag = "privileged-cracked"
+
+// Limits on the account metadata of a hashlist
+const (
+	MaxHashlistAccounts   = 100000
+	MaxAccountGroups      = 256
+	MaxAccountFieldLength = 256
+)
+
+// HashlistAccount is an account of a hashlist with its group memberships, as
+// given by the ingest source
+type HashlistAccount struct {
+	HashlistID int64      `json:"hashlist_id"`
+	Username   string     `json:"username"`
+	Domain     string     `json:"domain"`
+	Groups     []string   `json:"groups"`
+	Privileged bool       `json:"privileged"`           // Set explicitly or by membership of a privileged group
+	CrackedAt  *time.Time `json:"cracked_at,omitempty"` // When the account was flagged as a cracked privileged account
+}
+
+// ParsePrivilegedGroups splits the comma-separated privileged_groups setting
+func ParsePrivilegedGroups(value string) []string {
+	groups := []string{}
+	for _, group := range strings.Split(value, ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// Normalize trims the account, splits a DOMAIN\user or user@domain username
+// and drops duplicate groups. The account is privileged if it was marked so or
+// belongs to one of the privileged groups, compared ignoring case.
+func (a *HashlistAccount) Normalize(privilegedGroups []string) error {
+	a.Username = strings.TrimSpace(a.Username)
+	a.Domain = strings.TrimSpace(a.Domain)
+	if a.Domain == "" {
+		if i := strings.Index(a.Username, `\`); i > 0 {
+			a.Domain, a.Username = a.Username[:i], a.Username[i+1:]
+		} else if i := strings.LastIndex(a.Username, "@"); i > 0 {
+			a.Username, a.Domain = a.Username[:i], a.Username[i+1:]
+		}
+	}
+	if a.Username == "" {
+		return fmt.Errorf("account username is required")
+	}
+	if err := checkAccountField("username", a.Username); err != nil {
+		return err
+	}
+	if err := checkAccountField("domain", a.Domain); err != nil {
+		return err
+	}
+
+	privileged := make(map[string]bool, len(privilegedGroups))
+	for _, group := range privilegedGroups {
+		privileged[strings.ToLower(group)] = true
+	}
+	seen := make(map[string]bool, len(a.Groups))
+	groups := []string{}
+	for _, group := range a.Groups {
+		group = strings.Join(strings.Fields(group), " ")
+		key := strings.ToLower(group)
+		if group == "" || seen[key] {
+			continue
+		}
+		if err := checkAccountField("group", group); err != nil {
+			return err
+		}
+		seen[key] = true
+		groups = append(groups, group)
+		if privileged[key] {
+			a.Privileged = true
+		}
+	}
+	if len(groups) > MaxAccountGroups {
+		return fmt.Errorf("account %s has more than %d groups", a.Username, MaxAccountGroups)
+	}
+	a.Groups = groups
+	return nil
+}
+
+// QualifiedName returns the account as DOMAIN\user, or the username alone
+func (a *HashlistAccount) QualifiedName() string {
+	if a.Domain == "" {
+		return a.Username
+	}
+	return a.Domain + `\` + a.Username
+}
+
+func checkAccountField(field, value string) error {
+	if utf8.RuneCountInString(value) > MaxAccountFieldLength {
+		return fmt.Errorf("account %s exceeds %d characters", field, MaxAccountFieldLength)
+	}
+	if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return fmt.Errorf("account %s contains a control character", field)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrivilegedGroups(t *testing.T) {
+	assert.Equal(t, []string{"Domain Admins", "Backup Operators"}, ParsePrivilegedGroups(" Domain Admins ,, Backup Operators,"))
+	assert.Empty(t, ParsePrivilegedGroups(""))
+	assert.Len(t, ParsePrivilegedGroups(DefaultPrivilegedGroups), 8)
+}
+
+func TestHashlistAccountNormalize(t *testing.T) {
+	privileged := ParsePrivilegedGroups(DefaultPrivilegedGroups)
+
+	account := HashlistAccount{Username: ` CORP\alice `, Groups: []string{"Domain  Users", "domain admins", "Domain Users", ""}}
+	require.NoError(t, account.Normalize(privileged))
+	assert.Equal(t, "alice", account.Username)
+	assert.Equal(t, "CORP", account.Domain)
+	assert.Equal(t, []string{"Domain Users", "domain admins"}, account.Groups)
+	assert.True(t, account.Privileged)
+	assert.Equal(t, `CORP\alice`, account.QualifiedName())
+
+	account = HashlistAccount{Username: "bob@corp.local", Groups: []string{"Domain Users"}}
+	require.NoError(t, account.Normalize(privileged))
+	assert.Equal(t, "bob", account.Username)
+	assert.Equal(t, "corp.local", account.Domain)
+	assert.False(t, account.Privileged)
+
+	// Accounts can be marked privileged without a group
+	account = HashlistAccount{Username: "svc_backup", Privileged: true}
+	require.NoError(t, account.Normalize(privileged))
+	assert.True(t, account.Privileged)
+	assert.Equal(t, "svc_backup", account.QualifiedName())
+
+	assert.Error(t, (&HashlistAccount{Username: "  "}).Normalize(privileged))
+	assert.Error(t, (&HashlistAccount{Username: `CORP\`}).Normalize(privileged))
+	assert.Error(t, (&HashlistAccount{Username: "bell\a"}).Normalize(privileged))
+	assert.Error(t, (&HashlistAccount{Username: strings.Repeat("x", MaxAccountFieldLength+1)}).Normalize(privileged))
+}
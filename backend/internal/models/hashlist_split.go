@@ -0,0 +1,64 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SubListCount returns how many sub-lists a hashlist with the given number of
+// unique hashes is split into, 0 when it stays whole. A maxHashes of 0 or less
+// means no limit.
+func SubListCount(uniqueHashes, maxHashes int) int {
+	if maxHashes <= 0 || uniqueHashes <= maxHashes {
+		return 0
+	}
+	return (uniqueHashes + maxHashes - 1) / maxHashes
+}
+
+// SubListName names a part of a hashlist split into total sub-lists
+func SubListName(name string, part, total int) string {
+	return fmt.Sprintf("%s (part %d of %d)", name, part, total)
+}
+
+// SplitGroupPart is the job running an attack against one sub-list
+type SplitGroupPart struct {
+	JobID                  uuid.UUID          `json:"job_id"`
+	HashlistID             int64              `json:"hashlist_id"`
+	PartNumber             int                `json:"part_number"`
+	Status                 JobExecutionStatus `json:"status"`
+	OverallProgressPercent float64            `json:"overall_progress_percent"`
+	CrackedHashes          int                `json:"cracked_hashes"`
+}
+
+// SplitGroupProgress aggregates the jobs running one attack against all
+// sub-lists of a hashlist, so the attack reads like a single job
+type SplitGroupProgress struct {
+	GroupID                uuid.UUID        `json:"group_id"`
+	Parts                  int              `json:"parts"`
+	CompletedParts         int              `json:"completed_parts"`
+	OverallProgressPercent float64          `json:"overall_progress_percent"`
+	CrackedHashes          int              `json:"cracked_hashes"`
+	Jobs                   []SplitGroupPart `json:"jobs"`
+}
+
+// SummarizeSplitGroup aggregates the jobs of a split group. Every part runs
+// the same keyspace, so overall progress is the mean of the parts' progress.
+func SummarizeSplitGroup(groupID uuid.UUID, parts []SplitGroupPart) *SplitGroupProgress {
+	summary := &SplitGroupProgress{GroupID: groupID, Parts: len(parts), Jobs: parts}
+	if len(parts) == 0 {
+		return summary
+	}
+	var progress float64
+	for _, part := range parts {
+		if part.Status == JobExecutionStatusCompleted {
+			summary.CompletedParts++
+			progress += 100
+		} else {
+			progress += part.OverallProgressPercent
+		}
+		summary.CrackedHashes += part.CrackedHashes
+	}
+	summary.OverallProgressPercent = progress / float64(len(parts))
+	return summary
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubListCount(t *testing.T) {
+	assert.Equal(t, 0, SubListCount(1000, 0))
+	assert.Equal(t, 0, SubListCount(1000, 1000))
+	assert.Equal(t, 2, SubListCount(1001, 1000))
+	assert.Equal(t, 3, SubListCount(3000, 1000))
+	assert.Equal(t, 0, SubListCount(0, 10))
+}
+
+func TestSubListName(t *testing.T) {
+	assert.Equal(t, "breach (part 2 of 3)", SubListName("breach", 2, 3))
+}
+
+func TestSummarizeSplitGroup(t *testing.T) {
+	groupID := uuid.New()
+	summary := SummarizeSplitGroup(groupID, []SplitGroupPart{
+		{PartNumber: 1, Status: JobExecutionStatusCompleted, OverallProgressPercent: 99.5, CrackedHashes: 10},
+		{PartNumber: 2, Status: JobExecutionStatusRunning, OverallProgressPercent: 50, CrackedHashes: 4},
+		{PartNumber: 3, Status: JobExecutionStatusPending},
+		{PartNumber: 4, Status: JobExecutionStatusPending},
+	})
+	assert.Equal(t, groupID, summary.GroupID)
+	assert.Equal(t, 4, summary.Parts)
+	assert.Equal(t, 1, summary.CompletedParts)
+	// A completed part counts as fully searched
+	assert.InDelta(t, 37.5, summary.OverallProgressPercent, 0.001)
+	assert.Equal(t, 14, summary.CrackedHashes)
+
+	empty := SummarizeSplitGroup(groupID, nil)
+	assert.Equal(t, 0, empty.Parts)
+	assert.Zero(t, empty.OverallProgressPercent)
+}
@@ -0,0 +1,42 @@
+package models
+
+// MaxStagingInvalidSamples caps the invalid lines kept in a staging preview
+const MaxStagingInvalidSamples = 20
+
+// HashlistStagingPreview is what loading a staged upload would do, so junk
+// uploads can be discarded before their hashes reach the job pipeline
+type HashlistStagingPreview struct {
+	// Lines counts the non-empty, non-comment lines
+	Lines        int `json:"lines"`
+	ValidLines   int `json:"valid_lines"`
+	InvalidLines int `json:"invalid_lines"`
+	// DuplicateLines are valid lines whose hash already appeared earlier in the upload
+	DuplicateLines int `json:"duplicate_lines"`
+	UniqueHashes   int `json:"unique_hashes"`
+
+	// DetectedTypes groups the lines by detected hash type, largest group first
+	DetectedTypes []HashlistStagingType `json:"detected_types"`
+	// TypeMismatch is set when most lines look like a different hash type
+	// than the one chosen for the upload
+	TypeMismatch bool `json:"type_mismatch"`
+
+	// InvalidSamples are the first invalid lines and why they would be quarantined
+	InvalidSamples []HashlistStagingLine `json:"invalid_samples"`
+}
+
+// HashlistStagingType counts the lines of a staged upload that look like one hash type
+type HashlistStagingType struct {
+	HashTypeID   int    `json:"hash_type_id"`
+	HashTypeName string `json:"hash_type_name,omitempty"`
+	Lines        int    `json:"lines"`
+	// Detected is false for lines without a recognizable signature, which
+	// count towards the chosen hash type
+	Detected bool `json:"detected"`
+}
+
+// HashlistStagingLine is an invalid line of a staged upload
+type HashlistStagingLine struct {
+	LineNumber int    `json:"line_number"`
+	Line       string `json:"line"`
+	Reason     string `json:"reason"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "math"
+
+// Sources of the sides of a hybrid attack
+const (
+	HybridSourceWordlist = "wordlist"
+	HybridSourceMask     = "mask"
+)
+
+// HybridKeyspaceSide is one side of the candidates of a hybrid attack
+type HybridKeyspaceSide struct {
+	Source   string `json:"source"`   // wordlist or mask
+	Keyspace int64  `json:"keyspace"` // Words in the wordlist or candidates of the mask
+}
+
+// HybridKeyspace breaks the keyspace of a hybrid attack down into its sides.
+// Every word is combined with every mask candidate, the mask on the right
+// for -a 6 and on the left for -a 7, but hashcat's --keyspace only counts one
+// side, the other multiplies each keyspace unit.
+type HybridKeyspace struct {
+	Left       HybridKeyspaceSide `json:"left"`
+	Right      HybridKeyspaceSide `json:"right"`
+	Candidates int64              `json:"candidates"` // Total candidates, left × right
+}
+
+// NewHybridKeyspace returns the breakdown of a hybrid attack, or nil for any
+// other attack mode, an empty side or more candidates than fit an int64
+func NewHybridKeyspace(attackMode AttackMode, wordlistKeyspace, maskKeyspace int64) *HybridKeyspace {
+	if wordlistKeyspace <= 0 || maskKeyspace <= 0 || maskKeyspace > math.MaxInt64/wordlistKeyspace {
+		return nil
+	}
+	wordlist := HybridKeyspaceSide{Source: HybridSourceWordlist, Keyspace: wordlistKeyspace}
+	mask := HybridKeyspaceSide{Source: HybridSourceMask, Keyspace: maskKeyspace}
+
+	h := &HybridKeyspace{Candidates: wordlistKeyspace * maskKeyspace}
+	switch attackMode {
+	case AttackModeHybridWordlistMask:
+		h.Left, h.Right = wordlist, mask
+	case AttackModeHybridMaskWordlist:
+		h.Left, h.Right = mask, wordlist
+	default:
+		return nil
+	}
+	return h
+}
+
+// CandidatesPerUnit returns how many candidates hashcat tries per unit of
+// the job's total keyspace, the keyspace hashcat reported. hashcat counts the
+// words of the wordlist, which can be fewer than its lines, and tries each
+// with every mask candidate; a total equal to the mask's means it counted the
+// mask instead.
+func (h *HybridKeyspace) CandidatesPerUnit(totalKeyspace int64) int64 {
+	if h == nil {
+		return 1
+	}
+	if totalKeyspace == h.MaskKeyspace() {
+		return h.WordlistKeyspace()
+	}
+	return h.MaskKeyspace()
+}
+
+// WordlistKeyspace returns the number of words of the wordlist side
+func (h *HybridKeyspace) WordlistKeyspace() int64 {
+	return h.side(HybridSourceWordlist)
+}
+
+// MaskKeyspace returns the number of candidates of the mask side
+func (h *HybridKeyspace) MaskKeyspace() int64 {
+	return h.side(HybridSourceMask)
+}
+
+// side returns the keyspace of the side with the given source
+func (h *HybridKeyspace) side(source string) int64 {
+	if h.Left.Source == source {
+		return h.Left.Keyspace
+	}
+	return h.Right.Keyspace
+}
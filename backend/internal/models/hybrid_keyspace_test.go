@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHybridKeyspace(t *testing.T) {
+	h := NewHybridKeyspace(AttackModeHybridWordlistMask, 1000, 100)
+	require.NotNil(t, h)
+	assert.Equal(t, HybridSourceWordlist, h.Left.Source)
+	assert.Equal(t, HybridSourceMask, h.Right.Source)
+	assert.Equal(t, int64(100000), h.Candidates)
+
+	h = NewHybridKeyspace(AttackModeHybridMaskWordlist, 1000, 100)
+	require.NotNil(t, h)
+	assert.Equal(t, HybridSourceMask, h.Left.Source)
+	assert.Equal(t, int64(100), h.Left.Keyspace)
+	assert.Equal(t, HybridSourceWordlist, h.Right.Source)
+
+	assert.Nil(t, NewHybridKeyspace(AttackModeStraight, 1000, 100))
+	assert.Nil(t, NewHybridKeyspace(AttackModeHybridWordlistMask, 0, 100))
+}
+
+func TestHybridKeyspaceCandidatesPerUnit(t *testing.T) {
+	h := NewHybridKeyspace(AttackModeHybridWordlistMask, 1000, 100)
+
+	assert.Equal(t, int64(100), h.CandidatesPerUnit(1000), "hashcat counted the words")
+	assert.Equal(t, int64(100), h.CandidatesPerUnit(990), "hashcat skipped some lines of the wordlist")
+	assert.Equal(t, int64(1000), h.CandidatesPerUnit(100), "hashcat counted the mask")
+
+	var none *HybridKeyspace
+	assert.Equal(t, int64(1), none.CandidatesPerUnit(1000))
+}
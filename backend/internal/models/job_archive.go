@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobExecutionArchive is the queryable summary of an archived job execution.
+// The full execution, its tasks and job metrics are kept in a compressed
+// payload that is only decoded on restore.
+type JobExecutionArchive struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	Name              string     `json:"name" db:"name"`
+	HashlistID        *int64     `json:"hashlist_id" db:"hashlist_id"`
+	Status            string     `json:"status" db:"status"`
+	AttackMode        *int       `json:"attack_mode" db:"attack_mode"`
+	HashType          *int       `json:"hash_type" db:"hash_type"`
+	Priority          *int       `json:"priority" db:"priority"`
+	CreatedBy         *uuid.UUID `json:"created_by" db:"created_by"`
+	TotalKeyspace     *int64     `json:"total_keyspace" db:"total_keyspace"`
+	ProcessedKeyspace *int64     `json:"processed_keyspace" db:"processed_keyspace"`
+	TaskCount         int        `json:"task_count" db:"task_count"`
+	CrackCount        int        `json:"crack_count" db:"crack_count"`
+	CreatedAt         *time.Time `json:"created_at" db:"created_at"`
+	StartedAt         *time.Time `json:"started_at" db:"started_at"`
+	CompletedAt       *time.Time `json:"completed_at" db:"completed_at"`
+	PayloadSizeBytes  int64      `json:"payload_size_bytes" db:"payload_size_bytes"`
+	ArchivedAt        time.Time  `json:"archived_at" db:"archived_at"`
+	ArchivedBy        *uuid.UUID `json:"archived_by" db:"archived_by"`
+}
+
+// JobArchiveStats summarizes the archive table for reporting.
+type JobArchiveStats struct {
+	ArchivedJobs      int   `json:"archived_jobs"`
+	ArchivedTasks     int   `json:"archived_tasks"`
+	TotalCracks       int   `json:"total_cracks"`
+	ProcessedKeyspace int64 `json:"processed_keyspace"`
+	PayloadSizeBytes  int64 `json:"payload_size_bytes"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// JobCampaign is one job submission run against several hashlists of the same
+// hash type. Each hashlist gets its own jobs, the campaign links them.
+type JobCampaign struct {
+	ID          uuid.UUID     `json:"id"`
+	Name        string        `json:"name"`
+	HashTypeID  int           `json:"hash_type_id"`
+	HashlistIDs pq.Int64Array `json:"hashlist_ids"`
+	CreatedBy   *uuid.UUID    `json:"created_by,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// CampaignName names a campaign after the job name of its attacks
+func CampaignName(jobName string, hashlists int) string {
+	if jobName == "" {
+		return fmt.Sprintf("Campaign on %d hashlists", hashlists)
+	}
+	return fmt.Sprintf("%s (%d hashlists)", jobName, hashlists)
+}
+
+// CampaignJob is one of the jobs of a campaign
+type CampaignJob struct {
+	JobID                  uuid.UUID          `json:"job_id"`
+	Name                   string             `json:"name"`
+	HashlistID             int64              `json:"hashlist_id"`
+	Status                 JobExecutionStatus `json:"status"`
+	OverallProgressPercent float64            `json:"overall_progress_percent"`
+	CrackedHashes          int                `json:"cracked_hashes"` // Cracked by this job
+}
+
+// CampaignHashlist is one of the hashlists a campaign runs against
+type CampaignHashlist struct {
+	HashlistID    int64  `json:"hashlist_id"`
+	Name          string `json:"name"`
+	TotalHashes   int    `json:"total_hashes"`
+	CrackedHashes int    `json:"cracked_hashes"` // Cracked by any job, not only the campaign's
+}
+
+// CampaignProgress aggregates the jobs and hashlists of a campaign so it
+// reads like a single job
+type CampaignProgress struct {
+	JobCampaign
+	CompletedJobs          int                `json:"completed_jobs"`
+	OverallProgressPercent float64            `json:"overall_progress_percent"`
+	CampaignCrackedHashes  int                `json:"campaign_cracked_hashes"` // Cracked by the campaign's jobs
+	TotalHashes            int                `json:"total_hashes"`            // Sum over the hashlists
+	CrackedHashes          int                `json:"cracked_hashes"`          // Sum over the hashlists
+	Jobs                   []CampaignJob      `json:"jobs"`
+	Hashlists              []CampaignHashlist `json:"hashlists"`
+}
+
+// SummarizeCampaign aggregates the jobs and hashlists of a campaign. Overall
+// progress is the mean of the jobs' progress, a completed job counting as
+// fully searched.
+func SummarizeCampaign(campaign JobCampaign, jobs []CampaignJob, hashlists []CampaignHashlist) *CampaignProgress {
+	summary := &CampaignProgress{JobCampaign: campaign, Jobs: jobs, Hashlists: hashlists}
+	if summary.Jobs == nil {
+		summary.Jobs = []CampaignJob{}
+	}
+	if summary.Hashlists == nil {
+		summary.Hashlists = []CampaignHashlist{}
+	}
+
+	var progress float64
+	for _, job := range jobs {
+		if job.Status == JobExecutionStatusCompleted {
+			summary.CompletedJobs++
+			progress += 100
+		} else {
+			progress += job.OverallProgressPercent
+		}
+		summary.CampaignCrackedHashes += job.CrackedHashes
+	}
+	if len(jobs) > 0 {
+		summary.OverallProgressPercent = progress / float64(len(jobs))
+	}
+
+	for _, hashlist := range hashlists {
+		summary.TotalHashes += hashlist.TotalHashes
+		summary.CrackedHashes += hashlist.CrackedHashes
+	}
+	return summary
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCampaignName(t *testing.T) {
+	assert.Equal(t, "rockyou (3 hashlists)", CampaignName("rockyou", 3))
+	assert.Equal(t, "Campaign on 2 hashlists", CampaignName("", 2))
+}
+
+func TestSummarizeCampaign(t *testing.T) {
+	campaign := JobCampaign{ID: uuid.New(), Name: "rockyou", HashTypeID: 1000}
+	summary := SummarizeCampaign(campaign, []CampaignJob{
+		{HashlistID: 1, Status: JobExecutionStatusCompleted, OverallProgressPercent: 99, CrackedHashes: 7},
+		{HashlistID: 2, Status: JobExecutionStatusRunning, OverallProgressPercent: 40, CrackedHashes: 3},
+		{HashlistID: 3, Status: JobExecutionStatusPending},
+	}, []CampaignHashlist{
+		{HashlistID: 1, TotalHashes: 100, CrackedHashes: 20},
+		{HashlistID: 2, TotalHashes: 50, CrackedHashes: 3},
+		{HashlistID: 3, TotalHashes: 10},
+	})
+
+	assert.Equal(t, campaign.ID, summary.ID)
+	assert.Equal(t, 1, summary.CompletedJobs)
+	// A completed job counts as fully searched
+	assert.InDelta(t, 46.667, summary.OverallProgressPercent, 0.001)
+	assert.Equal(t, 10, summary.CampaignCrackedHashes)
+	assert.Equal(t, 160, summary.TotalHashes)
+	assert.Equal(t, 23, summary.CrackedHashes)
+
+	empty := SummarizeCampaign(campaign, nil, nil)
+	assert.Zero(t, empty.OverallProgressPercent)
+	assert.NotNil(t, empty.Jobs)
+	assert.NotNil(t, empty.Hashlists)
+}
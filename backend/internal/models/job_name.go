@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// JobNameTemplateSetting is the system setting holding the template of
+// generated job names
+const JobNameTemplateSetting = "job_name_template"
+
+// DefaultJobNameTemplate names jobs client-hashlist-attack-sequence
+const DefaultJobNameTemplate = "{client}-{hashlist}-{attack}-{seq}"
+
+// Limits on job names and their template
+const (
+	MaxJobNameLength         = 255
+	MaxJobNameTemplateLength = 200
+)
+
+// jobNamePlaceholder matches a {placeholder} of a job name template
+var jobNamePlaceholder = regexp.MustCompile(`\{([a-z_]*)\}`)
+
+// attackModeNames are the {attack} of custom jobs, which have no preset name
+var attackModeNames = map[AttackMode]string{
+	AttackModeStraight:           "dictionary",
+	AttackModeCombination:        "combination",
+	AttackModeBruteForce:         "mask",
+	AttackModeHybridWordlistMask: "hybrid-wordlist-mask",
+	AttackModeHybridMaskWordlist: "hybrid-mask-wordlist",
+	AttackModeAssociation:        "association",
+}
+
+// AttackModeName returns a short name of the attack mode
+func AttackModeName(mode AttackMode) string {
+	if name, ok := attackModeNames[mode]; ok {
+		return name
+	}
+	return "mode" + strconv.Itoa(int(mode))
+}
+
+// JobNameFields are the values of the job name template placeholders
+type JobNameFields struct {
+	Client   string    // {client}, "Unknown" for hashlists without a client
+	Hashlist string    // {hashlist}
+	Attack   string    // {attack}, the preset job name or the attack mode
+	HashType int       // {hash_type}
+	Sequence int       // {seq}, counts the jobs of the client
+	Created  time.Time // {date}, as YYYY-MM-DD
+}
+
+func (f JobNameFields) value(placeholder string) (string, bool) {
+	switch placeholder {
+	case "client":
+		if f.Client == "" {
+			return "Unknown", true
+		}
+		return f.Client, true
+	case "hashlist":
+		return f.Hashlist, true
+	case "attack":
+		return f.Attack, true
+	case "hash_type":
+		return strconv.Itoa(f.HashType), true
+	case "seq":
+		return strconv.Itoa(f.Sequence), true
+	case "date":
+		return f.Created.Format("2006-01-02"), true
+	}
+	return "", false
+}
+
+// ValidateJobNameTemplate checks a template only uses known placeholders
+func ValidateJobNameTemplate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return fmt.Errorf("job name template is empty")
+	}
+	if utf8.RuneCountInString(template) > MaxJobNameTemplateLength {
+		return fmt.Errorf("job name template exceeds %d characters", MaxJobNameTemplateLength)
+	}
+	for _, match := range jobNamePlaceholder.FindAllStringSubmatch(template, -1) {
+		if _, ok := (JobNameFields{}).value(match[1]); !ok {
+			return fmt.Errorf("unknown placeholder %s, use {client}, {hashlist}, {attack}, {hash_type}, {seq} or {date}", match[0])
+		}
+	}
+	return nil
+}
+
+// UsesJobSequence reports whether the template numbers jobs with {seq}
+func UsesJobSequence(template string) bool {
+	return strings.Contains(template, "{seq}")
+}
+
+// RenderJobName fills in a job name template. An invalid template falls back
+// to DefaultJobNameTemplate.
+func RenderJobName(template string, fields JobNameFields) string {
+	if ValidateJobNameTemplate(template) != nil {
+		template = DefaultJobNameTemplate
+	}
+	name := jobNamePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		value, _ := fields.value(placeholder[1 : len(placeholder)-1])
+		return value
+	})
+	return truncateJobName(collapseJobName(name))
+}
+
+// NormalizeJobName trims and collapses the whitespace of a job name given
+// by a user, rejecting empty names, control characters and names over the limit
+func NormalizeJobName(name string) (string, error) {
+	name = collapseJobName(name)
+	if name == "" {
+		return "", fmt.Errorf("job name is required")
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return "", fmt.Errorf("job name contains a control character")
+	}
+	if utf8.RuneCountInString(name) > MaxJobNameLength {
+		return "", fmt.Errorf("job name exceeds %d characters", MaxJobNameLength)
+	}
+	return name, nil
+}
+
+// DisambiguateJobName returns the name with a numeric suffix, used when a
+// name is already taken within its client
+func DisambiguateJobName(name string, n int) string {
+	suffix := fmt.Sprintf(" (%d)", n)
+	return truncateJobName(name, utf8.RuneCountInString(suffix)) + suffix
+}
+
+func collapseJobName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// truncateJobName shortens a name to fit MaxJobNameLength, leaving room for
+// the given number of characters
+func truncateJobName(name string, reserve ...int) string {
+	limit := MaxJobNameLength
+	for _, r := range reserve {
+		limit -= r
+	}
+	if utf8.RuneCountInString(name) <= limit {
+		return name
+	}
+	return strings.TrimSpace(string([]rune(name)[:limit]))
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderJobName(t *testing.T) {
+	fields := JobNameFields{
+		Client:   "Acme Corp",
+		Hashlist: "ntds  dump",
+		Attack:   "rockyou + best64",
+		HashType: 1000,
+		Sequence: 12,
+		Created:  time.Date(2024, 6, 10, 15, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, "Acme Corp-ntds dump-rockyou + best64-12", RenderJobName(DefaultJobNameTemplate, fields))
+	assert.Equal(t, "2024-06-10 1000 #12", RenderJobName("{date} {hash_type} #{seq}", fields))
+	assert.Equal(t, "Unknown-ntds dump", RenderJobName("{client}-{hashlist}", JobNameFields{Hashlist: "ntds dump"}))
+
+	// An invalid template falls back to the default
+	assert.Equal(t, RenderJobName(DefaultJobNameTemplate, fields), RenderJobName("{customer}-{seq}", fields))
+
+	long := RenderJobName("{hashlist}", JobNameFields{Hashlist: strings.Repeat("x", 300)})
+	assert.Equal(t, MaxJobNameLength, utf8.RuneCountInString(long))
+}
+
+func TestValidateJobNameTemplate(t *testing.T) {
+	assert.NoError(t, ValidateJobNameTemplate(DefaultJobNameTemplate))
+	assert.NoError(t, ValidateJobNameTemplate("{client}/{date}/{seq}"))
+
+	assert.Error(t, ValidateJobNameTemplate(" "))
+	assert.Error(t, ValidateJobNameTemplate("{client}-{customer}"))
+	assert.Error(t, ValidateJobNameTemplate("{user}-{seq}"))
+	assert.Error(t, ValidateJobNameTemplate("{}"))
+	assert.Error(t, ValidateJobNameTemplate(strings.Repeat("x", MaxJobNameTemplateLength+1)))
+}
+
+func TestNormalizeJobName(t *testing.T) {
+	name, err := NormalizeJobName("  DA  accounts\tround 2 ")
+	require.NoError(t, err)
+	assert.Equal(t, "DA accounts round 2", name)
+
+	_, err = NormalizeJobName("   ")
+	assert.Error(t, err)
+	_, err = NormalizeJobName(strings.Repeat("x", MaxJobNameLength+1))
+	assert.Error(t, err)
+	_, err = NormalizeJobName("bell\a")
+	assert.Error(t, err)
+}
+
+func TestDisambiguateJobName(t *testing.T) {
+	assert.Equal(t, "Acme-ntds-rockyou (2)", DisambiguateJobName("Acme-ntds-rockyou", 2))
+
+	long := DisambiguateJobName(strings.Repeat("x", MaxJobNameLength), 3)
+	assert.Equal(t, MaxJobNameLength, utf8.RuneCountInString(long))
+	assert.True(t, strings.HasSuffix(long, " (3)"))
+}
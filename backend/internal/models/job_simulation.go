@@ -0,0 +1,64 @@
+package models
+
+// JobSimulationRequest describes a hypothetical job and the agents that would
+// run it. Nothing is created, the simulation only predicts how the scheduler
+// would chunk and distribute the work.
+type JobSimulationRequest struct {
+	HashType  int   `json:"hash_type"`
+	HashCount int64 `json:"hash_count"`
+	// Salted hash types are cracked once per salt, so their speed is divided
+	// by the hash count
+	Salted     bool       `json:"salted"`
+	AttackMode AttackMode `json:"attack_mode"`
+	// Wordlists and rules are existing files by ID or hypothetical ones by
+	// word and rule count, IDs come first
+	WordlistIDs   []int   `json:"wordlist_ids,omitempty"`
+	WordlistSizes []int64 `json:"wordlist_sizes,omitempty"`
+	RuleIDs       []int   `json:"rule_ids,omitempty"`
+	RuleCounts    []int64 `json:"rule_counts,omitempty"`
+	Mask          string  `json:"mask,omitempty"`
+	// Keyspace overrides the keyspace calculated from the attack
+	Keyspace int64 `json:"keyspace,omitempty"`
+	// ChunkDurationSeconds defaults to the default_chunk_duration setting
+	ChunkDurationSeconds int              `json:"chunk_duration_seconds,omitempty"`
+	Agents               []SimulatedAgent `json:"agents"`
+}
+
+// SimulatedAgent is an existing agent by ID or a hypothetical one by speed
+type SimulatedAgent struct {
+	AgentID *int   `json:"agent_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// Speed in hashes per second against one hash. An existing agent without
+	// a speed uses its benchmark for the hash type and attack mode.
+	Speed int64 `json:"speed,omitempty"`
+}
+
+// Speed sources of a simulated agent
+const (
+	SimulatedSpeedRequest   = "request"
+	SimulatedSpeedBenchmark = "benchmark"
+)
+
+// JobSimulationResult is the predicted runtime and workload of a simulated job
+type JobSimulationResult struct {
+	Keyspace             int64                    `json:"keyspace"`
+	ChunkDurationSeconds int                      `json:"chunk_duration_seconds"`
+	ChunkCount           int64                    `json:"chunk_count"`
+	RuntimeSeconds       float64                  `json:"runtime_seconds"`
+	AgentSeconds         float64                  `json:"agent_seconds"`
+	Agents               []SimulatedAgentWorkload `json:"agents"`
+}
+
+// SimulatedAgentWorkload is the share of a simulated job one agent would run
+type SimulatedAgentWorkload struct {
+	AgentID     *int   `json:"agent_id,omitempty"`
+	Name        string `json:"name"`
+	Speed       int64  `json:"speed"`
+	SpeedSource string `json:"speed_source"`
+	// EffectiveSpeed is the keyspace the agent processes per second
+	EffectiveSpeed int64   `json:"effective_speed"`
+	Chunks         int64   `json:"chunks"`
+	Keyspace       int64   `json:"keyspace"`
+	BusySeconds    float64 `json:"busy_seconds"`
+	SharePercent   float64 `json:"share_percent"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStartCondition holds a job back in the scheduled status until its start
+// time has passed and the job it depends on has completed. Both empty starts
+// the job at once.
+type JobStartCondition struct {
+	StartAt        *time.Time `json:"start_at,omitempty" db:"start_at"`
+	DependsOnJobID *uuid.UUID `json:"depends_on_job_id,omitempty" db:"depends_on_job_id"`
+}
+
+// Held reports whether a new job with this condition must wait at now. A
+// start time in the past does not hold a job back.
+func (c JobStartCondition) Held(now time.Time) bool {
+	return c.DependsOnJobID != nil || (c.StartAt != nil && c.StartAt.After(now))
+}
+
+// Validate checks that a job may depend on the given job, which must not have
+// failed or been cancelled already. dependency is nil without a dependency.
+func (c JobStartCondition) Validate(dependency *JobExecution) error {
+	if c.DependsOnJobID == nil {
+		return nil
+	}
+	if dependency == nil {
+		return fmt.Errorf("depends_on_job_id: job %s not found", *c.DependsOnJobID)
+	}
+	switch dependency.Status {
+	case JobExecutionStatusFailed, JobExecutionStatusCancelled:
+		return fmt.Errorf("depends_on_job_id: job %s already %s", dependency.ID, dependency.Status)
+	}
+	return nil
+}
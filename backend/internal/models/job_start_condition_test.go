@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobStartConditionHeld(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+	dependency := uuid.New()
+
+	assert.False(t, JobStartCondition{}.Held(now))
+	assert.True(t, JobStartCondition{StartAt: &later}.Held(now))
+	assert.False(t, JobStartCondition{StartAt: &earlier}.Held(now))
+	assert.True(t, JobStartCondition{StartAt: &earlier, DependsOnJobID: &dependency}.Held(now))
+}
+
+func TestJobStartConditionValidate(t *testing.T) {
+	dependencyID := uuid.New()
+	condition := JobStartCondition{DependsOnJobID: &dependencyID}
+
+	assert.NoError(t, JobStartCondition{}.Validate(nil))
+	assert.Error(t, condition.Validate(nil))
+	assert.NoError(t, condition.Validate(&JobExecution{ID: dependencyID, Status: JobExecutionStatusRunning}))
+	assert.NoError(t, condition.Validate(&JobExecution{ID: dependencyID, Status: JobExecutionStatusCompleted}))
+	assert.Error(t, condition.Validate(&JobExecution{ID: dependencyID, Status: JobExecutionStatusFailed}))
+	assert.Error(t, condition.Validate(&JobExecution{ID: dependencyID, Status: JobExecutionStatusCancelled}))
+}
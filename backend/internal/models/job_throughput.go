@@ -0,0 +1,65 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Periods the throughput history can be bucketed by
+const (
+	ThroughputBucketDay   = "day"
+	ThroughputBucketWeek  = "week"
+	ThroughputBucketMonth = "month"
+)
+
+// JobThroughput summarizes the completed jobs of a hash type, per attack mode
+// and period when grouped by them, so teams can quote realistic turnaround
+// times during scoping. Speeds are in hashes per second.
+type JobThroughput struct {
+	HashType     int        `json:"hash_type"`
+	HashTypeName string     `json:"hash_type_name"`
+	AttackMode   *int       `json:"attack_mode,omitempty"`
+	Period       *time.Time `json:"period,omitempty"`
+	Jobs         int        `json:"jobs"`
+	Keyspace     float64    `json:"keyspace"`
+	WallSeconds  int64      `json:"wall_seconds"`
+	TaskSeconds  int64      `json:"task_seconds"`
+	AvgHashes    float64    `json:"avg_hashes"`
+	AvgAgents    float64    `json:"avg_agents"`
+	// Candidates per second of wall time over all the jobs, what a new job
+	// of the hash type can expect from the cluster
+	AggregateSpeed float64 `json:"aggregate_speed"`
+	// Median of the jobs' own aggregate speeds, less swayed by a few big jobs
+	MedianJobSpeed float64 `json:"median_job_speed"`
+	// Candidates per second of a single agent running the hash type
+	AgentSpeed       float64   `json:"agent_speed"`
+	FirstCompletedAt time.Time `json:"first_completed_at"`
+	LastCompletedAt  time.Time `json:"last_completed_at"`
+	// Time a job of the requested keyspace would take at AggregateSpeed
+	EstimatedSeconds *int64 `json:"estimated_seconds,omitempty"`
+}
+
+// IsValidThroughputBucket reports whether a throughput history can be bucketed by the period
+func IsValidThroughputBucket(bucket string) bool {
+	switch bucket {
+	case ThroughputBucketDay, ThroughputBucketWeek, ThroughputBucketMonth:
+		return true
+	}
+	return false
+}
+
+// CalculateSpeeds derives the speeds from the totals, and the estimate for a
+// job of the given keyspace when it is above 0
+func (t *JobThroughput) CalculateSpeeds(estimateKeyspace int64) {
+	t.AggregateSpeed, t.AgentSpeed, t.EstimatedSeconds = 0, 0, nil
+	if t.WallSeconds > 0 {
+		t.AggregateSpeed = t.Keyspace / float64(t.WallSeconds)
+	}
+	if t.TaskSeconds > 0 {
+		t.AgentSpeed = t.Keyspace / float64(t.TaskSeconds)
+	}
+	if estimateKeyspace > 0 && t.AggregateSpeed > 0 {
+		seconds := int64(math.Ceil(float64(estimateKeyspace) / t.AggregateSpeed))
+		t.EstimatedSeconds = &seconds
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobThroughputCalculateSpeeds(t *testing.T) {
+	// Two NTLM jobs: 3.6e15 candidates in 2 hours of wall time on 4 agents
+	throughput := JobThroughput{Jobs: 2, Keyspace: 3.6e15, WallSeconds: 7200, TaskSeconds: 28800}
+
+	throughput.CalculateSpeeds(1e15)
+	assert.Equal(t, 5e11, throughput.AggregateSpeed)
+	assert.Equal(t, 1.25e11, throughput.AgentSpeed)
+	require.NotNil(t, throughput.EstimatedSeconds)
+	assert.Equal(t, int64(2000), *throughput.EstimatedSeconds)
+
+	// No estimate without a keyspace or a speed to divide by
+	throughput.CalculateSpeeds(0)
+	assert.Nil(t, throughput.EstimatedSeconds)
+
+	empty := JobThroughput{}
+	empty.CalculateSpeeds(1e15)
+	assert.Zero(t, empty.AggregateSpeed)
+	assert.Nil(t, empty.EstimatedSeconds)
+}
+
+func TestIsValidThroughputBucket(t *testing.T) {
+	assert.True(t, IsValidThroughputBucket(ThroughputBucketWeek))
+	assert.False(t, IsValidThroughputBucket("year"))
+	assert.False(t, IsValidThroughputBucket(""))
+}
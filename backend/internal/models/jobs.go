@@ -1,9 +1,13 @@
 package models
 
 import (
+	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -50,26 +54,51 @@ func (a *IDArray) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, a)
 }
 
+// ComputeAttackFingerprint returns the canonical fingerprint of an attack: a
+// SHA-256 over everything that determines which candidates hashcat will try.
+// Wordlist and rule order is preserved since it changes the attack (combinator
+// sides, stacked rule files). Must stay in sync with migration 000074.
+func ComputeAttackFingerprint(attackMode AttackMode, hashType int, wordlistIDs, ruleIDs IDArray, mask string, additionalArgs *string, binaryVersionID int) string {
+	args := ""
+	if additionalArgs != nil {
+		args = strings.Join(strings.Fields(*additionalArgs), " ")
+	}
+	canonical := strings.Join([]string{
+		strconv.Itoa(int(attackMode)),
+		strconv.Itoa(hashType),
+		strings.Join(wordlistIDs, ","),
+		strings.Join(ruleIDs, ","),
+		mask,
+		args,
+		strconv.Itoa(binaryVersionID),
+	}, "|")
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
+
 // PresetJob mirrors the preset_jobs table structure.
 // It defines a pre-configured set of parameters for a cracking job.
 type PresetJob struct {
-	ID                        uuid.UUID  `json:"id" db:"id"`
-	Name                      string     `json:"name" db:"name"`
-	WordlistIDs               IDArray    `json:"wordlist_ids" db:"wordlist_ids"` // Stores numeric IDs as strings in JSONB
-	RuleIDs                   IDArray    `json:"rule_ids" db:"rule_ids"`         // Stores numeric IDs as strings in JSONB
-	AttackMode                AttackMode `json:"attack_mode" db:"attack_mode"`
-	HashType                  int        `json:"hash_type" db:"hash_type"` // Hashcat hash type number
-	Priority                  int        `json:"priority" db:"priority"`
-	ChunkSizeSeconds          int        `json:"chunk_size_seconds" db:"chunk_size_seconds"`
-	StatusUpdatesEnabled      bool       `json:"status_updates_enabled" db:"status_updates_enabled"`
-	AllowHighPriorityOverride bool       `json:"allow_high_priority_override" db:"allow_high_priority_override"`
-	BinaryVersionID           int        `json:"binary_version_id" db:"binary_version_id"`       // References binary_versions.id
-	Mask                      string     `json:"mask,omitempty" db:"mask"`                       // For mask-based attack modes
-	AdditionalArgs            *string    `json:"additional_args,omitempty" db:"additional_args"` // Additional hashcat arguments
-	Keyspace                  *int64     `json:"keyspace,omitempty" db:"keyspace"`               // Pre-calculated keyspace for this preset
-	MaxAgents                 int        `json:"max_agents" db:"max_agents"`                     // Max agents allowed (0 = unlimited)
-	CreatedAt                 time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt                 time.Time  `json:"updated_at" db:"updated_at"`
+	ID                        uuid.UUID      `json:"id" db:"id"`
+	Name                      string         `json:"name" db:"name"`
+	WordlistIDs               IDArray        `json:"wordlist_ids" db:"wordlist_ids"` // Stores numeric IDs as strings in JSONB
+	WordlistCollectionID      *int           `json:"wordlist_collection_id,omitempty" db:"wordlist_collection_id"` // Expanded into one job per wordlist, used instead of WordlistIDs
+	RuleIDs                   IDArray        `json:"rule_ids" db:"rule_ids"`         // Stores numeric IDs as strings in JSONB
+	AttackMode                AttackMode     `json:"attack_mode" db:"attack_mode"`
+	HashType                  int            `json:"hash_type" db:"hash_type"` // Hashcat hash type number
+	Priority                  int            `json:"priority" db:"priority"`
+	ChunkSizeSeconds          int            `json:"chunk_size_seconds" db:"chunk_size_seconds"`
+	StatusUpdatesEnabled      bool           `json:"status_updates_enabled" db:"status_updates_enabled"`
+	AllowHighPriorityOverride bool           `json:"allow_high_priority_override" db:"allow_high_priority_override"`
+	BinaryVersionID           int            `json:"binary_version_id" db:"binary_version_id"`       // References binary_versions.id
+	Mask                      string         `json:"mask,omitempty" db:"mask"`                       // For mask-based attack modes
+	MaskIncrement             *MaskIncrement `json:"mask_increment,omitempty" db:"mask_increment"`   // --increment bounds of a brute force mask
+	AdditionalArgs            *string        `json:"additional_args,omitempty" db:"additional_args"` // Additional hashcat arguments
+	Keyspace                  *int64         `json:"keyspace,omitempty" db:"keyspace"`               // Pre-calculated keyspace for this preset
+	MaxAgents                 int            `json:"max_agents" db:"max_agents"`                     // Max agents allowed (0 = unlimited)
+	NeedsReview               bool           `json:"needs_review" db:"needs_review"`                 // A wordlist or rule was force-deleted from the preset
+	CreatedAt                 time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt                 time.Time      `json:"updated_at" db:"updated_at"`
 
 	// Fields potentially populated by JOINs in specific queries
 	BinaryVersionName string `json:"binary_version_name,omitempty" db:"binary_version_name"` // Example: Populated when listing
@@ -83,10 +112,22 @@ type JobWorkflow struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 
+	// How follow-up steps inherit the priority of the step before them
+	WorkflowPriorityInheritance
+
 	// Populated field holding the ordered steps
 	Steps []JobWorkflowStep `json:"steps,omitempty"`
 }
 
+// WorkflowPriorityInheritance controls the priority of the follow-up jobs of a
+// workflow. With InheritPriority set, each step after the first runs with the
+// priority and max agents of the step before it, raised by PriorityBoost and
+// capped at max_job_priority.
+type WorkflowPriorityInheritance struct {
+	InheritPriority bool `json:"inherit_priority" db:"inherit_priority"`
+	PriorityBoost   int  `json:"priority_boost" db:"priority_boost"`
+}
+
 // JobWorkflowStep mirrors the job_workflow_steps table structure.
 // It links a JobWorkflow to a PresetJob at a specific order.
 type JobWorkflowStep struct {
@@ -123,6 +164,12 @@ const (
 	JobExecutionStatusCompleted JobExecutionStatus = "completed"
 	JobExecutionStatusFailed    JobExecutionStatus = "failed"
 	JobExecutionStatusCancelled JobExecutionStatus = "cancelled"
+	// JobExecutionStatusSuperseded marks a job that never started before every
+	// hash of its hashlist was cracked
+	JobExecutionStatusSuperseded JobExecutionStatus = "superseded"
+	// JobExecutionStatusScheduled marks a job held back until its start
+	// condition is met, see JobStartCondition
+	JobExecutionStatusScheduled JobExecutionStatus = "scheduled"
 )
 
 // JobExecution represents an actual running instance of a preset job
@@ -146,16 +193,19 @@ type JobExecution struct {
 	ConsecutiveFailures int                `json:"consecutive_failures" db:"consecutive_failures"` // Track consecutive task failures
 
 	// Self-contained configuration fields (no need to look up preset)
-	Name                      string  `json:"name" db:"name"`
-	WordlistIDs               IDArray `json:"wordlist_ids" db:"wordlist_ids"`
-	RuleIDs                   IDArray `json:"rule_ids" db:"rule_ids"`
-	HashType                  int     `json:"hash_type" db:"hash_type"`
-	ChunkSizeSeconds          int     `json:"chunk_size_seconds" db:"chunk_size_seconds"`
-	StatusUpdatesEnabled      bool    `json:"status_updates_enabled" db:"status_updates_enabled"`
-	AllowHighPriorityOverride bool    `json:"allow_high_priority_override" db:"allow_high_priority_override"`
-	BinaryVersionID           int     `json:"binary_version_id" db:"binary_version_id"`
-	Mask                      string  `json:"mask,omitempty" db:"mask"`
-	AdditionalArgs            *string `json:"additional_args,omitempty" db:"additional_args"`
+	Name                      string         `json:"name" db:"name"`
+	WordlistIDs               IDArray        `json:"wordlist_ids" db:"wordlist_ids"`
+	RuleIDs                   IDArray        `json:"rule_ids" db:"rule_ids"`
+	HashType                  int            `json:"hash_type" db:"hash_type"`
+	ChunkSizeSeconds          int            `json:"chunk_size_seconds" db:"chunk_size_seconds"`
+	StatusUpdatesEnabled      bool           `json:"status_updates_enabled" db:"status_updates_enabled"`
+	AllowHighPriorityOverride bool           `json:"allow_high_priority_override" db:"allow_high_priority_override"`
+	BinaryVersionID           int            `json:"binary_version_id" db:"binary_version_id"`
+	Mask                      string         `json:"mask,omitempty" db:"mask"`
+	MaskIncrement             *MaskIncrement `json:"mask_increment,omitempty" db:"mask_increment"` // --increment bounds and per-length keyspaces
+	AdditionalArgs            *string        `json:"additional_args,omitempty" db:"additional_args"`
+	AttackFingerprint         string         `json:"attack_fingerprint,omitempty" db:"attack_fingerprint"` // Canonical attack hash for duplicate detection
+	ExtraParameters           *string        `json:"extra_parameters,omitempty" db:"extra_parameters"`     // Extra hashcat parameters merged over the agent's own
 
 	// Enhanced chunking fields
 	BaseKeyspace         *int64   `json:"base_keyspace" db:"base_keyspace"`                 // Wordlist-only keyspace
@@ -165,6 +215,29 @@ type JobExecution struct {
 	IsAccurateKeyspace   bool     `json:"is_accurate_keyspace" db:"is_accurate_keyspace"`   // TRUE if effective_keyspace from hashcat progress[1]
 	UsesRuleSplitting    bool     `json:"uses_rule_splitting" db:"uses_rule_splitting"`     // Whether this job uses rule splitting
 	RuleSplitCount       int      `json:"rule_split_count" db:"rule_split_count"`           // Number of rule chunks created
+	ChunkOverlap         *int64   `json:"chunk_overlap" db:"chunk_overlap"`                 // Candidates re-processed before each chunk, nil uses the system setting
+
+	// Benchmark override
+	SkipBenchmark            bool `json:"skip_benchmark" db:"skip_benchmark"`                         // Skip the forced benchmark before the first task
+	BenchmarkDurationSeconds *int `json:"benchmark_duration_seconds" db:"benchmark_duration_seconds"` // Benchmark time limit, nil uses the system setting
+
+	// Background jobs only run on idle agents and give way to any normal job
+	IsBackground bool `json:"is_background" db:"is_background"`
+
+	// Agents the job is pinned to or kept off
+	AgentPlacement
+
+	// When the job may start, it is scheduled until then
+	JobStartCondition
+
+	// Whether the job may launch and run on temporary cloud burst agents
+	AllowCloudBurst bool `json:"allow_cloud_burst" db:"allow_cloud_burst"`
+
+	// Hashes in the job's snapshot of the uncracked hashes of its hashlist, nil when it runs the full hashlist
+	RemainingHashCount *int `json:"remaining_hash_count,omitempty" db:"remaining_hash_count"`
+
+	// Shared by the jobs running one attack against the sub-lists of a split hashlist
+	SplitGroupID *uuid.UUID `json:"split_group_id,omitempty" db:"split_group_id"`
 
 	// Progress tracking
 	OverallProgressPercent float64    `json:"overall_progress_percent" db:"overall_progress_percent"` // Overall job progress (0-100)
@@ -177,6 +250,24 @@ type JobExecution struct {
 	CompletionEmailError  *string    `json:"completion_email_error" db:"completion_email_error"`
 }
 
+// DuplicateJob is an existing job that runs the same attack against the same
+// hashlist as a job being created.
+type DuplicateJob struct {
+	ID                     uuid.UUID          `json:"id"`
+	Name                   string             `json:"name"`
+	Status                 JobExecutionStatus `json:"status"`
+	OverallProgressPercent float64            `json:"overall_progress_percent"`
+	CreatedAt              time.Time          `json:"created_at"`
+	CompletedAt            *time.Time         `json:"completed_at,omitempty"`
+}
+
+// RunningJobCounts holds the number of running jobs per creating user and per
+// client of the job's hashlist, used to enforce job concurrency caps.
+type RunningJobCounts struct {
+	ByUser   map[uuid.UUID]int
+	ByClient map[uuid.UUID]int
+}
+
 // JobTaskStatus represents the status of a job task
 type JobTaskStatus string
 
@@ -217,6 +308,8 @@ type JobTask struct {
 	UpdatedAt         time.Time     `json:"updated_at" db:"updated_at"`
 	LastCheckpoint    *time.Time    `json:"last_checkpoint" db:"last_checkpoint"`
 	ErrorMessage      *string       `json:"error_message" db:"error_message"`
+	ErrorCode         *TaskErrorCode `json:"error_code,omitempty" db:"error_code"` // Classified hashcat failure
+	RemediationHint   string         `json:"remediation_hint,omitempty"`           // Derived from ErrorCode, not stored
 
 	// Enhanced fields for detailed chunk tracking
 	CrackCount     int    `json:"crack_count" db:"crack_count"`
@@ -232,6 +325,23 @@ type JobTask struct {
 	// Chunk tracking
 	ChunkNumber int `json:"chunk_number" db:"chunk_number"` // Sequential chunk number within this job (1, 2, 3...)
 
+	// Speculative re-dispatch: set on a copy of a straggling task running on another agent
+	SpeculativeOf *uuid.UUID `json:"speculative_of,omitempty" db:"speculative_of"`
+
+	// Chunk verification: set on a copy of a completed task re-run on another agent
+	VerificationOf *uuid.UUID `json:"verification_of,omitempty" db:"verification_of"`
+
+	// Chunk reuse: set when this task took over the results of an identical chunk from another job
+	ReusedFrom *uuid.UUID `json:"reused_from,omitempty" db:"reused_from"`
+
+	// Chunk overlap: candidates before KeyspaceStart that were dispatched with this task
+	ChunkOverlap int64 `json:"chunk_overlap" db:"chunk_overlap"`
+
+	// Checkpointing: the last hashcat restore point reported for this task and
+	// how far past KeyspaceStart the current dispatch resumed from it
+	CheckpointKeyspace *int64 `json:"checkpoint_keyspace,omitempty" db:"checkpoint_keyspace"`
+	ResumeOffset       int64  `json:"resume_offset" db:"resume_offset"`
+
 	// Populated fields from JOINs
 	AgentName *string `json:"agent_name,omitempty" db:"agent_name"`
 }
@@ -245,6 +355,10 @@ type AgentBenchmark struct {
 	Speed      int64      `json:"speed" db:"speed"` // hashes per second
 	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Per-device speeds keyed by hashcat device ID, only written when a
+	// benchmark run reports them
+	DeviceSpeeds map[int]int64 `json:"device_speeds,omitempty" db:"device_speeds"`
 }
 
 // MetricType represents the type of metric being tracked
@@ -349,6 +463,7 @@ type DeviceMetric struct {
 type JobProgress struct {
 	TaskID                 uuid.UUID      `json:"task_id"`
 	KeyspaceProcessed      int64          `json:"keyspace_processed"`                   // Restore point (position in wordlist)
+	RestorePoint           *int64         `json:"restore_point,omitempty"`              // Hashcat restore point relative to the dispatched start, set when hashcat reported one
 	EffectiveProgress      int64          `json:"effective_progress"`                   // Actual effective progress (words × rules processed)
 	ProgressPercent        float64        `json:"progress_percent"`                     // Actual progress percentage (0-100)
 	TotalEffectiveKeyspace *int64         `json:"total_effective_keyspace,omitempty"`   // Only sent on first update - hashcat progress[1]
@@ -361,8 +476,22 @@ type JobProgress struct {
 	CrackedHashes          []CrackedHash  `json:"cracked_hashes"`                       // Detailed crack information
 	Status                 string         `json:"status,omitempty"`                     // Task status (running, completed, failed)
 	ErrorMessage           string         `json:"error_message,omitempty"`              // Error message if status is failed
+	ErrorCode              string         `json:"error_code,omitempty"`                 // Typed cause of a failure the agent detected itself, e.g. file_mismatch
 	DeviceMetrics          []DeviceMetric `json:"device_metrics,omitempty"`              // Per-device metrics
 	AllHashesCracked       bool           `json:"all_hashes_cracked,omitempty"`         // Flag indicating all hashes in hashlist were cracked (exit code 6)
+	ExitCode               *int           `json:"exit_code,omitempty"`                  // Hashcat exit code, only on the final update
+	FinalStatus            json.RawMessage `json:"final_status,omitempty"`              // Last hashcat JSON status line, only on the final update
+	OutputTail             string         `json:"output_tail,omitempty"`                // Last hashcat output lines, only on the final update
+	BinaryAttestation      *BinaryAttestation `json:"binary_attestation,omitempty"`     // Check of the hashcat binary, only on the first update or a binary_mismatch failure
+}
+
+// BinaryAttestation is an agent's check of its hashcat binary against the
+// hash recorded by the binary manager, made before it runs a task
+type BinaryAttestation struct {
+	Executable string `json:"executable"`         // File name of the executable checked, e.g. hashcat.bin
+	SHA256     string `json:"sha256"`             // Hash of the agent's copy
+	Expected   string `json:"expected,omitempty"` // Hash the server sent, empty if it sent none
+	Verified   bool   `json:"verified"`           // Whether the hashes matched
 }
 
 // CrackedHash represents a cracked hash with all available information
@@ -0,0 +1,30 @@
+package models
+
+import "testing"
+
+func TestComputeAttackFingerprint(t *testing.T) {
+	args := "-O  --increment"
+	normalizedArgs := "-O --increment"
+	base := ComputeAttackFingerprint(AttackModeStraight, 1000, IDArray{"1"}, IDArray{"2", "3"}, "", &args, 1)
+
+	if len(base) != 64 {
+		t.Fatalf("expected 64 hex characters, got %d", len(base))
+	}
+	if got := ComputeAttackFingerprint(AttackModeStraight, 1000, IDArray{"1"}, IDArray{"2", "3"}, "", &normalizedArgs, 1); got != base {
+		t.Errorf("whitespace in additional args should not change the fingerprint")
+	}
+
+	different := map[string]string{
+		"rule order":  ComputeAttackFingerprint(AttackModeStraight, 1000, IDArray{"1"}, IDArray{"3", "2"}, "", &args, 1),
+		"hash type":   ComputeAttackFingerprint(AttackModeStraight, 1800, IDArray{"1"}, IDArray{"2", "3"}, "", &args, 1),
+		"binary":      ComputeAttackFingerprint(AttackModeStraight, 1000, IDArray{"1"}, IDArray{"2", "3"}, "", &args, 2),
+		"no args":     ComputeAttackFingerprint(AttackModeStraight, 1000, IDArray{"1"}, IDArray{"2", "3"}, "", nil, 1),
+		"wordlist":    ComputeAttackFingerprint(AttackModeStraight, 1000, IDArray{"4"}, IDArray{"2", "3"}, "", &args, 1),
+		"attack mode": ComputeAttackFingerprint(AttackModeHybridWordlistMask, 1000, IDArray{"1"}, IDArray{"2", "3"}, "", &args, 1),
+	}
+	for name, fingerprint := range different {
+		if fingerprint == base {
+			t.Errorf("changing the %s should change the fingerprint", name)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaintenanceCode is the error code of API responses refused because the
+// system is in maintenance mode, so clients can show the banner message
+const MaintenanceCode = "maintenance_mode"
+
+// MaxMaintenanceMessageLength bounds the banner message of a maintenance window
+const MaxMaintenanceMessageLength = 500
+
+// MaintenanceWindow is one period of system-wide maintenance mode. While it is
+// open no new tasks are dispatched and no jobs can be created. Running chunks
+// finish, or are stopped once the drain timeout has passed. Ended windows are
+// kept as the audit trail.
+type MaintenanceWindow struct {
+	ID                  int64      `json:"id" db:"id"`
+	Message             string     `json:"message" db:"message"`
+	DrainTimeoutSeconds int        `json:"drain_timeout_seconds" db:"drain_timeout_seconds"` // 0 lets running chunks finish however long they take
+	StartedAt           time.Time  `json:"started_at" db:"started_at"`
+	StartedBy           *uuid.UUID `json:"started_by,omitempty" db:"started_by"`
+	StartedByUsername   string     `json:"started_by_username" db:"started_by_username"`
+	ForceStoppedAt      *time.Time `json:"force_stopped_at,omitempty" db:"force_stopped_at"`
+	StoppedTasks        int        `json:"stopped_tasks" db:"stopped_tasks"`
+	EndedAt             *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+	EndedBy             *uuid.UUID `json:"ended_by,omitempty" db:"ended_by"`
+	EndedByUsername     *string    `json:"ended_by_username,omitempty" db:"ended_by_username"`
+}
+
+// StartMaintenanceRequest is the body of a request to enter maintenance mode
+type StartMaintenanceRequest struct {
+	Message             string `json:"message"`
+	DrainTimeoutSeconds int    `json:"drain_timeout_seconds"`
+}
+
+// Validate trims the message and checks the request
+func (r *StartMaintenanceRequest) Validate() error {
+	r.Message = strings.TrimSpace(r.Message)
+	if r.Message == "" {
+		return fmt.Errorf("message is required")
+	}
+	if len(r.Message) > MaxMaintenanceMessageLength {
+		return fmt.Errorf("message must be at most %d characters", MaxMaintenanceMessageLength)
+	}
+	if r.DrainTimeoutSeconds < 0 {
+		return fmt.Errorf("drain_timeout_seconds must not be negative")
+	}
+	return nil
+}
+
+// DrainDeadline returns when running tasks are force-stopped, or nil if they
+// are left to finish
+func (w *MaintenanceWindow) DrainDeadline() *time.Time {
+	if w.DrainTimeoutSeconds <= 0 {
+		return nil
+	}
+	deadline := w.StartedAt.Add(time.Duration(w.DrainTimeoutSeconds) * time.Second)
+	return &deadline
+}
+
+// ForceStopDue reports whether the running tasks of the open window must be
+// stopped at now. Tasks are only force-stopped once per window.
+func (w *MaintenanceWindow) ForceStopDue(now time.Time) bool {
+	deadline := w.DrainDeadline()
+	return w.EndedAt == nil && w.ForceStoppedAt == nil && deadline != nil && !now.Before(*deadline)
+}
+
+// MaintenanceStatus is what every user sees of maintenance mode
+type MaintenanceStatus struct {
+	Active bool               `json:"active"`
+	Window *MaintenanceWindow `json:"window,omitempty"`
+}
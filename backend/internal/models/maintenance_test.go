@@ -0,0 +1,41 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceWindowForceStopDue(t *testing.T) {
+	started := time.Now().Add(-time.Hour)
+
+	// Without a drain timeout running tasks are left to finish
+	window := MaintenanceWindow{StartedAt: started}
+	assert.Nil(t, window.DrainDeadline())
+	assert.False(t, window.ForceStopDue(time.Now()))
+
+	window.DrainTimeoutSeconds = 1800
+	assert.Equal(t, started.Add(30*time.Minute), *window.DrainDeadline())
+	assert.False(t, window.ForceStopDue(started.Add(29*time.Minute)))
+	assert.True(t, window.ForceStopDue(started.Add(30*time.Minute)))
+
+	// Tasks are only stopped once, and not after the window ended
+	stopped := started.Add(30 * time.Minute)
+	window.ForceStoppedAt = &stopped
+	assert.False(t, window.ForceStopDue(time.Now()))
+	window.ForceStoppedAt = nil
+	window.EndedAt = &stopped
+	assert.False(t, window.ForceStopDue(time.Now()))
+}
+
+func TestStartMaintenanceRequestValidate(t *testing.T) {
+	req := StartMaintenanceRequest{Message: "  Upgrading the database  ", DrainTimeoutSeconds: 600}
+	assert.NoError(t, req.Validate())
+	assert.Equal(t, "Upgrading the database", req.Message)
+
+	assert.Error(t, (&StartMaintenanceRequest{Message: "   "}).Validate())
+	assert.Error(t, (&StartMaintenanceRequest{Message: strings.Repeat("a", MaxMaintenanceMessageLength+1)}).Validate())
+	assert.Error(t, (&StartMaintenanceRequest{Message: "Upgrade", DrainTimeoutSeconds: -1}).Validate())
+}
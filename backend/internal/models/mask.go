@@ -0,0 +1,167 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatmask"
+	"github.com/google/uuid"
+)
+
+// CustomCharset is a reusable custom charset from the charset library
+type CustomCharset struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Charset     string     `json:"charset"`
+	Description string     `json:"description"`
+	Size        int        `json:"size"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// MaskCharsetRef defines one of the ?1 to ?4 custom charsets of a mask,
+// either inline or by its ID in the charset library
+type MaskCharsetRef struct {
+	Charset   string `json:"charset,omitempty"`
+	LibraryID *int   `json:"library_id,omitempty"`
+}
+
+// MaskValidationRequest is the body of POST /api/masks/validate. The mask may
+// already carry its custom charsets in hcmask format, in which case
+// CustomCharsets must be empty.
+type MaskValidationRequest struct {
+	Mask           string           `json:"mask"`
+	CustomCharsets []MaskCharsetRef `json:"custom_charsets,omitempty"`
+	MinLength      int              `json:"min_length,omitempty"`
+	MaxLength      int              `json:"max_length,omitempty"`
+}
+
+// MaskValidationResult describes a validated mask. Line is the mask with its
+// custom charsets in hcmask format, the value to use as a job's mask.
+type MaskValidationResult struct {
+	Valid          bool                   `json:"valid"`
+	Error          string                 `json:"error,omitempty"`
+	Line           string                 `json:"line,omitempty"`
+	CustomCharsets []string               `json:"custom_charsets,omitempty"`
+	Length         int                    `json:"length,omitempty"`
+	Positions      []hashcatmask.Position `json:"positions,omitempty"`
+	Keyspace       int64                  `json:"keyspace,omitempty"`
+}
+
+// MaskIncrement holds the --increment bounds of a mask attack. Hashcat runs
+// the mask once for each length from Min to Max positions, so the job's
+// keyspace is the sum of the keyspaces of those lengths, kept in Keyspaces
+// shortest first. Chunks are cut from that combined keyspace and never span
+// two lengths.
+type MaskIncrement struct {
+	Min       int     `json:"min"`
+	Max       int     `json:"max"` // 0 runs up to the full mask length
+	Keyspaces []int64 `json:"keyspaces,omitempty"`
+}
+
+// MaskIncrementStep is the part of an increment job's keyspace run with the
+// mask cut to Length positions
+type MaskIncrementStep struct {
+	Length int   `json:"length"`
+	Start  int64 `json:"start"`
+	End    int64 `json:"end"`
+}
+
+// Resolve checks the bounds against the mask and returns the lines hashcat
+// runs, shortest first. A Max of 0 is set to the mask length.
+func (m *MaskIncrement) Resolve(mask string) ([]hashcatmask.Line, error) {
+	line, err := hashcatmask.ParseLine(mask)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := hashcatmask.Increments(line, m.Min, m.Max)
+	if err != nil {
+		return nil, err
+	}
+	m.Max = m.Min + len(lines) - 1
+	return lines, nil
+}
+
+// SameBounds reports whether both increments run the same lengths, nil
+// meaning no increment
+func (m *MaskIncrement) SameBounds(other *MaskIncrement) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+	return m.Min == other.Min && m.Max == other.Max
+}
+
+// Total returns the keyspace of all lengths
+func (m MaskIncrement) Total() int64 {
+	var total int64
+	for _, keyspace := range m.Keyspaces {
+		total += keyspace
+	}
+	return total
+}
+
+// Steps returns the keyspace range of each length, shortest first
+func (m MaskIncrement) Steps() []MaskIncrementStep {
+	steps := make([]MaskIncrementStep, 0, len(m.Keyspaces))
+	var start int64
+	for i, keyspace := range m.Keyspaces {
+		steps = append(steps, MaskIncrementStep{Length: m.Min + i, Start: start, End: start + keyspace})
+		start += keyspace
+	}
+	return steps
+}
+
+// StepAt returns the step holding the keyspace offset
+func (m MaskIncrement) StepAt(offset int64) (MaskIncrementStep, bool) {
+	for _, step := range m.Steps() {
+		if offset >= step.Start && offset < step.End {
+			return step, true
+		}
+	}
+	return MaskIncrementStep{}, false
+}
+
+// StepMask returns the hcmask line of the mask cut to the step's length
+func (s MaskIncrementStep) StepMask(mask string) (string, error) {
+	line, err := hashcatmask.ParseLine(mask)
+	if err != nil {
+		return "", err
+	}
+	lines, err := hashcatmask.Increments(line, s.Length, s.Length)
+	if err != nil {
+		return "", err
+	}
+	return lines[0].String(), nil
+}
+
+// FingerprintMask returns the mask as it is hashed into the attack
+// fingerprint, so the same mask with other increment bounds is another attack
+func (m *MaskIncrement) FingerprintMask(mask string) string {
+	if m == nil {
+		return mask
+	}
+	return fmt.Sprintf("%s --increment %d-%d", mask, m.Min, m.Max)
+}
+
+// Value implements driver.Valuer
+func (m MaskIncrement) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// Scan implements sql.Scanner
+func (m *MaskIncrement) Scan(value interface{}) error {
+	*m = MaskIncrement{}
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	default:
+		return fmt.Errorf("unsupported type for MaskIncrement: %T", value)
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskIncrementSteps(t *testing.T) {
+	increment := MaskIncrement{Min: 2}
+	lines, err := increment.Resolve("?l?d,?1?1?1?1")
+	require.NoError(t, err)
+	assert.Len(t, lines, 3)
+	assert.Equal(t, 4, increment.Max)
+
+	increment.Keyspaces = []int64{36, 1296, 46656}
+	assert.Equal(t, int64(47988), increment.Total())
+
+	step, ok := increment.StepAt(36)
+	require.True(t, ok)
+	assert.Equal(t, MaskIncrementStep{Length: 3, Start: 36, End: 1332}, step)
+	mask, err := step.StepMask("?l?d,?1?1?1?1")
+	require.NoError(t, err)
+	assert.Equal(t, "?l?d,?1?1?1", mask)
+
+	_, ok = increment.StepAt(47988)
+	assert.False(t, ok)
+
+	_, err = (&MaskIncrement{Min: 3, Max: 5}).Resolve("?d?d?d?d")
+	assert.Error(t, err)
+}
+
+func TestMaskIncrementFingerprint(t *testing.T) {
+	var none *MaskIncrement
+	assert.Equal(t, "?d?d?d", none.FingerprintMask("?d?d?d"))
+	assert.Equal(t, "?d?d?d --increment 1-3", (&MaskIncrement{Min: 1, Max: 3}).FingerprintMask("?d?d?d"))
+
+	var scanned MaskIncrement
+	value, err := MaskIncrement{Min: 1, Max: 2, Keyspaces: []int64{10, 100}}.Value()
+	require.NoError(t, err)
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, []int64{10, 100}, scanned.Keyspaces)
+}
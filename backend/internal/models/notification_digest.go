@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification digest modes
+const (
+	NotificationDigestOff    = "off"
+	NotificationDigestHourly = "hourly"
+	NotificationDigestDaily  = "daily"
+)
+
+// ValidateNotificationDigest checks a digest mode chosen by a user
+func ValidateNotificationDigest(mode string) error {
+	switch mode {
+	case NotificationDigestOff, NotificationDigestHourly, NotificationDigestDaily:
+		return nil
+	}
+	return fmt.Errorf("invalid notification digest %q, must be off, hourly or daily", mode)
+}
+
+// DigestRecipient is a user whose next digest is due
+type DigestRecipient struct {
+	UserID     uuid.UUID
+	Email      string
+	Mode       string
+	LastSentAt time.Time
+}
+
+// NotificationDigest summarizes what happened to a user's work between Since
+// and Until
+type NotificationDigest struct {
+	Since     time.Time
+	Until     time.Time
+	Jobs      []DigestJob
+	Cracks    []DigestCracks
+	Incidents []DigestAgentIncident
+}
+
+// DigestJob is a job of the user that finished during the digest period
+type DigestJob struct {
+	ID           uuid.UUID
+	Name         string
+	HashlistName string
+	Status       JobExecutionStatus
+	CompletedAt  time.Time
+}
+
+// DigestCracks counts the hashes cracked in one of the user's hashlists
+// during the digest period
+type DigestCracks struct {
+	HashlistID   int64
+	HashlistName string
+	Count        int
+}
+
+// DigestAgentIncident is an agent of the user going offline or crashing
+// during the digest period
+type DigestAgentIncident struct {
+	AgentID    int
+	AgentName  string
+	Kind       string // offline, or the crash report kind
+	Detail     string
+	OccurredAt time.Time
+}
+
+// CrackCount returns the number of hashes cracked during the digest period
+func (d *NotificationDigest) CrackCount() int {
+	total := 0
+	for _, cracks := range d.Cracks {
+		total += cracks.Count
+	}
+	return total
+}
+
+// Empty reports whether nothing happened during the digest period
+func (d *NotificationDigest) Empty() bool {
+	return len(d.Jobs) == 0 && len(d.Cracks) == 0 && len(d.Incidents) == 0
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuickCrackStatus is the progress of a quick crack submission
+type QuickCrackStatus string
+
+const (
+	// QuickCrackStatusProcessing means the hashes are still being imported
+	QuickCrackStatusProcessing QuickCrackStatus = "processing"
+	// QuickCrackStatusRunning means the quick attack jobs are queued or running
+	QuickCrackStatusRunning QuickCrackStatus = "running"
+	// QuickCrackStatusCompleted means every hash is cracked or every job has finished
+	QuickCrackStatusCompleted QuickCrackStatus = "completed"
+	// QuickCrackStatusFailed means the hashes could not be imported
+	QuickCrackStatusFailed QuickCrackStatus = "failed"
+)
+
+// IsFinal reports whether the submission will not change anymore
+func (s QuickCrackStatus) IsFinal() bool {
+	return s == QuickCrackStatusCompleted || s == QuickCrackStatusFailed
+}
+
+// QuickCrackSubmission is a handful of ad hoc hashes submitted for a quick crack attempt
+type QuickCrackSubmission struct {
+	ID               uuid.UUID   `json:"id"`
+	UserID           uuid.UUID   `json:"user_id"`
+	HashlistID       int64       `json:"hashlist_id"`
+	HashTypeID       int         `json:"hash_type_id"`
+	WorkflowID       *uuid.UUID  `json:"workflow_id,omitempty"`
+	JobIDs           []uuid.UUID `json:"job_ids"`
+	WebhookURL       *string     `json:"webhook_url,omitempty"`
+	WebhookAttempts  int         `json:"webhook_attempts"`
+	WebhookLastError *string     `json:"webhook_last_error,omitempty"`
+	WebhookSentAt    *time.Time  `json:"webhook_sent_at,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+}
+
+// QuickCrackRequest is the body of a quick crack submission
+type QuickCrackRequest struct {
+	Hashes     []string   `json:"hashes"`
+	HashType   string     `json:"hash_type"`   // Mode number, name or alias, detected from the hashes when empty
+	WorkflowID *uuid.UUID `json:"workflow_id"` // Defaults to the quick_crack_workflow_id setting
+	ClientID   *uuid.UUID `json:"client_id"`
+	WebhookURL string     `json:"webhook_url"` // Receives the results once the submission completes
+}
+
+// QuickCrackHash is the result for one submitted hash
+type QuickCrackHash struct {
+	Hash     string `json:"hash"`
+	Cracked  bool   `json:"cracked"`
+	Password string `json:"password,omitempty"`
+}
+
+// QuickCrackResult is the state and results of a quick crack submission
+type QuickCrackResult struct {
+	ID            uuid.UUID        `json:"id"`
+	Status        QuickCrackStatus `json:"status"`
+	HashTypeID    int              `json:"hash_type_id"`
+	HashlistID    int64            `json:"hashlist_id"`
+	JobIDs        []uuid.UUID      `json:"job_ids"`
+	TotalHashes   int              `json:"total_hashes"`
+	CrackedHashes int              `json:"cracked_hashes"`
+	Hashes        []QuickCrackHash `json:"hashes"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
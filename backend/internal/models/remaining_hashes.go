@@ -0,0 +1,46 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// remainingHashesFile matches the name of a job's remaining hashes file
+// relative to the hashlists directory
+var remainingHashesFile = regexp.MustCompile(`^remaining/([0-9]+)/([0-9a-fA-F-]{36})\.hash$`)
+
+// HashlistFile returns the name of a hashlist's hash file relative to the
+// hashlists directory
+func HashlistFile(hashlistID int64) string {
+	return fmt.Sprintf("%d.hash", hashlistID)
+}
+
+// RemainingHashesFile returns the name, relative to the hashlists directory,
+// of the snapshot of a hashlist's uncracked hashes a job runs against
+func RemainingHashesFile(hashlistID int64, jobID uuid.UUID) string {
+	return fmt.Sprintf("remaining/%d/%s.hash", hashlistID, jobID)
+}
+
+// JobHashlistFile returns the hash file a job runs against relative to the
+// hashlists directory: its remaining hashes when it has a snapshot of them,
+// otherwise the hashlist's own file
+func JobHashlistFile(hashlistID int64, jobID uuid.UUID, remainingHashCount *int) string {
+	if remainingHashCount != nil {
+		return RemainingHashesFile(hashlistID, jobID)
+	}
+	return HashlistFile(hashlistID)
+}
+
+// IsRemainingHashesFile reports whether name is a remaining hashes file of
+// the hashlist, as agents may ask to download
+func IsRemainingHashesFile(name string, hashlistID int64) bool {
+	match := remainingHashesFile.FindStringSubmatch(name)
+	if match == nil || match[1] != strconv.FormatInt(hashlistID, 10) {
+		return false
+	}
+	_, err := uuid.Parse(match[2])
+	return err == nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobHashlistFile(t *testing.T) {
+	jobID := uuid.MustParse("5b0c7d1e-8f43-4c55-9a1e-2f6b3c4d5e6f")
+	count := 120
+
+	assert.Equal(t, "42.hash", JobHashlistFile(42, jobID, nil))
+	assert.Equal(t, "remaining/42/5b0c7d1e-8f43-4c55-9a1e-2f6b3c4d5e6f.hash", JobHashlistFile(42, jobID, &count))
+}
+
+func TestIsRemainingHashesFile(t *testing.T) {
+	jobID := uuid.MustParse("5b0c7d1e-8f43-4c55-9a1e-2f6b3c4d5e6f")
+	name := RemainingHashesFile(42, jobID)
+
+	assert.True(t, IsRemainingHashesFile(name, 42))
+	// Another hashlist's remaining hashes are not served under this one
+	assert.False(t, IsRemainingHashesFile(name, 43))
+	assert.False(t, IsRemainingHashesFile("42.hash", 42))
+	assert.False(t, IsRemainingHashesFile("remaining/42/../../../etc/passwd.hash", 42))
+	assert.False(t, IsRemainingHashesFile("remaining/42/not-a-uuid-not-a-uuid-not-a-uuid-12345.hash", 42))
+}
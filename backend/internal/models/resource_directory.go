@@ -0,0 +1,30 @@
+package models
+
+// ResourceDirectory is a directory below the wordlists or rules root
+type ResourceDirectory struct {
+	Path      string `json:"path"`
+	FileCount int    `json:"file_count"`
+}
+
+// DirectoryCreateRequest represents a request to create a directory
+type DirectoryCreateRequest struct {
+	Path string `json:"path"`
+}
+
+// DirectoryMoveRequest represents a request to rename or move a directory
+type DirectoryMoveRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DirectoryMoveResponse reports how many files were moved with a directory
+type DirectoryMoveResponse struct {
+	Path       string `json:"path"`
+	FilesMoved int    `json:"files_moved"`
+}
+
+// FileMoveRequest represents a request to move a wordlist or rule file into
+// another directory
+type FileMoveRequest struct {
+	Directory string `json:"directory"`
+}
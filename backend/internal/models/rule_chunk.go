@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RuleChunkFile is a rule chunk generated for a rule-split job, registered so
+// agents download it like other synced files
+type RuleChunkFile struct {
+	ID             int64     `json:"id"`
+	JobExecutionID uuid.UUID `json:"job_execution_id"`
+	Name           string    `json:"name"` // Path below the rules directory, e.g. chunks/job_<id>/chunk_0_1000.rule
+	RuleStartIndex int       `json:"rule_start_index"`
+	RuleEndIndex   int       `json:"rule_end_index"`
+	MD5Hash        string    `json:"md5_hash"`
+	FileSize       int64     `json:"file_size"`
+	CreatedAt      time.Time `json:"created_at"`
+}
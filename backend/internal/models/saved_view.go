@@ -0,0 +1,146 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Lists a saved view can apply to
+const (
+	SavedViewResourceJobs   = "jobs"   // GET /api/jobs
+	SavedViewResourceTasks  = "tasks"  // GET /api/tasks and GET /api/jobs/{id}/tasks
+	SavedViewResourceHashes = "hashes" // GET /api/hashlists/{id}/hashes
+)
+
+// Limits on saved views
+const (
+	MaxSavedViewNameLength   = 100
+	MaxSavedViewParams       = 32
+	MaxSavedViewValueLength  = 500
+	maxSavedViewParamNameLen = 64
+)
+
+// savedViewReservedParams are request parameters a view cannot store:
+// pagination belongs to the request and a view cannot refer to another view
+var savedViewReservedParams = map[string]bool{
+	"view":   true,
+	"page":   true,
+	"offset": true,
+}
+
+// SavedView is a named set of filter and sort parameters for one of the list
+// endpoints, private to its owner or shared with one of the owner's teams
+type SavedView struct {
+	ID            uuid.UUID      `json:"id"`
+	UserID        uuid.UUID      `json:"user_id"`
+	OwnerUsername string         `json:"owner_username"`
+	TeamID        *uuid.UUID     `json:"team_id,omitempty"` // Nil for a private view
+	TeamName      *string        `json:"team_name,omitempty"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description"`
+	Resource      string         `json:"resource"`
+	Query         SavedViewQuery `json:"query"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// Normalize trims the name and description and validates the view
+func (v *SavedView) Normalize() error {
+	v.Name = strings.TrimSpace(v.Name)
+	v.Description = strings.TrimSpace(v.Description)
+	if v.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if utf8.RuneCountInString(v.Name) > MaxSavedViewNameLength {
+		return fmt.Errorf("name exceeds %d characters", MaxSavedViewNameLength)
+	}
+	switch v.Resource {
+	case SavedViewResourceJobs, SavedViewResourceTasks, SavedViewResourceHashes:
+	default:
+		return fmt.Errorf("resource must be jobs, tasks or hashes")
+	}
+	if v.Query == nil {
+		v.Query = SavedViewQuery{}
+	}
+	return v.Query.Validate()
+}
+
+// SavedViewQuery maps query parameter names to values, e.g.
+// {"status": "failed", "since": "7d", "sort": "-completed_at"}
+type SavedViewQuery map[string]string
+
+// Validate rejects reserved or malformed parameters and values over the limits
+func (q SavedViewQuery) Validate() error {
+	if len(q) > MaxSavedViewParams {
+		return fmt.Errorf("at most %d query parameters are allowed", MaxSavedViewParams)
+	}
+	for name, value := range q {
+		if name == "" || len(name) > maxSavedViewParamNameLen || strings.IndexFunc(name, invalidParamRune) >= 0 {
+			return fmt.Errorf("invalid query parameter name %q", name)
+		}
+		if savedViewReservedParams[paramBase(name)] {
+			return fmt.Errorf("query parameter %q cannot be saved in a view", name)
+		}
+		if utf8.RuneCountInString(value) > MaxSavedViewValueLength {
+			return fmt.Errorf("value of %q exceeds %d characters", name, MaxSavedViewValueLength)
+		}
+	}
+	return nil
+}
+
+// Apply adds the view's parameters to the request parameters. Parameters
+// given in the request win, whether as "status" or "filter[status]".
+func (q SavedViewQuery) Apply(values url.Values) {
+	given := make(map[string]bool, len(values))
+	for name := range values {
+		given[paramBase(name)] = true
+	}
+	for name, value := range q {
+		if !given[paramBase(name)] {
+			values.Set(name, value)
+		}
+	}
+}
+
+// paramBase returns the filter name of a filter[name] parameter, or the
+// parameter itself
+func paramBase(name string) string {
+	if strings.HasPrefix(name, "filter[") && strings.HasSuffix(name, "]") {
+		return name[len("filter[") : len(name)-1]
+	}
+	return name
+}
+
+func invalidParamRune(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '[' || r == ']')
+}
+
+// Value implements driver.Valuer
+func (q SavedViewQuery) Value() (driver.Value, error) {
+	if q == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(q)
+}
+
+// Scan implements sql.Scanner
+func (q *SavedViewQuery) Scan(value interface{}) error {
+	*q = SavedViewQuery{}
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, q)
+	case string:
+		return json.Unmarshal([]byte(v), q)
+	default:
+		return fmt.Errorf("unsupported type for SavedViewQuery: %T", value)
+	}
+}
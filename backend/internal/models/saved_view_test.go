@@ -0,0 +1,52 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedViewNormalize(t *testing.T) {
+	view := SavedView{Name: "  Failed chunks this week ", Resource: SavedViewResourceTasks}
+	require.NoError(t, view.Normalize())
+	assert.Equal(t, "Failed chunks this week", view.Name)
+	assert.NotNil(t, view.Query)
+
+	assert.Error(t, (&SavedView{Name: " ", Resource: SavedViewResourceJobs}).Normalize())
+	assert.Error(t, (&SavedView{Name: strings.Repeat("x", MaxSavedViewNameLength+1), Resource: SavedViewResourceJobs}).Normalize())
+	assert.Error(t, (&SavedView{Name: "Agents", Resource: "agents"}).Normalize())
+}
+
+func TestSavedViewQueryValidate(t *testing.T) {
+	assert.NoError(t, SavedViewQuery{"filter[status]": "failed", "since": "7d", "sort": "-completed_at"}.Validate())
+
+	assert.Error(t, SavedViewQuery{"view": "other"}.Validate())
+	assert.Error(t, SavedViewQuery{"filter[page]": "2"}.Validate())
+	assert.Error(t, SavedViewQuery{"Status": "failed"}.Validate())
+	assert.Error(t, SavedViewQuery{"search": strings.Repeat("x", MaxSavedViewValueLength+1)}.Validate())
+}
+
+func TestSavedViewQueryApply(t *testing.T) {
+	view := SavedViewQuery{"filter[status]": "failed", "since": "7d", "sort": "-completed_at"}
+
+	values := url.Values{"status": {"running"}, "page": {"2"}}
+	view.Apply(values)
+
+	assert.Equal(t, "running", values.Get("status"))
+	assert.Empty(t, values.Get("filter[status]"), "the request's status must win over the view's filter[status]")
+	assert.Equal(t, "7d", values.Get("since"))
+	assert.Equal(t, "-completed_at", values.Get("sort"))
+	assert.Equal(t, "2", values.Get("page"))
+}
+
+func TestSavedViewQueryScan(t *testing.T) {
+	var query SavedViewQuery
+	require.NoError(t, query.Scan([]byte(`{"cracked": "false"}`)))
+	assert.Equal(t, SavedViewQuery{"cracked": "false"}, query)
+
+	require.NoError(t, query.Scan(nil))
+	assert.Equal(t, SavedViewQuery{}, query)
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchedulingSnapshotRetentionSetting is how many hours of scheduling cycles
+// are kept in memory for debugging
+const (
+	SchedulingSnapshotRetentionSetting = "scheduling_snapshot_retention_hours"
+	DefaultSchedulingSnapshotRetention = 6 * time.Hour
+)
+
+// Outcomes of the scheduler's decision for an agent
+const (
+	SchedulingOutcomeAssigned = "assigned"
+	SchedulingOutcomeSkipped  = "skipped"
+	SchedulingOutcomeError    = "error"
+)
+
+// Reasons an agent was not given work, or a job was passed over for an agent
+const (
+	SchedulingReasonAgentBusy          = "agent_busy"
+	SchedulingReasonReconnectPending   = "reconnect_pending"
+	SchedulingReasonNoEligibleJob      = "no_eligible_job"
+	SchedulingReasonHashlistCracked    = "hashlist_fully_cracked"
+	SchedulingReasonChunksReused       = "all_chunks_reused"
+	SchedulingReasonBenchmarkPending   = "benchmark_pending"
+	SchedulingReasonBenchmarkRequested = "benchmark_requested"
+	SchedulingReasonPowerWindow        = "power_window_priority_limit"
+	SchedulingReasonCloudBurst         = "cloud_burst_not_allowed"
+	SchedulingReasonPlacement          = "agent_placement"
+	SchedulingReasonConcurrencyCap     = "concurrency_cap"
+	SchedulingReasonDeviceMemory       = "device_memory"
+	SchedulingReasonBackgroundNotIdle  = "agent_not_idle_for_background"
+)
+
+// SchedulingSnapshot is a compact record of one scheduling cycle. Consecutive
+// cycles that decided the same are merged into one snapshot.
+type SchedulingSnapshot struct {
+	Cycle      int64     `json:"cycle"`
+	StartedAt  time.Time `json:"started_at"`
+	LastSeenAt time.Time `json:"last_seen_at"` // Start of the last identical cycle merged into this one
+	Repeats    int       `json:"repeats"`      // Identical cycles merged into this one
+	DurationMs int64     `json:"duration_ms"`
+
+	Jobs            []SchedulingJobState   `json:"jobs"`   // Jobs with pending work seen during the cycle
+	Agents          []SchedulingAgentState `json:"agents"` // Agents available for work
+	Decisions       []SchedulingDecision   `json:"decisions"`
+	Skips           []SchedulingSkip       `json:"skips,omitempty"`
+	InterruptedJobs []uuid.UUID            `json:"interrupted_jobs,omitempty"`
+	Changes         []string               `json:"changes,omitempty"` // What differs from the previous snapshot
+}
+
+// SchedulingJobState is a job as the scheduler saw it during a cycle
+type SchedulingJobState struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Priority     int       `json:"priority"`
+	Status       string    `json:"status"`
+	ActiveAgents int       `json:"active_agents"`
+	MaxAgents    int       `json:"max_agents"`
+	PendingWork  int       `json:"pending_work"`
+}
+
+// SchedulingAgentState is an agent available for work during a cycle
+type SchedulingAgentState struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// SchedulingDecision is what the scheduler did with an available agent
+type SchedulingDecision struct {
+	AgentID int        `json:"agent_id"`
+	JobID   *uuid.UUID `json:"job_id,omitempty"`
+	Outcome string     `json:"outcome"`
+	Reason  string     `json:"reason,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// SchedulingSkip is a job passed over for some agents for the same reason
+type SchedulingSkip struct {
+	JobID    uuid.UUID `json:"job_id"`
+	Reason   string    `json:"reason"`
+	AgentIDs []int     `json:"agent_ids"`
+}
+
+// JobSchedulingEntry is what the scheduling cycles decided about one job.
+// Consecutive cycles that decided the same are merged into one entry.
+type JobSchedulingEntry struct {
+	Cycle      int64     `json:"cycle"`
+	StartedAt  time.Time `json:"started_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Repeats    int       `json:"repeats"`
+
+	// Considered is false when the job was not among the jobs with pending
+	// work, e.g. because it is paused or already has its maximum of agents
+	Considered      bool                 `json:"considered"`
+	Job             *SchedulingJobState  `json:"job,omitempty"`
+	AvailableAgents int                  `json:"available_agents"`
+	Decisions       []SchedulingDecision `json:"decisions,omitempty"` // Decisions of agents that picked the job
+	Skips           []SchedulingSkip     `json:"skips,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServerHandover is the assignment state recorded when the backend is drained
+// for a restart. The next start keeps these tasks running while their agents
+// reconnect.
+type ServerHandover struct {
+	ID                    int64          `json:"id"`
+	DrainedAt             time.Time      `json:"drained_at"`
+	ReconnectAfterSeconds int            `json:"reconnect_after_seconds"`
+	Tasks                 []HandoverTask `json:"tasks"`
+	ResumedAt             *time.Time     `json:"resumed_at,omitempty"`
+}
+
+// HandoverTask is a task that was in flight when the backend was drained
+type HandoverTask struct {
+	TaskID            uuid.UUID `json:"task_id"`
+	JobExecutionID    uuid.UUID `json:"job_execution_id"`
+	AgentID           int       `json:"agent_id"`
+	Status            string    `json:"status"`
+	KeyspaceProcessed int64     `json:"keyspace_processed"`
+}
+
+// ReconnectAfter is how long agents were told to wait before reconnecting
+func (h *ServerHandover) ReconnectAfter() time.Duration {
+	return time.Duration(h.ReconnectAfterSeconds) * time.Second
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SupportSessionHeader is the request header an admin sends to act as the
+// target user of one of their active support sessions
+const SupportSessionHeader = "X-Support-Session"
+
+// SupportSessionEventType identifies an entry in a support session's audit trail
+type SupportSessionEventType string
+
+const (
+	SupportSessionEventStarted SupportSessionEventType = "started"
+	SupportSessionEventRequest SupportSessionEventType = "request"
+	SupportSessionEventDenied  SupportSessionEventType = "denied"
+	SupportSessionEventEnded   SupportSessionEventType = "ended"
+)
+
+// SupportSession is a time-limited, read-only session in which an admin views
+// the system as another user
+type SupportSession struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	AdminID        *uuid.UUID `json:"admin_id,omitempty" db:"admin_id"`
+	AdminUsername  string     `json:"admin_username" db:"admin_username"`
+	TargetUserID   *uuid.UUID `json:"target_user_id,omitempty" db:"target_user_id"`
+	TargetUsername string     `json:"target_username" db:"target_username"`
+	TargetRole     string     `json:"-"` // Current role of the target user, used when acting as them
+	Reason         string     `json:"reason" db:"reason"`
+	IPAddress      *string    `json:"ip_address,omitempty" db:"ip_address"`
+	UserAgent      *string    `json:"user_agent,omitempty" db:"user_agent"`
+	StartedAt      time.Time  `json:"started_at" db:"started_at"`
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	EndedAt        *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+}
+
+// Status reports whether the session is active, ended early or expired
+func (s *SupportSession) Status() string {
+	switch {
+	case s.EndedAt != nil:
+		return "ended"
+	case !time.Now().Before(s.ExpiresAt):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+// IsActive reports whether the session can still be used
+func (s *SupportSession) IsActive() bool {
+	return s.Status() == "active"
+}
+
+// SupportSessionEvent is one entry in a support session's audit trail
+type SupportSessionEvent struct {
+	ID        int64                   `json:"id" db:"id"`
+	SessionID uuid.UUID               `json:"session_id" db:"session_id"`
+	EventType SupportSessionEventType `json:"event_type" db:"event_type"`
+	Method    *string                 `json:"method,omitempty" db:"method"`
+	Path      *string                 `json:"path,omitempty" db:"path"`
+	Detail    *string                 `json:"detail,omitempty" db:"detail"`
+	CreatedAt time.Time               `json:"created_at" db:"created_at"`
+}
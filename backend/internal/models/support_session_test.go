@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSupportSessionStatus(t *testing.T) {
+	now := time.Now()
+	ended := now.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		session SupportSession
+		want    string
+	}{
+		{"active", SupportSession{ExpiresAt: now.Add(time.Hour)}, "active"},
+		{"expired", SupportSession{ExpiresAt: now.Add(-time.Second)}, "expired"},
+		{"ended early", SupportSession{ExpiresAt: now.Add(time.Hour), EndedAt: &ended}, "ended"},
+		{"ended after expiry", SupportSession{ExpiresAt: now.Add(-time.Hour), EndedAt: &ended}, "ended"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.session.Status(); got != tt.want {
+				t.Errorf("Status() = %q, want %q", got, tt.want)
+			}
+			if got := tt.session.IsActive(); got != (tt.want == "active") {
+				t.Errorf("IsActive() = %v for status %q", got, tt.want)
+			}
+		})
+	}
+}
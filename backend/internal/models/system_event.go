@@ -0,0 +1,73 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// System event severity constants
+const (
+	SystemEventInfo    = "info"
+	SystemEventWarning = "warning"
+	SystemEventError   = "error"
+)
+
+// System event category constants
+const (
+	SystemEventCategoryAgent     = "agent"
+	SystemEventCategorySync      = "sync"
+	SystemEventCategoryScheduler = "scheduler"
+	SystemEventCategoryBenchmark = "benchmark"
+)
+
+// System event type constants
+const (
+	SystemEventAgentConnected      = "agent_connected"
+	SystemEventAgentDisconnected   = "agent_disconnected"
+	SystemEventSyncCompleted       = "sync_completed"
+	SystemEventSyncFailed          = "sync_failed"
+	SystemEventSchedulingFailed    = "scheduling_cycle_failed"
+	SystemEventSchedulingErrors    = "scheduling_cycle_errors"
+	SystemEventStaleRecoveryFailed = "stale_job_recovery_failed"
+	SystemEventBenchmarkTimedOut   = "benchmark_timed_out"
+	SystemEventBenchmarkCompleted  = "benchmark_completed"
+	SystemEventBenchmarkFailed     = "benchmark_failed"
+	SystemEventCredentialsRotated  = "agent_credentials_rotated"
+	SystemEventCredentialsFailed   = "agent_credential_rotation_failed"
+)
+
+// SystemEvent is an operational event recorded for operators, as opposed to
+// the audit log of user actions. EntityType and EntityID reference the record
+// the event is about, if any.
+type SystemEvent struct {
+	ID         int64                  `json:"id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Severity   string                 `json:"severity"`
+	Category   string                 `json:"category"`
+	EventType  string                 `json:"event_type"`
+	Message    string                 `json:"message"`
+	EntityType string                 `json:"entity_type,omitempty"`
+	EntityID   string                 `json:"entity_id,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
+
+// NewAgentSystemEvent returns an event about an agent
+func NewAgentSystemEvent(severity, category, eventType string, agentID int, message string) *SystemEvent {
+	return &SystemEvent{
+		Severity:   severity,
+		Category:   category,
+		EventType:  eventType,
+		Message:    message,
+		EntityType: "agent",
+		EntityID:   strconv.Itoa(agentID),
+	}
+}
+
+// IsValidSystemEventSeverity reports whether severity is a known severity
+func IsValidSystemEventSeverity(severity string) bool {
+	switch severity {
+	case SystemEventInfo, SystemEventWarning, SystemEventError:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAgentSystemEvent(t *testing.T) {
+	event := NewAgentSystemEvent(SystemEventWarning, SystemEventCategoryAgent, SystemEventAgentDisconnected, 42, "Agent 42 disconnected")
+	assert.Equal(t, "agent", event.EntityType)
+	assert.Equal(t, "42", event.EntityID)
+	assert.Equal(t, SystemEventWarning, event.Severity)
+	assert.Equal(t, SystemEventAgentDisconnected, event.EventType)
+}
+
+func TestIsValidSystemEventSeverity(t *testing.T) {
+	for _, severity := range []string{SystemEventInfo, SystemEventWarning, SystemEventError} {
+		assert.True(t, IsValidSystemEventSeverity(severity), severity)
+	}
+	assert.False(t, IsValidSystemEventSeverity("critical"))
+	assert.False(t, IsValidSystemEventSeverity(""))
+}
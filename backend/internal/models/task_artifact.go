@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Names of the files a task artifact can be downloaded as
+const (
+	TaskArtifactOutputFile = "output.log"
+	TaskArtifactStatusFile = "status.json"
+)
+
+// TaskArtifact is what a finished task run left behind: hashcat's last JSON
+// status, its exit code and the end of its output
+type TaskArtifact struct {
+	ID             int64           `json:"id"`
+	TaskID         uuid.UUID       `json:"task_id"`
+	JobExecutionID uuid.UUID       `json:"job_execution_id"`
+	AgentID        *int            `json:"agent_id,omitempty"`
+	Status         string          `json:"status"`
+	ExitCode       *int            `json:"exit_code,omitempty"`
+	ErrorMessage   *string         `json:"error_message,omitempty"`
+	FinalStatus    json.RawMessage `json:"final_status,omitempty"`
+	OutputTail     string          `json:"-"`
+	OutputBytes    int             `json:"output_bytes"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// TailBytes returns at most the last maxBytes bytes of output. A cut starts
+// at the next line when one begins within the kept part, and never splits a
+// UTF-8 character.
+func TailBytes(output string, maxBytes int) string {
+	if maxBytes <= 0 {
+		return ""
+	}
+	if len(output) <= maxBytes {
+		return output
+	}
+	tail := output[len(output)-maxBytes:]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+		return tail[i+1:]
+	}
+	for len(tail) > 0 && !utf8.RuneStart(tail[0]) {
+		tail = tail[1:]
+	}
+	return tail
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		maxBytes int
+		want     string
+	}{
+		{"short output kept", "line one\nline two", 100, "line one\nline two"},
+		{"cut at the next line", "line one\nline two\nline three", 16, "line three"},
+		{"single long line cut", "abcdefghij", 4, "ghij"},
+		{"utf-8 not split", "aéé", 3, "é"},
+		{"zero keeps nothing", "line", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TailBytes(tt.output, tt.maxBytes))
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TaskErrorCode classifies a hashcat failure reported by an agent
+type TaskErrorCode string
+
+const (
+	TaskErrorKernelBuild        TaskErrorCode = "kernel_build_failed"
+	TaskErrorNoDevices          TaskErrorCode = "no_devices"
+	TaskErrorOutOfMemory        TaskErrorCode = "out_of_memory"
+	TaskErrorTokenLength        TaskErrorCode = "token_length_exception"
+	TaskErrorSeparatorUnmatched TaskErrorCode = "separator_unmatched"
+	TaskErrorNoHashesLoaded     TaskErrorCode = "no_hashes_loaded"
+	TaskErrorAlreadyRunning     TaskErrorCode = "already_running"
+	TaskErrorGPUWatchdog        TaskErrorCode = "gpu_watchdog"
+	TaskErrorFileNotFound       TaskErrorCode = "file_not_found"
+	TaskErrorFileMismatch       TaskErrorCode = "file_mismatch"
+	TaskErrorBinaryMismatch     TaskErrorCode = "binary_mismatch"
+	TaskErrorAborted            TaskErrorCode = "aborted"
+	TaskErrorUnknown            TaskErrorCode = "unknown"
+)
+
+// taskErrorPatterns is checked in order; the first match wins. More specific
+// causes come before generic ones since hashcat often prints several lines.
+var taskErrorPatterns = []struct {
+	code    TaskErrorCode
+	pattern *regexp.Regexp
+}{
+	{TaskErrorAlreadyRunning, regexp.MustCompile(`(?i)already an instance .* running on pid|another instance is already running`)},
+	{TaskErrorKernelBuild, regexp.MustCompile(`(?i)clBuildProgram|kernel build failed|nvrtcCompileProgram|hiprtcCompileProgram|cuModuleLoadDataEx`)},
+	{TaskErrorOutOfMemory, regexp.MustCompile(`(?i)CL_MEM_OBJECT_ALLOCATION_FAILURE|CL_OUT_OF_RESOURCES|CUDA_ERROR_OUT_OF_MEMORY|hipErrorOutOfMemory|out of (device )?memory|not enough allocatable device memory`)},
+	{TaskErrorNoDevices, regexp.MustCompile(`(?i)no devices found/left|no (opencl|cuda|hip|metal)[^\n]*(platform|device)s? found|no compatible platform found|all devices? (were|are) skipped`)},
+	{TaskErrorTokenLength, regexp.MustCompile(`(?i)token length exception|line-length exception`)},
+	{TaskErrorSeparatorUnmatched, regexp.MustCompile(`(?i)separator unmatched`)},
+	{TaskErrorNoHashesLoaded, regexp.MustCompile(`(?i)no hashes loaded`)},
+	{TaskErrorGPUWatchdog, regexp.MustCompile(`(?i)watchdog|temperature abort`)},
+	{TaskErrorBinaryMismatch, regexp.MustCompile(`(?i)hashcat binary hash mismatch`)},
+	{TaskErrorFileMismatch, regexp.MustCompile(`(?i)file hash mismatch`)},
+	{TaskErrorFileNotFound, regexp.MustCompile(`(?i)no such file or directory|cannot find the file`)},
+	{TaskErrorAborted, regexp.MustCompile(`(?i)aborted with exit code`)},
+}
+
+var taskErrorRemediation = map[TaskErrorCode]string{
+	TaskErrorKernelBuild:        "Hashcat could not compile its kernels for this device. Update the GPU driver (and OpenCL/CUDA/HIP runtime) on the agent, clear the agent's kernel cache, or try a different hashcat binary version.",
+	TaskErrorNoDevices:          "Hashcat found no usable compute devices. Check that the GPU driver and OpenCL/CUDA/HIP runtime are installed on the agent and that at least one device is enabled in the agent's device settings.",
+	TaskErrorOutOfMemory:        "The device ran out of memory. Reduce the chunk size, use fewer or smaller rule files, lower the workload profile, or disable devices with little memory on this agent.",
+	TaskErrorTokenLength:        "One or more hashes do not match the expected length or format for the selected hash type. Verify the hash type of the hashlist and remove malformed lines.",
+	TaskErrorSeparatorUnmatched: "Hashcat could not find the expected separator in the hash lines. Check that the hashlist format (e.g. hash:salt or user:hash) matches the selected hash type.",
+	TaskErrorNoHashesLoaded:     "Hashcat could not load any hashes from the hashlist. Verify the hash type and the hashlist file contents on the agent.",
+	TaskErrorAlreadyRunning:     "Another hashcat process is already running on the agent. Stop the stray process or restart the agent so the task can be reassigned.",
+	TaskErrorGPUWatchdog:        "The GPU watchdog or temperature limit aborted hashcat. Check cooling on the agent, lower the workload profile, or review the agent's temperature settings.",
+	TaskErrorFileNotFound:       "A wordlist, rule or hashlist file was missing on the agent. Trigger a file sync for the agent or re-upload the missing file.",
+	TaskErrorFileMismatch:       "A wordlist or rule file on the agent no longer matched the server's copy. The agent re-downloads the file and the chunk is dispatched again; if it keeps happening, check the agent's disk for corruption or local edits.",
+	TaskErrorBinaryMismatch:     "The hashcat binary on the agent did not match the one recorded by the binary manager, so the task was not run and the agent was disabled. Check the agent host for tampering, delete its binaries directory so the binary is downloaded again, then re-enable the agent.",
+	TaskErrorAborted:            "Hashcat was aborted before finishing. This is usually the result of a stop request, a checkpoint or a runtime limit; retry the task if it was unexpected.",
+	TaskErrorUnknown:            "The failure could not be classified. Review the agent's hashcat output for details.",
+}
+
+// taskErrorLabels are short descriptions of each cause for failure summaries
+var taskErrorLabels = map[TaskErrorCode]string{
+	TaskErrorKernelBuild:        "kernel build failed",
+	TaskErrorNoDevices:          "no usable devices",
+	TaskErrorOutOfMemory:        "device out of memory",
+	TaskErrorTokenLength:        "hashes do not match the hash type",
+	TaskErrorSeparatorUnmatched: "hash separator unmatched",
+	TaskErrorNoHashesLoaded:     "no hashes loaded",
+	TaskErrorAlreadyRunning:     "hashcat already running",
+	TaskErrorGPUWatchdog:        "GPU watchdog or temperature abort",
+	TaskErrorFileNotFound:       "file missing",
+	TaskErrorFileMismatch:       "file out of date",
+	TaskErrorBinaryMismatch:     "hashcat binary modified",
+	TaskErrorAborted:            "hashcat aborted",
+	TaskErrorUnknown:            "unclassified error",
+}
+
+// missingFileKinds names the kind of a missing file by the directory in its path
+var missingFileKinds = []struct {
+	dir   string
+	label string
+}{
+	{"rules", "rule file missing"},
+	{"wordlists", "wordlist missing"},
+	{"hashlists", "hashlist missing"},
+	{"binaries", "hashcat binary missing"},
+}
+
+// ClassifyTaskError maps the error output reported by an agent to a typed error code
+func ClassifyTaskError(message string) TaskErrorCode {
+	for _, p := range taskErrorPatterns {
+		if p.pattern.MatchString(message) {
+			return p.code
+		}
+	}
+	return TaskErrorUnknown
+}
+
+// RemediationHint returns a human-readable hint for resolving the error
+func (c TaskErrorCode) RemediationHint() string {
+	if hint, ok := taskErrorRemediation[c]; ok {
+		return hint
+	}
+	return taskErrorRemediation[TaskErrorUnknown]
+}
+
+// Label returns a short description of the error for failure summaries
+func (c TaskErrorCode) Label() string {
+	if label, ok := taskErrorLabels[c]; ok {
+		return label
+	}
+	return taskErrorLabels[TaskErrorUnknown]
+}
+
+// TaskFailure is one failed run of a task
+type TaskFailure struct {
+	AgentID      *int
+	AgentName    *string
+	ErrorMessage *string
+}
+
+// JobFailureSummary rolls the failed task runs of a job up by cause, so an
+// operator sees why a job keeps failing without opening each task
+type JobFailureSummary struct {
+	TotalFailures int `json:"total_failures"`
+	// Summary describes the most common cause, e.g. "8/10 failures: rule file
+	// missing on agent gpu-05"
+	Summary string            `json:"summary"`
+	Causes  []JobFailureCause `json:"causes"`
+}
+
+// JobFailureCause is a group of failures with the same cause, most frequent first
+type JobFailureCause struct {
+	ErrorCode       TaskErrorCode     `json:"error_code"`
+	Label           string            `json:"label"`
+	Count           int               `json:"count"`
+	RemediationHint string            `json:"remediation_hint"`
+	Agents          []JobFailureAgent `json:"agents"`
+	// ExampleMessage is the error of one of the failures
+	ExampleMessage string `json:"example_message,omitempty"`
+}
+
+// JobFailureAgent counts the failures of a cause on one agent. AgentID is nil
+// for failures of deleted agents.
+type JobFailureAgent struct {
+	AgentID   *int   `json:"agent_id"`
+	AgentName string `json:"agent_name"`
+	Count     int    `json:"count"`
+}
+
+// SummarizeTaskFailures groups failed task runs by cause and agent. It
+// returns nil when there are no failures.
+func SummarizeTaskFailures(failures []TaskFailure) *JobFailureSummary {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	causes := make(map[string]*JobFailureCause)
+	for _, failure := range failures {
+		message := ""
+		if failure.ErrorMessage != nil {
+			message = *failure.ErrorMessage
+		}
+		code := ClassifyTaskError(message)
+		label := failureLabel(code, message)
+
+		cause, ok := causes[label]
+		if !ok {
+			cause = &JobFailureCause{
+				ErrorCode:       code,
+				Label:           label,
+				RemediationHint: code.RemediationHint(),
+				ExampleMessage:  message,
+			}
+			causes[label] = cause
+		}
+		cause.Count++
+		cause.addAgent(failure.AgentID, failure.AgentName)
+	}
+
+	summary := &JobFailureSummary{TotalFailures: len(failures)}
+	for _, cause := range causes {
+		sort.SliceStable(cause.Agents, func(i, j int) bool { return cause.Agents[i].Count > cause.Agents[j].Count })
+		summary.Causes = append(summary.Causes, *cause)
+	}
+	sort.Slice(summary.Causes, func(i, j int) bool {
+		if summary.Causes[i].Count != summary.Causes[j].Count {
+			return summary.Causes[i].Count > summary.Causes[j].Count
+		}
+		return summary.Causes[i].Label < summary.Causes[j].Label
+	})
+
+	top := summary.Causes[0]
+	summary.Summary = fmt.Sprintf("%d/%d failures: %s", top.Count, summary.TotalFailures, top.Label)
+	if len(top.Agents) == 1 {
+		summary.Summary += " on agent " + top.Agents[0].AgentName
+	} else {
+		summary.Summary += fmt.Sprintf(" on %d agents", len(top.Agents))
+	}
+	return summary
+}
+
+// addAgent counts a failure of the cause on an agent
+func (c *JobFailureCause) addAgent(agentID *int, agentName *string) {
+	for i := range c.Agents {
+		if sameAgent(c.Agents[i].AgentID, agentID) {
+			c.Agents[i].Count++
+			return
+		}
+	}
+
+	name := "unknown"
+	switch {
+	case agentName != nil && *agentName != "":
+		name = *agentName
+	case agentID != nil:
+		name = fmt.Sprintf("%d", *agentID)
+	}
+	c.Agents = append(c.Agents, JobFailureAgent{AgentID: agentID, AgentName: name, Count: 1})
+}
+
+// sameAgent compares two optional agent IDs
+func sameAgent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// failureLabel describes a failure, naming the kind of file for missing files
+func failureLabel(code TaskErrorCode, message string) string {
+	if code == TaskErrorFileNotFound {
+		lower := strings.ToLower(message)
+		for _, kind := range missingFileKinds {
+			if strings.Contains(lower, "/"+kind.dir+"/") || strings.Contains(lower, "\\"+kind.dir+"\\") {
+				return kind.label
+			}
+		}
+	}
+	return code.Label()
+}
@@ -0,0 +1,93 @@
+package models
+
+import "testing"
+
+func TestClassifyTaskError(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    TaskErrorCode
+	}{
+		{"kernel build", "Hashcat exited with unexpected code 255\n* Device #1: clBuildProgram(): CL_BUILD_PROGRAM_FAILURE", TaskErrorKernelBuild},
+		{"no devices", "No devices found/left.", TaskErrorNoDevices},
+		{"no platform", "ATTENTION! No OpenCL, HIP or CUDA compatible platform found.", TaskErrorNoDevices},
+		{"token length", "Hashfile 'hashes.txt' on line 3 (abc): Token length exception", TaskErrorTokenLength},
+		{"separator", "Hashfile 'hashes.txt' on line 1 (x): Separator unmatched", TaskErrorSeparatorUnmatched},
+		{"out of memory", "* Device #1: CUDA_ERROR_OUT_OF_MEMORY", TaskErrorOutOfMemory},
+		{"already running", "Already an instance /opt/hashcat running on pid 4242", TaskErrorAlreadyRunning},
+		{"watchdog", "GPU watchdog alarm - possible GPU hang or temperature issue", TaskErrorGPUWatchdog},
+		{"file mismatch", "file hash mismatch for wordlists/general/rockyou.txt: expected 1f2e, got 9a8b", TaskErrorFileMismatch},
+		{"binary mismatch", "hashcat binary hash mismatch for hashcat.bin: expected 5d41, got 7c21", TaskErrorBinaryMismatch},
+		{"unknown", "Hashcat exited with unexpected code 42", TaskErrorUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyTaskError(tt.message); got != tt.want {
+				t.Errorf("ClassifyTaskError() = %q, want %q", got, tt.want)
+			}
+			if tt.want.RemediationHint() == "" {
+				t.Errorf("missing remediation hint for %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeTaskFailures(t *testing.T) {
+	if SummarizeTaskFailures(nil) != nil {
+		t.Fatal("expected no summary without failures")
+	}
+
+	agent5, agent7 := 5, 7
+	name5 := "gpu-05"
+	missingRule := "open /var/lib/krakenhashes-agent/data/rules/best64.rule: no such file or directory"
+	oom := "* Device #1: CUDA_ERROR_OUT_OF_MEMORY"
+
+	var failures []TaskFailure
+	for i := 0; i < 8; i++ {
+		failures = append(failures, TaskFailure{AgentID: &agent5, AgentName: &name5, ErrorMessage: &missingRule})
+	}
+	failures = append(failures,
+		TaskFailure{AgentID: &agent7, ErrorMessage: &oom},
+		TaskFailure{AgentID: nil},
+	)
+
+	summary := SummarizeTaskFailures(failures)
+	if summary.TotalFailures != 10 {
+		t.Errorf("TotalFailures = %d, want 10", summary.TotalFailures)
+	}
+	if want := "8/10 failures: rule file missing on agent gpu-05"; summary.Summary != want {
+		t.Errorf("Summary = %q, want %q", summary.Summary, want)
+	}
+	if len(summary.Causes) != 3 {
+		t.Fatalf("got %d causes, want 3", len(summary.Causes))
+	}
+
+	top := summary.Causes[0]
+	if top.ErrorCode != TaskErrorFileNotFound || top.Count != 8 || len(top.Agents) != 1 || top.Agents[0].Count != 8 {
+		t.Errorf("unexpected top cause %+v", top)
+	}
+	if top.RemediationHint == "" || top.ExampleMessage != missingRule {
+		t.Errorf("top cause misses hint or example: %+v", top)
+	}
+	for _, cause := range summary.Causes[1:] {
+		if cause.Count != 1 {
+			t.Errorf("cause %q count = %d, want 1", cause.Label, cause.Count)
+		}
+	}
+	if agent := summary.Causes[1].Agents[0]; agent.AgentName != "7" && agent.AgentName != "unknown" {
+		t.Errorf("unexpected agent name %q", agent.AgentName)
+	}
+}
+
+func TestSummarizeTaskFailuresAcrossAgents(t *testing.T) {
+	agent1, agent2 := 1, 2
+	message := "No devices found/left."
+	summary := SummarizeTaskFailures([]TaskFailure{
+		{AgentID: &agent1, ErrorMessage: &message},
+		{AgentID: &agent2, ErrorMessage: &message},
+	})
+	if want := "2/2 failures: no usable devices on 2 agents"; summary.Summary != want {
+		t.Errorf("Summary = %q, want %q", summary.Summary, want)
+	}
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TelemetrySchemaVersion is the version of the reports published by this build
+const TelemetrySchemaVersion = 1
+
+// Limits on reports accepted by a collector
+const (
+	maxTelemetryLabelLength = 100
+	maxTelemetryEntries     = 1000
+	maxTelemetryClockSkew   = 24 * time.Hour
+)
+
+// TelemetryReport is the anonymized statistics an instance publishes to a
+// collector. It only holds aggregates per hash type and attack mode, never
+// hashes, plaintexts, usernames or client and hashlist names.
+type TelemetryReport struct {
+	SchemaVersion int                   `json:"schema_version"`
+	InstanceID    uuid.UUID             `json:"instance_id"`
+	InstanceLabel string                `json:"instance_label,omitempty"`
+	PeriodStart   time.Time             `json:"period_start"`
+	PeriodEnd     time.Time             `json:"period_end"`
+	Agents        int                   `json:"agents"`
+	HashTypes     []TelemetryHashType   `json:"hash_types"`
+	AttackModes   []TelemetryAttackMode `json:"attack_modes"`
+}
+
+// TelemetryHashType summarizes the hashlists of one hash type
+type TelemetryHashType struct {
+	HashTypeID    int     `json:"hash_type_id"`
+	Hashlists     int     `json:"hashlists"`
+	TotalHashes   int64   `json:"total_hashes"`
+	CrackedHashes int64   `json:"cracked_hashes"`
+	CrackRate     float64 `json:"crack_rate"`
+}
+
+// TelemetryAttackMode summarizes the jobs of one attack mode that finished
+// during the report period
+type TelemetryAttackMode struct {
+	AttackMode    int     `json:"attack_mode"`
+	Jobs          int     `json:"jobs"`
+	CompletedJobs int     `json:"completed_jobs"`
+	Cracks        int64   `json:"cracks"`
+	RunSeconds    int64   `json:"run_seconds"`
+	CracksPerHour float64 `json:"cracks_per_hour"`
+}
+
+// TelemetryInstanceReport is a report stored by a collector
+type TelemetryInstanceReport struct {
+	ID         int64           `json:"id"`
+	ReceivedAt time.Time       `json:"received_at"`
+	Report     TelemetryReport `json:"report"`
+}
+
+// FillRates derives the crack rates from the counts
+func (r *TelemetryReport) FillRates() {
+	for i := range r.HashTypes {
+		h := &r.HashTypes[i]
+		h.CrackRate = 0
+		if h.TotalHashes > 0 {
+			h.CrackRate = float64(h.CrackedHashes) / float64(h.TotalHashes)
+		}
+	}
+	for i := range r.AttackModes {
+		a := &r.AttackModes[i]
+		a.CracksPerHour = 0
+		if a.RunSeconds > 0 {
+			a.CracksPerHour = float64(a.Cracks) * 3600 / float64(a.RunSeconds)
+		}
+	}
+}
+
+// Validate checks a report received by a collector
+func (r *TelemetryReport) Validate(now time.Time) error {
+	if r.SchemaVersion < 1 || r.SchemaVersion > TelemetrySchemaVersion {
+		return fmt.Errorf("unsupported schema version %d", r.SchemaVersion)
+	}
+	if r.InstanceID == uuid.Nil {
+		return errors.New("instance_id is required")
+	}
+	if len(r.InstanceLabel) > maxTelemetryLabelLength {
+		return fmt.Errorf("instance_label must be at most %d characters", maxTelemetryLabelLength)
+	}
+	if r.PeriodStart.IsZero() || !r.PeriodEnd.After(r.PeriodStart) {
+		return errors.New("period_end must be after period_start")
+	}
+	if r.PeriodEnd.After(now.Add(maxTelemetryClockSkew)) {
+		return errors.New("period_end is in the future")
+	}
+	if r.Agents < 0 {
+		return errors.New("agents must not be negative")
+	}
+	if len(r.HashTypes) > maxTelemetryEntries || len(r.AttackModes) > maxTelemetryEntries {
+		return fmt.Errorf("reports may contain at most %d hash types and attack modes", maxTelemetryEntries)
+	}
+	for _, h := range r.HashTypes {
+		if h.Hashlists < 0 || h.TotalHashes < 0 || h.CrackedHashes < 0 || h.CrackedHashes > h.TotalHashes {
+			return fmt.Errorf("invalid counts for hash type %d", h.HashTypeID)
+		}
+	}
+	for _, a := range r.AttackModes {
+		if a.Jobs < 0 || a.CompletedJobs < 0 || a.CompletedJobs > a.Jobs || a.Cracks < 0 || a.RunSeconds < 0 {
+			return fmt.Errorf("invalid counts for attack mode %d", a.AttackMode)
+		}
+	}
+	return nil
+}
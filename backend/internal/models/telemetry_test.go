@@ -0,0 +1,77 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTelemetryReportFillRates(t *testing.T) {
+	report := TelemetryReport{
+		HashTypes: []TelemetryHashType{
+			{HashTypeID: 1000, TotalHashes: 200, CrackedHashes: 50},
+			{HashTypeID: 0},
+		},
+		AttackModes: []TelemetryAttackMode{
+			{AttackMode: 0, Cracks: 30, RunSeconds: 1800},
+			{AttackMode: 3, Cracks: 5},
+		},
+	}
+
+	report.FillRates()
+
+	if got := report.HashTypes[0].CrackRate; math.Abs(got-0.25) > 1e-9 {
+		t.Errorf("CrackRate = %v, want 0.25", got)
+	}
+	if got := report.HashTypes[1].CrackRate; got != 0 {
+		t.Errorf("CrackRate without hashes = %v, want 0", got)
+	}
+	if got := report.AttackModes[0].CracksPerHour; math.Abs(got-60) > 1e-9 {
+		t.Errorf("CracksPerHour = %v, want 60", got)
+	}
+	if got := report.AttackModes[1].CracksPerHour; got != 0 {
+		t.Errorf("CracksPerHour without run time = %v, want 0", got)
+	}
+}
+
+func TestTelemetryReportValidate(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	valid := func() TelemetryReport {
+		return TelemetryReport{
+			SchemaVersion: TelemetrySchemaVersion,
+			InstanceID:    uuid.New(),
+			PeriodStart:   now.Add(-24 * time.Hour),
+			PeriodEnd:     now,
+			Agents:        4,
+			HashTypes:     []TelemetryHashType{{HashTypeID: 1000, Hashlists: 2, TotalHashes: 10, CrackedHashes: 4}},
+			AttackModes:   []TelemetryAttackMode{{AttackMode: 0, Jobs: 3, CompletedJobs: 2, Cracks: 4, RunSeconds: 600}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(r *TelemetryReport)
+		wantErr bool
+	}{
+		{"valid", func(r *TelemetryReport) {}, false},
+		{"unknown schema", func(r *TelemetryReport) { r.SchemaVersion = TelemetrySchemaVersion + 1 }, true},
+		{"missing instance", func(r *TelemetryReport) { r.InstanceID = uuid.Nil }, true},
+		{"empty period", func(r *TelemetryReport) { r.PeriodEnd = r.PeriodStart }, true},
+		{"future period", func(r *TelemetryReport) { r.PeriodEnd = now.Add(48 * time.Hour) }, true},
+		{"more cracked than total", func(r *TelemetryReport) { r.HashTypes[0].CrackedHashes = 11 }, true},
+		{"negative cracks", func(r *TelemetryReport) { r.AttackModes[0].Cracks = -1 }, true},
+		{"more completed than jobs", func(r *TelemetryReport) { r.AttackModes[0].CompletedJobs = 4 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := valid()
+			tt.modify(&report)
+			if err := report.Validate(now); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
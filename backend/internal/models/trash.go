@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrashItemType identifies the kind of record held in the trash
+type TrashItemType string
+
+const (
+	TrashItemHashlist TrashItemType = "hashlist"
+	TrashItemJob      TrashItemType = "job"
+	TrashItemClient   TrashItemType = "client"
+)
+
+// IsValid reports whether t is a known trash item type
+func (t TrashItemType) IsValid() bool {
+	switch t {
+	case TrashItemHashlist, TrashItemJob, TrashItemClient:
+		return true
+	}
+	return false
+}
+
+// TrashItem is a soft-deleted hashlist, job or client awaiting restore or purge
+type TrashItem struct {
+	Type              TrashItemType `json:"type" db:"type"`
+	ID                string        `json:"id" db:"id"`
+	Name              string        `json:"name" db:"name"`
+	DeletedAt         time.Time     `json:"deleted_at" db:"deleted_at"`
+	DeletedBy         *uuid.UUID    `json:"deleted_by,omitempty" db:"deleted_by"`
+	DeletedByUsername *string       `json:"deleted_by_username,omitempty" db:"deleted_by_username"`
+	PurgeAt           *time.Time    `json:"purge_at,omitempty"` // Set when a trash retention window applies
+}
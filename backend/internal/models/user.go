@@ -44,6 +44,7 @@ type User struct {
 // NotificationPreferences represents user notification settings
 type NotificationPreferences struct {
 	NotifyOnJobCompletion bool    `json:"notifyOnJobCompletion"`
+	NotificationDigest    string  `json:"notificationDigest"` // off, hourly or daily, empty leaves it unchanged
 	EmailConfigured       bool    `json:"emailConfigured"`
 }
 
@@ -29,23 +29,25 @@ const (
 // Wordlist represents the structure of the 'wordlists' table.
 // Note: Add other fields from migration 000013 if needed for other contexts.
 type Wordlist struct {
-	ID                 int       `json:"id" db:"id"`
-	Name               string    `json:"name" db:"name"`
-	Description        string    `json:"description"`
-	WordlistType       string    `json:"wordlist_type"` // e.g., "dictionary", "password", "custom"
-	Format             string    `json:"format"`        // e.g., "txt", "gz", "zip"
-	FileName           string    `json:"file_name"`
-	MD5Hash            string    `json:"md5_hash"`
-	FileSize           int64     `json:"file_size" db:"file_size"`
-	WordCount          int64     `json:"word_count"`
-	CreatedAt          time.Time `json:"created_at" db:"created_at"`
-	CreatedBy          uuid.UUID `json:"created_by" db:"created_by"`
-	UpdatedAt          time.Time `json:"updated_at"`
-	UpdatedBy          uuid.UUID `json:"updated_by,omitempty"`
-	LastVerifiedAt     time.Time `json:"last_verified_at,omitempty"`
-	VerificationStatus string    `json:"verification_status"` // e.g., "pending", "verified", "failed"
-	IsPotfile          bool      `json:"is_potfile" db:"is_potfile"`
-	Tags               []string  `json:"tags,omitempty"`
+	ID                 int        `json:"id" db:"id"`
+	Name               string     `json:"name" db:"name"`
+	Description        string     `json:"description"`
+	WordlistType       string     `json:"wordlist_type"` // e.g., "dictionary", "password", "custom"
+	Format             string     `json:"format"`        // e.g., "txt", "gz", "zip"
+	FileName           string     `json:"file_name"`
+	MD5Hash            string     `json:"md5_hash"`
+	FileSize           int64      `json:"file_size" db:"file_size"`
+	WordCount          int64      `json:"word_count"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	CreatedBy          uuid.UUID  `json:"created_by" db:"created_by"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	UpdatedBy          uuid.UUID  `json:"updated_by,omitempty"`
+	LastVerifiedAt     time.Time  `json:"last_verified_at,omitempty"`
+	VerificationStatus string     `json:"verification_status"` // e.g., "pending", "verified", "failed"
+	IsPotfile          bool       `json:"is_potfile" db:"is_potfile"`
+	FileModifiedAt     *time.Time `json:"file_modified_at,omitempty"` // File mtime when last hashed by the directory monitor
+	Tags               []string   `json:"tags,omitempty"`
+	Aliases            []string   `json:"aliases,omitempty"` // Names duplicate uploads were aliased under
 }
 
 // WordlistBasic is a subset of Wordlist used for simple listings (e.g., form data).
@@ -105,3 +107,23 @@ type WordlistAuditLog struct {
 	PerformedAt time.Time `json:"performed_at" db:"performed_at"`
 	Details     []byte    `json:"details" db:"details"`
 }
+
+// Wordlist preview modes
+const (
+	WordlistPreviewHead   = "head"   // The first lines of the file
+	WordlistPreviewRandom = "random" // Lines picked at random positions
+)
+
+// WordlistPreview is a sample of a wordlist's lines for checking its content
+// and encoding without downloading it
+type WordlistPreview struct {
+	WordlistID int      `json:"wordlist_id"`
+	Mode       string   `json:"mode"`
+	Lines      []string `json:"lines"` // Lines that are not printable UTF-8 are in $HEX[...] notation
+	// Sampled from the first part of a compressed file only
+	Partial        bool `json:"partial"`
+	HasBOM         bool `json:"has_bom"`
+	NonUTF8Lines   int  `json:"non_utf8_lines"`
+	CRLFLines      int  `json:"crlf_lines"`
+	TruncatedLines int  `json:"truncated_lines"` // Lines cut to the maximum preview line length
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// WordlistCollection is a named, ordered set of wordlists, e.g. "standard-big"
+// for rockyou, crackstation and a weakpass slice. A preset job referencing a
+// collection is expanded into one job per wordlist when jobs are created from
+// it, so editing the collection changes every job created afterwards.
+type WordlistCollection struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	WordlistIDs pq.Int64Array   `json:"wordlist_ids"`
+	Wordlists   []WordlistBasic `json:"wordlists,omitempty"` // Members that still exist, in collection order
+	CreatedBy   *uuid.UUID      `json:"created_by,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// CollectionAttackMode reports whether jobs of an attack mode can take their
+// wordlist from a collection. Only modes using a single wordlist can, each
+// expanded job runs one wordlist of the collection.
+func CollectionAttackMode(mode AttackMode) bool {
+	switch mode {
+	case AttackModeStraight, AttackModeHybridWordlistMask, AttackModeHybridMaskWordlist:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExpandedPresetJob is a preset job to create one job from. Presets
+// referencing a collection expand into one per wordlist, named in Wordlist.
+type ExpandedPresetJob struct {
+	Preset   *PresetJob
+	Wordlist string
+}
+
+// JobName names the job of an expanded preset after the requested job name
+func (e ExpandedPresetJob) JobName(jobName string) string {
+	if e.Wordlist == "" {
+		return jobName
+	}
+	return fmt.Sprintf("%s - %s", jobName, e.Wordlist)
+}
+
+// ExpandCollection returns a copy of the preset for each wordlist of its
+// collection, in collection order. The copies keep the preset's ID for the
+// audit trail but have no pre-calculated keyspace, it is calculated for each
+// wordlist when its job is created.
+func (p *PresetJob) ExpandCollection(wordlists []WordlistBasic) []ExpandedPresetJob {
+	expanded := make([]ExpandedPresetJob, 0, len(wordlists))
+	for _, wordlist := range wordlists {
+		preset := *p
+		preset.WordlistIDs = IDArray{strconv.Itoa(wordlist.ID)}
+		preset.WordlistCollectionID = nil
+		preset.Keyspace = nil
+		expanded = append(expanded, ExpandedPresetJob{Preset: &preset, Wordlist: wordlist.Name})
+	}
+	return expanded
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresetJobExpandCollection(t *testing.T) {
+	collectionID := 3
+	keyspace := int64(1000)
+	preset := &PresetJob{
+		ID:                   uuid.New(),
+		Name:                 "Big dictionary",
+		WordlistCollectionID: &collectionID,
+		RuleIDs:              IDArray{"7"},
+		AttackMode:           AttackModeStraight,
+		Keyspace:             &keyspace,
+	}
+
+	expanded := preset.ExpandCollection([]WordlistBasic{{ID: 1, Name: "rockyou.txt"}, {ID: 4, Name: "crackstation.txt"}})
+	require.Len(t, expanded, 2)
+	assert.Equal(t, IDArray{"1"}, expanded[0].Preset.WordlistIDs)
+	assert.Equal(t, IDArray{"4"}, expanded[1].Preset.WordlistIDs)
+	for _, e := range expanded {
+		assert.Equal(t, preset.ID, e.Preset.ID)
+		assert.Equal(t, preset.RuleIDs, e.Preset.RuleIDs)
+		assert.Nil(t, e.Preset.WordlistCollectionID)
+		assert.Nil(t, e.Preset.Keyspace)
+	}
+	assert.Equal(t, "Client - crackstation.txt", expanded[1].JobName("Client"))
+
+	// The preset itself is left untouched
+	assert.Equal(t, &collectionID, preset.WordlistCollectionID)
+	assert.Equal(t, &keyspace, preset.Keyspace)
+	assert.Empty(t, preset.WordlistIDs)
+
+	assert.Equal(t, "Client", ExpandedPresetJob{Preset: preset}.JobName("Client"))
+}
+
+func TestCollectionAttackMode(t *testing.T) {
+	assert.True(t, CollectionAttackMode(AttackModeStraight))
+	assert.True(t, CollectionAttackMode(AttackModeHybridWordlistMask))
+	assert.True(t, CollectionAttackMode(AttackModeHybridMaskWordlist))
+	assert.False(t, CollectionAttackMode(AttackModeCombination))
+	assert.False(t, CollectionAttackMode(AttackModeBruteForce))
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/wordlist"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/fsutil"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/textnorm"
 	"github.com/google/uuid"
 )
 
@@ -118,6 +119,107 @@ func (m *DirectoryMonitor) Start() {
 			}
 		}
 	}()
+
+	// Pick up changes within seconds where file system notifications are
+	// available, the periodic scans above remain as a safety net
+	watcher, err := newFileWatcher(m.wordlistDir, m.ruleDir)
+	if err != nil {
+		debug.Warning("File system notifications unavailable, relying on directory scans every %s: %v", m.interval, err)
+		return
+	}
+	m.wg.Add(1)
+	go m.watchDirectories(watcher)
+}
+
+// watchDirectories checks paths reported by the watcher once they have been
+// quiet for watchDebounce
+func (m *DirectoryMonitor) watchDirectories(watcher fileWatcher) {
+	defer m.wg.Done()
+	defer watcher.Close()
+
+	pending := make(map[string]time.Time)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events():
+			if !ok {
+				debug.Warning("File watcher stopped, relying on directory scans")
+				return
+			}
+			if event.overflow {
+				debug.Warning("File system events were dropped, rescanning directories")
+				go m.checkWordlistDirectory()
+				go m.checkRuleDirectory()
+				continue
+			}
+			if event.removed {
+				// Nothing is left to check at the path or below it
+				for path := range pending {
+					if path == event.path || isWithin(event.path, path) {
+						delete(pending, path)
+					}
+				}
+				continue
+			}
+			pending[event.path] = time.Now()
+		case now := <-ticker.C:
+			for path, last := range pending {
+				if now.Sub(last) >= watchDebounce {
+					delete(pending, path)
+					m.checkChangedPath(path)
+				}
+			}
+		case <-m.stopChan:
+			debug.Info("Stopping directory watcher")
+			return
+		}
+	}
+}
+
+// checkChangedPath queues a changed file, or the files in a new directory,
+// for processing
+func (m *DirectoryMonitor) checkChangedPath(path string) {
+	var queue func(path string, info os.FileInfo, watched bool)
+	switch {
+	case isWithin(m.wordlistDir, path):
+		queue = m.queueWordlistFile
+	case isWithin(m.ruleDir, path):
+		queue = m.queueRuleFile
+	default:
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		// Removed or moved away again before it settled
+		return
+	}
+	if !info.IsDir() {
+		queue(path, info, true)
+		return
+	}
+
+	err = filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if fi.IsDir() && p != path && strings.HasPrefix(fi.Name(), ".") {
+			return filepath.SkipDir
+		}
+		queue(p, fi, true)
+		return nil
+	})
+	if err != nil {
+		debug.Error("Error walking directory %s: %v", path, err)
+	}
+}
+
+// isWithin reports whether path is below dir
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 // Stop stops monitoring directories
@@ -167,6 +269,30 @@ func (m *DirectoryMonitor) isFileStable(path string, waitDuration time.Duration)
 	return true, nil
 }
 
+// isStable reports whether a file is no longer being written. Files reported by
+// the watcher have had no events for watchDebounce, so their modification time
+// is enough, polled files get the full stability check.
+func (m *DirectoryMonitor) isStable(path string, info os.FileInfo, watched bool) bool {
+	if watched {
+		return time.Since(info.ModTime()) >= watchDebounce
+	}
+
+	isStable, err := m.isFileStable(path, 30*time.Second)
+	if err != nil {
+		debug.Error("Error checking if file is stable: %s: %v", path, err)
+		return false
+	}
+	return isStable
+}
+
+// fileUnchanged reports whether a file still has the size and modification
+// time recorded when it was last hashed. The database keeps microseconds.
+func fileUnchanged(recordedSize int64, recordedModTime *time.Time, info os.FileInfo) bool {
+	return recordedModTime != nil &&
+		recordedSize == info.Size() &&
+		recordedModTime.Equal(info.ModTime().Truncate(time.Microsecond))
+}
+
 // checkWordlistDirectory checks for new or modified wordlist files
 func (m *DirectoryMonitor) checkWordlistDirectory() {
 	debug.Debug("Checking wordlist directory: %s", m.wordlistDir)
@@ -184,115 +310,154 @@ func (m *DirectoryMonitor) checkWordlistDirectory() {
 			return nil // Continue walking
 		}
 
-		// Skip directories and hidden files
-		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
-			return nil
-		}
+		m.queueWordlistFile(path, info, false)
+		return nil
+	})
 
-		// Get relative path
-		relPath, err := filepath.Rel(m.wordlistDir, path)
-		if err != nil {
-			debug.Error("Failed to get relative path for %s: %v", path, err)
-			return nil
-		}
+	if err != nil {
+		debug.Error("Error walking wordlist directory: %v", err)
+	}
+}
 
-		// Skip potfile explicitly
-		if info.Name() == "potfile.txt" || strings.Contains(relPath, "custom/potfile.txt") {
-			debug.Debug("Skipping pot-file from directory monitoring: %s", relPath)
-			return nil
-		}
+// queueWordlistFile hands a wordlist file to a worker if it is new or changed.
+// Files whose size and modification time match what was recorded when they
+// were last hashed are skipped without reading them. watched is set for files
+// reported by the file watcher, which have already been quiet for
+// watchDebounce, otherwise the file must have been stable for 30 seconds.
+func (m *DirectoryMonitor) queueWordlistFile(path string, info os.FileInfo, watched bool) {
+	// Skip directories and hidden files
+	if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+		return
+	}
 
-		// Skip if already being processed
-		if _, isProcessing := m.processingFiles.Load(relPath); isProcessing {
-			debug.Debug("Skipping file that is already being processed: %s", relPath)
-			return nil
-		}
+	// Get relative path
+	relPath, err := filepath.Rel(m.wordlistDir, path)
+	if err != nil {
+		debug.Error("Failed to get relative path for %s: %v", path, err)
+		return
+	}
 
-		// Check if file is stable (not being written)
-		isStable, err := m.isFileStable(path, 30*time.Second)
-		if err != nil {
-			debug.Error("Error checking if file is stable: %s: %v", path, err)
-			return nil
-		}
+	// Skip potfile explicitly
+	if info.Name() == "potfile.txt" || strings.Contains(relPath, "custom/potfile.txt") {
+		debug.Debug("Skipping pot-file from directory monitoring: %s", relPath)
+		return
+	}
 
-		if !isStable {
-			debug.Debug("Skipping file that appears to be still transferring: %s", path)
-			return nil
-		}
+	// Skip if already being processed
+	if _, isProcessing := m.processingFiles.Load(relPath); isProcessing {
+		debug.Debug("Skipping file that is already being processed: %s", relPath)
+		return
+	}
 
-		// Mark file as being processed
-		m.processingFiles.Store(relPath, true)
-		m.fileStatuses.Store(relPath, "queued")
+	// Skip files that have not changed since they were last hashed
+	existingWordlist, err := m.wordlistManager.GetWordlistByFilename(context.Background(), relPath)
+	if err != nil {
+		debug.Error("Error checking if wordlist exists: %v", err)
+		return
+	}
+	if existingWordlist != nil && fileUnchanged(existingWordlist.FileSize, existingWordlist.FileModifiedAt, info) {
+		return
+	}
 
-		// Process file in a worker goroutine
-		go func(fullPath, relPath string) {
-			// Acquire worker semaphore slot
-			m.workerSem <- struct{}{}
-			defer func() {
-				<-m.workerSem
-				m.processingFiles.Delete(relPath)
-				m.fileStatuses.Delete(relPath)
-			}()
+	// Check if file is stable (not being written)
+	if !m.isStable(path, info, watched) {
+		debug.Debug("Skipping file that appears to be still transferring: %s", path)
+		return
+	}
 
-			m.fileStatuses.Store(relPath, "processing")
-			debug.Info("Processing wordlist file: %s", relPath)
+	// Mark file as being processed
+	m.processingFiles.Store(relPath, true)
+	m.fileStatuses.Store(relPath, "queued")
 
-			ctx := context.Background()
+	// Process file in a worker goroutine
+	go func(fullPath, relPath string, modTime time.Time) {
+		// Acquire worker semaphore slot
+		m.workerSem <- struct{}{}
+		defer func() {
+			<-m.workerSem
+			m.processingFiles.Delete(relPath)
+			m.fileStatuses.Delete(relPath)
+		}()
 
-			// Calculate MD5 hash first (faster than counting lines)
-			m.fileStatuses.Store(relPath, "calculating hash")
-			md5Hash, err := calculateFileMD5(fullPath)
-			if err != nil {
-				debug.Error("Failed to calculate MD5 hash for %s: %v", fullPath, err)
-				m.fileStatuses.Store(relPath, "error: "+err.Error())
-				return
-			}
+		m.fileStatuses.Store(relPath, "processing")
+		debug.Info("Processing wordlist file: %s", relPath)
 
-			// Check if file exists in database
-			existingWordlist, err := m.wordlistManager.GetWordlistByFilename(ctx, relPath)
-			if err != nil {
-				debug.Error("Error checking if wordlist exists: %v", err)
-				m.fileStatuses.Store(relPath, "error: "+err.Error())
-				return
-			}
+		ctx := context.Background()
 
-			// If file exists with same MD5, skip it
-			if existingWordlist != nil && existingWordlist.MD5Hash == md5Hash {
-				debug.Debug("Skipping wordlist with unchanged hash: %s", relPath)
-				m.fileStatuses.Store(relPath, "unchanged")
-				return
-			}
-			
-			// Skip pot-file from monitoring
-			if existingWordlist != nil && existingWordlist.IsPotfile {
-				debug.Info("Skipping pot-file from monitoring due to is_potfile flag: %s (ID: %d)", relPath, existingWordlist.ID)
-				m.fileStatuses.Store(relPath, "potfile-excluded")
-				return
-			}
+		// Calculate MD5 hash first (faster than counting lines)
+		m.fileStatuses.Store(relPath, "calculating hash")
+		md5Hash, err := calculateFileMD5(fullPath)
+		if err != nil {
+			debug.Error("Failed to calculate MD5 hash for %s: %v", fullPath, err)
+			m.fileStatuses.Store(relPath, "error: "+err.Error())
+			return
+		}
 
-			// If file exists but MD5 is different, update it
-			if existingWordlist != nil {
-				debug.Info("Found modified wordlist file: %s", relPath)
-				m.fileStatuses.Store(relPath, "updating")
-				m.updateExistingWordlist(ctx, fullPath, relPath, existingWordlist.ID, md5Hash)
-			} else {
-				// Process new file
-				debug.Info("Found new wordlist file: %s", relPath)
-				m.fileStatuses.Store(relPath, "adding")
-				m.processNewWordlistFile(ctx, fullPath, relPath, md5Hash)
-			}
-		}(path, relPath)
+		// Check if file exists in database
+		existingWordlist, err := m.wordlistManager.GetWordlistByFilename(ctx, relPath)
+		if err != nil {
+			debug.Error("Error checking if wordlist exists: %v", err)
+			m.fileStatuses.Store(relPath, "error: "+err.Error())
+			return
+		}
 
-		return nil
-	})
+		// If file exists with same MD5, skip it
+		if existingWordlist != nil && existingWordlist.MD5Hash == md5Hash {
+			debug.Debug("Skipping wordlist with unchanged hash: %s", relPath)
+			m.fileStatuses.Store(relPath, "unchanged")
+			m.recordWordlistModTime(ctx, existingWordlist.ID, modTime)
+			return
+		}
+
+		// Skip pot-file from monitoring
+		if existingWordlist != nil && existingWordlist.IsPotfile {
+			debug.Info("Skipping pot-file from monitoring due to is_potfile flag: %s (ID: %d)", relPath, existingWordlist.ID)
+			m.fileStatuses.Store(relPath, "potfile-excluded")
+			return
+		}
+
+		// If file exists but MD5 is different, update it
+		if existingWordlist != nil {
+			debug.Info("Found modified wordlist file: %s", relPath)
+			m.fileStatuses.Store(relPath, "updating")
+			m.updateExistingWordlist(ctx, fullPath, relPath, existingWordlist.ID, md5Hash, modTime)
+		} else if m.recordMovedWordlist(ctx, relPath, md5Hash, modTime) {
+			m.fileStatuses.Store(relPath, "moved")
+		} else {
+			// Process new file
+			debug.Info("Found new wordlist file: %s", relPath)
+			m.fileStatuses.Store(relPath, "adding")
+			m.processNewWordlistFile(ctx, fullPath, relPath, md5Hash, modTime)
+		}
+	}(path, relPath, info.ModTime())
+}
 
+// recordWordlistModTime records the modification time a wordlist file had when
+// it was hashed, so later checks can skip it while it is unchanged
+func (m *DirectoryMonitor) recordWordlistModTime(ctx context.Context, wordlistID int, modTime time.Time) {
+	if err := m.wordlistManager.UpdateWordlistFileModTime(ctx, wordlistID, modTime); err != nil {
+		debug.Error("Failed to record modification time of wordlist %d: %v", wordlistID, err)
+	}
+}
+
+// recordWordlistCanonicalHash hashes a plain wordlist file the way uploads
+// are compared for near-duplicates and records it
+func (m *DirectoryMonitor) recordWordlistCanonicalHash(ctx context.Context, wordlistID int, fullPath, relPath string) {
+	if determineFormat(relPath) != "plaintext" {
+		return
+	}
+	canonicalHash, err := textnorm.HashFile(fullPath, false)
 	if err != nil {
-		debug.Error("Error walking wordlist directory: %v", err)
+		debug.Error("Failed to calculate canonical hash of %s: %v", fullPath, err)
+		return
+	}
+	if err := m.wordlistManager.UpdateWordlistCanonicalHash(ctx, wordlistID, canonicalHash); err != nil {
+		debug.Error("Failed to record canonical hash of wordlist %d: %v", wordlistID, err)
 	}
 }
 
 // processNewWordlistFile processes a new wordlist file
-func (m *DirectoryMonitor) processNewWordlistFile(ctx context.Context, fullPath, relPath string, md5Hash string) {
+func (m *DirectoryMonitor) processNewWordlistFile(ctx context.Context, fullPath, relPath string, md5Hash string, modTime time.Time) {
 	// Get file info
 	fileInfo, err := os.Stat(fullPath)
 	if err != nil {
@@ -327,9 +492,12 @@ func (m *DirectoryMonitor) processNewWordlistFile(ctx context.Context, fullPath,
 		debug.Error("Failed to add wordlist %s: %v", relPath, err)
 		return
 	}
+	m.recordWordlistModTime(ctx, wordlist.ID, modTime)
 
 	// Count words in a separate goroutine
 	go func() {
+		m.recordWordlistCanonicalHash(ctx, wordlist.ID, fullPath, relPath)
+
 		debug.Info("Counting words in new wordlist: %s", relPath)
 		m.fileStatuses.Store(relPath, "counting words")
 
@@ -357,6 +525,28 @@ func (m *DirectoryMonitor) processNewWordlistFile(ctx context.Context, fullPath,
 	}()
 }
 
+// recordMovedWordlist checks whether a file without a database record is a
+// known wordlist that was moved on disk, by its MD5 hash and the old file being
+// gone. If so the new location is recorded instead of importing a duplicate,
+// which also lets agents move their copy rather than downloading it again.
+func (m *DirectoryMonitor) recordMovedWordlist(ctx context.Context, relPath, md5Hash string, modTime time.Time) bool {
+	existing, err := m.wordlistManager.GetWordlistByMD5Hash(ctx, md5Hash)
+	if err != nil || existing == nil || existing.IsPotfile {
+		return false
+	}
+	if fsutil.FileExists(filepath.Join(m.wordlistDir, existing.FileName)) {
+		return false
+	}
+
+	if err := m.wordlistManager.UpdateWordlistPath(ctx, existing.ID, relPath); err != nil {
+		debug.Error("Failed to record move of wordlist %d to %s: %v", existing.ID, relPath, err)
+		return false
+	}
+	m.recordWordlistModTime(ctx, existing.ID, modTime)
+	debug.Info("Wordlist %d was moved from %s to %s", existing.ID, existing.FileName, relPath)
+	return true
+}
+
 // determineWordlistType determines the wordlist type based on the directory structure
 func determineWordlistType(relPath string) string {
 	// Default type
@@ -404,7 +594,7 @@ func generateTagsFromPath(relPath string) []string {
 }
 
 // updateExistingWordlist updates an existing wordlist in the database
-func (m *DirectoryMonitor) updateExistingWordlist(ctx context.Context, fullPath, relPath string, wordlistID int, md5Hash string) {
+func (m *DirectoryMonitor) updateExistingWordlist(ctx context.Context, fullPath, relPath string, wordlistID int, md5Hash string, modTime time.Time) {
 	// Get file info
 	fileInfo, err := os.Stat(fullPath)
 	if err != nil {
@@ -418,6 +608,8 @@ func (m *DirectoryMonitor) updateExistingWordlist(ctx context.Context, fullPath,
 		debug.Error("Failed to update wordlist file info: %v", err)
 		return
 	}
+	m.recordWordlistModTime(ctx, wordlistID, modTime)
+	m.recordWordlistCanonicalHash(ctx, wordlistID, fullPath, relPath)
 
 	// Determine wordlist type based on directory structure
 	wordlistType := determineWordlistType(relPath)
@@ -522,102 +714,135 @@ func (m *DirectoryMonitor) checkRuleDirectory() {
 			return nil // Continue walking
 		}
 
-		// Skip directories and hidden files
-		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
-			return nil
-		}
+		m.queueRuleFile(path, info, false)
+		return nil
+	})
 
-		// Get relative path
-		relPath, err := filepath.Rel(m.ruleDir, path)
-		if err != nil {
-			debug.Error("Failed to get relative path for %s: %v", path, err)
-			return nil
-		}
+	if err != nil {
+		debug.Error("Error walking rule directory: %v", err)
+	}
+}
 
-		// Skip if already being processed
-		if _, isProcessing := m.processingFiles.Load(relPath); isProcessing {
-			debug.Debug("Skipping file that is already being processed: %s", relPath)
-			return nil
-		}
+// queueRuleFile hands a rule file to a worker if it is new or changed, see
+// queueWordlistFile
+func (m *DirectoryMonitor) queueRuleFile(path string, info os.FileInfo, watched bool) {
+	// Skip directories and hidden files
+	if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+		return
+	}
 
-		// Check if file is stable (not being written)
-		isStable, err := m.isFileStable(path, 30*time.Second)
-		if err != nil {
-			debug.Error("Error checking if file is stable: %s: %v", path, err)
-			return nil
-		}
+	// Get relative path
+	relPath, err := filepath.Rel(m.ruleDir, path)
+	if err != nil {
+		debug.Error("Failed to get relative path for %s: %v", path, err)
+		return
+	}
 
-		if !isStable {
-			debug.Debug("Skipping file that appears to be still transferring: %s", path)
-			return nil
-		}
+	// Skip if already being processed
+	if _, isProcessing := m.processingFiles.Load(relPath); isProcessing {
+		debug.Debug("Skipping file that is already being processed: %s", relPath)
+		return
+	}
 
-		// Mark file as being processed
-		m.processingFiles.Store(relPath, true)
-		m.fileStatuses.Store(relPath, "queued")
+	// Skip files that have not changed since they were last hashed
+	existingRule, err := m.ruleManager.GetRuleByFilename(context.Background(), relPath)
+	if err != nil {
+		debug.Error("Error checking if rule exists: %v", err)
+		return
+	}
+	if existingRule != nil && fileUnchanged(existingRule.FileSize, existingRule.FileModifiedAt, info) {
+		return
+	}
 
-		// Process file in a worker goroutine
-		go func(fullPath, relPath string) {
-			// Acquire worker semaphore slot
-			m.workerSem <- struct{}{}
-			defer func() {
-				<-m.workerSem
-				m.processingFiles.Delete(relPath)
-				m.fileStatuses.Delete(relPath)
-			}()
+	// Check if file is stable (not being written)
+	if !m.isStable(path, info, watched) {
+		debug.Debug("Skipping file that appears to be still transferring: %s", path)
+		return
+	}
 
-			m.fileStatuses.Store(relPath, "processing")
-			debug.Info("Processing rule file: %s", relPath)
+	// Mark file as being processed
+	m.processingFiles.Store(relPath, true)
+	m.fileStatuses.Store(relPath, "queued")
 
-			ctx := context.Background()
+	// Process file in a worker goroutine
+	go func(fullPath, relPath string, modTime time.Time) {
+		// Acquire worker semaphore slot
+		m.workerSem <- struct{}{}
+		defer func() {
+			<-m.workerSem
+			m.processingFiles.Delete(relPath)
+			m.fileStatuses.Delete(relPath)
+		}()
 
-			// Calculate MD5 hash first (faster than counting lines)
-			m.fileStatuses.Store(relPath, "calculating hash")
-			md5Hash, err := calculateFileMD5(fullPath)
-			if err != nil {
-				debug.Error("Failed to calculate MD5 hash for %s: %v", fullPath, err)
-				m.fileStatuses.Store(relPath, "error: "+err.Error())
-				return
-			}
+		m.fileStatuses.Store(relPath, "processing")
+		debug.Info("Processing rule file: %s", relPath)
 
-			// Check if file exists in database
-			existingRule, err := m.ruleManager.GetRuleByFilename(ctx, relPath)
-			if err != nil {
-				debug.Error("Error checking if rule exists: %v", err)
-				m.fileStatuses.Store(relPath, "error: "+err.Error())
-				return
-			}
+		ctx := context.Background()
 
-			// If file exists with same MD5, skip it
-			if existingRule != nil && existingRule.MD5Hash == md5Hash {
-				debug.Debug("Skipping rule with unchanged hash: %s", relPath)
-				m.fileStatuses.Store(relPath, "unchanged")
-				return
-			}
+		// Calculate MD5 hash first (faster than counting lines)
+		m.fileStatuses.Store(relPath, "calculating hash")
+		md5Hash, err := calculateFileMD5(fullPath)
+		if err != nil {
+			debug.Error("Failed to calculate MD5 hash for %s: %v", fullPath, err)
+			m.fileStatuses.Store(relPath, "error: "+err.Error())
+			return
+		}
 
-			// If file exists but MD5 is different, update it
-			if existingRule != nil {
-				debug.Info("Found modified rule file: %s", relPath)
-				m.fileStatuses.Store(relPath, "updating")
-				m.updateExistingRule(ctx, fullPath, relPath, existingRule.ID, md5Hash)
-			} else {
-				// Process new file
-				debug.Info("Found new rule file: %s", relPath)
-				m.fileStatuses.Store(relPath, "adding")
-				m.processNewRuleFile(ctx, fullPath, relPath, md5Hash)
-			}
-		}(path, relPath)
+		// Check if file exists in database
+		existingRule, err := m.ruleManager.GetRuleByFilename(ctx, relPath)
+		if err != nil {
+			debug.Error("Error checking if rule exists: %v", err)
+			m.fileStatuses.Store(relPath, "error: "+err.Error())
+			return
+		}
 
-		return nil
-	})
+		// If file exists with same MD5, skip it
+		if existingRule != nil && existingRule.MD5Hash == md5Hash {
+			debug.Debug("Skipping rule with unchanged hash: %s", relPath)
+			m.fileStatuses.Store(relPath, "unchanged")
+			m.recordRuleModTime(ctx, existingRule.ID, modTime)
+			return
+		}
+
+		// If file exists but MD5 is different, update it
+		if existingRule != nil {
+			debug.Info("Found modified rule file: %s", relPath)
+			m.fileStatuses.Store(relPath, "updating")
+			m.updateExistingRule(ctx, fullPath, relPath, existingRule.ID, md5Hash, modTime)
+		} else if m.recordMovedRule(ctx, relPath, md5Hash, modTime) {
+			m.fileStatuses.Store(relPath, "moved")
+		} else {
+			// Process new file
+			debug.Info("Found new rule file: %s", relPath)
+			m.fileStatuses.Store(relPath, "adding")
+			m.processNewRuleFile(ctx, fullPath, relPath, md5Hash, modTime)
+		}
+	}(path, relPath, info.ModTime())
+}
 
+// recordRuleModTime records the modification time a rule file had when it was
+// hashed, so later checks can skip it while it is unchanged
+func (m *DirectoryMonitor) recordRuleModTime(ctx context.Context, ruleID int, modTime time.Time) {
+	if err := m.ruleManager.UpdateRuleFileModTime(ctx, ruleID, modTime); err != nil {
+		debug.Error("Failed to record modification time of rule %d: %v", ruleID, err)
+	}
+}
+
+// recordRuleCanonicalHash hashes a rule file the way uploads are compared
+// for near-duplicates and records it
+func (m *DirectoryMonitor) recordRuleCanonicalHash(ctx context.Context, ruleID int, fullPath string) {
+	canonicalHash, err := textnorm.HashFile(fullPath, true)
 	if err != nil {
-		debug.Error("Error walking rule directory: %v", err)
+		debug.Error("Failed to calculate canonical hash of %s: %v", fullPath, err)
+		return
+	}
+	if err := m.ruleManager.UpdateRuleCanonicalHash(ctx, ruleID, canonicalHash); err != nil {
+		debug.Error("Failed to record canonical hash of rule %d: %v", ruleID, err)
 	}
 }
 
 // processNewRuleFile processes a new rule file
-func (m *DirectoryMonitor) processNewRuleFile(ctx context.Context, fullPath, relPath string, md5Hash string) {
+func (m *DirectoryMonitor) processNewRuleFile(ctx context.Context, fullPath, relPath string, md5Hash string, modTime time.Time) {
 	// Get file info
 	fileInfo, err := os.Stat(fullPath)
 	if err != nil {
@@ -651,9 +876,12 @@ func (m *DirectoryMonitor) processNewRuleFile(ctx context.Context, fullPath, rel
 		debug.Error("Failed to add rule %s: %v", relPath, err)
 		return
 	}
+	m.recordRuleModTime(ctx, rule.ID, modTime)
 
 	// Count rules in a separate goroutine
 	go func() {
+		m.recordRuleCanonicalHash(ctx, rule.ID, fullPath)
+
 		debug.Info("Counting rules in new rule file: %s", relPath)
 		m.fileStatuses.Store(relPath, "counting rules")
 
@@ -681,6 +909,26 @@ func (m *DirectoryMonitor) processNewRuleFile(ctx context.Context, fullPath, rel
 	}()
 }
 
+// recordMovedRule checks whether a file without a database record is a known
+// rule that was moved on disk, see recordMovedWordlist
+func (m *DirectoryMonitor) recordMovedRule(ctx context.Context, relPath, md5Hash string, modTime time.Time) bool {
+	existing, err := m.ruleManager.GetRuleByMD5Hash(ctx, md5Hash)
+	if err != nil || existing == nil {
+		return false
+	}
+	if fsutil.FileExists(filepath.Join(m.ruleDir, existing.FileName)) {
+		return false
+	}
+
+	if err := m.ruleManager.UpdateRulePath(ctx, existing.ID, relPath); err != nil {
+		debug.Error("Failed to record move of rule %d to %s: %v", existing.ID, relPath, err)
+		return false
+	}
+	m.recordRuleModTime(ctx, existing.ID, modTime)
+	debug.Info("Rule %d was moved from %s to %s", existing.ID, existing.FileName, relPath)
+	return true
+}
+
 // determineRuleType determines the rule type based on the directory structure and path
 func determineRuleType(relPath string) string {
 	// Default type
@@ -711,7 +959,7 @@ func determineRuleType(relPath string) string {
 }
 
 // updateExistingRule updates an existing rule in the database
-func (m *DirectoryMonitor) updateExistingRule(ctx context.Context, fullPath, relPath string, ruleID int, md5Hash string) {
+func (m *DirectoryMonitor) updateExistingRule(ctx context.Context, fullPath, relPath string, ruleID int, md5Hash string, modTime time.Time) {
 	// Get file info
 	fileInfo, err := os.Stat(fullPath)
 	if err != nil {
@@ -725,6 +973,8 @@ func (m *DirectoryMonitor) updateExistingRule(ctx context.Context, fullPath, rel
 		debug.Error("Failed to update rule file info: %v", err)
 		return
 	}
+	m.recordRuleModTime(ctx, ruleID, modTime)
+	m.recordRuleCanonicalHash(ctx, ruleID, fullPath)
 
 	// Determine rule type based on directory structure and path
 	ruleType := determineRuleType(relPath)
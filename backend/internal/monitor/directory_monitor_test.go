@@ -0,0 +1,36 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rockyou.txt")
+	require.NoError(t, os.WriteFile(path, []byte("password\n"), 0644))
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	// As read back from the database, which keeps microseconds
+	recorded := modTime.Truncate(time.Microsecond).In(time.Local)
+	assert.True(t, fileUnchanged(9, &recorded, info))
+	assert.False(t, fileUnchanged(10, &recorded, info), "size changed")
+	assert.False(t, fileUnchanged(9, nil, info), "never recorded")
+
+	later := recorded.Add(time.Second)
+	assert.False(t, fileUnchanged(9, &later, info), "modified")
+}
+
+func TestIsWithin(t *testing.T) {
+	assert.True(t, isWithin("/data/wordlists", "/data/wordlists/general/a.txt"))
+	assert.False(t, isWithin("/data/wordlists", "/data/wordlists"))
+	assert.False(t, isWithin("/data/wordlists", "/data/rules/a.rule"))
+	assert.False(t, isWithin("/data/wordlists", "/data/wordlists2/a.txt"))
+}
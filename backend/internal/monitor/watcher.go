@@ -0,0 +1,184 @@
+package monitor
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long a path reported by the watcher must see no further
+// events before it is checked, so a file being copied is hashed once when the
+// copy has finished rather than on every write
+const watchDebounce = 5 * time.Second
+
+// watchEvent is a change below a watched directory
+type watchEvent struct {
+	path string
+	// removed is set when path was deleted or moved away
+	removed bool
+	// overflow is set when the kernel dropped events, the directories have to
+	// be scanned again to catch up
+	overflow bool
+}
+
+// fileWatcher reports changes below a set of directories, including
+// directories created after it was started
+type fileWatcher interface {
+	Events() <-chan watchEvent
+	Close() error
+}
+
+// notifyWatcher watches directory trees with fsnotify. Its watches are not
+// recursive, so every directory gets its own watch, new ones as they appear.
+type notifyWatcher struct {
+	watcher *fsnotify.Watcher
+	events  chan watchEvent
+	done    chan struct{}
+	once    sync.Once
+}
+
+// newFileWatcher starts watching the directory trees below dirs
+func newFileWatcher(dirs ...string) (fileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &notifyWatcher{
+		watcher: watcher,
+		events:  make(chan watchEvent, 1024),
+		done:    make(chan struct{}),
+	}
+	for _, dir := range dirs {
+		if err := w.addTree(dir); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+
+	go w.readEvents()
+	return w, nil
+}
+
+// Events returns the channel of changes, it is closed when the watcher stops
+func (w *notifyWatcher) Events() <-chan watchEvent {
+	return w.events
+}
+
+// Close stops the watcher
+func (w *notifyWatcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.watcher.Close()
+	})
+	return err
+}
+
+// addTree adds a watch for root and every directory below it, skipping hidden
+// directories
+func (w *notifyWatcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		if err := w.watcher.Add(path); err != nil {
+			if path == root {
+				return err
+			}
+			debug.Warning("Failed to watch directory %s: %v", path, err)
+		}
+		return nil
+	})
+}
+
+// removeTree drops the watches of root and every directory below it. A moved
+// directory keeps its watches under the old paths otherwise.
+func (w *notifyWatcher) removeTree(root string) {
+	for _, path := range w.watcher.WatchList() {
+		if path == root || isWithin(root, path) {
+			// Fails for directories that were deleted, their watches are gone already
+			_ = w.watcher.Remove(path)
+		}
+	}
+}
+
+// readEvents turns fsnotify events into watchEvents until the watcher is closed
+func (w *notifyWatcher) readEvents() {
+	defer close(w.events)
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok || !w.handleEvent(event) {
+				return
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				if !w.send(watchEvent{overflow: true}) {
+					return
+				}
+				continue
+			}
+			debug.Error("Failed to read file system events: %v", err)
+		}
+	}
+}
+
+// handleEvent turns one fsnotify event into a watchEvent, it returns false once
+// the watcher is closed
+func (w *notifyWatcher) handleEvent(event fsnotify.Event) bool {
+	if strings.HasPrefix(filepath.Base(event.Name), ".") {
+		return true
+	}
+
+	switch {
+	case event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename):
+		// A directory moved within the tree is watched again when it is
+		// created at its new path
+		w.removeTree(event.Name)
+		return w.send(watchEvent{path: event.Name, removed: true})
+	case event.Has(fsnotify.Create):
+		// Files can land in a new directory before its watch exists, the
+		// event for the directory makes the monitor scan it
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err := w.addTree(event.Name); err != nil {
+				debug.Warning("Failed to watch new directory %s: %v", event.Name, err)
+			}
+		}
+		return w.send(watchEvent{path: event.Name})
+	case event.Has(fsnotify.Write):
+		return w.send(watchEvent{path: event.Name})
+	}
+	return true
+}
+
+// send delivers an event unless the watcher is closed
+func (w *notifyWatcher) send(event watchEvent) bool {
+	select {
+	case w.events <- event:
+		return true
+	case <-w.done:
+		return false
+	}
+}
@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextEvent waits for the next event for path, skipping others
+func nextEvent(t *testing.T, watcher fileWatcher, path string) watchEvent {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-watcher.Events():
+			require.True(t, ok, "watcher stopped")
+			if event.path == path {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("no event for %s", path)
+		}
+	}
+}
+
+func TestFileWatcher(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "general"), 0755))
+
+	watcher, err := newFileWatcher(root)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	// Existing subdirectories are watched
+	file := filepath.Join(root, "general", "rockyou.txt")
+	require.NoError(t, os.WriteFile(file, []byte("password\n"), 0644))
+	nextEvent(t, watcher, file)
+
+	// New directories are reported and watched from then on
+	dir := filepath.Join(root, "leaks")
+	require.NoError(t, os.Mkdir(dir, 0755))
+	nextEvent(t, watcher, dir)
+	file = filepath.Join(dir, "breach.txt")
+	require.NoError(t, os.WriteFile(file, []byte("123456\n"), 0644))
+	nextEvent(t, watcher, file)
+
+	// Moved files are reported at their new path
+	moved := filepath.Join(root, "general", "breach.txt")
+	require.NoError(t, os.Rename(file, moved))
+	assert.False(t, nextEvent(t, watcher, moved).removed)
+
+	// Deleted files are reported as removed
+	require.NoError(t, os.Remove(moved))
+	assert.True(t, nextEvent(t, watcher, moved).removed)
+
+	// Moved directories are watched at their new path only
+	renamed := filepath.Join(root, "breaches")
+	require.NoError(t, os.Rename(dir, renamed))
+	assert.True(t, nextEvent(t, watcher, dir).removed)
+	nextEvent(t, watcher, renamed)
+	file = filepath.Join(renamed, "combo.txt")
+	require.NoError(t, os.WriteFile(file, []byte("qwerty\n"), 0644))
+	nextEvent(t, watcher, file)
+	assert.NotContains(t, watcher.(*notifyWatcher).watcher.WatchList(), dir)
+
+	require.NoError(t, watcher.Close())
+	for range watcher.Events() {
+	}
+	assert.NoError(t, watcher.Close(), "closing twice is harmless")
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,18 +17,18 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashutils"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/plaintext"
 	"github.com/google/uuid"
 )
 
-// Add a new constant for the status
-const HashListStatusReadyWithErrors = "ready_with_errors"
-
 // HashlistDBProcessor handles the asynchronous processing of uploaded hashlists, focusing on DB interactions.
 type HashlistDBProcessor struct {
-	hashlistRepo *repository.HashListRepository
-	hashTypeRepo *repository.HashTypeRepository
-	hashRepo     *repository.HashRepository
-	config       *config.Config
+	hashlistRepo   *repository.HashListRepository
+	hashTypeRepo   *repository.HashTypeRepository
+	hashRepo       *repository.HashRepository
+	quarantineRepo *repository.HashlistQuarantineRepository
+	settingsRepo   *repository.SystemSettingsRepository
+	config         *config.Config
 	// valueProcessors map[int]HashValueProcessor // REMOVED: Replaced by hashutils
 }
 
@@ -35,6 +37,8 @@ func NewHashlistDBProcessor(
 	hashlistRepo *repository.HashListRepository,
 	hashTypeRepo *repository.HashTypeRepository,
 	hashRepo *repository.HashRepository,
+	quarantineRepo *repository.HashlistQuarantineRepository,
+	settingsRepo *repository.SystemSettingsRepository,
 	config *config.Config,
 ) *HashlistDBProcessor {
 	// REMOVED: Initialization of valueProcessors map
@@ -45,10 +49,12 @@ func NewHashlistDBProcessor(
 	*/
 
 	return &HashlistDBProcessor{
-		hashlistRepo: hashlistRepo,
-		hashTypeRepo: hashTypeRepo,
-		hashRepo:     hashRepo,
-		config:       config,
+		hashlistRepo:   hashlistRepo,
+		hashTypeRepo:   hashTypeRepo,
+		hashRepo:       hashRepo,
+		quarantineRepo: quarantineRepo,
+		settingsRepo:   settingsRepo,
+		config:         config,
 		// valueProcessors: valueProcessors, // REMOVED
 	}
 }
@@ -99,18 +105,28 @@ func (p *HashlistDBProcessor) processHashlist(hashlistID int64) {
 
 	// --- Process the file line by line ---
 	scanner := bufio.NewScanner(file)
+	// Allow lines past hashcat's limit so they are quarantined rather than aborting the scan
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	var totalHashes, crackedHashes int64
 	batchSize := p.config.HashlistBatchSize
 	hashesToProcess := make([]*models.Hash, 0, batchSize)
 	associationsToCreate := make([]*models.HashListHash, 0, batchSize)
+	linesToQuarantine := make([]*models.QuarantinedLine, 0)
 	lineNumber := 0
 	firstLineErrorMsg := ""     // Store the first line processing error
 	lineErrorsOccurred := false // Track if any line errors happened
+	quarantinedCount := 0
+
+	// Start from a clean quarantine in case this hashlist is being processed again
+	if _, err := p.quarantineRepo.DeleteByHashlist(ctx, hashlistID); err != nil {
+		debug.Warning("Background task: Failed to clear quarantine for hashlist %d: %v", hashlistID, err)
+	}
 
 	// valueProcessor, processorFound := p.valueProcessors[hashType.ID] // Removed unused variables
 
 	// Get the needs_processing flag from the fetched hashType
 	needsProcessing := hashType.NeedsProcessing
+	pattern := validationPattern(hashType)
 
 	for scanner.Scan() {
 		lineNumber++
@@ -119,67 +135,36 @@ func (p *HashlistDBProcessor) processHashlist(hashlistID int64) {
 			continue // Skip empty lines and comments
 		}
 
-		totalHashes++
-
-		// --- New Processing Logic ---
-		originalHash := line // Store the raw line
-		usernameAndDomain := hashutils.ExtractUsernameAndDomain(originalHash, hashType.ID)
-		hashValue := hashutils.ProcessHashIfNeeded(originalHash, hashType.ID, needsProcessing)
-
-		// Extract username and domain from result
-		var username *string
-		var domain *string
-		if usernameAndDomain != nil {
-			username = usernameAndDomain.Username
-			domain = usernameAndDomain.Domain
-		}
-
-		usernameStr := ""
-		domainStr := ""
-		if username != nil {
-			usernameStr = *username
-		}
-		if domain != nil {
-			domainStr = *domain
-		}
-		debug.Debug("[Processor:%d] Line %d: Original='%s', ProcessedValue='%s', User='%s', Domain='%s'", hashlistID, lineNumber, originalHash, hashValue, usernameStr, domainStr)
-		// --- End New Processing Logic ---
-
-		// Determine if cracked (e.g., from input format like hash:pass)
-		// Note: ProcessHashIfNeeded doesn't handle cracking detection currently.
-		// We might need a separate mechanism or refine processing rules.
-		// For now, let's assume a simple heuristic for :password suffix if no specific processor modified it.
-		password := ""
-		isCracked := false
-		if hashValue == originalHash { // Only apply suffix check if ProcessHashIfNeeded didn't modify it
-			parts := strings.SplitN(originalHash, ":", 2)
-			if len(parts) > 1 {
-				// Basic check: is the first part potentially the hashValue we expect?
-				// This is weak. A better approach might involve hash length/format checks.
-				if parts[0] == hashValue { // Check if splitting by ':' gives back the expected hash
-					password = parts[1]
-					isCracked = true
+		hash, err := p.buildHash(line, hashType, needsProcessing, pattern)
+		if err != nil {
+			// Quarantine the line instead of silently dropping it
+			debug.Debug("[Processor:%d] Line %d quarantined: %v", hashlistID, lineNumber, err)
+			lineErrorsOccurred = true
+			quarantinedCount++
+			if firstLineErrorMsg == "" {
+				firstLineErrorMsg = fmt.Sprintf("line %d: %v", lineNumber, err)
+			}
+			linesToQuarantine = append(linesToQuarantine, &models.QuarantinedLine{
+				HashlistID:  hashlistID,
+				LineNumber:  lineNumber,
+				LineContent: line,
+				ErrorReason: err.Error(),
+			})
+			if len(linesToQuarantine) >= batchSize {
+				if err := p.quarantineRepo.AddBatch(ctx, linesToQuarantine); err != nil {
+					debug.Error("Background task: Error quarantining lines for hashlist %d: %v", hashlistID, err)
+					p.updateHashlistStatus(ctx, hashlistID, models.HashListStatusError, "Error saving quarantined lines")
+					return
 				}
-				// Else: it might be user:hash or some other format, don't assume crack.
+				linesToQuarantine = linesToQuarantine[:0]
 			}
+			continue
 		}
 
-		if isCracked {
+		totalHashes++
+		if hash.IsCracked {
 			crackedHashes++
 		}
-
-		// Create hash model
-		hash := &models.Hash{
-			ID:           uuid.New(),   // Generate new UUID for potential insert
-			HashValue:    hashValue,    // The value to crack (potentially processed)
-			OriginalHash: originalHash, // Always store the original line
-			Username:     username,     // Store the extracted username (or nil)
-			Domain:       domain,       // Store the extracted domain (or nil)
-			HashTypeID:   hashlist.HashTypeID,
-			IsCracked:    isCracked,  // Mark cracked based on heuristic above
-			Password:     password,   // Store potential password from heuristic
-			LastUpdated:  time.Now(), // Set initial time
-		}
 		debug.Debug("[Processor:%d] Line %d: Created Hash struct with ID: %s", hashlistID, lineNumber, hash.ID)
 		hashesToProcess = append(hashesToProcess, hash)
 
@@ -216,6 +201,16 @@ func (p *HashlistDBProcessor) processHashlist(hashlistID int64) {
 		return
 	}
 
+	if err := p.quarantineRepo.AddBatch(ctx, linesToQuarantine); err != nil {
+		debug.Error("Background task: Error quarantining final lines for hashlist %d: %v", hashlistID, err)
+		p.updateHashlistStatus(ctx, hashlistID, models.HashListStatusError, "Error saving quarantined lines")
+		return
+	}
+	if quarantinedCount > 0 {
+		debug.Warning("Background task: Quarantined %d malformed lines for hashlist %d", quarantinedCount, hashlistID)
+		firstLineErrorMsg = fmt.Sprintf("%d line(s) were quarantined, first error at %s", quarantinedCount, firstLineErrorMsg)
+	}
+
 	// Create final associations batch (if any)
 	if len(associationsToCreate) > 0 {
 		err = p.hashRepo.AddBatchToHashList(ctx, associationsToCreate)
@@ -229,62 +224,13 @@ func (p *HashlistDBProcessor) processHashlist(hashlistID int64) {
 	debug.Info("Successfully created final hashlist associations for %d", hashlistID)
 
 	// --- Generate <id>.hash file with uncracked hashes ---
-	var finalFilePath string
-	uncrackedHashes, err := p.hashRepo.GetUncrackedHashValuesByHashlistID(ctx, hashlistID)
+	finalFilePath, err := p.writeAgentHashFile(ctx, hashlistID)
 	if err != nil {
-		errMsg := fmt.Sprintf("Failed to retrieve uncracked hashes for final file generation: %v", err)
-		debug.Error("Background task: %s (Hashlist: %d)", errMsg, hashlistID)
-		p.updateHashlistStatus(ctx, hashlistID, models.HashListStatusError, errMsg)
+		debug.Error("Background task: %v (Hashlist: %d)", err, hashlistID)
+		p.updateHashlistStatus(ctx, hashlistID, models.HashListStatusError, err.Error())
 		return
 	}
 
-	if len(uncrackedHashes) > 0 {
-		// Define the output path: <DataDir>/hashlists/<id>.hash
-		outputFilename := fmt.Sprintf("%d.hash", hashlistID)
-		// Construct path relative to the main DataDir
-		finalFilePath = filepath.Join(p.config.DataDir, "hashlists", outputFilename)
-		debug.Info("Generating final hash file for agents: %s", finalFilePath)
-
-		outFile, err := os.Create(finalFilePath)
-		if err != nil {
-			errMsg := fmt.Sprintf("Failed to create final hash file %s: %v", finalFilePath, err)
-			debug.Error("Background task: %s (Hashlist: %d)", errMsg, hashlistID)
-			p.updateHashlistStatus(ctx, hashlistID, models.HashListStatusError, errMsg)
-			return // Critical failure if we can't write the output file
-		}
-
-		writer := bufio.NewWriter(outFile)
-		for _, h := range uncrackedHashes {
-			_, err = writer.WriteString(h + "\n")
-			if err != nil {
-				_ = outFile.Close() // Close file before returning on write error
-				errMsg := fmt.Sprintf("Failed to write to final hash file %s: %v", finalFilePath, err)
-				debug.Error("Background task: %s (Hashlist: %d)", errMsg, hashlistID)
-				p.updateHashlistStatus(ctx, hashlistID, models.HashListStatusError, errMsg)
-				return
-			}
-		}
-
-		if err = writer.Flush(); err != nil {
-			_ = outFile.Close()
-			errMsg := fmt.Sprintf("Failed to flush final hash file %s: %v", finalFilePath, err)
-			debug.Error("Background task: %s (Hashlist: %d)", errMsg, hashlistID)
-			p.updateHashlistStatus(ctx, hashlistID, models.HashListStatusError, errMsg)
-			return
-		}
-		if err = outFile.Close(); err != nil {
-			// Log error, but proceed as file is likely written
-			debug.Warning("Failed to close final hash file %s cleanly: %v", finalFilePath, err)
-		}
-		debug.Info("Successfully wrote %d uncracked hashes to %s", len(uncrackedHashes), finalFilePath)
-
-	} else {
-		// No uncracked hashes, maybe skip file creation or create an empty file?
-		// Let's log this and set finalFilePath to empty, indicating no file for agents.
-		finalFilePath = ""
-		debug.Info("No uncracked hashes found for hashlist %d. No agent file generated.", hashlistID)
-	}
-
 	// --- Optionally delete original uploaded file ---
 	originalUploadPath := hashlist.FilePath                              // Path stored when processing started
 	if originalUploadPath != "" && originalUploadPath != finalFilePath { // Avoid deleting the file we just created!
@@ -302,7 +248,7 @@ func (p *HashlistDBProcessor) processHashlist(hashlistID int64) {
 	// Determine final status
 	finalStatus := models.HashListStatusReady
 	if lineErrorsOccurred {
-		finalStatus = HashListStatusReadyWithErrors // Use the new status constant
+		finalStatus = models.HashListStatusReadyWithErrors
 	}
 
 	// Update final hashlist status, counts, AND the file path
@@ -330,6 +276,287 @@ func (p *HashlistDBProcessor) processHashlist(hashlistID int64) {
 	} else {
 		debug.Info("Successfully synced cracked count for hashlist %d", hashlistID)
 	}
+
+	p.splitIntoSubLists(ctx, hashlist)
+}
+
+// maxHashesPerHashlist returns the max_hashes_per_hashlist setting, 0 when
+// hashlists are not split
+func (p *HashlistDBProcessor) maxHashesPerHashlist(ctx context.Context) int {
+	if p.settingsRepo == nil {
+		return 0
+	}
+	setting, err := p.settingsRepo.GetSetting(ctx, "max_hashes_per_hashlist")
+	if err != nil || setting.Value == nil {
+		return 0
+	}
+	maxHashes, err := strconv.Atoi(*setting.Value)
+	if err != nil {
+		debug.Warning("Invalid max_hashes_per_hashlist setting %q: %v", *setting.Value, err)
+		return 0
+	}
+	return maxHashes
+}
+
+// splitIntoSubLists splits a processed hashlist with more unique hashes than
+// max_hashes_per_hashlist into sub-lists and writes their agent hash files.
+// Jobs created on the hashlist run against the sub-lists, so agents never load
+// more hashes than the limit. If splitting fails the hashlist stays whole.
+func (p *HashlistDBProcessor) splitIntoSubLists(ctx context.Context, hashlist *models.HashList) {
+	maxHashes := p.maxHashesPerHashlist(ctx)
+	if maxHashes <= 0 {
+		return
+	}
+
+	subLists, err := p.hashlistRepo.CreateSubLists(ctx, hashlist, maxHashes)
+	if err != nil {
+		debug.Error("Failed to split hashlist %d into sub-lists: %v", hashlist.ID, err)
+		return
+	}
+	if len(subLists) == 0 {
+		return
+	}
+
+	for _, subList := range subLists {
+		filePath, err := p.writeAgentHashFile(ctx, subList.ID)
+		if err != nil {
+			debug.Error("Failed to write agent hash file of sub-list %d: %v", subList.ID, err)
+			p.updateHashlistStatus(ctx, subList.ID, models.HashListStatusError, err.Error())
+			continue
+		}
+		if err := p.hashlistRepo.UpdateStatsAndStatusWithPath(ctx, subList.ID, subList.TotalHashes, subList.CrackedHashes,
+			models.HashListStatusReady, "", filePath); err != nil {
+			debug.Error("Failed to mark sub-list %d ready: %v", subList.ID, err)
+		}
+	}
+
+	debug.Info("Split hashlist %d into %d sub-lists of at most %d unique hashes", hashlist.ID, len(subLists), maxHashes)
+}
+
+// validationPattern compiles the hash type's validation regex, nil if it has
+// none. An invalid pattern is ignored so it cannot block loading hashlists.
+func validationPattern(hashType *models.HashType) *regexp.Regexp {
+	if hashType.ValidationRegex == nil || *hashType.ValidationRegex == "" {
+		return nil
+	}
+	pattern, err := hashutils.CompileValidationRegex(*hashType.ValidationRegex)
+	if err != nil {
+		debug.Warning("Ignoring validation regex of hash type %d: %v", hashType.ID, err)
+		return nil
+	}
+	return pattern
+}
+
+// buildHash validates a single input line and converts it into a hash model.
+// It returns an error describing why the line should be quarantined.
+func (p *HashlistDBProcessor) buildHash(line string, hashType *models.HashType, needsProcessing bool, pattern *regexp.Regexp) (*models.Hash, error) {
+	originalHash := line // Store the raw line
+	usernameAndDomain := hashutils.ExtractUsernameAndDomain(originalHash, hashType.ID)
+	hashValue := hashutils.ProcessHashIfNeeded(originalHash, hashType.ID, needsProcessing)
+
+	if err := hashutils.ValidateHashLine(originalHash, hashValue, hashType.ID); err != nil {
+		return nil, err
+	}
+	if err := hashutils.ValidateHashPattern(hashValue, pattern); err != nil {
+		return nil, err
+	}
+
+	// Extract username and domain from result
+	var username *string
+	var domain *string
+	if usernameAndDomain != nil {
+		username = usernameAndDomain.Username
+		domain = usernameAndDomain.Domain
+	}
+
+	// Determine if cracked (e.g., from input format like hash:pass)
+	// Note: ProcessHashIfNeeded doesn't handle cracking detection currently.
+	// For now, assume a simple heuristic for :password suffix if no specific processor modified it.
+	password := ""
+	var passwordRaw []byte
+	isCracked := false
+	if hashValue == originalHash { // Only apply suffix check if ProcessHashIfNeeded didn't modify it
+		parts := strings.SplitN(originalHash, ":", 2)
+		if len(parts) > 1 && parts[0] == hashValue {
+			// Pot lines carry non-printable passwords in $HEX[...] notation
+			password, passwordRaw = plaintext.ForStorage(plaintext.Decode(parts[1], ""))
+			isCracked = true
+		}
+	}
+
+	return &models.Hash{
+		ID:           uuid.New(),   // Generate new UUID for potential insert
+		HashValue:    hashValue,    // The value to crack (potentially processed)
+		OriginalHash: originalHash, // Always store the original line
+		Username:     username,     // Store the extracted username (or nil)
+		Domain:       domain,       // Store the extracted domain (or nil)
+		HashTypeID:   hashType.ID,
+		IsCracked:    isCracked,  // Mark cracked based on heuristic above
+		Password:     password,   // Store potential password from heuristic
+		LastUpdated:  time.Now(), // Set initial time
+		PasswordRaw:  passwordRaw,
+	}, nil
+}
+
+// writeAgentHashFile generates <DataDir>/hashlists/<id>.hash with the uncracked
+// hashes agents will work on. It returns an empty path if nothing is left to crack.
+func (p *HashlistDBProcessor) writeAgentHashFile(ctx context.Context, hashlistID int64) (string, error) {
+	uncrackedHashes, err := p.hashRepo.GetUncrackedHashValuesByHashlistID(ctx, hashlistID)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve uncracked hashes for final file generation: %w", err)
+	}
+
+	if len(uncrackedHashes) == 0 {
+		debug.Info("No uncracked hashes found for hashlist %d. No agent file generated.", hashlistID)
+		return "", nil
+	}
+
+	finalFilePath := filepath.Join(p.config.DataDir, "hashlists", fmt.Sprintf("%d.hash", hashlistID))
+	debug.Info("Generating final hash file for agents: %s", finalFilePath)
+
+	outFile, err := os.Create(finalFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create final hash file %s: %w", finalFilePath, err)
+	}
+
+	writer := bufio.NewWriter(outFile)
+	for _, h := range uncrackedHashes {
+		if _, err := writer.WriteString(h + "\n"); err != nil {
+			_ = outFile.Close()
+			return "", fmt.Errorf("failed to write to final hash file %s: %w", finalFilePath, err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		_ = outFile.Close()
+		return "", fmt.Errorf("failed to flush final hash file %s: %w", finalFilePath, err)
+	}
+	if err := outFile.Close(); err != nil {
+		// Log error, but proceed as file is likely written
+		debug.Warning("Failed to close final hash file %s cleanly: %v", finalFilePath, err)
+	}
+
+	debug.Info("Successfully wrote %d uncracked hashes to %s", len(uncrackedHashes), finalFilePath)
+	return finalFilePath, nil
+}
+
+// QuarantineReprocessResult summarizes a quarantine reprocessing run.
+type QuarantineReprocessResult struct {
+	Loaded      int `json:"loaded"`      // Lines that passed validation and were added to the hashlist
+	Quarantined int `json:"quarantined"` // Lines still rejected after reprocessing
+}
+
+// ReprocessQuarantine replaces the quarantine of a hashlist by validating the
+// given lines again. Lines that now pass are added to the hashlist and the agent
+// hash file is regenerated; lines that still fail are quarantined again. When
+// lines is nil the currently quarantined lines are retried as-is. Lines keep
+// their number in the uploaded file when retried as-is, or when corrected
+// lines are given one for one in the order of the quarantine download.
+func (p *HashlistDBProcessor) ReprocessQuarantine(ctx context.Context, hashlistID int64, lines []string) (*QuarantineReprocessResult, error) {
+	hashlist, err := p.hashlistRepo.GetByID(ctx, hashlistID)
+	if err != nil || hashlist == nil {
+		return nil, fmt.Errorf("failed to get hashlist %d: %w", hashlistID, err)
+	}
+	if hashlist.Status != models.HashListStatusReady && hashlist.Status != models.HashListStatusReadyWithErrors {
+		return nil, fmt.Errorf("hashlist %d is %s, it must be ready to reprocess quarantined lines", hashlistID, hashlist.Status)
+	}
+
+	hashType, err := p.hashTypeRepo.GetByID(ctx, hashlist.HashTypeID)
+	if err != nil || hashType == nil {
+		return nil, fmt.Errorf("failed to get hash type %d: %w", hashlist.HashTypeID, err)
+	}
+
+	existing, _, err := p.quarantineRepo.ListByHashlist(ctx, hashlistID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if lines == nil {
+		for _, q := range existing {
+			lines = append(lines, q.LineContent)
+		}
+	}
+	var retried []string
+	for _, raw := range lines {
+		if line := strings.TrimSpace(raw); line != "" && !strings.HasPrefix(line, "#") {
+			retried = append(retried, line)
+		}
+	}
+
+	pattern := validationPattern(hashType)
+
+	result := &QuarantineReprocessResult{}
+	hashes := make([]*models.Hash, 0, len(retried))
+	rejected := make([]*models.QuarantinedLine, 0)
+	for i, line := range retried {
+		hash, err := p.buildHash(line, hashType, hashType.NeedsProcessing, pattern)
+		if err != nil {
+			lineNumber := 0
+			if len(retried) == len(existing) {
+				lineNumber = existing[i].LineNumber
+			}
+			rejected = append(rejected, &models.QuarantinedLine{
+				HashlistID:  hashlistID,
+				LineNumber:  lineNumber,
+				LineContent: line,
+				ErrorReason: err.Error(),
+			})
+			continue
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if len(hashes) > 0 {
+		associations, err := p.batchProcessHashes(ctx, hashes, hashlistID)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.hashRepo.AddBatchToHashList(ctx, associations); err != nil {
+			return nil, fmt.Errorf("failed to add reprocessed hashes to hashlist %d: %w", hashlistID, err)
+		}
+		result.Loaded = len(hashes)
+	}
+
+	if _, err := p.quarantineRepo.DeleteByHashlist(ctx, hashlistID); err != nil {
+		return nil, err
+	}
+	if err := p.quarantineRepo.AddBatch(ctx, rejected); err != nil {
+		return nil, err
+	}
+	result.Quarantined = len(rejected)
+
+	filePath := hashlist.FilePath
+	if result.Loaded > 0 {
+		if filePath, err = p.writeAgentHashFile(ctx, hashlistID); err != nil {
+			return nil, err
+		}
+	}
+
+	status := models.HashListStatusReady
+	errorMessage := ""
+	if result.Quarantined > 0 {
+		status = models.HashListStatusReadyWithErrors
+		if rejected[0].LineNumber > 0 {
+			errorMessage = fmt.Sprintf("%d line(s) were quarantined, first error at line %d: %s",
+				result.Quarantined, rejected[0].LineNumber, rejected[0].ErrorReason)
+		} else {
+			errorMessage = fmt.Sprintf("%d line(s) were quarantined, first error: %s",
+				result.Quarantined, rejected[0].ErrorReason)
+		}
+	}
+	// Counted rather than added up, lines already in the hashlist are not added again
+	totalHashes, err := p.hashRepo.CountByHashlistID(ctx, hashlistID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.hashlistRepo.UpdateStatsAndStatusWithPath(ctx, hashlistID, totalHashes,
+		hashlist.CrackedHashes, status, errorMessage, filePath); err != nil {
+		return nil, err
+	}
+	if err := p.hashlistRepo.SyncCrackedCount(ctx, hashlistID); err != nil {
+		debug.Error("Failed to sync cracked count for hashlist %d: %v", hashlistID, err)
+	}
+
+	debug.Info("Reprocessed quarantine for hashlist %d: %d loaded, %d still quarantined", hashlistID, result.Loaded, result.Quarantined)
+	return result, nil
 }
 
 // batchProcessHashes handles creating/updating hashes and preparing associations.
@@ -351,7 +578,7 @@ func (p *HashlistDBProcessor) batchProcessHashes(ctx context.Context, hashes []*
 	for _, h := range hashes {
 		// Skip exact duplicate lines (same original_hash)
 		if _, alreadySeen := uniqueHashesByOriginal[h.OriginalHash]; alreadySeen {
-			debug.Debug("[Processor:%d] Skipping duplicate original_hash: %s", hashlistID, h.OriginalHash)
+			debug.Debug("[Processor:%d] Skipping duplicate original_hash: %s", hashlistID, debug.Hash(h.OriginalHash))
 			continue
 		}
 
@@ -406,6 +633,7 @@ func (p *HashlistDBProcessor) batchProcessHashes(ctx context.Context, hashes []*
 			if !existingDBHash.IsCracked && inputHash.IsCracked {
 				existingDBHash.IsCracked = true
 				existingDBHash.Password = inputHash.Password
+				existingDBHash.PasswordRaw = inputHash.PasswordRaw
 				needsUpdate = true
 				newlyCrackedInBatch++
 			}
@@ -0,0 +1,114 @@
+package processor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// PreviewHashlist parses the upload of a staged hashlist with the same
+// validation processing uses and returns its statistics. No hashes are
+// stored. accept decides which detected hash types are reported.
+func (p *HashlistDBProcessor) PreviewHashlist(ctx context.Context, hashlist *models.HashList, accept func(int) bool) (*models.HashlistStagingPreview, error) {
+	hashType, err := p.hashTypeRepo.GetByID(ctx, hashlist.HashTypeID)
+	if err != nil || hashType == nil {
+		return nil, fmt.Errorf("failed to get hash type %d: %v", hashlist.HashTypeID, err)
+	}
+
+	file, err := os.Open(hashlist.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hashlist file: %w", err)
+	}
+	defer file.Close()
+
+	return p.previewLines(file, hashType, accept)
+}
+
+// previewLines computes the staging statistics of an upload
+func (p *HashlistDBProcessor) previewLines(r io.Reader, hashType *models.HashType, accept func(int) bool) (*models.HashlistStagingPreview, error) {
+	preview := &models.HashlistStagingPreview{
+		DetectedTypes:  []models.HashlistStagingType{},
+		InvalidSamples: []models.HashlistStagingLine{},
+	}
+	needsProcessing := hashType.NeedsProcessing
+	pattern := validationPattern(hashType)
+
+	// Hash values are remembered by a 64-bit digest to keep large uploads cheap
+	seen := make(map[uint64]struct{})
+	digest := fnv.New64a()
+	groups := make(map[int]*models.HashlistStagingType)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		preview.Lines++
+
+		hashTypeID, detected := classifyLine(line, hashType.ID, accept)
+		group, ok := groups[hashTypeID]
+		if !ok {
+			group = &models.HashlistStagingType{HashTypeID: hashTypeID, Detected: detected}
+			groups[hashTypeID] = group
+		}
+		group.Lines++
+
+		hash, err := p.buildHash(line, hashType, needsProcessing, pattern)
+		if err != nil {
+			preview.InvalidLines++
+			if len(preview.InvalidSamples) < models.MaxStagingInvalidSamples {
+				sample := line
+				if len(sample) > maxGroupSampleLength {
+					sample = sample[:maxGroupSampleLength] + "..."
+				}
+				preview.InvalidSamples = append(preview.InvalidSamples, models.HashlistStagingLine{
+					LineNumber: lineNumber,
+					Line:       sample,
+					Reason:     err.Error(),
+				})
+			}
+			continue
+		}
+
+		preview.ValidLines++
+		digest.Reset()
+		digest.Write([]byte(hash.HashValue))
+		key := digest.Sum64()
+		if _, dup := seen[key]; dup {
+			preview.DuplicateLines++
+			continue
+		}
+		seen[key] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	preview.UniqueHashes = len(seen)
+
+	for _, group := range groups {
+		preview.DetectedTypes = append(preview.DetectedTypes, *group)
+	}
+	sort.Slice(preview.DetectedTypes, func(i, j int) bool {
+		if preview.DetectedTypes[i].Lines != preview.DetectedTypes[j].Lines {
+			return preview.DetectedTypes[i].Lines > preview.DetectedTypes[j].Lines
+		}
+		return preview.DetectedTypes[i].HashTypeID < preview.DetectedTypes[j].HashTypeID
+	})
+	if len(preview.DetectedTypes) > 0 {
+		top := preview.DetectedTypes[0]
+		preview.TypeMismatch = top.Detected && top.HashTypeID != hashType.ID
+	}
+
+	return preview, nil
+}
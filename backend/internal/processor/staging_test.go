@@ -0,0 +1,55 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewLines(t *testing.T) {
+	md5 := &models.HashType{ID: 0, Name: "MD5"}
+	upload := strings.Join([]string{
+		"# exported hashes",
+		"5f4dcc3b5aa765d61d8327deb882cf99",
+		"5f4dcc3b5aa765d61d8327deb882cf99",
+		"",
+		"e10adc3949ba59abbe56e057f20f883e:123456",
+		"not-a-hash",
+		"$6$salt$abcdef",
+	}, "\n")
+
+	p := &HashlistDBProcessor{}
+	preview, err := p.previewLines(strings.NewReader(upload), md5, func(int) bool { return true })
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, preview.Lines)
+	assert.Equal(t, 3, preview.ValidLines)
+	assert.Equal(t, 2, preview.InvalidLines)
+	assert.Equal(t, 1, preview.DuplicateLines)
+	assert.Equal(t, 2, preview.UniqueHashes)
+
+	require.Len(t, preview.InvalidSamples, 2)
+	assert.Equal(t, 6, preview.InvalidSamples[0].LineNumber)
+	assert.Equal(t, "not-a-hash", preview.InvalidSamples[0].Line)
+	assert.NotEmpty(t, preview.InvalidSamples[0].Reason)
+
+	require.Len(t, preview.DetectedTypes, 2)
+	assert.Equal(t, models.HashlistStagingType{HashTypeID: 0, Lines: 4}, preview.DetectedTypes[0])
+	assert.Equal(t, models.HashlistStagingType{HashTypeID: 1800, Lines: 1, Detected: true}, preview.DetectedTypes[1])
+	assert.False(t, preview.TypeMismatch)
+}
+
+func TestPreviewLinesTypeMismatch(t *testing.T) {
+	md5 := &models.HashType{ID: 0, Name: "MD5"}
+	upload := "$6$salt$abcdef\n$6$salt$123456\n5f4dcc3b5aa765d61d8327deb882cf99\n"
+
+	p := &HashlistDBProcessor{}
+	preview, err := p.previewLines(strings.NewReader(upload), md5, func(int) bool { return true })
+	require.NoError(t, err)
+
+	assert.True(t, preview.TypeMismatch)
+	assert.Equal(t, 2, preview.InvalidLines)
+}
@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashutils"
+)
+
+// maxGroupSampleLength caps the example line returned for each hash type group
+const maxGroupSampleLength = 120
+
+// UploadTypeGroup counts the lines of an upload that belong to one hash type
+type UploadTypeGroup struct {
+	HashTypeID   int    `json:"hash_type_id"`
+	HashTypeName string `json:"hash_type_name,omitempty"`
+	Lines        int    `json:"lines"`
+	Detected     bool   `json:"detected"` // False for lines that fall back to the chosen hash type
+	Sample       string `json:"sample"`
+}
+
+// classifyLine returns the hash type of a line and whether it was detected
+// rather than falling back. Detected types rejected by accept fall back too.
+func classifyLine(line string, fallbackTypeID int, accept func(int) bool) (int, bool) {
+	if hashTypeID, ok := hashutils.DetectHashType(line); ok && hashTypeID != fallbackTypeID && accept(hashTypeID) {
+		return hashTypeID, true
+	}
+	return fallbackTypeID, false
+}
+
+// scanHashLines calls fn for every non-empty, non-comment line of r
+func scanHashLines(r io.Reader, fn func(line string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read upload: %w", err)
+	}
+	return nil
+}
+
+// DetectUploadTypes groups the lines of an upload by hash type, largest group
+// first. Lines without a recognizable signature count towards fallbackTypeID.
+func DetectUploadTypes(r io.Reader, fallbackTypeID int, accept func(int) bool) ([]UploadTypeGroup, error) {
+	groups := make(map[int]*UploadTypeGroup)
+	err := scanHashLines(r, func(line string) error {
+		hashTypeID, detected := classifyLine(line, fallbackTypeID, accept)
+		group, ok := groups[hashTypeID]
+		if !ok {
+			sample := line
+			if len(sample) > maxGroupSampleLength {
+				sample = sample[:maxGroupSampleLength] + "..."
+			}
+			group = &UploadTypeGroup{HashTypeID: hashTypeID, Detected: detected, Sample: sample}
+			groups[hashTypeID] = group
+		}
+		group.Lines++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]UploadTypeGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Lines != result[j].Lines {
+			return result[i].Lines > result[j].Lines
+		}
+		return result[i].HashTypeID < result[j].HashTypeID
+	})
+	return result, nil
+}
+
+// SplitUploadByType writes the lines of an upload into one file per hash type
+// in dir and returns the file of each type. The caller owns the files and
+// must remove them, also when an error is returned.
+func SplitUploadByType(r io.Reader, fallbackTypeID int, accept func(int) bool, dir string) (map[int]string, error) {
+	paths := make(map[int]string)
+	files := make(map[int]*os.File)
+	writers := make(map[int]*bufio.Writer)
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+
+	err := scanHashLines(r, func(line string) error {
+		hashTypeID, _ := classifyLine(line, fallbackTypeID, accept)
+		w, ok := writers[hashTypeID]
+		if !ok {
+			f, err := os.CreateTemp(dir, fmt.Sprintf("split_%d_*.txt", hashTypeID))
+			if err != nil {
+				return fmt.Errorf("failed to create split file for hash type %d: %w", hashTypeID, err)
+			}
+			files[hashTypeID] = f
+			paths[hashTypeID] = f.Name()
+			w = bufio.NewWriter(f)
+			writers[hashTypeID] = w
+		}
+		if _, err := w.WriteString(line + "\n"); err != nil {
+			return fmt.Errorf("failed to write split file for hash type %d: %w", hashTypeID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		closeAll()
+		return paths, err
+	}
+
+	for hashTypeID, w := range writers {
+		if err := w.Flush(); err != nil {
+			closeAll()
+			return paths, fmt.Errorf("failed to write split file for hash type %d: %w", hashTypeID, err)
+		}
+	}
+	for hashTypeID, f := range files {
+		if err := f.Close(); err != nil {
+			return paths, fmt.Errorf("failed to close split file for hash type %d: %w", hashTypeID, err)
+		}
+	}
+	return paths, nil
+}
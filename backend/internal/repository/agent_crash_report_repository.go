@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// AgentCrashReportRepository handles database operations for agent crash reports
+type AgentCrashReportRepository struct {
+	db *db.DB
+}
+
+// NewAgentCrashReportRepository creates a new agent crash report repository
+func NewAgentCrashReportRepository(db *db.DB) *AgentCrashReportRepository {
+	return &AgentCrashReportRepository{db: db}
+}
+
+// Create stores a crash report. A report the agent already sent is ignored
+// and false is returned.
+func (r *AgentCrashReportRepository) Create(ctx context.Context, report *models.AgentCrashReport) (bool, error) {
+	query := `
+		INSERT INTO agent_crash_reports (agent_id, report_id, kind, summary, task_id, occurred_at, bundle)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (agent_id, report_id) DO NOTHING
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		report.AgentID,
+		report.ReportID,
+		report.Kind,
+		report.Summary,
+		report.TaskID,
+		report.OccurredAt,
+		[]byte(report.Bundle),
+	).Scan(&report.ID, &report.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create agent crash report: %w", err)
+	}
+	return true, nil
+}
+
+// List returns up to limit crash reports, newest first, without their bundles.
+// With unreviewedOnly only reports still waiting for review are returned.
+func (r *AgentCrashReportRepository) List(ctx context.Context, unreviewedOnly bool, limit int) ([]models.AgentCrashReport, error) {
+	query := `
+		SELECT c.id, c.agent_id, a.name, c.report_id, c.kind, c.summary, c.task_id, c.occurred_at,
+			NULL::jsonb, c.reviewed_at, c.reviewed_by, c.created_at
+		FROM agent_crash_reports c
+		JOIN agents a ON a.id = c.agent_id
+		WHERE NOT $1 OR c.reviewed_at IS NULL
+		ORDER BY c.occurred_at DESC, c.id DESC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, unreviewedOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent crash reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.AgentCrashReport{}
+	for rows.Next() {
+		report, err := scanAgentCrashReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agent crash reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetByID returns a crash report including its bundle
+func (r *AgentCrashReportRepository) GetByID(ctx context.Context, id int64) (*models.AgentCrashReport, error) {
+	query := `
+		SELECT c.id, c.agent_id, a.name, c.report_id, c.kind, c.summary, c.task_id, c.occurred_at,
+			c.bundle, c.reviewed_at, c.reviewed_by, c.created_at
+		FROM agent_crash_reports c
+		JOIN agents a ON a.id = c.agent_id
+		WHERE c.id = $1`
+
+	report, err := scanAgentCrashReport(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return report, err
+}
+
+// MarkReviewed records that an administrator has reviewed a crash report
+func (r *AgentCrashReportRepository) MarkReviewed(ctx context.Context, id int64, reviewedBy uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE agent_crash_reports SET reviewed_at = CURRENT_TIMESTAMP, reviewed_by = $2 WHERE id = $1`,
+		id, reviewedBy)
+	if err != nil {
+		return fmt.Errorf("failed to mark agent crash report reviewed: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// scanAgentCrashReport scans a row selected by List or GetByID
+func scanAgentCrashReport(row rowScanner) (*models.AgentCrashReport, error) {
+	var report models.AgentCrashReport
+	var bundle []byte
+	err := row.Scan(
+		&report.ID,
+		&report.AgentID,
+		&report.AgentName,
+		&report.ReportID,
+		&report.Kind,
+		&report.Summary,
+		&report.TaskID,
+		&report.OccurredAt,
+		&bundle,
+		&report.ReviewedAt,
+		&report.ReviewedBy,
+		&report.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan agent crash report: %w", err)
+	}
+	if bundle != nil {
+		report.Bundle = bundle
+	}
+	return &report, nil
+}
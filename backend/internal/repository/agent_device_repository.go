@@ -22,7 +22,7 @@ func NewAgentDeviceRepository(db *db.DB) *AgentDeviceRepository {
 // GetByAgentID retrieves all devices for a specific agent
 func (r *AgentDeviceRepository) GetByAgentID(agentID int) ([]models.AgentDevice, error) {
 	query := `
-		SELECT id, agent_id, device_id, device_name, device_type, enabled, created_at, updated_at
+		SELECT id, agent_id, device_id, device_name, device_type, enabled, memory_total_mb, memory_free_mb, created_at, updated_at
 		FROM agent_devices
 		WHERE agent_id = $1
 		ORDER BY device_id`
@@ -43,6 +43,8 @@ func (r *AgentDeviceRepository) GetByAgentID(agentID int) ([]models.AgentDevice,
 			&device.DeviceName,
 			&device.DeviceType,
 			&device.Enabled,
+			&device.MemoryTotalMB,
+			&device.MemoryFreeMB,
 			&device.CreatedAt,
 			&device.UpdatedAt,
 		)
@@ -96,15 +98,26 @@ func (r *AgentDeviceRepository) UpsertDevices(agentID int, devices []models.Devi
 	// Upsert devices
 	for _, device := range devices {
 		upsertQuery := `
-			INSERT INTO agent_devices (agent_id, device_id, device_name, device_type, enabled, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6)
+			INSERT INTO agent_devices (agent_id, device_id, device_name, device_type, enabled, memory_total_mb, memory_free_mb, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 			ON CONFLICT (agent_id, device_id) 
 			DO UPDATE SET 
 				device_name = EXCLUDED.device_name,
 				device_type = EXCLUDED.device_type,
+				memory_total_mb = EXCLUDED.memory_total_mb,
+				memory_free_mb = EXCLUDED.memory_free_mb,
 				updated_at = EXCLUDED.updated_at`
 
-		_, err = tx.Exec(upsertQuery, agentID, device.ID, device.Name, device.Type, device.Enabled, time.Now())
+		// Hashcat omits memory for some backends, store unknown rather than zero
+		var memoryTotal, memoryFree sql.NullInt64
+		if device.MemoryTotal > 0 {
+			memoryTotal = sql.NullInt64{Int64: device.MemoryTotal, Valid: true}
+		}
+		if device.MemoryFree > 0 {
+			memoryFree = sql.NullInt64{Int64: device.MemoryFree, Valid: true}
+		}
+
+		_, err = tx.Exec(upsertQuery, agentID, device.ID, device.Name, device.Type, device.Enabled, memoryTotal, memoryFree, time.Now())
 		if err != nil {
 			return fmt.Errorf("failed to upsert device %d: %w", device.ID, err)
 		}
@@ -140,7 +153,7 @@ func (r *AgentDeviceRepository) UpdateDeviceStatus(agentID int, deviceID int, en
 // GetEnabledDevicesByAgentID retrieves only enabled devices for an agent
 func (r *AgentDeviceRepository) GetEnabledDevicesByAgentID(agentID int) ([]models.AgentDevice, error) {
 	query := `
-		SELECT id, agent_id, device_id, device_name, device_type, enabled, created_at, updated_at
+		SELECT id, agent_id, device_id, device_name, device_type, enabled, memory_total_mb, memory_free_mb, created_at, updated_at
 		FROM agent_devices
 		WHERE agent_id = $1 AND enabled = true
 		ORDER BY device_id`
@@ -161,6 +174,8 @@ func (r *AgentDeviceRepository) GetEnabledDevicesByAgentID(agentID int) ([]model
 			&device.DeviceName,
 			&device.DeviceType,
 			&device.Enabled,
+			&device.MemoryTotalMB,
+			&device.MemoryFreeMB,
 			&device.CreatedAt,
 			&device.UpdatedAt,
 		)
@@ -193,6 +208,25 @@ func (r *AgentDeviceRepository) HasEnabledDevices(agentID int) (bool, error) {
 	return count > 0, nil
 }
 
+// GetSmallestDeviceMemory returns the total memory in MB of the agent's enabled
+// device with the least memory, or 0 if no enabled device reported its memory.
+// Hashcat loads the rules and hashes onto every device, so the smallest one
+// decides whether an attack fits.
+func (r *AgentDeviceRepository) GetSmallestDeviceMemory(agentID int) (int64, error) {
+	var memoryMB sql.NullInt64
+	query := `
+		SELECT MIN(memory_total_mb)
+		FROM agent_devices
+		WHERE agent_id = $1 AND enabled = true AND memory_total_mb > 0`
+
+	err := r.db.QueryRow(query, agentID).Scan(&memoryMB)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get device memory for agent %d: %w", agentID, err)
+	}
+
+	return memoryMB.Int64, nil
+}
+
 // UpdateAgentDeviceDetectionStatus updates the device detection status for an agent
 func (r *AgentDeviceRepository) UpdateAgentDeviceDetectionStatus(agentID int, status string, errorMsg *string) error {
 	query := `
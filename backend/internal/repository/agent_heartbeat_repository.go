@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// AgentHeartbeatRepository handles database operations for agent heartbeat history
+type AgentHeartbeatRepository struct {
+	db *db.DB
+}
+
+// NewAgentHeartbeatRepository creates a new agent heartbeat repository
+func NewAgentHeartbeatRepository(db *db.DB) *AgentHeartbeatRepository {
+	return &AgentHeartbeatRepository{db: db}
+}
+
+// Create stores a heartbeat and prunes the agent's history down to the newest keep entries
+func (r *AgentHeartbeatRepository) Create(ctx context.Context, heartbeat *models.AgentHeartbeat, keep int) error {
+	query := `
+		INSERT INTO agent_heartbeats (agent_id, current_task_id, gpu_utilization, queue_depth, reconnect_count)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, received_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		heartbeat.AgentID,
+		heartbeat.CurrentTaskID,
+		heartbeat.GPUUtilization,
+		heartbeat.QueueDepth,
+		heartbeat.ReconnectCount,
+	).Scan(&heartbeat.ID, &heartbeat.ReceivedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create agent heartbeat: %w", err)
+	}
+
+	// Everything at or below the id of the first entry past the window is dropped
+	pruneQuery := `
+		DELETE FROM agent_heartbeats
+		WHERE agent_id = $1 AND id <= (
+			SELECT id FROM agent_heartbeats
+			WHERE agent_id = $1
+			ORDER BY id DESC
+			OFFSET $2 LIMIT 1
+		)`
+	if _, err := r.db.ExecContext(ctx, pruneQuery, heartbeat.AgentID, keep); err != nil {
+		return fmt.Errorf("failed to prune agent heartbeats: %w", err)
+	}
+
+	return nil
+}
+
+// ListRecent returns up to limit of the agent's most recent heartbeats, oldest first
+func (r *AgentHeartbeatRepository) ListRecent(ctx context.Context, agentID int, limit int) ([]models.AgentHeartbeat, error) {
+	query := `
+		SELECT id, agent_id, received_at, current_task_id, gpu_utilization, queue_depth, reconnect_count
+		FROM agent_heartbeats
+		WHERE agent_id = $1
+		ORDER BY id DESC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, agentID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent heartbeats: %w", err)
+	}
+	defer rows.Close()
+
+	heartbeats := []models.AgentHeartbeat{}
+	for rows.Next() {
+		var hb models.AgentHeartbeat
+		if err := rows.Scan(
+			&hb.ID,
+			&hb.AgentID,
+			&hb.ReceivedAt,
+			&hb.CurrentTaskID,
+			&hb.GPUUtilization,
+			&hb.QueueDepth,
+			&hb.ReconnectCount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan agent heartbeat: %w", err)
+		}
+		heartbeats = append(heartbeats, hb)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agent heartbeats: %w", err)
+	}
+
+	// Reverse into chronological order for charting
+	for i, j := 0, len(heartbeats)-1; i < j; i, j = i+1, j-1 {
+		heartbeats[i], heartbeats[j] = heartbeats[j], heartbeats[i]
+	}
+
+	return heartbeats, nil
+}
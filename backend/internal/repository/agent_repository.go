@@ -11,6 +11,7 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db/queries"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // AgentRepository handles database operations for agents
@@ -103,6 +104,10 @@ func (r *AgentRepository) GetByID(ctx context.Context, id int) (*models.Agent, e
 		&agent.FilesToSync,
 		&agent.FilesSynced,
 		&agent.SyncError,
+		pq.Array(&agent.Labels),
+		&agent.WorkloadClass,
+		&agent.WorkloadProfile,
+		&agent.ManagedConfig,
 		&createdByUser.ID,
 		&createdByUser.Username,
 		&createdByUser.Email,
@@ -157,7 +162,8 @@ func (r *AgentRepository) ExistsByName(ctx context.Context, name string) (bool,
 	return exists, nil
 }
 
-// Update updates an agent
+// Update updates an agent. Its API key is left alone, keys only change
+// through registration and credential rotation.
 func (r *AgentRepository) Update(ctx context.Context, agent *models.Agent) error {
 	// Convert hardware to JSON
 	hardwareJSON, err := json.Marshal(agent.Hardware)
@@ -187,8 +193,6 @@ func (r *AgentRepository) Update(ctx context.Context, agent *models.Agent) error
 		hardwareJSON,
 		osInfoJSON,
 		agent.UpdatedAt,
-		agent.APIKey,
-		agent.APIKeyCreatedAt,
 		agent.APIKeyLastUsed,
 		metadataJSON,
 		agent.SyncStatus,
@@ -349,6 +353,10 @@ func (r *AgentRepository) List(ctx context.Context, filters map[string]interface
 			&agent.FilesToSync,
 			&agent.FilesSynced,
 			&agent.SyncError,
+			pq.Array(&agent.Labels),
+			&agent.WorkloadClass,
+			&agent.WorkloadProfile,
+			&agent.ManagedConfig,
 			&createdByUser.ID,
 			&createdByUser.Username,
 			&createdByUser.Email,
@@ -688,6 +696,27 @@ func (r *AgentRepository) UpdateAgentSettings(ctx context.Context, agentID int,
 	return nil
 }
 
+// Disable stops the scheduler from giving an agent work until an admin
+// enables it again
+func (r *AgentRepository) Disable(ctx context.Context, q Querier, id int) error {
+	result, err := q.ExecContext(ctx,
+		`UPDATE agents SET is_enabled = false, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to disable agent: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 // UpdateVersion updates the version field for an agent
 func (r *AgentRepository) UpdateVersion(ctx context.Context, id int, version string) error {
 	query := `UPDATE agents SET version = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
@@ -709,6 +738,28 @@ func (r *AgentRepository) UpdateVersion(ctx context.Context, id int, version str
 	return nil
 }
 
+// UpdateRuntimeVersions stores the GPU driver and hashcat binary versions an
+// agent reported and returns the ones stored before. Empty versions leave the
+// stored value unchanged.
+func (r *AgentRepository) UpdateRuntimeVersions(ctx context.Context, id int, driverVersion, binaryVersion string) (prevDriver, prevBinary string, err error) {
+	query := `
+		UPDATE agents a
+		SET driver_version = COALESCE(NULLIF($2, ''), a.driver_version),
+		    binary_version = COALESCE(NULLIF($3, ''), a.binary_version)
+		FROM (SELECT id, driver_version, binary_version FROM agents WHERE id = $1 FOR UPDATE) old
+		WHERE a.id = old.id
+		RETURNING COALESCE(old.driver_version, ''), COALESCE(old.binary_version, '')`
+
+	err = r.db.QueryRowContext(ctx, query, id, driverVersion, binaryVersion).Scan(&prevDriver, &prevBinary)
+	if err == sql.ErrNoRows {
+		return "", "", fmt.Errorf("agent not found")
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to update agent runtime versions: %w", err)
+	}
+	return prevDriver, prevBinary, nil
+}
+
 // UpdateMetadata updates an agent's metadata field
 func (r *AgentRepository) UpdateMetadata(ctx context.Context, agentID int, metadata map[string]string) error {
 	// Convert metadata to JSON
@@ -789,3 +840,80 @@ func (r *AgentRepository) UpdateOSInfo(ctx context.Context, agentID int, osInfo
 
 	return nil
 }
+
+// SetPendingAPIKey stores the API key a credential rotation sent to the
+// agent, replacing that of an earlier rotation it never confirmed
+func (r *AgentRepository) SetPendingAPIKey(ctx context.Context, agentID int, apiKey string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE agents SET pending_api_key = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`,
+		agentID, apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to set pending API key: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// PromotePendingAPIKey makes the agent's pending API key its key if it is
+// still apiKey. The replaced key is accepted until previousExpiresAt. It
+// reports whether the key was promoted.
+func (r *AgentRepository) PromotePendingAPIKey(ctx context.Context, agentID int, apiKey string, previousExpiresAt time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE agents SET
+			previous_api_key = api_key,
+			previous_api_key_expires_at = $3,
+			api_key = pending_api_key,
+			api_key_created_at = CURRENT_TIMESTAMP,
+			pending_api_key = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND pending_api_key = $2`,
+		agentID, apiKey, previousExpiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to promote pending API key: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// ClearPendingAPIKey drops the pending API key of a rotation the agent
+// could not apply, if it is still apiKey
+func (r *AgentRepository) ClearPendingAPIKey(ctx context.Context, agentID int, apiKey string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE agents SET pending_api_key = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND pending_api_key = $2`,
+		agentID, apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to clear pending API key: %w", err)
+	}
+	return nil
+}
+
+// ListAPIKeysIssuedBefore returns the IDs of the agents whose API key was
+// issued before the given time, or has no issue time
+func (r *AgentRepository) ListAPIKeysIssuedBefore(ctx context.Context, before time.Time) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id FROM agents
+		WHERE api_key IS NOT NULL AND (api_key_created_at IS NULL OR api_key_created_at < $1)
+		ORDER BY id`,
+		before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents by API key age: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan agent ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/lib/pq"
 )
 
 // UpdateConsecutiveFailures updates the consecutive failures count for an agent
@@ -25,3 +28,121 @@ func (r *AgentRepository) UpdateConsecutiveFailures(ctx context.Context, agentID
 
 	return nil
 }
+
+// SetBootstrapToken sets the token a pre-registered agent uses to fetch its credentials
+func (r *AgentRepository) SetBootstrapToken(ctx context.Context, agentID int, token string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE agents SET bootstrap_token = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, agentID, token)
+	if err != nil {
+		return fmt.Errorf("failed to set agent bootstrap token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetIDByBootstrapToken returns the ID of the agent holding a bootstrap token
+func (r *AgentRepository) GetIDByBootstrapToken(ctx context.Context, token string) (int, error) {
+	var agentID int
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM agents WHERE bootstrap_token = $1`, token).Scan(&agentID)
+	if err != nil {
+		return 0, err
+	}
+	return agentID, nil
+}
+
+// GetBootstrapInfoByName returns the ID, owner and bootstrap token of the agent
+// with the given name. Returns sql.ErrNoRows if there is no such agent.
+func (r *AgentRepository) GetBootstrapInfoByName(ctx context.Context, name string) (int, sql.NullString, sql.NullString, error) {
+	var agentID int
+	var ownerID, token sql.NullString
+	err := r.db.QueryRowContext(ctx, `SELECT id, owner_id, bootstrap_token FROM agents WHERE name = $1`, name).Scan(&agentID, &ownerID, &token)
+	if err != nil {
+		return 0, ownerID, token, err
+	}
+	return agentID, ownerID, token, nil
+}
+
+// GetOwnerID returns the owner of an agent. Returns sql.ErrNoRows if there is
+// no such agent.
+func (r *AgentRepository) GetOwnerID(ctx context.Context, agentID int) (sql.NullString, error) {
+	var ownerID sql.NullString
+	err := r.db.QueryRowContext(ctx, `SELECT owner_id FROM agents WHERE id = $1`, agentID).Scan(&ownerID)
+	return ownerID, err
+}
+
+// ClearBootstrapToken revokes an agent's bootstrap token
+func (r *AgentRepository) ClearBootstrapToken(ctx context.Context, agentID int) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE agents SET bootstrap_token = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to clear agent bootstrap token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateEnabled enables or disables an agent for new work
+func (r *AgentRepository) UpdateEnabled(ctx context.Context, agentID int, isEnabled bool) error {
+	query := `UPDATE agents SET is_enabled = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+	return r.execAgentUpdate(ctx, query, "enabled state", agentID, isEnabled)
+}
+
+// UpdateExtraParameters replaces an agent's extra hashcat parameters
+func (r *AgentRepository) UpdateExtraParameters(ctx context.Context, agentID int, extraParameters string) error {
+	query := `UPDATE agents SET extra_parameters = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+	return r.execAgentUpdate(ctx, query, "extra parameters", agentID, extraParameters)
+}
+
+// UpdateLabels replaces an agent's labels
+func (r *AgentRepository) UpdateLabels(ctx context.Context, agentID int, labels []string) error {
+	query := `UPDATE agents SET labels = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+	return r.execAgentUpdate(ctx, query, "labels", agentID, pq.Array(labels))
+}
+
+// UpdateWorkload sets an agent's workload class and profile, a nil profile
+// uses the class default
+func (r *AgentRepository) UpdateWorkload(ctx context.Context, agentID int, class models.WorkloadClass, profile *int) error {
+	query := `UPDATE agents SET workload_class = $2, workload_profile = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+	return r.execAgentUpdate(ctx, query, "workload", agentID, string(class), profile)
+}
+
+// UpdateManagedConfig replaces the settings pushed to an agent
+func (r *AgentRepository) UpdateManagedConfig(ctx context.Context, agentID int, config models.AgentManagedConfig) error {
+	query := `UPDATE agents SET managed_config = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+	return r.execAgentUpdate(ctx, query, "managed config", agentID, config)
+}
+
+// execAgentUpdate runs a single agent update, sql.ErrNoRows if the agent does not exist
+func (r *AgentRepository) execAgentUpdate(ctx context.Context, query, what string, agentID int, values ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, append([]interface{}{agentID}, values...)...)
+	if err != nil {
+		return fmt.Errorf("failed to update agent %s: %w", what, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -227,4 +227,226 @@ func (r *AgentScheduleRepository) UpdateAgentScheduling(ctx context.Context, age
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// GetPowerWindowsByAgent gets all low-power windows for an agent
+func (r *AgentScheduleRepository) GetPowerWindowsByAgent(ctx context.Context, agentID int) ([]models.AgentPowerWindow, error) {
+	query := `
+		SELECT id, agent_id, day_of_week, start_time, end_time, timezone, max_priority, is_active, created_at, updated_at
+		FROM agent_power_windows
+		WHERE agent_id = $1
+		ORDER BY day_of_week`
+
+	rows, err := r.db.QueryContext(ctx, query, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get power windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []models.AgentPowerWindow{}
+	for rows.Next() {
+		var w models.AgentPowerWindow
+		if err := rows.Scan(
+			&w.ID,
+			&w.AgentID,
+			&w.DayOfWeek,
+			&w.StartTime,
+			&w.EndTime,
+			&w.Timezone,
+			&w.MaxPriority,
+			&w.IsActive,
+			&w.CreatedAt,
+			&w.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan power window: %w", err)
+		}
+		windows = append(windows, w)
+	}
+
+	return windows, rows.Err()
+}
+
+// UpsertPowerWindow creates or replaces the low-power window for an agent on a day
+func (r *AgentScheduleRepository) UpsertPowerWindow(ctx context.Context, window *models.AgentPowerWindow) error {
+	query := `
+		INSERT INTO agent_power_windows (agent_id, day_of_week, start_time, end_time, timezone, max_priority, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (agent_id, day_of_week) DO UPDATE
+		SET start_time = EXCLUDED.start_time, end_time = EXCLUDED.end_time, timezone = EXCLUDED.timezone,
+			max_priority = EXCLUDED.max_priority, is_active = EXCLUDED.is_active
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		window.AgentID,
+		window.DayOfWeek,
+		window.StartTime.String(), // Convert TimeOnly to string for storage
+		window.EndTime.String(),   // Convert TimeOnly to string for storage
+		window.Timezone,
+		window.MaxPriority,
+		window.IsActive,
+	).Scan(&window.ID, &window.CreatedAt, &window.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save power window: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePowerWindow deletes the low-power window for a specific day
+func (r *AgentScheduleRepository) DeletePowerWindow(ctx context.Context, agentID int, dayOfWeek int) error {
+	query := `DELETE FROM agent_power_windows WHERE agent_id = $1 AND day_of_week = $2`
+
+	result, err := r.db.ExecContext(ctx, query, agentID, dayOfWeek)
+	if err != nil {
+		return fmt.Errorf("failed to delete power window: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetActivePowerWindow returns the low-power window covering the current UTC time,
+// or nil if the agent is not in one. Overnight windows from yesterday are included.
+func (r *AgentScheduleRepository) GetActivePowerWindow(ctx context.Context, agentID int) (*models.AgentPowerWindow, error) {
+	now := time.Now().UTC()
+	currentDay := int(now.Weekday())
+	yesterday := (currentDay - 1 + 7) % 7
+	currentTimeStr := now.Format("15:04:05") // Include seconds for proper comparison
+
+	query := `
+		SELECT id, agent_id, day_of_week, start_time, end_time, timezone, max_priority, is_active, created_at, updated_at
+		FROM agent_power_windows
+		WHERE agent_id = $1
+		AND is_active = true
+		AND (
+			(day_of_week = $2 AND (
+				-- Normal window (start < end)
+				(start_time < end_time AND $4::time >= start_time AND $4::time < end_time)
+				OR
+				-- Overnight window (start > end, e.g., 22:00 - 02:00)
+				(start_time > end_time AND ($4::time >= start_time OR $4::time < end_time))
+			))
+			OR
+			-- Overnight window from yesterday that extends into today
+			(day_of_week = $3 AND start_time > end_time AND $4::time < end_time)
+		)
+		ORDER BY max_priority
+		LIMIT 1`
+
+	var w models.AgentPowerWindow
+	err := r.db.QueryRowContext(ctx, query, agentID, currentDay, yesterday, currentTimeStr).Scan(
+		&w.ID,
+		&w.AgentID,
+		&w.DayOfWeek,
+		&w.StartTime,
+		&w.EndTime,
+		&w.Timezone,
+		&w.MaxPriority,
+		&w.IsActive,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to check power window: %w", err)
+	}
+
+	return &w, nil
+}
+
+// GetPowerBudget gets the daily power budget for an agent along with the energy
+// used so far today, or nil if the agent has no budget
+func (r *AgentScheduleRepository) GetPowerBudget(ctx context.Context, agentID int) (*models.AgentPowerBudget, error) {
+	query := `
+		SELECT agent_id, power_draw_watts, daily_budget_wh, created_at, updated_at
+		FROM agent_power_budgets
+		WHERE agent_id = $1`
+
+	var budget models.AgentPowerBudget
+	err := r.db.QueryRowContext(ctx, query, agentID).Scan(
+		&budget.AgentID,
+		&budget.PowerDrawWatts,
+		&budget.DailyBudgetWh,
+		&budget.CreatedAt,
+		&budget.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get power budget: %w", err)
+	}
+
+	// Seconds spent running tasks since midnight UTC. Running tasks count up to now,
+	// finished ones up to when they last changed if they never recorded completion.
+	usageQuery := `
+		WITH day AS (
+			SELECT date_trunc('day', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS start
+		), spans AS (
+			SELECT
+				GREATEST(jt.started_at, day.start) AS span_start,
+				CASE WHEN jt.status = 'running' THEN NOW() ELSE COALESCE(jt.completed_at, jt.updated_at) END AS span_end
+			FROM job_tasks jt, day
+			WHERE jt.agent_id = $1 AND jt.started_at IS NOT NULL
+		)
+		SELECT COALESCE(SUM(EXTRACT(EPOCH FROM (span_end - span_start))), 0)
+		FROM spans
+		WHERE span_end > span_start`
+
+	var seconds float64
+	if err := r.db.QueryRowContext(ctx, usageQuery, agentID).Scan(&seconds); err != nil {
+		return nil, fmt.Errorf("failed to get power usage: %w", err)
+	}
+	budget.UsedTodayWh = seconds / 3600 * float64(budget.PowerDrawWatts)
+
+	return &budget, nil
+}
+
+// SetPowerBudget creates or replaces the daily power budget for an agent
+func (r *AgentScheduleRepository) SetPowerBudget(ctx context.Context, budget *models.AgentPowerBudget) error {
+	query := `
+		INSERT INTO agent_power_budgets (agent_id, power_draw_watts, daily_budget_wh)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (agent_id) DO UPDATE
+		SET power_draw_watts = EXCLUDED.power_draw_watts, daily_budget_wh = EXCLUDED.daily_budget_wh
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, budget.AgentID, budget.PowerDrawWatts, budget.DailyBudgetWh).
+		Scan(&budget.CreatedAt, &budget.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save power budget: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePowerBudget removes the daily power budget for an agent
+func (r *AgentScheduleRepository) DeletePowerBudget(ctx context.Context, agentID int) error {
+	query := `DELETE FROM agent_power_budgets WHERE agent_id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete power budget: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
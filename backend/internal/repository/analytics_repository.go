@@ -28,8 +28,8 @@ func (r *AnalyticsRepository) Create(ctx context.Context, report *models.Analyti
 		INSERT INTO analytics_reports (
 			id, client_id, user_id, start_date, end_date, status,
 			analytics_data, total_hashlists, total_hashes, total_cracked,
-			queue_position, custom_patterns, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			queue_position, custom_patterns, password_policy, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -45,6 +45,7 @@ func (r *AnalyticsRepository) Create(ctx context.Context, report *models.Analyti
 		report.TotalCracked,
 		report.QueuePosition,
 		report.CustomPatterns,
+		report.PasswordPolicy,
 		report.CreatedAt,
 	)
 	if err != nil {
@@ -59,7 +60,7 @@ func (r *AnalyticsRepository) GetByID(ctx context.Context, id uuid.UUID) (*model
 	query := `
 		SELECT id, client_id, user_id, start_date, end_date, status,
 			analytics_data, total_hashlists, total_hashes, total_cracked,
-			queue_position, custom_patterns, created_at, started_at, completed_at, error_message
+			queue_position, custom_patterns, password_policy, created_at, started_at, completed_at, error_message
 		FROM analytics_reports
 		WHERE id = $1
 	`
@@ -78,6 +79,7 @@ func (r *AnalyticsRepository) GetByID(ctx context.Context, id uuid.UUID) (*model
 		&report.TotalCracked,
 		&report.QueuePosition,
 		&report.CustomPatterns,
+		&report.PasswordPolicy,
 		&report.CreatedAt,
 		&report.StartedAt,
 		&report.CompletedAt,
@@ -98,7 +100,7 @@ func (r *AnalyticsRepository) GetByClient(ctx context.Context, clientID uuid.UUI
 	query := `
 		SELECT id, client_id, user_id, start_date, end_date, status,
 			analytics_data, total_hashlists, total_hashes, total_cracked,
-			queue_position, custom_patterns, created_at, started_at, completed_at, error_message
+			queue_position, custom_patterns, password_policy, created_at, started_at, completed_at, error_message
 		FROM analytics_reports
 		WHERE client_id = $1
 		ORDER BY created_at DESC
@@ -126,6 +128,7 @@ func (r *AnalyticsRepository) GetByClient(ctx context.Context, clientID uuid.UUI
 			&report.TotalCracked,
 			&report.QueuePosition,
 			&report.CustomPatterns,
+			&report.PasswordPolicy,
 			&report.CreatedAt,
 			&report.StartedAt,
 			&report.CompletedAt,
@@ -272,7 +275,7 @@ func (r *AnalyticsRepository) GetQueuedReports(ctx context.Context) ([]*models.A
 	query := `
 		SELECT id, client_id, user_id, start_date, end_date, status,
 			analytics_data, total_hashlists, total_hashes, total_cracked,
-			queue_position, custom_patterns, created_at, started_at, completed_at, error_message
+			queue_position, custom_patterns, password_policy, created_at, started_at, completed_at, error_message
 		FROM analytics_reports
 		WHERE status = 'queued'
 		ORDER BY queue_position ASC, created_at ASC
@@ -300,6 +303,7 @@ func (r *AnalyticsRepository) GetQueuedReports(ctx context.Context) ([]*models.A
 			&report.TotalCracked,
 			&report.QueuePosition,
 			&report.CustomPatterns,
+			&report.PasswordPolicy,
 			&report.CreatedAt,
 			&report.StartedAt,
 			&report.CompletedAt,
@@ -367,7 +371,7 @@ func (r *AnalyticsRepository) GetHashlistsByClientAndDateRange(ctx context.Conte
 		ORDER BY created_at ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, clientID, startDate, endDate)
+	rows, err := r.db.Reader().QueryContext(ctx, query, clientID, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query hashlists by date range: %w", err)
 	}
@@ -405,7 +409,7 @@ func (r *AnalyticsRepository) GetCrackedPasswordsByHashlists(ctx context.Context
 		ORDER BY h.password
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(hashlistIDs))
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashlistIDs))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query cracked passwords: %w", err)
 	}
@@ -462,7 +466,7 @@ func (r *AnalyticsRepository) GetCrackedPasswordsWithHashlists(ctx context.Conte
 		ORDER BY h.password
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(hashlistIDs))
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashlistIDs))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query cracked passwords with hashlists: %w", err)
 	}
@@ -495,6 +499,78 @@ func (r *AnalyticsRepository) GetCrackedPasswordsWithHashlists(ctx context.Conte
 	return results, nil
 }
 
+// CorrelatedAccount is an account of a hashlist that shares its username or
+// cracked password with an account of another hashlist
+type CorrelatedAccount struct {
+	HashlistID   int64
+	HashlistName string
+	Username     string
+	Domain       string
+	IsCracked    bool
+	Password     string
+}
+
+// GetCorrelatedAccounts retrieves the accounts of the hashlists whose username
+// (ignoring case) appears in another of the hashlists, or whose cracked
+// password was also cracked in another of the hashlists
+func (r *AnalyticsRepository) GetCorrelatedAccounts(ctx context.Context, hashlistIDs []int64) ([]CorrelatedAccount, error) {
+	if len(hashlistIDs) < 2 {
+		return []CorrelatedAccount{}, nil
+	}
+
+	query := `
+		WITH accounts AS (
+			SELECT DISTINCT
+				hh.hashlist_id, h.username, COALESCE(h.domain, '') AS domain,
+				h.is_cracked, COALESCE(h.password, '') AS password, LOWER(h.username) AS account
+			FROM hashes h
+			JOIN hashlist_hashes hh ON h.id = hh.hash_id
+			WHERE hh.hashlist_id = ANY($1)
+			  AND h.username IS NOT NULL
+			  AND h.username <> ''
+		),
+		shared_accounts AS (
+			SELECT account FROM accounts
+			GROUP BY account
+			HAVING COUNT(DISTINCT hashlist_id) > 1
+		),
+		shared_passwords AS (
+			SELECT password FROM accounts
+			WHERE is_cracked AND password <> ''
+			GROUP BY password
+			HAVING COUNT(DISTINCT hashlist_id) > 1
+		)
+		SELECT a.hashlist_id, hl.name, a.username, a.domain, a.is_cracked, a.password
+		FROM accounts a
+		JOIN hashlists hl ON hl.id = a.hashlist_id
+		WHERE a.account IN (SELECT account FROM shared_accounts)
+		   OR (a.is_cracked AND a.password IN (SELECT password FROM shared_passwords))
+		ORDER BY a.account, a.hashlist_id
+	`
+
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashlistIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query correlated accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var results []CorrelatedAccount
+	for rows.Next() {
+		var account CorrelatedAccount
+		if err := rows.Scan(&account.HashlistID, &account.HashlistName, &account.Username,
+			&account.Domain, &account.IsCracked, &account.Password); err != nil {
+			return nil, fmt.Errorf("failed to scan correlated account row: %w", err)
+		}
+		results = append(results, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating correlated account rows: %w", err)
+	}
+
+	return results, nil
+}
+
 // GetJobTaskSpeedsByHashlists retrieves average speeds from job tasks related to the hashlists
 func (r *AnalyticsRepository) GetJobTaskSpeedsByHashlists(ctx context.Context, hashlistIDs []int64) ([]int64, error) {
 	if len(hashlistIDs) == 0 {
@@ -510,7 +586,7 @@ func (r *AnalyticsRepository) GetJobTaskSpeedsByHashlists(ctx context.Context, h
 		  AND jt.average_speed > 0
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(hashlistIDs))
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashlistIDs))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query job task speeds: %w", err)
 	}
@@ -546,7 +622,7 @@ func (r *AnalyticsRepository) GetHashlistsInfo(ctx context.Context, hashlistIDs
 		WHERE id = ANY($1)
 	`
 
-	err = r.db.QueryRowContext(ctx, query, pq.Array(hashlistIDs)).Scan(&totalHashes, &totalCracked)
+	err = r.db.Reader().QueryRowContext(ctx, query, pq.Array(hashlistIDs)).Scan(&totalHashes, &totalCracked)
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get hashlist info: %w", err)
 	}
@@ -566,7 +642,7 @@ func (r *AnalyticsRepository) GetHashTypesByIDs(ctx context.Context, hashTypeIDs
 		WHERE id = ANY($1)
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(hashTypeIDs))
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashTypeIDs))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query hash types: %w", err)
 	}
@@ -603,7 +679,7 @@ func (r *AnalyticsRepository) List(ctx context.Context, limit, offset int) ([]*m
 	query := `
 		SELECT id, client_id, user_id, start_date, end_date, status,
 			analytics_data, total_hashlists, total_hashes, total_cracked,
-			queue_position, custom_patterns, created_at, started_at, completed_at, error_message
+			queue_position, custom_patterns, password_policy, created_at, started_at, completed_at, error_message
 		FROM analytics_reports
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -631,6 +707,7 @@ func (r *AnalyticsRepository) List(ctx context.Context, limit, offset int) ([]*m
 			&report.TotalCracked,
 			&report.QueuePosition,
 			&report.CustomPatterns,
+			&report.PasswordPolicy,
 			&report.CreatedAt,
 			&report.StartedAt,
 			&report.CompletedAt,
@@ -694,7 +771,7 @@ func (r *AnalyticsRepository) GetHashCountsByType(ctx context.Context, hashlistI
 		ORDER BY h.hash_type_id
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(hashlistIDs))
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashlistIDs))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query hash counts by type: %w", err)
 	}
@@ -733,7 +810,7 @@ func (r *AnalyticsRepository) GetDomainsByHashlists(ctx context.Context, hashlis
 		ORDER BY h.domain
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(hashlistIDs))
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashlistIDs))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query domains: %w", err)
 	}
@@ -771,7 +848,7 @@ func (r *AnalyticsRepository) GetDomainStats(ctx context.Context, hashlistIDs []
 		  AND h.domain = $2
 	`
 
-	err = r.db.QueryRowContext(ctx, query, pq.Array(hashlistIDs), domain).Scan(&total, &cracked)
+	err = r.db.Reader().QueryRowContext(ctx, query, pq.Array(hashlistIDs), domain).Scan(&total, &cracked)
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get domain stats for domain %s: %w", domain, err)
 	}
@@ -796,7 +873,7 @@ func (r *AnalyticsRepository) GetCrackedPasswordsByHashlistsAndDomain(ctx contex
 		ORDER BY h.password
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(hashlistIDs), domain)
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashlistIDs), domain)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query cracked passwords for domain %s: %w", domain, err)
 	}
@@ -849,7 +926,7 @@ func (r *AnalyticsRepository) GetCrackedPasswordsWithHashlistsAndDomain(ctx cont
 		ORDER BY h.password
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(hashlistIDs), domain)
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashlistIDs), domain)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query cracked passwords with hashlists for domain %s: %w", domain, err)
 	}
@@ -902,7 +979,7 @@ func (r *AnalyticsRepository) GetHashCountsByTypeDomain(ctx context.Context, has
 		ORDER BY h.hash_type_id
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(hashlistIDs), domain)
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashlistIDs), domain)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query hash counts by type for domain %s: %w", domain, err)
 	}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// AttackCoverageFilter narrows the coverage entries returned for a hashlist
+type AttackCoverageFilter struct {
+	AttackMode    *int
+	WordlistID    string // Entries that used this wordlist
+	RuleID        string // Entries that used this rule file
+	CompletedOnly bool
+}
+
+// AttackCoverageRepository reads the per-hashlist attack coverage maintained
+// by the record_hashlist_attack_coverage trigger.
+type AttackCoverageRepository struct {
+	db *db.DB
+}
+
+// NewAttackCoverageRepository creates a new attack coverage repository
+func NewAttackCoverageRepository(database *db.DB) *AttackCoverageRepository {
+	return &AttackCoverageRepository{db: database}
+}
+
+// coverageSelect resolves wordlist and rule IDs to names, keeping the order in
+// which they were passed to hashcat. Unknown (deleted) IDs fall back to the ID.
+const coverageSelect = `
+	SELECT c.id, c.hashlist_id, c.attack_mode, c.hash_type, c.wordlist_ids, c.rule_ids,
+		c.mask, c.additional_args, c.run_count, c.completed_count, c.best_progress_percent,
+		c.keyspace, c.crack_count, c.last_job_execution_id, c.last_status, c.first_run_at, c.last_run_at,
+		(SELECT COALESCE(json_agg(COALESCE(w.name, e.id) ORDER BY e.ord), '[]')
+			FROM jsonb_array_elements_text(c.wordlist_ids) WITH ORDINALITY AS e(id, ord)
+			LEFT JOIN wordlists w ON w.id::text = e.id),
+		(SELECT COALESCE(json_agg(COALESCE(rl.name, e.id) ORDER BY e.ord), '[]')
+			FROM jsonb_array_elements_text(c.rule_ids) WITH ORDINALITY AS e(id, ord)
+			LEFT JOIN rules rl ON rl.id::text = e.id)
+	FROM hashlist_attack_coverage c`
+
+// ListByHashlist returns the attacks that have been run against a hashlist, most recent first
+func (r *AttackCoverageRepository) ListByHashlist(ctx context.Context, hashlistID int64, filter AttackCoverageFilter) ([]models.HashlistAttackCoverage, error) {
+	conditions := []string{"c.hashlist_id = $1"}
+	args := []interface{}{hashlistID}
+
+	if filter.AttackMode != nil {
+		args = append(args, *filter.AttackMode)
+		conditions = append(conditions, fmt.Sprintf("c.attack_mode = $%d", len(args)))
+	}
+	if filter.WordlistID != "" {
+		args = append(args, filter.WordlistID)
+		conditions = append(conditions, fmt.Sprintf("c.wordlist_ids ? $%d", len(args)))
+	}
+	if filter.RuleID != "" {
+		args = append(args, filter.RuleID)
+		conditions = append(conditions, fmt.Sprintf("c.rule_ids ? $%d", len(args)))
+	}
+	if filter.CompletedOnly {
+		conditions = append(conditions, "c.completed_count > 0")
+	}
+
+	query := coverageSelect + " WHERE " + strings.Join(conditions, " AND ") + " ORDER BY c.last_run_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attack coverage for hashlist %d: %w", hashlistID, err)
+	}
+	defer rows.Close()
+
+	entries := []models.HashlistAttackCoverage{}
+	for rows.Next() {
+		entry, err := scanAttackCoverage(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attack coverage rows: %w", err)
+	}
+	return entries, nil
+}
+
+// GetSummary aggregates the coverage entries of a hashlist
+func (r *AttackCoverageRepository) GetSummary(ctx context.Context, hashlistID int64) (*models.HashlistCoverageSummary, error) {
+	summary := &models.HashlistCoverageSummary{HashlistID: hashlistID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE completed_count > 0),
+			COALESCE(SUM(run_count), 0), COALESCE(SUM(crack_count), 0)
+		FROM hashlist_attack_coverage
+		WHERE hashlist_id = $1`, hashlistID).Scan(
+		&summary.DistinctAttacks, &summary.CompletedAttacks, &summary.TotalRuns, &summary.TotalCracks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attack coverage summary for hashlist %d: %w", hashlistID, err)
+	}
+	return summary, nil
+}
+
+func scanAttackCoverage(row rowScanner) (*models.HashlistAttackCoverage, error) {
+	var c models.HashlistAttackCoverage
+	var wordlistNames, ruleNames []byte
+	err := row.Scan(
+		&c.ID, &c.HashlistID, &c.AttackMode, &c.HashType, &c.WordlistIDs, &c.RuleIDs,
+		&c.Mask, &c.AdditionalArgs, &c.RunCount, &c.CompletedCount, &c.BestProgressPercent,
+		&c.Keyspace, &c.CrackCount, &c.LastJobExecutionID, &c.LastStatus, &c.FirstRunAt, &c.LastRunAt,
+		&wordlistNames, &ruleNames,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan attack coverage: %w", err)
+	}
+	if err := json.Unmarshal(wordlistNames, &c.WordlistNames); err != nil {
+		return nil, fmt.Errorf("failed to decode wordlist names: %w", err)
+	}
+	if err := json.Unmarshal(ruleNames, &c.RuleNames); err != nil {
+		return nil, fmt.Errorf("failed to decode rule names: %w", err)
+	}
+	return &c, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -22,20 +23,32 @@ func NewBenchmarkRepository(db *db.DB) *BenchmarkRepository {
 	return &BenchmarkRepository{db: db}
 }
 
-// CreateOrUpdateAgentBenchmark creates or updates an agent benchmark
+// CreateOrUpdateAgentBenchmark stores the result of a benchmark run. Besides
+// speed, which completed tasks keep updating, it records the measured speed
+// and per-device speeds that performance scoring compares tasks against.
 func (r *BenchmarkRepository) CreateOrUpdateAgentBenchmark(ctx context.Context, benchmark *models.AgentBenchmark) error {
+	deviceSpeeds := benchmark.DeviceSpeeds
+	if deviceSpeeds == nil {
+		deviceSpeeds = map[int]int64{}
+	}
+	deviceSpeedsJSON, err := json.Marshal(deviceSpeeds)
+	if err != nil {
+		return fmt.Errorf("failed to encode benchmark device speeds: %w", err)
+	}
+
 	query := `
-		INSERT INTO agent_benchmarks (agent_id, attack_mode, hash_type, speed)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO agent_benchmarks (agent_id, attack_mode, hash_type, speed, measured_speed, device_speeds)
+		VALUES ($1, $2, $3, $4, $4, $5)
 		ON CONFLICT (agent_id, attack_mode, hash_type)
-		DO UPDATE SET speed = $4, updated_at = CURRENT_TIMESTAMP
+		DO UPDATE SET speed = $4, measured_speed = $4, device_speeds = $5, updated_at = CURRENT_TIMESTAMP
 		RETURNING id, created_at, updated_at`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err = r.db.QueryRowContext(ctx, query,
 		benchmark.AgentID,
 		benchmark.AttackMode,
 		benchmark.HashType,
 		benchmark.Speed,
+		deviceSpeedsJSON,
 	).Scan(&benchmark.ID, &benchmark.CreatedAt, &benchmark.UpdatedAt)
 
 	if err != nil {
@@ -108,6 +121,15 @@ func (r *BenchmarkRepository) GetAgentBenchmarks(ctx context.Context, agentID in
 	return benchmarks, nil
 }
 
+// DeleteAgentBenchmarks deletes all benchmarks of an agent and returns how many there were
+func (r *BenchmarkRepository) DeleteAgentBenchmarks(ctx context.Context, agentID int) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM agent_benchmarks WHERE agent_id = $1`, agentID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete agent benchmarks: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // IsRecentBenchmark checks if a benchmark is recent based on cache duration
 func (r *BenchmarkRepository) IsRecentBenchmark(ctx context.Context, agentID int, attackMode models.AttackMode, hashType int, cacheDuration time.Duration) (bool, error) {
 	query := `
@@ -401,3 +423,90 @@ func (r *BenchmarkRepository) DeleteOldMetrics(ctx context.Context, aggregationL
 
 	return nil
 }
+
+// GetAgentPerformance scores every agent's most recent completed tasks, at
+// most windowTasks per agent, by the ratio of their observed speed to the
+// benchmark speed they were expected to run at. Devices are scored by their
+// hash rate samples during those tasks against their benchmark device speeds.
+// Agents without scored tasks are left out.
+func (r *BenchmarkRepository) GetAgentPerformance(ctx context.Context, windowTasks int) (map[int]*models.AgentPerformance, error) {
+	agentQuery := `
+		WITH recent AS (
+			SELECT
+				agent_id,
+				observed_speed::float8 / expected_speed AS ratio,
+				ROW_NUMBER() OVER (PARTITION BY agent_id ORDER BY completed_at DESC) AS rn
+			FROM job_tasks
+			WHERE status = 'completed' AND agent_id IS NOT NULL
+			AND observed_speed > 0 AND expected_speed > 0
+		)
+		SELECT agent_id, AVG(ratio), COUNT(*)
+		FROM recent
+		WHERE rn <= $1
+		GROUP BY agent_id`
+
+	rows, err := r.db.QueryContext(ctx, agentQuery, windowTasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent performance: %w", err)
+	}
+	defer rows.Close()
+
+	performance := make(map[int]*models.AgentPerformance)
+	for rows.Next() {
+		var agentID int
+		p := &models.AgentPerformance{}
+		if err := rows.Scan(&agentID, &p.Score, &p.Tasks); err != nil {
+			return nil, fmt.Errorf("failed to scan agent performance: %w", err)
+		}
+		performance[agentID] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agent performance rows: %w", err)
+	}
+
+	deviceQuery := `
+		WITH recent AS (
+			SELECT
+				jt.id, jt.agent_id, je.attack_mode, je.hash_type,
+				ROW_NUMBER() OVER (PARTITION BY jt.agent_id ORDER BY jt.completed_at DESC) AS rn
+			FROM job_tasks jt
+			JOIN job_executions je ON je.id = jt.job_execution_id
+			WHERE jt.status = 'completed' AND jt.agent_id IS NOT NULL
+			AND jt.observed_speed > 0 AND jt.expected_speed > 0
+		)
+		SELECT
+			r.agent_id, m.device_id, COALESCE(MAX(m.device_name), ''),
+			AVG(m.value::float8 / (ab.device_speeds ->> m.device_id::text)::float8),
+			COUNT(DISTINCT r.id)
+		FROM recent r
+		JOIN agent_benchmarks ab
+			ON ab.agent_id = r.agent_id AND ab.attack_mode = r.attack_mode AND ab.hash_type = r.hash_type
+		JOIN agent_performance_metrics m
+			ON m.task_id = r.id AND m.metric_type = 'hash_rate' AND m.device_id IS NOT NULL AND m.value > 0
+		WHERE r.rn <= $1
+		AND (ab.device_speeds ->> m.device_id::text)::float8 > 0
+		GROUP BY r.agent_id, m.device_id
+		ORDER BY r.agent_id, m.device_id`
+
+	deviceRows, err := r.db.QueryContext(ctx, deviceQuery, windowTasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device performance: %w", err)
+	}
+	defer deviceRows.Close()
+
+	for deviceRows.Next() {
+		var agentID int
+		var d models.DevicePerformance
+		if err := deviceRows.Scan(&agentID, &d.DeviceID, &d.DeviceName, &d.Score, &d.Tasks); err != nil {
+			return nil, fmt.Errorf("failed to scan device performance: %w", err)
+		}
+		if p, ok := performance[agentID]; ok {
+			p.Devices = append(p.Devices, d)
+		}
+	}
+	if err := deviceRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating device performance rows: %w", err)
+	}
+
+	return performance, nil
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// binaryRolloutSpeedLookback bounds how far back tasks run with the previous
+// version are compared with the canaries' speed
+const binaryRolloutSpeedLookback = 30 * 24 * time.Hour
+
+const binaryRolloutSelect = `
+	SELECT r.id, r.binary_version_id, r.previous_version_id, r.canary_label, r.observation_hours,
+		r.min_tasks, r.max_failure_rate_increase, r.max_speed_regression, r.status, r.metrics,
+		COALESCE(r.decision_reason, ''), r.decided_automatically, r.created_by, r.started_at, r.decided_at,
+		COALESCE(bv.file_name, ''), COALESCE(pv.file_name, ''),
+		(SELECT COUNT(*) FROM agents a WHERE r.canary_label = ANY(a.labels))
+	FROM binary_rollouts r
+	LEFT JOIN binary_versions bv ON bv.id = r.binary_version_id
+	LEFT JOIN binary_versions pv ON pv.id = r.previous_version_id`
+
+// BinaryRolloutRepository stores staged rollouts of binary versions and
+// gathers the task metrics they are decided on
+type BinaryRolloutRepository struct {
+	db *db.DB
+}
+
+// NewBinaryRolloutRepository creates a new binary rollout repository
+func NewBinaryRolloutRepository(database *db.DB) *BinaryRolloutRepository {
+	return &BinaryRolloutRepository{db: database}
+}
+
+// Create starts a rollout. ErrDuplicateRecord is returned while another
+// rollout is still in canary status.
+func (r *BinaryRolloutRepository) Create(ctx context.Context, rollout *models.BinaryRollout) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO binary_rollouts (binary_version_id, previous_version_id, canary_label, observation_hours,
+			min_tasks, max_failure_rate_increase, max_speed_regression, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, status, started_at`,
+		rollout.BinaryVersionID, rollout.PreviousVersionID, rollout.CanaryLabel, rollout.ObservationHours,
+		rollout.MinTasks, rollout.MaxFailureRateIncrease, rollout.MaxSpeedRegression, rollout.CreatedBy,
+	).Scan(&rollout.ID, &rollout.Status, &rollout.StartedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return fmt.Errorf("another binary rollout is in progress: %w", ErrDuplicateRecord)
+		}
+		return fmt.Errorf("failed to create binary rollout: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a rollout, or ErrNotFound
+func (r *BinaryRolloutRepository) GetByID(ctx context.Context, id int) (*models.BinaryRollout, error) {
+	rollout, err := scanBinaryRollout(r.db.QueryRowContext(ctx, binaryRolloutSelect+` WHERE r.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get binary rollout: %w", err)
+	}
+	return rollout, nil
+}
+
+// GetActive returns the rollout in canary status, or ErrNotFound
+func (r *BinaryRolloutRepository) GetActive(ctx context.Context) (*models.BinaryRollout, error) {
+	rollout, err := scanBinaryRollout(r.db.QueryRowContext(ctx, binaryRolloutSelect+` WHERE r.status = $1`, models.BinaryRolloutCanary))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active binary rollout: %w", err)
+	}
+	return rollout, nil
+}
+
+// List returns all rollouts, newest first
+func (r *BinaryRolloutRepository) List(ctx context.Context) ([]models.BinaryRollout, error) {
+	rows, err := r.db.QueryContext(ctx, binaryRolloutSelect+` ORDER BY r.started_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list binary rollouts: %w", err)
+	}
+	defer rows.Close()
+
+	rollouts := []models.BinaryRollout{}
+	for rows.Next() {
+		rollout, err := scanBinaryRollout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan binary rollout: %w", err)
+		}
+		rollouts = append(rollouts, *rollout)
+	}
+	return rollouts, rows.Err()
+}
+
+// UpdateMetrics stores the latest evaluation of a rollout still in canary
+// status, with the reason it could not be decided yet if any
+func (r *BinaryRolloutRepository) UpdateMetrics(ctx context.Context, id int, metrics *models.BinaryRolloutMetrics, reason string) error {
+	raw, err := json.Marshal(metrics)
+	if err != nil {
+		return fmt.Errorf("failed to encode binary rollout metrics: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE binary_rollouts SET metrics = $2, decision_reason = NULLIF($3, '')
+		WHERE id = $1 AND status = $4`,
+		id, raw, reason, models.BinaryRolloutCanary)
+	if err != nil {
+		return fmt.Errorf("failed to update binary rollout metrics: %w", err)
+	}
+	return nil
+}
+
+// Decide ends the canary phase of a rollout. ErrNotFound is returned when the
+// rollout does not exist or was already decided.
+func (r *BinaryRolloutRepository) Decide(ctx context.Context, id int, status, reason string, automatic bool, metrics *models.BinaryRolloutMetrics) error {
+	raw, err := json.Marshal(metrics)
+	if err != nil {
+		return fmt.Errorf("failed to encode binary rollout metrics: %w", err)
+	}
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE binary_rollouts
+		SET status = $2, decision_reason = $3, decided_automatically = $4, metrics = $5, decided_at = NOW()
+		WHERE id = $1 AND status = $6`,
+		id, status, reason, automatic, raw, models.BinaryRolloutCanary)
+	if err != nil {
+		return fmt.Errorf("failed to decide binary rollout: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetMetrics compares the tasks run with the rollout's new version since it
+// started against those run with the previous version. Failure rates count
+// finished tasks across the fleet. Speed is compared per agent, attack mode
+// and hash type on the canary agents, against their earlier tasks with the
+// previous version.
+func (r *BinaryRolloutRepository) GetMetrics(ctx context.Context, rollout *models.BinaryRollout) (*models.BinaryRolloutMetrics, error) {
+	metrics := &models.BinaryRolloutMetrics{EvaluatedAt: time.Now()}
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE v.version_id = $2),
+			COUNT(*) FILTER (WHERE v.version_id = $2 AND jt.status = 'failed'),
+			COUNT(*) FILTER (WHERE v.version_id = $3),
+			COUNT(*) FILTER (WHERE v.version_id = $3 AND jt.status = 'failed')
+		FROM job_tasks jt
+		JOIN job_executions je ON je.id = jt.job_execution_id
+		CROSS JOIN LATERAL (SELECT COALESCE(jt.binary_version_id, je.binary_version_id) AS version_id) v
+		WHERE jt.status IN ('completed', 'failed')
+			AND jt.completed_at >= $1
+			AND v.version_id IN ($2, $3)`,
+		rollout.StartedAt, rollout.BinaryVersionID, rollout.PreviousVersionID,
+	).Scan(&metrics.CanaryTasks, &metrics.CanaryFailures, &metrics.BaselineTasks, &metrics.BaselineFailures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count binary rollout tasks: %w", err)
+	}
+
+	var speedRatio sql.NullFloat64
+	err = r.db.QueryRowContext(ctx, `
+		WITH speeds AS (
+			SELECT
+				AVG(jt.observed_speed) FILTER (WHERE v.version_id = $2 AND jt.completed_at >= $4) AS new_speed,
+				AVG(jt.observed_speed) FILTER (WHERE v.version_id = $3) AS previous_speed
+			FROM job_tasks jt
+			JOIN job_executions je ON je.id = jt.job_execution_id
+			JOIN hashlists h ON h.id = je.hashlist_id
+			JOIN agents a ON a.id = jt.agent_id
+			CROSS JOIN LATERAL (SELECT COALESCE(jt.binary_version_id, je.binary_version_id) AS version_id) v
+			WHERE $1 = ANY(a.labels)
+				AND jt.status = 'completed'
+				AND jt.observed_speed > 0
+				AND jt.completed_at >= $5
+				AND v.version_id IN ($2, $3)
+			GROUP BY jt.agent_id, je.attack_mode, h.hash_type_id
+		)
+		SELECT COUNT(*), AVG(new_speed / previous_speed)
+		FROM speeds
+		WHERE new_speed IS NOT NULL AND previous_speed IS NOT NULL`,
+		rollout.CanaryLabel, rollout.BinaryVersionID, rollout.PreviousVersionID,
+		rollout.StartedAt, rollout.StartedAt.Add(-binaryRolloutSpeedLookback),
+	).Scan(&metrics.SpeedProfiles, &speedRatio)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare binary rollout speeds: %w", err)
+	}
+	metrics.SpeedRatio = speedRatio.Float64
+
+	return metrics, nil
+}
+
+func scanBinaryRollout(row interface{ Scan(...interface{}) error }) (*models.BinaryRollout, error) {
+	var rollout models.BinaryRollout
+	var metrics []byte
+	err := row.Scan(&rollout.ID, &rollout.BinaryVersionID, &rollout.PreviousVersionID, &rollout.CanaryLabel,
+		&rollout.ObservationHours, &rollout.MinTasks, &rollout.MaxFailureRateIncrease, &rollout.MaxSpeedRegression,
+		&rollout.Status, &metrics, &rollout.DecisionReason, &rollout.DecidedAutomatically, &rollout.CreatedBy,
+		&rollout.StartedAt, &rollout.DecidedAt, &rollout.BinaryVersionName, &rollout.PreviousVersionName,
+		&rollout.CanaryAgents)
+	if err != nil {
+		return nil, err
+	}
+	if metrics != nil {
+		rollout.Metrics = &models.BinaryRolloutMetrics{}
+		if err := json.Unmarshal(metrics, rollout.Metrics); err != nil {
+			return nil, fmt.Errorf("failed to decode binary rollout metrics: %w", err)
+		}
+	}
+	return &rollout, nil
+}
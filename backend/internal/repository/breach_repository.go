@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BreachRepository stores breach corpus check results for hashes and hashlists
+type BreachRepository struct {
+	db *db.DB
+}
+
+// NewBreachRepository creates a new breach repository
+func NewBreachRepository(database *db.DB) *BreachRepository {
+	return &BreachRepository{db: database}
+}
+
+// StartCheck records a new running check for the hashlist, replacing the previous
+// one. It returns false without changes when a check is already running.
+func (r *BreachRepository) StartCheck(ctx context.Context, hashlistID int64, source string) (bool, error) {
+	query := `
+		INSERT INTO hashlist_breach_checks (hashlist_id, status, source, started_at)
+		VALUES ($1, 'running', $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (hashlist_id) DO UPDATE SET
+			status = 'running', source = EXCLUDED.source, checked_count = 0, breached_count = 0,
+			started_at = CURRENT_TIMESTAMP, completed_at = NULL, error_message = NULL
+		WHERE hashlist_breach_checks.status <> 'running'`
+
+	result, err := r.db.ExecContext(ctx, query, hashlistID, source)
+	if err != nil {
+		return false, fmt.Errorf("failed to start breach check for hashlist %d: %w", hashlistID, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// CompleteCheck records the result of a finished check
+func (r *BreachRepository) CompleteCheck(ctx context.Context, hashlistID int64, checked, breached int) error {
+	query := `
+		UPDATE hashlist_breach_checks
+		SET status = 'completed', checked_count = $2, breached_count = $3, completed_at = CURRENT_TIMESTAMP
+		WHERE hashlist_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, hashlistID, checked, breached); err != nil {
+		return fmt.Errorf("failed to complete breach check for hashlist %d: %w", hashlistID, err)
+	}
+	return nil
+}
+
+// FailCheck records why a check failed
+func (r *BreachRepository) FailCheck(ctx context.Context, hashlistID int64, message string) error {
+	query := `
+		UPDATE hashlist_breach_checks
+		SET status = 'failed', error_message = $2, completed_at = CURRENT_TIMESTAMP
+		WHERE hashlist_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, hashlistID, message); err != nil {
+		return fmt.Errorf("failed to record breach check failure for hashlist %d: %w", hashlistID, err)
+	}
+	return nil
+}
+
+// GetCheck returns the latest check of a hashlist
+func (r *BreachRepository) GetCheck(ctx context.Context, hashlistID int64) (*models.HashlistBreachCheck, error) {
+	query := `
+		SELECT hashlist_id, status, source, checked_count, breached_count, started_at, completed_at, error_message
+		FROM hashlist_breach_checks
+		WHERE hashlist_id = $1`
+
+	var check models.HashlistBreachCheck
+	err := r.db.QueryRowContext(ctx, query, hashlistID).Scan(
+		&check.HashlistID, &check.Status, &check.Source, &check.CheckedCount, &check.BreachedCount,
+		&check.StartedAt, &check.CompletedAt, &check.ErrorMessage,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get breach check for hashlist %d: %w", hashlistID, err)
+	}
+	return &check, nil
+}
+
+// GetCrackedPasswords returns the cracked hashes of a hashlist with their passwords
+func (r *BreachRepository) GetCrackedPasswords(ctx context.Context, hashlistID int64) ([]models.CrackedPassword, error) {
+	query := `
+		SELECT h.id, h.password
+		FROM hashes h
+		JOIN hashlist_hashes hh ON h.id = hh.hash_id
+		WHERE hh.hashlist_id = $1 AND h.is_cracked = true AND h.password IS NOT NULL
+		ORDER BY h.password`
+
+	rows, err := r.db.QueryContext(ctx, query, hashlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cracked passwords for hashlist %d: %w", hashlistID, err)
+	}
+	defer rows.Close()
+
+	var passwords []models.CrackedPassword
+	for rows.Next() {
+		var p models.CrackedPassword
+		if err := rows.Scan(&p.HashID, &p.Password); err != nil {
+			return nil, fmt.Errorf("failed to scan cracked password: %w", err)
+		}
+		passwords = append(passwords, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cracked passwords: %w", err)
+	}
+	return passwords, nil
+}
+
+// SetBreachCount stores how often the password of the given hashes appears in the breach corpus
+func (r *BreachRepository) SetBreachCount(ctx context.Context, hashIDs []uuid.UUID, count int) error {
+	if len(hashIDs) == 0 {
+		return nil
+	}
+	ids := make([]string, len(hashIDs))
+	for i, id := range hashIDs {
+		ids[i] = id.String()
+	}
+
+	query := `UPDATE hashes SET breach_count = $1 WHERE id = ANY($2::uuid[])`
+	if _, err := r.db.ExecContext(ctx, query, count, pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to update breach count: %w", err)
+	}
+	return nil
+}
+
+// ListBreachedHashes returns the hashes of a hashlist whose password is in the
+// breach corpus, most frequently breached first, and their total number
+func (r *BreachRepository) ListBreachedHashes(ctx context.Context, hashlistID int64, limit, offset int) ([]models.BreachedHash, int, error) {
+	var total int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM hashes h
+		JOIN hashlist_hashes hh ON h.id = hh.hash_id
+		WHERE hh.hashlist_id = $1 AND h.breach_count > 0`
+	if err := r.db.QueryRowContext(ctx, countQuery, hashlistID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count breached hashes for hashlist %d: %w", hashlistID, err)
+	}
+
+	query := `
+		SELECT h.id, h.username, h.domain, h.breach_count
+		FROM hashes h
+		JOIN hashlist_hashes hh ON h.id = hh.hash_id
+		WHERE hh.hashlist_id = $1 AND h.breach_count > 0
+		ORDER BY h.breach_count DESC, h.username
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, hashlistID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query breached hashes for hashlist %d: %w", hashlistID, err)
+	}
+	defer rows.Close()
+
+	hashes := []models.BreachedHash{}
+	for rows.Next() {
+		var h models.BreachedHash
+		if err := rows.Scan(&h.HashID, &h.Username, &h.Domain, &h.BreachCount); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan breached hash: %w", err)
+		}
+		hashes = append(hashes, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating breached hashes: %w", err)
+	}
+	return hashes, total, nil
+}
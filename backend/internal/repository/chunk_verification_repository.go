@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+const chunkVerificationColumns = `
+	id, task_id, job_execution_id, agent_id, agent_name, status,
+	verifier_task_id, verifier_agent_id, original_crack_count, verifier_crack_count,
+	agent_quarantined, created_at, completed_at`
+
+// ChunkVerificationCandidate is a pending verification with the completed
+// task it re-runs
+type ChunkVerificationCandidate struct {
+	VerificationID int64
+	Task           models.JobTask
+}
+
+// ChunkVerificationRepository stores the completed chunks sampled for
+// verification on another agent
+type ChunkVerificationRepository struct {
+	db *db.DB
+}
+
+// NewChunkVerificationRepository creates a new chunk verification repository
+func NewChunkVerificationRepository(database *db.DB) *ChunkVerificationRepository {
+	return &ChunkVerificationRepository{db: database}
+}
+
+// Create samples a completed task for verification. A task is only sampled
+// once; it returns false if it already was.
+func (r *ChunkVerificationRepository) Create(ctx context.Context, task *models.JobTask) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO chunk_verifications (task_id, job_execution_id, agent_id, agent_name)
+		VALUES ($1, $2, $3, COALESCE((SELECT name FROM agents WHERE id = $3), ''))
+		ON CONFLICT (task_id) DO NOTHING`,
+		task.ID, task.JobExecutionID, task.AgentID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create chunk verification: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// SkipFinishedJobs skips the pending verifications of jobs that are no longer
+// running, since their files may already have been cleaned up
+func (r *ChunkVerificationRepository) SkipFinishedJobs(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE chunk_verifications cv
+		SET status = 'skipped', completed_at = NOW()
+		FROM job_executions je
+		WHERE cv.job_execution_id = je.id
+			AND cv.status = 'pending'
+			AND (je.status <> 'running' OR je.deleted_at IS NOT NULL)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to skip chunk verifications of finished jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// GetPendingCandidates returns the pending verifications of running jobs with
+// the tasks they re-run, highest priority jobs and oldest samples first
+func (r *ChunkVerificationRepository) GetPendingCandidates(ctx context.Context) ([]ChunkVerificationCandidate, error) {
+	query := `
+		SELECT
+			cv.id,
+			jt.id, jt.job_execution_id, jt.agent_id, jt.status, COALESCE(jt.priority, 0), COALESCE(jt.attack_cmd, ''),
+			jt.keyspace_start, jt.keyspace_end,
+			jt.effective_keyspace_start, jt.effective_keyspace_end,
+			jt.benchmark_speed, jt.chunk_duration,
+			jt.rule_start_index, jt.rule_end_index, jt.rule_chunk_path, jt.is_rule_split_task,
+			COALESCE(jt.chunk_number, 0), COALESCE(jt.is_actual_keyspace, false), COALESCE(jt.crack_count, 0)
+		FROM chunk_verifications cv
+		JOIN job_tasks jt ON cv.task_id = jt.id
+		JOIN job_executions je ON cv.job_execution_id = je.id
+		WHERE cv.status = 'pending'
+			AND je.status = 'running'
+			AND je.deleted_at IS NULL
+		ORDER BY je.priority DESC, cv.created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk verification candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []ChunkVerificationCandidate
+	for rows.Next() {
+		var c ChunkVerificationCandidate
+		task := &c.Task
+		err := rows.Scan(
+			&c.VerificationID,
+			&task.ID, &task.JobExecutionID, &task.AgentID, &task.Status, &task.Priority, &task.AttackCmd,
+			&task.KeyspaceStart, &task.KeyspaceEnd,
+			&task.EffectiveKeyspaceStart, &task.EffectiveKeyspaceEnd,
+			&task.BenchmarkSpeed, &task.ChunkDuration,
+			&task.RuleStartIndex, &task.RuleEndIndex, &task.RuleChunkPath, &task.IsRuleSplitTask,
+			&task.ChunkNumber, &task.IsActualKeyspace, &task.CrackCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chunk verification candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
+}
+
+// Claim marks a pending verification as running on the verifier agent. It
+// reports false if another scheduler claimed it first.
+func (r *ChunkVerificationRepository) Claim(ctx context.Context, id int64, verifierAgentID int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE chunk_verifications SET status = 'running', verifier_agent_id = $2
+		WHERE id = $1 AND status = 'pending'`,
+		id, verifierAgentID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim chunk verification: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// SetVerifierTask records the task re-running a claimed verification
+func (r *ChunkVerificationRepository) SetVerifierTask(ctx context.Context, id int64, taskID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE chunk_verifications SET verifier_task_id = $2 WHERE id = $1`, id, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to set verifier task: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetByVerifierTask returns the verification a task re-runs, or ErrNotFound
+func (r *ChunkVerificationRepository) GetByVerifierTask(ctx context.Context, taskID uuid.UUID) (*models.ChunkVerification, error) {
+	query := `SELECT` + chunkVerificationColumns + ` FROM chunk_verifications WHERE verifier_task_id = $1`
+
+	verification, err := scanChunkVerification(r.db.QueryRowContext(ctx, query, taskID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk verification: %w", err)
+	}
+	return verification, nil
+}
+
+// CrackCounts returns how many new hashes the original and the verifier task
+// of a verification cracked
+func (r *ChunkVerificationRepository) CrackCounts(ctx context.Context, verification *models.ChunkVerification) (int, int, error) {
+	var original, verifier int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(MAX(crack_count) FILTER (WHERE id = $1), 0),
+			COALESCE(MAX(crack_count) FILTER (WHERE id = $2), 0)
+		FROM job_tasks
+		WHERE id IN ($1, $2)`,
+		verification.TaskID, verification.VerifierTaskID,
+	).Scan(&original, &verifier)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get crack counts of chunk verification: %w", err)
+	}
+	return original, verifier, nil
+}
+
+// Complete records the result of a running verification. It returns
+// ErrNotFound if the verification is not running, so a result is only
+// recorded once.
+func (r *ChunkVerificationRepository) Complete(ctx context.Context, q Querier, id int64, originalCracks, verifierCracks int) (*models.ChunkVerification, error) {
+	query := `
+		UPDATE chunk_verifications
+		SET status = $2, original_crack_count = $3, verifier_crack_count = $4, completed_at = NOW()
+		WHERE id = $1 AND status = 'running'
+		RETURNING` + chunkVerificationColumns
+
+	verification, err := scanChunkVerification(q.QueryRowContext(ctx, query,
+		id, models.ChunkVerificationResult(verifierCracks), originalCracks, verifierCracks))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete chunk verification: %w", err)
+	}
+	return verification, nil
+}
+
+// Skip gives up on a pending or running verification
+func (r *ChunkVerificationRepository) Skip(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE chunk_verifications SET status = 'skipped', completed_at = NOW()
+		WHERE id = $1 AND status IN ('pending', 'running')`, id)
+	if err != nil {
+		return fmt.Errorf("failed to skip chunk verification: %w", err)
+	}
+	return nil
+}
+
+// QuarantineAgent disables the agent of a mismatched verification so the
+// scheduler stops giving it work, and records that on the verification
+func (r *ChunkVerificationRepository) QuarantineAgent(ctx context.Context, q Querier, verification *models.ChunkVerification) error {
+	if verification.AgentID == nil {
+		return nil
+	}
+	if _, err := q.ExecContext(ctx,
+		`UPDATE agents SET is_enabled = false, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		*verification.AgentID,
+	); err != nil {
+		return fmt.Errorf("failed to quarantine agent %d: %w", *verification.AgentID, err)
+	}
+	if _, err := q.ExecContext(ctx,
+		`UPDATE chunk_verifications SET agent_quarantined = true WHERE id = $1`, verification.ID,
+	); err != nil {
+		return fmt.Errorf("failed to record agent quarantine: %w", err)
+	}
+	verification.AgentQuarantined = true
+	return nil
+}
+
+// List returns the most recent verifications, newest first, optionally only
+// those with the given status
+func (r *ChunkVerificationRepository) List(ctx context.Context, status string, limit int) ([]models.ChunkVerification, error) {
+	query := `SELECT` + chunkVerificationColumns + ` FROM chunk_verifications
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunk verifications: %w", err)
+	}
+	defer rows.Close()
+
+	verifications := []models.ChunkVerification{}
+	for rows.Next() {
+		verification, err := scanChunkVerification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chunk verification: %w", err)
+		}
+		verifications = append(verifications, *verification)
+	}
+	return verifications, rows.Err()
+}
+
+// scanChunkVerification scans a row selected with chunkVerificationColumns
+func scanChunkVerification(row rowScanner) (*models.ChunkVerification, error) {
+	var v models.ChunkVerification
+	err := row.Scan(
+		&v.ID, &v.TaskID, &v.JobExecutionID, &v.AgentID, &v.AgentName, &v.Status,
+		&v.VerifierTaskID, &v.VerifierAgentID, &v.OriginalCrackCount, &v.VerifierCrackCount,
+		&v.AgentQuarantined, &v.CreatedAt, &v.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
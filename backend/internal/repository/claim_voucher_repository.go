@@ -10,6 +10,7 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db/queries"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/lib/pq"
 )
 
 // ClaimVoucherRepository handles database operations for claim vouchers
@@ -24,6 +25,11 @@ func NewClaimVoucherRepository(db *db.DB) *ClaimVoucherRepository {
 
 // Create creates a new claim voucher
 func (r *ClaimVoucherRepository) Create(ctx context.Context, voucher *models.ClaimVoucher) error {
+	labels := voucher.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+
 	err := r.db.QueryRowContext(ctx, queries.CreateClaimVoucher,
 		voucher.Code,
 		voucher.IsActive,
@@ -31,6 +37,9 @@ func (r *ClaimVoucherRepository) Create(ctx context.Context, voucher *models.Cla
 		voucher.CreatedByID,
 		voucher.CreatedAt,
 		voucher.UpdatedAt,
+		voucher.MaxUses,
+		voucher.ExpiresAt,
+		pq.Array(labels),
 	).Scan(&voucher.Code)
 
 	if err != nil {
@@ -48,6 +57,9 @@ func (r *ClaimVoucherRepository) GetByCode(ctx context.Context, code string) (*m
 	var usedByAgent models.Agent
 	var usedByAgentID sql.NullInt64
 	var usedAt sql.NullTime
+	var maxUses sql.NullInt64
+	var expiresAt sql.NullTime
+	var labels pq.StringArray
 	var createdByUsername, createdByEmail, createdByRole sql.NullString
 	var agentID sql.NullInt64
 	var agentName, agentStatus sql.NullString
@@ -56,6 +68,10 @@ func (r *ClaimVoucherRepository) GetByCode(ctx context.Context, code string) (*m
 		&voucher.Code,
 		&voucher.IsActive,
 		&voucher.IsContinuous,
+		&maxUses,
+		&voucher.UseCount,
+		&expiresAt,
+		&labels,
 		&voucher.CreatedByID,
 		&usedByAgentID,
 		&usedAt,
@@ -72,7 +88,7 @@ func (r *ClaimVoucherRepository) GetByCode(ctx context.Context, code string) (*m
 
 	if err == sql.ErrNoRows {
 		debug.Debug("GetByCode: No voucher found with code: %q", code)
-		return nil, fmt.Errorf("claim voucher not found with code: %s", code)
+		return nil, fmt.Errorf("claim voucher not found with code: %s: %w", code, ErrNotFound)
 	} else if err != nil {
 		debug.Error("GetByCode: Failed to get voucher: %v", err)
 		return nil, fmt.Errorf("failed to get claim voucher: %w", err)
@@ -83,6 +99,14 @@ func (r *ClaimVoucherRepository) GetByCode(ctx context.Context, code string) (*m
 
 	voucher.UsedAt = usedAt
 	voucher.UsedByAgentID = usedByAgentID
+	voucher.Labels = []string(labels)
+	if maxUses.Valid {
+		n := int(maxUses.Int64)
+		voucher.MaxUses = &n
+	}
+	if expiresAt.Valid {
+		voucher.ExpiresAt = &expiresAt.Time
+	}
 
 	// Only set the created by user if we have valid data
 	if createdByUsername.Valid {
@@ -103,31 +127,6 @@ func (r *ClaimVoucherRepository) GetByCode(ctx context.Context, code string) (*m
 	return voucher, nil
 }
 
-// UseByAgent marks a claim voucher as used by an agent
-func (r *ClaimVoucherRepository) UseByAgent(ctx context.Context, code string, agentID int) error {
-	now := time.Now()
-	result, err := r.db.ExecContext(ctx, queries.UseClaimVoucherByAgent,
-		code,
-		agentID,
-		now,
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to use claim voucher: %w", err)
-	}
-
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rows == 0 {
-		return fmt.Errorf("claim voucher not found or already used: %s", code)
-	}
-
-	return nil
-}
-
 // Deactivate deactivates a claim voucher
 func (r *ClaimVoucherRepository) Deactivate(ctx context.Context, code string) error {
 	result, err := r.db.ExecContext(ctx, queries.DeactivateClaimVoucher, code)
@@ -162,6 +161,9 @@ func (r *ClaimVoucherRepository) ListActive(ctx context.Context) ([]models.Claim
 		var usedByAgent models.Agent
 		var usedByAgentID sql.NullInt64
 		var usedAt sql.NullTime
+		var maxUses sql.NullInt64
+		var expiresAt sql.NullTime
+		var labels pq.StringArray
 		var createdByUsername, createdByEmail, createdByRole sql.NullString
 		var agentID sql.NullInt64
 		var agentName, agentStatus sql.NullString
@@ -170,6 +172,10 @@ func (r *ClaimVoucherRepository) ListActive(ctx context.Context) ([]models.Claim
 			&voucher.Code,
 			&voucher.IsActive,
 			&voucher.IsContinuous,
+			&maxUses,
+			&voucher.UseCount,
+			&expiresAt,
+			&labels,
 			&voucher.CreatedByID,
 			&usedByAgentID,
 			&usedAt,
@@ -189,6 +195,14 @@ func (r *ClaimVoucherRepository) ListActive(ctx context.Context) ([]models.Claim
 
 		voucher.UsedAt = usedAt
 		voucher.UsedByAgentID = usedByAgentID
+		voucher.Labels = []string(labels)
+		if maxUses.Valid {
+			n := int(maxUses.Int64)
+			voucher.MaxUses = &n
+		}
+		if expiresAt.Valid {
+			voucher.ExpiresAt = &expiresAt.Time
+		}
 
 		// Only set the created by user if we have valid data
 		if createdByUsername.Valid {
@@ -215,3 +229,123 @@ func (r *ClaimVoucherRepository) ListActive(ctx context.Context) ([]models.Claim
 
 	return vouchers, nil
 }
+
+// Redeem counts a registration against a claim voucher and records it in the
+// voucher's usage history. It returns models.ErrClaimVoucherExhausted when the
+// voucher was used up, expired or deactivated in the meantime.
+func (r *ClaimVoucherRepository) Redeem(ctx context.Context, code string, agentID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, queries.RedeemClaimVoucher, code, agentID, now)
+	if err != nil {
+		return fmt.Errorf("failed to redeem claim voucher: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return models.ErrClaimVoucherExhausted
+	}
+
+	if _, err := tx.ExecContext(ctx, queries.CreateClaimVoucherUsage, code, agentID, now, true, nil); err != nil {
+		return fmt.Errorf("failed to record claim voucher usage: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RecordFailedAttempt records a rejected registration in the voucher's usage history
+func (r *ClaimVoucherRepository) RecordFailedAttempt(ctx context.Context, code string, reason string) error {
+	_, err := r.db.ExecContext(ctx, queries.CreateClaimVoucherUsage, code, nil, time.Now(), false, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record claim voucher usage: %w", err)
+	}
+	return nil
+}
+
+// RedeemedByAgent reports whether the agent was registered with the voucher
+func (r *ClaimVoucherRepository) RedeemedByAgent(ctx context.Context, code string, agentID int) (bool, error) {
+	var redeemed bool
+	if err := r.db.QueryRowContext(ctx, queries.ClaimVoucherRedeemedByAgent, code, agentID).Scan(&redeemed); err != nil {
+		return false, fmt.Errorf("failed to check claim voucher usage: %w", err)
+	}
+	return redeemed, nil
+}
+
+// ListUsage retrieves the usage history of a claim voucher, newest first
+func (r *ClaimVoucherRepository) ListUsage(ctx context.Context, code string) ([]models.ClaimVoucherUsage, error) {
+	rows, err := r.db.QueryContext(ctx, queries.ListClaimVoucherUsage, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list claim voucher usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []models.ClaimVoucherUsage{}
+	for rows.Next() {
+		var entry models.ClaimVoucherUsage
+		var attemptedByID models.NullUUID
+		var agentID sql.NullInt64
+		var ipAddress, userAgent, errorMessage sql.NullString
+		var agentName, agentStatus sql.NullString
+		var agentLabels pq.StringArray
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.VoucherCode,
+			&attemptedByID,
+			&agentID,
+			&entry.AttemptedAt,
+			&entry.Success,
+			&ipAddress,
+			&userAgent,
+			&errorMessage,
+			&agentName,
+			&agentStatus,
+			&agentLabels,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan claim voucher usage: %w", err)
+		}
+
+		if attemptedByID.Valid {
+			entry.AttemptedByID = &attemptedByID.UUID
+		}
+		entry.IPAddress = ipAddress.String
+		entry.UserAgent = userAgent.String
+		entry.ErrorMessage = errorMessage.String
+
+		// The agent may have been deleted since it registered
+		if agentID.Valid {
+			id := int(agentID.Int64)
+			entry.AgentID = &id
+			if agentName.Valid {
+				entry.Agent = &models.Agent{
+					ID:     id,
+					Name:   agentName.String,
+					Status: agentStatus.String,
+					Labels: []string(agentLabels),
+				}
+			}
+		}
+
+		usage = append(usage, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating claim voucher usage: %w", err)
+	}
+
+	return usage, nil
+}
@@ -28,9 +28,9 @@ func NewClientRepository(database *db.DB) *ClientRepository {
 
 // Create inserts a new client record into the database.
 func (r *ClientRepository) Create(ctx context.Context, client *models.Client) error {
-	client.CreatedAt = time.Now()                              // Ensure CreatedAt is set
-	client.UpdatedAt = time.Now()                              // Ensure UpdatedAt is set
-	_, err := r.db.ExecContext(ctx, queries.CreateClientQuery, // Use constant
+	client.CreatedAt = time.Now()                               // Ensure CreatedAt is set
+	client.UpdatedAt = time.Now()                               // Ensure UpdatedAt is set
+	err := r.db.QueryRowContext(ctx, queries.CreateClientQuery, // Use constant
 		client.ID,
 		client.Name,
 		client.Description,
@@ -39,7 +39,12 @@ func (r *ClientRepository) Create(ctx context.Context, client *models.Client) er
 		client.ExcludeFromPotfile,
 		client.CreatedAt,
 		client.UpdatedAt,
-	)
+		client.Contacts,
+		client.EngagementStatus,
+		client.EngagementStartDate,
+		client.EngagementEndDate,
+		client.ScopeNotes,
+	).Scan(&client.ClosedAt)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
 			return fmt.Errorf("client with name '%s' already exists: %w", client.Name, ErrDuplicateRecord)
@@ -49,9 +54,18 @@ func (r *ClientRepository) Create(ctx context.Context, client *models.Client) er
 	return nil
 }
 
-// GetByID retrieves a client by its ID.
+// GetByID retrieves a client by its ID. Clients in the trash are reported as not found.
 func (r *ClientRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Client, error) {
-	row := r.db.QueryRowContext(ctx, queries.GetClientByIDQuery, id) // Use constant
+	return r.getByID(ctx, queries.GetClientByIDQuery, id)
+}
+
+// GetByIDIncludingDeleted retrieves a client by its ID even if it is in the trash.
+func (r *ClientRepository) GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*models.Client, error) {
+	return r.getByID(ctx, queries.GetClientByIDIncludingDeletedQuery, id)
+}
+
+func (r *ClientRepository) getByID(ctx context.Context, query string, id uuid.UUID) (*models.Client, error) {
+	row := r.db.QueryRowContext(ctx, query, id)
 	var client models.Client
 	err := row.Scan(
 		&client.ID,
@@ -62,6 +76,12 @@ func (r *ClientRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.C
 		&client.ExcludeFromPotfile,
 		&client.CreatedAt,
 		&client.UpdatedAt,
+		&client.Contacts,
+		&client.EngagementStatus,
+		&client.EngagementStartDate,
+		&client.EngagementEndDate,
+		&client.ScopeNotes,
+		&client.ClosedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -92,6 +112,12 @@ func (r *ClientRepository) GetByName(ctx context.Context, name string) (*models.
 		&client.ExcludeFromPotfile,
 		&client.CreatedAt,
 		&client.UpdatedAt,
+		&client.Contacts,
+		&client.EngagementStatus,
+		&client.EngagementStartDate,
+		&client.EngagementEndDate,
+		&client.ScopeNotes,
+		&client.ClosedAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -122,6 +148,12 @@ func (r *ClientRepository) List(ctx context.Context) ([]models.Client, error) {
 			&client.ExcludeFromPotfile,
 			&client.CreatedAt,
 			&client.UpdatedAt,
+			&client.Contacts,
+			&client.EngagementStatus,
+			&client.EngagementStartDate,
+			&client.EngagementEndDate,
+			&client.ScopeNotes,
+			&client.ClosedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan client row: %w", err)
 		}
@@ -155,6 +187,12 @@ func (r *ClientRepository) ListWithCrackedCounts(ctx context.Context) ([]models.
 			&client.ExcludeFromPotfile,
 			&client.CreatedAt,
 			&client.UpdatedAt,
+			&client.Contacts,
+			&client.EngagementStatus,
+			&client.EngagementStartDate,
+			&client.EngagementEndDate,
+			&client.ScopeNotes,
+			&client.ClosedAt,
 			&crackedCount,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan client row with cracked count: %w", err)
@@ -199,6 +237,12 @@ func (r *ClientRepository) Search(ctx context.Context, query string) ([]models.C
 			&client.ExcludeFromPotfile,
 			&client.CreatedAt,
 			&client.UpdatedAt,
+			&client.Contacts,
+			&client.EngagementStatus,
+			&client.EngagementStartDate,
+			&client.EngagementEndDate,
+			&client.ScopeNotes,
+			&client.ClosedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan client search result row: %w", err)
 		}
@@ -222,6 +266,11 @@ func (r *ClientRepository) Update(ctx context.Context, client *models.Client) er
 		client.ExcludeFromPotfile,
 		client.UpdatedAt,
 		client.ID,
+		client.Contacts,
+		client.EngagementStatus,
+		client.EngagementStartDate,
+		client.EngagementEndDate,
+		client.ScopeNotes,
 	)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
@@ -257,6 +306,71 @@ func (r *ClientRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// SoftDelete moves a client to the trash. Its hashlists keep their client association.
+func (r *ClientRepository) SoftDelete(ctx context.Context, id uuid.UUID, deletedBy *uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, queries.SoftDeleteClientQuery, id, deletedBy)
+	if err != nil {
+		return fmt.Errorf("failed to move client %s to trash: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		debug.Warning("Could not get rows affected after moving client %s to trash: %v", id, err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("client with ID %s not found for deletion: %w", id, ErrNotFound)
+	}
+
+	return nil
+}
+
+// Restore takes a client out of the trash.
+func (r *ClientRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, queries.RestoreClientQuery, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore client %s: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		debug.Warning("Could not get rows affected after restoring client %s: %v", id, err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("client with ID %s not found in trash: %w", id, ErrNotFound)
+	}
+
+	return nil
+}
+
+// SetEngagementStatus opens or closes a client's engagement
+func (r *ClientRepository) SetEngagementStatus(ctx context.Context, id uuid.UUID, status string) error {
+	result, err := r.db.ExecContext(ctx, queries.SetClientEngagementStatusQuery, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to set engagement status of client %s: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		debug.Warning("Could not get rows affected after setting engagement status of client %s: %v", id, err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("client with ID %s not found: %w", id, ErrNotFound)
+	}
+
+	return nil
+}
+
+// IsEngagementClosed checks if a client's engagement is closed
+func (r *ClientRepository) IsEngagementClosed(ctx context.Context, clientID uuid.UUID) (bool, error) {
+	query := `SELECT engagement_status = 'closed' FROM clients WHERE id = $1`
+	var closed bool
+	err := r.db.QueryRowContext(ctx, query, clientID).Scan(&closed)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("client with ID %s not found: %w", clientID, ErrNotFound)
+		}
+		return false, fmt.Errorf("failed to check engagement status of client %s: %w", clientID, err)
+	}
+	return closed, nil
+}
+
 // IsExcludedFromPotfile checks if a client has potfile exclusion enabled
 func (r *ClientRepository) IsExcludedFromPotfile(ctx context.Context, clientID uuid.UUID) (bool, error) {
 	query := `SELECT exclude_from_potfile FROM clients WHERE id = $1`
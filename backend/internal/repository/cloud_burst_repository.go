@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// CloudBurstRepository tracks the temporary cloud agents launched for jobs
+// that allow cloud burst, and the queue and agent state deciding when to
+// launch and tear them down
+type CloudBurstRepository struct {
+	db *db.DB
+}
+
+// NewCloudBurstRepository creates a new cloud burst repository
+func NewCloudBurstRepository(database *db.DB) *CloudBurstRepository {
+	return &CloudBurstRepository{db: database}
+}
+
+const cloudBurstInstanceColumns = `
+	id, name, provider_instance_id, claim_code, agent_id, status, hourly_cost,
+	error_message, launched_at, registered_at, last_busy_at, terminated_at`
+
+// Create records a new instance before it is launched
+func (r *CloudBurstRepository) Create(ctx context.Context, instance *models.CloudBurstInstance) error {
+	query := `
+		INSERT INTO cloud_burst_instances (name, claim_code, status, hourly_cost)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, launched_at`
+
+	err := r.db.QueryRowContext(ctx, query, instance.Name, instance.ClaimCode, instance.Status, instance.HourlyCost).
+		Scan(&instance.ID, &instance.LaunchedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create cloud burst instance: %w", err)
+	}
+	return nil
+}
+
+// SetProviderInstanceID records the ID the provider gave a launched instance
+func (r *CloudBurstRepository) SetProviderInstanceID(ctx context.Context, id int64, providerInstanceID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE cloud_burst_instances SET provider_instance_id = $1 WHERE id = $2`,
+		providerInstanceID, id)
+	if err != nil {
+		return fmt.Errorf("failed to set cloud burst provider instance ID: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an instance
+func (r *CloudBurstRepository) GetByID(ctx context.Context, id int64) (*models.CloudBurstInstance, error) {
+	instances, err := r.list(ctx, `SELECT `+cloudBurstInstanceColumns+` FROM cloud_burst_instances WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) == 0 {
+		return nil, ErrNotFound
+	}
+	return &instances[0], nil
+}
+
+// ListActive returns the instances that are provisioning or running, oldest first
+func (r *CloudBurstRepository) ListActive(ctx context.Context) ([]models.CloudBurstInstance, error) {
+	return r.list(ctx, `
+		SELECT `+cloudBurstInstanceColumns+`
+		FROM cloud_burst_instances
+		WHERE status IN ('provisioning', 'running')
+		ORDER BY launched_at ASC`)
+}
+
+// ListRecent returns the most recently launched instances
+func (r *CloudBurstRepository) ListRecent(ctx context.Context, limit int) ([]models.CloudBurstInstance, error) {
+	return r.list(ctx, `
+		SELECT `+cloudBurstInstanceColumns+`
+		FROM cloud_burst_instances
+		ORDER BY launched_at DESC, id DESC
+		LIMIT $1`, limit)
+}
+
+func (r *CloudBurstRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.CloudBurstInstance, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cloud burst instances: %w", err)
+	}
+	defer rows.Close()
+
+	instances := []models.CloudBurstInstance{}
+	for rows.Next() {
+		var i models.CloudBurstInstance
+		if err := rows.Scan(
+			&i.ID, &i.Name, &i.ProviderInstanceID, &i.ClaimCode, &i.AgentID, &i.Status, &i.HourlyCost,
+			&i.ErrorMessage, &i.LaunchedAt, &i.RegisteredAt, &i.LastBusyAt, &i.TerminatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan cloud burst instance: %w", err)
+		}
+		instances = append(instances, i)
+	}
+	return instances, rows.Err()
+}
+
+// MarkRegistered moves provisioning instances whose claim voucher registered
+// an agent to running and returns how many registered
+func (r *CloudBurstRepository) MarkRegistered(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE cloud_burst_instances i SET
+			status = 'running',
+			agent_id = cv.used_by_agent_id,
+			registered_at = cv.used_at,
+			last_busy_at = cv.used_at
+		FROM claim_vouchers cv
+		WHERE cv.code = REPLACE(i.claim_code, '-', '')
+		AND cv.used_by_agent_id IS NOT NULL
+		AND i.status = 'provisioning'`
+
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark cloud burst instances registered: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// TouchBusy records that the instance's agent had a task
+func (r *CloudBurstRepository) TouchBusy(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE cloud_burst_instances SET last_busy_at = CURRENT_TIMESTAMP WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to update cloud burst instance activity: %w", err)
+	}
+	return nil
+}
+
+// MarkEnded moves an active instance to terminated or failed. Returns
+// ErrNotFound if the instance already ended.
+func (r *CloudBurstRepository) MarkEnded(ctx context.Context, id int64, status models.CloudBurstInstanceStatus, errorMessage *string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE cloud_burst_instances
+		SET status = $1, error_message = COALESCE($2, error_message), terminated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND status IN ('provisioning', 'running')`,
+		status, errorMessage, id)
+	if err != nil {
+		return fmt.Errorf("failed to end cloud burst instance: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordError stores the last error of an instance without ending it, e.g.
+// a failed terminate that is retried
+func (r *CloudBurstRepository) RecordError(ctx context.Context, id int64, message string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE cloud_burst_instances SET error_message = $1 WHERE id = $2`, message, id)
+	if err != nil {
+		return fmt.Errorf("failed to record cloud burst instance error: %w", err)
+	}
+	return nil
+}
+
+// GetMonthSpend returns what all instances cost in the current calendar
+// month, counting running instances up to now
+func (r *CloudBurstRepository) GetMonthSpend(ctx context.Context) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(
+			hourly_cost * EXTRACT(EPOCH FROM (
+				COALESCE(terminated_at, CURRENT_TIMESTAMP) - GREATEST(launched_at, date_trunc('month', CURRENT_TIMESTAMP))
+			)) / 3600
+		), 0)
+		FROM cloud_burst_instances
+		WHERE COALESCE(terminated_at, CURRENT_TIMESTAMP) > date_trunc('month', CURRENT_TIMESTAMP)`
+
+	var spend float64
+	if err := r.db.QueryRowContext(ctx, query).Scan(&spend); err != nil {
+		return 0, fmt.Errorf("failed to get cloud burst spend: %w", err)
+	}
+	return spend, nil
+}
+
+// CountBurstableJobsWithWork counts the pending or running jobs that allow
+// cloud burst and still have work to hand out
+func (r *CloudBurstRepository) CountBurstableJobsWithWork(ctx context.Context) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM job_executions je
+		WHERE je.allow_cloud_burst = true
+		AND je.status IN ('pending', 'running')
+		AND (
+			NOT EXISTS (SELECT 1 FROM job_tasks jt WHERE jt.job_execution_id = je.id)
+			OR EXISTS (
+				SELECT 1 FROM job_tasks jt
+				WHERE jt.job_execution_id = je.id
+				AND (jt.status = 'pending' OR (jt.status = 'failed' AND jt.retry_count < 3))
+			)
+			OR (je.uses_rule_splitting = true AND je.dispatched_keyspace < je.effective_keyspace)
+			OR (je.uses_rule_splitting = false AND je.total_keyspace IS NOT NULL AND je.dispatched_keyspace < je.total_keyspace)
+		)`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count jobs with work for cloud burst: %w", err)
+	}
+	return count, nil
+}
+
+// CountIdleAgents counts the online, enabled agents without an active task
+func (r *CloudBurstRepository) CountIdleAgents(ctx context.Context) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM agents a
+		WHERE a.status = $1 AND a.is_enabled = true
+		AND NOT EXISTS (
+			SELECT 1 FROM job_tasks jt
+			WHERE jt.agent_id = a.id AND jt.status IN ('assigned', 'running')
+		)`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, models.AgentStatusActive).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count idle agents: %w", err)
+	}
+	return count, nil
+}
+
+// AgentBusy reports whether the agent has an active task
+func (r *CloudBurstRepository) AgentBusy(ctx context.Context, agentID int) (bool, error) {
+	var busy bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM job_tasks WHERE agent_id = $1 AND status IN ('assigned', 'running'))`,
+		agentID).Scan(&busy)
+	if err != nil {
+		return false, fmt.Errorf("failed to check agent tasks: %w", err)
+	}
+	return busy, nil
+}
+
+// DisableAgent keeps the scheduler from assigning work to the agent of an
+// instance that is being torn down
+func (r *CloudBurstRepository) DisableAgent(ctx context.Context, agentID int) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE agents SET is_enabled = false, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to disable cloud burst agent: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// ClusterHealthRepository aggregates agent, queue and task statistics for the
+// cluster health summary
+type ClusterHealthRepository struct {
+	db *db.DB
+}
+
+// NewClusterHealthRepository creates a new cluster health repository
+func NewClusterHealthRepository(database *db.DB) *ClusterHealthRepository {
+	return &ClusterHealthRepository{db: database}
+}
+
+// CountAgents returns the number of active agents and the number of agents
+func (r *ClusterHealthRepository) CountAgents(ctx context.Context) (online, total int, err error) {
+	query := `SELECT COUNT(*) FILTER (WHERE status = $1), COUNT(*) FROM agents`
+	if err := r.db.Reader().QueryRowContext(ctx, query, models.AgentStatusActive).Scan(&online, &total); err != nil {
+		return 0, 0, fmt.Errorf("failed to count agents: %w", err)
+	}
+	return online, total, nil
+}
+
+// GetHashRates sums the last reported speed of the running tasks per hash type
+// class. Hash types missing from hash_types count as fast.
+func (r *ClusterHealthRepository) GetHashRates(ctx context.Context) ([]models.ClusterHashRate, error) {
+	query := `
+		SELECT
+			CASE WHEN COALESCE(ht.slow, false) THEN $1 ELSE $2 END AS class,
+			COALESCE(SUM(jt.benchmark_speed), 0),
+			COUNT(*)
+		FROM job_tasks jt
+		JOIN job_executions je ON je.id = jt.job_execution_id
+		LEFT JOIN hash_types ht ON ht.id = je.hash_type
+		WHERE jt.status = $3
+		GROUP BY class
+		ORDER BY class`
+
+	rows, err := r.db.Reader().QueryContext(ctx, query, models.HashClassSlow, models.HashClassFast, models.JobTaskStatusRunning)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := []models.ClusterHashRate{}
+	for rows.Next() {
+		var rate models.ClusterHashRate
+		if err := rows.Scan(&rate.Class, &rate.HashesPerSec, &rate.RunningTasks); err != nil {
+			return nil, fmt.Errorf("failed to scan hash rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+// GetQueueStats returns the number of pending jobs and when the oldest of them
+// was created, nil when none is pending
+func (r *ClusterHealthRepository) GetQueueStats(ctx context.Context) (int, *time.Time, error) {
+	var depth int
+	var oldest *time.Time
+	query := `SELECT COUNT(*), MIN(created_at) FROM job_executions WHERE status = $1`
+	if err := r.db.Reader().QueryRowContext(ctx, query, models.JobExecutionStatusPending).Scan(&depth, &oldest); err != nil {
+		return 0, nil, fmt.Errorf("failed to get queue statistics: %w", err)
+	}
+	return depth, oldest, nil
+}
+
+// CountFinishedTasks returns how many tasks completed or failed since a time
+// and how many of them failed
+func (r *ClusterHealthRepository) CountFinishedTasks(ctx context.Context, since time.Time) (finished, failed int, err error) {
+	query := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE status = $2)
+		FROM job_tasks
+		WHERE status IN ($1, $2) AND updated_at >= $3`
+	if err := r.db.Reader().QueryRowContext(ctx, query, models.JobTaskStatusCompleted, models.JobTaskStatusFailed, since).Scan(&finished, &failed); err != nil {
+		return 0, 0, fmt.Errorf("failed to count finished tasks: %w", err)
+	}
+	return finished, failed, nil
+}
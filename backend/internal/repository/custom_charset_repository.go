@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+const customCharsetColumns = `id, name, charset, description, created_by, created_at, updated_at`
+
+// CustomCharsetRepository stores the library of reusable custom charsets
+type CustomCharsetRepository struct {
+	db *db.DB
+}
+
+// NewCustomCharsetRepository creates a new custom charset repository
+func NewCustomCharsetRepository(database *db.DB) *CustomCharsetRepository {
+	return &CustomCharsetRepository{db: database}
+}
+
+// List returns all custom charsets ordered by name
+func (r *CustomCharsetRepository) List(ctx context.Context) ([]models.CustomCharset, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+customCharsetColumns+` FROM custom_charsets ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom charsets: %w", err)
+	}
+	defer rows.Close()
+
+	charsets := []models.CustomCharset{}
+	for rows.Next() {
+		charset, err := scanCustomCharset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom charset: %w", err)
+		}
+		charsets = append(charsets, *charset)
+	}
+	return charsets, rows.Err()
+}
+
+// GetByID returns a custom charset, or ErrNotFound
+func (r *CustomCharsetRepository) GetByID(ctx context.Context, id int) (*models.CustomCharset, error) {
+	charset, err := scanCustomCharset(r.db.QueryRowContext(ctx,
+		`SELECT `+customCharsetColumns+` FROM custom_charsets WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom charset: %w", err)
+	}
+	return charset, nil
+}
+
+// Create adds a custom charset to the library
+func (r *CustomCharsetRepository) Create(ctx context.Context, charset *models.CustomCharset) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO custom_charsets (name, charset, description, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		charset.Name, charset.Charset, charset.Description, charset.CreatedBy,
+	).Scan(&charset.ID, &charset.CreatedAt, &charset.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return fmt.Errorf("custom charset '%s' already exists: %w", charset.Name, ErrDuplicateRecord)
+		}
+		return fmt.Errorf("failed to create custom charset: %w", err)
+	}
+	return nil
+}
+
+// Update changes the name, charset and description of a custom charset
+func (r *CustomCharsetRepository) Update(ctx context.Context, charset *models.CustomCharset) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE custom_charsets
+		SET name = $2, charset = $3, description = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING created_by, created_at, updated_at`,
+		charset.ID, charset.Name, charset.Charset, charset.Description,
+	).Scan(&charset.CreatedBy, &charset.CreatedAt, &charset.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("custom charset '%s' already exists: %w", charset.Name, ErrDuplicateRecord)
+		}
+		return fmt.Errorf("failed to update custom charset: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a custom charset from the library
+func (r *CustomCharsetRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM custom_charsets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete custom charset: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanCustomCharset(row interface{ Scan(...interface{}) error }) (*models.CustomCharset, error) {
+	var charset models.CustomCharset
+	err := row.Scan(&charset.ID, &charset.Name, &charset.Charset, &charset.Description,
+		&charset.CreatedBy, &charset.CreatedAt, &charset.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &charset, nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// ExportJobRepository handles database operations for export jobs
+type ExportJobRepository struct {
+	db *db.DB
+}
+
+// NewExportJobRepository creates a new export job repository
+func NewExportJobRepository(database *db.DB) *ExportJobRepository {
+	return &ExportJobRepository{db: database}
+}
+
+const exportJobColumns = `
+	id, user_id, kind, params, status, processed, total, file_name, file_path, file_size,
+	error_message, created_at, started_at, completed_at, expires_at`
+
+// scanExportJob scans a row selected with exportJobColumns
+func scanExportJob(row rowScanner) (*models.ExportJob, error) {
+	var job models.ExportJob
+	var params []byte
+	var filePath sql.NullString
+	if err := row.Scan(
+		&job.ID, &job.UserID, &job.Kind, &params, &job.Status, &job.Processed, &job.Total,
+		&job.FileName, &filePath, &job.FileSize, &job.ErrorMessage,
+		&job.CreatedAt, &job.StartedAt, &job.CompletedAt, &job.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+	job.Params = params
+	job.FilePath = filePath.String
+	return &job, nil
+}
+
+// Create inserts a pending export job and fills in its ID, status and creation time
+func (r *ExportJobRepository) Create(ctx context.Context, job *models.ExportJob) error {
+	query := `
+		INSERT INTO export_jobs (user_id, kind, params)
+		VALUES ($1, $2, $3)
+		RETURNING id, status, created_at`
+	err := r.db.QueryRowContext(ctx, query, job.UserID, job.Kind, []byte(job.Params)).
+		Scan(&job.ID, &job.Status, &job.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create export job: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns an export job
+func (r *ExportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ExportJob, error) {
+	query := `SELECT ` + exportJobColumns + ` FROM export_jobs WHERE id = $1`
+	job, err := scanExportJob(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export job %s: %w", id, err)
+	}
+	return job, nil
+}
+
+// ListByUser returns the latest export jobs of a user, newest first
+func (r *ExportJobRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]models.ExportJob, error) {
+	query := `SELECT ` + exportJobColumns + ` FROM export_jobs WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list export jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []models.ExportJob{}
+	for rows.Next() {
+		job, err := scanExportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan export job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating export jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// MarkRunning records that an export job started writing its artifact to filePath
+func (r *ExportJobRepository) MarkRunning(ctx context.Context, id uuid.UUID, filePath string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE export_jobs SET status = 'running', file_path = $2, started_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		id, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to mark export job %s running: %w", id, err)
+	}
+	return nil
+}
+
+// UpdateProgress records how many records an export job has written and,
+// when known, how many it will write
+func (r *ExportJobRepository) UpdateProgress(ctx context.Context, id uuid.UUID, processed int64, total *int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE export_jobs SET processed = $2, total = COALESCE($3, total) WHERE id = $1`, id, processed, total)
+	if err != nil {
+		return fmt.Errorf("failed to update progress of export job %s: %w", id, err)
+	}
+	return nil
+}
+
+// Complete records the artifact of a finished export job
+func (r *ExportJobRepository) Complete(ctx context.Context, id uuid.UUID, processed int64, fileName string, fileSize int64, expiresAt time.Time) error {
+	query := `
+		UPDATE export_jobs
+		SET status = 'completed', processed = $2, total = $2, file_name = $3, file_size = $4,
+			completed_at = CURRENT_TIMESTAMP, expires_at = $5
+		WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, query, id, processed, fileName, fileSize, expiresAt); err != nil {
+		return fmt.Errorf("failed to complete export job %s: %w", id, err)
+	}
+	return nil
+}
+
+// Fail records why an export job could not be built
+func (r *ExportJobRepository) Fail(ctx context.Context, id uuid.UUID, message string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE export_jobs SET status = 'failed', error_message = $2, completed_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		id, message)
+	if err != nil {
+		return fmt.Errorf("failed to mark export job %s failed: %w", id, err)
+	}
+	return nil
+}
+
+// FailUnfinished marks every pending or running export job failed, for
+// exports a backend restart interrupted. It returns their artifact paths.
+func (r *ExportJobRepository) FailUnfinished(ctx context.Context, message string) ([]string, error) {
+	query := `
+		UPDATE export_jobs SET status = 'failed', error_message = $1, completed_at = CURRENT_TIMESTAMP
+		WHERE status IN ('pending', 'running')
+		RETURNING COALESCE(file_path, '')`
+	return r.queryPaths(ctx, query, message)
+}
+
+// ExpireCompleted marks completed export jobs whose retention ended before
+// now expired and returns their artifact paths
+func (r *ExportJobRepository) ExpireCompleted(ctx context.Context, now time.Time) ([]string, error) {
+	query := `
+		UPDATE export_jobs SET status = 'expired'
+		WHERE status = 'completed' AND expires_at <= $1
+		RETURNING COALESCE(file_path, '')`
+	return r.queryPaths(ctx, query, now)
+}
+
+// Delete removes an export job
+func (r *ExportJobRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM export_jobs WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete export job %s: %w", id, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryPaths runs an update returning artifact paths and collects the non-empty ones
+func (r *ExportJobRepository) queryPaths(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update export jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan export artifact path: %w", err)
+		}
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, rows.Err()
+}
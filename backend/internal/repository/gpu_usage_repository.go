@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// GPUUsageRepository handles database operations for the GPU time consumed by tasks
+type GPUUsageRepository struct {
+	db *db.DB
+}
+
+// NewGPUUsageRepository creates a new GPU usage repository
+func NewGPUUsageRepository(db *db.DB) *GPUUsageRepository {
+	return &GPUUsageRepository{db: db}
+}
+
+// RecordDeviceUsage adds the time since the device's previous report on the
+// task to its GPU seconds. The first report only starts the clock, and a gap
+// longer than maxGap (a lost connection or a paused agent) counts as maxGap.
+func (r *GPUUsageRepository) RecordDeviceUsage(ctx context.Context, task *models.JobTask, agentID int, devices []models.DeviceMetric, maxGap time.Duration) error {
+	query := `
+		INSERT INTO task_gpu_usage (task_id, job_execution_id, job_name, client_id, agent_id, device_id, device_name)
+		SELECT $1, je.id, COALESCE(je.name, ''), h.client_id, $3, $4, $5
+		FROM job_executions je
+		JOIN hashlists h ON h.id = je.hashlist_id
+		WHERE je.id = $2
+		ON CONFLICT (task_id, agent_id, device_id) DO UPDATE SET
+			gpu_seconds = task_gpu_usage.gpu_seconds +
+				LEAST(GREATEST(EXTRACT(EPOCH FROM (NOW() - task_gpu_usage.last_reported_at)), 0), $6),
+			device_name = EXCLUDED.device_name,
+			last_reported_at = NOW()`
+
+	for _, device := range devices {
+		_, err := r.db.ExecContext(ctx, query,
+			task.ID,
+			task.JobExecutionID,
+			agentID,
+			device.DeviceID,
+			device.DeviceName,
+			maxGap.Seconds(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record GPU usage for device %d: %w", device.DeviceID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetJobDeviceUsage returns the GPU seconds each agent device spent on a job
+func (r *GPUUsageRepository) GetJobDeviceUsage(ctx context.Context, jobExecutionID uuid.UUID) ([]models.DeviceGPUUsage, error) {
+	query := `
+		SELECT u.agent_id, COALESCE(a.name, ''), u.device_id, MAX(u.device_name), SUM(u.gpu_seconds)
+		FROM task_gpu_usage u
+		LEFT JOIN agents a ON a.id = u.agent_id
+		WHERE u.job_execution_id = $1
+		GROUP BY u.agent_id, a.name, u.device_id
+		ORDER BY SUM(u.gpu_seconds) DESC`
+
+	rows, err := r.db.Reader().QueryContext(ctx, query, jobExecutionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GPU usage for job: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []models.DeviceGPUUsage{}
+	for rows.Next() {
+		var device models.DeviceGPUUsage
+		if err := rows.Scan(
+			&device.AgentID,
+			&device.AgentName,
+			&device.DeviceID,
+			&device.DeviceName,
+			&device.GPUSeconds,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan device GPU usage: %w", err)
+		}
+		usage = append(usage, device)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating device GPU usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// GetClientJobUsage returns the GPU seconds spent on each job of a client,
+// optionally limited to usage reported within [from, to]. Usage of deleted
+// jobs is kept under the job's name.
+func (r *GPUUsageRepository) GetClientJobUsage(ctx context.Context, clientID uuid.UUID, from, to *time.Time) ([]models.JobGPUUsage, error) {
+	query := `
+		SELECT job_execution_id, MAX(job_name), SUM(gpu_seconds), MIN(first_reported_at), MAX(last_reported_at)
+		FROM task_gpu_usage
+		WHERE client_id = $1
+			AND ($2::timestamptz IS NULL OR last_reported_at >= $2)
+			AND ($3::timestamptz IS NULL OR first_reported_at <= $3)
+		GROUP BY job_execution_id, CASE WHEN job_execution_id IS NULL THEN job_name END
+		ORDER BY MIN(first_reported_at)`
+
+	rows, err := r.db.Reader().QueryContext(ctx, query, clientID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GPU usage for client: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []models.JobGPUUsage{}
+	for rows.Next() {
+		var job models.JobGPUUsage
+		if err := rows.Scan(
+			&job.JobExecutionID,
+			&job.JobName,
+			&job.GPUSeconds,
+			&job.FirstUsedAt,
+			&job.LastUsedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan job GPU usage: %w", err)
+		}
+		usage = append(usage, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job GPU usage: %w", err)
+	}
+
+	return usage, nil
+}
@@ -82,8 +82,8 @@ func (r *HashRepository) CreateBatch(ctx context.Context, hashes []*models.Hash)
 	defer txn.Rollback() // Rollback if commit isn't reached
 
 	stmt, err := txn.PrepareContext(ctx, `
-		INSERT INTO hashes (id, hash_value, original_hash, username, domain, hash_type_id, is_cracked, password, last_updated)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO hashes (id, hash_value, original_hash, username, domain, hash_type_id, is_cracked, password, last_updated, password_raw, cracked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CASE WHEN $7 THEN $9::timestamptz END)
 	`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare statement for batch hash create: %w", err)
@@ -98,7 +98,7 @@ func (r *HashRepository) CreateBatch(ctx context.Context, hashes []*models.Hash)
 		if hash.LastUpdated.IsZero() {
 			hash.LastUpdated = time.Now()
 		}
-		debug.Debug("[DB:CreateBatch] Attempting insert %d: ID=%s, Value='%s'", i+1, hash.ID, hash.HashValue)
+		debug.Debug("[DB:CreateBatch] Attempting insert %d: ID=%s, Value='%s'", i+1, hash.ID, debug.Hash(hash.HashValue))
 		_, err := stmt.ExecContext(ctx,
 			hash.ID,
 			hash.HashValue,
@@ -109,6 +109,7 @@ func (r *HashRepository) CreateBatch(ctx context.Context, hashes []*models.Hash)
 			hash.IsCracked,
 			hash.Password,
 			hash.LastUpdated,
+			hash.PasswordRaw,
 		)
 		if err != nil {
 			// If ON CONFLICT is removed, we might need error handling for other potential issues.
@@ -141,7 +142,8 @@ func (r *HashRepository) UpdateBatch(ctx context.Context, hashes []*models.Hash)
 
 	stmt, err := txn.PrepareContext(ctx, `
 		UPDATE hashes
-		SET is_cracked = $1, password = $2, username = COALESCE(username, $3), domain = COALESCE(domain, $4), last_updated = $5
+		SET is_cracked = $1, password = $2, username = COALESCE(username, $3), domain = COALESCE(domain, $4), last_updated = $5, password_raw = $7,
+			cracked_at = CASE WHEN $1 THEN COALESCE(cracked_at, $5) END
 		WHERE id = $6
 	`)
 	if err != nil {
@@ -161,6 +163,7 @@ func (r *HashRepository) UpdateBatch(ctx context.Context, hashes []*models.Hash)
 			hash.Domain,   // Add domain argument (COALESCE handles NULL case in SQL)
 			time.Now(),    // Update last_updated time
 			hash.ID,
+			hash.PasswordRaw,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to execute batch hash update for hash ID %s: %w", hash.ID, err)
@@ -240,6 +243,7 @@ func (r *HashRepository) SearchHashes(ctx context.Context, hashValues []string,
 		JOIN hashlists hl ON hlh.hashlist_id = hl.id
 		WHERE h.hash_value = ANY($1)
 		  AND hl.user_id = $2
+		  AND hl.deleted_at IS NULL
 		ORDER BY h.hash_value, hl.name; -- Group results by hash value
 	`
 
@@ -301,13 +305,52 @@ func (r *HashRepository) SearchHashes(ctx context.Context, hashValues []string,
 
 // GetHashesByHashlistID retrieves hashes associated with a specific hashlist, with pagination.
 func (r *HashRepository) GetHashesByHashlistID(ctx context.Context, hashlistID int64, limit, offset int) ([]models.Hash, int, error) {
-	// Query to count total hashes for the hashlist
+	return r.ListHashlistHashes(ctx, hashlistID, HashListFilter{}, limit, offset)
+}
+
+// CountByHashlistID returns the number of hashes in a hashlist
+func (r *HashRepository) CountByHashlistID(ctx context.Context, hashlistID int64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM hashlist_hashes WHERE hashlist_id = $1`, hashlistID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count hashes of hashlist %d: %w", hashlistID, err)
+	}
+	return count, nil
+}
+
+// HashListFilter selects the hashes returned by ListHashlistHashes. Zero
+// fields do not filter.
+type HashListFilter struct {
+	Cracked  *bool
+	Username string // Case-insensitive substring of the username
+	Domain   string // Case-insensitive substring of the domain
+}
+
+// ListHashlistHashes retrieves a filtered page of a hashlist's hashes, cracked
+// hashes first, along with the total number of matching hashes
+func (r *HashRepository) ListHashlistHashes(ctx context.Context, hashlistID int64, filter HashListFilter, limit, offset int) ([]models.Hash, int, error) {
+	where := "WHERE hlh.hashlist_id = $1"
+	args := []interface{}{hashlistID}
+	if filter.Cracked != nil {
+		args = append(args, *filter.Cracked)
+		where += fmt.Sprintf(" AND h.is_cracked = $%d", len(args))
+	}
+	if filter.Username != "" {
+		args = append(args, "%"+filter.Username+"%")
+		where += fmt.Sprintf(" AND h.username ILIKE $%d", len(args))
+	}
+	if filter.Domain != "" {
+		args = append(args, "%"+filter.Domain+"%")
+		where += fmt.Sprintf(" AND h.domain ILIKE $%d", len(args))
+	}
+
+	// Query to count the matching hashes of the hashlist
 	countQuery := `SELECT COUNT(h.id)
 				  FROM hashes h
 				  JOIN hashlist_hashes hlh ON h.id = hlh.hash_id
-				  WHERE hlh.hashlist_id = $1`
+				  ` + where
 	var totalCount int
-	err := r.db.QueryRowContext(ctx, countQuery, hashlistID).Scan(&totalCount)
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count hashes for hashlist %d: %w", hashlistID, err)
 	}
@@ -322,11 +365,11 @@ func (r *HashRepository) GetHashesByHashlistID(ctx context.Context, hashlistID i
 		SELECT h.id, h.hash_value, h.original_hash, h.username, h.domain, h.hash_type_id, h.is_cracked, h.password, h.last_updated
 		FROM hashes h
 		JOIN hashlist_hashes hlh ON h.id = hlh.hash_id
-		WHERE hlh.hashlist_id = $1
+		` + where + fmt.Sprintf(`
 		ORDER BY h.is_cracked DESC, h.id
-		LIMIT $2 OFFSET $3
-	`
-	rows, err := r.db.QueryContext(ctx, query, hashlistID, limit, offset)
+		LIMIT $%d OFFSET $%d
+	`, len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get hashes for hashlist %d: %w", hashlistID, err)
 	}
@@ -357,6 +400,61 @@ func (r *HashRepository) GetHashesByHashlistID(ctx context.Context, hashlistID i
 	return hashes, totalCount, nil
 }
 
+// GetCracksSince returns up to limit hashes of a hashlist cracked after the
+// cursor, oldest first, with the task and job that cracked them
+func (r *HashRepository) GetCracksSince(ctx context.Context, hashlistID int64, after models.CrackCursor, limit int) ([]models.CrackDelta, error) {
+	query := `
+		SELECT h.id, h.hash_value, h.original_hash, h.username, h.domain, h.password, h.cracked_at,
+			t.id, t.job_execution_id, t.agent_id, je.name, je.attack_mode, je.wordlist_ids, je.rule_ids, je.mask
+		FROM hashlist_hashes hlh
+		JOIN hashes h ON h.id = hlh.hash_id
+		LEFT JOIN job_tasks t ON t.id = h.cracked_by_task_id
+		LEFT JOIN job_executions je ON je.id = t.job_execution_id
+		WHERE hlh.hashlist_id = $1
+		  AND h.is_cracked = true
+		  AND (h.cracked_at, h.id) > ($2, $3)
+		ORDER BY h.cracked_at, h.id
+		LIMIT $4
+	`
+	rows, err := r.db.QueryContext(ctx, query, hashlistID, after.CrackedAt, after.HashID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cracks for hashlist %d: %w", hashlistID, err)
+	}
+	defer rows.Close()
+
+	deltas := []models.CrackDelta{}
+	for rows.Next() {
+		var delta models.CrackDelta
+		var password sql.NullString
+		if err := rows.Scan(
+			&delta.HashID,
+			&delta.HashValue,
+			&delta.OriginalHash,
+			&delta.Username,
+			&delta.Domain,
+			&password,
+			&delta.CrackedAt,
+			&delta.TaskID,
+			&delta.JobExecutionID,
+			&delta.AgentID,
+			&delta.JobName,
+			&delta.AttackMode,
+			&delta.WordlistIDs,
+			&delta.RuleIDs,
+			&delta.Mask,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan crack row for hashlist %d: %w", hashlistID, err)
+		}
+		delta.Password = password.String
+		deltas = append(deltas, delta)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating crack rows for hashlist %d: %w", hashlistID, err)
+	}
+
+	return deltas, nil
+}
+
 // GetUncrackedHashValuesByHashlistID retrieves only the hash_value strings for uncracked hashes
 // associated with a specific hashlist. Uses DISTINCT to ensure unique hash values only
 // (e.g., when multiple users have the same password, only send the hash once to hashcat).
@@ -424,13 +522,16 @@ func (r *HashRepository) GetByHashValueForUpdate(tx *sql.Tx, hashValue string) (
 }
 
 // UpdateCrackStatus updates the cracked status and password for a hash within a transaction.
-func (r *HashRepository) UpdateCrackStatus(tx *sql.Tx, hashID uuid.UUID, password string, crackedAt time.Time, username *string) error {
+// passwordRaw holds the exact bytes of the password when password cannot, see plaintext.ForStorage.
+// taskID is the task that cracked the hash, nil when it was not cracked by an agent.
+func (r *HashRepository) UpdateCrackStatus(tx *sql.Tx, hashID uuid.UUID, password string, passwordRaw []byte, crackedAt time.Time, username *string, taskID *uuid.UUID) error {
 	query := `
 		UPDATE hashes
-		SET is_cracked = TRUE, password = $1, username = COALESCE(username, $2), last_updated = $3
+		SET is_cracked = TRUE, password = $1, username = COALESCE(username, $2), last_updated = $3, password_raw = $5,
+			cracked_at = $3, cracked_by_task_id = $6
 		WHERE id = $4 AND is_cracked = FALSE -- Only update if not already cracked
 	`
-	result, err := tx.Exec(query, password, username, crackedAt, hashID)
+	result, err := tx.Exec(query, password, username, crackedAt, hashID, passwordRaw, taskID)
 	if err != nil {
 		return fmt.Errorf("failed to update crack status for hash %s: %w", hashID, err)
 	}
@@ -544,21 +645,21 @@ func (r *HashRepository) GetCrackedHashes(ctx context.Context, params CrackedHas
 	// First, get the total count
 	countQuery := `SELECT COUNT(*) FROM hashes WHERE is_cracked = true`
 	var totalCount int64
-	err := r.db.QueryRowContext(ctx, countQuery).Scan(&totalCount)
+	err := r.db.Reader().QueryRowContext(ctx, countQuery).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count cracked hashes: %w", err)
 	}
 
 	// Then get the paginated results
 	query := `
-		SELECT id, hash_value, original_hash, username, domain, hash_type_id, is_cracked, password, last_updated
+		SELECT id, hash_value, original_hash, username, domain, hash_type_id, is_cracked, password, last_updated, password_raw
 		FROM hashes
 		WHERE is_cracked = true
 		ORDER BY last_updated DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, params.Limit, params.Offset)
+	rows, err := r.db.Reader().QueryContext(ctx, query, params.Limit, params.Offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query cracked hashes: %w", err)
 	}
@@ -577,6 +678,7 @@ func (r *HashRepository) GetCrackedHashes(ctx context.Context, params CrackedHas
 			&hash.IsCracked,
 			&hash.Password,
 			&hash.LastUpdated,
+			&hash.PasswordRaw,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan cracked hash row: %w", err)
 		}
@@ -600,14 +702,14 @@ func (r *HashRepository) GetCrackedHashesByHashlist(ctx context.Context, hashlis
 		WHERE hh.hashlist_id = $1 AND h.is_cracked = true
 	`
 	var totalCount int64
-	err := r.db.QueryRowContext(ctx, countQuery, hashlistID).Scan(&totalCount)
+	err := r.db.Reader().QueryRowContext(ctx, countQuery, hashlistID).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count cracked hashes for hashlist %d: %w", hashlistID, err)
 	}
 
 	// Then get the paginated results
 	query := `
-		SELECT h.id, h.hash_value, h.original_hash, h.username, h.domain, h.hash_type_id, h.is_cracked, h.password, h.last_updated
+		SELECT h.id, h.hash_value, h.original_hash, h.username, h.domain, h.hash_type_id, h.is_cracked, h.password, h.last_updated, h.password_raw
 		FROM hashes h
 		JOIN hashlist_hashes hh ON h.id = hh.hash_id
 		WHERE hh.hashlist_id = $1 AND h.is_cracked = true
@@ -615,7 +717,7 @@ func (r *HashRepository) GetCrackedHashesByHashlist(ctx context.Context, hashlis
 		LIMIT $2 OFFSET $3
 	`
 	
-	rows, err := r.db.QueryContext(ctx, query, hashlistID, params.Limit, params.Offset)
+	rows, err := r.db.Reader().QueryContext(ctx, query, hashlistID, params.Limit, params.Offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query cracked hashes for hashlist %d: %w", hashlistID, err)
 	}
@@ -634,6 +736,7 @@ func (r *HashRepository) GetCrackedHashesByHashlist(ctx context.Context, hashlis
 			&hash.IsCracked,
 			&hash.Password,
 			&hash.LastUpdated,
+			&hash.PasswordRaw,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan cracked hash row for hashlist %d: %w", hashlistID, err)
 		}
@@ -658,14 +761,14 @@ func (r *HashRepository) GetCrackedHashesByClient(ctx context.Context, clientID
 		WHERE hl.client_id = $1 AND h.is_cracked = true
 	`
 	var totalCount int64
-	err := r.db.QueryRowContext(ctx, countQuery, clientID).Scan(&totalCount)
+	err := r.db.Reader().QueryRowContext(ctx, countQuery, clientID).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count cracked hashes for client %s: %w", clientID, err)
 	}
 
 	// Then get the paginated results
 	query := `
-		SELECT h.id, h.hash_value, h.original_hash, h.username, h.domain, h.hash_type_id, h.is_cracked, h.password, h.last_updated
+		SELECT h.id, h.hash_value, h.original_hash, h.username, h.domain, h.hash_type_id, h.is_cracked, h.password, h.last_updated, h.password_raw
 		FROM hashes h
 		JOIN hashlist_hashes hh ON h.id = hh.hash_id
 		JOIN hashlists hl ON hh.hashlist_id = hl.id
@@ -674,7 +777,7 @@ func (r *HashRepository) GetCrackedHashesByClient(ctx context.Context, clientID
 		LIMIT $2 OFFSET $3
 	`
 	
-	rows, err := r.db.QueryContext(ctx, query, clientID, params.Limit, params.Offset)
+	rows, err := r.db.Reader().QueryContext(ctx, query, clientID, params.Limit, params.Offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query cracked hashes for client %s: %w", clientID, err)
 	}
@@ -693,6 +796,7 @@ func (r *HashRepository) GetCrackedHashesByClient(ctx context.Context, clientID
 			&hash.IsCracked,
 			&hash.Password,
 			&hash.LastUpdated,
+			&hash.PasswordRaw,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan cracked hash row for client %s: %w", clientID, err)
 		}
@@ -706,6 +810,65 @@ func (r *HashRepository) GetCrackedHashesByClient(ctx context.Context, clientID
 	return hashes, totalCount, nil
 }
 
+// CountClientPotfile returns how many lines StreamClientPotfile produces for
+// the same client and range
+func (r *HashRepository) CountClientPotfile(ctx context.Context, clientID uuid.UUID, from, to *time.Time) (int64, error) {
+	query := `
+		SELECT COUNT(*) FROM (
+			SELECT DISTINCT h.hash_value, h.password, h.password_raw
+			FROM hashes h
+			JOIN hashlist_hashes hh ON h.id = hh.hash_id
+			JOIN hashlists hl ON hh.hashlist_id = hl.id
+			WHERE hl.client_id = $1 AND hl.deleted_at IS NULL AND h.is_cracked = true
+			  AND ($2::timestamptz IS NULL OR h.last_updated >= $2)
+			  AND ($3::timestamptz IS NULL OR h.last_updated < $3)
+		) potfile
+	`
+	var count int64
+	if err := r.db.Reader().QueryRowContext(ctx, query, clientID, from, to).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count potfile for client %s: %w", clientID, err)
+	}
+	return count, nil
+}
+
+// StreamClientPotfile calls fn for every distinct cracked hash and password of
+// a client's hashlists outside the trash, ordered by hash. from and to, when
+// set, limit the hashes to those cracked in [from, to), going by when the hash
+// was last updated.
+func (r *HashRepository) StreamClientPotfile(ctx context.Context, clientID uuid.UUID, from, to *time.Time, fn func(hashValue string, plain []byte) error) error {
+	query := `
+		SELECT DISTINCT h.hash_value, h.password, h.password_raw
+		FROM hashes h
+		JOIN hashlist_hashes hh ON h.id = hh.hash_id
+		JOIN hashlists hl ON hh.hashlist_id = hl.id
+		WHERE hl.client_id = $1 AND hl.deleted_at IS NULL AND h.is_cracked = true
+		  AND ($2::timestamptz IS NULL OR h.last_updated >= $2)
+		  AND ($3::timestamptz IS NULL OR h.last_updated < $3)
+		ORDER BY h.hash_value
+	`
+
+	rows, err := r.db.Reader().QueryContext(ctx, query, clientID, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to query potfile for client %s: %w", clientID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash models.Hash
+		if err := rows.Scan(&hash.HashValue, &hash.Password, &hash.PasswordRaw); err != nil {
+			return fmt.Errorf("failed to scan potfile row for client %s: %w", clientID, err)
+		}
+		if err := fn(hash.HashValue, hash.PlainBytes()); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating potfile rows for client %s: %w", clientID, err)
+	}
+	return nil
+}
+
 // GetCrackedHashesByJob retrieves cracked hashes for a specific job execution
 func (r *HashRepository) GetCrackedHashesByJob(ctx context.Context, jobID uuid.UUID, params CrackedHashParams) ([]*models.Hash, int64, error) {
 	// First, get the total count
@@ -717,14 +880,14 @@ func (r *HashRepository) GetCrackedHashesByJob(ctx context.Context, jobID uuid.U
 		WHERE j.id = $1 AND h.is_cracked = true
 	`
 	var totalCount int64
-	err := r.db.QueryRowContext(ctx, countQuery, jobID).Scan(&totalCount)
+	err := r.db.Reader().QueryRowContext(ctx, countQuery, jobID).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count cracked hashes for job %s: %w", jobID, err)
 	}
 
 	// Then get the paginated results
 	query := `
-		SELECT h.id, h.hash_value, h.original_hash, h.username, h.domain, h.hash_type_id, h.is_cracked, h.password, h.last_updated
+		SELECT h.id, h.hash_value, h.original_hash, h.username, h.domain, h.hash_type_id, h.is_cracked, h.password, h.last_updated, h.password_raw
 		FROM hashes h
 		JOIN hashlist_hashes hh ON h.id = hh.hash_id
 		JOIN job_executions j ON j.hashlist_id = hh.hashlist_id
@@ -733,7 +896,7 @@ func (r *HashRepository) GetCrackedHashesByJob(ctx context.Context, jobID uuid.U
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, jobID, params.Limit, params.Offset)
+	rows, err := r.db.Reader().QueryContext(ctx, query, jobID, params.Limit, params.Offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query cracked hashes for job %s: %w", jobID, err)
 	}
@@ -752,6 +915,7 @@ func (r *HashRepository) GetCrackedHashesByJob(ctx context.Context, jobID uuid.U
 			&hash.IsCracked,
 			&hash.Password,
 			&hash.LastUpdated,
+			&hash.PasswordRaw,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan cracked hash row for job %s: %w", jobID, err)
 		}
@@ -764,3 +928,83 @@ func (r *HashRepository) GetCrackedHashesByJob(ctx context.Context, jobID uuid.U
 
 	return hashes, totalCount, nil
 }
+
+// CrackProvenance is a cracked hash together with every live hashlist it belongs to
+type CrackProvenance struct {
+	Hash    models.Hash
+	Sources []models.CrackSource
+}
+
+// GetCrackProvenance finds the cracked hashes whose value or original line
+// matches one of hashValues across all users, optionally limited to a hash
+// type. Each hash comes with the hashlists it is in and, per hashlist, the jobs
+// that were running when it was cracked.
+func (r *HashRepository) GetCrackProvenance(ctx context.Context, hashValues []string, hashTypeID *int) ([]CrackProvenance, error) {
+	if len(hashValues) == 0 {
+		return []CrackProvenance{}, nil
+	}
+
+	query := `
+		SELECT
+			h.id, h.hash_value, h.original_hash, h.hash_type_id, h.password, h.last_updated,
+			hl.id, hl.name, hl.user_id, c.id, c.name,
+			hl.exclude_from_potfile OR COALESCE(c.exclude_from_potfile, false),
+			ARRAY(
+				SELECT je.id::text FROM job_executions je
+				WHERE je.hashlist_id = hl.id
+				  AND je.started_at <= h.last_updated
+				  AND (je.completed_at IS NULL OR je.completed_at >= h.last_updated)
+				ORDER BY je.started_at
+			)
+		FROM hashes h
+		JOIN hashlist_hashes hlh ON h.id = hlh.hash_id
+		JOIN hashlists hl ON hlh.hashlist_id = hl.id
+		LEFT JOIN clients c ON hl.client_id = c.id
+		WHERE (h.hash_value = ANY($1) OR h.original_hash = ANY($1))
+		  AND h.is_cracked = true
+		  AND hl.deleted_at IS NULL
+		  AND ($2::int IS NULL OR h.hash_type_id = $2)
+		ORDER BY h.hash_value, h.id, hl.id`
+
+	rows, err := r.db.Reader().QueryContext(ctx, query, pq.Array(hashValues), hashTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up cracked hashes: %w", err)
+	}
+	defer rows.Close()
+
+	var results []CrackProvenance
+	for rows.Next() {
+		var hash models.Hash
+		var source models.CrackSource
+		var jobIDs []string
+		if err := rows.Scan(
+			&hash.ID, &hash.HashValue, &hash.OriginalHash, &hash.HashTypeID, &hash.Password, &hash.LastUpdated,
+			&source.HashlistID, &source.HashlistName, &source.OwnerID, &source.ClientID, &source.ClientName,
+			&source.Restricted, pq.Array(&jobIDs),
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan cracked hash lookup row: %w", err)
+		}
+		hash.IsCracked = true
+
+		source.JobIDs = make([]uuid.UUID, 0, len(jobIDs))
+		for _, id := range jobIDs {
+			jobID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid job id %q: %w", id, err)
+			}
+			source.JobIDs = append(source.JobIDs, jobID)
+		}
+
+		// Rows are ordered by hash, so a hash's hashlists are adjacent
+		if n := len(results); n > 0 && results[n-1].Hash.ID == hash.ID {
+			results[n-1].Sources = append(results[n-1].Sources, source)
+			continue
+		}
+		results = append(results, CrackProvenance{Hash: hash, Sources: []models.CrackSource{source}})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cracked hash lookup rows: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashRepository_ClientPotfile_SkipsTrashedHashlist(t *testing.T) {
+	database := testutil.SetupTestDB(t)
+	ctx := context.Background()
+	hashRepo := NewHashRepository(database)
+	hashlistRepo := NewHashListRepository(database)
+
+	user := testutil.CreateTestUser(t, database, "potfileuser", "potfile@example.com", testutil.DefaultTestPassword, "user")
+	client := &models.Client{ID: uuid.New(), Name: "Potfile Client"}
+	require.NoError(t, client.Normalize())
+	require.NoError(t, NewClientRepository(database).Create(ctx, client))
+
+	// One cracked hash on a kept hashlist, another on a trashed one
+	var hashlistIDs []int64
+	for i, password := range []string{"kept", "trashed"} {
+		hashlist := &models.HashList{
+			Name:       "Potfile hashlist " + password,
+			UserID:     user.ID,
+			ClientID:   client.ID,
+			HashTypeID: 0,
+			Status:     models.HashListStatusReady,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+		require.NoError(t, hashlistRepo.Create(ctx, hashlist))
+		hashlistIDs = append(hashlistIDs, hashlist.ID)
+
+		hash := &models.Hash{
+			ID:          uuid.New(),
+			HashValue:   []string{"5f4dcc3b5aa765d61d8327deb882cf99", "8621ffdbc5698829397d97767ac13db3"}[i],
+			HashTypeID:  0,
+			IsCracked:   true,
+			Password:    password,
+			LastUpdated: time.Now(),
+		}
+		hash.OriginalHash = hash.HashValue
+		created, err := hashRepo.CreateBatch(ctx, []*models.Hash{hash})
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		require.NoError(t, hashRepo.AddBatchToHashList(ctx, []*models.HashListHash{{HashlistID: hashlist.ID, HashID: created[0].ID}}))
+	}
+	require.NoError(t, hashlistRepo.SoftDelete(ctx, hashlistIDs[1], nil))
+
+	count, err := hashRepo.CountClientPotfile(ctx, client.ID, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	var streamed []string
+	err = hashRepo.StreamClientPotfile(ctx, client.ID, nil, nil, func(hashValue string, plain []byte) error {
+		streamed = append(streamed, hashValue+":"+string(plain))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5f4dcc3b5aa765d61d8327deb882cf99:kept"}, streamed)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
@@ -24,8 +26,8 @@ func NewHashTypeRepository(database *db.DB) *HashTypeRepository {
 // Note: Typically managed via migrations, but might be needed for admin UI.
 func (r *HashTypeRepository) Create(ctx context.Context, hashType *models.HashType) error {
 	query := `
-		INSERT INTO hash_types (id, name, description, example, needs_processing, processing_logic, is_enabled, slow)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO hash_types (id, name, description, example, needs_processing, processing_logic, is_enabled, slow, aliases, validation_regex, is_custom)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		hashType.ID,
@@ -36,6 +38,9 @@ func (r *HashTypeRepository) Create(ctx context.Context, hashType *models.HashTy
 		hashType.ProcessingLogic,
 		hashType.IsEnabled,
 		hashType.Slow,
+		pq.Array(hashType.Aliases),
+		hashType.ValidationRegex,
+		hashType.IsCustom,
 	)
 	if err != nil {
 		// Check for primary key violation
@@ -50,7 +55,8 @@ func (r *HashTypeRepository) Create(ctx context.Context, hashType *models.HashTy
 // GetByID retrieves a hash type by its ID (hashcat mode number).
 func (r *HashTypeRepository) GetByID(ctx context.Context, id int) (*models.HashType, error) {
 	query := `
-		SELECT id, name, description, example, needs_processing, processing_logic, is_enabled, slow
+		SELECT id, name, description, example, needs_processing, processing_logic, is_enabled, slow,
+			aliases, validation_regex, is_custom
 		FROM hash_types
 		WHERE id = $1
 	`
@@ -64,6 +70,9 @@ func (r *HashTypeRepository) GetByID(ctx context.Context, id int) (*models.HashT
 		&hashType.ProcessingLogic,
 		&hashType.IsEnabled,
 		&hashType.Slow,
+		pq.Array(&hashType.Aliases),
+		&hashType.ValidationRegex,
+		&hashType.IsCustom,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -78,7 +87,8 @@ func (r *HashTypeRepository) GetByID(ctx context.Context, id int) (*models.HashT
 // It can optionally filter by the `is_enabled` status.
 func (r *HashTypeRepository) List(ctx context.Context, enabledOnly bool) ([]models.HashType, error) {
 	baseQuery := `
-		SELECT id, name, description, example, needs_processing, processing_logic, is_enabled, slow
+		SELECT id, name, description, example, needs_processing, processing_logic, is_enabled, slow,
+			aliases, validation_regex, is_custom
 		FROM hash_types
 	`
 	args := []interface{}{}
@@ -109,6 +119,9 @@ func (r *HashTypeRepository) List(ctx context.Context, enabledOnly bool) ([]mode
 			&hashType.ProcessingLogic,
 			&hashType.IsEnabled,
 			&hashType.Slow,
+			pq.Array(&hashType.Aliases),
+			&hashType.ValidationRegex,
+			&hashType.IsCustom,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan hash type row: %w", err)
 		}
@@ -121,13 +134,62 @@ func (r *HashTypeRepository) List(ctx context.Context, enabledOnly bool) ([]mode
 	return hashTypes, nil
 }
 
+// Resolve retrieves a hash type by its mode number, or case-insensitively by
+// its name or one of its aliases.
+func (r *HashTypeRepository) Resolve(ctx context.Context, ref string) (*models.HashType, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return r.GetByID(ctx, id)
+	}
+
+	query := `
+		SELECT id FROM hash_types
+		WHERE LOWER(name) = LOWER($1) OR LOWER($1) = ANY(aliases)
+		ORDER BY id ASC
+		LIMIT 1
+	`
+	var id int
+	if err := r.db.QueryRowContext(ctx, query, ref).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("hash type %q not found: %w", ref, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to resolve hash type %q: %w", ref, err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+// FindAliasOwner returns the ID of a hash type other than excludeID that
+// already uses one of the aliases, and that alias. It returns ok false when
+// none of them is taken.
+func (r *HashTypeRepository) FindAliasOwner(ctx context.Context, aliases []string, excludeID int) (id int, alias string, ok bool, err error) {
+	if len(aliases) == 0 {
+		return 0, "", false, nil
+	}
+	query := `
+		SELECT id, a
+		FROM hash_types, UNNEST(aliases) AS a
+		WHERE id <> $1 AND a = ANY($2)
+		ORDER BY id ASC
+		LIMIT 1
+	`
+	err = r.db.QueryRowContext(ctx, query, excludeID, pq.Array(aliases)).Scan(&id, &alias)
+	if err == sql.ErrNoRows {
+		return 0, "", false, nil
+	}
+	if err != nil {
+		return 0, "", false, fmt.Errorf("failed to check hash type aliases: %w", err)
+	}
+	return id, alias, true, nil
+}
+
 // Update modifies an existing hash type record.
 // Note: Typically managed via migrations.
 func (r *HashTypeRepository) Update(ctx context.Context, hashType *models.HashType) error {
 	query := `
 		UPDATE hash_types
-		SET name = $1, description = $2, example = $3, needs_processing = $4, processing_logic = $5, is_enabled = $6, slow = $7
-		WHERE id = $8
+		SET name = $1, description = $2, example = $3, needs_processing = $4, processing_logic = $5, is_enabled = $6, slow = $7,
+			aliases = $8, validation_regex = $9
+		WHERE id = $10
 	`
 	// Note: Not updating created_at, only updated_at if it existed.
 	// For simplicity, we assume migrations handle this or it's not critical for hash types.
@@ -139,6 +201,8 @@ func (r *HashTypeRepository) Update(ctx context.Context, hashType *models.HashTy
 		hashType.ProcessingLogic,
 		hashType.IsEnabled,
 		hashType.Slow,
+		pq.Array(hashType.Aliases),
+		hashType.ValidationRegex,
 		hashType.ID,
 	)
 	if err != nil {
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// HashlistAccountRepository stores the account metadata of hashlists
+type HashlistAccountRepository struct {
+	db *db.DB
+}
+
+// NewHashlistAccountRepository creates a new hashlist account repository
+func NewHashlistAccountRepository(database *db.DB) *HashlistAccountRepository {
+	return &HashlistAccountRepository{db: database}
+}
+
+// accountKey identifies an account within a hashlist, ignoring case
+func accountKey(domain, username string) string {
+	return strings.ToLower(domain) + `\` + strings.ToLower(username)
+}
+
+// Replace sets the accounts of a hashlist. Accounts that were already flagged
+// as cracked keep their flag, so replacing the metadata does not alert again.
+func (r *HashlistAccountRepository) Replace(ctx context.Context, hashlistID int64, accounts []models.HashlistAccount) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT domain, username, cracked_at
+		FROM hashlist_accounts
+		WHERE hashlist_id = $1 AND cracked_at IS NOT NULL`, hashlistID)
+	if err != nil {
+		return fmt.Errorf("failed to get flagged accounts: %w", err)
+	}
+	flagged := map[string]time.Time{}
+	for rows.Next() {
+		var domain, username string
+		var crackedAt time.Time
+		if err := rows.Scan(&domain, &username, &crackedAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan flagged account: %w", err)
+		}
+		flagged[accountKey(domain, username)] = crackedAt
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating flagged accounts: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM hashlist_accounts WHERE hashlist_id = $1`, hashlistID); err != nil {
+		return fmt.Errorf("failed to clear hashlist accounts: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO hashlist_accounts (hashlist_id, username, domain, groups, privileged, cracked_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare account insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, account := range accounts {
+		var crackedAt *time.Time
+		if t, ok := flagged[accountKey(account.Domain, account.Username)]; ok && account.Privileged {
+			crackedAt = &t
+		}
+		if _, err := stmt.ExecContext(ctx, hashlistID, account.Username, account.Domain,
+			pq.Array(account.Groups), account.Privileged, crackedAt); err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+				return fmt.Errorf("account %s is listed twice: %w", account.QualifiedName(), ErrDuplicateRecord)
+			}
+			return fmt.Errorf("failed to insert account %s: %w", account.QualifiedName(), err)
+		}
+	}
+	return tx.Commit()
+}
+
+// List returns the accounts of a hashlist, privileged ones first
+func (r *HashlistAccountRepository) List(ctx context.Context, hashlistID int64) ([]models.HashlistAccount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT hashlist_id, username, domain, groups, privileged, cracked_at
+		FROM hashlist_accounts
+		WHERE hashlist_id = $1
+		ORDER BY privileged DESC, LOWER(domain), LOWER(username)`, hashlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hashlist accounts: %w", err)
+	}
+	defer rows.Close()
+	return scanHashlistAccounts(rows)
+}
+
+// ClaimCrackedPrivileged flags the privileged accounts of a hashlist whose
+// hash is cracked and that were not flagged before, and returns them. An
+// account without a domain matches hashes of any domain.
+func (r *HashlistAccountRepository) ClaimCrackedPrivileged(ctx context.Context, q Querier, hashlistID int64) ([]models.HashlistAccount, error) {
+	rows, err := q.QueryContext(ctx, `
+		UPDATE hashlist_accounts a
+		SET cracked_at = NOW()
+		WHERE a.hashlist_id = $1
+		  AND a.privileged
+		  AND a.cracked_at IS NULL
+		  AND EXISTS (
+			SELECT 1
+			FROM hashlist_hashes hh
+			JOIN hashes h ON hh.hash_id = h.id
+			WHERE hh.hashlist_id = a.hashlist_id
+			  AND h.is_cracked
+			  AND LOWER(h.username) = LOWER(a.username)
+			  AND (a.domain = '' OR LOWER(COALESCE(h.domain, '')) = LOWER(a.domain))
+		  )
+		RETURNING a.hashlist_id, a.username, a.domain, a.groups, a.privileged, a.cracked_at`, hashlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to flag cracked privileged accounts: %w", err)
+	}
+	defer rows.Close()
+	return scanHashlistAccounts(rows)
+}
+
+// AddHashlistTag adds a tag to a hashlist unless it already has it or is at
+// the tag limit. Reports whether the tag was added.
+func (r *HashlistAccountRepository) AddHashlistTag(ctx context.Context, q Querier, hashlistID int64, tag string) (bool, error) {
+	result, err := q.ExecContext(ctx, `
+		UPDATE hashlists
+		SET tags = ARRAY(SELECT DISTINCT t FROM unnest(array_append(tags, $1::text)) AS t ORDER BY t),
+		    updated_at = NOW()
+		WHERE id = $2
+		  AND deleted_at IS NULL
+		  AND NOT ($1 = ANY(tags))
+		  AND cardinality(tags) < $3`, tag, hashlistID, models.MaxTags)
+	if err != nil {
+		return false, fmt.Errorf("failed to tag hashlist %d: %w", hashlistID, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+type hashlistAccountRows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+func scanHashlistAccounts(rows hashlistAccountRows) ([]models.HashlistAccount, error) {
+	accounts := []models.HashlistAccount{}
+	for rows.Next() {
+		var account models.HashlistAccount
+		var groups pq.StringArray
+		if err := rows.Scan(&account.HashlistID, &account.Username, &account.Domain, &groups,
+			&account.Privileged, &account.CrackedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan hashlist account: %w", err)
+		}
+		account.Groups = []string(groups)
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hashlist accounts: %w", err)
+	}
+	return accounts, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// HashlistQuarantineRepository handles database operations for lines rejected during hashlist parsing.
+type HashlistQuarantineRepository struct {
+	db *db.DB
+}
+
+// NewHashlistQuarantineRepository creates a new instance of HashlistQuarantineRepository.
+func NewHashlistQuarantineRepository(database *db.DB) *HashlistQuarantineRepository {
+	return &HashlistQuarantineRepository{db: database}
+}
+
+// AddBatch stores rejected lines for a hashlist.
+func (r *HashlistQuarantineRepository) AddBatch(ctx context.Context, lines []*models.QuarantinedLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	hashlistIDs := make([]int64, len(lines))
+	lineNumbers := make([]int64, len(lines))
+	contents := make([]string, len(lines))
+	reasons := make([]string, len(lines))
+	for i, l := range lines {
+		hashlistIDs[i] = l.HashlistID
+		lineNumbers[i] = int64(l.LineNumber)
+		contents[i] = l.LineContent
+		reasons[i] = l.ErrorReason
+	}
+
+	query := `
+		INSERT INTO hashlist_quarantine (hashlist_id, line_number, line_content, error_reason)
+		SELECT * FROM UNNEST($1::bigint[], $2::int[], $3::text[], $4::text[])
+	`
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(hashlistIDs), pq.Array(lineNumbers), pq.Array(contents), pq.Array(reasons)); err != nil {
+		return fmt.Errorf("failed to quarantine %d lines: %w", len(lines), err)
+	}
+	return nil
+}
+
+// ListByHashlist retrieves a page of quarantined lines for a hashlist along with the total count.
+// A limit of 0 returns all lines.
+func (r *HashlistQuarantineRepository) ListByHashlist(ctx context.Context, hashlistID int64, limit, offset int) ([]models.QuarantinedLine, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM hashlist_quarantine WHERE hashlist_id = $1`, hashlistID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count quarantined lines for hashlist %d: %w", hashlistID, err)
+	}
+
+	query := `
+		SELECT id, hashlist_id, line_number, line_content, error_reason, created_at
+		FROM hashlist_quarantine
+		WHERE hashlist_id = $1
+		ORDER BY line_number, id
+	`
+	args := []interface{}{hashlistID}
+	if limit > 0 {
+		query += ` LIMIT $2 OFFSET $3`
+		args = append(args, limit, offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list quarantined lines for hashlist %d: %w", hashlistID, err)
+	}
+	defer rows.Close()
+
+	lines := []models.QuarantinedLine{}
+	for rows.Next() {
+		var l models.QuarantinedLine
+		if err := rows.Scan(&l.ID, &l.HashlistID, &l.LineNumber, &l.LineContent, &l.ErrorReason, &l.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan quarantined line: %w", err)
+		}
+		lines = append(lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating quarantined lines: %w", err)
+	}
+	return lines, total, nil
+}
+
+// CountByHashlist returns the number of quarantined lines for a hashlist.
+func (r *HashlistQuarantineRepository) CountByHashlist(ctx context.Context, hashlistID int64) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM hashlist_quarantine WHERE hashlist_id = $1`, hashlistID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count quarantined lines for hashlist %d: %w", hashlistID, err)
+	}
+	return count, nil
+}
+
+// DeleteByHashlist removes all quarantined lines for a hashlist and returns how many were removed.
+func (r *HashlistQuarantineRepository) DeleteByHashlist(ctx context.Context, hashlistID int64) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM hashlist_quarantine WHERE hashlist_id = $1`, hashlistID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear quarantine for hashlist %d: %w", hashlistID, err)
+	}
+	return result.RowsAffected()
+}
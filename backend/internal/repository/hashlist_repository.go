@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,8 +12,12 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// ErrHashlistHasActiveJobs is returned when trashing a hashlist that still has scheduled, pending, running or paused jobs
+var ErrHashlistHasActiveJobs = errors.New("hashlist has active jobs")
+
 // HashListRepository handles database operations for hashlists.
 type HashListRepository struct {
 	db *db.DB
@@ -27,8 +32,8 @@ func NewHashListRepository(database *db.DB) *HashListRepository {
 // It updates the hashlist.ID field with the newly generated serial ID.
 func (r *HashListRepository) Create(ctx context.Context, hashlist *models.HashList) error {
 	query := `
-		INSERT INTO hashlists (name, user_id, client_id, hash_type_id, status, exclude_from_potfile, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO hashlists (name, user_id, client_id, hash_type_id, status, exclude_from_potfile, source_upload_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id
 	`
 	var clientIDArg interface{} // Handle NULL client_id
@@ -45,6 +50,7 @@ func (r *HashListRepository) Create(ctx context.Context, hashlist *models.HashLi
 		hashlist.HashTypeID,
 		hashlist.Status,
 		hashlist.ExcludeFromPotfile,
+		hashlist.SourceUploadID,
 		hashlist.CreatedAt,
 		hashlist.UpdatedAt,
 	)
@@ -144,18 +150,30 @@ func (r *HashListRepository) UpdateClientID(ctx context.Context, id int64, clien
 	return nil
 }
 
-// GetByID retrieves a hashlist by its ID.
+// GetByID retrieves a hashlist by its ID. Hashlists in the trash are reported as not found.
 func (r *HashListRepository) GetByID(ctx context.Context, id int64) (*models.HashList, error) {
+	return r.getByID(ctx, id, false)
+}
+
+// GetByIDIncludingDeleted retrieves a hashlist by its ID even if it is in the trash.
+func (r *HashListRepository) GetByIDIncludingDeleted(ctx context.Context, id int64) (*models.HashList, error) {
+	return r.getByID(ctx, id, true)
+}
+
+func (r *HashListRepository) getByID(ctx context.Context, id int64, includeDeleted bool) (*models.HashList, error) {
 	query := `
 		SELECT
 			h.id, h.name, h.user_id, h.client_id, h.hash_type_id, h.file_path,
 			h.total_hashes, h.cracked_hashes, h.status, h.error_message,
-			h.exclude_from_potfile, h.created_at, h.updated_at,
+			h.exclude_from_potfile, h.source_upload_id, h.parent_hashlist_id, h.part_number, h.notes, h.tags, h.created_at, h.updated_at,
 			c.name AS client_name
 		FROM hashlists h
 		LEFT JOIN clients c ON h.client_id = c.id
 		WHERE h.id = $1
 	`
+	if !includeDeleted {
+		query += " AND h.deleted_at IS NULL"
+	}
 	var hashlist models.HashList
 	var clientID sql.Null[uuid.UUID] // Handle nullable client_id
 	var filePath sql.NullString       // Handle nullable file_path
@@ -172,6 +190,11 @@ func (r *HashListRepository) GetByID(ctx context.Context, id int64) (*models.Has
 		&hashlist.Status,
 		&hashlist.ErrorMessage,
 		&hashlist.ExcludeFromPotfile,
+		&hashlist.SourceUploadID,
+		&hashlist.ParentHashlistID,
+		&hashlist.PartNumber,
+		&hashlist.Notes,
+		pq.Array(&hashlist.Tags),
 		&hashlist.CreatedAt,
 		&hashlist.UpdatedAt,
 		&clientName,
@@ -202,6 +225,9 @@ type ListHashlistsParams struct {
 	NameLike *string // For searching by name pattern
 	Limit    int
 	Offset   int
+	OrderBy  string // Whitelisted ORDER BY expression; defaults to newest first
+	// SourceUploadID limits the list to the hashlists split from one upload
+	SourceUploadID *uuid.UUID
 }
 
 func (r *HashListRepository) List(ctx context.Context, params ListHashlistsParams) ([]models.HashList, int, error) {
@@ -211,7 +237,7 @@ func (r *HashListRepository) List(ctx context.Context, params ListHashlistsParam
 		SELECT
 			h.id, h.name, h.user_id, h.client_id, h.hash_type_id,
 			h.file_path, h.total_hashes, h.cracked_hashes, h.status,
-			h.error_message, h.exclude_from_potfile, h.created_at, h.updated_at,
+			h.error_message, h.exclude_from_potfile, h.source_upload_id, h.parent_hashlist_id, h.part_number, h.notes, h.tags, h.created_at, h.updated_at,
 			c.name AS client_name
 		FROM hashlists h
 		LEFT JOIN clients c ON h.client_id = c.id
@@ -219,7 +245,8 @@ func (r *HashListRepository) List(ctx context.Context, params ListHashlistsParam
 	// Count needs to consider the same join and filters
 	countQuery := `SELECT COUNT(h.id) FROM hashlists h LEFT JOIN clients c ON h.client_id = c.id`
 
-	conditions := []string{}
+	// Hashlists in the trash are never listed, sub-lists are listed with their parent
+	conditions := []string{"h.deleted_at IS NULL", "h.parent_hashlist_id IS NULL"}
 	args := []interface{}{}
 	argID := 1
 
@@ -247,6 +274,11 @@ func (r *HashListRepository) List(ctx context.Context, params ListHashlistsParam
 		args = append(args, "%"+*params.NameLike+"%") // Add wildcards for ILIKE
 		argID++
 	}
+	if params.SourceUploadID != nil {
+		conditions = append(conditions, fmt.Sprintf("h.source_upload_id = $%d", argID))
+		args = append(args, *params.SourceUploadID)
+		argID++
+	}
 	// TODO: Add filtering by client_name if needed in the future?
 	// if params.ClientNameLike != nil { ... }
 
@@ -274,8 +306,12 @@ func (r *HashListRepository) List(ctx context.Context, params ListHashlistsParam
 
 	// Construct final query with ordering and pagination
 	finalQuery := baseQuery + whereClause
-	// Order by h.created_at
-	finalQuery += " ORDER BY h.created_at DESC"
+	// Order by the requested column, falling back to h.created_at
+	if params.OrderBy != "" {
+		finalQuery += " ORDER BY " + params.OrderBy + ", h.created_at DESC"
+	} else {
+		finalQuery += " ORDER BY h.created_at DESC"
+	}
 
 	if params.Limit > 0 {
 		finalQuery += fmt.Sprintf(" LIMIT $%d", argID)
@@ -317,6 +353,11 @@ func (r *HashListRepository) List(ctx context.Context, params ListHashlistsParam
 			&hashlist.Status,
 			&hashlist.ErrorMessage,
 			&hashlist.ExcludeFromPotfile,
+			&hashlist.SourceUploadID,
+			&hashlist.ParentHashlistID,
+			&hashlist.PartNumber,
+			&hashlist.Notes,
+			pq.Array(&hashlist.Tags),
 			&hashlist.CreatedAt,
 			&hashlist.UpdatedAt,
 			&clientName, // Scan into nullable string
@@ -354,7 +395,8 @@ func (r *HashListRepository) List(ctx context.Context, params ListHashlistsParam
 	return hashlists, totalCount, nil
 }
 
-// GetByClientID retrieves all hashlists associated with a specific client ID.
+// GetByClientID retrieves all hashlists associated with a specific client ID,
+// including any that are in the trash.
 func (r *HashListRepository) GetByClientID(ctx context.Context, clientID uuid.UUID) ([]models.HashList, error) {
 	query := `
 		SELECT id, name, user_id, client_id, hash_type_id, file_path, total_hashes, cracked_hashes, status, error_message, created_at, updated_at
@@ -535,10 +577,11 @@ func (r *HashListRepository) IncrementCrackedCount(ctx context.Context, id int64
 	if count <= 0 {
 		return nil // Nothing to increment
 	}
+	// Cracks in a sub-list also count towards the hashlist it was split from
 	query := `
 		UPDATE hashlists
 		SET cracked_hashes = cracked_hashes + $1, updated_at = $2
-		WHERE id = $3
+		WHERE id = $3 OR id = (SELECT parent_hashlist_id FROM hashlists WHERE id = $3)
 	`
 	result, err := r.db.ExecContext(ctx, query, count, time.Now(), id)
 	if err != nil {
@@ -557,7 +600,9 @@ func (r *HashListRepository) IncrementCrackedCount(ctx context.Context, id int64
 
 // IncrementCrackedCountTx atomically increments the cracked hashes count for a hashlist within a transaction.
 func (r *HashListRepository) IncrementCrackedCountTx(tx *sql.Tx, id int64, count int) error {
-	query := `UPDATE hashlists SET cracked_hashes = cracked_hashes + $1, updated_at = $2 WHERE id = $3`
+	query := `
+		UPDATE hashlists SET cracked_hashes = cracked_hashes + $1, updated_at = $2
+		WHERE id = $3 OR id = (SELECT parent_hashlist_id FROM hashlists WHERE id = $3)`
 	_, err := tx.Exec(query, count, time.Now(), id) // Use tx.Exec instead of r.db.ExecContext
 	if err != nil {
 		return fmt.Errorf("failed to increment cracked count for hashlist %d within transaction: %w", id, err)
@@ -625,3 +670,89 @@ func (r *HashListRepository) IsExcludedFromPotfile(ctx context.Context, hashlist
 	}
 	return excluded, nil
 }
+
+// SoftDelete moves a hashlist to the trash. It fails with ErrHashlistHasActiveJobs
+// while any non-trashed job on the hashlist is scheduled, pending, running or paused.
+func (r *HashListRepository) SoftDelete(ctx context.Context, id int64, deletedBy *uuid.UUID) error {
+	query := `
+		UPDATE hashlists
+		SET deleted_at = $2, deleted_by = $3
+		WHERE id = $1 AND deleted_at IS NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM job_executions
+			WHERE hashlist_id = $1 AND deleted_at IS NULL
+			  AND status IN ('scheduled', 'pending', 'running', 'paused')
+		  )
+	`
+	result, err := r.db.ExecContext(ctx, query, id, time.Now(), deletedBy)
+	if err != nil {
+		return fmt.Errorf("failed to move hashlist %d to trash: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	// Work out why nothing was updated
+	var hasActiveJobs bool
+	err = r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM job_executions
+			WHERE hashlist_id = $1 AND deleted_at IS NULL
+			  AND status IN ('scheduled', 'pending', 'running', 'paused')
+		)
+		FROM hashlists WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&hasActiveJobs)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("hashlist %d not found for deletion: %w", id, ErrNotFound)
+		}
+		return fmt.Errorf("failed to check active jobs for hashlist %d: %w", id, err)
+	}
+	if hasActiveJobs {
+		return ErrHashlistHasActiveJobs
+	}
+	return fmt.Errorf("hashlist %d not found for deletion: %w", id, ErrNotFound)
+}
+
+// Restore takes a hashlist out of the trash.
+func (r *HashListRepository) Restore(ctx context.Context, id int64) error {
+	query := `UPDATE hashlists SET deleted_at = NULL, deleted_by = NULL, updated_at = $2 WHERE id = $1 AND deleted_at IS NOT NULL`
+	result, err := r.db.ExecContext(ctx, query, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to restore hashlist %d: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("hashlist %d not found in trash: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// UpdateAnnotations replaces the notes and tags of a hashlist
+func (r *HashListRepository) UpdateAnnotations(ctx context.Context, id int64, annotations models.Annotations) error {
+	query := `
+		UPDATE hashlists
+		SET notes = $1, tags = $2, updated_at = $3
+		WHERE id = $4 AND deleted_at IS NULL
+	`
+	result, err := r.db.ExecContext(ctx, query, annotations.Notes, pq.Array(annotations.Tags), time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update annotations of hashlist %d: %w", id, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check annotation update of hashlist %d: %w", id, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("hashlist %d not found for annotation update: %w", id, ErrNotFound)
+	}
+	return nil
+}
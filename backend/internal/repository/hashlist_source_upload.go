@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// CreateSourceUpload records an upload that is split into several hashlists.
+// It fills in the upload's ID and creation time.
+func (r *HashListRepository) CreateSourceUpload(ctx context.Context, upload *models.HashlistSourceUpload) error {
+	query := `
+		INSERT INTO hashlist_source_uploads (file_name, user_id, client_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRowContext(ctx, query, upload.FileName, upload.UserID, upload.ClientID).
+		Scan(&upload.ID, &upload.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create hashlist source upload: %w", err)
+	}
+	return nil
+}
+
+// GetSourceUpload retrieves a source upload without its hashlists
+func (r *HashListRepository) GetSourceUpload(ctx context.Context, id uuid.UUID) (*models.HashlistSourceUpload, error) {
+	query := `
+		SELECT id, file_name, user_id, client_id, created_at
+		FROM hashlist_source_uploads
+		WHERE id = $1
+	`
+	upload := &models.HashlistSourceUpload{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&upload.ID,
+		&upload.FileName,
+		&upload.UserID,
+		&upload.ClientID,
+		&upload.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("hashlist source upload %s not found: %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get hashlist source upload %s: %w", id, err)
+	}
+	return upload, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// ErrHashlistNotStaged is returned when committing or discarding a hashlist that is not staged
+var ErrHashlistNotStaged = errors.New("hashlist is not staged")
+
+// SaveStagingPreview stores the parse statistics of a staged hashlist
+func (r *HashListRepository) SaveStagingPreview(ctx context.Context, hashlistID int64, preview *models.HashlistStagingPreview) error {
+	data, err := json.Marshal(preview)
+	if err != nil {
+		return fmt.Errorf("failed to encode staging preview: %w", err)
+	}
+
+	query := `
+		INSERT INTO hashlist_staging_previews (hashlist_id, preview)
+		VALUES ($1, $2)
+		ON CONFLICT (hashlist_id) DO UPDATE SET preview = EXCLUDED.preview, created_at = CURRENT_TIMESTAMP
+	`
+	if _, err := r.db.ExecContext(ctx, query, hashlistID, data); err != nil {
+		return fmt.Errorf("failed to save staging preview of hashlist %d: %w", hashlistID, err)
+	}
+	return nil
+}
+
+// GetStagingPreview returns the parse statistics of a staged hashlist
+func (r *HashListRepository) GetStagingPreview(ctx context.Context, hashlistID int64) (*models.HashlistStagingPreview, error) {
+	var data []byte
+	err := r.db.QueryRowContext(ctx, `SELECT preview FROM hashlist_staging_previews WHERE hashlist_id = $1`, hashlistID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staging preview of hashlist %d: %w", hashlistID, err)
+	}
+
+	var preview models.HashlistStagingPreview
+	if err := json.Unmarshal(data, &preview); err != nil {
+		return nil, fmt.Errorf("failed to decode staging preview of hashlist %d: %w", hashlistID, err)
+	}
+	return &preview, nil
+}
+
+// CommitStaged moves a staged hashlist to processing and drops its preview
+func (r *HashListRepository) CommitStaged(ctx context.Context, hashlistID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE hashlists SET status = $2, updated_at = NOW() WHERE id = $1 AND status = $3 AND deleted_at IS NULL`,
+		hashlistID, models.HashListStatusProcessing, models.HashListStatusStaged)
+	if err != nil {
+		return fmt.Errorf("failed to commit staged hashlist %d: %w", hashlistID, err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrHashlistNotStaged
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM hashlist_staging_previews WHERE hashlist_id = $1`, hashlistID); err != nil {
+		return fmt.Errorf("failed to delete staging preview of hashlist %d: %w", hashlistID, err)
+	}
+	return tx.Commit()
+}
+
+// DeleteStaged deletes a staged hashlist and returns the path of its upload.
+// Staged hashlists have no hashes, so nothing else needs cleaning up.
+func (r *HashListRepository) DeleteStaged(ctx context.Context, hashlistID int64) (string, error) {
+	var filePath sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		`DELETE FROM hashlists WHERE id = $1 AND status = $2 RETURNING file_path`,
+		hashlistID, models.HashListStatusStaged).Scan(&filePath)
+	if err == sql.ErrNoRows {
+		return "", ErrHashlistNotStaged
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to delete staged hashlist %d: %w", hashlistID, err)
+	}
+	return filePath.String, nil
+}
+
+// ListStagedBefore returns the IDs of hashlists staged before a time
+func (r *HashListRepository) ListStagedBefore(ctx context.Context, before time.Time) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id FROM hashlists WHERE status = $1 AND created_at < $2 ORDER BY id`,
+		models.HashListStatusStaged, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged hashlists: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan staged hashlist: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CountUniqueHashValues counts the distinct hash values of a hashlist, which
+// is what hashcat loads when it runs against the list
+func (r *HashListRepository) CountUniqueHashValues(ctx context.Context, hashlistID int64) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT h.hash_value)
+		FROM hashlist_hashes hh
+		JOIN hashes h ON h.id = hh.hash_id
+		WHERE hh.hashlist_id = $1`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, hashlistID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unique hash values of hashlist %d: %w", hashlistID, err)
+	}
+	return count, nil
+}
+
+// CreateSubLists splits a hashlist into sub-lists of at most maxHashes unique
+// hash values each. The parent keeps all its hashes; every sub-list links a
+// share of them, with hashes of the same value kept in the same sub-list so a
+// crack in one sub-list never leaves a copy uncracked in another. The
+// sub-lists are created with status processing and their hash counts set.
+func (r *HashListRepository) CreateSubLists(ctx context.Context, parent *models.HashList, maxHashes int) ([]models.HashList, error) {
+	uniqueHashes, err := r.CountUniqueHashValues(ctx, parent.ID)
+	if err != nil {
+		return nil, err
+	}
+	parts := models.SubListCount(uniqueHashes, maxHashes)
+	if parts == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction for sub-lists of hashlist %d: %w", parent.ID, err)
+	}
+	defer tx.Rollback()
+
+	var clientIDArg interface{}
+	if parent.ClientID != uuid.Nil {
+		clientIDArg = parent.ClientID
+	}
+
+	now := time.Now()
+	subLists := make([]models.HashList, 0, parts)
+	ids := make([]int64, 0, parts)
+	for part := 1; part <= parts; part++ {
+		partNumber := part
+		parentID := parent.ID
+		subList := models.HashList{
+			Name:               models.SubListName(parent.Name, part, parts),
+			UserID:             parent.UserID,
+			ClientID:           parent.ClientID,
+			ClientName:         parent.ClientName,
+			HashTypeID:         parent.HashTypeID,
+			Status:             models.HashListStatusProcessing,
+			ExcludeFromPotfile: parent.ExcludeFromPotfile,
+			ParentHashlistID:   &parentID,
+			PartNumber:         &partNumber,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		}
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO hashlists (name, user_id, client_id, hash_type_id, status, exclude_from_potfile,
+				parent_hashlist_id, part_number, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id`,
+			subList.Name, subList.UserID, clientIDArg, subList.HashTypeID, subList.Status, subList.ExcludeFromPotfile,
+			parent.ID, part, now, now,
+		).Scan(&subList.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sub-list %d of hashlist %d: %w", part, parent.ID, err)
+		}
+		subLists = append(subLists, subList)
+		ids = append(ids, subList.ID)
+	}
+
+	// Number the distinct values in order and hand them out in runs of maxHashes
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO hashlist_hashes (hashlist_id, hash_id)
+		SELECT ($2::bigint[])[(v.value_rank - 1) / $3 + 1], hh.hash_id
+		FROM hashlist_hashes hh
+		JOIN hashes h ON h.id = hh.hash_id
+		JOIN (
+			SELECT hash_value, DENSE_RANK() OVER (ORDER BY hash_value) AS value_rank
+			FROM (
+				SELECT DISTINCT h2.hash_value
+				FROM hashlist_hashes hh2
+				JOIN hashes h2 ON h2.id = hh2.hash_id
+				WHERE hh2.hashlist_id = $1
+			) d
+		) v ON v.hash_value = h.hash_value
+		WHERE hh.hashlist_id = $1`,
+		parent.ID, pq.Array(ids), maxHashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link hashes to sub-lists of hashlist %d: %w", parent.ID, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE hashlists s SET
+			total_hashes = c.total,
+			cracked_hashes = c.cracked
+		FROM (
+			SELECT hh.hashlist_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE h.is_cracked) AS cracked
+			FROM hashlist_hashes hh
+			JOIN hashes h ON h.id = hh.hash_id
+			WHERE hh.hashlist_id = ANY($1::bigint[])
+			GROUP BY hh.hashlist_id
+		) c
+		WHERE s.id = c.hashlist_id
+		RETURNING s.id, s.total_hashes, s.cracked_hashes`,
+		pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count hashes of sub-lists of hashlist %d: %w", parent.ID, err)
+	}
+	counts := make(map[int64][2]int, parts)
+	for rows.Next() {
+		var id int64
+		var total, cracked int
+		if err := rows.Scan(&id, &total, &cracked); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan sub-list hash counts: %w", err)
+		}
+		counts[id] = [2]int{total, cracked}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sub-list hash counts: %w", err)
+	}
+	for i := range subLists {
+		subLists[i].TotalHashes = counts[subLists[i].ID][0]
+		subLists[i].CrackedHashes = counts[subLists[i].ID][1]
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit sub-lists of hashlist %d: %w", parent.ID, err)
+	}
+	return subLists, nil
+}
+
+// ListSubLists returns the sub-lists a hashlist was split into, in part order.
+// A hashlist that was not split has none.
+func (r *HashListRepository) ListSubLists(ctx context.Context, parentID int64) ([]models.HashList, error) {
+	query := `
+		SELECT id, name, hash_type_id, total_hashes, cracked_hashes, status, part_number, created_at, updated_at
+		FROM hashlists
+		WHERE parent_hashlist_id = $1 AND deleted_at IS NULL
+		ORDER BY part_number`
+
+	rows, err := r.db.QueryContext(ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sub-lists of hashlist %d: %w", parentID, err)
+	}
+	defer rows.Close()
+
+	subLists := []models.HashList{}
+	for rows.Next() {
+		subList := models.HashList{ParentHashlistID: &parentID}
+		if err := rows.Scan(&subList.ID, &subList.Name, &subList.HashTypeID, &subList.TotalHashes,
+			&subList.CrackedHashes, &subList.Status, &subList.PartNumber, &subList.CreatedAt, &subList.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan sub-list of hashlist %d: %w", parentID, err)
+		}
+		subLists = append(subLists, subList)
+	}
+	return subLists, rows.Err()
+}
@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrJobNotArchivable is returned when a job execution is still active and cannot be archived
+var ErrJobNotArchivable = errors.New("job execution is not in a finished state")
+
+// ErrArchiveHashlistMissing is returned when restoring a job whose hashlist no
+// longer exists or is in the trash
+var ErrArchiveHashlistMissing = errors.New("hashlist for archived job no longer exists")
+
+// jobArchivePayload is the compressed body of an archive row
+type jobArchivePayload struct {
+	Execution json.RawMessage `json:"execution"`
+	Tasks     json.RawMessage `json:"tasks"`
+	Metrics   json.RawMessage `json:"metrics"`
+}
+
+// JobArchiveRepository handles moving job executions in and out of the archive table
+type JobArchiveRepository struct {
+	db *db.DB
+}
+
+// NewJobArchiveRepository creates a new job archive repository
+func NewJobArchiveRepository(database *db.DB) *JobArchiveRepository {
+	return &JobArchiveRepository{db: database}
+}
+
+// GetArchivableJobIDs returns finished job executions that completed before the cutoff
+func (r *JobArchiveRepository) GetArchivableJobIDs(ctx context.Context, cutoff time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM job_executions
+		WHERE status IN ('completed', 'failed', 'cancelled', 'superseded')
+		AND COALESCE(completed_at, updated_at) < $1
+		AND deleted_at IS NULL -- Trashed jobs are left to the trash purge
+		ORDER BY COALESCE(completed_at, updated_at) ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archivable job executions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan job execution id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Archive compresses a finished job execution with its tasks and job metrics
+// into job_execution_archives and removes the live rows.
+func (r *JobArchiveRepository) Archive(ctx context.Context, jobExecutionID uuid.UUID, archivedBy *uuid.UUID) (*models.JobExecutionArchive, error) {
+	archive := &models.JobExecutionArchive{ID: jobExecutionID, ArchivedBy: archivedBy}
+
+	err := r.db.WithTx(ctx, func(tx *sql.Tx) error {
+		var payload jobArchivePayload
+		err := tx.QueryRowContext(ctx, `
+			SELECT row_to_json(je), COALESCE(je.name, ''), je.hashlist_id, je.status, je.attack_mode,
+				je.hash_type, je.priority, je.created_by, je.total_keyspace, je.processed_keyspace,
+				je.created_at, je.started_at, je.completed_at
+			FROM job_executions je
+			WHERE je.id = $1
+			FOR UPDATE`, jobExecutionID).Scan(
+			&payload.Execution, &archive.Name, &archive.HashlistID, &archive.Status, &archive.AttackMode,
+			&archive.HashType, &archive.Priority, &archive.CreatedBy, &archive.TotalKeyspace, &archive.ProcessedKeyspace,
+			&archive.CreatedAt, &archive.StartedAt, &archive.CompletedAt,
+		)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load job execution: %w", err)
+		}
+
+		switch models.JobExecutionStatus(archive.Status) {
+		case models.JobExecutionStatusCompleted, models.JobExecutionStatusFailed, models.JobExecutionStatusCancelled,
+			models.JobExecutionStatusSuperseded:
+		default:
+			return ErrJobNotArchivable
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			SELECT COALESCE(json_agg(row_to_json(jt)), '[]'::json), COUNT(*), COALESCE(SUM(jt.crack_count), 0)
+			FROM job_tasks jt
+			WHERE jt.job_execution_id = $1`, jobExecutionID).Scan(&payload.Tasks, &archive.TaskCount, &archive.CrackCount)
+		if err != nil {
+			return fmt.Errorf("failed to load job tasks: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			SELECT COALESCE(json_agg(row_to_json(m)), '[]'::json)
+			FROM job_performance_metrics m
+			WHERE m.job_execution_id = $1`, jobExecutionID).Scan(&payload.Metrics)
+		if err != nil {
+			return fmt.Errorf("failed to load job metrics: %w", err)
+		}
+
+		compressed, err := compressArchivePayload(payload)
+		if err != nil {
+			return err
+		}
+		archive.PayloadSizeBytes = int64(len(compressed))
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO job_execution_archives (
+				id, name, hashlist_id, status, attack_mode, hash_type, priority, created_by,
+				total_keyspace, processed_keyspace, task_count, crack_count,
+				created_at, started_at, completed_at, payload, payload_size_bytes, archived_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+			RETURNING archived_at`,
+			archive.ID, archive.Name, archive.HashlistID, archive.Status, archive.AttackMode, archive.HashType,
+			archive.Priority, archive.CreatedBy, archive.TotalKeyspace, archive.ProcessedKeyspace,
+			archive.TaskCount, archive.CrackCount, archive.CreatedAt, archive.StartedAt, archive.CompletedAt,
+			compressed, archive.PayloadSizeBytes, archive.ArchivedBy,
+		).Scan(&archive.ArchivedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert job archive: %w", err)
+		}
+
+		// interrupted_by has no ON DELETE action, so detach jobs that point at this one
+		if _, err := tx.ExecContext(ctx, `UPDATE job_executions SET interrupted_by = NULL WHERE interrupted_by = $1`, jobExecutionID); err != nil {
+			return fmt.Errorf("failed to detach interrupted jobs: %w", err)
+		}
+
+		// Tasks and job metrics are removed by ON DELETE CASCADE
+		if _, err := tx.ExecContext(ctx, `DELETE FROM job_executions WHERE id = $1`, jobExecutionID); err != nil {
+			return fmt.Errorf("failed to delete archived job execution: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// Restore decompresses an archived job execution back into the live tables and
+// removes the archive row. References to agents, users, preset jobs,
+// campaigns, other jobs or tasks that no longer exist are cleared rather than
+// failing the restore, and columns added
+// since the archive was written take their table default. A job whose
+// hashlist was deleted or trashed is not restored.
+func (r *JobArchiveRepository) Restore(ctx context.Context, jobExecutionID uuid.UUID) error {
+	return r.db.WithTx(ctx, func(tx *sql.Tx) error {
+		var compressed []byte
+		var hashlistID sql.NullInt64
+		err := tx.QueryRowContext(ctx, `
+			SELECT payload, hashlist_id FROM job_execution_archives WHERE id = $1 FOR UPDATE`,
+			jobExecutionID).Scan(&compressed, &hashlistID)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load job archive: %w", err)
+		}
+
+		var hashlistExists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM hashlists WHERE id = $1 AND deleted_at IS NULL)`, hashlistID).Scan(&hashlistExists); err != nil {
+			return fmt.Errorf("failed to check hashlist: %w", err)
+		}
+		if !hashlistExists {
+			return ErrArchiveHashlistMissing
+		}
+
+		payload, err := decompressArchivePayload(compressed)
+		if err != nil {
+			return err
+		}
+
+		statements := []struct {
+			query string
+			args  []interface{}
+			// Live table whose defaults fill the NULL columns of the temp table just created
+			defaultsOf string
+		}{
+			{`CREATE TEMP TABLE restore_job_executions ON COMMIT DROP AS
+				SELECT * FROM json_populate_record(NULL::job_executions, $1::json)`, []interface{}{string(payload.Execution)}, "job_executions"},
+			{`UPDATE restore_job_executions SET interrupted_by = NULL`, nil, ""},
+			{`UPDATE restore_job_executions SET preset_job_id = NULL
+				WHERE preset_job_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM preset_jobs p WHERE p.id = preset_job_id)`, nil, ""},
+			{`UPDATE restore_job_executions SET created_by = NULL
+				WHERE created_by IS NOT NULL AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = created_by)`, nil, ""},
+			{`UPDATE restore_job_executions SET deleted_by = NULL
+				WHERE deleted_by IS NOT NULL AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = deleted_by)`, nil, ""},
+			{`UPDATE restore_job_executions SET depends_on_job_id = NULL
+				WHERE depends_on_job_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM job_executions d WHERE d.id = depends_on_job_id)`, nil, ""},
+			{`UPDATE restore_job_executions SET campaign_id = NULL
+				WHERE campaign_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM job_campaigns c WHERE c.id = campaign_id)`, nil, ""},
+			{`INSERT INTO job_executions SELECT * FROM restore_job_executions`, nil, ""},
+			{`CREATE TEMP TABLE restore_job_tasks ON COMMIT DROP AS
+				SELECT * FROM json_populate_recordset(NULL::job_tasks, $1::json)`, []interface{}{string(payload.Tasks)}, "job_tasks"},
+			{`UPDATE restore_job_tasks SET agent_id = NULL
+				WHERE agent_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM agents a WHERE a.id = agent_id)`, nil, ""},
+			{`UPDATE restore_job_tasks SET binary_version_id = NULL
+				WHERE binary_version_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM binary_versions b WHERE b.id = binary_version_id)`, nil, ""},
+			// Tasks may refer to other tasks of the same job, restored alongside them
+			{`UPDATE restore_job_tasks r SET reused_from = NULL
+				WHERE reused_from IS NOT NULL
+					AND NOT EXISTS (SELECT 1 FROM job_tasks t WHERE t.id = r.reused_from)
+					AND NOT EXISTS (SELECT 1 FROM restore_job_tasks t WHERE t.id = r.reused_from)`, nil, ""},
+			{`UPDATE restore_job_tasks r SET speculative_of = NULL
+				WHERE speculative_of IS NOT NULL
+					AND NOT EXISTS (SELECT 1 FROM job_tasks t WHERE t.id = r.speculative_of)
+					AND NOT EXISTS (SELECT 1 FROM restore_job_tasks t WHERE t.id = r.speculative_of)`, nil, ""},
+			{`UPDATE restore_job_tasks r SET verification_of = NULL
+				WHERE verification_of IS NOT NULL
+					AND NOT EXISTS (SELECT 1 FROM job_tasks t WHERE t.id = r.verification_of)
+					AND NOT EXISTS (SELECT 1 FROM restore_job_tasks t WHERE t.id = r.verification_of)`, nil, ""},
+			{`INSERT INTO job_tasks SELECT * FROM restore_job_tasks`, nil, ""},
+			{`CREATE TEMP TABLE restore_job_performance_metrics ON COMMIT DROP AS
+				SELECT * FROM json_populate_recordset(NULL::job_performance_metrics, $1::json)`, []interface{}{string(payload.Metrics)}, "job_performance_metrics"},
+			{`INSERT INTO job_performance_metrics SELECT * FROM restore_job_performance_metrics`, nil, ""},
+			{`DELETE FROM job_execution_archives WHERE id = $1`, []interface{}{jobExecutionID}, ""},
+		}
+
+		for _, stmt := range statements {
+			if _, err := tx.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+				return fmt.Errorf("failed to restore job execution %s: %w", jobExecutionID, err)
+			}
+			if stmt.defaultsOf == "" {
+				continue
+			}
+			if err := fillRestoreDefaults(ctx, tx, "restore_"+stmt.defaultsOf, stmt.defaultsOf); err != nil {
+				return fmt.Errorf("failed to restore job execution %s: %w", jobExecutionID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves the summary of an archived job execution
+func (r *JobArchiveRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.JobExecutionArchive, error) {
+	row := r.db.QueryRowContext(ctx, jobArchiveSelect+` WHERE id = $1`, id)
+	archive, err := scanJobArchive(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job archive: %w", err)
+	}
+	return archive, nil
+}
+
+// List retrieves archived job summaries, newest archive first, optionally filtered by hashlist
+func (r *JobArchiveRepository) List(ctx context.Context, hashlistID *int64, limit, offset int) ([]models.JobExecutionArchive, int, error) {
+	where := ""
+	args := []interface{}{}
+	if hashlistID != nil {
+		where = " WHERE hashlist_id = $1"
+		args = append(args, *hashlistID)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM job_execution_archives`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count job archives: %w", err)
+	}
+
+	query := jobArchiveSelect + where + fmt.Sprintf(" ORDER BY archived_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list job archives: %w", err)
+	}
+	defer rows.Close()
+
+	archives := []models.JobExecutionArchive{}
+	for rows.Next() {
+		archive, err := scanJobArchive(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan job archive: %w", err)
+		}
+		archives = append(archives, *archive)
+	}
+	return archives, total, rows.Err()
+}
+
+// GetStats returns aggregate statistics over all archived job executions
+func (r *JobArchiveRepository) GetStats(ctx context.Context) (*models.JobArchiveStats, error) {
+	var stats models.JobArchiveStats
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(task_count), 0), COALESCE(SUM(crack_count), 0),
+			COALESCE(SUM(processed_keyspace), 0), COALESCE(SUM(payload_size_bytes), 0)
+		FROM job_execution_archives`).Scan(
+		&stats.ArchivedJobs, &stats.ArchivedTasks, &stats.TotalCracks,
+		&stats.ProcessedKeyspace, &stats.PayloadSizeBytes,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job archive stats: %w", err)
+	}
+	return &stats, nil
+}
+
+const jobArchiveSelect = `
+	SELECT id, COALESCE(name, ''), hashlist_id, status, attack_mode, hash_type, priority, created_by,
+		total_keyspace, processed_keyspace, task_count, crack_count,
+		created_at, started_at, completed_at, payload_size_bytes, archived_at, archived_by
+	FROM job_execution_archives`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJobArchive(row rowScanner) (*models.JobExecutionArchive, error) {
+	var a models.JobExecutionArchive
+	err := row.Scan(
+		&a.ID, &a.Name, &a.HashlistID, &a.Status, &a.AttackMode, &a.HashType, &a.Priority, &a.CreatedBy,
+		&a.TotalKeyspace, &a.ProcessedKeyspace, &a.TaskCount, &a.CrackCount,
+		&a.CreatedAt, &a.StartedAt, &a.CompletedAt, &a.PayloadSizeBytes, &a.ArchivedAt, &a.ArchivedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func compressArchivePayload(payload jobArchivePayload) ([]byte, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal archive payload: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, fmt.Errorf("failed to compress archive payload: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress archive payload: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressArchivePayload(compressed []byte) (*jobArchivePayload, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive payload: %w", err)
+	}
+	defer zr.Close()
+
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress archive payload: %w", err)
+	}
+
+	var payload jobArchivePayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode archive payload: %w", err)
+	}
+	if len(payload.Tasks) == 0 {
+		payload.Tasks = json.RawMessage("[]")
+	}
+	if len(payload.Metrics) == 0 {
+		payload.Metrics = json.RawMessage("[]")
+	}
+	return &payload, nil
+}
+
+// columnDefault is a NOT NULL column of a live table and its default expression
+type columnDefault struct {
+	name       string
+	expression string
+}
+
+// fillRestoreDefaults sets the NOT NULL columns a restore temp table left NULL
+// to the default of the live table, covering columns added after the archive
+// was written
+func fillRestoreDefaults(ctx context.Context, tx *sql.Tx, tempTable, table string) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT column_name, column_default
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1
+			AND is_nullable = 'NO' AND column_default IS NOT NULL
+		ORDER BY ordinal_position`, table)
+	if err != nil {
+		return fmt.Errorf("failed to get column defaults of %s: %w", table, err)
+	}
+	var defaults []columnDefault
+	for rows.Next() {
+		var column columnDefault
+		if err := rows.Scan(&column.name, &column.expression); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan column default of %s: %w", table, err)
+		}
+		defaults = append(defaults, column)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get column defaults of %s: %w", table, err)
+	}
+
+	query := restoreDefaultsQuery(tempTable, defaults)
+	if query == "" {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to fill column defaults of %s: %w", table, err)
+	}
+	return nil
+}
+
+// restoreDefaultsQuery builds the UPDATE replacing NULLs in tempTable with the
+// column defaults, or "" when there are none
+func restoreDefaultsQuery(tempTable string, defaults []columnDefault) string {
+	if len(defaults) == 0 {
+		return ""
+	}
+	assignments := make([]string, len(defaults))
+	for i, column := range defaults {
+		name := pq.QuoteIdentifier(column.name)
+		assignments[i] = fmt.Sprintf("%s = COALESCE(%s, %s)", name, name, column.expression)
+	}
+	return "UPDATE " + pq.QuoteIdentifier(tempTable) + " SET " + strings.Join(assignments, ", ")
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveTestJob creates a finished job execution with one task on a new
+// hashlist and archives it
+func archiveTestJob(t *testing.T, database *db.DB, repo *JobArchiveRepository) (uuid.UUID, int64) {
+	t.Helper()
+	ctx := context.Background()
+
+	user := testutil.CreateTestUser(t, database, "archiveuser", "archive@example.com", testutil.DefaultTestPassword, "user")
+	hashlist := &models.HashList{
+		Name:       "Archived job hashlist",
+		UserID:     user.ID,
+		ClientID:   uuid.Nil,
+		HashTypeID: 1000,
+		Status:     models.HashListStatusReady,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	require.NoError(t, NewHashListRepository(database).Create(ctx, hashlist))
+
+	var jobID uuid.UUID
+	err := database.QueryRowContext(ctx, `
+		INSERT INTO job_executions (hashlist_id, attack_mode, status, completed_at)
+		VALUES ($1, 0, 'completed', NOW())
+		RETURNING id`, hashlist.ID).Scan(&jobID)
+	require.NoError(t, err)
+	_, err = database.ExecContext(ctx, `
+		INSERT INTO job_tasks (job_execution_id, status, keyspace_start, keyspace_end, chunk_duration, completed_at)
+		VALUES ($1, 'completed', 0, 1000, 60, NOW())`, jobID)
+	require.NoError(t, err)
+
+	_, err = repo.Archive(ctx, jobID, nil)
+	require.NoError(t, err)
+	return jobID, hashlist.ID
+}
+
+// removeArchivedTaskField rewrites an archive as if it had been written before
+// the task column existed
+func removeArchivedTaskField(t *testing.T, database *db.DB, jobID uuid.UUID, field string) {
+	t.Helper()
+	ctx := context.Background()
+
+	var compressed []byte
+	require.NoError(t, database.QueryRowContext(ctx, `SELECT payload FROM job_execution_archives WHERE id = $1`, jobID).Scan(&compressed))
+	payload, err := decompressArchivePayload(compressed)
+	require.NoError(t, err)
+
+	var tasks []map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(payload.Tasks, &tasks))
+	for _, task := range tasks {
+		delete(task, field)
+	}
+	payload.Tasks, err = json.Marshal(tasks)
+	require.NoError(t, err)
+
+	compressed, err = compressArchivePayload(*payload)
+	require.NoError(t, err)
+	_, err = database.ExecContext(ctx, `UPDATE job_execution_archives SET payload = $2 WHERE id = $1`, jobID, compressed)
+	require.NoError(t, err)
+}
+
+// setArchivedReferences points an archived job and its tasks at rows that no
+// longer exist, as if they had been archived or purged since
+func setArchivedReferences(t *testing.T, database *db.DB, jobID uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+
+	var compressed []byte
+	require.NoError(t, database.QueryRowContext(ctx, `SELECT payload FROM job_execution_archives WHERE id = $1`, jobID).Scan(&compressed))
+	payload, err := decompressArchivePayload(compressed)
+	require.NoError(t, err)
+
+	var execution map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload.Execution, &execution))
+	execution["depends_on_job_id"] = uuid.New()
+	execution["campaign_id"] = uuid.New()
+	payload.Execution, err = json.Marshal(execution)
+	require.NoError(t, err)
+
+	var tasks []map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload.Tasks, &tasks))
+	for _, task := range tasks {
+		task["reused_from"] = uuid.New()
+		task["verification_of"] = uuid.New()
+	}
+	payload.Tasks, err = json.Marshal(tasks)
+	require.NoError(t, err)
+
+	compressed, err = compressArchivePayload(*payload)
+	require.NoError(t, err)
+	_, err = database.ExecContext(ctx, `UPDATE job_execution_archives SET payload = $2 WHERE id = $1`, jobID, compressed)
+	require.NoError(t, err)
+}
+
+func TestJobArchiveRepository_Restore_MissingReferences(t *testing.T) {
+	database := testutil.SetupTestDB(t)
+	repo := NewJobArchiveRepository(database)
+	ctx := context.Background()
+
+	jobID, _ := archiveTestJob(t, database, repo)
+	setArchivedReferences(t, database, jobID)
+
+	require.NoError(t, repo.Restore(ctx, jobID))
+
+	var dependsOn, campaign *uuid.UUID
+	err := database.QueryRowContext(ctx, `SELECT depends_on_job_id, campaign_id FROM job_executions WHERE id = $1`, jobID).Scan(&dependsOn, &campaign)
+	require.NoError(t, err)
+	assert.Nil(t, dependsOn)
+	assert.Nil(t, campaign)
+
+	var reusedFrom, verificationOf *uuid.UUID
+	err = database.QueryRowContext(ctx, `SELECT reused_from, verification_of FROM job_tasks WHERE job_execution_id = $1`, jobID).Scan(&reusedFrom, &verificationOf)
+	require.NoError(t, err)
+	assert.Nil(t, reusedFrom)
+	assert.Nil(t, verificationOf)
+}
+
+func TestJobArchiveRepository_Restore_WithoutChunkOverlap(t *testing.T) {
+	database := testutil.SetupTestDB(t)
+	repo := NewJobArchiveRepository(database)
+	ctx := context.Background()
+
+	jobID, _ := archiveTestJob(t, database, repo)
+	removeArchivedTaskField(t, database, jobID, "chunk_overlap")
+
+	require.NoError(t, repo.Restore(ctx, jobID))
+
+	var chunkOverlap int64
+	err := database.QueryRowContext(ctx, `SELECT chunk_overlap FROM job_tasks WHERE job_execution_id = $1`, jobID).Scan(&chunkOverlap)
+	require.NoError(t, err)
+	assert.Zero(t, chunkOverlap)
+}
+
+func TestRestoreDefaultsQuery(t *testing.T) {
+	assert.Empty(t, restoreDefaultsQuery("restore_job_tasks", nil))
+
+	query := restoreDefaultsQuery("restore_job_tasks", []columnDefault{
+		{name: "chunk_overlap", expression: "0"},
+		{name: "status", expression: "'pending'::character varying"},
+	})
+	assert.Equal(t, `UPDATE "restore_job_tasks" SET "chunk_overlap" = COALESCE("chunk_overlap", 0), `+
+		`"status" = COALESCE("status", 'pending'::character varying)`, query)
+}
+
+func TestJobArchiveRepository_Restore_TrashedHashlist(t *testing.T) {
+	database := testutil.SetupTestDB(t)
+	repo := NewJobArchiveRepository(database)
+	ctx := context.Background()
+
+	jobID, hashlistID := archiveTestJob(t, database, repo)
+	_, err := database.ExecContext(ctx, `UPDATE hashlists SET deleted_at = NOW() WHERE id = $1`, hashlistID)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, repo.Restore(ctx, jobID), ErrArchiveHashlistMissing)
+
+	var archived bool
+	require.NoError(t, database.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM job_execution_archives WHERE id = $1)`, jobID).Scan(&archived))
+	assert.True(t, archived)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// JobCampaignRepository handles database operations for job campaigns, one
+// job submission run against several hashlists
+type JobCampaignRepository struct {
+	db *db.DB
+}
+
+// NewJobCampaignRepository creates a new job campaign repository
+func NewJobCampaignRepository(db *db.DB) *JobCampaignRepository {
+	return &JobCampaignRepository{db: db}
+}
+
+// Create stores a campaign and links its jobs to it
+func (r *JobCampaignRepository) Create(ctx context.Context, campaign *models.JobCampaign, jobIDs []uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO job_campaigns (name, hash_type_id, hashlist_ids, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+	err = tx.QueryRowContext(ctx, query, campaign.Name, campaign.HashTypeID, campaign.HashlistIDs, campaign.CreatedBy).
+		Scan(&campaign.ID, &campaign.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create job campaign: %w", err)
+	}
+
+	query = `
+		UPDATE job_executions
+		SET campaign_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ANY($2)`
+	if _, err := tx.ExecContext(ctx, query, campaign.ID, pq.Array(jobIDs)); err != nil {
+		return fmt.Errorf("failed to link jobs to campaign: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a campaign
+func (r *JobCampaignRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.JobCampaign, error) {
+	query := `
+		SELECT id, name, hash_type_id, hashlist_ids, created_by, created_at
+		FROM job_campaigns
+		WHERE id = $1`
+
+	var campaign models.JobCampaign
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&campaign.ID, &campaign.Name, &campaign.HashTypeID,
+		&campaign.HashlistIDs, &campaign.CreatedBy, &campaign.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job campaign: %w", err)
+	}
+	return &campaign, nil
+}
+
+// GetCampaignIDOfJob returns the campaign a job was created for, nil if none
+func (r *JobCampaignRepository) GetCampaignIDOfJob(ctx context.Context, jobID uuid.UUID) (*uuid.UUID, error) {
+	var campaignID *uuid.UUID
+	err := r.db.QueryRowContext(ctx, `SELECT campaign_id FROM job_executions WHERE id = $1`, jobID).Scan(&campaignID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign of job: %w", err)
+	}
+	return campaignID, nil
+}
+
+// ListJobs returns the jobs of a campaign in creation order, with the hashes
+// each has cracked
+func (r *JobCampaignRepository) ListJobs(ctx context.Context, campaignID uuid.UUID) ([]models.CampaignJob, error) {
+	query := `
+		SELECT je.id, je.name, je.hashlist_id, je.status, je.overall_progress_percent,
+			COALESCE((SELECT SUM(jt.crack_count) FROM job_tasks jt WHERE jt.job_execution_id = je.id), 0)
+		FROM job_executions je
+		WHERE je.campaign_id = $1
+		ORDER BY je.created_at, je.id`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaign jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []models.CampaignJob{}
+	for rows.Next() {
+		var job models.CampaignJob
+		if err := rows.Scan(&job.JobID, &job.Name, &job.HashlistID, &job.Status,
+			&job.OverallProgressPercent, &job.CrackedHashes); err != nil {
+			return nil, fmt.Errorf("failed to scan campaign job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// ListHashlists returns the hashlists a campaign runs against with their
+// current crack counts, skipping any deleted since
+func (r *JobCampaignRepository) ListHashlists(ctx context.Context, campaign *models.JobCampaign) ([]models.CampaignHashlist, error) {
+	query := `
+		SELECT id, name, total_hashes, cracked_hashes
+		FROM hashlists
+		WHERE id = ANY($1)
+		ORDER BY array_position($1, id)`
+
+	rows, err := r.db.QueryContext(ctx, query, campaign.HashlistIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaign hashlists: %w", err)
+	}
+	defer rows.Close()
+
+	hashlists := []models.CampaignHashlist{}
+	for rows.Next() {
+		var hashlist models.CampaignHashlist
+		if err := rows.Scan(&hashlist.HashlistID, &hashlist.Name, &hashlist.TotalHashes, &hashlist.CrackedHashes); err != nil {
+			return nil, fmt.Errorf("failed to scan campaign hashlist: %w", err)
+		}
+		hashlists = append(hashlists, hashlist)
+	}
+	return hashlists, rows.Err()
+}
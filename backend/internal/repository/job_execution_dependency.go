@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 )
 
 // HasActiveJobsUsingWordlist checks if there are any active jobs using the specified wordlist
@@ -10,7 +12,7 @@ func (r *JobExecutionRepository) HasActiveJobsUsingWordlist(ctx context.Context,
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM job_executions
-			WHERE status NOT IN ('completed', 'cancelled', 'failed')
+			WHERE status NOT IN ('completed', 'cancelled', 'failed', 'superseded')
 			AND wordlist_ids ? $1
 		)`
 
@@ -28,7 +30,7 @@ func (r *JobExecutionRepository) HasActiveJobsUsingRule(ctx context.Context, rul
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM job_executions
-			WHERE status NOT IN ('completed', 'cancelled', 'failed')
+			WHERE status NOT IN ('completed', 'cancelled', 'failed', 'superseded')
 			AND rule_ids ? $1
 		)`
 
@@ -39,4 +41,100 @@ func (r *JobExecutionRepository) HasActiveJobsUsingRule(ctx context.Context, rul
 	}
 
 	return exists, nil
-}
\ No newline at end of file
+}
+
+// GetWordlistReferences lists the preset jobs and active jobs using a wordlist
+func (r *JobExecutionRepository) GetWordlistReferences(ctx context.Context, wordlistID string) (*models.FileReferences, error) {
+	return r.getFileReferences(ctx, "wordlist_ids", wordlistID)
+}
+
+// GetRuleReferences lists the preset jobs and active jobs using a rule
+func (r *JobExecutionRepository) GetRuleReferences(ctx context.Context, ruleID string) (*models.FileReferences, error) {
+	return r.getFileReferences(ctx, "rule_ids", ruleID)
+}
+
+// DetachWordlistFromPresets removes a wordlist from every preset job using it
+// and flags those presets for review. It returns the presets it changed.
+func (r *JobExecutionRepository) DetachWordlistFromPresets(ctx context.Context, wordlistID string) ([]models.FileReference, error) {
+	return r.detachFromPresets(ctx, "wordlist_ids", wordlistID)
+}
+
+// DetachRuleFromPresets removes a rule from every preset job using it and
+// flags those presets for review. It returns the presets it changed.
+func (r *JobExecutionRepository) DetachRuleFromPresets(ctx context.Context, ruleID string) ([]models.FileReference, error) {
+	return r.detachFromPresets(ctx, "rule_ids", ruleID)
+}
+
+// getFileReferences lists the preset jobs and active jobs whose ID array
+// column contains id. column is one of wordlist_ids or rule_ids.
+func (r *JobExecutionRepository) getFileReferences(ctx context.Context, column, id string) (*models.FileReferences, error) {
+	refs := &models.FileReferences{
+		PresetJobs: []models.FileReference{},
+		Jobs:       []models.FileReference{},
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name FROM preset_jobs
+		WHERE `+column+` ? $1
+		ORDER BY name`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preset jobs using %s %s: %w", column, id, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var ref models.FileReference
+		if err := rows.Scan(&ref.ID, &ref.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan preset job reference: %w", err)
+		}
+		refs.PresetJobs = append(refs.PresetJobs, ref)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating preset job references: %w", err)
+	}
+
+	jobRows, err := r.db.QueryContext(ctx, `
+		SELECT id, COALESCE(name, ''), status FROM job_executions
+		WHERE status NOT IN ('completed', 'cancelled', 'failed', 'superseded')
+		AND `+column+` ? $1
+		ORDER BY created_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active jobs using %s %s: %w", column, id, err)
+	}
+	defer jobRows.Close()
+	for jobRows.Next() {
+		var ref models.FileReference
+		if err := jobRows.Scan(&ref.ID, &ref.Name, &ref.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan job reference: %w", err)
+		}
+		refs.Jobs = append(refs.Jobs, ref)
+	}
+	if err := jobRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job references: %w", err)
+	}
+
+	return refs, nil
+}
+
+// detachFromPresets removes id from the ID array column of the preset jobs
+// containing it and sets their needs_review flag
+func (r *JobExecutionRepository) detachFromPresets(ctx context.Context, column, id string) ([]models.FileReference, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE preset_jobs
+		SET `+column+` = `+column+` - $1, needs_review = true, updated_at = NOW()
+		WHERE `+column+` ? $1
+		RETURNING id, name`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detach %s %s from preset jobs: %w", column, id, err)
+	}
+	defer rows.Close()
+
+	var presets []models.FileReference
+	for rows.Next() {
+		var ref models.FileReference
+		if err := rows.Scan(&ref.ID, &ref.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan detached preset job: %w", err)
+		}
+		presets = append(presets, ref)
+	}
+	return presets, rows.Err()
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // JobExecutionRepository handles database operations for job executions
@@ -27,9 +28,10 @@ func (r *JobExecutionRepository) Create(ctx context.Context, exec *models.JobExe
 		INSERT INTO job_executions (
 			preset_job_id, hashlist_id, status, priority, max_agents, attack_mode, total_keyspace, created_by,
 			name, wordlist_ids, rule_ids, mask, binary_version_id, hash_type,
-			chunk_size_seconds, status_updates_enabled, allow_high_priority_override, additional_args
+			chunk_size_seconds, status_updates_enabled, allow_high_priority_override, additional_args,
+			attack_fingerprint, mask_increment
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, NULLIF($19, ''), $20)
 		RETURNING id, created_at`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -51,6 +53,8 @@ func (r *JobExecutionRepository) Create(ctx context.Context, exec *models.JobExe
 		exec.StatusUpdatesEnabled,
 		exec.AllowHighPriorityOverride,
 		exec.AdditionalArgs,
+		exec.AttackFingerprint,
+		exec.MaskIncrement,
 	).Scan(&exec.ID, &exec.CreatedAt)
 
 	if err != nil {
@@ -76,7 +80,10 @@ func (r *JobExecutionRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 			je.wordlist_ids, je.rule_ids, je.mask, je.binary_version_id,
 			je.chunk_size_seconds, je.status_updates_enabled, je.allow_high_priority_override,
 			je.additional_args, je.hash_type, je.updated_at,
-			je.avg_rule_multiplier, je.is_accurate_keyspace
+			je.avg_rule_multiplier, je.is_accurate_keyspace, je.chunk_overlap, je.extra_parameters,
+			je.skip_benchmark, je.benchmark_duration_seconds, je.is_background,
+			je.pinned_agent_ids, je.excluded_agent_ids, je.allow_cloud_burst, je.split_group_id,
+			je.mask_increment, je.start_at, je.depends_on_job_id, je.remaining_hash_count
 		FROM job_executions je
 		WHERE je.id = $1`
 
@@ -94,7 +101,10 @@ func (r *JobExecutionRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 		&exec.WordlistIDs, &exec.RuleIDs, &exec.Mask, &exec.BinaryVersionID,
 		&exec.ChunkSizeSeconds, &exec.StatusUpdatesEnabled, &exec.AllowHighPriorityOverride,
 		&exec.AdditionalArgs, &exec.HashType, &exec.UpdatedAt,
-		&exec.AvgRuleMultiplier, &exec.IsAccurateKeyspace,
+		&exec.AvgRuleMultiplier, &exec.IsAccurateKeyspace, &exec.ChunkOverlap, &exec.ExtraParameters,
+		&exec.SkipBenchmark, &exec.BenchmarkDurationSeconds, &exec.IsBackground,
+		&exec.PinnedAgentIDs, &exec.ExcludedAgentIDs, &exec.AllowCloudBurst, &exec.SplitGroupID,
+		&exec.MaskIncrement, &exec.StartAt, &exec.DependsOnJobID, &exec.RemainingHashCount,
 	)
 
 	if err == sql.ErrNoRows {
@@ -107,6 +117,16 @@ func (r *JobExecutionRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 	return &exec, nil
 }
 
+// workflowRunPriority is the scheduling priority of the job aliased je. A job
+// of a workflow run takes the highest priority of the unfinished jobs of its
+// run, so the steps of a chain are scheduled and interrupted as one and no job
+// between their priorities can run between them.
+const workflowRunPriority = `COALESCE((
+			SELECT MAX(wr.priority) FROM job_executions wr
+			WHERE wr.workflow_run_id = je.workflow_run_id
+			AND wr.status IN ('pending', 'running', 'paused')
+		), je.priority)`
+
 // GetPendingJobs retrieves pending jobs ordered by priority and creation time
 func (r *JobExecutionRepository) GetPendingJobs(ctx context.Context) ([]models.JobExecution, error) {
 	query := `
@@ -126,8 +146,8 @@ func (r *JobExecutionRepository) GetPendingJobs(ctx context.Context) ([]models.J
 			je.allow_high_priority_override, je.additional_args,
 			je.hash_type
 		FROM job_executions je
-		WHERE je.status = 'pending'
-		ORDER BY je.priority DESC, je.created_at ASC`
+		WHERE je.status = 'pending' AND je.deleted_at IS NULL
+		ORDER BY ` + workflowRunPriority + ` DESC, je.created_at ASC`
 
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
@@ -362,6 +382,30 @@ func (r *JobExecutionRepository) CompleteExecution(ctx context.Context, id uuid.
 	return nil
 }
 
+// SupersedeExecution marks a pending, scheduled or paused job execution as
+// superseded. Jobs in any other status are left alone and ErrNotFound is
+// returned.
+func (r *JobExecutionRepository) SupersedeExecution(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE job_executions SET status = $1, completed_at = $2
+		WHERE id = $3 AND status IN ('pending', 'scheduled', 'paused')`
+	result, err := r.db.ExecContext(ctx, query, models.JobExecutionStatusSuperseded, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to supersede job execution: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 // FailExecution marks a job execution as failed with an error message
 func (r *JobExecutionRepository) FailExecution(ctx context.Context, id uuid.UUID, errorMessage string) error {
 	now := time.Now()
@@ -423,8 +467,9 @@ func (r *JobExecutionRepository) GetPendingJobsWithHighPriorityOverride(ctx cont
 			allow_high_priority_override, additional_args,
 			hash_type
 		FROM job_executions
-		WHERE status = 'pending'
+		WHERE status = 'pending' AND deleted_at IS NULL
 			AND allow_high_priority_override = true
+			AND is_background = false
 		ORDER BY priority DESC, created_at ASC`
 
 	rows, err := r.db.QueryContext(ctx, query)
@@ -463,6 +508,7 @@ func (r *JobExecutionRepository) GetPendingJobsWithHighPriorityOverride(ctx cont
 
 // GetInterruptibleJobs retrieves running jobs that can be interrupted
 // Returns jobs with priority lower than the given priority, ordered by priority ASC (lowest first)
+// A job of a workflow run is only interruptible when its whole run is below the given priority
 func (r *JobExecutionRepository) GetInterruptibleJobs(ctx context.Context, priority int) ([]models.JobExecution, error) {
 	// Now we look for ANY running job with lower priority, not checking allow_high_priority_override
 	// The check for override permission is done by the caller
@@ -475,8 +521,8 @@ func (r *JobExecutionRepository) GetInterruptibleJobs(ctx context.Context, prior
 			je.allow_high_priority_override
 		FROM job_executions je
 		WHERE je.status = 'running' 
-		AND je.priority < $1
-		ORDER BY je.priority ASC
+		AND ` + workflowRunPriority + ` < $1
+		ORDER BY ` + workflowRunPriority + ` ASC
 		LIMIT 1`
 
 	rows, err := r.db.QueryContext(ctx, query, priority)
@@ -623,6 +669,72 @@ func (r *JobExecutionRepository) UpdateConsecutiveFailures(ctx context.Context,
 	return nil
 }
 
+// SetWorkflowRun links a job execution to its workflow run and sets the
+// priority and max agents it inherited from the step before it
+func (r *JobExecutionRepository) SetWorkflowRun(ctx context.Context, id, runID uuid.UUID, step, priority, maxAgents int) error {
+	query := `
+		UPDATE job_executions
+		SET workflow_run_id = $1, workflow_step = $2, priority = $3, max_agents = $4,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5`
+	result, err := r.db.ExecContext(ctx, query, runID, step, priority, maxAgents, id)
+	if err != nil {
+		return fmt.Errorf("failed to set workflow run of job execution: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SetSplitGroup links the jobs running one attack against the sub-lists of a
+// split hashlist
+func (r *JobExecutionRepository) SetSplitGroup(ctx context.Context, ids []uuid.UUID, groupID uuid.UUID) error {
+	query := `
+		UPDATE job_executions
+		SET split_group_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ANY($2)`
+	if _, err := r.db.ExecContext(ctx, query, groupID, pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to set split group of job executions: %w", err)
+	}
+	return nil
+}
+
+// GetSplitGroupParts returns the jobs of a split group in sub-list order
+func (r *JobExecutionRepository) GetSplitGroupParts(ctx context.Context, groupID uuid.UUID) ([]models.SplitGroupPart, error) {
+	query := `
+		SELECT je.id, je.hashlist_id, COALESCE(h.part_number, 0), je.status, je.overall_progress_percent,
+			COALESCE((SELECT SUM(jt.crack_count) FROM job_tasks jt WHERE jt.job_execution_id = je.id), 0)
+		FROM job_executions je
+		JOIN hashlists h ON h.id = je.hashlist_id
+		WHERE je.split_group_id = $1
+		ORDER BY h.part_number, je.created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get split group jobs: %w", err)
+	}
+	defer rows.Close()
+
+	parts := []models.SplitGroupPart{}
+	for rows.Next() {
+		var part models.SplitGroupPart
+		if err := rows.Scan(&part.JobID, &part.HashlistID, &part.PartNumber, &part.Status,
+			&part.OverallProgressPercent, &part.CrackedHashes); err != nil {
+			return nil, fmt.Errorf("failed to scan split group job: %w", err)
+		}
+		parts = append(parts, part)
+	}
+	return parts, rows.Err()
+}
+
 // UpdateDispatchedKeyspace updates the dispatched keyspace for a job execution
 func (r *JobExecutionRepository) UpdateDispatchedKeyspace(ctx context.Context, id uuid.UUID, dispatchedKeyspace int64) error {
 	query := `
@@ -726,7 +838,9 @@ func (r *JobExecutionRepository) GetJobsWithPendingWork(ctx context.Context) ([]
 			je.name, je.wordlist_ids, je.rule_ids, je.mask,
 			je.binary_version_id, je.chunk_size_seconds, je.status_updates_enabled,
 			je.allow_high_priority_override, je.additional_args,
-			je.hash_type,
+			je.hash_type, je.is_background,
+			je.pinned_agent_ids, je.excluded_agent_ids, je.allow_cloud_burst,
+			je.mask_increment,
 			COALESCE(js.active_agents, 0) as active_agents,
 			COALESCE(js.pending_tasks, 0) + COALESCE(js.retryable_tasks, 0) as pending_work
 		FROM job_executions je
@@ -751,7 +865,7 @@ func (r *JobExecutionRepository) GetJobsWithPendingWork(ctx context.Context) ([]
 				 AND je.dispatched_keyspace < je.total_keyspace
 				 AND COALESCE(js.active_agents, 0) < COALESCE(NULLIF(je.max_agents, 0), 999))
 			)
-		ORDER BY je.priority DESC, je.created_at ASC`
+		ORDER BY je.is_background ASC, ` + workflowRunPriority + ` DESC, je.created_at ASC`
 
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
@@ -776,7 +890,9 @@ func (r *JobExecutionRepository) GetJobsWithPendingWork(ctx context.Context) ([]
 			&exec.Name, &exec.WordlistIDs, &exec.RuleIDs, &exec.Mask,
 			&exec.BinaryVersionID, &exec.ChunkSizeSeconds, &exec.StatusUpdatesEnabled,
 			&exec.AllowHighPriorityOverride, &exec.AdditionalArgs,
-			&exec.HashType,
+			&exec.HashType, &exec.IsBackground,
+			&exec.PinnedAgentIDs, &exec.ExcludedAgentIDs, &exec.AllowCloudBurst,
+			&exec.MaskIncrement,
 			&exec.ActiveAgents, &exec.PendingWork,
 		)
 		if err != nil {
@@ -799,7 +915,7 @@ func (r *JobExecutionRepository) GetNonCompletedJobsByHashlistID(ctx context.Con
 			additional_args, binary_version_id, started_at, completed_at, error_message, created_by,
 			created_at, updated_at
 		FROM job_executions
-		WHERE hashlist_id = $1 AND status != 'completed'
+		WHERE hashlist_id = $1 AND status NOT IN ('completed', 'superseded')
 		ORDER BY priority DESC, created_at ASC
 	`
 
@@ -833,3 +949,77 @@ func (r *JobExecutionRepository) GetNonCompletedJobsByHashlistID(ctx context.Con
 
 	return executions, nil
 }
+
+// GetRunningJobCounts returns the number of running jobs per creating user and
+// per client of the job's hashlist
+func (r *JobExecutionRepository) GetRunningJobCounts(ctx context.Context) (*models.RunningJobCounts, error) {
+	query := `
+		SELECT je.created_by, h.client_id
+		FROM job_executions je
+		JOIN hashlists h ON h.id = je.hashlist_id
+		WHERE je.status = 'running'
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query running jobs: %w", err)
+	}
+	defer rows.Close()
+
+	counts := &models.RunningJobCounts{
+		ByUser:   make(map[uuid.UUID]int),
+		ByClient: make(map[uuid.UUID]int),
+	}
+	for rows.Next() {
+		var userID, clientID uuid.NullUUID
+		if err := rows.Scan(&userID, &clientID); err != nil {
+			return nil, fmt.Errorf("failed to scan running job: %w", err)
+		}
+		if userID.Valid {
+			counts.ByUser[userID.UUID]++
+		}
+		if clientID.Valid {
+			counts.ByClient[clientID.UUID]++
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating running jobs: %w", err)
+	}
+
+	return counts, nil
+}
+
+// FindDuplicateAttacks returns the jobs on a hashlist with the same attack
+// fingerprint that are queued, running, paused or already completed. Failed and
+// cancelled runs are not considered duplicates since they may be retried.
+func (r *JobExecutionRepository) FindDuplicateAttacks(ctx context.Context, hashlistID int64, fingerprint string) ([]models.DuplicateJob, error) {
+	query := `
+		SELECT id, COALESCE(name, ''), status, overall_progress_percent, created_at, completed_at
+		FROM job_executions
+		WHERE hashlist_id = $1 AND attack_fingerprint = $2
+		  AND status IN ('pending', 'running', 'paused', 'completed')
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, hashlistID, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query duplicate jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var duplicates []models.DuplicateJob
+	for rows.Next() {
+		var dup models.DuplicateJob
+		if err := rows.Scan(&dup.ID, &dup.Name, &dup.Status, &dup.OverallProgressPercent, &dup.CreatedAt, &dup.CompletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate job: %w", err)
+		}
+		duplicates = append(duplicates, dup)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating duplicate jobs: %w", err)
+	}
+
+	return duplicates, nil
+}
@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // ListWithPagination retrieves job executions with pagination, ordered by priority and creation time
@@ -16,6 +18,7 @@ func (r *JobExecutionRepository) ListWithPagination(ctx context.Context, limit,
 			total_keyspace, processed_keyspace, attack_mode, created_by,
 			created_at, started_at, completed_at, error_message, interrupted_by, updated_at
 		FROM job_executions
+		WHERE deleted_at IS NULL
 		ORDER BY 
 			-- Active jobs first (pending, running, paused)
 			CASE 
@@ -67,6 +70,9 @@ type JobFilter struct {
 	Priority *int
 	Search   *string
 	UserID   *string
+	// OrderBy overrides the default active-first ordering. It must be a
+	// whitelisted expression (see httputil.ListQuery.OrderBy), never raw input.
+	OrderBy string
 }
 
 // JobExecutionWithUser represents a job execution with user information
@@ -86,7 +92,7 @@ func (r *JobExecutionRepository) ListWithFilters(ctx context.Context, limit, off
 		LEFT JOIN preset_jobs pj ON je.preset_job_id = pj.id
 		JOIN hashlists h ON je.hashlist_id = h.id
 		LEFT JOIN users u ON je.created_by = u.id
-		WHERE 1=1`
+		WHERE je.deleted_at IS NULL AND h.deleted_at IS NULL`
 
 	args := []interface{}{}
 	argCount := 0
@@ -121,7 +127,10 @@ func (r *JobExecutionRepository) ListWithFilters(ctx context.Context, limit, off
 	}
 
 	// Add ordering
-	query += ` ORDER BY 
+	if filter.OrderBy != "" {
+		query += " ORDER BY " + filter.OrderBy + ", je.created_at DESC"
+	} else {
+		query += ` ORDER BY 
 		-- Active jobs first (pending, running, paused)
 		CASE 
 			WHEN je.status IN ('pending', 'running', 'paused') THEN 0
@@ -141,6 +150,7 @@ func (r *JobExecutionRepository) ListWithFilters(ctx context.Context, limit, off
 			WHEN je.status NOT IN ('pending', 'running', 'paused') THEN je.completed_at
 			ELSE NULL
 		END DESC`
+	}
 
 	// Add pagination
 	argCount++
@@ -176,7 +186,7 @@ func (r *JobExecutionRepository) ListWithFilters(ctx context.Context, limit, off
 
 // GetTotalCount returns the total number of job executions
 func (r *JobExecutionRepository) GetTotalCount(ctx context.Context) (int, error) {
-	query := `SELECT COUNT(*) FROM job_executions`
+	query := `SELECT COUNT(*) FROM job_executions WHERE deleted_at IS NULL`
 	var count int
 	err := r.db.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
@@ -192,7 +202,7 @@ func (r *JobExecutionRepository) GetFilteredCount(ctx context.Context, filter Jo
 		FROM job_executions je
 		LEFT JOIN preset_jobs pj ON je.preset_job_id = pj.id
 		JOIN hashlists h ON je.hashlist_id = h.id
-		WHERE 1=1`
+		WHERE je.deleted_at IS NULL AND h.deleted_at IS NULL`
 
 	args := []interface{}{}
 	argCount := 0
@@ -239,6 +249,7 @@ func (r *JobExecutionRepository) GetStatusCounts(ctx context.Context) (map[strin
 	query := `
 		SELECT status, COUNT(*) as count
 		FROM job_executions
+		WHERE deleted_at IS NULL
 		GROUP BY status`
 
 	rows, err := r.db.QueryContext(ctx, query)
@@ -265,7 +276,7 @@ func (r *JobExecutionRepository) GetStatusCountsForUser(ctx context.Context, use
 	query := `
 		SELECT je.status, COUNT(*) as count
 		FROM job_executions je
-		WHERE je.created_by = $1
+		WHERE je.created_by = $1 AND je.deleted_at IS NULL
 		GROUP BY je.status`
 
 	rows, err := r.db.QueryContext(ctx, query, userID)
@@ -347,6 +358,356 @@ func (r *JobExecutionRepository) UpdateChunkSizeSeconds(ctx context.Context, id
 	return nil
 }
 
+// UpdateChunkOverlap sets the per-job chunk overlap, nil reverts to the system setting
+func (r *JobExecutionRepository) UpdateChunkOverlap(ctx context.Context, id uuid.UUID, chunkOverlap *int64) error {
+	query := `UPDATE job_executions SET chunk_overlap = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, chunkOverlap, id)
+	if err != nil {
+		return fmt.Errorf("failed to update job execution chunk overlap: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetChunkOverlap returns the per-job chunk overlap, nil when the job uses the system setting
+func (r *JobExecutionRepository) GetChunkOverlap(ctx context.Context, id uuid.UUID) (*int64, error) {
+	var chunkOverlap *int64
+	err := r.db.QueryRowContext(ctx, `SELECT chunk_overlap FROM job_executions WHERE id = $1`, id).Scan(&chunkOverlap)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job execution chunk overlap: %w", err)
+	}
+	return chunkOverlap, nil
+}
+
+// UpdateBenchmarkOverride sets whether a job skips its forced benchmark and
+// the time limit of its benchmarks
+func (r *JobExecutionRepository) UpdateBenchmarkOverride(ctx context.Context, id uuid.UUID, override models.BenchmarkOverride) error {
+	query := `
+		UPDATE job_executions
+		SET skip_benchmark = $1, benchmark_duration_seconds = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3`
+	result, err := r.db.ExecContext(ctx, query, override.Skip, override.DurationSeconds, id)
+	if err != nil {
+		return fmt.Errorf("failed to update job execution benchmark override: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetBenchmarkOverride returns the benchmark override of a job execution
+func (r *JobExecutionRepository) GetBenchmarkOverride(ctx context.Context, id uuid.UUID) (*models.BenchmarkOverride, error) {
+	var override models.BenchmarkOverride
+	err := r.db.QueryRowContext(ctx,
+		`SELECT skip_benchmark, benchmark_duration_seconds FROM job_executions WHERE id = $1`, id,
+	).Scan(&override.Skip, &override.DurationSeconds)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job execution benchmark override: %w", err)
+	}
+	return &override, nil
+}
+
+// SetRemainingHashCount records that a job execution runs against a snapshot
+// of count uncracked hashes of its hashlist
+func (r *JobExecutionRepository) SetRemainingHashCount(ctx context.Context, id uuid.UUID, count int) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE job_executions SET remaining_hash_count = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		id, count)
+	if err != nil {
+		return fmt.Errorf("failed to update job execution remaining hash count: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetRemainingHashCount returns the size of a job execution's snapshot of
+// uncracked hashes, nil when it runs the full hashlist
+func (r *JobExecutionRepository) GetRemainingHashCount(ctx context.Context, id uuid.UUID) (*int, error) {
+	var count *int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT remaining_hash_count FROM job_executions WHERE id = $1`, id,
+	).Scan(&count)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job execution remaining hash count: %w", err)
+	}
+	return count, nil
+}
+
+// SetHybridKeyspace records the wordlist and mask sides of a hybrid attack's keyspace
+func (r *JobExecutionRepository) SetHybridKeyspace(ctx context.Context, id uuid.UUID, wordlistKeyspace, maskKeyspace int64) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE job_executions SET hybrid_wordlist_keyspace = $2, hybrid_mask_keyspace = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		id, wordlistKeyspace, maskKeyspace)
+	if err != nil {
+		return fmt.Errorf("failed to update job execution hybrid keyspace: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetHybridKeyspace returns the keyspace breakdown of a hybrid attack, nil
+// for other attacks or jobs created before it was recorded
+func (r *JobExecutionRepository) GetHybridKeyspace(ctx context.Context, id uuid.UUID) (*models.HybridKeyspace, error) {
+	var attackMode models.AttackMode
+	var wordlistKeyspace, maskKeyspace sql.NullInt64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT attack_mode, hybrid_wordlist_keyspace, hybrid_mask_keyspace FROM job_executions WHERE id = $1`, id,
+	).Scan(&attackMode, &wordlistKeyspace, &maskKeyspace)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job execution hybrid keyspace: %w", err)
+	}
+	return models.NewHybridKeyspace(attackMode, wordlistKeyspace.Int64, maskKeyspace.Int64), nil
+}
+
+// UpdateBackground moves a job execution into or out of the background class
+func (r *JobExecutionRepository) UpdateBackground(ctx context.Context, id uuid.UUID, background bool) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE job_executions SET is_background = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+		background, id)
+	if err != nil {
+		return fmt.Errorf("failed to update job execution background class: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateAllowCloudBurst sets whether a job execution may run on cloud burst agents
+func (r *JobExecutionRepository) UpdateAllowCloudBurst(ctx context.Context, id uuid.UUID, allow bool) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE job_executions SET allow_cloud_burst = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+		allow, id)
+	if err != nil {
+		return fmt.Errorf("failed to update job execution cloud burst opt-in: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateStartCondition holds a pending job execution back in the scheduled
+// status until its start condition is met, see ReleaseScheduledJobs
+func (r *JobExecutionRepository) UpdateStartCondition(ctx context.Context, id uuid.UUID, condition models.JobStartCondition) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE job_executions SET status = $1, start_at = $2, depends_on_job_id = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND status = 'pending'`,
+		models.JobExecutionStatusScheduled, condition.StartAt, condition.DependsOnJobID, id)
+	if err != nil {
+		return fmt.Errorf("failed to update job execution start condition: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ReleaseScheduledJobs moves scheduled job executions whose start time has
+// passed and whose dependency completed to pending. A job whose dependency
+// failed or was cancelled is cancelled too. Jobs in the trash, or on a hashlist
+// in the trash, are never released. It returns the released and the cancelled
+// jobs.
+func (r *JobExecutionRepository) ReleaseScheduledJobs(ctx context.Context) (released, cancelled []models.JobExecution, err error) {
+	cancelled, err = r.scanStatusChanges(ctx, `
+		UPDATE job_executions je
+		SET status = 'cancelled', completed_at = NOW(), updated_at = NOW(),
+			error_message = 'Dependency job ' || d.id || ' ' || d.status
+		FROM job_executions d
+		WHERE je.status = 'scheduled' AND d.id = je.depends_on_job_id
+			AND d.status IN ('failed', 'cancelled')
+		RETURNING je.id, COALESCE(je.name, ''), je.created_by`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to cancel scheduled jobs with a failed dependency: %w", err)
+	}
+
+	released, err = r.scanStatusChanges(ctx, `
+		UPDATE job_executions je
+		SET status = 'pending', updated_at = NOW()
+		WHERE je.status = 'scheduled' AND je.deleted_at IS NULL
+			AND (je.start_at IS NULL OR je.start_at <= NOW())
+			AND EXISTS (SELECT 1 FROM hashlists h WHERE h.id = je.hashlist_id AND h.deleted_at IS NULL)
+			AND (je.depends_on_job_id IS NULL OR EXISTS (
+				SELECT 1 FROM job_executions d
+				WHERE d.id = je.depends_on_job_id AND d.status IN ('completed', 'superseded')
+			))
+		RETURNING je.id, COALESCE(je.name, ''), je.created_by`)
+	if err != nil {
+		return nil, cancelled, fmt.Errorf("failed to release scheduled jobs: %w", err)
+	}
+
+	return released, cancelled, nil
+}
+
+// scanStatusChanges runs an UPDATE returning the ID, name and owner of the
+// changed job executions
+func (r *JobExecutionRepository) scanStatusChanges(ctx context.Context, query string) ([]models.JobExecution, error) {
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.JobExecution
+	for rows.Next() {
+		var job models.JobExecution
+		if err := rows.Scan(&job.ID, &job.Name, &job.CreatedBy); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// UpdateAgentPlacement sets the agents a job execution is pinned to and excludes
+func (r *JobExecutionRepository) UpdateAgentPlacement(ctx context.Context, id uuid.UUID, placement models.AgentPlacement) error {
+	if placement.PinnedAgentIDs == nil {
+		placement.PinnedAgentIDs = models.AgentIDList{}
+	}
+	if placement.ExcludedAgentIDs == nil {
+		placement.ExcludedAgentIDs = models.AgentIDList{}
+	}
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE job_executions SET pinned_agent_ids = $1, excluded_agent_ids = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3`,
+		placement.PinnedAgentIDs, placement.ExcludedAgentIDs, id)
+	if err != nil {
+		return fmt.Errorf("failed to update job execution agent placement: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetAnnotations returns the notes and tags of a job execution
+func (r *JobExecutionRepository) GetAnnotations(ctx context.Context, id uuid.UUID) (*models.Annotations, error) {
+	annotations := &models.Annotations{}
+	err := r.db.QueryRowContext(ctx, `SELECT notes, tags FROM job_executions WHERE id = $1`, id).
+		Scan(&annotations.Notes, pq.Array(&annotations.Tags))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job execution annotations: %w", err)
+	}
+	return annotations, nil
+}
+
+// UpdateAnnotations replaces the notes and tags of a job execution
+func (r *JobExecutionRepository) UpdateAnnotations(ctx context.Context, id uuid.UUID, annotations models.Annotations) error {
+	query := `UPDATE job_executions SET notes = $1, tags = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3`
+	result, err := r.db.ExecContext(ctx, query, annotations.Notes, pq.Array(annotations.Tags), id)
+	if err != nil {
+		return fmt.Errorf("failed to update job execution annotations: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateExtraParameters sets the job's extra hashcat parameters, nil clears them
+func (r *JobExecutionRepository) UpdateExtraParameters(ctx context.Context, id uuid.UUID, extraParameters *string) error {
+	query := `UPDATE job_executions SET extra_parameters = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, extraParameters, id)
+	if err != nil {
+		return fmt.Errorf("failed to update job execution extra parameters: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 // Delete deletes a job execution and related tasks
 func (r *JobExecutionRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	// Start transaction
@@ -397,6 +758,82 @@ func (r *JobExecutionRepository) Delete(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// SoftDelete moves a job execution to the trash. A job that is still scheduled,
+// pending, running or paused is cancelled so the scheduler no longer picks it up.
+func (r *JobExecutionRepository) SoftDelete(ctx context.Context, id uuid.UUID, deletedBy *uuid.UUID) error {
+	query := `
+		UPDATE job_executions
+		SET deleted_at = CURRENT_TIMESTAMP,
+			deleted_by = $2,
+			status = CASE WHEN status IN ('scheduled', 'pending', 'running', 'paused') THEN 'cancelled' ELSE status END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id, deletedBy)
+	if err != nil {
+		return fmt.Errorf("failed to move job execution to trash: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SoftDeleteFinished moves all completed, failed, cancelled and superseded job executions to the trash
+func (r *JobExecutionRepository) SoftDeleteFinished(ctx context.Context, deletedBy *uuid.UUID) (int, error) {
+	query := `
+		UPDATE job_executions
+		SET deleted_at = CURRENT_TIMESTAMP, deleted_by = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE status IN ('completed', 'failed', 'cancelled', 'superseded') AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, deletedBy)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move finished job executions to trash: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// Restore takes a job execution out of the trash. It fails with
+// ErrTrashParentDeleted while the job's hashlist is itself in the trash.
+func (r *JobExecutionRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	var hashlistDeleted bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT h.deleted_at IS NOT NULL
+		FROM job_executions je
+		JOIN hashlists h ON je.hashlist_id = h.id
+		WHERE je.id = $1 AND je.deleted_at IS NOT NULL`, id).Scan(&hashlistDeleted)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to get job execution from trash: %w", err)
+	}
+	if hashlistDeleted {
+		return ErrTrashParentDeleted
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE job_executions
+		SET deleted_at = NULL, deleted_by = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore job execution: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteFinished deletes all completed job executions
 func (r *JobExecutionRepository) DeleteFinished(ctx context.Context) (int, error) {
 	// Start transaction
@@ -412,7 +849,7 @@ func (r *JobExecutionRepository) DeleteFinished(ctx context.Context) (int, error
 		SET interrupted_by = NULL 
 		WHERE interrupted_by IN (
 			SELECT id FROM job_executions 
-			WHERE status IN ('completed', 'failed', 'cancelled')
+			WHERE status IN ('completed', 'failed', 'cancelled', 'superseded')
 		)`)
 	if err != nil {
 		return 0, fmt.Errorf("failed to clear interrupted_by references: %w", err)
@@ -423,7 +860,7 @@ func (r *JobExecutionRepository) DeleteFinished(ctx context.Context) (int, error
 		DELETE FROM job_performance_metrics 
 		WHERE job_execution_id IN (
 			SELECT id FROM job_executions 
-			WHERE status IN ('completed', 'failed', 'cancelled')
+			WHERE status IN ('completed', 'failed', 'cancelled', 'superseded')
 		)`)
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete related performance metrics: %w", err)
@@ -434,7 +871,7 @@ func (r *JobExecutionRepository) DeleteFinished(ctx context.Context) (int, error
 		DELETE FROM job_tasks 
 		WHERE job_execution_id IN (
 			SELECT id FROM job_executions 
-			WHERE status IN ('completed', 'failed', 'cancelled')
+			WHERE status IN ('completed', 'failed', 'cancelled', 'superseded')
 		)`)
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete related job tasks: %w", err)
@@ -443,7 +880,7 @@ func (r *JobExecutionRepository) DeleteFinished(ctx context.Context) (int, error
 	// Delete finished job executions
 	result, err := tx.ExecContext(ctx, `
 		DELETE FROM job_executions 
-		WHERE status IN ('completed', 'failed', 'cancelled')`)
+		WHERE status IN ('completed', 'failed', 'cancelled', 'superseded')`)
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete finished job executions: %w", err)
 	}
@@ -476,7 +913,7 @@ func (r *JobExecutionRepository) ListWithFiltersAndUser(ctx context.Context, lim
 		LEFT JOIN preset_jobs pj ON je.preset_job_id = pj.id
 		JOIN hashlists h ON je.hashlist_id = h.id
 		LEFT JOIN users u ON je.created_by = u.id
-		WHERE 1=1`
+		WHERE je.deleted_at IS NULL AND h.deleted_at IS NULL`
 
 	args := []interface{}{}
 	argCount := 0
@@ -511,7 +948,10 @@ func (r *JobExecutionRepository) ListWithFiltersAndUser(ctx context.Context, lim
 	}
 
 	// Add ordering
-	query += ` ORDER BY 
+	if filter.OrderBy != "" {
+		query += " ORDER BY " + filter.OrderBy + ", je.created_at DESC"
+	} else {
+		query += ` ORDER BY 
 		-- Active jobs first (pending, running, paused)
 		CASE 
 			WHEN je.status IN ('pending', 'running', 'paused') THEN 0
@@ -531,6 +971,7 @@ func (r *JobExecutionRepository) ListWithFiltersAndUser(ctx context.Context, lim
 			WHEN je.status NOT IN ('pending', 'running', 'paused') THEN je.completed_at
 			ELSE NULL
 		END DESC`
+	}
 
 	// Add pagination
 	argCount++
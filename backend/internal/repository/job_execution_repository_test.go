@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createScheduledTestJob creates a job execution on a new hashlist that is
+// scheduled to start an hour ago
+func createScheduledTestJob(t *testing.T, database *db.DB) (uuid.UUID, int64) {
+	t.Helper()
+	ctx := context.Background()
+
+	user := testutil.CreateTestUser(t, database, "scheduleuser", "schedule@example.com", testutil.DefaultTestPassword, "user")
+	hashlist := &models.HashList{
+		Name:       "Scheduled job hashlist",
+		UserID:     user.ID,
+		ClientID:   uuid.Nil,
+		HashTypeID: 1000,
+		Status:     models.HashListStatusReady,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	require.NoError(t, NewHashListRepository(database).Create(ctx, hashlist))
+
+	var jobID uuid.UUID
+	err := database.QueryRowContext(ctx, `
+		INSERT INTO job_executions (hashlist_id, attack_mode, status, start_at)
+		VALUES ($1, 0, 'scheduled', NOW() - INTERVAL '1 hour')
+		RETURNING id`, hashlist.ID).Scan(&jobID)
+	require.NoError(t, err)
+	return jobID, hashlist.ID
+}
+
+func jobStatus(t *testing.T, database *db.DB, jobID uuid.UUID) string {
+	t.Helper()
+	var status string
+	require.NoError(t, database.QueryRowContext(context.Background(), `SELECT status FROM job_executions WHERE id = $1`, jobID).Scan(&status))
+	return status
+}
+
+func TestJobExecutionRepository_ReleaseScheduledJobs_SkipsTrashedJob(t *testing.T) {
+	database := testutil.SetupTestDB(t)
+	repo := NewJobExecutionRepository(database)
+	ctx := context.Background()
+
+	jobID, _ := createScheduledTestJob(t, database)
+	require.NoError(t, repo.SoftDelete(ctx, jobID, nil))
+
+	released, _, err := repo.ReleaseScheduledJobs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, released)
+	assert.Equal(t, "cancelled", jobStatus(t, database, jobID))
+}
+
+func TestJobExecutionRepository_ReleaseScheduledJobs_SkipsTrashedHashlist(t *testing.T) {
+	database := testutil.SetupTestDB(t)
+	repo := NewJobExecutionRepository(database)
+	ctx := context.Background()
+
+	jobID, hashlistID := createScheduledTestJob(t, database)
+	// Trashing through the repository is refused while the job is scheduled
+	assert.ErrorIs(t, NewHashListRepository(database).SoftDelete(ctx, hashlistID, nil), ErrHashlistHasActiveJobs)
+	_, err := database.ExecContext(ctx, `UPDATE hashlists SET deleted_at = NOW() WHERE id = $1`, hashlistID)
+	require.NoError(t, err)
+
+	released, _, err := repo.ReleaseScheduledJobs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, released)
+	assert.Equal(t, "scheduled", jobStatus(t, database, jobID))
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// maxJobNameSuffix bounds the search for a free job name
+const maxJobNameSuffix = 1000
+
+// jobNameScope is the key of a client's job names: its ID, or "none" for
+// hashlists without a client
+func jobNameScope(clientID uuid.UUID) string {
+	if clientID == uuid.Nil {
+		return "none"
+	}
+	return clientID.String()
+}
+
+// nullableClientID maps uuid.Nil to NULL
+func nullableClientID(clientID uuid.UUID) interface{} {
+	if clientID == uuid.Nil {
+		return nil
+	}
+	return clientID
+}
+
+// NextJobNameSequence returns the next {seq} of the client's generated job names
+func (r *JobExecutionRepository) NextJobNameSequence(ctx context.Context, clientID uuid.UUID) (int, error) {
+	var seq int
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO job_name_sequences (scope, last_value) VALUES ($1, 1)
+		ON CONFLICT (scope) DO UPDATE SET last_value = job_name_sequences.last_value + 1
+		RETURNING last_value`, jobNameScope(clientID)).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get next job name sequence: %w", err)
+	}
+	return seq, nil
+}
+
+// jobNameTaken reports whether another job of the client already has the
+// name, ignoring case
+func jobNameTaken(ctx context.Context, q Querier, clientID uuid.UUID, name string, excludeID uuid.UUID) (bool, error) {
+	var taken bool
+	err := q.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM job_executions je
+			JOIN hashlists h ON je.hashlist_id = h.id
+			WHERE LOWER(je.name) = LOWER($1)
+			  AND je.id <> $2
+			  AND h.client_id IS NOT DISTINCT FROM $3
+		)`, name, excludeID, nullableClientID(clientID)).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("failed to check job name: %w", err)
+	}
+	return taken, nil
+}
+
+// UniqueJobName returns the name, or the name with a " (n)" suffix if another
+// job of the client already has it
+func (r *JobExecutionRepository) UniqueJobName(ctx context.Context, clientID uuid.UUID, name string) (string, error) {
+	candidate := name
+	for n := 2; n <= maxJobNameSuffix; n++ {
+		taken, err := jobNameTaken(ctx, r.db, clientID, candidate, uuid.Nil)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = models.DisambiguateJobName(name, n)
+	}
+	return "", fmt.Errorf("no free job name for %q", name)
+}
+
+// Rename changes a job's name. Returns ErrNotFound if the job does not exist
+// and ErrDuplicateRecord if another job of the same client has the name.
+func (r *JobExecutionRepository) Rename(ctx context.Context, id uuid.UUID, name string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var clientID uuid.NullUUID
+	err = tx.QueryRowContext(ctx, `
+		SELECT h.client_id
+		FROM job_executions je
+		JOIN hashlists h ON je.hashlist_id = h.id
+		WHERE je.id = $1
+		FOR UPDATE OF je`, id).Scan(&clientID)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get job client: %w", err)
+	}
+
+	// Renames within a client are serialized so two jobs cannot take the same name
+	scope := jobNameScope(clientID.UUID)
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('job_name:' || $1))`, scope); err != nil {
+		return fmt.Errorf("failed to lock job names: %w", err)
+	}
+
+	taken, err := jobNameTaken(ctx, tx, clientID.UUID, name, id)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrDuplicateRecord
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE job_executions SET name = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, name, id); err != nil {
+		return fmt.Errorf("failed to rename job execution: %w", err)
+	}
+	return tx.Commit()
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // JobTaskRepository handles database operations for job tasks
@@ -50,9 +52,9 @@ func (r *JobTaskRepository) Create(ctx context.Context, task *models.JobTask) er
 			effective_keyspace_start, effective_keyspace_end, effective_keyspace_processed,
 			benchmark_speed, chunk_duration,
 			rule_start_index, rule_end_index, rule_chunk_path, is_rule_split_task,
-			chunk_number, is_actual_keyspace
+			chunk_number, is_actual_keyspace, speculative_of, verification_of
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 		RETURNING id, assigned_at, created_at, updated_at`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -75,6 +77,8 @@ func (r *JobTaskRepository) Create(ctx context.Context, task *models.JobTask) er
 		task.IsRuleSplitTask,
 		task.ChunkNumber,
 		task.IsActualKeyspace,
+		task.SpeculativeOf,
+		task.VerificationOf,
 	).Scan(&task.ID, &task.AssignedAt, &task.CreatedAt, &task.UpdatedAt)
 
 	if err != nil {
@@ -145,6 +149,7 @@ func (r *JobTaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 			jt.benchmark_speed, jt.average_speed, jt.chunk_duration, jt.assigned_at,
 			jt.started_at, jt.completed_at, jt.last_checkpoint, jt.error_message,
 			jt.rule_start_index, jt.rule_end_index, jt.rule_chunk_path, jt.is_rule_split_task,
+			jt.speculative_of, jt.verification_of, jt.chunk_overlap, jt.checkpoint_keyspace, jt.resume_offset,
 			a.name as agent_name
 		FROM job_tasks jt
 		JOIN agents a ON jt.agent_id = a.id
@@ -158,6 +163,7 @@ func (r *JobTaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 		&task.BenchmarkSpeed, &task.AverageSpeed, &task.ChunkDuration, &task.AssignedAt,
 		&task.StartedAt, &task.CompletedAt, &task.LastCheckpoint, &task.ErrorMessage,
 		&task.RuleStartIndex, &task.RuleEndIndex, &task.RuleChunkPath, &task.IsRuleSplitTask,
+		&task.SpeculativeOf, &task.VerificationOf, &task.ChunkOverlap, &task.CheckpointKeyspace, &task.ResumeOffset,
 		&task.AgentName,
 	)
 
@@ -182,7 +188,8 @@ func (r *JobTaskRepository) GetTasksByJobExecution(ctx context.Context, jobExecu
 			jt.started_at, jt.completed_at, jt.last_checkpoint, jt.error_message,
 			jt.crack_count,
 			jt.rule_start_index, jt.rule_end_index, jt.rule_chunk_path, jt.is_rule_split_task,
-			jt.progress_percent,
+			jt.progress_percent, jt.speculative_of, jt.verification_of, jt.reused_from, jt.chunk_overlap,
+			jt.checkpoint_keyspace, jt.resume_offset,
 			a.name as agent_name
 		FROM job_tasks jt
 		LEFT JOIN agents a ON jt.agent_id = a.id
@@ -206,7 +213,8 @@ func (r *JobTaskRepository) GetTasksByJobExecution(ctx context.Context, jobExecu
 			&task.StartedAt, &task.CompletedAt, &task.LastCheckpoint, &task.ErrorMessage,
 			&task.CrackCount,
 			&task.RuleStartIndex, &task.RuleEndIndex, &task.RuleChunkPath, &task.IsRuleSplitTask,
-			&task.ProgressPercent,
+			&task.ProgressPercent, &task.SpeculativeOf, &task.VerificationOf, &task.ReusedFrom, &task.ChunkOverlap,
+			&task.CheckpointKeyspace, &task.ResumeOffset,
 			&task.AgentName,
 		)
 		if err != nil {
@@ -332,17 +340,43 @@ func (r *JobTaskRepository) GetTasksNotUpdatedSince(ctx context.Context, since t
 	return tasks, nil
 }
 
+// GetSlowHashJobExecutionIDs returns which of the given jobs crack a slow hash type
+func (r *JobTaskRepository) GetSlowHashJobExecutionIDs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	query := `
+		SELECT je.id
+		FROM job_executions je
+		JOIN hash_types ht ON ht.id = je.hash_type
+		WHERE je.id = ANY($1::uuid[]) AND ht.slow`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(jobIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slow hash jobs: %w", err)
+	}
+	defer rows.Close()
+
+	slow := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan job id: %w", err)
+		}
+		slow[id] = true
+	}
+	return slow, rows.Err()
+}
+
 // UpdateTaskError marks a task as failed with an error message
 func (r *JobTaskRepository) UpdateTaskError(ctx context.Context, taskID uuid.UUID, errorMessage string) error {
 	query := `
 		UPDATE job_tasks
 		SET status = $2,
 		    error_message = $3,
+		    error_code = $4,
 		    completed_at = NOW(),
 		    updated_at = NOW()
 		WHERE id = $1`
 
-	_, err := r.db.ExecContext(ctx, query, taskID, models.JobTaskStatusFailed, errorMessage)
+	_, err := r.db.ExecContext(ctx, query, taskID, models.JobTaskStatusFailed, errorMessage, models.ClassifyTaskError(errorMessage))
 	if err != nil {
 		return fmt.Errorf("failed to update task error: %w", err)
 	}
@@ -465,6 +499,31 @@ func (r *JobTaskRepository) UpdateProgress(ctx context.Context, id uuid.UUID, ke
 	return nil
 }
 
+// RecordTaskSpeed folds a progress hash rate into the task's running mean
+// observed speed. The first sample also captures the agent's measured
+// benchmark speed for the job, so later benchmark runs don't move the
+// baseline the task is scored against.
+func (r *JobTaskRepository) RecordTaskSpeed(ctx context.Context, id uuid.UUID, hashRate int64) error {
+	query := `
+		UPDATE job_tasks jt SET
+			observed_speed = (COALESCE(jt.observed_speed, 0) * jt.speed_samples + $1) / (jt.speed_samples + 1),
+			speed_samples = jt.speed_samples + 1,
+			expected_speed = COALESCE(jt.expected_speed, (
+				SELECT COALESCE(ab.measured_speed, ab.speed)
+				FROM agent_benchmarks ab
+				JOIN job_executions je ON je.id = jt.job_execution_id
+				WHERE ab.agent_id = jt.agent_id
+				AND ab.attack_mode = je.attack_mode
+				AND ab.hash_type = je.hash_type
+			))
+		WHERE jt.id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, hashRate, id); err != nil {
+		return fmt.Errorf("failed to record task speed: %w", err)
+	}
+	return nil
+}
+
 // CompleteTask marks a task as completed
 func (r *JobTaskRepository) CompleteTask(ctx context.Context, id uuid.UUID) error {
 	now := time.Now()
@@ -528,8 +587,8 @@ func (r *JobTaskRepository) CompleteTask(ctx context.Context, id uuid.UUID) erro
 func (r *JobTaskRepository) FailTask(ctx context.Context, id uuid.UUID, errorMessage string) error {
 	now := time.Now()
 	// Update both status and detailed_status to maintain database constraint consistency
-	query := `UPDATE job_tasks SET status = $1, detailed_status = $2, completed_at = $3, error_message = $4 WHERE id = $5`
-	result, err := r.db.ExecContext(ctx, query, models.JobTaskStatusFailed, "failed", now, errorMessage, id)
+	query := `UPDATE job_tasks SET status = $1, detailed_status = $2, completed_at = $3, error_message = $4, error_code = $5 WHERE id = $6`
+	result, err := r.db.ExecContext(ctx, query, models.JobTaskStatusFailed, "failed", now, errorMessage, models.ClassifyTaskError(errorMessage), id)
 	if err != nil {
 		return fmt.Errorf("failed to fail job task: %w", err)
 	}
@@ -621,7 +680,8 @@ func (r *JobTaskRepository) GetIncompleteTasksCount(ctx context.Context, jobExec
 	query := `
 		SELECT COUNT(*)
 		FROM job_tasks
-		WHERE job_execution_id = $1 AND status NOT IN ('completed', 'cancelled')`
+		WHERE job_execution_id = $1 AND status NOT IN ('completed', 'cancelled')
+			AND verification_of IS NULL`
 
 	var count int
 	err := r.db.QueryRowContext(ctx, query, jobExecutionID).Scan(&count)
@@ -713,7 +773,8 @@ func (r *JobTaskRepository) UpdateTaskStatus(ctx context.Context, id uuid.UUID,
 	return nil
 }
 
-// ResetTaskForRetry resets a task for retry by incrementing retry count and resetting status
+// ResetTaskForRetry resets a task for retry by incrementing retry count and resetting status.
+// The task's checkpoint is kept so the retry can resume from it.
 func (r *JobTaskRepository) ResetTaskForRetry(ctx context.Context, id uuid.UUID) error {
 	// First, get the current task details including keyspace range
 	var jobExecutionID uuid.UUID
@@ -744,11 +805,13 @@ func (r *JobTaskRepository) ResetTaskForRetry(ctx context.Context, id uuid.UUID)
 			started_at = NULL,
 			completed_at = NULL,
 			error_message = NULL,
+			error_code = NULL,
 			keyspace_processed = 0,
 			effective_keyspace_processed = 0,
 			progress_percent = 0,
 			agent_id = NULL,
 			last_checkpoint = NULL,
+			resume_offset = 0,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1`
 	_, err = tx.ExecContext(ctx, query, id)
@@ -1387,6 +1450,74 @@ func (r *JobTaskRepository) UpdateTaskEffectiveKeyspace(ctx context.Context, tas
 	return nil
 }
 
+// SetChunkOverlap records how many candidates before its start a task was dispatched with
+func (r *JobTaskRepository) SetChunkOverlap(ctx context.Context, taskID uuid.UUID, overlap int64) error {
+	query := `UPDATE job_tasks SET chunk_overlap = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, taskID, overlap)
+	if err != nil {
+		return fmt.Errorf("failed to update task chunk overlap: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SetBinaryVersion records the binary version a task was dispatched with
+func (r *JobTaskRepository) SetBinaryVersion(ctx context.Context, taskID uuid.UUID, binaryVersionID int) error {
+	query := `UPDATE job_tasks SET binary_version_id = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, taskID, binaryVersionID)
+	if err != nil {
+		return fmt.Errorf("failed to update task binary version: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SetBinaryAttestation records the agent's check of its hashcat binary for a task
+func (r *JobTaskRepository) SetBinaryAttestation(ctx context.Context, taskID uuid.UUID, attestation *models.BinaryAttestation) error {
+	raw, err := json.Marshal(attestation)
+	if err != nil {
+		return fmt.Errorf("failed to encode binary attestation: %w", err)
+	}
+
+	query := `UPDATE job_tasks SET binary_attestation = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, taskID, raw)
+	if err != nil {
+		return fmt.Errorf("failed to update task binary attestation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 // UpdateTaskEffectiveKeyspaceWithChunkSize updates effective keyspace values and stores the actual chunk size
 // This enables self-correcting cascade updates for subsequent chunks
 func (r *JobTaskRepository) UpdateTaskEffectiveKeyspaceWithChunkSize(ctx context.Context, taskID uuid.UUID, effectiveKeyspaceStart, effectiveKeyspaceEnd, chunkActualKeyspace int64) error {
@@ -1509,3 +1640,259 @@ func (r *JobTaskRepository) GetTaskCountForJob(ctx context.Context, jobExecution
 
 	return count, nil
 }
+
+// GetSpeculationCandidates returns running tasks that have taken more than
+// stragglerFactor times their chunk duration, belong to a running job that is
+// at least minJobProgress percent done and have never been speculatively
+// re-dispatched. Highest priority jobs and the oldest tasks come first.
+func (r *JobTaskRepository) GetSpeculationCandidates(ctx context.Context, stragglerFactor float64, minJobProgress float64) ([]models.JobTask, error) {
+	query := `
+		SELECT
+			jt.id, jt.job_execution_id, jt.agent_id, jt.status, COALESCE(jt.priority, 0), COALESCE(jt.attack_cmd, ''),
+			jt.keyspace_start, jt.keyspace_end,
+			jt.effective_keyspace_start, jt.effective_keyspace_end,
+			jt.benchmark_speed, jt.chunk_duration, jt.started_at,
+			jt.rule_start_index, jt.rule_end_index, jt.rule_chunk_path, jt.is_rule_split_task,
+			COALESCE(jt.chunk_number, 0), COALESCE(jt.is_actual_keyspace, false)
+		FROM job_tasks jt
+		JOIN job_executions je ON jt.job_execution_id = je.id
+		WHERE jt.status = 'running'
+			AND jt.speculative_of IS NULL
+			AND jt.verification_of IS NULL
+			AND jt.started_at IS NOT NULL
+			AND jt.chunk_duration > 0
+			AND jt.started_at < NOW() - make_interval(secs => jt.chunk_duration * $1)
+			AND je.status = 'running'
+			AND je.deleted_at IS NULL
+			AND je.overall_progress_percent >= $2
+			AND NOT EXISTS (SELECT 1 FROM job_tasks c WHERE c.speculative_of = jt.id)
+		ORDER BY je.priority DESC, jt.started_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, stragglerFactor, minJobProgress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get speculation candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []models.JobTask
+	for rows.Next() {
+		var task models.JobTask
+		err := rows.Scan(
+			&task.ID, &task.JobExecutionID, &task.AgentID, &task.Status, &task.Priority, &task.AttackCmd,
+			&task.KeyspaceStart, &task.KeyspaceEnd,
+			&task.EffectiveKeyspaceStart, &task.EffectiveKeyspaceEnd,
+			&task.BenchmarkSpeed, &task.ChunkDuration, &task.StartedAt,
+			&task.RuleStartIndex, &task.RuleEndIndex, &task.RuleChunkPath, &task.IsRuleSplitTask,
+			&task.ChunkNumber, &task.IsActualKeyspace,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan speculation candidate: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// GetSpeculativePeers returns the other tasks covering the same chunk as the
+// given task: its original when it is a speculative copy, or its copies when
+// it is the original. Only id, agent and status are populated.
+func (r *JobTaskRepository) GetSpeculativePeers(ctx context.Context, task *models.JobTask) ([]models.JobTask, error) {
+	originalID := task.ID
+	if task.SpeculativeOf != nil {
+		originalID = *task.SpeculativeOf
+	}
+
+	query := `
+		SELECT id, agent_id, status
+		FROM job_tasks
+		WHERE id <> $1 AND (id = $2 OR speculative_of = $2)`
+
+	rows, err := r.db.QueryContext(ctx, query, task.ID, originalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get speculative peers: %w", err)
+	}
+	defer rows.Close()
+
+	var peers []models.JobTask
+	for rows.Next() {
+		var peer models.JobTask
+		if err := rows.Scan(&peer.ID, &peer.AgentID, &peer.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan speculative peer: %w", err)
+		}
+		peers = append(peers, peer)
+	}
+
+	return peers, rows.Err()
+}
+
+// ReuseDuplicateChunk looks for a completed chunk in another job with the same
+// attack fingerprint against the same hashlist, starting where the given job's
+// next chunk would.
+// Keyspace chunks must start at keyspaceStart; rule-split chunks, when ruleStart
+// is set, must start at that rule index. If one exists it is copied into the job
+// as an already completed task and returned; otherwise nil is returned.
+// For rule-split chunks effectiveStart places the copy in this job's effective keyspace.
+func (r *JobTaskRepository) ReuseDuplicateChunk(ctx context.Context, jobExecutionID uuid.UUID, keyspaceStart int64, ruleStart *int, effectiveStart *int64, chunkNumber int) (*models.JobTask, error) {
+	query := `
+		WITH donor AS (
+			SELECT d.*
+			FROM job_tasks d
+			JOIN job_executions dj ON dj.id = d.job_execution_id
+			JOIN job_executions j ON j.id = $1
+			WHERE dj.id <> j.id
+				AND d.status = 'completed'
+				AND dj.hashlist_id = j.hashlist_id
+				AND dj.attack_fingerprint = j.attack_fingerprint
+				AND d.is_rule_split_task = ($3::int IS NOT NULL)
+				AND CASE WHEN $3::int IS NULL THEN d.keyspace_start = $2 ELSE d.rule_start_index = $3 END
+			ORDER BY d.completed_at
+			LIMIT 1
+		)
+		INSERT INTO job_tasks (
+			job_execution_id, agent_id, status, priority, attack_cmd,
+			keyspace_start, keyspace_end, keyspace_processed,
+			effective_keyspace_start, effective_keyspace_end, effective_keyspace_processed,
+			benchmark_speed, average_speed, chunk_duration,
+			rule_start_index, rule_end_index, is_rule_split_task,
+			chunk_number, is_actual_keyspace, chunk_actual_keyspace,
+			progress_percent, started_at, completed_at, reused_from
+		)
+		SELECT
+			$1, donor.agent_id, 'completed', j.priority, donor.attack_cmd,
+			donor.keyspace_start, donor.keyspace_end, donor.keyspace_end - donor.keyspace_start,
+			COALESCE($4, donor.effective_keyspace_start),
+			COALESCE($4, donor.effective_keyspace_start) + (donor.effective_keyspace_end - donor.effective_keyspace_start),
+			donor.effective_keyspace_processed,
+			donor.benchmark_speed, donor.average_speed, donor.chunk_duration,
+			donor.rule_start_index, donor.rule_end_index, donor.is_rule_split_task,
+			$5, donor.is_actual_keyspace, donor.chunk_actual_keyspace,
+			100, NOW(), NOW(), donor.id
+		FROM donor
+		JOIN job_executions j ON j.id = $1
+		RETURNING id, agent_id, status, keyspace_start, keyspace_end, keyspace_processed,
+			effective_keyspace_start, effective_keyspace_end, effective_keyspace_processed,
+			rule_start_index, rule_end_index, is_rule_split_task, chunk_number, reused_from`
+
+	task := models.JobTask{JobExecutionID: jobExecutionID}
+	err := r.db.QueryRowContext(ctx, query, jobExecutionID, keyspaceStart, ruleStart, effectiveStart, chunkNumber).Scan(
+		&task.ID, &task.AgentID, &task.Status, &task.KeyspaceStart, &task.KeyspaceEnd, &task.KeyspaceProcessed,
+		&task.EffectiveKeyspaceStart, &task.EffectiveKeyspaceEnd, &task.EffectiveKeyspaceProcessed,
+		&task.RuleStartIndex, &task.RuleEndIndex, &task.IsRuleSplitTask, &task.ChunkNumber, &task.ReusedFrom,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reuse duplicate chunk: %w", err)
+	}
+
+	return &task, nil
+}
+
+// UpdateCheckpoint records the absolute keyspace position of a task's latest
+// hashcat restore point. Checkpoints only move forward.
+func (r *JobTaskRepository) UpdateCheckpoint(ctx context.Context, taskID uuid.UUID, checkpoint int64) error {
+	query := `
+		UPDATE job_tasks
+		SET checkpoint_keyspace = GREATEST(COALESCE(checkpoint_keyspace, 0), $2)
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, taskID, checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to update task checkpoint: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetCheckpoint returns the checkpoint of a task, nil when none was reported
+func (r *JobTaskRepository) GetCheckpoint(ctx context.Context, taskID uuid.UUID) (*int64, error) {
+	var checkpoint *int64
+	err := r.db.QueryRowContext(ctx, `SELECT checkpoint_keyspace FROM job_tasks WHERE id = $1`, taskID).Scan(&checkpoint)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// SetResumeOffset records how many candidates after its start a task was dispatched from
+func (r *JobTaskRepository) SetResumeOffset(ctx context.Context, taskID uuid.UUID, offset int64) error {
+	query := `UPDATE job_tasks SET resume_offset = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, taskID, offset)
+	if err != nil {
+		return fmt.Errorf("failed to update task resume offset: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetLastForegroundActivity returns when the agent last worked on a task of a
+// normal, non-background job, nil if it never has
+func (r *JobTaskRepository) GetLastForegroundActivity(ctx context.Context, agentID int) (*time.Time, error) {
+	query := `
+		SELECT MAX(COALESCE(jt.completed_at, jt.updated_at))
+		FROM job_tasks jt
+		JOIN job_executions je ON je.id = jt.job_execution_id
+		WHERE jt.agent_id = $1 AND je.is_background = false`
+
+	var lastActivity sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, agentID).Scan(&lastActivity); err != nil {
+		return nil, fmt.Errorf("failed to get last foreground activity of agent: %w", err)
+	}
+	if !lastActivity.Valid {
+		return nil, nil
+	}
+	return &lastActivity.Time, nil
+}
+
+// GetActiveBackgroundTasks retrieves the assigned and running tasks of background jobs
+func (r *JobTaskRepository) GetActiveBackgroundTasks(ctx context.Context) ([]models.JobTask, error) {
+	query := `
+		SELECT jt.id, jt.job_execution_id, jt.agent_id, jt.status
+		FROM job_tasks jt
+		JOIN job_executions je ON je.id = jt.job_execution_id
+		WHERE je.is_background = true
+			AND jt.status IN ('assigned', 'running')
+			AND jt.agent_id IS NOT NULL
+		ORDER BY jt.assigned_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active background tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []models.JobTask
+	for rows.Next() {
+		var task models.JobTask
+		if err := rows.Scan(&task.ID, &task.JobExecutionID, &task.AgentID, &task.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan job task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/google/uuid"
@@ -17,7 +19,7 @@ func (r *JobTaskRepository) GetAllTasksByJobExecution(ctx context.Context, jobEx
 			COALESCE(crack_count, 0) as crack_count,
 			COALESCE(detailed_status, 'pending') as detailed_status,
 			COALESCE(retry_count, 0) as retry_count,
-			error_message,
+			error_message, error_code,
 			created_at, started_at, completed_at, updated_at,
 			effective_keyspace_start, effective_keyspace_end, effective_keyspace_processed,
 			rule_start_index, rule_end_index, is_rule_split_task,
@@ -44,7 +46,7 @@ func (r *JobTaskRepository) GetAllTasksByJobExecution(ctx context.Context, jobEx
 			&task.KeyspaceStart, &task.KeyspaceEnd, &task.KeyspaceProcessed,
 			&task.BenchmarkSpeed, &task.ChunkDuration,
 			&task.CrackCount, &task.DetailedStatus, &task.RetryCount,
-			&task.ErrorMessage,
+			&task.ErrorMessage, &task.ErrorCode,
 			&task.CreatedAt, &task.StartedAt, &task.CompletedAt, &task.UpdatedAt,
 			&task.EffectiveKeyspaceStart, &task.EffectiveKeyspaceEnd, &task.EffectiveKeyspaceProcessed,
 			&task.RuleStartIndex, &task.RuleEndIndex, &task.IsRuleSplitTask,
@@ -53,6 +55,9 @@ func (r *JobTaskRepository) GetAllTasksByJobExecution(ctx context.Context, jobEx
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan job task: %w", err)
 		}
+		if task.ErrorCode != nil {
+			task.RemediationHint = task.ErrorCode.RemediationHint()
+		}
 		tasks = append(tasks, task)
 	}
 
@@ -111,6 +116,99 @@ func (r *JobTaskRepository) GetTasksByJobExecutionWithPagination(ctx context.Con
 	return tasks, nil
 }
 
+// TaskListFilter selects the tasks returned by ListTasks. Zero fields do not filter.
+type TaskListFilter struct {
+	JobExecutionID *uuid.UUID
+	Status         string
+	AgentID        *int
+	Since          *time.Time // Tasks updated at or after this time
+}
+
+// ListTasks retrieves a filtered, sorted page of tasks, of one job execution
+// or across jobs, along with the total number of matching tasks. orderBy must
+// be a whitelisted expression; an empty value keeps the default
+// most-recent-first ordering.
+func (r *JobTaskRepository) ListTasks(ctx context.Context, filter TaskListFilter, orderBy string, limit, offset int) ([]models.JobTask, int, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.JobExecutionID != nil {
+		addCondition("job_execution_id = $%d", *filter.JobExecutionID)
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	}
+	if filter.AgentID != nil {
+		addCondition("agent_id = $%d", *filter.AgentID)
+	}
+	if filter.Since != nil {
+		addCondition("updated_at >= $%d", *filter.Since)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM job_tasks"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	if orderBy == "" {
+		orderBy = "CASE WHEN status = 'completed' THEN completed_at ELSE created_at END DESC"
+	}
+
+	query := `
+		SELECT
+			id, job_execution_id, agent_id, status, keyspace_start, keyspace_end,
+			keyspace_processed, benchmark_speed, chunk_duration,
+			COALESCE(crack_count, 0) as crack_count,
+			COALESCE(detailed_status, 'pending') as detailed_status,
+			COALESCE(retry_count, 0) as retry_count,
+			error_message, error_code,
+			created_at, started_at, completed_at, updated_at,
+			effective_keyspace_start, effective_keyspace_end, effective_keyspace_processed,
+			rule_start_index, rule_end_index, is_rule_split_task,
+			progress_percent, average_speed
+		FROM job_tasks` + where + `
+		ORDER BY ` + orderBy + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.JobTask{}
+	for rows.Next() {
+		var task models.JobTask
+		err := rows.Scan(
+			&task.ID, &task.JobExecutionID, &task.AgentID, &task.Status,
+			&task.KeyspaceStart, &task.KeyspaceEnd, &task.KeyspaceProcessed,
+			&task.BenchmarkSpeed, &task.ChunkDuration,
+			&task.CrackCount, &task.DetailedStatus, &task.RetryCount,
+			&task.ErrorMessage, &task.ErrorCode,
+			&task.CreatedAt, &task.StartedAt, &task.CompletedAt, &task.UpdatedAt,
+			&task.EffectiveKeyspaceStart, &task.EffectiveKeyspaceEnd, &task.EffectiveKeyspaceProcessed,
+			&task.RuleStartIndex, &task.RuleEndIndex, &task.IsRuleSplitTask,
+			&task.ProgressPercent, &task.AverageSpeed,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan job task: %w", err)
+		}
+		if task.ErrorCode != nil {
+			task.RemediationHint = task.ErrorCode.RemediationHint()
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, total, rows.Err()
+}
+
 // GetActiveTasksByJobExecution retrieves all active tasks for a job execution
 func (r *JobTaskRepository) GetActiveTasksByJobExecution(ctx context.Context, jobExecutionID uuid.UUID) ([]models.JobTask, error) {
 	query := `
@@ -262,7 +360,8 @@ func (r *JobTaskRepository) AreAllTasksComplete(ctx context.Context, jobExecutio
 		SELECT COUNT(*) = 0
 		FROM job_tasks
 		WHERE job_execution_id = $1
-		AND status NOT IN ($2, $3, $4, $5)`
+		AND status NOT IN ($2, $3, $4, $5)
+		AND verification_of IS NULL`
 
 	var allTasksComplete bool
 	err := r.db.QueryRowContext(ctx, query,
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// JobThroughputFilter selects and groups the throughput history. Every job
+// of a hash type is summarized together unless grouped by attack mode or
+// bucketed by period.
+type JobThroughputFilter struct {
+	HashType     *int
+	AttackMode   *int
+	Since        *time.Time
+	ByAttackMode bool
+	Bucket       string
+}
+
+// JobThroughputRepository reads the throughput history of completed jobs,
+// which the record_job_execution_throughput trigger maintains
+type JobThroughputRepository struct {
+	db *db.DB
+}
+
+// NewJobThroughputRepository creates a new job throughput repository
+func NewJobThroughputRepository(database *db.DB) *JobThroughputRepository {
+	return &JobThroughputRepository{db: database}
+}
+
+// Summarize totals the history matching the filter per hash type, and per
+// attack mode and period when grouped by them, ordered by hash type and then
+// newest period first. Speeds are left to the caller.
+func (r *JobThroughputRepository) Summarize(ctx context.Context, filter JobThroughputFilter) ([]models.JobThroughput, error) {
+	if filter.Bucket != "" && !models.IsValidThroughputBucket(filter.Bucket) {
+		return nil, fmt.Errorf("invalid throughput bucket %q", filter.Bucket)
+	}
+
+	attackMode, period := "NULL::INTEGER", "NULL::TIMESTAMPTZ"
+	groupBy := []string{"t.hash_type", "ht.name"}
+	orderBy := []string{"t.hash_type"}
+	if filter.ByAttackMode {
+		attackMode = "t.attack_mode"
+		groupBy = append(groupBy, attackMode)
+		orderBy = append(orderBy, attackMode)
+	}
+	if filter.Bucket != "" {
+		period = fmt.Sprintf("date_trunc('%s', t.completed_at)", filter.Bucket)
+		groupBy = append(groupBy, period)
+		orderBy = append(orderBy, period+" DESC")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.hash_type, COALESCE(ht.name, ''), %s, %s,
+			COUNT(*), SUM(t.keyspace)::FLOAT8, SUM(t.wall_seconds), SUM(t.task_seconds),
+			AVG(t.hash_count)::FLOAT8, AVG(t.agent_count)::FLOAT8,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY t.keyspace::FLOAT8 / t.wall_seconds),
+			MIN(t.completed_at), MAX(t.completed_at)
+		FROM job_throughput_history t
+		LEFT JOIN hash_types ht ON ht.id = t.hash_type
+		WHERE ($1::INTEGER IS NULL OR t.hash_type = $1)
+			AND ($2::INTEGER IS NULL OR t.attack_mode = $2)
+			AND ($3::TIMESTAMPTZ IS NULL OR t.completed_at >= $3)
+		GROUP BY %s
+		ORDER BY %s`,
+		attackMode, period, strings.Join(groupBy, ", "), strings.Join(orderBy, ", "))
+
+	rows, err := r.db.QueryContext(ctx, query, filter.HashType, filter.AttackMode, filter.Since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize job throughput: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []models.JobThroughput{}
+	for rows.Next() {
+		var t models.JobThroughput
+		if err := rows.Scan(&t.HashType, &t.HashTypeName, &t.AttackMode, &t.Period,
+			&t.Jobs, &t.Keyspace, &t.WallSeconds, &t.TaskSeconds, &t.AvgHashes, &t.AvgAgents,
+			&t.MedianJobSpeed, &t.FirstCompletedAt, &t.LastCompletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan job throughput: %w", err)
+		}
+		summaries = append(summaries, t)
+	}
+	return summaries, rows.Err()
+}
@@ -13,11 +13,11 @@ import (
 
 // JobWorkflowRepository defines the interface for interacting with job workflows and steps.
 type JobWorkflowRepository interface {
-	CreateWorkflow(ctx context.Context, name string) (*models.JobWorkflow, error)
+	CreateWorkflow(ctx context.Context, name string, inheritance models.WorkflowPriorityInheritance) (*models.JobWorkflow, error)
 	GetWorkflowByID(ctx context.Context, id uuid.UUID) (*models.JobWorkflow, error)
 	GetWorkflowByName(ctx context.Context, name string) (*models.JobWorkflow, error)
 	ListWorkflows(ctx context.Context) ([]models.JobWorkflow, error)
-	UpdateWorkflow(ctx context.Context, id uuid.UUID, name string) (*models.JobWorkflow, error)
+	UpdateWorkflow(ctx context.Context, id uuid.UUID, name string, inheritance models.WorkflowPriorityInheritance) (*models.JobWorkflow, error)
 	DeleteWorkflow(ctx context.Context, id uuid.UUID) error
 
 	CreateWorkflowStep(ctx context.Context, workflowID, presetJobID uuid.UUID, stepOrder int) (*models.JobWorkflowStep, error)
@@ -40,12 +40,14 @@ func NewJobWorkflowRepository(db *sql.DB) JobWorkflowRepository {
 }
 
 // CreateWorkflow inserts a new job workflow.
-func (r *jobWorkflowRepository) CreateWorkflow(ctx context.Context, name string) (*models.JobWorkflow, error) {
-	query := `INSERT INTO job_workflows (name) VALUES ($1) RETURNING id, name, created_at, updated_at`
-	row := r.db.QueryRowContext(ctx, query, name)
+func (r *jobWorkflowRepository) CreateWorkflow(ctx context.Context, name string, inheritance models.WorkflowPriorityInheritance) (*models.JobWorkflow, error) {
+	query := `
+		INSERT INTO job_workflows (name, inherit_priority, priority_boost) VALUES ($1, $2, $3)
+		RETURNING id, name, inherit_priority, priority_boost, created_at, updated_at`
+	row := r.db.QueryRowContext(ctx, query, name, inheritance.InheritPriority, inheritance.PriorityBoost)
 
 	var wf models.JobWorkflow
-	err := row.Scan(&wf.ID, &wf.Name, &wf.CreatedAt, &wf.UpdatedAt)
+	err := row.Scan(&wf.ID, &wf.Name, &wf.InheritPriority, &wf.PriorityBoost, &wf.CreatedAt, &wf.UpdatedAt)
 	if err != nil {
 		// TODO: Handle potential unique constraint violation error (e.g., convert pq error)
 		debug.Error("Error creating job workflow: %v", err)
@@ -56,11 +58,11 @@ func (r *jobWorkflowRepository) CreateWorkflow(ctx context.Context, name string)
 
 // GetWorkflowByID retrieves a job workflow by ID, including its steps.
 func (r *jobWorkflowRepository) GetWorkflowByID(ctx context.Context, id uuid.UUID) (*models.JobWorkflow, error) {
-	query := `SELECT id, name, created_at, updated_at FROM job_workflows WHERE id = $1 LIMIT 1`
+	query := `SELECT id, name, inherit_priority, priority_boost, created_at, updated_at FROM job_workflows WHERE id = $1 LIMIT 1`
 	row := r.db.QueryRowContext(ctx, query, id)
 
 	var wf models.JobWorkflow
-	err := row.Scan(&wf.ID, &wf.Name, &wf.CreatedAt, &wf.UpdatedAt)
+	err := row.Scan(&wf.ID, &wf.Name, &wf.InheritPriority, &wf.PriorityBoost, &wf.CreatedAt, &wf.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("job workflow not found: %w", ErrNotFound)
@@ -83,11 +85,11 @@ func (r *jobWorkflowRepository) GetWorkflowByID(ctx context.Context, id uuid.UUI
 
 // GetWorkflowByName retrieves a job workflow by name.
 func (r *jobWorkflowRepository) GetWorkflowByName(ctx context.Context, name string) (*models.JobWorkflow, error) {
-	query := `SELECT id, name, created_at, updated_at FROM job_workflows WHERE name = $1 LIMIT 1`
+	query := `SELECT id, name, inherit_priority, priority_boost, created_at, updated_at FROM job_workflows WHERE name = $1 LIMIT 1`
 	row := r.db.QueryRowContext(ctx, query, name)
 
 	var wf models.JobWorkflow
-	err := row.Scan(&wf.ID, &wf.Name, &wf.CreatedAt, &wf.UpdatedAt)
+	err := row.Scan(&wf.ID, &wf.Name, &wf.InheritPriority, &wf.PriorityBoost, &wf.CreatedAt, &wf.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("job workflow not found: %w", ErrNotFound)
@@ -102,10 +104,10 @@ func (r *jobWorkflowRepository) GetWorkflowByName(ctx context.Context, name stri
 // ListWorkflows retrieves all job workflows.
 func (r *jobWorkflowRepository) ListWorkflows(ctx context.Context) ([]models.JobWorkflow, error) {
 	query := `
-		SELECT w.id, w.name, w.created_at, w.updated_at, COUNT(s.id) as step_count
+		SELECT w.id, w.name, w.inherit_priority, w.priority_boost, w.created_at, w.updated_at, COUNT(s.id) as step_count
 		FROM job_workflows w
 		LEFT JOIN job_workflow_steps s ON w.id = s.job_workflow_id
-		GROUP BY w.id, w.name, w.created_at, w.updated_at
+		GROUP BY w.id, w.name, w.inherit_priority, w.priority_boost, w.created_at, w.updated_at
 		ORDER BY w.name
 	` // TODO: Pagination
 	rows, err := r.db.QueryContext(ctx, query)
@@ -119,7 +121,7 @@ func (r *jobWorkflowRepository) ListWorkflows(ctx context.Context) ([]models.Job
 	for rows.Next() {
 		var wf models.JobWorkflow
 		var stepCount int
-		if err := rows.Scan(&wf.ID, &wf.Name, &wf.CreatedAt, &wf.UpdatedAt, &stepCount); err != nil {
+		if err := rows.Scan(&wf.ID, &wf.Name, &wf.InheritPriority, &wf.PriorityBoost, &wf.CreatedAt, &wf.UpdatedAt, &stepCount); err != nil {
 			debug.Error("Error scanning job workflow row: %v", err)
 			return nil, fmt.Errorf("error scanning job workflow row: %w", err)
 		}
@@ -138,13 +140,15 @@ func (r *jobWorkflowRepository) ListWorkflows(ctx context.Context) ([]models.Job
 	return workflows, nil
 }
 
-// UpdateWorkflow updates a job workflow's name.
-func (r *jobWorkflowRepository) UpdateWorkflow(ctx context.Context, id uuid.UUID, name string) (*models.JobWorkflow, error) {
-	query := `UPDATE job_workflows SET name = $2, updated_at = NOW() WHERE id = $1 RETURNING id, name, created_at, updated_at`
-	row := r.db.QueryRowContext(ctx, query, id, name)
+// UpdateWorkflow updates a job workflow's name and priority inheritance.
+func (r *jobWorkflowRepository) UpdateWorkflow(ctx context.Context, id uuid.UUID, name string, inheritance models.WorkflowPriorityInheritance) (*models.JobWorkflow, error) {
+	query := `
+		UPDATE job_workflows SET name = $2, inherit_priority = $3, priority_boost = $4, updated_at = NOW() WHERE id = $1
+		RETURNING id, name, inherit_priority, priority_boost, created_at, updated_at`
+	row := r.db.QueryRowContext(ctx, query, id, name, inheritance.InheritPriority, inheritance.PriorityBoost)
 
 	var wf models.JobWorkflow
-	err := row.Scan(&wf.ID, &wf.Name, &wf.CreatedAt, &wf.UpdatedAt)
+	err := row.Scan(&wf.ID, &wf.Name, &wf.InheritPriority, &wf.PriorityBoost, &wf.CreatedAt, &wf.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("job workflow not found for update: %w", ErrNotFound)
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const maintenanceWindowColumns = `
+	id, message, drain_timeout_seconds, started_at, started_by, started_by_username,
+	force_stopped_at, stopped_tasks, ended_at, ended_by, ended_by_username`
+
+// MaintenanceRepository stores the windows of system-wide maintenance mode
+type MaintenanceRepository struct {
+	db *db.DB
+}
+
+// NewMaintenanceRepository creates a new maintenance repository
+func NewMaintenanceRepository(database *db.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{db: database}
+}
+
+// Start opens a maintenance window. It returns ErrDuplicateRecord if one is
+// already open.
+func (r *MaintenanceRepository) Start(ctx context.Context, req models.StartMaintenanceRequest, startedBy uuid.UUID) (*models.MaintenanceWindow, error) {
+	query := `
+		INSERT INTO maintenance_windows (message, drain_timeout_seconds, started_by, started_by_username)
+		VALUES ($1, $2, $3, COALESCE((SELECT username FROM users WHERE id = $3), ''))
+		RETURNING` + maintenanceWindowColumns
+
+	window, err := scanMaintenanceWindow(r.db.QueryRowContext(ctx, query, req.Message, req.DrainTimeoutSeconds, startedBy))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return nil, ErrDuplicateRecord
+		}
+		return nil, fmt.Errorf("failed to start maintenance window: %w", err)
+	}
+	return window, nil
+}
+
+// GetActive returns the open maintenance window, or nil if there is none
+func (r *MaintenanceRepository) GetActive(ctx context.Context) (*models.MaintenanceWindow, error) {
+	query := `SELECT` + maintenanceWindowColumns + ` FROM maintenance_windows WHERE ended_at IS NULL`
+
+	window, err := scanMaintenanceWindow(r.db.QueryRowContext(ctx, query))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active maintenance window: %w", err)
+	}
+	return window, nil
+}
+
+// ClaimForceStop marks the open window as force-stopped. It reports false if
+// the window was already force-stopped or has ended, so only one backend stops
+// the running tasks.
+func (r *MaintenanceRepository) ClaimForceStop(ctx context.Context, id int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE maintenance_windows SET force_stopped_at = NOW()
+		WHERE id = $1 AND force_stopped_at IS NULL AND ended_at IS NULL`,
+		id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim maintenance force stop: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// SetStoppedTasks records how many tasks the force stop of a window stopped
+func (r *MaintenanceRepository) SetStoppedTasks(ctx context.Context, id int64, count int) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE maintenance_windows SET stopped_tasks = $2 WHERE id = $1`, id, count)
+	if err != nil {
+		return fmt.Errorf("failed to set stopped tasks of maintenance window: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// End closes the open maintenance window, or returns ErrNotFound if there is none
+func (r *MaintenanceRepository) End(ctx context.Context, endedBy uuid.UUID) (*models.MaintenanceWindow, error) {
+	query := `
+		UPDATE maintenance_windows
+		SET ended_at = NOW(), ended_by = $1,
+			ended_by_username = COALESCE((SELECT username FROM users WHERE id = $1), '')
+		WHERE ended_at IS NULL
+		RETURNING` + maintenanceWindowColumns
+
+	window, err := scanMaintenanceWindow(r.db.QueryRowContext(ctx, query, endedBy))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to end maintenance window: %w", err)
+	}
+	return window, nil
+}
+
+// List returns the most recent maintenance windows, newest first
+func (r *MaintenanceRepository) List(ctx context.Context, limit int) ([]models.MaintenanceWindow, error) {
+	query := `SELECT` + maintenanceWindowColumns + ` FROM maintenance_windows ORDER BY started_at DESC LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []models.MaintenanceWindow{}
+	for rows.Next() {
+		window, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, *window)
+	}
+	return windows, rows.Err()
+}
+
+// scanMaintenanceWindow scans a row selected with maintenanceWindowColumns
+func scanMaintenanceWindow(row rowScanner) (*models.MaintenanceWindow, error) {
+	var w models.MaintenanceWindow
+	err := row.Scan(
+		&w.ID, &w.Message, &w.DrainTimeoutSeconds, &w.StartedAt, &w.StartedBy, &w.StartedByUsername,
+		&w.ForceStoppedAt, &w.StoppedTasks, &w.EndedAt, &w.EndedBy, &w.EndedByUsername,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// digestClaimLease is how long a claimed digest is left to the backend that
+// claimed it. A claim older than this is taken to belong to a backend that
+// stopped before sending, and the digest can be claimed again.
+const digestClaimLease = "15 minutes"
+
+// NotificationDigestRepository handles database operations for notification
+// digests
+type NotificationDigestRepository struct {
+	db *db.DB
+}
+
+// NewNotificationDigestRepository creates a new notification digest repository
+func NewNotificationDigestRepository(db *db.DB) *NotificationDigestRepository {
+	return &NotificationDigestRepository{db: db}
+}
+
+// GetMode returns the digest mode of a user
+func (r *NotificationDigestRepository) GetMode(ctx context.Context, userID uuid.UUID) (string, error) {
+	var mode string
+	err := r.db.QueryRowContext(ctx, `SELECT notification_digest FROM users WHERE id = $1`, userID).Scan(&mode)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get notification digest: %w", err)
+	}
+	return mode, nil
+}
+
+// SetMode changes the digest mode of a user. Switching from off starts the
+// first digest period now.
+func (r *NotificationDigestRepository) SetMode(ctx context.Context, userID uuid.UUID, mode string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET
+			last_digest_sent_at = CASE
+				WHEN notification_digest = 'off' OR last_digest_sent_at IS NULL THEN NOW()
+				ELSE last_digest_sent_at END,
+			notification_digest = $2,
+			updated_at = NOW()
+		WHERE id = $1`, userID, mode)
+	if err != nil {
+		return fmt.Errorf("failed to update notification digest: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListDue returns the users whose digest period has ended at now, leaving out
+// digests another backend is sending
+func (r *NotificationDigestRepository) ListDue(ctx context.Context, now time.Time) ([]models.DigestRecipient, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, email, notification_digest, COALESCE(last_digest_sent_at, created_at)
+		FROM users
+		WHERE notification_digest <> 'off'
+			AND COALESCE(last_digest_sent_at, created_at) <= $1 - CASE notification_digest
+				WHEN 'hourly' THEN INTERVAL '1 hour'
+				ELSE INTERVAL '1 day' END
+			AND (digest_claimed_at IS NULL OR digest_claimed_at < $1 - $2::interval)
+		ORDER BY id`, now, digestClaimLease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due notification digests: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []models.DigestRecipient
+	for rows.Next() {
+		var recipient models.DigestRecipient
+		if err := rows.Scan(&recipient.UserID, &recipient.Email, &recipient.Mode, &recipient.LastSentAt); err != nil {
+			return nil, fmt.Errorf("failed to scan digest recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, rows.Err()
+}
+
+// Claim claims a user's digest period ending at until, unless another
+// backend did so first, and calls send. The claim is committed before send is
+// called so no row stays locked while the email goes out. Once send succeeds
+// the period moves on to until; when it fails the claim is cleared so the
+// digest is sent again at the next check. It reports whether the caller sent
+// the digest ending at until.
+func (r *NotificationDigestRepository) Claim(ctx context.Context, userID uuid.UUID, lastSentAt, until time.Time, send func() error) (bool, error) {
+	var claimedAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE users SET digest_claimed_at = NOW()
+		WHERE id = $1 AND COALESCE(last_digest_sent_at, created_at) = $2
+			AND (digest_claimed_at IS NULL OR digest_claimed_at < NOW() - $3::interval)
+		RETURNING digest_claimed_at`,
+		userID, lastSentAt, digestClaimLease).Scan(&claimedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim notification digest: %w", err)
+	}
+
+	if sendErr := send(); sendErr != nil {
+		if _, err := r.db.ExecContext(ctx, `
+			UPDATE users SET digest_claimed_at = NULL
+			WHERE id = $1 AND digest_claimed_at = $2`, userID, claimedAt); err != nil {
+			// The claim expires after digestClaimLease anyway
+			debug.Error("Failed to release notification digest claim of user %s: %v", userID, err)
+		}
+		return false, sendErr
+	}
+
+	// The claim is the token, a backend that took over an expired claim has
+	// replaced it
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE users SET last_digest_sent_at = $3, digest_claimed_at = NULL
+		WHERE id = $1 AND digest_claimed_at = $2`, userID, claimedAt, until); err != nil {
+		return true, fmt.Errorf("failed to record sent notification digest: %w", err)
+	}
+	return true, nil
+}
+
+// Build gathers the jobs, cracks and agent incidents of a user between since
+// and until
+func (r *NotificationDigestRepository) Build(ctx context.Context, userID uuid.UUID, since, until time.Time) (*models.NotificationDigest, error) {
+	digest := &models.NotificationDigest{Since: since, Until: until}
+
+	jobRows, err := r.db.QueryContext(ctx, `
+		SELECT je.id, COALESCE(je.name, ''), COALESCE(h.name, ''), je.status, je.completed_at
+		FROM job_executions je
+		LEFT JOIN hashlists h ON h.id = je.hashlist_id
+		WHERE je.created_by = $1
+			AND je.status IN ('completed', 'failed', 'cancelled', 'superseded')
+			AND je.completed_at > $2 AND je.completed_at <= $3
+		ORDER BY je.completed_at`, userID, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest jobs: %w", err)
+	}
+	defer jobRows.Close()
+	for jobRows.Next() {
+		var job models.DigestJob
+		if err := jobRows.Scan(&job.ID, &job.Name, &job.HashlistName, &job.Status, &job.CompletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan digest job: %w", err)
+		}
+		digest.Jobs = append(digest.Jobs, job)
+	}
+	if err := jobRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating digest jobs: %w", err)
+	}
+
+	// Cracks are counted from the hash_cracked events, kept for
+	// domain_event_retention_days
+	crackRows, err := r.db.QueryContext(ctx, `
+		SELECT h.id, h.name, SUM((e.payload->>'count')::int)
+		FROM domain_events e
+		JOIN hashlists h ON h.id = (e.payload->>'hashlist_id')::bigint
+		WHERE e.event_type = 'hash_cracked' AND h.user_id = $1
+			AND e.created_at > $2 AND e.created_at <= $3
+		GROUP BY h.id, h.name
+		ORDER BY h.name`, userID, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest cracks: %w", err)
+	}
+	defer crackRows.Close()
+	for crackRows.Next() {
+		var cracks models.DigestCracks
+		if err := crackRows.Scan(&cracks.HashlistID, &cracks.HashlistName, &cracks.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan digest cracks: %w", err)
+		}
+		digest.Cracks = append(digest.Cracks, cracks)
+	}
+	if err := crackRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating digest cracks: %w", err)
+	}
+
+	incidentRows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, a.name, 'offline', COALESCE(e.payload->>'reason', ''), e.created_at
+		FROM domain_events e
+		JOIN agents a ON a.id = (e.payload->>'agent_id')::int
+		WHERE e.event_type = 'agent_offline' AND a.owner_id = $1
+			AND e.created_at > $2 AND e.created_at <= $3
+		UNION ALL
+		SELECT a.id, a.name, c.kind, c.summary, c.occurred_at
+		FROM agent_crash_reports c
+		JOIN agents a ON a.id = c.agent_id
+		WHERE a.owner_id = $1 AND c.occurred_at > $2 AND c.occurred_at <= $3
+		ORDER BY 5`, userID, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest agent incidents: %w", err)
+	}
+	defer incidentRows.Close()
+	for incidentRows.Next() {
+		var incident models.DigestAgentIncident
+		if err := incidentRows.Scan(&incident.AgentID, &incident.AgentName, &incident.Kind, &incident.Detail, &incident.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan digest agent incident: %w", err)
+		}
+		digest.Incidents = append(digest.Incidents, incident)
+	}
+	if err := incidentRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating digest agent incidents: %w", err)
+	}
+
+	return digest, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDigestRepositoryMock(t *testing.T) (*NotificationDigestRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+	return NewNotificationDigestRepository(&db.DB{DB: mockDB}), mock
+}
+
+func TestNotificationDigestClaim(t *testing.T) {
+	userID := uuid.New()
+	until := time.Now()
+	lastSentAt := until.Add(-time.Hour)
+	claimedAt := until.Add(time.Second)
+
+	expectClaim := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("UPDATE users SET digest_claimed_at = NOW\\(\\)").
+			WithArgs(userID, lastSentAt, digestClaimLease).
+			WillReturnRows(sqlmock.NewRows([]string{"digest_claimed_at"}).AddRow(claimedAt))
+	}
+
+	t.Run("claim committed before sending", func(t *testing.T) {
+		repo, mock := newDigestRepositoryMock(t)
+		expectClaim(mock)
+
+		sent := false
+		claimed, err := repo.Claim(context.Background(), userID, lastSentAt, until, func() error {
+			// No transaction is open while sending
+			require.NoError(t, mock.ExpectationsWereMet())
+			mock.ExpectExec("UPDATE users SET last_digest_sent_at").
+				WithArgs(userID, claimedAt, until).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			sent = true
+			return nil
+		})
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		assert.True(t, sent)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("released when sending fails", func(t *testing.T) {
+		repo, mock := newDigestRepositoryMock(t)
+		expectClaim(mock)
+		mock.ExpectExec("UPDATE users SET digest_claimed_at = NULL").
+			WithArgs(userID, claimedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		sendErr := errors.New("smtp unavailable")
+		claimed, err := repo.Claim(context.Background(), userID, lastSentAt, until, func() error {
+			return sendErr
+		})
+		assert.ErrorIs(t, err, sendErr)
+		assert.False(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not sent when claimed by another backend", func(t *testing.T) {
+		repo, mock := newDigestRepositoryMock(t)
+		mock.ExpectQuery("UPDATE users SET digest_claimed_at = NOW\\(\\)").
+			WithArgs(userID, lastSentAt, digestClaimLease).
+			WillReturnRows(sqlmock.NewRows([]string{"digest_claimed_at"}))
+
+		claimed, err := repo.Claim(context.Background(), userID, lastSentAt, until, func() error {
+			t.Fatal("digest sent without a claim")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.False(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
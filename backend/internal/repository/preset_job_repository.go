@@ -23,9 +23,10 @@ type PresetJobRepository interface {
 
 // PresetJobFormData holds lists needed for preset job forms.
 type PresetJobFormData struct {
-	Wordlists      []models.WordlistBasic      `json:"wordlists"`
-	Rules          []models.RuleBasic          `json:"rules"`
-	BinaryVersions []models.BinaryVersionBasic `json:"binary_versions"`
+	Wordlists           []models.WordlistBasic      `json:"wordlists"`
+	WordlistCollections []models.WordlistCollection `json:"wordlist_collections"`
+	Rules               []models.RuleBasic          `json:"rules"`
+	BinaryVersions      []models.BinaryVersionBasic `json:"binary_versions"`
 }
 
 // presetJobRepository implements PresetJobRepository.
@@ -42,25 +43,25 @@ func NewPresetJobRepository(db *sql.DB) PresetJobRepository {
 func (r *presetJobRepository) Create(ctx context.Context, params models.PresetJob) (*models.PresetJob, error) {
 	query := `
 		INSERT INTO preset_jobs (
-			name, wordlist_ids, rule_ids, attack_mode, priority, 
+			name, wordlist_ids, wordlist_collection_id, rule_ids, attack_mode, priority, 
 			chunk_size_seconds, status_updates_enabled, 
-			allow_high_priority_override, binary_version_id, mask, keyspace, max_agents
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING id, name, wordlist_ids, rule_ids, attack_mode, priority, chunk_size_seconds, 
+			allow_high_priority_override, binary_version_id, mask, mask_increment, keyspace, max_agents
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, name, wordlist_ids, wordlist_collection_id, rule_ids, attack_mode, priority, chunk_size_seconds, 
 				  status_updates_enabled, allow_high_priority_override, 
-				  binary_version_id, mask, keyspace, max_agents, created_at, updated_at`
+				  binary_version_id, mask, mask_increment, keyspace, max_agents, needs_review, created_at, updated_at`
 
 	row := r.db.QueryRowContext(ctx, query,
-		params.Name, params.WordlistIDs, params.RuleIDs, params.AttackMode, params.Priority,
+		params.Name, params.WordlistIDs, params.WordlistCollectionID, params.RuleIDs, params.AttackMode, params.Priority,
 		params.ChunkSizeSeconds, params.StatusUpdatesEnabled,
-		params.AllowHighPriorityOverride, params.BinaryVersionID, params.Mask, params.Keyspace, params.MaxAgents,
+		params.AllowHighPriorityOverride, params.BinaryVersionID, params.Mask, params.MaskIncrement, params.Keyspace, params.MaxAgents,
 	)
 
 	var created models.PresetJob
 	err := row.Scan(
-		&created.ID, &created.Name, &created.WordlistIDs, &created.RuleIDs, &created.AttackMode, &created.Priority,
+		&created.ID, &created.Name, &created.WordlistIDs, &created.WordlistCollectionID, &created.RuleIDs, &created.AttackMode, &created.Priority,
 		&created.ChunkSizeSeconds, &created.StatusUpdatesEnabled,
-		&created.AllowHighPriorityOverride, &created.BinaryVersionID, &created.Mask, &created.Keyspace, &created.MaxAgents, &created.CreatedAt, &created.UpdatedAt,
+		&created.AllowHighPriorityOverride, &created.BinaryVersionID, &created.Mask, &created.MaskIncrement, &created.Keyspace, &created.MaxAgents, &created.NeedsReview, &created.CreatedAt, &created.UpdatedAt,
 	)
 	if err != nil {
 		debug.Error("Error creating preset job: %v", err)
@@ -73,17 +74,17 @@ func (r *presetJobRepository) Create(ctx context.Context, params models.PresetJo
 func (r *presetJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PresetJob, error) {
 	query := `
 		SELECT 
-			id, name, wordlist_ids, rule_ids, attack_mode, priority, chunk_size_seconds, 
+			id, name, wordlist_ids, wordlist_collection_id, rule_ids, attack_mode, priority, chunk_size_seconds, 
 			status_updates_enabled, allow_high_priority_override, 
-			binary_version_id, mask, keyspace, max_agents, created_at, updated_at 
+			binary_version_id, mask, mask_increment, keyspace, max_agents, needs_review, created_at, updated_at 
 		FROM preset_jobs WHERE id = $1 LIMIT 1`
 
 	row := r.db.QueryRowContext(ctx, query, id)
 	var job models.PresetJob
 	err := row.Scan(
-		&job.ID, &job.Name, &job.WordlistIDs, &job.RuleIDs, &job.AttackMode, &job.Priority,
+		&job.ID, &job.Name, &job.WordlistIDs, &job.WordlistCollectionID, &job.RuleIDs, &job.AttackMode, &job.Priority,
 		&job.ChunkSizeSeconds, &job.StatusUpdatesEnabled,
-		&job.AllowHighPriorityOverride, &job.BinaryVersionID, &job.Mask, &job.Keyspace, &job.MaxAgents, &job.CreatedAt, &job.UpdatedAt,
+		&job.AllowHighPriorityOverride, &job.BinaryVersionID, &job.Mask, &job.MaskIncrement, &job.Keyspace, &job.MaxAgents, &job.NeedsReview, &job.CreatedAt, &job.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -99,17 +100,17 @@ func (r *presetJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*model
 func (r *presetJobRepository) GetByName(ctx context.Context, name string) (*models.PresetJob, error) {
 	query := `
 		SELECT 
-			id, name, wordlist_ids, rule_ids, attack_mode, priority, chunk_size_seconds, 
+			id, name, wordlist_ids, wordlist_collection_id, rule_ids, attack_mode, priority, chunk_size_seconds, 
 			status_updates_enabled, allow_high_priority_override, 
-			binary_version_id, mask, keyspace, max_agents, created_at, updated_at 
+			binary_version_id, mask, mask_increment, keyspace, max_agents, needs_review, created_at, updated_at 
 		FROM preset_jobs WHERE name = $1 LIMIT 1`
 
 	row := r.db.QueryRowContext(ctx, query, name)
 	var job models.PresetJob
 	err := row.Scan(
-		&job.ID, &job.Name, &job.WordlistIDs, &job.RuleIDs, &job.AttackMode, &job.Priority,
+		&job.ID, &job.Name, &job.WordlistIDs, &job.WordlistCollectionID, &job.RuleIDs, &job.AttackMode, &job.Priority,
 		&job.ChunkSizeSeconds, &job.StatusUpdatesEnabled,
-		&job.AllowHighPriorityOverride, &job.BinaryVersionID, &job.Mask, &job.Keyspace, &job.MaxAgents, &job.CreatedAt, &job.UpdatedAt,
+		&job.AllowHighPriorityOverride, &job.BinaryVersionID, &job.Mask, &job.MaskIncrement, &job.Keyspace, &job.MaxAgents, &job.NeedsReview, &job.CreatedAt, &job.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -125,9 +126,9 @@ func (r *presetJobRepository) GetByName(ctx context.Context, name string) (*mode
 func (r *presetJobRepository) List(ctx context.Context) ([]models.PresetJob, error) {
 	query := `
 		SELECT 
-			pj.id, pj.name, pj.wordlist_ids, pj.rule_ids, pj.attack_mode, pj.priority, 
+			pj.id, pj.name, pj.wordlist_ids, pj.wordlist_collection_id, pj.rule_ids, pj.attack_mode, pj.priority, 
 			pj.chunk_size_seconds, pj.status_updates_enabled, 
-			pj.allow_high_priority_override, pj.binary_version_id, pj.mask, pj.keyspace, pj.max_agents, pj.created_at, pj.updated_at,
+			pj.allow_high_priority_override, pj.binary_version_id, pj.mask, pj.mask_increment, pj.keyspace, pj.max_agents, pj.needs_review, pj.created_at, pj.updated_at,
 			bv.file_name as binary_version_name
 		FROM preset_jobs pj
 		LEFT JOIN binary_versions bv ON pj.binary_version_id = bv.id
@@ -145,9 +146,9 @@ func (r *presetJobRepository) List(ctx context.Context) ([]models.PresetJob, err
 		var job models.PresetJob
 		var binaryVersionName sql.NullString
 		if err := rows.Scan(
-			&job.ID, &job.Name, &job.WordlistIDs, &job.RuleIDs, &job.AttackMode, &job.Priority,
+			&job.ID, &job.Name, &job.WordlistIDs, &job.WordlistCollectionID, &job.RuleIDs, &job.AttackMode, &job.Priority,
 			&job.ChunkSizeSeconds, &job.StatusUpdatesEnabled,
-			&job.AllowHighPriorityOverride, &job.BinaryVersionID, &job.Mask, &job.Keyspace, &job.MaxAgents, &job.CreatedAt, &job.UpdatedAt,
+			&job.AllowHighPriorityOverride, &job.BinaryVersionID, &job.Mask, &job.MaskIncrement, &job.Keyspace, &job.MaxAgents, &job.NeedsReview, &job.CreatedAt, &job.UpdatedAt,
 			&binaryVersionName,
 		); err != nil {
 			debug.Error("Error scanning preset job row: %v", err)
@@ -182,25 +183,29 @@ func (r *presetJobRepository) Update(ctx context.Context, id uuid.UUID, params m
 			allow_high_priority_override = $9,
 			binary_version_id = $10,
 			mask = $11,
-			keyspace = $12,
-			max_agents = $13,
+			mask_increment = $12,
+			keyspace = $13,
+			max_agents = $14,
+			wordlist_collection_id = $15,
+			needs_review = false,
 			updated_at = NOW()
 		WHERE id = $1
-		RETURNING id, name, wordlist_ids, rule_ids, attack_mode, priority, chunk_size_seconds, 
+		RETURNING id, name, wordlist_ids, wordlist_collection_id, rule_ids, attack_mode, priority, chunk_size_seconds, 
 				  status_updates_enabled, allow_high_priority_override, 
-				  binary_version_id, mask, keyspace, max_agents, created_at, updated_at`
+				  binary_version_id, mask, mask_increment, keyspace, max_agents, needs_review, created_at, updated_at`
 
 	row := r.db.QueryRowContext(ctx, query,
 		id, params.Name, params.WordlistIDs, params.RuleIDs, params.AttackMode, params.Priority,
 		params.ChunkSizeSeconds, params.StatusUpdatesEnabled,
-		params.AllowHighPriorityOverride, params.BinaryVersionID, params.Mask, params.Keyspace, params.MaxAgents,
+		params.AllowHighPriorityOverride, params.BinaryVersionID, params.Mask, params.MaskIncrement, params.Keyspace, params.MaxAgents,
+		params.WordlistCollectionID,
 	)
 
 	var updated models.PresetJob
 	err := row.Scan(
-		&updated.ID, &updated.Name, &updated.WordlistIDs, &updated.RuleIDs, &updated.AttackMode, &updated.Priority,
+		&updated.ID, &updated.Name, &updated.WordlistIDs, &updated.WordlistCollectionID, &updated.RuleIDs, &updated.AttackMode, &updated.Priority,
 		&updated.ChunkSizeSeconds, &updated.StatusUpdatesEnabled,
-		&updated.AllowHighPriorityOverride, &updated.BinaryVersionID, &updated.Mask, &updated.Keyspace, &updated.MaxAgents, &updated.CreatedAt, &updated.UpdatedAt,
+		&updated.AllowHighPriorityOverride, &updated.BinaryVersionID, &updated.Mask, &updated.MaskIncrement, &updated.Keyspace, &updated.MaxAgents, &updated.NeedsReview, &updated.CreatedAt, &updated.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -258,6 +263,29 @@ func (r *presetJobRepository) ListFormData(ctx context.Context) (*PresetJobFormD
 	}
 	rows.Close()
 
+	// Fetch Wordlist Collections
+	collectionQuery := `SELECT id, name, description, wordlist_ids FROM wordlist_collections ORDER BY name`
+	rows, err = r.db.QueryContext(ctx, collectionQuery)
+	if err != nil {
+		debug.Error("Error fetching wordlist collections for form data: %v", err)
+		return nil, fmt.Errorf("error fetching wordlist collections: %w", err)
+	}
+	for rows.Next() {
+		var c models.WordlistCollection
+		if scanErr := rows.Scan(&c.ID, &c.Name, &c.Description, &c.WordlistIDs); scanErr != nil {
+			rows.Close()
+			debug.Error("Error scanning wordlist collection row: %v", scanErr)
+			return nil, fmt.Errorf("error scanning wordlist collection: %w", scanErr)
+		}
+		formData.WordlistCollections = append(formData.WordlistCollections, c)
+	}
+	if err = rows.Err(); err != nil {
+		rows.Close()
+		debug.Error("Error iterating wordlist collection rows: %v", err)
+		return nil, fmt.Errorf("error iterating wordlist collections: %w", err)
+	}
+	rows.Close()
+
 	// Fetch Rules
 	ruleQuery := `SELECT id, name FROM rules ORDER BY name`
 	rows, err = r.db.QueryContext(ctx, ruleQuery)
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// QuickCrackRepository handles database operations for quick crack submissions
+type QuickCrackRepository struct {
+	db *db.DB
+}
+
+// NewQuickCrackRepository creates a new quick crack repository
+func NewQuickCrackRepository(database *db.DB) *QuickCrackRepository {
+	return &QuickCrackRepository{db: database}
+}
+
+const quickCrackColumns = `
+	id, user_id, hashlist_id, hash_type_id, workflow_id, job_ids::text[],
+	webhook_url, webhook_attempts, webhook_last_error, webhook_sent_at, created_at`
+
+// scanQuickCrack scans a row selected with quickCrackColumns
+func scanQuickCrack(row interface{ Scan(...interface{}) error }) (*models.QuickCrackSubmission, error) {
+	var s models.QuickCrackSubmission
+	var jobIDs []string
+	if err := row.Scan(
+		&s.ID, &s.UserID, &s.HashlistID, &s.HashTypeID, &s.WorkflowID, pq.Array(&jobIDs),
+		&s.WebhookURL, &s.WebhookAttempts, &s.WebhookLastError, &s.WebhookSentAt, &s.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	s.JobIDs = make([]uuid.UUID, 0, len(jobIDs))
+	for _, id := range jobIDs {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job id %q: %w", id, err)
+		}
+		s.JobIDs = append(s.JobIDs, parsed)
+	}
+	return &s, nil
+}
+
+// Create inserts a submission and fills in its ID and creation time
+func (r *QuickCrackRepository) Create(ctx context.Context, s *models.QuickCrackSubmission) error {
+	query := `
+		INSERT INTO quick_crack_submissions (user_id, hashlist_id, hash_type_id, workflow_id, webhook_url)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	err := r.db.QueryRowContext(ctx, query, s.UserID, s.HashlistID, s.HashTypeID, s.WorkflowID, s.WebhookURL).
+		Scan(&s.ID, &s.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create quick crack submission: %w", err)
+	}
+	return nil
+}
+
+// SetJobIDs records the jobs created for a submission
+func (r *QuickCrackRepository) SetJobIDs(ctx context.Context, id uuid.UUID, jobIDs []uuid.UUID) error {
+	ids := make([]string, len(jobIDs))
+	for i, jobID := range jobIDs {
+		ids[i] = jobID.String()
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE quick_crack_submissions SET job_ids = $2::uuid[] WHERE id = $1`, id, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to set quick crack jobs: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetByID returns a submission
+func (r *QuickCrackRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.QuickCrackSubmission, error) {
+	query := `SELECT ` + quickCrackColumns + ` FROM quick_crack_submissions WHERE id = $1`
+	s, err := scanQuickCrack(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quick crack submission: %w", err)
+	}
+	return s, nil
+}
+
+// ListPendingWebhooks returns the submissions created after since whose
+// results have not been delivered to their webhook yet
+func (r *QuickCrackRepository) ListPendingWebhooks(ctx context.Context, since time.Time) ([]models.QuickCrackSubmission, error) {
+	query := `SELECT ` + quickCrackColumns + `
+		FROM quick_crack_submissions
+		WHERE webhook_url IS NOT NULL AND webhook_sent_at IS NULL AND created_at >= $1
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending quick crack webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var submissions []models.QuickCrackSubmission
+	for rows.Next() {
+		s, err := scanQuickCrack(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quick crack submission: %w", err)
+		}
+		submissions = append(submissions, *s)
+	}
+	return submissions, rows.Err()
+}
+
+// RecordWebhookAttempt counts a delivery attempt. A nil deliveryErr marks the
+// results as delivered; giveUp marks them as done despite the error.
+func (r *QuickCrackRepository) RecordWebhookAttempt(ctx context.Context, id uuid.UUID, deliveryErr error, giveUp bool) error {
+	var lastError *string
+	if deliveryErr != nil {
+		message := deliveryErr.Error()
+		lastError = &message
+	}
+
+	query := `
+		UPDATE quick_crack_submissions
+		SET webhook_attempts = webhook_attempts + 1,
+			webhook_last_error = $2,
+			webhook_sent_at = CASE WHEN $3 THEN CURRENT_TIMESTAMP ELSE NULL END
+		WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, query, id, lastError, deliveryErr == nil || giveUp); err != nil {
+		return fmt.Errorf("failed to record quick crack webhook attempt: %w", err)
+	}
+	return nil
+}
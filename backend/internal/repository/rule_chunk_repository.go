@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// RuleChunkRepository stores the registry of generated rule chunk files
+type RuleChunkRepository struct {
+	db *db.DB
+}
+
+// NewRuleChunkRepository creates a new rule chunk repository
+func NewRuleChunkRepository(database *db.DB) *RuleChunkRepository {
+	return &RuleChunkRepository{db: database}
+}
+
+// Register records a rule chunk file, replacing the record of a chunk with
+// the same name when the chunk was generated again
+func (r *RuleChunkRepository) Register(ctx context.Context, chunk *models.RuleChunkFile) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO rule_chunk_files (job_execution_id, name, rule_start_index, rule_end_index, md5_hash, file_size)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE SET
+			rule_start_index = EXCLUDED.rule_start_index,
+			rule_end_index = EXCLUDED.rule_end_index,
+			md5_hash = EXCLUDED.md5_hash,
+			file_size = EXCLUDED.file_size
+		RETURNING id, created_at`,
+		chunk.JobExecutionID, chunk.Name, chunk.RuleStartIndex, chunk.RuleEndIndex, chunk.MD5Hash, chunk.FileSize,
+	).Scan(&chunk.ID, &chunk.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to register rule chunk %s: %w", chunk.Name, err)
+	}
+	return nil
+}
+
+// GetByName returns the rule chunk file with the given name, or ErrNotFound
+func (r *RuleChunkRepository) GetByName(ctx context.Context, name string) (*models.RuleChunkFile, error) {
+	var chunk models.RuleChunkFile
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, job_execution_id, name, rule_start_index, rule_end_index, md5_hash, file_size, created_at
+		FROM rule_chunk_files
+		WHERE name = $1`, name,
+	).Scan(&chunk.ID, &chunk.JobExecutionID, &chunk.Name, &chunk.RuleStartIndex, &chunk.RuleEndIndex,
+		&chunk.MD5Hash, &chunk.FileSize, &chunk.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rule chunk %s: %w", name, err)
+	}
+	return &chunk, nil
+}
+
+// DeleteByJobExecution removes the records of a job's rule chunks
+func (r *RuleChunkRepository) DeleteByJobExecution(ctx context.Context, jobExecutionID uuid.UUID) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM rule_chunk_files WHERE job_execution_id = $1`, jobExecutionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete rule chunks of job %s: %w", jobExecutionID, err)
+	}
+	return result.RowsAffected()
+}
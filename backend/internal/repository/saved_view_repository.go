@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const savedViewColumns = `
+	v.id, v.user_id, u.username, v.team_id, t.name, v.name, v.description,
+	v.resource, v.query, v.created_at, v.updated_at`
+
+const savedViewFrom = `
+	FROM saved_views v
+	JOIN users u ON v.user_id = u.id
+	LEFT JOIN teams t ON v.team_id = t.id`
+
+// savedViewVisible limits a query to the views of user $1: their own and
+// those shared with one of their teams
+const savedViewVisible = `(v.user_id = $1 OR v.team_id IN (SELECT team_id FROM user_teams WHERE user_id = $1))`
+
+// SavedViewRepository stores the saved views of the job, task and hash lists
+type SavedViewRepository struct {
+	db *db.DB
+}
+
+// NewSavedViewRepository creates a new saved view repository
+func NewSavedViewRepository(database *db.DB) *SavedViewRepository {
+	return &SavedViewRepository{db: database}
+}
+
+// Create saves a new view. Returns ErrDuplicateRecord if the owner already has
+// a view of that name for the resource.
+func (r *SavedViewRepository) Create(ctx context.Context, view *models.SavedView) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO saved_views (user_id, team_id, name, description, resource, query)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`,
+		view.UserID, view.TeamID, view.Name, view.Description, view.Resource, view.Query,
+	).Scan(&view.ID, &view.CreatedAt, &view.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return ErrDuplicateRecord
+		}
+		return fmt.Errorf("failed to create saved view: %w", err)
+	}
+	return nil
+}
+
+// GetVisible returns a view the user owns or shares through a team, or
+// ErrNotFound
+func (r *SavedViewRepository) GetVisible(ctx context.Context, id, userID uuid.UUID) (*models.SavedView, error) {
+	query := `SELECT` + savedViewColumns + savedViewFrom + `
+		WHERE ` + savedViewVisible + ` AND v.id = $2`
+
+	view, err := scanSavedView(r.db.QueryRowContext(ctx, query, userID, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	}
+	return view, nil
+}
+
+// ListVisible returns the views the user owns or shares through a team,
+// optionally only those of one resource, ordered by name
+func (r *SavedViewRepository) ListVisible(ctx context.Context, userID uuid.UUID, resource string) ([]models.SavedView, error) {
+	query := `SELECT` + savedViewColumns + savedViewFrom + `
+		WHERE ` + savedViewVisible
+	args := []interface{}{userID}
+	if resource != "" {
+		query += ` AND v.resource = $2`
+		args = append(args, resource)
+	}
+	query += ` ORDER BY v.resource, LOWER(v.name), v.id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved views: %w", err)
+	}
+	defer rows.Close()
+
+	views := []models.SavedView{}
+	for rows.Next() {
+		view, err := scanSavedView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved view: %w", err)
+		}
+		views = append(views, *view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating saved views: %w", err)
+	}
+	return views, nil
+}
+
+// Update replaces a view's settings. Only its owner can change it; returns
+// ErrNotFound for anyone else and ErrDuplicateRecord on a name clash.
+func (r *SavedViewRepository) Update(ctx context.Context, view *models.SavedView) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE saved_views
+		SET team_id = $3, name = $4, description = $5, resource = $6, query = $7, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING created_at, updated_at`,
+		view.ID, view.UserID, view.TeamID, view.Name, view.Description, view.Resource, view.Query,
+	).Scan(&view.CreatedAt, &view.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return ErrDuplicateRecord
+		}
+		return fmt.Errorf("failed to update saved view: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a view. Only its owner can delete it; returns ErrNotFound
+// for anyone else.
+func (r *SavedViewRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM saved_views WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// IsTeamMember reports whether the user belongs to the team
+func (r *SavedViewRepository) IsTeamMember(ctx context.Context, userID, teamID uuid.UUID) (bool, error) {
+	var member bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM user_teams WHERE user_id = $1 AND team_id = $2)`,
+		userID, teamID).Scan(&member)
+	if err != nil {
+		return false, fmt.Errorf("failed to check team membership: %w", err)
+	}
+	return member, nil
+}
+
+type savedViewScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSavedView(row savedViewScanner) (*models.SavedView, error) {
+	var view models.SavedView
+	err := row.Scan(
+		&view.ID, &view.UserID, &view.OwnerUsername, &view.TeamID, &view.TeamName,
+		&view.Name, &view.Description, &view.Resource, &view.Query,
+		&view.CreatedAt, &view.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
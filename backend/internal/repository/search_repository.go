@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// SearchParams filters a search over jobs and hashlists
+type SearchParams struct {
+	Query string   // Full-text query in web search syntax, empty matches everything
+	Tags  []string // Results must carry all of these tags
+	Type  string   // models.SearchResultJob, models.SearchResultHashlist or empty for both
+	Limit int
+}
+
+// SearchRepository searches jobs and hashlists by name, notes and tags
+type SearchRepository struct {
+	db *db.DB
+}
+
+// NewSearchRepository creates a new search repository
+func NewSearchRepository(database *db.DB) *SearchRepository {
+	return &SearchRepository{db: database}
+}
+
+// Search returns the jobs and hashlists matching the query and tags, best
+// matches first and newest first among equals. Names also match on a plain
+// substring so partial words and IDs can be found.
+func (r *SearchRepository) Search(ctx context.Context, params SearchParams) ([]models.SearchResult, error) {
+	query := `
+		WITH q AS (
+			SELECT CASE WHEN $1 = '' THEN NULL ELSE websearch_to_tsquery('english', $1) END AS query
+		)
+		SELECT type, id, name, notes, tags, status, hashlist_id, created_at, rank
+		FROM (
+			SELECT 'job' AS type, je.id::text AS id, COALESCE(je.name, '') AS name, je.notes, je.tags,
+				je.status, je.hashlist_id, je.created_at,
+				COALESCE(ts_rank(je.search_vector, q.query), 0) AS rank
+			FROM job_executions je, q
+			WHERE ($2 = '' OR $2 = 'job')
+				AND je.deleted_at IS NULL
+				AND (q.query IS NULL OR je.search_vector @@ q.query OR je.name ILIKE '%' || $1 || '%')
+				AND je.tags @> $3
+			UNION ALL
+			SELECT 'hashlist' AS type, h.id::text AS id, h.name, h.notes, h.tags,
+				h.status, h.id AS hashlist_id, h.created_at,
+				COALESCE(ts_rank(h.search_vector, q.query), 0) AS rank
+			FROM hashlists h, q
+			WHERE ($2 = '' OR $2 = 'hashlist')
+				AND h.deleted_at IS NULL
+				AND (q.query IS NULL OR h.search_vector @@ q.query OR h.name ILIKE '%' || $1 || '%')
+				AND h.tags @> $3
+		) results
+		ORDER BY rank DESC, created_at DESC
+		LIMIT $4
+	`
+	tags := params.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, params.Query, params.Type, pq.Array(tags), params.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search jobs and hashlists: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+	for rows.Next() {
+		var result models.SearchResult
+		if err := rows.Scan(
+			&result.Type,
+			&result.ID,
+			&result.Name,
+			&result.Notes,
+			pq.Array(&result.Tags),
+			&result.Status,
+			&result.HashlistID,
+			&result.CreatedAt,
+			&result.Rank,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w", err)
+	}
+	return results, nil
+}
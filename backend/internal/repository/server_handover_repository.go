@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// ServerHandoverRepository stores the assignment state of graceful restarts
+type ServerHandoverRepository struct {
+	db *db.DB
+}
+
+// NewServerHandoverRepository creates a new server handover repository
+func NewServerHandoverRepository(database *db.DB) *ServerHandoverRepository {
+	return &ServerHandoverRepository{db: database}
+}
+
+// Create records a handover
+func (r *ServerHandoverRepository) Create(ctx context.Context, handover *models.ServerHandover) error {
+	tasks, err := json.Marshal(handover.Tasks)
+	if err != nil {
+		return fmt.Errorf("failed to marshal handover tasks: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO server_handovers (reconnect_after_seconds, tasks)
+		VALUES ($1, $2)
+		RETURNING id, drained_at`,
+		handover.ReconnectAfterSeconds, tasks,
+	).Scan(&handover.ID, &handover.DrainedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create server handover: %w", err)
+	}
+	return nil
+}
+
+// ClaimLatest marks the latest handover recorded since the given time as
+// resumed and returns it, or nil if there is none. Older handovers that were
+// never resumed are claimed with it.
+func (r *ServerHandoverRepository) ClaimLatest(ctx context.Context, since time.Time) (*models.ServerHandover, error) {
+	var handover models.ServerHandover
+	var tasks []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, drained_at, reconnect_after_seconds, tasks
+		FROM server_handovers
+		WHERE resumed_at IS NULL AND drained_at >= $1
+		ORDER BY drained_at DESC
+		LIMIT 1`,
+		since,
+	).Scan(&handover.ID, &handover.DrainedAt, &handover.ReconnectAfterSeconds, &tasks)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server handover: %w", err)
+	}
+	if err := json.Unmarshal(tasks, &handover.Tasks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal handover tasks: %w", err)
+	}
+
+	now := time.Now()
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE server_handovers SET resumed_at = $1
+		WHERE resumed_at IS NULL AND drained_at <= $2`,
+		now, handover.DrainedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark server handover resumed: %w", err)
+	}
+	handover.ResumedAt = &now
+	return &handover, nil
+}
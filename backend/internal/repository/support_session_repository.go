@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+const supportSessionColumns = `
+	s.id, s.admin_id, s.admin_username, s.target_user_id, s.target_username, s.reason,
+	s.ip_address, s.user_agent, s.started_at, s.expires_at, s.ended_at`
+
+// SupportSessionRepository stores admin support sessions and their audit trail
+type SupportSessionRepository struct {
+	db *db.DB
+}
+
+// NewSupportSessionRepository creates a new support session repository
+func NewSupportSessionRepository(database *db.DB) *SupportSessionRepository {
+	return &SupportSessionRepository{db: database}
+}
+
+// Create starts a new support session and records it in the audit trail
+func (r *SupportSessionRepository) Create(ctx context.Context, session *models.SupportSession) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO support_sessions (admin_id, admin_username, target_user_id, target_username, reason, ip_address, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, started_at`
+	err = tx.QueryRowContext(ctx, query,
+		session.AdminID, session.AdminUsername, session.TargetUserID, session.TargetUsername,
+		session.Reason, session.IPAddress, session.UserAgent, session.ExpiresAt,
+	).Scan(&session.ID, &session.StartedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create support session: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO support_session_events (session_id, event_type, detail) VALUES ($1, $2, $3)`,
+		session.ID, models.SupportSessionEventStarted, session.Reason)
+	if err != nil {
+		return fmt.Errorf("failed to record support session start: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetByID returns a support session, or ErrNotFound
+func (r *SupportSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SupportSession, error) {
+	query := `SELECT` + supportSessionColumns + `, COALESCE(u.role, '')
+		FROM support_sessions s
+		LEFT JOIN users u ON s.target_user_id = u.id
+		WHERE s.id = $1`
+
+	session, err := scanSupportSession(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get support session: %w", err)
+	}
+	return session, nil
+}
+
+// GetActiveForAdmin returns the admin's support session if it is still
+// active and its target can still be acted as, or ErrNotFound
+func (r *SupportSessionRepository) GetActiveForAdmin(ctx context.Context, id, adminID uuid.UUID) (*models.SupportSession, error) {
+	query := `SELECT` + supportSessionColumns + `, u.role
+		FROM support_sessions s
+		JOIN users u ON s.target_user_id = u.id
+		WHERE s.id = $1
+		  AND s.admin_id = $2
+		  AND s.ended_at IS NULL
+		  AND s.expires_at > NOW()
+		  AND u.account_enabled = true`
+
+	session, err := scanSupportSession(r.db.QueryRowContext(ctx, query, id, adminID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active support session: %w", err)
+	}
+	return session, nil
+}
+
+// List returns a page of support sessions, most recent first
+func (r *SupportSessionRepository) List(ctx context.Context, limit, offset int) ([]models.SupportSession, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM support_sessions`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count support sessions: %w", err)
+	}
+
+	query := `SELECT` + supportSessionColumns + `, COALESCE(u.role, '')
+		FROM support_sessions s
+		LEFT JOIN users u ON s.target_user_id = u.id
+		ORDER BY s.started_at DESC
+		LIMIT $1 OFFSET $2`
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list support sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.SupportSession{}
+	for rows.Next() {
+		session, err := scanSupportSession(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan support session: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating support sessions: %w", err)
+	}
+	return sessions, total, nil
+}
+
+// End closes an active support session. Returns ErrNotFound if the session
+// does not exist or has already ended.
+func (r *SupportSessionRepository) End(ctx context.Context, id uuid.UUID, detail string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE support_sessions SET ended_at = NOW() WHERE id = $1 AND ended_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to end support session: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rows == 0 {
+		return ErrNotFound
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO support_session_events (session_id, event_type, detail) VALUES ($1, $2, $3)`,
+		id, models.SupportSessionEventEnded, detail)
+	if err != nil {
+		return fmt.Errorf("failed to record support session end: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// LogRequest records a request made, or refused, during a support session
+func (r *SupportSessionRepository) LogRequest(ctx context.Context, sessionID uuid.UUID, eventType models.SupportSessionEventType, method, path, detail string) error {
+	var detailValue *string
+	if detail != "" {
+		detailValue = &detail
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO support_session_events (session_id, event_type, method, path, detail) VALUES ($1, $2, $3, $4, $5)`,
+		sessionID, eventType, method, path, detailValue)
+	if err != nil {
+		return fmt.Errorf("failed to log support session request: %w", err)
+	}
+	return nil
+}
+
+// GetEvents returns a support session's audit trail in order
+func (r *SupportSessionRepository) GetEvents(ctx context.Context, sessionID uuid.UUID) ([]models.SupportSessionEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, session_id, event_type, method, path, detail, created_at
+		FROM support_session_events
+		WHERE session_id = $1
+		ORDER BY created_at, id`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get support session events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.SupportSessionEvent{}
+	for rows.Next() {
+		var event models.SupportSessionEvent
+		if err := rows.Scan(&event.ID, &event.SessionID, &event.EventType, &event.Method, &event.Path, &event.Detail, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan support session event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating support session events: %w", err)
+	}
+	return events, nil
+}
+
+type supportSessionScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSupportSession(row supportSessionScanner) (*models.SupportSession, error) {
+	var session models.SupportSession
+	err := row.Scan(
+		&session.ID, &session.AdminID, &session.AdminUsername, &session.TargetUserID, &session.TargetUsername,
+		&session.Reason, &session.IPAddress, &session.UserAgent, &session.StartedAt, &session.ExpiresAt,
+		&session.EndedAt, &session.TargetRole,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// SystemEventRepository stores the operational event log
+type SystemEventRepository struct {
+	db *db.DB
+}
+
+// NewSystemEventRepository creates a new system event repository
+func NewSystemEventRepository(database *db.DB) *SystemEventRepository {
+	return &SystemEventRepository{db: database}
+}
+
+// SystemEventFilter selects the events returned by List. Zero fields do not filter.
+type SystemEventFilter struct {
+	Severity   string
+	Category   string
+	EventType  string
+	EntityType string
+	EntityID   string
+	Search     string     // Case-insensitive substring of the message
+	Since      *time.Time // Events at or after this time
+	Until      *time.Time // Events before this time
+}
+
+// Create records an event, setting its ID and time
+func (r *SystemEventRepository) Create(ctx context.Context, event *models.SystemEvent) error {
+	var details []byte
+	if len(event.Details) > 0 {
+		var err error
+		if details, err = json.Marshal(event.Details); err != nil {
+			return fmt.Errorf("failed to encode system event details: %w", err)
+		}
+	}
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO system_events (severity, category, event_type, message, entity_type, entity_id, details)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7)
+		RETURNING id, occurred_at`,
+		event.Severity, event.Category, event.EventType, event.Message, event.EntityType, event.EntityID, details,
+	).Scan(&event.ID, &event.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("failed to create system event: %w", err)
+	}
+	return nil
+}
+
+// List retrieves a filtered, sorted page of events along with the total
+// number of matching events. orderBy must be a whitelisted expression; an
+// empty value keeps the default newest-first ordering.
+func (r *SystemEventRepository) List(ctx context.Context, filter SystemEventFilter, orderBy string, limit, offset int) ([]models.SystemEvent, int, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Severity != "" {
+		addCondition("severity = $%d", filter.Severity)
+	}
+	if filter.Category != "" {
+		addCondition("category = $%d", filter.Category)
+	}
+	if filter.EventType != "" {
+		addCondition("event_type = $%d", filter.EventType)
+	}
+	if filter.EntityType != "" {
+		addCondition("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		addCondition("entity_id = $%d", filter.EntityID)
+	}
+	if filter.Search != "" {
+		addCondition("message ILIKE $%d", "%"+filter.Search+"%")
+	}
+	if filter.Since != nil {
+		addCondition("occurred_at >= $%d", *filter.Since)
+	}
+	if filter.Until != nil {
+		addCondition("occurred_at < $%d", *filter.Until)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM system_events"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count system events: %w", err)
+	}
+
+	if orderBy == "" {
+		orderBy = "occurred_at DESC, id DESC"
+	}
+
+	query := `
+		SELECT id, occurred_at, severity, category, event_type, message,
+			COALESCE(entity_type, ''), COALESCE(entity_id, ''), details
+		FROM system_events` + where + `
+		ORDER BY ` + orderBy + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list system events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.SystemEvent{}
+	for rows.Next() {
+		var event models.SystemEvent
+		var details []byte
+		if err := rows.Scan(&event.ID, &event.OccurredAt, &event.Severity, &event.Category, &event.EventType,
+			&event.Message, &event.EntityType, &event.EntityID, &details); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan system event: %w", err)
+		}
+		if details != nil {
+			if err := json.Unmarshal(details, &event.Details); err != nil {
+				return nil, 0, fmt.Errorf("failed to decode system event details: %w", err)
+			}
+		}
+		events = append(events, event)
+	}
+	return events, total, rows.Err()
+}
+
+// DeleteOlderThan purges events that occurred before cutoff and returns how
+// many were deleted
+func (r *SystemEventRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM system_events WHERE occurred_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge system events: %w", err)
+	}
+	return result.RowsAffected()
+}
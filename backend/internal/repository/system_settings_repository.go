@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -134,6 +135,73 @@ func (r *SystemSettingsRepository) SetMaxJobPriority(ctx context.Context, maxPri
 	return r.SetSetting(ctx, "max_job_priority", &value)
 }
 
+// GetGPUHourCost retrieves the cost of one GPU-hour used for cost reports, 0 if unset.
+func (r *SystemSettingsRepository) GetGPUHourCost(ctx context.Context) (float64, error) {
+	setting, err := r.GetSetting(ctx, "gpu_hour_cost")
+	if err != nil {
+		if err == ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if setting.Value == nil || *setting.Value == "" {
+		return 0, nil
+	}
+
+	cost, err := strconv.ParseFloat(*setting.Value, 64)
+	if err != nil || cost < 0 {
+		debug.Error("Invalid gpu_hour_cost value in database: %s", *setting.Value)
+		return 0, nil
+	}
+
+	return cost, nil
+}
+
+// GetJobNameTemplate retrieves the template of generated job names, the
+// default if unset or invalid.
+func (r *SystemSettingsRepository) GetJobNameTemplate(ctx context.Context) (string, error) {
+	setting, err := r.GetSetting(ctx, models.JobNameTemplateSetting)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return models.DefaultJobNameTemplate, nil
+		}
+		return "", err
+	}
+
+	if setting.Value == nil || models.ValidateJobNameTemplate(*setting.Value) != nil {
+		return models.DefaultJobNameTemplate, nil
+	}
+	return *setting.Value, nil
+}
+
+// SetSettings updates several settings in one transaction, either all of them
+// change or none does.
+func (r *SystemSettingsRepository) SetSettings(ctx context.Context, values map[string]string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for key, value := range values {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE system_settings SET value = $1, updated_at = $2 WHERE key = $3`, value, now, key)
+		if err != nil {
+			return fmt.Errorf("failed to set system setting '%s': %w", key, err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return fmt.Errorf("system setting with key '%s' not found for update: %w", key, ErrNotFound)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit system settings: %w", err)
+	}
+	return nil
+}
+
 // UpdateSetting updates a specific setting's value by its key (alias for SetSetting with string value).
 func (r *SystemSettingsRepository) UpdateSetting(ctx context.Context, key string, value string) error {
 	return r.SetSetting(ctx, key, &value)
@@ -195,6 +263,52 @@ func (r *SystemSettingsRepository) GetAgentDownloadSettings(ctx context.Context)
 	return &settings, nil
 }
 
+// GetAgentPerformanceSettings retrieves the agent performance scoring settings
+func (r *SystemSettingsRepository) GetAgentPerformanceSettings(ctx context.Context) (*models.AgentPerformanceSettings, error) {
+	query := `
+		SELECT key, value
+		FROM system_settings
+		WHERE key LIKE 'agent_performance_%'`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent performance settings: %w", err)
+	}
+	defer rows.Close()
+
+	settings := models.GetDefaultAgentPerformanceSettings()
+	for rows.Next() {
+		var key string
+		var value *string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan agent performance setting row: %w", err)
+		}
+
+		if value == nil {
+			continue
+		}
+
+		val, err := strconv.Atoi(*value)
+		if err != nil || val < 1 {
+			continue
+		}
+		switch key {
+		case "agent_performance_window_tasks":
+			settings.WindowTasks = val
+		case "agent_performance_min_tasks":
+			settings.MinTasks = val
+		case "agent_performance_threshold_percent":
+			settings.ThresholdPercent = val
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agent performance setting rows: %w", err)
+	}
+
+	return &settings, nil
+}
+
 // UpdateAgentDownloadSettings updates all agent download settings
 func (r *SystemSettingsRepository) UpdateAgentDownloadSettings(ctx context.Context, settings *models.AgentDownloadSettings) error {
 	tx, err := r.db.BeginTx(ctx, nil)
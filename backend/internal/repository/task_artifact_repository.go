@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// TaskArtifactRepository handles database operations for the artifacts of finished task runs
+type TaskArtifactRepository struct {
+	db *db.DB
+}
+
+// NewTaskArtifactRepository creates a new task artifact repository
+func NewTaskArtifactRepository(db *db.DB) *TaskArtifactRepository {
+	return &TaskArtifactRepository{db: db}
+}
+
+// Create stores the artifacts of a finished task run
+func (r *TaskArtifactRepository) Create(ctx context.Context, artifact *models.TaskArtifact) error {
+	var finalStatus interface{}
+	if len(artifact.FinalStatus) > 0 {
+		finalStatus = []byte(artifact.FinalStatus)
+	}
+
+	query := `
+		INSERT INTO task_artifacts (task_id, job_execution_id, agent_id, status, exit_code, error_message, final_status, output_tail)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		artifact.TaskID,
+		artifact.JobExecutionID,
+		artifact.AgentID,
+		artifact.Status,
+		artifact.ExitCode,
+		artifact.ErrorMessage,
+		finalStatus,
+		artifact.OutputTail,
+	).Scan(&artifact.ID, &artifact.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create task artifact: %w", err)
+	}
+
+	artifact.OutputBytes = len(artifact.OutputTail)
+	return nil
+}
+
+// ListByTask returns the artifacts of every run of a task, newest first, without the output
+func (r *TaskArtifactRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.TaskArtifact, error) {
+	query := `
+		SELECT id, task_id, job_execution_id, agent_id, status, exit_code, error_message, final_status, '', OCTET_LENGTH(output_tail), created_at
+		FROM task_artifacts
+		WHERE task_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task artifacts: %w", err)
+	}
+	defer rows.Close()
+
+	artifacts := []models.TaskArtifact{}
+	for rows.Next() {
+		artifact, err := scanTaskArtifact(rows)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, *artifact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task artifacts: %w", err)
+	}
+
+	return artifacts, nil
+}
+
+// GetByID returns an artifact of a task including its output
+func (r *TaskArtifactRepository) GetByID(ctx context.Context, taskID uuid.UUID, id int64) (*models.TaskArtifact, error) {
+	query := `
+		SELECT id, task_id, job_execution_id, agent_id, status, exit_code, error_message, final_status, output_tail, OCTET_LENGTH(output_tail), created_at
+		FROM task_artifacts
+		WHERE task_id = $1 AND id = $2`
+
+	artifact, err := scanTaskArtifact(r.db.QueryRowContext(ctx, query, taskID, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return artifact, err
+}
+
+// ListJobFailures returns every failed run of a job's tasks: the runs the
+// agents reported, which are kept across retries, and tasks the backend
+// failed itself without a report, such as timed out tasks
+func (r *TaskArtifactRepository) ListJobFailures(ctx context.Context, jobExecutionID uuid.UUID) ([]models.TaskFailure, error) {
+	query := `
+		SELECT ta.agent_id, a.name, ta.error_message
+		FROM task_artifacts ta
+		LEFT JOIN agents a ON a.id = ta.agent_id
+		WHERE ta.job_execution_id = $1 AND ta.status = $2
+		UNION ALL
+		SELECT jt.agent_id, a.name, jt.error_message
+		FROM job_tasks jt
+		LEFT JOIN agents a ON a.id = jt.agent_id
+		WHERE jt.job_execution_id = $1 AND jt.status = $2
+		  AND NOT EXISTS (
+			SELECT 1 FROM task_artifacts ta
+			WHERE ta.task_id = jt.id AND ta.status = $2
+			  AND ta.created_at >= COALESCE(jt.started_at, jt.created_at)
+		  )`
+
+	rows, err := r.db.QueryContext(ctx, query, jobExecutionID, models.JobTaskStatusFailed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job failures: %w", err)
+	}
+	defer rows.Close()
+
+	failures := []models.TaskFailure{}
+	for rows.Next() {
+		var failure models.TaskFailure
+		if err := rows.Scan(&failure.AgentID, &failure.AgentName, &failure.ErrorMessage); err != nil {
+			return nil, fmt.Errorf("failed to scan job failure: %w", err)
+		}
+		failures = append(failures, failure)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job failures: %w", err)
+	}
+
+	return failures, nil
+}
+
+// DeleteOlderThanDays removes artifacts of runs that finished more than days ago
+func (r *TaskArtifactRepository) DeleteOlderThanDays(ctx context.Context, days int) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM task_artifacts WHERE created_at < NOW() - make_interval(days => $1)`, days)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old task artifacts: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// scanTaskArtifact scans a task_artifacts row selected by ListByTask or GetByID
+func scanTaskArtifact(row rowScanner) (*models.TaskArtifact, error) {
+	var artifact models.TaskArtifact
+	var finalStatus []byte
+	err := row.Scan(
+		&artifact.ID,
+		&artifact.TaskID,
+		&artifact.JobExecutionID,
+		&artifact.AgentID,
+		&artifact.Status,
+		&artifact.ExitCode,
+		&artifact.ErrorMessage,
+		&finalStatus,
+		&artifact.OutputTail,
+		&artifact.OutputBytes,
+		&artifact.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan task artifact: %w", err)
+	}
+	if finalStatus != nil {
+		artifact.FinalStatus = finalStatus
+	}
+	return &artifact, nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// TelemetryRepository aggregates the anonymized statistics an instance
+// publishes and stores the reports a collector receives
+type TelemetryRepository struct {
+	db *db.DB
+}
+
+// NewTelemetryRepository creates a new telemetry repository
+func NewTelemetryRepository(database *db.DB) *TelemetryRepository {
+	return &TelemetryRepository{db: database}
+}
+
+// GetHashTypeStats summarizes the hashlists that are not in the trash per hash type
+func (r *TelemetryRepository) GetHashTypeStats(ctx context.Context) ([]models.TelemetryHashType, error) {
+	query := `
+		SELECT hash_type_id, COUNT(*), COALESCE(SUM(total_hashes), 0), COALESCE(SUM(cracked_hashes), 0)
+		FROM hashlists
+		WHERE deleted_at IS NULL
+		GROUP BY hash_type_id
+		ORDER BY hash_type_id`
+
+	rows, err := r.db.Reader().QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash type statistics: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []models.TelemetryHashType{}
+	for rows.Next() {
+		var s models.TelemetryHashType
+		if err := rows.Scan(&s.HashTypeID, &s.Hashlists, &s.TotalHashes, &s.CrackedHashes); err != nil {
+			return nil, fmt.Errorf("failed to scan hash type statistics: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// GetAttackModeStats summarizes the jobs that finished in [start, end) per attack mode
+func (r *TelemetryRepository) GetAttackModeStats(ctx context.Context, start, end time.Time) ([]models.TelemetryAttackMode, error) {
+	query := `
+		SELECT
+			je.attack_mode,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE je.status = 'completed'),
+			COALESCE(SUM(tasks.cracks), 0),
+			COALESCE(SUM(EXTRACT(EPOCH FROM (je.completed_at - je.started_at)))::BIGINT, 0)
+		FROM job_executions je
+		LEFT JOIN LATERAL (
+			SELECT SUM(COALESCE(jt.crack_count, 0)) AS cracks
+			FROM job_tasks jt
+			WHERE jt.job_execution_id = je.id
+		) tasks ON true
+		WHERE je.completed_at >= $1 AND je.completed_at < $2
+		AND je.status IN ('completed', 'failed', 'cancelled')
+		GROUP BY je.attack_mode
+		ORDER BY je.attack_mode`
+
+	rows, err := r.db.Reader().QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attack mode statistics: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []models.TelemetryAttackMode{}
+	for rows.Next() {
+		var s models.TelemetryAttackMode
+		if err := rows.Scan(&s.AttackMode, &s.Jobs, &s.CompletedJobs, &s.Cracks, &s.RunSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan attack mode statistics: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// CountAgents returns the number of enabled agents
+func (r *TelemetryRepository) CountAgents(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.Reader().QueryRowContext(ctx, `SELECT COUNT(*) FROM agents WHERE is_enabled = true`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count agents: %w", err)
+	}
+	return count, nil
+}
+
+// StoreReport stores a report received from another instance
+func (r *TelemetryRepository) StoreReport(ctx context.Context, report *models.TelemetryReport) (int64, error) {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode telemetry report: %w", err)
+	}
+
+	query := `
+		INSERT INTO telemetry_reports (instance_id, instance_label, schema_version, period_start, period_end, report)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6)
+		RETURNING id`
+
+	var id int64
+	err = r.db.QueryRowContext(ctx, query,
+		report.InstanceID, report.InstanceLabel, report.SchemaVersion, report.PeriodStart, report.PeriodEnd, body,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store telemetry report: %w", err)
+	}
+	return id, nil
+}
+
+// ListLatestReports returns the most recent report of every instance
+func (r *TelemetryRepository) ListLatestReports(ctx context.Context) ([]models.TelemetryInstanceReport, error) {
+	query := `
+		SELECT DISTINCT ON (instance_id) id, received_at, report
+		FROM telemetry_reports
+		ORDER BY instance_id, received_at DESC, id DESC`
+
+	return r.queryReports(ctx, query)
+}
+
+// ListInstanceReports returns the reports of one instance, newest first
+func (r *TelemetryRepository) ListInstanceReports(ctx context.Context, instanceID uuid.UUID, limit int) ([]models.TelemetryInstanceReport, error) {
+	query := `
+		SELECT id, received_at, report
+		FROM telemetry_reports
+		WHERE instance_id = $1
+		ORDER BY received_at DESC, id DESC
+		LIMIT $2`
+
+	return r.queryReports(ctx, query, instanceID, limit)
+}
+
+func (r *TelemetryRepository) queryReports(ctx context.Context, query string, args ...interface{}) ([]models.TelemetryInstanceReport, error) {
+	rows, err := r.db.Reader().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list telemetry reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.TelemetryInstanceReport{}
+	for rows.Next() {
+		var report models.TelemetryInstanceReport
+		var body []byte
+		if err := rows.Scan(&report.ID, &report.ReceivedAt, &body); err != nil {
+			return nil, fmt.Errorf("failed to scan telemetry report: %w", err)
+		}
+		if err := json.Unmarshal(body, &report.Report); err != nil {
+			return nil, fmt.Errorf("failed to decode telemetry report %d: %w", report.ID, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// timelineActivityLookahead is how long after a timeline ends a sync or
+// benchmark that started within it is looked for
+const timelineActivityLookahead = 24 * time.Hour
+
+// timelineActivityKinds maps the system events agent timelines are built from
+// to the activity they end
+var timelineActivityKinds = map[string]string{
+	models.SystemEventSyncCompleted:      models.TimelineSegmentSync,
+	models.SystemEventSyncFailed:         models.TimelineSegmentSync,
+	models.SystemEventBenchmarkCompleted: models.TimelineSegmentBenchmark,
+	models.SystemEventBenchmarkFailed:    models.TimelineSegmentBenchmark,
+	models.SystemEventBenchmarkTimedOut:  models.TimelineSegmentBenchmark,
+}
+
+// TimelineRepository reads the task history and system events agent
+// timelines are built from
+type TimelineRepository struct {
+	db *db.DB
+}
+
+// NewTimelineRepository creates a new timeline repository
+func NewTimelineRepository(database *db.DB) *TimelineRepository {
+	return &TimelineRepository{db: database}
+}
+
+// GetJobPeriod returns when a job started and when it finished, nil while it
+// has not. ErrNotFound is returned for an unknown job.
+func (r *TimelineRepository) GetJobPeriod(ctx context.Context, jobID uuid.UUID) (time.Time, *time.Time, error) {
+	var start time.Time
+	var end sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(started_at, created_at), completed_at
+		FROM job_executions
+		WHERE id = $1`, jobID).Scan(&start, &end)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil, ErrNotFound
+	}
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("failed to get job period: %w", err)
+	}
+	if end.Valid {
+		return start, &end.Time, nil
+	}
+	return start, nil, nil
+}
+
+// ListAgents returns the agents a timeline shows: the given agent, the agents
+// that ran tasks of the given job, or every agent
+func (r *TimelineRepository) ListAgents(ctx context.Context, agentID *int, jobID *uuid.UUID) ([]models.TimelineAgent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, created_at
+		FROM agents
+		WHERE ($1::int IS NULL OR id = $1)
+			AND ($2::uuid IS NULL OR id IN (
+				SELECT agent_id FROM job_tasks WHERE job_execution_id = $2 AND agent_id IS NOT NULL))
+		ORDER BY id`, agentID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timeline agents: %w", err)
+	}
+	defer rows.Close()
+
+	agents := []models.TimelineAgent{}
+	for rows.Next() {
+		var agent models.TimelineAgent
+		if err := rows.Scan(&agent.ID, &agent.Name, &agent.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline agent: %w", err)
+		}
+		agents = append(agents, agent)
+	}
+	return agents, rows.Err()
+}
+
+// ListTaskRuns returns the task runs of the agents overlapping from..to. A
+// task that was retried shows only its last run, on the agent it ran on last.
+func (r *TimelineRepository) ListTaskRuns(ctx context.Context, agentIDs []int, from, to time.Time) ([]models.TimelineTaskRun, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.agent_id, t.id, t.job_execution_id, COALESCE(je.name, ''), t.status, t.start_at, t.end_at
+		FROM (
+			SELECT agent_id, id, job_execution_id, status,
+				COALESCE(started_at, assigned_at) AS start_at,
+				CASE WHEN status IN ('assigned', 'running') THEN NULL
+					ELSE COALESCE(completed_at, updated_at) END AS end_at
+			FROM job_tasks
+			WHERE agent_id = ANY($1) AND status <> 'pending'
+		) t
+		JOIN job_executions je ON je.id = t.job_execution_id
+		WHERE t.start_at < $3 AND (t.end_at IS NULL OR t.end_at > $2)
+		ORDER BY t.agent_id, t.start_at`,
+		pq.Array(agentIDs), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timeline tasks: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []models.TimelineTaskRun{}
+	for rows.Next() {
+		var run models.TimelineTaskRun
+		var end sql.NullTime
+		if err := rows.Scan(&run.AgentID, &run.TaskID, &run.JobID, &run.JobName, &run.Status, &run.Start, &end); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline task: %w", err)
+		}
+		if end.Valid {
+			run.End = &end.Time
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// ListActivities returns the periods the agents were offline, syncing files
+// or benchmarking around from..to. Offline periods come from the connection
+// events, finished syncs and benchmarks from the events recording their
+// start, and those still running from the agents themselves.
+func (r *TimelineRepository) ListActivities(ctx context.Context, agentIDs []int, from, to time.Time) ([]models.TimelineActivity, error) {
+	entityIDs := make([]string, len(agentIDs))
+	for i, agentID := range agentIDs {
+		entityIDs[i] = strconv.Itoa(agentID)
+	}
+	eventTypes := []string{models.SystemEventAgentConnected, models.SystemEventAgentDisconnected}
+	for eventType := range timelineActivityKinds {
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	// The last connection event before the window tells whether an agent
+	// starts it offline
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT entity_id, event_type, occurred_at, COALESCE(details->>'started_at', details->>'requested_at', '')
+		FROM system_events
+		WHERE entity_type = 'agent' AND entity_id = ANY($1) AND event_type = ANY($2)
+			AND occurred_at >= $3 AND occurred_at < $4
+		UNION ALL
+		SELECT * FROM (
+			SELECT DISTINCT ON (entity_id) entity_id, event_type, occurred_at, ''
+			FROM system_events
+			WHERE entity_type = 'agent' AND entity_id = ANY($1) AND event_type IN ($5, $6)
+				AND occurred_at < $3
+			ORDER BY entity_id, occurred_at DESC
+		) last_connection
+		ORDER BY 3`,
+		pq.Array(entityIDs), pq.Array(eventTypes), from, to.Add(timelineActivityLookahead),
+		models.SystemEventAgentConnected, models.SystemEventAgentDisconnected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timeline events: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	activities := []models.TimelineActivity{}
+	offlineSince := make(map[int]time.Time)
+	for rows.Next() {
+		var entityID, eventType, startedAt string
+		var occurredAt time.Time
+		if err := rows.Scan(&entityID, &eventType, &occurredAt, &startedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline event: %w", err)
+		}
+		agentID, err := strconv.Atoi(entityID)
+		if err != nil {
+			continue
+		}
+
+		switch eventType {
+		case models.SystemEventAgentDisconnected:
+			if _, offline := offlineSince[agentID]; !offline {
+				offlineSince[agentID] = occurredAt
+			}
+		case models.SystemEventAgentConnected:
+			if since, offline := offlineSince[agentID]; offline {
+				activities = append(activities, models.TimelineActivity{AgentID: agentID, Kind: models.TimelineSegmentOffline, Start: since, End: occurredAt})
+				delete(offlineSince, agentID)
+			}
+		default:
+			// Events without their start only mark a point in time and are left out
+			start, err := time.Parse(time.RFC3339, startedAt)
+			if err != nil || !start.Before(occurredAt) {
+				continue
+			}
+			activities = append(activities, models.TimelineActivity{AgentID: agentID, Kind: timelineActivityKinds[eventType], Start: start, End: occurredAt})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list timeline events: %w", err)
+	}
+	for agentID, since := range offlineSince {
+		activities = append(activities, models.TimelineActivity{AgentID: agentID, Kind: models.TimelineSegmentOffline, Start: since, End: now})
+	}
+
+	// Syncs and forced benchmarks still running
+	agentRows, err := r.db.QueryContext(ctx, `
+		SELECT id,
+			CASE WHEN sync_status = 'in_progress' THEN sync_started_at END,
+			COALESCE(metadata->>'benchmark_requested_at', '')
+		FROM agents
+		WHERE id = ANY($1)`, pq.Array(agentIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running agent activities: %w", err)
+	}
+	defer agentRows.Close()
+
+	for agentRows.Next() {
+		var agentID int
+		var syncStartedAt sql.NullTime
+		var benchmarkRequestedAt string
+		if err := agentRows.Scan(&agentID, &syncStartedAt, &benchmarkRequestedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan running agent activities: %w", err)
+		}
+		if syncStartedAt.Valid {
+			activities = append(activities, models.TimelineActivity{AgentID: agentID, Kind: models.TimelineSegmentSync, Start: syncStartedAt.Time, End: now})
+		}
+		if start, err := time.Parse(time.RFC3339, benchmarkRequestedAt); err == nil {
+			activities = append(activities, models.TimelineActivity{AgentID: agentID, Kind: models.TimelineSegmentBenchmark, Start: start, End: now})
+		}
+	}
+	return activities, agentRows.Err()
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// ErrTrashParentDeleted is returned when restoring an item whose parent is still in the trash
+var ErrTrashParentDeleted = errors.New("parent record is in the trash")
+
+// trashItemsQuery unions the soft-deleted rows of every trashable table
+const trashItemsQuery = `
+	SELECT t.type, t.id, t.name, t.deleted_at, t.deleted_by, u.username
+	FROM (
+		SELECT 'hashlist' AS type, h.id::text AS id, h.name AS name, h.deleted_at, h.deleted_by
+		FROM hashlists h
+		WHERE h.deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'job', je.id::text, COALESCE(NULLIF(je.name, ''), pj.name, je.id::text), je.deleted_at, je.deleted_by
+		FROM job_executions je
+		LEFT JOIN preset_jobs pj ON je.preset_job_id = pj.id
+		WHERE je.deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'client', c.id::text, c.name, c.deleted_at, c.deleted_by
+		FROM clients c
+		WHERE c.deleted_at IS NOT NULL
+	) t
+	LEFT JOIN users u ON t.deleted_by = u.id`
+
+// TrashRepository lists soft-deleted hashlists, jobs and clients
+type TrashRepository struct {
+	db *db.DB
+}
+
+// NewTrashRepository creates a new trash repository
+func NewTrashRepository(database *db.DB) *TrashRepository {
+	return &TrashRepository{db: database}
+}
+
+// List returns a page of trashed items, most recently deleted first. An empty
+// itemType lists every type.
+func (r *TrashRepository) List(ctx context.Context, itemType models.TrashItemType, limit, offset int) ([]models.TrashItem, int, error) {
+	where := ""
+	args := []interface{}{}
+	if itemType != "" {
+		where = " WHERE t.type = $1"
+		args = append(args, string(itemType))
+	}
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM (" + trashItemsQuery + where + ") c"
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count trash items: %w", err)
+	}
+
+	query := trashItemsQuery + where + fmt.Sprintf(" ORDER BY t.deleted_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	items, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// GetExpired returns every trashed item deleted before the cutoff
+func (r *TrashRepository) GetExpired(ctx context.Context, cutoff time.Time) ([]models.TrashItem, error) {
+	return r.query(ctx, trashItemsQuery+" WHERE t.deleted_at < $1 ORDER BY t.deleted_at ASC", cutoff)
+}
+
+func (r *TrashRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.TrashItem, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trash items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.TrashItem{}
+	for rows.Next() {
+		var item models.TrashItem
+		if err := rows.Scan(&item.Type, &item.ID, &item.Name, &item.DeletedAt, &item.DeletedBy, &item.DeletedByUsername); err != nil {
+			return nil, fmt.Errorf("failed to scan trash item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trash items: %w", err)
+	}
+	return items, nil
+}
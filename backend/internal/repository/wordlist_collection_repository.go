@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+const wordlistCollectionColumns = `id, name, description, wordlist_ids, created_by, created_at, updated_at`
+
+// WordlistCollectionRepository stores named sets of wordlists that preset
+// jobs reference instead of a single wordlist
+type WordlistCollectionRepository struct {
+	db *db.DB
+}
+
+// NewWordlistCollectionRepository creates a new wordlist collection repository
+func NewWordlistCollectionRepository(database *db.DB) *WordlistCollectionRepository {
+	return &WordlistCollectionRepository{db: database}
+}
+
+// List returns all wordlist collections ordered by name
+func (r *WordlistCollectionRepository) List(ctx context.Context) ([]models.WordlistCollection, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+wordlistCollectionColumns+` FROM wordlist_collections ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wordlist collections: %w", err)
+	}
+	defer rows.Close()
+
+	collections := []models.WordlistCollection{}
+	for rows.Next() {
+		collection, err := scanWordlistCollection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan wordlist collection: %w", err)
+		}
+		collections = append(collections, *collection)
+	}
+	return collections, rows.Err()
+}
+
+// GetByID returns a wordlist collection, or ErrNotFound
+func (r *WordlistCollectionRepository) GetByID(ctx context.Context, id int) (*models.WordlistCollection, error) {
+	collection, err := scanWordlistCollection(r.db.QueryRowContext(ctx,
+		`SELECT `+wordlistCollectionColumns+` FROM wordlist_collections WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wordlist collection: %w", err)
+	}
+	return collection, nil
+}
+
+// ListWordlists returns the wordlists of a collection that still exist, in
+// collection order
+func (r *WordlistCollectionRepository) ListWordlists(ctx context.Context, collection *models.WordlistCollection) ([]models.WordlistBasic, error) {
+	query := `
+		SELECT id, name
+		FROM wordlists
+		WHERE id = ANY($1)
+		ORDER BY array_position($1, id::BIGINT)`
+
+	rows, err := r.db.QueryContext(ctx, query, collection.WordlistIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wordlists of collection: %w", err)
+	}
+	defer rows.Close()
+
+	wordlists := []models.WordlistBasic{}
+	for rows.Next() {
+		var wordlist models.WordlistBasic
+		if err := rows.Scan(&wordlist.ID, &wordlist.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan wordlist of collection: %w", err)
+		}
+		wordlists = append(wordlists, wordlist)
+	}
+	return wordlists, rows.Err()
+}
+
+// Create adds a wordlist collection
+func (r *WordlistCollectionRepository) Create(ctx context.Context, collection *models.WordlistCollection) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO wordlist_collections (name, description, wordlist_ids, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		collection.Name, collection.Description, collection.WordlistIDs, collection.CreatedBy,
+	).Scan(&collection.ID, &collection.CreatedAt, &collection.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return fmt.Errorf("wordlist collection '%s' already exists: %w", collection.Name, ErrDuplicateRecord)
+		}
+		return fmt.Errorf("failed to create wordlist collection: %w", err)
+	}
+	return nil
+}
+
+// Update changes the name, description and wordlists of a collection
+func (r *WordlistCollectionRepository) Update(ctx context.Context, collection *models.WordlistCollection) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE wordlist_collections
+		SET name = $2, description = $3, wordlist_ids = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING created_by, created_at, updated_at`,
+		collection.ID, collection.Name, collection.Description, collection.WordlistIDs,
+	).Scan(&collection.CreatedBy, &collection.CreatedAt, &collection.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("wordlist collection '%s' already exists: %w", collection.Name, ErrDuplicateRecord)
+		}
+		return fmt.Errorf("failed to update wordlist collection: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a wordlist collection. Collections still referenced by a
+// preset job cannot be deleted.
+func (r *WordlistCollectionRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM wordlist_collections WHERE id = $1`, id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" { // foreign_key_violation
+			return fmt.Errorf("wordlist collection %d: %w", id, models.ErrResourceReferenced)
+		}
+		return fmt.Errorf("failed to delete wordlist collection: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CountExistingWordlists returns how many of the given wordlist IDs exist
+func (r *WordlistCollectionRepository) CountExistingWordlists(ctx context.Context, wordlistIDs []int64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM wordlists WHERE id = ANY($1)`, pq.Int64Array(wordlistIDs)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count wordlists: %w", err)
+	}
+	return count, nil
+}
+
+func scanWordlistCollection(row interface{ Scan(...interface{}) error }) (*models.WordlistCollection, error) {
+	var collection models.WordlistCollection
+	err := row.Scan(&collection.ID, &collection.Name, &collection.Description, &collection.WordlistIDs,
+		&collection.CreatedBy, &collection.CreatedAt, &collection.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
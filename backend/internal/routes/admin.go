@@ -7,12 +7,24 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/email"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/auth"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/binaryrollouts"
+	admincharsets "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/charsets"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/hashcatexecs"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/jobarchive"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/jobparams"
 	adminsettings "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/settings"
+	adminsupport "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/support"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/systemevents"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/timeline"
+	admintrash "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/trash"
 	adminuser "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/user"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/wordlistcollections"
 	binaryhandler "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/binary"
 	emailhandler "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/email"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/middleware"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/settingspresets"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/gorilla/mux"
 )
@@ -61,6 +73,10 @@ func SetupAdminRoutes(router *mux.Router, database *db.DB, emailService *email.S
 	// Data Retention settings routes (New)
 	adminRouter.HandleFunc("/settings/retention", retentionSettingsHandler.GetDefaultRetention).Methods(http.MethodGet, http.MethodOptions)
 	adminRouter.HandleFunc("/settings/retention", retentionSettingsHandler.UpdateDefaultRetention).Methods(http.MethodPut, http.MethodOptions)
+	adminRouter.HandleFunc("/settings/trash", retentionSettingsHandler.GetTrashRetention).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/settings/trash", retentionSettingsHandler.UpdateTrashRetention).Methods(http.MethodPut, http.MethodOptions)
+	adminRouter.HandleFunc("/settings/hashlist-staging", retentionSettingsHandler.GetHashlistStagingExpiry).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/settings/hashlist-staging", retentionSettingsHandler.UpdateHashlistStagingExpiry).Methods(http.MethodPut, http.MethodOptions)
 
 	// System settings routes (New)
 	adminRouter.HandleFunc("/settings/max-priority", systemSettingsHandler.GetMaxPriority).Methods(http.MethodGet, http.MethodOptions)
@@ -79,6 +95,12 @@ func SetupAdminRoutes(router *mux.Router, database *db.DB, emailService *email.S
 	adminRouter.HandleFunc("/settings/agent-download", agentSettingsHandler.GetAgentDownloadSettings).Methods(http.MethodGet, http.MethodOptions)
 	adminRouter.HandleFunc("/settings/agent-download", agentSettingsHandler.UpdateAgentDownloadSettings).Methods(http.MethodPut, http.MethodOptions)
 
+	// Tuning presets for deployment sizes - Must be before generic {key} route
+	presetHandler := adminsettings.NewSettingsPresetHandler(settingspresets.NewSettingsPresetService(systemSettingsRepo))
+	adminRouter.HandleFunc("/settings/presets", presetHandler.ListPresets).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/settings/presets/{id}/preview", presetHandler.PreviewPreset).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/settings/presets/{id}/apply", presetHandler.ApplyPreset).Methods(http.MethodPost, http.MethodOptions)
+
 	// General system settings routes for listing and updating individual settings - Must be after specific routes
 	adminRouter.HandleFunc("/settings", systemSettingsHandler.ListSettings).Methods(http.MethodGet, http.MethodOptions)
 	adminRouter.HandleFunc("/settings/{key}", systemSettingsHandler.GetSetting).Methods(http.MethodGet, http.MethodOptions)
@@ -127,10 +149,71 @@ func SetupAdminRoutes(router *mux.Router, database *db.DB, emailService *email.S
 		adminRouter.HandleFunc("/binary/{id}/verify", binaryHandler.HandleVerifyVersion).Methods(http.MethodPost, http.MethodOptions)
 		adminRouter.HandleFunc("/binary/{id}/set-default", binaryHandler.HandleSetDefaultVersion).Methods(http.MethodPut, http.MethodOptions)
 		debug.Info("Configured admin binary management routes: /admin/binary/*")
+
+		// Staged rollouts of new binary versions to canary agents
+		rolloutHandler := binaryrollouts.NewHandler(services.NewBinaryRolloutService(repository.NewBinaryRolloutRepository(database), binaryManager, systemSettingsRepo))
+		adminRouter.HandleFunc("/binary-rollouts", rolloutHandler.ListRollouts).Methods(http.MethodGet, http.MethodOptions)
+		adminRouter.HandleFunc("/binary-rollouts", rolloutHandler.StartRollout).Methods(http.MethodPost, http.MethodOptions)
+		adminRouter.HandleFunc("/binary-rollouts/{id:[0-9]+}", rolloutHandler.GetRollout).Methods(http.MethodGet, http.MethodOptions)
+		adminRouter.HandleFunc("/binary-rollouts/{id:[0-9]+}/promote", rolloutHandler.PromoteRollout).Methods(http.MethodPost, http.MethodOptions)
+		adminRouter.HandleFunc("/binary-rollouts/{id:[0-9]+}/rollback", rolloutHandler.RollBackRollout).Methods(http.MethodPost, http.MethodOptions)
 	} else {
 		debug.Error("Binary manager not provided to SetupAdminRoutes")
 	}
 
+	// Job execution archive routes - stats must be registered before {id}
+	jobArchiveService := services.NewJobArchiveService(repository.NewJobArchiveRepository(database), systemSettingsRepo)
+	jobArchiveHandler := jobarchive.NewHandler(jobArchiveService)
+	adminRouter.HandleFunc("/job-archives", jobArchiveHandler.ListArchives).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/job-archives/stats", jobArchiveHandler.GetStats).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/job-archives/run", jobArchiveHandler.RunArchive).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/job-archives/{id:[0-9a-fA-F-]+}", jobArchiveHandler.GetArchive).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/job-archives/{id:[0-9a-fA-F-]+}/restore", jobArchiveHandler.RestoreJob).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/jobs/{id:[0-9a-fA-F-]+}/archive", jobArchiveHandler.ArchiveJob).Methods(http.MethodPost, http.MethodOptions)
+
+	// Operational event log, written by the agent connection handler and the scheduler
+	systemEventHandler := systemevents.NewHandler(services.NewSystemEventService(repository.NewSystemEventRepository(database), systemSettingsRepo))
+	adminRouter.HandleFunc("/system-events", systemEventHandler.ListEvents).Methods(http.MethodGet, http.MethodOptions)
+
+	// Gantt chart of the tasks agents ran and what they did in between, from the task history and the event log
+	timelineHandler := timeline.NewHandler(repository.NewTimelineRepository(database))
+	adminRouter.HandleFunc("/timeline", timelineHandler.GetTimeline).Methods(http.MethodGet, http.MethodOptions)
+
+	// Keyspace calculations and other hashcat runs of the backend, running and queued
+	hashcatExecHandler := hashcatexecs.NewHandler(services.HashcatExecs())
+	adminRouter.HandleFunc("/hashcat-execs", hashcatExecHandler.GetStatus).Methods(http.MethodGet, http.MethodOptions)
+
+	// Per-job extra hashcat parameters, merged over each agent's own parameters
+	jobParamsHandler := jobparams.NewHandler(repository.NewJobExecutionRepository(database))
+	adminRouter.HandleFunc("/jobs/{id:[0-9a-fA-F-]+}/extra-parameters", jobParamsHandler.UpdateExtraParameters).Methods(http.MethodPut, http.MethodOptions)
+
+	// Custom charset library used by the mask builder, listed for all users under /charsets
+	charsetHandler := admincharsets.NewHandler(services.NewMaskService(repository.NewCustomCharsetRepository(database)))
+	adminRouter.HandleFunc("/charsets", charsetHandler.CreateCharset).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/charsets/{id:[0-9]+}", charsetHandler.UpdateCharset).Methods(http.MethodPut, http.MethodOptions)
+	adminRouter.HandleFunc("/charsets/{id:[0-9]+}", charsetHandler.DeleteCharset).Methods(http.MethodDelete, http.MethodOptions)
+
+	// Wordlist collections, expanded into one job per wordlist by preset jobs referencing them
+	collectionHandler := wordlistcollections.NewHandler(services.NewWordlistCollectionService(repository.NewWordlistCollectionRepository(database)))
+	adminRouter.HandleFunc("/wordlist-collections", collectionHandler.ListCollections).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/wordlist-collections", collectionHandler.CreateCollection).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/wordlist-collections/{id:[0-9]+}", collectionHandler.GetCollection).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/wordlist-collections/{id:[0-9]+}", collectionHandler.UpdateCollection).Methods(http.MethodPut, http.MethodOptions)
+	adminRouter.HandleFunc("/wordlist-collections/{id:[0-9]+}", collectionHandler.DeleteCollection).Methods(http.MethodDelete, http.MethodOptions)
+
+	// Trash routes for restoring soft-deleted hashlists, jobs and clients
+	trashHandler := admintrash.NewHandler(newTrashService(database))
+	adminRouter.HandleFunc("/trash", trashHandler.ListTrash).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/trash/purge", trashHandler.PurgeExpired).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/trash/{type:hashlist|job|client}/{id}/restore", trashHandler.RestoreItem).Methods(http.MethodPost, http.MethodOptions)
+
+	// Support session routes for viewing the system as another user
+	supportHandler := adminsupport.NewHandler(repository.NewSupportSessionRepository(database), userRepo, systemSettingsRepo)
+	adminRouter.HandleFunc("/support-sessions", supportHandler.ListSessions).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/support-sessions", supportHandler.StartSession).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/support-sessions/{id:[0-9a-fA-F-]+}", supportHandler.GetSession).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/support-sessions/{id:[0-9a-fA-F-]+}/end", supportHandler.EndSession).Methods(http.MethodPost, http.MethodOptions)
+
 	// Setup Preset Job and Job Workflow routes using the passed handler
 	SetupAdminJobRoutes(adminRouter, jobHandler)
 	debug.Info("Configured admin preset job and workflow routes: /admin/preset-jobs/*, /admin/job-workflows/*")
@@ -189,6 +189,14 @@ func (h *AdminJobsHandler) RecalculatePresetJobKeyspace(w http.ResponseWriter, r
 
 	// Calculate keyspace
 	keyspace, err := h.presetJobService.CalculateKeyspaceForPresetJob(r.Context(), job)
+	if errors.Is(err, services.ErrCollectionKeyspace) {
+		httputil.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrHashcatExecQueueFull) {
+		httputil.RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
 	if err != nil {
 		debug.Error("Error calculating keyspace for preset job %s: %v", id, err)
 		httputil.RespondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to calculate keyspace: %v", err))
@@ -233,7 +241,13 @@ func (h *AdminJobsHandler) RecalculateAllMissingKeyspaces(w http.ResponseWriter,
 	debug.Info("Starting batch keyspace recalculation for %d preset jobs", len(jobs))
 
 	for _, job := range jobs {
-		// Skip if keyspace is already calculated
+		// Skip if keyspace is calculated for each job of a wordlist collection,
+		// or already calculated
+		if job.WordlistCollectionID != nil {
+			skipped++
+			debug.Info("Skipping preset job %s (%s) - runs a wordlist collection", job.ID, job.Name)
+			continue
+		}
 		if job.Keyspace != nil && *job.Keyspace > 0 {
 			skipped++
 			debug.Info("Skipping preset job %s (%s) - already has keyspace: %d", job.ID, job.Name, *job.Keyspace)
@@ -285,13 +299,26 @@ func (h *AdminJobsHandler) RecalculateAllMissingKeyspaces(w http.ResponseWriter,
 
 // Define request/response structs specific to handlers if needed
 type CreateWorkflowRequest struct {
-	Name         string      `json:"name"`
-	PresetJobIDs []uuid.UUID `json:"preset_job_ids"`
+	Name            string      `json:"name"`
+	PresetJobIDs    []uuid.UUID `json:"preset_job_ids"`
+	InheritPriority *bool       `json:"inherit_priority"` // Defaults to true
+	PriorityBoost   int         `json:"priority_boost"`
 }
 
 type UpdateWorkflowRequest struct {
-	Name         string      `json:"name"`
-	PresetJobIDs []uuid.UUID `json:"preset_job_ids"`
+	Name            string      `json:"name"`
+	PresetJobIDs    []uuid.UUID `json:"preset_job_ids"`
+	InheritPriority *bool       `json:"inherit_priority"` // Defaults to true
+	PriorityBoost   int         `json:"priority_boost"`
+}
+
+// workflowInheritance returns the priority inheritance of a workflow request
+func workflowInheritance(inheritPriority *bool, priorityBoost int) models.WorkflowPriorityInheritance {
+	inheritance := models.WorkflowPriorityInheritance{InheritPriority: true, PriorityBoost: priorityBoost}
+	if inheritPriority != nil {
+		inheritance.InheritPriority = *inheritPriority
+	}
+	return inheritance
 }
 
 func (h *AdminJobsHandler) CreateJobWorkflow(w http.ResponseWriter, r *http.Request) {
@@ -301,7 +328,7 @@ func (h *AdminJobsHandler) CreateJobWorkflow(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	createdWorkflow, err := h.workflowService.CreateJobWorkflow(r.Context(), req.Name, req.PresetJobIDs)
+	createdWorkflow, err := h.workflowService.CreateJobWorkflow(r.Context(), req.Name, req.PresetJobIDs, workflowInheritance(req.InheritPriority, req.PriorityBoost))
 	if err != nil {
 		debug.Error("Error creating job workflow: %v", err)
 		httputil.RespondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create job workflow: %v", err))
@@ -347,6 +374,8 @@ func (h *AdminJobsHandler) ListJobWorkflows(w http.ResponseWriter, r *http.Reque
 			"name":                      workflow.Name,
 			"created_at":                workflow.CreatedAt,
 			"updated_at":                workflow.UpdatedAt,
+			"inherit_priority":          workflow.InheritPriority,
+			"priority_boost":            workflow.PriorityBoost,
 			"has_high_priority_override": hasHighPriorityOverride,
 		}
 		
@@ -407,7 +436,7 @@ func (h *AdminJobsHandler) UpdateJobWorkflow(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	updatedWorkflow, err := h.workflowService.UpdateJobWorkflow(r.Context(), id, req.Name, req.PresetJobIDs)
+	updatedWorkflow, err := h.workflowService.UpdateJobWorkflow(r.Context(), id, req.Name, req.PresetJobIDs, workflowInheritance(req.InheritPriority, req.PriorityBoost))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			httputil.RespondWithError(w, http.StatusNotFound, "Job workflow not found")
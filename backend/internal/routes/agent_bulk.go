@@ -0,0 +1,79 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/agentbulk"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// agentCommander sends bulk commands through the WebSocket handler and job
+// integration, which are only set up after the routes
+type agentCommander struct{}
+
+// TriggerFileSync implements services.AgentCommander
+func (agentCommander) TriggerFileSync(agentID int) error {
+	return WSHandler.TriggerFileSync(agentID)
+}
+
+// SendForceCleanup implements services.AgentCommander
+func (agentCommander) SendForceCleanup(ctx context.Context, agentID int) error {
+	return JobIntegrationManager.GetWebSocketIntegration().SendForceCleanup(ctx, agentID)
+}
+
+// PushAgentConfig implements services.AgentCommander
+func (agentCommander) PushAgentConfig(agentID int) error {
+	return WSHandler.PushAgentConfig(agentID)
+}
+
+// AuditAgentFiles implements services.AgentCommander
+func (agentCommander) AuditAgentFiles(agentID int) (*models.AgentFileAudit, error) {
+	return WSHandler.AuditAgentFiles(agentID)
+}
+
+// FileAudit implements services.AgentCommander
+func (agentCommander) FileAudit(agentID int) *models.AgentFileAudit {
+	return WSHandler.FileAudit(agentID)
+}
+
+// RotateAgentCredentials implements services.AgentCommander
+func (agentCommander) RotateAgentCredentials(agentID int) (*models.AgentCredentialRotation, error) {
+	return WSHandler.RotateAgentCredentials(agentID)
+}
+
+// CredentialRotation implements services.AgentCommander
+func (agentCommander) CredentialRotation(agentID int) *models.AgentCredentialRotation {
+	return WSHandler.CredentialRotation(agentID)
+}
+
+// SetupAgentBulkRoutes configures the bulk agent operation routes on the admin router
+func SetupAgentBulkRoutes(adminRouter *mux.Router, database *db.DB) {
+	service := services.NewAgentBulkService(
+		repository.NewAgentRepository(database),
+		repository.NewAgentScheduleRepository(database),
+		repository.NewJobTaskRepository(database),
+		func() services.AgentCommander {
+			if WSHandler == nil || JobIntegrationManager == nil || JobIntegrationManager.GetWebSocketIntegration() == nil {
+				return nil
+			}
+			return agentCommander{}
+		},
+	)
+	handler := agentbulk.NewHandler(service)
+
+	adminRouter.HandleFunc("/agents/bulk", handler.Apply).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/agents/{id:[0-9]+}/labels", handler.UpdateLabels).Methods(http.MethodPut, http.MethodOptions)
+	adminRouter.HandleFunc("/agents/{id:[0-9]+}/workload", handler.UpdateWorkload).Methods(http.MethodPut, http.MethodOptions)
+	adminRouter.HandleFunc("/agents/{id:[0-9]+}/config", handler.UpdateConfig).Methods(http.MethodPut, http.MethodOptions)
+	adminRouter.HandleFunc("/agents/{id:[0-9]+}/file-audit", handler.StartFileAudit).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/agents/{id:[0-9]+}/file-audit", handler.GetFileAudit).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/agents/{id:[0-9]+}/credential-rotation", handler.RotateCredentials).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/agents/{id:[0-9]+}/credential-rotation", handler.GetCredentialRotation).Methods(http.MethodGet, http.MethodOptions)
+	debug.Info("Configured admin bulk agent routes: /admin/agents/bulk")
+}
@@ -20,7 +20,8 @@ func SetupBinaryRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, agentSe
 	// Initialize binary store and manager
 	store := binary.NewStore(sqlDB)
 	manager, err := binary.NewManager(store, binary.Config{
-		DataDir: cfg.DataDir,
+		DataDir:   cfg.DataDir,
+		Airgapped: cfg.Airgapped,
 	})
 	if err != nil {
 		debug.Error("Failed to initialize binary manager: %v", err)
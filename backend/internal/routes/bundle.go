@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"net/http"
+	"path/filepath"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/binary"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	adminbundle "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/bundle"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/rule"
+	bundlesvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/bundle"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/wordlist"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// SetupBundleRoutes configures the deployment bundle export and import routes
+// on the admin router
+func SetupBundleRoutes(adminRouter *mux.Router, database *db.DB, cfg *config.Config, wordlistManager wordlist.Manager, ruleManager rule.Manager, binaryManager binary.Manager) {
+	service := bundlesvc.NewBundleService(
+		wordlistManager,
+		ruleManager,
+		binaryManager,
+		repository.NewPresetJobRepository(database.DB),
+		repository.NewSystemSettingsRepository(database),
+		filepath.Join(cfg.ConfigDir, "bundle_signing.key"),
+		filepath.Join(cfg.DataDir, "bundle_imports"),
+	)
+	handler := adminbundle.NewHandler(service)
+
+	adminRouter.HandleFunc("/bundle/public-key", handler.GetPublicKey).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/bundle/export", handler.Export).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/bundle/import", handler.Import).Methods(http.MethodPost, http.MethodOptions)
+	debug.Info("Configured admin deployment bundle routes: /admin/bundle/*")
+}
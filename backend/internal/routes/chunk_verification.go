@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/chunkverification"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// SetupChunkVerificationRoutes configures the admin route listing the results
+// of re-running sampled chunks on other agents
+func SetupChunkVerificationRoutes(adminRouter *mux.Router, database *db.DB) {
+	handler := chunkverification.NewHandler(repository.NewChunkVerificationRepository(database))
+
+	adminRouter.HandleFunc("/chunk-verifications", handler.List).Methods(http.MethodGet, http.MethodOptions)
+	debug.Info("Configured chunk verification routes: /admin/chunk-verifications")
+}
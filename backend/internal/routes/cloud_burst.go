@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	admincloudburst "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/cloudburst"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	cloudburstsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/cloudburst"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// SetupCloudBurstRoutes configures the admin routes for watching and
+// terminating the temporary cloud agents launched for opted-in jobs
+func SetupCloudBurstRoutes(adminRouter *mux.Router, database *db.DB, cfg *config.Config) {
+	service := cloudburstsvc.NewService(
+		repository.NewCloudBurstRepository(database),
+		repository.NewSystemSettingsRepository(database),
+		services.NewClaimVoucherService(repository.NewClaimVoucherRepository(database)),
+		cloudburstsvc.NewCommandProvider(cfg.CloudBurstLaunchCommand, cfg.CloudBurstTerminateCommand),
+	)
+	handler := admincloudburst.NewHandler(service)
+
+	adminRouter.HandleFunc("/cloud-burst/instances", handler.ListInstances).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/cloud-burst/instances/{id:[0-9]+}/terminate", handler.Terminate).Methods(http.MethodPost, http.MethodOptions)
+	debug.Info("Configured cloud burst routes: /admin/cloud-burst/*")
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	adminclusterhealth "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/clusterhealth"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	clusterhealthsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/clusterhealth"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// SetupClusterHealthRoutes configures the cluster health summary: the scrape
+// endpoint for monitoring systems, which authenticates with its own token, and
+// the admin route
+func SetupClusterHealthRoutes(apiRouter *mux.Router, adminRouter *mux.Router, database *db.DB, cfg *config.Config) {
+	service := clusterhealthsvc.NewClusterHealthService(
+		repository.NewClusterHealthRepository(database),
+		repository.NewSystemSettingsRepository(database),
+		cfg.DataDir,
+	)
+	// The WebSocket handler is created later, it is looked up on each request
+	service.SetHubMetrics(func() *models.WebSocketHubMetrics {
+		if WSHandler == nil {
+			return nil
+		}
+		metrics := WSHandler.HubMetrics()
+		return &metrics
+	})
+	handler := adminclusterhealth.NewHandler(service)
+
+	apiRouter.HandleFunc("/cluster/health", handler.Scrape).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/cluster/health", handler.Get).Methods(http.MethodGet, http.MethodOptions)
+	debug.Info("Configured cluster health routes: /cluster/health, /admin/cluster/health")
+}
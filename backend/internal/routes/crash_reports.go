@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/crashreports"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// SetupCrashReportRoutes configures the admin routes for reviewing agent crash reports
+func SetupCrashReportRoutes(adminRouter *mux.Router, agentService *services.AgentService) {
+	handler := crashreports.NewHandler(agentService)
+
+	adminRouter.HandleFunc("/agent-crash-reports", handler.List).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/agent-crash-reports/{id:[0-9]+}", handler.Get).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/agent-crash-reports/{id:[0-9]+}/review", handler.MarkReviewed).Methods(http.MethodPost, http.MethodOptions)
+	debug.Info("Configured admin crash report routes: /admin/agent-crash-reports")
+}
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/exports"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/export"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// SetupExportRoutes configures the export job routes and the signed download
+// route, which needs no session, and starts cleaning up expired artifacts
+func SetupExportRoutes(apiRouter *mux.Router, jwtRouter *mux.Router, database *db.DB, cfg *config.Config) {
+	hashRepo := repository.NewHashRepository(database)
+
+	service := export.NewExportService(
+		repository.NewExportJobRepository(database),
+		repository.NewSystemSettingsRepository(database),
+		filepath.Join(cfg.DataDir, "exports"),
+		[]byte(os.Getenv("JWT_SECRET")),
+	)
+	service.Register(models.ExportKindClientPotfile,
+		export.ClientPotfileExporter(hashRepo, repository.NewClientRepository(database)))
+	service.Register(models.ExportKindHashlistCracked,
+		export.HashlistCrackedExporter(hashRepo, repository.NewHashListRepository(database)))
+	go service.Start(context.Background())
+
+	handler := exports.NewHandler(service)
+	apiRouter.HandleFunc("/exports/{id}/download", handler.Download).Methods(http.MethodGet, http.MethodOptions)
+
+	jwtRouter.HandleFunc("/exports", handler.Create).Methods(http.MethodPost, http.MethodOptions)
+	jwtRouter.HandleFunc("/exports", handler.List).Methods(http.MethodGet, http.MethodOptions)
+	jwtRouter.HandleFunc("/exports/{id}", handler.Get).Methods(http.MethodGet, http.MethodOptions)
+	jwtRouter.HandleFunc("/exports/{id}", handler.Delete).Methods(http.MethodDelete, http.MethodOptions)
+	debug.Info("Configured export routes: /exports, /exports/{id}, /exports/{id}/download")
+}
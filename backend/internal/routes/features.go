@@ -41,6 +41,7 @@ func SetupAgentRoutes(jwtRouter *mux.Router, agentService *services.AgentService
 	jwtRouter.HandleFunc("/agents/{id}/devices/{deviceId}", agentHandler.UpdateDeviceStatus).Methods("PUT", "OPTIONS")
 	jwtRouter.HandleFunc("/agents/{id}/with-devices", agentHandler.GetAgentWithDevices).Methods("GET", "OPTIONS")
 	jwtRouter.HandleFunc("/agents/{id}/metrics", agentHandler.GetAgentMetrics).Methods("GET", "OPTIONS")
+	jwtRouter.HandleFunc("/agents/{id}/heartbeats", agentHandler.GetAgentHeartbeats).Methods("GET", "OPTIONS")
 
 	// Clear busy status route - manual override for stuck agents
 	jwtRouter.HandleFunc("/agents/{id}/clear-busy-status", agentHandler.ClearBusyStatus).Methods("POST", "OPTIONS")
@@ -66,6 +67,11 @@ func SetupAgentRoutes(jwtRouter *mux.Router, agentService *services.AgentService
 	jwtRouter.HandleFunc("/agents/{id}/schedules/{day}", schedulingHandler.DeleteAgentSchedule).Methods("DELETE", "OPTIONS")
 	jwtRouter.HandleFunc("/agents/{id}/scheduling-enabled", schedulingHandler.ToggleAgentScheduling).Methods("PUT", "OPTIONS")
 	jwtRouter.HandleFunc("/agents/{id}/schedules/bulk", schedulingHandler.BulkUpdateSchedules).Methods("POST", "OPTIONS")
+	jwtRouter.HandleFunc("/agents/{id}/power", schedulingHandler.GetAgentPowerSettings).Methods("GET", "OPTIONS")
+	jwtRouter.HandleFunc("/agents/{id}/power-windows", schedulingHandler.UpdateAgentPowerWindow).Methods("POST", "OPTIONS")
+	jwtRouter.HandleFunc("/agents/{id}/power-windows/{day}", schedulingHandler.DeleteAgentPowerWindow).Methods("DELETE", "OPTIONS")
+	jwtRouter.HandleFunc("/agents/{id}/power-budget", schedulingHandler.UpdateAgentPowerBudget).Methods("PUT", "OPTIONS")
+	jwtRouter.HandleFunc("/agents/{id}/power-budget", schedulingHandler.DeleteAgentPowerBudget).Methods("DELETE", "OPTIONS")
 
 	debug.Info("Configured agent management endpoints: /agents")
 }
@@ -75,6 +81,7 @@ func SetupVoucherRoutes(jwtRouter *mux.Router, voucherService *services.ClaimVou
 	voucherHandler := vouchers.NewVoucherHandler(voucherService)
 	jwtRouter.HandleFunc("/vouchers/temp", voucherHandler.GenerateVoucher).Methods("POST", "OPTIONS")
 	jwtRouter.HandleFunc("/vouchers", voucherHandler.ListVouchers).Methods("GET", "OPTIONS")
+	jwtRouter.HandleFunc("/vouchers/{code}/usage", voucherHandler.ListVoucherUsage).Methods("GET", "OPTIONS")
 	jwtRouter.HandleFunc("/vouchers/{code}/disable", voucherHandler.DeactivateVoucher).Methods("DELETE", "OPTIONS")
 	debug.Info("Configured voucher management endpoints: /vouchers")
 }
@@ -88,6 +95,7 @@ func SetupPotRoutes(jwtRouter *mux.Router, hashRepo *repository.HashRepository,
 	jwtRouter.HandleFunc("/pot/hashlist/{id}", potHandler.HandleListCrackedHashesByHashlist).Methods("GET", "OPTIONS")
 	jwtRouter.HandleFunc("/pot/client/{id}", potHandler.HandleListCrackedHashesByClient).Methods("GET", "OPTIONS")
 	jwtRouter.HandleFunc("/pot/job/{id}", potHandler.HandleListCrackedHashesByJob).Methods("GET", "OPTIONS")
+	jwtRouter.HandleFunc("/pot/lookup", potHandler.HandleLookup).Methods("POST", "OPTIONS")
 
 	// Download routes for all cracked hashes
 	jwtRouter.HandleFunc("/pot/download/hash-pass", potHandler.HandleDownloadHashPass).Methods("GET", "OPTIONS")
@@ -106,6 +114,7 @@ func SetupPotRoutes(jwtRouter *mux.Router, hashRepo *repository.HashRepository,
 	jwtRouter.HandleFunc("/pot/client/{id}/download/user-pass", potHandler.HandleDownloadUserPassByClient).Methods("GET", "OPTIONS")
 	jwtRouter.HandleFunc("/pot/client/{id}/download/user", potHandler.HandleDownloadUserByClient).Methods("GET", "OPTIONS")
 	jwtRouter.HandleFunc("/pot/client/{id}/download/pass", potHandler.HandleDownloadPassByClient).Methods("GET", "OPTIONS")
+	jwtRouter.HandleFunc("/pot/client/{id}/download/potfile", potHandler.HandleDownloadClientPotfile).Methods("GET", "OPTIONS")
 
 	// Download routes for job-specific cracked hashes
 	jwtRouter.HandleFunc("/pot/job/{id}/download/hash-pass", potHandler.HandleDownloadHashPassByJob).Methods("GET", "OPTIONS")
@@ -113,5 +122,5 @@ func SetupPotRoutes(jwtRouter *mux.Router, hashRepo *repository.HashRepository,
 	jwtRouter.HandleFunc("/pot/job/{id}/download/user", potHandler.HandleDownloadUserByJob).Methods("GET", "OPTIONS")
 	jwtRouter.HandleFunc("/pot/job/{id}/download/pass", potHandler.HandleDownloadPassByJob).Methods("GET", "OPTIONS")
 
-	debug.Info("Configured pot endpoints: list and download routes for all/hashlist/client/job contexts")
+	debug.Info("Configured pot endpoints: list, lookup and download routes for all/hashlist/client/job contexts")
 }
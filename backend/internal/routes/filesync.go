@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,7 +35,7 @@ func SetupFileDownloadRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, a
 
 	// Create rule split manager for chunk recreation
 	ruleSplitDir := filepath.Join(cfg.DataDir, "temp", "rule_chunks")
-	ruleSplitManager := services.NewRuleSplitManager(ruleSplitDir, fileRepo)
+	ruleSplitManager := services.NewRuleSplitManager(ruleSplitDir, fileRepo, repository.NewRuleChunkRepository(dbWrapper))
 
 	// Create file download handlers
 	fileRouter := r.PathPrefix("/api/files").Subrouter()
@@ -337,8 +338,22 @@ func SetupFileDownloadRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, a
 
 		debug.Info("Hashlist download request from agent: id=%s", hashlistID)
 
-		// Build the hashlist file path
-		hashlistPath := filepath.Join(cfg.DataDir, "hashlists", fmt.Sprintf("%s.hash", hashlistID))
+		// Build the hashlist file path. Jobs targeting only the remaining
+		// hashes have a file of their own, named in the file parameter.
+		id, err := strconv.ParseInt(hashlistID, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid hashlist ID", http.StatusBadRequest)
+			return
+		}
+		fileName := models.HashlistFile(id)
+		if requested := r.URL.Query().Get("file"); requested != "" {
+			if !models.IsRemainingHashesFile(requested, id) {
+				http.Error(w, "Invalid hashlist file", http.StatusBadRequest)
+				return
+			}
+			fileName = requested
+		}
+		hashlistPath := filepath.Join(cfg.DataDir, "hashlists", filepath.FromSlash(fileName))
 
 		debug.Info("Looking for hashlist at path: %s", hashlistPath)
 
@@ -360,7 +375,7 @@ func SetupFileDownloadRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, a
 		defer file.Close()
 
 		// Set headers
-		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.hash", hashlistID))
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filepath.Base(fileName)))
 		w.Header().Set("Content-Type", "text/plain")
 		w.Header().Set("Content-Length", fmt.Sprintf("%d", fileInfo.Size()))
 
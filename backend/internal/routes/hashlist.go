@@ -17,13 +17,17 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
 	adminclient "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/client"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/views"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/processor"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
-	clientsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/client"
-	retentionsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/retention"
+	breachsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/breach"
+	trashsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/trash"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashutils"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/httputil"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/textnorm"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/lib/pq"
@@ -45,10 +49,16 @@ type hashlistHandler struct {
 	fileRepo           *repository.FileRepository
 	clientSettingsRepo *repository.ClientSettingsRepository
 	systemSettingsRepo *repository.SystemSettingsRepository
+	coverageRepo       *repository.AttackCoverageRepository
+	quarantineRepo     *repository.HashlistQuarantineRepository
 	dataDir            string // Base directory for storing hashlist files
 	cfg                *config.Config
 	agentService       *services.AgentService
 	processor          *processor.HashlistDBProcessor
+	trashService       *trashsvc.TrashService
+	breachService      *breachsvc.BreachService
+	accountRepo        *repository.HashlistAccountRepository
+	privilegedService  *services.PrivilegedAccountService
 	// Job-related dependencies
 	jobsHandler interface {
 		GetAvailablePresetJobs(w http.ResponseWriter, r *http.Request)
@@ -74,6 +84,8 @@ func registerHashlistRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, ag
 	fileRepo := repository.NewFileRepository(database, cfg.HashUploadDir)
 	clientSettingsRepo := repository.NewClientSettingsRepository(database)
 	systemSettingsRepo := repository.NewSystemSettingsRepository(database)
+	coverageRepo := repository.NewAttackCoverageRepository(database)
+	quarantineRepo := repository.NewHashlistQuarantineRepository(database)
 
 	// Define the storage directory for hashlists
 	hashlistDataDir := filepath.Join(cfg.DataDir, "hashlists")
@@ -85,7 +97,10 @@ func registerHashlistRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, ag
 	}
 
 	// Create processor
-	proc := processor.NewHashlistDBProcessor(hashlistRepo, hashTypeRepo, hashRepo, cfg)
+	proc := processor.NewHashlistDBProcessor(hashlistRepo, hashTypeRepo, hashRepo, quarantineRepo, systemSettingsRepo, cfg)
+
+	// Deletions of hashlists and clients go through the trash
+	trashService := newTrashService(database)
 
 	// Create handler
 	h := &hashlistHandler{
@@ -95,12 +110,18 @@ func registerHashlistRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, ag
 		clientRepo:         clientRepo,
 		clientSettingsRepo: clientSettingsRepo,
 		systemSettingsRepo: systemSettingsRepo,
+		coverageRepo:       coverageRepo,
+		quarantineRepo:     quarantineRepo,
 		hashRepo:           hashRepo,
 		fileRepo:           fileRepo,
 		dataDir:            hashlistDataDir,
 		cfg:                cfg,
 		agentService:       agentService,
 		processor:          proc,
+		trashService:       trashService,
+		breachService:      breachsvc.NewBreachService(repository.NewBreachRepository(database), systemSettingsRepo, cfg.Airgapped),
+		accountRepo:        repository.NewHashlistAccountRepository(database),
+		privilegedService:  services.NewPrivilegedAccountService(database),
 		jobsHandler:        jobsHandler,
 	}
 
@@ -110,17 +131,36 @@ func registerHashlistRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, ag
 	// userRouter := r.PathPrefix("/api").Subrouter() // REMOVE: Don't create a new /api subrouter
 	// userRouter.Use(middleware.RequireAuth(database)) // REMOVE: Middleware already applied
 
+	// Saved views expand ?view=<id> on the hash list
+	viewHandler := views.NewHandler(repository.NewSavedViewRepository(database))
+
 	// 2.1. Hashlist Management API
 	hashlistRouter := r.PathPrefix("/hashlists").Subrouter() // Use 'r' directly
 	hashlistRouter.HandleFunc("", h.handleUploadHashlist).Methods(http.MethodPost, http.MethodOptions)
 	hashlistRouter.HandleFunc("", h.handleListHashlists).Methods(http.MethodGet, http.MethodOptions)
+	hashlistRouter.HandleFunc("/detect-types", h.handleDetectHashlistTypes).Methods(http.MethodPost, http.MethodOptions)
+	hashlistRouter.HandleFunc("/source-uploads/{uploadId}", h.handleGetSourceUpload).Methods(http.MethodGet, http.MethodOptions)
 	hashlistRouter.HandleFunc("/{id}", h.handleGetHashlist).Methods(http.MethodGet, http.MethodOptions)
 	hashlistRouter.HandleFunc("/{id}", h.handleDeleteHashlist).Methods(http.MethodDelete, http.MethodOptions)
 	hashlistRouter.HandleFunc("/{id}/download", h.handleDownloadHashlist).Methods(http.MethodGet, http.MethodOptions)
-	hashlistRouter.HandleFunc("/{id}/hashes", h.handleGetHashlistHashes).Methods(http.MethodGet, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/hashes", viewHandler.Apply(models.SavedViewResourceHashes, h.handleGetHashlistHashes)).Methods(http.MethodGet, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/cracks", h.handleGetHashlistCracks).Methods(http.MethodGet, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/coverage", h.handleGetHashlistCoverage).Methods(http.MethodGet, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/quarantine", h.handleGetHashlistQuarantine).Methods(http.MethodGet, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/quarantine", h.handleClearHashlistQuarantine).Methods(http.MethodDelete, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/quarantine/download", h.handleDownloadHashlistQuarantine).Methods(http.MethodGet, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/quarantine/reprocess", h.handleReprocessHashlistQuarantine).Methods(http.MethodPost, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/breach-check", h.handleGetHashlistBreachCheck).Methods(http.MethodGet, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/breach-check", h.handleStartHashlistBreachCheck).Methods(http.MethodPost, http.MethodOptions)
 	hashlistRouter.HandleFunc("/{id}/available-jobs", h.handleGetAvailableJobs).Methods(http.MethodGet, http.MethodOptions)
 	hashlistRouter.HandleFunc("/{id}/create-job", h.handleCreateJob).Methods(http.MethodPost, http.MethodOptions)
 	hashlistRouter.HandleFunc("/{id}/client", h.handleUpdateHashlistClient).Methods(http.MethodPatch, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/annotations", h.handleUpdateHashlistAnnotations).Methods(http.MethodPut, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/accounts", h.handleGetHashlistAccounts).Methods(http.MethodGet, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/accounts", h.handleReplaceHashlistAccounts).Methods(http.MethodPut, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/staging", h.handleGetHashlistStaging).Methods(http.MethodGet, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/staging", h.handleDiscardStagedHashlist).Methods(http.MethodDelete, http.MethodOptions)
+	hashlistRouter.HandleFunc("/{id}/commit", h.handleCommitHashlist).Methods(http.MethodPost, http.MethodOptions)
 
 	// 2.2. Hash Types API
 	hashTypeRouter := r.PathPrefix("/hashtypes").Subrouter() // Use 'r' directly
@@ -131,12 +171,7 @@ func registerHashlistRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, ag
 
 	// 2.3. Clients API - Using admin handler for all authenticated users
 	// Create the admin client handler with full functionality (including cracked counts)
-	clientRepoForHandler := repository.NewClientRepository(database)
-	clientSettingsRepoForHandler := repository.NewClientSettingsRepository(database)
-	analyticsRepoForHandler := repository.NewAnalyticsRepository(database)
-	retentionService := retentionsvc.NewRetentionService(database, hashlistRepo, hashRepo, clientRepoForHandler, clientSettingsRepoForHandler, analyticsRepoForHandler)
-	clientService := clientsvc.NewClientService(clientRepoForHandler, hashlistRepo, clientSettingsRepoForHandler, retentionService)
-	clientHandler := adminclient.NewClientHandler(clientRepoForHandler, clientService)
+	clientHandler := adminclient.NewClientHandler(clientRepo, repository.NewGPUUsageRepository(database), systemSettingsRepo, trashService)
 
 	// Register client routes for all authenticated users
 	clientRouter := r.PathPrefix("/clients").Subrouter() // Use 'r' directly
@@ -146,6 +181,8 @@ func registerHashlistRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, ag
 	clientRouter.HandleFunc("/{id:[0-9a-fA-F-]+}", clientHandler.GetClient).Methods(http.MethodGet)
 	clientRouter.HandleFunc("/{id:[0-9a-fA-F-]+}", clientHandler.UpdateClient).Methods(http.MethodPut)
 	clientRouter.HandleFunc("/{id:[0-9a-fA-F-]+}", clientHandler.DeleteClient).Methods(http.MethodDelete)
+	clientRouter.HandleFunc("/{id:[0-9a-fA-F-]+}/cost", clientHandler.GetClientCost).Methods(http.MethodGet)
+	clientRouter.HandleFunc("/{id:[0-9a-fA-F-]+}/engagement", clientHandler.SetEngagementStatus).Methods(http.MethodPut)
 
 	// 2.4. Hash Search API
 	hashSearchRouter := r.PathPrefix("/hashes").Subrouter() // Use 'r' directly
@@ -194,19 +231,19 @@ func (h *hashlistHandler) handleUploadHashlist(w http.ResponseWriter, r *http.Re
 	excludeStr := r.FormValue("exclude_from_potfile")
 	debug.Info("Received hashlist upload: name='%s', hashTypeID='%s', clientName='%s', excludeFromPotfile='%s'", name, hashTypeIDStr, clientName, excludeStr)
 
-	// --- Parse and validate hash type ID ---
-	hashTypeID, err := strconv.Atoi(hashTypeIDStr)
-	if err != nil {
-		jsonError(w, "Invalid hash_type_id format", http.StatusBadRequest)
+	// --- Resolve the hash type by mode number, name or alias ---
+	if strings.TrimSpace(hashTypeIDStr) == "" {
+		jsonError(w, "hash_type_id is required", http.StatusBadRequest)
 		return
 	}
 	// Re-verify hash type exists and is enabled (important!)
-	hashType, err := h.hashTypeRepo.GetByID(ctx, hashTypeID)
+	hashType, err := h.hashTypeRepo.Resolve(ctx, hashTypeIDStr)
 	if err != nil || hashType == nil || !hashType.IsEnabled {
-		debug.Error("Invalid or disabled hash type ID %d provided during upload: %v", hashTypeID, err)
-		jsonError(w, fmt.Sprintf("Invalid or disabled hash type ID: %d", hashTypeID), http.StatusBadRequest)
+		debug.Error("Invalid or disabled hash type %q provided during upload: %v", hashTypeIDStr, err)
+		jsonError(w, fmt.Sprintf("Invalid or disabled hash type: %s", hashTypeIDStr), http.StatusBadRequest)
 		return
 	}
+	hashTypeID := hashType.ID
 
 	// --- Check if client is required ---
 	trimmedClientName := strings.TrimSpace(clientName)
@@ -335,6 +372,25 @@ func (h *hashlistHandler) handleUploadHashlist(w http.ResponseWriter, r *http.Re
 	}
 	debug.Info("Parsed exclude_from_potfile as: %v", excludeFromPotfile)
 
+	// --- Optionally convert the upload to UTF-8 with LF line endings ---
+	var upload io.Reader = file
+	var normalizer *textnorm.Reader
+	if normalize, _ := strconv.ParseBool(r.FormValue("normalize_encoding")); normalize {
+		normalizer = textnorm.NewReader(file)
+		upload = normalizer
+	}
+
+	// --- Split mixed uploads into one hashlist per detected hash type ---
+	if splitByType, _ := strconv.ParseBool(r.FormValue("split_by_type")); splitByType {
+		h.uploadSplitHashlists(w, r, upload, normalizer, header.Filename, hashType, models.HashList{
+			Name:               name,
+			UserID:             userID,
+			ClientID:           clientID,
+			ExcludeFromPotfile: excludeFromPotfile,
+		})
+		return
+	}
+
 	// --- Create database entry ---
 	now := time.Now()
 	hashlist := &models.HashList{
@@ -382,7 +438,7 @@ func (h *hashlistHandler) handleUploadHashlist(w http.ResponseWriter, r *http.Re
 	defer dst.Close()
 
 	// Copy the uploaded file data
-	_, err = io.Copy(dst, file)
+	_, err = io.Copy(dst, upload)
 	if err != nil {
 		debug.Error("Failed to copy uploaded file to %s: %v", hashlistPath, err)
 		h.updateHashlistStatus(ctx, hashlist.ID, models.HashListStatusError, "Failed to copy uploaded file data")
@@ -390,28 +446,58 @@ func (h *hashlistHandler) handleUploadHashlist(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	// --- Update database entry with file path and trigger processing ---
-	hashlist.FilePath = hashlistPath                  // Set the path for the update
-	hashlist.Status = models.HashListStatusProcessing // Ready for background processing
-	hashlist.UpdatedAt = time.Now()
+	if err := dst.Close(); err != nil {
+		debug.Error("Failed to write uploaded file to %s: %v", hashlistPath, err)
+		h.updateHashlistStatus(ctx, hashlist.ID, models.HashListStatusError, "Failed to copy uploaded file data")
+		jsonError(w, "Failed to copy uploaded file data", http.StatusInternalServerError)
+		return
+	}
 
-	err = h.hashlistRepo.UpdateFilePathAndStatus(ctx, hashlist.ID, hashlist.FilePath, hashlist.Status)
+	// --- Update database entry with file path and trigger processing or staging ---
+	hashlist.UpdatedAt = time.Now()
+	stagingPreview, err := h.finishUpload(ctx, hashlist, hashlistPath, h.stagingRequested(r))
 	if err != nil {
-		debug.Error("Failed to update hashlist path/status for %d: %v", hashlist.ID, err)
+		debug.Error("Failed to finalize hashlist upload %d: %v", hashlist.ID, err)
 		// Attempt cleanup of the saved file
 		os.Remove(hashlistPath)
-		// Maybe update status again to error?
 		jsonError(w, "Failed to finalize hashlist upload", http.StatusInternalServerError)
 		return
 	}
-
-	// --- Start background processing ---
-	go h.processor.SubmitHashlistForProcessing(hashlist.ID)
-	debug.Info("Hashlist %d uploaded successfully, path: %s. Background processing triggered.", hashlist.ID, hashlistPath)
+	debug.Info("Hashlist %d uploaded successfully, path: %s, status: %s", hashlist.ID, hashlistPath, hashlist.Status)
 
 	// Return the initial hashlist record (without file path for security)
-	hashlist.FilePath = ""                         // Don't expose file path in response
-	jsonResponse(w, http.StatusAccepted, hashlist) // Use 202 Accepted as processing is happening
+	hashlist.FilePath = "" // Don't expose file path in response
+	response := hashlistUploadResponse{HashList: hashlist, StagingPreview: stagingPreview}
+	if normalizer != nil {
+		report := normalizer.Report()
+		response.Normalization = &report
+		debug.Info("Hashlist %d normalized from %s, %d of %d lines changed", hashlist.ID, report.SourceEncoding, report.ChangedLines, report.Lines)
+	}
+	if stagingPreview != nil {
+		jsonResponse(w, http.StatusCreated, response) // Staged uploads wait for a commit
+		return
+	}
+	jsonResponse(w, http.StatusAccepted, response) // Use 202 Accepted as processing is happening
+}
+
+// hashlistUploadResponse is an uploaded hashlist with what normalizing its
+// encoding changed and, for a staged upload, its preview
+type hashlistUploadResponse struct {
+	*models.HashList
+	Normalization *textnorm.Report `json:"normalization,omitempty"`
+	// StagingPreview is set when the upload was staged for review
+	StagingPreview *models.HashlistStagingPreview `json:"staging_preview,omitempty"`
+}
+
+// hashlistSortColumns maps the sort fields accepted by the hashlist list endpoints to SQL columns
+var hashlistSortColumns = map[string]string{
+	"name":           "h.name",
+	"status":         "h.status",
+	"total_hashes":   "h.total_hashes",
+	"cracked_hashes": "h.cracked_hashes",
+	"client_name":    "c.name",
+	"created_at":     "h.created_at",
+	"updated_at":     "h.updated_at",
 }
 
 func (h *hashlistHandler) handleListHashlists(w http.ResponseWriter, r *http.Request) {
@@ -433,19 +519,34 @@ func (h *hashlistHandler) handleListHashlists(w http.ResponseWriter, r *http.Req
 		offset = 0 // Default offset
 	}
 
+	// Common list syntax: page/page_size, sort, filter[...] and fields
+	listQuery := httputil.ParseListQuery(r, limit, 500)
+	if queryVals.Get("page") != "" || queryVals.Get("page_size") != "" {
+		limit, offset = listQuery.Limit(), listQuery.Offset()
+	}
+
 	// Filtering
 	params := repository.ListHashlistsParams{
-		Limit:  limit,
-		Offset: offset,
+		Limit:   limit,
+		Offset:  offset,
+		OrderBy: listQuery.OrderBy(hashlistSortColumns),
 	}
 
-	if status := queryVals.Get("status"); status != "" {
+	if status := listQuery.Filter(r, "status"); status != "" {
 		params.Status = &status
 	}
-	if name := queryVals.Get("name"); name != "" {
+	if name := listQuery.Filter(r, "name"); name != "" {
 		params.NameLike = &name
 	}
-	if clientIDStr := queryVals.Get("client_id"); clientIDStr != "" {
+	if uploadIDStr := listQuery.Filter(r, "source_upload_id"); uploadIDStr != "" {
+		if uploadID, err := uuid.Parse(uploadIDStr); err == nil {
+			params.SourceUploadID = &uploadID
+		} else {
+			jsonError(w, "Invalid source_upload_id format", http.StatusBadRequest)
+			return
+		}
+	}
+	if clientIDStr := listQuery.Filter(r, "client_id"); clientIDStr != "" {
 		clientID, err := uuid.Parse(clientIDStr)
 		if err == nil {
 			params.ClientID = &clientID
@@ -473,14 +574,20 @@ func (h *hashlistHandler) handleListHashlists(w http.ResponseWriter, r *http.Req
 		debug.Info("[handleListHashlists] No hashlists retrieved.")
 	}
 
+	data, err := httputil.SelectFields(hashlists, listQuery.Fields)
+	if err != nil {
+		jsonError(w, "Failed to retrieve hashlists", http.StatusInternalServerError)
+		return
+	}
+
 	// Prepare response with pagination metadata
 	response := struct {
-		Data       []models.HashList `json:"data"` // Ensure this matches frontend expectation
-		TotalCount int               `json:"total_count"`
-		Limit      int               `json:"limit"`
-		Offset     int               `json:"offset"`
+		Data       interface{} `json:"data"` // Ensure this matches frontend expectation
+		TotalCount int         `json:"total_count"`
+		Limit      int         `json:"limit"`
+		Offset     int         `json:"offset"`
 	}{
-		Data:       hashlists,
+		Data:       data,
 		TotalCount: totalCount,
 		Limit:      limit,
 		Offset:     offset,
@@ -488,8 +595,8 @@ func (h *hashlistHandler) handleListHashlists(w http.ResponseWriter, r *http.Req
 
 	// Log the data just before sending the response for debugging
 	debug.Debug("[handleListHashlists] Final response structure to be sent: %+v", response)
-	if len(response.Data) > 0 {
-		debug.Debug("[handleListHashlists] First hashlist in response data: ClientID=%s, ClientName=%v", response.Data[0].ClientID, response.Data[0].ClientName)
+	if len(hashlists) > 0 {
+		debug.Debug("[handleListHashlists] First hashlist in response data: ClientID=%s, ClientName=%v", hashlists[0].ClientID, hashlists[0].ClientName)
 	}
 
 	jsonResponse(w, http.StatusOK, response)
@@ -519,18 +626,25 @@ func (h *hashlistHandler) handleListUserHashlists(w http.ResponseWriter, r *http
 		offset = 0 // Default offset
 	}
 
+	// Common list syntax: page/page_size, sort, filter[...] and fields
+	listQuery := httputil.ParseListQuery(r, limit, 500)
+	if queryVals.Get("page") != "" || queryVals.Get("page_size") != "" {
+		limit, offset = listQuery.Limit(), listQuery.Offset()
+	}
+
 	// Filtering
 	params := repository.ListHashlistsParams{
-		Limit:  limit,
-		Offset: offset,
-		UserID: &userID, // Filter by authenticated user
+		Limit:   limit,
+		Offset:  offset,
+		UserID:  &userID, // Filter by authenticated user
+		OrderBy: listQuery.OrderBy(hashlistSortColumns),
 	}
 
 	// Support additional filters
-	if status := queryVals.Get("status"); status != "" {
+	if status := listQuery.Filter(r, "status"); status != "" {
 		params.Status = &status
 	}
-	if name := queryVals.Get("name"); name != "" {
+	if name := listQuery.Filter(r, "name"); name != "" {
 		params.NameLike = &name
 	}
 
@@ -542,14 +656,20 @@ func (h *hashlistHandler) handleListUserHashlists(w http.ResponseWriter, r *http
 		return
 	}
 
+	data, err := httputil.SelectFields(hashlists, listQuery.Fields)
+	if err != nil {
+		jsonError(w, "Failed to retrieve hashlists", http.StatusInternalServerError)
+		return
+	}
+
 	// Prepare response with pagination metadata
 	response := struct {
-		Data       []models.HashList `json:"data"`
-		TotalCount int               `json:"total_count"`
-		Limit      int               `json:"limit"`
-		Offset     int               `json:"offset"`
+		Data       interface{} `json:"data"`
+		TotalCount int         `json:"total_count"`
+		Limit      int         `json:"limit"`
+		Offset     int         `json:"offset"`
 	}{
-		Data:       hashlists,
+		Data:       data,
 		TotalCount: totalCount,
 		Limit:      limit,
 		Offset:     offset,
@@ -605,10 +725,23 @@ func (h *hashlistHandler) handleGetHashlist(w http.ResponseWriter, r *http.Reque
 		"status":               hashlist.Status,
 		"error_message":        hashlist.ErrorMessage,
 		"exclude_from_potfile": hashlist.ExcludeFromPotfile,
+		"source_upload_id":     hashlist.SourceUploadID,
+		"parent_hashlist_id":   hashlist.ParentHashlistID,
+		"part_number":          hashlist.PartNumber,
+		"notes":                hashlist.Notes,
+		"tags":                 hashlist.Tags,
 		"createdAt":            hashlist.CreatedAt,
 		"updatedAt":            hashlist.UpdatedAt,
 	}
 
+	// A hashlist above max_hashes_per_hashlist lists the sub-lists its jobs run against
+	subLists, err := h.hashlistRepo.ListSubLists(ctx, hashlist.ID)
+	if err != nil {
+		debug.Error("Error getting sub-lists of hashlist %d: %v", id, err)
+	} else if len(subLists) > 0 {
+		response["sub_lists"] = subLists
+	}
+
 	// Add enriched hash type field if available
 	if hashType != nil {
 		response["hashTypeName"] = fmt.Sprintf("%s (%d)", hashType.Name, hashType.ID)
@@ -620,17 +753,13 @@ func (h *hashlistHandler) handleGetHashlist(w http.ResponseWriter, r *http.Reque
 
 func (h *hashlistHandler) handleDeleteHashlist(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	_, err := getUserIDFromContext(ctx)
+	userID, err := getUserIDFromContext(ctx)
 	if err != nil {
 		jsonError(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 	// Note: Ownership check removed - all authenticated users can delete all hashlists
 	// This will change when teams are implemented
-	// Check if user is admin
-	role, _ := getUserRoleFromContext(ctx) // Keeping for potential future use
-	isAdmin := role == "admin"
-	_ = isAdmin // Suppress unused variable warning
 
 	id, err := getInt64FromPath(r, "id")
 	if err != nil {
@@ -638,34 +767,21 @@ func (h *hashlistHandler) handleDeleteHashlist(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	hashlist, err := h.hashlistRepo.GetByID(ctx, id)
+	// Moves the hashlist to the trash, or deletes it and its file outright when the trash is disabled
+	err = h.trashService.DeleteHashlist(ctx, id, &userID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			jsonError(w, "Hashlist not found", http.StatusNotFound) // Or just return 204? Debateable.
-		} else {
-			debug.Error("Error checking hashlist %d before delete: %v", id, err)
-			jsonError(w, "Failed to retrieve hashlist before deletion", http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			jsonError(w, "Hashlist not found", http.StatusNotFound)
+		case errors.Is(err, repository.ErrHashlistHasActiveJobs):
+			jsonError(w, "Hashlist has scheduled, pending, running or paused jobs; stop or delete them first", http.StatusConflict)
+		default:
+			debug.Error("Error deleting hashlist %d: %v", id, err)
+			jsonError(w, "Failed to delete hashlist", http.StatusInternalServerError)
 		}
 		return
 	}
 
-	// Delete from database (associations are handled by ON DELETE CASCADE)
-	err = h.hashlistRepo.Delete(ctx, id)
-	if err != nil {
-		debug.Error("Error deleting hashlist %d from DB: %v", id, err)
-		jsonError(w, "Failed to delete hashlist record", http.StatusInternalServerError)
-		return
-	}
-
-	// Delete the physical file
-	if hashlist.FilePath != "" {
-		err := os.Remove(hashlist.FilePath)
-		if err != nil && !os.IsNotExist(err) {
-			// Log error but proceed, DB record is gone.
-			debug.Warning("Failed to delete hashlist file %s after DB delete for ID %d: %v", hashlist.FilePath, id, err)
-		}
-	}
-
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -749,6 +865,116 @@ func (h *hashlistHandler) handleUpdateHashlistClient(w http.ResponseWriter, r *h
 	jsonResponse(w, http.StatusOK, hashlist)
 }
 
+// handleUpdateHashlistAnnotations replaces the notes and tags of a hashlist
+func (h *hashlistHandler) handleUpdateHashlistAnnotations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, err := getUserIDFromContext(ctx); err != nil {
+		jsonError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := getInt64FromPath(r, "id")
+	if err != nil {
+		jsonError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var annotations models.Annotations
+	if err := json.NewDecoder(r.Body).Decode(&annotations); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := annotations.Normalize(); err != nil {
+		jsonError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.hashlistRepo.UpdateAnnotations(ctx, id, annotations); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			jsonError(w, "Hashlist not found", http.StatusNotFound)
+		} else {
+			debug.Error("Error updating annotations of hashlist %d: %v", id, err)
+			jsonError(w, "Failed to update hashlist annotations", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, annotations)
+}
+
+// hashlistAccountsRequest is the body of PUT /hashlists/{id}/accounts
+type hashlistAccountsRequest struct {
+	Accounts []models.HashlistAccount `json:"accounts"`
+}
+
+// handleGetHashlistAccounts returns the account metadata of a hashlist,
+// privileged accounts first
+func (h *hashlistHandler) handleGetHashlistAccounts(w http.ResponseWriter, r *http.Request) {
+	hashlist := h.getHashlistForRequest(w, r)
+	if hashlist == nil {
+		return
+	}
+
+	accounts, err := h.accountRepo.List(r.Context(), hashlist.ID)
+	if err != nil {
+		debug.Error("Error listing accounts of hashlist %d: %v", hashlist.ID, err)
+		jsonError(w, "Failed to retrieve hashlist accounts", http.StatusInternalServerError)
+		return
+	}
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"accounts": accounts})
+}
+
+// handleReplaceHashlistAccounts sets the account metadata of a hashlist, the
+// group memberships from its ingest source. Accounts in a privileged group
+// that are already cracked are flagged right away.
+func (h *hashlistHandler) handleReplaceHashlistAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	hashlist := h.getHashlistForRequest(w, r)
+	if hashlist == nil {
+		return
+	}
+
+	var req hashlistAccountsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Accounts) > models.MaxHashlistAccounts {
+		jsonError(w, fmt.Sprintf("At most %d accounts are allowed", models.MaxHashlistAccounts), http.StatusBadRequest)
+		return
+	}
+	privilegedGroups := h.privilegedService.PrivilegedGroups(ctx)
+	for i := range req.Accounts {
+		if err := req.Accounts[i].Normalize(privilegedGroups); err != nil {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req.Accounts[i].HashlistID = hashlist.ID
+	}
+
+	if err := h.accountRepo.Replace(ctx, hashlist.ID, req.Accounts); err != nil {
+		if errors.Is(err, repository.ErrDuplicateRecord) {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+		} else {
+			debug.Error("Error replacing accounts of hashlist %d: %v", hashlist.ID, err)
+			jsonError(w, "Failed to update hashlist accounts", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := h.privilegedService.CheckHashlist(ctx, hashlist.ID); err != nil {
+		debug.Error("Error checking privileged accounts of hashlist %d: %v", hashlist.ID, err)
+	}
+
+	accounts, err := h.accountRepo.List(ctx, hashlist.ID)
+	if err != nil {
+		debug.Error("Error listing accounts of hashlist %d: %v", hashlist.ID, err)
+		jsonError(w, "Failed to retrieve hashlist accounts", http.StatusInternalServerError)
+		return
+	}
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"accounts": accounts})
+}
+
 func (h *hashlistHandler) handleDownloadHashlist(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id, err := getInt64FromPath(r, "id")
@@ -873,8 +1099,23 @@ func (h *hashlistHandler) handleGetHashlistHashes(w http.ResponseWriter, r *http
 		}
 	}
 
+	// Optional filters: ?cracked=true|false, ?username= and ?domain= substrings
+	query := r.URL.Query()
+	filter := repository.HashListFilter{
+		Username: strings.TrimSpace(query.Get("username")),
+		Domain:   strings.TrimSpace(query.Get("domain")),
+	}
+	if crackedStr := query.Get("cracked"); crackedStr != "" {
+		cracked, err := strconv.ParseBool(crackedStr)
+		if err != nil {
+			jsonError(w, "Invalid cracked filter, use true or false", http.StatusBadRequest)
+			return
+		}
+		filter.Cracked = &cracked
+	}
+
 	// Get hashes for this hashlist
-	hashes, total, err := h.hashRepo.GetHashesByHashlistID(ctx, id, limit, offset)
+	hashes, total, err := h.hashRepo.ListHashlistHashes(ctx, id, filter, limit, offset)
 	if err != nil {
 		debug.Error("Error retrieving hashes for hashlist %d: %v", id, err)
 		jsonError(w, "Failed to retrieve hashes", http.StatusInternalServerError)
@@ -892,6 +1133,329 @@ func (h *hashlistHandler) handleGetHashlistHashes(w http.ResponseWriter, r *http
 	jsonResponse(w, http.StatusOK, response)
 }
 
+// handleGetHashlistCracks returns the hashes of a hashlist cracked since a
+// cursor, oldest first, so views of large hashlists can apply new cracks
+// without reloading every hash. Pass the next_cursor of the previous response
+// as ?cursor=, or ?since=<RFC3339 time> to start from a point in time; with
+// neither, all cracks are returned. has_more means another page is waiting.
+func (h *hashlistHandler) handleGetHashlistCracks(w http.ResponseWriter, r *http.Request) {
+	hashlist := h.getHashlistForRequest(w, r)
+	if hashlist == nil {
+		return
+	}
+
+	var after models.CrackCursor
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		parsed, err := models.ParseCrackCursor(cursor)
+		if err != nil {
+			jsonError(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		after = parsed
+	} else if since := r.URL.Query().Get("since"); since != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, since)
+		if err != nil {
+			jsonError(w, "Invalid since, expected an RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		after.CrackedAt = parsed
+	}
+
+	limit := 500
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 2000 {
+			limit = parsedLimit
+		}
+	}
+
+	// Fetch one extra crack to know whether another page follows
+	cracks, err := h.hashRepo.GetCracksSince(r.Context(), hashlist.ID, after, limit+1)
+	if err != nil {
+		debug.Error("Error getting cracks for hashlist %d: %v", hashlist.ID, err)
+		jsonError(w, "Failed to retrieve cracks", http.StatusInternalServerError)
+		return
+	}
+	hasMore := len(cracks) > limit
+	if hasMore {
+		cracks = cracks[:limit]
+	}
+
+	// Without new cracks the client keeps polling from where it was
+	nextCursor := r.URL.Query().Get("cursor")
+	if len(cracks) > 0 {
+		nextCursor = cracks[len(cracks)-1].CursorAfter().String()
+	} else if nextCursor == "" && !after.CrackedAt.IsZero() {
+		nextCursor = after.String()
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"cracks":         cracks,
+		"next_cursor":    nextCursor,
+		"has_more":       hasMore,
+		"cracked_hashes": hashlist.CrackedHashes,
+	})
+}
+
+// handleGetHashlistCoverage returns the attacks that have already been run
+// against a hashlist. Optional filters: attack_mode, wordlist_id, rule_id and
+// completed=true, e.g. ?wordlist_id=3&rule_id=7 answers "have we tried this
+// wordlist with this rule file?".
+func (h *hashlistHandler) handleGetHashlistCoverage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, err := getUserIDFromContext(ctx); err != nil {
+		jsonError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := getInt64FromPath(r, "id")
+	if err != nil {
+		jsonError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.hashlistRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) || strings.Contains(err.Error(), "not found") {
+			jsonError(w, "Hashlist not found", http.StatusNotFound)
+		} else {
+			debug.Error("Error getting hashlist %d: %v", id, err)
+			jsonError(w, "Failed to retrieve hashlist", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	query := httputil.ParseListQuery(r, 100, 1000)
+	filter := repository.AttackCoverageFilter{
+		WordlistID:    query.Filter(r, "wordlist_id"),
+		RuleID:        query.Filter(r, "rule_id"),
+		CompletedOnly: query.Filter(r, "completed") == "true",
+	}
+	if modeStr := query.Filter(r, "attack_mode"); modeStr != "" {
+		mode, err := strconv.Atoi(modeStr)
+		if err != nil {
+			jsonError(w, "Invalid attack_mode", http.StatusBadRequest)
+			return
+		}
+		filter.AttackMode = &mode
+	}
+
+	entries, err := h.coverageRepo.ListByHashlist(ctx, id, filter)
+	if err != nil {
+		debug.Error("Error getting attack coverage for hashlist %d: %v", id, err)
+		jsonError(w, "Failed to retrieve attack coverage", http.StatusInternalServerError)
+		return
+	}
+
+	summary, err := h.coverageRepo.GetSummary(ctx, id)
+	if err != nil {
+		debug.Error("Error getting attack coverage summary for hashlist %d: %v", id, err)
+		jsonError(w, "Failed to retrieve attack coverage", http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"summary": summary,
+		"data":    entries,
+	})
+}
+
+// getHashlistForRequest loads the hashlist from the {id} path variable,
+// writing the appropriate error response and returning nil if it fails.
+func (h *hashlistHandler) getHashlistForRequest(w http.ResponseWriter, r *http.Request) *models.HashList {
+	if _, err := getUserIDFromContext(r.Context()); err != nil {
+		jsonError(w, "Unauthorized", http.StatusUnauthorized)
+		return nil
+	}
+
+	id, err := getInt64FromPath(r, "id")
+	if err != nil {
+		jsonError(w, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+
+	hashlist, err := h.hashlistRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || strings.Contains(err.Error(), "not found") {
+			jsonError(w, "Hashlist not found", http.StatusNotFound)
+		} else {
+			debug.Error("Error getting hashlist %d: %v", id, err)
+			jsonError(w, "Failed to retrieve hashlist", http.StatusInternalServerError)
+		}
+		return nil
+	}
+	return hashlist
+}
+
+// handleGetHashlistQuarantine returns the lines rejected while parsing a hashlist, paginated.
+func (h *hashlistHandler) handleGetHashlistQuarantine(w http.ResponseWriter, r *http.Request) {
+	hashlist := h.getHashlistForRequest(w, r)
+	if hashlist == nil {
+		return
+	}
+
+	query := httputil.ParseListQuery(r, 100, 1000)
+	lines, total, err := h.quarantineRepo.ListByHashlist(r.Context(), hashlist.ID, query.Limit(), query.Offset())
+	if err != nil {
+		debug.Error("Error getting quarantined lines for hashlist %d: %v", hashlist.ID, err)
+		jsonError(w, "Failed to retrieve quarantined lines", http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"data":       lines,
+		"pagination": httputil.NewPagination(query, total),
+	})
+}
+
+// handleDownloadHashlistQuarantine streams the quarantined lines as a text file so they can be fixed offline.
+func (h *hashlistHandler) handleDownloadHashlistQuarantine(w http.ResponseWriter, r *http.Request) {
+	hashlist := h.getHashlistForRequest(w, r)
+	if hashlist == nil {
+		return
+	}
+
+	lines, _, err := h.quarantineRepo.ListByHashlist(r.Context(), hashlist.ID, 0, 0)
+	if err != nil {
+		debug.Error("Error getting quarantined lines for hashlist %d: %v", hashlist.ID, err)
+		jsonError(w, "Failed to retrieve quarantined lines", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%d_quarantine.txt\"", hashlist.ID))
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line.LineContent); err != nil {
+			debug.Error("Error streaming quarantine for hashlist %d: %v", hashlist.ID, err)
+			return
+		}
+	}
+}
+
+// handleReprocessHashlistQuarantine validates quarantined lines again and loads
+// the ones that now pass. The body may be text/plain with corrected lines, a
+// JSON object {"lines": [...]}, or empty to retry the stored lines unchanged.
+func (h *hashlistHandler) handleReprocessHashlistQuarantine(w http.ResponseWriter, r *http.Request) {
+	hashlist := h.getHashlistForRequest(w, r)
+	if hashlist == nil {
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 100<<20))
+	if err != nil {
+		jsonError(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var lines []string
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			var req struct {
+				Lines []string `json:"lines"`
+			}
+			if err := json.Unmarshal(body, &req); err != nil {
+				jsonError(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+			lines = req.Lines
+		} else {
+			lines = strings.Split(string(body), "\n")
+		}
+	}
+
+	result, err := h.processor.ReprocessQuarantine(r.Context(), hashlist.ID, lines)
+	if err != nil {
+		debug.Error("Error reprocessing quarantine for hashlist %d: %v", hashlist.ID, err)
+		jsonError(w, fmt.Sprintf("Failed to reprocess quarantined lines: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, result)
+}
+
+// handleClearHashlistQuarantine discards all quarantined lines of a hashlist.
+func (h *hashlistHandler) handleClearHashlistQuarantine(w http.ResponseWriter, r *http.Request) {
+	hashlist := h.getHashlistForRequest(w, r)
+	if hashlist == nil {
+		return
+	}
+
+	removed, err := h.quarantineRepo.DeleteByHashlist(r.Context(), hashlist.ID)
+	if err != nil {
+		debug.Error("Error clearing quarantine for hashlist %d: %v", hashlist.ID, err)
+		jsonError(w, "Failed to clear quarantined lines", http.StatusInternalServerError)
+		return
+	}
+
+	if hashlist.Status == models.HashListStatusReadyWithErrors {
+		if err := h.hashlistRepo.UpdateStatus(r.Context(), hashlist.ID, models.HashListStatusReady, ""); err != nil {
+			debug.Error("Error updating status of hashlist %d after clearing quarantine: %v", hashlist.ID, err)
+		}
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"removed": removed})
+}
+
+// handleStartHashlistBreachCheck starts checking the cracked passwords of a
+// hashlist against the configured breach corpus. The check runs in the
+// background, poll the GET endpoint for its result.
+func (h *hashlistHandler) handleStartHashlistBreachCheck(w http.ResponseWriter, r *http.Request) {
+	hashlist := h.getHashlistForRequest(w, r)
+	if hashlist == nil {
+		return
+	}
+
+	check, err := h.breachService.StartCheck(r.Context(), hashlist.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, breachsvc.ErrDisabled):
+			jsonError(w, "Breach checks are disabled, set breach_check_mode to online or offline", http.StatusBadRequest)
+		case errors.Is(err, breachsvc.ErrCheckRunning):
+			jsonError(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, breachsvc.ErrAirgapped):
+			jsonError(w, err.Error(), http.StatusBadRequest)
+		default:
+			debug.Error("Error starting breach check for hashlist %d: %v", hashlist.ID, err)
+			jsonError(w, fmt.Sprintf("Failed to start breach check: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	jsonResponse(w, http.StatusAccepted, check)
+}
+
+// handleGetHashlistBreachCheck returns the latest breach check of a hashlist
+// and its breached hashes, paginated and most frequently breached first.
+func (h *hashlistHandler) handleGetHashlistBreachCheck(w http.ResponseWriter, r *http.Request) {
+	hashlist := h.getHashlistForRequest(w, r)
+	if hashlist == nil {
+		return
+	}
+
+	check, err := h.breachService.GetCheck(r.Context(), hashlist.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			jsonError(w, "Hashlist has not been checked for breached passwords", http.StatusNotFound)
+		} else {
+			debug.Error("Error getting breach check for hashlist %d: %v", hashlist.ID, err)
+			jsonError(w, "Failed to retrieve breach check", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	query := httputil.ParseListQuery(r, 100, 1000)
+	hashes, total, err := h.breachService.ListBreachedHashes(r.Context(), hashlist.ID, query.Limit(), query.Offset())
+	if err != nil {
+		debug.Error("Error getting breached hashes for hashlist %d: %v", hashlist.ID, err)
+		jsonError(w, "Failed to retrieve breach check", http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"check":      check,
+		"data":       hashes,
+		"pagination": httputil.NewPagination(query, total),
+	})
+}
+
 // 2.2. Hash Types Handlers
 
 func (h *hashlistHandler) handleListHashTypes(w http.ResponseWriter, r *http.Request) {
@@ -959,6 +1523,12 @@ func (h *hashlistHandler) handleCreateHashType(w http.ResponseWriter, r *http.Re
 		jsonError(w, "Hash Type ID must be positive and Name is required", http.StatusBadRequest)
 		return
 	}
+	if status, msg := h.validateHashTypeDefinition(ctx, &hashType); status != 0 {
+		jsonError(w, msg, status)
+		return
+	}
+	// Hash types created through the API are custom, built-in ones come from migrations
+	hashType.IsCustom = true
 
 	err = h.hashTypeRepo.Create(ctx, &hashType)
 	if err != nil {
@@ -1011,6 +1581,10 @@ func (h *hashlistHandler) handleUpdateHashType(w http.ResponseWriter, r *http.Re
 		jsonError(w, "Hash Type Name is required", http.StatusBadRequest)
 		return
 	}
+	if status, msg := h.validateHashTypeDefinition(ctx, &hashType); status != 0 {
+		jsonError(w, msg, status)
+		return
+	}
 
 	err = h.hashTypeRepo.Update(ctx, &hashType)
 	if err != nil {
@@ -1026,6 +1600,55 @@ func (h *hashlistHandler) handleUpdateHashType(w http.ResponseWriter, r *http.Re
 	jsonResponse(w, http.StatusOK, hashType)
 }
 
+// validateHashTypeDefinition normalizes the aliases of a hash type being saved
+// and checks them and its validation regex. It returns the HTTP status and
+// message to reject it with, or 0 if it is valid.
+func (h *hashlistHandler) validateHashTypeDefinition(ctx context.Context, hashType *models.HashType) (int, string) {
+	aliases := make([]string, 0, len(hashType.Aliases))
+	seen := make(map[string]bool)
+	for _, alias := range hashType.Aliases {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		if alias == "" || seen[alias] {
+			continue
+		}
+		// Numbers always refer to hashcat modes
+		if _, err := strconv.Atoi(alias); err == nil {
+			return http.StatusBadRequest, fmt.Sprintf("Alias %q must not be a number", alias)
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+	}
+	hashType.Aliases = aliases
+
+	ownerID, alias, taken, err := h.hashTypeRepo.FindAliasOwner(ctx, aliases, hashType.ID)
+	if err != nil {
+		debug.Error("Error checking aliases of hash type %d: %v", hashType.ID, err)
+		return http.StatusInternalServerError, "Failed to check hash type aliases"
+	}
+	if taken {
+		return http.StatusConflict, fmt.Sprintf("Alias %q is already used by hash type %d", alias, ownerID)
+	}
+
+	if hashType.ValidationRegex != nil && strings.TrimSpace(*hashType.ValidationRegex) == "" {
+		hashType.ValidationRegex = nil
+	}
+	if hashType.ValidationRegex == nil {
+		return 0, ""
+	}
+	pattern, err := hashutils.CompileValidationRegex(*hashType.ValidationRegex)
+	if err != nil {
+		return http.StatusBadRequest, err.Error()
+	}
+	// The example must be loadable with the pattern, or the definition is inconsistent
+	if hashType.Example != nil && *hashType.Example != "" {
+		value := hashutils.ProcessHashIfNeeded(*hashType.Example, hashType.ID, hashType.NeedsProcessing)
+		if err := hashutils.ValidateHashPattern(value, pattern); err != nil {
+			return http.StatusBadRequest, "Example hash does not match the validation regex"
+		}
+	}
+	return 0, ""
+}
+
 func (h *hashlistHandler) handleDeleteHashType(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	isAdmin, err := requireAdmin(ctx)
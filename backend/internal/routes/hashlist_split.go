@@ -0,0 +1,267 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/processor"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/textnorm"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// splitUploadResponse is returned for an upload with split_by_type set
+type splitUploadResponse struct {
+	SourceUploadID *uuid.UUID         `json:"source_upload_id"` // Nil when the upload held a single hash type
+	Hashlists      []*models.HashList `json:"hashlists"`
+	Normalization  *textnorm.Report   `json:"normalization,omitempty"`
+	// StagingPreviews maps the hashlist IDs to their previews when the upload was staged
+	StagingPreviews map[int64]*models.HashlistStagingPreview `json:"staging_previews,omitempty"`
+}
+
+// hashTypeLookup returns an accept function for the upload splitter that
+// admits enabled hash types, and the map it caches the hash types in.
+func (h *hashlistHandler) hashTypeLookup(ctx context.Context, chosen *models.HashType) (func(int) bool, map[int]*models.HashType) {
+	types := map[int]*models.HashType{chosen.ID: chosen}
+	rejected := map[int]bool{}
+	accept := func(hashTypeID int) bool {
+		if _, ok := types[hashTypeID]; ok {
+			return true
+		}
+		if rejected[hashTypeID] {
+			return false
+		}
+		hashType, err := h.hashTypeRepo.GetByID(ctx, hashTypeID)
+		if err != nil || hashType == nil || !hashType.IsEnabled {
+			rejected[hashTypeID] = true
+			return false
+		}
+		types[hashTypeID] = hashType
+		return true
+	}
+	return accept, types
+}
+
+// handleDetectHashlistTypes reports which hash types an upload contains so the
+// user can choose to split it before uploading it for real
+func (h *hashlistHandler) handleDetectHashlistTypes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, err := getUserIDFromContext(ctx); err != nil {
+		jsonError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<30)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		jsonError(w, "Error processing upload form", http.StatusBadRequest)
+		return
+	}
+
+	hashTypeRef := r.FormValue("hash_type_id")
+	if strings.TrimSpace(hashTypeRef) == "" {
+		jsonError(w, "hash_type_id is required", http.StatusBadRequest)
+		return
+	}
+	chosen, err := h.hashTypeRepo.Resolve(ctx, hashTypeRef)
+	if err != nil || chosen == nil || !chosen.IsEnabled {
+		jsonError(w, fmt.Sprintf("Invalid or disabled hash type: %s", hashTypeRef), http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("hashlist_file")
+	if err != nil {
+		jsonError(w, "hashlist_file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	accept, types := h.hashTypeLookup(ctx, chosen)
+	groups, err := processor.DetectUploadTypes(file, chosen.ID, accept)
+	if err != nil {
+		debug.Error("Failed to detect hash types of upload: %v", err)
+		jsonError(w, "Failed to read uploaded file", http.StatusBadRequest)
+		return
+	}
+	for i := range groups {
+		if hashType, ok := types[groups[i].HashTypeID]; ok {
+			groups[i].HashTypeName = hashType.Name
+		}
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"groups":            groups,
+		"split_recommended": len(groups) > 1,
+	})
+}
+
+// uploadSplitHashlists splits an upload into one hashlist per detected hash
+// type. Lines without a recognizable signature stay with the chosen type.
+// When more than one hashlist results they share a source upload record.
+func (h *hashlistHandler) uploadSplitHashlists(w http.ResponseWriter, r *http.Request, file io.Reader, normalizer *textnorm.Reader, fileName string, chosen *models.HashType, template models.HashList) {
+	ctx := r.Context()
+	stage := h.stagingRequested(r)
+	accept, types := h.hashTypeLookup(ctx, chosen)
+
+	paths, err := processor.SplitUploadByType(file, chosen.ID, accept, h.dataDir)
+	// Parts that were moved into place no longer exist, so this only removes leftovers
+	defer func() {
+		for _, path := range paths {
+			os.Remove(path)
+		}
+	}()
+	if err != nil {
+		debug.Error("Failed to split upload %s by hash type: %v", fileName, err)
+		jsonError(w, "Failed to save uploaded file", http.StatusInternalServerError)
+		return
+	}
+	if len(paths) == 0 {
+		jsonError(w, "Uploaded file contains no hashes", http.StatusBadRequest)
+		return
+	}
+
+	// The chosen type comes first, the detected ones after it by mode number
+	hashTypeIDs := make([]int, 0, len(paths))
+	for hashTypeID := range paths {
+		hashTypeIDs = append(hashTypeIDs, hashTypeID)
+	}
+	sort.Slice(hashTypeIDs, func(i, j int) bool {
+		if (hashTypeIDs[i] == chosen.ID) != (hashTypeIDs[j] == chosen.ID) {
+			return hashTypeIDs[i] == chosen.ID
+		}
+		return hashTypeIDs[i] < hashTypeIDs[j]
+	})
+
+	response := splitUploadResponse{Hashlists: []*models.HashList{}}
+	if normalizer != nil {
+		report := normalizer.Report()
+		response.Normalization = &report
+	}
+	if len(hashTypeIDs) > 1 {
+		baseName := filepath.Base(fileName)
+		if len(baseName) > 255 {
+			baseName = baseName[:255]
+		}
+		upload := &models.HashlistSourceUpload{FileName: baseName, UserID: template.UserID}
+		if template.ClientID != uuid.Nil {
+			clientID := template.ClientID
+			upload.ClientID = &clientID
+		}
+		if err := h.hashlistRepo.CreateSourceUpload(ctx, upload); err != nil {
+			debug.Error("Failed to record source upload for %s: %v", fileName, err)
+			jsonError(w, "Failed to create hashlist record", http.StatusInternalServerError)
+			return
+		}
+		response.SourceUploadID = &upload.ID
+	}
+
+	for _, hashTypeID := range hashTypeIDs {
+		hashlist := template
+		hashlist.HashTypeID = hashTypeID
+		hashlist.SourceUploadID = response.SourceUploadID
+		if response.SourceUploadID != nil {
+			hashlist.Name = fmt.Sprintf("%s (%s)", template.Name, types[hashTypeID].Name)
+		}
+
+		preview, err := h.createHashlistFromFile(ctx, &hashlist, paths[hashTypeID], ".txt", stage)
+		if err != nil {
+			debug.Error("Failed to create split hashlist for hash type %d: %v", hashTypeID, err)
+			jsonError(w, "Failed to create hashlist record", http.StatusInternalServerError)
+			return
+		}
+		if preview != nil {
+			if response.StagingPreviews == nil {
+				response.StagingPreviews = make(map[int64]*models.HashlistStagingPreview)
+			}
+			response.StagingPreviews[hashlist.ID] = preview
+		}
+		hashlist.FilePath = ""
+		response.Hashlists = append(response.Hashlists, &hashlist)
+	}
+
+	debug.Info("Upload %s split into %d hashlists", fileName, len(response.Hashlists))
+	if stage {
+		jsonResponse(w, http.StatusCreated, response)
+		return
+	}
+	jsonResponse(w, http.StatusAccepted, response)
+}
+
+// createHashlistFromFile creates the hashlist record, moves the file into the
+// hashlist directory and submits it for background processing, or stages it
+// and returns its preview
+func (h *hashlistHandler) createHashlistFromFile(ctx context.Context, hashlist *models.HashList, srcPath, ext string, stage bool) (*models.HashlistStagingPreview, error) {
+	now := time.Now()
+	hashlist.Status = models.HashListStatusUploading
+	hashlist.CreatedAt = now
+	hashlist.UpdatedAt = now
+	if err := h.hashlistRepo.Create(ctx, hashlist); err != nil {
+		return nil, err
+	}
+
+	filename := fmt.Sprintf("%d_%s%s",
+		hashlist.ID,
+		SanitizeFilenameSimple(strings.ReplaceAll(strings.ToLower(hashlist.Name), " ", "_")),
+		ext,
+	)
+	hashlistPath := filepath.Join(h.dataDir, filename)
+	if err := os.Rename(srcPath, hashlistPath); err != nil {
+		h.updateHashlistStatus(ctx, hashlist.ID, models.HashListStatusError, "Failed to save uploaded file")
+		return nil, fmt.Errorf("failed to move hashlist file: %w", err)
+	}
+
+	preview, err := h.finishUpload(ctx, hashlist, hashlistPath, stage)
+	if err != nil {
+		os.Remove(hashlistPath)
+		return nil, err
+	}
+	return preview, nil
+}
+
+// handleGetSourceUpload returns a split upload with its hashlists and their
+// combined totals
+func (h *hashlistHandler) handleGetSourceUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := uuid.Parse(mux.Vars(r)["uploadId"])
+	if err != nil {
+		jsonError(w, "Invalid source upload ID", http.StatusBadRequest)
+		return
+	}
+
+	upload, err := h.hashlistRepo.GetSourceUpload(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			jsonError(w, "Source upload not found", http.StatusNotFound)
+		} else {
+			debug.Error("Error getting source upload %s: %v", id, err)
+			jsonError(w, "Failed to retrieve source upload", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	hashlists, _, err := h.hashlistRepo.List(ctx, repository.ListHashlistsParams{
+		SourceUploadID: &id,
+		OrderBy:        "h.hash_type_id ASC",
+	})
+	if err != nil {
+		jsonError(w, "Failed to retrieve hashlists", http.StatusInternalServerError)
+		return
+	}
+
+	upload.Hashlists = hashlists
+	for _, hashlist := range hashlists {
+		upload.TotalHashes += hashlist.TotalHashes
+		upload.CrackedHashes += hashlist.CrackedHashes
+	}
+	jsonResponse(w, http.StatusOK, upload)
+}
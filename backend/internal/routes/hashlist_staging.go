@@ -0,0 +1,165 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// stagingRequested reports whether an upload is staged for review instead of
+// processed right away. The require_hashlist_staging setting stages every upload.
+func (h *hashlistHandler) stagingRequested(r *http.Request) bool {
+	if stage, _ := strconv.ParseBool(r.FormValue("stage")); stage {
+		return true
+	}
+	setting, err := h.systemSettingsRepo.GetSetting(r.Context(), "require_hashlist_staging")
+	return err == nil && setting != nil && setting.Value != nil && *setting.Value == "true"
+}
+
+// finishUpload moves a hashlist whose file is in place to its next state. A
+// staged hashlist gets a preview and waits for a commit, any other is
+// submitted for background processing.
+func (h *hashlistHandler) finishUpload(ctx context.Context, hashlist *models.HashList, hashlistPath string, stage bool) (*models.HashlistStagingPreview, error) {
+	hashlist.FilePath = hashlistPath
+	hashlist.Status = models.HashListStatusProcessing
+	if stage {
+		hashlist.Status = models.HashListStatusStaged
+	}
+	if err := h.hashlistRepo.UpdateFilePathAndStatus(ctx, hashlist.ID, hashlistPath, hashlist.Status); err != nil {
+		return nil, err
+	}
+
+	if !stage {
+		go h.processor.SubmitHashlistForProcessing(hashlist.ID)
+		return nil, nil
+	}
+
+	preview, err := h.previewStagedHashlist(ctx, hashlist)
+	if err != nil {
+		h.updateHashlistStatus(ctx, hashlist.ID, models.HashListStatusError, "Failed to parse uploaded file")
+		return nil, err
+	}
+	debug.Info("Hashlist %d staged: %d valid and %d invalid of %d lines", hashlist.ID, preview.ValidLines, preview.InvalidLines, preview.Lines)
+	return preview, nil
+}
+
+// previewStagedHashlist parses a staged upload and stores its preview
+func (h *hashlistHandler) previewStagedHashlist(ctx context.Context, hashlist *models.HashList) (*models.HashlistStagingPreview, error) {
+	hashType, err := h.hashTypeRepo.GetByID(ctx, hashlist.HashTypeID)
+	if err != nil || hashType == nil {
+		return nil, fmt.Errorf("failed to get hash type %d: %v", hashlist.HashTypeID, err)
+	}
+
+	accept, types := h.hashTypeLookup(ctx, hashType)
+	preview, err := h.processor.PreviewHashlist(ctx, hashlist, accept)
+	if err != nil {
+		return nil, err
+	}
+	for i := range preview.DetectedTypes {
+		if hashType, ok := types[preview.DetectedTypes[i].HashTypeID]; ok {
+			preview.DetectedTypes[i].HashTypeName = hashType.Name
+		}
+	}
+
+	if err := h.hashlistRepo.SaveStagingPreview(ctx, hashlist.ID, preview); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+// handleGetHashlistStaging returns the preview of a staged hashlist
+func (h *hashlistHandler) handleGetHashlistStaging(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, err := getUserIDFromContext(ctx); err != nil {
+		jsonError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := getInt64FromPath(r, "id")
+	if err != nil {
+		jsonError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	preview, err := h.hashlistRepo.GetStagingPreview(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			jsonError(w, "Hashlist is not staged", http.StatusNotFound)
+		} else {
+			debug.Error("Error getting staging preview of hashlist %d: %v", id, err)
+			jsonError(w, "Failed to retrieve staging preview", http.StatusInternalServerError)
+		}
+		return
+	}
+	jsonResponse(w, http.StatusOK, preview)
+}
+
+// handleCommitHashlist loads the hashes of a staged hashlist
+func (h *hashlistHandler) handleCommitHashlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, err := getUserIDFromContext(ctx); err != nil {
+		jsonError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := getInt64FromPath(r, "id")
+	if err != nil {
+		jsonError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.hashlistRepo.CommitStaged(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrHashlistNotStaged) {
+			jsonError(w, "Hashlist is not staged", http.StatusConflict)
+		} else {
+			debug.Error("Error committing staged hashlist %d: %v", id, err)
+			jsonError(w, "Failed to commit hashlist", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	go h.processor.SubmitHashlistForProcessing(id)
+	debug.Info("Staged hashlist %d committed, background processing triggered", id)
+	jsonResponse(w, http.StatusAccepted, map[string]interface{}{"id": id, "status": models.HashListStatusProcessing})
+}
+
+// handleDiscardStagedHashlist deletes a staged hashlist and its upload
+func (h *hashlistHandler) handleDiscardStagedHashlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, err := getUserIDFromContext(ctx); err != nil {
+		jsonError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := getInt64FromPath(r, "id")
+	if err != nil {
+		jsonError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filePath, err := h.hashlistRepo.DeleteStaged(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrHashlistNotStaged) {
+			jsonError(w, "Hashlist is not staged", http.StatusConflict)
+		} else {
+			debug.Error("Error discarding staged hashlist %d: %v", id, err)
+			jsonError(w, "Failed to discard hashlist", http.StatusInternalServerError)
+		}
+		return
+	}
+	if filePath != "" {
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			debug.Warning("Failed to remove upload of discarded hashlist %d: %v", id, err)
+		}
+	}
+
+	debug.Info("Staged hashlist %d discarded", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/jobs"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/jobstream"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// SetupJobStreamRoutes configures the server-sent event stream of job progress
+// and starts the hub feeding it. It must run before the /jobs/{id} routes are
+// registered, which would otherwise match /jobs/stream.
+func SetupJobStreamRoutes(router *mux.Router) *jobstream.Hub {
+	hub := jobstream.NewHub()
+	go hub.Start(context.Background())
+
+	handler := jobs.NewJobStreamHandler(hub)
+	router.HandleFunc("/jobs/stream", handler.Stream).Methods(http.MethodGet, http.MethodOptions)
+	debug.Info("Configured job progress stream: /jobs/stream")
+	return hub
+}
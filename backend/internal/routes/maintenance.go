@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/maintenance"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// SetupMaintenanceRoutes configures maintenance mode: the status every user
+// polls for the banner and the admin routes to start, end and audit it
+func SetupMaintenanceRoutes(jwtRouter *mux.Router, adminRouter *mux.Router, database *db.DB) {
+	handler := maintenance.NewHandler(services.NewMaintenanceService(repository.NewMaintenanceRepository(database)))
+
+	jwtRouter.HandleFunc("/maintenance", handler.Status).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/maintenance", handler.List).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/maintenance", handler.Start).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/maintenance", handler.End).Methods(http.MethodDelete, http.MethodOptions)
+	debug.Info("Configured maintenance routes: /maintenance, /admin/maintenance")
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"encoding/json"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
@@ -17,6 +18,10 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// draining is set when the server starts shutting down, failing readiness
+// checks so load balancers stop routing to it
+var draining atomic.Bool
+
 // SetupPublicRoutes configures all public routes that don't require authentication
 func SetupPublicRoutes(apiRouter *mux.Router, database *db.DB, agentService *services.AgentService, binaryService *services.AgentBinaryService, appConfig *config.Config, tlsProvider tls.Provider) {
 	debug.Debug("Setting up public routes")
@@ -42,6 +47,17 @@ func SetupPublicRoutes(apiRouter *mux.Router, database *db.DB, agentService *ser
 	}).Methods("GET", "OPTIONS")
 	debug.Info("Configured health check endpoint: /health")
 
+	// Readiness endpoint - fails while the server drains for a restart
+	publicRouter.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		if draining.Load() {
+			http.Error(w, "Draining", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}).Methods("GET", "OPTIONS")
+	debug.Info("Configured readiness endpoint: /ready")
+
 	// Version endpoint - publicly accessible
 	publicRouter.HandleFunc("/version", handlers.GetVersion).Methods("GET", "OPTIONS")
 	debug.Info("Configured version endpoint: /version")
@@ -51,6 +67,10 @@ func SetupPublicRoutes(apiRouter *mux.Router, database *db.DB, agentService *ser
 	apiRouter.HandleFunc("/agent/register", registrationHandler.HandleRegistration).Methods("POST", "OPTIONS")
 	debug.Info("Configured agent registration endpoint: /agent/register")
 
+	// Agent bootstrap endpoint for agents pre-registered by automation
+	apiRouter.HandleFunc("/agent/bootstrap", registrationHandler.HandleBootstrap).Methods("POST", "OPTIONS")
+	debug.Info("Configured agent bootstrap endpoint: /agent/bootstrap")
+
 	// Agent configuration endpoint - publicly accessible for agents to get WebSocket config
 	apiRouter.HandleFunc("/agent/config", agenthandlers.GetConfig).Methods("GET", "OPTIONS")
 	debug.Info("Configured agent configuration endpoint: /agent/config")
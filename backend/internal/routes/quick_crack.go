@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/binary"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/jobs"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/processor"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/quickcrack"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// SetupQuickCrackRoutes configures the quick crack routes for ad hoc hashes
+// and starts delivering finished submissions to their webhooks
+func SetupQuickCrackRoutes(router *mux.Router, database *db.DB, cfg *config.Config, binaryManager binary.Manager) {
+	hashlistRepo := repository.NewHashListRepository(database)
+	hashTypeRepo := repository.NewHashTypeRepository(database)
+	hashRepo := repository.NewHashRepository(database)
+
+	hashlistDir := filepath.Join(cfg.DataDir, "hashlists")
+	if err := os.MkdirAll(hashlistDir, 0755); err != nil {
+		debug.Error("Failed to create hashlist storage directory %s: %v", hashlistDir, err)
+	}
+
+	service := quickcrack.NewQuickCrackService(
+		repository.NewQuickCrackRepository(database),
+		hashlistRepo,
+		hashTypeRepo,
+		hashRepo,
+		repository.NewClientRepository(database),
+		repository.NewJobWorkflowRepository(database.DB),
+		repository.NewJobExecutionRepository(database),
+		repository.NewSystemSettingsRepository(database),
+		newJobExecutionService(database, cfg.DataDir, binaryManager),
+		processor.NewHashlistDBProcessor(hashlistRepo, hashTypeRepo, hashRepo, repository.NewHashlistQuarantineRepository(database), repository.NewSystemSettingsRepository(database), cfg),
+		hashlistDir,
+		cfg.Airgapped,
+	)
+	go service.StartWebhookDelivery(context.Background())
+
+	handler := jobs.NewQuickCrackHandler(service)
+	router.HandleFunc("/quick-crack", handler.Submit).Methods(http.MethodPost, http.MethodOptions)
+	router.HandleFunc("/quick-crack/{id}", handler.Get).Methods(http.MethodGet, http.MethodOptions)
+	debug.Info("Configured quick crack routes: /quick-crack, /quick-crack/{id}")
+}
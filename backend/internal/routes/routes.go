@@ -13,8 +13,11 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/email"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers"
 	adminsettings "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/settings"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/auth"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/search"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/views"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/middleware"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/rule"
@@ -69,7 +72,7 @@ func CORSMiddleware(next http.Handler) http.Handler {
 
 		// Set standard CORS headers
 		w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, X-Agent-ID, Origin, Cookie, Cache-Control")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, X-Agent-ID, Origin, Cookie, Cache-Control, X-Support-Session")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 		w.Header().Set("Access-Control-Max-Age", "3600")
 		w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type, Authorization")
@@ -222,18 +225,46 @@ func SetupRoutes(r *mux.Router, sqlDB *sql.DB, tlsProvider tls.Provider, agentSe
 	SetupHashlistRoutes(jwtRouter)
 	// Note: Skipping SetupJobRoutes(jwtRouter) as it conflicts with SetupUserRoutes - the real job routes are in SetupUserRoutes
 	SetupAgentRoutes(jwtRouter, agentService, database)
+
+	// Agent pre-registration for provisioning automation
+	preregistrationHandler := handlers.NewRegistrationHandler(agentService, appConfig, tlsProvider)
+	jwtRouter.HandleFunc("/agents/preregister", preregistrationHandler.HandlePreregistration).Methods("POST", "OPTIONS")
+	jwtRouter.HandleFunc("/agents/{id}/bootstrap-token", preregistrationHandler.RevokeBootstrapToken).Methods("DELETE", "OPTIONS")
 	SetupVoucherRoutes(jwtRouter, services.NewClaimVoucherService(repository.NewClaimVoucherRepository(database)))
 	SetupPotRoutes(jwtRouter, hashRepo, hashlistRepo, clientRepo, jobExecutionRepo)
+	jwtRouter.HandleFunc("/search", search.NewHandler(repository.NewSearchRepository(database)).Search).Methods(http.MethodGet, http.MethodOptions)
+
+	// Saved views of the job, task and hash lists
+	viewHandler := views.NewHandler(repository.NewSavedViewRepository(database))
+	jwtRouter.HandleFunc("/views", viewHandler.ListViews).Methods(http.MethodGet, http.MethodOptions)
+	jwtRouter.HandleFunc("/views", viewHandler.CreateView).Methods(http.MethodPost, http.MethodOptions)
+	jwtRouter.HandleFunc("/views/{id}", viewHandler.GetView).Methods(http.MethodGet, http.MethodOptions)
+	jwtRouter.HandleFunc("/views/{id}", viewHandler.UpdateView).Methods(http.MethodPut, http.MethodOptions)
+	jwtRouter.HandleFunc("/views/{id}", viewHandler.DeleteView).Methods(http.MethodDelete, http.MethodOptions)
 
 	// Add user accessible routes for settings (read-only)
 	jwtRouter.HandleFunc("/settings/max-priority", userSystemSettingsHandler.GetMaxPriorityForUsers).Methods(http.MethodGet, http.MethodOptions)
 	jwtRouter.HandleFunc("/settings/retention", userRetentionSettingsHandler.GetDefaultRetention).Methods(http.MethodGet, http.MethodOptions)
 
-	SetupAdminRoutes(jwtRouter, database, emailService, adminJobsHandler, binaryManager) // Pass adminJobsHandler and binaryManager
+	adminRouter := SetupAdminRoutes(jwtRouter, database, emailService, adminJobsHandler, binaryManager) // Pass adminJobsHandler and binaryManager
+	SetupBundleRoutes(adminRouter, database, appConfig, wordlistManager, ruleManager, binaryManager)
+	SetupAgentBulkRoutes(adminRouter, database)
+	SetupCrashReportRoutes(adminRouter, agentService)
+	SetupTelemetryRoutes(apiRouter, adminRouter, database, appConfig)
+	SetupCloudBurstRoutes(adminRouter, database, appConfig)
+	SetupClusterHealthRoutes(apiRouter, adminRouter, database, appConfig)
+	SetupMaintenanceRoutes(jwtRouter, adminRouter, database)
+	SetupChunkVerificationRoutes(adminRouter, database)
+	progressHub := SetupJobStreamRoutes(jwtRouter)
 	SetupUserRoutes(jwtRouter, database, appConfig.DataDir, binaryManager, agentService)
+	SetupQuickCrackRoutes(jwtRouter, database, appConfig, binaryManager)
+	SetupExportRoutes(apiRouter, jwtRouter, database, appConfig)
 	SetupMFARoutes(jwtRouter, mfaHandler, database, emailService)
 	// Use the enhanced WebSocket setup with job integration
 	SetupWebSocketWithJobRoutes(r, agentService, tlsProvider, sqlDB, appConfig, wordlistManager, ruleManager, binaryManager, potfileService)
+	if JobIntegrationManager != nil {
+		JobIntegrationManager.SetProgressHub(progressHub)
+	}
 	SetupBinaryRoutes(jwtRouter, sqlDB, appConfig, agentService)
 
 	// Setup wordlist and rule routes
@@ -295,6 +326,13 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Flush implements the http.Flusher interface to support server-sent events
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 // Hijack implements the http.Hijacker interface to support WebSocket connections
 func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	if hijacker, ok := rw.ResponseWriter.(http.Hijacker); ok {
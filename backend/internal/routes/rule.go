@@ -43,6 +43,9 @@ func SetupRuleRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, agentServ
 
 	// Create handler
 	handler := rulehandler.NewHandler(manager, cfg)
+	if WSHandler != nil {
+		handler.SetAgentFileRemover(WSHandler)
+	}
 
 	// User routes (accessible to all authenticated users)
 	userRouter := r.PathPrefix("/rules").Subrouter()
@@ -52,6 +55,7 @@ func SetupRuleRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, agentServ
 	userRouter.HandleFunc("", handler.HandleListRules).Methods(http.MethodGet)
 	userRouter.HandleFunc("/{id:[0-9]+}", handler.HandleGetRule).Methods(http.MethodGet)
 	userRouter.HandleFunc("/{id:[0-9]+}/download", handler.HandleDownloadRule).Methods(http.MethodGet)
+	userRouter.HandleFunc("/{id:[0-9]+}/references", handler.HandleGetRuleReferences).Methods(http.MethodGet)
 
 	// Add upload endpoint with special handling
 	uploadHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -142,6 +146,12 @@ func SetupRuleRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, agentServ
 	userRouter.HandleFunc("/{id:[0-9]+}/tags", handler.HandleAddRuleTag).Methods(http.MethodPost)
 	userRouter.HandleFunc("/{id:[0-9]+}/tags/{tag}", handler.HandleDeleteRuleTag).Methods(http.MethodDelete)
 
+	// Directory hierarchy management
+	userRouter.HandleFunc("/directories", handler.HandleListDirectories).Methods(http.MethodGet)
+	userRouter.HandleFunc("/directories", handler.HandleCreateDirectory).Methods(http.MethodPost)
+	userRouter.HandleFunc("/directories", handler.HandleMoveDirectory).Methods(http.MethodPut)
+	userRouter.HandleFunc("/{id:[0-9]+}/move", handler.HandleMoveRule).Methods(http.MethodPost)
+
 	// Add simplified handler for verify operations
 	verifyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		debug.Info("Handling rule verify request: %s %s", r.Method, r.URL.Path)
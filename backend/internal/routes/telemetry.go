@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	admintelemetry "github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/admin/telemetry"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	telemetrysvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/telemetry"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/gorilla/mux"
+)
+
+// SetupTelemetryRoutes configures the anonymized statistics routes: the
+// collector endpoint other instances publish to, which authenticates with its
+// own token, and the admin routes for previewing, publishing and comparing
+func SetupTelemetryRoutes(apiRouter *mux.Router, adminRouter *mux.Router, database *db.DB, cfg *config.Config) {
+	service := telemetrysvc.NewTelemetryService(
+		repository.NewTelemetryRepository(database),
+		repository.NewSystemSettingsRepository(database),
+		cfg.Airgapped,
+	)
+	handler := admintelemetry.NewHandler(service)
+
+	apiRouter.HandleFunc("/telemetry/reports", handler.ReceiveReport).Methods(http.MethodPost, http.MethodOptions)
+
+	adminRouter.HandleFunc("/telemetry/preview", handler.Preview).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/telemetry/publish", handler.Publish).Methods(http.MethodPost, http.MethodOptions)
+	adminRouter.HandleFunc("/telemetry/instances", handler.ListInstances).Methods(http.MethodGet, http.MethodOptions)
+	adminRouter.HandleFunc("/telemetry/instances/{id:[0-9a-fA-F-]+}/reports", handler.ListInstanceReports).Methods(http.MethodGet, http.MethodOptions)
+	debug.Info("Configured telemetry routes: /telemetry/reports, /admin/telemetry/*")
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	clientsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/client"
+	retentionsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/retention"
+	trashsvc "github.com/ZerkerEOD/krakenhashes/backend/internal/services/trash"
+)
+
+// newTrashService wires up the trash service and the retention and client
+// services it uses to purge items permanently
+func newTrashService(database *db.DB) *trashsvc.TrashService {
+	hashlistRepo := repository.NewHashListRepository(database)
+	clientRepo := repository.NewClientRepository(database)
+	clientSettingsRepo := repository.NewClientSettingsRepository(database)
+	retentionService := retentionsvc.NewRetentionService(database, hashlistRepo, repository.NewHashRepository(database), clientRepo, clientSettingsRepo, repository.NewAnalyticsRepository(database))
+	clientService := clientsvc.NewClientService(clientRepo, hashlistRepo, clientSettingsRepo, retentionService)
+	return trashsvc.NewTrashService(repository.NewTrashRepository(database), hashlistRepo, repository.NewJobExecutionRepository(database), clientRepo, retentionService, clientService)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/agent"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/jobs"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/user"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/handlers/views"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/rule"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
@@ -34,47 +36,18 @@ func CreateJobsHandler(database *db.DB, dataDir string, binaryManager binary.Man
 	jobTaskRepo := repository.NewJobTaskRepository(dbWrapper)
 	hashlistRepo := repository.NewHashListRepository(dbWrapper)
 	presetJobRepo := repository.NewPresetJobRepository(database.DB)
-	benchmarkRepo := repository.NewBenchmarkRepository(dbWrapper)
-	agentHashlistRepo := repository.NewAgentHashlistRepository(dbWrapper)
-	agentRepo := repository.NewAgentRepository(dbWrapper)
 	systemSettingsRepo := repository.NewSystemSettingsRepository(dbWrapper)
-	fileRepo := repository.NewFileRepository(dbWrapper, dataDir)
 	hashTypeRepo := repository.NewHashTypeRepository(dbWrapper)
 
 	// Create client repository
 	clientRepo := repository.NewClientRepository(dbWrapper)
 
-	// Create device repository
-	deviceRepo := repository.NewAgentDeviceRepository(dbWrapper)
-
-	// Create schedule repository
-	scheduleRepo := repository.NewAgentScheduleRepository(dbWrapper)
-
 	// Create additional repositories for job creation
 	workflowRepo := repository.NewJobWorkflowRepository(database.DB)
 	wordlistStore := wordlist.NewStore(database.DB)
 	ruleStore := rule.NewStore(database.DB)
 	binaryStore := binary.NewStore(database.DB)
 
-	// Create job execution service
-	jobExecutionService := services.NewJobExecutionService(
-		database,
-		jobExecRepo,
-		jobTaskRepo,
-		benchmarkRepo,
-		agentHashlistRepo,
-		agentRepo,
-		deviceRepo,
-		presetJobRepo,
-		hashlistRepo,
-		systemSettingsRepo,
-		fileRepo,
-		scheduleRepo,
-		binaryManager,
-		"", // hashcatBinaryPath - not needed for keyspace calculation
-		dataDir,
-	)
-
 	// Create jobs handler
 	return jobs.NewUserJobsHandler(
 		jobExecRepo,
@@ -87,11 +60,57 @@ func CreateJobsHandler(database *db.DB, dataDir string, binaryManager binary.Man
 		wordlistStore,
 		ruleStore,
 		binaryStore,
-		jobExecutionService,
+		newJobExecutionService(database, dataDir, binaryManager),
 		systemSettingsRepo,
+		repository.NewGPUUsageRepository(dbWrapper),
+		repository.NewTaskArtifactRepository(dbWrapper),
+		repository.NewJobCampaignRepository(dbWrapper),
+		newTrashService(dbWrapper),
 	)
 }
 
+// newJobExecutionService creates the job execution service used to create
+// jobs from user requests
+func newJobExecutionService(database *db.DB, dataDir string, binaryManager binary.Manager) *services.JobExecutionService {
+	dbWrapper := &db.DB{DB: database.DB}
+	return services.NewJobExecutionService(
+		database,
+		repository.NewJobExecutionRepository(dbWrapper),
+		repository.NewJobTaskRepository(dbWrapper),
+		repository.NewBenchmarkRepository(dbWrapper),
+		repository.NewAgentHashlistRepository(dbWrapper),
+		repository.NewAgentRepository(dbWrapper),
+		repository.NewAgentDeviceRepository(dbWrapper),
+		repository.NewPresetJobRepository(database.DB),
+		repository.NewHashListRepository(dbWrapper),
+		repository.NewSystemSettingsRepository(dbWrapper),
+		repository.NewFileRepository(dbWrapper, dataDir),
+		repository.NewAgentScheduleRepository(dbWrapper),
+		binaryManager,
+		"", // hashcatBinaryPath - not needed for keyspace calculation
+		dataDir,
+	)
+}
+
+// newJobSimulationHandler creates the capacity planning simulation handler
+func newJobSimulationHandler(database *db.DB) *jobs.SimulationHandler {
+	dbWrapper := &db.DB{DB: database.DB}
+	systemSettingsRepo := repository.NewSystemSettingsRepository(dbWrapper)
+	chunkingService := services.NewJobChunkingService(
+		repository.NewBenchmarkRepository(dbWrapper),
+		repository.NewJobTaskRepository(dbWrapper),
+		systemSettingsRepo,
+	)
+
+	return jobs.NewSimulationHandler(services.NewJobSimulationService(
+		chunkingService,
+		repository.NewAgentRepository(dbWrapper),
+		systemSettingsRepo,
+		wordlist.NewStore(database.DB),
+		rule.NewStore(database.DB),
+	))
+}
+
 // SetupUserRoutes configures all user-related routes
 func SetupUserRoutes(router *mux.Router, database *db.DB, dataDir string, binaryManager binary.Manager, agentService *services.AgentService) {
 	debug.Info("Setting up user routes")
@@ -104,6 +123,10 @@ func SetupUserRoutes(router *mux.Router, database *db.DB, dataDir string, binary
 	dbWrapper := &db.DB{DB: database.DB}
 	userHandler := user.NewHandler(dbWrapper)
 	agentHandler := agent.NewAgentHandler(agentService)
+	simulationHandler := newJobSimulationHandler(database)
+	throughputHandler := jobs.NewThroughputHandler(repository.NewJobThroughputRepository(dbWrapper))
+	maskHandler := jobs.NewMaskHandler(services.NewMaskService(repository.NewCustomCharsetRepository(dbWrapper)))
+	viewHandler := views.NewHandler(repository.NewSavedViewRepository(dbWrapper))
 
 	// SSE removed - using polling instead
 	// The frontend now polls /jobs endpoint every 5 seconds for updates
@@ -121,13 +144,30 @@ func SetupUserRoutes(router *mux.Router, database *db.DB, dataDir string, binary
 
 	// Other specific job routes (before generic {id} pattern)
 	router.HandleFunc("/jobs/finished", jobsHandler.DeleteFinishedJobs).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/jobs/simulate", simulationHandler.Simulate).Methods("POST", "OPTIONS")
+	router.HandleFunc("/jobs/throughput", throughputHandler.GetThroughput).Methods("GET", "OPTIONS")
+
+	// Mask builder: validation with keyspace preview and the charset library
+	router.HandleFunc("/masks/validate", maskHandler.ValidateMask).Methods("POST", "OPTIONS")
+	router.HandleFunc("/charsets", maskHandler.ListCharsets).Methods("GET", "OPTIONS")
+
+	// Tasks across all jobs
+	router.HandleFunc("/tasks", viewHandler.Apply(models.SavedViewResourceTasks, jobsHandler.ListTasks)).Methods("GET", "OPTIONS")
 
 	// Generic job routes (MUST come after specific routes)
-	router.HandleFunc("/jobs", jobsHandler.ListJobs).Methods("GET", "OPTIONS")
+	router.HandleFunc("/jobs", viewHandler.Apply(models.SavedViewResourceJobs, jobsHandler.ListJobs)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/jobs/campaigns/{id}", jobsHandler.GetCampaign).Methods("GET", "OPTIONS")
 	router.HandleFunc("/jobs/{id}", jobsHandler.GetJobDetail).Methods("GET", "OPTIONS")
 	router.HandleFunc("/jobs/{id}", jobsHandler.UpdateJob).Methods("PATCH", "OPTIONS")
 	router.HandleFunc("/jobs/{id}/retry", jobsHandler.RetryJob).Methods("POST", "OPTIONS")
+	router.HandleFunc("/jobs/{id}/cost", jobsHandler.GetJobCost).Methods("GET", "OPTIONS")
+	router.HandleFunc("/jobs/{id}/scheduling", jobsHandler.GetJobScheduling).Methods("GET", "OPTIONS")
+	router.HandleFunc("/jobs/{id}/annotations", jobsHandler.UpdateJobAnnotations).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/jobs/{id}/name", jobsHandler.RenameJob).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/jobs/{id}/tasks", viewHandler.Apply(models.SavedViewResourceTasks, jobsHandler.ListJobTasks)).Methods("GET", "OPTIONS")
 	router.HandleFunc("/jobs/{id}/tasks/{taskId}/retry", jobsHandler.RetryTask).Methods("POST", "OPTIONS")
+	router.HandleFunc("/jobs/{id}/tasks/{taskId}/artifacts", jobsHandler.ListTaskArtifacts).Methods("GET", "OPTIONS")
+	router.HandleFunc("/jobs/{id}/tasks/{taskId}/artifacts/{artifactId}/{file}", jobsHandler.DownloadTaskArtifact).Methods("GET", "OPTIONS")
 	router.HandleFunc("/jobs/{id}", jobsHandler.DeleteJob).Methods("DELETE", "OPTIONS")
 
 	// Get user profile
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/binary"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/config"
@@ -31,6 +32,17 @@ var JobIntegrationManager *integration.JobIntegrationManager
 // This is a temporary solution until we refactor to use proper dependency injection
 var WSHandler *wshandler.Handler
 
+// DrainAgents marks the server not ready and hands its agents over to the next
+// start: they are told to reconnect after reconnectAfter and disconnected,
+// while their tasks keep running
+func DrainAgents(reconnectAfter time.Duration) {
+	draining.Store(true)
+	if WSHandler == nil {
+		return
+	}
+	WSHandler.Drain(reconnectAfter, 5*time.Second)
+}
+
 // wsHandlerAdapter adapts the WebSocket handler to the WSHandler interface
 type wsHandlerAdapter struct {
 	handler *wshandler.Handler
@@ -129,6 +141,11 @@ func SetupWebSocketWithJobRoutes(
 		systemSettingsRepo,
 	)
 
+	// Let users see what the scheduler decided about their jobs
+	if UserJobsHandlerInstance != nil {
+		UserJobsHandlerInstance.SetSchedulingSnapshots(jobSchedulingService.Snapshots())
+	}
+
 	// Create WebSocket service
 	wsService := wsservice.NewService(agentService)
 
@@ -152,6 +169,9 @@ func SetupWebSocketWithJobRoutes(
 	// Create WebSocket handler
 	wsHandler := wshandler.NewHandler(wsService, agentService, systemSettingsRepo, jobTaskRepo, jobExecutionRepo, agentTLSConfig)
 
+	// Credential rotations send agents a new client certificate with their key
+	wsHandler.SetCertificateIssuer(tlsProvider)
+
 	// Store WebSocket handler globally for access by other handlers
 	WSHandler = wsHandler
 
@@ -163,16 +183,12 @@ func SetupWebSocketWithJobRoutes(
 		UserJobsHandlerInstance.SetWSHandler(adapter)
 	}
 
-	// Create notification service for job completion
-	notificationService := services.NewNotificationService(sqlDB)
-
 	// Create hashlist completion service for handling fully cracked hashlists
 	hashlistCompletionService := services.NewHashlistCompletionService(
 		database,
 		jobExecutionRepo,
 		jobTaskRepo,
 		hashlistRepo,
-		notificationService,
 		&wsHandlerAdapter{handler: wsHandler},
 	)
 
@@ -219,6 +235,15 @@ func SetupWebSocketWithJobRoutes(
 	go metricsCleanupService.StartCleanupScheduler(context.Background())
 	debug.Info("Metrics cleanup service started")
 
+	// Initialize and start task artifact cleanup service
+	taskArtifactCleanupService := services.NewTaskArtifactCleanupService(repository.NewTaskArtifactRepository(database), systemSettingsRepo)
+	go taskArtifactCleanupService.StartCleanupScheduler(context.Background())
+	debug.Info("Task artifact cleanup service started")
+
+	// Rotate agent credentials once they reach agent_credential_rotation_days
+	go wsHandler.StartCredentialRotationScheduler(context.Background())
+	debug.Info("Agent credential rotation scheduler started")
+
 	if tlsConfig != nil {
 		debug.Debug("WebSocket TLS Configuration:")
 		debug.Debug("- Min Version: %v", agentTLSConfig.MinVersion)
@@ -43,6 +43,9 @@ func SetupWordlistRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, agent
 
 	// Create handler
 	handler := wordlisthandler.NewHandler(manager, potfileService)
+	if WSHandler != nil {
+		handler.SetAgentFileRemover(WSHandler)
+	}
 
 	// User routes (accessible to all authenticated users)
 	userRouter := r.PathPrefix("/wordlists").Subrouter()
@@ -52,6 +55,8 @@ func SetupWordlistRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, agent
 	userRouter.HandleFunc("", handler.HandleListWordlists).Methods(http.MethodGet)
 	userRouter.HandleFunc("/{id:[0-9]+}", handler.HandleGetWordlist).Methods(http.MethodGet)
 	userRouter.HandleFunc("/{id:[0-9]+}/download", handler.HandleDownloadWordlist).Methods(http.MethodGet)
+	userRouter.HandleFunc("/{id:[0-9]+}/preview", handler.HandlePreviewWordlist).Methods(http.MethodGet)
+	userRouter.HandleFunc("/{id:[0-9]+}/references", handler.HandleGetWordlistReferences).Methods(http.MethodGet)
 
 	// Add upload endpoint with special handling
 	uploadHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -162,6 +167,12 @@ func SetupWordlistRoutes(r *mux.Router, sqlDB *sql.DB, cfg *config.Config, agent
 	userRouter.HandleFunc("/{id:[0-9]+}/tags", handler.HandleAddWordlistTag).Methods(http.MethodPost)
 	userRouter.HandleFunc("/{id:[0-9]+}/tags/{tag}", handler.HandleDeleteWordlistTag).Methods(http.MethodDelete)
 
+	// Directory hierarchy management
+	userRouter.HandleFunc("/directories", handler.HandleListDirectories).Methods(http.MethodGet)
+	userRouter.HandleFunc("/directories", handler.HandleCreateDirectory).Methods(http.MethodPost)
+	userRouter.HandleFunc("/directories", handler.HandleMoveDirectory).Methods(http.MethodPut)
+	userRouter.HandleFunc("/{id:[0-9]+}/move", handler.HandleMoveWordlist).Methods(http.MethodPost)
+
 	// Agent routes (accessible to agents with API key)
 	agentRouter := r.PathPrefix("/agent/wordlists").Subrouter()
 	agentRouter.Use(api.APIKeyMiddleware(agentService))
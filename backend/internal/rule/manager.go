@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
@@ -24,14 +26,24 @@ type Manager interface {
 	GetRule(ctx context.Context, id int) (*models.Rule, error)
 	GetRuleByFilename(ctx context.Context, filename string) (*models.Rule, error)
 	GetRuleByMD5Hash(ctx context.Context, md5Hash string) (*models.Rule, error)
+	GetRuleByCanonicalHash(ctx context.Context, canonicalHash string) (*models.Rule, error)
 	GetRuleByName(ctx context.Context, name string) (*models.Rule, error)
 	AddRule(ctx context.Context, req *models.RuleAddRequest, userID uuid.UUID) (*models.Rule, error)
 	UpdateRule(ctx context.Context, id int, req *models.RuleUpdateRequest, userID uuid.UUID) (*models.Rule, error)
-	DeleteRule(ctx context.Context, id int) error
+	DeleteRule(ctx context.Context, id int, force bool) (*models.Rule, error)
+	GetRuleReferences(ctx context.Context, id int) (*models.FileReferences, error)
 	VerifyRule(ctx context.Context, id int, req *models.RuleVerifyRequest) error
 	UpdateRuleFileInfo(ctx context.Context, id int, md5Hash string, fileSize int64) error
 	AddRuleTag(ctx context.Context, id int, tag string, userID uuid.UUID) error
 	DeleteRuleTag(ctx context.Context, id int, tag string) error
+	AddRuleAlias(ctx context.Context, id int, alias string, userID uuid.UUID) error
+	UpdateRulePath(ctx context.Context, id int, fileName string) error
+	UpdateRuleCanonicalHash(ctx context.Context, id int, canonicalHash string) error
+	UpdateRuleFileModTime(ctx context.Context, id int, modTime time.Time) error
+	MoveRule(ctx context.Context, id int, dir string) (*models.Rule, error)
+	ListDirectories(ctx context.Context) ([]models.ResourceDirectory, error)
+	CreateDirectory(ctx context.Context, dir string) (string, error)
+	MoveDirectory(ctx context.Context, from, to string) (int, error)
 	GetRulePath(filename string, ruleType string) string
 	CountRulesInFile(filepath string) (int64, error)
 	CalculateFileMD5(filepath string) (string, error)
@@ -44,17 +56,25 @@ type RuleStore interface {
 	GetRule(ctx context.Context, id int) (*models.Rule, error)
 	GetRuleByFilename(ctx context.Context, filename string) (*models.Rule, error)
 	GetRuleByMD5Hash(ctx context.Context, md5Hash string) (*models.Rule, error)
+	GetRuleByCanonicalHash(ctx context.Context, canonicalHash string) (*models.Rule, error)
 	GetRuleByName(ctx context.Context, name string) (*models.Rule, error)
 	CreateRule(ctx context.Context, rule *models.Rule) error
 	UpdateRule(ctx context.Context, rule *models.Rule) error
 	DeleteRule(ctx context.Context, id int) error
 	UpdateRuleVerification(ctx context.Context, id int, status string, ruleCount *int64) error
 	UpdateRuleFileInfo(ctx context.Context, id int, md5Hash string, fileSize int64) error
+	UpdateRulePath(ctx context.Context, id int, fileName string) error
+	UpdateRuleCanonicalHash(ctx context.Context, id int, canonicalHash string) error
+	UpdateRuleFileModTime(ctx context.Context, id int, modTime time.Time) error
 
 	// Tag operations
 	GetRuleTags(ctx context.Context, id int) ([]string, error)
 	AddRuleTag(ctx context.Context, id int, tag string, userID uuid.UUID) error
 	DeleteRuleTag(ctx context.Context, id int, tag string) error
+
+	// Alias operations
+	GetRuleAliases(ctx context.Context, id int) ([]string, error)
+	AddRuleAlias(ctx context.Context, id int, alias string, userID uuid.UUID) error
 }
 
 type manager struct {
@@ -142,6 +162,11 @@ func (m *manager) GetRuleByName(ctx context.Context, name string) (*models.Rule,
 	return m.store.GetRuleByName(ctx, name)
 }
 
+// GetRuleByCanonicalHash retrieves a rule by canonical hash
+func (m *manager) GetRuleByCanonicalHash(ctx context.Context, canonicalHash string) (*models.Rule, error) {
+	return m.store.GetRuleByCanonicalHash(ctx, canonicalHash)
+}
+
 // AddRule adds a new rule
 func (m *manager) AddRule(ctx context.Context, req *models.RuleAddRequest, userID uuid.UUID) (*models.Rule, error) {
 	// Create rule model
@@ -236,30 +261,44 @@ func (m *manager) UpdateRule(ctx context.Context, id int, req *models.RuleUpdate
 }
 
 // DeleteRule deletes a rule
-func (m *manager) DeleteRule(ctx context.Context, id int) error {
-	// Check if rule is being used by active jobs
+func (m *manager) DeleteRule(ctx context.Context, id int, force bool) (*models.Rule, error) {
+	// A rule used by active jobs is never deleted, one used by preset jobs
+	// only when forced, which removes it from those presets
 	if m.jobExecRepo != nil {
-		hasActiveJobs, err := m.jobExecRepo.HasActiveJobsUsingRule(ctx, strconv.Itoa(id))
+		refs, err := m.jobExecRepo.GetRuleReferences(ctx, strconv.Itoa(id))
 		if err != nil {
-			return fmt.Errorf("failed to check for active jobs: %w", err)
+			return nil, fmt.Errorf("failed to check rule references: %w", err)
 		}
-		if hasActiveJobs {
-			return models.ErrResourceInUse
+		if len(refs.Jobs) > 0 {
+			return nil, models.ErrResourceInUse
+		}
+		if len(refs.PresetJobs) > 0 && !force {
+			return nil, models.ErrResourceReferenced
 		}
 	}
 
 	// Get rule to find filename
 	rule, err := m.store.GetRule(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if rule == nil {
-		return fmt.Errorf("rule not found")
+		return nil, fmt.Errorf("rule not found")
+	}
+
+	if force && m.jobExecRepo != nil {
+		presets, err := m.jobExecRepo.DetachRuleFromPresets(ctx, strconv.Itoa(id))
+		if err != nil {
+			return nil, err
+		}
+		for _, preset := range presets {
+			debug.Warning("Removed deleted rule %d from preset job %s (%s), flagged for review", id, preset.Name, preset.ID)
+		}
 	}
 
 	// Delete from database
 	if err := m.store.DeleteRule(ctx, id); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Delete file
@@ -269,7 +308,15 @@ func (m *manager) DeleteRule(ctx context.Context, id int) error {
 		// Don't return error, as the database entry is already deleted
 	}
 
-	return nil
+	return rule, nil
+}
+
+// GetRuleReferences lists the preset jobs and active jobs using a rule
+func (m *manager) GetRuleReferences(ctx context.Context, id int) (*models.FileReferences, error) {
+	if m.jobExecRepo == nil {
+		return &models.FileReferences{PresetJobs: []models.FileReference{}, Jobs: []models.FileReference{}}, nil
+	}
+	return m.jobExecRepo.GetRuleReferences(ctx, strconv.Itoa(id))
 }
 
 // VerifyRule updates a rule's verification status
@@ -313,6 +360,202 @@ func (m *manager) DeleteRuleTag(ctx context.Context, id int, tag string) error {
 	return m.store.DeleteRuleTag(ctx, id, tag)
 }
 
+// UpdateRulePath records a new file name for a rule whose file was moved
+// outside of the API, it does not touch the file itself
+func (m *manager) UpdateRulePath(ctx context.Context, id int, fileName string) error {
+	return m.store.UpdateRulePath(ctx, id, filepath.ToSlash(fileName))
+}
+
+// UpdateRuleCanonicalHash records the canonical hash of a rule's file
+func (m *manager) UpdateRuleCanonicalHash(ctx context.Context, id int, canonicalHash string) error {
+	return m.store.UpdateRuleCanonicalHash(ctx, id, canonicalHash)
+}
+
+// AddRuleAlias records the name of a duplicate upload aliased to a rule
+func (m *manager) AddRuleAlias(ctx context.Context, id int, alias string, userID uuid.UUID) error {
+	return m.store.AddRuleAlias(ctx, id, alias, userID)
+}
+
+// UpdateRuleFileModTime records the modification time of the rule file as it
+// was when it was last hashed, so unchanged files are not hashed again
+func (m *manager) UpdateRuleFileModTime(ctx context.Context, id int, modTime time.Time) error {
+	return m.store.UpdateRuleFileModTime(ctx, id, modTime)
+}
+
+// checkMovable returns models.ErrResourceInUse if the rule is used by active
+// jobs and must not be moved
+func (m *manager) checkMovable(ctx context.Context, rule *models.Rule) error {
+	if m.jobExecRepo != nil {
+		hasActiveJobs, err := m.jobExecRepo.HasActiveJobsUsingRule(ctx, strconv.Itoa(rule.ID))
+		if err != nil {
+			return fmt.Errorf("failed to check for active jobs: %w", err)
+		}
+		if hasActiveJobs {
+			return models.ErrResourceInUse
+		}
+	}
+	return nil
+}
+
+// MoveRule moves a rule file into dir, relative to the rules directory, and
+// records the new file name. The file is renamed rather than copied, agents
+// that already have it move their copy as well.
+func (m *manager) MoveRule(ctx context.Context, id int, dir string) (*models.Rule, error) {
+	dir, err := fsutil.CleanRelativeDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	// Files directly in the root would be resolved into a type directory
+	if dir == "" {
+		return nil, fmt.Errorf("%w: a directory is required", models.ErrInvalidInput)
+	}
+
+	rule, err := m.store.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, models.ErrNotFound
+	}
+	if err := m.checkMovable(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	oldName := filepath.ToSlash(rule.FileName)
+	newName := path.Join(dir, path.Base(oldName))
+	if newName == oldName {
+		return rule, nil
+	}
+
+	newPath := filepath.Join(m.rulesDir, filepath.FromSlash(newName))
+	if fsutil.FileExists(newPath) {
+		return nil, models.ErrAlreadyExists
+	}
+	oldPath := filepath.Join(m.rulesDir, filepath.FromSlash(oldName))
+	if err := fsutil.MovePath(oldPath, newPath); err != nil {
+		return nil, fmt.Errorf("failed to move rule file: %w", err)
+	}
+
+	if err := m.store.UpdateRulePath(ctx, id, newName); err != nil {
+		if rbErr := os.Rename(newPath, oldPath); rbErr != nil {
+			debug.Error("Failed to move rule file %s back to %s: %v", newPath, oldPath, rbErr)
+		}
+		return nil, err
+	}
+
+	debug.Info("Moved rule %d from %s to %s", id, oldName, newName)
+	rule.FileName = newName
+	return rule, nil
+}
+
+// ListDirectories returns the directories below the rules directory with the
+// number of rules directly inside each
+func (m *manager) ListDirectories(ctx context.Context) ([]models.ResourceDirectory, error) {
+	dirs, err := fsutil.ListDirectories(m.rulesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := m.store.ListRules(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, rule := range rules {
+		counts[path.Dir(filepath.ToSlash(rule.FileName))]++
+	}
+
+	result := make([]models.ResourceDirectory, 0, len(dirs))
+	for _, dir := range dirs {
+		result = append(result, models.ResourceDirectory{Path: dir, FileCount: counts[dir]})
+	}
+	return result, nil
+}
+
+// CreateDirectory creates dir, relative to the rules directory, with any
+// missing parents and returns its cleaned path
+func (m *manager) CreateDirectory(ctx context.Context, dir string) (string, error) {
+	dir, err := fsutil.CleanRelativeDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	if dir == "" {
+		return "", fmt.Errorf("%w: a directory is required", models.ErrInvalidInput)
+	}
+
+	if err := os.MkdirAll(filepath.Join(m.rulesDir, filepath.FromSlash(dir)), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	return dir, nil
+}
+
+// MoveDirectory renames or moves a directory below the rules directory together
+// with every rule inside it and returns the number of rules moved. The directory is renamed on disk first, if recording the new file
+// names fails it is renamed back.
+func (m *manager) MoveDirectory(ctx context.Context, from, to string) (int, error) {
+	from, err := fsutil.CleanRelativeDir(from)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	to, err = fsutil.CleanRelativeDir(to)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	if from == "" || to == "" {
+		return 0, fmt.Errorf("%w: the rules directory itself cannot be moved", models.ErrInvalidInput)
+	}
+	if to == from || strings.HasPrefix(to, from+"/") {
+		return 0, fmt.Errorf("%w: cannot move a directory into itself", models.ErrInvalidInput)
+	}
+
+	fromPath := filepath.Join(m.rulesDir, filepath.FromSlash(from))
+	toPath := filepath.Join(m.rulesDir, filepath.FromSlash(to))
+	if !fsutil.DirectoryExists(fromPath) {
+		return 0, models.ErrNotFound
+	}
+	if fsutil.DirectoryExists(toPath) || fsutil.FileExists(toPath) {
+		return 0, models.ErrAlreadyExists
+	}
+
+	all, err := m.store.ListRules(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	var affected []*models.Rule
+	for _, rule := range all {
+		if strings.HasPrefix(filepath.ToSlash(rule.FileName), from+"/") {
+			if err := m.checkMovable(ctx, rule); err != nil {
+				return 0, err
+			}
+			affected = append(affected, rule)
+		}
+	}
+
+	if err := fsutil.MovePath(fromPath, toPath); err != nil {
+		return 0, fmt.Errorf("failed to move directory: %w", err)
+	}
+
+	for i, rule := range affected {
+		oldName := filepath.ToSlash(rule.FileName)
+		newName := to + strings.TrimPrefix(oldName, from)
+		if err := m.store.UpdateRulePath(ctx, rule.ID, newName); err != nil {
+			// Put the database and the directory back as they were
+			for _, done := range affected[:i] {
+				if rbErr := m.store.UpdateRulePath(ctx, done.ID, filepath.ToSlash(done.FileName)); rbErr != nil {
+					debug.Error("Failed to restore path of rule %d: %v", done.ID, rbErr)
+				}
+			}
+			if rbErr := os.Rename(toPath, fromPath); rbErr != nil {
+				debug.Error("Failed to move directory %s back to %s: %v", toPath, fromPath, rbErr)
+			}
+			return 0, err
+		}
+	}
+
+	debug.Info("Moved rule directory %s to %s with %d rules", from, to, len(affected))
+	return len(affected), nil
+}
+
 // GetRulePath returns the full path to a rule file
 func (m *manager) GetRulePath(filename string, ruleType string) string {
 	// Check if the filename already contains a subdirectory
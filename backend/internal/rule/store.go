@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
@@ -26,7 +27,8 @@ func (s *Store) ListRules(ctx context.Context, filter *models.RuleFilter) ([]*mo
 	query := `
 		SELECT r.id, r.name, r.description, r.rule_type, r.file_name, 
 		       r.md5_hash, r.file_size, r.rule_count, r.created_at, r.created_by, 
-		       r.updated_at, r.updated_by, r.last_verified_at, r.verification_status
+		       r.updated_at, r.updated_by, r.last_verified_at, r.verification_status,
+		       r.file_modified_at
 		FROM rules r
 		WHERE 1=1
 	`
@@ -97,7 +99,7 @@ func (s *Store) ListRules(ctx context.Context, filter *models.RuleFilter) ([]*mo
 		err := rows.Scan(
 			&r.ID, &r.Name, &r.Description, &r.RuleType, &r.FileName,
 			&r.MD5Hash, &r.FileSize, &r.RuleCount, &r.CreatedAt, &r.CreatedBy,
-			&r.UpdatedAt, &r.UpdatedBy, &lastVerifiedAt, &r.VerificationStatus,
+			&r.UpdatedAt, &r.UpdatedBy, &lastVerifiedAt, &r.VerificationStatus, &r.FileModifiedAt,
 		)
 		if err != nil {
 			debug.Error("Failed to scan rule: %v", err)
@@ -117,6 +119,12 @@ func (s *Store) ListRules(ctx context.Context, filter *models.RuleFilter) ([]*mo
 		}
 		r.Tags = tags
 
+		aliases, err := s.GetRuleAliases(ctx, r.ID)
+		if err != nil {
+			return nil, err
+		}
+		r.Aliases = aliases
+
 		rules = append(rules, r)
 	}
 
@@ -133,7 +141,8 @@ func (s *Store) GetRule(ctx context.Context, id int) (*models.Rule, error) {
 	query := `
 		SELECT r.id, r.name, r.description, r.rule_type, r.file_name, 
 		       r.md5_hash, r.file_size, r.rule_count, r.created_at, r.created_by, 
-		       r.updated_at, r.updated_by, r.last_verified_at, r.verification_status
+		       r.updated_at, r.updated_by, r.last_verified_at, r.verification_status,
+		       r.file_modified_at
 		FROM rules r
 		WHERE r.id = $1
 	`
@@ -144,7 +153,7 @@ func (s *Store) GetRule(ctx context.Context, id int) (*models.Rule, error) {
 	err := s.db.QueryRowContext(ctx, query, id).Scan(
 		&r.ID, &r.Name, &r.Description, &r.RuleType, &r.FileName,
 		&r.MD5Hash, &r.FileSize, &r.RuleCount, &r.CreatedAt, &r.CreatedBy,
-		&r.UpdatedAt, &r.UpdatedBy, &lastVerifiedAt, &r.VerificationStatus,
+		&r.UpdatedAt, &r.UpdatedBy, &lastVerifiedAt, &r.VerificationStatus, &r.FileModifiedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -167,6 +176,12 @@ func (s *Store) GetRule(ctx context.Context, id int) (*models.Rule, error) {
 	}
 	r.Tags = tags
 
+	aliases, err := s.GetRuleAliases(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	r.Aliases = aliases
+
 	return r, nil
 }
 
@@ -175,7 +190,8 @@ func (s *Store) GetRuleByFilename(ctx context.Context, filename string) (*models
 	query := `
 		SELECT r.id, r.name, r.description, r.rule_type, r.file_name, 
 		       r.md5_hash, r.file_size, r.rule_count, r.created_at, r.created_by, 
-		       r.updated_at, r.updated_by, r.last_verified_at, r.verification_status
+		       r.updated_at, r.updated_by, r.last_verified_at, r.verification_status,
+		       r.file_modified_at
 		FROM rules r
 		WHERE r.file_name = $1
 	`
@@ -186,7 +202,7 @@ func (s *Store) GetRuleByFilename(ctx context.Context, filename string) (*models
 	err := s.db.QueryRowContext(ctx, query, filename).Scan(
 		&r.ID, &r.Name, &r.Description, &r.RuleType, &r.FileName,
 		&r.MD5Hash, &r.FileSize, &r.RuleCount, &r.CreatedAt, &r.CreatedBy,
-		&r.UpdatedAt, &r.UpdatedBy, &lastVerifiedAt, &r.VerificationStatus,
+		&r.UpdatedAt, &r.UpdatedBy, &lastVerifiedAt, &r.VerificationStatus, &r.FileModifiedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -217,7 +233,8 @@ func (s *Store) GetRuleByMD5Hash(ctx context.Context, md5Hash string) (*models.R
 	query := `
 		SELECT r.id, r.name, r.description, r.rule_type, r.file_name, 
 		       r.md5_hash, r.file_size, r.rule_count, r.created_at, r.created_by, 
-		       r.updated_at, r.updated_by, r.last_verified_at, r.verification_status
+		       r.updated_at, r.updated_by, r.last_verified_at, r.verification_status,
+		       r.file_modified_at
 		FROM rules r
 		WHERE r.md5_hash = $1
 	`
@@ -228,7 +245,7 @@ func (s *Store) GetRuleByMD5Hash(ctx context.Context, md5Hash string) (*models.R
 	err := s.db.QueryRowContext(ctx, query, md5Hash).Scan(
 		&r.ID, &r.Name, &r.Description, &r.RuleType, &r.FileName,
 		&r.MD5Hash, &r.FileSize, &r.RuleCount, &r.CreatedAt, &r.CreatedBy,
-		&r.UpdatedAt, &r.UpdatedBy, &lastVerifiedAt, &r.VerificationStatus,
+		&r.UpdatedAt, &r.UpdatedBy, &lastVerifiedAt, &r.VerificationStatus, &r.FileModifiedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -254,6 +271,35 @@ func (s *Store) GetRuleByMD5Hash(ctx context.Context, md5Hash string) (*models.R
 	return r, nil
 }
 
+// GetRuleByCanonicalHash retrieves the oldest rule with the given canonical
+// hash, or nil if there is none
+func (s *Store) GetRuleByCanonicalHash(ctx context.Context, canonicalHash string) (*models.Rule, error) {
+	var id int
+	err := s.db.QueryRowContext(ctx,
+		"SELECT id FROM rules WHERE canonical_hash = $1 ORDER BY id LIMIT 1", canonicalHash).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		debug.Error("Failed to get rule by canonical hash %s: %v", canonicalHash, err)
+		return nil, err
+	}
+
+	return s.GetRule(ctx, id)
+}
+
+// UpdateRuleCanonicalHash records the canonical hash of a rule's file
+func (s *Store) UpdateRuleCanonicalHash(ctx context.Context, id int, canonicalHash string) error {
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE rules SET canonical_hash = NULLIF($1, '') WHERE id = $2", canonicalHash, id)
+	if err != nil {
+		debug.Error("Failed to update canonical hash of rule %d: %v", id, err)
+		return err
+	}
+
+	return nil
+}
+
 // CreateRule creates a new rule
 func (s *Store) CreateRule(ctx context.Context, rule *models.Rule) error {
 	query := `
@@ -309,6 +355,37 @@ func (s *Store) UpdateRule(ctx context.Context, rule *models.Rule) error {
 	return nil
 }
 
+// UpdateRuleFileModTime records the modification time of the rule file as it was
+// when it was last hashed
+func (s *Store) UpdateRuleFileModTime(ctx context.Context, id int, modTime time.Time) error {
+	// The column keeps microseconds, Postgres would round the rest instead of
+	// truncating it like the comparison with the file's time does
+	_, err := s.db.ExecContext(ctx, "UPDATE rules SET file_modified_at = $1 WHERE id = $2", modTime.Truncate(time.Microsecond), id)
+	if err != nil {
+		debug.Error("Failed to update file modification time of rule %d: %v", id, err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateRulePath updates the file name of a rule after its file was moved
+func (s *Store) UpdateRulePath(ctx context.Context, id int, fileName string) error {
+	query := `
+		UPDATE rules
+		SET file_name = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	_, err := s.db.ExecContext(ctx, query, fileName, id)
+	if err != nil {
+		debug.Error("Failed to update path of rule %d: %v", id, err)
+		return err
+	}
+
+	return nil
+}
+
 // DeleteRule deletes a rule
 func (s *Store) DeleteRule(ctx context.Context, id int) error {
 	// Delete tags first (foreign key constraint)
@@ -438,7 +515,8 @@ func (s *Store) GetRuleByName(ctx context.Context, name string) (*models.Rule, e
 	query := `
 		SELECT r.id, r.name, r.description, r.rule_type, r.file_name, 
 		       r.md5_hash, r.file_size, r.rule_count, r.created_at, r.created_by, 
-		       r.updated_at, r.updated_by, r.last_verified_at, r.verification_status
+		       r.updated_at, r.updated_by, r.last_verified_at, r.verification_status,
+		       r.file_modified_at
 		FROM rules r
 		WHERE r.name = $1
 	`
@@ -449,7 +527,7 @@ func (s *Store) GetRuleByName(ctx context.Context, name string) (*models.Rule, e
 	err := s.db.QueryRowContext(ctx, query, name).Scan(
 		&r.ID, &r.Name, &r.Description, &r.RuleType, &r.FileName,
 		&r.MD5Hash, &r.FileSize, &r.RuleCount, &r.CreatedAt, &r.CreatedBy,
-		&r.UpdatedAt, &r.UpdatedBy, &lastVerifiedAt, &r.VerificationStatus,
+		&r.UpdatedAt, &r.UpdatedBy, &lastVerifiedAt, &r.VerificationStatus, &r.FileModifiedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -474,3 +552,43 @@ func (s *Store) GetRuleByName(ctx context.Context, name string) (*models.Rule, e
 
 	return r, nil
 }
+
+// GetRuleAliases gets the names duplicate uploads of a rule were aliased under
+func (s *Store) GetRuleAliases(ctx context.Context, id int) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT alias FROM rule_aliases WHERE rule_id = $1 ORDER BY created_at", id)
+	if err != nil {
+		debug.Error("Failed to get aliases for rule %d: %v", id, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []string{}
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			debug.Error("Failed to scan alias: %v", err)
+			return nil, err
+		}
+		aliases = append(aliases, alias)
+	}
+
+	if err := rows.Err(); err != nil {
+		debug.Error("Error iterating alias rows: %v", err)
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+// AddRuleAlias records a name a duplicate upload of a rule was aliased under
+func (s *Store) AddRuleAlias(ctx context.Context, id int, alias string, userID uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO rule_aliases (rule_id, alias, created_by) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING",
+		id, alias, userID)
+	if err != nil {
+		debug.Error("Failed to add alias %s to rule %d: %v", alias, id, err)
+		return err
+	}
+
+	return nil
+}
@@ -14,10 +14,10 @@ import (
 
 // AdminJobWorkflowService defines the interface for managing job workflows.
 type AdminJobWorkflowService interface {
-	CreateJobWorkflow(ctx context.Context, name string, presetJobIDs []uuid.UUID) (*models.JobWorkflow, error)
+	CreateJobWorkflow(ctx context.Context, name string, presetJobIDs []uuid.UUID, inheritance models.WorkflowPriorityInheritance) (*models.JobWorkflow, error)
 	GetJobWorkflowByID(ctx context.Context, id uuid.UUID) (*models.JobWorkflow, error)
 	ListJobWorkflows(ctx context.Context) ([]models.JobWorkflow, error)
-	UpdateJobWorkflow(ctx context.Context, id uuid.UUID, name string, presetJobIDs []uuid.UUID) (*models.JobWorkflow, error)
+	UpdateJobWorkflow(ctx context.Context, id uuid.UUID, name string, presetJobIDs []uuid.UUID, inheritance models.WorkflowPriorityInheritance) (*models.JobWorkflow, error)
 	DeleteJobWorkflow(ctx context.Context, id uuid.UUID) error
 	GetJobWorkflowFormData(ctx context.Context) ([]models.PresetJobBasic, error)
 }
@@ -39,13 +39,16 @@ func NewAdminJobWorkflowService(db *sql.DB, workflowRepo repository.JobWorkflowR
 }
 
 // validateWorkflowInput performs input validation for create/update operations.
-func (s *adminJobWorkflowService) validateWorkflowInput(ctx context.Context, name string, presetJobIDs []uuid.UUID, isUpdate bool, existingID uuid.UUID) error {
+func (s *adminJobWorkflowService) validateWorkflowInput(ctx context.Context, name string, presetJobIDs []uuid.UUID, inheritance models.WorkflowPriorityInheritance, isUpdate bool, existingID uuid.UUID) error {
 	if name == "" {
 		return errors.New("job workflow name cannot be empty")
 	}
 	if len(presetJobIDs) == 0 {
 		return errors.New("job workflow must have at least one step")
 	}
+	if inheritance.PriorityBoost < 0 {
+		return errors.New("priority boost cannot be negative")
+	}
 
 	// Check name uniqueness
 	existingByName, err := s.workflowRepo.GetWorkflowByName(ctx, name)
@@ -82,8 +85,8 @@ func (s *adminJobWorkflowService) validateWorkflowInput(ctx context.Context, nam
 }
 
 // CreateJobWorkflow creates a new workflow and its steps transactionally.
-func (s *adminJobWorkflowService) CreateJobWorkflow(ctx context.Context, name string, presetJobIDs []uuid.UUID) (*models.JobWorkflow, error) {
-	if err := s.validateWorkflowInput(ctx, name, presetJobIDs, false, uuid.Nil); err != nil {
+func (s *adminJobWorkflowService) CreateJobWorkflow(ctx context.Context, name string, presetJobIDs []uuid.UUID, inheritance models.WorkflowPriorityInheritance) (*models.JobWorkflow, error) {
+	if err := s.validateWorkflowInput(ctx, name, presetJobIDs, inheritance, false, uuid.Nil); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
@@ -98,7 +101,7 @@ func (s *adminJobWorkflowService) CreateJobWorkflow(ctx context.Context, name st
 		// 1. Create the workflow record
 		// Assuming repo methods are modified to accept *sql.Tx (or we pass ctx and repo uses internal DB handle)
 		// For now, let's assume repo methods don't take Tx and work directly on s.db within the transaction context
-		createdWorkflow, err = s.workflowRepo.CreateWorkflow(ctx, name) // Need repo to work with Tx or handle context
+		createdWorkflow, err = s.workflowRepo.CreateWorkflow(ctx, name, inheritance) // Need repo to work with Tx or handle context
 		if err != nil {
 			return fmt.Errorf("failed to create workflow record in transaction: %w", err)
 		}
@@ -159,7 +162,7 @@ func (s *adminJobWorkflowService) ListJobWorkflows(ctx context.Context) ([]model
 }
 
 // UpdateJobWorkflow updates a workflow name and replaces its steps transactionally.
-func (s *adminJobWorkflowService) UpdateJobWorkflow(ctx context.Context, id uuid.UUID, name string, presetJobIDs []uuid.UUID) (*models.JobWorkflow, error) {
+func (s *adminJobWorkflowService) UpdateJobWorkflow(ctx context.Context, id uuid.UUID, name string, presetJobIDs []uuid.UUID, inheritance models.WorkflowPriorityInheritance) (*models.JobWorkflow, error) {
 	// 1. Check if workflow exists first
 	_, err := s.GetJobWorkflowByID(ctx, id)
 	if err != nil {
@@ -167,7 +170,7 @@ func (s *adminJobWorkflowService) UpdateJobWorkflow(ctx context.Context, id uuid
 	}
 
 	// 2. Validate input
-	if err := s.validateWorkflowInput(ctx, name, presetJobIDs, true, id); err != nil {
+	if err := s.validateWorkflowInput(ctx, name, presetJobIDs, inheritance, true, id); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
@@ -179,8 +182,8 @@ func (s *adminJobWorkflowService) UpdateJobWorkflow(ctx context.Context, id uuid
 	// Execute DB operations within a transaction
 	err = s.executeTransaction(ctx, func(tx *sql.Tx) error {
 		var err error
-		// 3. Update workflow name
-		updatedWorkflow, err = s.workflowRepo.UpdateWorkflow(ctx, id, name)
+		// 3. Update workflow name and priority inheritance
+		updatedWorkflow, err = s.workflowRepo.UpdateWorkflow(ctx, id, name, inheritance)
 		if err != nil {
 			return fmt.Errorf("failed to update workflow name in transaction: %w", err)
 		}
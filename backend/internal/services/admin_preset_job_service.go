@@ -16,6 +16,8 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatargs"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatmask"
 	"github.com/google/uuid"
 )
 
@@ -128,10 +130,25 @@ func (s *adminPresetJobService) validatePresetJob(ctx context.Context, params mo
 		}
 	}
 
+	// A wordlist collection takes the place of the single wordlist of the
+	// attack, each job created from the preset runs one of its wordlists
+	if params.WordlistCollectionID != nil {
+		if !models.CollectionAttackMode(params.AttackMode) {
+			return errors.New("wordlist collections are only supported in straight and hybrid attack modes")
+		}
+		if len(params.WordlistIDs) > 0 {
+			return errors.New("a preset job uses either wordlists or a wordlist collection, not both")
+		}
+	}
+	wordlists := len(params.WordlistIDs)
+	if params.WordlistCollectionID != nil {
+		wordlists = 1
+	}
+
 	// Attack mode specific validation
 	switch params.AttackMode {
 	case models.AttackModeStraight:
-		if len(params.WordlistIDs) != 1 {
+		if wordlists != 1 {
 			return errors.New("straight attack mode requires exactly one wordlist")
 		}
 		// Rules are optional for straight mode
@@ -154,12 +171,12 @@ func (s *adminPresetJobService) validatePresetJob(ctx context.Context, params mo
 		if params.Mask == "" {
 			return errors.New("mask is required for brute force attack mode")
 		}
-		if !validateMaskPattern(params.Mask) {
-			return errors.New("invalid mask pattern format")
+		if _, err := hashcatmask.Validate(params.Mask); err != nil {
+			return err
 		}
 
 	case models.AttackModeHybridWordlistMask, models.AttackModeHybridMaskWordlist:
-		if len(params.WordlistIDs) != 1 {
+		if wordlists != 1 {
 			return errors.New("hybrid attack modes require exactly one wordlist")
 		}
 		if len(params.RuleIDs) > 0 {
@@ -168,14 +185,33 @@ func (s *adminPresetJobService) validatePresetJob(ctx context.Context, params mo
 		if params.Mask == "" {
 			return errors.New("mask is required for hybrid attack modes")
 		}
-		if !validateMaskPattern(params.Mask) {
-			return errors.New("invalid mask pattern format")
+		if _, err := hashcatmask.Validate(params.Mask); err != nil {
+			return err
 		}
 
 	case models.AttackModeAssociation:
 		return errors.New("association attack mode is not currently implemented")
 	}
 
+	// --increment runs a brute force mask once for each length between its bounds
+	if params.MaskIncrement != nil {
+		if params.AttackMode != models.AttackModeBruteForce {
+			return errors.New("mask increments are only supported in brute force attack mode")
+		}
+		increment := *params.MaskIncrement
+		if _, err := increment.Resolve(params.Mask); err != nil {
+			return fmt.Errorf("invalid mask increment: %w", err)
+		}
+	}
+
+	// Additional arguments are passed to hashcat on the agents, only allowed
+	// options are accepted
+	if params.AdditionalArgs != nil {
+		if err := hashcatargs.Validate(*params.AdditionalArgs); err != nil {
+			return fmt.Errorf("invalid additional arguments: %w", err)
+		}
+	}
+
 	// TODO: Add deeper validation if necessary:
 	// - Check if BinaryVersionID actually exists in binary_versions table.
 	// - Check if all WordlistIDs/RuleIDs exist (might require fetching all valid IDs).
@@ -185,53 +221,6 @@ func (s *adminPresetJobService) validatePresetJob(ctx context.Context, params mo
 	return nil
 }
 
-// validateMaskPattern validates that the mask follows the expected pattern for hashcat.
-// Simple validation to check for valid character sets: ?u, ?l, ?d, ?s, ?a, ?b
-// and length requirements.
-func validateMaskPattern(mask string) bool {
-	if mask == "" {
-		return false
-	}
-
-	// Pattern should consist of character set specifiers
-	// Each valid specifier is two characters: ? followed by a character class
-	validSpecifiers := map[string]bool{
-		"?u": true, // uppercase
-		"?l": true, // lowercase
-		"?d": true, // digit
-		"?s": true, // special
-		"?a": true, // all (uppercase, lowercase, digit, special)
-		"?b": true, // binary (0x00 - 0xff)
-		"?h": true, // lowercase hex
-		"?H": true, // uppercase hex
-	}
-
-	i := 0
-	for i < len(mask) {
-		// If we encounter a literal character (not part of a specifier)
-		if mask[i] != '?' {
-			i++
-			continue
-		}
-
-		// Check if we have enough characters for a complete specifier
-		if i+1 >= len(mask) {
-			return false // Incomplete specifier at end of mask
-		}
-
-		// Check if the specifier is valid
-		specifier := mask[i : i+2]
-		if !validSpecifiers[specifier] {
-			return false // Invalid specifier
-		}
-
-		i += 2 // Move past this specifier
-	}
-
-	// Ensure mask isn't empty after validation
-	return true
-}
-
 // CreatePresetJob creates a new preset job after validation.
 func (s *adminPresetJobService) CreatePresetJob(ctx context.Context, params models.PresetJob) (*models.PresetJob, error) {
 	// Set default values if not provided
@@ -252,6 +241,13 @@ func (s *adminPresetJobService) CreatePresetJob(ctx context.Context, params mode
 
 	debug.Info("Creating preset job: %s", params.Name)
 
+	// The keyspace of a collection depends on its wordlists at job creation,
+	// it is calculated for each job then
+	if params.WordlistCollectionID != nil {
+		params.Keyspace = nil
+		return s.presetJobRepo.Create(ctx, params)
+	}
+
 	// Calculate keyspace for the preset job
 	keyspace, err := s.CalculateKeyspaceForPresetJob(ctx, &params)
 	if err != nil {
@@ -318,7 +314,10 @@ func (s *adminPresetJobService) UpdatePresetJob(ctx context.Context, id uuid.UUI
 	existingJob, _ := s.presetJobRepo.GetByID(ctx, id)
 
 	// Check if keyspace was explicitly provided (from recalculation endpoint)
-	if params.Keyspace != nil {
+	if params.WordlistCollectionID != nil {
+		// Calculated for each job when the collection is expanded
+		params.Keyspace = nil
+	} else if params.Keyspace != nil {
 		// Keyspace was explicitly set, use it
 		debug.Info("Using explicitly provided keyspace for preset job %s: %v", id, params.Keyspace)
 	} else if existingJob != nil && s.needsKeyspaceRecalculation(existingJob, &params) {
@@ -333,6 +332,7 @@ func (s *adminPresetJobService) UpdatePresetJob(ctx context.Context, id uuid.UUI
 	} else if existingJob != nil {
 		// Keep existing keyspace if no changes affecting it
 		params.Keyspace = existingJob.Keyspace
+		params.MaskIncrement = existingJob.MaskIncrement
 	}
 
 	updatedJob, err := s.presetJobRepo.Update(ctx, id, params)
@@ -382,6 +382,12 @@ func (s *adminPresetJobService) needsKeyspaceRecalculation(existing, updated *mo
 		return true
 	}
 
+	// Check if the wordlist collection changed
+	if (existing.WordlistCollectionID == nil) != (updated.WordlistCollectionID == nil) ||
+		(existing.WordlistCollectionID != nil && *existing.WordlistCollectionID != *updated.WordlistCollectionID) {
+		return true
+	}
+
 	// Check if wordlists changed
 	if len(existing.WordlistIDs) != len(updated.WordlistIDs) {
 		return true
@@ -409,6 +415,11 @@ func (s *adminPresetJobService) needsKeyspaceRecalculation(existing, updated *mo
 		return true
 	}
 
+	// Check if the increment bounds changed
+	if !existing.MaskIncrement.SameBounds(updated.MaskIncrement) {
+		return true
+	}
+
 	// Check if binary version changed
 	if existing.BinaryVersionID != updated.BinaryVersionID {
 		return true
@@ -417,8 +428,44 @@ func (s *adminPresetJobService) needsKeyspaceRecalculation(existing, updated *mo
 	return false
 }
 
-// CalculateKeyspaceForPresetJob calculates the total keyspace for a preset job using hashcat --keyspace
+// ErrCollectionKeyspace is returned when calculating the keyspace of a preset
+// job running a wordlist collection, it is calculated for each job instead
+var ErrCollectionKeyspace = errors.New("the keyspace of a wordlist collection is calculated for each job it creates")
+
+// CalculateKeyspaceForPresetJob calculates the total keyspace for a preset job.
+// A mask with increment bounds is calculated once per length, and the keyspace
+// of each length is stored in the preset's MaskIncrement.
 func (s *adminPresetJobService) CalculateKeyspaceForPresetJob(ctx context.Context, presetJob *models.PresetJob) (*int64, error) {
+	if presetJob.WordlistCollectionID != nil {
+		return nil, ErrCollectionKeyspace
+	}
+	if presetJob.MaskIncrement == nil {
+		return s.calculateKeyspace(ctx, presetJob)
+	}
+
+	increment := models.MaskIncrement{Min: presetJob.MaskIncrement.Min, Max: presetJob.MaskIncrement.Max}
+	lines, err := increment.Resolve(presetJob.Mask)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mask increment: %w", err)
+	}
+	for _, line := range lines {
+		lengthJob := *presetJob
+		lengthJob.Mask = line.String()
+		lengthJob.MaskIncrement = nil
+		keyspace, err := s.calculateKeyspace(ctx, &lengthJob)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate keyspace of mask %s: %w", lengthJob.Mask, err)
+		}
+		increment.Keyspaces = append(increment.Keyspaces, *keyspace)
+	}
+
+	presetJob.MaskIncrement = &increment
+	total := increment.Total()
+	return &total, nil
+}
+
+// calculateKeyspace calculates the keyspace of a preset job using hashcat --keyspace
+func (s *adminPresetJobService) calculateKeyspace(ctx context.Context, presetJob *models.PresetJob) (*int64, error) {
 	debug.Log("Starting keyspace calculation for preset job", map[string]interface{}{
 		"preset_job_id":    presetJob.ID,
 		"binary_version_id": presetJob.BinaryVersionID,
@@ -486,7 +533,7 @@ func (s *adminPresetJobService) CalculateKeyspaceForPresetJob(ctx context.Contex
 
 	case models.AttackModeBruteForce: // Mask attack
 		if presetJob.Mask != "" {
-			args = append(args, presetJob.Mask)
+			args = append(args, hashcatmask.LineArgs(presetJob.Mask)...)
 		}
 
 	case models.AttackModeHybridWordlistMask: // Hybrid Wordlist + Mask
@@ -495,7 +542,8 @@ func (s *adminPresetJobService) CalculateKeyspaceForPresetJob(ctx context.Contex
 			if err != nil {
 				return nil, fmt.Errorf("failed to resolve wordlist path: %w", err)
 			}
-			args = append(args, wordlistPath, presetJob.Mask)
+			args = append(args, wordlistPath)
+			args = append(args, hashcatmask.LineArgs(presetJob.Mask)...)
 		}
 
 	case models.AttackModeHybridMaskWordlist: // Hybrid Mask + Wordlist
@@ -504,7 +552,8 @@ func (s *adminPresetJobService) CalculateKeyspaceForPresetJob(ctx context.Contex
 			if err != nil {
 				return nil, fmt.Errorf("failed to resolve wordlist path: %w", err)
 			}
-			args = append(args, presetJob.Mask, wordlistPath)
+			args = append(args, hashcatmask.LineArgs(presetJob.Mask)...)
+			args = append(args, wordlistPath)
 		}
 
 	default:
@@ -534,6 +583,13 @@ func (s *adminPresetJobService) CalculateKeyspaceForPresetJob(ctx context.Contex
 		"full_command":   fmt.Sprintf("%s %s", hashcatPath, strings.Join(args, " ")),
 	})
 
+	// Wait for a hashcat slot first, so the timeout only counts the run itself
+	release, err := acquireHashcatExec(ctx, s.systemSettingsRepo, HashcatExecPresetKeyspace, fmt.Sprintf("preset job %s", presetJob.ID))
+	if err != nil {
+		return nil, fmt.Errorf("hashcat keyspace calculation not started: %w", err)
+	}
+	defer release()
+
 	// Execute hashcat command with timeout
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
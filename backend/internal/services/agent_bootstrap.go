@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// MaxPreregisteredAgents bounds how many agents or claim codes a single
+// pre-registration request creates
+const MaxPreregisteredAgents = 100
+
+var (
+	// ErrAgentNameTaken is returned when pre-registering a name that belongs to
+	// an agent which was not pre-registered by the same user
+	ErrAgentNameTaken = errors.New("agent name is already in use")
+
+	// ErrInvalidBootstrapToken is returned when no agent holds a bootstrap token
+	ErrInvalidBootstrapToken = errors.New("invalid bootstrap token")
+
+	// ErrNotAgentOwner is returned when a user who is neither the agent's owner
+	// nor an administrator revokes its bootstrap token
+	ErrNotAgentOwner = errors.New("agent is owned by another user")
+)
+
+// randomHex returns n random bytes as a hex string
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// PreregisterAgent creates an agent owned by userID before it has ever
+// connected and gives it a bootstrap token. Pre-registering a name again returns
+// the existing agent and token, so provisioning automation can be re-run safely.
+// Returns the agent, its bootstrap token and whether this call created it.
+func (s *AgentService) PreregisterAgent(ctx context.Context, userID uuid.UUID, name string) (*models.Agent, string, bool, error) {
+	existingID, ownerID, existingToken, err := s.agentRepo.GetBootstrapInfoByName(ctx, name)
+	if err == nil {
+		if !existingToken.Valid || !ownerID.Valid || ownerID.String != userID.String() {
+			return nil, "", false, ErrAgentNameTaken
+		}
+		agent, err := s.agentRepo.GetByID(ctx, existingID)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return agent, existingToken.String, false, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, "", false, fmt.Errorf("failed to check agent name: %w", err)
+	}
+
+	apiKey, err := randomHex(32)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	token, err := randomHex(32)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to generate bootstrap token: %w", err)
+	}
+
+	now := time.Now()
+	agent := &models.Agent{
+		Name:          name,
+		Status:        models.AgentStatusPending,
+		CreatedByID:   userID,
+		OwnerID:       &userID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		LastHeartbeat: now,
+		Version:       "unknown",
+		APIKey: sql.NullString{
+			String: apiKey,
+			Valid:  true,
+		},
+		APIKeyCreatedAt: sql.NullTime{
+			Time:  now,
+			Valid: true,
+		},
+	}
+	if err := s.agentRepo.Create(ctx, agent); err != nil {
+		return nil, "", false, err
+	}
+
+	if err := s.agentRepo.SetBootstrapToken(ctx, agent.ID, token); err != nil {
+		if delErr := s.agentRepo.Delete(ctx, agent.ID); delErr != nil {
+			debug.Error("Failed to delete agent %d after bootstrap token failure: %v", agent.ID, delErr)
+		}
+		return nil, "", false, err
+	}
+
+	debug.Info("Pre-registered agent %d (%s) for user %s", agent.ID, agent.Name, userID)
+	return agent, token, true, nil
+}
+
+// BootstrapAgent returns the pre-registered agent holding a bootstrap token and
+// records the version it is running. The token stays valid, so an agent that
+// lost its credentials can bootstrap again as the same agent.
+func (s *AgentService) BootstrapAgent(ctx context.Context, token, hostname, version string) (*models.Agent, error) {
+	if token == "" {
+		return nil, ErrInvalidBootstrapToken
+	}
+
+	agentID, err := s.agentRepo.GetIDByBootstrapToken(ctx, token)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidBootstrapToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up bootstrap token: %w", err)
+	}
+
+	if version != "" {
+		if err := s.agentRepo.UpdateVersion(ctx, agentID, version); err != nil {
+			debug.Warning("Failed to update version of bootstrapped agent %d: %v", agentID, err)
+		}
+	}
+
+	agent, err := s.agentRepo.GetByID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	debug.Info("Agent %d (%s) bootstrapped from host %s", agent.ID, agent.Name, hostname)
+	return agent, nil
+}
+
+// RevokeBootstrapToken stops an agent's bootstrap token from being used again.
+// Only the agent's owner or an administrator may revoke it.
+func (s *AgentService) RevokeBootstrapToken(ctx context.Context, agentID int, userID uuid.UUID, isAdmin bool) error {
+	ownerID, err := s.agentRepo.GetOwnerID(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	if !isAdmin && (!ownerID.Valid || ownerID.String != userID.String()) {
+		return ErrNotAgentOwner
+	}
+	return s.agentRepo.ClearBootstrapToken(ctx, agentID)
+}
+
+// CreateClaimCodes creates single-use claim codes owned by userID and returns
+// them formatted for display
+func (s *AgentService) CreateClaimCodes(ctx context.Context, userID uuid.UUID, count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		code := generateClaimCode()
+		now := time.Now()
+		voucher := &models.ClaimVoucher{
+			Code:        normalizeClaimCode(code),
+			IsActive:    true,
+			CreatedByID: userID,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := s.voucherRepo.Create(ctx, voucher); err != nil {
+			return codes, fmt.Errorf("failed to create claim code: %w", err)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
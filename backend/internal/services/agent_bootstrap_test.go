@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeBootstrapTokenRequiresOwnerOrAdmin(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		isAdmin bool
+		wantErr error
+	}{
+		{"owner", owner, false, nil},
+		{"admin", other, true, nil},
+		{"other user", other, false, ErrNotAgentOwner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockDB.Close()
+			service := &AgentService{agentRepo: repository.NewAgentRepository(&db.DB{DB: mockDB})}
+
+			mock.ExpectQuery("SELECT owner_id FROM agents").WithArgs(7).
+				WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow(owner.String()))
+			if tt.wantErr == nil {
+				mock.ExpectExec("UPDATE agents SET bootstrap_token = NULL").WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			err = service.RevokeBootstrapToken(context.Background(), 7, tt.userID, tt.isAdmin)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("unknown agent", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+		service := &AgentService{agentRepo: repository.NewAgentRepository(&db.DB{DB: mockDB})}
+
+		mock.ExpectQuery("SELECT owner_id FROM agents").WithArgs(7).WillReturnError(sql.ErrNoRows)
+
+		assert.ErrorIs(t, service.RevokeBootstrapToken(context.Background(), 7, owner, true), sql.ErrNoRows)
+	})
+}
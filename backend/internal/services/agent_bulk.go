@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatargs"
+)
+
+// ErrInvalidBulkRequest is returned for a bulk agent request that cannot be applied
+var ErrInvalidBulkRequest = errors.New("invalid bulk agent request")
+
+// ErrAgentNotConnected is returned for a command to an agent that is offline
+var ErrAgentNotConnected = errors.New("agent not connected")
+
+// AgentCommander sends control commands to connected agents
+type AgentCommander interface {
+	TriggerFileSync(agentID int) error
+	SendForceCleanup(ctx context.Context, agentID int) error
+	PushAgentConfig(agentID int) error
+	AuditAgentFiles(agentID int) (*models.AgentFileAudit, error)
+	FileAudit(agentID int) *models.AgentFileAudit
+	RotateAgentCredentials(agentID int) (*models.AgentCredentialRotation, error)
+	CredentialRotation(agentID int) *models.AgentCredentialRotation
+}
+
+// AgentBulkService applies one action to every agent matching a selector
+type AgentBulkService struct {
+	agentRepo    *repository.AgentRepository
+	scheduleRepo *repository.AgentScheduleRepository
+	jobTaskRepo  *repository.JobTaskRepository
+	commander    func() AgentCommander
+}
+
+// NewAgentBulkService creates a new bulk agent service. commander is called
+// per request since the WebSocket handler is set up after the routes.
+func NewAgentBulkService(agentRepo *repository.AgentRepository, scheduleRepo *repository.AgentScheduleRepository, jobTaskRepo *repository.JobTaskRepository, commander func() AgentCommander) *AgentBulkService {
+	return &AgentBulkService{
+		agentRepo:    agentRepo,
+		scheduleRepo: scheduleRepo,
+		jobTaskRepo:  jobTaskRepo,
+		commander:    commander,
+	}
+}
+
+// UpdateLabels replaces the labels of one agent
+func (s *AgentBulkService) UpdateLabels(ctx context.Context, agentID int, labels []string) ([]string, error) {
+	normalized, err := models.NormalizeAgentLabels(labels)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBulkRequest, err)
+	}
+	if err := s.agentRepo.UpdateLabels(ctx, agentID, normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// UpdateWorkload sets the workload class and profile of one agent
+func (s *AgentBulkService) UpdateWorkload(ctx context.Context, agentID int, class models.WorkloadClass, profile *int) error {
+	if err := models.ValidateWorkload(class, profile); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBulkRequest, err)
+	}
+	return s.agentRepo.UpdateWorkload(ctx, agentID, class, profile)
+}
+
+// UpdateManagedConfig replaces the backend-managed settings of one agent and
+// pushes them to it if it is connected
+func (s *AgentBulkService) UpdateManagedConfig(ctx context.Context, agentID int, config models.AgentManagedConfig) error {
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBulkRequest, err)
+	}
+	return s.setManagedConfig(ctx, agentID, config)
+}
+
+// setManagedConfig stores the agent's managed settings and pushes them to it
+func (s *AgentBulkService) setManagedConfig(ctx context.Context, agentID int, config models.AgentManagedConfig) error {
+	if err := s.agentRepo.UpdateManagedConfig(ctx, agentID, config); err != nil {
+		return err
+	}
+	if commander := s.commander(); commander != nil {
+		if err := commander.PushAgentConfig(agentID); err != nil {
+			return fmt.Errorf("saved, but failed to push the configuration: %w", err)
+		}
+	}
+	return nil
+}
+
+// Apply validates the request and runs its action on every selected agent. A
+// failure on one agent is reported in its result and does not stop the others.
+func (s *AgentBulkService) Apply(ctx context.Context, req *models.BulkAgentRequest) (*models.BulkAgentResponse, error) {
+	if req.Empty() {
+		return nil, fmt.Errorf("%w: select agents by agent_ids or labels", ErrInvalidBulkRequest)
+	}
+	labels, err := models.NormalizeAgentLabels(req.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBulkRequest, err)
+	}
+	selector := models.AgentSelector{AgentIDs: req.AgentIDs, Labels: labels}
+
+	apply, err := s.action(req)
+	if err != nil {
+		return nil, err
+	}
+
+	agents, err := s.agentRepo.List(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	response := &models.BulkAgentResponse{Action: req.Action, Results: []models.BulkAgentResult{}}
+	for i := range agents {
+		agent := &agents[i]
+		if !selector.Matches(agent) {
+			continue
+		}
+		response.Matched++
+
+		result := models.BulkAgentResult{AgentID: agent.ID, Name: agent.Name, Status: models.BulkAgentStatusOK}
+		if status, message, err := apply(ctx, agent); err != nil {
+			result.Status = models.BulkAgentStatusFailed
+			result.Message = err.Error()
+		} else {
+			if status != "" {
+				result.Status = status
+			}
+			result.Message = message
+		}
+		response.Add(result)
+	}
+
+	debug.Info("Bulk agent action %s: %d matched, %d succeeded, %d failed",
+		req.Action, response.Matched, response.Succeeded, response.Failed)
+	return response, nil
+}
+
+// bulkAgentFunc applies an action to one agent. It may return a status other
+// than ok and a message to report.
+type bulkAgentFunc func(ctx context.Context, agent *models.Agent) (status, message string, err error)
+
+// action validates the action's parameters once and returns the function that
+// applies it to an agent
+func (s *AgentBulkService) action(req *models.BulkAgentRequest) (bulkAgentFunc, error) {
+	switch req.Action {
+	case models.BulkAgentActionEnable, models.BulkAgentActionDisable:
+		enabled := req.Action == models.BulkAgentActionEnable
+		return func(ctx context.Context, agent *models.Agent) (string, string, error) {
+			return "", "", s.agentRepo.UpdateEnabled(ctx, agent.ID, enabled)
+		}, nil
+
+	case models.BulkAgentActionDrain:
+		return s.drain, nil
+
+	case models.BulkAgentActionSetSchedule:
+		if len(req.Schedules) == 0 && req.SchedulingEnabled == nil {
+			return nil, fmt.Errorf("%w: set_schedule needs schedules or scheduling_enabled", ErrInvalidBulkRequest)
+		}
+		schedules, err := parseBulkSchedules(req.Schedules)
+		if err != nil {
+			return nil, err
+		}
+		timezone := req.Timezone
+		if timezone == "" {
+			timezone = "UTC"
+		}
+		return func(ctx context.Context, agent *models.Agent) (string, string, error) {
+			return "", "", s.setSchedule(ctx, agent, schedules, req.SchedulingEnabled, timezone)
+		}, nil
+
+	case models.BulkAgentActionFileSync:
+		return func(ctx context.Context, agent *models.Agent) (string, string, error) {
+			commander := s.commander()
+			if commander == nil {
+				return "", "", errors.New("agent connections are not available")
+			}
+			return "", "", commander.TriggerFileSync(agent.ID)
+		}, nil
+
+	case models.BulkAgentActionFileAudit:
+		return func(ctx context.Context, agent *models.Agent) (string, string, error) {
+			audit, err := s.StartFileAudit(ctx, agent.ID)
+			if err != nil {
+				return "", "", err
+			}
+			return "", "audit " + audit.RequestID + " started", nil
+		}, nil
+
+	case models.BulkAgentActionRotateCredentials:
+		return func(ctx context.Context, agent *models.Agent) (string, string, error) {
+			rotation, err := s.RotateCredentials(ctx, agent.ID)
+			if err != nil {
+				return "", "", err
+			}
+			return "", "rotation " + rotation.RotationID + " sent", nil
+		}, nil
+
+	case models.BulkAgentActionForceCleanup:
+		return func(ctx context.Context, agent *models.Agent) (string, string, error) {
+			commander := s.commander()
+			if commander == nil {
+				return "", "", errors.New("agent connections are not available")
+			}
+			return "", "", commander.SendForceCleanup(ctx, agent.ID)
+		}, nil
+
+	case models.BulkAgentActionSetExtraParameters:
+		params := strings.Join(strings.Fields(req.ExtraParameters), " ")
+		if params != "" {
+			if err := hashcatargs.Validate(params); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidBulkRequest, err)
+			}
+		}
+		return func(ctx context.Context, agent *models.Agent) (string, string, error) {
+			return "", "", s.agentRepo.UpdateExtraParameters(ctx, agent.ID, params)
+		}, nil
+
+	case models.BulkAgentActionAddLabels, models.BulkAgentActionRemoveLabels:
+		change, err := models.NormalizeAgentLabels(req.ChangeLabels)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBulkRequest, err)
+		}
+		if len(change) == 0 {
+			return nil, fmt.Errorf("%w: change_labels is required", ErrInvalidBulkRequest)
+		}
+		add := req.Action == models.BulkAgentActionAddLabels
+		return func(ctx context.Context, agent *models.Agent) (string, string, error) {
+			labels, err := models.NormalizeAgentLabels(changeLabels(agent.Labels, change, add))
+			if err != nil {
+				return "", "", err
+			}
+			return "", "", s.agentRepo.UpdateLabels(ctx, agent.ID, labels)
+		}, nil
+
+	case models.BulkAgentActionSetWorkload:
+		if err := models.ValidateWorkload(req.WorkloadClass, req.WorkloadProfile); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBulkRequest, err)
+		}
+		return func(ctx context.Context, agent *models.Agent) (string, string, error) {
+			return "", "", s.agentRepo.UpdateWorkload(ctx, agent.ID, req.WorkloadClass, req.WorkloadProfile)
+		}, nil
+
+	case models.BulkAgentActionSetConfig:
+		if req.Config == nil {
+			return nil, fmt.Errorf("%w: set_config needs config", ErrInvalidBulkRequest)
+		}
+		if err := req.Config.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBulkRequest, err)
+		}
+		return func(ctx context.Context, agent *models.Agent) (string, string, error) {
+			return "", "", s.setManagedConfig(ctx, agent.ID, *req.Config)
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown action %q", ErrInvalidBulkRequest, req.Action)
+	}
+}
+
+// StartFileAudit makes the agent rehash its cached files and corrects what
+// has drifted, see FileAudit for the report
+func (s *AgentBulkService) StartFileAudit(ctx context.Context, agentID int) (*models.AgentFileAudit, error) {
+	commander := s.commander()
+	if commander == nil {
+		return nil, errors.New("agent connections are not available")
+	}
+	audit, err := commander.AuditAgentFiles(agentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAgentNotConnected, err)
+	}
+	return audit, nil
+}
+
+// FileAudit returns the latest file audit of the agent, or nil if there is none
+func (s *AgentBulkService) FileAudit(agentID int) *models.AgentFileAudit {
+	commander := s.commander()
+	if commander == nil {
+		return nil
+	}
+	return commander.FileAudit(agentID)
+}
+
+// RotateCredentials sends the agent a new API key and client certificate over
+// its connection, see CredentialRotation for the outcome
+func (s *AgentBulkService) RotateCredentials(ctx context.Context, agentID int) (*models.AgentCredentialRotation, error) {
+	commander := s.commander()
+	if commander == nil {
+		return nil, errors.New("agent connections are not available")
+	}
+	return commander.RotateAgentCredentials(agentID)
+}
+
+// CredentialRotation returns the latest credential rotation of the agent, or
+// nil if there is none
+func (s *AgentBulkService) CredentialRotation(agentID int) *models.AgentCredentialRotation {
+	commander := s.commander()
+	if commander == nil {
+		return nil
+	}
+	return commander.CredentialRotation(agentID)
+}
+
+// drain stops the agent from receiving new work while it finishes the task it
+// is running, if any
+func (s *AgentBulkService) drain(ctx context.Context, agent *models.Agent) (string, string, error) {
+	if err := s.agentRepo.UpdateEnabled(ctx, agent.ID, false); err != nil {
+		return "", "", err
+	}
+
+	tasks, err := s.jobTaskRepo.GetActiveTasksByAgent(ctx, agent.ID)
+	if err != nil {
+		return "", "", fmt.Errorf("disabled, but failed to check for running tasks: %w", err)
+	}
+	if len(tasks) == 0 {
+		return "", "idle", nil
+	}
+
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID.String())
+	}
+	return models.BulkAgentStatusDraining, "finishing task " + strings.Join(ids, ", "), nil
+}
+
+// setSchedule replaces the agent's weekly schedule and optionally turns
+// scheduling on or off
+func (s *AgentBulkService) setSchedule(ctx context.Context, agent *models.Agent, schedules []models.AgentSchedule, enabled *bool, timezone string) error {
+	if len(schedules) > 0 {
+		if err := s.scheduleRepo.DeleteAllSchedules(ctx, agent.ID); err != nil {
+			return err
+		}
+		for _, schedule := range schedules {
+			schedule.AgentID = agent.ID
+			if err := s.scheduleRepo.UpdateSchedule(ctx, &schedule); err != nil {
+				return fmt.Errorf("failed to set schedule for day %d: %w", schedule.DayOfWeek, err)
+			}
+		}
+	}
+
+	if enabled != nil {
+		if err := s.scheduleRepo.UpdateAgentScheduling(ctx, agent.ID, *enabled, timezone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseBulkSchedules converts and validates the schedules of a set_schedule request
+func parseBulkSchedules(dtos []models.AgentScheduleDTO) ([]models.AgentSchedule, error) {
+	schedules := make([]models.AgentSchedule, 0, len(dtos))
+	for _, dto := range dtos {
+		startTime, err := models.ParseTimeOnly(dto.StartTimeUTC)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid start time for day %d: %v", ErrInvalidBulkRequest, dto.DayOfWeek, err)
+		}
+		endTime, err := models.ParseTimeOnly(dto.EndTimeUTC)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid end time for day %d: %v", ErrInvalidBulkRequest, dto.DayOfWeek, err)
+		}
+
+		schedule := models.AgentSchedule{
+			DayOfWeek: dto.DayOfWeek,
+			StartTime: startTime,
+			EndTime:   endTime,
+			Timezone:  dto.Timezone,
+			IsActive:  dto.IsActive,
+		}
+		if err := schedule.ValidateSchedule(); err != nil {
+			return nil, fmt.Errorf("%w: invalid schedule for day %d", ErrInvalidBulkRequest, dto.DayOfWeek)
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// changeLabels adds the labels to, or removes them from, the current ones
+func changeLabels(current, change []string, add bool) []string {
+	if add {
+		return append(append([]string{}, current...), change...)
+	}
+
+	remove := make(map[string]bool, len(change))
+	for _, label := range change {
+		remove[label] = true
+	}
+	kept := []string{}
+	for _, label := range current {
+		if !remove[label] {
+			kept = append(kept, label)
+		}
+	}
+	return kept
+}
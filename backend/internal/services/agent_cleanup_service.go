@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
@@ -74,6 +75,15 @@ func (s *AgentCleanupService) CleanupStaleAgents(ctx context.Context, heartbeatT
 			err := s.agentRepo.UpdateStatus(ctx, agent.ID, models.AgentStatusInactive, nil)
 			if err != nil {
 				debug.Error("Failed to mark stale agent %d as inactive: %v", agent.ID, err)
+				continue
+			}
+
+			err = events.Publish(ctx, s.agentRepo.GetDB(), events.AgentOffline, events.AgentOfflinePayload{
+				AgentID: agent.ID,
+				Reason:  "heartbeat_timeout",
+			})
+			if err != nil {
+				debug.Error("Failed to publish agent offline event for agent %d: %v", agent.ID, err)
 			}
 		}
 	}
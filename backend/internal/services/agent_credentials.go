@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// defaultCredentialRotationGrace is how long a replaced API key keeps working
+// when agent_credential_rotation_grace_minutes is not set
+const defaultCredentialRotationGrace = time.Hour
+
+// ErrCredentialRotationSuperseded is returned when an agent confirms a
+// rotation whose key was replaced by a later rotation
+var ErrCredentialRotationSuperseded = errors.New("credential rotation was superseded")
+
+// BeginCredentialRotation issues a new API key for the agent. It works at
+// once alongside the current one, so the agent may store it before confirming.
+func (s *AgentService) BeginCredentialRotation(ctx context.Context, agentID int) (*models.AgentCredentialRotation, error) {
+	apiKeyBytes := make([]byte, 32) // 32 bytes = 64 hex characters
+	if _, err := rand.Read(apiKeyBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	rotation := models.NewAgentCredentialRotation(agentID, hex.EncodeToString(apiKeyBytes))
+
+	if err := s.agentRepo.SetPendingAPIKey(ctx, agentID, rotation.APIKey()); err != nil {
+		return nil, err
+	}
+	return rotation, nil
+}
+
+// ConfirmCredentialRotation makes the rotation's key the agent's key after
+// the agent stored it. The replaced key works until the returned time, which
+// is zero when the rotation was already completed by the agent authenticating
+// with the new key.
+func (s *AgentService) ConfirmCredentialRotation(ctx context.Context, rotation *models.AgentCredentialRotation) (time.Time, error) {
+	expiresAt := time.Now().Add(s.CredentialRotationGrace(ctx))
+	promoted, err := s.agentRepo.PromotePendingAPIKey(ctx, rotation.AgentID, rotation.APIKey(), expiresAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !promoted {
+		// The agent may have authenticated with the key before confirming
+		if agent, err := s.agentRepo.GetByID(ctx, rotation.AgentID); err == nil && agent.APIKey.String == rotation.APIKey() {
+			return time.Time{}, nil
+		}
+		return time.Time{}, ErrCredentialRotationSuperseded
+	}
+
+	s.RecordSystemEvent(ctx, models.NewAgentSystemEvent(models.SystemEventInfo, models.SystemEventCategoryAgent,
+		models.SystemEventCredentialsRotated, rotation.AgentID,
+		fmt.Sprintf("Agent %d credentials rotated, previous API key valid until %s", rotation.AgentID, expiresAt.Format(time.RFC3339))))
+	return expiresAt, nil
+}
+
+// CancelCredentialRotation withdraws the key of a rotation the agent could
+// not apply, it keeps its current key
+func (s *AgentService) CancelCredentialRotation(ctx context.Context, rotation *models.AgentCredentialRotation, reason string) error {
+	if err := s.agentRepo.ClearPendingAPIKey(ctx, rotation.AgentID, rotation.APIKey()); err != nil {
+		return err
+	}
+
+	event := models.NewAgentSystemEvent(models.SystemEventWarning, models.SystemEventCategoryAgent,
+		models.SystemEventCredentialsFailed, rotation.AgentID,
+		fmt.Sprintf("Agent %d could not rotate its credentials: %s", rotation.AgentID, reason))
+	s.RecordSystemEvent(ctx, event)
+	return nil
+}
+
+// CredentialRotationGrace returns how long a replaced API key keeps working
+func (s *AgentService) CredentialRotationGrace(ctx context.Context) time.Duration {
+	if minutes, ok := positiveIntSetting(ctx, s.systemSettingsRepo, "agent_credential_rotation_grace_minutes"); ok {
+		return time.Duration(minutes) * time.Minute
+	}
+	return defaultCredentialRotationGrace
+}
+
+// CredentialRotationAge returns the age of API keys to rotate automatically,
+// or 0 when keys are only rotated on request
+func (s *AgentService) CredentialRotationAge(ctx context.Context) time.Duration {
+	if days, ok := positiveIntSetting(ctx, s.systemSettingsRepo, "agent_credential_rotation_days"); ok {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return 0
+}
+
+// ListAgentsWithKeysIssuedBefore returns the agents whose API key is older
+// than the given time
+func (s *AgentService) ListAgentsWithKeysIssuedBefore(ctx context.Context, before time.Time) ([]int, error) {
+	return s.agentRepo.ListAPIKeysIssuedBefore(ctx, before)
+}
+
+// promoteRotatedAPIKey completes a rotation the agent authenticated with
+// before its confirmation arrived, such as when the connection dropped right
+// after it stored the key. Authenticating with a replaced key in its grace
+// period changes nothing.
+func (s *AgentService) promoteRotatedAPIKey(ctx context.Context, agent *models.Agent, apiKey string) {
+	if agent.APIKey.String == apiKey {
+		return
+	}
+	expiresAt := time.Now().Add(s.CredentialRotationGrace(ctx))
+	promoted, err := s.agentRepo.PromotePendingAPIKey(ctx, agent.ID, apiKey, expiresAt)
+	if err != nil {
+		debug.Error("Failed to promote rotated API key of agent %d: %v", agent.ID, err)
+		return
+	}
+	if promoted {
+		debug.Info("Agent %d authenticated with its rotated API key, completing the rotation", agent.ID)
+		agent.APIKey.String = apiKey
+		agent.APIKeyCreatedAt.Time, agent.APIKeyCreatedAt.Valid = time.Now(), true
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// placementWaitingPrefix starts the message recorded on a job whose pinned or
+// excluded agents leave no online agent to run it, so it can be cleared again
+// once an eligible agent picks the job up
+const placementWaitingPrefix = "Waiting for an eligible agent: "
+
+// placementWaitingReason returns why no online agent may run a job with the
+// placement, or "" if at least one of them may
+func placementWaitingReason(placement models.AgentPlacement, onlineAgentIDs []int) string {
+	for _, id := range onlineAgentIDs {
+		if placement.Allows(id) {
+			return ""
+		}
+	}
+	if len(placement.PinnedAgentIDs) > 0 {
+		return fmt.Sprintf("%sthe job is pinned to agents %s and none of them is online",
+			placementWaitingPrefix, placement.PinnedAgentIDs)
+	}
+	return fmt.Sprintf("%severy online agent is excluded (excluded agents %s)",
+		placementWaitingPrefix, placement.ExcludedAgentIDs)
+}
+
+// admitJobPlacement reports whether the agent may run the job under its agent
+// pinning and exclusion. When no online agent may run it, the reason is
+// recorded on the job; it is cleared once an eligible agent takes the job.
+func (s *JobExecutionService) admitJobPlacement(ctx context.Context, job *models.JobExecution, agentID int) bool {
+	waiting := job.ErrorMessage != nil && strings.HasPrefix(*job.ErrorMessage, placementWaitingPrefix)
+	if job.AgentPlacement.Allows(agentID) {
+		if waiting {
+			if err := s.jobExecRepo.ClearError(ctx, job.ID); err != nil {
+				debug.Error("Failed to clear placement reason on job %s: %v", job.ID, err)
+			}
+		}
+		return true
+	}
+
+	agents, err := s.agentRepo.List(ctx, map[string]interface{}{"status": models.AgentStatusActive})
+	if err != nil {
+		debug.Warning("Failed to list agents for placement check of job %s: %v", job.ID, err)
+		return false
+	}
+	online := make([]int, 0, len(agents))
+	for _, agent := range agents {
+		if agent.IsEnabled {
+			online = append(online, agent.ID)
+		}
+	}
+
+	reason := placementWaitingReason(job.AgentPlacement, online)
+	if reason != "" && (!waiting || *job.ErrorMessage != reason) {
+		debug.Log("No eligible agent for job", map[string]interface{}{
+			"job_id": job.ID,
+			"reason": reason,
+		})
+		if err := s.jobExecRepo.UpdateErrorMessage(ctx, job.ID, reason); err != nil {
+			debug.Error("Failed to record placement reason on job %s: %v", job.ID, err)
+		}
+	}
+	return false
+}
+
+// isCloudBurstAgent reports whether the agent was registered by a cloud burst
+// instance. Those agents only run jobs that allow cloud burst.
+func (s *JobExecutionService) isCloudBurstAgent(ctx context.Context, agentID int) bool {
+	agent, err := s.agentRepo.GetByID(ctx, agentID)
+	if err != nil {
+		debug.Warning("Failed to get agent %d for cloud burst check: %v", agentID, err)
+		return false
+	}
+	return agent.IsCloudBurst()
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlacementWaitingReason(t *testing.T) {
+	pinned := models.AgentPlacement{PinnedAgentIDs: models.AgentIDList{3, 5}}
+	assert.Empty(t, placementWaitingReason(pinned, []int{1, 5}))
+	assert.Equal(t, "Waiting for an eligible agent: the job is pinned to agents 3, 5 and none of them is online",
+		placementWaitingReason(pinned, []int{1, 2}))
+
+	excluded := models.AgentPlacement{ExcludedAgentIDs: models.AgentIDList{1, 2}}
+	assert.Empty(t, placementWaitingReason(excluded, []int{1, 2, 3}))
+	assert.Equal(t, "Waiting for an eligible agent: every online agent is excluded (excluded agents 1, 2)",
+		placementWaitingReason(excluded, []int{1, 2}))
+}
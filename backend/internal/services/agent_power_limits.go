@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// agentSchedulingEnabled reports whether the agent scheduling system, which
+// includes low-power windows and power budgets, is enabled globally
+func (s *JobExecutionService) agentSchedulingEnabled(ctx context.Context) bool {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, "agent_scheduling_enabled")
+	return err == nil && setting.Value != nil && *setting.Value == "true"
+}
+
+// AgentPriorityLimit returns the highest job priority the agent may run right
+// now, or nil if it is not inside a low-power window
+func (s *JobExecutionService) AgentPriorityLimit(ctx context.Context, agentID int) (*int, error) {
+	if !s.agentSchedulingEnabled(ctx) {
+		return nil, nil
+	}
+
+	window, err := s.scheduleRepo.GetActivePowerWindow(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if window == nil {
+		return nil, nil
+	}
+	return &window.MaxPriority, nil
+}
+
+// isPowerBudgetExhausted reports whether the agent has used up today's power budget
+func (s *JobExecutionService) isPowerBudgetExhausted(ctx context.Context, agentID int) (bool, error) {
+	if !s.agentSchedulingEnabled(ctx) {
+		return false, nil
+	}
+
+	budget, err := s.scheduleRepo.GetPowerBudget(ctx, agentID)
+	if err != nil || budget == nil {
+		return false, err
+	}
+
+	if budget.IsExhausted() {
+		debug.Log("Agent has used its daily power budget", map[string]interface{}{
+			"agent_id":        agentID,
+			"used_today_wh":   budget.UsedTodayWh,
+			"daily_budget_wh": budget.DailyBudgetWh,
+		})
+		return true, nil
+	}
+	return false, nil
+}
+
+// GetNextJobWithWorkForAgent returns the next job with available work that the
+// agent may run, skipping jobs pinned to other agents or excluding this one,
+// jobs above its priority limit during a low-power window, jobs whose attack
+// does not fit in its device memory and jobs held back by the per user and per
+// client concurrency caps. Background jobs are only returned
+// once the agent has been idle long enough.
+func (s *JobExecutionService) GetNextJobWithWorkForAgent(ctx context.Context, agentID int) (*models.JobExecutionWithWork, error) {
+	next, err := s.nextJobWithWorkForAgent(ctx, agentID)
+	if err != nil || next == nil || !next.IsBackground {
+		return next, err
+	}
+
+	// Background jobs are ordered last, so the agent has no normal work to do
+	idle, err := s.agentIdleForBackground(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if !idle {
+		debug.Log("Agent has not been idle long enough for background jobs", map[string]interface{}{
+			"agent_id": agentID,
+			"job_id":   next.ID,
+		})
+		schedulingRecorderFrom(ctx).skipJob(next.ID, agentID, models.SchedulingReasonBackgroundNotIdle)
+		return nil, nil
+	}
+	return next, nil
+}
+
+// nextJobWithWorkForAgent returns the first job with work that the job's agent
+// placement, the agent's power window, device memory and the concurrency caps admit
+func (s *JobExecutionService) nextJobWithWorkForAgent(ctx context.Context, agentID int) (*models.JobExecutionWithWork, error) {
+	limit, err := s.AgentPriorityLimit(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check power window: %w", err)
+	}
+	caps := s.jobConcurrencyCaps(ctx)
+	cloudBurst := s.isCloudBurstAgent(ctx, agentID)
+	if deviceMB, _ := s.deviceMemoryLimits(ctx, agentID); limit == nil && deviceMB == 0 && !caps.enabled() && !cloudBurst {
+		next, err := s.GetNextJobWithWork(ctx)
+		if err != nil || next == nil || s.admitJobPlacement(ctx, &next.JobExecution, agentID) {
+			return next, err
+		}
+		// The first job is not for this agent, look further down the queue
+	}
+
+	jobsWithWork, err := s.jobExecRepo.GetJobsWithPendingWork(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs with pending work: %w", err)
+	}
+	rec := schedulingRecorderFrom(ctx)
+	rec.considerJobs(jobsWithWork)
+
+	var running *models.RunningJobCounts
+	if caps.enabled() {
+		running, err = s.jobExecRepo.GetRunningJobCounts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count running jobs: %w", err)
+		}
+	}
+
+	// Jobs are ordered by priority DESC, so the first one the agent may run is next
+	for i := range jobsWithWork {
+		jobID := jobsWithWork[i].ID
+		if limit != nil && jobsWithWork[i].Priority > *limit {
+			rec.skipJob(jobID, agentID, models.SchedulingReasonPowerWindow)
+			continue
+		}
+		if cloudBurst && !jobsWithWork[i].AllowCloudBurst {
+			rec.skipJob(jobID, agentID, models.SchedulingReasonCloudBurst)
+			continue
+		}
+		if !s.admitJobPlacement(ctx, &jobsWithWork[i].JobExecution, agentID) {
+			rec.skipJob(jobID, agentID, models.SchedulingReasonPlacement)
+			continue
+		}
+		if caps.enabled() && !s.admitJobConcurrency(ctx, &jobsWithWork[i].JobExecution, caps, running) {
+			rec.skipJob(jobID, agentID, models.SchedulingReasonConcurrencyCap)
+			continue
+		}
+		if s.admitJobForAgent(ctx, &jobsWithWork[i].JobExecution, agentID) {
+			return &jobsWithWork[i], nil
+		}
+		rec.skipJob(jobID, agentID, models.SchedulingReasonDeviceMemory)
+	}
+
+	debug.Log("No job with work is within the agent's placement, priority limit, device memory and concurrency caps", map[string]interface{}{
+		"agent_id":     agentID,
+		"max_priority": limit,
+		"cloud_burst":  cloudBurst,
+	})
+	return nil, nil
+}
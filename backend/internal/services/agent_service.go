@@ -7,11 +7,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
@@ -27,6 +29,11 @@ type AgentService struct {
 	deviceRepo      *repository.AgentDeviceRepository
 	jobTaskRepo     *repository.JobTaskRepository
 	jobExecutionRepo *repository.JobExecutionRepository
+	heartbeatRepo   *repository.AgentHeartbeatRepository
+	crashReportRepo *repository.AgentCrashReportRepository
+	systemSettingsRepo *repository.SystemSettingsRepository
+	benchmarkRepo   *repository.BenchmarkRepository
+	systemEvents    *SystemEventService
 	tokens          map[string]downloadToken
 	tokenMutex      sync.RWMutex
 }
@@ -38,6 +45,7 @@ type downloadToken struct {
 
 // NewAgentService creates a new instance of AgentService
 func NewAgentService(agentRepo *repository.AgentRepository, voucherRepo *repository.ClaimVoucherRepository, fileRepo *repository.FileRepository, deviceRepo *repository.AgentDeviceRepository, jobTaskRepo *repository.JobTaskRepository, jobExecutionRepo *repository.JobExecutionRepository) *AgentService {
+	dbWrapper := &db.DB{DB: agentRepo.GetDB()}
 	return &AgentService{
 		agentRepo:        agentRepo,
 		voucherRepo:      voucherRepo,
@@ -45,6 +53,11 @@ func NewAgentService(agentRepo *repository.AgentRepository, voucherRepo *reposit
 		deviceRepo:       deviceRepo,
 		jobTaskRepo:      jobTaskRepo,
 		jobExecutionRepo: jobExecutionRepo,
+		heartbeatRepo:    repository.NewAgentHeartbeatRepository(dbWrapper),
+		crashReportRepo:  repository.NewAgentCrashReportRepository(dbWrapper),
+		systemSettingsRepo: repository.NewSystemSettingsRepository(dbWrapper),
+		benchmarkRepo:    repository.NewBenchmarkRepository(dbWrapper),
+		systemEvents:     NewSystemEventService(repository.NewSystemEventRepository(dbWrapper), repository.NewSystemSettingsRepository(dbWrapper)),
 		tokens:           make(map[string]downloadToken),
 	}
 }
@@ -95,9 +108,12 @@ func (s *AgentService) RegisterAgent(ctx context.Context, claimCode, hostname st
 		return nil, fmt.Errorf("invalid claim code")
 	}
 
-	if !voucher.IsValid() {
-		debug.Error("Claim code is not active")
-		return nil, fmt.Errorf("claim code is not active")
+	if err := voucher.Validate(time.Now()); err != nil {
+		debug.Error("Claim code rejected: %v", err)
+		if recordErr := s.voucherRepo.RecordFailedAttempt(ctx, normalizedCode, err.Error()); recordErr != nil {
+			debug.Warning("Failed to record claim code attempt: %v", recordErr)
+		}
+		return nil, err
 	}
 	debug.Info("Claim code validated successfully")
 
@@ -155,12 +171,14 @@ func (s *AgentService) RegisterAgent(ctx context.Context, claimCode, hostname st
 	}
 	debug.Info("Successfully created agent with ID: %d", agent.ID)
 
-	// Mark claim code as used if it's a single-use code
-	if !voucher.IsContinuous {
-		if err := s.MarkClaimCodeUsed(ctx, claimCode, agent.ID); err != nil {
-			debug.Warning("Failed to mark claim code as used: %v", err)
-			// Continue anyway as the agent is already created
+	// Count the registration against the voucher, it may have been used up
+	// by another agent registering at the same time
+	if err := s.redeemClaimVoucher(ctx, voucher, agent); err != nil {
+		debug.Error("Failed to redeem claim code: %v", err)
+		if deleteErr := s.agentRepo.Delete(ctx, agent.ID); deleteErr != nil {
+			debug.Error("Failed to delete agent after claim code redemption failure: %v", deleteErr)
 		}
+		return nil, err
 	}
 
 	return agent, nil
@@ -194,9 +212,12 @@ func (s *AgentService) RegisterAgentWithVersion(ctx context.Context, claimCode,
 		return nil, fmt.Errorf("invalid claim code")
 	}
 
-	if !voucher.IsValid() {
-		debug.Error("Claim code is not active")
-		return nil, fmt.Errorf("claim code is not active")
+	if err := voucher.Validate(time.Now()); err != nil {
+		debug.Error("Claim code rejected: %v", err)
+		if recordErr := s.voucherRepo.RecordFailedAttempt(ctx, normalizedCode, err.Error()); recordErr != nil {
+			debug.Warning("Failed to record claim code attempt: %v", recordErr)
+		}
+		return nil, err
 	}
 	debug.Info("Claim code validated successfully")
 
@@ -260,18 +281,22 @@ func (s *AgentService) RegisterAgentWithVersion(ctx context.Context, claimCode,
 	}
 	debug.Info("Successfully created agent with ID: %d and version: %s", agent.ID, agent.Version)
 
-	// Mark claim code as used if it's a single-use code
-	if !voucher.IsContinuous {
-		if err := s.MarkClaimCodeUsed(ctx, claimCode, agent.ID); err != nil {
-			debug.Warning("Failed to mark claim code as used: %v", err)
-			// Continue anyway as the agent is already created
+	// Count the registration against the voucher, it may have been used up
+	// by another agent registering at the same time
+	if err := s.redeemClaimVoucher(ctx, voucher, agent); err != nil {
+		debug.Error("Failed to redeem claim code: %v", err)
+		if deleteErr := s.agentRepo.Delete(ctx, agent.ID); deleteErr != nil {
+			debug.Error("Failed to delete agent after claim code redemption failure: %v", deleteErr)
 		}
+		return nil, err
 	}
 
 	return agent, nil
 }
 
-// MarkClaimCodeUsed marks a claim code as used by an agent after successful connection
+// MarkClaimCodeUsed marks a claim code as used by an agent after successful
+// connection. Registration already redeems the code, so this only counts the
+// agent if it has not been counted yet.
 func (s *AgentService) MarkClaimCodeUsed(ctx context.Context, claimCode string, agentID int) error {
 	// Normalize claim code
 	normalizedCode := strings.ToUpper(strings.ReplaceAll(claimCode, "-", ""))
@@ -282,19 +307,48 @@ func (s *AgentService) MarkClaimCodeUsed(ctx context.Context, claimCode string,
 		return fmt.Errorf("invalid claim code")
 	}
 
-	// Only mark as used if it's a single-use code
-	if !voucher.IsContinuous {
-		if err := s.voucherRepo.UseByAgent(ctx, normalizedCode, agentID); err != nil {
-			debug.Error("failed to mark voucher as used: %v", err)
-			return fmt.Errorf("failed to mark voucher as used: %v", err)
-		}
-		// Deactivate single-use vouchers after use
-		if err := s.voucherRepo.Deactivate(ctx, normalizedCode); err != nil {
-			debug.Error("failed to deactivate voucher: %v", err)
-			return fmt.Errorf("failed to deactivate voucher: %v", err)
-		}
-		debug.Info("Successfully marked single-use claim code as used for agent %d", agentID)
+	redeemed, err := s.voucherRepo.RedeemedByAgent(ctx, normalizedCode, agentID)
+	if err != nil {
+		return err
+	}
+	if redeemed {
+		return nil
+	}
+
+	agent, err := s.agentRepo.GetByID(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to get agent: %w", err)
+	}
+
+	if err := s.redeemClaimVoucher(ctx, voucher, agent); err != nil {
+		debug.Error("failed to mark voucher as used: %v", err)
+		return fmt.Errorf("failed to mark voucher as used: %w", err)
+	}
+	debug.Info("Successfully marked claim code as used for agent %d", agentID)
+
+	return nil
+}
+
+// redeemClaimVoucher counts a registration against the voucher and tags the
+// agent with the voucher's labels
+func (s *AgentService) redeemClaimVoucher(ctx context.Context, voucher *models.ClaimVoucher, agent *models.Agent) error {
+	if err := s.voucherRepo.Redeem(ctx, voucher.Code, agent.ID); err != nil {
+		return err
+	}
+
+	if len(voucher.Labels) == 0 {
+		return nil
+	}
+
+	labels, err := models.NormalizeAgentLabels(append(append([]string{}, agent.Labels...), voucher.Labels...))
+	if err != nil {
+		return err
+	}
+	if err := s.agentRepo.UpdateLabels(ctx, agent.ID, labels); err != nil {
+		return fmt.Errorf("failed to apply voucher labels: %w", err)
 	}
+	agent.Labels = labels
+	debug.Info("Applied voucher labels %v to agent %d", voucher.Labels, agent.ID)
 
 	return nil
 }
@@ -352,7 +406,39 @@ func (s *AgentService) GetAgent(ctx context.Context, id int) (*models.Agent, err
 // ListAgents retrieves all agents with optional filters
 func (s *AgentService) ListAgents(ctx context.Context, filters map[string]interface{}) ([]models.Agent, error) {
 	debug.Info("Listing agents with filters: %v", filters)
-	return s.agentRepo.List(ctx, filters)
+	agents, err := s.agentRepo.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// Performance scores are best effort; the list is still useful without them
+	if err := s.attachPerformance(ctx, agents); err != nil {
+		debug.Warning("Failed to attach agent performance scores: %v", err)
+	}
+	return agents, nil
+}
+
+// attachPerformance scores each agent's recent task speeds against its
+// benchmarks and flags agents persistently running below them
+func (s *AgentService) attachPerformance(ctx context.Context, agents []models.Agent) error {
+	if len(agents) == 0 {
+		return nil
+	}
+	settings, err := s.systemSettingsRepo.GetAgentPerformanceSettings(ctx)
+	if err != nil {
+		return err
+	}
+	performance, err := s.benchmarkRepo.GetAgentPerformance(ctx, settings.WindowTasks)
+	if err != nil {
+		return err
+	}
+	for i := range agents {
+		if p, ok := performance[agents[i].ID]; ok {
+			settings.Flag(p)
+			agents[i].Performance = p
+		}
+	}
+	return nil
 }
 
 // DeleteAgent deletes an agent by ID
@@ -451,6 +537,26 @@ func (s *AgentService) UpdateAgentStatus(ctx context.Context, id int, status str
 	return s.agentRepo.UpdateStatus(ctx, id, status, lastError)
 }
 
+// MarkAgentOffline marks a disconnected agent inactive and announces it on the event bus
+func (s *AgentService) MarkAgentOffline(ctx context.Context, id int, reason string) error {
+	if err := s.agentRepo.UpdateStatus(ctx, id, models.AgentStatusInactive, nil); err != nil {
+		return err
+	}
+	if err := events.Publish(ctx, s.agentRepo.GetDB(), events.AgentOffline, events.AgentOfflinePayload{AgentID: id, Reason: reason}); err != nil {
+		debug.Error("Failed to publish agent offline event for agent %d: %v", id, err)
+	}
+	event := models.NewAgentSystemEvent(models.SystemEventWarning, models.SystemEventCategoryAgent, models.SystemEventAgentDisconnected,
+		id, fmt.Sprintf("Agent %d disconnected", id))
+	event.Details = map[string]interface{}{"reason": reason}
+	s.systemEvents.Record(ctx, event)
+	return nil
+}
+
+// RecordSystemEvent adds an operational event to the system event log
+func (s *AgentService) RecordSystemEvent(ctx context.Context, event *models.SystemEvent) {
+	s.systemEvents.Record(ctx, event)
+}
+
 // UpdateAgentVersion updates an agent's version
 func (s *AgentService) UpdateAgentVersion(ctx context.Context, id int, version string) error {
 	// Don't update if version is empty
@@ -463,6 +569,46 @@ func (s *AgentService) UpdateAgentVersion(ctx context.Context, id int, version s
 	return s.agentRepo.UpdateVersion(ctx, id, version)
 }
 
+// RecordRuntimeVersions stores the GPU driver and hashcat binary versions an
+// agent reported. When either changed, the agent's benchmarks no longer
+// describe its speed, so they are deleted and the scheduler benchmarks the
+// agent again before its next assignment.
+func (s *AgentService) RecordRuntimeVersions(ctx context.Context, agentID int, driverVersion, binaryVersion string) error {
+	if driverVersion == "" && binaryVersion == "" {
+		return nil
+	}
+
+	prevDriver, prevBinary, err := s.agentRepo.UpdateRuntimeVersions(ctx, agentID, driverVersion, binaryVersion)
+	if err != nil {
+		return err
+	}
+
+	driverChanged := runtimeVersionChanged(prevDriver, driverVersion)
+	binaryChanged := runtimeVersionChanged(prevBinary, binaryVersion)
+	if !driverChanged && !binaryChanged {
+		return nil
+	}
+
+	deleted, err := s.benchmarkRepo.DeleteAgentBenchmarks(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate benchmarks of agent %d: %w", agentID, err)
+	}
+	if driverChanged {
+		debug.Info("Agent %d driver changed from %s to %s, invalidated %d benchmarks", agentID, prevDriver, driverVersion, deleted)
+	}
+	if binaryChanged {
+		debug.Info("Agent %d hashcat binary changed from version %s to %s, invalidated %d benchmarks", agentID, prevBinary, binaryVersion, deleted)
+	}
+	return nil
+}
+
+// runtimeVersionChanged reports whether a reported version replaces a
+// different known one. The first report and agents that do not report a
+// version never count as a change.
+func runtimeVersionChanged(previous, reported string) bool {
+	return previous != "" && reported != "" && previous != reported
+}
+
 // UpdateAgentMetadata updates an agent's metadata
 func (s *AgentService) UpdateAgentMetadata(ctx context.Context, id int, metadata map[string]string) error {
 	return s.agentRepo.UpdateMetadata(ctx, id, metadata)
@@ -493,7 +639,41 @@ func (s *AgentService) UpdateAgentSyncStatus(ctx context.Context, id int, status
 		agent.SyncCompletedAt = sql.NullTime{Time: now, Valid: true}
 	}
 
-	return s.agentRepo.Update(ctx, agent)
+	if err := s.agentRepo.Update(ctx, agent); err != nil {
+		return err
+	}
+	s.RecordSyncEvent(ctx, agent)
+	return nil
+}
+
+// RecordSyncEvent adds the outcome of an agent's file sync to the system
+// event log, if the sync finished
+func (s *AgentService) RecordSyncEvent(ctx context.Context, agent *models.Agent) {
+	switch agent.SyncStatus {
+	case models.AgentSyncStatusCompleted:
+		event := models.NewAgentSystemEvent(models.SystemEventInfo, models.SystemEventCategorySync, models.SystemEventSyncCompleted,
+			agent.ID, fmt.Sprintf("Agent %d completed file sync", agent.ID))
+		event.Details = map[string]interface{}{"files_synced": agent.FilesSynced}
+		addSyncStart(event, agent)
+		s.systemEvents.Record(ctx, event)
+	case models.AgentSyncStatusFailed:
+		event := models.NewAgentSystemEvent(models.SystemEventError, models.SystemEventCategorySync, models.SystemEventSyncFailed,
+			agent.ID, fmt.Sprintf("Agent %d failed file sync: %s", agent.ID, agent.SyncError.String))
+		addSyncStart(event, agent)
+		s.systemEvents.Record(ctx, event)
+	}
+}
+
+// addSyncStart records when a finished sync started, so agent timelines can
+// show the time it took
+func addSyncStart(event *models.SystemEvent, agent *models.Agent) {
+	if !agent.SyncStartedAt.Valid {
+		return
+	}
+	if event.Details == nil {
+		event.Details = make(map[string]interface{})
+	}
+	event.Details["started_at"] = agent.SyncStartedAt.Time.Format(time.RFC3339)
 }
 
 // UpdateLastSeen updates the last seen timestamp for an agent
@@ -612,6 +792,7 @@ func (s *AgentService) GetByAPIKey(ctx context.Context, apiKey string) (*models.
 	if err != nil {
 		return nil, fmt.Errorf("failed to get agent by API key: %w", err)
 	}
+	s.promoteRotatedAPIKey(ctx, agent, apiKey)
 
 	// Update last used timestamp (use specialized method to avoid sync_status errors)
 	now := time.Now()
@@ -653,6 +834,111 @@ func (s *AgentService) UpdateHeartbeat(ctx context.Context, agentID int) error {
 	return nil
 }
 
+// defaultHeartbeatHistorySize is used when agent_heartbeat_history_size is missing or invalid
+const defaultHeartbeatHistorySize = 120
+
+// heartbeatHistorySize returns how many heartbeats are kept per agent
+func (s *AgentService) heartbeatHistorySize(ctx context.Context) int {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, "agent_heartbeat_history_size")
+	if err != nil || setting.Value == nil {
+		return defaultHeartbeatHistorySize
+	}
+	size, err := strconv.Atoi(*setting.Value)
+	if err != nil || size < 1 {
+		return defaultHeartbeatHistorySize
+	}
+	return size
+}
+
+// RecordHeartbeat stores a heartbeat sample, keeping only the most recent ones per agent
+func (s *AgentService) RecordHeartbeat(ctx context.Context, heartbeat *models.AgentHeartbeat) error {
+	if err := s.heartbeatRepo.Create(ctx, heartbeat, s.heartbeatHistorySize(ctx)); err != nil {
+		return fmt.Errorf("failed to record heartbeat: %w", err)
+	}
+	return nil
+}
+
+// GetHeartbeatHistory returns the agent's stored heartbeats and whether its
+// connection has been flapping within that window
+func (s *AgentService) GetHeartbeatHistory(ctx context.Context, agentID int) (*models.AgentHeartbeatHistory, error) {
+	heartbeats, err := s.heartbeatRepo.ListRecent(ctx, agentID, s.heartbeatHistorySize(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// The reconnect counter is cumulative per agent process, so a drop means
+	// the agent restarted, which counts as one reconnect on its own
+	reconnects := 0
+	for i := 1; i < len(heartbeats); i++ {
+		prev, cur := heartbeats[i-1].ReconnectCount, heartbeats[i].ReconnectCount
+		if cur >= prev {
+			reconnects += cur - prev
+		} else {
+			reconnects += 1 + cur
+		}
+	}
+
+	return &models.AgentHeartbeatHistory{
+		AgentID:    agentID,
+		Heartbeats: heartbeats,
+		Reconnects: reconnects,
+		Flapping:   reconnects >= models.FlappingReconnectThreshold,
+	}, nil
+}
+
+// RecordCrashReport stores a crash bundle sent by an agent for an administrator
+// to review. The bundle is kept as sent, only the fields listed are read.
+func (s *AgentService) RecordCrashReport(ctx context.Context, agentID int, bundle json.RawMessage) error {
+	var header models.CrashReportBundle
+	if err := json.Unmarshal(bundle, &header); err != nil {
+		return fmt.Errorf("failed to decode crash report: %w", err)
+	}
+	if header.ID == "" || header.Kind == "" {
+		return fmt.Errorf("crash report without id or kind")
+	}
+
+	report := &models.AgentCrashReport{
+		AgentID:    agentID,
+		ReportID:   header.ID,
+		Kind:       header.Kind,
+		Summary:    header.Summary,
+		OccurredAt: header.OccurredAt,
+		Bundle:     bundle,
+	}
+	if report.OccurredAt.IsZero() {
+		report.OccurredAt = time.Now()
+	}
+	if header.TaskID != "" {
+		if taskID, err := uuid.Parse(header.TaskID); err == nil {
+			report.TaskID = &taskID
+		}
+	}
+
+	created, err := s.crashReportRepo.Create(ctx, report)
+	if err != nil {
+		return fmt.Errorf("failed to record crash report: %w", err)
+	}
+	if created {
+		debug.Warning("Agent %d reported a crash (%s): %s", agentID, report.Kind, report.Summary)
+	}
+	return nil
+}
+
+// ListCrashReports returns the most recent crash reports of all agents
+func (s *AgentService) ListCrashReports(ctx context.Context, unreviewedOnly bool, limit int) ([]models.AgentCrashReport, error) {
+	return s.crashReportRepo.List(ctx, unreviewedOnly, limit)
+}
+
+// GetCrashReport returns a crash report with its full bundle
+func (s *AgentService) GetCrashReport(ctx context.Context, id int64) (*models.AgentCrashReport, error) {
+	return s.crashReportRepo.GetByID(ctx, id)
+}
+
+// MarkCrashReportReviewed clears a crash report from the review queue
+func (s *AgentService) MarkCrashReportReviewed(ctx context.Context, id int64, reviewedBy uuid.UUID) error {
+	return s.crashReportRepo.MarkReviewed(ctx, id, reviewedBy)
+}
+
 // GetFiles retrieves files of specified types and category from the database
 func (s *AgentService) GetFiles(ctx context.Context, fileTypes []string, category string) ([]repository.FileInfo, error) {
 	debug.Info("Getting files of types %v, category %s", fileTypes, category)
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeVersionChanged(t *testing.T) {
+	assert.True(t, runtimeVersionChanged("535.129.03", "550.54.14"))
+	assert.True(t, runtimeVersionChanged("3", "4"))
+	assert.False(t, runtimeVersionChanged("535.129.03", "535.129.03"))
+	// First report
+	assert.False(t, runtimeVersionChanged("", "535.129.03"))
+	// Not reported, e.g. before device detection or by an older agent
+	assert.False(t, runtimeVersionChanged("535.129.03", ""))
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+)
+
+// correlateAccounts links the accounts of a report's hashlists: usernames
+// found in several hashlists, and passwords cracked in several hashlists
+// (reuse chains). Usernames are compared ignoring case and domain, so a local
+// administrator in a workstation dump matches the domain account.
+func (s *AnalyticsService) correlateAccounts(accounts []repository.CorrelatedAccount) *models.AccountCorrelationStats {
+	byUsername := make(map[string][]models.AccountOccurrence)
+	byPassword := make(map[string][]models.AccountOccurrence)
+	for _, account := range accounts {
+		occurrence := models.AccountOccurrence{
+			HashlistID:   account.HashlistID,
+			HashlistName: account.HashlistName,
+			Username:     account.Username,
+			Domain:       account.Domain,
+			Cracked:      account.IsCracked,
+		}
+		if account.IsCracked {
+			occurrence.Password = account.Password
+			byPassword[account.Password] = append(byPassword[account.Password], occurrence)
+		}
+		key := strings.ToLower(account.Username)
+		byUsername[key] = append(byUsername[key], occurrence)
+	}
+
+	stats := &models.AccountCorrelationStats{
+		Accounts:    []models.AccountCorrelation{},
+		ReuseChains: []models.ReuseChain{},
+	}
+
+	for _, occurrences := range byUsername {
+		hashlistCount := countHashlists(occurrences)
+		if hashlistCount < 2 {
+			continue
+		}
+		sortOccurrences(occurrences)
+
+		// The same password cracked in two of the account's hashlists
+		passwordHashlists := make(map[string]map[int64]bool)
+		reused := false
+		for _, occurrence := range occurrences {
+			if !occurrence.Cracked {
+				continue
+			}
+			if passwordHashlists[occurrence.Password] == nil {
+				passwordHashlists[occurrence.Password] = make(map[int64]bool)
+			}
+			passwordHashlists[occurrence.Password][occurrence.HashlistID] = true
+			if len(passwordHashlists[occurrence.Password]) > 1 {
+				reused = true
+			}
+		}
+
+		stats.Accounts = append(stats.Accounts, models.AccountCorrelation{
+			Username:       occurrences[0].Username,
+			Occurrences:    occurrences,
+			HashlistCount:  hashlistCount,
+			PasswordReused: reused,
+		})
+		if reused {
+			stats.ReusedAccounts++
+		}
+	}
+	stats.SharedAccounts = len(stats.Accounts)
+
+	for password, occurrences := range byPassword {
+		hashlistCount := countHashlists(occurrences)
+		if hashlistCount < 2 {
+			continue
+		}
+		sortOccurrences(occurrences)
+
+		usernames := make(map[string]bool)
+		for _, occurrence := range occurrences {
+			usernames[strings.ToLower(occurrence.Username)] = true
+		}
+		stats.ReuseChains = append(stats.ReuseChains, models.ReuseChain{
+			Password:      password,
+			Accounts:      occurrences,
+			HashlistCount: hashlistCount,
+			AccountCount:  len(usernames),
+		})
+	}
+
+	// Accounts reusing a password first, then those in the most hashlists
+	sort.Slice(stats.Accounts, func(i, j int) bool {
+		a, b := stats.Accounts[i], stats.Accounts[j]
+		if a.PasswordReused != b.PasswordReused {
+			return a.PasswordReused
+		}
+		if a.HashlistCount != b.HashlistCount {
+			return a.HashlistCount > b.HashlistCount
+		}
+		return strings.ToLower(a.Username) < strings.ToLower(b.Username)
+	})
+
+	// Chains spanning the most hashlists first, then those with the most accounts
+	sort.Slice(stats.ReuseChains, func(i, j int) bool {
+		a, b := stats.ReuseChains[i], stats.ReuseChains[j]
+		if a.HashlistCount != b.HashlistCount {
+			return a.HashlistCount > b.HashlistCount
+		}
+		if a.AccountCount != b.AccountCount {
+			return a.AccountCount > b.AccountCount
+		}
+		return a.Password < b.Password
+	})
+
+	return stats
+}
+
+// countHashlists returns the number of distinct hashlists of the occurrences
+func countHashlists(occurrences []models.AccountOccurrence) int {
+	hashlists := make(map[int64]bool)
+	for _, occurrence := range occurrences {
+		hashlists[occurrence.HashlistID] = true
+	}
+	return len(hashlists)
+}
+
+// sortOccurrences orders occurrences by hashlist, then domain and username
+func sortOccurrences(occurrences []models.AccountOccurrence) {
+	sort.Slice(occurrences, func(i, j int) bool {
+		a, b := occurrences[i], occurrences[j]
+		if a.HashlistID != b.HashlistID {
+			return a.HashlistID < b.HashlistID
+		}
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		return a.Username < b.Username
+	})
+}
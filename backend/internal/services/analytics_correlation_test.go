@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelateAccounts(t *testing.T) {
+	service := &AnalyticsService{}
+
+	accounts := []repository.CorrelatedAccount{
+		// jsmith reuses his password on a workstation and in the domain
+		{HashlistID: 1, HashlistName: "WS01 SAM", Username: "jsmith", IsCracked: true, Password: "Summer2024!"},
+		{HashlistID: 2, HashlistName: "CORP NTDS", Username: "JSmith", Domain: "CORP", IsCracked: true, Password: "Summer2024!"},
+		// The local administrator shares its password with a domain admin
+		{HashlistID: 1, HashlistName: "WS01 SAM", Username: "Administrator", IsCracked: true, Password: "Adm1n#Corp"},
+		{HashlistID: 2, HashlistName: "CORP NTDS", Username: "da_admin", Domain: "CORP", IsCracked: true, Password: "Adm1n#Corp"},
+		{HashlistID: 3, HashlistName: "App DB", Username: "svc_app", IsCracked: true, Password: "Adm1n#Corp"},
+		// bwhite is in two hashlists with different passwords, one not cracked
+		{HashlistID: 2, HashlistName: "CORP NTDS", Username: "bwhite", Domain: "CORP", IsCracked: true, Password: "Winter2023"},
+		{HashlistID: 3, HashlistName: "App DB", Username: "bwhite"},
+	}
+
+	stats := service.correlateAccounts(accounts)
+	require.NotNil(t, stats)
+
+	assert.Equal(t, 2, stats.SharedAccounts)
+	assert.Equal(t, 1, stats.ReusedAccounts)
+	require.Len(t, stats.Accounts, 2)
+	assert.Equal(t, "jsmith", stats.Accounts[0].Username)
+	assert.True(t, stats.Accounts[0].PasswordReused)
+	assert.Equal(t, 2, stats.Accounts[0].HashlistCount)
+	assert.Equal(t, "bwhite", stats.Accounts[1].Username)
+	assert.False(t, stats.Accounts[1].PasswordReused)
+	assert.Empty(t, stats.Accounts[1].Occurrences[1].Password)
+
+	// The widest chain comes first
+	require.Len(t, stats.ReuseChains, 2)
+	assert.Equal(t, "Adm1n#Corp", stats.ReuseChains[0].Password)
+	assert.Equal(t, 3, stats.ReuseChains[0].HashlistCount)
+	assert.Equal(t, 3, stats.ReuseChains[0].AccountCount)
+	assert.Equal(t, "Summer2024!", stats.ReuseChains[1].Password)
+	assert.Equal(t, 1, stats.ReuseChains[1].AccountCount)
+
+	empty := service.correlateAccounts(nil)
+	assert.Zero(t, empty.SharedAccounts)
+	assert.Empty(t, empty.ReuseChains)
+}
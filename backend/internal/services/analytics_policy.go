@@ -0,0 +1,138 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// Policy violation keys reported in PolicyComplianceStats.Violations
+const (
+	violationTooShort         = "too_short"
+	violationTooFewClasses    = "too_few_character_classes"
+	violationMissingLowercase = "missing_lowercase"
+	violationMissingUppercase = "missing_uppercase"
+	violationMissingNumbers   = "missing_numbers"
+	violationMissingSpecial   = "missing_special"
+	violationDictionaryWord   = "dictionary_word"
+	violationUsernameDerived  = "username_derived"
+)
+
+// policyDictionaryWords are common words a dictionary word policy rejects
+var policyDictionaryWords = []string{
+	"password", "welcome", "admin", "login", "letmein", "changeme",
+	"qwerty", "monkey", "dragon", "master", "shadow", "sunshine", "princess",
+	"football", "baseball", "iloveyou", "secret", "company", "winter",
+	"spring", "summer", "autumn", "december", "january",
+}
+
+// minPolicyWordLength skips shorter words, they match too many passwords by chance
+const minPolicyWordLength = 4
+
+// leetReplacer undoes common character substitutions so p@ssw0rd matches password
+var leetReplacer = strings.NewReplacer(
+	"@", "a", "4", "a", "3", "e", "1", "i", "!", "i", "0", "o", "$", "s", "5", "s", "7", "t",
+)
+
+// checkPolicyCompliance checks every cracked password against the report's
+// password policy. Each password counts once as compliant or non-compliant and
+// once for every rule it breaks. Returns nil when the report has no policy.
+func (s *AnalyticsService) checkPolicyCompliance(passwords []*models.Hash, policy *models.PasswordPolicy) *models.PolicyComplianceStats {
+	if policy == nil {
+		return nil
+	}
+
+	var words []string
+	if policy.DisallowDictionaryWords {
+		for _, list := range [][]string{policyDictionaryWords, policy.DictionaryWords} {
+			for _, word := range list {
+				word = strings.ToLower(strings.TrimSpace(word))
+				if len(word) >= minPolicyWordLength {
+					words = append(words, word)
+				}
+			}
+		}
+	}
+
+	compliant := 0
+	violations := make(map[string]int)
+	for _, pwd := range passwords {
+		broken := s.policyViolations(pwd, policy, words)
+		if len(broken) == 0 {
+			compliant++
+		}
+		for _, violation := range broken {
+			violations[violation]++
+		}
+	}
+
+	total := len(passwords)
+	stats := &models.PolicyComplianceStats{
+		Policy:     *policy,
+		Violations: s.mapToCategories(violations, total),
+	}
+	if total > 0 {
+		stats.Compliant = models.CategoryCount{Count: compliant, Percentage: float64(compliant) / float64(total) * 100}
+		stats.NonCompliant = models.CategoryCount{Count: total - compliant, Percentage: float64(total-compliant) / float64(total) * 100}
+	}
+	return stats
+}
+
+// policyViolations returns the rules of the policy the password breaks
+func (s *AnalyticsService) policyViolations(pwd *models.Hash, policy *models.PasswordPolicy, words []string) []string {
+	var broken []string
+
+	if len([]rune(pwd.Password)) < policy.MinLength {
+		broken = append(broken, violationTooShort)
+	}
+
+	types := s.detectCharacterTypes(pwd.Password)
+	if types.CountTypes() < policy.MinCharacterClasses {
+		broken = append(broken, violationTooFewClasses)
+	}
+	if policy.RequireLowercase && !types.HasLowercase {
+		broken = append(broken, violationMissingLowercase)
+	}
+	if policy.RequireUppercase && !types.HasUppercase {
+		broken = append(broken, violationMissingUppercase)
+	}
+	if policy.RequireNumbers && !types.HasNumbers {
+		broken = append(broken, violationMissingNumbers)
+	}
+	if policy.RequireSpecial && !types.HasSpecial {
+		broken = append(broken, violationMissingSpecial)
+	}
+
+	lower := strings.ToLower(pwd.Password)
+	normalized := leetReplacer.Replace(lower)
+
+	for _, word := range words {
+		if strings.Contains(lower, word) || strings.Contains(normalized, word) {
+			broken = append(broken, violationDictionaryWord)
+			break
+		}
+	}
+
+	if policy.DisallowUsername && pwd.Username != nil {
+		if username := policyUsername(*pwd.Username); len(username) >= 3 {
+			reversed := reverse(username)
+			if strings.Contains(lower, username) || strings.Contains(normalized, username) ||
+				strings.Contains(lower, reversed) || strings.Contains(normalized, reversed) {
+				broken = append(broken, violationUsernameDerived)
+			}
+		}
+	}
+
+	return broken
+}
+
+// policyUsername strips a DOMAIN\ prefix or @domain suffix and lowercases the username
+func policyUsername(username string) string {
+	if i := strings.LastIndex(username, `\`); i >= 0 {
+		username = username[i+1:]
+	}
+	if i := strings.Index(username, "@"); i > 0 {
+		username = username[:i]
+	}
+	return strings.ToLower(username)
+}
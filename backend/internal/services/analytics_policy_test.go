@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPolicyCompliance(t *testing.T) {
+	service := &AnalyticsService{}
+
+	policy := &models.PasswordPolicy{
+		MinLength:               10,
+		MinCharacterClasses:     3,
+		DisallowDictionaryWords: true,
+		DictionaryWords:         []string{"acme"},
+		DisallowUsername:        true,
+	}
+
+	jsmith, domainJsmith := "jsmith", `CORP\jsmith`
+	passwords := []*models.Hash{
+		{Password: "Tr0ub4dor&3x", Username: &jsmith},        // compliant
+		{Password: "Short1!"},                                // too short
+		{Password: "P@ssw0rd2024!"},                          // dictionary word after leet normalization
+		{Password: "Acme-Winter-99"},                         // custom and built-in dictionary word, counted once
+		{Password: "Jsmith#2024xx", Username: &domainJsmith}, // username derived
+		{Password: "alllowercaseletters"},                    // too few character classes
+	}
+
+	result := service.checkPolicyCompliance(passwords, policy)
+
+	require.NotNil(t, result)
+	assert.Equal(t, 1, result.Compliant.Count)
+	assert.Equal(t, 5, result.NonCompliant.Count)
+	assert.InDelta(t, 16.67, result.Compliant.Percentage, 0.01)
+	assert.Equal(t, 1, result.Violations["too_short"].Count)
+	assert.Equal(t, 2, result.Violations["dictionary_word"].Count)
+	assert.Equal(t, 1, result.Violations["username_derived"].Count)
+	assert.Equal(t, 1, result.Violations["too_few_character_classes"].Count)
+
+	assert.Nil(t, service.checkPolicyCompliance(passwords, nil))
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	assert.NoError(t, (&models.PasswordPolicy{MinLength: 14, MinCharacterClasses: 3}).Validate())
+	assert.Error(t, (&models.PasswordPolicy{MinLength: -1}).Validate())
+	assert.Error(t, (&models.PasswordPolicy{MinCharacterClasses: 5}).Validate())
+}
@@ -61,6 +61,12 @@ func (s *AnalyticsService) GenerateAnalytics(ctx context.Context, reportID uuid.
 		return fmt.Errorf("failed to get cracked passwords with hashlists: %w", err)
 	}
 
+	// Get accounts shared between the hashlists for cross-hashlist correlation
+	correlatedAccounts, err := s.repo.GetCorrelatedAccounts(ctx, hashlistIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get correlated accounts: %w", err)
+	}
+
 	// Get job task speeds
 	speeds, err := s.repo.GetJobTaskSpeedsByHashlists(ctx, hashlistIDs)
 	if err != nil {
@@ -116,6 +122,10 @@ func (s *AnalyticsService) GenerateAnalytics(ctx context.Context, reportID uuid.
 		CustomPatterns:      s.checkCustomPatterns(passwords, report.CustomPatterns, report.ClientID.String()),
 		StrengthMetrics:     s.calculateStrengthMetrics(passwords, speeds),
 		TopPasswords:        s.getTopPasswords(passwords, 50),
+		PolicyCompliance:    s.checkPolicyCompliance(passwords, report.PasswordPolicy),
+	}
+	if len(hashlistIDs) > 1 {
+		analyticsData.AccountCorrelation = s.correlateAccounts(correlatedAccounts)
 	}
 
 	// Calculate per-domain analytics if domains exist
@@ -162,6 +172,7 @@ func (s *AnalyticsService) GenerateAnalytics(ctx context.Context, reportID uuid.
 				CustomPatterns:      s.checkCustomPatterns(domainPasswords, report.CustomPatterns, report.ClientID.String()),
 				StrengthMetrics:     s.calculateStrengthMetrics(domainPasswords, speeds),
 				TopPasswords:        s.getTopPasswords(domainPasswords, 50),
+				PolicyCompliance:    s.checkPolicyCompliance(domainPasswords, report.PasswordPolicy),
 			}
 
 			domainAnalytics = append(domainAnalytics, domainAnalytic)
@@ -1122,6 +1133,27 @@ func (s *AnalyticsService) generateRecommendations(data *models.AnalyticsData) [
 		})
 	}
 
+	// Account correlation - the same account with the same password in several hashlists
+	if data.AccountCorrelation != nil && data.AccountCorrelation.ReusedAccounts > 0 {
+		percent := float64(data.AccountCorrelation.ReusedAccounts) / float64(data.AccountCorrelation.SharedAccounts) * 100
+		recs = append(recs, models.Recommendation{
+			Severity:   "CRITICAL",
+			Count:      data.AccountCorrelation.ReusedAccounts,
+			Percentage: percent,
+			Message:    fmt.Sprintf("%d accounts (%.2f%% of those found in several hashlists) use the same password in more than one hashlist. Require distinct passwords per system and randomize local administrator passwords (e.g. LAPS).", data.AccountCorrelation.ReusedAccounts, percent),
+		})
+	}
+
+	// Policy compliance - cracked passwords the client's policy allowed
+	if data.PolicyCompliance != nil && data.PolicyCompliance.Compliant.Count > 0 {
+		recs = append(recs, models.Recommendation{
+			Severity:   "HIGH",
+			Count:      data.PolicyCompliance.Compliant.Count,
+			Percentage: data.PolicyCompliance.Compliant.Percentage,
+			Message:    fmt.Sprintf("%d cracked passwords (%.2f%%) comply with the password policy. The policy alone does not prevent crackable passwords; add banned word and breached password checks.", data.PolicyCompliance.Compliant.Count, data.PolicyCompliance.Compliant.Percentage),
+		})
+	}
+
 	// Entropy-based
 	if data.StrengthMetrics.EntropyDistribution.Low.Percentage > 30 {
 		recs = append(recs, models.Recommendation{
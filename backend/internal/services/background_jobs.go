@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// defaultBackgroundJobIdle is how long an agent must have been without normal
+// work before it runs background jobs, when the setting is missing
+const defaultBackgroundJobIdle = 10 * time.Minute
+
+// backgroundJobIdle returns the background_job_idle_minutes setting
+func (s *JobExecutionService) backgroundJobIdle(ctx context.Context) time.Duration {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, "background_job_idle_minutes")
+	if err != nil || setting.Value == nil {
+		return defaultBackgroundJobIdle
+	}
+	minutes, err := strconv.Atoi(*setting.Value)
+	if err != nil || minutes < 0 {
+		return defaultBackgroundJobIdle
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// agentIdleForBackground reports whether the agent has been without normal
+// work for long enough to run background jobs
+func (s *JobExecutionService) agentIdleForBackground(ctx context.Context, agentID int) (bool, error) {
+	lastActivity, err := s.jobTaskRepo.GetLastForegroundActivity(ctx, agentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check agent idle time: %w", err)
+	}
+	return idleLongEnough(lastActivity, s.backgroundJobIdle(ctx), time.Now()), nil
+}
+
+// idleLongEnough reports whether at least idle has passed since lastActivity.
+// An agent that never worked on a normal job is idle.
+func idleLongEnough(lastActivity *time.Time, idle time.Duration, now time.Time) bool {
+	return lastActivity == nil || now.Sub(*lastActivity) >= idle
+}
+
+// preemptBackgroundTasks stops the background tasks whose agents a normal job
+// has work for, up to the number of agents each normal job can still take. The
+// tasks go back to pending so the background jobs resume once the agents are
+// idle again. It returns the agents that were freed.
+func (s *JobSchedulingService) preemptBackgroundTasks(ctx context.Context) ([]int, error) {
+	tasks, err := s.jobExecutionService.jobTaskRepo.GetActiveBackgroundTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	// Agents each normal job can still take, counted down as tasks are preempted for it
+	capacity := make(map[uuid.UUID]int)
+	var freed []int
+	for _, task := range tasks {
+		next, err := s.jobExecutionService.nextJobWithWorkForAgent(ctx, *task.AgentID)
+		if err != nil {
+			debug.Error("Failed to check normal work for agent %d: %v", *task.AgentID, err)
+			continue
+		}
+		if next == nil || next.IsBackground {
+			continue
+		}
+		if _, ok := capacity[next.ID]; !ok {
+			capacity[next.ID] = next.MaxAgents - next.ActiveAgents
+			if next.MaxAgents == 0 {
+				capacity[next.ID] = len(tasks)
+			}
+		}
+		if capacity[next.ID] <= 0 {
+			continue
+		}
+
+		debug.Info("Preempting background task %s on agent %d for job %s", task.ID, *task.AgentID, next.ID)
+		if s.wsIntegration != nil {
+			if err := s.wsIntegration.SendJobStop(ctx, task.ID, fmt.Sprintf("Background job preempted by job %s", next.ID)); err != nil {
+				debug.Error("Failed to send stop command to agent %d for task %s: %v", *task.AgentID, task.ID, err)
+			}
+		}
+		if err := s.jobExecutionService.jobTaskRepo.SetTaskPending(ctx, task.ID); err != nil {
+			debug.Error("Failed to set preempted background task %s to pending: %v", task.ID, err)
+			continue
+		}
+
+		agent, err := s.agentRepo.GetByID(ctx, *task.AgentID)
+		if err == nil && agent.Metadata != nil {
+			agent.Metadata["busy_status"] = "false"
+			delete(agent.Metadata, "current_task_id")
+			delete(agent.Metadata, "current_job_id")
+			if err := s.agentRepo.Update(ctx, agent); err != nil {
+				debug.Error("Failed to clear agent busy status after preemption: %v", err)
+			}
+		}
+
+		capacity[next.ID]--
+		freed = append(freed, *task.AgentID)
+	}
+
+	return freed, nil
+}
+
+// assignToPreemptedAgents gives the agents freed by preempting background
+// tasks their normal work right away
+func (s *JobSchedulingService) assignToPreemptedAgents(ctx context.Context, agentIDs []int, result *ScheduleJobsResult) {
+	freed := make(map[int]bool, len(agentIDs))
+	for _, id := range agentIDs {
+		freed[id] = true
+	}
+
+	availableAgents, err := s.jobExecutionService.GetAvailableAgents(ctx)
+	if err != nil {
+		debug.Error("Failed to get available agents after preempting background tasks: %v", err)
+		return
+	}
+	for i := range availableAgents {
+		if !freed[availableAgents[i].ID] {
+			continue
+		}
+		task, interruptedJobs, err := s.assignWorkToAgent(ctx, &availableAgents[i])
+		schedulingRecorderFrom(ctx).decide(availableAgents[i].ID, task, err)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to assign work to agent %d: %w", availableAgents[i].ID, err))
+			continue
+		}
+		if task != nil {
+			result.AssignedTasks = append(result.AssignedTasks, *task)
+		}
+		result.InterruptedJobs = append(result.InterruptedJobs, interruptedJobs...)
+	}
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdleLongEnough(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	idle := 10 * time.Minute
+
+	// Never ran a normal job
+	assert.True(t, idleLongEnough(nil, idle, now))
+
+	recent := now.Add(-5 * time.Minute)
+	assert.False(t, idleLongEnough(&recent, idle, now))
+
+	exact := now.Add(-idle)
+	assert.True(t, idleLongEnough(&exact, idle, now))
+
+	// No idle time required
+	assert.True(t, idleLongEnough(&recent, 0, now))
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/binary"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// defaultBinaryRolloutCheckInterval is how often an active rollout is
+// evaluated when binary_rollout_check_interval_minutes is not set
+const defaultBinaryRolloutCheckInterval = 15 * time.Minute
+
+// ErrInvalidBinaryRollout is returned for a rollout that cannot be started
+var ErrInvalidBinaryRollout = errors.New("invalid binary rollout")
+
+// BinaryRolloutService stages new binary versions on canary agents, compares
+// them with the version they replace and promotes or rolls them back
+type BinaryRolloutService struct {
+	rolloutRepo        *repository.BinaryRolloutRepository
+	binaryManager      binary.Manager
+	systemSettingsRepo *repository.SystemSettingsRepository
+}
+
+// NewBinaryRolloutService creates a new binary rollout service
+func NewBinaryRolloutService(rolloutRepo *repository.BinaryRolloutRepository, binaryManager binary.Manager, systemSettingsRepo *repository.SystemSettingsRepository) *BinaryRolloutService {
+	return &BinaryRolloutService{
+		rolloutRepo:        rolloutRepo,
+		binaryManager:      binaryManager,
+		systemSettingsRepo: systemSettingsRepo,
+	}
+}
+
+// ListRollouts returns all rollouts, newest first
+func (s *BinaryRolloutService) ListRollouts(ctx context.Context) ([]models.BinaryRollout, error) {
+	return s.rolloutRepo.List(ctx)
+}
+
+// GetRollout returns a rollout. The metrics of a rollout in canary status are
+// current rather than those of its last evaluation.
+func (s *BinaryRolloutService) GetRollout(ctx context.Context, id int) (*models.BinaryRollout, error) {
+	rollout, err := s.rolloutRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rollout.Status == models.BinaryRolloutCanary {
+		if rollout.Metrics, err = s.rolloutRepo.GetMetrics(ctx, rollout); err != nil {
+			return nil, err
+		}
+	}
+	return rollout, nil
+}
+
+// StartRollout puts a verified binary version on the canary agents. Without a
+// previous version the rollout replaces the current default of its type.
+func (s *BinaryRolloutService) StartRollout(ctx context.Context, rollout *models.BinaryRollout) error {
+	labels, err := models.NormalizeAgentLabels([]string{rollout.CanaryLabel})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBinaryRollout, err)
+	}
+	rollout.CanaryLabel = ""
+	if len(labels) == 1 {
+		rollout.CanaryLabel = labels[0]
+	}
+	if err := rollout.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBinaryRollout, err)
+	}
+
+	version, err := s.binaryManager.GetVersion(ctx, int64(rollout.BinaryVersionID))
+	if err != nil || version == nil {
+		return fmt.Errorf("%w: binary version %d not found", ErrInvalidBinaryRollout, rollout.BinaryVersionID)
+	}
+	if !version.IsActive || version.VerificationStatus != binary.VerificationStatusVerified {
+		return fmt.Errorf("%w: binary version %d is not active and verified", ErrInvalidBinaryRollout, version.ID)
+	}
+
+	if rollout.PreviousVersionID == 0 {
+		previous, err := s.defaultVersion(ctx, version.BinaryType)
+		if err != nil {
+			return err
+		}
+		rollout.PreviousVersionID = int(previous.ID)
+	} else {
+		previous, err := s.binaryManager.GetVersion(ctx, int64(rollout.PreviousVersionID))
+		if err != nil || previous == nil {
+			return fmt.Errorf("%w: previous binary version %d not found", ErrInvalidBinaryRollout, rollout.PreviousVersionID)
+		}
+		if previous.BinaryType != version.BinaryType {
+			return fmt.Errorf("%w: previous binary version %d is not a %s binary", ErrInvalidBinaryRollout, previous.ID, version.BinaryType)
+		}
+	}
+	if rollout.PreviousVersionID == rollout.BinaryVersionID {
+		return fmt.Errorf("%w: binary version %d is already the version it would replace", ErrInvalidBinaryRollout, version.ID)
+	}
+
+	if err := s.rolloutRepo.Create(ctx, rollout); err != nil {
+		return err
+	}
+	debug.Info("Started rollout %d of binary version %d in place of %d on agents labelled %q for %d hours",
+		rollout.ID, rollout.BinaryVersionID, rollout.PreviousVersionID, rollout.CanaryLabel, rollout.ObservationHours)
+	return nil
+}
+
+// PromoteRollout ends a rollout early by making its version the default
+func (s *BinaryRolloutService) PromoteRollout(ctx context.Context, id int, reason string) (*models.BinaryRollout, error) {
+	rollout, err := s.activeRollout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := s.rolloutRepo.GetMetrics(ctx, rollout)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		reason = "promoted by an administrator"
+	}
+	if err := s.promote(ctx, rollout, reason, false, metrics); err != nil {
+		return nil, err
+	}
+	return s.rolloutRepo.GetByID(ctx, id)
+}
+
+// RollBackRollout ends a rollout, returning the canary agents to the previous version
+func (s *BinaryRolloutService) RollBackRollout(ctx context.Context, id int, reason string) (*models.BinaryRollout, error) {
+	rollout, err := s.activeRollout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := s.rolloutRepo.GetMetrics(ctx, rollout)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		reason = "rolled back by an administrator"
+	}
+	if err := s.rollBack(ctx, rollout, reason, false, metrics); err != nil {
+		return nil, err
+	}
+	return s.rolloutRepo.GetByID(ctx, id)
+}
+
+// TaskBinaryVersion returns the binary version a task of a job using
+// binaryVersionID runs with on an agent. Canary agents of the active rollout
+// run its new version in place of the one it replaces.
+func (s *BinaryRolloutService) TaskBinaryVersion(ctx context.Context, agent *models.Agent, binaryVersionID int) int {
+	rollout, err := s.rolloutRepo.GetActive(ctx)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			debug.Warning("Failed to get active binary rollout: %v", err)
+		}
+		return binaryVersionID
+	}
+	if rollout.PreviousVersionID == binaryVersionID && agent.HasLabel(rollout.CanaryLabel) {
+		return rollout.BinaryVersionID
+	}
+	return binaryVersionID
+}
+
+// StartScheduler evaluates the active rollout on startup and then at the
+// configured interval
+func (s *BinaryRolloutService) StartScheduler(ctx context.Context) {
+	for {
+		if err := s.EvaluateActive(ctx); err != nil {
+			debug.Error("Binary rollout evaluation failed: %v", err)
+		}
+
+		interval := defaultBinaryRolloutCheckInterval
+		if minutes, ok := positiveIntSetting(ctx, s.systemSettingsRepo, "binary_rollout_check_interval_minutes"); ok {
+			interval = time.Duration(minutes) * time.Minute
+		}
+		select {
+		case <-ctx.Done():
+			debug.Info("Binary rollout scheduler stopped")
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// EvaluateActive compares the canaries of the active rollout with the previous
+// version and promotes or rolls it back once the metrics decide it
+func (s *BinaryRolloutService) EvaluateActive(ctx context.Context) error {
+	rollout, err := s.rolloutRepo.GetActive(ctx)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	metrics, err := s.rolloutRepo.GetMetrics(ctx, rollout)
+	if err != nil {
+		return err
+	}
+
+	status, reason := rollout.Decide(*metrics, time.Now())
+	switch status {
+	case models.BinaryRolloutPromoted:
+		return s.promote(ctx, rollout, reason, true, metrics)
+	case models.BinaryRolloutRolledBack:
+		return s.rollBack(ctx, rollout, reason, true, metrics)
+	default:
+		return s.rolloutRepo.UpdateMetrics(ctx, rollout.ID, metrics, reason)
+	}
+}
+
+// promote makes the rollout's version the default and moves preset jobs and
+// queued jobs onto it before recording the decision, so a failed promotion
+// leaves the rollout in canary status to be retried
+func (s *BinaryRolloutService) promote(ctx context.Context, rollout *models.BinaryRollout, reason string, automatic bool, metrics *models.BinaryRolloutMetrics) error {
+	if err := s.binaryManager.PromoteVersion(ctx, int64(rollout.PreviousVersionID), int64(rollout.BinaryVersionID)); err != nil {
+		return fmt.Errorf("failed to promote binary version %d: %w", rollout.BinaryVersionID, err)
+	}
+	if err := s.rolloutRepo.Decide(ctx, rollout.ID, models.BinaryRolloutPromoted, reason, automatic, metrics); err != nil {
+		return err
+	}
+	debug.Info("Promoted binary rollout %d: %s", rollout.ID, reason)
+	return nil
+}
+
+// rollBack ends the canary phase, tasks dispatched from then on run the
+// previous version again while tasks already running finish on the new one
+func (s *BinaryRolloutService) rollBack(ctx context.Context, rollout *models.BinaryRollout, reason string, automatic bool, metrics *models.BinaryRolloutMetrics) error {
+	if err := s.rolloutRepo.Decide(ctx, rollout.ID, models.BinaryRolloutRolledBack, reason, automatic, metrics); err != nil {
+		return err
+	}
+	debug.Warning("Rolled back binary rollout %d: %s", rollout.ID, reason)
+	return nil
+}
+
+// activeRollout returns a rollout that is still in canary status, or ErrNotFound
+func (s *BinaryRolloutService) activeRollout(ctx context.Context, id int) (*models.BinaryRollout, error) {
+	rollout, err := s.rolloutRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rollout.Status != models.BinaryRolloutCanary {
+		return nil, fmt.Errorf("%w: rollout %d was already %s", ErrInvalidBinaryRollout, id, rollout.Status)
+	}
+	return rollout, nil
+}
+
+// defaultVersion returns the default version of a binary type
+func (s *BinaryRolloutService) defaultVersion(ctx context.Context, binaryType binary.BinaryType) (*binary.BinaryVersion, error) {
+	versions, err := s.binaryManager.ListVersions(ctx, map[string]interface{}{
+		"binary_type": binaryType,
+		"is_active":   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		if version.IsDefault {
+			return version, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no default %s binary to replace", ErrInvalidBinaryRollout, binaryType)
+}
+
+// TaskBinaryVersion returns the binary version to dispatch a task of a job
+// using binaryVersionID to an agent with, the job's own unless the agent is a
+// canary of the active binary rollout
+func (s *JobExecutionService) TaskBinaryVersion(ctx context.Context, agent *models.Agent, binaryVersionID int) int {
+	if s.rollouts == nil {
+		return binaryVersionID
+	}
+	return s.rollouts.TaskBinaryVersion(ctx, agent, binaryVersionID)
+}
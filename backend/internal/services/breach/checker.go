@@ -0,0 +1,270 @@
+package breach
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/md4"
+)
+
+// Checker looks up how often a password appears in a breach corpus
+type Checker interface {
+	// Count returns how often the password appears in the corpus, 0 if it does not
+	Count(ctx context.Context, password string) (int, error)
+	Close() error
+}
+
+// SHA1Hex returns the uppercase hex SHA-1 of a password as used by Pwned Passwords
+func SHA1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// NTLMHex returns the uppercase hex NTLM hash (MD4 of UTF-16LE) of a password
+func NTLMHex(password string) string {
+	h := md4.New()
+	for _, u := range utf16.Encode([]rune(password)) {
+		h.Write([]byte{byte(u), byte(u >> 8)})
+	}
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
+}
+
+// maxRangeAttempts bounds retries of a rate limited range request
+const maxRangeAttempts = 3
+
+// OnlineChecker queries a Pwned Passwords compatible k-anonymity range API.
+// Only the first five characters of each password's SHA-1 leave the server.
+// Responses are padded and cached per prefix for the lifetime of the checker.
+type OnlineChecker struct {
+	baseURL string
+	client  *http.Client
+
+	mu     sync.Mutex
+	ranges map[string]map[string]int
+}
+
+// NewOnlineChecker creates a checker for the range API at baseURL, e.g.
+// https://api.pwnedpasswords.com/range/
+func NewOnlineChecker(baseURL string, client *http.Client) *OnlineChecker {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return &OnlineChecker{
+		baseURL: baseURL,
+		client:  client,
+		ranges:  make(map[string]map[string]int),
+	}
+}
+
+// Count implements Checker
+func (c *OnlineChecker) Count(ctx context.Context, password string) (int, error) {
+	hash := SHA1Hex(password)
+	prefix, suffix := hash[:5], hash[5:]
+
+	c.mu.Lock()
+	suffixes, ok := c.ranges[prefix]
+	c.mu.Unlock()
+	if !ok {
+		var err error
+		if suffixes, err = c.fetchRange(ctx, prefix); err != nil {
+			return 0, err
+		}
+		c.mu.Lock()
+		c.ranges[prefix] = suffixes
+		c.mu.Unlock()
+	}
+	return suffixes[suffix], nil
+}
+
+// fetchRange downloads the hash suffixes and counts for a prefix
+func (c *OnlineChecker) fetchRange(ctx context.Context, prefix string) (map[string]int, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+prefix, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create range request: %w", err)
+		}
+		req.Header.Set("Add-Padding", "true")
+		req.Header.Set("User-Agent", "KrakenHashes")
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("range request failed: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRangeAttempts {
+			resp.Body.Close()
+			wait := 2 * time.Second
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+				wait = time.Duration(seconds) * time.Second
+			}
+			select {
+			case <-time.After(wait):
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		suffixes, err := parseRange(resp)
+		resp.Body.Close()
+		return suffixes, err
+	}
+}
+
+// parseRange reads "SUFFIX:COUNT" lines, padding entries have a count of 0
+func parseRange(resp *http.Response) (map[string]int, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("range request returned status %d", resp.StatusCode)
+	}
+
+	suffixes := make(map[string]int)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		suffix, countStr, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok {
+			continue
+		}
+		if count, err := strconv.Atoi(countStr); err == nil && count > 0 {
+			suffixes[strings.ToUpper(suffix)] = count
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read range response: %w", err)
+	}
+	return suffixes, nil
+}
+
+// Close implements Checker
+func (c *OnlineChecker) Close() error {
+	return nil
+}
+
+// CorpusChecker looks passwords up in a local Pwned Passwords download ordered
+// by hash, one "HASH:COUNT" line per hash. NTLM and SHA-1 corpora are both
+// supported and told apart by their hash length. Lookups binary search the
+// file, so even the full corpus needs no loading or index.
+type CorpusChecker struct {
+	file *os.File
+	size int64
+	ntlm bool
+}
+
+// NewCorpusChecker opens the corpus at path
+func NewCorpusChecker(path string) (*CorpusChecker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breach corpus: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat breach corpus: %w", err)
+	}
+
+	c := &CorpusChecker{file: file, size: info.Size()}
+	first, _, err := c.lineFrom(0)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	hash, _, _ := strings.Cut(first, ":")
+	switch len(hash) {
+	case 32:
+		c.ntlm = true
+	case 40:
+	default:
+		file.Close()
+		return nil, fmt.Errorf("breach corpus %s is not an NTLM or SHA-1 Pwned Passwords file", path)
+	}
+	return c, nil
+}
+
+// Count implements Checker
+func (c *CorpusChecker) Count(ctx context.Context, password string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	target := SHA1Hex(password)
+	if c.ntlm {
+		target = NTLMHex(password)
+	}
+
+	// Find the smallest offset from which the next line's hash is >= target
+	lo, hi := int64(0), c.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		line, _, err := c.lineFrom(mid)
+		if err != nil {
+			return 0, err
+		}
+		if line == "" || corpusHash(line) >= target {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	line, _, err := c.lineFrom(lo)
+	if err != nil || line == "" {
+		return 0, err
+	}
+	hash, countStr, _ := strings.Cut(line, ":")
+	if !strings.EqualFold(hash, target) {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countStr))
+	if err != nil {
+		return 0, fmt.Errorf("invalid count in breach corpus line %q", line)
+	}
+	return count, nil
+}
+
+// lineFrom returns the first line that starts at or after offset and where it
+// starts, or "" at the end of the file
+func (c *CorpusChecker) lineFrom(offset int64) (string, int64, error) {
+	start := offset
+	if offset > 0 {
+		// Skip the rest of the line offset-1 belongs to
+		start = offset - 1
+	}
+	reader := bufio.NewReaderSize(io.NewSectionReader(c.file, start, c.size-start), 128)
+	if offset > 0 {
+		skipped, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return "", c.size, nil
+		}
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to read breach corpus: %w", err)
+		}
+		start += int64(len(skipped))
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", 0, fmt.Errorf("failed to read breach corpus: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), start, nil
+}
+
+// corpusHash returns the uppercase hash of a corpus line
+func corpusHash(line string) string {
+	hash, _, _ := strings.Cut(line, ":")
+	return strings.ToUpper(hash)
+}
+
+// Close implements Checker
+func (c *CorpusChecker) Close() error {
+	return c.file.Close()
+}
@@ -0,0 +1,116 @@
+package breach
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashes(t *testing.T) {
+	assert.Equal(t, "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8", SHA1Hex("password"))
+	assert.Equal(t, "8846F7EAEE8FB117AD06BDD830B7586C", NTLMHex("password"))
+}
+
+func TestOnlineChecker(t *testing.T) {
+	hash := SHA1Hex("password")
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+		if r.URL.Path != "/range/"+hash[:5] {
+			fmt.Fprintln(w, "0000000000000000000000000000000000A:0")
+			return
+		}
+		fmt.Fprintf(w, "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n%s:3861493\r\n00D4F6E8FA6EECAD2A3AA415EEC418D38EC:0\r\n", hash[5:])
+	}))
+	defer server.Close()
+
+	checker := NewOnlineChecker(server.URL+"/range", server.Client())
+	ctx := context.Background()
+
+	count, err := checker.Count(ctx, "password")
+	require.NoError(t, err)
+	assert.Equal(t, 3861493, count)
+
+	// Same prefix is served from the cache
+	count, err = checker.Count(ctx, "password")
+	require.NoError(t, err)
+	assert.Equal(t, 3861493, count)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	count, err = checker.Count(ctx, "kraken-not-breached")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestOnlineChecker_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewOnlineChecker(server.URL, server.Client()).Count(context.Background(), "password")
+	assert.Error(t, err)
+}
+
+// writeCorpus writes a sorted corpus of the given passwords with counts 1, 2, ...
+func writeCorpus(t *testing.T, hash func(string) string, passwords []string) string {
+	t.Helper()
+	lines := make([]string, len(passwords))
+	for i, password := range passwords {
+		lines[i] = fmt.Sprintf("%s:%d", hash(password), i+1)
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0644))
+	return path
+}
+
+func TestCorpusChecker(t *testing.T) {
+	passwords := []string{"password", "123456", "Summer2024!", "letmein", "qwerty", "dragon", "monkey"}
+	for i := 0; i < 500; i++ {
+		passwords = append(passwords, fmt.Sprintf("generated%d", i))
+	}
+
+	for name, hash := range map[string]func(string) string{"ntlm": NTLMHex, "sha1": SHA1Hex} {
+		t.Run(name, func(t *testing.T) {
+			checker, err := NewCorpusChecker(writeCorpus(t, hash, passwords))
+			require.NoError(t, err)
+			defer checker.Close()
+			assert.Equal(t, name == "ntlm", checker.ntlm)
+
+			ctx := context.Background()
+			for i, password := range passwords {
+				count, err := checker.Count(ctx, password)
+				require.NoError(t, err)
+				assert.Equal(t, i+1, count, password)
+			}
+
+			count, err := checker.Count(ctx, "kraken-not-breached")
+			require.NoError(t, err)
+			assert.Equal(t, 0, count)
+		})
+	}
+}
+
+func TestCorpusChecker_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	require.NoError(t, os.WriteFile(path, []byte("not a corpus\n"), 0644))
+
+	_, err := NewCorpusChecker(path)
+	assert.Error(t, err)
+
+	_, err = NewCorpusChecker(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package breach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// Settings controlling breach checks
+const (
+	settingMode       = "breach_check_mode"
+	settingAPIURL     = "breach_check_api_url"
+	settingCorpusPath = "breach_corpus_path"
+
+	modeDisabled = "disabled"
+)
+
+// onlineRequestTimeout bounds a single range API request
+const onlineRequestTimeout = 30 * time.Second
+
+var (
+	// ErrDisabled is returned when breach checks are turned off
+	ErrDisabled = errors.New("breach checks are disabled")
+	// ErrCheckRunning is returned when the hashlist is already being checked
+	ErrCheckRunning = errors.New("a breach check is already running for this hashlist")
+	// ErrAirgapped is returned for online checks on an air-gapped install
+	ErrAirgapped = errors.New("online breach checks are not available in air-gapped mode, use offline mode")
+)
+
+// BreachService correlates cracked passwords with a corpus of breached
+// passwords, either through the Pwned Passwords range API or, for air-gapped
+// installs, a locally mounted copy of the corpus.
+type BreachService struct {
+	breachRepo   *repository.BreachRepository
+	settingsRepo *repository.SystemSettingsRepository
+	airgapped    bool
+}
+
+// NewBreachService creates a new BreachService. Air-gapped installs only
+// allow offline checks.
+func NewBreachService(br *repository.BreachRepository, sr *repository.SystemSettingsRepository, airgapped bool) *BreachService {
+	return &BreachService{
+		breachRepo:   br,
+		settingsRepo: sr,
+		airgapped:    airgapped,
+	}
+}
+
+// StartCheck checks the cracked passwords of a hashlist in the background and
+// returns the running check.
+func (s *BreachService) StartCheck(ctx context.Context, hashlistID int64) (*models.HashlistBreachCheck, error) {
+	checker, source, err := s.newChecker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	started, err := s.breachRepo.StartCheck(ctx, hashlistID, source)
+	if err != nil {
+		checker.Close()
+		return nil, err
+	}
+	if !started {
+		checker.Close()
+		return nil, ErrCheckRunning
+	}
+
+	go s.runCheck(context.Background(), hashlistID, checker)
+
+	return s.breachRepo.GetCheck(ctx, hashlistID)
+}
+
+// newChecker creates the checker for the configured mode
+func (s *BreachService) newChecker(ctx context.Context) (Checker, string, error) {
+	mode := s.settingValue(ctx, settingMode)
+	switch mode {
+	case "", modeDisabled:
+		return nil, "", ErrDisabled
+	case models.BreachSourceOnline:
+		if s.airgapped {
+			return nil, "", ErrAirgapped
+		}
+		apiURL := s.settingValue(ctx, settingAPIURL)
+		if apiURL == "" {
+			return nil, "", fmt.Errorf("%s is not configured", settingAPIURL)
+		}
+		return NewOnlineChecker(apiURL, &http.Client{Timeout: onlineRequestTimeout}), models.BreachSourceOnline, nil
+	case models.BreachSourceOffline:
+		path := s.settingValue(ctx, settingCorpusPath)
+		if path == "" {
+			return nil, "", fmt.Errorf("%s is not configured", settingCorpusPath)
+		}
+		checker, err := NewCorpusChecker(path)
+		if err != nil {
+			return nil, "", err
+		}
+		return checker, models.BreachSourceOffline, nil
+	default:
+		return nil, "", fmt.Errorf("unknown %s %q", settingMode, mode)
+	}
+}
+
+// settingValue returns a system setting, or "" when it is missing
+func (s *BreachService) settingValue(ctx context.Context, key string) string {
+	setting, err := s.settingsRepo.GetSetting(ctx, key)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			debug.Warning("Failed to read setting %s: %v", key, err)
+		}
+		return ""
+	}
+	if setting.Value == nil {
+		return ""
+	}
+	return *setting.Value
+}
+
+// runCheck looks up every distinct cracked password once and stores the
+// result on all hashes sharing it.
+func (s *BreachService) runCheck(ctx context.Context, hashlistID int64, checker Checker) {
+	defer checker.Close()
+
+	checked, breached, err := s.checkPasswords(ctx, hashlistID, checker)
+	if err != nil {
+		debug.Error("Breach check of hashlist %d failed: %v", hashlistID, err)
+		if err := s.breachRepo.FailCheck(ctx, hashlistID, err.Error()); err != nil {
+			debug.Error("%v", err)
+		}
+		return
+	}
+
+	if err := s.breachRepo.CompleteCheck(ctx, hashlistID, checked, breached); err != nil {
+		debug.Error("%v", err)
+		return
+	}
+	debug.Info("Breach check of hashlist %d completed: %d of %d cracked hashes breached", hashlistID, breached, checked)
+}
+
+// checkPasswords returns the number of cracked hashes checked and breached
+func (s *BreachService) checkPasswords(ctx context.Context, hashlistID int64, checker Checker) (int, int, error) {
+	passwords, err := s.breachRepo.GetCrackedPasswords(ctx, hashlistID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	byPassword := make(map[string][]uuid.UUID)
+	for _, p := range passwords {
+		byPassword[p.Password] = append(byPassword[p.Password], p.HashID)
+	}
+
+	breached := 0
+	for password, hashIDs := range byPassword {
+		count, err := checker.Count(ctx, password)
+		if err != nil {
+			return 0, 0, err
+		}
+		if err := s.breachRepo.SetBreachCount(ctx, hashIDs, count); err != nil {
+			return 0, 0, err
+		}
+		if count > 0 {
+			breached += len(hashIDs)
+		}
+	}
+	return len(passwords), breached, nil
+}
+
+// GetCheck returns the latest check of a hashlist
+func (s *BreachService) GetCheck(ctx context.Context, hashlistID int64) (*models.HashlistBreachCheck, error) {
+	return s.breachRepo.GetCheck(ctx, hashlistID)
+}
+
+// ListBreachedHashes returns a page of the hashlist's breached hashes and their total
+func (s *BreachService) ListBreachedHashes(ctx context.Context, hashlistID int64, limit, offset int) ([]models.BreachedHash, int, error) {
+	return s.breachRepo.ListBreachedHashes(ctx, hashlistID, limit, offset)
+}
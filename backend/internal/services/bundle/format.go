@@ -0,0 +1,363 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// A bundle is an uncompressed tar archive. Asset files come first under
+// files/, followed by manifest.json and its ed25519 signature manifest.sig.
+// The manifest lists the SHA-256 of every file, so the signature covers the
+// whole bundle.
+const (
+	// FormatVersion is the bundle format this server writes and reads
+	FormatVersion = 1
+
+	manifestName  = "manifest.json"
+	signatureName = "manifest.sig"
+	filesDir      = "files"
+
+	// maxManifestSize bounds the manifest read into memory on import
+	maxManifestSize = 64 << 20
+)
+
+// ErrUntrustedKey is returned when a bundle is not signed by a trusted key
+var ErrUntrustedKey = errors.New("bundle is not signed by a trusted key")
+
+// Manifest describes the contents of a bundle
+type Manifest struct {
+	FormatVersion int                `json:"format_version"`
+	CreatedAt     time.Time          `json:"created_at"`
+	PublicKey     string             `json:"public_key"`
+	Wordlists     []WordlistEntry    `json:"wordlists"`
+	Rules         []RuleEntry        `json:"rules"`
+	Binaries      []BinaryEntry      `json:"binaries"`
+	PresetJobs    []models.PresetJob `json:"preset_jobs"`
+	Settings      []SettingEntry     `json:"settings"`
+	Files         []FileEntry        `json:"files"`
+}
+
+// FileEntry is a file stored in the bundle
+type FileEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// WordlistEntry is an exported wordlist, ID is its ID on the exporting server
+type WordlistEntry struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	WordlistType string   `json:"wordlist_type"`
+	Format       string   `json:"format"`
+	FileName     string   `json:"file_name"`
+	MD5Hash      string   `json:"md5_hash"`
+	WordCount    int64    `json:"word_count"`
+	Tags         []string `json:"tags,omitempty"`
+	Path         string   `json:"path"`
+}
+
+// RuleEntry is an exported rule file, ID is its ID on the exporting server
+type RuleEntry struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	RuleType    string   `json:"rule_type"`
+	FileName    string   `json:"file_name"`
+	MD5Hash     string   `json:"md5_hash"`
+	RuleCount   int64    `json:"rule_count"`
+	Tags        []string `json:"tags,omitempty"`
+	Path        string   `json:"path"`
+}
+
+// BinaryEntry is an exported binary archive, ID is its ID on the exporting server
+type BinaryEntry struct {
+	ID              int64  `json:"id"`
+	BinaryType      string `json:"binary_type"`
+	CompressionType string `json:"compression_type"`
+	SourceURL       string `json:"source_url"`
+	FileName        string `json:"file_name"`
+	MD5Hash         string `json:"md5_hash"`
+	IsDefault       bool   `json:"is_default"`
+	Path            string `json:"path"`
+}
+
+// SettingEntry is an exported system setting
+type SettingEntry struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value"`
+}
+
+// LoadOrCreateSigningKey reads the server's bundle signing key, generating
+// and storing a new one on first use
+func LoadOrCreateSigningKey(keyPath string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(keyPath)
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("invalid bundle signing key %s", keyPath)
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bundle signing key: %w", err)
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("bundle signing key %s is not an ed25519 key", keyPath)
+		}
+		return privateKey, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read bundle signing key: %w", err)
+	}
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate bundle signing key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle signing key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create bundle signing key directory: %w", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, fmt.Errorf("failed to store bundle signing key: %w", err)
+	}
+	return privateKey, nil
+}
+
+// EncodePublicKey returns the base64 form of a public key used in settings and manifests
+func EncodePublicKey(key ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// ParsePublicKeys parses a comma or newline separated list of base64 public keys
+func ParsePublicKeys(value string) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	for _, field := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' || r == ' ' }) {
+		raw, err := base64.StdEncoding.DecodeString(field)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid public key %q", field)
+		}
+		keys = append(keys, ed25519.PublicKey(raw))
+	}
+	return keys, nil
+}
+
+// writer streams a bundle
+type writer struct {
+	tw    *tar.Writer
+	files []FileEntry
+}
+
+func newWriter(w io.Writer) *writer {
+	return &writer{tw: tar.NewWriter(w)}
+}
+
+// addFile copies a file into the bundle under files/<name> and returns its entry path
+func (w *writer) addFile(name, srcPath string) (string, error) {
+	file, err := os.Open(srcPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", srcPath, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", srcPath, err)
+	}
+
+	entryPath := path.Join(filesDir, name)
+	if err := w.tw.WriteHeader(&tar.Header{
+		Name:    entryPath,
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		return "", fmt.Errorf("failed to write bundle entry %s: %w", entryPath, err)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w.tw, hash), file); err != nil {
+		return "", fmt.Errorf("failed to write bundle entry %s: %w", entryPath, err)
+	}
+
+	w.files = append(w.files, FileEntry{Path: entryPath, Size: info.Size(), SHA256: hex.EncodeToString(hash.Sum(nil))})
+	return entryPath, nil
+}
+
+// finish writes the signed manifest and closes the archive
+func (w *writer) finish(manifest *Manifest, key ed25519.PrivateKey) error {
+	manifest.FormatVersion = FormatVersion
+	manifest.PublicKey = EncodePublicKey(key.Public().(ed25519.PublicKey))
+	manifest.Files = w.files
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := w.writeEntry(manifestName, data); err != nil {
+		return err
+	}
+	if err := w.writeEntry(signatureName, ed25519.Sign(key, data)); err != nil {
+		return err
+	}
+	return w.tw.Close()
+}
+
+func (w *writer) writeEntry(name string, data []byte) error {
+	if err := w.tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}); err != nil {
+		return fmt.Errorf("failed to write bundle entry %s: %w", name, err)
+	}
+	if _, err := w.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write bundle entry %s: %w", name, err)
+	}
+	return nil
+}
+
+// extracted is a bundle unpacked into a staging directory, not yet verified
+type extracted struct {
+	dir       string
+	manifest  []byte
+	signature []byte
+	files     map[string]FileEntry
+}
+
+// extract unpacks a bundle into dir, hashing every file on the way
+func extract(r io.Reader, dir string) (*extracted, error) {
+	result := &extracted{dir: dir, files: make(map[string]FileEntry)}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		switch header.Name {
+		case manifestName:
+			if result.manifest, err = io.ReadAll(io.LimitReader(tr, maxManifestSize)); err != nil {
+				return nil, fmt.Errorf("failed to read manifest: %w", err)
+			}
+			continue
+		case signatureName:
+			if result.signature, err = io.ReadAll(io.LimitReader(tr, ed25519.SignatureSize+1)); err != nil {
+				return nil, fmt.Errorf("failed to read manifest signature: %w", err)
+			}
+			continue
+		}
+
+		if !validEntryPath(header.Name) {
+			return nil, fmt.Errorf("invalid bundle entry %q", header.Name)
+		}
+		entry, err := extractFile(tr, header.Name, dir)
+		if err != nil {
+			return nil, err
+		}
+		result.files[header.Name] = entry
+	}
+
+	if result.manifest == nil || result.signature == nil {
+		return nil, fmt.Errorf("bundle has no signed manifest")
+	}
+	return result, nil
+}
+
+// validEntryPath accepts clean relative paths below files/
+func validEntryPath(name string) bool {
+	return strings.HasPrefix(name, filesDir+"/") && path.Clean(name) == name && !strings.Contains(name, "..")
+}
+
+func extractFile(r io.Reader, name, dir string) (FileEntry, error) {
+	dest := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(dest), 0750); err != nil {
+		return FileEntry{}, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	file, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0640)
+	if err != nil {
+		return FileEntry{}, fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), r)
+	if err != nil {
+		return FileEntry{}, fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	return FileEntry{Path: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// verify checks the manifest signature against the trusted keys and every
+// file against the manifest, and returns the manifest
+func (e *extracted) verify(trusted []ed25519.PublicKey) (*Manifest, error) {
+	var manifest Manifest
+	if err := json.Unmarshal(e.manifest, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported bundle format version %d", manifest.FormatVersion)
+	}
+
+	signer, err := ParsePublicKeys(manifest.PublicKey)
+	if err != nil || len(signer) != 1 {
+		return nil, fmt.Errorf("invalid manifest public key")
+	}
+	isTrusted := false
+	for _, key := range trusted {
+		if bytes.Equal(key, signer[0]) {
+			isTrusted = true
+			break
+		}
+	}
+	if !isTrusted {
+		return nil, ErrUntrustedKey
+	}
+	if !ed25519.Verify(signer[0], e.manifest, e.signature) {
+		return nil, fmt.Errorf("invalid bundle signature")
+	}
+
+	if len(manifest.Files) != len(e.files) {
+		return nil, fmt.Errorf("bundle contains %d files, manifest lists %d", len(e.files), len(manifest.Files))
+	}
+	for _, expected := range manifest.Files {
+		actual, ok := e.files[expected.Path]
+		if !ok {
+			return nil, fmt.Errorf("bundle is missing %s", expected.Path)
+		}
+		if actual != expected {
+			return nil, fmt.Errorf("bundle file %s does not match the manifest", expected.Path)
+		}
+	}
+	return &manifest, nil
+}
+
+// path returns where a bundle file was extracted
+func (e *extracted) path(entryPath string) string {
+	return filepath.Join(e.dir, filepath.FromSlash(entryPath))
+}
@@ -0,0 +1,107 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestBundle builds a bundle with one wordlist signed by key
+func writeTestBundle(t *testing.T, key ed25519.PrivateKey) []byte {
+	t.Helper()
+	src := filepath.Join(t.TempDir(), "rockyou.txt")
+	require.NoError(t, os.WriteFile(src, []byte("password\n123456\n"), 0644))
+
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	entryPath, err := w.addFile("wordlists/1/rockyou.txt", src)
+	require.NoError(t, err)
+	require.NoError(t, w.finish(&Manifest{
+		Wordlists: []WordlistEntry{{ID: 1, Name: "rockyou", FileName: "general/rockyou.txt", Path: entryPath}},
+	}, key))
+	return buf.Bytes()
+}
+
+func TestSigningKey(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "bundle", "signing.key")
+
+	key, err := LoadOrCreateSigningKey(keyPath)
+	require.NoError(t, err)
+	info, err := os.Stat(keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := LoadOrCreateSigningKey(keyPath)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(loaded))
+
+	keys, err := ParsePublicKeys(EncodePublicKey(key.Public().(ed25519.PublicKey)) + ", ")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.True(t, keys[0].Equal(key.Public()))
+
+	_, err = ParsePublicKeys("not-a-key")
+	assert.Error(t, err)
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	key, err := LoadOrCreateSigningKey(filepath.Join(t.TempDir(), "signing.key"))
+	require.NoError(t, err)
+	data := writeTestBundle(t, key)
+
+	bundle, err := extract(bytes.NewReader(data), t.TempDir())
+	require.NoError(t, err)
+	manifest, err := bundle.verify([]ed25519.PublicKey{key.Public().(ed25519.PublicKey)})
+	require.NoError(t, err)
+
+	require.Len(t, manifest.Wordlists, 1)
+	assert.Equal(t, "rockyou", manifest.Wordlists[0].Name)
+	content, err := os.ReadFile(bundle.path(manifest.Wordlists[0].Path))
+	require.NoError(t, err)
+	assert.Equal(t, "password\n123456\n", string(content))
+}
+
+func TestBundleUntrustedKey(t *testing.T) {
+	key, err := LoadOrCreateSigningKey(filepath.Join(t.TempDir(), "signing.key"))
+	require.NoError(t, err)
+	other, err := LoadOrCreateSigningKey(filepath.Join(t.TempDir(), "other.key"))
+	require.NoError(t, err)
+
+	bundle, err := extract(bytes.NewReader(writeTestBundle(t, key)), t.TempDir())
+	require.NoError(t, err)
+	_, err = bundle.verify([]ed25519.PublicKey{other.Public().(ed25519.PublicKey)})
+	assert.ErrorIs(t, err, ErrUntrustedKey)
+}
+
+func TestBundleTamperedFile(t *testing.T) {
+	key, err := LoadOrCreateSigningKey(filepath.Join(t.TempDir(), "signing.key"))
+	require.NoError(t, err)
+	data := writeTestBundle(t, key)
+
+	// Same length, different content
+	tampered := bytes.Replace(data, []byte("password\n"), []byte("p4ssw0rd\n"), 1)
+	require.NotEqual(t, data, tampered)
+
+	bundle, err := extract(bytes.NewReader(tampered), t.TempDir())
+	require.NoError(t, err)
+	_, err = bundle.verify([]ed25519.PublicKey{key.Public().(ed25519.PublicKey)})
+	assert.ErrorContains(t, err, "does not match the manifest")
+}
+
+func TestBundleRejectsPathTraversal(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "files/../../etc/cron.d/x", Mode: 0644, Size: 1}))
+	_, err := tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	_, err = extract(&buf, t.TempDir())
+	assert.ErrorContains(t, err, "invalid bundle entry")
+}
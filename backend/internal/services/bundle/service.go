@@ -0,0 +1,627 @@
+package bundle
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/binary"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/rule"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/wordlist"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// settingTrustedKeys lists the public keys of servers whose bundles may be imported
+const settingTrustedKeys = "bundle_trusted_keys"
+
+// excludedSettings are never exported or imported, a bundle must not be able
+// to change which bundles the importing server trusts. The telemetry identity
+// and tokens belong to one server and would make servers report as each other.
+var excludedSettings = map[string]bool{
+	settingTrustedKeys:          true,
+	"telemetry_instance_id":     true,
+	"telemetry_last_sent_at":    true,
+	"telemetry_token":           true,
+	"telemetry_collector_token": true,
+}
+
+// ErrImportRunning is returned when another import is in progress
+var ErrImportRunning = errors.New("a bundle import is already running")
+
+// ExportOptions selects what goes into a bundle. Preset jobs pull in the
+// wordlists, rules and binary they use. With nothing selected every wordlist,
+// rule, verified binary and preset job is exported.
+type ExportOptions struct {
+	WordlistIDs     []int       `json:"wordlist_ids"`
+	RuleIDs         []int       `json:"rule_ids"`
+	BinaryIDs       []int64     `json:"binary_ids"`
+	PresetJobIDs    []uuid.UUID `json:"preset_job_ids"`
+	IncludeSettings bool        `json:"include_settings"`
+}
+
+func (o ExportOptions) empty() bool {
+	return len(o.WordlistIDs) == 0 && len(o.RuleIDs) == 0 && len(o.BinaryIDs) == 0 && len(o.PresetJobIDs) == 0
+}
+
+// ImportCount counts the items of one kind in an imported bundle
+type ImportCount struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+}
+
+// ImportResult summarizes an import. Items already present on this server are
+// skipped, warnings explain anything that could not be imported.
+type ImportResult struct {
+	Wordlists  ImportCount `json:"wordlists"`
+	Rules      ImportCount `json:"rules"`
+	Binaries   ImportCount `json:"binaries"`
+	PresetJobs ImportCount `json:"preset_jobs"`
+	Settings   ImportCount `json:"settings"`
+	Warnings   []string    `json:"warnings,omitempty"`
+}
+
+func (r *ImportResult) warn(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	debug.Warning("Bundle import: %s", message)
+	r.Warnings = append(r.Warnings, message)
+}
+
+// BundleService exports wordlists, rules, binaries, preset jobs and settings
+// into signed deployment bundles and imports them, so air-gapped servers can
+// be provisioned without Internet access.
+type BundleService struct {
+	wordlistManager wordlist.Manager
+	ruleManager     rule.Manager
+	binaryManager   binary.Manager
+	presetJobRepo   repository.PresetJobRepository
+	settingsRepo    *repository.SystemSettingsRepository
+	keyPath         string
+	stagingDir      string
+
+	importMu sync.Mutex
+}
+
+// NewBundleService creates a new BundleService. The signing key is created at
+// keyPath on first use, imports are unpacked below stagingDir.
+func NewBundleService(wm wordlist.Manager, rm rule.Manager, bm binary.Manager, pr repository.PresetJobRepository, sr *repository.SystemSettingsRepository, keyPath, stagingDir string) *BundleService {
+	return &BundleService{
+		wordlistManager: wm,
+		ruleManager:     rm,
+		binaryManager:   bm,
+		presetJobRepo:   pr,
+		settingsRepo:    sr,
+		keyPath:         keyPath,
+		stagingDir:      stagingDir,
+	}
+}
+
+// PublicKey returns the public key bundles from this server are signed with.
+// Add it to bundle_trusted_keys on the servers that import them.
+func (s *BundleService) PublicKey() (string, error) {
+	key, err := LoadOrCreateSigningKey(s.keyPath)
+	if err != nil {
+		return "", err
+	}
+	return EncodePublicKey(key.Public().(ed25519.PublicKey)), nil
+}
+
+// Export writes a signed bundle to w
+func (s *BundleService) Export(ctx context.Context, w io.Writer, opts ExportOptions) error {
+	key, err := LoadOrCreateSigningKey(s.keyPath)
+	if err != nil {
+		return err
+	}
+
+	sel, err := s.resolveSelection(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	manifest := &Manifest{CreatedAt: time.Now().UTC()}
+	bw := newWriter(w)
+
+	for _, wl := range sel.wordlists {
+		srcPath := s.wordlistManager.GetWordlistPath(wl.FileName, wl.WordlistType)
+		entryPath, err := bw.addFile(fmt.Sprintf("wordlists/%d/%s", wl.ID, filepath.Base(wl.FileName)), srcPath)
+		if err != nil {
+			return err
+		}
+		manifest.Wordlists = append(manifest.Wordlists, WordlistEntry{
+			ID: wl.ID, Name: wl.Name, Description: wl.Description, WordlistType: wl.WordlistType,
+			Format: wl.Format, FileName: wl.FileName, MD5Hash: wl.MD5Hash, WordCount: wl.WordCount,
+			Tags: wl.Tags, Path: entryPath,
+		})
+	}
+
+	for _, r := range sel.rules {
+		srcPath := s.ruleManager.GetRulePath(r.FileName, r.RuleType)
+		entryPath, err := bw.addFile(fmt.Sprintf("rules/%d/%s", r.ID, filepath.Base(r.FileName)), srcPath)
+		if err != nil {
+			return err
+		}
+		manifest.Rules = append(manifest.Rules, RuleEntry{
+			ID: r.ID, Name: r.Name, Description: r.Description, RuleType: r.RuleType,
+			FileName: r.FileName, MD5Hash: r.MD5Hash, RuleCount: r.RuleCount,
+			Tags: r.Tags, Path: entryPath,
+		})
+	}
+
+	for _, b := range sel.binaries {
+		entryPath, err := bw.addFile(fmt.Sprintf("binaries/%d/%s", b.ID, b.FileName), s.binaryManager.GetArchivePath(b))
+		if err != nil {
+			return err
+		}
+		manifest.Binaries = append(manifest.Binaries, BinaryEntry{
+			ID: b.ID, BinaryType: string(b.BinaryType), CompressionType: string(b.CompressionType),
+			SourceURL: b.SourceURL, FileName: b.FileName, MD5Hash: b.MD5Hash, IsDefault: b.IsDefault,
+			Path: entryPath,
+		})
+	}
+
+	manifest.PresetJobs = sel.presetJobs
+
+	if opts.IncludeSettings {
+		settings, err := s.settingsRepo.GetAllSettings(ctx)
+		if err != nil {
+			return err
+		}
+		for _, setting := range settings {
+			if !excludedSettings[setting.Key] {
+				manifest.Settings = append(manifest.Settings, SettingEntry{Key: setting.Key, Value: setting.Value})
+			}
+		}
+	}
+
+	if err := bw.finish(manifest, key); err != nil {
+		return err
+	}
+	debug.Info("Exported bundle with %d wordlists, %d rules, %d binaries, %d preset jobs and %d settings",
+		len(manifest.Wordlists), len(manifest.Rules), len(manifest.Binaries), len(manifest.PresetJobs), len(manifest.Settings))
+	return nil
+}
+
+// selection is the resolved content of a bundle
+type selection struct {
+	wordlists  []*models.Wordlist
+	rules      []*models.Rule
+	binaries   []*binary.BinaryVersion
+	presetJobs []models.PresetJob
+}
+
+// resolveSelection loads the selected items and the dependencies of the selected preset jobs
+func (s *BundleService) resolveSelection(ctx context.Context, opts ExportOptions) (*selection, error) {
+	sel := &selection{}
+	wordlistIDs := make(map[int]bool)
+	ruleIDs := make(map[int]bool)
+	binaryIDs := make(map[int64]bool)
+
+	if opts.empty() {
+		presetJobs, err := s.presetJobRepo.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sel.presetJobs = presetJobs
+
+		wordlists, err := s.wordlistManager.ListWordlists(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, wl := range wordlists {
+			wordlistIDs[wl.ID] = true
+		}
+		rules, err := s.ruleManager.ListRules(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rules {
+			ruleIDs[r.ID] = true
+		}
+		versions, err := s.binaryManager.ListVersions(ctx, map[string]interface{}{
+			"is_active":           true,
+			"verification_status": binary.VerificationStatusVerified,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			binaryIDs[v.ID] = true
+		}
+	} else {
+		for _, id := range opts.PresetJobIDs {
+			job, err := s.presetJobRepo.GetByID(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get preset job %s: %w", id, err)
+			}
+			sel.presetJobs = append(sel.presetJobs, *job)
+		}
+		for _, id := range opts.WordlistIDs {
+			wordlistIDs[id] = true
+		}
+		for _, id := range opts.RuleIDs {
+			ruleIDs[id] = true
+		}
+		for _, id := range opts.BinaryIDs {
+			binaryIDs[id] = true
+		}
+	}
+
+	for _, job := range sel.presetJobs {
+		for _, idStr := range job.WordlistIDs {
+			if id, err := strconv.Atoi(idStr); err == nil {
+				wordlistIDs[id] = true
+			}
+		}
+		for _, idStr := range job.RuleIDs {
+			if id, err := strconv.Atoi(idStr); err == nil {
+				ruleIDs[id] = true
+			}
+		}
+		binaryIDs[int64(job.BinaryVersionID)] = true
+	}
+
+	for id := range wordlistIDs {
+		wl, err := s.wordlistManager.GetWordlist(ctx, id)
+		if err != nil || wl == nil {
+			return nil, fmt.Errorf("failed to get wordlist %d: %v", id, err)
+		}
+		// The potfile belongs to this server's cracks, not to the deployment
+		if !wl.IsPotfile {
+			sel.wordlists = append(sel.wordlists, wl)
+		}
+	}
+	for id := range ruleIDs {
+		r, err := s.ruleManager.GetRule(ctx, id)
+		if err != nil || r == nil {
+			return nil, fmt.Errorf("failed to get rule %d: %v", id, err)
+		}
+		sel.rules = append(sel.rules, r)
+	}
+	for id := range binaryIDs {
+		v, err := s.binaryManager.GetVersion(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get binary version %d: %w", id, err)
+		}
+		sel.binaries = append(sel.binaries, v)
+	}
+	return sel, nil
+}
+
+// Import verifies a bundle read from r and installs its contents. Nothing is
+// installed unless the signature and every file check out.
+func (s *BundleService) Import(ctx context.Context, r io.Reader, userID uuid.UUID) (*ImportResult, error) {
+	if !s.importMu.TryLock() {
+		return nil, ErrImportRunning
+	}
+	defer s.importMu.Unlock()
+
+	trusted, err := s.trustedKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(s.stagingDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create bundle staging directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(s.stagingDir, "import-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle staging directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bundle, err := extract(r, dir)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := bundle.verify(trusted)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	binaryIDs := s.importBinaries(ctx, bundle, manifest, userID, result)
+	wordlistIDs := s.importWordlists(ctx, bundle, manifest, userID, result)
+	ruleIDs := s.importRules(ctx, bundle, manifest, userID, result)
+	s.importPresetJobs(ctx, manifest, wordlistIDs, ruleIDs, binaryIDs, result)
+	s.importSettings(ctx, manifest, result)
+
+	debug.Info("Imported bundle signed by %s: %+v", manifest.PublicKey, *result)
+	return result, nil
+}
+
+// trustedKeys returns this server's own key and the keys in bundle_trusted_keys
+func (s *BundleService) trustedKeys(ctx context.Context) ([]ed25519.PublicKey, error) {
+	key, err := LoadOrCreateSigningKey(s.keyPath)
+	if err != nil {
+		return nil, err
+	}
+	keys := []ed25519.PublicKey{key.Public().(ed25519.PublicKey)}
+
+	setting, err := s.settingsRepo.GetSetting(ctx, settingTrustedKeys)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return keys, nil
+		}
+		return nil, err
+	}
+	if setting.Value != nil {
+		trusted, err := ParsePublicKeys(*setting.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s setting: %w", settingTrustedKeys, err)
+		}
+		keys = append(keys, trusted...)
+	}
+	return keys, nil
+}
+
+// importBinaries installs the binaries and maps their exported IDs to local ones
+func (s *BundleService) importBinaries(ctx context.Context, bundle *extracted, manifest *Manifest, userID uuid.UUID, result *ImportResult) map[int64]int64 {
+	ids := make(map[int64]int64)
+	existing, err := s.binaryManager.ListVersions(ctx, nil)
+	if err != nil {
+		result.warn("failed to list binary versions: %v", err)
+		return ids
+	}
+
+	hasDefault := false
+	for _, v := range existing {
+		hasDefault = hasDefault || v.IsDefault
+	}
+
+	for _, entry := range manifest.Binaries {
+		var found *binary.BinaryVersion
+		for _, v := range existing {
+			if v.MD5Hash == entry.MD5Hash && v.IsActive {
+				found = v
+				break
+			}
+		}
+		if found != nil {
+			ids[entry.ID] = found.ID
+			result.Binaries.Skipped++
+			continue
+		}
+
+		version := &binary.BinaryVersion{
+			BinaryType:      binary.BinaryType(entry.BinaryType),
+			CompressionType: binary.CompressionType(entry.CompressionType),
+			SourceURL:       entry.SourceURL,
+			FileName:        entry.FileName,
+			MD5Hash:         entry.MD5Hash,
+			CreatedBy:       userID,
+			IsActive:        true,
+		}
+		if err := s.binaryManager.ImportVersion(ctx, version, bundle.path(entry.Path)); err != nil {
+			result.warn("binary %s was not imported: %v", entry.FileName, err)
+			continue
+		}
+		ids[entry.ID] = version.ID
+		result.Binaries.Imported++
+
+		if entry.IsDefault && !hasDefault {
+			if err := s.binaryManager.SetDefaultVersion(ctx, version.ID); err != nil {
+				result.warn("binary %s could not be made the default: %v", entry.FileName, err)
+			} else {
+				hasDefault = true
+			}
+		}
+	}
+	return ids
+}
+
+// importWordlists installs the wordlists and maps their exported IDs to local ones
+func (s *BundleService) importWordlists(ctx context.Context, bundle *extracted, manifest *Manifest, userID uuid.UUID, result *ImportResult) map[int]int {
+	ids := make(map[int]int)
+	for _, entry := range manifest.Wordlists {
+		existing, err := s.wordlistManager.GetWordlistByMD5Hash(ctx, entry.MD5Hash)
+		if err != nil {
+			result.warn("wordlist %s was not imported: %v", entry.Name, err)
+			continue
+		}
+		if existing != nil {
+			ids[entry.ID] = existing.ID
+			result.Wordlists.Skipped++
+			continue
+		}
+
+		destPath := s.wordlistManager.GetWordlistPath(entry.FileName, entry.WordlistType)
+		if err := installFile(bundle.path(entry.Path), destPath); err != nil {
+			result.warn("wordlist %s was not imported: %v", entry.Name, err)
+			continue
+		}
+
+		info, err := os.Stat(destPath)
+		if err != nil {
+			result.warn("wordlist %s was not imported: %v", entry.Name, err)
+			continue
+		}
+		wl, err := s.wordlistManager.AddWordlist(ctx, &models.WordlistAddRequest{
+			Name:         entry.Name,
+			Description:  entry.Description,
+			WordlistType: entry.WordlistType,
+			Format:       entry.Format,
+			FileName:     entry.FileName,
+			MD5Hash:      entry.MD5Hash,
+			FileSize:     info.Size(),
+			WordCount:    entry.WordCount,
+			Tags:         entry.Tags,
+		}, userID)
+		if err != nil {
+			os.Remove(destPath)
+			result.warn("wordlist %s was not imported: %v", entry.Name, err)
+			continue
+		}
+		wordCount := entry.WordCount
+		if err := s.wordlistManager.VerifyWordlist(ctx, wl.ID, &models.WordlistVerifyRequest{Status: "verified", WordCount: &wordCount}); err != nil {
+			result.warn("wordlist %s could not be marked verified: %v", entry.Name, err)
+		}
+		ids[entry.ID] = wl.ID
+		result.Wordlists.Imported++
+	}
+	return ids
+}
+
+// importRules installs the rule files and maps their exported IDs to local ones
+func (s *BundleService) importRules(ctx context.Context, bundle *extracted, manifest *Manifest, userID uuid.UUID, result *ImportResult) map[int]int {
+	ids := make(map[int]int)
+	for _, entry := range manifest.Rules {
+		existing, err := s.ruleManager.GetRuleByMD5Hash(ctx, entry.MD5Hash)
+		if err != nil {
+			result.warn("rule %s was not imported: %v", entry.Name, err)
+			continue
+		}
+		if existing != nil {
+			ids[entry.ID] = existing.ID
+			result.Rules.Skipped++
+			continue
+		}
+
+		destPath := s.ruleManager.GetRulePath(entry.FileName, entry.RuleType)
+		if err := installFile(bundle.path(entry.Path), destPath); err != nil {
+			result.warn("rule %s was not imported: %v", entry.Name, err)
+			continue
+		}
+
+		info, err := os.Stat(destPath)
+		if err != nil {
+			result.warn("rule %s was not imported: %v", entry.Name, err)
+			continue
+		}
+		r, err := s.ruleManager.AddRule(ctx, &models.RuleAddRequest{
+			Name:        entry.Name,
+			Description: entry.Description,
+			RuleType:    entry.RuleType,
+			FileName:    entry.FileName,
+			MD5Hash:     entry.MD5Hash,
+			FileSize:    info.Size(),
+			RuleCount:   entry.RuleCount,
+			Tags:        entry.Tags,
+		}, userID)
+		if err != nil {
+			os.Remove(destPath)
+			result.warn("rule %s was not imported: %v", entry.Name, err)
+			continue
+		}
+		ruleCount := entry.RuleCount
+		if err := s.ruleManager.VerifyRule(ctx, r.ID, &models.RuleVerifyRequest{Status: "verified", RuleCount: &ruleCount}); err != nil {
+			result.warn("rule %s could not be marked verified: %v", entry.Name, err)
+		}
+		ids[entry.ID] = r.ID
+		result.Rules.Imported++
+	}
+	return ids
+}
+
+// importPresetJobs creates the preset jobs whose wordlists, rules and binary
+// are all available, skipping those with a name already in use
+func (s *BundleService) importPresetJobs(ctx context.Context, manifest *Manifest, wordlistIDs, ruleIDs map[int]int, binaryIDs map[int64]int64, result *ImportResult) {
+	for _, job := range manifest.PresetJobs {
+		if _, err := s.presetJobRepo.GetByName(ctx, job.Name); err == nil {
+			result.PresetJobs.Skipped++
+			continue
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			result.warn("preset job %s was not imported: %v", job.Name, err)
+			continue
+		}
+
+		wordlists, ok := remapIDs(job.WordlistIDs, wordlistIDs)
+		if !ok {
+			result.warn("preset job %s was not imported: a wordlist it uses is missing", job.Name)
+			continue
+		}
+		rules, ok := remapIDs(job.RuleIDs, ruleIDs)
+		if !ok {
+			result.warn("preset job %s was not imported: a rule it uses is missing", job.Name)
+			continue
+		}
+		binaryID, ok := binaryIDs[int64(job.BinaryVersionID)]
+		if !ok {
+			result.warn("preset job %s was not imported: its binary is missing", job.Name)
+			continue
+		}
+
+		job.WordlistIDs = wordlists
+		job.RuleIDs = rules
+		job.BinaryVersionID = int(binaryID)
+		if _, err := s.presetJobRepo.Create(ctx, job); err != nil {
+			result.warn("preset job %s was not imported: %v", job.Name, err)
+			continue
+		}
+		result.PresetJobs.Imported++
+	}
+}
+
+// remapIDs translates exported IDs to local ones, false if any is unknown
+func remapIDs(ids models.IDArray, mapping map[int]int) (models.IDArray, bool) {
+	remapped := make(models.IDArray, 0, len(ids))
+	for _, idStr := range ids {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			return nil, false
+		}
+		localID, ok := mapping[id]
+		if !ok {
+			return nil, false
+		}
+		remapped = append(remapped, strconv.Itoa(localID))
+	}
+	return remapped, true
+}
+
+// importSettings updates settings this server knows, unknown keys are skipped
+func (s *BundleService) importSettings(ctx context.Context, manifest *Manifest, result *ImportResult) {
+	for _, setting := range manifest.Settings {
+		if excludedSettings[setting.Key] {
+			result.Settings.Skipped++
+			continue
+		}
+		if err := s.settingsRepo.SetSetting(ctx, setting.Key, setting.Value); err != nil {
+			if !errors.Is(err, repository.ErrNotFound) {
+				result.warn("setting %s was not imported: %v", setting.Key, err)
+			}
+			result.Settings.Skipped++
+			continue
+		}
+		result.Settings.Imported++
+	}
+}
+
+// installFile moves an extracted file to its destination, refusing to
+// overwrite a different file that is already there
+func installFile(src, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("a different file already exists at %s", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.Rename(src, dest); err == nil {
+		return nil
+	}
+
+	// The staging directory may be on another filesystem
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+	return out.Close()
+}
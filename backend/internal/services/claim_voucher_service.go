@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -60,8 +61,13 @@ func formatClaimCode(code string) string {
 		code[15:20])
 }
 
-// CreateTempVoucher creates a temporary claim voucher
-func (s *ClaimVoucherService) CreateTempVoucher(ctx context.Context, userID string, expiresIn time.Duration, isContinuous bool) (*models.ClaimVoucher, error) {
+// ErrInvalidVoucherRequest is returned for voucher settings that cannot be applied
+var ErrInvalidVoucherRequest = errors.New("invalid voucher request")
+
+// CreateTempVoucher creates a temporary claim voucher. A positive expiresIn
+// makes the voucher expire, maxUses caps how many agents a continuous voucher
+// registers and labels are applied to every agent it registers.
+func (s *ClaimVoucherService) CreateTempVoucher(ctx context.Context, userID string, expiresIn time.Duration, isContinuous bool, maxUses *int, labels []string) (*models.ClaimVoucher, error) {
 	// Parse user ID to UUID
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
@@ -69,15 +75,38 @@ func (s *ClaimVoucherService) CreateTempVoucher(ctx context.Context, userID stri
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
+	if expiresIn < 0 {
+		return nil, fmt.Errorf("%w: expiry must not be negative", ErrInvalidVoucherRequest)
+	}
+	if maxUses != nil {
+		if !isContinuous {
+			return nil, fmt.Errorf("%w: usage caps only apply to continuous vouchers", ErrInvalidVoucherRequest)
+		}
+		if *maxUses < 1 {
+			return nil, fmt.Errorf("%w: usage cap must be at least 1", ErrInvalidVoucherRequest)
+		}
+	}
+	labels, err = models.NormalizeAgentLabels(labels)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVoucherRequest, err)
+	}
+
 	// Create voucher with normalized code for storage
 	code := generateClaimCode()
+	now := time.Now()
 	voucher := &models.ClaimVoucher{
 		Code:         normalizeClaimCode(code),
 		IsActive:     true,
 		IsContinuous: isContinuous,
+		MaxUses:      maxUses,
+		Labels:       labels,
 		CreatedByID:  userUUID,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if expiresIn > 0 {
+		expiresAt := now.Add(expiresIn)
+		voucher.ExpiresAt = &expiresAt
 	}
 
 	// Save voucher
@@ -137,8 +166,8 @@ func (s *ClaimVoucherService) UseVoucher(ctx context.Context, code string, agent
 		return fmt.Errorf("invalid claim code")
 	}
 
-	if !voucher.IsValid() {
-		return fmt.Errorf("claim code is not active")
+	if err := voucher.Validate(time.Now()); err != nil {
+		return err
 	}
 
 	return nil
@@ -155,23 +184,37 @@ func (s *ClaimVoucherService) MarkVoucherAsUsed(ctx context.Context, code string
 		return fmt.Errorf("invalid claim code")
 	}
 
-	if !voucher.IsValid() {
-		return fmt.Errorf("claim code is not active")
+	if err := voucher.Validate(time.Now()); err != nil {
+		return err
 	}
 
-	// Mark voucher as used by the agent
-	if err := s.repo.UseByAgent(ctx, normalizedCode, agentID); err != nil {
+	// Count the registration, this deactivates single-use and exhausted vouchers
+	if err := s.repo.Redeem(ctx, normalizedCode, agentID); err != nil {
 		return fmt.Errorf("failed to mark voucher as used: %w", err)
 	}
 
-	// For single-use vouchers, deactivate after use
-	if !voucher.IsContinuous {
-		if err := s.repo.Deactivate(ctx, normalizedCode); err != nil {
-			return fmt.Errorf("failed to deactivate voucher: %w", err)
-		}
+	return nil
+}
+
+// ListVoucherUsage retrieves the registrations made with a voucher and the
+// agents they created
+func (s *ClaimVoucherService) ListVoucherUsage(ctx context.Context, code string) ([]models.ClaimVoucherUsage, error) {
+	normalizedCode := normalizeClaimCode(code)
+
+	// Make sure the voucher exists so unknown codes are reported as such
+	if _, err := s.repo.GetByCode(ctx, normalizedCode); err != nil {
+		return nil, err
 	}
 
-	return nil
+	usage, err := s.repo.ListUsage(ctx, normalizedCode)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range usage {
+		usage[i].VoucherCode = formatClaimCode(usage[i].VoucherCode)
+	}
+	return usage, nil
 }
 
 // ValidateClaimCode validates a claim code
@@ -190,9 +233,9 @@ func (s *ClaimVoucherService) ValidateClaimCode(ctx context.Context, claimCode s
 		return fmt.Errorf("claim code is not active")
 	}
 
-	if !voucher.IsValid() {
-		debug.Error("Claim code is not valid")
-		return fmt.Errorf("claim code is not valid")
+	if err := voucher.Validate(time.Now()); err != nil {
+		debug.Error("Claim code is not valid: %v", err)
+		return err
 	}
 
 	debug.Info("Claim code validated successfully")
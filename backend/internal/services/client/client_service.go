@@ -36,7 +36,7 @@ func (s *ClientService) DeleteClient(ctx context.Context, clientID uuid.UUID) er
 	debug.Debug("Starting client deletion process for ID: %s", clientID)
 
 	// 1. Get the client to check its retention policy
-	client, err := s.clientRepo.GetByID(ctx, clientID)
+	client, err := s.clientRepo.GetByIDIncludingDeleted(ctx, clientID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return err
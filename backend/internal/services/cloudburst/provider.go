@@ -0,0 +1,96 @@
+package cloudburst
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// commandTimeout bounds a single launch or terminate command
+const commandTimeout = 5 * time.Minute
+
+// Provider launches and terminates cloud instances
+type Provider interface {
+	// Launch starts an instance booting with the cloud-init user data and
+	// returns the provider's ID for it
+	Launch(ctx context.Context, name, userData string) (string, error)
+	// Terminate tears down an instance started by Launch
+	Terminate(ctx context.Context, instanceID string) error
+}
+
+// commandProvider runs admin-supplied shell commands, so any cloud with a CLI
+// can be used without the backend knowing its API. The commands come from the
+// environment rather than system settings so they cannot be changed over the API.
+type commandProvider struct {
+	launchCommand    string
+	terminateCommand string
+}
+
+// NewCommandProvider returns a provider running the launch and terminate
+// commands, or nil when no launch command is configured.
+//
+// The launch command gets the cloud-init user data on stdin and the instance
+// name in KH_INSTANCE_NAME, and prints the instance ID as the last line of its
+// output. The terminate command gets that ID in KH_INSTANCE_ID.
+func NewCommandProvider(launchCommand, terminateCommand string) Provider {
+	if launchCommand == "" {
+		return nil
+	}
+	return &commandProvider{launchCommand: launchCommand, terminateCommand: terminateCommand}
+}
+
+// Launch implements Provider
+func (p *commandProvider) Launch(ctx context.Context, name, userData string) (string, error) {
+	output, err := p.run(ctx, p.launchCommand, strings.NewReader(userData), "KH_INSTANCE_NAME="+name)
+	if err != nil {
+		return "", fmt.Errorf("launch command failed: %w", err)
+	}
+	instanceID := lastLine(output)
+	if instanceID == "" {
+		return "", fmt.Errorf("launch command printed no instance ID")
+	}
+	return instanceID, nil
+}
+
+// Terminate implements Provider
+func (p *commandProvider) Terminate(ctx context.Context, instanceID string) error {
+	if p.terminateCommand == "" {
+		return fmt.Errorf("no terminate command is configured")
+	}
+	if _, err := p.run(ctx, p.terminateCommand, nil, "KH_INSTANCE_ID="+instanceID); err != nil {
+		return fmt.Errorf("terminate command failed: %w", err)
+	}
+	return nil
+}
+
+func (p *commandProvider) run(ctx context.Context, command string, stdin *strings.Reader, env ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := lastLine(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: %s", err, message)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// lastLine returns the last non-empty line of command output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
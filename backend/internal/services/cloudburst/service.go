@@ -0,0 +1,403 @@
+package cloudburst
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// Settings controlling cloud burst
+const (
+	settingEnabled             = "cloud_burst_enabled"
+	settingMaxInstances        = "cloud_burst_max_instances"
+	settingHourlyCost          = "cloud_burst_hourly_cost"
+	settingMonthlyBudget       = "cloud_burst_monthly_budget"
+	settingIdleMinutes         = "cloud_burst_idle_minutes"
+	settingRegistrationTimeout = "cloud_burst_registration_timeout_minutes"
+	settingCloudInitTemplate   = "cloud_burst_cloud_init_template"
+)
+
+const (
+	// schedulerTick is how often instances are launched, checked and torn down
+	schedulerTick = time.Minute
+	// defaultMaxInstances is used when cloud_burst_max_instances is missing or invalid
+	defaultMaxInstances = 2
+	// defaultIdle is used when cloud_burst_idle_minutes is missing or invalid
+	defaultIdle = 10 * time.Minute
+	// defaultRegistrationTimeout is used when cloud_burst_registration_timeout_minutes is missing or invalid
+	defaultRegistrationTimeout = 20 * time.Minute
+	// recentInstances is how many instances the admin list shows
+	recentInstances = 100
+)
+
+var (
+	// ErrNotActive is returned when terminating an instance that already ended
+	ErrNotActive = errors.New("cloud burst instance is not running")
+	// ErrNoProvider is returned when no launch command is configured
+	ErrNoProvider = errors.New("no cloud burst provider is configured, set KH_CLOUD_BURST_LAUNCH_COMMAND")
+)
+
+// settings are the cloud burst system settings read on every tick
+type settings struct {
+	enabled             bool
+	maxInstances        int
+	hourlyCost          float64
+	monthlyBudget       float64
+	idle                time.Duration
+	registrationTimeout time.Duration
+	cloudInitTemplate   string
+}
+
+// userData is what the cloud-init template is rendered with
+type userData struct {
+	Name      string
+	ClaimCode string
+}
+
+// Service launches temporary cloud agents when jobs that allow cloud burst
+// have work and no agent is free, and tears them down once the queue drains,
+// they fail to register or the monthly budget is reached.
+type Service struct {
+	repo         *repository.CloudBurstRepository
+	settingsRepo *repository.SystemSettingsRepository
+	vouchers     *services.ClaimVoucherService
+	provider     Provider
+}
+
+// NewService creates a new cloud burst service. provider may be nil, instances
+// are then neither launched nor terminated.
+func NewService(repo *repository.CloudBurstRepository, sr *repository.SystemSettingsRepository, vouchers *services.ClaimVoucherService, provider Provider) *Service {
+	return &Service{
+		repo:         repo,
+		settingsRepo: sr,
+		vouchers:     vouchers,
+		provider:     provider,
+	}
+}
+
+// StartScheduler reconciles the cloud burst instances with the queue until ctx is done
+func (s *Service) StartScheduler(ctx context.Context) {
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+
+	for {
+		s.reconcile(ctx)
+
+		select {
+		case <-ctx.Done():
+			debug.Info("Cloud burst scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcile records registered agents, tears down instances that failed to
+// register, sat idle or exceed the budget, and launches one instance if the
+// queue needs it
+func (s *Service) reconcile(ctx context.Context) {
+	cfg := s.loadSettings(ctx)
+
+	if registered, err := s.repo.MarkRegistered(ctx); err != nil {
+		debug.Error("Failed to check cloud burst registrations: %v", err)
+	} else if registered > 0 {
+		debug.Info("%d cloud burst agent(s) registered", registered)
+	}
+
+	instances, err := s.repo.ListActive(ctx)
+	if err != nil {
+		debug.Error("Failed to list cloud burst instances: %v", err)
+		return
+	}
+	if !cfg.enabled && len(instances) == 0 {
+		return
+	}
+
+	spend, err := s.repo.GetMonthSpend(ctx)
+	if err != nil {
+		debug.Error("Failed to get cloud burst spend: %v", err)
+		return
+	}
+	if budgetReached(spend, cfg.monthlyBudget) {
+		for i := range instances {
+			s.terminate(ctx, &instances[i], models.CloudBurstTerminated, "monthly budget reached")
+		}
+		return
+	}
+
+	jobsWithWork, err := s.repo.CountBurstableJobsWithWork(ctx)
+	if err != nil {
+		debug.Error("Failed to count jobs for cloud burst: %v", err)
+		return
+	}
+
+	now := time.Now()
+	active, provisioning := 0, 0
+	for i := range instances {
+		instance := &instances[i]
+		switch instance.Status {
+		case models.CloudBurstProvisioning:
+			if now.Sub(instance.LaunchedAt) > cfg.registrationTimeout {
+				err := s.terminate(ctx, instance, models.CloudBurstFailed,
+					fmt.Sprintf("agent did not register within %s", cfg.registrationTimeout))
+				if err == nil {
+					continue
+				}
+			}
+			provisioning++
+		case models.CloudBurstRunning:
+			if s.agentBusy(ctx, instance) {
+				if err := s.repo.TouchBusy(ctx, instance.ID); err != nil {
+					debug.Warning("Failed to record activity of cloud burst instance %d: %v", instance.ID, err)
+				}
+			} else if jobsWithWork == 0 && idleExpired(instance, cfg.idle, now) {
+				if err := s.terminate(ctx, instance, models.CloudBurstTerminated, "idle"); err == nil {
+					continue
+				}
+			}
+		}
+		active++
+	}
+
+	if !cfg.enabled || s.provider == nil || jobsWithWork == 0 || provisioning > 0 {
+		return
+	}
+	idleAgents, err := s.repo.CountIdleAgents(ctx)
+	if err != nil {
+		debug.Error("Failed to count idle agents for cloud burst: %v", err)
+		return
+	}
+	if reason := launchBlocked(cfg, idleAgents, active, spend); reason != "" {
+		debug.Debug("Not launching a cloud burst instance for %d job(s): %s", jobsWithWork, reason)
+		return
+	}
+	if _, err := s.launch(ctx, cfg); err != nil {
+		debug.Error("Failed to launch cloud burst instance: %v", err)
+	}
+}
+
+// launch starts one instance with a single-use claim voucher that labels its
+// agent as a cloud burst agent
+func (s *Service) launch(ctx context.Context, cfg settings) (*models.CloudBurstInstance, error) {
+	// The system user issues the voucher, it expires with the registration timeout
+	voucher, err := s.vouchers.CreateTempVoucher(ctx, uuid.Nil.String(), cfg.registrationTimeout, false, nil,
+		[]string{models.CloudBurstAgentLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create claim voucher: %w", err)
+	}
+
+	instance := &models.CloudBurstInstance{
+		Name:       fmt.Sprintf("kh-burst-%d", time.Now().Unix()),
+		ClaimCode:  voucher.Code,
+		Status:     models.CloudBurstProvisioning,
+		HourlyCost: cfg.hourlyCost,
+	}
+	data, err := renderUserData(cfg.cloudInitTemplate, userData{Name: instance.Name, ClaimCode: voucher.Code})
+	if err != nil {
+		s.disableVoucher(ctx, voucher.Code)
+		return nil, err
+	}
+
+	// Recorded before launching so an instance is never running untracked
+	if err := s.repo.Create(ctx, instance); err != nil {
+		s.disableVoucher(ctx, voucher.Code)
+		return nil, err
+	}
+
+	providerID, err := s.provider.Launch(ctx, instance.Name, data)
+	if err != nil {
+		message := err.Error()
+		if endErr := s.repo.MarkEnded(ctx, instance.ID, models.CloudBurstFailed, &message); endErr != nil {
+			debug.Error("Failed to record failed launch of cloud burst instance %d: %v", instance.ID, endErr)
+		}
+		s.disableVoucher(ctx, voucher.Code)
+		return nil, err
+	}
+	instance.ProviderInstanceID = &providerID
+	if err := s.repo.SetProviderInstanceID(ctx, instance.ID, providerID); err != nil {
+		return nil, err
+	}
+
+	debug.Info("Launched cloud burst instance %s (%s)", instance.Name, providerID)
+	return instance, nil
+}
+
+// terminate tears an instance down at the provider, disables its agent and
+// records why it ended. A failed terminate is retried on the next tick.
+func (s *Service) terminate(ctx context.Context, instance *models.CloudBurstInstance, status models.CloudBurstInstanceStatus, reason string) error {
+	if instance.ProviderInstanceID != nil {
+		if s.provider == nil {
+			return ErrNoProvider
+		}
+		if err := s.provider.Terminate(ctx, *instance.ProviderInstanceID); err != nil {
+			debug.Error("Failed to terminate cloud burst instance %s: %v", instance.Name, err)
+			if recordErr := s.repo.RecordError(ctx, instance.ID, err.Error()); recordErr != nil {
+				debug.Warning("Failed to record error of cloud burst instance %d: %v", instance.ID, recordErr)
+			}
+			return err
+		}
+	}
+
+	if instance.AgentID != nil {
+		if err := s.repo.DisableAgent(ctx, *instance.AgentID); err != nil {
+			debug.Warning("Failed to disable agent %d of cloud burst instance %s: %v", *instance.AgentID, instance.Name, err)
+		}
+	} else {
+		s.disableVoucher(ctx, instance.ClaimCode)
+	}
+
+	if err := s.repo.MarkEnded(ctx, instance.ID, status, &reason); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotActive
+		}
+		return err
+	}
+
+	debug.Info("Terminated cloud burst instance %s: %s", instance.Name, reason)
+	return nil
+}
+
+// Terminate tears down an instance on an admin's request
+func (s *Service) Terminate(ctx context.Context, id int64) error {
+	instance, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !instance.Active() {
+		return ErrNotActive
+	}
+	return s.terminate(ctx, instance, models.CloudBurstTerminated, "terminated by an admin")
+}
+
+// ListInstances returns the most recently launched instances
+func (s *Service) ListInstances(ctx context.Context) ([]models.CloudBurstInstance, error) {
+	return s.repo.ListRecent(ctx, recentInstances)
+}
+
+// MonthSpend returns this month's spend and the monthly budget (0 = no cap)
+func (s *Service) MonthSpend(ctx context.Context) (float64, float64, error) {
+	spend, err := s.repo.GetMonthSpend(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return spend, s.loadSettings(ctx).monthlyBudget, nil
+}
+
+// agentBusy reports whether the instance's agent has a task; errors count as
+// busy so an instance is never torn down on a failed lookup
+func (s *Service) agentBusy(ctx context.Context, instance *models.CloudBurstInstance) bool {
+	if instance.AgentID == nil {
+		return false
+	}
+	busy, err := s.repo.AgentBusy(ctx, *instance.AgentID)
+	if err != nil {
+		debug.Warning("Failed to check agent of cloud burst instance %s: %v", instance.Name, err)
+		return true
+	}
+	return busy
+}
+
+func (s *Service) disableVoucher(ctx context.Context, code string) {
+	if err := s.vouchers.DisableVoucher(ctx, code); err != nil {
+		debug.Warning("Failed to disable cloud burst claim voucher: %v", err)
+	}
+}
+
+// loadSettings reads the cloud burst settings, falling back to defaults for
+// missing or invalid values
+func (s *Service) loadSettings(ctx context.Context) settings {
+	cfg := settings{
+		maxInstances:        defaultMaxInstances,
+		idle:                defaultIdle,
+		registrationTimeout: defaultRegistrationTimeout,
+		cloudInitTemplate:   s.settingValue(ctx, settingCloudInitTemplate),
+	}
+	cfg.enabled, _ = strconv.ParseBool(s.settingValue(ctx, settingEnabled))
+	if n, err := strconv.Atoi(s.settingValue(ctx, settingMaxInstances)); err == nil && n > 0 {
+		cfg.maxInstances = n
+	}
+	if f, err := strconv.ParseFloat(s.settingValue(ctx, settingHourlyCost), 64); err == nil && f > 0 {
+		cfg.hourlyCost = f
+	}
+	if f, err := strconv.ParseFloat(s.settingValue(ctx, settingMonthlyBudget), 64); err == nil && f > 0 {
+		cfg.monthlyBudget = f
+	}
+	if n, err := strconv.Atoi(s.settingValue(ctx, settingIdleMinutes)); err == nil && n > 0 {
+		cfg.idle = time.Duration(n) * time.Minute
+	}
+	if n, err := strconv.Atoi(s.settingValue(ctx, settingRegistrationTimeout)); err == nil && n > 0 {
+		cfg.registrationTimeout = time.Duration(n) * time.Minute
+	}
+	return cfg
+}
+
+// settingValue returns a system setting, or "" when it is missing
+func (s *Service) settingValue(ctx context.Context, key string) string {
+	setting, err := s.settingsRepo.GetSetting(ctx, key)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			debug.Warning("Failed to read setting %s: %v", key, err)
+		}
+		return ""
+	}
+	if setting.Value == nil {
+		return ""
+	}
+	return *setting.Value
+}
+
+// budgetReached reports whether this month's spend used up the budget (0 = no cap)
+func budgetReached(spend, budget float64) bool {
+	return budget > 0 && spend >= budget
+}
+
+// launchBlocked returns why no instance may be launched now, or "" if one
+// may. An idle agent can take the work itself; the budget must cover another
+// hour of every active instance plus the new one.
+func launchBlocked(cfg settings, idleAgents, active int, spend float64) string {
+	if idleAgents > 0 {
+		return fmt.Sprintf("%d agent(s) are idle", idleAgents)
+	}
+	if active >= cfg.maxInstances {
+		return fmt.Sprintf("%d of %d instances are running", active, cfg.maxInstances)
+	}
+	if cfg.monthlyBudget > 0 && spend+cfg.hourlyCost*float64(active+1) > cfg.monthlyBudget {
+		return fmt.Sprintf("another hour would exceed the monthly budget of %.2f", cfg.monthlyBudget)
+	}
+	return ""
+}
+
+// idleExpired reports whether a running instance's agent has been idle too long
+func idleExpired(instance *models.CloudBurstInstance, idle time.Duration, now time.Time) bool {
+	lastBusy := instance.LaunchedAt
+	if instance.LastBusyAt != nil {
+		lastBusy = *instance.LastBusyAt
+	}
+	return now.Sub(lastBusy) >= idle
+}
+
+// renderUserData fills in the cloud-init template for one instance
+func renderUserData(text string, data userData) (string, error) {
+	if text == "" {
+		return "", fmt.Errorf("%s is empty", settingCloudInitTemplate)
+	}
+	tmpl, err := template.New(settingCloudInitTemplate).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", settingCloudInitTemplate, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", settingCloudInitTemplate, err)
+	}
+	return out.String(), nil
+}
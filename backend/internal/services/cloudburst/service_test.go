@@ -0,0 +1,60 @@
+package cloudburst
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLaunchBlocked(t *testing.T) {
+	cfg := settings{maxInstances: 2, hourlyCost: 3, monthlyBudget: 100}
+
+	assert.Empty(t, launchBlocked(cfg, 0, 0, 0))
+	assert.Contains(t, launchBlocked(cfg, 1, 0, 0), "idle")
+	assert.Contains(t, launchBlocked(cfg, 0, 2, 0), "2 of 2")
+
+	// Another hour of the running instance plus the new one must fit the budget
+	assert.Empty(t, launchBlocked(cfg, 0, 1, 94))
+	assert.Contains(t, launchBlocked(cfg, 0, 1, 95), "budget")
+
+	// Without a budget only the instance cap applies
+	cfg.monthlyBudget = 0
+	assert.Empty(t, launchBlocked(cfg, 0, 1, 10000))
+}
+
+func TestBudgetReached(t *testing.T) {
+	assert.False(t, budgetReached(500, 0))
+	assert.False(t, budgetReached(99.5, 100))
+	assert.True(t, budgetReached(100, 100))
+}
+
+func TestIdleExpired(t *testing.T) {
+	now := time.Now()
+	lastBusy := now.Add(-5 * time.Minute)
+	instance := &models.CloudBurstInstance{LaunchedAt: now.Add(-time.Hour), LastBusyAt: &lastBusy}
+
+	assert.False(t, idleExpired(instance, 10*time.Minute, now))
+	assert.True(t, idleExpired(instance, 5*time.Minute, now))
+
+	instance.LastBusyAt = nil
+	assert.True(t, idleExpired(instance, 10*time.Minute, now))
+}
+
+func TestRenderUserData(t *testing.T) {
+	out, err := renderUserData("claim={{.ClaimCode}} name={{.Name}}", userData{Name: "kh-burst-1", ClaimCode: "ABCDE-FGHIJ"})
+	require.NoError(t, err)
+	assert.Equal(t, "claim=ABCDE-FGHIJ name=kh-burst-1", out)
+
+	_, err = renderUserData("", userData{})
+	assert.Error(t, err)
+	_, err = renderUserData("{{.Region}}", userData{})
+	assert.Error(t, err)
+}
+
+func TestLastLine(t *testing.T) {
+	assert.Equal(t, "i-0abc", lastLine("Creating instance...\ni-0abc\n\n"))
+	assert.Equal(t, "", lastLine(""))
+}
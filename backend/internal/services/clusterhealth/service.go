@@ -0,0 +1,150 @@
+// Package clusterhealth builds a single summary of the cluster for dashboards
+// and external monitoring: agents online, hash rate per hash type class, queue
+// depth and age, the chunk failure rate of the last hour and the storage used
+// by the data directory. Monitoring systems read it with a bearer token so
+// they do not need a user session.
+package clusterhealth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// settingToken is the bearer token monitoring systems scrape the summary with
+const settingToken = "cluster_health_token"
+
+// failureWindow is the period the chunk failure rate covers
+const failureWindow = time.Hour
+
+var (
+	// ErrScrapeDisabled is returned when no scrape token is configured
+	ErrScrapeDisabled = errors.New("cluster health scraping is disabled")
+	// ErrUnauthorized is returned for a wrong scrape token
+	ErrUnauthorized = errors.New("invalid cluster health token")
+)
+
+// ClusterHealthService builds the cluster health summary
+type ClusterHealthService struct {
+	repo         *repository.ClusterHealthRepository
+	settingsRepo *repository.SystemSettingsRepository
+	dataDir      string
+	hubMetrics   func() *models.WebSocketHubMetrics
+}
+
+// NewClusterHealthService creates a new ClusterHealthService reporting the
+// storage of dataDir
+func NewClusterHealthService(repo *repository.ClusterHealthRepository, sr *repository.SystemSettingsRepository, dataDir string) *ClusterHealthService {
+	return &ClusterHealthService{
+		repo:         repo,
+		settingsRepo: sr,
+		dataDir:      dataDir,
+	}
+}
+
+// SetHubMetrics sets where the agent WebSocket metrics are read from. It
+// returns nil while the hub is not running.
+func (s *ClusterHealthService) SetHubMetrics(hubMetrics func() *models.WebSocketHubMetrics) {
+	s.hubMetrics = hubMetrics
+}
+
+// Get builds the cluster health summary
+func (s *ClusterHealthService) Get(ctx context.Context) (*models.ClusterHealth, error) {
+	now := time.Now()
+	health := &models.ClusterHealth{GeneratedAt: now}
+
+	var err error
+	if health.AgentsOnline, health.AgentsTotal, err = s.repo.CountAgents(ctx); err != nil {
+		return nil, err
+	}
+	if health.HashRates, err = s.repo.GetHashRates(ctx); err != nil {
+		return nil, err
+	}
+
+	var oldest *time.Time
+	if health.QueueDepth, oldest, err = s.repo.GetQueueStats(ctx); err != nil {
+		return nil, err
+	}
+	health.OldestPendingJobAgeSeconds = ageSeconds(now, oldest)
+
+	if health.ChunksFinishedLastHour, health.ChunksFailedLastHour, err = s.repo.CountFinishedTasks(ctx, now.Add(-failureWindow)); err != nil {
+		return nil, err
+	}
+	health.ChunkFailureRate = failureRate(health.ChunksFinishedLastHour, health.ChunksFailedLastHour)
+
+	if health.Storage, err = storageUsage(s.dataDir); err != nil {
+		debug.Warning("Failed to read storage usage of %s: %v", s.dataDir, err)
+	}
+	if s.hubMetrics != nil {
+		health.WebSocket = s.hubMetrics()
+	}
+
+	return health, nil
+}
+
+// Scrape builds the summary for a monitoring system authenticated with the
+// scrape token
+func (s *ClusterHealthService) Scrape(ctx context.Context, token string) (*models.ClusterHealth, error) {
+	expected := s.scrapeToken(ctx)
+	if expected == "" {
+		return nil, ErrScrapeDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		return nil, ErrUnauthorized
+	}
+	return s.Get(ctx)
+}
+
+// scrapeToken returns the configured scrape token, empty when scraping is off
+func (s *ClusterHealthService) scrapeToken(ctx context.Context) string {
+	setting, err := s.settingsRepo.GetSetting(ctx, settingToken)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			debug.Warning("Failed to read setting %s: %v", settingToken, err)
+		}
+		return ""
+	}
+	if setting.Value == nil {
+		return ""
+	}
+	return *setting.Value
+}
+
+// ageSeconds returns how long ago a time was, 0 for nil
+func ageSeconds(now time.Time, t *time.Time) int64 {
+	if t == nil || t.After(now) {
+		return 0
+	}
+	return int64(now.Sub(*t).Seconds())
+}
+
+// failureRate returns the share of finished chunks that failed
+func failureRate(finished, failed int) float64 {
+	if finished == 0 {
+		return 0
+	}
+	return float64(failed) / float64(finished)
+}
+
+// newStorage builds the storage usage from filesystem sizes. free is the space
+// available to the backend, unused the space not used by anyone including the
+// blocks reserved for root.
+func newStorage(path string, total, free, unused uint64) *models.ClusterStorage {
+	storage := &models.ClusterStorage{
+		Path:       path,
+		TotalBytes: total,
+		FreeBytes:  free,
+	}
+	if total >= unused {
+		storage.UsedBytes = total - unused
+	}
+	if usable := storage.UsedBytes + free; usable > 0 {
+		storage.UsedPercent = float64(storage.UsedBytes) / float64(usable) * 100
+	}
+	return storage
+}
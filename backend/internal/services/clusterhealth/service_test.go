@@ -0,0 +1,45 @@
+package clusterhealth
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgeSeconds(t *testing.T) {
+	now := time.Now()
+	created := now.Add(-90 * time.Second)
+	future := now.Add(time.Minute)
+
+	assert.Equal(t, int64(90), ageSeconds(now, &created))
+	assert.Equal(t, int64(0), ageSeconds(now, nil))
+	assert.Equal(t, int64(0), ageSeconds(now, &future))
+}
+
+func TestFailureRate(t *testing.T) {
+	assert.Equal(t, 0.0, failureRate(0, 0))
+	assert.Equal(t, 0.25, failureRate(8, 2))
+}
+
+func TestNewStorage(t *testing.T) {
+	// 100 bytes, 30 unused of which 10 are reserved for root
+	storage := newStorage("/data", 100, 20, 30)
+	assert.Equal(t, uint64(70), storage.UsedBytes)
+	assert.Equal(t, uint64(20), storage.FreeBytes)
+	assert.InDelta(t, 77.78, storage.UsedPercent, 0.01)
+
+	empty := newStorage("/data", 0, 0, 0)
+	assert.Equal(t, 0.0, empty.UsedPercent)
+}
+
+func TestStorageUsage(t *testing.T) {
+	storage, err := storageUsage(os.TempDir())
+	if err != nil {
+		t.Skipf("storage usage not supported: %v", err)
+	}
+	require.NotNil(t, storage)
+	assert.Greater(t, storage.TotalBytes, uint64(0))
+}
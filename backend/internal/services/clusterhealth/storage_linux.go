@@ -0,0 +1,19 @@
+//go:build linux
+
+package clusterhealth
+
+import (
+	"syscall"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// storageUsage returns the usage of the filesystem holding path
+func storageUsage(path string) (*models.ClusterStorage, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return nil, err
+	}
+	blockSize := uint64(stat.Bsize)
+	return newStorage(path, stat.Blocks*blockSize, stat.Bavail*blockSize, stat.Bfree*blockSize), nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package clusterhealth
+
+import (
+	"errors"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// storageUsage is only implemented on Linux, the summary omits storage elsewhere
+func storageUsage(path string) (*models.ClusterStorage, error) {
+	return nil, errors.New("storage usage is only supported on Linux")
+}
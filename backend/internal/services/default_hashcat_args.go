@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatargs"
+	"github.com/google/uuid"
+)
+
+// defaultHashcatArgs returns the arguments of the default_hashcat_args rules
+// matching the job, later rules merged over earlier ones
+func (s *JobExecutionService) defaultHashcatArgs(ctx context.Context, job *models.JobExecution) []string {
+	if s.systemSettingsRepo == nil {
+		return nil
+	}
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, models.DefaultHashcatArgsSetting)
+	if err != nil || setting.Value == nil {
+		return nil
+	}
+	rules, err := models.ParseDefaultHashcatArgs(*setting.Value)
+	if err != nil {
+		debug.Warning("Ignoring default hashcat arguments: %v", err)
+		return nil
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	slow := false
+	for _, rule := range rules {
+		if rule.HashClass != "" {
+			slowJobs, err := s.jobTaskRepo.GetSlowHashJobExecutionIDs(ctx, []uuid.UUID{job.ID})
+			if err != nil {
+				debug.Error("Failed to check hash class of job %s: %v", job.ID, err)
+				return nil
+			}
+			slow = slowJobs[job.ID]
+			break
+		}
+	}
+	return mergeDefaultHashcatArgs(rules, job, slow)
+}
+
+// mergeDefaultHashcatArgs merges the arguments of the rules matching the job.
+// Arguments that fail validation are dropped.
+func mergeDefaultHashcatArgs(rules []models.DefaultHashcatArgsRule, job *models.JobExecution, slow bool) []string {
+	var args []string
+	for i, rule := range rules {
+		if !rule.Matches(job, slow) {
+			continue
+		}
+		ruleArgs, err := hashcatargs.Split(rule.Args)
+		if err != nil {
+			debug.Warning("Ignoring default hashcat arguments rule %d: %v", i+1, err)
+			continue
+		}
+		args = hashcatargs.Merge(args, ruleArgs)
+	}
+	return args
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeDefaultHashcatArgs(t *testing.T) {
+	straight := models.AttackModeStraight
+	rules := []models.DefaultHashcatArgsRule{
+		{Args: "-w 3"},
+		{HashClass: models.HashClassFast, Args: "-O"},
+		{AttackMode: &straight, MinRules: 10000, Args: "--slow-candidates -w 2"},
+		{Args: "--outfile /tmp/x"}, // Not allowed, dropped
+	}
+
+	bruteForce := &models.JobExecution{AttackMode: models.AttackModeBruteForce}
+	assert.Equal(t, []string{"-w", "3", "-O"}, mergeDefaultHashcatArgs(rules, bruteForce, false))
+	assert.Equal(t, []string{"-w", "3"}, mergeDefaultHashcatArgs(rules, bruteForce, true))
+
+	// A later rule overrides the same option of an earlier one
+	huge := &models.JobExecution{AttackMode: models.AttackModeStraight, RuleIDs: models.IDArray{"1"}, MultiplicationFactor: 64000}
+	assert.Equal(t, []string{"--slow-candidates", "-w", "2"}, mergeDefaultHashcatArgs(rules, huge, true))
+}
+
+func TestJobHashcatArgsOverrideDefaults(t *testing.T) {
+	additional := "-w 4"
+	extra := "-O"
+	job := &models.JobExecution{AttackMode: models.AttackModeBruteForce, AdditionalArgs: &additional, ExtraParameters: &extra}
+
+	// Without settings there are no defaults, the job's own arguments remain
+	s := &JobExecutionService{}
+	assert.Equal(t, []string{"-w", "4", "-O"}, s.JobHashcatArgs(context.Background(), job))
+}
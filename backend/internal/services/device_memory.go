@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+const (
+	// ruleBytes is the size of one rule on the device (hashcat's kernel_rule_t).
+	// Every loaded rule stays resident on every device for the whole attack.
+	ruleBytes = 128
+	// hashBytes is a rough per-hash cost of the digest, salt and bitmap buffers
+	hashBytes = 64
+	// defaultDeviceMemoryReserveMB applies when device_memory_reserve_mb is missing or invalid
+	defaultDeviceMemoryReserveMB = 512
+)
+
+// memoryAdmission is the outcome of checking an attack against an agent's device memory
+type memoryAdmission int
+
+const (
+	// memoryFits means the attack fits, or the agent's memory is unknown
+	memoryFits memoryAdmission = iota
+	// memorySplitRules means the full rule set does not fit but rule-split chunks do
+	memorySplitRules
+	// memoryRefused means not even a single rule fits
+	memoryRefused
+)
+
+// estimateDeviceMemoryMB estimates the device memory in MB hashcat needs for
+// the rules and hashes of an attack, on top of the reserve for its kernels and
+// candidate buffers. Wordlists are not counted, hashcat streams them to the
+// device in batches sized to the memory that is left.
+func estimateDeviceMemoryMB(rules, hashes, reserveMB int64) int64 {
+	if rules < 1 {
+		rules = 1
+	}
+	bytes := rules*ruleBytes + hashes*hashBytes
+	return reserveMB + (bytes+(1<<20)-1)>>20
+}
+
+// maxRulesForDeviceMemory returns how many rules fit on a device with
+// deviceMB of memory next to the hashes, 0 if not even one does
+func maxRulesForDeviceMemory(deviceMB, hashes, reserveMB int64) int64 {
+	free := (deviceMB-reserveMB)<<20 - hashes*hashBytes
+	if free < ruleBytes {
+		return 0
+	}
+	return free / ruleBytes
+}
+
+// deviceMemoryLimits returns the memory in MB of the agent's smallest enabled
+// device and the reserve to keep free on it. The device memory is 0 when
+// admission is disabled or the agent did not report its memory, in which case
+// every attack is admitted.
+func (s *JobExecutionService) deviceMemoryLimits(ctx context.Context, agentID int) (int64, int64) {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, "device_memory_admission_enabled")
+	if err != nil || setting.Value == nil || *setting.Value != "true" {
+		return 0, 0
+	}
+
+	deviceMB, err := s.deviceRepo.GetSmallestDeviceMemory(agentID)
+	if err != nil {
+		debug.Warning("Failed to get device memory of agent %d: %v", agentID, err)
+		return 0, 0
+	}
+
+	reserveMB := int64(defaultDeviceMemoryReserveMB)
+	if setting, err := s.systemSettingsRepo.GetSetting(ctx, "device_memory_reserve_mb"); err == nil && setting.Value != nil {
+		if parsed, err := strconv.ParseInt(*setting.Value, 10, 64); err == nil && parsed >= 0 {
+			reserveMB = parsed
+		}
+	}
+	return deviceMB, reserveMB
+}
+
+// attackRuleCount returns how many rules hashcat loads for a chunk of the job.
+// Rule-split chunks are sized to fit, so only a single rule must fit for them.
+func attackRuleCount(job *models.JobExecution) int64 {
+	if job.AttackMode != models.AttackModeStraight || len(job.RuleIDs) == 0 || job.UsesRuleSplitting {
+		return 1
+	}
+	return int64(job.MultiplicationFactor)
+}
+
+// canSplitRulesForMemory reports whether rule splitting can still be switched on for the job
+func (s *JobExecutionService) canSplitRulesForMemory(job *models.JobExecution) bool {
+	return job.AttackMode == models.AttackModeStraight && len(job.RuleIDs) == 1 &&
+		!job.UsesRuleSplitting && job.DispatchedKeyspace == 0 && s.ruleSplitManager != nil
+}
+
+// checkDeviceMemory checks whether the job's next chunk fits in the agent's
+// device memory. It returns the admission together with the estimated and the
+// available memory in MB for reporting.
+func (s *JobExecutionService) checkDeviceMemory(ctx context.Context, job *models.JobExecution, hashlist *models.HashList, agentID int) (memoryAdmission, int64, int64) {
+	deviceMB, reserveMB := s.deviceMemoryLimits(ctx, agentID)
+	if deviceMB == 0 {
+		return memoryFits, 0, 0
+	}
+
+	hashes := int64(hashlist.TotalHashes - hashlist.CrackedHashes)
+	rules := attackRuleCount(job)
+	requiredMB := estimateDeviceMemoryMB(rules, hashes, reserveMB)
+	if requiredMB <= deviceMB {
+		return memoryFits, requiredMB, deviceMB
+	}
+
+	if rules > 1 && s.canSplitRulesForMemory(job) && maxRulesForDeviceMemory(deviceMB, hashes, reserveMB) > 0 {
+		return memorySplitRules, requiredMB, deviceMB
+	}
+	return memoryRefused, requiredMB, deviceMB
+}
+
+// NeedsRuleSplitForMemory reports whether the job's full rule set does not fit
+// in the agent's device memory, so its rules must be split before dispatch
+func (s *JobExecutionService) NeedsRuleSplitForMemory(ctx context.Context, job *models.JobExecution, hashlist *models.HashList, agentID int) bool {
+	admission, _, _ := s.checkDeviceMemory(ctx, job, hashlist, agentID)
+	return admission == memorySplitRules
+}
+
+// MaxRulesPerChunkForAgent returns how many rules of a rule-split job fit in
+// the agent's device memory, 0 when there is no limit
+func (s *JobExecutionService) MaxRulesPerChunkForAgent(ctx context.Context, hashlist *models.HashList, agentID int) int {
+	deviceMB, reserveMB := s.deviceMemoryLimits(ctx, agentID)
+	if deviceMB == 0 {
+		return 0
+	}
+	maxRules := maxRulesForDeviceMemory(deviceMB, int64(hashlist.TotalHashes-hashlist.CrackedHashes), reserveMB)
+	if maxRules < 1 {
+		return 1
+	}
+	return int(maxRules)
+}
+
+// admitJobForAgent reports whether the job's next chunk fits in the agent's
+// device memory. A refused job stays queued for agents with more memory and
+// the reason is recorded on the job so it is visible instead of the agent
+// repeatedly failing the chunk with an out of memory error.
+func (s *JobExecutionService) admitJobForAgent(ctx context.Context, job *models.JobExecution, agentID int) bool {
+	hashlist, err := s.hashlistRepo.GetByID(ctx, job.HashlistID)
+	if err != nil {
+		debug.Warning("Failed to get hashlist %d for device memory check: %v", job.HashlistID, err)
+		return true
+	}
+
+	admission, requiredMB, deviceMB := s.checkDeviceMemory(ctx, job, hashlist, agentID)
+	if admission != memoryRefused {
+		return true
+	}
+
+	message := fmt.Sprintf("Needs about %d MB of device memory per device but agent %d has %d MB; waiting for an agent with more device memory",
+		requiredMB, agentID, deviceMB)
+	debug.Warning("Not assigning job %s to agent %d: %s", job.ID, agentID, message)
+	if job.ErrorMessage == nil || *job.ErrorMessage != message {
+		if err := s.jobExecRepo.UpdateErrorMessage(ctx, job.ID, message); err != nil {
+			debug.Error("Failed to record device memory refusal on job %s: %v", job.ID, err)
+		}
+	}
+	return false
+}
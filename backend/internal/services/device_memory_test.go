@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateDeviceMemoryMB(t *testing.T) {
+	// 1M rules take 122 MB, 1M hashes another 61 MB
+	assert.Equal(t, int64(512+184), estimateDeviceMemoryMB(1_000_000, 1_000_000, 512))
+	assert.Equal(t, int64(513), estimateDeviceMemoryMB(0, 0, 512))
+}
+
+func TestMaxRulesForDeviceMemory(t *testing.T) {
+	// 8 GB device, 512 MB reserve and 1M hashes
+	maxRules := maxRulesForDeviceMemory(8192, 1_000_000, 512)
+	assert.Equal(t, int64((7680<<20-64_000_000)/128), maxRules)
+	assert.LessOrEqual(t, estimateDeviceMemoryMB(maxRules, 1_000_000, 512), int64(8192))
+
+	// The hashes alone exceed the device
+	assert.Equal(t, int64(0), maxRulesForDeviceMemory(1024, 20_000_000, 512))
+}
+
+func TestAttackRuleCount(t *testing.T) {
+	job := &models.JobExecution{
+		AttackMode:           models.AttackModeStraight,
+		RuleIDs:              models.IDArray{"1"},
+		MultiplicationFactor: 50000,
+	}
+	assert.Equal(t, int64(50000), attackRuleCount(job))
+
+	job.UsesRuleSplitting = true
+	assert.Equal(t, int64(1), attackRuleCount(job))
+
+	mask := &models.JobExecution{AttackMode: models.AttackModeBruteForce, MultiplicationFactor: 1}
+	assert.Equal(t, int64(1), attackRuleCount(mask))
+}
@@ -0,0 +1,181 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/fsutil"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/plaintext"
+	"github.com/google/uuid"
+)
+
+// crackedPageSize is how many cracked hashes are read at once for a hashlist export
+const crackedPageSize = 10000
+
+// clientPotfileParams are the params of a client potfile export
+type clientPotfileParams struct {
+	ClientID uuid.UUID  `json:"client_id"`
+	From     *time.Time `json:"from,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+}
+
+// hashlistCrackedParams are the params of a hashlist cracked hashes export
+type hashlistCrackedParams struct {
+	HashlistID int64 `json:"hashlist_id"`
+	// Plain is "hashcat" to write passwords that are not printable text in
+	// $HEX[...] notation
+	Plain string `json:"plain,omitempty"`
+}
+
+// ClientPotfileExporter exports the deduplicated potfile of every hash cracked
+// in a client's hashlists, optionally limited to hashes cracked between from
+// and to
+func ClientPotfileExporter(hashRepo *repository.HashRepository, clientRepo *repository.ClientRepository) Exporter {
+	parse := func(raw json.RawMessage) (clientPotfileParams, error) {
+		var params clientPotfileParams
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return params, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+		}
+		if params.ClientID == uuid.Nil {
+			return params, fmt.Errorf("%w: client_id is required", ErrInvalidExport)
+		}
+		if params.From != nil && params.To != nil && !params.From.Before(*params.To) {
+			return params, fmt.Errorf("%w: from must be before to", ErrInvalidExport)
+		}
+		return params, nil
+	}
+
+	return Exporter{
+		Validate: func(ctx context.Context, raw json.RawMessage) error {
+			params, err := parse(raw)
+			if err != nil {
+				return err
+			}
+			if _, err := clientRepo.GetByID(ctx, params.ClientID); errors.Is(err, repository.ErrNotFound) {
+				return fmt.Errorf("%w: client not found", ErrInvalidExport)
+			} else if err != nil {
+				return err
+			}
+			return nil
+		},
+		Write: func(ctx context.Context, raw json.RawMessage, w io.Writer, progress Progress) (string, error) {
+			params, err := parse(raw)
+			if err != nil {
+				return "", err
+			}
+			client, err := clientRepo.GetByID(ctx, params.ClientID)
+			if err != nil {
+				return "", err
+			}
+
+			total, err := hashRepo.CountClientPotfile(ctx, params.ClientID, params.From, params.To)
+			if err != nil {
+				return "", err
+			}
+			progress(0, &total)
+
+			var count int64
+			err = hashRepo.StreamClientPotfile(ctx, params.ClientID, params.From, params.To, func(hashValue string, plain []byte) error {
+				if _, err := fmt.Fprintf(w, "%s:%s\n", hashValue, plaintext.Hashcat(plain)); err != nil {
+					return err
+				}
+				count++
+				progress(count, nil)
+				return nil
+			})
+			if err != nil {
+				return "", err
+			}
+			return fsutil.SanitizeFilename(client.Name) + ".potfile", nil
+		},
+	}
+}
+
+// HashlistCrackedExporter exports the cracked hashes of a hashlist as
+// hash:password lines
+func HashlistCrackedExporter(hashRepo *repository.HashRepository, hashlistRepo *repository.HashListRepository) Exporter {
+	parse := func(raw json.RawMessage) (hashlistCrackedParams, error) {
+		var params hashlistCrackedParams
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return params, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+		}
+		if params.HashlistID <= 0 {
+			return params, fmt.Errorf("%w: hashlist_id is required", ErrInvalidExport)
+		}
+		if params.Plain != "" && params.Plain != "hashcat" {
+			return params, fmt.Errorf("%w: plain must be empty or hashcat", ErrInvalidExport)
+		}
+		return params, nil
+	}
+
+	return Exporter{
+		Validate: func(ctx context.Context, raw json.RawMessage) error {
+			params, err := parse(raw)
+			if err != nil {
+				return err
+			}
+			if _, err := hashlistRepo.GetByID(ctx, params.HashlistID); errors.Is(err, repository.ErrNotFound) {
+				return fmt.Errorf("%w: hashlist not found", ErrInvalidExport)
+			} else if err != nil {
+				return err
+			}
+			return nil
+		},
+		Write: func(ctx context.Context, raw json.RawMessage, w io.Writer, progress Progress) (string, error) {
+			params, err := parse(raw)
+			if err != nil {
+				return "", err
+			}
+			hashlist, err := hashlistRepo.GetByID(ctx, params.HashlistID)
+			if err != nil {
+				return "", err
+			}
+
+			var written int64
+			for offset := 0; ; offset += crackedPageSize {
+				hashes, total, err := hashRepo.GetCrackedHashesByHashlist(ctx, params.HashlistID,
+					repository.CrackedHashParams{Limit: crackedPageSize, Offset: offset})
+				if err != nil {
+					return "", err
+				}
+				for _, hash := range hashes {
+					if _, err := fmt.Fprintf(w, "%s:%s\n", crackedHashValue(hash), crackedPassword(hash, params.Plain)); err != nil {
+						return "", err
+					}
+				}
+				written += int64(len(hashes))
+				if offset == 0 {
+					progress(written, &total)
+				} else {
+					progress(written, nil)
+				}
+				if len(hashes) < crackedPageSize {
+					break
+				}
+			}
+			return fsutil.SanitizeFilename(hashlist.Name) + "-cracked.txt", nil
+		},
+	}
+}
+
+// crackedHashValue returns the hash as it was uploaded
+func crackedHashValue(hash *models.Hash) string {
+	if hash.OriginalHash != "" {
+		return hash.OriginalHash
+	}
+	return hash.HashValue
+}
+
+// crackedPassword returns the password to write for a cracked hash
+func crackedPassword(hash *models.Hash, plain string) string {
+	if plain == "hashcat" {
+		return plaintext.Hashcat(hash.PlainBytes())
+	}
+	return hash.Password
+}
@@ -0,0 +1,366 @@
+// Package export builds exports too large to be written within an HTTP request.
+// An export job is created, its artifact is written in the background while
+// its progress is polled, and the finished artifact is downloaded through an
+// expiring signed link until it is deleted once its retention has passed.
+package export
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxConcurrentExports is how many artifacts are written at once, further
+	// exports stay pending until a slot is free
+	maxConcurrentExports = 2
+	// maxListedExports caps how many exports of a user are listed
+	maxListedExports = 100
+	// progressInterval is how often the progress of a running export is stored
+	progressInterval = time.Second
+	// cleanupTick is how often expired artifacts are deleted
+	cleanupTick = 15 * time.Minute
+
+	settingRetentionHours = "export_retention_hours"
+	settingLinkMinutes    = "export_link_minutes"
+
+	defaultRetention    = 24 * time.Hour
+	defaultLinkLifetime = time.Hour
+)
+
+var (
+	// ErrInvalidExport is wrapped by every export request validation error
+	ErrInvalidExport = errors.New("invalid export request")
+	// ErrNotFound is returned for exports that do not exist or belong to someone else
+	ErrNotFound = errors.New("export not found")
+	// ErrNotFinished is returned when an export that is still being built is deleted
+	ErrNotFinished = errors.New("export is still being built")
+	// ErrInvalidLink is returned for download links that are forged, expired
+	// or point to an artifact that no longer exists
+	ErrInvalidLink = errors.New("invalid or expired download link")
+)
+
+// Progress reports how many records an exporter has written and, once known,
+// how many it will write in total
+type Progress func(processed int64, total *int64)
+
+// Exporter builds one kind of export
+type Exporter struct {
+	// Validate checks the params of a new export
+	Validate func(ctx context.Context, params json.RawMessage) error
+	// Write writes the artifact to w and returns the file name to download it as
+	Write func(ctx context.Context, params json.RawMessage, w io.Writer, progress Progress) (string, error)
+}
+
+// ExportService creates export jobs, writes their artifacts and serves them
+// through signed download links
+type ExportService struct {
+	repo         *repository.ExportJobRepository
+	settingsRepo *repository.SystemSettingsRepository
+	exporters    map[string]Exporter
+	dir          string
+	secret       []byte
+	slots        chan struct{}
+}
+
+// NewExportService creates a new ExportService writing artifacts to dir.
+// Download links are signed with secret.
+func NewExportService(repo *repository.ExportJobRepository, settingsRepo *repository.SystemSettingsRepository, dir string, secret []byte) *ExportService {
+	return &ExportService{
+		repo:         repo,
+		settingsRepo: settingsRepo,
+		exporters:    make(map[string]Exporter),
+		dir:          dir,
+		secret:       secret,
+		slots:        make(chan struct{}, maxConcurrentExports),
+	}
+}
+
+// Register adds a kind of export
+func (s *ExportService) Register(kind string, exporter Exporter) {
+	s.exporters[kind] = exporter
+}
+
+// Create validates an export request and starts building it in the background
+func (s *ExportService) Create(ctx context.Context, userID uuid.UUID, req *models.ExportRequest) (*models.ExportJob, error) {
+	exporter, ok := s.exporters[req.Kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown export kind %q", ErrInvalidExport, req.Kind)
+	}
+	params := req.Params
+	if len(params) == 0 {
+		params = json.RawMessage("{}")
+	}
+	if err := exporter.Validate(ctx, params); err != nil {
+		return nil, err
+	}
+
+	job := &models.ExportJob{UserID: userID, Kind: req.Kind, Params: params}
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	go s.run(job, exporter)
+	debug.Info("Export %s of kind %s created by user %s", job.ID, job.Kind, userID)
+	return job, nil
+}
+
+// Get returns an export of the user, with a fresh download link once it is completed
+func (s *ExportService) Get(ctx context.Context, userID, id uuid.UUID) (*models.ExportJob, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && job.UserID != userID) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.addDownloadURL(ctx, job)
+	return job, nil
+}
+
+// List returns the latest exports of the user, newest first
+func (s *ExportService) List(ctx context.Context, userID uuid.UUID) ([]models.ExportJob, error) {
+	jobs, err := s.repo.ListByUser(ctx, userID, maxListedExports)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		s.addDownloadURL(ctx, &jobs[i])
+	}
+	return jobs, nil
+}
+
+// Delete removes a finished export of the user along with its artifact
+func (s *ExportService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	job, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && job.UserID != userID) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !job.Status.IsFinal() {
+		return ErrNotFinished
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	removeArtifact(job.FilePath)
+	return nil
+}
+
+// OpenDownload checks a signed download link and returns the completed export
+// it points to
+func (s *ExportService) OpenDownload(ctx context.Context, id uuid.UUID, expires, signature string) (*models.ExportJob, error) {
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresUnix {
+		return nil, ErrInvalidLink
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, s.sign(id, expiresUnix)) {
+		return nil, ErrInvalidLink
+	}
+
+	job, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrInvalidLink
+	}
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.ExportStatusCompleted || job.FilePath == "" {
+		return nil, ErrInvalidLink
+	}
+	return job, nil
+}
+
+// Start fails the exports a restart interrupted and then deletes expired
+// artifacts until ctx is done
+func (s *ExportService) Start(ctx context.Context) {
+	if err := os.MkdirAll(s.dir, 0750); err != nil {
+		debug.Error("Failed to create export directory %s: %v", s.dir, err)
+	}
+
+	paths, err := s.repo.FailUnfinished(ctx, "interrupted by a backend restart")
+	if err != nil {
+		debug.Error("Failed to fail interrupted exports: %v", err)
+	}
+	for _, path := range paths {
+		removeArtifact(path)
+	}
+	if len(paths) > 0 {
+		debug.Warning("Failed %d exports interrupted by a restart", len(paths))
+	}
+
+	ticker := time.NewTicker(cleanupTick)
+	defer ticker.Stop()
+	for {
+		s.deleteExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			debug.Info("Export cleanup stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteExpired deletes the artifacts of exports whose retention has passed
+func (s *ExportService) deleteExpired(ctx context.Context) {
+	paths, err := s.repo.ExpireCompleted(ctx, time.Now())
+	if err != nil {
+		debug.Error("Failed to expire exports: %v", err)
+		return
+	}
+	for _, path := range paths {
+		removeArtifact(path)
+	}
+	if len(paths) > 0 {
+		debug.Info("Deleted %d expired export artifacts", len(paths))
+	}
+}
+
+// run writes the artifact of an export once a slot is free
+func (s *ExportService) run(job *models.ExportJob, exporter Exporter) {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	ctx := context.Background()
+	path := filepath.Join(s.dir, job.ID.String())
+	if err := s.repo.MarkRunning(ctx, job.ID, path); err != nil {
+		debug.Error("%v", err)
+		return
+	}
+
+	processed, fileName, size, err := s.write(ctx, job, exporter, path)
+	if err != nil {
+		debug.Error("Export %s failed after %d records: %v", job.ID, processed, err)
+		removeArtifact(path)
+		if failErr := s.repo.Fail(ctx, job.ID, err.Error()); failErr != nil {
+			debug.Error("%v", failErr)
+		}
+		return
+	}
+
+	expiresAt := time.Now().Add(s.retention(ctx))
+	if err := s.repo.Complete(ctx, job.ID, processed, fileName, size, expiresAt); err != nil {
+		debug.Error("%v", err)
+		removeArtifact(path)
+		return
+	}
+	debug.Info("Export %s completed: %d records, %d bytes", job.ID, processed, size)
+}
+
+// write writes the artifact of an export to path, storing its progress along
+// the way. It returns the records written, the download file name and the size.
+func (s *ExportService) write(ctx context.Context, job *models.ExportJob, exporter Exporter, path string) (int64, string, int64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return 0, "", 0, fmt.Errorf("failed to create export artifact: %w", err)
+	}
+	defer file.Close()
+
+	var processed int64
+	var lastUpdate time.Time
+	progress := func(done int64, total *int64) {
+		processed = done
+		if total == nil && time.Since(lastUpdate) < progressInterval {
+			return
+		}
+		lastUpdate = time.Now()
+		if err := s.repo.UpdateProgress(ctx, job.ID, done, total); err != nil {
+			debug.Warning("%v", err)
+		}
+	}
+
+	out := bufio.NewWriter(file)
+	fileName, err := exporter.Write(ctx, job.Params, out, progress)
+	if err != nil {
+		return processed, "", 0, err
+	}
+	if err := out.Flush(); err != nil {
+		return processed, "", 0, fmt.Errorf("failed to write export artifact: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return processed, "", 0, fmt.Errorf("failed to stat export artifact: %w", err)
+	}
+	return processed, fileName, info.Size(), nil
+}
+
+// addDownloadURL sets a signed download link on a completed export. The link
+// never outlives the artifact.
+func (s *ExportService) addDownloadURL(ctx context.Context, job *models.ExportJob) {
+	if job.Status != models.ExportStatusCompleted {
+		return
+	}
+	expires := time.Now().Add(s.linkLifetime(ctx))
+	if job.ExpiresAt != nil && job.ExpiresAt.Before(expires) {
+		expires = *job.ExpiresAt
+	}
+	url := s.downloadURL(job.ID, expires.Unix())
+	job.DownloadURL = &url
+}
+
+// downloadURL returns the signed download path of an export
+func (s *ExportService) downloadURL(id uuid.UUID, expires int64) string {
+	return fmt.Sprintf("/api/exports/%s/download?expires=%d&signature=%s",
+		id, expires, hex.EncodeToString(s.sign(id, expires)))
+}
+
+// sign returns the signature of a download link
+func (s *ExportService) sign(id uuid.UUID, expires int64) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "export-download:%s:%d", id, expires)
+	return mac.Sum(nil)
+}
+
+// retention returns how long finished artifacts are kept
+func (s *ExportService) retention(ctx context.Context) time.Duration {
+	return s.durationSetting(ctx, settingRetentionHours, time.Hour, defaultRetention)
+}
+
+// linkLifetime returns how long a download link stays valid
+func (s *ExportService) linkLifetime(ctx context.Context) time.Duration {
+	return s.durationSetting(ctx, settingLinkMinutes, time.Minute, defaultLinkLifetime)
+}
+
+// durationSetting reads a positive integer setting in units of unit
+func (s *ExportService) durationSetting(ctx context.Context, key string, unit, fallback time.Duration) time.Duration {
+	setting, err := s.settingsRepo.GetSetting(ctx, key)
+	if err != nil || setting.Value == nil {
+		return fallback
+	}
+	value, err := strconv.Atoi(*setting.Value)
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return time.Duration(value) * unit
+}
+
+// removeArtifact deletes an export artifact, which may not exist
+func removeArtifact(path string) {
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		debug.Warning("Failed to delete export artifact %s: %v", path, err)
+	}
+}
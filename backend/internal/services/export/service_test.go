@@ -0,0 +1,87 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadURLSignature(t *testing.T) {
+	service := NewExportService(nil, nil, t.TempDir(), []byte("secret"))
+	id := uuid.New()
+	expires := time.Now().Add(time.Hour).Unix()
+
+	link, err := url.Parse(service.downloadURL(id, expires))
+	require.NoError(t, err)
+	assert.Equal(t, "/api/exports/"+id.String()+"/download", link.Path)
+	assert.Equal(t, strconv.FormatInt(expires, 10), link.Query().Get("expires"))
+
+	signature := link.Query().Get("signature")
+	tampered := []byte(signature)
+	if tampered[0] == '0' {
+		tampered[0] = '1'
+	} else {
+		tampered[0] = '0'
+	}
+	other := NewExportService(nil, nil, t.TempDir(), []byte("other secret"))
+	assert.NotEqual(t, service.sign(id, expires), other.sign(id, expires))
+
+	tests := []struct {
+		name      string
+		id        uuid.UUID
+		expires   string
+		signature string
+	}{
+		{"other export", uuid.New(), strconv.FormatInt(expires, 10), signature},
+		{"extended expiry", id, strconv.FormatInt(expires+3600, 10), signature},
+		{"tampered signature", id, strconv.FormatInt(expires, 10), string(tampered)},
+		{"not hex", id, strconv.FormatInt(expires, 10), "zz"},
+		{"bad expiry", id, "tomorrow", signature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.OpenDownload(context.Background(), tt.id, tt.expires, tt.signature)
+			assert.True(t, errors.Is(err, ErrInvalidLink))
+		})
+	}
+}
+
+func TestOpenDownloadRejectsExpiredLink(t *testing.T) {
+	service := NewExportService(nil, nil, t.TempDir(), []byte("secret"))
+	id := uuid.New()
+	expires := time.Now().Add(-time.Minute).Unix()
+
+	link, err := url.Parse(service.downloadURL(id, expires))
+	require.NoError(t, err)
+
+	_, err = service.OpenDownload(context.Background(), id, link.Query().Get("expires"), link.Query().Get("signature"))
+	assert.True(t, errors.Is(err, ErrInvalidLink))
+}
+
+func TestCreateRejectsUnknownKind(t *testing.T) {
+	service := NewExportService(nil, nil, t.TempDir(), []byte("secret"))
+
+	_, err := service.Create(context.Background(), uuid.New(), &models.ExportRequest{Kind: "everything"})
+	assert.True(t, errors.Is(err, ErrInvalidExport))
+}
+
+func TestExporterParamsValidation(t *testing.T) {
+	potfile := ClientPotfileExporter(nil, nil)
+	for _, params := range []string{`{}`, `{"client_id":"nope"}`,
+		`{"client_id":"` + uuid.NewString() + `","from":"2024-02-01T00:00:00Z","to":"2024-01-01T00:00:00Z"}`} {
+		assert.True(t, errors.Is(potfile.Validate(context.Background(), []byte(params)), ErrInvalidExport), params)
+	}
+
+	cracked := HashlistCrackedExporter(nil, nil)
+	for _, params := range []string{`{}`, `{"hashlist_id":-1}`, `{"hashlist_id":1,"plain":"base64"}`} {
+		assert.True(t, errors.Is(cracked.Validate(context.Background(), []byte(params)), ErrInvalidExport), params)
+	}
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// Defaults when hashcat_exec_max_concurrent and hashcat_exec_max_queued are not set
+const (
+	defaultHashcatExecMaxConcurrent = 2
+	defaultHashcatExecMaxQueued     = 100
+)
+
+// Kinds of hashcat runs made by the backend itself
+const (
+	HashcatExecKeyspace       = "keyspace"
+	HashcatExecPresetKeyspace = "preset_keyspace"
+)
+
+// ErrHashcatExecQueueFull is returned when a hashcat run would exceed the
+// number of runs allowed to wait for a slot
+var ErrHashcatExecQueueFull = errors.New("too many hashcat runs are waiting, try again later")
+
+// HashcatExecRequest is the status of one hashcat run made by the backend
+type HashcatExecRequest struct {
+	ID          uint64     `json:"id"`
+	Kind        string     `json:"kind"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	Position    int        `json:"position,omitempty"`
+	QueuedAt    time.Time  `json:"queued_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+}
+
+// HashcatExecStatus is a snapshot of the backend's hashcat runs
+type HashcatExecStatus struct {
+	MaxConcurrent int                  `json:"max_concurrent"`
+	MaxQueued     int                  `json:"max_queued"`
+	Running       []HashcatExecRequest `json:"running"`
+	Queued        []HashcatExecRequest `json:"queued"`
+	Completed     uint64               `json:"completed"`
+	Rejected      uint64               `json:"rejected"`
+}
+
+type hashcatExecWaiter struct {
+	request *HashcatExecRequest
+	ready   chan struct{}
+}
+
+// HashcatExecLimiter bounds how many hashcat processes the backend runs at
+// once, for keyspace calculations and the like. Runs beyond the limit wait in
+// order of arrival for a slot, and runs beyond the queue limit are rejected,
+// so bulk job creation cannot fork a hashcat process per job.
+type HashcatExecLimiter struct {
+	mu            sync.Mutex
+	maxConcurrent int
+	maxQueued     int
+	running       map[uint64]*HashcatExecRequest
+	queue         []*hashcatExecWaiter
+	nextID        uint64
+	completed     uint64
+	rejected      uint64
+}
+
+// NewHashcatExecLimiter creates a limiter running at most maxConcurrent
+// hashcat processes with at most maxQueued waiting
+func NewHashcatExecLimiter(maxConcurrent, maxQueued int) *HashcatExecLimiter {
+	l := &HashcatExecLimiter{running: make(map[uint64]*HashcatExecRequest)}
+	l.SetLimits(maxConcurrent, maxQueued)
+	return l
+}
+
+// hashcatExecs is shared by every service that runs hashcat, since several
+// instances of those services exist
+var hashcatExecs = NewHashcatExecLimiter(defaultHashcatExecMaxConcurrent, defaultHashcatExecMaxQueued)
+
+// HashcatExecs returns the limiter of the backend's hashcat runs
+func HashcatExecs() *HashcatExecLimiter {
+	return hashcatExecs
+}
+
+// SetLimits changes the limits. Raising the concurrency starts waiting runs
+// at once, lowering it lets running ones finish.
+func (l *HashcatExecLimiter) SetLimits(maxConcurrent, maxQueued int) {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	if maxQueued < 0 {
+		maxQueued = 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxConcurrent = maxConcurrent
+	l.maxQueued = maxQueued
+	l.dispatch()
+}
+
+// Acquire waits for a slot to run hashcat in. The returned function frees the
+// slot and must be called once the process has exited. It fails with
+// ErrHashcatExecQueueFull when too many runs are waiting already, or with the
+// context's error when it ends first.
+func (l *HashcatExecLimiter) Acquire(ctx context.Context, kind, description string) (func(), error) {
+	l.mu.Lock()
+	l.nextID++
+	request := &HashcatExecRequest{
+		ID:          l.nextID,
+		Kind:        kind,
+		Description: description,
+		State:       "queued",
+		QueuedAt:    time.Now(),
+	}
+
+	if len(l.queue) == 0 && len(l.running) < l.maxConcurrent {
+		l.start(request)
+		l.mu.Unlock()
+		return l.releaser(request.ID), nil
+	}
+	if len(l.queue) >= l.maxQueued {
+		l.rejected++
+		l.mu.Unlock()
+		return nil, ErrHashcatExecQueueFull
+	}
+
+	waiter := &hashcatExecWaiter{request: request, ready: make(chan struct{})}
+	l.queue = append(l.queue, waiter)
+	position := len(l.queue)
+	l.mu.Unlock()
+
+	debug.Debug("Hashcat %s run %d waiting for a slot at position %d: %s", kind, request.ID, position, description)
+
+	select {
+	case <-waiter.ready:
+		return l.releaser(request.ID), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, queued := range l.queue {
+			if queued == waiter {
+				l.queue = append(l.queue[:i], l.queue[i+1:]...)
+				return nil, fmt.Errorf("gave up waiting for a hashcat slot: %w", ctx.Err())
+			}
+		}
+		// The slot was granted as the context ended, hand it on
+		delete(l.running, request.ID)
+		l.dispatch()
+		return nil, fmt.Errorf("gave up waiting for a hashcat slot: %w", ctx.Err())
+	}
+}
+
+// Status returns the runs holding a slot and those waiting, in queue order
+func (l *HashcatExecLimiter) Status() HashcatExecStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	status := HashcatExecStatus{
+		MaxConcurrent: l.maxConcurrent,
+		MaxQueued:     l.maxQueued,
+		Running:       make([]HashcatExecRequest, 0, len(l.running)),
+		Queued:        make([]HashcatExecRequest, 0, len(l.queue)),
+		Completed:     l.completed,
+		Rejected:      l.rejected,
+	}
+	for _, request := range l.running {
+		status.Running = append(status.Running, *request)
+	}
+	for i, waiter := range l.queue {
+		request := *waiter.request
+		request.Position = i + 1
+		status.Queued = append(status.Queued, request)
+	}
+	sort.Slice(status.Running, func(i, j int) bool { return status.Running[i].ID < status.Running[j].ID })
+	return status
+}
+
+// start gives a request a slot. The caller holds the lock.
+func (l *HashcatExecLimiter) start(request *HashcatExecRequest) {
+	now := time.Now()
+	request.State = "running"
+	request.StartedAt = &now
+	l.running[request.ID] = request
+}
+
+// dispatch hands free slots to waiting runs in order. The caller holds the lock.
+func (l *HashcatExecLimiter) dispatch() {
+	for len(l.queue) > 0 && len(l.running) < l.maxConcurrent {
+		waiter := l.queue[0]
+		l.queue = l.queue[1:]
+		l.start(waiter.request)
+		close(waiter.ready)
+	}
+}
+
+func (l *HashcatExecLimiter) releaser(id uint64) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			delete(l.running, id)
+			l.completed++
+			l.dispatch()
+		})
+	}
+}
+
+// acquireHashcatExec waits for a slot of the shared limiter after applying
+// the current limits from the system settings
+func acquireHashcatExec(ctx context.Context, systemSettingsRepo *repository.SystemSettingsRepository, kind, description string) (func(), error) {
+	maxConcurrent, maxQueued := defaultHashcatExecMaxConcurrent, defaultHashcatExecMaxQueued
+	if systemSettingsRepo != nil {
+		if value, ok := positiveIntSetting(ctx, systemSettingsRepo, "hashcat_exec_max_concurrent"); ok {
+			maxConcurrent = value
+		}
+		if value, ok := positiveIntSetting(ctx, systemSettingsRepo, "hashcat_exec_max_queued"); ok {
+			maxQueued = value
+		}
+	}
+	hashcatExecs.SetLimits(maxConcurrent, maxQueued)
+	return hashcatExecs.Acquire(ctx, kind, description)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashcatExecLimiterQueuesInOrder(t *testing.T) {
+	limiter := NewHashcatExecLimiter(1, 2)
+	ctx := context.Background()
+
+	release, err := limiter.Acquire(ctx, HashcatExecKeyspace, "first")
+	require.NoError(t, err)
+
+	started := make(chan string, 2)
+	for _, name := range []string{"second", "third"} {
+		name := name
+		go func() {
+			release, err := limiter.Acquire(ctx, HashcatExecKeyspace, name)
+			if err == nil {
+				started <- name
+				release()
+			}
+		}()
+		require.Eventually(t, func() bool {
+			queued := limiter.Status().Queued
+			return len(queued) > 0 && queued[len(queued)-1].Description == name
+		}, time.Second, time.Millisecond)
+	}
+
+	status := limiter.Status()
+	require.Len(t, status.Running, 1)
+	assert.Equal(t, "first", status.Running[0].Description)
+	require.Len(t, status.Queued, 2)
+	assert.Equal(t, 1, status.Queued[0].Position)
+	assert.Equal(t, "queued", status.Queued[1].State)
+
+	// The queue is full
+	_, err = limiter.Acquire(ctx, HashcatExecKeyspace, "fourth")
+	assert.ErrorIs(t, err, ErrHashcatExecQueueFull)
+
+	release()
+	release() // releasing twice frees one slot only
+	assert.Equal(t, "second", <-started)
+	assert.Equal(t, "third", <-started)
+
+	require.Eventually(t, func() bool { return len(limiter.Status().Running) == 0 }, time.Second, time.Millisecond)
+	status = limiter.Status()
+	assert.Equal(t, uint64(3), status.Completed)
+	assert.Equal(t, uint64(1), status.Rejected)
+}
+
+func TestHashcatExecLimiterGivesUpWithContext(t *testing.T) {
+	limiter := NewHashcatExecLimiter(1, 5)
+	release, err := limiter.Acquire(context.Background(), HashcatExecKeyspace, "running")
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = limiter.Acquire(ctx, HashcatExecKeyspace, "waiting")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, limiter.Status().Queued)
+}
+
+func TestHashcatExecLimiterRaisedLimitStartsWaitingRuns(t *testing.T) {
+	limiter := NewHashcatExecLimiter(1, 5)
+	release, err := limiter.Acquire(context.Background(), HashcatExecKeyspace, "running")
+	require.NoError(t, err)
+	defer release()
+
+	acquired := make(chan struct{})
+	go func() {
+		if release, err := limiter.Acquire(context.Background(), HashcatExecKeyspace, "waiting"); err == nil {
+			release()
+			close(acquired)
+		}
+	}()
+	require.Eventually(t, func() bool { return len(limiter.Status().Queued) == 1 }, time.Second, time.Millisecond)
+
+	limiter.SetLimits(2, 5)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("waiting run did not start after the limit was raised")
+	}
+}
@@ -3,9 +3,12 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
@@ -17,14 +20,29 @@ type WSHandler interface {
 	SendMessage(agentID int, msg interface{}) error
 }
 
+// What happens to the jobs of a hashlist once every hash is cracked, set by
+// the hashlist_fully_cracked_action system setting
+const (
+	// FullyCrackedActionSupersede completes started jobs and marks queued jobs superseded
+	FullyCrackedActionSupersede = "supersede"
+	// FullyCrackedActionDelete completes started jobs and deletes queued jobs
+	FullyCrackedActionDelete = "delete"
+	// FullyCrackedActionNone leaves the jobs alone, the scheduler only stops assigning them
+	FullyCrackedActionNone = "none"
+)
+
 // HashlistCompletionService handles auto-completion/deletion of jobs when all hashes are cracked
 type HashlistCompletionService struct {
 	db                 *db.DB
 	jobExecRepo        *repository.JobExecutionRepository
 	jobTaskRepo        *repository.JobTaskRepository
 	hashlistRepo       *repository.HashListRepository
-	notificationService *NotificationService
+	systemSettingsRepo *repository.SystemSettingsRepository
 	wsHandler          WSHandler
+
+	// Hashlists being processed, several agents and cracks report the same one
+	processingMu sync.Mutex
+	processing   map[int64]bool
 }
 
 // NewHashlistCompletionService creates a new hashlist completion service
@@ -33,7 +51,6 @@ func NewHashlistCompletionService(
 	jobExecRepo *repository.JobExecutionRepository,
 	jobTaskRepo *repository.JobTaskRepository,
 	hashlistRepo *repository.HashListRepository,
-	notificationService *NotificationService,
 	wsHandler WSHandler,
 ) *HashlistCompletionService {
 	return &HashlistCompletionService{
@@ -41,23 +58,80 @@ func NewHashlistCompletionService(
 		jobExecRepo:        jobExecRepo,
 		jobTaskRepo:        jobTaskRepo,
 		hashlistRepo:       hashlistRepo,
-		notificationService: notificationService,
+		systemSettingsRepo: repository.NewSystemSettingsRepository(database),
 		wsHandler:          wsHandler,
+		processing:         make(map[int64]bool),
 	}
 }
 
+// SubscribeEvents registers the handler that checks a hashlist after cracks
+// were stored, so jobs are wrapped up however the last hashes got cracked
+func (s *HashlistCompletionService) SubscribeEvents(bus *events.Bus) {
+	bus.Subscribe(events.HashCracked, "hashlist_completion.check_fully_cracked", func(ctx context.Context, event *events.Event) error {
+		var payload events.HashCrackedPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		return s.CheckHashlist(ctx, payload.HashlistID)
+	})
+}
+
+// CheckHashlist wraps up the jobs of a hashlist if all of its hashes are cracked
+func (s *HashlistCompletionService) CheckHashlist(ctx context.Context, hashlistID int64) error {
+	hashlist, err := s.hashlistRepo.GetByID(ctx, hashlistID)
+	if err != nil {
+		return fmt.Errorf("failed to get hashlist %d: %w", hashlistID, err)
+	}
+	if hashlist.TotalHashes == 0 || hashlist.CrackedHashes < hashlist.TotalHashes {
+		return nil
+	}
+	return s.HandleHashlistFullyCracked(ctx, hashlistID)
+}
+
+// fullyCrackedAction returns the hashlist_fully_cracked_action setting
+func (s *HashlistCompletionService) fullyCrackedAction(ctx context.Context) string {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, "hashlist_fully_cracked_action")
+	if err != nil || setting.Value == nil {
+		return FullyCrackedActionSupersede
+	}
+	switch *setting.Value {
+	case FullyCrackedActionSupersede, FullyCrackedActionDelete, FullyCrackedActionNone:
+		return *setting.Value
+	}
+	debug.Warning("Unknown hashlist_fully_cracked_action %q, using %q", *setting.Value, FullyCrackedActionSupersede)
+	return FullyCrackedActionSupersede
+}
+
 // HandleHashlistFullyCracked processes all jobs for a hashlist when all hashes are cracked
 func (s *HashlistCompletionService) HandleHashlistFullyCracked(ctx context.Context, hashlistID int64) error {
 	debug.Info("HandleHashlistFullyCracked called for hashlist %d", hashlistID)
 
-	// Note: We skip database verification here because this handler is triggered by
-	// hashcat status code 6 (AllHashesCracked flag), which is authoritative.
-	// The database may lag behind due to async crack processing, causing a race condition
-	// where the handler checks before cracks are written to DB.
-	// We trust hashcat's status code 6 signal and proceed immediately.
+	// Note: We skip database verification here because callers have already
+	// decided: hashcat status code 6 (AllHashesCracked flag) is authoritative,
+	// and the database may lag behind it due to async crack processing, while
+	// CheckHashlist and the scheduler only call this once the counts match.
 
-	debug.Info("Hashlist %d - processing job completion (triggered by hashcat status code 6)",
-		hashlistID)
+	debug.Info("Hashlist %d - processing job completion", hashlistID)
+
+	action := s.fullyCrackedAction(ctx)
+	if action == FullyCrackedActionNone {
+		debug.Info("Hashlist %d is fully cracked, leaving its jobs alone (hashlist_fully_cracked_action=none)", hashlistID)
+		return nil
+	}
+
+	s.processingMu.Lock()
+	if s.processing[hashlistID] {
+		s.processingMu.Unlock()
+		debug.Info("Hashlist %d completion is already being processed", hashlistID)
+		return nil
+	}
+	s.processing[hashlistID] = true
+	s.processingMu.Unlock()
+	defer func() {
+		s.processingMu.Lock()
+		delete(s.processing, hashlistID)
+		s.processingMu.Unlock()
+	}()
 
 	// 2. Get all non-completed jobs for this hashlist
 	jobs, err := s.jobExecRepo.GetNonCompletedJobsByHashlistID(ctx, hashlistID)
@@ -75,6 +149,7 @@ func (s *HashlistCompletionService) HandleHashlistFullyCracked(ctx context.Conte
 	// 3. Process each job
 	jobsCompleted := 0
 	jobsDeleted := 0
+	jobsSuperseded := 0
 	jobsFailed := 0
 
 	for _, job := range jobs {
@@ -110,6 +185,31 @@ func (s *HashlistCompletionService) HandleHashlistFullyCracked(ctx context.Conte
 			jobsCompleted++
 			debug.Info("Job %s (%s) marked as completed (all hashes cracked)", job.ID, job.Name)
 
+		} else if action == FullyCrackedActionSupersede {
+			// Job has no tasks - it never started
+			err = s.jobExecRepo.SupersedeExecution(ctx, job.ID)
+			if errors.Is(err, repository.ErrNotFound) {
+				// Neither pending nor paused, e.g. already cancelled
+				continue
+			}
+			if err != nil {
+				debug.Error("Failed to supersede unstarted job %s: %v", job.ID, err)
+				jobsFailed++
+				continue
+			}
+
+			err = events.Publish(ctx, s.db, events.JobSuperseded, events.JobSupersededPayload{
+				JobExecutionID: job.ID,
+				HashlistID:     hashlistID,
+				CreatedBy:      job.CreatedBy,
+			})
+			if err != nil {
+				debug.Warning("Failed to publish superseded event for job %s: %v", job.ID, err)
+			}
+
+			jobsSuperseded++
+			debug.Info("Job %s (%s) superseded (never started, hashlist fully cracked)", job.ID, job.Name)
+
 		} else {
 			// Job has no tasks - it never started
 			debug.Info("Job %s (%s) has no tasks - deleting (never started)", job.ID, job.Name)
@@ -126,8 +226,8 @@ func (s *HashlistCompletionService) HandleHashlistFullyCracked(ctx context.Conte
 		}
 	}
 
-	debug.Info("Hashlist %d completion processing finished: %d completed, %d deleted, %d failed",
-		hashlistID, jobsCompleted, jobsDeleted, jobsFailed)
+	debug.Info("Hashlist %d completion processing finished: %d completed, %d superseded, %d deleted, %d failed",
+		hashlistID, jobsCompleted, jobsSuperseded, jobsDeleted, jobsFailed)
 
 	return nil
 }
@@ -179,6 +279,11 @@ func (s *HashlistCompletionService) stopJobTasks(ctx context.Context, jobID uuid
 			if err := s.jobTaskRepo.UpdateStatus(ctx, task.ID, models.JobTaskStatusCancelled); err != nil {
 				debug.Error("Failed to update task %s status to cancelled: %v", task.ID, err)
 			}
+		} else if task.Status == models.JobTaskStatusPending || task.Status == models.JobTaskStatusReconnectPending {
+			// No agent is working on it, it must just not be picked up again
+			if err := s.jobTaskRepo.UpdateStatus(ctx, task.ID, models.JobTaskStatusCancelled); err != nil {
+				debug.Error("Failed to update task %s status to cancelled: %v", task.ID, err)
+			}
 		}
 	}
 
@@ -214,16 +319,14 @@ func (s *HashlistCompletionService) completeJob(ctx context.Context, job *models
 		}
 	}
 
-	// Send notification if notification service is available and job has a user
-	if s.notificationService != nil && job.CreatedBy != nil {
-		// Send job completion email notification
-		err = s.notificationService.SendJobCompletionEmail(ctx, job.ID, *job.CreatedBy)
-		if err != nil {
-			debug.Warning("Failed to send job completion notification for job %s: %v", job.ID, err)
-			// Not critical, just log - user preferences might not be set or email disabled
-		} else {
-			debug.Info("Sent job completion notification for job %s", job.ID)
-		}
+	// Subscribers such as the notification service send the completion email
+	err = events.Publish(ctx, s.db, events.JobCompleted, events.JobCompletedPayload{
+		JobExecutionID: job.ID,
+		CreatedBy:      job.CreatedBy,
+	})
+	if err != nil {
+		debug.Warning("Failed to publish completion event for job %s: %v", job.ID, err)
+		// Not critical, just log
 	}
 
 	return nil
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCompletionServiceWithMock creates a hashlist completion service on a mocked database
+func newCompletionServiceWithMock(t *testing.T) (*HashlistCompletionService, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	database := &db.DB{DB: mockDB}
+	service := NewHashlistCompletionService(
+		database,
+		repository.NewJobExecutionRepository(database),
+		repository.NewJobTaskRepository(database),
+		repository.NewHashListRepository(database),
+		nil,
+	)
+	return service, mock
+}
+
+// expectFullyCrackedAction mocks the hashlist_fully_cracked_action setting, nil for a missing one
+func expectFullyCrackedAction(mock sqlmock.Sqlmock, value *string) {
+	query := mock.ExpectQuery("SELECT key, value, description, data_type, updated_at FROM system_settings").
+		WithArgs("hashlist_fully_cracked_action")
+	if value == nil {
+		query.WillReturnError(sql.ErrNoRows)
+		return
+	}
+	query.WillReturnRows(sqlmock.NewRows([]string{"key", "value", "description", "data_type", "updated_at"}).
+		AddRow("hashlist_fully_cracked_action", *value, "", "string", time.Now()))
+}
+
+func TestFullyCrackedAction(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		setting *string
+		want    string
+	}{
+		{"missing setting", nil, FullyCrackedActionSupersede},
+		{"supersede", strPtr("supersede"), FullyCrackedActionSupersede},
+		{"delete", strPtr("delete"), FullyCrackedActionDelete},
+		{"none", strPtr("none"), FullyCrackedActionNone},
+		{"unknown value", strPtr("archive"), FullyCrackedActionSupersede},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mock := newCompletionServiceWithMock(t)
+			expectFullyCrackedAction(mock, tt.setting)
+
+			assert.Equal(t, tt.want, service.fullyCrackedAction(context.Background()))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestHandleHashlistFullyCrackedLeavesJobsAloneWithActionNone(t *testing.T) {
+	service, mock := newCompletionServiceWithMock(t)
+	none := FullyCrackedActionNone
+	expectFullyCrackedAction(mock, &none)
+
+	// No jobs are queried, completed or superseded
+	require.NoError(t, service.HandleHashlistFullyCracked(context.Background(), 42))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// jobArchiveBatchSize limits how many executions are archived per pass
+const jobArchiveBatchSize = 100
+
+// JobArchiveService moves old finished job executions into the archive table
+// and restores them on demand
+type JobArchiveService struct {
+	archiveRepo        *repository.JobArchiveRepository
+	systemSettingsRepo *repository.SystemSettingsRepository
+}
+
+// NewJobArchiveService creates a new job archive service
+func NewJobArchiveService(archiveRepo *repository.JobArchiveRepository, systemSettingsRepo *repository.SystemSettingsRepository) *JobArchiveService {
+	return &JobArchiveService{
+		archiveRepo:        archiveRepo,
+		systemSettingsRepo: systemSettingsRepo,
+	}
+}
+
+// StartArchiveScheduler runs the archive pass on startup and then once a day
+func (s *JobArchiveService) StartArchiveScheduler(ctx context.Context) {
+	if _, err := s.RunArchive(ctx); err != nil {
+		debug.Error("Job archive pass failed: %v", err)
+	}
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			debug.Info("Job archive scheduler stopped")
+			return
+		case <-ticker.C:
+			if _, err := s.RunArchive(ctx); err != nil {
+				debug.Error("Job archive pass failed: %v", err)
+			}
+		}
+	}
+}
+
+// RunArchive archives every finished job execution older than the configured
+// threshold and returns the number archived. A threshold of 0 disables archival.
+func (s *JobArchiveService) RunArchive(ctx context.Context) (int, error) {
+	days, err := s.getArchiveAfterDays(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if days == 0 {
+		debug.Debug("Job archival is disabled, skipping")
+		return 0, nil
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	debug.Info("Archiving job executions finished before %s (%d days)", cutoff.Format("2006-01-02"), days)
+
+	archived := 0
+	for {
+		ids, err := s.archiveRepo.GetArchivableJobIDs(ctx, cutoff, jobArchiveBatchSize)
+		if err != nil {
+			return archived, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		batchArchived := 0
+		for _, id := range ids {
+			if _, err := s.archiveRepo.Archive(ctx, id, nil); err != nil {
+				debug.Error("Failed to archive job execution %s: %v", id, err)
+				continue
+			}
+			batchArchived++
+		}
+		archived += batchArchived
+
+		// Stop if nothing in this batch could be archived to avoid looping on failures
+		if batchArchived == 0 || len(ids) < jobArchiveBatchSize {
+			break
+		}
+	}
+
+	debug.Info("Job archive pass completed, archived %d job executions", archived)
+	return archived, nil
+}
+
+// ArchiveJob archives a single finished job execution on behalf of an admin
+func (s *JobArchiveService) ArchiveJob(ctx context.Context, jobExecutionID uuid.UUID, archivedBy *uuid.UUID) (*models.JobExecutionArchive, error) {
+	return s.archiveRepo.Archive(ctx, jobExecutionID, archivedBy)
+}
+
+// RestoreJob moves an archived job execution back into the live tables
+func (s *JobArchiveService) RestoreJob(ctx context.Context, jobExecutionID uuid.UUID) error {
+	if err := s.archiveRepo.Restore(ctx, jobExecutionID); err != nil {
+		return err
+	}
+	debug.Info("Restored job execution %s from archive", jobExecutionID)
+	return nil
+}
+
+// ListArchives returns a page of archived job summaries
+func (s *JobArchiveService) ListArchives(ctx context.Context, hashlistID *int64, limit, offset int) ([]models.JobExecutionArchive, int, error) {
+	return s.archiveRepo.List(ctx, hashlistID, limit, offset)
+}
+
+// GetArchive returns a single archived job summary
+func (s *JobArchiveService) GetArchive(ctx context.Context, jobExecutionID uuid.UUID) (*models.JobExecutionArchive, error) {
+	return s.archiveRepo.GetByID(ctx, jobExecutionID)
+}
+
+// GetStats returns aggregate statistics over the archive
+func (s *JobArchiveService) GetStats(ctx context.Context) (*models.JobArchiveStats, error) {
+	return s.archiveRepo.GetStats(ctx)
+}
+
+// getArchiveAfterDays reads the job_archive_after_days system setting
+func (s *JobArchiveService) getArchiveAfterDays(ctx context.Context) (int, error) {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, "job_archive_after_days")
+	if err != nil || setting.Value == nil {
+		// Archival is opt-in
+		return 0, nil
+	}
+
+	var days int
+	if _, err := fmt.Sscanf(*setting.Value, "%d", &days); err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid job_archive_after_days value: %s", *setting.Value)
+	}
+	return days, nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// pendingBenchmarkTimeout is how long a requested speed benchmark is waited
+// for before it may be requested again, matching the forced benchmark timeout
+const pendingBenchmarkTimeout = 5 * time.Minute
+
+// benchmarkProfile is what an agent's speed benchmark depends on. Jobs with
+// the same profile share one benchmark per agent.
+type benchmarkProfile struct {
+	agentID         int
+	attackMode      models.AttackMode
+	hashType        int
+	binaryVersionID int
+}
+
+// pendingBenchmarks tracks the speed benchmarks that were requested but not
+// answered yet, so queued jobs with the same profile wait for the one running
+// benchmark instead of each requesting their own
+type pendingBenchmarks struct {
+	mu        sync.Mutex
+	requested map[benchmarkProfile]time.Time
+}
+
+// newPendingBenchmarks creates an empty pending benchmark cache
+func newPendingBenchmarks() *pendingBenchmarks {
+	return &pendingBenchmarks{requested: make(map[benchmarkProfile]time.Time)}
+}
+
+// claim reports whether the caller should request a benchmark for the
+// profile, recording it as pending if so. It returns false while an earlier
+// request for the profile has not timed out.
+func (p *pendingBenchmarks) claim(profile benchmarkProfile, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if requestedAt, ok := p.requested[profile]; ok && now.Sub(requestedAt) < pendingBenchmarkTimeout {
+		return false
+	}
+	p.requested[profile] = now
+	return true
+}
+
+// release forgets a pending benchmark, once its result arrived or the request
+// could not be sent
+func (p *pendingBenchmarks) release(profile benchmarkProfile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.requested, profile)
+}
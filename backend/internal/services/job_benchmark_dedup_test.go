@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingBenchmarks(t *testing.T) {
+	now := time.Now()
+	pending := newPendingBenchmarks()
+	profile := benchmarkProfile{agentID: 1, attackMode: models.AttackModeStraight, hashType: 1000, binaryVersionID: 2}
+
+	// The first job requests the benchmark, later jobs with the same profile wait
+	assert.True(t, pending.claim(profile, now))
+	assert.False(t, pending.claim(profile, now.Add(time.Minute)))
+
+	// Another agent, hash type or binary is benchmarked separately
+	otherAgent := profile
+	otherAgent.agentID = 2
+	assert.True(t, pending.claim(otherAgent, now))
+	otherBinary := profile
+	otherBinary.binaryVersionID = 3
+	assert.True(t, pending.claim(otherBinary, now))
+
+	// A request that was never answered may be repeated
+	assert.True(t, pending.claim(profile, now.Add(pendingBenchmarkTimeout)))
+
+	// Once released the next job requests a new benchmark
+	pending.release(profile)
+	assert.True(t, pending.claim(profile, now.Add(pendingBenchmarkTimeout)))
+}
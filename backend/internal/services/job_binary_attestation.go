@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// QuarantineForBinaryMismatch is called when an agent refused a task because
+// its hashcat binary did not match the hash recorded by the binary manager. A
+// modified binary can silently skip or fake work, so the agent is disabled
+// until an admin has checked it, and admins are alerted.
+func (s *JobSchedulingService) QuarantineForBinaryMismatch(ctx context.Context, task *models.JobTask, agentID int, attestation *models.BinaryAttestation) error {
+	agent, err := s.agentRepo.GetByID(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to get agent %d: %w", agentID, err)
+	}
+
+	payload := events.BinaryIntegrityFailedPayload{
+		TaskID:         task.ID,
+		JobExecutionID: task.JobExecutionID,
+		AgentID:        agent.ID,
+		AgentName:      agent.Name,
+	}
+	if attestation != nil {
+		payload.Executable = attestation.Executable
+		payload.Expected = attestation.Expected
+		payload.Actual = attestation.SHA256
+	}
+
+	tx, err := s.jobExecutionService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.agentRepo.Disable(ctx, tx, agent.ID); err != nil {
+		return err
+	}
+	if err := events.Publish(ctx, tx, events.BinaryIntegrityFailed, payload); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	debug.Warning("Agent %q (%d) reported a modified hashcat binary %s for task %s, agent disabled",
+		agent.Name, agent.ID, payload.Executable, task.ID)
+	return nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// maxReusedChunksPerPass bounds how many chunks a single scheduling pass copies into a job
+const maxReusedChunksPerPass = 1000
+
+// reuseDuplicateChunks advances a job past every chunk that another job running
+// the identical attack on the same hashlist has already completed. Those chunks
+// are recorded as completed tasks that point at the original instead of being
+// dispatched again; their cracks are already in the shared hashlist. Only the
+// chunks at the job's dispatch frontier are reused, so the two jobs must split
+// their keyspace identically for a chunk to match. Returns the number reused.
+func (s *JobSchedulingService) reuseDuplicateChunks(ctx context.Context, job *models.JobExecution) (int, error) {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, "chunk_dedup_enabled")
+	if err != nil || setting.Value == nil || *setting.Value != "true" {
+		return 0, nil
+	}
+
+	taskRepo := s.jobExecutionService.jobTaskRepo
+	reused := 0
+	for reused < maxReusedChunksPerPass {
+		var task *models.JobTask
+
+		if job.UsesRuleSplitting {
+			ruleStart := 0
+			maxRuleEnd, err := taskRepo.GetMaxRuleEndIndex(ctx, job.ID)
+			if err != nil {
+				return reused, fmt.Errorf("failed to get max rule end index: %w", err)
+			}
+			if maxRuleEnd != nil {
+				ruleStart = *maxRuleEnd
+			}
+
+			chunkNumber, err := taskRepo.GetNextChunkNumber(ctx, job.ID)
+			if err != nil {
+				return reused, fmt.Errorf("failed to get next chunk number: %w", err)
+			}
+
+			// Place the copy after this job's previous chunks, as for a freshly created chunk
+			baseKeyspace := int64(0)
+			if job.BaseKeyspace != nil {
+				baseKeyspace = *job.BaseKeyspace
+			}
+			effectiveStart, err := taskRepo.GetPreviousChunksActualKeyspace(ctx, job.ID, chunkNumber)
+			if err != nil || effectiveStart == 0 {
+				effectiveStart = baseKeyspace * int64(ruleStart)
+			}
+
+			task, err = taskRepo.ReuseDuplicateChunk(ctx, job.ID, 0, &ruleStart, &effectiveStart, chunkNumber)
+			if err != nil {
+				return reused, err
+			}
+			if task == nil {
+				break
+			}
+
+			dispatched := baseKeyspace * int64(*task.RuleEndIndex-*task.RuleStartIndex)
+			if err := s.jobExecutionService.jobExecRepo.IncrementDispatchedKeyspace(ctx, job.ID, dispatched); err != nil {
+				debug.Error("Failed to update dispatched keyspace: %v", err)
+			}
+			job.DispatchedKeyspace += dispatched
+
+			job.RuleSplitCount = chunkNumber
+			if err := s.jobExecutionService.jobExecRepo.UpdateKeyspaceInfo(ctx, job); err != nil {
+				debug.Error("Failed to update rule split count: %v", err)
+			}
+		} else {
+			keyspaceStart, _, err := taskRepo.GetNextKeyspaceRange(ctx, job.ID)
+			if err != nil {
+				return reused, err
+			}
+			if job.TotalKeyspace != nil && keyspaceStart >= *job.TotalKeyspace {
+				break
+			}
+
+			task, err = taskRepo.ReuseDuplicateChunk(ctx, job.ID, keyspaceStart, nil, nil, 0)
+			if err != nil {
+				return reused, err
+			}
+			if task == nil {
+				break
+			}
+
+			dispatched := task.KeyspaceEnd - task.KeyspaceStart
+			if err := s.jobExecutionService.jobExecRepo.IncrementDispatchedKeyspace(ctx, job.ID, dispatched); err != nil {
+				debug.Error("Failed to update dispatched keyspace: %v", err)
+			}
+			job.DispatchedKeyspace += dispatched
+		}
+
+		debug.Log("Reused duplicate chunk from another job", map[string]interface{}{
+			"job_id":         job.ID,
+			"task_id":        task.ID,
+			"reused_from":    task.ReusedFrom,
+			"keyspace_start": task.KeyspaceStart,
+			"keyspace_end":   task.KeyspaceEnd,
+			"rule_start":     task.RuleStartIndex,
+			"rule_end":       task.RuleEndIndex,
+		})
+		reused++
+	}
+
+	if reused > 0 {
+		debug.Info("Reused %d completed chunks from identical jobs for job %s", reused, job.ID)
+		if err := s.jobExecutionService.RefreshJobProgress(ctx, job.ID); err != nil {
+			debug.Error("Failed to refresh progress of job %s after reusing chunks: %v", job.ID, err)
+		}
+	}
+
+	return reused, nil
+}
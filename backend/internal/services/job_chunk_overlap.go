@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// ResolveChunkOverlap returns how many candidates before its start a task is
+// dispatched with. Rules can reorder candidates so that a few are lost exactly
+// at the --skip/--limit boundary between two chunks; starting each chunk a
+// little early covers them. The job's own chunk_overlap takes precedence over
+// the chunk_overlap_candidates setting, and the overlap never reaches before
+// the start of the keyspace. Rule-split tasks always cover the whole wordlist
+// and get no overlap.
+func (s *JobExecutionService) ResolveChunkOverlap(ctx context.Context, task *models.JobTask) int64 {
+	if task.IsRuleSplitTask || task.KeyspaceStart <= 0 {
+		return 0
+	}
+
+	overlap := int64(0)
+	jobOverlap, err := s.jobExecRepo.GetChunkOverlap(ctx, task.JobExecutionID)
+	if err != nil {
+		debug.Warning("Failed to get chunk overlap for job %s: %v", task.JobExecutionID, err)
+	}
+	if jobOverlap != nil {
+		overlap = *jobOverlap
+	} else if value, err := s.GetSystemSetting(ctx, "chunk_overlap_candidates"); err == nil && value > 0 {
+		overlap = int64(value)
+	}
+
+	if overlap > task.KeyspaceStart {
+		overlap = task.KeyspaceStart
+	}
+	return overlap
+}
+
+// RemoveChunkOverlap rewrites a progress update from an agent that ran a task
+// with a chunk overlap so it describes the task's own keyspace only. The agent
+// counts from the dispatched start, which lies task.ChunkOverlap candidates
+// before task.KeyspaceStart; progress through the overlap does not count
+// towards the chunk. Cracks found in the overlap need no adjustment, hashes
+// that were already cracked are skipped when cracks are stored.
+func RemoveChunkOverlap(task *models.JobTask, progress *models.JobProgress) {
+	overlap := task.ChunkOverlap
+	if overlap <= 0 {
+		return
+	}
+	chunkSize := task.KeyspaceEnd - task.KeyspaceStart
+	dispatchedSize := chunkSize + overlap
+	if chunkSize <= 0 {
+		return
+	}
+
+	progress.KeyspaceProcessed = clampInt64(progress.KeyspaceProcessed-overlap, 0, chunkSize)
+
+	// The effective keyspace includes the rule multiplier, scale the overlap by it
+	if progress.TotalEffectiveKeyspace != nil && *progress.TotalEffectiveKeyspace > 0 {
+		total := *progress.TotalEffectiveKeyspace
+		effectiveOverlap := int64(float64(total) * float64(overlap) / float64(dispatchedSize))
+		chunkTotal := total - effectiveOverlap
+		progress.TotalEffectiveKeyspace = &chunkTotal
+		progress.EffectiveProgress = clampInt64(progress.EffectiveProgress-effectiveOverlap, 0, chunkTotal)
+	} else if progress.EffectiveProgress > 0 && progress.ProgressPercent > 0 {
+		total := float64(progress.EffectiveProgress) * 100 / progress.ProgressPercent
+		effectiveOverlap := int64(total * float64(overlap) / float64(dispatchedSize))
+		progress.EffectiveProgress = clampInt64(progress.EffectiveProgress-effectiveOverlap, 0, progress.EffectiveProgress)
+	}
+
+	processed := progress.ProgressPercent/100*float64(dispatchedSize) - float64(overlap)
+	progress.ProgressPercent = clampFloat64(processed/float64(chunkSize)*100, 0, 100)
+}
+
+func clampInt64(v, min, max int64) int64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func clampFloat64(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
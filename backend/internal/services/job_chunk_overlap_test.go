@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveChunkOverlap(t *testing.T) {
+	// Chunk of 1000 candidates dispatched with 100 candidates of overlap
+	task := &models.JobTask{KeyspaceStart: 5000, KeyspaceEnd: 6000, ChunkOverlap: 100}
+
+	t.Run("first update scales the effective keyspace", func(t *testing.T) {
+		total := int64(11000) // 1100 candidates x 10 rules
+		progress := &models.JobProgress{
+			KeyspaceProcessed:      550,
+			EffectiveProgress:      5500,
+			ProgressPercent:        50,
+			TotalEffectiveKeyspace: &total,
+		}
+
+		RemoveChunkOverlap(task, progress)
+		assert.Equal(t, int64(450), progress.KeyspaceProcessed)
+		assert.Equal(t, int64(10000), *progress.TotalEffectiveKeyspace)
+		assert.Equal(t, int64(4500), progress.EffectiveProgress)
+		assert.InDelta(t, 45.0, progress.ProgressPercent, 0.001)
+	})
+
+	t.Run("later updates derive the total from the percentage", func(t *testing.T) {
+		progress := &models.JobProgress{KeyspaceProcessed: 1100, EffectiveProgress: 11000, ProgressPercent: 100}
+
+		RemoveChunkOverlap(task, progress)
+		assert.Equal(t, int64(1000), progress.KeyspaceProcessed)
+		assert.Equal(t, int64(10000), progress.EffectiveProgress)
+		assert.InDelta(t, 100.0, progress.ProgressPercent, 0.001)
+	})
+
+	t.Run("progress inside the overlap counts as nothing", func(t *testing.T) {
+		progress := &models.JobProgress{KeyspaceProcessed: 50, EffectiveProgress: 500, ProgressPercent: 50.0 / 11}
+
+		RemoveChunkOverlap(task, progress)
+		assert.Equal(t, int64(0), progress.KeyspaceProcessed)
+		assert.Equal(t, int64(0), progress.EffectiveProgress)
+		assert.Equal(t, 0.0, progress.ProgressPercent)
+	})
+
+	t.Run("tasks without overlap are untouched", func(t *testing.T) {
+		progress := &models.JobProgress{KeyspaceProcessed: 500, EffectiveProgress: 5000, ProgressPercent: 50}
+
+		RemoveChunkOverlap(&models.JobTask{KeyspaceStart: 5000, KeyspaceEnd: 6000}, progress)
+		assert.Equal(t, int64(500), progress.KeyspaceProcessed)
+		assert.Equal(t, int64(5000), progress.EffectiveProgress)
+		assert.Equal(t, 50.0, progress.ProgressPercent)
+	})
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// Default used when the chunk verification sample setting is missing or invalid
+const defaultChunkVerificationSamplePercent = 2.0
+
+// chunkVerificationSettings reads whether chunk verification is enabled and
+// the percentage of completed chunks it re-runs
+func (s *JobSchedulingService) chunkVerificationSettings(ctx context.Context) (bool, float64) {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, "chunk_verification_enabled")
+	if err != nil || setting.Value == nil || *setting.Value != "true" {
+		return false, 0
+	}
+
+	percent := defaultChunkVerificationSamplePercent
+	if setting, err := s.systemSettingsRepo.GetSetting(ctx, "chunk_verification_sample_percent"); err == nil && setting.Value != nil {
+		if parsed, err := strconv.ParseFloat(*setting.Value, 64); err == nil && parsed >= 0 && parsed <= 100 {
+			percent = parsed
+		}
+	}
+
+	return true, percent
+}
+
+// sampledForVerification reports whether a completed task is re-run for
+// verification, given a roll uniform in [0, 100). Rule-split chunks are never
+// sampled since their rule file is removed once they complete, and neither are
+// verifier tasks themselves.
+func sampledForVerification(task *models.JobTask, percent float64, roll float64) bool {
+	if task.AgentID == nil || task.VerificationOf != nil || task.IsRuleSplitTask {
+		return false
+	}
+	return roll < percent
+}
+
+// SampleCompletedTask is called once a task has completed. With chunk
+// verification enabled a random sample of completed chunks is queued to be
+// re-run on a different agent; see assignVerificationTask.
+func (s *JobSchedulingService) SampleCompletedTask(ctx context.Context, task *models.JobTask) error {
+	repo := s.jobExecutionService.chunkVerifications
+	if repo == nil {
+		return nil
+	}
+	enabled, percent := s.chunkVerificationSettings(ctx)
+	if !enabled || !sampledForVerification(task, percent, rand.Float64()*100) {
+		return nil
+	}
+
+	created, err := repo.Create(ctx, task)
+	if err != nil {
+		return err
+	}
+	if created {
+		debug.Info("Sampled task %s (chunk %d of job %s, agent %d) for verification",
+			task.ID, task.ChunkNumber, task.JobExecutionID, *task.AgentID)
+	}
+	return nil
+}
+
+// assignVerificationTask gives an idle agent a copy of a completed task that
+// was sampled for verification, never one the agent ran itself. Returns nil
+// when verification is disabled, the agent is not idle or nothing is pending.
+func (s *JobSchedulingService) assignVerificationTask(ctx context.Context, agent *models.Agent) (*models.JobTask, error) {
+	repo := s.jobExecutionService.chunkVerifications
+	if repo == nil || s.wsIntegration == nil {
+		return nil, nil
+	}
+	if enabled, _ := s.chunkVerificationSettings(ctx); !enabled {
+		return nil, nil
+	}
+
+	idle, err := s.agentHasNoWork(ctx, agent)
+	if err != nil || !idle {
+		return nil, err
+	}
+
+	if skipped, err := repo.SkipFinishedJobs(ctx); err != nil {
+		debug.Warning("Failed to skip chunk verifications of finished jobs: %v", err)
+	} else if skipped > 0 {
+		debug.Info("Skipped %d chunk verifications of jobs that are no longer running", skipped)
+	}
+
+	candidates, err := repo.GetPendingCandidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	priorityLimit, err := s.jobExecutionService.AgentPriorityLimit(ctx, agent.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check power window: %w", err)
+	}
+
+	for _, candidate := range candidates {
+		original := candidate.Task
+		if original.AgentID != nil && *original.AgentID == agent.ID {
+			continue
+		}
+		if priorityLimit != nil && original.Priority > *priorityLimit {
+			continue
+		}
+
+		job, err := s.jobExecutionService.jobExecRepo.GetByID(ctx, original.JobExecutionID)
+		if err != nil {
+			debug.Warning("Skipping verification of task %s: failed to get job: %v", original.ID, err)
+			continue
+		}
+		if !job.AgentPlacement.Allows(agent.ID) || (agent.IsCloudBurst() && !job.AllowCloudBurst) {
+			continue
+		}
+
+		if err := s.hashlistSyncService.EnsureHashlistOnAgent(ctx, agent.ID, job.HashlistID); err != nil {
+			debug.Warning("Skipping verification of task %s on agent %d: failed to sync hashlist: %v", original.ID, agent.ID, err)
+			continue
+		}
+
+		claimed, err := repo.Claim(ctx, candidate.VerificationID, agent.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			continue
+		}
+
+		originalID := original.ID
+		verifierTask := &models.JobTask{
+			JobExecutionID:         original.JobExecutionID,
+			AgentID:                &agent.ID,
+			Status:                 models.JobTaskStatusPending,
+			Priority:               original.Priority,
+			AttackCmd:              original.AttackCmd,
+			KeyspaceStart:          original.KeyspaceStart,
+			KeyspaceEnd:            original.KeyspaceEnd,
+			EffectiveKeyspaceStart: original.EffectiveKeyspaceStart,
+			EffectiveKeyspaceEnd:   original.EffectiveKeyspaceEnd,
+			IsActualKeyspace:       original.IsActualKeyspace,
+			BenchmarkSpeed:         original.BenchmarkSpeed,
+			ChunkDuration:          original.ChunkDuration,
+			ChunkNumber:            original.ChunkNumber,
+			VerificationOf:         &originalID,
+		}
+		if err := s.jobExecutionService.jobTaskRepo.Create(ctx, verifierTask); err != nil {
+			if skipErr := repo.Skip(ctx, candidate.VerificationID); skipErr != nil {
+				debug.Warning("Failed to skip chunk verification %d: %v", candidate.VerificationID, skipErr)
+			}
+			return nil, fmt.Errorf("failed to create verification task: %w", err)
+		}
+		if err := repo.SetVerifierTask(ctx, candidate.VerificationID, verifierTask.ID); err != nil {
+			debug.Error("Failed to record verifier task %s of chunk verification %d: %v", verifierTask.ID, candidate.VerificationID, err)
+		}
+
+		if err := s.wsIntegration.SendJobAssignment(ctx, verifierTask, job); err != nil {
+			debug.Error("Failed to send verification task %s to agent %d: %v", verifierTask.ID, agent.ID, err)
+		}
+
+		debug.Info("Re-running task %s (chunk %d of job %s) on agent %d as verification task %s",
+			original.ID, original.ChunkNumber, job.ID, agent.ID, verifierTask.ID)
+		return verifierTask, nil
+	}
+
+	return nil, nil
+}
+
+// ResolveVerificationTask is called once a verification task has completed.
+// Any hash it cracked was in a keyspace the original agent reported as done,
+// so the original agent is quarantined and admins are alerted.
+func (s *JobSchedulingService) ResolveVerificationTask(ctx context.Context, verifier *models.JobTask) error {
+	repo := s.jobExecutionService.chunkVerifications
+	if repo == nil || verifier.VerificationOf == nil {
+		return nil
+	}
+
+	verification, err := repo.GetByVerifierTask(ctx, verifier.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	originalCracks, verifierCracks, err := repo.CrackCounts(ctx, verification)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.jobExecutionService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	verification, err = repo.Complete(ctx, tx, verification.ID, originalCracks, verifierCracks)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil // Already resolved
+	}
+	if err != nil {
+		return err
+	}
+
+	if verification.Status == models.ChunkVerificationMismatch {
+		if err := repo.QuarantineAgent(ctx, tx, verification); err != nil {
+			return err
+		}
+		err = events.Publish(ctx, tx, events.ChunkVerificationFailed, events.ChunkVerificationFailedPayload{
+			VerificationID:   verification.ID,
+			TaskID:           verification.TaskID,
+			JobExecutionID:   verification.JobExecutionID,
+			AgentID:          verification.AgentID,
+			AgentName:        verification.AgentName,
+			VerifierAgentID:  verification.VerifierAgentID,
+			MissedCracks:     verifierCracks,
+			AgentQuarantined: verification.AgentQuarantined,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if verification.Status == models.ChunkVerificationMismatch {
+		debug.Warning("Chunk verification %d failed: verification task %s cracked %d hashes that agent %q missed in task %s, agent quarantined",
+			verification.ID, verifier.ID, verifierCracks, verification.AgentName, verification.TaskID)
+	} else {
+		debug.Info("Chunk verification %d passed: task %s re-run as %s found no missed hashes",
+			verification.ID, verification.TaskID, verifier.ID)
+	}
+	return nil
+}
+
+// AbandonVerificationTask is called when a verification task fails. The
+// verification is skipped rather than counted against either agent.
+func (s *JobSchedulingService) AbandonVerificationTask(ctx context.Context, verifier *models.JobTask) error {
+	repo := s.jobExecutionService.chunkVerifications
+	if repo == nil || verifier.VerificationOf == nil {
+		return nil
+	}
+
+	verification, err := repo.GetByVerifierTask(ctx, verifier.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return repo.Skip(ctx, verification.ID)
+}
+
+// agentHasNoWork reports whether an agent is connected with nothing at all to
+// do, the only agents given speculative or verification work
+func (s *JobSchedulingService) agentHasNoWork(ctx context.Context, agent *models.Agent) (bool, error) {
+	if agent.Metadata != nil {
+		if agent.Metadata["busy_status"] == "true" || agent.Metadata["pending_benchmark_job"] != "" {
+			return false, nil
+		}
+	}
+	activeTasks, err := s.jobExecutionService.jobTaskRepo.GetActiveTasksByAgent(ctx, agent.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get active tasks: %w", err)
+	}
+	return len(activeTasks) == 0, nil
+}
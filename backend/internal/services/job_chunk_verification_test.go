@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampledForVerification(t *testing.T) {
+	agentID := 3
+	task := &models.JobTask{ID: uuid.New(), AgentID: &agentID}
+
+	assert.True(t, sampledForVerification(task, 2, 1.5))
+	assert.False(t, sampledForVerification(task, 2, 2), "roll at the sample percentage")
+	assert.False(t, sampledForVerification(task, 0, 0), "sampling disabled by a zero percentage")
+	assert.True(t, sampledForVerification(task, 100, 99.9))
+
+	original := task.ID
+	assert.False(t, sampledForVerification(&models.JobTask{AgentID: &agentID, VerificationOf: &original}, 100, 0),
+		"verification tasks are not verified again")
+	assert.False(t, sampledForVerification(&models.JobTask{AgentID: &agentID, IsRuleSplitTask: true}, 100, 0),
+		"rule chunks are removed on completion")
+	assert.False(t, sampledForVerification(&models.JobTask{}, 100, 0), "no agent to check")
+}
+
+func TestProgressTasksIgnoresVerification(t *testing.T) {
+	original := models.JobTask{ID: uuid.New(), Status: models.JobTaskStatusCompleted}
+	originalID := original.ID
+	verifier := models.JobTask{ID: uuid.New(), Status: models.JobTaskStatusCompleted, VerificationOf: &originalID}
+	running := models.JobTask{ID: uuid.New(), Status: models.JobTaskStatusRunning, VerificationOf: &originalID}
+
+	counted := progressTasks([]models.JobTask{original, verifier, running})
+	assert.Len(t, counted, 1)
+	assert.Equal(t, original.ID, counted[0].ID)
+}
+
+func TestChunkVerificationResult(t *testing.T) {
+	assert.Equal(t, models.ChunkVerificationPassed, models.ChunkVerificationResult(0))
+	assert.Equal(t, models.ChunkVerificationMismatch, models.ChunkVerificationResult(4))
+}
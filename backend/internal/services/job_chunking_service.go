@@ -37,6 +37,9 @@ type ChunkCalculationRequest struct {
 	AttackMode    models.AttackMode
 	HashType      int
 	ChunkDuration int // Desired chunk duration in seconds
+	// CandidatesPerUnit is how many candidates hashcat tries per unit of the
+	// job's keyspace, above 1 for hybrid attacks; 0 is treated as 1
+	CandidatesPerUnit int64
 }
 
 // ChunkCalculationResult contains the calculated chunk parameters
@@ -91,6 +94,15 @@ func (s *JobChunkingService) CalculateNextChunk(ctx context.Context, req ChunkCa
 		return nil, fmt.Errorf("no remaining keyspace for job")
 	}
 
+	// An --increment mask runs one mask length per task, so a chunk ends at
+	// the end of the length it starts in
+	chunkLimit := totalKeyspace
+	if req.JobExecution.MaskIncrement != nil {
+		if step, ok := req.JobExecution.MaskIncrement.StepAt(keyspaceStart); ok {
+			chunkLimit = step.End
+		}
+	}
+
 	// Get agent benchmark for this attack mode and hash type
 	benchmarkSpeed, err := s.GetOrEstimateBenchmark(ctx, req.Agent.ID, req.AttackMode, req.HashType)
 	if err != nil {
@@ -114,8 +126,18 @@ func (s *JobChunkingService) CalculateNextChunk(ctx context.Context, req ChunkCa
 		benchmarkSpeed = s.getDefaultBenchmarkEstimate(req.AttackMode, req.HashType)
 	}
 
+	// The benchmark speed counts candidates, a hybrid attack tries many per
+	// unit of its keyspace
+	candidatesPerUnit := req.CandidatesPerUnit
+	if candidatesPerUnit < 1 {
+		candidatesPerUnit = 1
+	}
+
 	// Calculate chunk size based on benchmark and desired duration
-	desiredChunkSize := int64(req.ChunkDuration) * benchmarkSpeed
+	desiredChunkSize := int64(req.ChunkDuration) * benchmarkSpeed / candidatesPerUnit
+	if desiredChunkSize < 1 {
+		desiredChunkSize = 1
+	}
 
 	debug.Log("Calculated desired chunk size", map[string]interface{}{
 		"chunk_duration":    req.ChunkDuration,
@@ -150,27 +172,27 @@ func (s *JobChunkingService) CalculateNextChunk(ctx context.Context, req ChunkCa
 		"total_keyspace":     totalKeyspace,
 	})
 
-	if keyspaceEnd >= totalKeyspace {
-		// This is the last chunk
-		keyspaceEnd = totalKeyspace
-		isLastChunk = true
-		actualDuration = int((totalKeyspace - keyspaceStart) / benchmarkSpeed)
+	if keyspaceEnd >= chunkLimit {
+		// This is the last chunk of the job or of its mask length
+		keyspaceEnd = chunkLimit
+		isLastChunk = chunkLimit == totalKeyspace
+		actualDuration = int((chunkLimit - keyspaceStart) * candidatesPerUnit / benchmarkSpeed)
 
 		debug.Log("Adjusted to last chunk", map[string]interface{}{
-			"reason":           "keyspace_end >= total_keyspace",
+			"reason":           "keyspace_end >= chunk_limit",
 			"keyspace_end":     keyspaceEnd,
 			"actual_duration":  actualDuration,
 		})
 	} else {
 		// Check if the remaining keyspace after this chunk would be too small
-		remainingAfterChunk := totalKeyspace - keyspaceEnd
+		remainingAfterChunk := chunkLimit - keyspaceEnd
 		fluctuationThreshold := int64(float64(desiredChunkSize) * float64(fluctuationPercentage) / 100.0)
 
 		if remainingAfterChunk <= fluctuationThreshold {
 			// Merge the final small chunk into this one
-			keyspaceEnd = totalKeyspace
-			isLastChunk = true
-			actualDuration = int((totalKeyspace - keyspaceStart) / benchmarkSpeed)
+			keyspaceEnd = chunkLimit
+			isLastChunk = chunkLimit == totalKeyspace
+			actualDuration = int((chunkLimit - keyspaceStart) * candidatesPerUnit / benchmarkSpeed)
 
 			debug.Log("Merging final chunk to avoid small remainder", map[string]interface{}{
 				"remaining_after_chunk": remainingAfterChunk,
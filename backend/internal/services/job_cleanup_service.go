@@ -18,6 +18,7 @@ type JobCleanupService struct {
 	jobTaskRepo        *repository.JobTaskRepository
 	systemSettingsRepo *repository.SystemSettingsRepository
 	agentRepo          *repository.AgentRepository
+	lateReports        *lateReports
 }
 
 // NewJobCleanupService creates a new job cleanup service
@@ -32,11 +33,14 @@ func NewJobCleanupService(
 		jobTaskRepo:        jobTaskRepo,
 		systemSettingsRepo: systemSettingsRepo,
 		agentRepo:          agentRepo,
+		lateReports:        newLateReports(),
 	}
 }
 
-// CleanupStaleTasksOnStartup cleans up tasks that were left in an incomplete state
-func (s *JobCleanupService) CleanupStaleTasksOnStartup(ctx context.Context) error {
+// CleanupStaleTasksOnStartup cleans up tasks that were left in an incomplete state.
+// Tasks handed over by a graceful shutdown keep running while their agents
+// reconnect; handover is nil after an unplanned stop.
+func (s *JobCleanupService) CleanupStaleTasksOnStartup(ctx context.Context, handover *models.ServerHandover) error {
 	debug.Info("Starting cleanup of stale tasks on startup with grace period for reconnection")
 
 	// FIRST: Check for orphaned running jobs (jobs with no active tasks)
@@ -51,6 +55,10 @@ func (s *JobCleanupService) CleanupStaleTasksOnStartup(ctx context.Context) erro
 		return fmt.Errorf("failed to get stale tasks: %w", err)
 	}
 
+	if handover != nil {
+		staleTasks = s.keepHandedOverTasks(ctx, staleTasks, handover)
+	}
+
 	if len(staleTasks) == 0 {
 		debug.Info("No stale tasks found during startup cleanup")
 		return nil
@@ -97,23 +105,81 @@ func (s *JobCleanupService) CleanupStaleTasksOnStartup(ctx context.Context) erro
 	return nil
 }
 
-// handleGracePeriodExpiration handles the expiration of the grace period for reconnect_pending tasks
-func (s *JobCleanupService) handleGracePeriodExpiration(ctx context.Context, tasks []*models.JobTask) {
-	// Get grace period from settings or use default
-	gracePeriod := 5 * time.Minute // Default 5 minutes instead of 2
-	gracePeriodSetting, err := s.systemSettingsRepo.GetSetting(ctx, "reconnect_grace_period_minutes")
-	if err == nil && gracePeriodSetting.Value != nil {
-		if minutes, err := strconv.Atoi(*gracePeriodSetting.Value); err == nil {
-			gracePeriod = time.Duration(minutes) * time.Minute
+// keepHandedOverTasks leaves the tasks of a server handover running and
+// returns the other stale tasks. Handed over tasks that show no progress by
+// the end of the reconnect grace period are retried.
+func (s *JobCleanupService) keepHandedOverTasks(ctx context.Context, staleTasks []models.JobTask, handover *models.ServerHandover) []models.JobTask {
+	handedOver := make(map[uuid.UUID]int, len(handover.Tasks))
+	for _, task := range handover.Tasks {
+		handedOver[task.TaskID] = task.AgentID
+	}
+
+	remaining := make([]models.JobTask, 0, len(staleTasks))
+	var kept []*models.JobTask
+	for i := range staleTasks {
+		task := &staleTasks[i]
+		if agentID, ok := handedOver[task.ID]; ok && task.AgentID != nil && *task.AgentID == agentID {
+			kept = append(kept, task)
+			continue
+		}
+		remaining = append(remaining, *task)
+	}
+
+	if len(kept) > 0 {
+		debug.Info("Keeping %d handed over tasks running while their agents reconnect", len(kept))
+		go s.awaitHandedOverTasks(ctx, kept, handover.ReconnectAfter(), time.Now())
+	}
+	return remaining
+}
+
+// awaitHandedOverTasks retries handed over tasks without progress since the
+// server started, once their agents had time to reconnect: the agent did not
+// come back, or came back without the task.
+func (s *JobCleanupService) awaitHandedOverTasks(ctx context.Context, tasks []*models.JobTask, reconnectAfter time.Duration, startedAt time.Time) {
+	time.Sleep(reconnectAfter + reconnectGracePeriod(ctx, s.systemSettingsRepo))
+
+	var lost []*models.JobTask
+	for _, task := range tasks {
+		current, err := s.jobTaskRepo.GetByID(ctx, task.ID)
+		if err != nil {
+			debug.Error("Failed to get handed over task %s: %v", task.ID, err)
+			continue
+		}
+		if current.Status != models.JobTaskStatusRunning && current.Status != models.JobTaskStatusAssigned {
+			continue
+		}
+		if current.LastCheckpoint != nil && current.LastCheckpoint.After(startedAt) {
+			continue
 		}
+
+		debug.Info("Handed over task %s made no progress since the restart, marking as reconnect_pending", current.ID)
+		if err := s.jobTaskRepo.UpdateStatus(ctx, current.ID, models.JobTaskStatusReconnectPending); err != nil {
+			debug.Error("Failed to update task %s to reconnect_pending: %v", current.ID, err)
+			continue
+		}
+		lost = append(lost, current)
+	}
+
+	if len(lost) > 0 {
+		s.expireReconnectPendingTasks(ctx, lost)
 	}
+}
+
+// handleGracePeriodExpiration handles the expiration of the grace period for reconnect_pending tasks
+func (s *JobCleanupService) handleGracePeriodExpiration(ctx context.Context, tasks []*models.JobTask) {
+	gracePeriod := reconnectGracePeriod(ctx, s.systemSettingsRepo)
 	
 	debug.Info("Starting grace period timer for %d tasks - duration: %v", len(tasks), gracePeriod)
 	
 	time.Sleep(gracePeriod)
 	
 	debug.Info("Grace period expired - checking for tasks that didn't reconnect")
-	
+	s.expireReconnectPendingTasks(ctx, tasks)
+}
+
+// expireReconnectPendingTasks retries, or fails after too many retries, the
+// tasks that are still reconnect_pending
+func (s *JobCleanupService) expireReconnectPendingTasks(ctx context.Context, tasks []*models.JobTask) {
 	// Get max retry attempts from settings
 	maxRetries := 3
 	retrySetting, err := s.systemSettingsRepo.GetSetting(ctx, "max_chunk_retry_attempts")
@@ -234,31 +300,16 @@ func (s *JobCleanupService) MonitorStaleTasksPeriodically(ctx context.Context, i
 
 // checkForStaleTasks checks for tasks that have been assigned/running too long without updates
 func (s *JobCleanupService) checkForStaleTasks(ctx context.Context) {
-	// Get task timeout setting (default 5 minutes for agent heartbeat)
-	taskTimeout := 5 * time.Minute
-	timeoutSetting, err := s.systemSettingsRepo.GetSetting(ctx, "task_heartbeat_timeout_minutes")
-	if err == nil && timeoutSetting.Value != nil {
-		if minutes, err := time.ParseDuration(*timeoutSetting.Value + "m"); err == nil {
-			taskTimeout = minutes
-		}
-	} else {
-		// Fall back to task_timeout_minutes if heartbeat setting doesn't exist
-		timeoutSetting, err = s.systemSettingsRepo.GetSetting(ctx, "task_timeout_minutes")
-		if err == nil && timeoutSetting.Value != nil {
-			if minutes, err := time.ParseDuration(*timeoutSetting.Value + "m"); err == nil {
-				taskTimeout = minutes
-			}
-		}
-	}
+	staleness := loadTaskStaleness(ctx, s.systemSettingsRepo)
 
 	// FIRST: Always check for orphaned running jobs (jobs with no active tasks at all)
 	// This must run regardless of whether there are stale tasks
 	s.checkForOrphanedRunningJobs(ctx)
 
-	// Find tasks that haven't been updated in the timeout period
-	cutoffTime := time.Now().Add(-taskTimeout)
-
-	staleTasks, err := s.jobTaskRepo.GetTasksNotUpdatedSince(ctx, cutoffTime)
+	// Find tasks that haven't been updated within the shortest timeout, then
+	// apply each task's own timeout
+	now := time.Now()
+	candidates, err := s.jobTaskRepo.GetTasksNotUpdatedSince(ctx, now.Add(-staleness.shortest()))
 	if err != nil {
 		debug.Log("Failed to check for stale tasks", map[string]interface{}{
 			"error": err.Error(),
@@ -266,16 +317,22 @@ func (s *JobCleanupService) checkForStaleTasks(ctx context.Context) {
 		return
 	}
 
+	if len(candidates) == 0 {
+		return
+	}
+
+	staleTasks, timeouts := s.filterStaleTasks(ctx, candidates, staleness, now)
 	if len(staleTasks) == 0 {
 		return
 	}
 
 	debug.Log("Found stale tasks during periodic check", map[string]interface{}{
-		"count":   len(staleTasks),
-		"timeout": taskTimeout,
+		"count":      len(staleTasks),
+		"candidates": len(candidates),
 	})
 
 	for _, task := range staleTasks {
+		taskTimeout := timeouts[task.ID]
 		// Check if task has exceeded retry limit (3 attempts)
 		if task.RetryCount >= 3 {
 			// Mark task as permanently failed
@@ -337,6 +394,48 @@ func (s *JobCleanupService) checkForStaleTasks(ctx context.Context) {
 	}
 }
 
+// filterStaleTasks returns the candidates that went without progress for
+// longer than their timeout, along with that timeout. Tasks of slow hash types
+// get the slow timeout, and agents that recently had a task go quiet while
+// still sending heartbeats get theirs doubled per occurrence.
+func (s *JobCleanupService) filterStaleTasks(ctx context.Context, candidates []models.JobTask, staleness taskStaleness, now time.Time) ([]models.JobTask, map[uuid.UUID]time.Duration) {
+	jobIDs := make([]uuid.UUID, 0, len(candidates))
+	for _, task := range candidates {
+		jobIDs = append(jobIDs, task.JobExecutionID)
+	}
+	slowJobs, err := s.jobTaskRepo.GetSlowHashJobExecutionIDs(ctx, jobIDs)
+	if err != nil {
+		debug.Warning("Failed to look up slow hash jobs, using the fast hash timeout: %v", err)
+	}
+	agentTimeout := AgentHeartbeatTimeout(ctx, s.systemSettingsRepo)
+
+	var staleTasks []models.JobTask
+	timeouts := make(map[uuid.UUID]time.Duration)
+	for _, task := range candidates {
+		late := 0
+		if task.AgentID != nil {
+			late = s.lateReports.count(*task.AgentID, now)
+		}
+		timeout := staleness.timeoutFor(slowJobs[task.JobExecutionID], late)
+		if now.Sub(lastActivity(task)) < timeout {
+			continue
+		}
+
+		// An agent that is still connected is slow to report rather than gone
+		if task.AgentID != nil {
+			agent, err := s.agentRepo.GetByID(ctx, *task.AgentID)
+			if err == nil && now.Sub(agent.LastHeartbeat) < agentTimeout {
+				s.lateReports.record(*task.AgentID, now)
+				debug.Info("Agent %d is connected but task %s went %v without progress, extending its grace", *task.AgentID, task.ID, timeout)
+			}
+		}
+
+		staleTasks = append(staleTasks, task)
+		timeouts[task.ID] = timeout
+	}
+	return staleTasks, timeouts
+}
+
 // checkJobForPendingTransition checks if a job should be transitioned to pending
 func (s *JobCleanupService) checkJobForPendingTransition(ctx context.Context, jobID uuid.UUID) {
 	// Check if this job has any running, assigned, or pending tasks
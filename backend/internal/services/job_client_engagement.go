@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// ErrClientEngagementClosed is returned when a job is created against a
+// hashlist of a client whose engagement is closed
+var ErrClientEngagementClosed = errors.New("client engagement is closed")
+
+// CheckClientEngagement returns an error wrapping ErrClientEngagementClosed
+// when the hashlist belongs to a client whose engagement is closed
+func (s *JobExecutionService) CheckClientEngagement(ctx context.Context, hashlist *models.HashList) error {
+	if s.clientRepo == nil || hashlist.ClientID == uuid.Nil {
+		return nil
+	}
+	closed, err := s.clientRepo.IsEngagementClosed(ctx, hashlist.ClientID)
+	if err != nil {
+		return fmt.Errorf("failed to check client engagement: %w", err)
+	}
+	if closed {
+		return fmt.Errorf("%w: reopen the engagement of the client of hashlist %q to create jobs", ErrClientEngagementClosed, hashlist.Name)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// concurrencyQueuedPrefix starts the message recorded on a job held back by a
+// concurrency cap, so it can be told apart from real errors and cleared again
+const concurrencyQueuedPrefix = "Queued: "
+
+// jobConcurrencyCaps are the maximum numbers of running jobs per user and per
+// client, 0 means unlimited
+type jobConcurrencyCaps struct {
+	perUser   int
+	perClient int
+}
+
+// enabled reports whether any cap is set
+func (c jobConcurrencyCaps) enabled() bool {
+	return c.perUser > 0 || c.perClient > 0
+}
+
+// jobConcurrencyCaps reads the concurrency caps from the system settings.
+// Missing or invalid settings leave the cap off.
+func (s *JobExecutionService) jobConcurrencyCaps(ctx context.Context) jobConcurrencyCaps {
+	readCap := func(key string) int {
+		setting, err := s.systemSettingsRepo.GetSetting(ctx, key)
+		if err != nil || setting.Value == nil {
+			return 0
+		}
+		parsed, err := strconv.Atoi(*setting.Value)
+		if err != nil || parsed < 0 {
+			return 0
+		}
+		return parsed
+	}
+	return jobConcurrencyCaps{
+		perUser:   readCap("max_running_jobs_per_user"),
+		perClient: readCap("max_running_jobs_per_client"),
+	}
+}
+
+// concurrencyCapReason returns why starting the job would exceed a cap, or ""
+// if it may start. clientID is the client of the job's hashlist, uuid.Nil if
+// it has none.
+func concurrencyCapReason(job *models.JobExecution, clientID uuid.UUID, counts *models.RunningJobCounts, caps jobConcurrencyCaps) string {
+	if caps.perUser > 0 && job.CreatedBy != nil {
+		if running := counts.ByUser[*job.CreatedBy]; running >= caps.perUser {
+			return fmt.Sprintf("%sthe job's owner already has %d running jobs, the limit per user is %d",
+				concurrencyQueuedPrefix, running, caps.perUser)
+		}
+	}
+	if caps.perClient > 0 && clientID != uuid.Nil {
+		if running := counts.ByClient[clientID]; running >= caps.perClient {
+			return fmt.Sprintf("%sthe hashlist's client already has %d running jobs, the limit per client is %d",
+				concurrencyQueuedPrefix, running, caps.perClient)
+		}
+	}
+	return ""
+}
+
+// admitJobConcurrency reports whether the job may get work without exceeding
+// the per user and per client caps. The caps only hold back jobs that have not
+// started yet, running jobs keep their slot. A held back job stays queued and
+// the reason is recorded on it, it is cleared once the job is admitted.
+func (s *JobExecutionService) admitJobConcurrency(ctx context.Context, job *models.JobExecution, caps jobConcurrencyCaps, counts *models.RunningJobCounts) bool {
+	if job.Status != models.JobExecutionStatusPending {
+		return true
+	}
+
+	clientID := uuid.Nil
+	if caps.perClient > 0 {
+		hashlist, err := s.hashlistRepo.GetByID(ctx, job.HashlistID)
+		if err != nil {
+			debug.Warning("Failed to get hashlist %d for concurrency check: %v", job.HashlistID, err)
+		} else {
+			clientID = hashlist.ClientID
+		}
+	}
+
+	queued := job.ErrorMessage != nil && strings.HasPrefix(*job.ErrorMessage, concurrencyQueuedPrefix)
+	reason := concurrencyCapReason(job, clientID, counts, caps)
+	if reason == "" {
+		if queued {
+			if err := s.jobExecRepo.ClearError(ctx, job.ID); err != nil {
+				debug.Error("Failed to clear queue reason on job %s: %v", job.ID, err)
+			}
+		}
+		return true
+	}
+
+	debug.Log("Job held back by concurrency cap", map[string]interface{}{
+		"job_id": job.ID,
+		"reason": reason,
+	})
+	if !queued || *job.ErrorMessage != reason {
+		if err := s.jobExecRepo.UpdateErrorMessage(ctx, job.ID, reason); err != nil {
+			debug.Error("Failed to record queue reason on job %s: %v", job.ID, err)
+		}
+	}
+	return false
+}
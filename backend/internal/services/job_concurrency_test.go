@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyCapReason(t *testing.T) {
+	userID := uuid.New()
+	clientID := uuid.New()
+	job := &models.JobExecution{CreatedBy: &userID}
+	counts := &models.RunningJobCounts{
+		ByUser:   map[uuid.UUID]int{userID: 2},
+		ByClient: map[uuid.UUID]int{clientID: 3},
+	}
+
+	// Unlimited
+	assert.Empty(t, concurrencyCapReason(job, clientID, counts, jobConcurrencyCaps{}))
+
+	// Under both caps
+	assert.Empty(t, concurrencyCapReason(job, clientID, counts, jobConcurrencyCaps{perUser: 3, perClient: 4}))
+
+	reason := concurrencyCapReason(job, clientID, counts, jobConcurrencyCaps{perUser: 2})
+	assert.Equal(t, "Queued: the job's owner already has 2 running jobs, the limit per user is 2", reason)
+
+	reason = concurrencyCapReason(job, clientID, counts, jobConcurrencyCaps{perClient: 3})
+	assert.Equal(t, "Queued: the hashlist's client already has 3 running jobs, the limit per client is 3", reason)
+
+	// Hashlists without a client and jobs without an owner are not capped
+	assert.Empty(t, concurrencyCapReason(job, uuid.Nil, counts, jobConcurrencyCaps{perClient: 1}))
+	assert.Empty(t, concurrencyCapReason(&models.JobExecution{}, clientID, counts, jobConcurrencyCaps{perUser: 1}))
+}
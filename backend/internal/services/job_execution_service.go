@@ -14,9 +14,12 @@ import (
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/binary"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatargs"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatmask"
 	"github.com/google/uuid"
 )
 
@@ -36,6 +39,12 @@ type JobExecutionService struct {
 	scheduleRepo       *repository.AgentScheduleRepository
 	binaryManager      binary.Manager
 	ruleSplitManager   *RuleSplitManager
+	maintenance        *MaintenanceService
+	chunkVerifications *repository.ChunkVerificationRepository
+	clientRepo         *repository.ClientRepository
+	collections        *WordlistCollectionService
+	rollouts           *BinaryRolloutService
+	systemEvents       *SystemEventService
 
 	// Configuration paths
 	hashcatBinaryPath string
@@ -67,7 +76,26 @@ func NewJobExecutionService(
 
 	// Create rule split manager with temp directory
 	ruleSplitDir := filepath.Join(dataDirectory, "temp", "rule_chunks")
-	ruleSplitManager := NewRuleSplitManager(ruleSplitDir, fileRepo)
+	var ruleChunkRepo *repository.RuleChunkRepository
+	if database != nil {
+		ruleChunkRepo = repository.NewRuleChunkRepository(database)
+	}
+	ruleSplitManager := NewRuleSplitManager(ruleSplitDir, fileRepo, ruleChunkRepo)
+
+	var maintenance *MaintenanceService
+	var chunkVerifications *repository.ChunkVerificationRepository
+	var clientRepo *repository.ClientRepository
+	var collections *WordlistCollectionService
+	var rollouts *BinaryRolloutService
+	var systemEvents *SystemEventService
+	if database != nil {
+		maintenance = NewMaintenanceService(repository.NewMaintenanceRepository(database))
+		chunkVerifications = repository.NewChunkVerificationRepository(database)
+		clientRepo = repository.NewClientRepository(database)
+		collections = NewWordlistCollectionService(repository.NewWordlistCollectionRepository(database))
+		rollouts = NewBinaryRolloutService(repository.NewBinaryRolloutRepository(database), binaryManager, systemSettingsRepo)
+		systemEvents = NewSystemEventService(repository.NewSystemEventRepository(database), systemSettingsRepo)
+	}
 
 	return &JobExecutionService{
 		db:                 database,
@@ -84,6 +112,12 @@ func NewJobExecutionService(
 		scheduleRepo:       scheduleRepo,
 		binaryManager:      binaryManager,
 		ruleSplitManager:   ruleSplitManager,
+		maintenance:        maintenance,
+		chunkVerifications: chunkVerifications,
+		clientRepo:         clientRepo,
+		collections:        collections,
+		rollouts:           rollouts,
+		systemEvents:       systemEvents,
 		hashcatBinaryPath:  hashcatBinaryPath,
 		dataDirectory:      dataDirectory,
 	}
@@ -96,6 +130,7 @@ type CustomJobConfig struct {
 	RuleIDs                   models.IDArray
 	AttackMode                models.AttackMode
 	Mask                      string
+	MaskIncrement             *models.MaskIncrement
 	Priority                  int
 	MaxAgents                 int
 	BinaryVersionID           int
@@ -110,21 +145,78 @@ func (s *JobExecutionService) CreateJobExecution(ctx context.Context, presetJobI
 		"hashlist_id":   hashlistID,
 	})
 
+	if err := s.CheckMaintenance(ctx); err != nil {
+		return nil, err
+	}
+
 	// Get the preset job
+	presetJob, err := s.presetJobRepo.GetByID(ctx, presetJobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preset job: %w", err)
+	}
+	if presetJob.WordlistCollectionID != nil {
+		return nil, fmt.Errorf("preset job %s runs a wordlist collection, its jobs are created by CreateJobExecutions", presetJobID)
+	}
+
+	return s.createJobFromPreset(ctx, presetJob, hashlistID, createdBy, customJobName)
+}
+
+// CreateJobExecutions creates the jobs of a preset job against a hashlist. A
+// preset referencing a wordlist collection is expanded into one job per
+// wordlist of the collection as it is now, named after the wordlist, other
+// presets create a single job. Jobs created before a failure are returned
+// along with the error.
+func (s *JobExecutionService) CreateJobExecutions(ctx context.Context, presetJobID uuid.UUID, hashlistID int64, createdBy *uuid.UUID, customJobName string) ([]*models.JobExecution, error) {
+	if err := s.CheckMaintenance(ctx); err != nil {
+		return nil, err
+	}
+
 	presetJob, err := s.presetJobRepo.GetByID(ctx, presetJobID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get preset job: %w", err)
 	}
 
+	expanded := []models.ExpandedPresetJob{{Preset: presetJob}}
+	if presetJob.WordlistCollectionID != nil {
+		if s.collections == nil {
+			return nil, fmt.Errorf("preset job %s runs a wordlist collection but collections are unavailable", presetJobID)
+		}
+		if expanded, err = s.collections.ExpandPresetJob(ctx, presetJob); err != nil {
+			return nil, err
+		}
+		debug.Info("Expanded wordlist collection %d of preset job %s into %d jobs",
+			*presetJob.WordlistCollectionID, presetJobID, len(expanded))
+	}
+
+	var jobs []*models.JobExecution
+	for _, e := range expanded {
+		job, err := s.createJobFromPreset(ctx, e.Preset, hashlistID, createdBy, e.JobName(customJobName))
+		if err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// createJobFromPreset creates a job execution with its configuration copied
+// from a preset job running a single attack
+func (s *JobExecutionService) createJobFromPreset(ctx context.Context, presetJob *models.PresetJob, hashlistID int64, createdBy *uuid.UUID, customJobName string) (*models.JobExecution, error) {
+	presetJobID := presetJob.ID
+
 	// Get the hashlist
 	hashlist, err := s.hashlistRepo.GetByID(ctx, hashlistID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get hashlist: %w", err)
 	}
+	if err := s.CheckClientEngagement(ctx, hashlist); err != nil {
+		return nil, err
+	}
 
 	// Use pre-calculated keyspace from preset job if available
 	var totalKeyspace *int64
-	if presetJob.Keyspace != nil && *presetJob.Keyspace > 0 {
+	maskIncrement := presetJob.MaskIncrement
+	if presetJob.Keyspace != nil && *presetJob.Keyspace > 0 && (maskIncrement == nil || len(maskIncrement.Keyspaces) > 0) {
 		totalKeyspace = presetJob.Keyspace
 		debug.Log("Using pre-calculated keyspace from preset job", map[string]interface{}{
 			"preset_job_id": presetJobID,
@@ -133,7 +225,7 @@ func (s *JobExecutionService) CreateJobExecution(ctx context.Context, presetJobI
 	} else {
 		// Fallback to calculating keyspace if not pre-calculated
 		debug.Warning("Preset job has no pre-calculated keyspace, calculating now")
-		totalKeyspace, err = s.calculateKeyspace(ctx, presetJob, hashlist)
+		totalKeyspace, maskIncrement, err = s.calculateMaskKeyspace(ctx, presetJob, hashlist)
 		if err != nil {
 			debug.Error("Failed to calculate keyspace: %v", err)
 			return nil, fmt.Errorf("keyspace calculation is required for job execution: %w", err)
@@ -162,8 +254,11 @@ func (s *JobExecutionService) CreateJobExecution(ctx context.Context, presetJobI
 		AllowHighPriorityOverride: presetJob.AllowHighPriorityOverride,
 		BinaryVersionID:           presetJob.BinaryVersionID,
 		Mask:                      presetJob.Mask,
+		MaskIncrement:             maskIncrement,
 		AdditionalArgs:            presetJob.AdditionalArgs,
 	}
+	jobExecution.AttackFingerprint = models.ComputeAttackFingerprint(jobExecution.AttackMode, jobExecution.HashType,
+		jobExecution.WordlistIDs, jobExecution.RuleIDs, jobExecution.MaskIncrement.FingerprintMask(jobExecution.Mask), jobExecution.AdditionalArgs, jobExecution.BinaryVersionID)
 
 	err = s.jobExecRepo.Create(ctx, jobExecution)
 	if err != nil {
@@ -208,11 +303,18 @@ func (s *JobExecutionService) CreateCustomJobExecution(ctx context.Context, conf
 		"attack_mode": config.AttackMode,
 	})
 
+	if err := s.CheckMaintenance(ctx); err != nil {
+		return nil, err
+	}
+
 	// Get the hashlist
 	hashlist, err := s.hashlistRepo.GetByID(ctx, hashlistID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get hashlist: %w", err)
 	}
+	if err := s.CheckClientEngagement(ctx, hashlist); err != nil {
+		return nil, err
+	}
 
 	// Get chunk size from config or system settings
 	chunkSize := config.ChunkSizeSeconds
@@ -240,6 +342,7 @@ func (s *JobExecutionService) CreateCustomJobExecution(ctx context.Context, conf
 		HashType:                  hashlist.HashTypeID,
 		BinaryVersionID:           config.BinaryVersionID,
 		Mask:                      config.Mask,
+		MaskIncrement:             config.MaskIncrement,
 		Priority:                  config.Priority,
 		MaxAgents:                 config.MaxAgents,
 		AllowHighPriorityOverride: config.AllowHighPriorityOverride,
@@ -248,7 +351,7 @@ func (s *JobExecutionService) CreateCustomJobExecution(ctx context.Context, conf
 	}
 
 	// Use the same keyspace calculation as preset jobs
-	totalKeyspace, err := s.calculateKeyspace(ctx, tempPreset, hashlist)
+	totalKeyspace, maskIncrement, err := s.calculateMaskKeyspace(ctx, tempPreset, hashlist)
 	if err != nil {
 		debug.Error("Failed to calculate keyspace for custom job: %v", err)
 		return nil, fmt.Errorf("keyspace calculation is required for job execution: %w", err)
@@ -276,8 +379,11 @@ func (s *JobExecutionService) CreateCustomJobExecution(ctx context.Context, conf
 		AllowHighPriorityOverride: config.AllowHighPriorityOverride,
 		BinaryVersionID:           config.BinaryVersionID,
 		Mask:                      config.Mask,
+		MaskIncrement:             maskIncrement,
 		AdditionalArgs:            nil,
 	}
+	jobExecution.AttackFingerprint = models.ComputeAttackFingerprint(jobExecution.AttackMode, jobExecution.HashType,
+		jobExecution.WordlistIDs, jobExecution.RuleIDs, jobExecution.MaskIncrement.FingerprintMask(jobExecution.Mask), jobExecution.AdditionalArgs, jobExecution.BinaryVersionID)
 
 	err = s.jobExecRepo.Create(ctx, jobExecution)
 	if err != nil {
@@ -314,6 +420,44 @@ func (s *JobExecutionService) CreateCustomJobExecution(ctx context.Context, conf
 	return jobExecution, nil
 }
 
+// calculateMaskKeyspace calculates the total keyspace of a job. Hashcat has no
+// combined keyspace for --increment, so a mask with increment bounds is
+// calculated once per length and the keyspaces are summed. The returned
+// increment holds the keyspace of each length.
+func (s *JobExecutionService) calculateMaskKeyspace(ctx context.Context, presetJob *models.PresetJob, hashlist *models.HashList) (*int64, *models.MaskIncrement, error) {
+	if presetJob.MaskIncrement == nil {
+		keyspace, err := s.calculateKeyspace(ctx, presetJob, hashlist)
+		return keyspace, nil, err
+	}
+
+	increment := models.MaskIncrement{Min: presetJob.MaskIncrement.Min, Max: presetJob.MaskIncrement.Max}
+	lines, err := increment.Resolve(presetJob.Mask)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid mask increment: %w", err)
+	}
+
+	for _, line := range lines {
+		lengthJob := *presetJob
+		lengthJob.Mask = line.String()
+		lengthJob.MaskIncrement = nil
+		keyspace, err := s.calculateKeyspace(ctx, &lengthJob, hashlist)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to calculate keyspace of mask %s: %w", lengthJob.Mask, err)
+		}
+		increment.Keyspaces = append(increment.Keyspaces, *keyspace)
+	}
+
+	total := increment.Total()
+	debug.Log("Calculated increment mask keyspace", map[string]interface{}{
+		"mask":      presetJob.Mask,
+		"min":       increment.Min,
+		"max":       increment.Max,
+		"keyspaces": increment.Keyspaces,
+		"total":     total,
+	})
+	return &total, &increment, nil
+}
+
 // calculateKeyspace calculates the total keyspace for a job using hashcat --keyspace
 func (s *JobExecutionService) calculateKeyspace(ctx context.Context, presetJob *models.PresetJob, hashlist *models.HashList) (*int64, error) {
 	debug.Log("Starting keyspace calculation for job execution", map[string]interface{}{
@@ -389,7 +533,7 @@ func (s *JobExecutionService) calculateKeyspace(ctx context.Context, presetJob *
 
 	case models.AttackModeBruteForce: // Mask attack
 		if presetJob.Mask != "" {
-			args = append(args, presetJob.Mask)
+			args = append(args, hashcatmask.LineArgs(presetJob.Mask)...)
 		}
 
 	case models.AttackModeHybridWordlistMask: // Hybrid Wordlist + Mask
@@ -398,7 +542,8 @@ func (s *JobExecutionService) calculateKeyspace(ctx context.Context, presetJob *
 			if err != nil {
 				return nil, fmt.Errorf("failed to resolve wordlist path: %w", err)
 			}
-			args = append(args, wordlistPath, presetJob.Mask)
+			args = append(args, wordlistPath)
+			args = append(args, hashcatmask.LineArgs(presetJob.Mask)...)
 		}
 
 	case models.AttackModeHybridMaskWordlist: // Hybrid Mask + Wordlist
@@ -407,7 +552,8 @@ func (s *JobExecutionService) calculateKeyspace(ctx context.Context, presetJob *
 			if err != nil {
 				return nil, fmt.Errorf("failed to resolve wordlist path: %w", err)
 			}
-			args = append(args, presetJob.Mask, wordlistPath)
+			args = append(args, hashcatmask.LineArgs(presetJob.Mask)...)
+			args = append(args, wordlistPath)
 		}
 
 	default:
@@ -423,6 +569,14 @@ func (s *JobExecutionService) calculateKeyspace(ctx context.Context, presetJob *
 		"attack_mode": presetJob.AttackMode,
 	})
 
+	// Wait for a hashcat slot first, so the timeout only counts the run itself
+	release, err := acquireHashcatExec(ctx, s.systemSettingsRepo, HashcatExecKeyspace,
+		fmt.Sprintf("preset job %s on hashlist %d", presetJob.ID, hashlist.ID))
+	if err != nil {
+		return nil, fmt.Errorf("hashcat keyspace calculation not started: %w", err)
+	}
+	defer release()
+
 	// Execute hashcat command with timeout
 	// Increase timeout to 2 minutes to allow for large wordlist processing
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
@@ -761,11 +915,48 @@ func (s *JobExecutionService) calculateEffectiveKeyspace(ctx context.Context, jo
 			job.EffectiveKeyspace = &baseKeyspace
 		}
 
-	default: // Attacks 3, 6, 7 - hashcat calculates correctly
+	case models.AttackModeHybridWordlistMask, models.AttackModeHybridMaskWordlist:
+		job.BaseKeyspace = &baseKeyspace
+		job.MultiplicationFactor = 1
+		job.EffectiveKeyspace = &baseKeyspace
+
+		// hashcat's keyspace counts one side only, every unit of it is tried
+		// with each candidate of the other side
+		hybrid, err := s.calculateHybridKeyspace(ctx, presetJob)
+		if err != nil {
+			debug.Warning("Failed to break down hybrid keyspace of job %s, using hashcat's keyspace: %v", job.ID, err)
+			break
+		}
+		if err := s.jobExecRepo.SetHybridKeyspace(ctx, job.ID, hybrid.WordlistKeyspace(), hybrid.MaskKeyspace()); err != nil {
+			debug.Warning("Failed to store hybrid keyspace of job %s: %v", job.ID, err)
+		}
+		estimatedEffective := baseKeyspace * hybrid.CandidatesPerUnit(baseKeyspace)
+		job.EffectiveKeyspace = &estimatedEffective
+
+		debug.Log("Hybrid attack - using estimated effective keyspace", map[string]interface{}{
+			"attack_mode":         attackMode,
+			"keyspace":            baseKeyspace,
+			"left":                hybrid.Left,
+			"right":               hybrid.Right,
+			"estimated_effective": estimatedEffective,
+		})
+
+	default: // Attack 3 - hashcat calculates correctly
 		job.BaseKeyspace = &baseKeyspace
 		job.MultiplicationFactor = 1
 		job.EffectiveKeyspace = &baseKeyspace
 
+		// A benchmark only sees one mask length, so the candidates of an
+		// --increment mask are counted here and kept as the accurate total
+		if job.MaskIncrement != nil {
+			candidates, err := incrementCandidates(job.Mask, *job.MaskIncrement)
+			if err != nil {
+				return err
+			}
+			job.EffectiveKeyspace = &candidates
+			job.IsAccurateKeyspace = true
+		}
+
 		debug.Log("Standard attack mode", map[string]interface{}{
 			"attack_mode": attackMode,
 			"keyspace":    baseKeyspace,
@@ -776,6 +967,24 @@ func (s *JobExecutionService) calculateEffectiveKeyspace(ctx context.Context, jo
 	return s.jobExecRepo.UpdateKeyspaceInfo(ctx, job)
 }
 
+// incrementCandidates returns the number of candidates hashcat tries for the
+// mask across all of its increment lengths
+func incrementCandidates(mask string, increment models.MaskIncrement) (int64, error) {
+	lines, err := increment.Resolve(mask)
+	if err != nil {
+		return 0, fmt.Errorf("invalid mask increment: %w", err)
+	}
+	var total int64
+	for _, line := range lines {
+		analysis, err := hashcatmask.Analyze(line, hashcatmask.Bounds{})
+		if err != nil {
+			return 0, err
+		}
+		total += analysis.Keyspace
+	}
+	return total, nil
+}
+
 // determineRuleSplitting determines if a job should use rule splitting
 func (s *JobExecutionService) determineRuleSplitting(ctx context.Context, job *models.JobExecution, presetJob *models.PresetJob) error {
 	// Check if rule splitting is enabled
@@ -918,6 +1127,7 @@ func (s *JobExecutionService) GetNextJobWithWork(ctx context.Context) (*models.J
 	debug.Log("Retrieved jobs with pending work", map[string]interface{}{
 		"count": len(jobsWithWork),
 	})
+	schedulingRecorderFrom(ctx).considerJobs(jobsWithWork)
 
 	if len(jobsWithWork) == 0 {
 		return nil, nil // No jobs with available work
@@ -1064,11 +1274,11 @@ func (s *JobExecutionService) GetAvailableAgents(ctx context.Context) ([]models.
 			}
 
 			if hasEnabledDevices {
-				// Check if scheduling is enabled for this agent
-				if agent.SchedulingEnabled {
+				// Check if scheduling is enabled for this agent. Shared workstations
+				// always follow their schedule so they never run during business hours.
+				if agent.WorkloadClass == models.WorkloadClassShared || agent.SchedulingEnabled {
 					// Check if scheduling system is enabled globally
-					schedulingSetting, err := s.systemSettingsRepo.GetSetting(ctx, "agent_scheduling_enabled")
-					if err == nil && schedulingSetting.Value != nil && *schedulingSetting.Value == "true" {
+					if agent.WorkloadClass == models.WorkloadClassShared || s.agentSchedulingEnabled(ctx) {
 						// Check if agent is scheduled for current UTC time
 						isScheduled, err := s.scheduleRepo.IsAgentScheduledNow(ctx, agent.ID)
 						if err != nil {
@@ -1086,6 +1296,19 @@ func (s *JobExecutionService) GetAvailableAgents(ctx context.Context) ([]models.
 						}
 					}
 				}
+
+				// Agents that have used today's power budget wait until midnight UTC
+				exhausted, err := s.isPowerBudgetExhausted(ctx, agent.ID)
+				if err != nil {
+					debug.Log("Failed to check agent power budget", map[string]interface{}{
+						"agent_id": agent.ID,
+						"error":    err.Error(),
+					})
+					continue
+				}
+				if exhausted {
+					continue
+				}
 				
 				availableAgents = append(availableAgents, agent)
 			} else {
@@ -1211,15 +1434,14 @@ func (s *JobExecutionService) CompleteJobExecution(ctx context.Context, jobExecu
 	// Get the job execution to find the user who created it
 	jobExec, err := s.jobExecRepo.GetByID(ctx, jobExecutionID)
 	if err != nil {
-		debug.Error("Failed to get job execution for notification: %v", err)
+		debug.Error("Failed to get job execution for completion event: %v", err)
+		// Don't fail the completion due to notification errors
+	} else if pubErr := events.Publish(ctx, s.db, events.JobCompleted, events.JobCompletedPayload{
+		JobExecutionID: jobExecutionID,
+		CreatedBy:      jobExec.CreatedBy,
+	}); pubErr != nil {
+		debug.Error("Failed to publish job completion event: %v", pubErr)
 		// Don't fail the completion due to notification errors
-	} else if jobExec.CreatedBy != nil {
-		// Send completion email notification
-		notificationService := NewNotificationService(s.db.DB)
-		if notifErr := notificationService.SendJobCompletionEmail(ctx, jobExecutionID, *jobExec.CreatedBy); notifErr != nil {
-			debug.Error("Failed to send job completion email: %v", notifErr)
-			// Don't fail the completion due to email errors
-		}
 	}
 
 	// Clean up resources for completed job
@@ -1263,26 +1485,31 @@ func (s *JobExecutionService) UpdateTaskProgress(ctx context.Context, taskID uui
 		return fmt.Errorf("failed to update task progress: %w", err)
 	}
 
+	return s.RefreshJobProgress(ctx, task.JobExecutionID)
+}
+
+// RefreshJobProgress recalculates a job's processed keyspace and overall progress from its tasks
+func (s *JobExecutionService) RefreshJobProgress(ctx context.Context, jobExecutionID uuid.UUID) error {
 	// Calculate total job progress
-	totalProgress, err := s.calculateTotalJobProgress(ctx, task.JobExecutionID)
+	totalProgress, err := s.calculateTotalJobProgress(ctx, jobExecutionID)
 	if err != nil {
 		return fmt.Errorf("failed to calculate total job progress: %w", err)
 	}
 
 	// Calculate overall progress percentage
-	overallPercent, err := s.calculateOverallProgressPercent(ctx, task.JobExecutionID)
+	overallPercent, err := s.calculateOverallProgressPercent(ctx, jobExecutionID)
 	if err != nil {
 		return fmt.Errorf("failed to calculate overall progress percent: %w", err)
 	}
 
 	// Update job execution progress
-	err = s.UpdateJobProgress(ctx, task.JobExecutionID, totalProgress)
+	err = s.UpdateJobProgress(ctx, jobExecutionID, totalProgress)
 	if err != nil {
 		return fmt.Errorf("failed to update job progress: %w", err)
 	}
 
 	// Update overall progress percentage
-	err = s.UpdateJobProgressPercent(ctx, task.JobExecutionID, overallPercent)
+	err = s.UpdateJobProgressPercent(ctx, jobExecutionID, overallPercent)
 	if err != nil {
 		return fmt.Errorf("failed to update job progress percent: %w", err)
 	}
@@ -1301,6 +1528,7 @@ func (s *JobExecutionService) calculateTotalJobProgress(ctx context.Context, job
 	if err != nil {
 		return 0, fmt.Errorf("failed to get tasks: %w", err)
 	}
+	tasks = progressTasks(tasks) // Count each speculatively re-dispatched chunk once
 
 	var totalProgress int64
 
@@ -1363,6 +1591,7 @@ func (s *JobExecutionService) calculateOverallProgressPercent(ctx context.Contex
 	if err != nil {
 		return 0, fmt.Errorf("failed to get tasks: %w", err)
 	}
+	tasks = progressTasks(tasks) // Count each speculatively re-dispatched chunk once
 
 	var overallPercent float64
 
@@ -2008,26 +2237,51 @@ func (s *JobExecutionService) createJobTasksWithRuleSplitting(ctx context.Contex
 	return nil
 }
 
-// buildAttackCommand builds the hashcat attack command from a job execution
+// JobHashcatArgs returns the job's hashcat parameters as argv: the default
+// arguments of the rules matching the job, with those of its preset job and
+// then the extra parameters set by an administrator merged over them. Stored
+// parameters that fail validation are dropped.
+func (s *JobExecutionService) JobHashcatArgs(ctx context.Context, job *models.JobExecution) []string {
+	args := s.defaultHashcatArgs(ctx, job)
+	if job.AdditionalArgs != nil {
+		additional, err := hashcatargs.Split(*job.AdditionalArgs)
+		if err != nil {
+			debug.Warning("Ignoring additional arguments of job %s: %v", job.ID, err)
+		}
+		args = hashcatargs.Merge(args, additional)
+	}
+	if job.ExtraParameters != nil {
+		extra, err := hashcatargs.Split(*job.ExtraParameters)
+		if err != nil {
+			debug.Warning("Ignoring extra parameters of job %s: %v", job.ID, err)
+		}
+		args = hashcatargs.Merge(args, extra)
+	}
+	return args
+}
+
+// buildAttackCommand builds the argv of the hashcat attack command of a job
+// execution, starting with the hashcat binary. The argv is never run through
+// a shell; hashcatargs.Quote renders it for display.
 // Job executions are self-contained and no longer require preset lookups
 // The presetJob parameter is deprecated and should always be nil
-func (s *JobExecutionService) buildAttackCommand(ctx context.Context, presetJob *models.PresetJob, job *models.JobExecution) (string, error) {
+func (s *JobExecutionService) buildAttackCommand(ctx context.Context, presetJob *models.PresetJob, job *models.JobExecution) ([]string, error) {
 	// Use binary version ID from job (job_executions are self-contained)
 	if job.BinaryVersionID == 0 {
-		return "", fmt.Errorf("no binary version ID available in job execution")
+		return nil, fmt.Errorf("no binary version ID available in job execution")
 	}
 	binaryVersionID := int64(job.BinaryVersionID)
 
 	// Get the hashcat binary path
 	hashcatPath, err := s.binaryManager.GetLocalBinaryPath(ctx, binaryVersionID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get hashcat binary path: %w", err)
+		return nil, fmt.Errorf("failed to get hashcat binary path: %w", err)
 	}
 
 	// Get the hashlist path
 	hashlist, err := s.hashlistRepo.GetByID(ctx, job.HashlistID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get hashlist: %w", err)
+		return nil, fmt.Errorf("failed to get hashlist: %w", err)
 	}
 	hashlistPath := filepath.Join(s.dataDirectory, "hashlists", hashlist.FilePath)
 
@@ -2060,7 +2314,7 @@ func (s *JobExecutionService) buildAttackCommand(ctx context.Context, presetJob
 		for _, wordlistIDStr := range wordlistIDs {
 			wordlistPath, err := s.resolveWordlistPath(ctx, wordlistIDStr)
 			if err != nil {
-				return "", fmt.Errorf("failed to resolve wordlist path: %w", err)
+				return nil, fmt.Errorf("failed to resolve wordlist path: %w", err)
 			}
 			args = append(args, wordlistPath)
 		}
@@ -2068,7 +2322,7 @@ func (s *JobExecutionService) buildAttackCommand(ctx context.Context, presetJob
 		for _, ruleIDStr := range ruleIDs {
 			rulePath, err := s.resolveRulePath(ctx, ruleIDStr)
 			if err != nil {
-				return "", fmt.Errorf("failed to resolve rule path: %w", err)
+				return nil, fmt.Errorf("failed to resolve rule path: %w", err)
 			}
 			args = append(args, "-r", rulePath)
 		}
@@ -2078,11 +2332,11 @@ func (s *JobExecutionService) buildAttackCommand(ctx context.Context, presetJob
 		if len(wordlistIDs) >= 2 {
 			wordlist1Path, err := s.resolveWordlistPath(ctx, wordlistIDs[0])
 			if err != nil {
-				return "", fmt.Errorf("failed to resolve wordlist1 path: %w", err)
+				return nil, fmt.Errorf("failed to resolve wordlist1 path: %w", err)
 			}
 			wordlist2Path, err := s.resolveWordlistPath(ctx, wordlistIDs[1])
 			if err != nil {
-				return "", fmt.Errorf("failed to resolve wordlist2 path: %w", err)
+				return nil, fmt.Errorf("failed to resolve wordlist2 path: %w", err)
 			}
 			args = append(args, wordlist1Path, wordlist2Path)
 		}
@@ -2090,7 +2344,7 @@ func (s *JobExecutionService) buildAttackCommand(ctx context.Context, presetJob
 	case models.AttackModeBruteForce:
 		// Add mask from job (job_executions are self-contained)
 		if job.Mask != "" {
-			args = append(args, job.Mask)
+			args = append(args, hashcatmask.LineArgs(job.Mask)...)
 		}
 
 	case models.AttackModeHybridWordlistMask:
@@ -2099,9 +2353,10 @@ func (s *JobExecutionService) buildAttackCommand(ctx context.Context, presetJob
 		if len(wordlistIDs) > 0 && mask != "" {
 			wordlistPath, err := s.resolveWordlistPath(ctx, wordlistIDs[0])
 			if err != nil {
-				return "", fmt.Errorf("failed to resolve wordlist path: %w", err)
+				return nil, fmt.Errorf("failed to resolve wordlist path: %w", err)
 			}
-			args = append(args, wordlistPath, mask)
+			args = append(args, wordlistPath)
+			args = append(args, hashcatmask.LineArgs(mask)...)
 		}
 
 	case models.AttackModeHybridMaskWordlist:
@@ -2110,21 +2365,17 @@ func (s *JobExecutionService) buildAttackCommand(ctx context.Context, presetJob
 		if mask != "" && len(wordlistIDs) > 0 {
 			wordlistPath, err := s.resolveWordlistPath(ctx, wordlistIDs[0])
 			if err != nil {
-				return "", fmt.Errorf("failed to resolve wordlist path: %w", err)
+				return nil, fmt.Errorf("failed to resolve wordlist path: %w", err)
 			}
-			args = append(args, mask, wordlistPath)
+			args = append(args, hashcatmask.LineArgs(mask)...)
+			args = append(args, wordlistPath)
 		}
 	}
 
-	// Add any additional arguments from job (job_executions are self-contained)
-	if job.AdditionalArgs != nil && *job.AdditionalArgs != "" {
-		additionalArgs := strings.Fields(*job.AdditionalArgs)
-		args = append(args, additionalArgs...)
-	}
+	// Add the job's own parameters (job_executions are self-contained)
+	args = append(args, s.JobHashcatArgs(ctx, job)...)
 
-	// Join command
-	fullCmd := hashcatPath + " " + strings.Join(args, " ")
-	return fullCmd, nil
+	return append([]string{hashcatPath}, args...), nil
 }
 
 // cleanupTaskResources cleans up resources associated with a completed or failed task
@@ -2170,6 +2421,7 @@ func (s *JobExecutionService) CleanupJobResources(ctx context.Context, jobID uui
 			// Don't return error - continue with other cleanup
 		}
 	}
+	s.removeRemainingHashes(job)
 
 	// Get all tasks for this job
 	tasks, err := s.jobTaskRepo.GetTasksByJobExecution(ctx, jobID)
@@ -2230,6 +2482,12 @@ func (s *JobExecutionService) HandleTaskCompletion(ctx context.Context, taskID u
 	return nil
 }
 
+// RuleChunkFile returns the registered file of a rule chunk, or
+// repository.ErrNotFound for chunks created before chunks were registered
+func (s *JobExecutionService) RuleChunkFile(ctx context.Context, chunkPath string) (*models.RuleChunkFile, error) {
+	return s.ruleSplitManager.ChunkFile(ctx, chunkPath)
+}
+
 // InitializeRuleSplitting initializes rule splitting for a job
 func (s *JobExecutionService) InitializeRuleSplitting(ctx context.Context, job *models.JobExecution) error {
 	debug.Log("InitializeRuleSplitting called", map[string]interface{}{
@@ -2415,3 +2673,8 @@ func parseIntValueFromString(value string) (int, error) {
 func (s *JobExecutionService) GetPreviousChunksActualKeyspace(ctx context.Context, jobExecutionID uuid.UUID, currentChunkNumber int) (int64, error) {
 	return s.jobTaskRepo.GetPreviousChunksActualKeyspace(ctx, jobExecutionID, currentChunkNumber)
 }
+
+// RecordSystemEvent adds an operational event to the system event log
+func (s *JobExecutionService) RecordSystemEvent(ctx context.Context, event *models.SystemEvent) {
+	s.systemEvents.Record(ctx, event)
+}
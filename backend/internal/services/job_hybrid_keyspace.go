@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatmask"
+)
+
+// calculateHybridKeyspace counts the words of a hybrid attack's wordlist and
+// the candidates of its mask
+func (s *JobExecutionService) calculateHybridKeyspace(ctx context.Context, presetJob *models.PresetJob) (*models.HybridKeyspace, error) {
+	if len(presetJob.WordlistIDs) == 0 || presetJob.Mask == "" {
+		return nil, fmt.Errorf("a hybrid attack needs a wordlist and a mask")
+	}
+
+	wordlistPath, err := s.resolveWordlistPath(ctx, presetJob.WordlistIDs[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve wordlist path: %w", err)
+	}
+	words, err := s.calculateWordlistKeyspace(ctx, wordlistPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count wordlist: %w", err)
+	}
+
+	analysis, err := hashcatmask.Validate(presetJob.Mask)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mask: %w", err)
+	}
+
+	hybrid := models.NewHybridKeyspace(presetJob.AttackMode, words, analysis.Keyspace)
+	if hybrid == nil {
+		return nil, fmt.Errorf("empty or oversized hybrid keyspace: %d words, %d mask candidates", words, analysis.Keyspace)
+	}
+	return hybrid, nil
+}
+
+// hybridCandidatesPerUnit returns how many candidates hashcat tries per unit
+// of a job's keyspace, 1 for anything but a hybrid attack with a recorded
+// breakdown
+func (s *JobExecutionService) hybridCandidatesPerUnit(ctx context.Context, job *models.JobExecution) int64 {
+	if job.TotalKeyspace == nil || (job.AttackMode != models.AttackModeHybridWordlistMask && job.AttackMode != models.AttackModeHybridMaskWordlist) {
+		return 1
+	}
+	hybrid, err := s.jobExecRepo.GetHybridKeyspace(ctx, job.ID)
+	if err != nil {
+		return 1
+	}
+	return hybrid.CandidatesPerUnit(*job.TotalKeyspace)
+}
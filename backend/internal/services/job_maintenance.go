@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// Maintenance returns the maintenance service, or nil without a database
+func (s *JobExecutionService) Maintenance() *MaintenanceService {
+	return s.maintenance
+}
+
+// CheckMaintenance returns an error wrapping ErrMaintenanceMode while
+// maintenance mode is active, refusing job creation
+func (s *JobExecutionService) CheckMaintenance(ctx context.Context) error {
+	if s.maintenance == nil {
+		return nil
+	}
+	return s.maintenance.CheckJobCreation(ctx)
+}
+
+// activeMaintenance returns the open maintenance window, or nil. A failed
+// lookup does not hold up scheduling.
+func (s *JobExecutionService) activeMaintenance(ctx context.Context) *models.MaintenanceWindow {
+	if s.maintenance == nil {
+		return nil
+	}
+	window, err := s.maintenance.Active(ctx)
+	if err != nil {
+		debug.Error("Failed to check maintenance mode: %v", err)
+		return nil
+	}
+	return window
+}
+
+// drainForMaintenance lets running tasks finish during maintenance, and once
+// the window's drain timeout has passed stops them. Stopped tasks go back to
+// pending so their jobs resume after maintenance.
+func (s *JobSchedulingService) drainForMaintenance(ctx context.Context, window *models.MaintenanceWindow) {
+	if !window.ForceStopDue(time.Now()) {
+		return
+	}
+	claimed, err := s.jobExecutionService.maintenance.repo.ClaimForceStop(ctx, window.ID)
+	if err != nil {
+		debug.Error("Failed to claim force stop of maintenance window %d: %v", window.ID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	tasks, err := s.jobExecutionService.jobTaskRepo.GetStaleTasks(ctx)
+	if err != nil {
+		debug.Error("Failed to get running tasks to stop for maintenance: %v", err)
+		return
+	}
+
+	stopped := 0
+	for _, task := range tasks {
+		if task.AgentID == nil {
+			continue
+		}
+		if s.wsIntegration != nil {
+			if err := s.wsIntegration.SendJobStop(ctx, task.ID, "Stopped for maintenance"); err != nil {
+				debug.Error("Failed to send stop command to agent %d for task %s: %v", *task.AgentID, task.ID, err)
+			}
+		}
+		if err := s.jobExecutionService.jobTaskRepo.SetTaskPending(ctx, task.ID); err != nil {
+			debug.Error("Failed to set task %s stopped for maintenance to pending: %v", task.ID, err)
+			continue
+		}
+
+		agent, err := s.agentRepo.GetByID(ctx, *task.AgentID)
+		if err == nil && agent.Metadata != nil {
+			agent.Metadata["busy_status"] = "false"
+			delete(agent.Metadata, "current_task_id")
+			delete(agent.Metadata, "current_job_id")
+			if err := s.agentRepo.Update(ctx, agent); err != nil {
+				debug.Error("Failed to clear agent busy status after maintenance stop: %v", err)
+			}
+		}
+		stopped++
+	}
+
+	if err := s.jobExecutionService.maintenance.repo.SetStoppedTasks(ctx, window.ID, stopped); err != nil {
+		debug.Error("Failed to record tasks stopped for maintenance window %d: %v", window.ID, err)
+	}
+	debug.Warning("Drain timeout of maintenance window %d passed, stopped %d running tasks", window.ID, stopped)
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// ErrNoRemainingHashes is returned when a job would target the remaining
+// hashes of a hashlist that has none left to crack
+var ErrNoRemainingHashes = errors.New("no uncracked hashes remain in the hashlist")
+
+// TargetRemainingHashes makes a job run against the hashes of its hashlist
+// that are still uncracked, like hashcat --left. The hashes are written to a
+// file of the job's own so agents download and load only those, and cracks
+// found later by other jobs do not change it. It returns the number of
+// hashes the job targets.
+func (s *JobExecutionService) TargetRemainingHashes(ctx context.Context, jobID uuid.UUID) (int, error) {
+	job, err := s.jobExecRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return 0, err
+	}
+
+	hashes, err := repository.NewHashRepository(s.db).GetUncrackedHashValuesByHashlistID(ctx, job.HashlistID)
+	if err != nil {
+		return 0, err
+	}
+	if len(hashes) == 0 {
+		return 0, fmt.Errorf("%w: hashlist %d", ErrNoRemainingHashes, job.HashlistID)
+	}
+
+	path := s.remainingHashesPath(job.HashlistID, jobID)
+	if err := writeHashFile(path, hashes); err != nil {
+		return 0, err
+	}
+	if err := s.jobExecRepo.SetRemainingHashCount(ctx, jobID, len(hashes)); err != nil {
+		os.Remove(path)
+		return 0, err
+	}
+
+	debug.Info("Job %s targets the %d remaining hashes of hashlist %d", jobID, len(hashes), job.HashlistID)
+	return len(hashes), nil
+}
+
+// JobHashlistPath returns the path of the hash file a job runs against,
+// relative to the agent's data directory
+func (s *JobExecutionService) JobHashlistPath(ctx context.Context, job *models.JobExecution) string {
+	count, err := s.jobExecRepo.GetRemainingHashCount(ctx, job.ID)
+	if err != nil {
+		// The full hashlist still cracks the remaining hashes, only slower
+		debug.Warning("Failed to get remaining hash count of job %s, using the full hashlist: %v", job.ID, err)
+		count = nil
+	}
+	return "hashlists/" + models.JobHashlistFile(job.HashlistID, job.ID, count)
+}
+
+// removeRemainingHashes deletes a finished job's file of remaining hashes
+func (s *JobExecutionService) removeRemainingHashes(job *models.JobExecution) {
+	if job.RemainingHashCount == nil {
+		return
+	}
+	if err := os.Remove(s.remainingHashesPath(job.HashlistID, job.ID)); err != nil && !os.IsNotExist(err) {
+		debug.Error("Failed to remove remaining hashes of job %s: %v", job.ID, err)
+	}
+}
+
+func (s *JobExecutionService) remainingHashesPath(hashlistID int64, jobID uuid.UUID) string {
+	return filepath.Join(s.dataDirectory, "hashlists", filepath.FromSlash(models.RemainingHashesFile(hashlistID, jobID)))
+}
+
+// writeHashFile writes one hash per line, replacing the file only once it is
+// complete
+func writeHashFile(path string, hashes []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmpPath, err)
+	}
+	defer os.Remove(tmpPath)
+
+	writer := bufio.NewWriter(file)
+	for _, hash := range hashes {
+		if _, err := writer.WriteString(hash + "\n"); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write %s: %w", tmpPath, err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", tmpPath, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", tmpPath, err)
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -8,9 +8,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatargs"
 	"github.com/google/uuid"
 )
 
@@ -29,12 +31,25 @@ type JobSchedulingService struct {
 	agentRepo           *repository.AgentRepository
 	systemSettingsRepo  *repository.SystemSettingsRepository
 	wsIntegration       JobWebSocketIntegration
+	hashlistCompletion  *HashlistCompletionService
+	snapshots           *SchedulingSnapshotStore
+	pendingBenchmarks   *pendingBenchmarks
 
 	// Scheduling state
-	schedulingMutex sync.Mutex
-	isScheduling    bool
+	schedulingMutex  sync.Mutex
+	isScheduling     bool
+	scheduleRequests chan struct{}
+
+	// Last scheduling cycle problem written to the system event log, so a
+	// problem repeating every cycle is recorded once per schedulerEventRepeat
+	lastCycleEvent   string
+	lastCycleEventAt time.Time
 }
 
+// schedulerEventRepeat is how often a scheduling cycle problem that keeps
+// recurring is recorded again
+const schedulerEventRepeat = 15 * time.Minute
+
 // NewJobSchedulingService creates a new job scheduling service
 func NewJobSchedulingService(
 	jobExecutionService *JobExecutionService,
@@ -49,9 +64,35 @@ func NewJobSchedulingService(
 		hashlistSyncService: hashlistSyncService,
 		agentRepo:           agentRepo,
 		systemSettingsRepo:  systemSettingsRepo,
+		scheduleRequests:    make(chan struct{}, 1),
+		snapshots:           NewSchedulingSnapshotStore(models.DefaultSchedulingSnapshotRetention),
+		pendingBenchmarks:   newPendingBenchmarks(),
+	}
+}
+
+// Snapshots returns the store of recent scheduling cycles
+func (s *JobSchedulingService) Snapshots() *SchedulingSnapshotStore {
+	return s.snapshots
+}
+
+// RequestSchedule asks the running scheduler for a scheduling cycle now
+// instead of at the next interval. Requests made while one is pending are merged.
+func (s *JobSchedulingService) RequestSchedule() {
+	select {
+	case s.scheduleRequests <- struct{}{}:
+	default:
 	}
 }
 
+// SubscribeEvents registers the scheduler's event handlers so agents freed by a
+// completed task are given new work without waiting for the next interval
+func (s *JobSchedulingService) SubscribeEvents(bus *events.Bus) {
+	bus.Subscribe(events.TaskCompleted, "scheduling.schedule_on_task_completed", func(ctx context.Context, event *events.Event) error {
+		s.RequestSchedule()
+		return nil
+	})
+}
+
 // ScheduleJobsResult contains the result of a scheduling operation
 type ScheduleJobsResult struct {
 	AssignedTasks   []models.JobTask
@@ -73,12 +114,28 @@ func (s *JobSchedulingService) ScheduleJobs(ctx context.Context) (*ScheduleJobsR
 
 	debug.Log("Starting job scheduling cycle", nil)
 
+	// Record what the cycle considers and decides for later debugging
+	rec := newSchedulingRecorder(time.Now())
+	ctx = withSchedulingRecorder(ctx, rec)
+
 	result := &ScheduleJobsResult{
 		AssignedTasks:   []models.JobTask{},
 		InterruptedJobs: []uuid.UUID{},
 		Errors:          []error{},
 	}
 
+	// Start scheduled jobs whose start time or dependency has been reached
+	s.jobExecutionService.releaseScheduledJobs(ctx)
+
+	// Nothing is dispatched during maintenance, running tasks drain instead
+	if window := s.jobExecutionService.activeMaintenance(ctx); window != nil {
+		debug.Log("Maintenance mode active, skipping task dispatch", map[string]interface{}{
+			"maintenance_window_id": window.ID,
+		})
+		s.drainForMaintenance(ctx, window)
+		return result, nil
+	}
+
 	// Get available agents
 	availableAgents, err := s.jobExecutionService.GetAvailableAgents(ctx)
 	if err != nil {
@@ -112,27 +169,43 @@ func (s *JobSchedulingService) ScheduleJobs(ctx context.Context) (*ScheduleJobsR
 				"agent_count": len(availableAgents),
 			})
 		}
-		
-		// If still no agents available after interruption check, return
-		if len(availableAgents) == 0 {
-			return result, nil
-		}
+		// With still no agents available only background preemption below can free one
+	} else {
+		debug.Log("Found available agents", map[string]interface{}{
+			"agent_count": len(availableAgents),
+		})
 	}
 
-	debug.Log("Found available agents", map[string]interface{}{
-		"agent_count": len(availableAgents),
-	})
+	rec.considerAgents(availableAgents)
 
 	// Process each available agent
 	for _, agent := range availableAgents {
 		taskAssigned, interruptedJobs, err := s.assignWorkToAgent(ctx, &agent)
 		if err != nil {
+			rec.decide(agent.ID, nil, err)
 			assignErr := fmt.Errorf("failed to assign work to agent %d: %w", agent.ID, err)
 			result.Errors = append(result.Errors, assignErr)
 			debug.Error("Failed to assign work to agent: %v", assignErr)
 			continue
 		}
 
+		// An agent with no regular work may race a straggler of a nearly finished job
+		if taskAssigned == nil {
+			taskAssigned, err = s.assignSpeculativeTask(ctx, &agent)
+			if err != nil {
+				debug.Error("Failed to assign speculative work to agent %d: %v", agent.ID, err)
+			}
+		}
+
+		// Otherwise it may re-run a completed chunk sampled for verification
+		if taskAssigned == nil {
+			taskAssigned, err = s.assignVerificationTask(ctx, &agent)
+			if err != nil {
+				debug.Error("Failed to assign verification work to agent %d: %v", agent.ID, err)
+			}
+		}
+
+		rec.decide(agent.ID, taskAssigned, nil)
 		if taskAssigned != nil {
 			result.AssignedTasks = append(result.AssignedTasks, *taskAssigned)
 		}
@@ -140,15 +213,40 @@ func (s *JobSchedulingService) ScheduleJobs(ctx context.Context) (*ScheduleJobsR
 		result.InterruptedJobs = append(result.InterruptedJobs, interruptedJobs...)
 	}
 
+	// Background jobs give way to normal work that the free agents could not take
+	preemptedAgents, err := s.preemptBackgroundTasks(ctx)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("failed to preempt background tasks: %w", err))
+		debug.Error("Failed to preempt background tasks: %v", err)
+	} else if len(preemptedAgents) > 0 {
+		s.assignToPreemptedAgents(ctx, preemptedAgents, result)
+	}
+
 	debug.Log("Job scheduling cycle completed", map[string]interface{}{
 		"assigned_tasks":   len(result.AssignedTasks),
 		"interrupted_jobs": len(result.InterruptedJobs),
 		"errors":           len(result.Errors),
 	})
 
+	s.snapshots.SetRetention(s.snapshotRetention(ctx))
+	s.snapshots.Record(rec.finish(result.InterruptedJobs))
+
 	return result, nil
 }
 
+// snapshotRetention returns how long scheduling snapshots are kept
+func (s *JobSchedulingService) snapshotRetention(ctx context.Context) time.Duration {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, models.SchedulingSnapshotRetentionSetting)
+	if err != nil || setting.Value == nil {
+		return models.DefaultSchedulingSnapshotRetention
+	}
+	hours, err := strconv.Atoi(*setting.Value)
+	if err != nil || hours <= 0 {
+		return models.DefaultSchedulingSnapshotRetention
+	}
+	return time.Duration(hours) * time.Hour
+}
+
 // assignWorkToAgent assigns work to a specific agent
 // The function now checks if the agent has a valid benchmark for the job's attack mode and hash type.
 // If no benchmark exists or it's outdated, it requests a benchmark from the agent and defers the job assignment.
@@ -222,6 +320,7 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 							"agent_name": agent.Name,
 							"task_id":    taskIDStr,
 						})
+						schedulingRecorderFrom(ctx).note(agent.ID, &task.JobExecutionID, models.SchedulingReasonAgentBusy)
 						return nil, nil, nil // Agent is busy, skip assignment
 					}
 				}
@@ -261,6 +360,7 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 				"task_count":  len(reconnectPendingTasks),
 				"task_ids":    reconnectPendingTasks,
 			})
+			schedulingRecorderFrom(ctx).note(agent.ID, nil, models.SchedulingReasonReconnectPending)
 			return nil, nil, nil // Agent is still running the task
 		} else {
 			debug.Log("Agent has reconnect_pending tasks but is not busy, these should have been reset", map[string]interface{}{
@@ -271,8 +371,8 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 		}
 	}
 
-	// Get the next job with available work (respects priority + FIFO and max_agents)
-	nextJobWithWork, err := s.jobExecutionService.GetNextJobWithWork(ctx)
+	// Get the next job with available work (respects priority + FIFO, max_agents and low-power windows)
+	nextJobWithWork, err := s.jobExecutionService.GetNextJobWithWorkForAgent(ctx, agent.ID)
 	if err != nil {
 		debug.Log("Error getting next job with work", map[string]interface{}{
 			"agent_id": agent.ID,
@@ -307,8 +407,31 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 	} else if hashlist.CrackedHashes >= hashlist.TotalHashes {
 		debug.Warning("Hashlist %d is fully cracked (%d/%d), skipping task assignment for job %s",
 			nextJob.HashlistID, hashlist.CrackedHashes, hashlist.TotalHashes, nextJob.ID)
-		// Don't create tasks for fully cracked hashlists
-		// The hashlist completion handler should clean this up
+		schedulingRecorderFrom(ctx).note(agent.ID, &nextJob.ID, models.SchedulingReasonHashlistCracked)
+		// Don't create tasks for fully cracked hashlists, wrap up their jobs
+		// instead so they do not linger at the head of the queue
+		if s.hashlistCompletion != nil {
+			go func(hashlistID int64) {
+				bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+				defer cancel()
+				if err := s.hashlistCompletion.HandleHashlistFullyCracked(bgCtx, hashlistID); err != nil {
+					debug.Error("Failed to handle fully cracked hashlist %d: %v", hashlistID, err)
+				}
+			}(nextJob.HashlistID)
+		}
+		return nil, nil, nil
+	}
+
+	// Skip over chunks that an identical job on the same hashlist has already completed
+	if reused, err := s.reuseDuplicateChunks(ctx, nextJob); err != nil {
+		debug.Error("Failed to reuse duplicate chunks for job %s: %v", nextJob.ID, err)
+	} else if reused > 0 && !nextJob.UsesRuleSplitting &&
+		nextJob.TotalKeyspace != nil && nextJob.DispatchedKeyspace >= *nextJob.TotalKeyspace {
+		// Every chunk was reused, nothing is left to dispatch
+		schedulingRecorderFrom(ctx).note(agent.ID, &nextJob.ID, models.SchedulingReasonChunksReused)
+		if err := s.ProcessJobCompletion(ctx, nextJob.ID); err != nil {
+			debug.Error("Failed to process completion of job %s: %v", nextJob.ID, err)
+		}
 		return nil, nil, nil
 	}
 
@@ -322,6 +445,10 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 			if parsedTime, err := time.Parse(time.RFC3339, requestedAt); err == nil {
 				if time.Since(parsedTime) > 5*time.Minute {
 					debug.Warning("Benchmark request for agent %d timed out after 5 minutes, clearing and retrying", agent.ID)
+					event := models.NewAgentSystemEvent(models.SystemEventWarning, models.SystemEventCategoryBenchmark, models.SystemEventBenchmarkTimedOut,
+						agent.ID, fmt.Sprintf("Benchmark request for agent %d timed out after 5 minutes", agent.ID))
+					event.Details = map[string]interface{}{"job_id": agent.Metadata["pending_benchmark_job"], "requested_at": requestedAt}
+					s.recordSystemEvent(ctx, event)
 					delete(agent.Metadata, "pending_benchmark_job")
 					delete(agent.Metadata, "benchmark_requested_at")
 					s.agentRepo.Update(ctx, agent)
@@ -331,8 +458,17 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 		}
 	}
 
+	// Jobs can skip the forced benchmark and accept the estimated keyspace
+	benchmarkOverride, err := s.jobExecutionService.jobExecRepo.GetBenchmarkOverride(ctx, nextJob.ID)
+	if err != nil {
+		debug.Warning("Failed to get benchmark override for job %s: %v", nextJob.ID, err)
+		benchmarkOverride = &models.BenchmarkOverride{}
+	}
+
 	// Check if this job needs a forced benchmark before first task assignment
-	if !nextJob.IsAccurateKeyspace {
+	if !nextJob.IsAccurateKeyspace && benchmarkOverride.Skip {
+		debug.Info("Job %s skips its forced benchmark, chunking on the estimated keyspace", nextJob.ID)
+	} else if !nextJob.IsAccurateKeyspace {
 		// Check if any tasks have been created for this job yet
 		taskCount, err := s.jobExecutionService.jobTaskRepo.GetTaskCountForJob(ctx, nextJob.ID)
 		if err != nil {
@@ -345,6 +481,7 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 			if agent.Metadata != nil {
 				if pendingBench, exists := agent.Metadata["pending_benchmark_job"]; exists && pendingBench == nextJob.ID.String() {
 					debug.Info("Benchmark already pending for job %s on agent %d, waiting...", nextJob.ID, agent.ID)
+					schedulingRecorderFrom(ctx).note(agent.ID, &nextJob.ID, models.SchedulingReasonBenchmarkPending)
 					return nil, nil, nil // Benchmark in progress, don't assign yet
 				}
 			}
@@ -373,6 +510,7 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 				// Continue with task assignment using estimated keyspace
 			} else {
 				debug.Info("Sent forced benchmark request for job %s to agent %d", nextJob.ID, agent.ID)
+				schedulingRecorderFrom(ctx).note(agent.ID, &nextJob.ID, models.SchedulingReasonBenchmarkRequested)
 				return nil, nil, nil // Wait for benchmark to complete before assigning task
 			}
 		}
@@ -416,7 +554,24 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 		}
 
 		isRecent, err := s.jobExecutionService.benchmarkRepo.IsRecentBenchmark(ctx, agent.ID, nextJob.AttackMode, hashlist.HashTypeID, cacheDuration)
-		needsBenchmark = err != nil || !isRecent
+		// A job that skips benchmarks makes do with an outdated speed
+		needsBenchmark = (err != nil || !isRecent) && !benchmarkOverride.Skip
+	}
+
+	profile := benchmarkProfile{
+		agentID:         agent.ID,
+		attackMode:      nextJob.AttackMode,
+		hashType:        hashlist.HashTypeID,
+		binaryVersionID: nextJob.BinaryVersionID,
+	}
+	if !needsBenchmark {
+		s.pendingBenchmarks.release(profile)
+	} else if !s.pendingBenchmarks.claim(profile, time.Now()) {
+		// Another job with the same profile already asked this agent for a benchmark
+		debug.Info("Benchmark already pending on agent %d for attack mode %d, hash type %d, waiting...",
+			agent.ID, nextJob.AttackMode, hashlist.HashTypeID)
+		schedulingRecorderFrom(ctx).note(agent.ID, &nextJob.ID, models.SchedulingReasonBenchmarkPending)
+		return nil, interruptedJobs, nil
 	}
 
 	if needsBenchmark {
@@ -431,6 +586,7 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 		if s.wsIntegration != nil {
 			err = s.wsIntegration.RequestAgentBenchmark(ctx, agent.ID, nextJob)
 			if err != nil {
+				s.pendingBenchmarks.release(profile)
 				debug.Log("Failed to request benchmark from agent", map[string]interface{}{
 					"agent_id": agent.ID,
 					"error":    err.Error(),
@@ -445,10 +601,12 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 
 			// Return without assigning work - the agent will be available for assignment
 			// once the benchmark completes
+			schedulingRecorderFrom(ctx).note(agent.ID, &nextJob.ID, models.SchedulingReasonBenchmarkRequested)
 			return nil, interruptedJobs, nil
 		}
 
 		// If no WebSocket integration, we can't request benchmarks
+		s.pendingBenchmarks.release(profile)
 		return nil, interruptedJobs, fmt.Errorf("benchmark required but WebSocket integration not available")
 	}
 
@@ -565,8 +723,11 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 			// Estimate time to complete based on benchmark
 			estimatedTime := float64(effectiveKeyspace) / float64(benchmark.Speed)
 			
+			// Also split when the full rule set does not fit in the agent's device memory
+			splitForMemory := s.jobExecutionService.NeedsRuleSplitForMemory(ctx, nextJob, hashlist, agent.ID)
+
 			// If job would take longer than max duration, enable rule splitting
-			if estimatedTime > maxDuration {
+			if estimatedTime > maxDuration || splitForMemory {
 				nextJob.UsesRuleSplitting = true
 				nextJob.RuleSplitCount = 0  // Start at 0, will increment as chunks are created
 				// Update the job in database
@@ -596,6 +757,7 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 					"chunk_duration": chunkDuration,
 					"fluctuation_percent": fluctuationPercent,
 					"rule_split_count": 0,
+					"split_for_memory": splitForMemory,
 				})
 			} else {
 				debug.Log("Job can be completed in single chunk", map[string]interface{}{
@@ -622,6 +784,7 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 	if chunkDuration, err := s.getChunkDuration(ctx, nextJob); err == nil {
 		chunkReq.ChunkDuration = chunkDuration
 	}
+	chunkReq.CandidatesPerUnit = s.jobExecutionService.hybridCandidatesPerUnit(ctx, nextJob)
 
 	debug.Log("Calculating chunk for agent", map[string]interface{}{
 		"agent_id":       agent.ID,
@@ -785,6 +948,16 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 			}
 		}
 
+		// Never hand the agent more rules than fit in its device memory
+		if maxRules := s.jobExecutionService.MaxRulesPerChunkForAgent(ctx, hashlist, agent.ID); maxRules > 0 && nextRuleEnd-nextRuleStart > maxRules {
+			debug.Log("Limiting rule chunk to agent device memory", map[string]interface{}{
+				"agent_id":       agent.ID,
+				"rules_in_chunk": nextRuleEnd - nextRuleStart,
+				"max_rules":      maxRules,
+			})
+			nextRuleEnd = nextRuleStart + maxRules
+		}
+
 		// Create rule chunk file on-demand
 		// Get the rule path from the job execution (which has all needed data)
 		var rulePath string
@@ -822,12 +995,20 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 			"wordlist_ids":      nextJob.WordlistIDs,
 			"rule_ids":          nextJob.RuleIDs,
 		})
-		attackCmd, err := s.jobExecutionService.buildAttackCommand(ctx, nil, nextJob)
+		attackArgv, err := s.jobExecutionService.buildAttackCommand(ctx, nil, nextJob)
 		if err != nil {
 			debug.Error("Failed to build attack command: %v", err)
 			fmt.Printf("ERROR in assignWorkToAgent: Failed to build attack command for job %s: %v\n", nextJob.ID, err)
 			return nil, interruptedJobs, fmt.Errorf("failed to build attack command: %w", err)
 		}
+		// Replace rule file with chunk path
+		for i, arg := range attackArgv {
+			if arg == rulePath {
+				attackArgv[i] = chunk.Path
+				break
+			}
+		}
+		attackCmd := hashcatargs.Quote(attackArgv)
 		cmdPreview := attackCmd
 		if len(attackCmd) > 100 {
 			cmdPreview = attackCmd[:100] + "..."
@@ -836,8 +1017,6 @@ func (s *JobSchedulingService) assignWorkToAgent(ctx context.Context, agent *mod
 			"job_id":      nextJob.ID,
 			"cmd_preview": cmdPreview,
 		})
-		// Replace rule file with chunk path
-		attackCmd = strings.Replace(attackCmd, rulePath, chunk.Path, 1)
 
 		// Calculate effective keyspace for this chunk using previous chunks' ACTUAL sizes
 		effectiveKeyspaceStart := int64(0)
@@ -1132,6 +1311,8 @@ func (s *JobSchedulingService) StartScheduler(ctx context.Context, interval time
 		debug.Log("Failed to recover stale jobs on startup", map[string]interface{}{
 			"error": err.Error(),
 		})
+		s.recordSchedulerEvent(ctx, models.SystemEventWarning, models.SystemEventStaleRecoveryFailed,
+			"Failed to recover stale jobs on startup: "+err.Error(), nil)
 	}
 
 	// Run cleanup on startup
@@ -1147,22 +1328,9 @@ func (s *JobSchedulingService) StartScheduler(ctx context.Context, interval time
 			debug.Log("Job scheduler stopped", nil)
 			return
 		case <-ticker.C:
-			result, err := s.ScheduleJobs(ctx)
-			if err != nil {
-				debug.Log("Scheduling cycle failed", map[string]interface{}{
-					"error": err.Error(),
-				})
-				continue
-			}
-
-			// Log scheduling results
-			if len(result.AssignedTasks) > 0 || len(result.InterruptedJobs) > 0 || len(result.Errors) > 0 {
-				debug.Log("Scheduling cycle completed", map[string]interface{}{
-					"assigned_tasks":   len(result.AssignedTasks),
-					"interrupted_jobs": len(result.InterruptedJobs),
-					"errors":           len(result.Errors),
-				})
-			}
+			s.runSchedulingCycle(ctx)
+		case <-s.scheduleRequests:
+			s.runSchedulingCycle(ctx)
 		case <-cleanupTicker.C:
 			// Run periodic cleanup of stale agent status
 			if err := s.CleanupStaleAgentStatus(ctx); err != nil {
@@ -1174,6 +1342,67 @@ func (s *JobSchedulingService) StartScheduler(ctx context.Context, interval time
 	}
 }
 
+// runSchedulingCycle runs one scheduling cycle and logs the result
+func (s *JobSchedulingService) runSchedulingCycle(ctx context.Context) {
+	result, err := s.ScheduleJobs(ctx)
+	if err != nil {
+		debug.Log("Scheduling cycle failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		s.recordCycleEvent(ctx, models.SystemEventError, models.SystemEventSchedulingFailed,
+			"Scheduling cycle failed: "+err.Error(), nil)
+		return
+	}
+
+	// Log scheduling results
+	if len(result.AssignedTasks) > 0 || len(result.InterruptedJobs) > 0 || len(result.Errors) > 0 {
+		debug.Log("Scheduling cycle completed", map[string]interface{}{
+			"assigned_tasks":   len(result.AssignedTasks),
+			"interrupted_jobs": len(result.InterruptedJobs),
+			"errors":           len(result.Errors),
+		})
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, err := range result.Errors {
+			messages[i] = err.Error()
+		}
+		s.recordCycleEvent(ctx, models.SystemEventWarning, models.SystemEventSchedulingErrors,
+			fmt.Sprintf("Scheduling cycle completed with %d errors: %s", len(result.Errors), strings.Join(messages, "; ")),
+			map[string]interface{}{"assigned_tasks": len(result.AssignedTasks)})
+	}
+}
+
+// recordCycleEvent records a scheduling cycle problem unless the same one was
+// recorded within schedulerEventRepeat. Cycles run one at a time from the
+// scheduler loop, so the last event needs no locking.
+func (s *JobSchedulingService) recordCycleEvent(ctx context.Context, severity, eventType, message string, details map[string]interface{}) {
+	if message == s.lastCycleEvent && time.Since(s.lastCycleEventAt) < schedulerEventRepeat {
+		return
+	}
+	s.lastCycleEvent, s.lastCycleEventAt = message, time.Now()
+	s.recordSchedulerEvent(ctx, severity, eventType, message, details)
+}
+
+// recordSchedulerEvent adds a scheduler event to the system event log
+func (s *JobSchedulingService) recordSchedulerEvent(ctx context.Context, severity, eventType, message string, details map[string]interface{}) {
+	s.recordSystemEvent(ctx, &models.SystemEvent{
+		Severity:  severity,
+		Category:  models.SystemEventCategoryScheduler,
+		EventType: eventType,
+		Message:   message,
+		Details:   details,
+	})
+}
+
+// recordSystemEvent adds an event to the system event log
+func (s *JobSchedulingService) recordSystemEvent(ctx context.Context, event *models.SystemEvent) {
+	if s.jobExecutionService == nil {
+		return
+	}
+	s.jobExecutionService.systemEvents.Record(ctx, event)
+}
+
 // checkAndInterruptForHighPriority checks if there are high-priority jobs waiting
 // that should interrupt lower priority running jobs. This only runs when no agents are available.
 func (s *JobSchedulingService) checkAndInterruptForHighPriority(ctx context.Context) (*uuid.UUID, error) {
@@ -1230,6 +1459,17 @@ func (s *JobSchedulingService) checkAndInterruptForHighPriority(ctx context.Cont
 		return nil, nil
 	}
 
+	// Don't interrupt for a job that a concurrency cap keeps from starting
+	if caps := s.jobExecutionService.jobConcurrencyCaps(ctx); caps.enabled() {
+		running, err := s.jobExecutionService.jobExecRepo.GetRunningJobCounts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count running jobs: %w", err)
+		}
+		if !s.jobExecutionService.admitJobConcurrency(ctx, &highPriorityJob, caps, running) {
+			return nil, nil
+		}
+	}
+
 	// Check if there are any interruptible jobs with lower priority
 	interruptibleJobs, err := s.jobExecutionService.CanInterruptJob(ctx, highPriorityJob.Priority)
 	if err != nil {
@@ -1429,6 +1669,14 @@ func (s *JobSchedulingService) ProcessTaskProgress(ctx context.Context, taskID u
 				"error": err.Error(),
 			})
 		}
+
+		// Track the task's observed speed for agent performance scoring
+		if err := s.jobExecutionService.jobTaskRepo.RecordTaskSpeed(ctx, taskID, progress.HashRate); err != nil {
+			debug.Log("Failed to record task speed", map[string]interface{}{
+				"task_id": taskID,
+				"error":   err.Error(),
+			})
+		}
 	}
 
 	// Store device-specific metrics if available
@@ -1545,6 +1793,12 @@ func (s *JobSchedulingService) SetWebSocketIntegration(integration JobWebSocketI
 	s.wsIntegration = integration
 }
 
+// SetHashlistCompletionService sets the service that wraps up the jobs of a
+// hashlist the scheduler finds fully cracked
+func (s *JobSchedulingService) SetHashlistCompletionService(service *HashlistCompletionService) {
+	s.hashlistCompletion = service
+}
+
 // StopJob stops a running job execution and all its tasks
 func (s *JobSchedulingService) StopJob(ctx context.Context, jobExecutionID uuid.UUID, reason string) error {
 	// Update job execution status to cancelled
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/rule"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/wordlist"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatmask"
+)
+
+// ErrInvalidSimulation is returned for a job simulation request that cannot be simulated
+var ErrInvalidSimulation = errors.New("invalid job simulation")
+
+// maxSimulatedChunks bounds the chunks simulated one by one, larger jobs
+// skip their full scheduling rounds arithmetically
+const maxSimulatedChunks = 100000
+
+// JobSimulationService predicts the runtime and chunking of hypothetical jobs
+// for capacity planning, without creating any work
+type JobSimulationService struct {
+	chunkingService    *JobChunkingService
+	agentRepo          *repository.AgentRepository
+	systemSettingsRepo *repository.SystemSettingsRepository
+	wordlistStore      *wordlist.Store
+	ruleStore          *rule.Store
+}
+
+// NewJobSimulationService creates a new job simulation service
+func NewJobSimulationService(
+	chunkingService *JobChunkingService,
+	agentRepo *repository.AgentRepository,
+	systemSettingsRepo *repository.SystemSettingsRepository,
+	wordlistStore *wordlist.Store,
+	ruleStore *rule.Store,
+) *JobSimulationService {
+	return &JobSimulationService{
+		chunkingService:    chunkingService,
+		agentRepo:          agentRepo,
+		systemSettingsRepo: systemSettingsRepo,
+		wordlistStore:      wordlistStore,
+		ruleStore:          ruleStore,
+	}
+}
+
+// Simulate predicts how the scheduler would chunk a job and spread it over
+// the given agents
+func (s *JobSimulationService) Simulate(ctx context.Context, req *models.JobSimulationRequest) (*models.JobSimulationResult, error) {
+	if len(req.Agents) == 0 {
+		return nil, fmt.Errorf("%w: at least one agent is required", ErrInvalidSimulation)
+	}
+	if req.HashCount < 0 || req.Keyspace < 0 || req.ChunkDurationSeconds < 0 {
+		return nil, fmt.Errorf("%w: hash_count, keyspace and chunk_duration_seconds cannot be negative", ErrInvalidSimulation)
+	}
+
+	keyspace := req.Keyspace
+	if keyspace == 0 {
+		var err error
+		if keyspace, err = s.attackKeyspace(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	chunkDuration := req.ChunkDurationSeconds
+	if chunkDuration == 0 {
+		chunkDuration = s.intSetting(ctx, "default_chunk_duration", 1200)
+	}
+	if chunkDuration <= 0 {
+		chunkDuration = 1200
+	}
+	fluctuation := s.intSetting(ctx, "chunk_fluctuation_percentage", 20)
+
+	workloads := make([]models.SimulatedAgentWorkload, len(req.Agents))
+	for i, agent := range req.Agents {
+		workload, err := s.simulatedAgent(ctx, req, i, agent)
+		if err != nil {
+			return nil, err
+		}
+		workloads[i] = *workload
+	}
+
+	result := simulateJob(keyspace, chunkDuration, fluctuation, workloads)
+
+	debug.Log("Simulated job", map[string]interface{}{
+		"keyspace":        result.Keyspace,
+		"agents":          len(result.Agents),
+		"chunk_count":     result.ChunkCount,
+		"runtime_seconds": result.RuntimeSeconds,
+	})
+
+	return result, nil
+}
+
+// simulatedAgent resolves the name and speed of a simulated agent
+func (s *JobSimulationService) simulatedAgent(ctx context.Context, req *models.JobSimulationRequest, index int, agent models.SimulatedAgent) (*models.SimulatedAgentWorkload, error) {
+	if agent.Speed < 0 {
+		return nil, fmt.Errorf("%w: agent %d has a negative speed", ErrInvalidSimulation, index+1)
+	}
+	workload := &models.SimulatedAgentWorkload{
+		AgentID:     agent.AgentID,
+		Name:        agent.Name,
+		Speed:       agent.Speed,
+		SpeedSource: models.SimulatedSpeedRequest,
+	}
+
+	if agent.AgentID != nil {
+		existing, err := s.agentRepo.GetByID(ctx, *agent.AgentID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: agent %d: %v", ErrInvalidSimulation, *agent.AgentID, err)
+		}
+		if workload.Name == "" {
+			workload.Name = existing.Name
+		}
+		if workload.Speed == 0 {
+			speed, err := s.chunkingService.GetOrEstimateBenchmark(ctx, existing.ID, req.AttackMode, req.HashType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get benchmark of agent %d: %w", existing.ID, err)
+			}
+			workload.Speed = speed
+			workload.SpeedSource = models.SimulatedSpeedBenchmark
+		}
+	}
+
+	if workload.Speed == 0 {
+		return nil, fmt.Errorf("%w: agent %d needs a speed or an agent_id", ErrInvalidSimulation, index+1)
+	}
+	if workload.Name == "" {
+		workload.Name = "Agent " + strconv.Itoa(index+1)
+	}
+
+	workload.EffectiveSpeed = workload.Speed
+	if req.Salted && req.HashCount > 1 {
+		workload.EffectiveSpeed = workload.Speed / req.HashCount
+	}
+	if workload.EffectiveSpeed < 1 {
+		workload.EffectiveSpeed = 1
+	}
+	return workload, nil
+}
+
+// attackKeyspace calculates the keyspace of the simulated attack the way job
+// creation estimates it: words times rules for straight attacks, the product
+// of both wordlists for combination attacks and the mask size for masks
+func (s *JobSimulationService) attackKeyspace(ctx context.Context, req *models.JobSimulationRequest) (int64, error) {
+	words, err := s.wordlistSizes(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	rules, err := s.ruleCounts(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	var keyspace int64
+	switch req.AttackMode {
+	case models.AttackModeStraight:
+		if len(words) == 0 {
+			return 0, fmt.Errorf("%w: a straight attack needs a wordlist", ErrInvalidSimulation)
+		}
+		for _, count := range words {
+			keyspace += count
+		}
+		for _, count := range rules {
+			if keyspace, err = multiplyKeyspace(keyspace, count); err != nil {
+				return 0, err
+			}
+		}
+	case models.AttackModeCombination:
+		if len(words) != 2 {
+			return 0, fmt.Errorf("%w: a combination attack needs two wordlists", ErrInvalidSimulation)
+		}
+		if keyspace, err = multiplyKeyspace(words[0], words[1]); err != nil {
+			return 0, err
+		}
+	case models.AttackModeBruteForce:
+		if keyspace, err = maskKeyspace(req.Mask); err != nil {
+			return 0, err
+		}
+	case models.AttackModeHybridWordlistMask, models.AttackModeHybridMaskWordlist:
+		if len(words) != 1 {
+			return 0, fmt.Errorf("%w: a hybrid attack needs one wordlist", ErrInvalidSimulation)
+		}
+		if keyspace, err = maskKeyspace(req.Mask); err != nil {
+			return 0, err
+		}
+		if keyspace, err = multiplyKeyspace(keyspace, words[0]); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("%w: attack mode %d needs an explicit keyspace", ErrInvalidSimulation, req.AttackMode)
+	}
+
+	if keyspace <= 0 {
+		return 0, fmt.Errorf("%w: the attack has an empty keyspace", ErrInvalidSimulation)
+	}
+	return keyspace, nil
+}
+
+// wordlistSizes returns the word counts of the requested wordlists
+func (s *JobSimulationService) wordlistSizes(ctx context.Context, req *models.JobSimulationRequest) ([]int64, error) {
+	var sizes []int64
+	for _, id := range req.WordlistIDs {
+		list, err := s.wordlistStore.GetWordlist(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get wordlist %d: %w", id, err)
+		}
+		if list == nil {
+			return nil, fmt.Errorf("%w: wordlist %d not found", ErrInvalidSimulation, id)
+		}
+		sizes = append(sizes, list.WordCount)
+	}
+	for _, size := range req.WordlistSizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("%w: wordlist sizes must be positive", ErrInvalidSimulation)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// ruleCounts returns the rule counts of the requested rule files
+func (s *JobSimulationService) ruleCounts(ctx context.Context, req *models.JobSimulationRequest) ([]int64, error) {
+	var counts []int64
+	for _, id := range req.RuleIDs {
+		r, err := s.ruleStore.GetRule(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rule %d: %w", id, err)
+		}
+		if r == nil {
+			return nil, fmt.Errorf("%w: rule %d not found", ErrInvalidSimulation, id)
+		}
+		counts = append(counts, r.RuleCount)
+	}
+	for _, count := range req.RuleCounts {
+		if count <= 0 {
+			return nil, fmt.Errorf("%w: rule counts must be positive", ErrInvalidSimulation)
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+// intSetting reads an integer system setting, falling back to def
+func (s *JobSimulationService) intSetting(ctx context.Context, key string, def int) int {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, key)
+	if err != nil || setting == nil || setting.Value == nil {
+		return def
+	}
+	parsed, err := parseIntValue(*setting.Value)
+	if err != nil {
+		return def
+	}
+	return parsed
+}
+
+// maskKeyspace returns the number of candidates of a mask, which may carry
+// custom charsets in hcmask format
+func maskKeyspace(mask string) (int64, error) {
+	if mask == "" {
+		return 0, fmt.Errorf("%w: the attack needs a mask", ErrInvalidSimulation)
+	}
+	analysis, err := hashcatmask.Validate(mask)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidSimulation, err)
+	}
+	return analysis.Keyspace, nil
+}
+
+// multiplyKeyspace multiplies two keyspaces, failing instead of overflowing
+func multiplyKeyspace(a, b int64) (int64, error) {
+	if a != 0 && b > math.MaxInt64/a {
+		return 0, fmt.Errorf("%w: the keyspace is too large, pass it as keyspace", ErrInvalidSimulation)
+	}
+	return a * b, nil
+}
+
+// simulateJob hands out chunks of keyspace the way the scheduler does: every
+// free agent gets a chunk of chunkDuration seconds at its speed, and a last
+// remainder of at most fluctuation percent of a chunk is merged into the
+// chunk before it. Agents are taken in order when several are free.
+func simulateJob(keyspace int64, chunkDuration, fluctuation int, agents []models.SimulatedAgentWorkload) *models.JobSimulationResult {
+	result := &models.JobSimulationResult{
+		Keyspace:             keyspace,
+		ChunkDurationSeconds: chunkDuration,
+		Agents:               agents,
+	}
+
+	chunkSizes := make([]int64, len(agents))
+	var roundSize int64
+	for i := range agents {
+		chunkSizes[i] = int64(chunkDuration) * agents[i].EffectiveSpeed
+		if chunkSizes[i] < 1 {
+			chunkSizes[i] = 1
+		}
+		roundSize += chunkSizes[i]
+	}
+
+	// Full chunks take chunkDuration on every agent, so until the tail of the
+	// job all agents take a chunk per round. Skip the rounds that would
+	// simulate more chunks than maxSimulatedChunks, keeping two rounds of
+	// tail so the last chunks are handed out as they would be.
+	var start int64
+	var clock float64
+	if rounds := keyspace/roundSize - 2; rounds > 0 && rounds*int64(len(agents)) > maxSimulatedChunks {
+		for i := range agents {
+			agents[i].Chunks += rounds
+			agents[i].Keyspace += rounds * chunkSizes[i]
+			agents[i].BusySeconds += float64(rounds) * float64(chunkDuration)
+		}
+		start = rounds * roundSize
+		clock = float64(rounds) * float64(chunkDuration)
+		result.ChunkCount = rounds * int64(len(agents))
+	}
+
+	freeAt := make([]float64, len(agents))
+	for i := range freeAt {
+		freeAt[i] = clock
+	}
+	for start < keyspace {
+		next := 0
+		for i := range freeAt {
+			if freeAt[i] < freeAt[next] {
+				next = i
+			}
+		}
+
+		end := start + chunkSizes[next]
+		threshold := chunkSizes[next] * int64(fluctuation) / 100
+		if end >= keyspace || keyspace-end <= threshold {
+			end = keyspace
+		}
+
+		duration := float64(end-start) / float64(agents[next].EffectiveSpeed)
+		agents[next].Chunks++
+		agents[next].Keyspace += end - start
+		agents[next].BusySeconds += duration
+		freeAt[next] += duration
+		result.ChunkCount++
+		start = end
+	}
+
+	for i := range agents {
+		result.AgentSeconds += agents[i].BusySeconds
+		// Agents start together and stay busy until they run out of chunks,
+		// so the job ends with the busiest agent
+		if agents[i].BusySeconds > result.RuntimeSeconds {
+			result.RuntimeSeconds = agents[i].BusySeconds
+		}
+		if keyspace > 0 {
+			agents[i].SharePercent = float64(agents[i].Keyspace) * 100 / float64(keyspace)
+		}
+	}
+	return result
+}
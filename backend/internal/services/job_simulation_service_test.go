@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskKeyspace(t *testing.T) {
+	tests := []struct {
+		mask     string
+		keyspace int64
+		invalid  bool
+	}{
+		{mask: "?d?d?d?d", keyspace: 10000},
+		{mask: "?l?u?d?s", keyspace: 26 * 26 * 10 * 33},
+		{mask: "pass?d?d", keyspace: 100},
+		{mask: "?h?H??", keyspace: 256},
+		{mask: "?a?a?a?a?a?a?a?a", keyspace: 6634204312890625},
+		{mask: "?l?d,?1?1", keyspace: 36 * 36},
+		{mask: "", invalid: true},
+		{mask: "?d?", invalid: true},
+		{mask: "?1?d", invalid: true},
+		{mask: "?b?b?b?b?b?b?b?b", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mask, func(t *testing.T) {
+			keyspace, err := maskKeyspace(tt.mask)
+			if tt.invalid {
+				assert.True(t, errors.Is(err, ErrInvalidSimulation), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.keyspace, keyspace)
+		})
+	}
+}
+
+func simulatedAgents(speeds ...int64) []models.SimulatedAgentWorkload {
+	agents := make([]models.SimulatedAgentWorkload, len(speeds))
+	for i, speed := range speeds {
+		agents[i] = models.SimulatedAgentWorkload{Speed: speed, EffectiveSpeed: speed}
+	}
+	return agents
+}
+
+func TestSimulateJobSingleAgent(t *testing.T) {
+	// 10 chunks of 1000, the remainder of 100 is merged into the last one
+	result := simulateJob(10100, 10, 20, simulatedAgents(100))
+
+	assert.Equal(t, int64(10), result.ChunkCount)
+	assert.InDelta(t, 101, result.RuntimeSeconds, 0.001)
+	assert.InDelta(t, 101, result.AgentSeconds, 0.001)
+	assert.Equal(t, int64(10100), result.Agents[0].Keyspace)
+	assert.InDelta(t, 100, result.Agents[0].SharePercent, 0.001)
+}
+
+func TestSimulateJobRemainderAboveFluctuation(t *testing.T) {
+	result := simulateJob(10500, 10, 20, simulatedAgents(100))
+
+	assert.Equal(t, int64(11), result.ChunkCount)
+	assert.InDelta(t, 105, result.RuntimeSeconds, 0.001)
+}
+
+func TestSimulateJobSpreadsBySpeed(t *testing.T) {
+	// Chunks take 10 seconds on both agents, the fast one covers three times
+	// the keyspace
+	result := simulateJob(40000, 10, 0, simulatedAgents(300, 100))
+
+	assert.Equal(t, int64(20), result.ChunkCount)
+	assert.Equal(t, int64(10), result.Agents[0].Chunks)
+	assert.Equal(t, int64(10), result.Agents[1].Chunks)
+	assert.Equal(t, int64(30000), result.Agents[0].Keyspace)
+	assert.Equal(t, int64(10000), result.Agents[1].Keyspace)
+	assert.InDelta(t, 100, result.RuntimeSeconds, 0.001)
+	assert.InDelta(t, 200, result.AgentSeconds, 0.001)
+	assert.InDelta(t, 75, result.Agents[0].SharePercent, 0.001)
+}
+
+func TestSimulateJobSmallJob(t *testing.T) {
+	// One chunk is enough, the second agent stays idle
+	result := simulateJob(500, 10, 20, simulatedAgents(100, 100))
+
+	assert.Equal(t, int64(1), result.ChunkCount)
+	assert.Equal(t, int64(1), result.Agents[0].Chunks)
+	assert.Equal(t, int64(0), result.Agents[1].Chunks)
+	assert.InDelta(t, 5, result.RuntimeSeconds, 0.001)
+}
+
+func TestSimulateJobSkipsFullRounds(t *testing.T) {
+	// A keyspace needing billions of chunks is simulated in full rounds
+	keyspace := int64(1) << 50
+	result := simulateJob(keyspace, 60, 20, simulatedAgents(1000, 3000))
+
+	var covered int64
+	var chunks int64
+	for _, agent := range result.Agents {
+		covered += agent.Keyspace
+		chunks += agent.Chunks
+	}
+	assert.Equal(t, keyspace, covered)
+	assert.Equal(t, result.ChunkCount, chunks)
+	assert.Greater(t, result.ChunkCount, int64(maxSimulatedChunks))
+	assert.InDelta(t, float64(keyspace)/4000, result.RuntimeSeconds, 60)
+}
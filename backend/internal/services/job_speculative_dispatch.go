@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// Defaults used when the speculative dispatch settings are missing or invalid
+const (
+	defaultSpeculativeStragglerFactor = 3.0
+	defaultSpeculativeMinJobProgress  = 90.0
+)
+
+// speculativeDispatchSettings reads whether speculative re-dispatch is enabled,
+// how many times its chunk duration a task may run before it is a straggler,
+// and how far along a job must be before its stragglers are re-dispatched
+func (s *JobSchedulingService) speculativeDispatchSettings(ctx context.Context) (bool, float64, float64) {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, "speculative_dispatch_enabled")
+	if err != nil || setting.Value == nil || *setting.Value != "true" {
+		return false, 0, 0
+	}
+
+	factor := defaultSpeculativeStragglerFactor
+	if setting, err := s.systemSettingsRepo.GetSetting(ctx, "speculative_straggler_factor"); err == nil && setting.Value != nil {
+		if parsed, err := strconv.ParseFloat(*setting.Value, 64); err == nil && parsed >= 1 {
+			factor = parsed
+		}
+	}
+
+	minProgress := defaultSpeculativeMinJobProgress
+	if setting, err := s.systemSettingsRepo.GetSetting(ctx, "speculative_min_job_progress"); err == nil && setting.Value != nil {
+		if parsed, err := strconv.ParseFloat(*setting.Value, 64); err == nil && parsed >= 0 && parsed <= 100 {
+			minProgress = parsed
+		}
+	}
+
+	return true, factor, minProgress
+}
+
+// assignSpeculativeTask gives an idle agent a copy of a straggling task from a
+// nearly finished job. Both tasks then race over the same keyspace and the
+// first to complete wins; see ResolveSpeculativeTask. Returns nil when
+// speculation is disabled, the agent is not idle or there is no straggler.
+func (s *JobSchedulingService) assignSpeculativeTask(ctx context.Context, agent *models.Agent) (*models.JobTask, error) {
+	enabled, factor, minProgress := s.speculativeDispatchSettings(ctx)
+	if !enabled || s.wsIntegration == nil {
+		return nil, nil
+	}
+
+	// Only agents with nothing at all to do take on speculative work
+	idle, err := s.agentHasNoWork(ctx, agent)
+	if err != nil || !idle {
+		return nil, err
+	}
+
+	candidates, err := s.jobExecutionService.jobTaskRepo.GetSpeculationCandidates(ctx, factor, minProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	priorityLimit, err := s.jobExecutionService.AgentPriorityLimit(ctx, agent.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check power window: %w", err)
+	}
+
+	for _, straggler := range candidates {
+		if straggler.AgentID != nil && *straggler.AgentID == agent.ID {
+			continue
+		}
+		if priorityLimit != nil && straggler.Priority > *priorityLimit {
+			continue
+		}
+
+		job, err := s.jobExecutionService.jobExecRepo.GetByID(ctx, straggler.JobExecutionID)
+		if err != nil {
+			debug.Warning("Skipping speculation for task %s: failed to get job: %v", straggler.ID, err)
+			continue
+		}
+		if !job.AgentPlacement.Allows(agent.ID) || (agent.IsCloudBurst() && !job.AllowCloudBurst) {
+			continue
+		}
+
+		if err := s.hashlistSyncService.EnsureHashlistOnAgent(ctx, agent.ID, job.HashlistID); err != nil {
+			debug.Warning("Skipping speculation for task %s on agent %d: failed to sync hashlist: %v", straggler.ID, agent.ID, err)
+			continue
+		}
+
+		stragglerID := straggler.ID
+		copyTask := &models.JobTask{
+			JobExecutionID:         straggler.JobExecutionID,
+			AgentID:                &agent.ID,
+			Status:                 models.JobTaskStatusPending,
+			Priority:               straggler.Priority,
+			AttackCmd:              straggler.AttackCmd,
+			KeyspaceStart:          straggler.KeyspaceStart,
+			KeyspaceEnd:            straggler.KeyspaceEnd,
+			EffectiveKeyspaceStart: straggler.EffectiveKeyspaceStart,
+			EffectiveKeyspaceEnd:   straggler.EffectiveKeyspaceEnd,
+			IsActualKeyspace:       straggler.IsActualKeyspace,
+			BenchmarkSpeed:         straggler.BenchmarkSpeed,
+			ChunkDuration:          straggler.ChunkDuration,
+			RuleStartIndex:         straggler.RuleStartIndex,
+			RuleEndIndex:           straggler.RuleEndIndex,
+			RuleChunkPath:          straggler.RuleChunkPath,
+			IsRuleSplitTask:        straggler.IsRuleSplitTask,
+			ChunkNumber:            straggler.ChunkNumber,
+			SpeculativeOf:          &stragglerID,
+		}
+		if err := s.jobExecutionService.jobTaskRepo.Create(ctx, copyTask); err != nil {
+			return nil, fmt.Errorf("failed to create speculative task: %w", err)
+		}
+
+		if copyTask.IsRuleSplitTask {
+			if err := s.hashlistSyncService.SyncJobFiles(ctx, agent.ID, copyTask); err != nil {
+				debug.Warning("Failed to sync rule chunk for speculative task %s: %v", copyTask.ID, err)
+			}
+		}
+
+		if err := s.wsIntegration.SendJobAssignment(ctx, copyTask, job); err != nil {
+			debug.Error("Failed to send speculative task %s to agent %d: %v", copyTask.ID, agent.ID, err)
+		}
+
+		debug.Info("Speculatively re-dispatched straggling task %s (chunk %d of job %s, started %v) to agent %d as task %s",
+			straggler.ID, straggler.ChunkNumber, job.ID, straggler.StartedAt, agent.ID, copyTask.ID)
+		return copyTask, nil
+	}
+
+	return nil, nil
+}
+
+// ResolveSpeculativeTask is called once a task has completed. If the task was
+// racing a speculative peer over the same chunk, the peers still running lose:
+// their agents are told to stop and the tasks are cancelled.
+func (s *JobSchedulingService) ResolveSpeculativeTask(ctx context.Context, winner *models.JobTask) error {
+	peers, err := s.jobExecutionService.jobTaskRepo.GetSpeculativePeers(ctx, winner)
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		if peer.Status != models.JobTaskStatusPending && peer.Status != models.JobTaskStatusAssigned && peer.Status != models.JobTaskStatusRunning {
+			continue
+		}
+
+		if s.wsIntegration != nil {
+			if err := s.wsIntegration.SendJobStop(ctx, peer.ID, "Chunk completed by another agent"); err != nil {
+				debug.Warning("Failed to stop speculative peer %s: %v", peer.ID, err)
+			}
+		}
+		if err := s.jobExecutionService.jobTaskRepo.CancelTask(ctx, peer.ID); err != nil {
+			debug.Warning("Failed to cancel speculative peer %s: %v", peer.ID, err)
+		}
+
+		if peer.AgentID != nil {
+			if agent, err := s.agentRepo.GetByID(ctx, *peer.AgentID); err == nil && agent.Metadata != nil {
+				agent.Metadata["busy_status"] = "false"
+				delete(agent.Metadata, "current_task_id")
+				delete(agent.Metadata, "current_job_id")
+				if err := s.agentRepo.UpdateMetadata(ctx, agent.ID, agent.Metadata); err != nil {
+					debug.Error("Failed to clear busy status for agent %d: %v", agent.ID, err)
+				}
+			}
+		}
+
+		debug.Info("Task %s won the speculative race, cancelled peer task %s", winner.ID, peer.ID)
+	}
+
+	return nil
+}
+
+// IsSpeculativeLoser reports whether a cancelled task lost a speculative race,
+// in which case any late updates from its agent should be ignored
+func (s *JobSchedulingService) IsSpeculativeLoser(ctx context.Context, task *models.JobTask) bool {
+	if task.Status != models.JobTaskStatusCancelled {
+		return false
+	}
+
+	peers, err := s.jobExecutionService.jobTaskRepo.GetSpeculativePeers(ctx, task)
+	if err != nil {
+		debug.Warning("Failed to get speculative peers of task %s: %v", task.ID, err)
+		return false
+	}
+	for _, peer := range peers {
+		if peer.Status == models.JobTaskStatusCompleted {
+			return true
+		}
+	}
+	return false
+}
+
+// progressTasks drops speculative duplicates so each chunk is counted once when
+// aggregating job progress. A copy only counts once it has completed, and then
+// replaces the original it was racing. Verification re-runs never count.
+func progressTasks(tasks []models.JobTask) []models.JobTask {
+	replaced := make(map[string]bool)
+	for _, task := range tasks {
+		if task.VerificationOf != nil {
+			continue
+		}
+		if task.SpeculativeOf != nil && task.Status == models.JobTaskStatusCompleted {
+			replaced[task.SpeculativeOf.String()] = true
+		}
+	}
+
+	counted := make([]models.JobTask, 0, len(tasks))
+	for _, task := range tasks {
+		if task.VerificationOf != nil {
+			continue
+		}
+		if task.SpeculativeOf != nil {
+			if task.Status == models.JobTaskStatusCompleted && !replaced[task.ID.String()] {
+				counted = append(counted, task)
+			}
+			continue
+		}
+		if replaced[task.ID.String()] {
+			continue
+		}
+		counted = append(counted, task)
+	}
+	return counted
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// releaseScheduledJobs moves scheduled jobs whose start condition is met to
+// pending so the scheduling cycle can pick them up. Jobs whose dependency
+// failed or was cancelled are cancelled as well.
+func (s *JobExecutionService) releaseScheduledJobs(ctx context.Context) {
+	released, cancelled, err := s.jobExecRepo.ReleaseScheduledJobs(ctx)
+	for _, job := range cancelled {
+		debug.Warning("Cancelled scheduled job %s (%s), the job it depends on did not complete", job.ID, job.Name)
+	}
+	if err != nil {
+		debug.Error("Failed to release scheduled jobs: %v", err)
+		return
+	}
+	for _, job := range released {
+		debug.Info("Released scheduled job %s (%s)", job.ID, job.Name)
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+)
+
+// ResolveResumeOffset returns how many candidates after its start a task can
+// be dispatched from. Agents report hashcat's restore point with their
+// progress; every candidate before it was fully processed, so a chunk that is
+// reassigned after its agent crashed resumes there with --skip instead of
+// starting over. Rule-split tasks always cover the whole wordlist and never
+// resume.
+func ResolveResumeOffset(task *models.JobTask, checkpoint *int64) int64 {
+	if task.IsRuleSplitTask || checkpoint == nil {
+		return 0
+	}
+	if *checkpoint <= task.KeyspaceStart || *checkpoint >= task.KeyspaceEnd {
+		return 0
+	}
+	return *checkpoint - task.KeyspaceStart
+}
+
+// TaskCheckpoint returns the absolute keyspace position of the restore point
+// in a progress update that was already corrected by RemoveChunkOverlap and
+// ApplyResumeOffset. It returns false when the agent reported no restore point.
+func TaskCheckpoint(task *models.JobTask, progress *models.JobProgress) (int64, bool) {
+	if task.IsRuleSplitTask || progress.RestorePoint == nil || progress.KeyspaceProcessed <= 0 {
+		return 0, false
+	}
+	return task.KeyspaceStart + progress.KeyspaceProcessed, true
+}
+
+// ApplyResumeOffset rewrites a progress update from an agent that resumed a
+// task from its checkpoint so it describes the task's whole keyspace. The
+// agent counts from the dispatched start, which lies task.ResumeOffset
+// candidates after task.KeyspaceStart; the candidates before it were processed
+// by an earlier run and count towards the chunk.
+func ApplyResumeOffset(task *models.JobTask, progress *models.JobProgress) {
+	offset := task.ResumeOffset
+	if offset <= 0 {
+		return
+	}
+	chunkSize := task.KeyspaceEnd - task.KeyspaceStart
+	dispatchedSize := chunkSize - offset
+	if dispatchedSize <= 0 {
+		return
+	}
+
+	progress.KeyspaceProcessed = clampInt64(progress.KeyspaceProcessed+offset, 0, chunkSize)
+
+	// The effective keyspace includes the rule multiplier, scale the offset by it
+	if progress.TotalEffectiveKeyspace != nil && *progress.TotalEffectiveKeyspace > 0 {
+		total := *progress.TotalEffectiveKeyspace
+		chunkTotal := int64(float64(total) * float64(chunkSize) / float64(dispatchedSize))
+		progress.TotalEffectiveKeyspace = &chunkTotal
+		progress.EffectiveProgress = clampInt64(progress.EffectiveProgress+chunkTotal-total, 0, chunkTotal)
+	} else if progress.EffectiveProgress > 0 && progress.ProgressPercent > 0 {
+		total := float64(progress.EffectiveProgress) * 100 / progress.ProgressPercent
+		effectiveOffset := int64(total * float64(offset) / float64(dispatchedSize))
+		progress.EffectiveProgress += effectiveOffset
+	}
+
+	processed := progress.ProgressPercent/100*float64(dispatchedSize) + float64(offset)
+	progress.ProgressPercent = clampFloat64(processed/float64(chunkSize)*100, 0, 100)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveResumeOffset(t *testing.T) {
+	task := &models.JobTask{KeyspaceStart: 5000, KeyspaceEnd: 6000}
+	checkpoint := func(v int64) *int64 { return &v }
+
+	assert.Equal(t, int64(0), ResolveResumeOffset(task, nil))
+	assert.Equal(t, int64(400), ResolveResumeOffset(task, checkpoint(5400)))
+	assert.Equal(t, int64(0), ResolveResumeOffset(task, checkpoint(5000)), "checkpoint at the start")
+	assert.Equal(t, int64(0), ResolveResumeOffset(task, checkpoint(6000)), "checkpoint at the end")
+	assert.Equal(t, int64(0), ResolveResumeOffset(&models.JobTask{KeyspaceStart: 5000, KeyspaceEnd: 6000, IsRuleSplitTask: true}, checkpoint(5400)))
+}
+
+func TestApplyResumeOffset(t *testing.T) {
+	// Chunk of 1000 candidates resumed 400 candidates in
+	task := &models.JobTask{KeyspaceStart: 5000, KeyspaceEnd: 6000, ResumeOffset: 400}
+
+	t.Run("first update scales the effective keyspace", func(t *testing.T) {
+		total := int64(6000) // 600 candidates x 10 rules
+		progress := &models.JobProgress{
+			KeyspaceProcessed:      300,
+			EffectiveProgress:      3000,
+			ProgressPercent:        50,
+			TotalEffectiveKeyspace: &total,
+		}
+
+		ApplyResumeOffset(task, progress)
+		assert.Equal(t, int64(700), progress.KeyspaceProcessed)
+		assert.Equal(t, int64(10000), *progress.TotalEffectiveKeyspace)
+		assert.Equal(t, int64(7000), progress.EffectiveProgress)
+		assert.InDelta(t, 70.0, progress.ProgressPercent, 0.001)
+	})
+
+	t.Run("later updates derive the total from the percentage", func(t *testing.T) {
+		progress := &models.JobProgress{KeyspaceProcessed: 600, EffectiveProgress: 6000, ProgressPercent: 100}
+
+		ApplyResumeOffset(task, progress)
+		assert.Equal(t, int64(1000), progress.KeyspaceProcessed)
+		assert.Equal(t, int64(10000), progress.EffectiveProgress)
+		assert.InDelta(t, 100.0, progress.ProgressPercent, 0.001)
+	})
+
+	t.Run("tasks that did not resume are untouched", func(t *testing.T) {
+		progress := &models.JobProgress{KeyspaceProcessed: 500, EffectiveProgress: 5000, ProgressPercent: 50}
+
+		ApplyResumeOffset(&models.JobTask{KeyspaceStart: 5000, KeyspaceEnd: 6000}, progress)
+		assert.Equal(t, int64(500), progress.KeyspaceProcessed)
+		assert.Equal(t, int64(5000), progress.EffectiveProgress)
+		assert.Equal(t, 50.0, progress.ProgressPercent)
+	})
+}
+
+func TestTaskCheckpoint(t *testing.T) {
+	task := &models.JobTask{KeyspaceStart: 5000, KeyspaceEnd: 6000}
+	restorePoint := int64(300)
+
+	checkpoint, ok := TaskCheckpoint(task, &models.JobProgress{KeyspaceProcessed: 700, RestorePoint: &restorePoint})
+	assert.True(t, ok)
+	assert.Equal(t, int64(5700), checkpoint)
+
+	_, ok = TaskCheckpoint(task, &models.JobProgress{KeyspaceProcessed: 700})
+	assert.False(t, ok, "no restore point reported")
+
+	_, ok = TaskCheckpoint(task, &models.JobProgress{RestorePoint: &restorePoint})
+	assert.False(t, ok, "restore point inside the overlap")
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// inheritStepPriority returns the priority and max agents of a follow-up step
+// of a workflow. The step keeps its own priority if that is higher than the
+// one it inherits, so a chain never loses priority from one step to the next.
+func inheritStepPriority(parent, step *models.JobExecution, inheritance models.WorkflowPriorityInheritance, maxPriority int) (int, int) {
+	if !inheritance.InheritPriority {
+		return step.Priority, step.MaxAgents
+	}
+	priority := parent.Priority + inheritance.PriorityBoost
+	if priority > maxPriority {
+		priority = maxPriority
+	}
+	if step.Priority > priority {
+		priority = step.Priority
+	}
+	return priority, parent.MaxAgents
+}
+
+// LinkWorkflowRun links the jobs created from the steps of a workflow, in step
+// order, to one workflow run. The scheduler runs the jobs of a run as a chain
+// and each follow-up step inherits the priority and max agents of the step
+// before it if the workflow asks for it.
+func (s *JobExecutionService) LinkWorkflowRun(ctx context.Context, workflow *models.JobWorkflow, jobs []*models.JobExecution) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	maxPriority, err := s.systemSettingsRepo.GetMaxJobPriority(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get max job priority: %w", err)
+	}
+
+	runID := uuid.New()
+	for i, job := range jobs {
+		if i > 0 {
+			job.Priority, job.MaxAgents = inheritStepPriority(jobs[i-1], job, workflow.WorkflowPriorityInheritance, maxPriority)
+		}
+		if err := s.jobExecRepo.SetWorkflowRun(ctx, job.ID, runID, i+1, job.Priority, job.MaxAgents); err != nil {
+			return fmt.Errorf("failed to link job %s to workflow run: %w", job.ID, err)
+		}
+	}
+
+	debug.Info("Linked %d jobs of workflow %s to run %s", len(jobs), workflow.Name, runID)
+	return nil
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInheritStepPriority(t *testing.T) {
+	parent := &models.JobExecution{Priority: 500, MaxAgents: 4}
+	step := &models.JobExecution{Priority: 100, MaxAgents: 0}
+
+	// Without inheritance the step keeps its own settings
+	priority, maxAgents := inheritStepPriority(parent, step, models.WorkflowPriorityInheritance{}, 1000)
+	assert.Equal(t, 100, priority)
+	assert.Equal(t, 0, maxAgents)
+
+	priority, maxAgents = inheritStepPriority(parent, step, models.WorkflowPriorityInheritance{InheritPriority: true}, 1000)
+	assert.Equal(t, 500, priority)
+	assert.Equal(t, 4, maxAgents)
+
+	priority, _ = inheritStepPriority(parent, step, models.WorkflowPriorityInheritance{InheritPriority: true, PriorityBoost: 10}, 1000)
+	assert.Equal(t, 510, priority)
+
+	// The boost is capped at the maximum priority
+	priority, _ = inheritStepPriority(parent, step, models.WorkflowPriorityInheritance{InheritPriority: true, PriorityBoost: 800}, 1000)
+	assert.Equal(t, 1000, priority)
+
+	// A step with a higher priority of its own keeps it
+	priority, _ = inheritStepPriority(parent, &models.JobExecution{Priority: 700}, models.WorkflowPriorityInheritance{InheritPriority: true}, 1000)
+	assert.Equal(t, 700, priority)
+}
@@ -0,0 +1,253 @@
+// Package jobstream pushes job and task progress to the UI. Progress reported
+// by agents is published to a Hub, which coalesces it per job and flushes it
+// once a second to the subscribers watching that job, so a dashboard following
+// many running jobs receives one message a second instead of polling each job.
+//
+// The hub lives in the backend process: subscribers only see progress from the
+// agents connected to the same backend.
+package jobstream
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+const (
+	// flushInterval is how often coalesced progress is sent to subscribers
+	flushInterval = time.Second
+	// MaxJobsPerSubscription caps how many jobs one subscription watches
+	MaxJobsPerSubscription = 200
+)
+
+// TaskProgress is the latest progress of a task
+type TaskProgress struct {
+	TaskID            uuid.UUID `json:"task_id"`
+	AgentID           *int      `json:"agent_id,omitempty"`
+	Status            string    `json:"status"`
+	KeyspaceProcessed int64     `json:"keyspace_processed"`
+	EffectiveProgress int64     `json:"effective_progress"`
+	ProgressPercent   float64   `json:"progress_percent"`
+	HashRate          int64     `json:"hash_rate"`
+	TimeRemaining     *int      `json:"time_remaining,omitempty"`
+}
+
+// JobUpdate is what changed in a job since the previous message
+type JobUpdate struct {
+	JobID uuid.UUID `json:"job_id"`
+	// Status is set when the job changed status
+	Status string `json:"status,omitempty"`
+	// Cracked is how many hashes were cracked by the job since the previous message
+	Cracked int `json:"cracked,omitempty"`
+	// Tasks holds the latest progress of the tasks that reported any
+	Tasks []TaskProgress `json:"tasks,omitempty"`
+}
+
+// Message is one flush of coalesced progress to a subscriber
+type Message struct {
+	Jobs []JobUpdate `json:"jobs"`
+	// Resync is set when an earlier message was dropped because the subscriber
+	// fell behind, and its jobs should be fetched again
+	Resync bool `json:"resync,omitempty"`
+}
+
+// pendingJob is the progress of a job accumulated since the last flush
+type pendingJob struct {
+	status  string
+	cracked int
+	tasks   map[uuid.UUID]TaskProgress
+}
+
+// Subscription receives the progress of the jobs it watches
+type Subscription struct {
+	hub      *Hub
+	jobs     map[uuid.UUID]struct{}
+	messages chan Message
+}
+
+// Messages returns the channel messages are delivered on. Only the latest
+// undelivered message is kept.
+func (s *Subscription) Messages() <-chan Message {
+	return s.messages
+}
+
+// Close stops the subscription
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// Hub coalesces job progress and fans it out to subscriptions
+type Hub struct {
+	mu          sync.Mutex
+	pending     map[uuid.UUID]*pendingJob
+	subscribers map[*Subscription]struct{}
+	// watchers counts the subscriptions of each job, progress of jobs nobody
+	// watches is dropped when it is published
+	watchers map[uuid.UUID]int
+}
+
+// NewHub creates a new Hub
+func NewHub() *Hub {
+	return &Hub{
+		pending:     make(map[uuid.UUID]*pendingJob),
+		subscribers: make(map[*Subscription]struct{}),
+		watchers:    make(map[uuid.UUID]int),
+	}
+}
+
+// Subscribe starts watching jobs
+func (h *Hub) Subscribe(jobIDs []uuid.UUID) *Subscription {
+	sub := &Subscription{
+		hub:      h,
+		jobs:     make(map[uuid.UUID]struct{}, len(jobIDs)),
+		messages: make(chan Message, 1),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, id := range jobIDs {
+		if _, ok := sub.jobs[id]; ok {
+			continue
+		}
+		sub.jobs[id] = struct{}{}
+		h.watchers[id]++
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// unsubscribe removes a subscription
+func (h *Hub) unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	for id := range sub.jobs {
+		if h.watchers[id]--; h.watchers[id] <= 0 {
+			delete(h.watchers, id)
+			delete(h.pending, id)
+		}
+	}
+}
+
+// PublishTask records the latest progress of a task of a job
+func (h *Hub) PublishTask(jobID uuid.UUID, task TaskProgress, cracked int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	job := h.pendingJob(jobID)
+	if job == nil {
+		return
+	}
+	job.tasks[task.TaskID] = task
+	job.cracked += cracked
+}
+
+// PublishJobStatus records that a job changed status
+func (h *Hub) PublishJobStatus(jobID uuid.UUID, status models.JobExecutionStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if job := h.pendingJob(jobID); job != nil {
+		job.status = string(status)
+	}
+}
+
+// pendingJob returns the pending progress of a watched job, nil when nobody
+// watches it. The caller holds the lock.
+func (h *Hub) pendingJob(jobID uuid.UUID) *pendingJob {
+	if h.watchers[jobID] == 0 {
+		return nil
+	}
+	job, ok := h.pending[jobID]
+	if !ok {
+		job = &pendingJob{tasks: make(map[uuid.UUID]TaskProgress)}
+		h.pending[jobID] = job
+	}
+	return job
+}
+
+// Start flushes coalesced progress to subscribers until ctx is done
+func (h *Hub) Start(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			debug.Info("Job progress stream stopped")
+			return
+		case <-ticker.C:
+			h.flush()
+		}
+	}
+}
+
+// flush sends each subscriber the pending progress of the jobs it watches
+func (h *Hub) flush() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.pending) == 0 {
+		return
+	}
+
+	updates := make(map[uuid.UUID]JobUpdate, len(h.pending))
+	for id, job := range h.pending {
+		update := JobUpdate{JobID: id, Status: job.status, Cracked: job.cracked}
+		for _, task := range job.tasks {
+			update.Tasks = append(update.Tasks, task)
+		}
+		updates[id] = update
+	}
+	h.pending = make(map[uuid.UUID]*pendingJob)
+
+	for sub := range h.subscribers {
+		var msg Message
+		for id := range sub.jobs {
+			if update, ok := updates[id]; ok {
+				msg.Jobs = append(msg.Jobs, update)
+			}
+		}
+		if len(msg.Jobs) == 0 {
+			continue
+		}
+
+		select {
+		case sub.messages <- msg:
+		default:
+			// The subscriber has not read the previous message yet. Replace it,
+			// flush is the only sender so the send cannot block once it is gone.
+			select {
+			case <-sub.messages:
+			default:
+			}
+			msg.Resync = true
+			sub.messages <- msg
+		}
+	}
+}
+
+// SubscribeEvents registers the hub with the event bus for job status changes
+// that are not reported through task progress
+func (h *Hub) SubscribeEvents(bus *events.Bus) {
+	bus.Subscribe(events.JobCompleted, "jobstream.job_completed", func(ctx context.Context, event *events.Event) error {
+		var payload events.JobCompletedPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		h.PublishJobStatus(payload.JobExecutionID, models.JobExecutionStatusCompleted)
+		return nil
+	})
+	bus.Subscribe(events.JobSuperseded, "jobstream.job_superseded", func(ctx context.Context, event *events.Event) error {
+		var payload events.JobSupersededPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		h.PublishJobStatus(payload.JobExecutionID, models.JobExecutionStatusSuperseded)
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package jobstream
+
+import (
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receive returns the pending message of a subscription
+func receive(t *testing.T, sub *Subscription) Message {
+	select {
+	case msg := <-sub.Messages():
+		return msg
+	default:
+		t.Fatal("no message delivered")
+		return Message{}
+	}
+}
+
+func TestHubCoalescesTaskProgress(t *testing.T) {
+	hub := NewHub()
+	jobID, taskID := uuid.New(), uuid.New()
+	sub := hub.Subscribe([]uuid.UUID{jobID})
+	defer sub.Close()
+
+	hub.PublishTask(jobID, TaskProgress{TaskID: taskID, ProgressPercent: 10}, 2)
+	hub.PublishTask(jobID, TaskProgress{TaskID: taskID, ProgressPercent: 20}, 3)
+	hub.PublishJobStatus(jobID, models.JobExecutionStatusFailed)
+	hub.flush()
+
+	msg := receive(t, sub)
+	require.Len(t, msg.Jobs, 1)
+	assert.Equal(t, jobID, msg.Jobs[0].JobID)
+	assert.Equal(t, "failed", msg.Jobs[0].Status)
+	assert.Equal(t, 5, msg.Jobs[0].Cracked)
+	require.Len(t, msg.Jobs[0].Tasks, 1)
+	assert.Equal(t, 20.0, msg.Jobs[0].Tasks[0].ProgressPercent)
+	assert.False(t, msg.Resync)
+
+	// Nothing changed since
+	hub.flush()
+	assert.Empty(t, sub.Messages())
+}
+
+func TestHubOnlyDeliversWatchedJobs(t *testing.T) {
+	hub := NewHub()
+	watched, other := uuid.New(), uuid.New()
+	sub := hub.Subscribe([]uuid.UUID{watched})
+
+	hub.PublishTask(other, TaskProgress{TaskID: uuid.New()}, 0)
+	assert.Empty(t, hub.pending)
+
+	hub.PublishTask(watched, TaskProgress{TaskID: uuid.New()}, 0)
+	hub.flush()
+	msg := receive(t, sub)
+	require.Len(t, msg.Jobs, 1)
+	assert.Equal(t, watched, msg.Jobs[0].JobID)
+
+	sub.Close()
+	assert.Empty(t, hub.watchers)
+	hub.PublishTask(watched, TaskProgress{TaskID: uuid.New()}, 0)
+	assert.Empty(t, hub.pending)
+}
+
+func TestHubReplacesUnreadMessageWithResync(t *testing.T) {
+	hub := NewHub()
+	jobID := uuid.New()
+	sub := hub.Subscribe([]uuid.UUID{jobID})
+	defer sub.Close()
+
+	hub.PublishTask(jobID, TaskProgress{TaskID: uuid.New(), ProgressPercent: 10}, 0)
+	hub.flush()
+	hub.PublishTask(jobID, TaskProgress{TaskID: uuid.New(), ProgressPercent: 30}, 0)
+	hub.flush()
+
+	msg := receive(t, sub)
+	assert.True(t, msg.Resync)
+	assert.Equal(t, 30.0, msg.Jobs[0].Tasks[0].ProgressPercent)
+	assert.Empty(t, sub.Messages())
+}
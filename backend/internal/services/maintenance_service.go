@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrMaintenanceMode is returned when a job is created during maintenance
+	ErrMaintenanceMode = errors.New("system is in maintenance mode")
+	// ErrMaintenanceActive is returned when maintenance is started while a
+	// window is already open
+	ErrMaintenanceActive = errors.New("maintenance mode is already active")
+)
+
+// MaintenanceService switches system-wide maintenance mode. Its state is kept
+// in the database so every backend instance sees the same window.
+type MaintenanceService struct {
+	repo *repository.MaintenanceRepository
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(repo *repository.MaintenanceRepository) *MaintenanceService {
+	return &MaintenanceService{repo: repo}
+}
+
+// Active returns the open maintenance window, or nil outside maintenance
+func (s *MaintenanceService) Active(ctx context.Context) (*models.MaintenanceWindow, error) {
+	return s.repo.GetActive(ctx)
+}
+
+// Start enters maintenance mode. New task dispatch and job creation stop at
+// once; running tasks are stopped after the drain timeout, if one is given.
+// The request must have been validated.
+func (s *MaintenanceService) Start(ctx context.Context, req models.StartMaintenanceRequest, adminID uuid.UUID) (*models.MaintenanceWindow, error) {
+	window, err := s.repo.Start(ctx, req, adminID)
+	if errors.Is(err, repository.ErrDuplicateRecord) {
+		return nil, ErrMaintenanceActive
+	}
+	if err != nil {
+		return nil, err
+	}
+	debug.Info("Maintenance window %d started by %s (drain timeout %ds): %s",
+		window.ID, window.StartedByUsername, window.DrainTimeoutSeconds, window.Message)
+	return window, nil
+}
+
+// End leaves maintenance mode, or returns repository.ErrNotFound outside it
+func (s *MaintenanceService) End(ctx context.Context, adminID uuid.UUID) (*models.MaintenanceWindow, error) {
+	window, err := s.repo.End(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	debug.Info("Maintenance window %d ended by %s", window.ID, adminID)
+	return window, nil
+}
+
+// List returns the most recent maintenance windows, newest first
+func (s *MaintenanceService) List(ctx context.Context, limit int) ([]models.MaintenanceWindow, error) {
+	return s.repo.List(ctx, limit)
+}
+
+// CheckJobCreation returns an error wrapping ErrMaintenanceMode, with the
+// window's message, while maintenance mode is active
+func (s *MaintenanceService) CheckJobCreation(ctx context.Context) error {
+	window, err := s.repo.GetActive(ctx)
+	if err != nil {
+		return err
+	}
+	if window != nil {
+		return fmt.Errorf("%w: %s", ErrMaintenanceMode, window.Message)
+	}
+	return nil
+}
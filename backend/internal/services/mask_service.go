@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashcatmask"
+)
+
+// ErrInvalidCustomCharset is returned for a library charset without a usable
+// name or a charset reference that cannot be resolved
+var ErrInvalidCustomCharset = errors.New("invalid custom charset")
+
+// MaskService validates masks and manages the custom charset library
+type MaskService struct {
+	charsetRepo *repository.CustomCharsetRepository
+}
+
+// NewMaskService creates a new mask service
+func NewMaskService(charsetRepo *repository.CustomCharsetRepository) *MaskService {
+	return &MaskService{charsetRepo: charsetRepo}
+}
+
+// Validate checks a mask and its custom charsets and returns its positions
+// and keyspace. Problems with the mask are reported in the result, errors
+// are only returned when the validation itself failed.
+func (s *MaskService) Validate(ctx context.Context, req *models.MaskValidationRequest) (*models.MaskValidationResult, error) {
+	line, err := hashcatmask.ParseLine(req.Mask)
+	if err != nil {
+		return &models.MaskValidationResult{Error: err.Error()}, nil
+	}
+
+	if len(req.CustomCharsets) > 0 {
+		if len(line.Charsets) > 0 {
+			return &models.MaskValidationResult{
+				Error: "custom charsets are defined both in the mask and in custom_charsets",
+			}, nil
+		}
+		line.Charsets = make([]string, len(req.CustomCharsets))
+		for i, ref := range req.CustomCharsets {
+			charset, err := s.resolveCharset(ctx, ref)
+			if err != nil {
+				if errors.Is(err, ErrInvalidCustomCharset) {
+					return &models.MaskValidationResult{Error: fmt.Sprintf("custom charset ?%d: %v", i+1, err)}, nil
+				}
+				return nil, err
+			}
+			line.Charsets[i] = charset
+		}
+	}
+
+	analysis, err := hashcatmask.Analyze(line, hashcatmask.Bounds{MinLength: req.MinLength, MaxLength: req.MaxLength})
+	if err != nil {
+		return &models.MaskValidationResult{Error: err.Error()}, nil
+	}
+
+	return &models.MaskValidationResult{
+		Valid:          true,
+		Line:           line.String(),
+		CustomCharsets: line.Charsets,
+		Length:         analysis.Length,
+		Positions:      analysis.Positions,
+		Keyspace:       analysis.Keyspace,
+	}, nil
+}
+
+// resolveCharset returns an inline charset or looks one up in the library
+func (s *MaskService) resolveCharset(ctx context.Context, ref models.MaskCharsetRef) (string, error) {
+	if ref.LibraryID == nil {
+		return ref.Charset, nil
+	}
+	if ref.Charset != "" {
+		return "", fmt.Errorf("%w: set either charset or library_id", ErrInvalidCustomCharset)
+	}
+
+	charset, err := s.charsetRepo.GetByID(ctx, *ref.LibraryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", fmt.Errorf("%w: library charset %d does not exist", ErrInvalidCustomCharset, *ref.LibraryID)
+	}
+	if err != nil {
+		return "", err
+	}
+	return charset.Charset, nil
+}
+
+// ListCharsets returns the charset library with the size of each charset
+func (s *MaskService) ListCharsets(ctx context.Context) ([]models.CustomCharset, error) {
+	charsets, err := s.charsetRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range charsets {
+		charsets[i].Size, _ = hashcatmask.CharsetSize(charsets[i].Charset)
+	}
+	return charsets, nil
+}
+
+// CreateCharset validates and adds a charset to the library
+func (s *MaskService) CreateCharset(ctx context.Context, charset *models.CustomCharset) error {
+	if err := validateCustomCharset(charset); err != nil {
+		return err
+	}
+	return s.charsetRepo.Create(ctx, charset)
+}
+
+// UpdateCharset validates and saves a library charset. Jobs keep the
+// charsets they were created with.
+func (s *MaskService) UpdateCharset(ctx context.Context, charset *models.CustomCharset) error {
+	if err := validateCustomCharset(charset); err != nil {
+		return err
+	}
+	return s.charsetRepo.Update(ctx, charset)
+}
+
+// DeleteCharset removes a charset from the library
+func (s *MaskService) DeleteCharset(ctx context.Context, id int) error {
+	return s.charsetRepo.Delete(ctx, id)
+}
+
+// validateCustomCharset trims the charset's name and checks the charset is
+// one hashcat accepts, setting its size. Invalid charsets return an error
+// wrapping hashcatmask.ErrInvalidCharset.
+func validateCustomCharset(charset *models.CustomCharset) error {
+	charset.Name = strings.TrimSpace(charset.Name)
+	if charset.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCustomCharset)
+	}
+	if len(charset.Name) > 100 {
+		return fmt.Errorf("%w: name is longer than 100 characters", ErrInvalidCustomCharset)
+	}
+	if len(charset.Charset) > 512 {
+		return fmt.Errorf("%w: charset is longer than 512 characters", ErrInvalidCustomCharset)
+	}
+
+	size, err := hashcatmask.CharsetSize(charset.Charset)
+	if err != nil {
+		return err
+	}
+	charset.Size = size
+	return nil
+}
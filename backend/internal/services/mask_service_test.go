@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskServiceValidate(t *testing.T) {
+	service := NewMaskService(nil)
+	ctx := context.Background()
+
+	result, err := service.Validate(ctx, &models.MaskValidationRequest{
+		Mask:           "?u?1?1?d",
+		CustomCharsets: []models.MaskCharsetRef{{Charset: "?l,"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Valid, result.Error)
+	assert.Equal(t, "?l\\,,?u?1?1?d", result.Line)
+	assert.Equal(t, 4, result.Length)
+	assert.Equal(t, int64(26*27*27*10), result.Keyspace)
+
+	// The line returned for a job validates the same way
+	again, err := service.Validate(ctx, &models.MaskValidationRequest{Mask: result.Line})
+	require.NoError(t, err)
+	assert.Equal(t, result.Keyspace, again.Keyspace)
+
+	invalid := []*models.MaskValidationRequest{
+		{Mask: "?1?d"},
+		{Mask: "?l,?1", CustomCharsets: []models.MaskCharsetRef{{Charset: "?d"}}},
+		{Mask: "?d?d", MinLength: 3},
+		{Mask: "?1", CustomCharsets: []models.MaskCharsetRef{{Charset: "?q"}}},
+	}
+	for _, req := range invalid {
+		result, err := service.Validate(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, result.Valid, req.Mask)
+		assert.NotEmpty(t, result.Error, req.Mask)
+	}
+}
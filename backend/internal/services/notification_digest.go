@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// digestCheckInterval is how often the digest scheduler looks for due digests
+const digestCheckInterval = 5 * time.Minute
+
+// digestSections render the sections of a digest as plain text lines, which
+// the notification_digest email template places in its HTML and text bodies
+var digestSections = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+}).Parse(`
+{{- define "jobs" }}{{ range . }}- {{ .Name }} on {{ .HashlistName }}: {{ .Status }} at {{ time .CompletedAt }}
+{{ else }}No jobs finished.
+{{ end }}{{ end }}
+{{- define "cracks" }}{{ range . }}- {{ .HashlistName }}: {{ .Count }} cracked
+{{ else }}No new cracks.
+{{ end }}{{ end }}
+{{- define "incidents" }}{{ range . }}- {{ .AgentName }} (#{{ .AgentID }}) {{ .Kind }} at {{ time .OccurredAt }}{{ if .Detail }}: {{ .Detail }}{{ end }}
+{{ else }}No agent incidents.
+{{ end }}{{ end }}`))
+
+// renderDigest returns the email template data of a digest
+func renderDigest(digest *models.NotificationDigest, mode string) (map[string]interface{}, error) {
+	render := func(section string, data interface{}) (string, error) {
+		var out strings.Builder
+		if err := digestSections.ExecuteTemplate(&out, section, data); err != nil {
+			return "", fmt.Errorf("failed to render digest %s: %w", section, err)
+		}
+		return strings.TrimRight(out.String(), "\n"), nil
+	}
+
+	jobs, err := render("jobs", digest.Jobs)
+	if err != nil {
+		return nil, err
+	}
+	cracks, err := render("cracks", digest.Cracks)
+	if err != nil {
+		return nil, err
+	}
+	incidents, err := render("incidents", digest.Incidents)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"Period":           mode,
+		"Since":            digest.Since.UTC().Format(time.RFC1123),
+		"Until":            digest.Until.UTC().Format(time.RFC1123),
+		"JobCount":         len(digest.Jobs),
+		"CrackCount":       digest.CrackCount(),
+		"IncidentCount":    len(digest.Incidents),
+		"JobsSummary":      jobs,
+		"CracksSummary":    cracks,
+		"IncidentsSummary": incidents,
+	}, nil
+}
+
+// StartDigestScheduler sends the users' due notification digests until ctx
+// is cancelled. Several backends may run it, each digest is claimed by one.
+func (s *NotificationService) StartDigestScheduler(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(digestCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			s.SendDueDigests(ctx)
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				debug.Info("Notification digest scheduler stopped")
+				return
+			}
+		}
+	}()
+}
+
+// SendDueDigests sends the digest of every user whose digest period has
+// ended. A period without any activity is skipped without an email.
+func (s *NotificationService) SendDueDigests(ctx context.Context) {
+	now := time.Now()
+	recipients, err := s.digestRepo.ListDue(ctx, now)
+	if err != nil {
+		debug.Error("Failed to list due notification digests: %v", err)
+		return
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	hasEmailProvider, err := s.db.HasActiveEmailProvider()
+	if err != nil {
+		debug.Error("Failed to check email provider for notification digests: %v", err)
+		return
+	}
+	if !hasEmailProvider {
+		debug.Warning("No active email provider configured, skipping %d notification digests", len(recipients))
+		return
+	}
+
+	for _, recipient := range recipients {
+		if err := s.sendDigest(ctx, recipient, now); err != nil {
+			debug.Error("Failed to send notification digest to user %s: %v", recipient.UserID, err)
+		}
+	}
+}
+
+// sendDigest claims and sends one user's digest ending at until. The claim
+// is released when sending fails, so the digest is retried.
+func (s *NotificationService) sendDigest(ctx context.Context, recipient models.DigestRecipient, until time.Time) error {
+	// A digest claimed by another backend is left to it
+	_, err := s.digestRepo.Claim(ctx, recipient.UserID, recipient.LastSentAt, until, func() error {
+		return s.deliverDigest(ctx, recipient, until)
+	})
+	return err
+}
+
+// deliverDigest builds and emails one user's digest ending at until
+func (s *NotificationService) deliverDigest(ctx context.Context, recipient models.DigestRecipient, until time.Time) error {
+	digest, err := s.digestRepo.Build(ctx, recipient.UserID, recipient.LastSentAt, until)
+	if err != nil {
+		return err
+	}
+	if digest.Empty() {
+		debug.Log("Skipping empty notification digest", map[string]interface{}{
+			"user_id": recipient.UserID,
+		})
+		return nil
+	}
+
+	data, err := renderDigest(digest, recipient.Mode)
+	if err != nil {
+		return err
+	}
+	tmpl, err := s.emailService.GetTemplateByType(ctx, "notification_digest")
+	if err != nil {
+		return fmt.Errorf("failed to get email template: %w", err)
+	}
+	if err := s.emailService.SendTemplatedEmail(ctx, recipient.Email, tmpl.ID, data); err != nil {
+		return fmt.Errorf("failed to send notification digest: %w", err)
+	}
+
+	debug.Log("Notification digest sent", map[string]interface{}{
+		"recipient": recipient.Email,
+		"jobs":      len(digest.Jobs),
+		"cracks":    digest.CrackCount(),
+		"incidents": len(digest.Incidents),
+	})
+	return nil
+}
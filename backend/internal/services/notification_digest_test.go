@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDigest(t *testing.T) {
+	until := time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC)
+	digest := &models.NotificationDigest{
+		Since: until.Add(-24 * time.Hour),
+		Until: until,
+		Jobs: []models.DigestJob{
+			{Name: "NTLM rockyou", HashlistName: "corp-dc", Status: models.JobExecutionStatusCompleted, CompletedAt: until.Add(-time.Hour)},
+		},
+		Cracks: []models.DigestCracks{
+			{HashlistID: 1, HashlistName: "corp-dc", Count: 40},
+			{HashlistID: 2, HashlistName: "web", Count: 2},
+		},
+	}
+
+	data, err := renderDigest(digest, models.NotificationDigestDaily)
+	require.NoError(t, err)
+
+	assert.Equal(t, "daily", data["Period"])
+	assert.Equal(t, 1, data["JobCount"])
+	assert.Equal(t, 42, data["CrackCount"])
+	assert.Equal(t, 0, data["IncidentCount"])
+	assert.Equal(t, "- NTLM rockyou on corp-dc: completed at 2024-05-02 07:00 UTC", data["JobsSummary"])
+	assert.Equal(t, "- corp-dc: 40 cracked\n- web: 2 cracked", data["CracksSummary"])
+	assert.Equal(t, "No agent incidents.", data["IncidentsSummary"])
+
+	digest.Incidents = []models.DigestAgentIncident{
+		{AgentID: 3, AgentName: "rig-1", Kind: "offline", Detail: "heartbeat timeout", OccurredAt: until.Add(-2 * time.Hour)},
+	}
+	data, err = renderDigest(digest, models.NotificationDigestDaily)
+	require.NoError(t, err)
+	assert.Equal(t, "- rig-1 (#3) offline at 2024-05-02 06:00 UTC: heartbeat timeout", data["IncidentsSummary"])
+}
+
+func TestValidateNotificationDigest(t *testing.T) {
+	assert.NoError(t, models.ValidateNotificationDigest(models.NotificationDigestHourly))
+	assert.Error(t, models.ValidateNotificationDigest("weekly"))
+}
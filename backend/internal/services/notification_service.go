@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
 	emailPkg "github.com/ZerkerEOD/krakenhashes/backend/internal/email"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
@@ -21,6 +23,7 @@ type NotificationService struct {
 	jobExecRepo      *repository.JobExecutionRepository
 	hashlistRepo     *repository.HashListRepository
 	emailService     *emailPkg.Service
+	digestRepo       *repository.NotificationDigestRepository
 }
 
 // NewNotificationService creates a new NotificationService
@@ -32,6 +35,7 @@ func NewNotificationService(dbConn *sql.DB) *NotificationService {
 		jobExecRepo:  repository.NewJobExecutionRepository(database),
 		hashlistRepo: repository.NewHashListRepository(database),
 		emailService: emailPkg.NewService(dbConn),
+		digestRepo:   repository.NewNotificationDigestRepository(database),
 	}
 }
 
@@ -51,6 +55,19 @@ func (s *NotificationService) SendJobCompletionEmail(ctx context.Context, jobExe
 		return nil
 	}
 
+	// Users on a digest get their completed jobs in the next digest instead
+	digestMode, err := s.digestRepo.GetMode(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get notification digest: %w", err)
+	}
+	if digestMode != models.NotificationDigestOff {
+		debug.Log("User receives job completions in a digest", map[string]interface{}{
+			"user_id": userID,
+			"digest":  digestMode,
+		})
+		return nil
+	}
+
 	// Check if email provider is configured
 	hasEmailProvider, err := s.db.HasActiveEmailProvider()
 	if err != nil {
@@ -157,8 +174,14 @@ func (s *NotificationService) GetUserNotificationPreferences(ctx context.Context
 		return nil, fmt.Errorf("failed to check email provider: %w", err)
 	}
 
+	digestMode, err := s.digestRepo.GetMode(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification digest: %w", err)
+	}
+
 	prefs := &models.NotificationPreferences{
 		NotifyOnJobCompletion: user.NotifyOnJobCompletion,
+		NotificationDigest:    digestMode,
 		EmailConfigured:       hasEmailProvider,
 	}
 
@@ -174,8 +197,14 @@ func (s *NotificationService) GetUserNotificationPreferences(ctx context.Context
 
 // UpdateUserNotificationPreferences updates the notification preferences for a user
 func (s *NotificationService) UpdateUserNotificationPreferences(ctx context.Context, userID uuid.UUID, prefs *models.NotificationPreferences) error {
+	if prefs.NotificationDigest != "" {
+		if err := models.ValidateNotificationDigest(prefs.NotificationDigest); err != nil {
+			return err
+		}
+	}
+
 	// Check if email provider is configured when enabling notifications
-	if prefs.NotifyOnJobCompletion {
+	if prefs.NotifyOnJobCompletion || (prefs.NotificationDigest != "" && prefs.NotificationDigest != models.NotificationDigestOff) {
 		hasEmailProvider, err := s.db.HasActiveEmailProvider()
 		if err != nil {
 			return fmt.Errorf("failed to check email provider: %w", err)
@@ -195,10 +224,164 @@ func (s *NotificationService) UpdateUserNotificationPreferences(ctx context.Cont
 	if err != nil {
 		return fmt.Errorf("failed to update notification preferences: %w", err)
 	}
+	if prefs.NotificationDigest != "" {
+		if err := s.digestRepo.SetMode(ctx, userID, prefs.NotificationDigest); err != nil {
+			return fmt.Errorf("failed to update notification digest: %w", err)
+		}
+	}
 
 	debug.Log("Successfully updated notification preferences", map[string]interface{}{
 		"user_id": userID,
 	})
 
 	return nil
-}
\ No newline at end of file
+}
+// SubscribeEvents registers the notification handlers on the event bus
+func (s *NotificationService) SubscribeEvents(bus *events.Bus) {
+	bus.Subscribe(events.JobCompleted, "notification.job_completion_email", s.handleJobCompleted)
+	bus.Subscribe(events.JobSuperseded, "notification.job_superseded_email", s.handleJobSuperseded)
+	bus.Subscribe(events.PrivilegedAccountCracked, "notification.privileged_account_email", s.handlePrivilegedAccountCracked)
+	bus.Subscribe(events.ChunkVerificationFailed, "notification.chunk_verification_email", s.handleChunkVerificationFailed)
+	bus.Subscribe(events.BinaryIntegrityFailed, "notification.binary_integrity_email", s.handleBinaryIntegrityFailed)
+}
+
+// handleJobCompleted sends the job completion email to the user who created the job
+func (s *NotificationService) handleJobCompleted(ctx context.Context, event *events.Event) error {
+	var payload events.JobCompletedPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	if payload.CreatedBy == nil {
+		return nil
+	}
+	return s.SendJobCompletionEmail(ctx, payload.JobExecutionID, *payload.CreatedBy)
+}
+
+// handleJobSuperseded tells the user who created a queued job that it will not
+// run because its hashlist was fully cracked, with the job completion email
+func (s *NotificationService) handleJobSuperseded(ctx context.Context, event *events.Event) error {
+	var payload events.JobSupersededPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	if payload.CreatedBy == nil {
+		return nil
+	}
+	return s.SendJobCompletionEmail(ctx, payload.JobExecutionID, *payload.CreatedBy)
+}
+
+// handlePrivilegedAccountCracked alerts the owner of a hashlist that privileged
+// accounts were cracked, with the security event email
+func (s *NotificationService) handlePrivilegedAccountCracked(ctx context.Context, event *events.Event) error {
+	var payload events.PrivilegedAccountCrackedPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	hasEmailProvider, err := s.db.HasActiveEmailProvider()
+	if err != nil {
+		return fmt.Errorf("failed to check email provider: %w", err)
+	}
+	if !hasEmailProvider {
+		debug.Warning("No active email provider configured, skipping privileged account alert for hashlist %d", payload.HashlistID)
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, payload.OwnerID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	tmpl, err := s.emailService.GetTemplateByType(ctx, "security_event")
+	if err != nil {
+		return fmt.Errorf("failed to get email template: %w", err)
+	}
+
+	templateData := map[string]interface{}{
+		"EventType": "Privileged account cracked",
+		"Timestamp": event.CreatedAt.UTC().Format(time.RFC1123),
+		"Details": fmt.Sprintf("Hashlist %q has cracked privileged accounts: %s",
+			payload.HashlistName, strings.Join(payload.Accounts, ", ")),
+		"IPAddress": "n/a",
+	}
+	if err := s.emailService.SendTemplatedEmail(ctx, user.Email, tmpl.ID, templateData); err != nil {
+		return fmt.Errorf("failed to send privileged account alert: %w", err)
+	}
+
+	debug.Log("Privileged account alert sent", map[string]interface{}{
+		"recipient":   user.Email,
+		"hashlist_id": payload.HashlistID,
+	})
+	return nil
+}
+
+// handleChunkVerificationFailed alerts every admin that a re-run chunk
+// recovered hashes its original agent missed, with the security event email
+func (s *NotificationService) handleChunkVerificationFailed(ctx context.Context, event *events.Event) error {
+	var payload events.ChunkVerificationFailedPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("Agent %q reported chunk %s of job %s as done, but a re-run on another agent cracked %d hashes it missed.",
+		payload.AgentName, payload.TaskID, payload.JobExecutionID, payload.MissedCracks)
+	if payload.AgentQuarantined {
+		details += " The agent has been disabled until an admin re-enables it."
+	}
+	return s.alertAdmins(ctx, event, "Chunk verification failed", details)
+}
+
+// handleBinaryIntegrityFailed alerts every admin that an agent's hashcat
+// binary did not match the one recorded by the binary manager
+func (s *NotificationService) handleBinaryIntegrityFailed(ctx context.Context, event *events.Event) error {
+	var payload events.BinaryIntegrityFailedPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("Agent %q refused task %s of job %s because its hashcat binary %s has SHA-256 %s, expected %s. The agent has been disabled until an admin re-enables it.",
+		payload.AgentName, payload.TaskID, payload.JobExecutionID, payload.Executable, payload.Actual, payload.Expected)
+	return s.alertAdmins(ctx, event, "Hashcat binary integrity check failed", details)
+}
+
+// alertAdmins emails a security alert about an event to every admin
+func (s *NotificationService) alertAdmins(ctx context.Context, event *events.Event, eventType, details string) error {
+	hasEmailProvider, err := s.db.HasActiveEmailProvider()
+	if err != nil {
+		return fmt.Errorf("failed to check email provider: %w", err)
+	}
+	if !hasEmailProvider {
+		debug.Warning("No active email provider configured, skipping %s alert for event %d", event.Type, event.ID)
+		return nil
+	}
+
+	admins, err := s.userRepo.List(ctx, map[string]interface{}{"role": "admin"})
+	if err != nil {
+		return fmt.Errorf("failed to list admins: %w", err)
+	}
+
+	tmpl, err := s.emailService.GetTemplateByType(ctx, "security_event")
+	if err != nil {
+		return fmt.Errorf("failed to get email template: %w", err)
+	}
+
+	templateData := map[string]interface{}{
+		"EventType": eventType,
+		"Timestamp": event.CreatedAt.UTC().Format(time.RFC1123),
+		"Details":   details,
+		"IPAddress": "n/a",
+	}
+	// One failed recipient does not retry the event, the others already have it
+	for _, admin := range admins {
+		if err := s.emailService.SendTemplatedEmail(ctx, admin.Email, tmpl.ID, templateData); err != nil {
+			debug.Error("Failed to send %s alert to %s: %v", event.Type, admin.Username, err)
+		}
+	}
+
+	debug.Log("Admin alert sent", map[string]interface{}{
+		"event_type": event.Type,
+		"event_id":   event.ID,
+		"recipients": len(admins),
+	})
+	return nil
+}
@@ -119,7 +119,7 @@ func (s *PotfileService) StagePassword(ctx context.Context, password, hashValue
 		return fmt.Errorf("failed to stage password: %w", err)
 	}
 	
-	debug.Debug("Staged password for hash %s", hashValue)
+	debug.Debug("Staged password for hash %s", debug.Hash(hashValue))
 	return nil
 }
 
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/events"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// PrivilegedAccountService flags cracked privileged accounts as soon as they
+// are cracked: the hashlist is tagged and a PrivilegedAccountCracked event
+// raises the alert, instead of the finding waiting for the final report
+type PrivilegedAccountService struct {
+	db                 *db.DB
+	accountRepo        *repository.HashlistAccountRepository
+	hashlistRepo       *repository.HashListRepository
+	systemSettingsRepo *repository.SystemSettingsRepository
+}
+
+// NewPrivilegedAccountService creates a new privileged account service
+func NewPrivilegedAccountService(database *db.DB) *PrivilegedAccountService {
+	return &PrivilegedAccountService{
+		db:                 database,
+		accountRepo:        repository.NewHashlistAccountRepository(database),
+		hashlistRepo:       repository.NewHashListRepository(database),
+		systemSettingsRepo: repository.NewSystemSettingsRepository(database),
+	}
+}
+
+// SubscribeEvents registers the handler that checks a hashlist's privileged
+// accounts after cracks were stored
+func (s *PrivilegedAccountService) SubscribeEvents(bus *events.Bus) {
+	bus.Subscribe(events.HashCracked, "privileged_account.flag_cracked", func(ctx context.Context, event *events.Event) error {
+		var payload events.HashCrackedPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		return s.CheckHashlist(ctx, payload.HashlistID)
+	})
+}
+
+// PrivilegedGroups returns the privileged_groups setting
+func (s *PrivilegedAccountService) PrivilegedGroups(ctx context.Context) []string {
+	setting, err := s.systemSettingsRepo.GetSetting(ctx, models.PrivilegedGroupsSetting)
+	if err != nil || setting.Value == nil {
+		return models.ParsePrivilegedGroups(models.DefaultPrivilegedGroups)
+	}
+	return models.ParsePrivilegedGroups(*setting.Value)
+}
+
+// CheckHashlist flags the cracked privileged accounts of a hashlist that were
+// not flagged before. Flagging, tagging and publishing the event share a
+// transaction, so each account raises exactly one alert.
+func (s *PrivilegedAccountService) CheckHashlist(ctx context.Context, hashlistID int64) error {
+	hashlist, err := s.hashlistRepo.GetByID(ctx, hashlistID)
+	if err != nil {
+		return fmt.Errorf("failed to get hashlist %d: %w", hashlistID, err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	accounts, err := s.accountRepo.ClaimCrackedPrivileged(ctx, tx, hashlistID)
+	if err != nil {
+		return err
+	}
+	if len(accounts) == 0 {
+		return nil
+	}
+
+	if _, err := s.accountRepo.AddHashlistTag(ctx, tx, hashlistID, models.PrivilegedCrackedTag); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		names = append(names, account.QualifiedName())
+	}
+	err = events.Publish(ctx, tx, events.PrivilegedAccountCracked, events.PrivilegedAccountCrackedPayload{
+		HashlistID:   hashlistID,
+		HashlistName: hashlist.Name,
+		OwnerID:      hashlist.UserID,
+		Accounts:     names,
+	})
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	debug.Warning("Privileged accounts cracked in hashlist %d (%s): %v", hashlistID, hashlist.Name, names)
+	return nil
+}
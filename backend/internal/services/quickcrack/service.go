@@ -0,0 +1,580 @@
+// Package quickcrack runs quick crack attempts on a handful of ad hoc hashes.
+// A submission gets a hashlist of its own and the jobs of the quick attack
+// workflow; its results are polled or delivered to a webhook.
+package quickcrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/processor"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/hashutils"
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxHashes is the most hashes a single submission may contain
+	MaxHashes = 100
+
+	// settingWorkflowID names the default quick attack workflow
+	settingWorkflowID = "quick_crack_workflow_id"
+
+	// webhookTick is how often finished submissions are delivered to their webhook
+	webhookTick = 30 * time.Second
+	// webhookTimeout bounds a single webhook request
+	webhookTimeout = 15 * time.Second
+	// maxWebhookAttempts is how often delivery is tried before giving up
+	maxWebhookAttempts = 5
+	// webhookWindow is how long after submission results are still delivered
+	webhookWindow = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrInvalidSubmission is wrapped by every submission validation error
+	ErrInvalidSubmission = errors.New("invalid quick crack submission")
+	// ErrNotConfigured is returned when no quick attack workflow is configured
+	ErrNotConfigured = errors.New("no quick attack workflow is configured")
+	// ErrNotFound is returned for submissions that do not exist or belong to someone else
+	ErrNotFound = errors.New("quick crack submission not found")
+	// ErrAirgapped is returned for webhooks submitted while the server is air-gapped
+	ErrAirgapped = errors.New("webhooks are not available in air-gapped mode")
+	// errInternalAddress is returned for webhooks that resolve to internal addresses
+	errInternalAddress = errors.New("webhook_url must not point to a loopback, private or link-local address")
+)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// net.IP.IsPrivate does not cover
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// QuickCrackService creates quick crack submissions and reports their results
+type QuickCrackService struct {
+	repo                *repository.QuickCrackRepository
+	hashlistRepo        *repository.HashListRepository
+	hashTypeRepo        *repository.HashTypeRepository
+	hashRepo            *repository.HashRepository
+	clientRepo          *repository.ClientRepository
+	workflowRepo        repository.JobWorkflowRepository
+	jobExecRepo         *repository.JobExecutionRepository
+	settingsRepo        *repository.SystemSettingsRepository
+	jobExecutionService *services.JobExecutionService
+	processor           *processor.HashlistDBProcessor
+	hashlistDir         string
+	airgapped           bool
+	client              *http.Client
+	lookupIP            func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// NewQuickCrackService creates a new QuickCrackService. hashlistDir is where
+// the hashlist files of submissions are written. Webhooks are refused when
+// airgapped is set.
+func NewQuickCrackService(
+	repo *repository.QuickCrackRepository,
+	hashlistRepo *repository.HashListRepository,
+	hashTypeRepo *repository.HashTypeRepository,
+	hashRepo *repository.HashRepository,
+	clientRepo *repository.ClientRepository,
+	workflowRepo repository.JobWorkflowRepository,
+	jobExecRepo *repository.JobExecutionRepository,
+	settingsRepo *repository.SystemSettingsRepository,
+	jobExecutionService *services.JobExecutionService,
+	proc *processor.HashlistDBProcessor,
+	hashlistDir string,
+	airgapped bool,
+) *QuickCrackService {
+	return &QuickCrackService{
+		repo:                repo,
+		hashlistRepo:        hashlistRepo,
+		hashTypeRepo:        hashTypeRepo,
+		hashRepo:            hashRepo,
+		clientRepo:          clientRepo,
+		workflowRepo:        workflowRepo,
+		jobExecRepo:         jobExecRepo,
+		settingsRepo:        settingsRepo,
+		jobExecutionService: jobExecutionService,
+		processor:           proc,
+		hashlistDir:         hashlistDir,
+		airgapped:           airgapped,
+		client:              newWebhookClient(),
+		lookupIP:            net.DefaultResolver.LookupIPAddr,
+	}
+}
+
+// newWebhookClient creates the client webhooks are posted with. Every
+// connection, including those of redirects, is checked against the address it
+// actually dials, so a host cannot be rebound to an internal address after
+// submission. Proxies are not used as they would hide that address.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isInternalIP(ip) {
+				return fmt.Errorf("refusing to connect to %s: %w", host, errInternalAddress)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: webhookTimeout, Transport: transport}
+}
+
+// Submit imports the hashes into a new hashlist and queues the jobs of the
+// quick attack workflow against it
+func (s *QuickCrackService) Submit(ctx context.Context, userID uuid.UUID, req *models.QuickCrackRequest) (*models.QuickCrackResult, error) {
+	if err := s.jobExecutionService.CheckMaintenance(ctx); err != nil {
+		return nil, err
+	}
+
+	hashes, err := normalizeHashes(req.Hashes)
+	if err != nil {
+		return nil, err
+	}
+	webhookURL, err := s.validateWebhook(ctx, req.WebhookURL)
+	if err != nil {
+		return nil, err
+	}
+
+	hashType, err := s.resolveHashType(ctx, req.HashType, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.resolveWorkflow(ctx, req.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID, err := s.resolveClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	hashlist, err := s.createHashlist(ctx, userID, clientID, hashType.ID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	submission := &models.QuickCrackSubmission{
+		UserID:     userID,
+		HashlistID: hashlist.ID,
+		HashTypeID: hashType.ID,
+		WorkflowID: &workflow.ID,
+		WebhookURL: webhookURL,
+	}
+	if err := s.repo.Create(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	var jobs []*models.JobExecution
+	for _, step := range workflow.Steps {
+		name := fmt.Sprintf("Quick crack %s - %s", submission.ID.String()[:8], step.PresetJobName)
+		stepJobs, err := s.jobExecutionService.CreateJobExecutions(ctx, step.PresetJobID, hashlist.ID, &userID, name)
+		if err != nil {
+			debug.Error("Failed to create quick crack job for preset %s: %v", step.PresetJobID, err)
+		}
+		for _, job := range stepJobs {
+			jobs = append(jobs, job)
+			submission.JobIDs = append(submission.JobIDs, job.ID)
+		}
+	}
+	if len(submission.JobIDs) == 0 {
+		return nil, fmt.Errorf("failed to create any job of workflow %s", workflow.Name)
+	}
+	if err := s.jobExecutionService.LinkWorkflowRun(ctx, workflow, jobs); err != nil {
+		debug.Error("Failed to link jobs of quick crack %s: %v", submission.ID, err)
+	}
+	if err := s.repo.SetJobIDs(ctx, submission.ID, submission.JobIDs); err != nil {
+		return nil, err
+	}
+
+	debug.Info("Quick crack %s submitted %d hashes of type %d with %d jobs",
+		submission.ID, len(hashes), hashType.ID, len(submission.JobIDs))
+	return s.result(ctx, submission)
+}
+
+// Get returns the state and results of a submission of the user
+func (s *QuickCrackService) Get(ctx context.Context, userID, id uuid.UUID) (*models.QuickCrackResult, error) {
+	submission, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && submission.UserID != userID) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.result(ctx, submission)
+}
+
+// StartWebhookDelivery delivers the results of finished submissions to their
+// webhook until ctx is done
+func (s *QuickCrackService) StartWebhookDelivery(ctx context.Context) {
+	if s.airgapped {
+		debug.Info("Quick crack webhook delivery disabled in air-gapped mode")
+		return
+	}
+
+	ticker := time.NewTicker(webhookTick)
+	defer ticker.Stop()
+
+	for {
+		s.deliverWebhooks(ctx)
+
+		select {
+		case <-ctx.Done():
+			debug.Info("Quick crack webhook delivery stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverWebhooks sends the results of every finished submission still waiting for delivery
+func (s *QuickCrackService) deliverWebhooks(ctx context.Context) {
+	submissions, err := s.repo.ListPendingWebhooks(ctx, time.Now().Add(-webhookWindow))
+	if err != nil {
+		debug.Error("Failed to list pending quick crack webhooks: %v", err)
+		return
+	}
+
+	for i := range submissions {
+		submission := &submissions[i]
+		result, err := s.result(ctx, submission)
+		if err != nil {
+			debug.Error("Failed to get results of quick crack %s: %v", submission.ID, err)
+			continue
+		}
+		if !result.Status.IsFinal() {
+			continue
+		}
+
+		deliveryErr := s.sendWebhook(ctx, *submission.WebhookURL, result)
+		giveUp := submission.WebhookAttempts+1 >= maxWebhookAttempts
+		if deliveryErr != nil {
+			debug.Warning("Failed to deliver quick crack %s to its webhook (attempt %d): %v",
+				submission.ID, submission.WebhookAttempts+1, deliveryErr)
+		}
+		if err := s.repo.RecordWebhookAttempt(ctx, submission.ID, deliveryErr, giveUp); err != nil {
+			debug.Error("%v", err)
+		}
+	}
+}
+
+// sendWebhook posts the results to the webhook
+func (s *QuickCrackService) sendWebhook(ctx context.Context, webhookURL string, result *models.QuickCrackResult) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook rejected results: %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
+
+// result collects the state and per-hash results of a submission
+func (s *QuickCrackService) result(ctx context.Context, submission *models.QuickCrackSubmission) (*models.QuickCrackResult, error) {
+	hashlist, err := s.hashlistRepo.GetByID(ctx, submission.HashlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quick crack hashlist: %w", err)
+	}
+
+	hashes, _, err := s.hashRepo.GetHashesByHashlistID(ctx, submission.HashlistID, MaxHashes, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.QuickCrackResult{
+		ID:         submission.ID,
+		HashTypeID: submission.HashTypeID,
+		HashlistID: submission.HashlistID,
+		JobIDs:     submission.JobIDs,
+		Hashes:     make([]models.QuickCrackHash, 0, len(hashes)),
+		CreatedAt:  submission.CreatedAt,
+	}
+	for _, hash := range hashes {
+		entry := models.QuickCrackHash{Hash: hash.OriginalHash, Cracked: hash.IsCracked}
+		if hash.IsCracked {
+			entry.Password = hash.Password
+			result.CrackedHashes++
+		}
+		result.Hashes = append(result.Hashes, entry)
+	}
+	result.TotalHashes = len(result.Hashes)
+
+	jobsFinished, err := s.jobsFinished(ctx, submission.JobIDs)
+	if err != nil {
+		return nil, err
+	}
+	result.Status = submissionStatus(hashlist.Status, result.TotalHashes, result.CrackedHashes, jobsFinished)
+	return result, nil
+}
+
+// jobsFinished reports whether every job of a submission has finished or been deleted
+func (s *QuickCrackService) jobsFinished(ctx context.Context, jobIDs []uuid.UUID) (bool, error) {
+	for _, id := range jobIDs {
+		job, err := s.jobExecRepo.GetByID(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to get quick crack job: %w", err)
+		}
+		switch job.Status {
+		case models.JobExecutionStatusCompleted, models.JobExecutionStatusFailed, models.JobExecutionStatusCancelled,
+			models.JobExecutionStatusSuperseded:
+		default:
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// submissionStatus derives the state of a submission from its hashlist and jobs
+func submissionStatus(hashlistStatus string, total, cracked int, jobsFinished bool) models.QuickCrackStatus {
+	switch hashlistStatus {
+	case models.HashListStatusError:
+		return models.QuickCrackStatusFailed
+	case models.HashListStatusUploading, models.HashListStatusProcessing:
+		return models.QuickCrackStatusProcessing
+	}
+	if (total > 0 && cracked >= total) || jobsFinished {
+		return models.QuickCrackStatusCompleted
+	}
+	return models.QuickCrackStatusRunning
+}
+
+// normalizeHashes trims the hashes, drops blank lines and checks the count
+func normalizeHashes(input []string) ([]string, error) {
+	hashes := make([]string, 0, len(input))
+	for _, hash := range input {
+		if hash = strings.TrimSpace(hash); hash != "" {
+			hashes = append(hashes, hash)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("%w: no hashes given", ErrInvalidSubmission)
+	}
+	if len(hashes) > MaxHashes {
+		return nil, fmt.Errorf("%w: at most %d hashes can be submitted, upload a hashlist instead", ErrInvalidSubmission, MaxHashes)
+	}
+	return hashes, nil
+}
+
+// validateWebhook checks a webhook URL and that its host resolves only to
+// public addresses, returning nil when none is given
+func (s *QuickCrackService) validateWebhook(ctx context.Context, raw string) (*string, error) {
+	webhookURL, err := validateWebhookURL(raw)
+	if err != nil || webhookURL == nil {
+		return webhookURL, err
+	}
+	if s.airgapped {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSubmission, ErrAirgapped)
+	}
+
+	parsed, _ := url.Parse(*webhookURL)
+	if net.ParseIP(parsed.Hostname()) != nil {
+		return webhookURL, nil
+	}
+	addrs, err := s.lookupIP(ctx, parsed.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return nil, fmt.Errorf("%w: webhook_url host %q cannot be resolved", ErrInvalidSubmission, parsed.Hostname())
+	}
+	for _, addr := range addrs {
+		if isInternalIP(addr.IP) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSubmission, errInternalAddress)
+		}
+	}
+	return webhookURL, nil
+}
+
+// validateWebhookURL checks that a webhook is an absolute http or https URL
+// whose host is not a literal internal address, returning nil when none is given
+func validateWebhookURL(raw string) (*string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("%w: webhook_url must be an http or https URL", ErrInvalidSubmission)
+	}
+	if ip := net.ParseIP(parsed.Hostname()); ip != nil && isInternalIP(ip) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSubmission, errInternalAddress)
+	}
+	return &raw, nil
+}
+
+// isInternalIP reports whether an address is loopback, private, link-local
+// (which includes cloud metadata endpoints), shared, multicast or unspecified
+func isInternalIP(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip)
+}
+
+// resolveHashType looks up the requested hash type, or detects it from the
+// hashes when none is given. Detected hashes must all be of the same type.
+func (s *QuickCrackService) resolveHashType(ctx context.Context, ref string, hashes []string) (*models.HashType, error) {
+	if strings.TrimSpace(ref) == "" {
+		detected, err := detectHashType(hashes)
+		if err != nil {
+			return nil, err
+		}
+		ref = fmt.Sprint(detected)
+	}
+
+	hashType, err := s.hashTypeRepo.Resolve(ctx, ref)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: unknown hash type %q", ErrInvalidSubmission, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !hashType.IsEnabled {
+		return nil, fmt.Errorf("%w: hash type %d (%s) is disabled", ErrInvalidSubmission, hashType.ID, hashType.Name)
+	}
+	return hashType, nil
+}
+
+// detectHashType returns the hash type all hashes share
+func detectHashType(hashes []string) (int, error) {
+	detected := 0
+	for _, hash := range hashes {
+		hashTypeID, ok := hashutils.DetectHashType(hash)
+		if !ok {
+			return 0, fmt.Errorf("%w: the type of %q cannot be detected, set hash_type", ErrInvalidSubmission, truncate(hash))
+		}
+		if detected != 0 && hashTypeID != detected {
+			return 0, fmt.Errorf("%w: the hashes are of different types (%d and %d), submit them separately", ErrInvalidSubmission, detected, hashTypeID)
+		}
+		detected = hashTypeID
+	}
+	return detected, nil
+}
+
+// truncate shortens a hash for error messages
+func truncate(hash string) string {
+	if len(hash) > 40 {
+		return hash[:40] + "..."
+	}
+	return hash
+}
+
+// resolveWorkflow returns the requested workflow, or the configured default
+func (s *QuickCrackService) resolveWorkflow(ctx context.Context, id *uuid.UUID) (*models.JobWorkflow, error) {
+	if id == nil {
+		setting, err := s.settingsRepo.GetSetting(ctx, settingWorkflowID)
+		if err != nil || setting.Value == nil || *setting.Value == "" {
+			return nil, ErrNotConfigured
+		}
+		parsed, err := uuid.Parse(*setting.Value)
+		if err != nil {
+			debug.Warning("Invalid %s value %q: %v", settingWorkflowID, *setting.Value, err)
+			return nil, ErrNotConfigured
+		}
+		id = &parsed
+	}
+
+	workflow, err := s.workflowRepo.GetWorkflowByID(ctx, *id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: workflow %s not found", ErrInvalidSubmission, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(workflow.Steps) == 0 {
+		return nil, fmt.Errorf("%w: workflow %s has no steps", ErrInvalidSubmission, workflow.Name)
+	}
+	return workflow, nil
+}
+
+// resolveClient checks the requested client, and that one is given when
+// hashlists require a client
+func (s *QuickCrackService) resolveClient(ctx context.Context, id *uuid.UUID) (uuid.UUID, error) {
+	if id == nil {
+		setting, err := s.settingsRepo.GetSetting(ctx, "require_client_for_hashlist")
+		if err == nil && setting.Value != nil && *setting.Value == "true" {
+			return uuid.Nil, fmt.Errorf("%w: client_id is required", ErrInvalidSubmission)
+		}
+		return uuid.Nil, nil
+	}
+
+	if _, err := s.clientRepo.GetByID(ctx, *id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return uuid.Nil, fmt.Errorf("%w: client %s not found", ErrInvalidSubmission, id)
+		}
+		return uuid.Nil, err
+	}
+	return *id, nil
+}
+
+// createHashlist writes the hashes to a new hashlist and submits it for processing
+func (s *QuickCrackService) createHashlist(ctx context.Context, userID, clientID uuid.UUID, hashTypeID int, hashes []string) (*models.HashList, error) {
+	now := time.Now()
+	hashlist := &models.HashList{
+		Name:       fmt.Sprintf("Quick crack %s", now.Format("2006-01-02 15:04:05")),
+		UserID:     userID,
+		ClientID:   clientID,
+		HashTypeID: hashTypeID,
+		Status:     models.HashListStatusUploading,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.hashlistRepo.Create(ctx, hashlist); err != nil {
+		return nil, fmt.Errorf("failed to create quick crack hashlist: %w", err)
+	}
+
+	path := filepath.Join(s.hashlistDir, fmt.Sprintf("%d_quick_crack.txt", hashlist.ID))
+	if err := os.WriteFile(path, []byte(strings.Join(hashes, "\n")+"\n"), 0644); err != nil {
+		if statusErr := s.hashlistRepo.UpdateStatus(ctx, hashlist.ID, models.HashListStatusError, "Failed to save submitted hashes"); statusErr != nil {
+			debug.Error("Failed to update hashlist %d status: %v", hashlist.ID, statusErr)
+		}
+		return nil, fmt.Errorf("failed to write quick crack hashlist: %w", err)
+	}
+
+	hashlist.FilePath = path
+	hashlist.Status = models.HashListStatusProcessing
+	if err := s.hashlistRepo.UpdateFilePathAndStatus(ctx, hashlist.ID, path, hashlist.Status); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	go s.processor.SubmitHashlistForProcessing(hashlist.ID)
+	return hashlist, nil
+}
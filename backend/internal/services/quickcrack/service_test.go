@@ -0,0 +1,112 @@
+package quickcrack
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeHashes(t *testing.T) {
+	hashes, err := normalizeHashes([]string{" $2a$10$abc ", "", "\t", "$2b$12$def"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"$2a$10$abc", "$2b$12$def"}, hashes)
+
+	_, err = normalizeHashes([]string{" ", ""})
+	assert.True(t, errors.Is(err, ErrInvalidSubmission))
+
+	tooMany := make([]string, MaxHashes+1)
+	for i := range tooMany {
+		tooMany[i] = "hash"
+	}
+	_, err = normalizeHashes(tooMany)
+	assert.True(t, errors.Is(err, ErrInvalidSubmission))
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	url, err := validateWebhookURL("")
+	require.NoError(t, err)
+	assert.Nil(t, url)
+
+	url, err = validateWebhookURL(" https://hooks.example.com/quick ")
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.example.com/quick", *url)
+
+	for _, invalid := range []string{
+		"ftp://example.com", "example.com/hook", "http://",
+		"http://127.0.0.1:8080/hook", "http://10.1.2.3/hook", "http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook", "http://100.64.0.1/hook", "http://0.0.0.0/hook",
+	} {
+		_, err = validateWebhookURL(invalid)
+		assert.True(t, errors.Is(err, ErrInvalidSubmission), invalid)
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	addrs := map[string][]net.IPAddr{
+		"hooks.example.com": {{IP: net.ParseIP("93.184.216.34")}},
+		"internal.example":  {{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("192.168.1.10")}},
+	}
+	s := &QuickCrackService{lookupIP: func(_ context.Context, host string) ([]net.IPAddr, error) {
+		if a, ok := addrs[host]; ok {
+			return a, nil
+		}
+		return nil, errors.New("no such host")
+	}}
+
+	url, err := s.validateWebhook(context.Background(), "https://hooks.example.com/quick")
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.example.com/quick", *url)
+
+	_, err = s.validateWebhook(context.Background(), "https://internal.example/quick")
+	assert.True(t, errors.Is(err, errInternalAddress))
+
+	_, err = s.validateWebhook(context.Background(), "https://unknown.example/quick")
+	assert.True(t, errors.Is(err, ErrInvalidSubmission))
+
+	s.airgapped = true
+	_, err = s.validateWebhook(context.Background(), "https://hooks.example.com/quick")
+	assert.True(t, errors.Is(err, ErrAirgapped))
+
+	url, err = s.validateWebhook(context.Background(), "")
+	require.NoError(t, err)
+	assert.Nil(t, url)
+}
+
+func TestWebhookClientRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	s := &QuickCrackService{client: newWebhookClient()}
+	err := s.sendWebhook(context.Background(), server.URL, &models.QuickCrackResult{})
+	assert.True(t, errors.Is(err, errInternalAddress))
+}
+
+func TestDetectHashType(t *testing.T) {
+	hashType, err := detectHashType([]string{"$2a$10$abcdefghijklmnopqrstuv", "$2y$12$abcdefghijklmnopqrstuv"})
+	require.NoError(t, err)
+	assert.Equal(t, 3200, hashType)
+
+	// Bare hex digests are ambiguous
+	_, err = detectHashType([]string{"5f4dcc3b5aa765d61d8327deb882cf99"})
+	assert.True(t, errors.Is(err, ErrInvalidSubmission))
+
+	_, err = detectHashType([]string{"$2a$10$abcdefghijklmnopqrstuv", "$1$salt$abcdefghijklmnopqrstuv"})
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "different types"))
+}
+
+func TestSubmissionStatus(t *testing.T) {
+	assert.Equal(t, models.QuickCrackStatusProcessing, submissionStatus(models.HashListStatusProcessing, 0, 0, false))
+	assert.Equal(t, models.QuickCrackStatusFailed, submissionStatus(models.HashListStatusError, 0, 0, true))
+	assert.Equal(t, models.QuickCrackStatusRunning, submissionStatus(models.HashListStatusReady, 3, 1, false))
+	assert.Equal(t, models.QuickCrackStatusCompleted, submissionStatus(models.HashListStatusReady, 3, 3, false), "all cracked")
+	assert.Equal(t, models.QuickCrackStatusCompleted, submissionStatus(models.HashListStatusReady, 3, 1, true), "jobs exhausted")
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"crypto/rand"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/db"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/google/uuid"
@@ -54,6 +56,9 @@ func (s *RetentionService) PurgeOldHashlists(ctx context.Context) error {
 	}
 	debug.Debug("Purge: Default retention is %d months.", defaultRetentionMonths)
 
+	// Expired hashlists go through the trash when a trash window is configured
+	trashDays := s.TrashRetentionDays(ctx)
+
 	// 2. Get all clients to check their specific policies
 	clients, err := s.clientRepo.List(ctx)
 	if err != nil {
@@ -61,10 +66,12 @@ func (s *RetentionService) PurgeOldHashlists(ctx context.Context) error {
 		return fmt.Errorf("purge failed: could not list clients")
 	}
 	clientRetentionMap := make(map[string]int)
+	clientEngagementMap := make(map[string]models.ClientEngagement)
 	for _, client := range clients {
 		if client.DataRetentionMonths != nil {
 			clientRetentionMap[client.ID.String()] = *client.DataRetentionMonths
 		} // Clients with NULL will use the default later
+		clientEngagementMap[client.ID.String()] = client.ClientEngagement
 	}
 
 	// 3. Find and process hashlists eligible for purging
@@ -75,6 +82,7 @@ func (s *RetentionService) PurgeOldHashlists(ctx context.Context) error {
 	offset := 0
 	processedCount := 0
 	deletedCount := 0
+	trashedCount := 0
 
 	for {
 		hashlists, total, err := s.hashlistRepo.List(ctx, repository.ListHashlistsParams{Limit: limit, Offset: offset})
@@ -104,12 +112,26 @@ func (s *RetentionService) PurgeOldHashlists(ctx context.Context) error {
 				continue
 			}
 
-			// Calculate expiration date
+			// Calculate expiration date, counted from the close of a closed engagement
 			retentionDuration := time.Duration(retentionMonths) * 30 * 24 * time.Hour // Approx. months
-			expirationDate := hl.CreatedAt.Add(retentionDuration)
+			expirationDate := clientEngagementMap[hl.ClientID.String()].RetentionStart(hl.CreatedAt).Add(retentionDuration)
 
 			// Check if expired
 			if time.Now().After(expirationDate) {
+				if trashDays > 0 {
+					debug.Info("Purge: Hashlist %d (Created: %s, Client: %s, Retention: %d months) has expired (Expiry: %s). Moving to trash...", hl.ID, hl.CreatedAt, hl.ClientID, retentionMonths, expirationDate)
+					if err := s.hashlistRepo.SoftDelete(ctx, hl.ID, nil); err != nil {
+						if errors.Is(err, repository.ErrHashlistHasActiveJobs) {
+							debug.Info("Purge: Hashlist %d still has active jobs, will retry on the next run", hl.ID)
+						} else {
+							debug.Error("Purge: Failed to move expired hashlist %d to trash: %v", hl.ID, err)
+						}
+						continue
+					}
+					trashedCount++
+					continue
+				}
+
 				debug.Info("Purge: Hashlist %d (Created: %s, Client: %s, Retention: %d months) has expired (Expiry: %s). Deleting...", hl.ID, hl.CreatedAt, hl.ClientID, retentionMonths, expirationDate)
 				err := s.DeleteHashlistAndOrphanedHashes(ctx, hl.ID)
 				if err != nil {
@@ -147,7 +169,7 @@ func (s *RetentionService) PurgeOldHashlists(ctx context.Context) error {
 		// Log error but don't fail the whole operation
 	}
 
-	debug.Info("Data retention purge completed. Processed: %d, Deleted: %d", processedCount, deletedCount)
+	debug.Info("Data retention purge completed. Processed: %d, Deleted: %d, Moved to trash: %d", processedCount, deletedCount, trashedCount)
 	return nil
 }
 
@@ -261,11 +283,27 @@ func (s *RetentionService) PurgeOldAnalyticsReports(ctx context.Context) error {
 	return nil
 }
 
+// TrashRetentionDays returns how many days deleted items are kept in the trash
+// before being purged. 0 means deletions are permanent immediately.
+func (s *RetentionService) TrashRetentionDays(ctx context.Context) int {
+	setting, err := s.clientSettingsRepo.GetSetting(ctx, "trash_retention_days")
+	if err != nil || setting.Value == nil {
+		debug.Warning("Trash retention setting not available, deleting immediately: %v", err)
+		return 0
+	}
+	days, err := strconv.Atoi(*setting.Value)
+	if err != nil || days < 0 {
+		debug.Error("Invalid trash retention setting value '%s', deleting immediately", *setting.Value)
+		return 0
+	}
+	return days
+}
+
 // DeleteHashlistAndOrphanedHashes deletes a hashlist and any hashes that become orphaned as a result.
 // It also securely deletes the associated file from disk.
 func (s *RetentionService) DeleteHashlistAndOrphanedHashes(ctx context.Context, hashlistID int64) error {
 	// First, get the hashlist details including file path BEFORE starting transaction
-	hashlist, err := s.hashlistRepo.GetByID(ctx, hashlistID)
+	hashlist, err := s.hashlistRepo.GetByIDIncludingDeleted(ctx, hashlistID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			debug.Warning("Purge: Hashlist %d not found, may have been already deleted", hashlistID)
@@ -274,6 +312,17 @@ func (s *RetentionService) DeleteHashlistAndOrphanedHashes(ctx context.Context,
 		return fmt.Errorf("failed to get hashlist %d details: %w", hashlistID, err)
 	}
 
+	// Sub-lists share the hashes of their parent, purge them first so their agent files go too
+	subLists, err := s.hashlistRepo.ListSubLists(ctx, hashlistID)
+	if err != nil {
+		return fmt.Errorf("failed to get sub-lists of hashlist %d: %w", hashlistID, err)
+	}
+	for _, subList := range subLists {
+		if err := s.DeleteHashlistAndOrphanedHashes(ctx, subList.ID); err != nil {
+			return fmt.Errorf("failed to delete sub-list %d of hashlist %d: %w", subList.ID, hashlistID, err)
+		}
+	}
+
 	// Store the file path for later deletion
 	filePath := hashlist.FilePath
 	debug.Info("Purge: Will delete hashlist %d and its file at: %s", hashlistID, filePath)
@@ -0,0 +1,59 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// PurgeExpiredStagedHashlists discards staged hashlist uploads that waited for
+// review longer than the hashlist_staging_expiry_hours client setting. A value
+// of 0 keeps staged uploads until they are committed or discarded.
+func (s *RetentionService) PurgeExpiredStagedHashlists(ctx context.Context) error {
+	setting, err := s.clientSettingsRepo.GetSetting(ctx, "hashlist_staging_expiry_hours")
+	if err != nil || setting.Value == nil {
+		return fmt.Errorf("staging purge failed: could not retrieve hashlist_staging_expiry_hours setting: %v", err)
+	}
+	hours, err := strconv.Atoi(*setting.Value)
+	if err != nil || hours < 0 {
+		return fmt.Errorf("staging purge failed: invalid hashlist_staging_expiry_hours value '%s'", *setting.Value)
+	}
+	if hours == 0 {
+		debug.Debug("Staging Purge: Expiry disabled, keeping staged hashlists")
+		return nil
+	}
+
+	ids, err := s.hashlistRepo.ListStagedBefore(ctx, time.Now().Add(-time.Duration(hours)*time.Hour))
+	if err != nil {
+		return fmt.Errorf("staging purge failed: %w", err)
+	}
+
+	discarded := 0
+	for _, id := range ids {
+		filePath, err := s.hashlistRepo.DeleteStaged(ctx, id)
+		if err != nil {
+			// Committed or discarded since it was listed
+			if !errors.Is(err, repository.ErrHashlistNotStaged) {
+				debug.Error("Staging Purge: Failed to discard staged hashlist %d: %v", id, err)
+			}
+			continue
+		}
+		if filePath != "" {
+			if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+				debug.Warning("Staging Purge: Failed to remove upload of hashlist %d: %v", id, err)
+			}
+		}
+		discarded++
+	}
+
+	if discarded > 0 {
+		debug.Info("Staging purge completed, discarded %d staged hashlists older than %d hours", discarded, hours)
+	}
+	return nil
+}
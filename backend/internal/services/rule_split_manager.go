@@ -3,11 +3,15 @@ package services
 import (
 	"bufio"
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
 	"github.com/google/uuid"
@@ -19,25 +23,84 @@ type RuleChunk struct {
 	StartIndex int    // Starting rule index in the original file
 	EndIndex   int    // Ending rule index in the original file
 	RuleCount  int    // Number of rules in this chunk
+
+	// Set for chunks registered for download by agents
+	ID      int64  // ID in rule_chunk_files
+	Name    string // Path below the agent rules directory
+	MD5Hash string // MD5 hash of the chunk file
+	Size    int64  // Size of the chunk file in bytes
 }
 
 // RuleSplitManager handles splitting rule files into smaller chunks
 type RuleSplitManager struct {
-	tempDir  string
-	fileRepo *repository.FileRepository
+	tempDir   string
+	fileRepo  *repository.FileRepository
+	chunkRepo *repository.RuleChunkRepository
 }
 
-// NewRuleSplitManager creates a new rule split manager
-func NewRuleSplitManager(tempDir string, fileRepo *repository.FileRepository) *RuleSplitManager {
+// NewRuleSplitManager creates a new rule split manager. Chunks created on
+// demand are registered in chunkRepo when it is set.
+func NewRuleSplitManager(tempDir string, fileRepo *repository.FileRepository, chunkRepo *repository.RuleChunkRepository) *RuleSplitManager {
 	// Ensure temp directory exists
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
 		debug.Error("Failed to create rule chunk temp directory: %v", err)
 	}
 
 	return &RuleSplitManager{
-		tempDir:  tempDir,
-		fileRepo: fileRepo,
+		tempDir:   tempDir,
+		fileRepo:  fileRepo,
+		chunkRepo: chunkRepo,
+	}
+}
+
+// ChunkName returns the name a chunk file is synced under, its path below
+// the agent rules directory, e.g. chunks/job_<id>/chunk_0_1000.rule
+func (m *RuleSplitManager) ChunkName(chunkPath string) (string, error) {
+	rel, err := filepath.Rel(m.tempDir, chunkPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("rule chunk %s is outside %s", chunkPath, m.tempDir)
+	}
+	return "chunks/" + filepath.ToSlash(rel), nil
+}
+
+// ChunkFile returns the registered file of a rule chunk, or
+// repository.ErrNotFound for chunks that were never registered
+func (m *RuleSplitManager) ChunkFile(ctx context.Context, chunkPath string) (*models.RuleChunkFile, error) {
+	if m.chunkRepo == nil {
+		return nil, repository.ErrNotFound
+	}
+	name, err := m.ChunkName(chunkPath)
+	if err != nil {
+		return nil, err
 	}
+	return m.chunkRepo.GetByName(ctx, name)
+}
+
+// registerChunk records a chunk created for a job so agents can download and
+// verify it
+func (m *RuleSplitManager) registerChunk(ctx context.Context, jobID uuid.UUID, chunk *RuleChunk) error {
+	name, err := m.ChunkName(chunk.Path)
+	if err != nil {
+		return err
+	}
+	chunk.Name = name
+	if m.chunkRepo == nil {
+		return nil
+	}
+
+	file := &models.RuleChunkFile{
+		JobExecutionID: jobID,
+		Name:           name,
+		RuleStartIndex: chunk.StartIndex,
+		RuleEndIndex:   chunk.EndIndex,
+		MD5Hash:        chunk.MD5Hash,
+		FileSize:       chunk.Size,
+	}
+	if err := m.chunkRepo.Register(ctx, file); err != nil {
+		return err
+	}
+	chunk.ID = file.ID
+	return nil
 }
 
 // CountRules counts the number of rules in a rule file
@@ -262,18 +325,21 @@ func (m *RuleSplitManager) CreateSingleRuleChunk(ctx context.Context, jobID uuid
 	}
 	defer chunkFile.Close()
 
-	writer := bufio.NewWriter(chunkFile)
+	hasher := md5.New()
+	counter := &countingWriter{}
+	writer := bufio.NewWriter(io.MultiWriter(chunkFile, hasher, counter))
 	scanner := bufio.NewScanner(file)
-	
+
 	// Skip to startIndex
 	currentIndex := 0
-	for scanner.Scan() && currentIndex < startIndex {
+	// The index is checked first, so the first rule of the chunk is not consumed
+	for currentIndex < startIndex && scanner.Scan() {
 		currentIndex++
 	}
 	
 	// Write numRules lines to chunk file
 	rulesWritten := 0
-	for scanner.Scan() && rulesWritten < numRules {
+	for rulesWritten < numRules && scanner.Scan() {
 		line := scanner.Text()
 		if _, err := writer.WriteString(line + "\n"); err != nil {
 			os.Remove(chunkPath) // Clean up on error
@@ -298,6 +364,12 @@ func (m *RuleSplitManager) CreateSingleRuleChunk(ctx context.Context, jobID uuid
 		StartIndex: startIndex,
 		EndIndex:   startIndex + rulesWritten,
 		RuleCount:  rulesWritten,
+		MD5Hash:    hex.EncodeToString(hasher.Sum(nil)),
+		Size:       counter.n,
+	}
+	if err := m.registerChunk(ctx, jobID, chunk); err != nil {
+		os.Remove(chunkPath) // Clean up on error
+		return nil, fmt.Errorf("failed to register rule chunk: %w", err)
 	}
 
 	debug.Log("Created single rule chunk", map[string]interface{}{
@@ -306,20 +378,42 @@ func (m *RuleSplitManager) CreateSingleRuleChunk(ctx context.Context, jobID uuid
 		"start_index": startIndex,
 		"end_index":   chunk.EndIndex,
 		"rule_count":  rulesWritten,
+		"chunk_id":    chunk.ID,
+		"md5_hash":    chunk.MD5Hash,
 	})
 
 	return chunk, nil
 }
 
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
 // CleanupJobChunksUUID removes all chunk files for a specific job with UUID
 func (m *RuleSplitManager) CleanupJobChunksUUID(jobID uuid.UUID) error {
 	jobDir := filepath.Join(m.tempDir, fmt.Sprintf("job_%s", jobID.String()))
 
+	// Drop the registry records with the files
+	var recordCount int64
+	if m.chunkRepo != nil {
+		var err error
+		if recordCount, err = m.chunkRepo.DeleteByJobExecution(context.Background(), jobID); err != nil {
+			return err
+		}
+	}
+
 	// Check if directory exists
 	if _, err := os.Stat(jobDir); os.IsNotExist(err) {
 		debug.Log("Job directory does not exist, nothing to clean", map[string]interface{}{
-			"job_id":  jobID,
-			"job_dir": jobDir,
+			"job_id":       jobID,
+			"job_dir":      jobDir,
+			"record_count": recordCount,
 		})
 		return nil
 	}
@@ -334,9 +428,10 @@ func (m *RuleSplitManager) CleanupJobChunksUUID(jobID uuid.UUID) error {
 	}
 
 	debug.Log("Cleaned up rule chunks for job", map[string]interface{}{
-		"job_id":     jobID,
-		"job_dir":    jobDir,
-		"file_count": fileCount,
+		"job_id":       jobID,
+		"job_dir":      jobDir,
+		"file_count":   fileCount,
+		"record_count": recordCount,
 	})
 
 	return nil
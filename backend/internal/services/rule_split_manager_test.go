@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSingleRuleChunk(t *testing.T) {
+	dir := t.TempDir()
+	ruleFile := filepath.Join(dir, "best.rule")
+	require.NoError(t, os.WriteFile(ruleFile, []byte(":\nc\nu\n$1\n$2\n"), 0644))
+
+	manager := NewRuleSplitManager(filepath.Join(dir, "rule_chunks"), nil, nil)
+	jobID := uuid.New()
+
+	chunk, err := manager.CreateSingleRuleChunk(context.Background(), jobID, ruleFile, 1, 3)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(chunk.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "c\nu\n$1\n", string(content))
+
+	sum := md5.Sum(content)
+	assert.Equal(t, hex.EncodeToString(sum[:]), chunk.MD5Hash)
+	assert.Equal(t, int64(len(content)), chunk.Size)
+	assert.Equal(t, "chunks/job_"+jobID.String()+"/chunk_1_4.rule", chunk.Name)
+	assert.Equal(t, 1, chunk.StartIndex)
+	assert.Equal(t, 4, chunk.EndIndex)
+}
+
+func TestRuleChunkName(t *testing.T) {
+	manager := NewRuleSplitManager(t.TempDir(), nil, nil)
+
+	_, err := manager.ChunkName("/elsewhere/job_1/chunk_0_10.rule")
+	assert.Error(t, err)
+}
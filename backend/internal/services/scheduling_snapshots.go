@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// maxSchedulingSnapshots caps the snapshots kept whatever the retention, as a
+// busy scheduler changes its decisions on almost every cycle
+const maxSchedulingSnapshots = 20000
+
+// schedulingRecorderKey is the context key of the current cycle's recorder
+type schedulingRecorderKey struct{}
+
+// schedulingRecorder collects what one scheduling cycle considered and
+// decided. The code deciding reaches it through the context; all methods
+// do nothing on a nil recorder, so the same code runs outside of a cycle.
+type schedulingRecorder struct {
+	mu       sync.Mutex
+	snapshot models.SchedulingSnapshot
+	jobs     map[uuid.UUID]bool
+	skips    map[string]int    // Index in snapshot.Skips by job and reason
+	notes    map[int]agentNote // Why assignWorkToAgent gave an agent no task
+	decided  map[int]bool      // Agents with a decision, preempted agents are decided twice
+}
+
+// agentNote is why an agent got no task, and the job it was looking at
+type agentNote struct {
+	jobID  *uuid.UUID
+	reason string
+}
+
+func newSchedulingRecorder(startedAt time.Time) *schedulingRecorder {
+	return &schedulingRecorder{
+		snapshot: models.SchedulingSnapshot{StartedAt: startedAt, LastSeenAt: startedAt},
+		jobs:     make(map[uuid.UUID]bool),
+		skips:    make(map[string]int),
+		notes:    make(map[int]agentNote),
+		decided:  make(map[int]bool),
+	}
+}
+
+// withSchedulingRecorder returns a context carrying the recorder
+func withSchedulingRecorder(ctx context.Context, rec *schedulingRecorder) context.Context {
+	return context.WithValue(ctx, schedulingRecorderKey{}, rec)
+}
+
+// schedulingRecorderFrom returns the recorder of the context, or nil
+func schedulingRecorderFrom(ctx context.Context) *schedulingRecorder {
+	rec, _ := ctx.Value(schedulingRecorderKey{}).(*schedulingRecorder)
+	return rec
+}
+
+// considerAgents records the agents available for work
+func (r *schedulingRecorder) considerAgents(agents []models.Agent) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, agent := range agents {
+		r.snapshot.Agents = append(r.snapshot.Agents, models.SchedulingAgentState{ID: agent.ID, Name: agent.Name})
+	}
+}
+
+// considerJobs records jobs with pending work, once each
+func (r *schedulingRecorder) considerJobs(jobs []models.JobExecutionWithWork) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range jobs {
+		if r.jobs[jobs[i].ID] {
+			continue
+		}
+		r.jobs[jobs[i].ID] = true
+		r.snapshot.Jobs = append(r.snapshot.Jobs, models.SchedulingJobState{
+			ID:           jobs[i].ID,
+			Name:         jobs[i].Name,
+			Priority:     jobs[i].Priority,
+			Status:       string(jobs[i].Status),
+			ActiveAgents: jobs[i].ActiveAgents,
+			MaxAgents:    jobs[i].MaxAgents,
+			PendingWork:  jobs[i].PendingWork,
+		})
+	}
+}
+
+// skipJob records that a job was passed over for an agent
+func (r *schedulingRecorder) skipJob(jobID uuid.UUID, agentID int, reason string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := jobID.String() + "/" + reason
+	if i, ok := r.skips[key]; ok {
+		r.snapshot.Skips[i].AgentIDs = append(r.snapshot.Skips[i].AgentIDs, agentID)
+		return
+	}
+	r.skips[key] = len(r.snapshot.Skips)
+	r.snapshot.Skips = append(r.snapshot.Skips, models.SchedulingSkip{JobID: jobID, Reason: reason, AgentIDs: []int{agentID}})
+}
+
+// note records why an agent is about to be left without a task
+func (r *schedulingRecorder) note(agentID int, jobID *uuid.UUID, reason string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notes[agentID] = agentNote{jobID: jobID, reason: reason}
+}
+
+// decide records the outcome of giving an agent work
+func (r *schedulingRecorder) decide(agentID int, task *models.JobTask, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	decision := models.SchedulingDecision{AgentID: agentID}
+	note := r.notes[agentID]
+	delete(r.notes, agentID)
+	switch {
+	case err != nil:
+		decision.Outcome = models.SchedulingOutcomeError
+		decision.JobID = note.jobID
+		decision.Error = err.Error()
+	case task != nil:
+		jobID := task.JobExecutionID
+		decision.Outcome = models.SchedulingOutcomeAssigned
+		decision.JobID = &jobID
+	default:
+		decision.Outcome = models.SchedulingOutcomeSkipped
+		decision.JobID = note.jobID
+		decision.Reason = note.reason
+		if decision.Reason == "" {
+			decision.Reason = models.SchedulingReasonNoEligibleJob
+		}
+	}
+
+	// A preempted agent's second decision replaces the first
+	if r.decided[agentID] {
+		for i := range r.snapshot.Decisions {
+			if r.snapshot.Decisions[i].AgentID == agentID {
+				r.snapshot.Decisions[i] = decision
+				return
+			}
+		}
+	}
+	r.decided[agentID] = true
+	r.snapshot.Decisions = append(r.snapshot.Decisions, decision)
+}
+
+// finish returns the cycle's snapshot
+func (r *schedulingRecorder) finish(interruptedJobs []uuid.UUID) *models.SchedulingSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshot.DurationMs = time.Since(r.snapshot.StartedAt).Milliseconds()
+	r.snapshot.InterruptedJobs = interruptedJobs
+	snapshot := r.snapshot
+	return &snapshot
+}
+
+// SchedulingSnapshotStore keeps the snapshots of recent scheduling cycles in
+// memory, for answering why a job is not running without reading debug logs
+type SchedulingSnapshotStore struct {
+	mu        sync.RWMutex
+	retention time.Duration
+	cycles    int64
+	snapshots []*models.SchedulingSnapshot
+}
+
+// NewSchedulingSnapshotStore creates a store keeping snapshots for retention
+func NewSchedulingSnapshotStore(retention time.Duration) *SchedulingSnapshotStore {
+	return &SchedulingSnapshotStore{retention: retention}
+}
+
+// SetRetention changes how long snapshots are kept
+func (st *SchedulingSnapshotStore) SetRetention(retention time.Duration) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.retention = retention
+}
+
+// Record adds a cycle's snapshot. A cycle that decided the same as the
+// previous one is merged into it; otherwise the differences are recorded in
+// the snapshot's Changes.
+func (st *SchedulingSnapshotStore) Record(snapshot *models.SchedulingSnapshot) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.cycles++
+	snapshot.Cycle = st.cycles
+	if n := len(st.snapshots); n > 0 {
+		previous := st.snapshots[n-1]
+		snapshot.Changes = diffSchedulingSnapshots(previous, snapshot)
+		if len(snapshot.Changes) == 0 {
+			previous.Repeats++
+			previous.LastSeenAt = snapshot.StartedAt
+			// Keep the latest view of the jobs, their counts move every cycle
+			previous.Jobs = snapshot.Jobs
+			return
+		}
+	}
+	st.snapshots = append(st.snapshots, snapshot)
+
+	// Drop snapshots past the retention, and the oldest beyond the cap
+	cutoff := snapshot.StartedAt.Add(-st.retention)
+	drop := 0
+	for drop < len(st.snapshots)-1 && (st.snapshots[drop].LastSeenAt.Before(cutoff) || len(st.snapshots)-drop > maxSchedulingSnapshots) {
+		drop++
+	}
+	if drop > 0 {
+		st.snapshots = append([]*models.SchedulingSnapshot(nil), st.snapshots[drop:]...)
+	}
+}
+
+// Recent returns up to limit of the latest snapshots, newest first
+func (st *SchedulingSnapshotStore) Recent(limit int) []models.SchedulingSnapshot {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	result := []models.SchedulingSnapshot{}
+	for i := len(st.snapshots) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, *st.snapshots[i])
+	}
+	return result
+}
+
+// ForJob returns up to limit of the latest cycles' decisions about a job,
+// newest first. Consecutive cycles that decided the same about the job are
+// merged.
+func (st *SchedulingSnapshotStore) ForJob(jobID uuid.UUID, limit int) []models.JobSchedulingEntry {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	result := []models.JobSchedulingEntry{}
+	var last *models.JobSchedulingEntry
+	for i := len(st.snapshots) - 1; i >= 0; i-- {
+		entry := jobSchedulingEntry(st.snapshots[i], jobID)
+		if last != nil && sameJobScheduling(last, &entry) {
+			// Walking back in time, the merged entry starts earlier
+			last.Cycle = entry.Cycle
+			last.StartedAt = entry.StartedAt
+			last.Repeats += entry.Repeats + 1
+			continue
+		}
+		if len(result) == limit {
+			break
+		}
+		result = append(result, entry)
+		last = &result[len(result)-1]
+	}
+	return result
+}
+
+// jobSchedulingEntry extracts what a snapshot says about a job
+func jobSchedulingEntry(snapshot *models.SchedulingSnapshot, jobID uuid.UUID) models.JobSchedulingEntry {
+	entry := models.JobSchedulingEntry{
+		Cycle:           snapshot.Cycle,
+		StartedAt:       snapshot.StartedAt,
+		LastSeenAt:      snapshot.LastSeenAt,
+		Repeats:         snapshot.Repeats,
+		AvailableAgents: len(snapshot.Agents),
+	}
+	for i := range snapshot.Jobs {
+		if snapshot.Jobs[i].ID == jobID {
+			job := snapshot.Jobs[i]
+			entry.Considered = true
+			entry.Job = &job
+		}
+	}
+	for _, decision := range snapshot.Decisions {
+		if decision.JobID != nil && *decision.JobID == jobID {
+			entry.Decisions = append(entry.Decisions, decision)
+		}
+	}
+	for _, skip := range snapshot.Skips {
+		if skip.JobID == jobID {
+			entry.Skips = append(entry.Skips, skip)
+		}
+	}
+	return entry
+}
+
+// sameJobScheduling reports whether two entries decided the same about a job
+func sameJobScheduling(a, b *models.JobSchedulingEntry) bool {
+	if a.Considered != b.Considered || a.AvailableAgents != b.AvailableAgents {
+		return false
+	}
+	return strings.Join(decisionKeys(a.Decisions), ";") == strings.Join(decisionKeys(b.Decisions), ";") &&
+		strings.Join(skipKeys(a.Skips), ";") == strings.Join(skipKeys(b.Skips), ";")
+}
+
+// diffSchedulingSnapshots describes what changed from one snapshot to the
+// next: jobs and agents that appeared or left, and changed decisions and skips
+func diffSchedulingSnapshots(previous, current *models.SchedulingSnapshot) []string {
+	var changes []string
+
+	previousJobs := make(map[uuid.UUID]bool, len(previous.Jobs))
+	for _, job := range previous.Jobs {
+		previousJobs[job.ID] = true
+	}
+	currentJobs := make(map[uuid.UUID]bool, len(current.Jobs))
+	for _, job := range current.Jobs {
+		currentJobs[job.ID] = true
+		if !previousJobs[job.ID] {
+			changes = append(changes, fmt.Sprintf("job %s (%s) has pending work", job.ID, job.Name))
+		}
+	}
+	for _, job := range previous.Jobs {
+		if !currentJobs[job.ID] {
+			changes = append(changes, fmt.Sprintf("job %s (%s) no longer has pending work", job.ID, job.Name))
+		}
+	}
+
+	previousAgents := make(map[int]bool, len(previous.Agents))
+	for _, agent := range previous.Agents {
+		previousAgents[agent.ID] = true
+	}
+	currentAgents := make(map[int]bool, len(current.Agents))
+	for _, agent := range current.Agents {
+		currentAgents[agent.ID] = true
+		if !previousAgents[agent.ID] {
+			changes = append(changes, fmt.Sprintf("agent %d (%s) became available", agent.ID, agent.Name))
+		}
+	}
+	for _, agent := range previous.Agents {
+		if !currentAgents[agent.ID] {
+			changes = append(changes, fmt.Sprintf("agent %d (%s) is no longer available", agent.ID, agent.Name))
+		}
+	}
+
+	previousDecisions := make(map[int]string, len(previous.Decisions))
+	for _, decision := range previous.Decisions {
+		previousDecisions[decision.AgentID] = describeDecision(decision)
+	}
+	for _, decision := range current.Decisions {
+		now := describeDecision(decision)
+		if before, ok := previousDecisions[decision.AgentID]; ok && before != now {
+			changes = append(changes, fmt.Sprintf("agent %d: %s, was %s", decision.AgentID, now, before))
+		}
+	}
+
+	previousSkips := strings.Join(skipKeys(previous.Skips), ";")
+	if currentSkips := strings.Join(skipKeys(current.Skips), ";"); currentSkips != previousSkips {
+		changes = append(changes, "jobs passed over changed")
+	}
+
+	if len(current.InterruptedJobs) > 0 {
+		changes = append(changes, fmt.Sprintf("%d jobs interrupted for higher priority work", len(current.InterruptedJobs)))
+	}
+	return changes
+}
+
+// describeDecision summarizes a decision for comparison and for Changes
+func describeDecision(decision models.SchedulingDecision) string {
+	description := decision.Outcome
+	if decision.JobID != nil {
+		description += " job " + decision.JobID.String()
+	}
+	if decision.Reason != "" {
+		description += " (" + decision.Reason + ")"
+	}
+	return description
+}
+
+// decisionKeys returns the decisions' descriptions in agent order
+func decisionKeys(decisions []models.SchedulingDecision) []string {
+	sorted := append([]models.SchedulingDecision(nil), decisions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].AgentID < sorted[j].AgentID })
+	keys := make([]string, len(sorted))
+	for i, decision := range sorted {
+		keys[i] = fmt.Sprintf("%d:%s", decision.AgentID, describeDecision(decision))
+	}
+	return keys
+}
+
+// skipKeys returns the skips as sorted job, reason and agent keys
+func skipKeys(skips []models.SchedulingSkip) []string {
+	var keys []string
+	for _, skip := range skips {
+		for _, agentID := range skip.AgentIDs {
+			keys = append(keys, fmt.Sprintf("%s/%s/%d", skip.JobID, skip.Reason, agentID))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
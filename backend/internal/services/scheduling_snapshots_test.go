@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cycle records one scheduling cycle where agent 1 is given a task of
+// assigned, or skipped for reason, and job is passed over for agent 2
+func cycle(store *SchedulingSnapshotStore, at time.Time, job models.JobExecutionWithWork, assigned bool, reason string) {
+	rec := newSchedulingRecorder(at)
+	ctx := withSchedulingRecorder(context.Background(), rec)
+
+	rec.considerAgents([]models.Agent{{ID: 1, Name: "gpu-1"}, {ID: 2, Name: "cpu-1"}})
+	schedulingRecorderFrom(ctx).considerJobs([]models.JobExecutionWithWork{job})
+	if assigned {
+		rec.decide(1, &models.JobTask{JobExecutionID: job.ID}, nil)
+	} else {
+		schedulingRecorderFrom(ctx).note(1, &job.ID, reason)
+		rec.decide(1, nil, nil)
+	}
+	rec.skipJob(job.ID, 2, models.SchedulingReasonCloudBurst)
+	rec.decide(2, nil, nil)
+	store.Record(rec.finish(nil))
+}
+
+func TestSchedulingSnapshotsMergeAndDiff(t *testing.T) {
+	store := NewSchedulingSnapshotStore(time.Hour)
+	job := models.JobExecutionWithWork{JobExecution: models.JobExecution{ID: uuid.New(), Name: "NTLM rockyou"}, PendingWork: 10}
+	start := time.Now()
+
+	cycle(store, start, job, false, models.SchedulingReasonBenchmarkPending)
+	cycle(store, start.Add(5*time.Second), job, false, models.SchedulingReasonBenchmarkPending)
+	cycle(store, start.Add(10*time.Second), job, true, "")
+
+	snapshots := store.Recent(10)
+	require.Len(t, snapshots, 2)
+
+	// Identical cycles are merged
+	assert.Equal(t, 1, snapshots[1].Repeats)
+	assert.Equal(t, start.Add(5*time.Second), snapshots[1].LastSeenAt)
+	require.Len(t, snapshots[1].Decisions, 2)
+	assert.Equal(t, models.SchedulingOutcomeSkipped, snapshots[1].Decisions[0].Outcome)
+	assert.Equal(t, models.SchedulingReasonBenchmarkPending, snapshots[1].Decisions[0].Reason)
+	assert.Equal(t, models.SchedulingReasonNoEligibleJob, snapshots[1].Decisions[1].Reason)
+
+	// The change of decision is described
+	assert.Equal(t, int64(3), snapshots[0].Cycle)
+	require.Len(t, snapshots[0].Changes, 1)
+	assert.Contains(t, snapshots[0].Changes[0], "agent 1: assigned job "+job.ID.String())
+
+	entries := store.ForJob(job.ID, 10)
+	require.Len(t, entries, 2)
+	assert.True(t, entries[0].Considered)
+	assert.Equal(t, models.SchedulingOutcomeAssigned, entries[0].Decisions[0].Outcome)
+	assert.Equal(t, 1, entries[1].Repeats)
+	assert.Equal(t, []int{2}, entries[1].Skips[0].AgentIDs)
+
+	// A job absent from the cycles was never considered
+	other := store.ForJob(uuid.New(), 10)
+	require.Len(t, other, 1)
+	assert.False(t, other[0].Considered)
+	assert.Equal(t, 2, other[0].AvailableAgents)
+	assert.Equal(t, 2, other[0].Repeats)
+}
+
+func TestSchedulingSnapshotsRetention(t *testing.T) {
+	store := NewSchedulingSnapshotStore(time.Minute)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		job := models.JobExecutionWithWork{JobExecution: models.JobExecution{ID: uuid.New()}}
+		cycle(store, start.Add(time.Duration(i)*time.Minute), job, true, "")
+	}
+
+	// Snapshots last seen over a minute before the latest cycle are dropped
+	snapshots := store.Recent(10)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, int64(5), snapshots[0].Cycle)
+}
+
+func TestSchedulingRecorderErrorsAndPreemption(t *testing.T) {
+	rec := newSchedulingRecorder(time.Now())
+	jobID := uuid.New()
+
+	rec.note(1, &jobID, models.SchedulingReasonAgentBusy)
+	rec.decide(1, nil, nil)
+	// A preempted agent is decided again in the same cycle
+	rec.decide(1, nil, errors.New("failed to sync hashlist"))
+
+	snapshot := rec.finish(nil)
+	require.Len(t, snapshot.Decisions, 1)
+	assert.Equal(t, models.SchedulingOutcomeError, snapshot.Decisions[0].Outcome)
+	assert.Equal(t, "failed to sync hashlist", snapshot.Decisions[0].Error)
+
+	// Outside of a cycle recording does nothing
+	var none *schedulingRecorder
+	none.skipJob(jobID, 1, models.SchedulingReasonPlacement)
+	none.decide(1, nil, nil)
+	assert.Nil(t, schedulingRecorderFrom(context.Background()))
+}
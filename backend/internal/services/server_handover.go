@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// ServerHandoverService carries task assignments across a graceful restart.
+// When the backend shuts down it records the tasks in flight; the next start
+// keeps them running while their agents reconnect, instead of marking every
+// task reconnect_pending.
+type ServerHandoverService struct {
+	handoverRepo       *repository.ServerHandoverRepository
+	jobTaskRepo        *repository.JobTaskRepository
+	systemSettingsRepo *repository.SystemSettingsRepository
+}
+
+// NewServerHandoverService creates a new server handover service
+func NewServerHandoverService(
+	handoverRepo *repository.ServerHandoverRepository,
+	jobTaskRepo *repository.JobTaskRepository,
+	systemSettingsRepo *repository.SystemSettingsRepository,
+) *ServerHandoverService {
+	return &ServerHandoverService{
+		handoverRepo:       handoverRepo,
+		jobTaskRepo:        jobTaskRepo,
+		systemSettingsRepo: systemSettingsRepo,
+	}
+}
+
+// Record persists the tasks assigned to agents before the agents are told to
+// reconnect after reconnectAfter
+func (s *ServerHandoverService) Record(ctx context.Context, reconnectAfter time.Duration) (*models.ServerHandover, error) {
+	tasks, err := s.jobTaskRepo.GetStaleTasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get in-flight tasks: %w", err)
+	}
+
+	handover := &models.ServerHandover{
+		ReconnectAfterSeconds: int(reconnectAfter / time.Second),
+		Tasks:                 make([]models.HandoverTask, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		if task.AgentID == nil {
+			continue
+		}
+		handover.Tasks = append(handover.Tasks, models.HandoverTask{
+			TaskID:            task.ID,
+			JobExecutionID:    task.JobExecutionID,
+			AgentID:           *task.AgentID,
+			Status:            string(task.Status),
+			KeyspaceProcessed: task.KeyspaceProcessed,
+		})
+	}
+
+	if err := s.handoverRepo.Create(ctx, handover); err != nil {
+		return nil, err
+	}
+	debug.Info("Recorded server handover %d with %d in-flight tasks", handover.ID, len(handover.Tasks))
+	return handover, nil
+}
+
+// Resume returns the handover of the previous shutdown, or nil if it was not
+// graceful or happened too long ago for its agents to still be waiting. A
+// handover is resumed at most once.
+func (s *ServerHandoverService) Resume(ctx context.Context) (*models.ServerHandover, error) {
+	gracePeriod := reconnectGracePeriod(ctx, s.systemSettingsRepo)
+	handover, err := s.handoverRepo.ClaimLatest(ctx, time.Now().Add(-time.Hour))
+	if err != nil || handover == nil {
+		return nil, err
+	}
+
+	if age := time.Since(handover.DrainedAt); age > handover.ReconnectAfter()+gracePeriod {
+		debug.Warning("Ignoring server handover %d from %v ago, its agents stopped waiting", handover.ID, age.Round(time.Second))
+		return nil, nil
+	}
+	debug.Info("Resuming server handover %d with %d in-flight tasks", handover.ID, len(handover.Tasks))
+	return handover, nil
+}
+
+// reconnectGracePeriod returns how long tasks wait for their agent to
+// reconnect before they are retried
+func reconnectGracePeriod(ctx context.Context, systemSettingsRepo *repository.SystemSettingsRepository) time.Duration {
+	gracePeriod := 5 * time.Minute
+	setting, err := systemSettingsRepo.GetSetting(ctx, "reconnect_grace_period_minutes")
+	if err == nil && setting.Value != nil {
+		if minutes, err := strconv.Atoi(*setting.Value); err == nil {
+			gracePeriod = time.Duration(minutes) * time.Minute
+		}
+	}
+	return gracePeriod
+}
@@ -0,0 +1,193 @@
+// Package settingspresets tunes the system settings for a deployment size in
+// one step. A preset sets coherent values for the settings that depend on how
+// many agents and users an instance has: chunk sizes, rule splitting, agent
+// concurrency, heartbeat and reconnect timeouts and refresh intervals. An
+// admin previews what a preset would change before applying it.
+package settingspresets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// ErrNotFound is returned for unknown presets
+var ErrNotFound = errors.New("settings preset not found")
+
+// Preset is a named set of setting values
+type Preset struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Settings    map[string]string `json:"settings"`
+}
+
+// Change is the effect of a preset on one setting
+type Change struct {
+	Key string `json:"key"`
+	// Current is nil when the setting has no value
+	Current  *string `json:"current"`
+	Proposed string  `json:"proposed"`
+	Changed  bool    `json:"changed"`
+}
+
+// presets are ordered from the smallest to the largest deployment
+var presets = []Preset{
+	{
+		ID:          "small_lab",
+		Name:        "Small lab",
+		Description: "Up to 5 agents on a local network. Short chunks keep retries cheap and let new jobs get a turn quickly, and offline agents are noticed fast.",
+		Settings: map[string]string{
+			"default_chunk_duration":                   "600",
+			"chunk_fluctuation_percentage":             "20",
+			"max_concurrent_jobs_per_agent":            "1",
+			"progress_reporting_interval":              "30",
+			"task_heartbeat_timeout_minutes":           "5",
+			"slow_hash_task_heartbeat_timeout_minutes": "15",
+			"agent_heartbeat_timeout_seconds":          "90",
+			"reconnect_grace_period_minutes":           "5",
+			"rule_split_enabled":                       "true",
+			"rule_split_threshold":                     "2.0",
+			"rule_split_min_rules":                     "100",
+			"rule_split_max_chunks":                    "200",
+			"job_refresh_interval_seconds":             "5",
+			"agent_max_concurrent_downloads":           "3",
+			"potfile_batch_interval":                   "30",
+			"speculative_dispatch_enabled":             "false",
+		},
+	},
+	{
+		ID:          "mid_team",
+		Name:        "Mid-size team",
+		Description: "5 to 20 agents shared by a team. The default chunk length and longer timeouts, so brief network trouble does not reassign work.",
+		Settings: map[string]string{
+			"default_chunk_duration":                   "1200",
+			"chunk_fluctuation_percentage":             "20",
+			"max_concurrent_jobs_per_agent":            "1",
+			"progress_reporting_interval":              "60",
+			"task_heartbeat_timeout_minutes":           "10",
+			"slow_hash_task_heartbeat_timeout_minutes": "30",
+			"agent_heartbeat_timeout_seconds":          "120",
+			"reconnect_grace_period_minutes":           "10",
+			"rule_split_enabled":                       "true",
+			"rule_split_threshold":                     "2.0",
+			"rule_split_min_rules":                     "100",
+			"rule_split_max_chunks":                    "1000",
+			"job_refresh_interval_seconds":             "10",
+			"agent_max_concurrent_downloads":           "5",
+			"potfile_batch_interval":                   "60",
+			"speculative_dispatch_enabled":             "true",
+		},
+	},
+	{
+		ID:          "large_cluster",
+		Name:        "Large cluster",
+		Description: "More than 20 agents, possibly across sites. Longer chunks and less frequent progress reports reduce backend load, agents run two jobs at once to keep multi-GPU hosts busy, rules are split more aggressively and timeouts tolerate longer network interruptions.",
+		Settings: map[string]string{
+			"default_chunk_duration":                   "1800",
+			"chunk_fluctuation_percentage":             "30",
+			"max_concurrent_jobs_per_agent":            "2",
+			"progress_reporting_interval":              "120",
+			"task_heartbeat_timeout_minutes":           "15",
+			"slow_hash_task_heartbeat_timeout_minutes": "45",
+			"agent_heartbeat_timeout_seconds":          "180",
+			"reconnect_grace_period_minutes":           "15",
+			"rule_split_enabled":                       "true",
+			"rule_split_threshold":                     "1.5",
+			"rule_split_min_rules":                     "50",
+			"rule_split_max_chunks":                    "5000",
+			"job_refresh_interval_seconds":             "15",
+			"agent_max_concurrent_downloads":           "10",
+			"potfile_batch_interval":                   "120",
+			"speculative_dispatch_enabled":             "true",
+		},
+	},
+}
+
+// SettingsPresetService previews and applies settings presets
+type SettingsPresetService struct {
+	settingsRepo *repository.SystemSettingsRepository
+}
+
+// NewSettingsPresetService creates a new SettingsPresetService
+func NewSettingsPresetService(settingsRepo *repository.SystemSettingsRepository) *SettingsPresetService {
+	return &SettingsPresetService{settingsRepo: settingsRepo}
+}
+
+// List returns the available presets
+func (s *SettingsPresetService) List() []Preset {
+	return presets
+}
+
+// Preview returns what applying a preset would change, sorted by setting key
+func (s *SettingsPresetService) Preview(ctx context.Context, id string) ([]Change, error) {
+	preset, err := findPreset(id)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settingsRepo.GetAllSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*string, len(settings))
+	for _, setting := range settings {
+		current[setting.Key] = setting.Value
+	}
+
+	return diff(preset, current), nil
+}
+
+// Apply sets the settings a preset changes in one transaction and returns the
+// changes it made
+func (s *SettingsPresetService) Apply(ctx context.Context, id string) ([]Change, error) {
+	changes, err := s.Preview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, change := range changes {
+		if change.Changed {
+			values[change.Key] = change.Proposed
+		}
+	}
+	if len(values) > 0 {
+		if err := s.settingsRepo.SetSettings(ctx, values); err != nil {
+			return nil, fmt.Errorf("failed to apply settings preset %s: %w", id, err)
+		}
+	}
+
+	debug.Info("Applied settings preset %s, %d settings changed", id, len(values))
+	return changes, nil
+}
+
+// findPreset returns the preset with an ID
+func findPreset(id string) (*Preset, error) {
+	for i := range presets {
+		if presets[i].ID == id {
+			return &presets[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// diff compares a preset with the current setting values
+func diff(preset *Preset, current map[string]*string) []Change {
+	changes := make([]Change, 0, len(preset.Settings))
+	for key, proposed := range preset.Settings {
+		value := current[key]
+		changes = append(changes, Change{
+			Key:      key,
+			Current:  value,
+			Proposed: proposed,
+			Changed:  value == nil || *value != proposed,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
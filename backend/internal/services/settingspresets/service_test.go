@@ -0,0 +1,53 @@
+package settingspresets
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresetsSetTheSameSettings(t *testing.T) {
+	require.NotEmpty(t, presets)
+	for _, preset := range presets[1:] {
+		assert.Len(t, preset.Settings, len(presets[0].Settings), preset.ID)
+		for key := range presets[0].Settings {
+			assert.Contains(t, preset.Settings, key, preset.ID)
+		}
+	}
+}
+
+func TestFindPreset(t *testing.T) {
+	preset, err := findPreset("mid_team")
+	require.NoError(t, err)
+	assert.Equal(t, "Mid-size team", preset.Name)
+
+	_, err = findPreset("huge")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestDiff(t *testing.T) {
+	same, other := "600", "1200"
+	preset := &Preset{Settings: map[string]string{
+		"default_chunk_duration":       "600",
+		"job_refresh_interval_seconds": "5",
+		"rule_split_max_chunks":        "200",
+	}}
+
+	changes := diff(preset, map[string]*string{
+		"default_chunk_duration":       &same,
+		"job_refresh_interval_seconds": &other,
+		"rule_split_max_chunks":        nil,
+	})
+
+	require.Len(t, changes, 3)
+	assert.Equal(t, "default_chunk_duration", changes[0].Key)
+	assert.False(t, changes[0].Changed)
+	assert.Equal(t, "job_refresh_interval_seconds", changes[1].Key)
+	assert.True(t, changes[1].Changed)
+	assert.Equal(t, "1200", *changes[1].Current)
+	assert.Equal(t, "rule_split_max_chunks", changes[2].Key)
+	assert.True(t, changes[2].Changed)
+	assert.Nil(t, changes[2].Current)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// defaultSystemEventRetentionDays applies when system_event_retention_days is
+// missing or invalid
+const defaultSystemEventRetentionDays = 30
+
+// SystemEventService records operational events and purges them once they
+// are older than the retention period. Recording never fails the caller: an
+// event that cannot be stored is only logged.
+type SystemEventService struct {
+	eventRepo          *repository.SystemEventRepository
+	systemSettingsRepo *repository.SystemSettingsRepository
+}
+
+// NewSystemEventService creates a new system event service
+func NewSystemEventService(eventRepo *repository.SystemEventRepository, systemSettingsRepo *repository.SystemSettingsRepository) *SystemEventService {
+	return &SystemEventService{
+		eventRepo:          eventRepo,
+		systemSettingsRepo: systemSettingsRepo,
+	}
+}
+
+// Record stores an event. A nil service records nothing, so optional
+// dependents can call it unconditionally.
+func (s *SystemEventService) Record(ctx context.Context, event *models.SystemEvent) {
+	if s == nil {
+		return
+	}
+	// The event outlives the request or connection it came from
+	ctx = context.WithoutCancel(ctx)
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		debug.Error("Failed to record %s system event: %v", event.EventType, err)
+	}
+}
+
+// ListEvents returns a filtered page of events and the total matching
+func (s *SystemEventService) ListEvents(ctx context.Context, filter repository.SystemEventFilter, orderBy string, limit, offset int) ([]models.SystemEvent, int, error) {
+	return s.eventRepo.List(ctx, filter, orderBy, limit, offset)
+}
+
+// StartPurgeScheduler purges expired events on startup and then daily
+func (s *SystemEventService) StartPurgeScheduler(ctx context.Context) {
+	s.purge(ctx)
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			debug.Info("System event purge scheduler stopped")
+			return
+		case <-ticker.C:
+			s.purge(ctx)
+		}
+	}
+}
+
+// purge deletes events older than the retention period
+func (s *SystemEventService) purge(ctx context.Context) {
+	days := defaultSystemEventRetentionDays
+	if value, ok := positiveIntSetting(ctx, s.systemSettingsRepo, "system_event_retention_days"); ok {
+		days = value
+	}
+	n, err := s.eventRepo.DeleteOlderThan(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		debug.Error("System event purge failed: %v", err)
+		return
+	}
+	if n > 0 {
+		debug.Info("Purged %d system events older than %d days", n, days)
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+)
+
+// defaultTaskArtifactRetentionDays applies when task_artifact_retention_days is missing or invalid
+const defaultTaskArtifactRetentionDays = 30
+
+// TaskArtifactCleanupService deletes task artifacts past their retention period
+type TaskArtifactCleanupService struct {
+	artifactRepo       *repository.TaskArtifactRepository
+	systemSettingsRepo *repository.SystemSettingsRepository
+}
+
+// NewTaskArtifactCleanupService creates a new task artifact cleanup service
+func NewTaskArtifactCleanupService(artifactRepo *repository.TaskArtifactRepository, systemSettingsRepo *repository.SystemSettingsRepository) *TaskArtifactCleanupService {
+	return &TaskArtifactCleanupService{
+		artifactRepo:       artifactRepo,
+		systemSettingsRepo: systemSettingsRepo,
+	}
+}
+
+// StartCleanupScheduler runs the cleanup on startup and then daily
+func (s *TaskArtifactCleanupService) StartCleanupScheduler(ctx context.Context) {
+	s.runCleanup(ctx)
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			debug.Info("Task artifact cleanup scheduler stopped")
+			return
+		case <-ticker.C:
+			s.runCleanup(ctx)
+		}
+	}
+}
+
+// runCleanup deletes artifacts older than task_artifact_retention_days, 0 keeps them forever
+func (s *TaskArtifactCleanupService) runCleanup(ctx context.Context) {
+	days := defaultTaskArtifactRetentionDays
+	if setting, err := s.systemSettingsRepo.GetSetting(ctx, "task_artifact_retention_days"); err == nil && setting.Value != nil {
+		if v, err := strconv.Atoi(*setting.Value); err == nil && v >= 0 {
+			days = v
+		}
+	}
+
+	if days == 0 {
+		debug.Info("Task artifact retention is unlimited, skipping cleanup")
+		return
+	}
+
+	deleted, err := s.artifactRepo.DeleteOlderThanDays(ctx, days)
+	if err != nil {
+		debug.Error("Failed to clean up task artifacts: %v", err)
+		return
+	}
+	if deleted > 0 {
+		debug.Info("Deleted %d task artifacts older than %d days", deleted, days)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+)
+
+// Defaults used when the task staleness settings are missing or invalid
+const (
+	defaultAgentHeartbeatTimeout          = 90 * time.Second
+	defaultTaskHeartbeatTimeout           = 5 * time.Minute
+	defaultSlowHashTaskHeartbeatTimeout   = 15 * time.Minute
+	defaultTaskHeartbeatMaxGraceDoublings = 3
+)
+
+// lateReportMemory is how long a task that went quiet on a connected agent
+// counts towards that agent's grace
+const lateReportMemory = 24 * time.Hour
+
+// taskStaleness holds how long a running task may go without progress before
+// it is reclaimed
+type taskStaleness struct {
+	timeout           time.Duration // Tasks of fast hash types
+	slowTimeout       time.Duration // Tasks of slow hash types
+	maxGraceDoublings int           // Cap on how often an agent's timeout is doubled
+}
+
+// loadTaskStaleness reads the task staleness settings
+func loadTaskStaleness(ctx context.Context, systemSettingsRepo *repository.SystemSettingsRepository) taskStaleness {
+	staleness := taskStaleness{
+		timeout:           defaultTaskHeartbeatTimeout,
+		slowTimeout:       defaultSlowHashTaskHeartbeatTimeout,
+		maxGraceDoublings: defaultTaskHeartbeatMaxGraceDoublings,
+	}
+
+	if minutes, ok := positiveIntSetting(ctx, systemSettingsRepo, "task_heartbeat_timeout_minutes"); ok {
+		staleness.timeout = time.Duration(minutes) * time.Minute
+	} else if minutes, ok := positiveIntSetting(ctx, systemSettingsRepo, "task_timeout_minutes"); ok {
+		// Fall back to task_timeout_minutes if heartbeat setting doesn't exist
+		staleness.timeout = time.Duration(minutes) * time.Minute
+	}
+	if minutes, ok := positiveIntSetting(ctx, systemSettingsRepo, "slow_hash_task_heartbeat_timeout_minutes"); ok {
+		staleness.slowTimeout = time.Duration(minutes) * time.Minute
+	}
+	setting, err := systemSettingsRepo.GetSetting(ctx, "task_heartbeat_max_grace_doublings")
+	if err == nil && setting.Value != nil {
+		if doublings, err := strconv.Atoi(*setting.Value); err == nil && doublings >= 0 && doublings <= 10 {
+			staleness.maxGraceDoublings = doublings
+		}
+	}
+	return staleness
+}
+
+// shortest returns the smallest timeout any task can have
+func (t taskStaleness) shortest() time.Duration {
+	if t.slowTimeout < t.timeout {
+		return t.slowTimeout
+	}
+	return t.timeout
+}
+
+// timeoutFor returns the timeout of a task, doubled for each recent late
+// report of its agent up to maxGraceDoublings
+func (t taskStaleness) timeoutFor(slowHash bool, lateReports int) time.Duration {
+	timeout := t.timeout
+	if slowHash {
+		timeout = t.slowTimeout
+	}
+	if lateReports > t.maxGraceDoublings {
+		lateReports = t.maxGraceDoublings
+	}
+	return timeout << lateReports
+}
+
+// lastActivity returns when the task last reported progress or changed
+func lastActivity(task models.JobTask) time.Time {
+	if task.LastCheckpoint != nil && task.LastCheckpoint.After(task.UpdatedAt) {
+		return *task.LastCheckpoint
+	}
+	return task.UpdatedAt
+}
+
+// AgentHeartbeatTimeout returns how long an agent may go without a heartbeat
+// before it is marked inactive
+func AgentHeartbeatTimeout(ctx context.Context, systemSettingsRepo *repository.SystemSettingsRepository) time.Duration {
+	if seconds, ok := positiveIntSetting(ctx, systemSettingsRepo, "agent_heartbeat_timeout_seconds"); ok {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultAgentHeartbeatTimeout
+}
+
+// positiveIntSetting reads a setting that must be a positive integer
+func positiveIntSetting(ctx context.Context, systemSettingsRepo *repository.SystemSettingsRepository, key string) (int, bool) {
+	setting, err := systemSettingsRepo.GetSetting(ctx, key)
+	if err != nil || setting.Value == nil {
+		return 0, false
+	}
+	value, err := strconv.Atoi(*setting.Value)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+	return value, true
+}
+
+// lateReports remembers which agents had a task go quiet past its timeout
+// while the agent kept sending heartbeats. Such agents are alive but slow to
+// report status, so their tasks get more time before they are reclaimed.
+type lateReports struct {
+	mu     sync.Mutex
+	agents map[int][]time.Time
+}
+
+func newLateReports() *lateReports {
+	return &lateReports{agents: make(map[int][]time.Time)}
+}
+
+// count returns the agent's late reports within lateReportMemory of now
+func (l *lateReports) count(agentID int, now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := l.agents[agentID][:0]
+	for _, at := range l.agents[agentID] {
+		if now.Sub(at) < lateReportMemory {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) == 0 {
+		delete(l.agents, agentID)
+		return 0
+	}
+	l.agents[agentID] = recent
+	return len(recent)
+}
+
+// record adds a late report for the agent
+func (l *lateReports) record(agentID int, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.agents[agentID] = append(l.agents[agentID], now)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskStalenessTimeouts(t *testing.T) {
+	staleness := taskStaleness{timeout: 5 * time.Minute, slowTimeout: 15 * time.Minute, maxGraceDoublings: 2}
+
+	assert.Equal(t, 5*time.Minute, staleness.shortest())
+	assert.Equal(t, 5*time.Minute, staleness.timeoutFor(false, 0))
+	assert.Equal(t, 15*time.Minute, staleness.timeoutFor(true, 0))
+
+	// The grace doubles per late report up to the cap
+	assert.Equal(t, 10*time.Minute, staleness.timeoutFor(false, 1))
+	assert.Equal(t, 60*time.Minute, staleness.timeoutFor(true, 2))
+	assert.Equal(t, 20*time.Minute, staleness.timeoutFor(false, 7))
+
+	staleness.maxGraceDoublings = 0
+	assert.Equal(t, 5*time.Minute, staleness.timeoutFor(false, 3))
+}
+
+func TestLateReportsExpire(t *testing.T) {
+	reports := newLateReports()
+	start := time.Now()
+
+	reports.record(1, start)
+	reports.record(1, start.Add(time.Hour))
+	assert.Equal(t, 2, reports.count(1, start.Add(2*time.Hour)))
+	assert.Equal(t, 0, reports.count(2, start))
+
+	// Late reports are forgotten after a day
+	assert.Equal(t, 1, reports.count(1, start.Add(lateReportMemory+time.Minute)))
+	assert.Equal(t, 0, reports.count(1, start.Add(lateReportMemory+2*time.Hour)))
+}
+
+func TestLastActivity(t *testing.T) {
+	updated := time.Now().Add(-time.Hour)
+	checkpoint := updated.Add(30 * time.Minute)
+
+	assert.Equal(t, updated, lastActivity(models.JobTask{UpdatedAt: updated}))
+	assert.Equal(t, checkpoint, lastActivity(models.JobTask{UpdatedAt: updated, LastCheckpoint: &checkpoint}))
+}
@@ -0,0 +1,288 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// Settings controlling telemetry publishing and collection
+const (
+	settingEnabled        = "telemetry_enabled"
+	settingEndpoint       = "telemetry_endpoint"
+	settingToken          = "telemetry_token"
+	settingIntervalHours  = "telemetry_interval_hours"
+	settingInstanceLabel  = "telemetry_instance_label"
+	settingInstanceID     = "telemetry_instance_id"
+	settingLastSentAt     = "telemetry_last_sent_at"
+	settingCollectorToken = "telemetry_collector_token"
+)
+
+const (
+	// defaultInterval is used when telemetry_interval_hours is missing or invalid
+	defaultInterval = 24 * time.Hour
+	// schedulerTick is how often the scheduler checks whether a report is due
+	schedulerTick = time.Hour
+	// publishTimeout bounds a single request to the collector
+	publishTimeout = 30 * time.Second
+)
+
+var (
+	// ErrDisabled is returned when publishing is turned off
+	ErrDisabled = errors.New("telemetry publishing is disabled")
+	// ErrAirgapped is returned when publishing on an air-gapped install
+	ErrAirgapped = errors.New("telemetry cannot be published in air-gapped mode")
+	// ErrCollectorDisabled is returned for reports sent to an instance that does not collect them
+	ErrCollectorDisabled = errors.New("this instance does not accept telemetry reports")
+	// ErrUnauthorized is returned for reports with a wrong collector token
+	ErrUnauthorized = errors.New("invalid telemetry token")
+	// ErrInvalidReport is returned for reports that fail validation
+	ErrInvalidReport = errors.New("invalid telemetry report")
+)
+
+// TelemetryService publishes anonymized cracking statistics to a collector
+// when an admin opts in, and acts as the collector for other instances when a
+// collector token is configured.
+type TelemetryService struct {
+	repo         *repository.TelemetryRepository
+	settingsRepo *repository.SystemSettingsRepository
+	client       *http.Client
+	airgapped    bool
+}
+
+// NewTelemetryService creates a new TelemetryService. Air-gapped installs
+// never publish but can still collect.
+func NewTelemetryService(repo *repository.TelemetryRepository, sr *repository.SystemSettingsRepository, airgapped bool) *TelemetryService {
+	return &TelemetryService{
+		repo:         repo,
+		settingsRepo: sr,
+		client:       &http.Client{Timeout: publishTimeout},
+		airgapped:    airgapped,
+	}
+}
+
+// StartScheduler publishes a report whenever one is due until ctx is done
+func (s *TelemetryService) StartScheduler(ctx context.Context) {
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+
+	for {
+		s.publishIfDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			debug.Info("Telemetry scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishIfDue publishes a report when publishing is enabled and the
+// interval since the last report has passed
+func (s *TelemetryService) publishIfDue(ctx context.Context) {
+	if !s.publishingEnabled(ctx) {
+		return
+	}
+	if s.airgapped {
+		debug.Warning("Telemetry is enabled but cannot be published in air-gapped mode")
+		return
+	}
+	if !isDue(s.lastSentAt(ctx), s.interval(ctx), time.Now()) {
+		return
+	}
+	if _, err := s.Publish(ctx); err != nil {
+		debug.Error("Failed to publish telemetry report: %v", err)
+	}
+}
+
+// BuildReport collects the statistics that would be published now
+func (s *TelemetryService) BuildReport(ctx context.Context) (*models.TelemetryReport, error) {
+	instanceID, err := uuid.Parse(s.settingValue(ctx, settingInstanceID))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", settingInstanceID, err)
+	}
+
+	// Whole seconds so the stored end of the period matches the next start
+	now := time.Now().UTC().Truncate(time.Second)
+	report := &models.TelemetryReport{
+		SchemaVersion: models.TelemetrySchemaVersion,
+		InstanceID:    instanceID,
+		InstanceLabel: s.settingValue(ctx, settingInstanceLabel),
+		PeriodStart:   periodStart(s.lastSentAt(ctx), s.interval(ctx), now),
+		PeriodEnd:     now,
+	}
+
+	if report.Agents, err = s.repo.CountAgents(ctx); err != nil {
+		return nil, err
+	}
+	if report.HashTypes, err = s.repo.GetHashTypeStats(ctx); err != nil {
+		return nil, err
+	}
+	if report.AttackModes, err = s.repo.GetAttackModeStats(ctx, report.PeriodStart, report.PeriodEnd); err != nil {
+		return nil, err
+	}
+	report.FillRates()
+
+	return report, nil
+}
+
+// Publish sends a report to the configured collector and returns it
+func (s *TelemetryService) Publish(ctx context.Context) (*models.TelemetryReport, error) {
+	if !s.publishingEnabled(ctx) {
+		return nil, ErrDisabled
+	}
+	if s.airgapped {
+		return nil, ErrAirgapped
+	}
+	endpoint := s.settingValue(ctx, settingEndpoint)
+	if endpoint == "" {
+		return nil, fmt.Errorf("%s is not configured", settingEndpoint)
+	}
+
+	report, err := s.BuildReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode telemetry report: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := s.settingValue(ctx, settingToken); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send telemetry report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("collector rejected telemetry report: %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+
+	lastSent := report.PeriodEnd.Format(time.RFC3339)
+	if err := s.settingsRepo.SetSetting(ctx, settingLastSentAt, &lastSent); err != nil {
+		debug.Warning("Failed to record telemetry publication time: %v", err)
+	}
+
+	debug.Info("Published telemetry report for %s to %s", report.PeriodStart.Format(time.RFC3339), endpoint)
+	return report, nil
+}
+
+// Receive stores a report published by another instance. token is the bearer
+// token of the request.
+func (s *TelemetryService) Receive(ctx context.Context, token string, report *models.TelemetryReport) (int64, error) {
+	collectorToken := s.settingValue(ctx, settingCollectorToken)
+	if collectorToken == "" {
+		return 0, ErrCollectorDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(collectorToken)) != 1 {
+		return 0, ErrUnauthorized
+	}
+
+	if err := report.Validate(time.Now()); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidReport, err)
+	}
+	// Rates are derived here rather than trusted from the sender
+	report.FillRates()
+
+	id, err := s.repo.StoreReport(ctx, report)
+	if err != nil {
+		return 0, err
+	}
+
+	debug.Info("Received telemetry report %d from instance %s", id, report.InstanceID)
+	return id, nil
+}
+
+// ListInstances returns the latest report of every instance reporting to this collector
+func (s *TelemetryService) ListInstances(ctx context.Context) ([]models.TelemetryInstanceReport, error) {
+	return s.repo.ListLatestReports(ctx)
+}
+
+// ListInstanceReports returns the most recent reports of one instance
+func (s *TelemetryService) ListInstanceReports(ctx context.Context, instanceID uuid.UUID, limit int) ([]models.TelemetryInstanceReport, error) {
+	return s.repo.ListInstanceReports(ctx, instanceID, limit)
+}
+
+// publishingEnabled reports whether the admin opted in to publishing
+func (s *TelemetryService) publishingEnabled(ctx context.Context) bool {
+	enabled, _ := strconv.ParseBool(s.settingValue(ctx, settingEnabled))
+	return enabled
+}
+
+// interval returns the configured time between reports
+func (s *TelemetryService) interval(ctx context.Context) time.Duration {
+	hours, err := strconv.Atoi(s.settingValue(ctx, settingIntervalHours))
+	if err != nil || hours < 1 {
+		return defaultInterval
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// lastSentAt returns the end of the last published period, or the zero time
+func (s *TelemetryService) lastSentAt(ctx context.Context) time.Time {
+	value := s.settingValue(ctx, settingLastSentAt)
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		debug.Warning("Invalid %s value %q: %v", settingLastSentAt, value, err)
+		return time.Time{}
+	}
+	return t
+}
+
+// settingValue returns a system setting, or "" when it is missing
+func (s *TelemetryService) settingValue(ctx context.Context, key string) string {
+	setting, err := s.settingsRepo.GetSetting(ctx, key)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			debug.Warning("Failed to read setting %s: %v", key, err)
+		}
+		return ""
+	}
+	if setting.Value == nil {
+		return ""
+	}
+	return *setting.Value
+}
+
+// isDue reports whether the next report should be published
+func isDue(lastSent time.Time, interval time.Duration, now time.Time) bool {
+	return lastSent.IsZero() || !now.Before(lastSent.Add(interval))
+}
+
+// periodStart returns where the next report's period begins: where the last
+// one ended, or one interval back for the first report. Gaps longer than an
+// interval, e.g. while publishing was off, are not reported retroactively.
+func periodStart(lastSent time.Time, interval time.Duration, now time.Time) time.Time {
+	earliest := now.Add(-interval)
+	if lastSent.IsZero() || lastSent.Before(earliest) || !lastSent.Before(now) {
+		return earliest
+	}
+	return lastSent
+}
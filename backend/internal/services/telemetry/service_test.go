@@ -0,0 +1,26 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDue(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, isDue(time.Time{}, 24*time.Hour, now), "never published")
+	assert.False(t, isDue(now.Add(-23*time.Hour), 24*time.Hour, now))
+	assert.True(t, isDue(now.Add(-24*time.Hour), 24*time.Hour, now))
+}
+
+func TestPeriodStart(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	interval := 24 * time.Hour
+
+	assert.Equal(t, now.Add(-interval), periodStart(time.Time{}, interval, now), "first report covers one interval")
+	assert.Equal(t, now.Add(-6*time.Hour), periodStart(now.Add(-6*time.Hour), interval, now), "continues from the last report")
+	assert.Equal(t, now.Add(-interval), periodStart(now.Add(-72*time.Hour), interval, now), "long gaps are not reported")
+	assert.Equal(t, now.Add(-interval), periodStart(now.Add(time.Hour), interval, now), "last report in the future")
+}
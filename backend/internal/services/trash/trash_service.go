@@ -0,0 +1,205 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/client"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/services/retention"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
+)
+
+// TrashService soft-deletes hashlists, jobs and clients, restores them and
+// permanently purges them once the trash retention window has passed.
+// With a window of 0 days every deletion is permanent immediately.
+type TrashService struct {
+	trashRepo        *repository.TrashRepository
+	hashlistRepo     *repository.HashListRepository
+	jobExecRepo      *repository.JobExecutionRepository
+	clientRepo       *repository.ClientRepository
+	retentionService *retention.RetentionService
+	clientService    *client.ClientService
+}
+
+// NewTrashService creates a new TrashService.
+func NewTrashService(tr *repository.TrashRepository, hr *repository.HashListRepository, jr *repository.JobExecutionRepository, cr *repository.ClientRepository, retsvc *retention.RetentionService, clientsvc *client.ClientService) *TrashService {
+	return &TrashService{
+		trashRepo:        tr,
+		hashlistRepo:     hr,
+		jobExecRepo:      jr,
+		clientRepo:       cr,
+		retentionService: retsvc,
+		clientService:    clientsvc,
+	}
+}
+
+// DeleteHashlist moves a hashlist to the trash, or deletes it permanently when the trash is disabled.
+func (s *TrashService) DeleteHashlist(ctx context.Context, hashlistID int64, deletedBy *uuid.UUID) error {
+	if s.retentionService.TrashRetentionDays(ctx) == 0 {
+		if _, err := s.hashlistRepo.GetByID(ctx, hashlistID); err != nil {
+			return err
+		}
+		return s.retentionService.DeleteHashlistAndOrphanedHashes(ctx, hashlistID)
+	}
+
+	if err := s.hashlistRepo.SoftDelete(ctx, hashlistID, deletedBy); err != nil {
+		return err
+	}
+	debug.Info("Moved hashlist %d to trash", hashlistID)
+	return nil
+}
+
+// DeleteJob moves a job execution to the trash, or deletes it permanently when the trash is disabled.
+// Callers are expected to have stopped any agents still working on the job.
+func (s *TrashService) DeleteJob(ctx context.Context, jobID uuid.UUID, deletedBy *uuid.UUID) error {
+	if s.retentionService.TrashRetentionDays(ctx) == 0 {
+		return s.jobExecRepo.Delete(ctx, jobID)
+	}
+
+	if err := s.jobExecRepo.SoftDelete(ctx, jobID, deletedBy); err != nil {
+		return err
+	}
+	debug.Info("Moved job %s to trash", jobID)
+	return nil
+}
+
+// DeleteFinishedJobs moves all finished job executions to the trash, or deletes them permanently
+// when the trash is disabled. It returns the number of jobs removed.
+func (s *TrashService) DeleteFinishedJobs(ctx context.Context, deletedBy *uuid.UUID) (int, error) {
+	if s.retentionService.TrashRetentionDays(ctx) == 0 {
+		return s.jobExecRepo.DeleteFinished(ctx)
+	}
+	return s.jobExecRepo.SoftDeleteFinished(ctx, deletedBy)
+}
+
+// DeleteClient moves a client to the trash, or deletes it permanently when the trash is disabled.
+// The client's hashlists are only handled according to its retention policy once it is purged.
+func (s *TrashService) DeleteClient(ctx context.Context, clientID uuid.UUID, deletedBy *uuid.UUID) error {
+	if s.retentionService.TrashRetentionDays(ctx) == 0 {
+		return s.clientService.DeleteClient(ctx, clientID)
+	}
+
+	if err := s.clientRepo.SoftDelete(ctx, clientID, deletedBy); err != nil {
+		return err
+	}
+	debug.Info("Moved client %s to trash", clientID)
+	return nil
+}
+
+// List returns a page of trashed items along with when each will be purged.
+func (s *TrashService) List(ctx context.Context, itemType models.TrashItemType, limit, offset int) ([]models.TrashItem, int, error) {
+	items, total, err := s.trashRepo.List(ctx, itemType, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if days := s.retentionService.TrashRetentionDays(ctx); days > 0 {
+		for i := range items {
+			purgeAt := items[i].DeletedAt.AddDate(0, 0, days)
+			items[i].PurgeAt = &purgeAt
+		}
+	}
+	return items, total, nil
+}
+
+// Restore takes an item out of the trash. A malformed ID is reported as
+// repository.ErrNotFound since it can never be in the trash.
+func (s *TrashService) Restore(ctx context.Context, itemType models.TrashItemType, id string) error {
+	var err error
+	switch itemType {
+	case models.TrashItemHashlist:
+		hashlistID, parseErr := strconv.ParseInt(id, 10, 64)
+		if parseErr != nil {
+			return repository.ErrNotFound
+		}
+		err = s.hashlistRepo.Restore(ctx, hashlistID)
+	case models.TrashItemJob:
+		jobID, parseErr := uuid.Parse(id)
+		if parseErr != nil {
+			return repository.ErrNotFound
+		}
+		err = s.jobExecRepo.Restore(ctx, jobID)
+	case models.TrashItemClient:
+		clientID, parseErr := uuid.Parse(id)
+		if parseErr != nil {
+			return repository.ErrNotFound
+		}
+		err = s.clientRepo.Restore(ctx, clientID)
+	default:
+		return fmt.Errorf("unknown trash item type %q", itemType)
+	}
+	if err != nil {
+		return err
+	}
+
+	debug.Info("Restored %s %s from trash", itemType, id)
+	return nil
+}
+
+// purge permanently deletes a single trashed item.
+func (s *TrashService) purge(ctx context.Context, item models.TrashItem) error {
+	switch item.Type {
+	case models.TrashItemHashlist:
+		hashlistID, err := strconv.ParseInt(item.ID, 10, 64)
+		if err != nil {
+			return err
+		}
+		return s.retentionService.DeleteHashlistAndOrphanedHashes(ctx, hashlistID)
+	case models.TrashItemJob:
+		jobID, err := uuid.Parse(item.ID)
+		if err != nil {
+			return err
+		}
+		return s.jobExecRepo.Delete(ctx, jobID)
+	case models.TrashItemClient:
+		clientID, err := uuid.Parse(item.ID)
+		if err != nil {
+			return err
+		}
+		return s.clientService.DeleteClient(ctx, clientID)
+	}
+	return fmt.Errorf("unknown trash item type %q", item.Type)
+}
+
+// PurgeExpired permanently deletes every item that has been in the trash longer
+// than the retention window and returns the number purged. Jobs are purged
+// before hashlists and clients, since purging a hashlist cascades to its jobs.
+func (s *TrashService) PurgeExpired(ctx context.Context) (int, error) {
+	days := s.retentionService.TrashRetentionDays(ctx)
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	items, err := s.trashRepo.GetExpired(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+	debug.Info("Purging %d items that have been in the trash since before %s", len(items), cutoff.Format(time.RFC3339))
+
+	purged := 0
+	for _, itemType := range []models.TrashItemType{models.TrashItemJob, models.TrashItemHashlist, models.TrashItemClient} {
+		for _, item := range items {
+			if item.Type != itemType {
+				continue
+			}
+			if err := s.purge(ctx, item); err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					continue // Already removed along with its hashlist
+				}
+				debug.Error("Failed to purge %s %s from trash: %v", item.Type, item.ID, err)
+				continue
+			}
+			purged++
+		}
+	}
+
+	debug.Info("Trash purge completed, purged %d items", purged)
+	return purged, nil
+}
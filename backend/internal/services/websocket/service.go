@@ -11,6 +11,7 @@ import (
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/services"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
+	"github.com/google/uuid"
 )
 
 // JobHandler interface for handling job-related WebSocket messages
@@ -42,6 +43,9 @@ const (
 	TypeBufferedMessages MessageType = "buffered_messages"
 	TypeCurrentTaskStatus MessageType = "current_task_status"
 	TypeAgentShutdown    MessageType = "agent_shutdown"
+	TypeCrashReport      MessageType = "crash_report"
+	TypeCrackJournal     MessageType = "crack_journal" // Only replayed within buffered_messages
+	TypeCredentialRotationAck MessageType = "credential_rotation_ack"
 
 	// Server -> Agent messages
 	TypeTaskAssignment   MessageType = "task_assignment"
@@ -53,6 +57,8 @@ const (
 	TypeSyncCommand      MessageType = "file_sync_command"
 	TypeForceCleanup     MessageType = "force_cleanup"
 	TypeBufferAck        MessageType = "buffer_ack"
+	TypeServerDraining   MessageType = "server_draining"
+	TypeCredentialRotation MessageType = "credential_rotation"
 
 	// Download progress messages
 	TypeDownloadProgress MessageType = "download_progress"
@@ -66,6 +72,17 @@ const (
 	TypeSyncProgress  MessageType = "sync_progress"
 )
 
+// IsControl reports whether a message steers agent work. Control messages are
+// sent ahead of any queued bulk traffic such as file sync commands so a stop
+// or assignment is never stuck behind them.
+func (t MessageType) IsControl() bool {
+	switch t {
+	case TypeTaskAssignment, TypeJobStop, TypeBenchmarkRequest, TypeAgentCommand, TypeConfigUpdate, TypeForceCleanup, TypeServerDraining, TypeCredentialRotation:
+		return true
+	}
+	return false
+}
+
 // Client represents a connected agent
 type Client struct {
 	LastSeen time.Time
@@ -82,10 +99,14 @@ type Message struct {
 
 // HeartbeatPayload represents a heartbeat message from agent
 type HeartbeatPayload struct {
-	AgentID     int     `json:"agent_id"`
-	LoadAverage float64 `json:"load_average"`
-	MemoryUsage float64 `json:"memory_usage"`
-	DiskUsage   float64 `json:"disk_usage"`
+	AgentID        int      `json:"agent_id"`
+	LoadAverage    float64  `json:"load_average"`
+	MemoryUsage    float64  `json:"memory_usage"`
+	DiskUsage      float64  `json:"disk_usage"`
+	CurrentTaskID  string   `json:"current_task_id,omitempty"`
+	GPUUtilization *float64 `json:"gpu_utilization,omitempty"` // Average across enabled devices (0-100)
+	QueueDepth     int      `json:"queue_depth"`               // Messages waiting to be sent by the agent
+	ReconnectCount int      `json:"reconnect_count"`           // Reconnects since the agent process started
 }
 
 // TaskStatusPayload represents task status update from agent
@@ -107,6 +128,10 @@ type AgentStatusPayload struct {
 	UpdatedAt   time.Time              `json:"updated_at"`
 	Environment map[string]string      `json:"environment"`
 	OSInfo      map[string]interface{} `json:"os_info,omitempty"`
+	// DriverVersion lists the GPU driver versions of the agent's devices
+	DriverVersion string `json:"driver_version,omitempty"`
+	// BinaryVersion is the ID of the hashcat binary version the agent runs
+	BinaryVersion string `json:"binary_version,omitempty"`
 }
 
 // ErrorReportPayload represents detailed error report from agent
@@ -123,6 +148,7 @@ type FileSyncRequestPayload struct {
 	RequestID string   `json:"request_id"`
 	FileTypes []string `json:"file_types"`         // "wordlist", "rule", "binary", "hashlist"
 	Category  string   `json:"category,omitempty"` // Filter by category if needed
+	Audit     bool     `json:"audit,omitempty"`    // Rehash every file and echo the request ID, see AuditAgentFiles
 }
 
 // FileInfo represents information about a file for synchronization
@@ -134,6 +160,9 @@ type FileInfo struct {
 	Category  string `json:"category,omitempty"`
 	ID        int    `json:"id,omitempty"`
 	Timestamp int64  `json:"timestamp,omitempty"`
+	// MoveFrom names an identical file the agent already has under another
+	// name, the agent renames it instead of downloading the file again
+	MoveFrom string `json:"move_from,omitempty"`
 }
 
 // FileSyncResponsePayload represents the agent's response with its current files
@@ -146,7 +175,7 @@ type FileSyncResponsePayload struct {
 // FileSyncCommandPayload represents a command to download specific files
 type FileSyncCommandPayload struct {
 	RequestID string     `json:"request_id"`
-	Action    string     `json:"action"` // "download", "verify", etc.
+	Action    string     `json:"action"` // "download" or "delete"
 	Files     []FileInfo `json:"files"`
 }
 
@@ -179,6 +208,41 @@ type SyncCompletedPayload struct {
 	FilesSynced int `json:"files_synced"`
 }
 
+// ServerDrainingPayload tells agents the backend is restarting. Agents keep
+// running their tasks, buffer results and reconnect after the given delay.
+type ServerDrainingPayload struct {
+	ReconnectAfterSeconds int `json:"reconnect_after_seconds"`
+}
+
+// CredentialRotationPayload gives a connected agent a new API key, and a new
+// client certificate when the backend issues them. The agent stores them,
+// uses them from then on and confirms with a credential_rotation_ack. Its
+// current key stays valid for GracePeriodSeconds after the confirmation.
+type CredentialRotationPayload struct {
+	RotationID         string `json:"rotation_id"`
+	APIKey             string `json:"api_key"`
+	ClientCertificate  string `json:"client_certificate,omitempty"`
+	ClientKey          string `json:"client_key,omitempty"`
+	GracePeriodSeconds int    `json:"grace_period_seconds"`
+}
+
+// CredentialRotationAckPayload is the agent's answer to a credential
+// rotation. Without success the agent kept its current credentials.
+type CredentialRotationAckPayload struct {
+	RotationID string `json:"rotation_id"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CrackJournalPayload holds cracks an agent journaled while disconnected. It
+// may repeat cracks that were also delivered in a job progress message.
+type CrackJournalPayload struct {
+	ID            string               `json:"id"`
+	TaskID        uuid.UUID            `json:"task_id"`
+	CrackedHashes []models.CrackedHash `json:"cracked_hashes"`
+	Timestamp     time.Time            `json:"timestamp"`
+}
+
 // SyncFailedPayload represents sync failure notification from agent
 type SyncFailedPayload struct {
 	AgentID int    `json:"agent_id"`
@@ -212,6 +276,32 @@ type TaskAssignmentPayload struct {
 	OutputFormat    string   `json:"output_format"`
 	ExtraParameters string   `json:"extra_parameters,omitempty"`
 	EnabledDevices  []int    `json:"enabled_devices,omitempty"`
+	// JobExtraParameters are the job's own hashcat parameters, options in it
+	// replace the same options from ExtraParameters
+	JobExtraParameters string `json:"job_extra_parameters,omitempty"`
+	// ExtraArgs and JobArgs carry ExtraParameters and JobExtraParameters as
+	// argv tokens, passed to hashcat as they are. Agents that predate them
+	// split the string fields instead.
+	ExtraArgs []string `json:"extra_args,omitempty"`
+	JobArgs   []string `json:"job_args,omitempty"`
+	// FileHashes maps the wordlist and rule paths to their MD5 hashes on the
+	// server, so the agent can detect stale or corrupted local copies
+	FileHashes map[string]string `json:"file_hashes,omitempty"`
+	// RuleChunks are the registered rule chunk files of a rule-split task,
+	// downloaded by the agent through file sync
+	RuleChunks []RuleChunkFile `json:"rule_chunks,omitempty"`
+	// BinaryHashes maps the hashcat executable names in the binary archive to
+	// their SHA-256 hashes, which the agent checks its copy against before
+	// running the task
+	BinaryHashes map[string]string `json:"binary_hashes,omitempty"`
+}
+
+// RuleChunkFile describes a rule chunk file an agent downloads for a task
+type RuleChunkFile struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"` // Path below the rules directory, e.g. chunks/job_<id>/chunk_0_1000.rule
+	MD5Hash string `json:"md5_hash"`
+	Size    int64  `json:"size"`
 }
 
 // BenchmarkResultPayload represents benchmark results from an agent
@@ -257,6 +347,7 @@ type BenchmarkRequestPayload struct {
 	TestDuration    int      `json:"test_duration,omitempty"`    // Duration in seconds for speed test
 	TimeoutDuration int      `json:"timeout_duration,omitempty"` // Maximum time to wait for speedtest (seconds)
 	ExtraParameters string   `json:"extra_parameters,omitempty"` // Agent-specific hashcat parameters
+	ExtraArgs       []string `json:"extra_args,omitempty"`       // ExtraParameters as argv tokens
 	EnabledDevices  []int    `json:"enabled_devices,omitempty"`  // List of enabled device IDs
 }
 
@@ -328,6 +419,11 @@ func (s *Service) HandleMessage(ctx context.Context, agent *models.Agent, msg *M
 		// Agent shutdown is handled in the handler layer
 		// Just update heartbeat here
 		return nil
+	case TypeCrashReport:
+		return s.handleCrashReport(ctx, agent, msg)
+	case TypeCredentialRotationAck:
+		// Credential rotation is handled in the handler layer
+		return nil
 	case TypeSyncStarted:
 		return s.handleSyncStarted(ctx, agent, msg)
 	case TypeSyncCompleted:
@@ -362,9 +458,13 @@ func (s *Service) GetLastSeen(agentID int) time.Time {
 
 // handleHeartbeat processes heartbeat messages
 func (s *Service) handleHeartbeat(ctx context.Context, agent *models.Agent, msg *Message) error {
-	var payload HeartbeatPayload
-	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
-		return fmt.Errorf("failed to unmarshal heartbeat: %w", err)
+	// Older agents send heartbeats without a payload
+	var payload *HeartbeatPayload
+	if len(msg.Payload) > 0 {
+		payload = &HeartbeatPayload{}
+		if err := json.Unmarshal(msg.Payload, payload); err != nil {
+			return fmt.Errorf("failed to unmarshal heartbeat: %w", err)
+		}
 	}
 
 	// Update agent status in database
@@ -373,7 +473,25 @@ func (s *Service) handleHeartbeat(ctx context.Context, agent *models.Agent, msg
 	}
 
 	s.updateLastSeen(agent.ID)
-	return nil
+
+	if payload == nil {
+		return nil
+	}
+
+	heartbeat := &models.AgentHeartbeat{
+		AgentID:        agent.ID,
+		GPUUtilization: payload.GPUUtilization,
+		QueueDepth:     payload.QueueDepth,
+		ReconnectCount: payload.ReconnectCount,
+	}
+	if payload.CurrentTaskID != "" {
+		if taskID, err := uuid.Parse(payload.CurrentTaskID); err == nil {
+			heartbeat.CurrentTaskID = &taskID
+		} else {
+			debug.Warning("Agent %d sent heartbeat with invalid task ID %q", agent.ID, payload.CurrentTaskID)
+		}
+	}
+	return s.agentService.RecordHeartbeat(ctx, heartbeat)
 }
 
 
@@ -423,6 +541,11 @@ func (s *Service) handleAgentStatus(ctx context.Context, agent *models.Agent, ms
 		}
 	}
 
+	// A new driver or binary invalidates the agent's benchmarks
+	if err := s.agentService.RecordRuntimeVersions(ctx, agent.ID, payload.DriverVersion, payload.BinaryVersion); err != nil {
+		debug.Error("Failed to record runtime versions of agent %d: %v", agent.ID, err)
+	}
+
 	return nil
 }
 
@@ -441,6 +564,14 @@ func (s *Service) handleErrorReport(ctx context.Context, agent *models.Agent, ms
 	return nil
 }
 
+// handleCrashReport stores the diagnostic bundle of an agent or hashcat crash
+func (s *Service) handleCrashReport(ctx context.Context, agent *models.Agent, msg *Message) error {
+	if err := s.agentService.RecordCrashReport(ctx, agent.ID, msg.Payload); err != nil {
+		return fmt.Errorf("failed to handle crash report: %w", err)
+	}
+	return nil
+}
+
 // handleHardwareInfo processes hardware information messages
 func (s *Service) handleHardwareInfo(ctx context.Context, agent *models.Agent, msg *Message) error {
 	// If HardwareInfo is not directly populated, try to unmarshal from Payload
@@ -598,6 +729,22 @@ func (s *Service) HandleAgentDisconnection(ctx context.Context, agentID int) err
 	return nil
 }
 
+// HandleCrackJournal reconciles a crack journal entry replayed by an agent
+func (s *Service) HandleCrackJournal(ctx context.Context, agent *models.Agent, payload json.RawMessage) error {
+	if s.jobHandler == nil {
+		return fmt.Errorf("no job handler set")
+	}
+
+	type crackJournalHandler interface {
+		ProcessCrackJournal(ctx context.Context, agentID int, payload json.RawMessage) error
+	}
+
+	if handler, ok := s.jobHandler.(crackJournalHandler); ok {
+		return handler.ProcessCrackJournal(ctx, agent.ID, payload)
+	}
+	return fmt.Errorf("job handler does not support crack journals")
+}
+
 // handleSyncStarted processes sync started messages from agents
 func (s *Service) handleSyncStarted(ctx context.Context, agent *models.Agent, msg *Message) error {
 	var payload SyncStartedPayload
@@ -638,6 +785,7 @@ func (s *Service) handleSyncCompleted(ctx context.Context, agent *models.Agent,
 	}
 
 	debug.Info("Agent %d completed file sync with %d files", agent.ID, payload.FilesSynced)
+	s.agentService.RecordSyncEvent(ctx, agent)
 	return nil
 }
 
@@ -657,6 +805,7 @@ func (s *Service) handleSyncFailed(ctx context.Context, agent *models.Agent, msg
 	}
 
 	debug.Error("Agent %d failed file sync: %s", agent.ID, payload.Error)
+	s.agentService.RecordSyncEvent(ctx, agent)
 	return nil
 }
 
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
+)
+
+// ErrInvalidWordlistCollection is returned for a wordlist collection without a
+// usable name or wordlists
+var ErrInvalidWordlistCollection = errors.New("invalid wordlist collection")
+
+// WordlistCollectionService manages wordlist collections and expands them
+// into their wordlists when jobs are created
+type WordlistCollectionService struct {
+	collectionRepo *repository.WordlistCollectionRepository
+}
+
+// NewWordlistCollectionService creates a new wordlist collection service
+func NewWordlistCollectionService(collectionRepo *repository.WordlistCollectionRepository) *WordlistCollectionService {
+	return &WordlistCollectionService{collectionRepo: collectionRepo}
+}
+
+// ListCollections returns all collections with the wordlists they contain
+func (s *WordlistCollectionService) ListCollections(ctx context.Context) ([]models.WordlistCollection, error) {
+	collections, err := s.collectionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range collections {
+		if collections[i].Wordlists, err = s.collectionRepo.ListWordlists(ctx, &collections[i]); err != nil {
+			return nil, err
+		}
+	}
+	return collections, nil
+}
+
+// GetCollection returns a collection with the wordlists it contains
+func (s *WordlistCollectionService) GetCollection(ctx context.Context, id int) (*models.WordlistCollection, error) {
+	collection, err := s.collectionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if collection.Wordlists, err = s.collectionRepo.ListWordlists(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// CreateCollection validates and adds a collection
+func (s *WordlistCollectionService) CreateCollection(ctx context.Context, collection *models.WordlistCollection) error {
+	if err := s.validateCollection(ctx, collection); err != nil {
+		return err
+	}
+	return s.collectionRepo.Create(ctx, collection)
+}
+
+// UpdateCollection validates and saves a collection. Existing jobs keep the
+// wordlists they were created with, jobs created afterwards use the new set.
+func (s *WordlistCollectionService) UpdateCollection(ctx context.Context, collection *models.WordlistCollection) error {
+	if err := s.validateCollection(ctx, collection); err != nil {
+		return err
+	}
+	return s.collectionRepo.Update(ctx, collection)
+}
+
+// DeleteCollection removes a collection no preset job references
+func (s *WordlistCollectionService) DeleteCollection(ctx context.Context, id int) error {
+	return s.collectionRepo.Delete(ctx, id)
+}
+
+// ExpandPresetJob returns the presets to create jobs from: the preset itself,
+// or a copy per wordlist of its collection. A collection without any
+// remaining wordlists is an error rather than silently creating no jobs.
+func (s *WordlistCollectionService) ExpandPresetJob(ctx context.Context, presetJob *models.PresetJob) ([]models.ExpandedPresetJob, error) {
+	if presetJob.WordlistCollectionID == nil {
+		return []models.ExpandedPresetJob{{Preset: presetJob}}, nil
+	}
+
+	collection, err := s.GetCollection(ctx, *presetJob.WordlistCollectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wordlist collection %d: %w", *presetJob.WordlistCollectionID, err)
+	}
+	if len(collection.Wordlists) == 0 {
+		return nil, fmt.Errorf("%w: collection '%s' has no wordlists", ErrInvalidWordlistCollection, collection.Name)
+	}
+	return presetJob.ExpandCollection(collection.Wordlists), nil
+}
+
+// validateCollection trims the collection's name and checks it lists at least
+// one wordlist, each existing and listed once
+func (s *WordlistCollectionService) validateCollection(ctx context.Context, collection *models.WordlistCollection) error {
+	collection.Name = strings.TrimSpace(collection.Name)
+	if collection.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWordlistCollection)
+	}
+	if len(collection.Name) > 255 {
+		return fmt.Errorf("%w: name is longer than 255 characters", ErrInvalidWordlistCollection)
+	}
+	if len(collection.WordlistIDs) == 0 {
+		return fmt.Errorf("%w: at least one wordlist is required", ErrInvalidWordlistCollection)
+	}
+
+	seen := make(map[int64]bool, len(collection.WordlistIDs))
+	for _, id := range collection.WordlistIDs {
+		if seen[id] {
+			return fmt.Errorf("%w: wordlist %d is listed more than once", ErrInvalidWordlistCollection, id)
+		}
+		seen[id] = true
+	}
+
+	count, err := s.collectionRepo.CountExistingWordlists(ctx, collection.WordlistIDs)
+	if err != nil {
+		return err
+	}
+	if count != len(collection.WordlistIDs) {
+		return fmt.Errorf("%w: %d of its wordlists do not exist", ErrInvalidWordlistCollection, len(collection.WordlistIDs)-count)
+	}
+	return nil
+}
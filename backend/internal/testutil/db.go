@@ -3,6 +3,7 @@ package testutil
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -34,12 +35,10 @@ func SetupTestDB(t *testing.T) *db.DB {
 	// Wrap in our DB type
 	testDB := &db.DB{DB: rawDB}
 
-	// Set environment variables for migrations
-	os.Setenv("DB_HOST", "localhost")
-	os.Setenv("DB_PORT", "5432")
-	os.Setenv("DB_USER", "krakenhashes")
-	os.Setenv("DB_PASSWORD", "krakenhashes")
-	os.Setenv("DB_NAME", "krakenhashes_test")
+	// Set environment variables for migrations from the test database URL
+	if err := setDBEnv(testDBURL); err != nil {
+		t.Fatalf("Invalid TEST_DATABASE_URL: %v", err)
+	}
 
 	// Run migrations from the backend directory by temporarily changing directory
 	originalDir, _ := os.Getwd()
@@ -104,7 +103,8 @@ func SetupTestDB(t *testing.T) *db.DB {
 	// Clean up function
 	t.Cleanup(func() {
 		// Clean all tables in reverse order of foreign key dependencies
-		// Only truncate tables that exist
+		// Only truncate tables that exist. system_settings is kept, it holds
+		// the defaults seeded by migrations, which do not run again.
 		tables := []string{
 			"email_mfa_codes",      // MFA codes (references users)
 			"pending_mfa_setup",    // MFA setup (references users)
@@ -115,7 +115,6 @@ func SetupTestDB(t *testing.T) *db.DB {
 			"hashlists",
 			"agents",
 			"users",
-			"system_settings",
 			"auth_settings",
 		}
 
@@ -135,6 +134,26 @@ func SetupTestDB(t *testing.T) *db.DB {
 	return testDB
 }
 
+// setDBEnv points the DB_* variables the migrator connects with at the test database
+func setDBEnv(dbURL string) error {
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		return err
+	}
+	password, _ := u.User.Password()
+	port := u.Port()
+	if port == "" {
+		port = "5432"
+	}
+
+	os.Setenv("DB_HOST", u.Hostname())
+	os.Setenv("DB_PORT", port)
+	os.Setenv("DB_USER", u.User.Username())
+	os.Setenv("DB_PASSWORD", password)
+	os.Setenv("DB_NAME", strings.TrimPrefix(u.Path, "/"))
+	return nil
+}
+
 // CreateTestUser creates a test user with the given attributes
 func CreateTestUser(t *testing.T, database *db.DB, username, email, pass string, role string) *models.User {
 	t.Helper()
@@ -0,0 +1,470 @@
+// Package fakeagent implements an agent that speaks the backend's WebSocket
+// protocol without running hashcat. It answers file syncs, benchmarks and task
+// assignments with canned results so scheduling, chunking, interruption and
+// crack ingestion can be tested end to end without GPUs.
+package fakeagent
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	wsservice "github.com/ZerkerEOD/krakenhashes/backend/internal/services/websocket"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// writeWait bounds a single write to the backend
+const writeWait = 10 * time.Second
+
+// ErrNotConnected is returned when sending while the agent is disconnected
+var ErrNotConnected = errors.New("fake agent is not connected")
+
+// Config describes a fake agent and the canned results it reports
+type Config struct {
+	// URL is the backend's agent WebSocket endpoint, e.g. wss://host/ws/agent
+	URL string
+	// AgentID and APIKey authenticate the agent like a registered one
+	AgentID int
+	APIKey  string
+	// TLSConfig is used for wss URLs, tests against an httptest server
+	// usually set InsecureSkipVerify
+	TLSConfig *tls.Config
+
+	// Devices are reported on connect, one enabled GPU when empty
+	Devices []models.Device
+	// Speed is the hash rate reported by benchmarks and progress updates
+	Speed int64
+	// ProgressSteps is the number of progress updates per task, the last one
+	// completes it. Defaults to 4.
+	ProgressSteps int
+	// ProgressInterval is the delay between progress updates. Defaults to 50ms.
+	ProgressInterval time.Duration
+	// Cracks are reported with the progress updates of the first tasks that
+	// run, each crack once across all tasks of the agent. ProgressSteps must
+	// be at least 2 for a task to report cracks.
+	Cracks []models.CrackedHash
+	// FailTasks makes every task fail with this error message
+	FailTasks string
+}
+
+// Task is the state of a task assigned to the agent
+type Task struct {
+	Assignment wsservice.TaskAssignmentPayload
+	// Processed is the part of the chunk already processed
+	Processed int64
+	Completed bool
+	Stopped   bool
+}
+
+// Agent is a fake agent connected to a backend
+type Agent struct {
+	cfg Config
+
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	cancel   context.CancelFunc
+	current  *Task
+	tasks    []*Task
+	cracks   []models.CrackedHash
+	received map[wsservice.MessageType]int
+	notify   chan struct{}
+
+	writeMu sync.Mutex
+}
+
+// New creates a fake agent, Connect connects it
+func New(cfg Config) *Agent {
+	if len(cfg.Devices) == 0 {
+		cfg.Devices = []models.Device{{ID: 1, Name: "Fake GPU", Type: "GPU", Enabled: true, Backend: "CUDA"}}
+	}
+	if cfg.Speed <= 0 {
+		cfg.Speed = 1000
+	}
+	if cfg.ProgressSteps <= 0 {
+		cfg.ProgressSteps = 4
+	}
+	if cfg.ProgressInterval <= 0 {
+		cfg.ProgressInterval = 50 * time.Millisecond
+	}
+
+	return &Agent{
+		cfg:      cfg,
+		cracks:   append([]models.CrackedHash(nil), cfg.Cracks...),
+		received: make(map[wsservice.MessageType]int),
+		notify:   make(chan struct{}),
+	}
+}
+
+// Connect connects to the backend and reports devices and the current task,
+// like a real agent does after starting or reconnecting. A task interrupted
+// by Disconnect is reported as running and resumed.
+func (a *Agent) Connect(ctx context.Context) error {
+	dialer := websocket.Dialer{
+		TLSClientConfig:  a.cfg.TLSConfig,
+		HandshakeTimeout: 10 * time.Second,
+	}
+	header := http.Header{}
+	header.Set("X-API-Key", a.cfg.APIKey)
+	header.Set("X-Agent-ID", strconv.Itoa(a.cfg.AgentID))
+
+	conn, resp, err := dialer.DialContext(ctx, a.cfg.URL, header)
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("failed to connect fake agent %d: %s: %w", a.cfg.AgentID, resp.Status, err)
+		}
+		return fmt.Errorf("failed to connect fake agent %d: %w", a.cfg.AgentID, err)
+	}
+
+	connCtx, cancel := context.WithCancel(context.Background())
+	a.mu.Lock()
+	a.conn = conn
+	a.cancel = cancel
+	current := a.current
+	a.mu.Unlock()
+
+	go a.readLoop(connCtx, conn)
+
+	if err := a.send(wsservice.TypeDeviceDetection, models.DeviceDetectionResult{Devices: a.cfg.Devices}); err != nil {
+		return err
+	}
+
+	status := map[string]interface{}{"agent_id": a.cfg.AgentID, "has_running_task": false}
+	if current != nil {
+		status["has_running_task"] = true
+		status["task_id"] = current.Assignment.TaskID
+		status["job_id"] = current.Assignment.JobExecutionID
+		status["keyspace_processed"] = current.Assignment.KeyspaceStart + current.Processed
+		status["status"] = "running"
+	}
+	if err := a.send(wsservice.TypeCurrentTaskStatus, status); err != nil {
+		return err
+	}
+
+	if current != nil {
+		go a.runTask(connCtx, current)
+	}
+	return nil
+}
+
+// Disconnect drops the connection without a shutdown message, as a crashed
+// agent or a network failure would. A running task is paused and resumed by
+// the next Connect.
+func (a *Agent) Disconnect() {
+	a.mu.Lock()
+	conn, cancel := a.conn, a.cancel
+	a.conn, a.cancel = nil, nil
+	a.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// Forget drops the interrupted task, so the next Connect reports no running
+// task like an agent that restarted and lost its state
+func (a *Agent) Forget() {
+	a.mu.Lock()
+	a.current = nil
+	a.mu.Unlock()
+}
+
+// Shutdown tells the backend the agent is stopping and disconnects
+func (a *Agent) Shutdown() error {
+	err := a.send(wsservice.TypeAgentShutdown, map[string]interface{}{"agent_id": a.cfg.AgentID})
+	a.Disconnect()
+	return err
+}
+
+// Tasks returns a snapshot of the tasks assigned to the agent
+func (a *Agent) Tasks() []Task {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	tasks := make([]Task, len(a.tasks))
+	for i, t := range a.tasks {
+		tasks[i] = *t
+	}
+	return tasks
+}
+
+// Received returns how many messages of a type the agent received
+func (a *Agent) Received(msgType wsservice.MessageType) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.received[msgType]
+}
+
+// WaitFor blocks until cond is true, checking it after every message the
+// agent receives or sends, or until ctx is done
+func (a *Agent) WaitFor(ctx context.Context, cond func(a *Agent) bool) error {
+	for {
+		a.mu.Lock()
+		notify := a.notify
+		a.mu.Unlock()
+
+		if cond(a) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notify:
+		case <-time.After(a.cfg.ProgressInterval):
+		}
+	}
+}
+
+// changed wakes up WaitFor, callers hold mu
+func (a *Agent) changed() {
+	close(a.notify)
+	a.notify = make(chan struct{})
+}
+
+func (a *Agent) readLoop(ctx context.Context, conn *websocket.Conn) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg wsservice.Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+
+		a.mu.Lock()
+		a.received[msg.Type]++
+		a.changed()
+		a.mu.Unlock()
+
+		a.handle(ctx, &msg)
+	}
+}
+
+func (a *Agent) handle(ctx context.Context, msg *wsservice.Message) {
+	switch msg.Type {
+	case wsservice.TypeSyncRequest:
+		var req wsservice.FileSyncRequestPayload
+		if json.Unmarshal(msg.Payload, &req) != nil {
+			return
+		}
+		// Reporting no files makes the backend send everything it has
+		a.send(wsservice.TypeSyncResponse, wsservice.FileSyncResponsePayload{
+			RequestID: req.RequestID,
+			AgentID:   a.cfg.AgentID,
+			Files:     []wsservice.FileInfo{},
+		})
+
+	case wsservice.TypeSyncCommand:
+		var cmd wsservice.FileSyncCommandPayload
+		if json.Unmarshal(msg.Payload, &cmd) != nil || cmd.Action == "delete" {
+			return
+		}
+		// Files are not downloaded, the fake agent never reads them
+		a.send(wsservice.TypeSyncCompleted, wsservice.SyncCompletedPayload{
+			AgentID:     a.cfg.AgentID,
+			FilesSynced: len(cmd.Files),
+		})
+
+	case wsservice.TypeBenchmarkRequest:
+		var req wsservice.BenchmarkRequestPayload
+		if json.Unmarshal(msg.Payload, &req) != nil {
+			return
+		}
+		a.send(wsservice.TypeBenchmarkResult, a.benchmarkResult(req))
+
+	case wsservice.TypeTaskAssignment:
+		var assignment wsservice.TaskAssignmentPayload
+		if json.Unmarshal(msg.Payload, &assignment) != nil {
+			return
+		}
+		task := &Task{Assignment: assignment}
+		a.mu.Lock()
+		a.tasks = append(a.tasks, task)
+		a.current = task
+		a.changed()
+		a.mu.Unlock()
+		go a.runTask(ctx, task)
+
+	case wsservice.TypeJobStop:
+		var stop wsservice.JobStopPayload
+		if json.Unmarshal(msg.Payload, &stop) != nil {
+			return
+		}
+		a.mu.Lock()
+		for _, t := range a.tasks {
+			if t.Assignment.TaskID == stop.TaskID && !t.Completed {
+				t.Stopped = true
+			}
+		}
+		if a.current != nil && a.current.Stopped {
+			a.current = nil
+		}
+		a.changed()
+		a.mu.Unlock()
+	}
+}
+
+func (a *Agent) benchmarkResult(req wsservice.BenchmarkRequestPayload) wsservice.BenchmarkResultPayload {
+	result := wsservice.BenchmarkResultPayload{
+		JobExecutionID: req.JobExecutionID,
+		AttackMode:     req.AttackMode,
+		HashType:       req.HashType,
+		Speed:          a.cfg.Speed,
+		Success:        true,
+	}
+	enabled := 0
+	for _, d := range a.cfg.Devices {
+		if d.Enabled {
+			enabled++
+		}
+	}
+	for _, d := range a.cfg.Devices {
+		if d.Enabled {
+			result.DeviceSpeeds = append(result.DeviceSpeeds, wsservice.DeviceSpeed{
+				DeviceID:   d.ID,
+				DeviceName: d.Name,
+				Speed:      a.cfg.Speed / int64(enabled),
+			})
+		}
+	}
+	return result
+}
+
+// runTask reports progress for a task until it completes, is stopped or the
+// connection ctx belongs to is dropped
+func (a *Agent) runTask(ctx context.Context, task *Task) {
+	taskID, err := uuid.Parse(task.Assignment.TaskID)
+	if err != nil {
+		return
+	}
+	size := task.Assignment.KeyspaceEnd - task.Assignment.KeyspaceStart
+	step := size / int64(a.cfg.ProgressSteps)
+	if step < 1 {
+		step = 1
+	}
+
+	ticker := time.NewTicker(a.cfg.ProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		a.mu.Lock()
+		if task.Stopped || task.Completed {
+			a.mu.Unlock()
+			return
+		}
+		first := task.Processed == 0
+		task.Processed += step
+		if task.Processed > size {
+			task.Processed = size
+		}
+		done := task.Processed == size
+		// Like hashcat, cracks are reported while running, the backend does
+		// not ingest cracks from the completing update. The update before it
+		// carries whatever is left.
+		var cracks []models.CrackedHash
+		if len(a.cracks) > 0 && !done {
+			cracks = a.cracks[:1]
+			if task.Processed+step >= size {
+				cracks = a.cracks
+			}
+			a.cracks = a.cracks[len(cracks):]
+		}
+		processed := task.Processed
+		a.mu.Unlock()
+
+		progress := models.JobProgress{
+			TaskID:            taskID,
+			KeyspaceProcessed: task.Assignment.KeyspaceStart + processed,
+			EffectiveProgress: processed,
+			ProgressPercent:   float64(processed) * 100 / float64(max(size, 1)),
+			IsFirstUpdate:     first,
+			HashRate:          a.cfg.Speed,
+			CrackedCount:      len(cracks),
+			CrackedHashes:     cracks,
+			Status:            "running",
+		}
+		if first {
+			progress.TotalEffectiveKeyspace = &size
+		}
+		if a.cfg.FailTasks != "" {
+			progress.Status = "failed"
+			progress.ErrorMessage = a.cfg.FailTasks
+			done = true
+		} else if done {
+			progress.Status = "completed"
+			progress.ProgressPercent = 100
+		}
+
+		if err := a.send(wsservice.TypeJobProgress, progress); err != nil {
+			// Unsent cracks are reported again after reconnecting
+			a.mu.Lock()
+			a.cracks = append(cracks, a.cracks...)
+			task.Processed -= step
+			if task.Processed < 0 {
+				task.Processed = 0
+			}
+			a.mu.Unlock()
+			return
+		}
+
+		if done {
+			a.mu.Lock()
+			task.Completed = true
+			if a.current == task {
+				a.current = nil
+			}
+			a.changed()
+			a.mu.Unlock()
+			return
+		}
+	}
+}
+
+// send writes a message with the payload encoded as JSON
+func (a *Agent) send(msgType wsservice.MessageType, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s payload: %w", msgType, err)
+	}
+	data, err := json.Marshal(wsservice.Message{Type: msgType, Payload: body})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s message: %w", msgType, err)
+	}
+
+	a.mu.Lock()
+	conn := a.conn
+	a.mu.Unlock()
+	if conn == nil {
+		return ErrNotConnected
+	}
+
+	a.writeMu.Lock()
+	defer a.writeMu.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return fmt.Errorf("failed to send %s: %w", msgType, err)
+	}
+
+	a.mu.Lock()
+	a.changed()
+	a.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,273 @@
+package fakeagent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	wsservice "github.com/ZerkerEOD/krakenhashes/backend/internal/services/websocket"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backend is a minimal stand-in for the agent WebSocket endpoint that records
+// what the fake agent sends and lets tests push messages to it
+type backend struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	messages []wsservice.Message
+	headers  http.Header
+}
+
+func newBackend(t *testing.T) *backend {
+	b := &backend{t: t}
+	upgrader := websocket.Upgrader{}
+	b.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.conn = conn
+		b.headers = r.Header.Clone()
+		b.mu.Unlock()
+
+		for {
+			var msg wsservice.Message
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			b.mu.Lock()
+			b.messages = append(b.messages, msg)
+			b.mu.Unlock()
+		}
+	}))
+	t.Cleanup(b.server.Close)
+	return b
+}
+
+func (b *backend) url() string {
+	return "ws" + strings.TrimPrefix(b.server.URL, "http")
+}
+
+func (b *backend) send(msgType wsservice.MessageType, payload interface{}) {
+	body, err := json.Marshal(payload)
+	require.NoError(b.t, err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	require.NoError(b.t, b.conn.WriteJSON(wsservice.Message{Type: msgType, Payload: body}))
+}
+
+// received returns the messages of a type the backend received
+func (b *backend) received(msgType wsservice.MessageType) []wsservice.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var messages []wsservice.Message
+	for _, msg := range b.messages {
+		if msg.Type == msgType {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+func (b *backend) progress(t *testing.T) []models.JobProgress {
+	var updates []models.JobProgress
+	for _, msg := range b.received(wsservice.TypeJobProgress) {
+		var p models.JobProgress
+		require.NoError(t, json.Unmarshal(msg.Payload, &p))
+		updates = append(updates, p)
+	}
+	return updates
+}
+
+func connect(t *testing.T, b *backend, cfg Config) *Agent {
+	cfg.URL = b.url()
+	cfg.AgentID = 7
+	cfg.APIKey = "test-key"
+	cfg.ProgressInterval = 5 * time.Millisecond
+
+	agent := New(cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, agent.Connect(ctx))
+	t.Cleanup(agent.Disconnect)
+
+	require.Eventually(t, func() bool {
+		return len(b.received(wsservice.TypeCurrentTaskStatus)) > 0
+	}, 5*time.Second, 5*time.Millisecond)
+	return agent
+}
+
+func TestConnectAuthenticatesAndReportsState(t *testing.T) {
+	b := newBackend(t)
+	connect(t, b, Config{})
+
+	assert.Equal(t, "test-key", b.headers.Get("X-API-Key"))
+	assert.Equal(t, "7", b.headers.Get("X-Agent-ID"))
+
+	detection := b.received(wsservice.TypeDeviceDetection)
+	require.Len(t, detection, 1)
+	var result models.DeviceDetectionResult
+	require.NoError(t, json.Unmarshal(detection[0].Payload, &result))
+	require.Len(t, result.Devices, 1)
+	assert.True(t, result.Devices[0].Enabled)
+
+	var status map[string]interface{}
+	require.NoError(t, json.Unmarshal(b.received(wsservice.TypeCurrentTaskStatus)[0].Payload, &status))
+	assert.Equal(t, false, status["has_running_task"])
+}
+
+func TestFileSync(t *testing.T) {
+	b := newBackend(t)
+	connect(t, b, Config{})
+
+	b.send(wsservice.TypeSyncRequest, wsservice.FileSyncRequestPayload{RequestID: "sync-1"})
+	require.Eventually(t, func() bool { return len(b.received(wsservice.TypeSyncResponse)) == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	b.send(wsservice.TypeSyncCommand, wsservice.FileSyncCommandPayload{
+		Action: "download",
+		Files:  []wsservice.FileInfo{{Name: "rockyou.txt", FileType: "wordlist"}, {Name: "best64.rule", FileType: "rule"}},
+	})
+	require.Eventually(t, func() bool { return len(b.received(wsservice.TypeSyncCompleted)) == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	var completed wsservice.SyncCompletedPayload
+	require.NoError(t, json.Unmarshal(b.received(wsservice.TypeSyncCompleted)[0].Payload, &completed))
+	assert.Equal(t, 2, completed.FilesSynced)
+}
+
+func TestBenchmark(t *testing.T) {
+	b := newBackend(t)
+	connect(t, b, Config{Speed: 5000})
+
+	b.send(wsservice.TypeBenchmarkRequest, wsservice.BenchmarkRequestPayload{JobExecutionID: "job-1", AttackMode: 0, HashType: 1000})
+	require.Eventually(t, func() bool { return len(b.received(wsservice.TypeBenchmarkResult)) == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	var result wsservice.BenchmarkResultPayload
+	require.NoError(t, json.Unmarshal(b.received(wsservice.TypeBenchmarkResult)[0].Payload, &result))
+	assert.True(t, result.Success)
+	assert.Equal(t, "job-1", result.JobExecutionID)
+	assert.Equal(t, 1000, result.HashType)
+	assert.Equal(t, int64(5000), result.Speed)
+	require.Len(t, result.DeviceSpeeds, 1)
+}
+
+func TestTaskReportsProgressAndCracks(t *testing.T) {
+	b := newBackend(t)
+	cracks := []models.CrackedHash{
+		{Hash: "8846f7eaee8fb117ad06bdd830b7586c", Plain: "password"},
+		{Hash: "32ed87bdb5fdc5e9cba88547376818d4", Plain: "123456"},
+		{Hash: "b4b9b02e6f09a9bd760f388b67351e2b", Plain: "hashcat"},
+	}
+	agent := connect(t, b, Config{ProgressSteps: 4, Cracks: cracks})
+
+	taskID := uuid.New()
+	b.send(wsservice.TypeTaskAssignment, wsservice.TaskAssignmentPayload{TaskID: taskID.String(), KeyspaceStart: 100, KeyspaceEnd: 500})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, agent.WaitFor(ctx, func(a *Agent) bool {
+		tasks := a.Tasks()
+		return len(tasks) == 1 && tasks[0].Completed
+	}))
+
+	require.Eventually(t, func() bool { return len(b.progress(t)) == 4 }, 5*time.Second, 5*time.Millisecond)
+	updates := b.progress(t)
+	assert.True(t, updates[0].IsFirstUpdate)
+	require.NotNil(t, updates[0].TotalEffectiveKeyspace)
+	assert.Equal(t, int64(400), *updates[0].TotalEffectiveKeyspace)
+	assert.Equal(t, int64(200), updates[0].KeyspaceProcessed)
+
+	last := updates[3]
+	assert.Equal(t, taskID, last.TaskID)
+	assert.Equal(t, "completed", last.Status)
+	assert.Equal(t, float64(100), last.ProgressPercent)
+	assert.Equal(t, int64(500), last.KeyspaceProcessed)
+	assert.Empty(t, last.CrackedHashes, "cracks are not sent with the completing update")
+
+	var reported []models.CrackedHash
+	for _, u := range updates {
+		assert.Equal(t, len(u.CrackedHashes), u.CrackedCount)
+		reported = append(reported, u.CrackedHashes...)
+	}
+	assert.Equal(t, cracks, reported)
+}
+
+func TestFailingTask(t *testing.T) {
+	b := newBackend(t)
+	agent := connect(t, b, Config{FailTasks: "No hashes loaded"})
+
+	b.send(wsservice.TypeTaskAssignment, wsservice.TaskAssignmentPayload{TaskID: uuid.NewString(), KeyspaceEnd: 100})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, agent.WaitFor(ctx, func(a *Agent) bool {
+		tasks := a.Tasks()
+		return len(tasks) == 1 && tasks[0].Completed
+	}))
+
+	require.Eventually(t, func() bool { return len(b.progress(t)) == 1 }, 5*time.Second, 5*time.Millisecond)
+	updates := b.progress(t)
+	assert.Equal(t, "failed", updates[0].Status)
+	assert.Equal(t, "No hashes loaded", updates[0].ErrorMessage)
+}
+
+func TestJobStop(t *testing.T) {
+	b := newBackend(t)
+	agent := connect(t, b, Config{ProgressSteps: 1000})
+
+	taskID := uuid.NewString()
+	b.send(wsservice.TypeTaskAssignment, wsservice.TaskAssignmentPayload{TaskID: taskID, KeyspaceEnd: 1000})
+	require.Eventually(t, func() bool { return len(b.received(wsservice.TypeJobProgress)) > 0 }, 5*time.Second, 5*time.Millisecond)
+
+	b.send(wsservice.TypeJobStop, wsservice.JobStopPayload{TaskID: taskID})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, agent.WaitFor(ctx, func(a *Agent) bool { return a.Tasks()[0].Stopped }))
+
+	// An update already being sent when the stop arrived may still land
+	time.Sleep(20 * time.Millisecond)
+	stoppedAt := len(b.received(wsservice.TypeJobProgress))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, stoppedAt, len(b.received(wsservice.TypeJobProgress)))
+	assert.False(t, agent.Tasks()[0].Completed)
+}
+
+func TestReconnectResumesTask(t *testing.T) {
+	b := newBackend(t)
+	agent := connect(t, b, Config{ProgressSteps: 100})
+
+	taskID := uuid.NewString()
+	b.send(wsservice.TypeTaskAssignment, wsservice.TaskAssignmentPayload{TaskID: taskID, KeyspaceStart: 1000, KeyspaceEnd: 2000})
+	require.Eventually(t, func() bool { return len(b.received(wsservice.TypeJobProgress)) >= 3 }, 5*time.Second, 5*time.Millisecond)
+
+	agent.Disconnect()
+	processed := agent.Tasks()[0].Processed
+	require.Greater(t, processed, int64(0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, agent.Connect(ctx))
+	require.Eventually(t, func() bool { return len(b.received(wsservice.TypeCurrentTaskStatus)) == 2 }, 5*time.Second, 5*time.Millisecond)
+
+	var status map[string]interface{}
+	require.NoError(t, json.Unmarshal(b.received(wsservice.TypeCurrentTaskStatus)[1].Payload, &status))
+	assert.Equal(t, true, status["has_running_task"])
+	assert.Equal(t, taskID, status["task_id"])
+	assert.GreaterOrEqual(t, status["keyspace_processed"], float64(1000+processed))
+
+	require.NoError(t, agent.WaitFor(ctx, func(a *Agent) bool { return a.Tasks()[0].Completed }))
+}
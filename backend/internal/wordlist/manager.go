@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/repository"
@@ -26,17 +27,28 @@ type Manager interface {
 	GetWordlist(ctx context.Context, id int) (*models.Wordlist, error)
 	GetWordlistByFilename(ctx context.Context, filename string) (*models.Wordlist, error)
 	GetWordlistByMD5Hash(ctx context.Context, md5Hash string) (*models.Wordlist, error)
+	GetWordlistByCanonicalHash(ctx context.Context, canonicalHash string) (*models.Wordlist, error)
 	AddWordlist(ctx context.Context, req *models.WordlistAddRequest, userID uuid.UUID) (*models.Wordlist, error)
 	UpdateWordlist(ctx context.Context, id int, req *models.WordlistUpdateRequest, userID uuid.UUID) (*models.Wordlist, error)
-	DeleteWordlist(ctx context.Context, id int) error
+	DeleteWordlist(ctx context.Context, id int, force bool) (*models.Wordlist, error)
+	GetWordlistReferences(ctx context.Context, id int) (*models.FileReferences, error)
 	VerifyWordlist(ctx context.Context, id int, req *models.WordlistVerifyRequest) error
 	UpdateWordlistFileInfo(ctx context.Context, id int, md5Hash string, fileSize int64) error
 	UpdateWordlistComplete(ctx context.Context, id int, md5Hash string, fileSize int64, wordCount int64) error
 	AddWordlistTag(ctx context.Context, id int, tag string, userID uuid.UUID) error
 	DeleteWordlistTag(ctx context.Context, id int, tag string) error
+	AddWordlistAlias(ctx context.Context, id int, alias string, userID uuid.UUID) error
+	UpdateWordlistPath(ctx context.Context, id int, fileName string) error
+	UpdateWordlistCanonicalHash(ctx context.Context, id int, canonicalHash string) error
+	UpdateWordlistFileModTime(ctx context.Context, id int, modTime time.Time) error
+	MoveWordlist(ctx context.Context, id int, dir string) (*models.Wordlist, error)
+	ListDirectories(ctx context.Context) ([]models.ResourceDirectory, error)
+	CreateDirectory(ctx context.Context, dir string) (string, error)
+	MoveDirectory(ctx context.Context, from, to string) (int, error)
 	GetWordlistPath(filename string, wordlistType string) string
 	CountWordsInFile(filepath string) (int64, error)
 	CalculateFileMD5(filepath string) (string, error)
+	PreviewWordlistFile(filePath string, mode string, lines int) (*models.WordlistPreview, error)
 }
 
 // Store defines the interface for wordlist data storage operations
@@ -46,17 +58,25 @@ type WordlistStore interface {
 	GetWordlist(ctx context.Context, id int) (*models.Wordlist, error)
 	GetWordlistByFilename(ctx context.Context, filename string) (*models.Wordlist, error)
 	GetWordlistByMD5Hash(ctx context.Context, md5Hash string) (*models.Wordlist, error)
+	GetWordlistByCanonicalHash(ctx context.Context, canonicalHash string) (*models.Wordlist, error)
 	CreateWordlist(ctx context.Context, wordlist *models.Wordlist) error
 	UpdateWordlist(ctx context.Context, wordlist *models.Wordlist) error
 	DeleteWordlist(ctx context.Context, id int) error
 	UpdateWordlistVerification(ctx context.Context, id int, status string, wordCount *int64) error
 	UpdateWordlistFileInfo(ctx context.Context, id int, md5Hash string, fileSize int64) error
 	UpdateWordlistComplete(ctx context.Context, id int, md5Hash string, fileSize int64, wordCount int64) error
+	UpdateWordlistPath(ctx context.Context, id int, fileName string) error
+	UpdateWordlistCanonicalHash(ctx context.Context, id int, canonicalHash string) error
+	UpdateWordlistFileModTime(ctx context.Context, id int, modTime time.Time) error
 
 	// Tag operations
 	GetWordlistTags(ctx context.Context, id int) ([]string, error)
 	AddWordlistTag(ctx context.Context, id int, tag string, userID uuid.UUID) error
 	DeleteWordlistTag(ctx context.Context, id int, tag string) error
+
+	// Alias operations
+	GetWordlistAliases(ctx context.Context, id int) ([]string, error)
+	AddWordlistAlias(ctx context.Context, id int, alias string, userID uuid.UUID) error
 }
 
 type manager struct {
@@ -106,6 +126,11 @@ func (m *manager) GetWordlistByMD5Hash(ctx context.Context, md5Hash string) (*mo
 	return m.store.GetWordlistByMD5Hash(ctx, md5Hash)
 }
 
+// GetWordlistByCanonicalHash retrieves a wordlist by canonical hash
+func (m *manager) GetWordlistByCanonicalHash(ctx context.Context, canonicalHash string) (*models.Wordlist, error) {
+	return m.store.GetWordlistByCanonicalHash(ctx, canonicalHash)
+}
+
 // AddWordlist adds a new wordlist
 func (m *manager) AddWordlist(ctx context.Context, req *models.WordlistAddRequest, userID uuid.UUID) (*models.Wordlist, error) {
 	// Create wordlist model
@@ -207,30 +232,44 @@ func (m *manager) UpdateWordlist(ctx context.Context, id int, req *models.Wordli
 }
 
 // DeleteWordlist deletes a wordlist
-func (m *manager) DeleteWordlist(ctx context.Context, id int) error {
-	// Check if wordlist is being used by active jobs
+func (m *manager) DeleteWordlist(ctx context.Context, id int, force bool) (*models.Wordlist, error) {
+	// A wordlist used by active jobs is never deleted, one used by preset jobs
+	// only when forced, which removes it from those presets
 	if m.jobExecRepo != nil {
-		hasActiveJobs, err := m.jobExecRepo.HasActiveJobsUsingWordlist(ctx, strconv.Itoa(id))
+		refs, err := m.jobExecRepo.GetWordlistReferences(ctx, strconv.Itoa(id))
 		if err != nil {
-			return fmt.Errorf("failed to check for active jobs: %w", err)
+			return nil, fmt.Errorf("failed to check wordlist references: %w", err)
 		}
-		if hasActiveJobs {
-			return models.ErrResourceInUse
+		if len(refs.Jobs) > 0 {
+			return nil, models.ErrResourceInUse
+		}
+		if len(refs.PresetJobs) > 0 && !force {
+			return nil, models.ErrResourceReferenced
 		}
 	}
 
 	// Get wordlist to find filename
 	wordlist, err := m.store.GetWordlist(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if wordlist == nil {
-		return fmt.Errorf("wordlist not found")
+		return nil, fmt.Errorf("wordlist not found")
+	}
+
+	if force && m.jobExecRepo != nil {
+		presets, err := m.jobExecRepo.DetachWordlistFromPresets(ctx, strconv.Itoa(id))
+		if err != nil {
+			return nil, err
+		}
+		for _, preset := range presets {
+			debug.Warning("Removed deleted wordlist %d from preset job %s (%s), flagged for review", id, preset.Name, preset.ID)
+		}
 	}
 
 	// Delete from database
 	if err := m.store.DeleteWordlist(ctx, id); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Delete file
@@ -240,7 +279,15 @@ func (m *manager) DeleteWordlist(ctx context.Context, id int) error {
 		// Don't return error, as the database entry is already deleted
 	}
 
-	return nil
+	return wordlist, nil
+}
+
+// GetWordlistReferences lists the preset jobs and active jobs using a wordlist
+func (m *manager) GetWordlistReferences(ctx context.Context, id int) (*models.FileReferences, error) {
+	if m.jobExecRepo == nil {
+		return &models.FileReferences{PresetJobs: []models.FileReference{}, Jobs: []models.FileReference{}}, nil
+	}
+	return m.jobExecRepo.GetWordlistReferences(ctx, strconv.Itoa(id))
 }
 
 // VerifyWordlist updates a wordlist's verification status
@@ -289,6 +336,206 @@ func (m *manager) DeleteWordlistTag(ctx context.Context, id int, tag string) err
 	return m.store.DeleteWordlistTag(ctx, id, tag)
 }
 
+// UpdateWordlistPath records a new file name for a wordlist whose file was
+// moved outside of the API, it does not touch the file itself
+func (m *manager) UpdateWordlistPath(ctx context.Context, id int, fileName string) error {
+	return m.store.UpdateWordlistPath(ctx, id, filepath.ToSlash(fileName))
+}
+
+// UpdateWordlistCanonicalHash records the canonical hash of a wordlist's file
+func (m *manager) UpdateWordlistCanonicalHash(ctx context.Context, id int, canonicalHash string) error {
+	return m.store.UpdateWordlistCanonicalHash(ctx, id, canonicalHash)
+}
+
+// AddWordlistAlias records the name of a duplicate upload aliased to a wordlist
+func (m *manager) AddWordlistAlias(ctx context.Context, id int, alias string, userID uuid.UUID) error {
+	return m.store.AddWordlistAlias(ctx, id, alias, userID)
+}
+
+// UpdateWordlistFileModTime records the modification time of the wordlist file as it
+// was when it was last hashed, so unchanged files are not hashed again
+func (m *manager) UpdateWordlistFileModTime(ctx context.Context, id int, modTime time.Time) error {
+	return m.store.UpdateWordlistFileModTime(ctx, id, modTime)
+}
+
+// checkMovable returns models.ErrResourceInUse if the wordlist must not be
+// moved, because it is the pot-file or used by active jobs
+func (m *manager) checkMovable(ctx context.Context, wordlist *models.Wordlist) error {
+	if wordlist.IsPotfile {
+		return models.ErrResourceInUse
+	}
+	if m.jobExecRepo != nil {
+		hasActiveJobs, err := m.jobExecRepo.HasActiveJobsUsingWordlist(ctx, strconv.Itoa(wordlist.ID))
+		if err != nil {
+			return fmt.Errorf("failed to check for active jobs: %w", err)
+		}
+		if hasActiveJobs {
+			return models.ErrResourceInUse
+		}
+	}
+	return nil
+}
+
+// MoveWordlist moves a wordlist file into dir, relative to the wordlists
+// directory, and records the new file name. The file is renamed rather than
+// copied, agents that already have it move their copy as well.
+func (m *manager) MoveWordlist(ctx context.Context, id int, dir string) (*models.Wordlist, error) {
+	dir, err := fsutil.CleanRelativeDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	// Files directly in the root would be resolved into a type directory
+	if dir == "" {
+		return nil, fmt.Errorf("%w: a directory is required", models.ErrInvalidInput)
+	}
+
+	wordlist, err := m.store.GetWordlist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wordlist == nil {
+		return nil, models.ErrNotFound
+	}
+	if err := m.checkMovable(ctx, wordlist); err != nil {
+		return nil, err
+	}
+
+	oldName := filepath.ToSlash(wordlist.FileName)
+	newName := path.Join(dir, path.Base(oldName))
+	if newName == oldName {
+		return wordlist, nil
+	}
+
+	newPath := filepath.Join(m.wordlistsDir, filepath.FromSlash(newName))
+	if fsutil.FileExists(newPath) {
+		return nil, models.ErrAlreadyExists
+	}
+	oldPath := filepath.Join(m.wordlistsDir, filepath.FromSlash(oldName))
+	if err := fsutil.MovePath(oldPath, newPath); err != nil {
+		return nil, fmt.Errorf("failed to move wordlist file: %w", err)
+	}
+
+	if err := m.store.UpdateWordlistPath(ctx, id, newName); err != nil {
+		if rbErr := os.Rename(newPath, oldPath); rbErr != nil {
+			debug.Error("Failed to move wordlist file %s back to %s: %v", newPath, oldPath, rbErr)
+		}
+		return nil, err
+	}
+
+	debug.Info("Moved wordlist %d from %s to %s", id, oldName, newName)
+	wordlist.FileName = newName
+	return wordlist, nil
+}
+
+// ListDirectories returns the directories below the wordlists directory with
+// the number of wordlists directly inside each
+func (m *manager) ListDirectories(ctx context.Context) ([]models.ResourceDirectory, error) {
+	dirs, err := fsutil.ListDirectories(m.wordlistsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	wordlists, err := m.store.ListWordlists(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, wordlist := range wordlists {
+		counts[path.Dir(filepath.ToSlash(wordlist.FileName))]++
+	}
+
+	result := make([]models.ResourceDirectory, 0, len(dirs))
+	for _, dir := range dirs {
+		result = append(result, models.ResourceDirectory{Path: dir, FileCount: counts[dir]})
+	}
+	return result, nil
+}
+
+// CreateDirectory creates dir, relative to the wordlists directory, with any
+// missing parents and returns its cleaned path
+func (m *manager) CreateDirectory(ctx context.Context, dir string) (string, error) {
+	dir, err := fsutil.CleanRelativeDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	if dir == "" {
+		return "", fmt.Errorf("%w: a directory is required", models.ErrInvalidInput)
+	}
+
+	if err := os.MkdirAll(filepath.Join(m.wordlistsDir, filepath.FromSlash(dir)), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	return dir, nil
+}
+
+// MoveDirectory renames or moves a directory below the wordlists directory
+// together with every wordlist inside it and returns the number of wordlists
+// moved. The directory is renamed on disk first, if recording the new file
+// names fails it is renamed back.
+func (m *manager) MoveDirectory(ctx context.Context, from, to string) (int, error) {
+	from, err := fsutil.CleanRelativeDir(from)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	to, err = fsutil.CleanRelativeDir(to)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", models.ErrInvalidInput, err)
+	}
+	if from == "" || to == "" {
+		return 0, fmt.Errorf("%w: the wordlists directory itself cannot be moved", models.ErrInvalidInput)
+	}
+	if to == from || strings.HasPrefix(to, from+"/") {
+		return 0, fmt.Errorf("%w: cannot move a directory into itself", models.ErrInvalidInput)
+	}
+
+	fromPath := filepath.Join(m.wordlistsDir, filepath.FromSlash(from))
+	toPath := filepath.Join(m.wordlistsDir, filepath.FromSlash(to))
+	if !fsutil.DirectoryExists(fromPath) {
+		return 0, models.ErrNotFound
+	}
+	if fsutil.DirectoryExists(toPath) || fsutil.FileExists(toPath) {
+		return 0, models.ErrAlreadyExists
+	}
+
+	all, err := m.store.ListWordlists(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	var affected []*models.Wordlist
+	for _, wordlist := range all {
+		if strings.HasPrefix(filepath.ToSlash(wordlist.FileName), from+"/") {
+			if err := m.checkMovable(ctx, wordlist); err != nil {
+				return 0, err
+			}
+			affected = append(affected, wordlist)
+		}
+	}
+
+	if err := fsutil.MovePath(fromPath, toPath); err != nil {
+		return 0, fmt.Errorf("failed to move directory: %w", err)
+	}
+
+	for i, wordlist := range affected {
+		oldName := filepath.ToSlash(wordlist.FileName)
+		newName := to + strings.TrimPrefix(oldName, from)
+		if err := m.store.UpdateWordlistPath(ctx, wordlist.ID, newName); err != nil {
+			// Put the database and the directory back as they were
+			for _, done := range affected[:i] {
+				if rbErr := m.store.UpdateWordlistPath(ctx, done.ID, filepath.ToSlash(done.FileName)); rbErr != nil {
+					debug.Error("Failed to restore path of wordlist %d: %v", done.ID, rbErr)
+				}
+			}
+			if rbErr := os.Rename(toPath, fromPath); rbErr != nil {
+				debug.Error("Failed to move directory %s back to %s: %v", toPath, fromPath, rbErr)
+			}
+			return 0, err
+		}
+	}
+
+	debug.Info("Moved wordlist directory %s to %s with %d wordlists", from, to, len(affected))
+	return len(affected), nil
+}
+
 // GetWordlistPath returns the full path to a wordlist file
 func (m *manager) GetWordlistPath(filename string, wordlistType string) string {
 	// Check if the filename already contains a subdirectory
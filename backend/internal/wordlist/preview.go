@@ -0,0 +1,281 @@
+package wordlist
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/ZerkerEOD/krakenhashes/backend/pkg/plaintext"
+)
+
+const (
+	// MaxPreviewLines caps the number of lines a preview returns
+	MaxPreviewLines = 1000
+	// maxPreviewLineBytes caps the length of a previewed line
+	maxPreviewLineBytes = 512
+	// previewScanBytes is how much of a file is read at most for a preview.
+	// Larger plain files are sampled at random offsets instead, compressed
+	// ones only from their start.
+	previewScanBytes = 64 * 1024 * 1024
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// previewLine is a line read for a preview, without its line ending
+type previewLine struct {
+	raw       []byte
+	crlf      bool
+	truncated bool
+}
+
+// PreviewWordlistFile returns the first lines of a wordlist file, or lines
+// picked at random, without reading more than a bounded part of it
+func (m *manager) PreviewWordlistFile(filePath string, mode string, lines int) (*models.WordlistPreview, error) {
+	if lines < 1 || lines > MaxPreviewLines {
+		return nil, fmt.Errorf("lines must be between 1 and %d", MaxPreviewLines)
+	}
+	if mode != models.WordlistPreviewHead && mode != models.WordlistPreviewRandom {
+		return nil, fmt.Errorf("unknown preview mode %q", mode)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wordlist: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat wordlist: %w", err)
+	}
+
+	var reader io.Reader = file
+	compressed := true
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".gz":
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip wordlist: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	case ".zip":
+		zr, err := zip.NewReader(file, info.Size())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zip wordlist: %w", err)
+		}
+		entry, err := firstZipFile(zr)
+		if err != nil {
+			return nil, err
+		}
+		defer entry.Close()
+		reader = entry
+	default:
+		compressed = false
+	}
+
+	preview := &models.WordlistPreview{Mode: mode}
+	var picked []previewLine
+	if mode == models.WordlistPreviewRandom && !compressed && info.Size() > previewScanBytes {
+		preview.HasBOM, picked, err = sampleAtOffsets(file, info.Size(), lines)
+	} else {
+		preview.HasBOM, picked, preview.Partial, err = scanLines(reader, mode, lines)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wordlist: %w", err)
+	}
+
+	preview.Lines = make([]string, 0, len(picked))
+	for _, line := range picked {
+		if !utf8.Valid(line.raw) {
+			preview.NonUTF8Lines++
+		}
+		if line.crlf {
+			preview.CRLFLines++
+		}
+		if line.truncated {
+			preview.TruncatedLines++
+		}
+		preview.Lines = append(preview.Lines, plaintext.Hashcat(line.raw))
+	}
+	return preview, nil
+}
+
+// firstZipFile opens the first file in a zip wordlist
+func firstZipFile(zr *zip.Reader) (io.ReadCloser, error) {
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() {
+			return f.Open()
+		}
+	}
+	return nil, fmt.Errorf("zip wordlist contains no file")
+}
+
+// scanLines reads the first previewScanBytes of r and returns its first lines,
+// or lines picked at random among them. partial reports that random lines were
+// picked from the scanned part only.
+func scanLines(r io.Reader, mode string, lines int) (hasBOM bool, picked []previewLine, partial bool, err error) {
+	limited := &io.LimitedReader{R: r, N: previewScanBytes}
+	br := bufio.NewReader(limited)
+	if hasBOM, err = skipBOM(br); err != nil {
+		return false, nil, false, err
+	}
+
+	rng := rand.New(rand.NewSource(rand.Int63()))
+	seen := 0
+	for {
+		line, _, err := readPreviewLine(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, nil, false, err
+		}
+
+		if mode == models.WordlistPreviewHead {
+			picked = append(picked, line)
+			if len(picked) == lines {
+				break
+			}
+			continue
+		}
+
+		// Reservoir sampling keeps every line equally likely
+		seen++
+		if len(picked) < lines {
+			picked = append(picked, line)
+		} else if i := rng.Intn(seen); i < lines {
+			// Keep file order: drop the replaced line and append the new one
+			picked = append(picked[:i], picked[i+1:]...)
+			picked = append(picked, line)
+		}
+	}
+
+	partial = mode == models.WordlistPreviewRandom && limited.N <= 0
+	return hasBOM, picked, partial, nil
+}
+
+// sampleAtOffsets picks up to lines lines of a large plain file, each the line
+// following a random offset, in file order
+func sampleAtOffsets(file *os.File, size int64, lines int) (bool, []previewLine, error) {
+	head := make([]byte, len(utf8BOM))
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return false, nil, err
+	}
+	hasBOM := bytes.Equal(head[:n], utf8BOM)
+
+	offsets := make([]int64, lines)
+	for i := range offsets {
+		offsets[i] = rand.Int63n(size)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	var picked []previewLine
+	next := int64(0) // Everything before next has been read already
+	for _, offset := range offsets {
+		if offset < next {
+			continue
+		}
+		br := bufio.NewReader(io.NewSectionReader(file, offset, size-offset))
+		start := offset
+
+		// Move to the start of the line after the one the offset falls into
+		if offset > 0 {
+			prev := make([]byte, 1)
+			if _, err := file.ReadAt(prev, offset-1); err != nil {
+				return false, nil, err
+			}
+			if prev[0] != '\n' {
+				_, skipped, err := readPreviewLine(br)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return false, nil, err
+				}
+				start += skipped
+			}
+		} else if hasBOM {
+			br.Discard(len(utf8BOM))
+			start += int64(len(utf8BOM))
+		}
+
+		line, read, err := readPreviewLine(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, nil, err
+		}
+		picked = append(picked, line)
+		next = start + read
+	}
+	return hasBOM, picked, nil
+}
+
+// skipBOM reports whether br starts with a UTF-8 byte order mark and skips it
+func skipBOM(br *bufio.Reader) (bool, error) {
+	head, err := br.Peek(len(utf8BOM))
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return false, err
+	}
+	if !bytes.Equal(head, utf8BOM) {
+		return false, nil
+	}
+	_, err = br.Discard(len(utf8BOM))
+	return true, err
+}
+
+// readPreviewLine reads the next line of br, keeping at most
+// maxPreviewLineBytes of it, and returns how many bytes it consumed
+func readPreviewLine(br *bufio.Reader) (previewLine, int64, error) {
+	var raw []byte
+	var read int64
+	for {
+		chunk, err := br.ReadSlice('\n')
+		read += int64(len(chunk))
+		// Room for a CRLF after a line of the maximum length
+		if room := maxPreviewLineBytes + 2 - len(raw); room > 0 {
+			if len(chunk) > room {
+				chunk = chunk[:room]
+			}
+			raw = append(raw, chunk...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == io.EOF && read > 0 {
+			break
+		}
+		if err != nil {
+			return previewLine{}, read, err
+		}
+		break
+	}
+
+	var line previewLine
+	if bytes.HasSuffix(raw, []byte("\n")) {
+		raw = raw[:len(raw)-1]
+		if bytes.HasSuffix(raw, []byte("\r")) {
+			raw = raw[:len(raw)-1]
+			line.crlf = true
+		}
+	}
+	if len(raw) > maxPreviewLineBytes {
+		raw = raw[:maxPreviewLineBytes]
+		line.truncated = true
+	}
+	line.raw = raw
+	return line, read, nil
+}
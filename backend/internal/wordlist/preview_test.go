@@ -0,0 +1,86 @@
+package wordlist
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeWordlist writes content to a file named name in a temporary directory
+func writeWordlist(t *testing.T, name string, content []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, content, 0644))
+	return path
+}
+
+func TestPreviewWordlistFileHead(t *testing.T) {
+	m := &manager{}
+	content := "\xEF\xBB\xBFpassword\r\nletmein\n\xFFbyte\n" + strings.Repeat("a", 600) + "\nlast"
+	path := writeWordlist(t, "list.txt", []byte(content))
+
+	preview, err := m.PreviewWordlistFile(path, models.WordlistPreviewHead, 10)
+	require.NoError(t, err)
+
+	require.Len(t, preview.Lines, 5)
+	assert.Equal(t, "password", preview.Lines[0])
+	assert.Equal(t, "letmein", preview.Lines[1])
+	assert.Equal(t, "$HEX[ff62797465]", preview.Lines[2])
+	assert.Len(t, preview.Lines[3], maxPreviewLineBytes)
+	assert.Equal(t, "last", preview.Lines[4])
+	assert.True(t, preview.HasBOM)
+	assert.Equal(t, 1, preview.CRLFLines)
+	assert.Equal(t, 1, preview.NonUTF8Lines)
+	assert.Equal(t, 1, preview.TruncatedLines)
+	assert.False(t, preview.Partial)
+}
+
+func TestPreviewWordlistFileRandomKeepsFileOrder(t *testing.T) {
+	m := &manager{}
+	var sb strings.Builder
+	for i := 0; i < 500; i++ {
+		sb.WriteString(strings.Repeat("x", i%7+1))
+		sb.WriteString("\n")
+	}
+	path := writeWordlist(t, "list.txt", []byte(sb.String()))
+
+	preview, err := m.PreviewWordlistFile(path, models.WordlistPreviewRandom, 25)
+	require.NoError(t, err)
+	assert.Len(t, preview.Lines, 25)
+	for _, line := range preview.Lines {
+		assert.NotEmpty(t, line)
+	}
+}
+
+func TestPreviewWordlistFileGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.txt.gz")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	_, err = gz.Write([]byte("one\ntwo\nthree\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+
+	m := &manager{}
+	preview, err := m.PreviewWordlistFile(path, models.WordlistPreviewHead, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, preview.Lines)
+}
+
+func TestPreviewWordlistFileRejectsInvalidArguments(t *testing.T) {
+	m := &manager{}
+	path := writeWordlist(t, "list.txt", []byte("one\n"))
+
+	_, err := m.PreviewWordlistFile(path, "tail", 10)
+	assert.Error(t, err)
+	_, err = m.PreviewWordlistFile(path, models.WordlistPreviewHead, 0)
+	assert.Error(t, err)
+	_, err = m.PreviewWordlistFile(path, models.WordlistPreviewHead, MaxPreviewLines+1)
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"strconv"
+	"time"
 
 	"github.com/ZerkerEOD/krakenhashes/backend/internal/models"
 	"github.com/ZerkerEOD/krakenhashes/backend/pkg/debug"
@@ -27,7 +28,7 @@ func (s *Store) ListWordlists(ctx context.Context, filters map[string]interface{
 		SELECT w.id, w.name, w.description, w.wordlist_type, w.format, w.file_name, 
 		       w.md5_hash, w.file_size, w.word_count, w.created_at, w.created_by, 
 		       w.updated_at, w.updated_by, w.last_verified_at, w.verification_status,
-		       w.is_potfile
+		       w.is_potfile, w.file_modified_at
 		FROM wordlists w
 		WHERE 1=1
 	`
@@ -75,7 +76,7 @@ func (s *Store) ListWordlists(ctx context.Context, filters map[string]interface{
 			&w.ID, &w.Name, &w.Description, &w.WordlistType, &w.Format, &w.FileName,
 			&w.MD5Hash, &w.FileSize, &w.WordCount, &w.CreatedAt, &w.CreatedBy,
 			&w.UpdatedAt, &w.UpdatedBy, &lastVerifiedAt, &w.VerificationStatus,
-			&w.IsPotfile,
+			&w.IsPotfile, &w.FileModifiedAt,
 		)
 		if err != nil {
 			debug.Error("Failed to scan wordlist row: %v", err)
@@ -95,6 +96,12 @@ func (s *Store) ListWordlists(ctx context.Context, filters map[string]interface{
 		}
 		w.Tags = tags
 
+		aliases, err := s.GetWordlistAliases(ctx, w.ID)
+		if err != nil {
+			return nil, err
+		}
+		w.Aliases = aliases
+
 		wordlists = append(wordlists, w)
 	}
 
@@ -112,7 +119,7 @@ func (s *Store) GetWordlist(ctx context.Context, id int) (*models.Wordlist, erro
 		SELECT w.id, w.name, w.description, w.wordlist_type, w.format, w.file_name, 
 		       w.md5_hash, w.file_size, w.word_count, w.created_at, w.created_by, 
 		       w.updated_at, w.updated_by, w.last_verified_at, w.verification_status,
-		       w.is_potfile
+		       w.is_potfile, w.file_modified_at
 		FROM wordlists w
 		WHERE w.id = $1
 	`
@@ -124,7 +131,7 @@ func (s *Store) GetWordlist(ctx context.Context, id int) (*models.Wordlist, erro
 		&w.ID, &w.Name, &w.Description, &w.WordlistType, &w.Format, &w.FileName,
 		&w.MD5Hash, &w.FileSize, &w.WordCount, &w.CreatedAt, &w.CreatedBy,
 		&w.UpdatedAt, &w.UpdatedBy, &lastVerifiedAt, &w.VerificationStatus,
-		&w.IsPotfile,
+		&w.IsPotfile, &w.FileModifiedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -147,6 +154,12 @@ func (s *Store) GetWordlist(ctx context.Context, id int) (*models.Wordlist, erro
 	}
 	w.Tags = tags
 
+	aliases, err := s.GetWordlistAliases(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	w.Aliases = aliases
+
 	return w, nil
 }
 
@@ -156,7 +169,7 @@ func (s *Store) GetWordlistByFilename(ctx context.Context, filename string) (*mo
 		SELECT w.id, w.name, w.description, w.wordlist_type, w.format, w.file_name, 
 		       w.md5_hash, w.file_size, w.word_count, w.created_at, w.created_by, 
 		       w.updated_at, w.updated_by, w.last_verified_at, w.verification_status,
-		       w.is_potfile
+		       w.is_potfile, w.file_modified_at
 		FROM wordlists w
 		WHERE w.file_name = $1
 	`
@@ -168,7 +181,7 @@ func (s *Store) GetWordlistByFilename(ctx context.Context, filename string) (*mo
 		&w.ID, &w.Name, &w.Description, &w.WordlistType, &w.Format, &w.FileName,
 		&w.MD5Hash, &w.FileSize, &w.WordCount, &w.CreatedAt, &w.CreatedBy,
 		&w.UpdatedAt, &w.UpdatedBy, &lastVerifiedAt, &w.VerificationStatus,
-		&w.IsPotfile,
+		&w.IsPotfile, &w.FileModifiedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -200,7 +213,7 @@ func (s *Store) GetWordlistByMD5Hash(ctx context.Context, md5Hash string) (*mode
 		SELECT w.id, w.name, w.description, w.wordlist_type, w.format, w.file_name, 
 		       w.md5_hash, w.file_size, w.word_count, w.created_at, w.created_by, 
 		       w.updated_at, w.updated_by, w.last_verified_at, w.verification_status,
-		       w.is_potfile
+		       w.is_potfile, w.file_modified_at
 		FROM wordlists w
 		WHERE w.md5_hash = $1
 	`
@@ -212,7 +225,7 @@ func (s *Store) GetWordlistByMD5Hash(ctx context.Context, md5Hash string) (*mode
 		&w.ID, &w.Name, &w.Description, &w.WordlistType, &w.Format, &w.FileName,
 		&w.MD5Hash, &w.FileSize, &w.WordCount, &w.CreatedAt, &w.CreatedBy,
 		&w.UpdatedAt, &w.UpdatedBy, &lastVerifiedAt, &w.VerificationStatus,
-		&w.IsPotfile,
+		&w.IsPotfile, &w.FileModifiedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -238,6 +251,35 @@ func (s *Store) GetWordlistByMD5Hash(ctx context.Context, md5Hash string) (*mode
 	return w, nil
 }
 
+// GetWordlistByCanonicalHash retrieves the oldest wordlist with the given canonical
+// hash, or nil if there is none
+func (s *Store) GetWordlistByCanonicalHash(ctx context.Context, canonicalHash string) (*models.Wordlist, error) {
+	var id int
+	err := s.db.QueryRowContext(ctx,
+		"SELECT id FROM wordlists WHERE canonical_hash = $1 ORDER BY id LIMIT 1", canonicalHash).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		debug.Error("Failed to get wordlist by canonical hash %s: %v", canonicalHash, err)
+		return nil, err
+	}
+
+	return s.GetWordlist(ctx, id)
+}
+
+// UpdateWordlistCanonicalHash records the canonical hash of a wordlist's file
+func (s *Store) UpdateWordlistCanonicalHash(ctx context.Context, id int, canonicalHash string) error {
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE wordlists SET canonical_hash = NULLIF($1, '') WHERE id = $2", canonicalHash, id)
+	if err != nil {
+		debug.Error("Failed to update canonical hash of wordlist %d: %v", id, err)
+		return err
+	}
+
+	return nil
+}
+
 // CreateWordlist creates a new wordlist
 func (s *Store) CreateWordlist(ctx context.Context, wordlist *models.Wordlist) error {
 	query := `
@@ -294,6 +336,37 @@ func (s *Store) UpdateWordlist(ctx context.Context, wordlist *models.Wordlist) e
 	return nil
 }
 
+// UpdateWordlistFileModTime records the modification time of the wordlist file as it was
+// when it was last hashed
+func (s *Store) UpdateWordlistFileModTime(ctx context.Context, id int, modTime time.Time) error {
+	// The column keeps microseconds, Postgres would round the rest instead of
+	// truncating it like the comparison with the file's time does
+	_, err := s.db.ExecContext(ctx, "UPDATE wordlists SET file_modified_at = $1 WHERE id = $2", modTime.Truncate(time.Microsecond), id)
+	if err != nil {
+		debug.Error("Failed to update file modification time of wordlist %d: %v", id, err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateWordlistPath updates the file name of a wordlist after its file was moved
+func (s *Store) UpdateWordlistPath(ctx context.Context, id int, fileName string) error {
+	query := `
+		UPDATE wordlists
+		SET file_name = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	_, err := s.db.ExecContext(ctx, query, fileName, id)
+	if err != nil {
+		debug.Error("Failed to update path of wordlist %d: %v", id, err)
+		return err
+	}
+
+	return nil
+}
+
 // DeleteWordlist deletes a wordlist
 func (s *Store) DeleteWordlist(ctx context.Context, id int) error {
 	// Delete tags first (foreign key constraint)
@@ -434,3 +507,43 @@ func (s *Store) DeleteWordlistTag(ctx context.Context, id int, tag string) error
 
 	return nil
 }
+
+// GetWordlistAliases gets the names duplicate uploads of a wordlist were aliased under
+func (s *Store) GetWordlistAliases(ctx context.Context, id int) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT alias FROM wordlist_aliases WHERE wordlist_id = $1 ORDER BY created_at", id)
+	if err != nil {
+		debug.Error("Failed to get aliases for wordlist %d: %v", id, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []string{}
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			debug.Error("Failed to scan alias: %v", err)
+			return nil, err
+		}
+		aliases = append(aliases, alias)
+	}
+
+	if err := rows.Err(); err != nil {
+		debug.Error("Error iterating alias rows: %v", err)
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+// AddWordlistAlias records a name a duplicate upload of a wordlist was aliased under
+func (s *Store) AddWordlistAlias(ctx context.Context, id int, alias string, userID uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO wordlist_aliases (wordlist_id, alias, created_by) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING",
+		id, alias, userID)
+	if err != nil {
+		debug.Error("Failed to add alias %s to wordlist %d: %v", alias, id, err)
+		return err
+	}
+
+	return nil
+}
@@ -69,7 +69,7 @@ func Log(message string, fields map[string]interface{}) {
 	} else {
 		var fieldStrs []string
 		for k, v := range fields {
-			fieldStrs = append(fieldStrs, fmt.Sprintf("%s=%v", k, v))
+			fieldStrs = append(fieldStrs, fmt.Sprintf("%s=%v", k, redactField(k, v)))
 		}
 		LogWithLevel(LevelInfo, "%s [%s]", message, strings.Join(fieldStrs, ", "))
 	}
@@ -85,8 +85,8 @@ func LogWithLevel(level LogLevel, format string, v ...interface{}) {
 	pc, file, line, _ := runtime.Caller(2)
 	funcName := runtime.FuncForPC(pc).Name()
 
-	// Format the message
-	message := fmt.Sprintf(format, v...)
+	// Format the message, masking any hashes in it
+	message := redactMessage(fmt.Sprintf(format, v...))
 	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
 
 	logger.Printf("[%s] [%s] [%s:%d] [%s] %s\n",
@@ -133,6 +133,8 @@ func Reinitialize() {
 		CurrentLevel = LevelInfo // Default to INFO if not specified
 	}
 
+	Redact = redactFromEnv()
+
 	// Only log initialization if debugging is enabled
 	if IsEnabled {
 		Info("Debug logging reinitialized - Enabled: %v, Level: %s", IsEnabled, levelNames[CurrentLevel])
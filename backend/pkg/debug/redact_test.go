@@ -0,0 +1,35 @@
+package debug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	original := Redact
+	defer func() { Redact = original }()
+
+	Redact = true
+	ntlm := "8846f7eaee8fb117ad06bdd830b7586c"
+
+	masked := Hash(ntlm)
+	assert.True(t, strings.HasPrefix(masked, "8846f7ea…#"))
+	assert.NotContains(t, masked, ntlm)
+	assert.Equal(t, masked, Hash(ntlm), "fingerprints are stable within a run")
+	assert.NotContains(t, Plain("password"), "password")
+
+	message := redactMessage("Cracked " + ntlm + " and $2y$10$abcdefghijklmnopqrstuv in task 42")
+	assert.NotContains(t, message, ntlm)
+	assert.NotContains(t, message, "abcdefghijklmnopqrstuv")
+	assert.Contains(t, message, "in task 42")
+
+	assert.NotEqual(t, "hunter2", redactField("password", "hunter2"))
+	assert.Equal(t, 7, redactField("hashlist_id", 7))
+
+	Redact = false
+	assert.Equal(t, ntlm, Hash(ntlm))
+	assert.Equal(t, "password", Plain("password"))
+	assert.Equal(t, ntlm, redactMessage(ntlm))
+}
@@ -2,9 +2,13 @@ package fsutil
 
 import (
 	"bufio"
+	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -178,3 +182,68 @@ func ExtractBaseNameWithoutExt(filename string) string {
 	
 	return nameWithoutExt
 }
+
+// CleanRelativeDir validates a directory given relative to a managed root such
+// as the wordlists directory and returns it cleaned, with forward slashes.
+// The root itself is returned as "". Absolute paths, paths leaving the root
+// and hidden directories are rejected.
+func CleanRelativeDir(dir string) (string, error) {
+	dir = strings.TrimSpace(strings.ReplaceAll(dir, "\\", "/"))
+	if strings.HasPrefix(dir, "/") {
+		return "", fmt.Errorf("directory must be relative")
+	}
+	for _, part := range strings.Split(dir, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("directory must stay inside its root")
+		}
+	}
+	cleaned := path.Clean("/" + dir)[1:]
+	for _, part := range strings.Split(cleaned, "/") {
+		if strings.HasPrefix(part, ".") {
+			return "", fmt.Errorf("hidden directories are not allowed")
+		}
+	}
+	return cleaned, nil
+}
+
+// ListDirectories returns every directory below root as a slash separated
+// path relative to it, sorted. Hidden directories and their contents are skipped.
+func ListDirectories(root string) ([]string, error) {
+	dirs := []string{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || p == root {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		dirs = append(dirs, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// MovePath renames a file or directory, creating the parent directory of dst.
+// It refuses to overwrite an existing dst.
+func MovePath(src, dst string) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("%s already exists", dst)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.Rename(src, dst)
+}
@@ -1,6 +1,9 @@
 package fsutil
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -89,3 +92,68 @@ func TestExtractBaseNameWithoutExt(t *testing.T) {
 		})
 	}
 }
+
+func TestCleanRelativeDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "root", input: "", expected: ""},
+		{name: "nested", input: "general/leaks/", expected: "general/leaks"},
+		{name: "backslashes", input: "general\\leaks", expected: "general/leaks"},
+		{name: "redundant parts", input: "general/./leaks//2024", expected: "general/leaks/2024"},
+		{name: "absolute", input: "/etc", wantErr: true},
+		{name: "parent", input: "general/../../etc", wantErr: true},
+		{name: "hidden", input: "general/.cache", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CleanRelativeDir(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CleanRelativeDir(%q) = %q, want error", tt.input, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CleanRelativeDir(%q) returned error: %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("CleanRelativeDir(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestListDirectoriesAndMovePath(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"general/leaks", "custom", ".hidden/inner"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "general", "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dirs, err := ListDirectories(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"custom", "general", "general/leaks"}; !reflect.DeepEqual(dirs, expected) {
+		t.Errorf("ListDirectories() = %v, want %v", dirs, expected)
+	}
+
+	if err := MovePath(filepath.Join(root, "general", "a.txt"), filepath.Join(root, "targeted", "x", "a.txt")); err != nil {
+		t.Fatalf("MovePath() returned error: %v", err)
+	}
+	if !FileExists(filepath.Join(root, "targeted", "x", "a.txt")) {
+		t.Error("moved file not found at destination")
+	}
+	if err := MovePath(filepath.Join(root, "custom"), filepath.Join(root, "general")); err == nil {
+		t.Error("MovePath() overwrote an existing destination")
+	}
+}
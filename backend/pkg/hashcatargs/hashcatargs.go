@@ -0,0 +1,312 @@
+// Package hashcatargs validates extra hashcat parameters that administrators
+// attach to jobs, preset jobs and agents. The agent builds most of the hashcat
+// command line itself, so only options from an allowlist are accepted and
+// parameters travel to the agent as an argv array, never through a shell.
+package hashcatargs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	reasonJob      = "is set from the job's attack configuration"
+	reasonKeyspace = "would change how the keyspace is split into chunks"
+	reasonOutput   = "would break how the agent reads hashcat's status and cracks"
+	reasonMode     = "runs hashcat in a mode other than cracking"
+	reasonDevices  = "is managed per agent through device management"
+	reasonFiles    = "reads or writes files on the agent"
+)
+
+// reserved maps each refused option to the reason it is refused
+var reserved = map[string]string{
+	"-m": reasonJob, "--hash-type": reasonJob,
+	"-a": reasonJob, "--attack-mode": reasonJob,
+	"-r": reasonJob, "--rules-file": reasonJob,
+	"-j": reasonJob, "--rule-left": reasonJob,
+	"-k": reasonJob, "--rule-right": reasonJob,
+	"-g": reasonJob, "--generate-rules": reasonJob,
+	"--username": reasonJob,
+
+	"-1": reasonJob, "--custom-charset1": reasonJob,
+	"-2": reasonJob, "--custom-charset2": reasonJob,
+	"-3": reasonJob, "--custom-charset3": reasonJob,
+	"-4": reasonJob, "--custom-charset4": reasonJob,
+
+	"-s": reasonKeyspace, "--skip": reasonKeyspace,
+	"-l": reasonKeyspace, "--limit": reasonKeyspace,
+	"-i": reasonKeyspace, "--increment": reasonKeyspace,
+	"--increment-min": reasonKeyspace, "--increment-max": reasonKeyspace,
+
+	"-o": reasonOutput, "--outfile": reasonOutput,
+	"--outfile-format": reasonOutput, "--outfile-autohex-disable": reasonOutput,
+	"--status": reasonOutput, "--status-json": reasonOutput, "--status-timer": reasonOutput,
+	"--quiet": reasonOutput, "--machine-readable": reasonOutput,
+	"--potfile-disable": reasonOutput, "--remove": reasonOutput, "--remove-timer": reasonOutput,
+	"--restore": reasonOutput, "--restore-disable": reasonOutput, "--session": reasonOutput,
+
+	"--show": reasonMode, "--left": reasonMode, "--stdout": reasonMode,
+	"-b": reasonMode, "--benchmark": reasonMode, "--benchmark-all": reasonMode,
+	"--keyspace": reasonMode, "--identify": reasonMode,
+	"-V": reasonMode, "--version": reasonMode, "-h": reasonMode, "--help": reasonMode,
+	"-I": reasonMode, "--backend-info": reasonMode,
+	"--example-hashes": reasonMode, "--hash-info": reasonMode,
+
+	"-d": reasonDevices, "--backend-devices": reasonDevices,
+
+	"--potfile-path": reasonFiles, "--restore-file-path": reasonFiles,
+	"--debug-file": reasonFiles, "--debug-mode": reasonFiles,
+	"--induction-dir": reasonFiles, "--outfile-check-dir": reasonFiles,
+	"--markov-hcstat2": reasonFiles, "--logfile-disable": reasonFiles,
+}
+
+// valueKind is the kind of value an allowed option takes
+type valueKind int
+
+const (
+	valueNone   valueKind = iota // A flag
+	valueNumber                  // A non-negative integer, e.g. -w 3
+	valueList                    // Comma-separated integers, e.g. -D 1,2
+	valueWord                    // A name such as an encoding, e.g. utf-8
+)
+
+// allowed maps each option that may be set to the kind of value it takes.
+// Anything else is refused.
+var allowed = map[string]valueKind{
+	"-O": valueNone, "--optimized-kernel-enable": valueNone,
+	"-S": valueNone, "--slow-candidates": valueNone,
+	"-M": valueNone, "--multiply-accel-disable": valueNone,
+	"-w": valueNumber, "--workload-profile": valueNumber,
+	"-n": valueNumber, "--kernel-accel": valueNumber,
+	"-u": valueNumber, "--kernel-loops": valueNumber,
+	"-T": valueNumber, "--kernel-threads": valueNumber,
+	"-c": valueNumber, "--segment-size": valueNumber,
+	"-t": valueNumber, "--markov-threshold": valueNumber,
+	"-D": valueList, "--opencl-device-types": valueList,
+	"--backend-vector-width":     valueNumber,
+	"--backend-ignore-cuda":      valueNone,
+	"--backend-ignore-hip":       valueNone,
+	"--backend-ignore-metal":     valueNone,
+	"--backend-ignore-opencl":    valueNone,
+	"--cpu-affinity":             valueList,
+	"--spin-damp":                valueNumber,
+	"--scrypt-tmto":              valueNumber,
+	"--bitmap-min":               valueNumber,
+	"--bitmap-max":               valueNumber,
+	"--hook-threads":             valueNumber,
+	"--hwmon-disable":            valueNone,
+	"--hwmon-temp-abort":         valueNumber,
+	"--force":                    valueNone,
+	"--self-test-disable":        valueNone,
+	"--keep-guessing":            valueNone,
+	"--markov-disable":           valueNone,
+	"--markov-classic":           valueNone,
+	"--markov-inverse":           valueNone,
+	"--wordlist-autohex-disable": valueNone,
+	"--hex-charset":              valueNone,
+	"--hex-salt":                 valueNone,
+	"--hex-wordlist":             valueNone,
+	"--encoding-from":            valueWord,
+	"--encoding-to":              valueWord,
+}
+
+var (
+	listValue = regexp.MustCompile(`^[0-9]+(,[0-9]+)*$`)
+	wordValue = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+// OptionName returns the option a command line token sets, e.g. "-w" for
+// "-w3" and "--bitmap-max" for "--bitmap-max=24", or "" for a value
+func OptionName(token string) string {
+	if !strings.HasPrefix(token, "-") || len(token) < 2 {
+		return ""
+	}
+	if strings.HasPrefix(token, "--") {
+		if i := strings.Index(token, "="); i >= 0 {
+			return token[:i]
+		}
+		return token
+	}
+	return token[:2]
+}
+
+// Validate checks extra hashcat parameters and returns an error naming the
+// first option that may not be set
+func Validate(params string) error {
+	_, err := Split(params)
+	return err
+}
+
+// Split validates extra hashcat parameters and returns them as argv tokens.
+// Every token must be an allowed option or the value that follows one, so
+// parameters can neither add files to the attack nor smuggle in other options.
+func Split(params string) ([]string, error) {
+	tokens := strings.Fields(params)
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		name := OptionName(token)
+		if name == "" {
+			return nil, fmt.Errorf("argument %q is not an option, only hashcat options can be set", token)
+		}
+		if reason, ok := reserved[name]; ok {
+			return nil, fmt.Errorf("option %s cannot be set: it %s", name, reason)
+		}
+		kind, ok := allowed[name]
+		if !ok {
+			return nil, fmt.Errorf("option %s cannot be set: it is not a supported hashcat option", name)
+		}
+
+		// The value is attached ("-w3", "--bitmap-max=24") or the next token
+		value, attached := strings.CutPrefix(token, name)
+		if strings.HasPrefix(name, "--") {
+			value, attached = strings.CutPrefix(value, "=")
+		} else {
+			attached = value != ""
+		}
+		if kind == valueNone {
+			if attached {
+				return nil, fmt.Errorf("option %s cannot be set: it takes no value", name)
+			}
+			continue
+		}
+		if !attached {
+			if i+1 == len(tokens) {
+				return nil, fmt.Errorf("option %s cannot be set: it needs a value", name)
+			}
+			i++
+			value = tokens[i]
+		}
+		if err := checkValue(kind, value); err != nil {
+			return nil, fmt.Errorf("option %s cannot be set: %w", name, err)
+		}
+	}
+	return tokens, nil
+}
+
+// checkValue checks the value of an option against its kind
+func checkValue(kind valueKind, value string) error {
+	switch kind {
+	case valueNumber:
+		if _, err := strconv.ParseUint(value, 10, 32); err != nil {
+			return fmt.Errorf("value %q is not a number", value)
+		}
+	case valueList:
+		if !listValue.MatchString(value) {
+			return fmt.Errorf("value %q is not a comma-separated list of numbers", value)
+		}
+	case valueWord:
+		if !wordValue.MatchString(value) {
+			return fmt.Errorf("value %q may only contain letters, digits, '.', '_' and '-'", value)
+		}
+	}
+	return nil
+}
+
+// Quote renders argv tokens as a command line for display, single-quoting
+// tokens that a shell would split or expand
+func Quote(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, token := range argv {
+		if token != "" && !strings.ContainsAny(token, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+			quoted[i] = token
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(token, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// isWorkloadOption reports whether an option name sets hashcat's workload profile
+func isWorkloadOption(name string) bool {
+	return name == "-w" || name == "--workload-profile"
+}
+
+// WithWorkloadProfile returns params with "-w level" in front, unless params
+// already set a workload profile. A level of 0 returns params unchanged.
+func WithWorkloadProfile(params string, level int) string {
+	if level == 0 {
+		return params
+	}
+	for _, token := range strings.Fields(params) {
+		if isWorkloadOption(OptionName(token)) {
+			return params
+		}
+	}
+	return strings.TrimSpace(fmt.Sprintf("-w %d %s", level, params))
+}
+
+// WithoutWorkloadProfile returns argv without any workload profile option and
+// its value. Other tokens are kept as they are, spaces included.
+func WithoutWorkloadProfile(argv []string) []string {
+	kept := make([]string, 0, len(argv))
+	for i := 0; i < len(argv); i++ {
+		token := argv[i]
+		name := OptionName(token)
+		if !isWorkloadOption(name) {
+			kept = append(kept, token)
+			continue
+		}
+		// "-w 3" and "--workload-profile 3" carry the level in the next token
+		if token == name && i+1 < len(argv) && OptionName(argv[i+1]) == "" {
+			i++
+		}
+	}
+	return kept
+}
+
+// shortNames maps the long form of options that also have a short form, so
+// either form is recognised as the same option when merging
+var shortNames = map[string]string{
+	"--optimized-kernel-enable": "-O",
+	"--slow-candidates":         "-S",
+	"--multiply-accel-disable":  "-M",
+	"--workload-profile":        "-w",
+	"--kernel-accel":            "-n",
+	"--kernel-loops":            "-u",
+	"--kernel-threads":          "-T",
+	"--segment-size":            "-c",
+	"--markov-threshold":        "-t",
+	"--opencl-device-types":     "-D",
+}
+
+// Merge returns base with the options of override merged over it: an option
+// set in override replaces the same option in base, other options of base
+// are kept in order ahead of those of override
+func Merge(base, override []string) []string {
+	overridden := make(map[string]bool)
+	for _, group := range groupOptions(override) {
+		overridden[canonicalName(group[0])] = true
+	}
+
+	merged := make([]string, 0, len(base)+len(override))
+	for _, group := range groupOptions(base) {
+		if !overridden[canonicalName(group[0])] {
+			merged = append(merged, group...)
+		}
+	}
+	return append(merged, override...)
+}
+
+// canonicalName returns the short form of the option a token sets, if it has one
+func canonicalName(token string) string {
+	name := OptionName(token)
+	if short, ok := shortNames[name]; ok {
+		return short
+	}
+	return name
+}
+
+// groupOptions splits argv into options, each with the values that follow it
+func groupOptions(argv []string) [][]string {
+	var groups [][]string
+	for _, token := range argv {
+		if OptionName(token) != "" || len(groups) == 0 {
+			groups = append(groups, []string{token})
+			continue
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], token)
+	}
+	return groups
+}
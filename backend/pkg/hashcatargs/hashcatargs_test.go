@@ -0,0 +1,100 @@
+package hashcatargs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionName(t *testing.T) {
+	assert.Equal(t, "-w", OptionName("-w"))
+	assert.Equal(t, "-w", OptionName("-w3"))
+	assert.Equal(t, "--bitmap-max", OptionName("--bitmap-max=24"))
+	assert.Equal(t, "--bitmap-max", OptionName("--bitmap-max"))
+	assert.Equal(t, "", OptionName("24"))
+	assert.Equal(t, "-1", OptionName("-1"))
+	assert.Equal(t, "", OptionName("-"))
+}
+
+func TestValidate(t *testing.T) {
+	allowed := []string{
+		"",
+		"-S",
+		"--bitmap-max 24 -w 4",
+		"-O --kernel-accel=64 --hwmon-temp-abort=90",
+		"--segment-size 64",
+	}
+	for _, params := range allowed {
+		assert.NoError(t, Validate(params), params)
+	}
+
+	refused := map[string]string{
+		"-m 1000":               "-m",
+		"--hash-type=1000":      "--hash-type",
+		"-w 3 -a3":              "-a",
+		"--skip 100":            "--skip",
+		"-O --outfile /tmp/out": "--outfile",
+		"--show":                "--show",
+		"-d 1,2":                "-d",
+		"--potfile-path=/tmp/p": "--potfile-path",
+		"-r /etc/passwd":        "-r",
+		"-1 ?l?d":               "-1",
+		"--brain-server":        "--brain-server",
+		"-w $(reboot)":          "-w",
+		"-O3":                   "-O",
+		"--bitmap-max":          "--bitmap-max",
+		"-D 1;id":               "-D",
+		"--encoding-to=a/b":     "--encoding-to",
+	}
+	for params, option := range refused {
+		err := Validate(params)
+		if assert.Error(t, err, params) {
+			assert.Contains(t, err.Error(), "option "+option+" ")
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	argv, err := Split("  -w 3 --bitmap-max=24 -n64 -D 1,2 --encoding-to utf-8 ")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-w", "3", "--bitmap-max=24", "-n64", "-D", "1,2", "--encoding-to", "utf-8"}, argv)
+
+	argv, err = Split("")
+	assert.NoError(t, err)
+	assert.Empty(t, argv)
+
+	// Values without an option would be taken by hashcat as extra attack files
+	_, err = Split("-O /etc/shadow")
+	assert.ErrorContains(t, err, `"/etc/shadow" is not an option`)
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, "/opt/hashcat -a 3 -m 1000 'a b.hash' '?u?l?l?d'", Quote([]string{"/opt/hashcat", "-a", "3", "-m", "1000", "a b.hash", "?u?l?l?d"}))
+	assert.Equal(t, `'it'\''s' ''`, Quote([]string{"it's", ""}))
+}
+
+func TestWithWorkloadProfile(t *testing.T) {
+	assert.Equal(t, "-w 2", WithWorkloadProfile("", 2))
+	assert.Equal(t, "-w 3 -O", WithWorkloadProfile("-O", 3))
+	assert.Equal(t, "-O -w4", WithWorkloadProfile("-O -w4", 3), "explicit profile wins")
+	assert.Equal(t, "--workload-profile=1", WithWorkloadProfile("--workload-profile=1", 3))
+	assert.Equal(t, "-O", WithWorkloadProfile("-O", 0))
+}
+
+func TestWithoutWorkloadProfile(t *testing.T) {
+	assert.Equal(t, []string{"-O"}, WithoutWorkloadProfile([]string{"-w", "4", "-O"}))
+	assert.Equal(t, []string{"-O"}, WithoutWorkloadProfile([]string{"-O", "-w4"}))
+	assert.Equal(t, []string{"-O", "--bitmap-max", "24"}, WithoutWorkloadProfile([]string{"--workload-profile", "4", "-O", "--bitmap-max", "24"}))
+	assert.Equal(t, []string{"-S"}, WithoutWorkloadProfile([]string{"--workload-profile=4", "-S"}))
+	assert.Empty(t, WithoutWorkloadProfile([]string{"-w", "3"}))
+	// Tokens are filtered whole, a value with spaces stays one token
+	assert.Equal(t, []string{"-a", "0", "hashes file.hash", "word list.txt"},
+		WithoutWorkloadProfile([]string{"-a", "0", "-w", "3", "hashes file.hash", "word list.txt"}))
+}
+
+func TestMerge(t *testing.T) {
+	assert.Equal(t, []string{"-O", "--bitmap-max", "24", "--workload-profile=4"},
+		Merge([]string{"-w", "3", "-O", "--bitmap-max", "24"}, []string{"--workload-profile=4"}))
+	assert.Equal(t, []string{"-w", "3"}, Merge([]string{"-w", "3"}, nil))
+	assert.Equal(t, []string{"-S"}, Merge(nil, []string{"-S"}))
+}
@@ -0,0 +1,299 @@
+// Package hashcatmask parses and validates hashcat masks. A mask is stored in
+// the hcmask line format, so up to four custom charsets can travel with it:
+// "?l?d,?u?1?1?1?d" defines ?1 as lower case letters and digits. Commas that
+// belong to a charset or the mask are escaped as "\,".
+package hashcatmask
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// MaxCustomCharsets is the number of custom charsets hashcat accepts
+	MaxCustomCharsets = 4
+	// MaxLength is the longest mask hashcat accepts, in positions
+	MaxLength = 256
+)
+
+var (
+	// ErrInvalidMask is wrapped by every mask validation error
+	ErrInvalidMask = errors.New("invalid mask")
+	// ErrInvalidCharset is wrapped by every custom charset validation error
+	ErrInvalidCharset = errors.New("invalid charset")
+)
+
+const (
+	lower   = "abcdefghijklmnopqrstuvwxyz"
+	upper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digits  = "0123456789"
+	special = " !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+// builtin maps the built-in charsets to their characters, ?b is handled
+// separately as it covers every byte
+var builtin = map[byte]string{
+	'l': lower,
+	'u': upper,
+	'd': digits,
+	'h': "0123456789abcdef",
+	'H': "0123456789ABCDEF",
+	's': special,
+	'a': lower + upper + digits + special,
+}
+
+// Line is a mask together with the custom charsets it references
+type Line struct {
+	Charsets []string
+	Mask     string
+}
+
+// Position describes one position of a mask
+type Position struct {
+	Token string `json:"token"`
+	Size  int    `json:"size"`
+}
+
+// Analysis is the result of validating a mask
+type Analysis struct {
+	Length    int        `json:"length"`
+	Positions []Position `json:"positions"`
+	Keyspace  int64      `json:"keyspace"`
+}
+
+// Bounds limits the length of the candidates a mask produces, zero means no
+// limit
+type Bounds struct {
+	MinLength int
+	MaxLength int
+}
+
+// ParseLine splits an hcmask line into its custom charsets and mask
+func ParseLine(line string) (Line, error) {
+	var fields []string
+	var current strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case line[i] == ',':
+			fields = append(fields, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(line[i])
+		}
+	}
+	fields = append(fields, current.String())
+
+	if len(fields) > MaxCustomCharsets+1 {
+		return Line{}, fmt.Errorf("%w: at most %d custom charsets can be defined", ErrInvalidMask, MaxCustomCharsets)
+	}
+	return Line{Charsets: fields[:len(fields)-1], Mask: fields[len(fields)-1]}, nil
+}
+
+// String returns the line in hcmask format
+func (l Line) String() string {
+	parts := make([]string, 0, len(l.Charsets)+1)
+	for _, charset := range l.Charsets {
+		parts = append(parts, escape(charset))
+	}
+	parts = append(parts, escape(l.Mask))
+	return strings.Join(parts, ",")
+}
+
+// Args returns the hashcat arguments for the line, the custom charset options
+// followed by the mask
+func (l Line) Args() []string {
+	args := make([]string, 0, 2*len(l.Charsets)+1)
+	for i, charset := range l.Charsets {
+		args = append(args, fmt.Sprintf("-%d", i+1), charset)
+	}
+	return append(args, l.Mask)
+}
+
+// LineArgs returns the hashcat arguments for an hcmask line. A line that
+// cannot be parsed is passed on unchanged, so hashcat reports the problem.
+func LineArgs(line string) []string {
+	l, err := ParseLine(line)
+	if err != nil {
+		return []string{line}
+	}
+	return l.Args()
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(s, ",", "\\,")
+}
+
+// Analyze validates the line and returns its positions and keyspace
+func Analyze(l Line, bounds Bounds) (*Analysis, error) {
+	if len(l.Charsets) > MaxCustomCharsets {
+		return nil, fmt.Errorf("%w: at most %d custom charsets can be defined", ErrInvalidMask, MaxCustomCharsets)
+	}
+	customSizes := make([]int, len(l.Charsets))
+	for i, charset := range l.Charsets {
+		size, err := charsetSize(charset)
+		if err != nil {
+			return nil, fmt.Errorf("%w: custom charset ?%d: %v", ErrInvalidMask, i+1, err)
+		}
+		customSizes[i] = size
+	}
+
+	if l.Mask == "" {
+		return nil, fmt.Errorf("%w: mask is empty", ErrInvalidMask)
+	}
+
+	analysis := &Analysis{Keyspace: 1}
+	for i := 0; i < len(l.Mask); i++ {
+		position := Position{Token: l.Mask[i : i+1], Size: 1}
+		if l.Mask[i] == '?' {
+			if i+1 >= len(l.Mask) {
+				return nil, fmt.Errorf("%w: mask ends with an incomplete placeholder", ErrInvalidMask)
+			}
+			i++
+			position.Token = l.Mask[i-1 : i+1]
+			size, err := placeholderSize(l.Mask[i], customSizes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: position %d: %v", ErrInvalidMask, len(analysis.Positions)+1, err)
+			}
+			position.Size = size
+		}
+		if analysis.Keyspace > math.MaxInt64/int64(position.Size) {
+			return nil, fmt.Errorf("%w: keyspace is too large", ErrInvalidMask)
+		}
+		analysis.Keyspace *= int64(position.Size)
+		analysis.Positions = append(analysis.Positions, position)
+	}
+	analysis.Length = len(analysis.Positions)
+
+	if analysis.Length > MaxLength {
+		return nil, fmt.Errorf("%w: mask is %d positions long, at most %d are allowed", ErrInvalidMask, analysis.Length, MaxLength)
+	}
+	if bounds.MinLength > 0 && analysis.Length < bounds.MinLength {
+		return nil, fmt.Errorf("%w: mask is %d positions long, at least %d are required", ErrInvalidMask, analysis.Length, bounds.MinLength)
+	}
+	if bounds.MaxLength > 0 && analysis.Length > bounds.MaxLength {
+		return nil, fmt.Errorf("%w: mask is %d positions long, at most %d are allowed", ErrInvalidMask, analysis.Length, bounds.MaxLength)
+	}
+	return analysis, nil
+}
+
+// Validate parses and analyzes an hcmask line
+func Validate(line string) (*Analysis, error) {
+	l, err := ParseLine(line)
+	if err != nil {
+		return nil, err
+	}
+	return Analyze(l, Bounds{})
+}
+
+// Increments returns the lines hashcat runs for a mask with --increment from
+// min to max positions, shortest first. Each keeps the custom charsets and the
+// first positions of the mask. A max of 0 runs up to the full mask length.
+func Increments(l Line, min, max int) ([]Line, error) {
+	analysis, err := Analyze(l, Bounds{})
+	if err != nil {
+		return nil, err
+	}
+	if max == 0 {
+		max = analysis.Length
+	}
+	if min < 1 || min > max {
+		return nil, fmt.Errorf("%w: increment minimum %d must be between 1 and the maximum %d", ErrInvalidMask, min, max)
+	}
+	if max > analysis.Length {
+		return nil, fmt.Errorf("%w: increment maximum %d is longer than the mask's %d positions", ErrInvalidMask, max, analysis.Length)
+	}
+
+	lines := make([]Line, 0, max-min+1)
+	for length := min; length <= max; length++ {
+		var mask strings.Builder
+		for _, position := range analysis.Positions[:length] {
+			mask.WriteString(position.Token)
+		}
+		lines = append(lines, Line{Charsets: l.Charsets, Mask: mask.String()})
+	}
+	return lines, nil
+}
+
+// CharsetSize validates a custom charset and returns the number of distinct
+// characters it stands for
+func CharsetSize(charset string) (int, error) {
+	size, err := charsetSize(charset)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidCharset, err)
+	}
+	return size, nil
+}
+
+// placeholderSize returns the number of characters a ?x placeholder in a mask
+// stands for
+func placeholderSize(c byte, customSizes []int) (int, error) {
+	switch {
+	case c == '?':
+		return 1, nil
+	case c == 'b':
+		return 256, nil
+	case c >= '1' && c <= '4':
+		index := int(c - '1')
+		if index >= len(customSizes) {
+			return 0, fmt.Errorf("custom charset ?%c is not defined", c)
+		}
+		return customSizes[index], nil
+	}
+	if chars, ok := builtin[c]; ok {
+		return len(chars), nil
+	}
+	return 0, fmt.Errorf("unknown charset ?%c", c)
+}
+
+// charsetSize returns the number of distinct characters of a custom charset,
+// which may combine built-in charsets and literal characters
+func charsetSize(charset string) (int, error) {
+	if charset == "" {
+		return 0, errors.New("charset is empty")
+	}
+
+	var seen [256]bool
+	add := func(chars string) {
+		for i := 0; i < len(chars); i++ {
+			seen[chars[i]] = true
+		}
+	}
+	for i := 0; i < len(charset); i++ {
+		if charset[i] != '?' {
+			seen[charset[i]] = true
+			continue
+		}
+		if i+1 >= len(charset) {
+			return 0, errors.New("charset ends with an incomplete placeholder")
+		}
+		i++
+		c := charset[i]
+		switch {
+		case c == '?':
+			seen['?'] = true
+		case c == 'b':
+			return 256, nil
+		case c >= '1' && c <= '4':
+			return 0, fmt.Errorf("charset cannot reference custom charset ?%c", c)
+		default:
+			chars, ok := builtin[c]
+			if !ok {
+				return 0, fmt.Errorf("unknown charset ?%c", c)
+			}
+			add(chars)
+		}
+	}
+
+	size := 0
+	for _, ok := range seen {
+		if ok {
+			size++
+		}
+	}
+	return size, nil
+}
@@ -0,0 +1,141 @@
+package hashcatmask
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		charsets []string
+		mask     string
+		invalid  bool
+	}{
+		{line: "?d?d?d?d", charsets: []string{}, mask: "?d?d?d?d"},
+		{line: "?l?d,?1?1?1", charsets: []string{"?l?d"}, mask: "?1?1?1"},
+		{line: "?l,?u,?d,?s,?1?2?3?4", charsets: []string{"?l", "?u", "?d", "?s"}, mask: "?1?2?3?4"},
+		{line: "\\,.;,pass?1", charsets: []string{",.;"}, mask: "pass?1"},
+		{line: "a,b,c,d,e,?1", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			l, err := ParseLine(tt.line)
+			if tt.invalid {
+				assert.True(t, errors.Is(err, ErrInvalidMask), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.charsets, l.Charsets)
+			assert.Equal(t, tt.mask, l.Mask)
+			assert.Equal(t, tt.line, l.String())
+		})
+	}
+}
+
+func TestArgs(t *testing.T) {
+	l := Line{Charsets: []string{"?l?d", ",."}, Mask: "?1?2"}
+	assert.Equal(t, []string{"-1", "?l?d", "-2", ",.", "?1?2"}, l.Args())
+	assert.Equal(t, []string{"?d?d"}, Line{Mask: "?d?d"}.Args())
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     Line
+		bounds   Bounds
+		keyspace int64
+		sizes    []int
+		invalid  bool
+	}{
+		{name: "digits", line: Line{Mask: "?d?d?d?d"}, keyspace: 10000, sizes: []int{10, 10, 10, 10}},
+		{name: "builtins", line: Line{Mask: "?l?u?d?s"}, keyspace: 26 * 26 * 10 * 33},
+		{name: "literals", line: Line{Mask: "pass?d"}, keyspace: 10, sizes: []int{1, 1, 1, 1, 10}},
+		{name: "escaped question mark", line: Line{Mask: "?h?H??"}, keyspace: 256},
+		{name: "custom charset", line: Line{Charsets: []string{"?l?d"}, Mask: "?1?1"}, keyspace: 36 * 36},
+		{name: "overlapping charset", line: Line{Charsets: []string{"?h?d"}, Mask: "?1"}, keyspace: 16},
+		{name: "literal charset", line: Line{Charsets: []string{"!@#$"}, Mask: "?u?1"}, keyspace: 26 * 4},
+		{name: "byte charset", line: Line{Charsets: []string{"?b"}, Mask: "?1"}, keyspace: 256},
+		{name: "within bounds", line: Line{Mask: "?d?d?d"}, bounds: Bounds{MinLength: 3, MaxLength: 3}, keyspace: 1000},
+		{name: "empty", line: Line{}, invalid: true},
+		{name: "incomplete placeholder", line: Line{Mask: "?d?"}, invalid: true},
+		{name: "unknown charset", line: Line{Mask: "?x"}, invalid: true},
+		{name: "undefined custom charset", line: Line{Charsets: []string{"?l"}, Mask: "?1?2"}, invalid: true},
+		{name: "empty custom charset", line: Line{Charsets: []string{""}, Mask: "?1"}, invalid: true},
+		{name: "nested custom charset", line: Line{Charsets: []string{"?l", "?1?d"}, Mask: "?2"}, invalid: true},
+		{name: "too short", line: Line{Mask: "?d?d"}, bounds: Bounds{MinLength: 3}, invalid: true},
+		{name: "too long", line: Line{Mask: "?d?d"}, bounds: Bounds{MaxLength: 1}, invalid: true},
+		{name: "keyspace overflow", line: Line{Mask: "?b?b?b?b?b?b?b?b"}, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis, err := Analyze(tt.line, tt.bounds)
+			if tt.invalid {
+				assert.True(t, errors.Is(err, ErrInvalidMask), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.keyspace, analysis.Keyspace)
+			assert.Equal(t, len(analysis.Positions), analysis.Length)
+			if tt.sizes != nil {
+				sizes := make([]int, len(analysis.Positions))
+				for i, position := range analysis.Positions {
+					sizes[i] = position.Size
+				}
+				assert.Equal(t, tt.sizes, sizes)
+			}
+		})
+	}
+}
+
+func TestAnalyzeMaxLength(t *testing.T) {
+	mask := make([]byte, MaxLength+1)
+	for i := range mask {
+		mask[i] = 'a'
+	}
+	_, err := Analyze(Line{Mask: string(mask)}, Bounds{})
+	assert.True(t, errors.Is(err, ErrInvalidMask))
+
+	_, err = Analyze(Line{Mask: string(mask[:MaxLength])}, Bounds{})
+	assert.NoError(t, err)
+}
+
+func TestIncrements(t *testing.T) {
+	l := Line{Charsets: []string{"?l?d"}, Mask: "a?1?d??"}
+
+	lines, err := Increments(l, 2, 0)
+	require.NoError(t, err)
+	masks := make([]string, len(lines))
+	for i, line := range lines {
+		masks[i] = line.Mask
+		assert.Equal(t, l.Charsets, line.Charsets)
+	}
+	assert.Equal(t, []string{"a?1", "a?1?d", "a?1?d??"}, masks)
+
+	lines, err = Increments(Line{Mask: "?d?d?d"}, 1, 2)
+	require.NoError(t, err)
+	assert.Len(t, lines, 2)
+
+	for _, bounds := range [][2]int{{0, 2}, {3, 2}, {1, 4}} {
+		_, err := Increments(Line{Mask: "?d?d?d"}, bounds[0], bounds[1])
+		assert.True(t, errors.Is(err, ErrInvalidMask), "bounds %v: got %v", bounds, err)
+	}
+}
+
+func TestCharsetSize(t *testing.T) {
+	size, err := CharsetSize("?l?d")
+	require.NoError(t, err)
+	assert.Equal(t, 36, size)
+
+	size, err = CharsetSize("aab??")
+	require.NoError(t, err)
+	assert.Equal(t, 3, size)
+
+	_, err = CharsetSize("?z")
+	assert.True(t, errors.Is(err, ErrInvalidCharset))
+}
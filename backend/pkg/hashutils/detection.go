@@ -0,0 +1,55 @@
+package hashutils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// hashSignature recognizes one hash type by the layout of an input line
+type hashSignature struct {
+	hashTypeID int
+	prefix     string         // Case-sensitive prefix, checked before pattern
+	pattern    *regexp.Regexp // Optional pattern the whole line must match
+}
+
+// hashSignatures lists the layouts that identify a hash type without doubt,
+// most specific first. Bare hex digests are deliberately absent since their
+// length alone cannot tell MD5 from NTLM or SHA1 from RIPEMD-160.
+var hashSignatures = []hashSignature{
+	{hashTypeID: 13100, prefix: "$krb5tgs$23$"},
+	{hashTypeID: 19600, prefix: "$krb5tgs$17$"},
+	{hashTypeID: 19700, prefix: "$krb5tgs$18$"},
+	{hashTypeID: 18200, prefix: "$krb5asrep$23$"},
+	{hashTypeID: 7500, prefix: "$krb5pa$23$"},
+	{hashTypeID: 2100, prefix: "$DCC2$"},
+	{hashTypeID: 3200, prefix: "$2a$"},
+	{hashTypeID: 3200, prefix: "$2b$"},
+	{hashTypeID: 3200, prefix: "$2y$"},
+	{hashTypeID: 1600, prefix: "$apr1$"},
+	{hashTypeID: 500, prefix: "$1$"},
+	{hashTypeID: 7400, prefix: "$5$"},
+	{hashTypeID: 1800, prefix: "$6$"},
+	// NetNTLMv2: user::domain:challenge:ntproofstr:blob
+	{hashTypeID: 5600, pattern: regexp.MustCompile(`^[^:]+::[^:]*:[0-9a-fA-F]{16}:[0-9a-fA-F]{32}:[0-9a-fA-F]+$`)},
+	// NetNTLMv1: user::domain:lmresponse:ntresponse:challenge
+	{hashTypeID: 5500, pattern: regexp.MustCompile(`^[^:]+::[^:]*:[0-9a-fA-F]{48}:[0-9a-fA-F]{48}:[0-9a-fA-F]{16}$`)},
+	// pwdump: user:rid:lmhash:nthash:::
+	{hashTypeID: 1000, pattern: regexp.MustCompile(`^[^:]*:[0-9]+:[0-9a-fA-F]{32}:[0-9a-fA-F]{32}:::`)},
+}
+
+// DetectHashType identifies the hash type of an input line from its layout.
+// It returns false when the line does not match a known signature, in which
+// case the type the user chose for the upload applies.
+func DetectHashType(line string) (int, bool) {
+	line = strings.TrimSpace(line)
+	for _, sig := range hashSignatures {
+		if sig.prefix != "" && !strings.HasPrefix(line, sig.prefix) {
+			continue
+		}
+		if sig.pattern != nil && !sig.pattern.MatchString(line) {
+			continue
+		}
+		return sig.hashTypeID, true
+	}
+	return 0, false
+}
@@ -0,0 +1,40 @@
+package hashutils
+
+import "testing"
+
+func TestDetectHashType(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		wantID int
+		wantOK bool
+	}{
+		{"pwdump", "Administrator:500:aad3b435b51404eeaad3b435b51404ee:31d6cfe0d16ae931b73c59d7e0c089c0:::", 1000, true},
+		{"netntlmv2", "admin::CORP:1122334455667788:0123456789abcdef0123456789abcdef:0101000000000000", 5600, true},
+		{"netntlmv1", "admin::CORP:" + hexOf(48) + ":" + hexOf(48) + ":1122334455667788", 5500, true},
+		{"kerberoast", "$krb5tgs$23$*svc$CORP$http/web*$abcdef$0123", 13100, true},
+		{"asrep", "$krb5asrep$23$user@CORP.LOCAL:abcdef$0123", 18200, true},
+		{"dcc2", "$DCC2$10240#admin#0123456789abcdef0123456789abcdef", 2100, true},
+		{"bcrypt", "$2y$10$abcdefghijklmnopqrstuu5Iq4l3tWyAf6fT6sD5u1R1rI0y9g8Qm", 3200, true},
+		{"sha512crypt", "$6$salt$hash", 1800, true},
+		{"bare md5 or ntlm", "5f4dcc3b5aa765d61d8327deb882cf99", 0, false},
+		{"plain text", "not a hash", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := DetectHashType(tt.line)
+			if id != tt.wantID || ok != tt.wantOK {
+				t.Errorf("DetectHashType() = %d, %v, want %d, %v", id, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
+
+func hexOf(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = "0123456789abcdef"[i%16]
+	}
+	return string(b)
+}
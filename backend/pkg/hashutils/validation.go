@@ -0,0 +1,80 @@
+package hashutils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxHashLineLength is the longest input line accepted, matching hashcat's
+// own line buffer limit.
+const MaxHashLineLength = 65535
+
+// fixedHexLengths maps hash types whose hash is a bare hex digest of a known
+// length. Lines for these types must contain a field of exactly that length.
+var fixedHexLengths = map[int]int{
+	0:     32,  // MD5
+	100:   40,  // SHA1
+	900:   32,  // MD4
+	1000:  32,  // NTLM
+	1300:  56,  // SHA2-224
+	1400:  64,  // SHA2-256
+	1700:  128, // SHA2-512
+	3000:  16,  // LM
+	5100:  16,  // Half MD5
+	6000:  40,  // RIPEMD-160
+	10800: 96,  // SHA2-384
+	17300: 56,  // SHA3-224
+	17400: 64,  // SHA3-256
+	17500: 96,  // SHA3-384
+	17600: 128, // SHA3-512
+}
+
+// ValidateHashLine checks an input line before it is loaded into a hashlist.
+// hashValue is the value produced by ProcessHashIfNeeded. It returns an error
+// describing why the line was rejected, or nil if it looks loadable.
+func ValidateHashLine(line string, hashValue string, hashTypeID int) error {
+	if len(line) > MaxHashLineLength {
+		return fmt.Errorf("line exceeds maximum length of %d characters", MaxHashLineLength)
+	}
+	// Checked byte by byte, lines may carry non-UTF-8 plaintexts or usernames
+	for i := 0; i < len(line); i++ {
+		if c := line[i]; c < ' ' && c != '\t' {
+			return fmt.Errorf("line contains control character 0x%02x", c)
+		}
+	}
+	if strings.TrimSpace(hashValue) == "" {
+		return fmt.Errorf("no hash value could be extracted")
+	}
+
+	expected, ok := fixedHexLengths[hashTypeID]
+	if !ok {
+		return nil
+	}
+	// Accept hash:plain, user:hash and pwdump layouts as long as one field is a valid digest
+	for _, field := range strings.Split(hashValue, ":") {
+		if len(field) == expected && isHexString(field) {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected a %d character hex hash for hash type %d", expected, hashTypeID)
+}
+
+// CompileValidationRegex compiles a hash type's validation pattern. The
+// pattern must match the whole hash, so it is anchored at both ends.
+func CompileValidationRegex(expr string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(`^(?:` + expr + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validation regex: %w", err)
+	}
+	return re, nil
+}
+
+// ValidateHashPattern checks a hash value against a hash type's compiled
+// validation pattern. A nil pattern accepts every value.
+func ValidateHashPattern(hashValue string, pattern *regexp.Regexp) error {
+	if pattern == nil || pattern.MatchString(hashValue) {
+		return nil
+	}
+	return fmt.Errorf("hash does not match the validation pattern of its hash type")
+}
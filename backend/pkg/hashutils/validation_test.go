@@ -0,0 +1,55 @@
+package hashutils
+
+import "testing"
+
+func TestValidateHashLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		hashTypeID int
+		wantErr    bool
+	}{
+		{"md5", "5f4dcc3b5aa765d61d8327deb882cf99", 0, false},
+		{"md5 with plain", "5f4dcc3b5aa765d61d8327deb882cf99:password", 0, false},
+		{"md5 with user", "admin:5f4dcc3b5aa765d61d8327deb882cf99", 0, false},
+		{"md5 truncated", "5f4dcc3b5aa765d61d8327deb882cf9", 0, true},
+		{"md5 not hex", "zf4dcc3b5aa765d61d8327deb882cf99", 0, true},
+		{"ntlm pwdump", "Administrator:500:aad3b435b51404eeaad3b435b51404ee:31d6cfe0d16ae931b73c59d7e0c089c0:::", 1000, false},
+		{"ntlm broken", "Administrator:500:aad3b435b51404ee:31d6cfe0:::", 1000, true},
+		{"unknown type", "$krb5asrep$23$user@domain.com:abcdef", 18200, false},
+		{"control character", "5f4dcc3b5aa765d61d8327deb882cf99\x00", 0, true},
+		{"non-utf8 plain", "5f4dcc3b5aa765d61d8327deb882cf99:p\xe4ssword", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := ProcessHashIfNeeded(tt.line, tt.hashTypeID, tt.hashTypeID == 1000)
+			err := ValidateHashLine(tt.line, value, tt.hashTypeID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHashLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHashPattern(t *testing.T) {
+	pattern, err := CompileValidationRegex(`\$custom\$[0-9a-f]{8}`)
+	if err != nil {
+		t.Fatalf("CompileValidationRegex() error = %v", err)
+	}
+
+	if err := ValidateHashPattern("$custom$deadbeef", pattern); err != nil {
+		t.Errorf("ValidateHashPattern() unexpected error = %v", err)
+	}
+	// The pattern is anchored, a partial match is rejected
+	if err := ValidateHashPattern("$custom$deadbeef00", pattern); err == nil {
+		t.Error("ValidateHashPattern() accepted a hash with trailing characters")
+	}
+	if err := ValidateHashPattern("anything", nil); err != nil {
+		t.Errorf("ValidateHashPattern() with no pattern error = %v", err)
+	}
+
+	if _, err := CompileValidationRegex(`[0-9`); err == nil {
+		t.Error("CompileValidationRegex() accepted an invalid pattern")
+	}
+}
@@ -0,0 +1,249 @@
+package httputil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListQuery holds the common query parameters accepted by collection endpoints.
+//
+// Supported syntax:
+//
+//	?page=2&page_size=50          page-based pagination
+//	?limit=50&offset=100          offset-based pagination (legacy)
+//	?sort=-created_at             sort field, leading '-' for descending
+//	?sort_by=name&sort_order=asc  alternative sort syntax
+//	?filter[status]=running       field filter (plain ?status=running also works)
+//	?fields=id,name,status        sparse field selection on the response items
+type ListQuery struct {
+	Page     int
+	PageSize int
+	Sort     string
+	SortDesc bool
+	Filters  map[string]string
+	Fields   []string
+	// Paginated is true when the caller explicitly asked for a page. Endpoints
+	// that historically returned unbounded lists only page when this is set.
+	Paginated bool
+}
+
+// ParseListQuery parses the common list parameters from the request.
+// pageSize is clamped to [1, maxPageSize]; invalid values fall back to defaultPageSize.
+// The page is clamped so its offset fits in a PostgreSQL integer.
+func ParseListQuery(r *http.Request, defaultPageSize, maxPageSize int) ListQuery {
+	values := r.URL.Query()
+	q := ListQuery{
+		Page:     1,
+		PageSize: defaultPageSize,
+		Filters:  make(map[string]string),
+	}
+
+	if v := values.Get("page_size"); v != "" {
+		q.Paginated = true
+		if size, err := strconv.Atoi(v); err == nil && size > 0 {
+			q.PageSize = size
+		}
+	} else if v := values.Get("limit"); v != "" {
+		q.Paginated = true
+		if size, err := strconv.Atoi(v); err == nil && size > 0 {
+			q.PageSize = size
+		}
+	}
+	if q.PageSize > maxPageSize {
+		q.PageSize = maxPageSize
+	}
+
+	if v := values.Get("page"); v != "" {
+		q.Paginated = true
+		if page, err := strconv.Atoi(v); err == nil && page > 0 {
+			q.Page = page
+		}
+	} else if v := values.Get("offset"); v != "" {
+		q.Paginated = true
+		if offset, err := strconv.Atoi(v); err == nil && offset > 0 {
+			q.Page = offset/q.PageSize + 1
+		}
+	}
+	if maxPage := math.MaxInt32/q.PageSize + 1; q.Page > maxPage {
+		q.Page = maxPage
+	}
+
+	if sort := values.Get("sort"); sort != "" {
+		q.Sort = strings.TrimPrefix(sort, "-")
+		q.SortDesc = strings.HasPrefix(sort, "-")
+	} else if sortBy := values.Get("sort_by"); sortBy != "" {
+		q.Sort = sortBy
+		q.SortDesc = strings.EqualFold(values.Get("sort_order"), "desc")
+	}
+
+	for key, vals := range values {
+		if len(vals) == 0 {
+			continue
+		}
+		if strings.HasPrefix(key, "filter[") && strings.HasSuffix(key, "]") {
+			q.Filters[key[len("filter["):len(key)-1]] = vals[0]
+		}
+	}
+
+	if fields := values.Get("fields"); fields != "" {
+		for _, f := range strings.Split(fields, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				q.Fields = append(q.Fields, f)
+			}
+		}
+	}
+
+	return q
+}
+
+// Limit returns the number of rows to fetch.
+func (q ListQuery) Limit() int {
+	return q.PageSize
+}
+
+// Offset returns the number of rows to skip.
+func (q ListQuery) Offset() int {
+	if q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.PageSize
+}
+
+// Filter returns the value of a filter, accepting both filter[key] and plain key forms.
+func (q ListQuery) Filter(r *http.Request, key string) string {
+	if v, ok := q.Filters[key]; ok {
+		return v
+	}
+	return r.URL.Query().Get(key)
+}
+
+// OrderBy maps the requested sort field onto a whitelisted SQL column and
+// returns an ORDER BY expression. If no sort was requested or the field is
+// not in the whitelist, it returns an empty string so the caller keeps its
+// default ordering.
+func (q ListQuery) OrderBy(columns map[string]string) string {
+	if q.Sort == "" {
+		return ""
+	}
+	column, ok := columns[q.Sort]
+	if !ok {
+		return ""
+	}
+	direction := "ASC"
+	if q.SortDesc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s NULLS LAST", column, direction)
+}
+
+// ParseSince parses a time filter relative to now, given either as an age
+// ("7d", "12h", "90m") or as an RFC3339 time. Ages keep saved queries such as
+// "failed this week" current.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	var age time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid age %q", value)
+		}
+		age = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q, use an age such as 7d or 12h, or an RFC3339 time", value)
+		}
+		age = d
+	}
+	if age <= 0 {
+		return time.Time{}, fmt.Errorf("age %q must be positive", value)
+	}
+	return now.Add(-age), nil
+}
+
+// Pagination is the pagination metadata returned alongside a page of results.
+type Pagination struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// NewPagination builds pagination metadata for the query and total row count.
+func NewPagination(q ListQuery, total int) Pagination {
+	totalPages := 0
+	if q.PageSize > 0 {
+		totalPages = (total + q.PageSize - 1) / q.PageSize
+	}
+	return Pagination{
+		Page:       q.Page,
+		PageSize:   q.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
+// PageBounds returns the slice bounds of the current page for an in-memory
+// list of the given length, both within [0, length].
+func (q ListQuery) PageBounds(length int) (int, int) {
+	start := q.Offset()
+	if start < 0 || start > length {
+		start = length
+	}
+	end := length
+	if q.PageSize > 0 && q.PageSize < length-start {
+		end = start + q.PageSize
+	}
+	return start, end
+}
+
+// SelectFields reduces each item in data to the requested JSON fields.
+// data may be a single object or a slice of objects; when fields is empty
+// the data is returned unchanged.
+func SelectFields(data interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data for field selection: %w", err)
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		keep[f] = true
+	}
+
+	pick := func(item map[string]json.RawMessage) map[string]json.RawMessage {
+		out := make(map[string]json.RawMessage, len(keep))
+		for k, v := range item {
+			if keep[k] {
+				out[k] = v
+			}
+		}
+		return out
+	}
+
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &items); err == nil {
+		result := make([]map[string]json.RawMessage, 0, len(items))
+		for _, item := range items {
+			result = append(result, pick(item))
+		}
+		return result, nil
+	}
+
+	var item map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return nil, fmt.Errorf("field selection requires an object or list of objects: %w", err)
+	}
+	return pick(item), nil
+}
@@ -0,0 +1,219 @@
+package httputil
+
+import (
+	"encoding/json"
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseListQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		wantPage      int
+		wantPageSize  int
+		wantSort      string
+		wantDesc      bool
+		wantPaginated bool
+	}{
+		{
+			name:         "defaults",
+			url:          "/items",
+			wantPage:     1,
+			wantPageSize: 25,
+		},
+		{
+			name:          "page and page_size",
+			url:           "/items?page=3&page_size=10",
+			wantPage:      3,
+			wantPageSize:  10,
+			wantPaginated: true,
+		},
+		{
+			name:          "page_size clamped to max",
+			url:           "/items?page_size=5000",
+			wantPage:      1,
+			wantPageSize:  100,
+			wantPaginated: true,
+		},
+		{
+			name:          "limit and offset converted to page",
+			url:           "/items?limit=20&offset=40",
+			wantPage:      3,
+			wantPageSize:  20,
+			wantPaginated: true,
+		},
+		{
+			name:         "descending sort prefix",
+			url:          "/items?sort=-created_at",
+			wantPage:     1,
+			wantPageSize: 25,
+			wantSort:     "created_at",
+			wantDesc:     true,
+		},
+		{
+			name:         "sort_by and sort_order",
+			url:          "/items?sort_by=name&sort_order=DESC",
+			wantPage:     1,
+			wantPageSize: 25,
+			wantSort:     "name",
+			wantDesc:     true,
+		},
+		{
+			name:          "invalid values fall back to defaults",
+			url:           "/items?page=-1&page_size=abc",
+			wantPage:      1,
+			wantPageSize:  25,
+			wantPaginated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ParseListQuery(httptest.NewRequest("GET", tt.url, nil), 25, 100)
+			if q.Page != tt.wantPage {
+				t.Errorf("Page = %d, want %d", q.Page, tt.wantPage)
+			}
+			if q.PageSize != tt.wantPageSize {
+				t.Errorf("PageSize = %d, want %d", q.PageSize, tt.wantPageSize)
+			}
+			if q.Sort != tt.wantSort || q.SortDesc != tt.wantDesc {
+				t.Errorf("Sort = %q desc=%v, want %q desc=%v", q.Sort, q.SortDesc, tt.wantSort, tt.wantDesc)
+			}
+			if q.Paginated != tt.wantPaginated {
+				t.Errorf("Paginated = %v, want %v", q.Paginated, tt.wantPaginated)
+			}
+		})
+	}
+}
+
+func TestListQueryFilterAndFields(t *testing.T) {
+	r := httptest.NewRequest("GET", "/items?filter[status]=running&name=foo&fields=id,%20name,,status", nil)
+	q := ParseListQuery(r, 25, 100)
+
+	if got := q.Filter(r, "status"); got != "running" {
+		t.Errorf("Filter(status) = %q, want running", got)
+	}
+	if got := q.Filter(r, "name"); got != "foo" {
+		t.Errorf("Filter(name) = %q, want foo", got)
+	}
+	if len(q.Fields) != 3 || q.Fields[0] != "id" || q.Fields[1] != "name" || q.Fields[2] != "status" {
+		t.Errorf("Fields = %v, want [id name status]", q.Fields)
+	}
+}
+
+func TestListQueryOrderBy(t *testing.T) {
+	columns := map[string]string{"name": "t.name"}
+
+	q := ListQuery{Sort: "name", SortDesc: true}
+	if got := q.OrderBy(columns); got != "t.name DESC NULLS LAST" {
+		t.Errorf("OrderBy = %q", got)
+	}
+
+	q = ListQuery{Sort: "name; DROP TABLE users"}
+	if got := q.OrderBy(columns); got != "" {
+		t.Errorf("OrderBy for unknown field = %q, want empty", got)
+	}
+}
+
+func TestPageBoundsAndPagination(t *testing.T) {
+	q := ListQuery{Page: 3, PageSize: 10}
+	start, end := q.PageBounds(25)
+	if start != 20 || end != 25 {
+		t.Errorf("PageBounds = (%d, %d), want (20, 25)", start, end)
+	}
+
+	start, end = ListQuery{Page: 5, PageSize: 10}.PageBounds(25)
+	if start != 25 || end != 25 {
+		t.Errorf("PageBounds past end = (%d, %d), want (25, 25)", start, end)
+	}
+
+	p := NewPagination(q, 25)
+	if p.TotalPages != 3 || p.Total != 25 {
+		t.Errorf("NewPagination = %+v", p)
+	}
+}
+
+func TestListQueryPageOverflow(t *testing.T) {
+	r := httptest.NewRequest("GET", "/items?page=9223372036854775807&page_size=100", nil)
+	q := ParseListQuery(r, 25, 100)
+	if offset := q.Offset(); offset < 0 || offset > math.MaxInt32 {
+		t.Errorf("Offset = %d, want within [0, %d]", offset, math.MaxInt32)
+	}
+
+	r = httptest.NewRequest("GET", "/items?offset=9223372036854775807", nil)
+	q = ParseListQuery(r, 25, 100)
+	if offset := q.Offset(); offset < 0 || offset > math.MaxInt32 {
+		t.Errorf("Offset from offset = %d, want within [0, %d]", offset, math.MaxInt32)
+	}
+
+	start, end := ListQuery{Page: math.MaxInt, PageSize: 100}.PageBounds(25)
+	if start != 25 || end != 25 {
+		t.Errorf("PageBounds with overflowing page = (%d, %d), want (25, 25)", start, end)
+	}
+	start, end = ListQuery{Page: 1, PageSize: math.MaxInt}.PageBounds(25)
+	if start != 0 || end != 25 {
+		t.Errorf("PageBounds with huge page size = (%d, %d), want (0, 25)", start, end)
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	type item struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		Secret string `json:"secret"`
+	}
+
+	out, err := SelectFields([]item{{ID: 1, Name: "a", Secret: "x"}}, []string{"id", "name"})
+	if err != nil {
+		t.Fatalf("SelectFields returned error: %v", err)
+	}
+	list, ok := out.([]map[string]json.RawMessage)
+	if !ok || len(list) != 1 {
+		t.Fatalf("unexpected result type %T", out)
+	}
+	if _, ok := list[0]["secret"]; ok {
+		t.Errorf("secret field should have been removed")
+	}
+	if string(list[0]["name"]) != `"a"` {
+		t.Errorf("name = %s, want \"a\"", list[0]["name"])
+	}
+
+	single, err := SelectFields(item{ID: 2}, []string{"id"})
+	if err != nil {
+		t.Fatalf("SelectFields on object returned error: %v", err)
+	}
+	if m, ok := single.(map[string]json.RawMessage); !ok || len(m) != 1 {
+		t.Errorf("unexpected single result %v", single)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"7d", now.Add(-7 * 24 * time.Hour)},
+		{"12h", now.Add(-12 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"2024-06-01T00:00:00Z", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.value, now)
+		if err != nil {
+			t.Fatalf("ParseSince(%q) failed: %v", tt.value, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "week", "-7d", "0h", "7x"} {
+		if _, err := ParseSince(value, now); err == nil {
+			t.Errorf("ParseSince(%q) should fail", value)
+		}
+	}
+}
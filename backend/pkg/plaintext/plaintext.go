@@ -0,0 +1,102 @@
+// Package plaintext converts cracked passwords between the bytes hashcat
+// cracked, the $HEX[...] notation hashcat prints them in and a readable form
+// that fits a PostgreSQL TEXT column. Passwords in a legacy code page, with
+// control characters or with NUL bytes are not valid text, so the readable form
+// is only a best effort and the raw bytes are kept alongside when it loses
+// information.
+package plaintext
+
+import (
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	hexPrefix = "$HEX["
+	hexSuffix = "]"
+)
+
+// Decode returns the raw bytes of a cracked password. A non-empty hexPlain, the
+// hex encoding of the password sent by agents, wins; otherwise a plain in
+// $HEX[...] notation is decoded and anything else is taken as is.
+func Decode(plain, hexPlain string) []byte {
+	if hexPlain != "" {
+		if raw, err := hex.DecodeString(hexPlain); err == nil {
+			return raw
+		}
+	}
+	if raw, ok := decodeHexNotation(plain); ok {
+		return raw
+	}
+	return []byte(plain)
+}
+
+// decodeHexNotation decodes a $HEX[...] plain
+func decodeHexNotation(plain string) ([]byte, bool) {
+	if !strings.HasPrefix(plain, hexPrefix) || !strings.HasSuffix(plain, hexSuffix) {
+		return nil, false
+	}
+	raw, err := hex.DecodeString(plain[len(hexPrefix) : len(plain)-len(hexSuffix)])
+	if err != nil {
+		return nil, false
+	}
+	return raw, true
+}
+
+// Display returns a readable form of raw. Valid UTF-8 is kept, otherwise each
+// byte is read as ISO-8859-1, which is how most legacy passwords were typed.
+// NUL bytes, which PostgreSQL text cannot hold, become U+FFFD.
+func Display(raw []byte) string {
+	var b strings.Builder
+	if utf8.Valid(raw) {
+		for _, r := range string(raw) {
+			if r == 0 {
+				r = utf8.RuneError
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	}
+	for _, c := range raw {
+		r := rune(c)
+		if r == 0 {
+			r = utf8.RuneError
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ForStorage splits raw into the readable password and the raw bytes to store
+// next to it, which are nil when the readable password holds exactly raw.
+func ForStorage(raw []byte) (string, []byte) {
+	password := Display(raw)
+	if password == string(raw) {
+		return password, nil
+	}
+	return password, raw
+}
+
+// Hashcat returns raw the way hashcat writes it: as is when it is printable
+// UTF-8, in $HEX[...] notation otherwise. Lines in this form can be fed back
+// to hashcat as a wordlist without losing bytes.
+func Hashcat(raw []byte) string {
+	if needsHex(raw) {
+		return hexPrefix + hex.EncodeToString(raw) + hexSuffix
+	}
+	return string(raw)
+}
+
+// needsHex reports whether raw cannot be written as a plain line
+func needsHex(raw []byte) bool {
+	if !utf8.Valid(raw) || strings.HasPrefix(string(raw), hexPrefix) {
+		return true
+	}
+	for _, c := range raw {
+		if c < 0x20 || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
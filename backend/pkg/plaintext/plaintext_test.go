@@ -0,0 +1,53 @@
+package plaintext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		plain    string
+		hexPlain string
+		want     []byte
+	}{
+		{"plain", "Summer2024", "", []byte("Summer2024")},
+		{"hex notation", "$HEX[70e47373776f7264]", "", []byte("p\xe4ssword")},
+		{"upper case hex notation", "$HEX[4142]", "", []byte("AB")},
+		{"hex plain wins", "p?ssword", "70e47373776f7264", []byte("p\xe4ssword")},
+		{"invalid hex plain falls back", "pass", "zz", []byte("pass")},
+		{"broken hex notation kept", "$HEX[zz]", "", []byte("$HEX[zz]")},
+		{"utf-8 kept", "пароль", "", []byte("пароль")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Decode(tt.plain, tt.hexPlain))
+		})
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	assert.Equal(t, "пароль", Display([]byte("пароль")))
+	assert.Equal(t, "pässword", Display([]byte("p\xe4ssword")), "ISO-8859-1 fallback")
+	assert.Equal(t, "a�b", Display([]byte("a\x00b")))
+}
+
+func TestForStorage(t *testing.T) {
+	password, raw := ForStorage([]byte("Summer2024"))
+	assert.Equal(t, "Summer2024", password)
+	assert.Nil(t, raw)
+
+	password, raw = ForStorage([]byte("p\xe4ssword"))
+	assert.Equal(t, "pässword", password)
+	assert.Equal(t, []byte("p\xe4ssword"), raw)
+}
+
+func TestHashcat(t *testing.T) {
+	assert.Equal(t, "Summer2024", Hashcat([]byte("Summer2024")))
+	assert.Equal(t, "pässword", Hashcat([]byte("pässword")))
+	assert.Equal(t, "$HEX[70e47373776f7264]", Hashcat([]byte("p\xe4ssword")))
+	assert.Equal(t, "$HEX[6109]", Hashcat([]byte("a\t")))
+	assert.Equal(t, "$HEX[244845585b615d]", Hashcat([]byte("$HEX[a]")), "literal $HEX[] is escaped")
+}
@@ -0,0 +1,95 @@
+package textnorm
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+)
+
+// Hasher computes the canonical hash of a text file: the MD5 of its content
+// without a UTF-8 byte order mark, with LF line endings and a final newline.
+// Files that only differ in these, such as the same wordlist saved on Windows
+// and on Linux, have the same canonical hash. For rule files, blank and
+// comment lines and the whitespace around rules are ignored as well, since
+// hashcat ignores them.
+type Hasher struct {
+	md5     hash.Hash
+	rules   bool
+	started bool
+	line    []byte
+}
+
+// NewHasher returns a Hasher of wordlists, or of rule files if rules is set
+func NewHasher(rules bool) *Hasher {
+	return &Hasher{md5: md5.New(), rules: rules}
+}
+
+// Write implements io.Writer
+func (h *Hasher) Write(p []byte) (int, error) {
+	n := len(p)
+	if !h.started {
+		// Only strip a BOM at the very start of the file
+		h.line = append(h.line, p...)
+		if len(h.line) < len(bomUTF8) && bytes.HasPrefix(bomUTF8, h.line) {
+			return n, nil
+		}
+		h.started = true
+		p = bytes.TrimPrefix(h.line, bomUTF8)
+		h.line = nil
+	}
+
+	for {
+		idx := bytes.IndexByte(p, '\n')
+		if idx == -1 {
+			h.line = append(h.line, p...)
+			return n, nil
+		}
+		h.line = append(h.line, p[:idx]...)
+		h.writeLine()
+		p = p[idx+1:]
+	}
+}
+
+// writeLine adds the pending line to the hash
+func (h *Hasher) writeLine() {
+	line := bytes.TrimSuffix(h.line, []byte("\r"))
+	if h.rules {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			h.line = h.line[:0]
+			return
+		}
+	}
+	h.md5.Write(line)
+	h.md5.Write([]byte("\n"))
+	h.line = h.line[:0]
+}
+
+// Sum returns the canonical hash of everything written, as hex
+func (h *Hasher) Sum() string {
+	if len(h.line) > 0 {
+		if !h.started {
+			h.line = bytes.TrimPrefix(h.line, bomUTF8)
+		}
+		h.writeLine()
+	}
+	return hex.EncodeToString(h.md5.Sum(nil))
+}
+
+// HashFile returns the canonical hash of a file
+func HashFile(path string, rules bool) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := NewHasher(rules)
+	if _, err := io.CopyBuffer(hasher, file, make([]byte, 32*1024)); err != nil {
+		return "", err
+	}
+	return hasher.Sum(), nil
+}
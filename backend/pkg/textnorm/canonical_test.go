@@ -0,0 +1,47 @@
+package textnorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// canonicalHash writes input to a Hasher in chunks of the given size
+func canonicalHash(input string, rules bool, chunk int) string {
+	hasher := NewHasher(rules)
+	for len(input) > chunk {
+		hasher.Write([]byte(input[:chunk]))
+		input = input[chunk:]
+	}
+	hasher.Write([]byte(input))
+	return hasher.Sum()
+}
+
+func TestHasherIgnoresLineEndingsAndBOM(t *testing.T) {
+	want := canonicalHash("password\n123456\n", false, 1024)
+
+	for _, input := range []string{
+		"password\r\n123456\r\n",
+		"password\n123456",
+		"\xEF\xBB\xBFpassword\r\n123456",
+	} {
+		for _, chunk := range []int{1, 2, 5, 1024} {
+			assert.Equal(t, want, canonicalHash(input, false, chunk), "%q in chunks of %d", input, chunk)
+		}
+	}
+
+	// Whitespace and blank lines are candidates in a wordlist
+	assert.NotEqual(t, want, canonicalHash("password \n123456\n", false, 1024))
+	assert.NotEqual(t, want, canonicalHash("password\n\n123456\n", false, 1024))
+	assert.NotEqual(t, want, canonicalHash("123456\npassword\n", false, 1024))
+}
+
+func TestHasherRulesIgnoreCommentsAndBlankLines(t *testing.T) {
+	want := canonicalHash(":\nc\n$1\n", true, 1024)
+
+	input := "## best64 copy\r\n:\r\n\r\n  c  \r\n# appends\r\n$1"
+	for _, chunk := range []int{1, 3, 1024} {
+		assert.Equal(t, want, canonicalHash(input, true, chunk))
+	}
+	assert.NotEqual(t, want, canonicalHash(":\nc\n$2\n", true, 1024))
+}
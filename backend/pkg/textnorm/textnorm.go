@@ -0,0 +1,276 @@
+// Package textnorm normalizes uploaded text files to what hashcat parses best:
+// UTF-8 without a byte order mark and with LF line endings. Files exported on
+// Windows are often UTF-16 or in a legacy code page and end their lines in
+// CRLF, which hashcat takes as part of the hash or the candidate.
+package textnorm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Source encodings a Reader can detect
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+)
+
+// MaxReportedLines caps the line numbers a Report lists
+const MaxReportedLines = 100
+
+// detectBytes is how much of the start of a file is inspected to detect
+// UTF-16 without a byte order mark
+const detectBytes = 4096
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// Report describes what normalizing a file changed
+type Report struct {
+	SourceEncoding string `json:"source_encoding"`
+	BOMStripped    bool   `json:"bom_stripped"`
+	Lines          int64  `json:"lines"`
+	ChangedLines   int64  `json:"changed_lines"`
+	CRLFLines      int64  `json:"crlf_lines"`
+	// Lines that were not valid UTF-8 and were converted from latin-1
+	Latin1Lines int64 `json:"latin1_lines"`
+	// Numbers of the first changed lines, starting at 1
+	ChangedLineNumbers []int64 `json:"changed_line_numbers,omitempty"`
+}
+
+// Reader reads a text file converted to UTF-8 with LF line endings and no
+// byte order mark. UTF-16 is detected by its byte order mark or by its NUL
+// bytes, and lines that are not valid UTF-8 are taken as latin-1.
+type Reader struct {
+	src     *bufio.Reader
+	pending []byte
+	line    []byte
+	started bool
+	err     error
+	report  Report
+}
+
+// NewReader returns a Reader normalizing r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{src: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// Report returns what has been normalized so far. It is complete once Read
+// returned io.EOF.
+func (n *Reader) Report() Report {
+	return n.report
+}
+
+// Read implements io.Reader
+func (n *Reader) Read(p []byte) (int, error) {
+	for len(n.pending) == 0 {
+		if n.err != nil {
+			return 0, n.err
+		}
+		n.err = n.nextLine()
+	}
+	count := copy(p, n.pending)
+	n.pending = n.pending[count:]
+	return count, nil
+}
+
+// nextLine normalizes the next line of the source into pending
+func (n *Reader) nextLine() error {
+	if !n.started {
+		n.started = true
+		if err := n.detect(); err != nil {
+			return err
+		}
+	}
+
+	n.line = n.line[:0]
+	var err error
+	for {
+		var chunk []byte
+		chunk, err = n.src.ReadSlice('\n')
+		n.line = append(n.line, chunk...)
+		if err != bufio.ErrBufferFull {
+			break
+		}
+	}
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if len(n.line) == 0 {
+		return io.EOF
+	}
+
+	n.report.Lines++
+	changed := n.report.SourceEncoding != EncodingUTF8
+
+	content := n.line
+	hasLF := bytes.HasSuffix(content, []byte("\n"))
+	if hasLF {
+		content = content[:len(content)-1]
+		if bytes.HasSuffix(content, []byte("\r")) {
+			content = content[:len(content)-1]
+			n.report.CRLFLines++
+			changed = true
+		}
+	}
+
+	out := make([]byte, 0, len(content)+1)
+	if utf8.Valid(content) {
+		out = append(out, content...)
+	} else {
+		for _, b := range content {
+			out = utf8.AppendRune(out, rune(b))
+		}
+		n.report.Latin1Lines++
+		changed = true
+	}
+	if hasLF {
+		out = append(out, '\n')
+	}
+	n.pending = out
+
+	if changed {
+		n.report.ChangedLines++
+		if len(n.report.ChangedLineNumbers) < MaxReportedLines {
+			n.report.ChangedLineNumbers = append(n.report.ChangedLineNumbers, n.report.Lines)
+		}
+	}
+	return err
+}
+
+// detect skips a byte order mark and switches to decoding UTF-16 when the
+// source is UTF-16
+func (n *Reader) detect() error {
+	n.report.SourceEncoding = EncodingUTF8
+	head, err := n.src.Peek(detectBytes)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return err
+	}
+
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		n.src.Discard(len(bomUTF8))
+		n.report.BOMStripped = true
+		return nil
+	case bytes.HasPrefix(head, bomUTF16LE):
+		n.src.Discard(len(bomUTF16LE))
+		n.report.BOMStripped = true
+		order = binary.LittleEndian
+	case bytes.HasPrefix(head, bomUTF16BE):
+		n.src.Discard(len(bomUTF16BE))
+		n.report.BOMStripped = true
+		order = binary.BigEndian
+	default:
+		order = guessUTF16(head)
+		if order == nil {
+			return nil
+		}
+	}
+
+	if order == binary.LittleEndian {
+		n.report.SourceEncoding = EncodingUTF16LE
+	} else {
+		n.report.SourceEncoding = EncodingUTF16BE
+	}
+	n.src = bufio.NewReaderSize(&utf16Reader{src: n.src, order: order}, 64*1024)
+	return nil
+}
+
+// guessUTF16 returns the byte order of UTF-16 text without a byte order mark,
+// or nil for anything else. Text that is mostly ASCII has a NUL byte in
+// every other position when it is UTF-16, which text in any 8-bit encoding
+// has not.
+func guessUTF16(head []byte) binary.ByteOrder {
+	pairs := len(head) / 2
+	if pairs < 2 {
+		return nil
+	}
+	var evenZeros, oddZeros int
+	for i := 0; i < pairs*2; i += 2 {
+		if head[i] == 0 {
+			evenZeros++
+		}
+		if head[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	switch {
+	case oddZeros*2 > pairs && evenZeros*4 < oddZeros:
+		return binary.LittleEndian
+	case evenZeros*2 > pairs && oddZeros*4 < evenZeros:
+		return binary.BigEndian
+	}
+	return nil
+}
+
+// utf16Reader decodes UTF-16 to UTF-8
+type utf16Reader struct {
+	src   *bufio.Reader
+	order binary.ByteOrder
+	buf   []byte
+	err   error
+}
+
+// Read implements io.Reader
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for len(u.buf) < len(p) && u.err == nil {
+		var r rune
+		r, u.err = u.readRune()
+		if u.err == nil {
+			u.buf = utf8.AppendRune(u.buf, r)
+		}
+	}
+	if len(u.buf) == 0 {
+		return 0, u.err
+	}
+	count := copy(p, u.buf)
+	u.buf = u.buf[:copy(u.buf, u.buf[count:])]
+	return count, nil
+}
+
+// readRune decodes the next code point. Unpaired surrogates and a trailing odd
+// byte become U+FFFD.
+func (u *utf16Reader) readRune() (rune, error) {
+	unit, err := u.readUnit()
+	if err != nil {
+		return 0, err
+	}
+	if !utf16.IsSurrogate(rune(unit)) {
+		return rune(unit), nil
+	}
+
+	next, err := u.src.Peek(2)
+	if len(next) < 2 {
+		if err == io.EOF {
+			return utf8.RuneError, nil
+		}
+		return 0, err
+	}
+	r := utf16.DecodeRune(rune(unit), rune(u.order.Uint16(next)))
+	if r != utf8.RuneError {
+		u.src.Discard(2)
+	}
+	return r, nil
+}
+
+// readUnit reads the next 16-bit code unit
+func (u *utf16Reader) readUnit() (uint16, error) {
+	var pair [2]byte
+	read, err := io.ReadFull(u.src, pair[:])
+	if err == io.ErrUnexpectedEOF && read == 1 {
+		return utf8.RuneError, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return u.order.Uint16(pair[:]), nil
+}
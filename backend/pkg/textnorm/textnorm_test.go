@@ -0,0 +1,98 @@
+package textnorm
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeUTF16 encodes s as UTF-16 in the given byte order, with an optional BOM
+func encodeUTF16(s string, order binary.ByteOrder, bom bool) []byte {
+	units := utf16.Encode([]rune(s))
+	if bom {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+	out := make([]byte, len(units)*2)
+	for i, unit := range units {
+		order.PutUint16(out[i*2:], unit)
+	}
+	return out
+}
+
+// normalize reads all of input through a Reader
+func normalize(t *testing.T, input []byte) (string, Report) {
+	reader := NewReader(strings.NewReader(string(input)))
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(out), reader.Report()
+}
+
+func TestReaderLeavesCleanUTF8Alone(t *testing.T) {
+	out, report := normalize(t, []byte("password\nmotdepasseé\n"))
+
+	assert.Equal(t, "password\nmotdepasseé\n", out)
+	assert.Equal(t, EncodingUTF8, report.SourceEncoding)
+	assert.False(t, report.BOMStripped)
+	assert.Equal(t, int64(2), report.Lines)
+	assert.Zero(t, report.ChangedLines)
+	assert.Empty(t, report.ChangedLineNumbers)
+}
+
+func TestReaderStripsBOMAndCRLF(t *testing.T) {
+	out, report := normalize(t, []byte("\xEF\xBB\xBFone\r\ntwo\nthree\r\n"))
+
+	assert.Equal(t, "one\ntwo\nthree\n", out)
+	assert.True(t, report.BOMStripped)
+	assert.Equal(t, int64(2), report.CRLFLines)
+	assert.Equal(t, int64(2), report.ChangedLines)
+	assert.Equal(t, []int64{1, 3}, report.ChangedLineNumbers)
+}
+
+func TestReaderConvertsLatin1Lines(t *testing.T) {
+	out, report := normalize(t, []byte("caf\xe9\nok\nna\xefve"))
+
+	assert.Equal(t, "café\nok\nnaïve", out)
+	assert.Equal(t, int64(2), report.Latin1Lines)
+	assert.Equal(t, []int64{1, 3}, report.ChangedLineNumbers)
+}
+
+func TestReaderDecodesUTF16(t *testing.T) {
+	text := "admin:hash\r\nuser:été\U0001F600\r\n"
+
+	tests := []struct {
+		name     string
+		input    []byte
+		encoding string
+		bom      bool
+	}{
+		{"little endian with BOM", encodeUTF16(text, binary.LittleEndian, true), EncodingUTF16LE, true},
+		{"big endian with BOM", encodeUTF16(text, binary.BigEndian, true), EncodingUTF16BE, true},
+		{"little endian without BOM", encodeUTF16(text, binary.LittleEndian, false), EncodingUTF16LE, false},
+		{"big endian without BOM", encodeUTF16(text, binary.BigEndian, false), EncodingUTF16BE, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, report := normalize(t, tt.input)
+
+			assert.Equal(t, "admin:hash\nuser:été\U0001F600\n", out)
+			assert.Equal(t, tt.encoding, report.SourceEncoding)
+			assert.Equal(t, tt.bom, report.BOMStripped)
+			assert.Equal(t, int64(2), report.Lines)
+			assert.Equal(t, int64(2), report.ChangedLines)
+			assert.Zero(t, report.Latin1Lines)
+		})
+	}
+}
+
+func TestReaderCapsReportedLineNumbers(t *testing.T) {
+	_, report := normalize(t, []byte(strings.Repeat("x\r\n", MaxReportedLines+50)))
+
+	assert.Equal(t, int64(MaxReportedLines+50), report.ChangedLines)
+	assert.Len(t, report.ChangedLineNumbers, MaxReportedLines)
+}
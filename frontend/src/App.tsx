@@ -59,6 +59,7 @@ const LoginPage = lazy(() => import('./pages/Login'));
 const DashboardPage = lazy(() => import('./pages/Dashboard'));
 const JobsPage = lazy(() => import('./pages/Jobs'));
 const JobDetails = lazy(() => import('./pages/Jobs/JobDetails'));
+const CampaignDetails = lazy(() => import('./pages/Jobs/CampaignDetails'));
 const AgentManagementPage = lazy(() => import('./pages/AgentManagement'));
 const WordlistsManagementPage = lazy(() => import('./pages/WordlistsManagement'));
 const RulesManagementPage = lazy(() => import('./pages/RulesManagement'));
@@ -81,6 +82,8 @@ const PresetJobListPage = lazy(() => import('./pages/admin/PresetJobList'));
 const PresetJobFormPage = lazy(() => import('./pages/admin/PresetJobForm'));
 const JobWorkflowListPage = lazy(() => import('./pages/admin/JobWorkflowList'));
 const JobWorkflowFormPage = lazy(() => import('./pages/admin/JobWorkflowForm'));
+const WordlistCollectionListPage = lazy(() => import('./pages/admin/WordlistCollectionList'));
+const SystemEventLogPage = lazy(() => import('./pages/admin/SystemEventLog'));
 const AdminAuthSettingsPage = lazy(() => import('./pages/admin/AuthSettings'));
 const AdminUserListPage = lazy(() => import('./pages/admin/UserList'));
 const AdminUserDetailPage = lazy(() => import('./pages/admin/UserDetail'));
@@ -135,6 +138,7 @@ const App: React.FC = () => {
                   <Route element={<RequireAuth><Layout /></RequireAuth>}>
                     <Route path="/dashboard" element={<DashboardPage />} />
                     <Route path="/jobs" element={<JobsPage />} />
+                    <Route path="/jobs/campaigns/:id" element={<CampaignDetails />} />
                     <Route path="/jobs/:id" element={<JobDetails />} />
                     <Route path="/agents" element={<AgentManagementPage />} />
                     <Route path="/agents/:id" element={<AgentDetailsPage />} />
@@ -160,6 +164,8 @@ const App: React.FC = () => {
                       <Route path="job-workflows" element={<JobWorkflowListPage />} />
                       <Route path="job-workflows/new" element={<JobWorkflowFormPage />} />
                       <Route path="job-workflows/:jobWorkflowId/edit" element={<JobWorkflowFormPage />} />
+                      <Route path="wordlist-collections" element={<WordlistCollectionListPage />} />
+                      <Route path="system-events" element={<SystemEventLogPage />} />
                       <Route path="auth-settings" element={<AdminAuthSettingsPage />} />
                       <Route path="users" element={<AdminUserListPage />} />
                       <Route path="users/:id" element={<AdminUserDetailPage />} />
@@ -5,7 +5,9 @@ import {
   Settings as SettingsIcon,
   PlaylistAddCheck as PlaylistAddCheckIcon,
   AccountTree as AccountTreeIcon,
-  SupervisorAccount as SupervisorAccountIcon
+  LibraryBooks as LibraryBooksIcon,
+  SupervisorAccount as SupervisorAccountIcon,
+  EventNote as EventNoteIcon
 } from '@mui/icons-material';
 
 const AdminMenu: React.FC = () => {
@@ -93,6 +95,46 @@ const AdminMenu: React.FC = () => {
         </ListItemIcon>
         <ListItemText primary="Job Workflows" />
       </ListItemButton>
+
+      <ListItemButton
+        onClick={() => navigate('/admin/wordlist-collections')}
+        selected={location.pathname.startsWith('/admin/wordlist-collections')}
+        sx={{
+          minHeight: 48,
+          px: 2.5,
+        }}
+      >
+        <ListItemIcon
+          sx={{
+            minWidth: 0,
+            mr: 3,
+            justifyContent: 'center',
+          }}
+        >
+          <LibraryBooksIcon />
+        </ListItemIcon>
+        <ListItemText primary="Wordlist Collections" />
+      </ListItemButton>
+
+      <ListItemButton
+        onClick={() => navigate('/admin/system-events')}
+        selected={location.pathname.startsWith('/admin/system-events')}
+        sx={{
+          minHeight: 48,
+          px: 2.5,
+        }}
+      >
+        <ListItemIcon
+          sx={{
+            minWidth: 0,
+            mr: 3,
+            justifyContent: 'center',
+          }}
+        >
+          <EventNoteIcon />
+        </ListItemIcon>
+        <ListItemText primary="System Events" />
+      </ListItemButton>
     </List>
   );
 };
@@ -78,6 +78,7 @@ import { useAuth } from '../contexts/AuthContext';
 import AdminMenu from './AdminMenu';
 import UserMenu from './common/UserMenu';
 import Footer from './Footer';
+import MaintenanceBanner from './MaintenanceBanner';
 
 interface MenuItem {
   text: string;
@@ -290,6 +291,7 @@ const Layout: React.FC<LayoutProps> = () => {
         }}
       >
         <Toolbar /> {/* Spacer for AppBar */}
+        <MaintenanceBanner />
         <Outlet />
       </Box>
       <Footer drawerOpen={open} />
@@ -0,0 +1,48 @@
+import React, { useEffect, useState } from 'react';
+import { Alert, AlertTitle } from '@mui/material';
+import { getMaintenanceStatus, MaintenanceWindow } from '../services/maintenance';
+
+// How often the banner checks whether maintenance mode changed
+const POLL_INTERVAL_MS = 60000;
+
+/**
+ * MaintenanceBanner tells every user while the system is in maintenance mode:
+ * no new tasks are dispatched and no jobs can be created until it ends.
+ */
+const MaintenanceBanner: React.FC = () => {
+  const [maintenanceWindow, setMaintenanceWindow] = useState<MaintenanceWindow | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+    const check = async () => {
+      try {
+        const status = await getMaintenanceStatus();
+        if (!cancelled) {
+          setMaintenanceWindow(status.active && status.window ? status.window : null);
+        }
+      } catch (err) {
+        console.error('Failed to check maintenance status:', err);
+      }
+    };
+
+    check();
+    const timer = setInterval(check, POLL_INTERVAL_MS);
+    return () => {
+      cancelled = true;
+      clearInterval(timer);
+    };
+  }, []);
+
+  if (!maintenanceWindow) {
+    return null;
+  }
+
+  return (
+    <Alert severity="warning" sx={{ mb: 2 }}>
+      <AlertTitle>Maintenance in progress</AlertTitle>
+      {maintenanceWindow.message} New jobs cannot be created and no new work is dispatched until maintenance ends.
+    </Alert>
+  );
+};
+
+export default MaintenanceBanner;
@@ -23,17 +23,31 @@ import {
   DialogActions,
   FormControlLabel,
   Switch,
+  Alert,
+  TextField,
 } from '@mui/material';
 import {
   Delete as DeleteIcon,
   Refresh as RefreshIcon,
   Add as AddIcon,
   Verified as VerifiedIcon,
+  RocketLaunch as RolloutIcon,
 } from '@mui/icons-material';
 import { format } from 'date-fns';
 import AddBinaryForm from './AddBinaryForm';
 import { useSnackbar } from 'notistack';
-import { BinaryVersion, listBinaries, verifyBinary, deleteBinary, setDefaultBinary } from '../../services/binary';
+import {
+  BinaryVersion,
+  BinaryRollout,
+  listBinaries,
+  verifyBinary,
+  deleteBinary,
+  setDefaultBinary,
+  listBinaryRollouts,
+  startBinaryRollout,
+  promoteBinaryRollout,
+  rollBackBinaryRollout,
+} from '../../services/binary';
 
 const BinaryManagement: React.FC = () => {
   const [binaries, setBinaries] = useState<BinaryVersion[]>([]);
@@ -42,6 +56,15 @@ const BinaryManagement: React.FC = () => {
   const [deleteDialogOpen, setDeleteDialogOpen] = useState(false);
   const [selectedBinary, setSelectedBinary] = useState<BinaryVersion | null>(null);
   const [showActiveOnly, setShowActiveOnly] = useState(true);
+  const [activeRollout, setActiveRollout] = useState<BinaryRollout | null>(null);
+  const [rolloutBinary, setRolloutBinary] = useState<BinaryVersion | null>(null);
+  const [rolloutForm, setRolloutForm] = useState({
+    canary_label: 'canary',
+    observation_hours: 24,
+    min_tasks: 10,
+    max_failure_rate_increase: 5,
+    max_speed_regression: 10,
+  });
   const { enqueueSnackbar } = useSnackbar();
   const theme = useTheme();
 
@@ -59,10 +82,56 @@ const BinaryManagement: React.FC = () => {
     }
   };
 
+  const fetchActiveRollout = async () => {
+    try {
+      const response = await listBinaryRollouts();
+      setActiveRollout((response.data || []).find(r => r.status === 'canary') || null);
+    } catch (error) {
+      console.error('Error fetching binary rollouts:', error);
+    }
+  };
+
   useEffect(() => {
     fetchBinaries();
+    fetchActiveRollout();
   }, []);
 
+  const handleStartRollout = async () => {
+    if (!rolloutBinary) return;
+    try {
+      setIsLoading(true);
+      await startBinaryRollout({ binary_version_id: rolloutBinary.id, ...rolloutForm });
+      enqueueSnackbar('Canary rollout started', { variant: 'success' });
+      setRolloutBinary(null);
+      fetchActiveRollout();
+    } catch (error: any) {
+      console.error('Error starting binary rollout:', error);
+      enqueueSnackbar(error.response?.data?.error || 'Failed to start rollout', { variant: 'error' });
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  const handleDecideRollout = async (promote: boolean) => {
+    if (!activeRollout) return;
+    try {
+      setIsLoading(true);
+      if (promote) {
+        await promoteBinaryRollout(activeRollout.id);
+      } else {
+        await rollBackBinaryRollout(activeRollout.id);
+      }
+      enqueueSnackbar(promote ? 'Binary promoted to the fleet' : 'Rollout rolled back', { variant: 'success' });
+      fetchActiveRollout();
+      fetchBinaries();
+    } catch (error: any) {
+      console.error('Error deciding binary rollout:', error);
+      enqueueSnackbar(error.response?.data?.error || 'Failed to update rollout', { variant: 'error' });
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
   const handleVerify = async (id: number) => {
     try {
       setIsLoading(true);
@@ -214,6 +283,34 @@ const BinaryManagement: React.FC = () => {
         />
       </Box>
 
+      {activeRollout && (
+        <Alert
+          severity="info"
+          sx={{ mb: 2 }}
+          action={
+            <Stack direction="row" spacing={1}>
+              <Button color="inherit" size="small" onClick={() => handleDecideRollout(true)} disabled={isLoading}>
+                Promote
+              </Button>
+              <Button color="inherit" size="small" onClick={() => handleDecideRollout(false)} disabled={isLoading}>
+                Roll back
+              </Button>
+            </Stack>
+          }
+        >
+          Canary rollout of {activeRollout.binary_version_name} in place of {activeRollout.previous_version_name} on{' '}
+          {activeRollout.canary_agents} agent(s) labelled "{activeRollout.canary_label}" since{' '}
+          {format(new Date(activeRollout.started_at), 'yyyy-MM-dd HH:mm')} for {activeRollout.observation_hours} hours.
+          {activeRollout.metrics && (
+            <> {activeRollout.metrics.canary_failures}/{activeRollout.metrics.canary_tasks} canary tasks failed
+              {' '}vs {activeRollout.metrics.baseline_failures}/{activeRollout.metrics.baseline_tasks} on the previous version
+              {activeRollout.metrics.speed_profiles > 0 &&
+                `, speed ${(activeRollout.metrics.speed_ratio * 100).toFixed(1)}% of the previous version`}.</>
+          )}
+          {activeRollout.decision_reason && <> {activeRollout.decision_reason}.</>}
+        </Alert>
+      )}
+
       <TableContainer component={Paper}>
         <Table>
           <TableHead>
@@ -294,6 +391,18 @@ const BinaryManagement: React.FC = () => {
                             </IconButton>
                           </span>
                         </Tooltip>
+                        <Tooltip title="Start canary rollout">
+                          <span>
+                            <IconButton
+                              onClick={() => setRolloutBinary(binary)}
+                              disabled={isLoading || binary.is_default || binary.verification_status !== 'verified' || !!activeRollout}
+                              color="primary"
+                              size="small"
+                            >
+                              <RolloutIcon />
+                            </IconButton>
+                          </span>
+                        </Tooltip>
                         <Tooltip title="Delete binary">
                           <span>
                             <IconButton
@@ -347,6 +456,61 @@ const BinaryManagement: React.FC = () => {
         </DialogActions>
       </Dialog>
 
+      {/* Start Rollout Dialog */}
+      <Dialog open={!!rolloutBinary} onClose={() => setRolloutBinary(null)} maxWidth="sm" fullWidth>
+        <DialogTitle>Canary Rollout of {rolloutBinary?.file_name}</DialogTitle>
+        <DialogContent>
+          <DialogContentText sx={{ mb: 2 }}>
+            Agents carrying the canary label run this version in place of the current default. After the observation
+            period it is promoted to the fleet, or rolled back as soon as the canaries fail or slow down more than allowed.
+          </DialogContentText>
+          <Stack spacing={2}>
+            <TextField
+              label="Canary agent label"
+              value={rolloutForm.canary_label}
+              onChange={(e) => setRolloutForm({ ...rolloutForm, canary_label: e.target.value })}
+              required
+            />
+            <TextField
+              label="Observation period (hours)"
+              type="number"
+              value={rolloutForm.observation_hours}
+              onChange={(e) => setRolloutForm({ ...rolloutForm, observation_hours: Number(e.target.value) })}
+              inputProps={{ min: 1 }}
+            />
+            <TextField
+              label="Canary tasks needed before deciding"
+              type="number"
+              value={rolloutForm.min_tasks}
+              onChange={(e) => setRolloutForm({ ...rolloutForm, min_tasks: Number(e.target.value) })}
+              inputProps={{ min: 1 }}
+            />
+            <TextField
+              label="Allowed failure rate increase (percentage points)"
+              type="number"
+              value={rolloutForm.max_failure_rate_increase}
+              onChange={(e) => setRolloutForm({ ...rolloutForm, max_failure_rate_increase: Number(e.target.value) })}
+              inputProps={{ min: 0 }}
+            />
+            <TextField
+              label="Allowed speed regression (%)"
+              type="number"
+              value={rolloutForm.max_speed_regression}
+              onChange={(e) => setRolloutForm({ ...rolloutForm, max_speed_regression: Number(e.target.value) })}
+              inputProps={{ min: 0 }}
+            />
+          </Stack>
+        </DialogContent>
+        <DialogActions>
+          <Button onClick={() => setRolloutBinary(null)} disabled={isLoading}>
+            Cancel
+          </Button>
+          <Button onClick={handleStartRollout} variant="contained" disabled={isLoading || !rolloutForm.canary_label.trim()}>
+            Start Rollout
+          </Button>
+        </DialogActions>
+      </Dialog>
+
       {/* Add Binary Dialog */}
       <Dialog
         open={openAddDialog}
@@ -34,6 +34,8 @@ const HashTypeDialog: React.FC<HashTypeDialogProps> = ({
     description: '',
     example: '',
     slow: false,
+    aliases: '',
+    validationRegex: '',
   });
   const [errors, setErrors] = useState<Record<string, string>>({});
   const [loading, setLoading] = useState(false);
@@ -48,6 +50,8 @@ const HashTypeDialog: React.FC<HashTypeDialogProps> = ({
         description: hashType.description || '',
         example: hashType.example || '',
         slow: hashType.slow,
+        aliases: (hashType.aliases || []).join(', '),
+        validationRegex: hashType.validation_regex || '',
       });
     } else {
       setFormData({
@@ -56,6 +60,8 @@ const HashTypeDialog: React.FC<HashTypeDialogProps> = ({
         description: '',
         example: '',
         slow: false,
+        aliases: '',
+        validationRegex: '',
       });
     }
     setErrors({});
@@ -76,6 +82,14 @@ const HashTypeDialog: React.FC<HashTypeDialogProps> = ({
       newErrors.name = 'Name is required';
     }
 
+    if (formData.validationRegex) {
+      try {
+        new RegExp(formData.validationRegex);
+      } catch {
+        newErrors.validationRegex = 'Invalid regular expression';
+      }
+    }
+
     setErrors(newErrors);
     return Object.keys(newErrors).length === 0;
   };
@@ -83,6 +97,11 @@ const HashTypeDialog: React.FC<HashTypeDialogProps> = ({
   const handleSubmit = async () => {
     if (!validate()) return;
 
+    const aliases = formData.aliases
+      .split(',')
+      .map((alias) => alias.trim().toLowerCase())
+      .filter((alias) => alias !== '');
+
     setLoading(true);
     try {
       if (isEditMode) {
@@ -92,6 +111,8 @@ const HashTypeDialog: React.FC<HashTypeDialogProps> = ({
           example: formData.example || null,
           is_enabled: true,
           slow: formData.slow,
+          aliases,
+          validation_regex: formData.validationRegex || null,
         };
         await onSave(updateData, hashType.id);
       } else {
@@ -102,6 +123,8 @@ const HashTypeDialog: React.FC<HashTypeDialogProps> = ({
           example: formData.example || null,
           is_enabled: true,
           slow: formData.slow,
+          aliases,
+          validation_regex: formData.validationRegex || null,
         };
         await onSave(createData);
       }
@@ -161,6 +184,24 @@ const HashTypeDialog: React.FC<HashTypeDialogProps> = ({
             helperText="Example of this hash format"
           />
           
+          <TextField
+            label="Aliases"
+            value={formData.aliases}
+            onChange={(e) => setFormData({ ...formData, aliases: e.target.value })}
+            fullWidth
+            helperText="Comma separated alternative names, e.g. ntlm, nt. Hashlists can be uploaded with an alias instead of the mode number."
+          />
+
+          <TextField
+            label="Validation Regex"
+            value={formData.validationRegex}
+            onChange={(e) => setFormData({ ...formData, validationRegex: e.target.value })}
+            error={!!errors.validationRegex}
+            helperText={errors.validationRegex || 'Optional pattern every hash must fully match, lines that do not are quarantined'}
+            fullWidth
+            sx={{ '& .MuiInputBase-input': { fontFamily: 'monospace' } }}
+          />
+
           <FormControlLabel
             control={
               <Checkbox
@@ -45,6 +45,7 @@ const HashTypeTable: React.FC<HashTypeTableProps> = ({
     return (
       ht.id.toString().includes(search) ||
       ht.name.toLowerCase().includes(search) ||
+      (ht.aliases || []).some((alias) => alias.includes(search)) ||
       (ht.description && ht.description.toLowerCase().includes(search))
     );
   });
@@ -131,7 +132,15 @@ const HashTypeTable: React.FC<HashTypeTableProps> = ({
                           />
                         </Tooltip>
                       )}
+                      {hashType.is_custom && (
+                        <Chip label="Custom" size="small" color="secondary" variant="outlined" />
+                      )}
                     </Box>
+                    {hashType.aliases && hashType.aliases.length > 0 && (
+                      <Typography variant="caption" color="text.secondary">
+                        {hashType.aliases.join(', ')}
+                      </Typography>
+                    )}
                   </TableCell>
                   <TableCell>
                     <Tooltip title={hashType.description || ''} arrow>
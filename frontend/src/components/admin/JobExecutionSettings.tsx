@@ -12,9 +12,21 @@ import {
   Divider,
   Paper,
   InputAdornment,
+  IconButton,
+  MenuItem,
 } from '@mui/material';
+import { Add as AddIcon, Delete as DeleteIcon } from '@mui/icons-material';
 import { useSnackbar } from 'notistack';
-import { getJobExecutionSettings, updateJobExecutionSettings, JobExecutionSettings } from '../../services/jobSettings';
+import { getJobExecutionSettings, updateJobExecutionSettings, JobExecutionSettings, DefaultHashcatArgsRule } from '../../services/jobSettings';
+
+const ATTACK_MODES = [
+  { value: 0, label: 'Straight (0)' },
+  { value: 1, label: 'Combination (1)' },
+  { value: 3, label: 'Brute-force (3)' },
+  { value: 6, label: 'Hybrid Wordlist + Mask (6)' },
+  { value: 7, label: 'Hybrid Mask + Wordlist (7)' },
+  { value: 9, label: 'Association (9)' },
+];
 
 const JobExecutionSettingsComponent: React.FC = () => {
   const [settings, setSettings] = useState<JobExecutionSettings | null>(null);
@@ -76,6 +88,16 @@ const JobExecutionSettingsComponent: React.FC = () => {
     });
   };
 
+  const defaultArgsRules = settings?.default_hashcat_args || [];
+
+  const updateDefaultArgsRule = (index: number, rule: DefaultHashcatArgsRule) => {
+    if (!settings) return;
+    setSettings({
+      ...settings,
+      default_hashcat_args: defaultArgsRules.map((r, i) => (i === index ? rule : r)),
+    });
+  };
+
   if (loading) {
     return (
       <Box display="flex" justifyContent="center" alignItems="center" minHeight="400px">
@@ -256,6 +278,61 @@ const JobExecutionSettingsComponent: React.FC = () => {
                   }}
                 />
               </Grid>
+              <Grid item xs={12} md={6}>
+                <TextField
+                  fullWidth
+                  type="number"
+                  label="Task Heartbeat Timeout"
+                  value={settings.task_heartbeat_timeout_minutes}
+                  onChange={handleChange('task_heartbeat_timeout_minutes')}
+                  helperText="Time a running task may go without progress before it is reassigned"
+                  InputProps={{
+                    inputProps: { min: 1 },
+                    endAdornment: <InputAdornment position="end">minutes</InputAdornment>,
+                  }}
+                />
+              </Grid>
+              <Grid item xs={12} md={6}>
+                <TextField
+                  fullWidth
+                  type="number"
+                  label="Slow Hash Task Heartbeat Timeout"
+                  value={settings.slow_hash_task_heartbeat_timeout_minutes}
+                  onChange={handleChange('slow_hash_task_heartbeat_timeout_minutes')}
+                  helperText="Task heartbeat timeout for slow hash types such as bcrypt"
+                  InputProps={{
+                    inputProps: { min: 1 },
+                    endAdornment: <InputAdornment position="end">minutes</InputAdornment>,
+                  }}
+                />
+              </Grid>
+              <Grid item xs={12} md={6}>
+                <TextField
+                  fullWidth
+                  type="number"
+                  label="Max Grace Doublings"
+                  value={settings.task_heartbeat_max_grace_doublings}
+                  onChange={handleChange('task_heartbeat_max_grace_doublings')}
+                  helperText="Times the timeout is doubled for agents slow to report status (0 disables)"
+                  InputProps={{
+                    inputProps: { min: 0, max: 10 },
+                  }}
+                />
+              </Grid>
+              <Grid item xs={12} md={6}>
+                <TextField
+                  fullWidth
+                  type="number"
+                  label="Agent Heartbeat Timeout"
+                  value={settings.agent_heartbeat_timeout_seconds}
+                  onChange={handleChange('agent_heartbeat_timeout_seconds')}
+                  helperText="Time without a heartbeat before an agent is marked inactive"
+                  InputProps={{
+                    inputProps: { min: 1 },
+                    endAdornment: <InputAdornment position="end">seconds</InputAdornment>,
+                  }}
+                />
+              </Grid>
             </Grid>
           </Paper>
         </Grid>
@@ -366,6 +443,127 @@ const JobExecutionSettingsComponent: React.FC = () => {
           </Paper>
         </Grid>
 
+        {/* Job Naming Settings */}
+        <Grid item xs={12}>
+          <Paper sx={{ p: 3 }}>
+            <Typography variant="subtitle1" gutterBottom fontWeight="bold">
+              Job Naming
+            </Typography>
+            <Divider sx={{ mb: 2 }} />
+            <Grid container spacing={2}>
+              <Grid item xs={12}>
+                <TextField
+                  fullWidth
+                  label="Job Name Template"
+                  value={settings.job_name_template}
+                  onChange={(e) => {
+                    setSettings({
+                      ...settings,
+                      job_name_template: e.target.value,
+                    });
+                  }}
+                  helperText="Names jobs created without a custom name. Placeholders: {client}, {hashlist}, {attack}, {hash_type}, {seq} (per-client counter), {date}"
+                  inputProps={{ maxLength: 200 }}
+                />
+              </Grid>
+            </Grid>
+          </Paper>
+        </Grid>
+
+        {/* Default Hashcat Arguments */}
+        <Grid item xs={12}>
+          <Paper sx={{ p: 3 }}>
+            <Typography variant="subtitle1" gutterBottom fontWeight="bold">
+              Default Hashcat Arguments
+            </Typography>
+            <Divider sx={{ mb: 2 }} />
+            <Typography variant="body2" color="textSecondary" sx={{ mb: 2 }}>
+              Arguments added to every job matching a rule. Later rules override the same option of earlier ones, and a job's own arguments override all of them.
+            </Typography>
+            {defaultArgsRules.map((rule, index) => (
+              <Grid container spacing={2} key={index} sx={{ mb: 1 }} alignItems="center">
+                <Grid item xs={12} md={3}>
+                  <TextField
+                    select
+                    fullWidth
+                    label="Attack Mode"
+                    value={rule.attack_mode ?? ''}
+                    onChange={(e) => updateDefaultArgsRule(index, {
+                      ...rule,
+                      attack_mode: e.target.value === '' ? undefined : Number(e.target.value),
+                    })}
+                  >
+                    <MenuItem value="">Any</MenuItem>
+                    {ATTACK_MODES.map((mode) => (
+                      <MenuItem key={mode.value} value={mode.value}>{mode.label}</MenuItem>
+                    ))}
+                  </TextField>
+                </Grid>
+                <Grid item xs={12} md={2}>
+                  <TextField
+                    select
+                    fullWidth
+                    label="Hash Types"
+                    value={rule.hash_class || ''}
+                    onChange={(e) => updateDefaultArgsRule(index, {
+                      ...rule,
+                      hash_class: e.target.value as DefaultHashcatArgsRule['hash_class'],
+                    })}
+                  >
+                    <MenuItem value="">Any</MenuItem>
+                    <MenuItem value="fast">Fast</MenuItem>
+                    <MenuItem value="slow">Slow</MenuItem>
+                  </TextField>
+                </Grid>
+                <Grid item xs={12} md={2}>
+                  <TextField
+                    fullWidth
+                    type="number"
+                    label="Minimum Rules"
+                    value={rule.min_rules || 0}
+                    onChange={(e) => updateDefaultArgsRule(index, {
+                      ...rule,
+                      min_rules: Math.max(0, parseInt(e.target.value, 10) || 0),
+                    })}
+                    InputProps={{
+                      inputProps: { min: 0 },
+                    }}
+                  />
+                </Grid>
+                <Grid item xs={10} md={4}>
+                  <TextField
+                    fullWidth
+                    label="Arguments"
+                    value={rule.args}
+                    onChange={(e) => updateDefaultArgsRule(index, { ...rule, args: e.target.value })}
+                    placeholder="-O"
+                  />
+                </Grid>
+                <Grid item xs={2} md={1}>
+                  <IconButton
+                    aria-label="remove rule"
+                    onClick={() => setSettings({
+                      ...settings,
+                      default_hashcat_args: defaultArgsRules.filter((_, i) => i !== index),
+                    })}
+                  >
+                    <DeleteIcon />
+                  </IconButton>
+                </Grid>
+              </Grid>
+            ))}
+            <Button
+              startIcon={<AddIcon />}
+              onClick={() => setSettings({
+                ...settings,
+                default_hashcat_args: [...defaultArgsRules, { args: '' }],
+              })}
+            >
+              Add Rule
+            </Button>
+          </Paper>
+        </Grid>
+
         {/* Rule Splitting Settings */}
         <Grid item xs={12}>
           <Paper sx={{ p: 3 }}>
@@ -0,0 +1,194 @@
+import React, { useState, useEffect } from 'react';
+import {
+  Box,
+  Typography,
+  TextField,
+  Button,
+  Alert,
+  CircularProgress,
+  Paper,
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableRow,
+} from '@mui/material';
+import { useSnackbar } from 'notistack';
+import {
+  listMaintenanceWindows,
+  startMaintenance,
+  endMaintenance,
+  MaintenanceWindow,
+} from '../../services/maintenance';
+
+const formatTime = (value?: string) => (value ? new Date(value).toLocaleString() : '-');
+
+const MaintenanceSettings: React.FC = () => {
+  const [windows, setWindows] = useState<MaintenanceWindow[]>([]);
+  const [message, setMessage] = useState('');
+  const [drainMinutes, setDrainMinutes] = useState(0);
+  const [loading, setLoading] = useState(true);
+  const [saving, setSaving] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const { enqueueSnackbar } = useSnackbar();
+
+  const active = windows.find((w) => !w.ended_at);
+
+  useEffect(() => {
+    fetchWindows();
+  }, []);
+
+  const fetchWindows = async () => {
+    setLoading(true);
+    setError(null);
+    try {
+      setWindows(await listMaintenanceWindows());
+    } catch (err) {
+      console.error('Failed to fetch maintenance windows:', err);
+      setError('Failed to load maintenance windows. Please try again.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleStart = async () => {
+    setError(null);
+    setSaving(true);
+    try {
+      await startMaintenance(message, drainMinutes * 60);
+      enqueueSnackbar('Maintenance mode started', { variant: 'success' });
+      setMessage('');
+      await fetchWindows();
+    } catch (err: any) {
+      console.error('Failed to start maintenance:', err);
+      setError(err.response?.data?.error || 'Failed to start maintenance. Please try again.');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleEnd = async () => {
+    setError(null);
+    setSaving(true);
+    try {
+      await endMaintenance();
+      enqueueSnackbar('Maintenance mode ended', { variant: 'success' });
+      await fetchWindows();
+    } catch (err: any) {
+      console.error('Failed to end maintenance:', err);
+      setError(err.response?.data?.error || 'Failed to end maintenance. Please try again.');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  if (loading) {
+    return (
+      <Box display="flex" justifyContent="center" alignItems="center" minHeight={200}>
+        <CircularProgress />
+      </Box>
+    );
+  }
+
+  return (
+    <Box>
+      <Typography variant="h6" gutterBottom>
+        Maintenance Mode
+      </Typography>
+
+      {error && (
+        <Alert severity="error" sx={{ mb: 2 }}>
+          {error}
+        </Alert>
+      )}
+
+      <Paper sx={{ p: 3, mb: 3 }}>
+        {active ? (
+          <>
+            <Alert severity="warning" sx={{ mb: 2 }}>
+              Maintenance mode is active since {formatTime(active.started_at)}: {active.message}
+              {active.force_stopped_at
+                ? ` Running tasks were stopped at ${formatTime(active.force_stopped_at)}.`
+                : active.drain_timeout_seconds > 0
+                  ? ` Running tasks are stopped ${Math.round(active.drain_timeout_seconds / 60)} minutes after the start.`
+                  : ' Running tasks are left to finish.'}
+            </Alert>
+            <Button variant="contained" onClick={handleEnd} disabled={saving}>
+              {saving ? <CircularProgress size={24} /> : 'End Maintenance'}
+            </Button>
+          </>
+        ) : (
+          <>
+            <Typography variant="body2" color="text.secondary" sx={{ mb: 3 }}>
+              While in maintenance mode no new tasks are dispatched and no jobs can be created. Every user sees the message as a banner.
+            </Typography>
+            <TextField
+              fullWidth
+              label="Banner Message"
+              value={message}
+              onChange={(e) => setMessage(e.target.value)}
+              inputProps={{ maxLength: 500 }}
+              sx={{ mb: 2 }}
+            />
+            <TextField
+              fullWidth
+              label="Drain Timeout (minutes)"
+              type="number"
+              value={drainMinutes}
+              onChange={(e) => {
+                const value = parseInt(e.target.value, 10);
+                setDrainMinutes(!isNaN(value) && value >= 0 ? value : 0);
+              }}
+              helperText="Running chunks are stopped after this time and resume after maintenance. 0 lets them finish."
+              inputProps={{ min: 0, step: 1 }}
+              sx={{ mb: 2 }}
+            />
+            <Button variant="contained" color="warning" onClick={handleStart} disabled={saving || !message.trim()}>
+              {saving ? <CircularProgress size={24} /> : 'Start Maintenance'}
+            </Button>
+          </>
+        )}
+      </Paper>
+
+      <Typography variant="subtitle1" gutterBottom sx={{ fontWeight: 'bold' }}>
+        Maintenance History
+      </Typography>
+      <Paper>
+        <Table size="small">
+          <TableHead>
+            <TableRow>
+              <TableCell>Started</TableCell>
+              <TableCell>Started By</TableCell>
+              <TableCell>Message</TableCell>
+              <TableCell>Stopped Tasks</TableCell>
+              <TableCell>Ended</TableCell>
+              <TableCell>Ended By</TableCell>
+            </TableRow>
+          </TableHead>
+          <TableBody>
+            {windows.length === 0 ? (
+              <TableRow>
+                <TableCell colSpan={6} align="center">
+                  No maintenance windows yet
+                </TableCell>
+              </TableRow>
+            ) : (
+              windows.map((w) => (
+                <TableRow key={w.id}>
+                  <TableCell>{formatTime(w.started_at)}</TableCell>
+                  <TableCell>{w.started_by_username || '-'}</TableCell>
+                  <TableCell>{w.message}</TableCell>
+                  <TableCell>{w.force_stopped_at ? w.stopped_tasks : '-'}</TableCell>
+                  <TableCell>{formatTime(w.ended_at)}</TableCell>
+                  <TableCell>{w.ended_by_username || '-'}</TableCell>
+                </TableRow>
+              ))
+            )}
+          </TableBody>
+        </Table>
+      </Paper>
+    </Box>
+  );
+};
+
+export default MaintenanceSettings;
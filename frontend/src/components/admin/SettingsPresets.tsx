@@ -0,0 +1,173 @@
+import React, { useState, useEffect } from 'react';
+import {
+  Box,
+  Typography,
+  Button,
+  Alert,
+  CircularProgress,
+  FormControl,
+  InputLabel,
+  Select,
+  MenuItem,
+  Paper,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+} from '@mui/material';
+import { useSnackbar } from 'notistack';
+import { api } from '../../services/api';
+
+interface SettingsPreset {
+  id: string;
+  name: string;
+  description: string;
+  settings: Record<string, string>;
+}
+
+interface SettingsPresetChange {
+  key: string;
+  current: string | null;
+  proposed: string;
+  changed: boolean;
+}
+
+interface SettingsPresetsProps {
+  // Called after a preset was applied, so forms showing the settings reload
+  onApplied?: () => void;
+}
+
+const SettingsPresets: React.FC<SettingsPresetsProps> = ({ onApplied }) => {
+  const { enqueueSnackbar } = useSnackbar();
+  const [presets, setPresets] = useState<SettingsPreset[]>([]);
+  const [selected, setSelected] = useState('');
+  const [changes, setChanges] = useState<SettingsPresetChange[] | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [applying, setApplying] = useState(false);
+
+  useEffect(() => {
+    api.get<SettingsPreset[]>('/api/admin/settings/presets')
+      .then(response => setPresets(response.data))
+      .catch(error => {
+        console.error('Failed to fetch settings presets:', error);
+        enqueueSnackbar('Failed to load settings presets', { variant: 'error' });
+      });
+  }, [enqueueSnackbar]);
+
+  const handleSelect = async (id: string) => {
+    setSelected(id);
+    setChanges(null);
+    if (!id) {
+      return;
+    }
+    setLoading(true);
+    try {
+      const response = await api.get<{ changes: SettingsPresetChange[] }>(`/api/admin/settings/presets/${id}/preview`);
+      setChanges(response.data.changes);
+    } catch (error) {
+      console.error('Failed to preview settings preset:', error);
+      enqueueSnackbar('Failed to preview settings preset', { variant: 'error' });
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleApply = async () => {
+    setApplying(true);
+    try {
+      const response = await api.post<{ changes: SettingsPresetChange[] }>(`/api/admin/settings/presets/${selected}/apply`);
+      const changed = response.data.changes.filter(change => change.changed).length;
+      enqueueSnackbar(`Preset applied, ${changed} settings changed`, { variant: 'success' });
+      setSelected('');
+      setChanges(null);
+      onApplied?.();
+    } catch (error) {
+      console.error('Failed to apply settings preset:', error);
+      enqueueSnackbar('Failed to apply settings preset', { variant: 'error' });
+    } finally {
+      setApplying(false);
+    }
+  };
+
+  const preset = presets.find(p => p.id === selected);
+  const changedCount = changes?.filter(change => change.changed).length ?? 0;
+
+  return (
+    <Paper sx={{ p: 3, mb: 3 }}>
+      <Typography variant="h6" gutterBottom>
+        Deployment Presets
+      </Typography>
+      <Typography variant="body2" color="text.secondary" sx={{ mb: 2 }}>
+        Set coherent defaults for chunk sizes, rule splitting, agent concurrency, timeouts and refresh
+        intervals in one step. Review the changes before applying them.
+      </Typography>
+
+      <FormControl size="small" sx={{ minWidth: 260 }}>
+        <InputLabel id="settings-preset-label">Preset</InputLabel>
+        <Select
+          labelId="settings-preset-label"
+          label="Preset"
+          value={selected}
+          onChange={(e) => handleSelect(e.target.value as string)}
+        >
+          <MenuItem value=""><em>None</em></MenuItem>
+          {presets.map(p => (
+            <MenuItem key={p.id} value={p.id}>{p.name}</MenuItem>
+          ))}
+        </Select>
+      </FormControl>
+
+      {preset && (
+        <Alert severity="info" sx={{ mt: 2 }}>{preset.description}</Alert>
+      )}
+
+      {loading && (
+        <Box sx={{ display: 'flex', justifyContent: 'center', p: 2 }}>
+          <CircularProgress size={24} />
+        </Box>
+      )}
+
+      {changes && (
+        <>
+          <TableContainer sx={{ mt: 2 }}>
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>Setting</TableCell>
+                  <TableCell>Current</TableCell>
+                  <TableCell>Preset</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {changes.map(change => (
+                  <TableRow key={change.key} sx={change.changed ? undefined : { opacity: 0.5 }}>
+                    <TableCell sx={{ fontFamily: 'monospace' }}>{change.key}</TableCell>
+                    <TableCell>{change.current ?? '—'}</TableCell>
+                    <TableCell sx={change.changed ? { fontWeight: 'bold' } : undefined}>{change.proposed}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+          <Box sx={{ display: 'flex', justifyContent: 'flex-end', alignItems: 'center', gap: 2, mt: 2 }}>
+            <Typography variant="body2" color="text.secondary">
+              {changedCount === 0 ? 'All settings already match this preset' : `${changedCount} settings will change`}
+            </Typography>
+            <Button
+              variant="contained"
+              onClick={handleApply}
+              disabled={applying || changedCount === 0}
+              startIcon={applying ? <CircularProgress size={20} /> : undefined}
+            >
+              Apply Preset
+            </Button>
+          </Box>
+        </>
+      )}
+    </Paper>
+  );
+};
+
+export default SettingsPresets;
@@ -0,0 +1,169 @@
+/**
+ * Account correlation section showing accounts found in several hashlists of
+ * the report (workstation, domain and application dumps) and the passwords
+ * reused across them.
+ */
+import React, { useState } from 'react';
+import {
+  Paper,
+  Typography,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+  TablePagination,
+  Box,
+  Chip,
+} from '@mui/material';
+import { AccountCorrelationStats, AccountOccurrence } from '../../types/analytics';
+import { threeColumnTableStyles } from './tableStyles';
+
+interface AccountCorrelationSectionProps {
+  data: AccountCorrelationStats;
+}
+
+const formatAccount = (occurrence: AccountOccurrence) =>
+  occurrence.domain ? `${occurrence.domain}\\${occurrence.username}` : occurrence.username;
+
+export default function AccountCorrelationSection({ data }: AccountCorrelationSectionProps) {
+  const [chainPage, setChainPage] = useState(0);
+  const [accountPage, setAccountPage] = useState(0);
+  const rowsPerPage = 25;
+
+  const chains = data.reuse_chains || [];
+  const accounts = data.accounts || [];
+
+  if (chains.length === 0 && accounts.length === 0) {
+    return null;
+  }
+
+  const reusedPercentage = data.shared_accounts > 0 ? (data.reused_accounts / data.shared_accounts) * 100 : 0;
+
+  return (
+    <Paper sx={{ p: 3, mb: 3 }}>
+      <Typography variant="h5" gutterBottom>
+        Cross-Hashlist Account Correlation
+      </Typography>
+      <Typography variant="body2" color="text.secondary" sx={{ mb: 2 }}>
+        Accounts are matched by username across the report's hashlists, ignoring case and domain
+      </Typography>
+
+      <Box sx={{ mb: 3 }}>
+        <Table size="small">
+          <TableHead>
+            <TableRow>
+              <TableCell sx={threeColumnTableStyles.labelCell}>Metric</TableCell>
+              <TableCell sx={threeColumnTableStyles.countCell}>Count</TableCell>
+              <TableCell sx={threeColumnTableStyles.percentageCell}>Percentage</TableCell>
+            </TableRow>
+          </TableHead>
+          <TableBody>
+            <TableRow>
+              <TableCell sx={threeColumnTableStyles.labelCell}>Accounts in Several Hashlists</TableCell>
+              <TableCell sx={threeColumnTableStyles.countCell}>{data.shared_accounts.toLocaleString()}</TableCell>
+              <TableCell sx={threeColumnTableStyles.percentageCell}>-</TableCell>
+            </TableRow>
+            <TableRow>
+              <TableCell sx={threeColumnTableStyles.labelCell}>Accounts Reusing a Password</TableCell>
+              <TableCell sx={threeColumnTableStyles.countCell}>{data.reused_accounts.toLocaleString()}</TableCell>
+              <TableCell sx={threeColumnTableStyles.percentageCell}>{reusedPercentage.toFixed(2)}%</TableCell>
+            </TableRow>
+            <TableRow>
+              <TableCell sx={threeColumnTableStyles.labelCell}>Reuse Chains</TableCell>
+              <TableCell sx={threeColumnTableStyles.countCell}>{chains.length.toLocaleString()}</TableCell>
+              <TableCell sx={threeColumnTableStyles.percentageCell}>-</TableCell>
+            </TableRow>
+          </TableBody>
+        </Table>
+      </Box>
+
+      {chains.length > 0 && (
+        <Box sx={{ mb: 3 }}>
+          <Typography variant="h6" gutterBottom>
+            Reuse Chains
+          </Typography>
+          <TableContainer>
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>Password</TableCell>
+                  <TableCell>Accounts (Hashlist)</TableCell>
+                  <TableCell align="right">Hashlists</TableCell>
+                  <TableCell align="right">Accounts</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {chains.slice(chainPage * rowsPerPage, chainPage * rowsPerPage + rowsPerPage).map((chain, index) => (
+                  <TableRow key={index}>
+                    <TableCell>
+                      <Chip label={chain.password} size="small" />
+                    </TableCell>
+                    <TableCell>
+                      {chain.accounts.map((account) => `${formatAccount(account)} (${account.hashlist_name})`).join(', ')}
+                    </TableCell>
+                    <TableCell align="right">{chain.hashlist_count}</TableCell>
+                    <TableCell align="right">{chain.account_count}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+          <TablePagination
+            rowsPerPageOptions={[rowsPerPage]}
+            component="div"
+            count={chains.length}
+            rowsPerPage={rowsPerPage}
+            page={chainPage}
+            onPageChange={(_event, newPage) => setChainPage(newPage)}
+          />
+        </Box>
+      )}
+
+      {accounts.length > 0 && (
+        <Box>
+          <Typography variant="h6" gutterBottom>
+            Accounts in Several Hashlists
+          </Typography>
+          <TableContainer>
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>Username</TableCell>
+                  <TableCell>Found In</TableCell>
+                  <TableCell align="right">Password Reused</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {accounts.slice(accountPage * rowsPerPage, accountPage * rowsPerPage + rowsPerPage).map((account, index) => (
+                  <TableRow key={index}>
+                    <TableCell>{account.username}</TableCell>
+                    <TableCell>
+                      {account.occurrences
+                        .map((occurrence) =>
+                          `${occurrence.hashlist_name}: ${formatAccount(occurrence)}${occurrence.cracked ? ` = ${occurrence.password}` : ' (not cracked)'}`
+                        )
+                        .join('; ')}
+                    </TableCell>
+                    <TableCell align="right">
+                      {account.password_reused ? <Chip label="Yes" color="error" size="small" /> : 'No'}
+                    </TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+          <TablePagination
+            rowsPerPageOptions={[rowsPerPage]}
+            component="div"
+            count={accounts.length}
+            rowsPerPage={rowsPerPage}
+            page={accountPage}
+            onPageChange={(_event, newPage) => setAccountPage(newPage)}
+          />
+        </Box>
+      )}
+    </Paper>
+  );
+}
@@ -21,9 +21,11 @@ import PositionalAnalysisSection from './PositionalAnalysisSection';
 import PatternDetectionSection from './PatternDetectionSection';
 import UsernameCorrelationSection from './UsernameCorrelationSection';
 import PasswordReuseSection from './PasswordReuseSection';
+import AccountCorrelationSection from './AccountCorrelationSection';
 import TemporalPatternsSection from './TemporalPatternsSection';
 import MaskAnalysisSection from './MaskAnalysisSection';
 import CustomPatternsSection from './CustomPatternsSection';
+import PolicyComplianceSection from './PolicyComplianceSection';
 import StrengthMetricsSection from './StrengthMetricsSection';
 import TopPasswordsSection from './TopPasswordsSection';
 import RecommendationsSection from './RecommendationsSection';
@@ -133,6 +135,7 @@ export default function AnalyticsReportDisplay({
       custom_patterns: domainData.custom_patterns,
       strength_metrics: domainData.strength_metrics,
       top_passwords: domainData.top_passwords,
+      policy_compliance: domainData.policy_compliance,
       recommendations: data.recommendations, // Keep global recommendations
       domain_analytics: data.domain_analytics, // Keep for reference
     };
@@ -194,8 +197,13 @@ export default function AnalyticsReportDisplay({
       {/* Full-Width Sections Below Grid */}
       <StrengthMetricsSection data={filteredData.strength_metrics} />
 
+      {filteredData.policy_compliance && <PolicyComplianceSection data={filteredData.policy_compliance} />}
+
       <PasswordReuseSection data={filteredData.password_reuse} />
 
+      {/* Correlation spans domains, so it is only shown for "All" */}
+      {!selectedDomain && data.account_correlation && <AccountCorrelationSection data={data.account_correlation} />}
+
       <TopPasswordsSection data={filteredData.top_passwords} />
 
       <RecommendationsSection data={filteredData.recommendations} />
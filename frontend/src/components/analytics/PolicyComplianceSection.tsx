@@ -0,0 +1,83 @@
+/**
+ * Password policy compliance section showing how many cracked passwords the
+ * client's password policy allowed.
+ */
+import React from 'react';
+import {
+  Paper,
+  Typography,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+} from '@mui/material';
+import { PolicyComplianceStats } from '../../types/analytics';
+import { threeColumnTableStyles } from './tableStyles';
+
+interface PolicyComplianceSectionProps {
+  data: PolicyComplianceStats;
+}
+
+const violationLabels: Record<string, string> = {
+  too_short: 'Shorter than minimum length',
+  too_few_character_classes: 'Too few character classes',
+  missing_lowercase: 'No lowercase letter',
+  missing_uppercase: 'No uppercase letter',
+  missing_numbers: 'No number',
+  missing_special: 'No special character',
+  dictionary_word: 'Contains a dictionary word',
+  username_derived: 'Derived from username',
+};
+
+export default function PolicyComplianceSection({ data }: PolicyComplianceSectionProps) {
+  const violations = Object.entries(data.violations || {})
+    .filter(([_, value]) => value.count > 0)
+    .sort(([, a], [, b]) => b.count - a.count);
+
+  return (
+    <Paper sx={{ p: 3, mb: 3 }}>
+      <Typography variant="h5" gutterBottom>
+        Password Policy Compliance
+      </Typography>
+      <Typography variant="body2" color="text.secondary" sx={{ mb: 2 }}>
+        {data.compliant.count.toLocaleString()} cracked passwords ({data.compliant.percentage.toFixed(2)}%) comply with the
+        password policy (minimum length {data.policy.min_length}, {data.policy.min_character_classes} character classes)
+      </Typography>
+
+      <TableContainer>
+        <Table size="small">
+          <TableHead>
+            <TableRow>
+              <TableCell sx={threeColumnTableStyles.labelCell}>Result</TableCell>
+              <TableCell sx={threeColumnTableStyles.countCell}>Count</TableCell>
+              <TableCell sx={threeColumnTableStyles.percentageCell}>Percentage</TableCell>
+            </TableRow>
+          </TableHead>
+          <TableBody>
+            <TableRow>
+              <TableCell sx={threeColumnTableStyles.labelCell}>Compliant</TableCell>
+              <TableCell sx={threeColumnTableStyles.countCell}>{data.compliant.count.toLocaleString()}</TableCell>
+              <TableCell sx={threeColumnTableStyles.percentageCell}>{data.compliant.percentage.toFixed(2)}%</TableCell>
+            </TableRow>
+            <TableRow>
+              <TableCell sx={threeColumnTableStyles.labelCell}>Non-compliant</TableCell>
+              <TableCell sx={threeColumnTableStyles.countCell}>{data.non_compliant.count.toLocaleString()}</TableCell>
+              <TableCell sx={threeColumnTableStyles.percentageCell}>{data.non_compliant.percentage.toFixed(2)}%</TableCell>
+            </TableRow>
+            {violations.map(([violation, stats]) => (
+              <TableRow key={violation}>
+                <TableCell sx={{ ...threeColumnTableStyles.labelCell, pl: 4 }}>
+                  {violationLabels[violation] || violation}
+                </TableCell>
+                <TableCell sx={threeColumnTableStyles.countCell}>{stats.count.toLocaleString()}</TableCell>
+                <TableCell sx={threeColumnTableStyles.percentageCell}>{stats.percentage.toFixed(2)}%</TableCell>
+              </TableRow>
+            ))}
+          </TableBody>
+        </Table>
+      </TableContainer>
+    </Paper>
+  );
+}
@@ -0,0 +1,108 @@
+import React, { useEffect, useState } from 'react';
+import {
+  Alert,
+  Box,
+  Button,
+  CircularProgress,
+  Dialog,
+  DialogActions,
+  DialogContent,
+  DialogTitle,
+  List,
+  ListItem,
+  ListItemText,
+  Typography,
+} from '@mui/material';
+import { FileReferences } from '../../types/wordlists';
+
+interface DeleteFileDialogProps {
+  open: boolean;
+  kind: 'wordlist' | 'rule';
+  name: string;
+  loadReferences: () => Promise<FileReferences>;
+  onCancel: () => void;
+  onDelete: (force: boolean) => void;
+}
+
+// Confirms deleting a wordlist or rule and lists what uses it. A file used by
+// active jobs cannot be deleted, one used by preset jobs only by removing it
+// from those presets.
+export default function DeleteFileDialog({ open, kind, name, loadReferences, onCancel, onDelete }: DeleteFileDialogProps) {
+  const [references, setReferences] = useState<FileReferences | null>(null);
+  const [loading, setLoading] = useState(false);
+
+  useEffect(() => {
+    if (!open) {
+      setReferences(null);
+      return;
+    }
+    setLoading(true);
+    loadReferences()
+      .then(setReferences)
+      .catch(err => console.error(`Error loading ${kind} references:`, err))
+      .finally(() => setLoading(false));
+  }, [open, kind, loadReferences]);
+
+  const activeJobs = references?.jobs ?? [];
+  const presets = references?.preset_jobs ?? [];
+
+  return (
+    <Dialog open={open} onClose={onCancel} aria-labelledby="delete-dialog-title" maxWidth="sm" fullWidth>
+      <DialogTitle id="delete-dialog-title">Delete {kind === 'wordlist' ? 'Wordlist' : 'Rule'}</DialogTitle>
+      <DialogContent>
+        <Typography variant="body1" gutterBottom>
+          Are you sure you want to delete {kind} "{name}"? This action cannot be undone.
+        </Typography>
+
+        {loading && (
+          <Box sx={{ display: 'flex', justifyContent: 'center', p: 2 }}>
+            <CircularProgress size={24} />
+          </Box>
+        )}
+
+        {activeJobs.length > 0 && (
+          <>
+            <Alert severity="error" sx={{ mt: 2 }}>
+              The {kind} is used by {activeJobs.length} active job{activeJobs.length === 1 ? '' : 's'} and cannot be
+              deleted until they finish or are cancelled.
+            </Alert>
+            <List dense>
+              {activeJobs.map(job => (
+                <ListItem key={job.id}>
+                  <ListItemText primary={job.name || job.id} secondary={job.status} />
+                </ListItem>
+              ))}
+            </List>
+          </>
+        )}
+
+        {presets.length > 0 && (
+          <>
+            <Alert severity="warning" sx={{ mt: 2 }}>
+              The {kind} is used by {presets.length} preset job{presets.length === 1 ? '' : 's'}. Deleting it removes it
+              from them and flags them for review.
+            </Alert>
+            <List dense>
+              {presets.map(preset => (
+                <ListItem key={preset.id}>
+                  <ListItemText primary={preset.name} />
+                </ListItem>
+              ))}
+            </List>
+          </>
+        )}
+      </DialogContent>
+      <DialogActions>
+        <Button onClick={onCancel}>Cancel</Button>
+        <Button
+          onClick={() => onDelete(presets.length > 0)}
+          color="error"
+          variant="contained"
+          disabled={loading || activeJobs.length > 0}
+        >
+          {presets.length > 0 ? 'Remove from Presets and Delete' : 'Delete'}
+        </Button>
+      </DialogActions>
+    </Dialog>
+  );
+}
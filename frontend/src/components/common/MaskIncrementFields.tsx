@@ -0,0 +1,55 @@
+import React from 'react';
+import { Box, Checkbox, FormControlLabel, FormHelperText, TextField } from '@mui/material';
+import { MaskIncrement } from '../../types/adminJobs';
+
+interface MaskIncrementFieldsProps {
+  value: MaskIncrement | null | undefined;
+  onChange: (value: MaskIncrement | null) => void;
+}
+
+// Edits the --increment bounds of a brute force mask. The job runs the mask
+// cut to each length from min to max, shortest first.
+export default function MaskIncrementFields({ value, onChange }: MaskIncrementFieldsProps) {
+  const setBound = (bound: 'min' | 'max', input: string) => {
+    if (!value) return;
+    onChange({ min: value.min, max: value.max, [bound]: Math.max(0, parseInt(input) || 0) });
+  };
+
+  return (
+    <Box>
+      <FormControlLabel
+        control={
+          <Checkbox
+            checked={!!value}
+            onChange={(e) => onChange(e.target.checked ? { min: 1, max: 0 } : null)}
+          />
+        }
+        label="Increment mask length"
+      />
+      {value && (
+        <Box sx={{ display: 'flex', gap: 2, mt: 1 }}>
+          <TextField
+            label="Minimum length"
+            type="number"
+            value={value.min}
+            onChange={(e) => setBound('min', e.target.value)}
+            inputProps={{ min: 1 }}
+            size="small"
+          />
+          <TextField
+            label="Maximum length"
+            type="number"
+            value={value.max}
+            onChange={(e) => setBound('max', e.target.value)}
+            inputProps={{ min: 0 }}
+            helperText="0 = full mask length"
+            size="small"
+          />
+        </Box>
+      )}
+      <FormHelperText>
+        Runs the mask at each length between the bounds, like hashcat --increment. Keyspace is calculated per length.
+      </FormHelperText>
+    </Box>
+  );
+}
@@ -22,7 +22,7 @@ import { useNavigate } from 'react-router-dom';
 interface Hashlist {
   id: string;
   name: string;
-  status: 'uploading' | 'processing' | 'ready' | 'error';
+  status: 'uploading' | 'staged' | 'processing' | 'ready' | 'ready_with_errors' | 'error';
   total_hashes: number;
   cracked_hashes: number;
   clientName?: string;
@@ -166,7 +166,9 @@ export default function HashlistOverview() {
                         size="small"
                         color={
                           hashlist.status === 'ready' ? 'success' :
+                          hashlist.status === 'ready_with_errors' ? 'warning' :
                           hashlist.status === 'error' ? 'error' :
+                          hashlist.status === 'staged' ? 'warning' :
                           'primary'  
                         }
                       />
@@ -39,6 +39,8 @@ import {
 } from '@mui/icons-material';
 import { api } from '../../services/api';
 import { getJobExecutionSettings } from '../../services/jobSettings';
+import MaskIncrementFields from '../common/MaskIncrementFields';
+import { MaskIncrement } from '../../types/adminJobs';
 import { useNavigate } from 'react-router-dom';
 
 interface PresetJob {
@@ -73,6 +75,18 @@ interface FormData {
   binary_versions: Array<{ id: number; version: string; type: string }>;
 }
 
+// An agent a job can be pinned to or kept off
+interface AgentOption {
+  id: number;
+  name: string;
+}
+
+// Another hashlist of the same hash type a campaign can also run against
+interface HashlistOption {
+  id: number;
+  name: string;
+}
+
 interface CreateJobDialogProps {
   open: boolean;
   onClose: () => void;
@@ -99,6 +113,26 @@ export default function CreateJobDialog({
   const [selectedPresetJobs, setSelectedPresetJobs] = useState<string[]>([]);
   const [selectedWorkflows, setSelectedWorkflows] = useState<string[]>([]);
   const [customJobName, setCustomJobName] = useState<string>('');
+
+  // Benchmark override, applies to every job created
+  const [skipBenchmark, setSkipBenchmark] = useState(false);
+  const [benchmarkDuration, setBenchmarkDuration] = useState<string>('');
+  const [background, setBackground] = useState(false);
+  const [allowCloudBurst, setAllowCloudBurst] = useState(false);
+  const [remainingHashesOnly, setRemainingHashesOnly] = useState(false);
+
+  // Start condition, holds every job created as scheduled until it is met
+  const [startAt, setStartAt] = useState<string>('');
+  const [dependsOnJobId, setDependsOnJobId] = useState<string>('');
+
+  // Agent placement, applies to every job created
+  const [agents, setAgents] = useState<AgentOption[]>([]);
+  const [pinnedAgents, setPinnedAgents] = useState<AgentOption[]>([]);
+  const [excludedAgents, setExcludedAgents] = useState<AgentOption[]>([]);
+
+  // Campaign, runs the same attacks against these hashlists too
+  const [campaignOptions, setCampaignOptions] = useState<HashlistOption[]>([]);
+  const [campaignHashlists, setCampaignHashlists] = useState<HashlistOption[]>([]);
   
   // Custom job state
   const [customJob, setCustomJob] = useState({
@@ -107,6 +141,7 @@ export default function CreateJobDialog({
     wordlist_ids: [] as string[],
     rule_ids: [] as string[],
     mask: '',
+    mask_increment: null as MaskIncrement | null,
     priority: 5,
     max_agents: 0,
     binary_version_id: 1,
@@ -131,10 +166,17 @@ export default function CreateJobDialog({
     setLoadingJobs(true);
     try {
       // Fetch available jobs and job execution settings in parallel
-      const [response, jobExecutionSettings] = await Promise.all([
+      const [response, jobExecutionSettings, agentsResponse, hashlistsResponse] = await Promise.all([
         api.get(`/api/hashlists/${hashlistId}/available-jobs`),
-        getJobExecutionSettings().catch(() => null) // Gracefully handle if settings fetch fails
+        getJobExecutionSettings().catch(() => null), // Gracefully handle if settings fetch fails
+        api.get<AgentOption[]>('/api/agents').catch(() => null),
+        api.get('/api/hashlists', { params: { limit: 500 } }).catch(() => null)
       ]);
+      setAgents((agentsResponse?.data || []).map(agent => ({ id: Number(agent.id), name: agent.name })));
+      setCampaignOptions((hashlistsResponse?.data?.data || [])
+        .filter((hashlist: any) => hashlist.id !== hashlistId && hashlist.hash_type_id === hashTypeId
+          && !hashlist.parent_hashlist_id && hashlist.status !== 'staged')
+        .map((hashlist: any) => ({ id: hashlist.id, name: hashlist.name })));
       
       setPresetJobs(response.data.preset_jobs || []);
       setWorkflows(response.data.workflows || []);
@@ -237,6 +279,8 @@ export default function CreateJobDialog({
         // Map chunk_duration to chunk_size_seconds for API
         const customJobPayload = {
           ...customJob,
+          // Increments only apply to brute force masks
+          mask_increment: customJob.attack_mode === 3 ? customJob.mask_increment : null,
           chunk_size_seconds: customJob.chunk_duration
         };
         delete (customJobPayload as any).chunk_duration;
@@ -248,15 +292,54 @@ export default function CreateJobDialog({
         };
       }
 
+      if (skipBenchmark) {
+        payload.skip_benchmark = true;
+      } else if (benchmarkDuration !== '') {
+        const duration = parseInt(benchmarkDuration);
+        if (isNaN(duration) || duration < 10 || duration > 600) {
+          setError('Benchmark time limit must be between 10 and 600 seconds');
+          setLoading(false);
+          return;
+        }
+        payload.benchmark_duration_seconds = duration;
+      }
+
+      if (background) {
+        payload.is_background = true;
+      }
+      if (allowCloudBurst) {
+        payload.allow_cloud_burst = true;
+      }
+      if (remainingHashesOnly) {
+        payload.remaining_hashes_only = true;
+      }
+      if (startAt !== '') {
+        payload.start_at = new Date(startAt).toISOString();
+      }
+      if (dependsOnJobId.trim() !== '') {
+        payload.depends_on_job_id = dependsOnJobId.trim();
+      }
+
+      if (pinnedAgents.length > 0) {
+        payload.pinned_agent_ids = pinnedAgents.map(agent => agent.id);
+      }
+      if (excludedAgents.length > 0) {
+        payload.excluded_agent_ids = excludedAgents.map(agent => agent.id);
+      }
+      if (campaignHashlists.length > 0) {
+        payload.campaign_hashlist_ids = campaignHashlists.map(hashlist => hashlist.id);
+      }
+
       const response = await api.post(`/api/hashlists/${hashlistId}/create-job`, payload);
       
       setLoadingMessage(response.data.message || 'Job created successfully!');
       setSuccess(true);
       
-      // Navigate to jobs page after a short delay
+      // Navigate to the campaign or jobs page after a short delay
+      const campaignId = response.data.campaign_id;
       setTimeout(() => {
         onClose();
-        navigate('/jobs');
+        navigate(campaignId ? `/jobs/campaigns/${campaignId}` : '/jobs');
       }, 1500);
     } catch (err: any) {
       console.error('Failed to create job:', err);
@@ -290,12 +373,19 @@ export default function CreateJobDialog({
       setSuccess(false);
       setSelectedPresetJobs([]);
       setSelectedWorkflows([]);
+      setSkipBenchmark(false);
+      setBenchmarkDuration('');
+      setBackground(false);
+      setPinnedAgents([]);
+      setExcludedAgents([]);
+      setCampaignHashlists([]);
       setCustomJob({
         name: '',
         attack_mode: 0,
         wordlist_ids: [],
         rule_ids: [],
         mask: '',
+        mask_increment: null,
         priority: 5,
         max_agents: 0,
         binary_version_id: 1,
@@ -655,6 +745,10 @@ export default function CreateJobDialog({
                         helperText="?l = lowercase, ?u = uppercase, ?d = digit, ?s = special"
                         required
                       />
+                      <MaskIncrementFields
+                        value={customJob.mask_increment}
+                        onChange={(maskIncrement) => setCustomJob(prev => ({ ...prev, mask_increment: maskIncrement }))}
+                      />
                     </Grid>
                   )}
 
@@ -793,6 +887,144 @@ export default function CreateJobDialog({
                 </Grid>
               </Box>
             )}
+
+            {/* Benchmark override, shared by all tabs */}
+            <Grid container spacing={2} sx={{ mt: 1 }}>
+              <Grid item xs={12} sm={6}>
+                <FormControlLabel
+                  control={
+                    <Checkbox
+                      checked={skipBenchmark}
+                      onChange={(e) => setSkipBenchmark(e.target.checked)}
+                    />
+                  }
+                  label="Skip pre-task benchmark (use estimated keyspace)"
+                />
+              </Grid>
+              <Grid item xs={12} sm={6}>
+                <TextField
+                  fullWidth
+                  size="small"
+                  label="Benchmark Time Limit (seconds)"
+                  type="number"
+                  value={benchmarkDuration}
+                  disabled={skipBenchmark}
+                  onChange={(e) => setBenchmarkDuration(e.target.value)}
+                  inputProps={{ min: 10, max: 600 }}
+                  helperText="Leave empty for the system default (10-600 seconds)"
+                />
+              </Grid>
+              <Grid item xs={12}>
+                <FormControlLabel
+                  control={
+                    <Checkbox
+                      checked={background}
+                      onChange={(e) => setBackground(e.target.checked)}
+                    />
+                  }
+                  label="Background job (only runs on idle agents, gives way to any other job)"
+                />
+              </Grid>
+              <Grid item xs={12}>
+                <FormControlLabel
+                  control={
+                    <Checkbox
+                      checked={allowCloudBurst}
+                      onChange={(e) => setAllowCloudBurst(e.target.checked)}
+                    />
+                  }
+                  label="Allow cloud burst (may launch temporary cloud agents when no agent is free)"
+                />
+              </Grid>
+              <Grid item xs={12}>
+                <FormControlLabel
+                  control={
+                    <Checkbox
+                      checked={remainingHashesOnly}
+                      onChange={(e) => setRemainingHashesOnly(e.target.checked)}
+                    />
+                  }
+                  label="Only remaining hashes (runs against the hashes still uncracked when the job is created)"
+                />
+              </Grid>
+              <Grid item xs={12} sm={6}>
+                <TextField
+                  fullWidth
+                  size="small"
+                  type="datetime-local"
+                  label="Start at"
+                  value={startAt}
+                  onChange={(e) => setStartAt(e.target.value)}
+                  InputLabelProps={{ shrink: true }}
+                  helperText="Leave empty to start as soon as agents are free"
+                />
+              </Grid>
+              <Grid item xs={12} sm={6}>
+                <TextField
+                  fullWidth
+                  size="small"
+                  label="Start after job"
+                  value={dependsOnJobId}
+                  onChange={(e) => setDependsOnJobId(e.target.value)}
+                  placeholder="Job ID"
+                  helperText="Waits until this job completes, cancelled if it fails"
+                />
+              </Grid>
+              <Grid item xs={12} sm={6}>
+                <Autocomplete
+                  multiple
+                  size="small"
+                  options={agents.filter(agent => !excludedAgents.some(excluded => excluded.id === agent.id))}
+                  getOptionLabel={(agent) => `${agent.name} (#${agent.id})`}
+                  isOptionEqualToValue={(option, value) => option.id === value.id}
+                  value={pinnedAgents}
+                  onChange={(_, value) => setPinnedAgents(value)}
+                  renderInput={(params) => (
+                    <TextField
+                      {...params}
+                      label="Pin to agents"
+                      helperText="Only these agents run the job, leave empty for any agent"
+                    />
+                  )}
+                />
+              </Grid>
+              <Grid item xs={12} sm={6}>
+                <Autocomplete
+                  multiple
+                  size="small"
+                  options={agents.filter(agent => !pinnedAgents.some(pinned => pinned.id === agent.id))}
+                  getOptionLabel={(agent) => `${agent.name} (#${agent.id})`}
+                  isOptionEqualToValue={(option, value) => option.id === value.id}
+                  value={excludedAgents}
+                  onChange={(_, value) => setExcludedAgents(value)}
+                  renderInput={(params) => (
+                    <TextField
+                      {...params}
+                      label="Exclude agents"
+                      helperText="These agents never run the job"
+                    />
+                  )}
+                />
+              </Grid>
+              <Grid item xs={12}>
+                <Autocomplete
+                  multiple
+                  size="small"
+                  options={campaignOptions}
+                  getOptionLabel={(hashlist) => `${hashlist.name} (#${hashlist.id})`}
+                  isOptionEqualToValue={(option, value) => option.id === value.id}
+                  value={campaignHashlists}
+                  onChange={(_, value) => setCampaignHashlists(value)}
+                  renderInput={(params) => (
+                    <TextField
+                      {...params}
+                      label="Also run against hashlists"
+                      helperText="Runs the same attacks against other hashlists of this hash type as one campaign, with combined progress and results"
+                    />
+                  )}
+                />
+              </Grid>
+            </Grid>
           </>
         )}
       </DialogContent>
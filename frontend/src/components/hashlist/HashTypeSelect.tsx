@@ -6,7 +6,8 @@ import {
   CircularProgress,
   Box,
   TextField,
-  Autocomplete
+  Autocomplete,
+  createFilterOptions
 } from '@mui/material';
 import { useQuery } from '@tanstack/react-query';
 import { api } from '../../services/api';
@@ -15,9 +16,15 @@ import { AxiosResponse } from 'axios';
 interface HashType {
   id: number;
   name: string;
+  aliases?: string[];
   // Add other relevant fields from API if necessary
 }
 
+// Match typed text against the mode number, the name and the aliases
+const filterHashTypes = createFilterOptions<HashType>({
+  stringify: (option) => `${option.id} ${option.name} ${(option.aliases || []).join(' ')}`,
+});
+
 interface HashTypeApiResponse {
   data: HashType[];
   // Add other potential response fields like total_count, etc.
@@ -68,6 +75,7 @@ export default function HashTypeSelect({
           <Autocomplete
             options={hashTypes}
             getOptionLabel={(option) => `${option.id} - ${option.name}` || ''}
+            filterOptions={filterHashTypes}
             isOptionEqualToValue={(option, value) => option.id === value?.id}
             value={selectedOption}
             loading={isLoading}
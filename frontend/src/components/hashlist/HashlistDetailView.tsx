@@ -29,6 +29,7 @@ import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { api } from '../../services/api';
 import CreateJobDialog from './CreateJobDialog';
 import HashlistHashesTable from './HashlistHashesTable';
+import HashlistStagingReview from './HashlistStagingReview';
 import ClientAutocomplete from './ClientAutocomplete';
 import { useSnackbar } from 'notistack';
 import { AxiosResponse, AxiosError } from 'axios';
@@ -166,7 +167,7 @@ export default function HashlistDetailView() {
               variant="contained"
               startIcon={<PlayArrowIcon />}
               onClick={() => setCreateJobDialogOpen(true)}
-              disabled={hashlist.status !== 'ready'}
+              disabled={hashlist.status !== 'ready' && hashlist.status !== 'ready_with_errors'}
             >
               Create Job
             </Button>
@@ -193,7 +194,9 @@ export default function HashlistDetailView() {
               label={hashlist.status}
               color={
                 hashlist.status === 'ready' ? 'success' :
-                hashlist.status === 'error' ? 'error' : 'primary'
+                hashlist.status === 'ready_with_errors' ? 'warning' :
+                hashlist.status === 'error' ? 'error' :
+                hashlist.status === 'staged' ? 'warning' : 'primary'
               }
             />
           </Typography>
@@ -235,9 +238,29 @@ export default function HashlistDetailView() {
             </Typography>
           </Box>
         </Box>
+
+        {hashlist.sub_lists?.length > 0 && (
+          <Box sx={{ mt: 3 }}>
+            <Typography variant="subtitle2">
+              Split into {hashlist.sub_lists.length} sub-lists, jobs run against each of them
+            </Typography>
+            {hashlist.sub_lists.map((subList: any) => (
+              <Typography key={subList.id} variant="body2">
+                Part {subList.part_number}: {subList.cracked_hashes} of {subList.total_hashes} cracked ({subList.status})
+              </Typography>
+            ))}
+          </Box>
+        )}
       </Paper>
 
-      {hashlist && (
+      {hashlist.status === 'staged' && (
+        <HashlistStagingReview
+          hashlistId={id!}
+          hashTypeId={hashlist.hashTypeID || hashlist.hash_type_id}
+        />
+      )}
+
+      {hashlist && hashlist.status !== 'staged' && (
         <HashlistHashesTable
           hashlistId={id!}
           hashlistName={hashlist.name}
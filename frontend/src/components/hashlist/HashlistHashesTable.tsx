@@ -1,4 +1,4 @@
-import React, { useState, useEffect, useCallback } from 'react';
+import React, { useState, useEffect, useCallback, useRef } from 'react';
 import {
   Box,
   Paper,
@@ -40,8 +40,23 @@ interface HashDetail {
   is_cracked: boolean;
   password?: string;
   last_updated: string;
+  cracked_by?: string;
 }
 
+// A crack returned by the incremental crack endpoint
+interface CrackDelta {
+  hash_id: string;
+  password: string;
+  cracked_at: string;
+  job_name?: string;
+}
+
+// How often the table asks for cracks made since it loaded
+const CRACK_POLL_INTERVAL_MS = 5000;
+// Margin for clock skew between the browser and the server; cracks seen
+// twice are applied twice without harm
+const CRACK_SINCE_MARGIN_MS = 60000;
+
 interface HashlistHashesTableProps {
   hashlistId: string;
   hashlistName: string;
@@ -63,6 +78,9 @@ export default function HashlistHashesTable({
   const [totalCount, setTotalCount] = useState(0);
   const [searchTerm, setSearchTerm] = useState('');
   const [openAllConfirm, setOpenAllConfirm] = useState(false);
+  const [liveCrackedHashes, setLiveCrackedHashes] = useState<number | null>(null);
+  const crackCursorRef = useRef<string>('');
+  const crackSinceRef = useRef<string>('');
   const { enqueueSnackbar } = useSnackbar();
 
   const pageSizeOptions = [500, 1000, 1500, 2000, -1];
@@ -74,6 +92,7 @@ export default function HashlistHashesTable({
 
       const limit = rowsPerPage === -1 ? -1 : rowsPerPage;
       const offset = page * (rowsPerPage === -1 ? 0 : rowsPerPage);
+      const loadedAt = new Date(Date.now() - CRACK_SINCE_MARGIN_MS).toISOString();
 
       const response = await api.get(
         `/api/hashlists/${hashlistId}/hashes?limit=${limit}&offset=${offset}`
@@ -81,6 +100,8 @@ export default function HashlistHashesTable({
 
       setData(response.data.hashes || []);
       setTotalCount(response.data.total || 0);
+      crackCursorRef.current = '';
+      crackSinceRef.current = loadedAt;
     } catch (err) {
       console.error('Error loading hash data:', err);
       setError('Failed to load hashes');
@@ -94,6 +115,59 @@ export default function HashlistHashesTable({
     loadData();
   }, [loadData]);
 
+  // Apply cracks made since the page loaded to the rows on screen instead of
+  // reloading them all
+  const pollCracks = useCallback(async () => {
+    if (!crackSinceRef.current) return;
+    try {
+      let hasMore = true;
+      const cracks: CrackDelta[] = [];
+      let cracked: number | null = null;
+      while (hasMore) {
+        const params = crackCursorRef.current
+          ? `cursor=${encodeURIComponent(crackCursorRef.current)}`
+          : `since=${encodeURIComponent(crackSinceRef.current)}`;
+        const response = await api.get(
+          `/api/hashlists/${hashlistId}/cracks?${params}&limit=2000`
+        );
+        cracks.push(...(response.data.cracks || []));
+        if (response.data.next_cursor) {
+          crackCursorRef.current = response.data.next_cursor;
+        }
+        cracked = response.data.cracked_hashes;
+        hasMore = !!response.data.has_more && !!response.data.next_cursor;
+      }
+      if (cracked !== null) {
+        setLiveCrackedHashes(cracked);
+      }
+      if (cracks.length === 0) return;
+
+      const byID = new Map(cracks.map((crack) => [crack.hash_id, crack]));
+      setData((rows) =>
+        rows.map((row) => {
+          const crack = byID.get(row.id);
+          if (!crack) return row;
+          return {
+            ...row,
+            is_cracked: true,
+            password: crack.password,
+            last_updated: crack.cracked_at,
+            cracked_by: crack.job_name,
+          };
+        })
+      );
+    } catch (err) {
+      console.error('Error polling cracks:', err);
+    }
+  }, [hashlistId]);
+
+  useEffect(() => {
+    const interval = setInterval(pollCracks, CRACK_POLL_INTERVAL_MS);
+    return () => clearInterval(interval);
+  }, [pollCracks]);
+
+  const shownCrackedHashes = liveCrackedHashes ?? crackedHashes;
+
   const handleChangePage = (event: unknown, newPage: number) => {
     setPage(newPage);
   };
@@ -179,9 +253,9 @@ export default function HashlistHashesTable({
               Hashes
             </Typography>
             <Typography variant="body2" color="text.secondary">
-              {crackedHashes} of {totalHashes} cracked (
+              {shownCrackedHashes} of {totalHashes} cracked (
               {totalHashes > 0
-                ? Math.round((crackedHashes / totalHashes) * 100)
+                ? Math.round((shownCrackedHashes / totalHashes) * 100)
                 : 0}
               %)
             </Typography>
@@ -263,7 +337,13 @@ export default function HashlistHashesTable({
                       whiteSpace: 'nowrap',
                     }}
                   >
-                    {hash.password || '-'}
+                    {hash.cracked_by ? (
+                      <Tooltip title={`Cracked by ${hash.cracked_by}`}>
+                        <span>{hash.password || '-'}</span>
+                      </Tooltip>
+                    ) : (
+                      hash.password || '-'
+                    )}
                   </TableCell>
                   <TableCell>
                     <Chip
@@ -0,0 +1,200 @@
+import React from 'react';
+import {
+  Alert,
+  Box,
+  Button,
+  CircularProgress,
+  Paper,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+  Typography,
+} from '@mui/material';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { useSnackbar } from 'notistack';
+import { useNavigate } from 'react-router-dom';
+import { api } from '../../services/api';
+
+// Lines of a staged upload that look like one hash type
+interface StagingType {
+  hash_type_id: number;
+  hash_type_name?: string;
+  lines: number;
+  detected: boolean;
+}
+
+// An invalid line of a staged upload
+interface StagingLine {
+  line_number: number;
+  line: string;
+  reason: string;
+}
+
+// What loading a staged upload would do, from /api/hashlists/{id}/staging
+interface StagingPreview {
+  lines: number;
+  valid_lines: number;
+  invalid_lines: number;
+  duplicate_lines: number;
+  unique_hashes: number;
+  detected_types: StagingType[];
+  type_mismatch: boolean;
+  invalid_samples: StagingLine[];
+}
+
+interface HashlistStagingReviewProps {
+  hashlistId: string;
+  hashTypeId: number;
+}
+
+export default function HashlistStagingReview({ hashlistId, hashTypeId }: HashlistStagingReviewProps) {
+  const queryClient = useQueryClient();
+  const navigate = useNavigate();
+  const { enqueueSnackbar } = useSnackbar();
+
+  const { data: preview, isLoading } = useQuery({
+    queryKey: ['hashlist-staging', hashlistId],
+    queryFn: () => api.get<StagingPreview>(`/api/hashlists/${hashlistId}/staging`).then(res => res.data)
+  });
+
+  const commitMutation = useMutation({
+    mutationFn: () => api.post(`/api/hashlists/${hashlistId}/commit`),
+    onSuccess: () => {
+      enqueueSnackbar('Hashlist committed, loading hashes', { variant: 'success' });
+      queryClient.invalidateQueries({ queryKey: ['hashlist', hashlistId] });
+      queryClient.invalidateQueries({ queryKey: ['hashlists'] });
+    },
+    onError: () => {
+      enqueueSnackbar('Failed to commit hashlist', { variant: 'error' });
+    }
+  });
+
+  const discardMutation = useMutation({
+    mutationFn: () => api.delete(`/api/hashlists/${hashlistId}/staging`),
+    onSuccess: () => {
+      enqueueSnackbar('Staged hashlist discarded', { variant: 'success' });
+      queryClient.invalidateQueries({ queryKey: ['hashlists'] });
+      navigate('/hashlists');
+    },
+    onError: () => {
+      enqueueSnackbar('Failed to discard hashlist', { variant: 'error' });
+    }
+  });
+
+  if (isLoading) {
+    return (
+      <Box sx={{ display: 'flex', justifyContent: 'center', p: 2 }}>
+        <CircularProgress size={24} />
+      </Box>
+    );
+  }
+  if (!preview) {
+    return null;
+  }
+
+  const busy = commitMutation.isPending || discardMutation.isPending;
+  const topType = preview.detected_types[0];
+
+  return (
+    <Paper sx={{ p: 3, mb: 3 }}>
+      <Typography variant="h6" gutterBottom>
+        Staged Upload
+      </Typography>
+      <Typography variant="body2" color="text.secondary" sx={{ mb: 2 }}>
+        No hashes have been loaded yet. Review the upload, then commit it to load its hashes or discard it.
+      </Typography>
+
+      {preview.type_mismatch && topType && (
+        <Alert severity="warning" sx={{ mb: 2 }}>
+          Most lines look like {topType.hash_type_name || `mode ${topType.hash_type_id}`}, not the hash type
+          chosen for this hashlist (mode {hashTypeId}). Discard the upload and upload it again with the right type.
+        </Alert>
+      )}
+      {preview.valid_lines === 0 && (
+        <Alert severity="error" sx={{ mb: 2 }}>
+          The upload contains no valid hashes.
+        </Alert>
+      )}
+
+      <Box display="flex" gap={3} flexWrap="wrap" sx={{ mb: 2 }}>
+        <Typography>Lines: {preview.lines.toLocaleString()}</Typography>
+        <Typography>Valid: {preview.valid_lines.toLocaleString()}</Typography>
+        <Typography>Invalid: {preview.invalid_lines.toLocaleString()}</Typography>
+        <Typography>Duplicates: {preview.duplicate_lines.toLocaleString()}</Typography>
+        <Typography>Unique hashes: {preview.unique_hashes.toLocaleString()}</Typography>
+      </Box>
+
+      {preview.detected_types.length > 0 && (
+        <>
+          <Typography variant="subtitle2" gutterBottom>Detected hash types</Typography>
+          <TableContainer sx={{ mb: 2 }}>
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>Hash type</TableCell>
+                  <TableCell align="right">Lines</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {preview.detected_types.map(type => (
+                  <TableRow key={type.hash_type_id}>
+                    <TableCell>
+                      {type.hash_type_name || `Mode ${type.hash_type_id}`}
+                      {!type.detected && ' (no recognizable signature)'}
+                    </TableCell>
+                    <TableCell align="right">{type.lines.toLocaleString()}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+        </>
+      )}
+
+      {preview.invalid_samples.length > 0 && (
+        <>
+          <Typography variant="subtitle2" gutterBottom>
+            Invalid lines {preview.invalid_lines > preview.invalid_samples.length && `(first ${preview.invalid_samples.length})`}
+          </Typography>
+          <TableContainer sx={{ mb: 2 }}>
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>Line</TableCell>
+                  <TableCell>Content</TableCell>
+                  <TableCell>Reason</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {preview.invalid_samples.map(sample => (
+                  <TableRow key={sample.line_number}>
+                    <TableCell>{sample.line_number}</TableCell>
+                    <TableCell sx={{ fontFamily: 'monospace', wordBreak: 'break-all' }}>{sample.line}</TableCell>
+                    <TableCell>{sample.reason}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+        </>
+      )}
+
+      <Box sx={{ display: 'flex', justifyContent: 'flex-end', gap: 2 }}>
+        <Button color="error" onClick={() => discardMutation.mutate()} disabled={busy}>
+          Discard
+        </Button>
+        <Button
+          variant="contained"
+          onClick={() => commitMutation.mutate()}
+          disabled={busy || preview.valid_lines === 0}
+          startIcon={commitMutation.isPending ? <CircularProgress size={20} /> : undefined}
+        >
+          Commit
+        </Button>
+      </Box>
+    </Paper>
+  );
+}
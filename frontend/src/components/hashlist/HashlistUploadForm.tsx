@@ -55,6 +55,15 @@ type FormData = {
   excludeFromPotfile?: boolean;
 };
 
+// One hash type found in an upload by /api/hashlists/detect-types
+interface DetectedTypeGroup {
+  hash_type_id: number;
+  hash_type_name?: string;
+  lines: number;
+  detected: boolean;
+  sample: string;
+}
+
 interface HashlistUploadFormProps {
   onSuccess?: () => void;
 }
@@ -67,6 +76,10 @@ export default function HashlistUploadForm({ onSuccess }: HashlistUploadFormProp
   const [potfileGloballyEnabled, setPotfileGloballyEnabled] = useState(true);
   const [clientPotfileEnabled, setClientPotfileEnabled] = useState(true);
   const [requireClient, setRequireClient] = useState(false);
+  const [detectedGroups, setDetectedGroups] = useState<DetectedTypeGroup[] | null>(null);
+  const [splitByType, setSplitByType] = useState(true);
+  const [normalizeEncoding, setNormalizeEncoding] = useState(false);
+  const [stageUpload, setStageUpload] = useState(false);
   const queryClient = useQueryClient();
   const navigate = useNavigate();
 
@@ -161,6 +174,11 @@ export default function HashlistUploadForm({ onSuccess }: HashlistUploadFormProp
     fetchClientPotfileSetting();
   }, [control._formValues.clientName]);
 
+  // A different file needs its hash types detected again
+  useEffect(() => {
+    setDetectedGroups(null);
+  }, [file, pastedHashes]);
+
   // Clear the other input when mode changes
   useEffect(() => {
     if (uploadMode === 'file') {
@@ -170,19 +188,32 @@ export default function HashlistUploadForm({ onSuccess }: HashlistUploadFormProp
     }
   }, [uploadMode]);
 
+  const getFileToUpload = (): File => {
+    if (uploadMode === 'file' && file) {
+      return file;
+    } else if (uploadMode === 'paste' && pastedHashes) {
+      // Create a Blob from pasted text
+      const blob = new Blob([pastedHashes], { type: 'text/plain' });
+      return new File([blob], 'pasted_hashes.txt', { type: 'text/plain' });
+    }
+    throw new Error('No hashes to upload');
+  };
+
+  // Check the upload for several hash types before sending it, so the user
+  // can choose to split it into one hashlist per type
+  const detectMutation = useMutation({
+    mutationFn: async (data: FormData) => {
+      const formData = new FormData();
+      formData.append('hashlist_file', getFileToUpload());
+      formData.append('hash_type_id', data.hashTypeId.toString());
+      const response = await api.post('/api/hashlists/detect-types', formData);
+      return response.data as { groups: DetectedTypeGroup[]; split_recommended: boolean };
+    },
+  });
+
   const uploadMutation = useMutation({
     mutationFn: async (data: FormData) => {
-      let fileToUpload: File;
-
-      if (uploadMode === 'file' && file) {
-        fileToUpload = file;
-      } else if (uploadMode === 'paste' && pastedHashes) {
-        // Create a Blob from pasted text
-        const blob = new Blob([pastedHashes], { type: 'text/plain' });
-        fileToUpload = new File([blob], 'pasted_hashes.txt', { type: 'text/plain' });
-      } else {
-        throw new Error('No hashes to upload');
-      }
+      const fileToUpload = getFileToUpload();
 
       const formData = new FormData();
       formData.append('hashlist_file', fileToUpload);
@@ -193,6 +224,15 @@ export default function HashlistUploadForm({ onSuccess }: HashlistUploadFormProp
       if (data.excludeFromPotfile !== undefined) {
         formData.append('exclude_from_potfile', data.excludeFromPotfile.toString());
       }
+      if (detectedGroups && detectedGroups.length > 1 && splitByType) {
+        formData.append('split_by_type', 'true');
+      }
+      if (normalizeEncoding) {
+        formData.append('normalize_encoding', 'true');
+      }
+      if (stageUpload) {
+        formData.append('stage', 'true');
+      }
 
       return api.post('/api/hashlists', formData, {
         onUploadProgress: (progressEvent) => {
@@ -204,8 +244,11 @@ export default function HashlistUploadForm({ onSuccess }: HashlistUploadFormProp
       });
     },
     onSuccess: (response) => {
-      // The backend returns the created hashlist data
-      const hashlistId = response.data?.id || response.data?.data?.id;
+      // The backend returns the created hashlist data, or all of them for a split upload
+      const splitHashlists = response.data?.hashlists;
+      const hashlistId = splitHashlists
+        ? splitHashlists.length === 1 && splitHashlists[0].id
+        : response.data?.id || response.data?.data?.id;
 
       if (hashlistId) {
         // Navigate to the hashlist detail page
@@ -218,6 +261,7 @@ export default function HashlistUploadForm({ onSuccess }: HashlistUploadFormProp
       reset();
       setFile(null);
       setPastedHashes('');
+      setDetectedGroups(null);
       setUploadProgress(0);
     },
     onError: (error) => {
@@ -226,7 +270,21 @@ export default function HashlistUploadForm({ onSuccess }: HashlistUploadFormProp
     }
   });
 
-  const onSubmit = (data: FormData): void => {
+  const onSubmit = async (data: FormData): Promise<void> => {
+    if (detectedGroups === null) {
+      try {
+        const detection = await detectMutation.mutateAsync(data);
+        if (detection.split_recommended) {
+          // Show the detected types and let the user confirm the upload
+          setDetectedGroups(detection.groups);
+          setSplitByType(true);
+          return;
+        }
+      } catch (error) {
+        // Detection is only advisory, upload as a single hashlist
+        console.error('Failed to detect hash types:', error);
+      }
+    }
     uploadMutation.mutate(data);
   };
 
@@ -428,13 +486,63 @@ export default function HashlistUploadForm({ onSuccess }: HashlistUploadFormProp
         </Typography>
       )}
 
+      <FormControlLabel
+        control={
+          <Checkbox
+            checked={normalizeEncoding}
+            onChange={(e) => setNormalizeEncoding(e.target.checked)}
+          />
+        }
+        label="Normalize encoding"
+      />
+      <Typography variant="caption" color="textSecondary" display="block" sx={{ ml: 4, mt: -1, mb: 2 }}>
+        Convert UTF-16 and latin-1 files to UTF-8, strip the byte order mark and CRLF line endings
+      </Typography>
+
+      <FormControlLabel
+        control={
+          <Checkbox
+            checked={stageUpload}
+            onChange={(e) => setStageUpload(e.target.checked)}
+          />
+        }
+        label="Review before loading"
+      />
+      <Typography variant="caption" color="textSecondary" display="block" sx={{ ml: 4, mt: -1, mb: 2 }}>
+        Stage the upload and show its valid, invalid and duplicate lines before any hashes are loaded
+      </Typography>
+
+      {detectedGroups && detectedGroups.length > 1 && (
+        <Box sx={{ mt: 2, p: 2, border: 1, borderColor: 'divider', borderRadius: 1 }}>
+          <Typography variant="subtitle2" gutterBottom>
+            This upload contains {detectedGroups.length} hash types
+          </Typography>
+          {detectedGroups.map((group) => (
+            <Typography key={group.hash_type_id} variant="body2">
+              {group.hash_type_id} - {group.hash_type_name || 'Unknown'}: {group.lines} line{group.lines !== 1 ? 's' : ''}
+              {!group.detected && ' (chosen hash type)'}
+            </Typography>
+          ))}
+          <FormControlLabel
+            control={
+              <Checkbox
+                checked={splitByType}
+                onChange={(e) => setSplitByType(e.target.checked)}
+              />
+            }
+            label="Split into one hashlist per hash type"
+            sx={{ mt: 1 }}
+          />
+        </Box>
+      )}
+
       <Button
         type="submit"
         variant="contained"
-        disabled={uploadMutation.isPending || !hasValidInput}
+        disabled={uploadMutation.isPending || detectMutation.isPending || !hasValidInput}
         sx={{ mt: 2 }}
       >
-        {uploadMutation.isPending ? 'Uploading...' : 'Upload Hashlist'}
+        {uploadMutation.isPending ? 'Uploading...' : detectMutation.isPending ? 'Checking hash types...' : 'Upload Hashlist'}
       </Button>
 
       {uploadMutation.isError && (
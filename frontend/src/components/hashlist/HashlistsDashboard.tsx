@@ -47,13 +47,13 @@ import { format, parse, isValid, parseISO } from 'date-fns'; // Import parse and
 type OrderBy = 'name' | 'clientName' | 'status' | 'createdAt';
 
 // Define Hashlist Status type/enum if not already globally defined
-type HashlistStatus = 'uploading' | 'processing' | 'ready' | 'error';
-const allStatuses: HashlistStatus[] = ['uploading', 'processing', 'ready', 'error'];
+type HashlistStatus = 'uploading' | 'staged' | 'processing' | 'ready' | 'ready_with_errors' | 'error';
+const allStatuses: HashlistStatus[] = ['uploading', 'staged', 'processing', 'ready', 'ready_with_errors', 'error'];
 
 interface Hashlist {
   id: string;
   name: string;
-  status: 'uploading' | 'processing' | 'ready' | 'error';
+  status: HashlistStatus;
   total_hashes: number;
   cracked_hashes: number;
   createdAt: string;
@@ -286,7 +286,7 @@ export default function HashlistsDashboard({ uploadDialogOpen, setUploadDialogOp
               >
                 <MenuItem value=""><em>All</em></MenuItem>
                 {allStatuses.map(status => (
-                  <MenuItem key={status} value={status}>{status.charAt(0).toUpperCase() + status.slice(1)}</MenuItem>
+                  <MenuItem key={status} value={status}>{status.charAt(0).toUpperCase() + status.slice(1).replace(/_/g, ' ')}</MenuItem>
                 ))}
               </Select>
             </FormControl>
@@ -400,7 +400,9 @@ export default function HashlistsDashboard({ uploadDialogOpen, setUploadDialogOp
                     label={hashlist.status}
                     color={
                       hashlist.status === 'ready' ? 'success' :
+                      hashlist.status === 'ready_with_errors' ? 'warning' :
                       hashlist.status === 'error' ? 'error' :
+                      hashlist.status === 'staged' ? 'warning' :
                       'primary'  
                     }
                   />
@@ -130,7 +130,7 @@ export default function PotTable({ title, fetchData, filterParam, filterValue, c
     enqueueSnackbar('Copied to clipboard', { variant: 'success' });
   };
 
-  const downloadFormat = async (format: 'hash-pass' | 'user-pass' | 'user' | 'pass') => {
+  const downloadFormat = async (format: 'hash-pass' | 'user-pass' | 'user' | 'pass' | 'potfile') => {
     try {
       setDownloadingFormat(format);
       
@@ -292,6 +292,21 @@ export default function PotTable({ title, fetchData, filterParam, filterValue, c
           >
             Password
           </Button>
+          {contextType === 'client' && (
+            <Tooltip title="Deduplicated hash:plain potfile of all the client's hashlists">
+              <span>
+                <Button
+                  size="small"
+                  variant="outlined"
+                  startIcon={<DownloadIcon />}
+                  onClick={() => downloadFormat('potfile')}
+                  disabled={downloadingFormat !== null}
+                >
+                  Potfile
+                </Button>
+              </span>
+            </Tooltip>
+          )}
         </Box>
         
         <TableContainer>
@@ -8,13 +8,17 @@ import {
   FormControlLabel,
   Alert,
   CircularProgress,
+  FormControl,
+  InputLabel,
+  Select,
+  MenuItem,
 } from '@mui/material';
 import {
   Email as EmailIcon,
   Warning as WarningIcon,
 } from '@mui/icons-material';
 import { getNotificationPreferences, updateNotificationPreferences } from '../../services/user';
-import { NotificationPreferences } from '../../types/user';
+import { NotificationDigest, NotificationPreferences } from '../../types/user';
 
 interface NotificationCardProps {
   onNotificationChange?: () => void;
@@ -47,22 +51,38 @@ const NotificationCard: React.FC<NotificationCardProps> = ({ onNotificationChang
   const handleToggleNotifications = async () => {
     if (!preferences) return;
 
+    // Check if email is configured
+    if (!preferences.emailConfigured && !preferences.notifyOnJobCompletion) {
+      setError('Email gateway must be configured before enabling email notifications. Please contact your administrator.');
+      return;
+    }
+
+    await savePreferences({
+      ...preferences,
+      notifyOnJobCompletion: !preferences.notifyOnJobCompletion,
+    });
+  };
+
+  const handleDigestChange = async (digest: NotificationDigest) => {
+    if (!preferences) return;
+
+    if (!preferences.emailConfigured && digest !== 'off') {
+      setError('Email gateway must be configured before enabling email notifications. Please contact your administrator.');
+      return;
+    }
+
+    await savePreferences({
+      ...preferences,
+      notificationDigest: digest,
+    });
+  };
+
+  const savePreferences = async (updatedPrefs: NotificationPreferences) => {
     try {
       setSaving(true);
       setError(null);
       setSuccess(null);
 
-      // Check if email is configured
-      if (!preferences.emailConfigured && !preferences.notifyOnJobCompletion) {
-        setError('Email gateway must be configured before enabling email notifications. Please contact your administrator.');
-        return;
-      }
-
-      const updatedPrefs: NotificationPreferences = {
-        ...preferences,
-        notifyOnJobCompletion: !preferences.notifyOnJobCompletion,
-      };
-
       const result = await updateNotificationPreferences(updatedPrefs);
       setPreferences(result);
       setSuccess('Notification preferences updated successfully');
@@ -154,6 +174,25 @@ const NotificationCard: React.FC<NotificationCardProps> = ({ onNotificationChang
           />
         </Box>
 
+        <Box sx={{ mb: 3 }}>
+          <FormControl size="small" sx={{ minWidth: 200 }} disabled={!preferences.emailConfigured || saving}>
+            <InputLabel id="notification-digest-label">Digest</InputLabel>
+            <Select
+              labelId="notification-digest-label"
+              label="Digest"
+              value={preferences.notificationDigest || 'off'}
+              onChange={(e) => handleDigestChange(e.target.value as NotificationDigest)}
+            >
+              <MenuItem value="off">Off</MenuItem>
+              <MenuItem value="hourly">Hourly</MenuItem>
+              <MenuItem value="daily">Daily</MenuItem>
+            </Select>
+          </FormControl>
+          <Typography variant="body2" color="text.secondary" sx={{ mt: 1 }}>
+            Summarize completed jobs, new cracks and incidents on your agents in one email instead of an email per job
+          </Typography>
+        </Box>
+
         {preferences.notifyOnJobCompletion && !preferences.emailConfigured && (
           <Typography variant="body2" color="text.secondary" sx={{ mt: 1 }}>
             Note: Notifications are enabled but will not be sent until an email gateway is configured.
@@ -0,0 +1,50 @@
+import { useEffect, useRef, useState } from 'react';
+import { API_URL, getJobStreamURL, isSSESupported } from '../services/api';
+import { JobProgressMessage } from '../types/jobs';
+
+/**
+ * Streams the progress of jobs over server-sent events.
+ * @param jobIds The jobs to follow, the stream is reopened when they change.
+ * @param onMessage Called with what changed in the jobs, at most once a second.
+ * @returns Whether the stream is connected, callers can poll less while it is.
+ */
+export default function useJobProgressStream(
+  jobIds: string[],
+  onMessage: (message: JobProgressMessage) => void
+): boolean {
+  const [connected, setConnected] = useState(false);
+  const onMessageRef = useRef(onMessage);
+  onMessageRef.current = onMessage;
+
+  // Sorted so a reordered list of the same jobs keeps the stream open
+  const key = [...jobIds].sort().join(',');
+
+  useEffect(() => {
+    if (!key || !isSSESupported()) {
+      setConnected(false);
+      return;
+    }
+
+    const source = new EventSource(
+      `${API_URL}${getJobStreamURL()}?job_ids=${encodeURIComponent(key)}`,
+      { withCredentials: true }
+    );
+    source.addEventListener('ready', () => setConnected(true));
+    source.addEventListener('progress', (event) => {
+      try {
+        onMessageRef.current(JSON.parse((event as MessageEvent).data));
+      } catch (err) {
+        console.error('Failed to parse job progress:', err);
+      }
+    });
+    // EventSource reconnects by itself, polling takes over until it does
+    source.onerror = () => setConnected(false);
+
+    return () => {
+      source.close();
+      setConnected(false);
+    };
+  }, [key]);
+
+  return connected;
+}
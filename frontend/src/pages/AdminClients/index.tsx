@@ -1,18 +1,24 @@
 import React, { useState, useEffect, useCallback } from 'react';
 import {
     Box, Typography, Button, Paper, CircularProgress, Alert,
-    Dialog, DialogActions, DialogContent, DialogContentText, DialogTitle, TextField, FormControlLabel, Checkbox
+    Dialog, DialogActions, DialogContent, DialogContentText, DialogTitle, TextField, FormControlLabel, Checkbox,
+    Chip, MenuItem, IconButton, Divider
 } from '@mui/material';
 import { DataGrid, GridColDef, GridRowParams, GridActionsCellItem } from '@mui/x-data-grid';
 import AddIcon from '@mui/icons-material/Add';
 import EditIcon from '@mui/icons-material/Edit';
 import DeleteIcon from '@mui/icons-material/Delete';
+import RemoveCircleOutlineIcon from '@mui/icons-material/RemoveCircleOutline';
 import { useSnackbar } from 'notistack';
 import { useNavigate } from 'react-router-dom';
 
-import { Client } from '../../types/client';
+import { Client, ClientContact } from '../../types/client';
 import { listClients, createClient, updateClient, deleteClient, getDefaultClientRetentionSetting } from '../../services/api';
 
+// Date inputs work with YYYY-MM-DD while the API sends and expects ISO timestamps
+const toDateInput = (value?: string): string => (value ? value.slice(0, 10) : '');
+const fromDateInput = (value?: string): string | undefined => (value ? `${value.slice(0, 10)}T00:00:00Z` : undefined);
+
 export const AdminClients: React.FC = () => {
     const [clients, setClients] = useState<Client[]>([]);
     const [loading, setLoading] = useState<boolean>(true);
@@ -84,6 +90,16 @@ export const AdminClients: React.FC = () => {
         { field: 'name', headerName: 'Name', flex: 1, minWidth: 150 },
         { field: 'description', headerName: 'Description', flex: 2, minWidth: 200 },
         { field: 'contactInfo', headerName: 'Contact', flex: 1, minWidth: 150 },
+        {
+            field: 'engagementStatus',
+            headerName: 'Engagement',
+            width: 120,
+            renderCell: (params) => (
+                params.value === 'closed'
+                    ? <Chip label="Closed" size="small" />
+                    : <Chip label="Active" size="small" color="success" />
+            ),
+        },
         {
             field: 'cracked_count',
             headerName: 'Cracked',
@@ -157,7 +173,12 @@ export const AdminClients: React.FC = () => {
           description: '',
           contactInfo: '',
           dataRetentionMonths: defaultRetention ? parseInt(defaultRetention, 10) : null,
-          exclude_from_potfile: false
+          exclude_from_potfile: false,
+          contacts: [],
+          engagementStatus: 'active',
+          engagementStartDate: '',
+          engagementEndDate: '',
+          scopeNotes: ''
         });
         setIsAddEditDialogOpen(true);
     };
@@ -169,7 +190,12 @@ export const AdminClients: React.FC = () => {
             description: client.description || '',
             contactInfo: client.contactInfo || '',
             dataRetentionMonths: client.dataRetentionMonths === undefined ? null : client.dataRetentionMonths,
-            exclude_from_potfile: client.exclude_from_potfile || false
+            exclude_from_potfile: client.exclude_from_potfile || false,
+            contacts: (client.contacts || []).map(contact => ({ ...contact })),
+            engagementStatus: client.engagementStatus || 'active',
+            engagementStartDate: toDateInput(client.engagementStartDate),
+            engagementEndDate: toDateInput(client.engagementEndDate),
+            scopeNotes: client.scopeNotes || ''
         });
         setFormError(null);
         setIsAddEditDialogOpen(true);
@@ -195,6 +221,32 @@ export const AdminClients: React.FC = () => {
         }));
     };
 
+    const handleContactChange = (index: number, field: keyof ClientContact, value: string | boolean) => {
+        setClientFormData(prev => ({
+            ...prev,
+            contacts: (prev.contacts || []).map((contact, i) => {
+                if (i === index) return { ...contact, [field]: value };
+                // Only one contact can be primary
+                if (field === 'primary' && value === true) return { ...contact, primary: false };
+                return contact;
+            })
+        }));
+    };
+
+    const handleAddContact = () => {
+        setClientFormData(prev => ({
+            ...prev,
+            contacts: [...(prev.contacts || []), { name: '', role: '', email: '', phone: '', primary: (prev.contacts || []).length === 0 }]
+        }));
+    };
+
+    const handleRemoveContact = (index: number) => {
+        setClientFormData(prev => ({
+            ...prev,
+            contacts: (prev.contacts || []).filter((_, i) => i !== index)
+        }));
+    };
+
     const handleSaveClient = async () => {
         setFormError(null);
         setIsSaving(true);
@@ -210,13 +262,29 @@ export const AdminClients: React.FC = () => {
             setIsSaving(false);
             return;
         }
+        if (clientFormData.contacts?.some(contact => !contact.name.trim())) {
+            setFormError('Every contact needs a name.');
+            setIsSaving(false);
+            return;
+        }
+        const { engagementStartDate, engagementEndDate } = clientFormData;
+        if (engagementStartDate && engagementEndDate && engagementEndDate < engagementStartDate) {
+            setFormError('Engagement end date cannot be before its start date.');
+            setIsSaving(false);
+            return;
+        }
 
         const payload: Partial<Client> = {
             name: clientFormData.name,
             description: clientFormData.description || undefined,
             contactInfo: clientFormData.contactInfo || undefined,
             dataRetentionMonths: clientFormData.dataRetentionMonths,
-            exclude_from_potfile: clientFormData.exclude_from_potfile
+            exclude_from_potfile: clientFormData.exclude_from_potfile,
+            contacts: clientFormData.contacts || [],
+            engagementStatus: clientFormData.engagementStatus || 'active',
+            engagementStartDate: fromDateInput(engagementStartDate),
+            engagementEndDate: fromDateInput(engagementEndDate),
+            scopeNotes: clientFormData.scopeNotes || undefined
         };
 
         try {
@@ -363,6 +431,113 @@ export const AdminClients: React.FC = () => {
                     <Typography variant="caption" color="textSecondary" display="block" sx={{ ml: 4, mt: -1, mb: 2 }}>
                         Enable this for clients with strict data retention requirements
                     </Typography>
+
+                    <Divider sx={{ my: 2 }} />
+                    <Typography variant="subtitle1" gutterBottom>Engagement</Typography>
+                    <TextField
+                        select
+                        margin="dense"
+                        name="engagementStatus"
+                        label="Status"
+                        fullWidth
+                        variant="outlined"
+                        value={clientFormData.engagementStatus || 'active'}
+                        onChange={handleFormChange}
+                        helperText="New jobs cannot be created for a closed engagement, and retention counts from when it closed."
+                    >
+                        <MenuItem value="active">Active</MenuItem>
+                        <MenuItem value="closed">Closed</MenuItem>
+                    </TextField>
+                    <Box sx={{ display: 'flex', gap: 2 }}>
+                        <TextField
+                            margin="dense"
+                            name="engagementStartDate"
+                            label="Start Date"
+                            type="date"
+                            fullWidth
+                            variant="outlined"
+                            value={clientFormData.engagementStartDate || ''}
+                            onChange={handleFormChange}
+                            InputLabelProps={{ shrink: true }}
+                        />
+                        <TextField
+                            margin="dense"
+                            name="engagementEndDate"
+                            label="End Date"
+                            type="date"
+                            fullWidth
+                            variant="outlined"
+                            value={clientFormData.engagementEndDate || ''}
+                            onChange={handleFormChange}
+                            InputLabelProps={{ shrink: true }}
+                        />
+                    </Box>
+                    <TextField
+                        margin="dense"
+                        name="scopeNotes"
+                        label="Scope Notes"
+                        type="text"
+                        fullWidth
+                        multiline
+                        rows={3}
+                        variant="outlined"
+                        value={clientFormData.scopeNotes || ''}
+                        onChange={handleFormChange}
+                    />
+
+                    <Box sx={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center', mt: 2 }}>
+                        <Typography variant="subtitle1">Contacts</Typography>
+                        <Button size="small" startIcon={<AddIcon />} onClick={handleAddContact}>
+                            Add Contact
+                        </Button>
+                    </Box>
+                    {(clientFormData.contacts || []).map((contact, index) => (
+                        <Box key={index} sx={{ display: 'flex', flexWrap: 'wrap', gap: 1, alignItems: 'center', mb: 1 }}>
+                            <TextField
+                                size="small"
+                                label="Name"
+                                required
+                                value={contact.name}
+                                onChange={(e) => handleContactChange(index, 'name', e.target.value)}
+                                sx={{ flex: '1 1 40%' }}
+                            />
+                            <TextField
+                                size="small"
+                                label="Role"
+                                value={contact.role || ''}
+                                onChange={(e) => handleContactChange(index, 'role', e.target.value)}
+                                sx={{ flex: '1 1 40%' }}
+                            />
+                            <TextField
+                                size="small"
+                                label="Email"
+                                type="email"
+                                value={contact.email || ''}
+                                onChange={(e) => handleContactChange(index, 'email', e.target.value)}
+                                sx={{ flex: '1 1 40%' }}
+                            />
+                            <TextField
+                                size="small"
+                                label="Phone"
+                                value={contact.phone || ''}
+                                onChange={(e) => handleContactChange(index, 'phone', e.target.value)}
+                                sx={{ flex: '1 1 40%' }}
+                            />
+                            <FormControlLabel
+                                control={
+                                    <Checkbox
+                                        size="small"
+                                        checked={contact.primary}
+                                        onChange={(e) => handleContactChange(index, 'primary', e.target.checked)}
+                                    />
+                                }
+                                label="Primary"
+                            />
+                            <IconButton size="small" onClick={() => handleRemoveContact(index)} title="Remove contact">
+                                <RemoveCircleOutlineIcon fontSize="small" />
+                            </IconButton>
+                        </Box>
+                    ))}
                 </DialogContent>
                 <DialogActions>
                     <Button onClick={handleCloseDialog} disabled={isSaving}>Cancel</Button>
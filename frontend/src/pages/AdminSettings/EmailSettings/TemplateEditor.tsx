@@ -48,7 +48,7 @@ interface TemplateEditorProps {
 
 interface Template {
   id?: number;
-  templateType: 'security_event' | 'job_completion' | 'admin_error' | 'mfa_code';
+  templateType: 'security_event' | 'job_completion' | 'admin_error' | 'mfa_code' | 'notification_digest';
   name: string;
   subject: string;
   htmlContent: string;
@@ -294,6 +294,7 @@ export const TemplateEditor: React.FC<TemplateEditorProps> = ({ onNotification }
                 <MenuItem value="job_completion">Job Completion</MenuItem>
                 <MenuItem value="admin_error">Admin Error</MenuItem>
                 <MenuItem value="mfa_code">MFA Code</MenuItem>
+                <MenuItem value="notification_digest">Notification Digest</MenuItem>
               </Select>
             </FormControl>
           </Grid>
@@ -10,6 +10,8 @@ import { HashTypeManager } from '../../components/admin/HashTypeManager';
 import JobExecutionSettings from '../../components/admin/JobExecutionSettings';
 import MonitoringSettings from '../../components/admin/MonitoringSettings';
 import AgentDownloadSettings from '../../components/admin/AgentDownloadSettings';
+import MaintenanceSettings from '../../components/admin/MaintenanceSettings';
+import SettingsPresets from '../../components/admin/SettingsPresets';
 import { useSnackbar } from 'notistack';
 import { updateAuthSettings } from '../../services/auth';
 import { getDefaultClientRetentionSetting, updateDefaultClientRetentionSetting } from '../../services/api';
@@ -158,10 +160,12 @@ export const AdminSettings = () => {
   const [currentTab, setCurrentTab] = useState(() => {
     const savedTab = localStorage.getItem('adminSettingsTab');
     const initialTab = savedTab ? parseInt(savedTab, 10) : 0;
-    return initialTab >= 0 && initialTab < 9 ? initialTab : 0;
+    return initialTab >= 0 && initialTab < 10 ? initialTab : 0;
   });
   
   const [loading, setLoading] = useState(false);
+  // Bumped when a preset is applied to reload the job execution settings form
+  const [jobSettingsVersion, setJobSettingsVersion] = useState(0);
   const { userRole } = useAuth();
   const { enqueueSnackbar } = useSnackbar();
 
@@ -197,6 +201,7 @@ export const AdminSettings = () => {
             <Tab label="Job Execution" />
             <Tab label="Monitoring" />
             <Tab label="Agent Downloads" />
+            <Tab label="Maintenance" />
           </Tabs>
         </Box>
 
@@ -234,7 +239,8 @@ export const AdminSettings = () => {
           <HashTypeManager />
         </TabPanel>
         <TabPanel value={currentTab} index={6}>
-          <JobExecutionSettings />
+          <SettingsPresets onApplied={() => setJobSettingsVersion(v => v + 1)} />
+          <JobExecutionSettings key={jobSettingsVersion} />
         </TabPanel>
         <TabPanel value={currentTab} index={7}>
           <MonitoringSettings />
@@ -242,6 +248,9 @@ export const AdminSettings = () => {
         <TabPanel value={currentTab} index={8}>
           <AgentDownloadSettings />
         </TabPanel>
+        <TabPanel value={currentTab} index={9}>
+          <MaintenanceSettings />
+        </TabPanel>
       </Paper>
     </Box>
   );
@@ -52,9 +52,13 @@ import {
   getAgentSchedules, 
   toggleAgentScheduling, 
   bulkUpdateAgentSchedules, 
-  deleteAgentSchedule 
+  deleteAgentSchedule,
+  updateAgentManagedConfig,
+  startAgentFileAudit,
+  getAgentFileAudit,
 } from '../services/api';
 import { AgentSchedule, AgentScheduleDTO } from '../types/scheduling';
+import { AgentManagedConfig, AgentFileAudit, AgentFileAuditItem } from '../types/agent';
 
 interface Agent {
   id: number;
@@ -83,8 +87,16 @@ interface Agent {
   ownerId?: string;
   extraParameters?: string;
   isEnabled?: boolean;
+  managedConfig?: AgentManagedConfig;
 }
 
+// Managed settings fields, edited as text so blank means "use the default"
+const managedConfigFields: { key: keyof AgentManagedConfig; label: string; helper: string; max: number }[] = [
+  { key: 'heartbeat_interval_seconds', label: 'Heartbeat interval (seconds)', helper: 'Blank uses the server default', max: 300 },
+  { key: 'max_concurrent_downloads', label: 'Concurrent downloads', helper: 'Blank uses the global download setting', max: 20 },
+  { key: 'file_retention_days', label: 'File retention (days)', helper: 'Hashlists and rule chunks unused this long are removed; blank keeps the agent default of 3', max: 365 },
+];
+
 interface AgentDevice {
   id: number;
   agent_id: number;
@@ -92,6 +104,7 @@ interface AgentDevice {
   device_name: string;
   device_type: string;
   enabled: boolean;
+  memory_total_mb?: number;
 }
 
 interface User {
@@ -136,6 +149,10 @@ const AgentDetails: React.FC = () => {
   const [isEnabled, setIsEnabled] = useState(true);
   const [ownerId, setOwnerId] = useState('');
   const [extraParameters, setExtraParameters] = useState('');
+  const [managedConfig, setManagedConfig] = useState<{ [key: string]: string }>({});
+  const [managedConfigSaving, setManagedConfigSaving] = useState(false);
+  const [fileAudit, setFileAudit] = useState<AgentFileAudit | null>(null);
+  const [fileAuditStarting, setFileAuditStarting] = useState(false);
   const [deviceStates, setDeviceStates] = useState<{ [key: number]: boolean }>({});
   
   // Scheduling state
@@ -192,6 +209,10 @@ const AgentDetails: React.FC = () => {
       setIsEnabled(agentData.isEnabled !== undefined ? agentData.isEnabled : true);
       setOwnerId(agentData.ownerId || '');
       setExtraParameters(agentData.extraParameters || '');
+      const config: AgentManagedConfig = agentData.managedConfig || {};
+      setManagedConfig(Object.fromEntries(
+        managedConfigFields.map(({ key }) => [key, config[key] !== undefined ? String(config[key]) : ''])
+      ));
       
       // Initialize device states using device_id as the key
       const initialDeviceStates: { [key: number]: boolean } = {};
@@ -200,6 +221,12 @@ const AgentDetails: React.FC = () => {
       });
       setDeviceStates(initialDeviceStates);
       
+      try {
+        setFileAudit(await getAgentFileAudit(agentData.id));
+      } catch (err) {
+        console.error('Failed to fetch agent file audit:', err);
+      }
+
       // Fetch scheduling information
       try {
         const schedulingInfo = await getAgentSchedules(agentData.id);
@@ -434,6 +461,64 @@ const AgentDetails: React.FC = () => {
     }, 1000); // 1 second debounce
   };
 
+  // Save the managed settings, the agent applies them at once if connected
+  const handleSaveManagedConfig = async () => {
+    const config: AgentManagedConfig = {};
+    for (const { key, label, max } of managedConfigFields) {
+      const value = (managedConfig[key] || '').trim();
+      if (value === '') continue;
+      const parsed = parseInt(value, 10);
+      if (isNaN(parsed) || parsed < 1 || parsed > max) {
+        setError(`${label} must be between 1 and ${max}`);
+        return;
+      }
+      config[key] = parsed;
+    }
+
+    setManagedConfigSaving(true);
+    try {
+      await updateAgentManagedConfig(agent!.id, config);
+      setSuccess('Managed settings saved and sent to the agent');
+      setTimeout(() => setSuccess(''), 3000);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to update managed settings');
+    } finally {
+      setManagedConfigSaving(false);
+    }
+  };
+
+  // Start a file integrity audit, the report is polled until the agent answers
+  const handleStartFileAudit = async () => {
+    setFileAuditStarting(true);
+    try {
+      setFileAudit(await startAgentFileAudit(agent!.id));
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to start file audit');
+    } finally {
+      setFileAuditStarting(false);
+    }
+  };
+
+  useEffect(() => {
+    if (!agent || fileAudit?.status !== 'scanning') return;
+    const timer = setTimeout(async () => {
+      try {
+        setFileAudit(await getAgentFileAudit(agent.id));
+      } catch (err) {
+        console.error('Failed to refresh agent file audit:', err);
+      }
+    }, 3000);
+    return () => clearTimeout(timer);
+  }, [agent, fileAudit]);
+
+  const fileAuditItems: { kind: string; item: AgentFileAuditItem }[] = fileAudit
+    ? [
+        ...(fileAudit.mismatched || []).map((item) => ({ kind: 'Mismatched', item })),
+        ...(fileAudit.missing || []).map((item) => ({ kind: 'Missing', item })),
+        ...(fileAudit.extra || []).map((item) => ({ kind: 'Extra', item })),
+      ]
+    : [];
+
   if (loading) {
     return (
       <Box sx={{ p: 3, display: 'flex', justifyContent: 'center', alignItems: 'center', height: '50vh' }}>
@@ -573,6 +658,7 @@ const AgentDetails: React.FC = () => {
                       <TableCell>Device ID</TableCell>
                       <TableCell>Type</TableCell>
                       <TableCell>Name</TableCell>
+                      <TableCell>Memory</TableCell>
                       <TableCell>Enabled</TableCell>
                     </TableRow>
                   </TableHead>
@@ -582,6 +668,9 @@ const AgentDetails: React.FC = () => {
                         <TableCell>{device.device_id}</TableCell>
                         <TableCell>{device.device_type}</TableCell>
                         <TableCell>{device.device_name}</TableCell>
+                        <TableCell>
+                          {device.memory_total_mb ? `${device.memory_total_mb.toLocaleString()} MB` : '-'}
+                        </TableCell>
                         <TableCell>
                           <Switch
                             checked={deviceStates[device.device_id] || false}
@@ -620,6 +709,109 @@ const AgentDetails: React.FC = () => {
           </Paper>
         </Grid>
 
+        {/* Managed Settings */}
+        <Grid item xs={12}>
+          <Paper sx={{ p: 3 }}>
+            <Typography variant="h6" gutterBottom>Managed Settings</Typography>
+            <Typography variant="body2" color="text.secondary" gutterBottom>
+              Settings pushed to the agent, replacing the values in its .env file. A connected agent applies them immediately.
+            </Typography>
+
+            <Grid container spacing={2} sx={{ mt: 1 }}>
+              {managedConfigFields.map(({ key, label, helper, max }) => (
+                <Grid item xs={12} md={4} key={key}>
+                  <TextField
+                    fullWidth
+                    type="number"
+                    label={label}
+                    helperText={helper}
+                    value={managedConfig[key] || ''}
+                    onChange={(e) => setManagedConfig({ ...managedConfig, [key]: e.target.value })}
+                    inputProps={{ min: 1, max }}
+                  />
+                </Grid>
+              ))}
+            </Grid>
+            <Box sx={{ mt: 2, display: 'flex', justifyContent: 'flex-end' }}>
+              <Button
+                variant="contained"
+                onClick={handleSaveManagedConfig}
+                disabled={managedConfigSaving}
+                startIcon={managedConfigSaving ? <CircularProgress size={16} /> : undefined}
+              >
+                Save
+              </Button>
+            </Box>
+          </Paper>
+        </Grid>
+
+        {/* File Audit */}
+        <Grid item xs={12}>
+          <Paper sx={{ p: 3 }}>
+            <Typography variant="h6" gutterBottom>File Integrity Audit</Typography>
+            <Typography variant="body2" color="text.secondary" gutterBottom>
+              The agent rehashes every cached wordlist, rule and binary. Mismatched and missing files are downloaded again, extra wordlists and rules are deleted.
+            </Typography>
+
+            {fileAudit && (
+              <Box sx={{ mt: 2 }}>
+                <Box sx={{ display: 'flex', gap: 1, alignItems: 'center', flexWrap: 'wrap' }}>
+                  <Chip
+                    size="small"
+                    label={fileAudit.status}
+                    color={fileAudit.status === 'completed' ? 'success' : fileAudit.status === 'failed' ? 'error' : 'default'}
+                  />
+                  <Typography variant="body2">
+                    Started {formatDistanceToNow(new Date(fileAudit.started_at), { addSuffix: true })}
+                  </Typography>
+                  {fileAudit.status === 'completed' && (
+                    <Typography variant="body2">
+                      {fileAudit.files_checked} files checked, {fileAudit.downloads} downloads and {fileAudit.deletions} deletions issued
+                    </Typography>
+                  )}
+                </Box>
+                {fileAudit.error && <Alert severity="error" sx={{ mt: 1 }}>{fileAudit.error}</Alert>}
+                {fileAudit.status === 'completed' && fileAuditItems.length === 0 && (
+                  <Alert severity="success" sx={{ mt: 1 }}>All cached files match the backend</Alert>
+                )}
+                {fileAuditItems.length > 0 && (
+                  <TableContainer sx={{ mt: 1, maxHeight: 300 }}>
+                    <Table size="small" stickyHeader>
+                      <TableHead>
+                        <TableRow>
+                          <TableCell>Finding</TableCell>
+                          <TableCell>Type</TableCell>
+                          <TableCell>File</TableCell>
+                        </TableRow>
+                      </TableHead>
+                      <TableBody>
+                        {fileAuditItems.map(({ kind, item }) => (
+                          <TableRow key={`${kind}:${item.file_type}:${item.name}`}>
+                            <TableCell>{kind}</TableCell>
+                            <TableCell>{item.file_type}</TableCell>
+                            <TableCell>{item.name}</TableCell>
+                          </TableRow>
+                        ))}
+                      </TableBody>
+                    </Table>
+                  </TableContainer>
+                )}
+              </Box>
+            )}
+
+            <Box sx={{ mt: 2, display: 'flex', justifyContent: 'flex-end' }}>
+              <Button
+                variant="outlined"
+                onClick={handleStartFileAudit}
+                disabled={fileAuditStarting || fileAudit?.status === 'scanning'}
+                startIcon={fileAuditStarting || fileAudit?.status === 'scanning' ? <CircularProgress size={16} /> : undefined}
+              >
+                Audit Files
+              </Button>
+            </Box>
+          </Paper>
+        </Grid>
+
         {/* Scheduling */}
         <Grid item xs={12}>
           <AgentScheduling
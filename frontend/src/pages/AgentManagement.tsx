@@ -34,6 +34,7 @@ import {
   CircularProgress,
   Alert,
   Link,
+  Tooltip,
 } from '@mui/material';
 import {
   Delete as DeleteIcon,
@@ -188,6 +189,12 @@ export default function AgentManagement() {
     return busyStatus === 'true' && !currentTaskId;
   };
 
+  // Names of the devices running persistently below their benchmark speed
+  const slowDevices = (agent: Agent): string[] =>
+    (agent.performance?.devices ?? [])
+      .filter((device) => device.underperforming)
+      .map((device) => device.deviceName || `Device ${device.deviceId}`);
+
   if (loading) {
     return (
       <Box sx={{ p: 3, display: 'flex', justifyContent: 'center', alignItems: 'center', height: '50vh' }}>
@@ -264,6 +271,15 @@ export default function AgentManagement() {
                         label={voucher.is_continuous ? "Continuous" : "Single Use"}
                         color={voucher.is_continuous ? "primary" : "default"}
                       />
+                      {voucher.max_uses && (
+                        <Chip label={`${voucher.use_count} / ${voucher.max_uses} used`} size="small" sx={{ ml: 1 }} />
+                      )}
+                      {voucher.expires_at && (
+                        <Chip label={`Expires ${new Date(voucher.expires_at).toLocaleString()}`} size="small" sx={{ ml: 1 }} />
+                      )}
+                      {voucher.labels?.map((label) => (
+                        <Chip key={label} label={label} size="small" variant="outlined" sx={{ ml: 1 }} />
+                      ))}
                     </TableCell>
                     <TableCell>
                       <IconButton
@@ -356,6 +372,15 @@ export default function AgentManagement() {
                         color={agent.status === 'active' ? 'success' : agent.status === 'error' ? 'error' : 'default'}
                         size="small"
                       />
+                      {agent.performance?.underperforming && (
+                        <Tooltip
+                          title={`Recent tasks ran at ${Math.round(agent.performance.score * 100)}% of benchmark speed${
+                            slowDevices(agent).length > 0 ? ` (slow: ${slowDevices(agent).join(', ')})` : ''
+                          }`}
+                        >
+                          <Chip label="Below benchmark" color="warning" size="small" sx={{ ml: 1 }} />
+                        </Tooltip>
+                      )}
                     </TableCell>
                     <TableCell>
                       {isAgentStuck(agent) && (
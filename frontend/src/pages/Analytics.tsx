@@ -36,6 +36,8 @@ import {
   LinearProgress,
   AlertTitle,
   Tooltip,
+  FormControlLabel,
+  Checkbox,
 } from '@mui/material';
 import {
   Add as AddIcon,
@@ -66,6 +68,10 @@ export default function Analytics() {
   const [startDate, setStartDate] = useState<string>(thirtyDaysAgo.toISOString().slice(0, 16));
   const [endDate, setEndDate] = useState<string>(new Date().toISOString().slice(0, 16));
   const [customPatterns, setCustomPatterns] = useState<string>('');
+  const [checkPolicy, setCheckPolicy] = useState(false);
+  const [policyMinLength, setPolicyMinLength] = useState<number>(12);
+  const [policyMinClasses, setPolicyMinClasses] = useState<number>(3);
+  const [policyDictionaryWords, setPolicyDictionaryWords] = useState<string>('');
   const [loading, setLoading] = useState(false);
   const [clientReports, setClientReports] = useState<AnalyticsReport[]>([]);
   const [currentReport, setCurrentReport] = useState<AnalyticsReport | null>(null);
@@ -201,6 +207,20 @@ export default function Analytics() {
         custom_patterns: patterns,
       };
 
+      if (checkPolicy) {
+        request.password_policy = {
+          min_length: policyMinLength,
+          min_character_classes: policyMinClasses,
+          require_lowercase: false,
+          require_uppercase: false,
+          require_numbers: false,
+          require_special: false,
+          disallow_dictionary_words: true,
+          dictionary_words: policyDictionaryWords.split(',').map(w => w.trim()).filter(w => w),
+          disallow_username: true,
+        };
+      }
+
       const report = await analyticsService.createReport(request);
       setCurrentReport(report);
       setReportStatus('queued');
@@ -374,6 +394,47 @@ export default function Analytics() {
                       helperText="Add custom organization name variations to check"
                     />
                   </Grid>
+                  <Grid item xs={12}>
+                    <FormControlLabel
+                      control={<Checkbox checked={checkPolicy} onChange={(e) => setCheckPolicy(e.target.checked)} />}
+                      label="Check cracked passwords against the client's password policy"
+                    />
+                  </Grid>
+                  {checkPolicy && (
+                    <>
+                      <Grid item xs={12} md={3}>
+                        <TextField
+                          fullWidth
+                          label="Minimum Length"
+                          type="number"
+                          value={policyMinLength}
+                          onChange={(e) => setPolicyMinLength(Math.max(0, parseInt(e.target.value, 10) || 0))}
+                          inputProps={{ min: 0, max: 256 }}
+                        />
+                      </Grid>
+                      <Grid item xs={12} md={3}>
+                        <TextField
+                          fullWidth
+                          label="Character Classes"
+                          type="number"
+                          value={policyMinClasses}
+                          onChange={(e) => setPolicyMinClasses(Math.min(4, Math.max(0, parseInt(e.target.value, 10) || 0)))}
+                          inputProps={{ min: 0, max: 4 }}
+                          helperText="Of lowercase, uppercase, numbers, special"
+                        />
+                      </Grid>
+                      <Grid item xs={12} md={6}>
+                        <TextField
+                          fullWidth
+                          label="Banned Words (comma-separated)"
+                          placeholder="e.g., acme, london"
+                          value={policyDictionaryWords}
+                          onChange={(e) => setPolicyDictionaryWords(e.target.value)}
+                          helperText="Checked with common words and the username"
+                        />
+                      </Grid>
+                    </>
+                  )}
                   <Grid item xs={12}>
                     <Button
                       variant="contained"
@@ -0,0 +1,185 @@
+import React, { useState, useEffect, useCallback } from 'react';
+import { useParams, useNavigate } from 'react-router-dom';
+import {
+  Box,
+  Typography,
+  Paper,
+  Button,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+  Chip,
+  CircularProgress,
+  Alert,
+  IconButton,
+  LinearProgress,
+  Link
+} from '@mui/material';
+import { ArrowBack, Refresh as RefreshIcon } from '@mui/icons-material';
+import { getCampaign } from '../../services/api';
+import { JobCampaign } from '../../types/jobs';
+
+// How often a campaign with unfinished jobs is refreshed
+const REFRESH_INTERVAL_MS = 10000;
+
+const CampaignDetails: React.FC = () => {
+  const { id } = useParams<{ id: string }>();
+  const navigate = useNavigate();
+
+  const [campaign, setCampaign] = useState<JobCampaign | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  const fetchCampaign = useCallback(async () => {
+    if (!id) return;
+    setLoading(true);
+    try {
+      setCampaign(await getCampaign(id));
+      setError(null);
+    } catch (err: any) {
+      console.error('Failed to fetch campaign:', err);
+      setError(err.response?.status === 404 ? 'Campaign not found' : 'Failed to load campaign');
+    } finally {
+      setLoading(false);
+    }
+  }, [id]);
+
+  useEffect(() => {
+    fetchCampaign();
+  }, [fetchCampaign]);
+
+  const finished = campaign !== null && campaign.completed_jobs === campaign.jobs.length;
+  useEffect(() => {
+    if (finished) return;
+    const interval = setInterval(fetchCampaign, REFRESH_INTERVAL_MS);
+    return () => clearInterval(interval);
+  }, [fetchCampaign, finished]);
+
+  const hashlistName = (hashlistId: number): string =>
+    campaign?.hashlists.find(hashlist => hashlist.hashlist_id === hashlistId)?.name || `#${hashlistId}`;
+
+  if (!campaign) {
+    return (
+      <Box sx={{ p: 3 }}>
+        {error ? <Alert severity="error">{error}</Alert> : <CircularProgress />}
+      </Box>
+    );
+  }
+
+  const crackedPercent = campaign.total_hashes > 0 ? (campaign.cracked_hashes / campaign.total_hashes) * 100 : 0;
+
+  return (
+    <Box sx={{ p: 3 }}>
+      {/* Header */}
+      <Box sx={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center', mb: 3 }}>
+        <Box sx={{ display: 'flex', alignItems: 'center', gap: 2 }}>
+          <Button startIcon={<ArrowBack />} onClick={() => navigate(-1)}>
+            Back
+          </Button>
+          <Typography variant="h4" component="h1">
+            {campaign.name}
+          </Typography>
+          <Chip label={`Hash type ${campaign.hash_type_id}`} size="small" />
+        </Box>
+        <IconButton onClick={fetchCampaign} disabled={loading} title="Refresh now">
+          <RefreshIcon />
+        </IconButton>
+      </Box>
+
+      {error && (
+        <Alert severity="error" sx={{ mb: 3 }} onClose={() => setError(null)}>
+          {error}
+        </Alert>
+      )}
+
+      {/* Combined progress */}
+      <Paper sx={{ p: 2, mb: 3 }}>
+        <Typography variant="h6" gutterBottom>Progress</Typography>
+        <Typography variant="body2" gutterBottom>
+          {campaign.completed_jobs} of {campaign.jobs.length} jobs complete,{' '}
+          {campaign.overall_progress_percent.toFixed(1)}% overall
+        </Typography>
+        <LinearProgress variant="determinate" value={campaign.overall_progress_percent} sx={{ height: 10, borderRadius: 1, mb: 2 }} />
+        <Typography variant="body2" gutterBottom>
+          {campaign.cracked_hashes.toLocaleString()} of {campaign.total_hashes.toLocaleString()} hashes cracked
+          ({crackedPercent.toFixed(1)}%) across {campaign.hashlists.length} hashlists,{' '}
+          {campaign.campaign_cracked_hashes.toLocaleString()} by this campaign
+        </Typography>
+        <LinearProgress variant="determinate" color="success" value={crackedPercent} sx={{ height: 10, borderRadius: 1 }} />
+      </Paper>
+
+      {/* Results per hashlist */}
+      <Paper sx={{ mb: 3 }}>
+        <Box sx={{ p: 2, borderBottom: 1, borderColor: 'divider' }}>
+          <Typography variant="h6">Hashlists</Typography>
+        </Box>
+        <TableContainer>
+          <Table size="small">
+            <TableHead>
+              <TableRow>
+                <TableCell>Hashlist</TableCell>
+                <TableCell align="right">Cracked</TableCell>
+                <TableCell align="right">Total</TableCell>
+              </TableRow>
+            </TableHead>
+            <TableBody>
+              {campaign.hashlists.map(hashlist => (
+                <TableRow key={hashlist.hashlist_id}>
+                  <TableCell>
+                    <Link component="button" onClick={() => navigate(`/hashlists/${hashlist.hashlist_id}`)}>
+                      {hashlist.name}
+                    </Link>
+                  </TableCell>
+                  <TableCell align="right">{hashlist.cracked_hashes.toLocaleString()}</TableCell>
+                  <TableCell align="right">{hashlist.total_hashes.toLocaleString()}</TableCell>
+                </TableRow>
+              ))}
+            </TableBody>
+          </Table>
+        </TableContainer>
+      </Paper>
+
+      {/* Jobs */}
+      <Paper>
+        <Box sx={{ p: 2, borderBottom: 1, borderColor: 'divider' }}>
+          <Typography variant="h6">Jobs</Typography>
+        </Box>
+        <TableContainer>
+          <Table size="small">
+            <TableHead>
+              <TableRow>
+                <TableCell>Job</TableCell>
+                <TableCell>Hashlist</TableCell>
+                <TableCell>Status</TableCell>
+                <TableCell align="right">Progress</TableCell>
+                <TableCell align="right">Cracked</TableCell>
+              </TableRow>
+            </TableHead>
+            <TableBody>
+              {campaign.jobs.map(job => (
+                <TableRow key={job.job_id}>
+                  <TableCell>
+                    <Link component="button" onClick={() => navigate(`/jobs/${job.job_id}`)}>
+                      {job.name}
+                    </Link>
+                  </TableCell>
+                  <TableCell>{hashlistName(job.hashlist_id)}</TableCell>
+                  <TableCell>{job.status}</TableCell>
+                  <TableCell align="right">
+                    {job.status === 'completed' ? '100.0' : job.overall_progress_percent.toFixed(1)}%
+                  </TableCell>
+                  <TableCell align="right">{job.cracked_hashes.toLocaleString()}</TableCell>
+                </TableRow>
+              ))}
+            </TableBody>
+          </Table>
+        </TableContainer>
+      </Paper>
+    </Box>
+  );
+};
+
+export default CampaignDetails;
@@ -28,7 +28,7 @@ import {
   Refresh as RefreshIcon,
   Replay as ReplayIcon
 } from '@mui/icons-material';
-import { getJobDetails, api } from '../../services/api';
+import { getJobDetails, api, renameJob } from '../../services/api';
 import { JobDetailsResponse, JobTask } from '../../types/jobs';
 import JobProgressBar from '../../components/JobProgressBar';
 import { useSnackbar } from 'notistack';
@@ -46,9 +46,11 @@ const JobDetails: React.FC = () => {
   const [maxPriority, setMaxPriority] = useState<number>(1000); // Default to 1000
   
   // Edit states
+  const [editingName, setEditingName] = useState(false);
   const [editingPriority, setEditingPriority] = useState(false);
   const [editingMaxAgents, setEditingMaxAgents] = useState(false);
   const [editingChunkSize, setEditingChunkSize] = useState(false);
+  const [tempName, setTempName] = useState<string>('');
   const [tempPriority, setTempPriority] = useState<string>('');
   const [tempMaxAgents, setTempMaxAgents] = useState<string>('');
   const [tempChunkSize, setTempChunkSize] = useState<string>('');
@@ -148,6 +150,41 @@ const JobDetails: React.FC = () => {
     };
   }, [jobData?.status, autoRefreshEnabled, fetchJobDetails]);
 
+  // Handle name edit
+  const handleEditName = () => {
+    setTempName(jobData?.name || '');
+    setEditingName(true);
+    setAutoRefreshEnabled(false); // Pause auto-refresh during edit
+  };
+
+  const handleSaveName = async () => {
+    if (!id) return;
+
+    if (!tempName.trim()) {
+      enqueueSnackbar('Job name is required', { variant: 'error' });
+      return;
+    }
+
+    setSaving(true);
+    try {
+      await renameJob(id, tempName);
+      await fetchJobDetails();
+      setEditingName(false);
+      setAutoRefreshEnabled(true); // Resume auto-refresh after save
+    } catch (err: any) {
+      console.error('Failed to rename job:', err);
+      // 409 when another job of the client already has the name
+      enqueueSnackbar(err.response?.data || 'Failed to rename job', { variant: 'error' });
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleCancelName = () => {
+    setEditingName(false);
+    setAutoRefreshEnabled(true); // Resume auto-refresh after cancel
+  };
+
   // Handle priority edit
   const handleEditPriority = () => {
     setTempPriority(String(jobData?.priority || 0));
@@ -436,10 +473,12 @@ const JobDetails: React.FC = () => {
     switch (status.toLowerCase()) {
       case 'running': return 'success';
       case 'pending': return 'warning';
+      case 'scheduled': return 'default';
       case 'reconnect_pending': return 'warning';
       case 'completed': return 'info';
       case 'failed': return 'error';
       case 'cancelled': return 'default';
+      case 'superseded': return 'default';
       default: return 'default';
     }
   };
@@ -567,7 +606,33 @@ const JobDetails: React.FC = () => {
               </TableRow>
               <TableRow>
                 <TableCell sx={{ fontWeight: 'bold' }}>Name</TableCell>
-                <TableCell>{jobData.name}</TableCell>
+                <TableCell>
+                  {editingName ? (
+                    <Box sx={{ display: 'flex', alignItems: 'center', gap: 1 }}>
+                      <TextField
+                        value={tempName}
+                        onChange={(e) => setTempName(e.target.value)}
+                        size="small"
+                        sx={{ minWidth: 300 }}
+                        disabled={saving}
+                        inputProps={{ maxLength: 255 }}
+                      />
+                      <IconButton onClick={handleSaveName} disabled={saving} size="small" title="Save">
+                        <SaveIcon />
+                      </IconButton>
+                      <IconButton onClick={handleCancelName} disabled={saving} size="small" title="Cancel">
+                        <CancelIcon />
+                      </IconButton>
+                    </Box>
+                  ) : (
+                    <Box sx={{ display: 'flex', alignItems: 'center', gap: 1 }}>
+                      {jobData.name}
+                      <IconButton onClick={handleEditName} size="small" title="Rename">
+                        <EditIcon />
+                      </IconButton>
+                    </Box>
+                  )}
+                </TableCell>
               </TableRow>
               <TableRow>
                 <TableCell sx={{ fontWeight: 'bold' }}>Status</TableCell>
@@ -712,6 +777,18 @@ const JobDetails: React.FC = () => {
                   )}
                 </TableCell>
               </TableRow>
+              {jobData.hybrid_keyspace && (
+                <TableRow>
+                  <TableCell sx={{ fontWeight: 'bold' }}>Hybrid Keyspace</TableCell>
+                  <TableCell>
+                    {formatKeyspace(jobData.hybrid_keyspace.left.keyspace)} ({jobData.hybrid_keyspace.left.source})
+                    {' × '}
+                    {formatKeyspace(jobData.hybrid_keyspace.right.keyspace)} ({jobData.hybrid_keyspace.right.source})
+                    {' = '}
+                    {formatKeyspace(jobData.hybrid_keyspace.candidates)}
+                  </TableCell>
+                </TableRow>
+              )}
               <TableRow>
                 <TableCell sx={{ fontWeight: 'bold' }}>Processed Keyspace</TableCell>
                 <TableCell>{formatKeyspace(jobData.processed_keyspace)}</TableCell>
@@ -761,16 +838,119 @@ const JobDetails: React.FC = () => {
                 <TableCell sx={{ fontWeight: 'bold' }}>Completed At</TableCell>
                 <TableCell>{formatDate(jobData.completed_at)}</TableCell>
               </TableRow>
+              {((jobData.pinned_agent_ids?.length ?? 0) > 0 || (jobData.excluded_agent_ids?.length ?? 0) > 0) && (
+                <TableRow>
+                  <TableCell sx={{ fontWeight: 'bold' }}>Agent Placement</TableCell>
+                  <TableCell>
+                    {(jobData.pinned_agent_ids?.length ?? 0) > 0 && (
+                      <Typography variant="body2">Pinned to agents {jobData.pinned_agent_ids!.join(', ')}</Typography>
+                    )}
+                    {(jobData.excluded_agent_ids?.length ?? 0) > 0 && (
+                      <Typography variant="body2">Excludes agents {jobData.excluded_agent_ids!.join(', ')}</Typography>
+                    )}
+                  </TableCell>
+                </TableRow>
+              )}
+              {jobData.split_group && (
+                <TableRow>
+                  <TableCell sx={{ fontWeight: 'bold' }}>Sub-lists</TableCell>
+                  <TableCell>
+                    <Typography variant="body2">
+                      {jobData.split_group.completed_parts} of {jobData.split_group.parts} parts complete,{' '}
+                      {jobData.split_group.overall_progress_percent.toFixed(1)}% overall,{' '}
+                      {jobData.split_group.cracked_hashes} cracked
+                    </Typography>
+                    {jobData.split_group.jobs.map((part) => (
+                      <Typography key={part.job_id} variant="body2">
+                        <Link
+                          component="button"
+                          variant="body2"
+                          onClick={() => navigate(`/jobs/${part.job_id}`)}
+                          sx={{ fontWeight: part.job_id === jobData.id ? 'bold' : 'normal' }}
+                        >
+                          Part {part.part_number}
+                        </Link>
+                        : {part.status}, {part.overall_progress_percent.toFixed(1)}%
+                      </Typography>
+                    ))}
+                  </TableCell>
+                </TableRow>
+              )}
+              {jobData.campaign_id && (
+                <TableRow>
+                  <TableCell sx={{ fontWeight: 'bold' }}>Campaign</TableCell>
+                  <TableCell>
+                    <Link component="button" onClick={() => navigate(`/jobs/campaigns/${jobData.campaign_id}`)}>
+                      View combined progress of all hashlists
+                    </Link>
+                  </TableCell>
+                </TableRow>
+              )}
+              {(jobData.start_at || jobData.depends_on_job_id) && (
+                <TableRow>
+                  <TableCell sx={{ fontWeight: 'bold' }}>Start Condition</TableCell>
+                  <TableCell>
+                    {jobData.start_at && <Typography variant="body2">Starts at {new Date(jobData.start_at).toLocaleString()}</Typography>}
+                    {jobData.depends_on_job_id && (
+                      <Typography variant="body2">
+                        Starts after job{' '}
+                        <Link component="button" onClick={() => navigate(`/jobs/${jobData.depends_on_job_id}`)}>
+                          {jobData.depends_on_job_id}
+                        </Link>
+                        {' '}completes
+                      </Typography>
+                    )}
+                  </TableCell>
+                </TableRow>
+              )}
+              {jobData.allow_cloud_burst && (
+                <TableRow>
+                  <TableCell sx={{ fontWeight: 'bold' }}>Cloud Burst</TableCell>
+                  <TableCell>May launch and run on temporary cloud agents</TableCell>
+                </TableRow>
+              )}
+              {jobData.remaining_hash_count != null && (
+                <TableRow>
+                  <TableCell sx={{ fontWeight: 'bold' }}>Target Hashes</TableCell>
+                  <TableCell>
+                    Only the {jobData.remaining_hash_count.toLocaleString()} hashes uncracked when the job was created
+                  </TableCell>
+                </TableRow>
+              )}
               {jobData.error_message && (
                 <TableRow>
-                  <TableCell sx={{ fontWeight: 'bold' }}>Error</TableCell>
+                  <TableCell sx={{ fontWeight: 'bold' }}>
+                    {jobData.error_message.startsWith('Waiting for an eligible agent') ? 'Queue State' : 'Error'}
+                  </TableCell>
                   <TableCell>
-                    <Alert severity="error" sx={{ py: 0.5 }}>
+                    <Alert severity={jobData.error_message.startsWith('Waiting for an eligible agent') ? 'info' : 'error'} sx={{ py: 0.5 }}>
                       {jobData.error_message}
                     </Alert>
                   </TableCell>
                 </TableRow>
               )}
+              {jobData.failure_summary && (
+                <TableRow>
+                  <TableCell sx={{ fontWeight: 'bold' }}>Failures</TableCell>
+                  <TableCell>
+                    <Alert severity="warning" sx={{ py: 0.5 }}>
+                      <Typography variant="body2" sx={{ fontWeight: 'bold' }}>
+                        {jobData.failure_summary.summary}
+                      </Typography>
+                      {jobData.failure_summary.causes.map(cause => (
+                        <Box key={cause.label} sx={{ mt: 1 }}>
+                          <Typography variant="body2">
+                            {cause.count}× {cause.label} ({cause.agents.map(agent => `${agent.agent_name}: ${agent.count}`).join(', ')})
+                          </Typography>
+                          <Typography variant="caption" color="text.secondary">
+                            {cause.remediation_hint}
+                          </Typography>
+                        </Box>
+                      ))}
+                    </Alert>
+                  </TableCell>
+                </TableRow>
+              )}
             </TableBody>
           </Table>
         </TableContainer>
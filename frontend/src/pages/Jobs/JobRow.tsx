@@ -100,6 +100,8 @@ const JobRow: React.FC<JobRowProps> = ({ job, onJobUpdated, isLastActiveJob, isC
         return 'success';
       case 'pending':
         return 'warning';
+      case 'scheduled':
+        return 'default';
       case 'completed':
         return 'info';
       case 'failed':
@@ -107,6 +109,7 @@ const JobRow: React.FC<JobRowProps> = ({ job, onJobUpdated, isLastActiveJob, isC
       case 'paused':
         return 'default';
       case 'cancelled':
+      case 'superseded':
         return 'default';
       default:
         return 'default';
@@ -115,6 +118,8 @@ const JobRow: React.FC<JobRowProps> = ({ job, onJobUpdated, isLastActiveJob, isC
 
   const canRetry = ['failed', 'cancelled'].includes(job.status.toLowerCase());
   const hasError = job.error_message && job.status === 'failed';
+  // Pending jobs carry the reason they are waiting, e.g. a concurrency cap
+  const queueReason = job.status === 'pending' && job.error_message ? job.error_message : '';
 
   // Format completion time if available
   const completionTime = job.completed_at ? new Date(job.completed_at).toLocaleString() : null;
@@ -148,13 +153,15 @@ const JobRow: React.FC<JobRowProps> = ({ job, onJobUpdated, isLastActiveJob, isC
             >
               {job.name}
             </Link>
-            <Chip
-              label={job.status}
-              color={getStatusColor(job.status)}
-              size="small"
-              variant="outlined"
-              icon={hasError ? <ErrorIcon /> : undefined}
-            />
+            <Tooltip title={queueReason} arrow>
+              <Chip
+                label={job.status}
+                color={getStatusColor(job.status)}
+                size="small"
+                variant="outlined"
+                icon={hasError ? <ErrorIcon /> : queueReason ? <InfoIcon /> : undefined}
+              />
+            </Tooltip>
           </Box>
         </TableCell>
 
@@ -316,7 +323,7 @@ const JobRow: React.FC<JobRowProps> = ({ job, onJobUpdated, isLastActiveJob, isC
 
         {/* Priority */}
         <TableCell align="center">
-          {(job.status === 'completed' || job.status === 'cancelled') ? (
+          {['completed', 'cancelled', 'superseded'].includes(job.status) ? (
             <Typography variant="body2">{job.priority}</Typography>
           ) : (
             <EditableCell
@@ -338,7 +345,7 @@ const JobRow: React.FC<JobRowProps> = ({ job, onJobUpdated, isLastActiveJob, isC
 
         {/* Max Agents */}
         <TableCell align="center">
-          {(job.status === 'completed' || job.status === 'cancelled') ? (
+          {['completed', 'cancelled', 'superseded'].includes(job.status) ? (
             <Typography variant="body2">{job.max_agents}</Typography>
           ) : (
             <EditableCell
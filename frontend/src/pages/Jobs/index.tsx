@@ -16,17 +16,23 @@ import {
   Badge,
   ToggleButton,
   ToggleButtonGroup,
+  Dialog,
+  DialogTitle,
+  DialogContent,
+  DialogActions,
 } from '@mui/material';
 import { 
   Delete as DeleteIcon, 
   Refresh as RefreshIcon,
   Search as SearchIcon,
   FilterList as FilterListIcon,
+  BookmarkAdd as BookmarkAddIcon,
 } from '@mui/icons-material';
 import JobsTable from './JobsTable';
 import DeleteConfirm from './DeleteConfirm';
-import { api } from '../../services/api';
-import { JobSummary, PaginationInfo } from '../../types/jobs';
+import { api, listSavedViews, createSavedView } from '../../services/api';
+import { JobSummary, PaginationInfo, JobProgressMessage, SavedView } from '../../types/jobs';
+import useJobProgressStream from '../../hooks/useJobProgressStream';
 
 interface JobsResponse {
   jobs: JobSummary[];
@@ -64,10 +70,19 @@ const Jobs: React.FC = () => {
   const [isDeleting, setIsDeleting] = useState(false);
   const [lastUpdateTime, setLastUpdateTime] = useState(new Date());
   const [isPolling, setIsPolling] = useState(true);
+
+  // Saved views state
+  const [savedViews, setSavedViews] = useState<SavedView[]>([]);
+  const [selectedViewId, setSelectedViewId] = useState('');
+  const [saveViewOpen, setSaveViewOpen] = useState(false);
+  const [newViewName, setNewViewName] = useState('');
+  const [saveViewError, setSaveViewError] = useState('');
   
   // Refs for cleanup
   const pollingTimer = useRef<NodeJS.Timeout | null>(null);
   const abortController = useRef<AbortController | null>(null);
+  // Latest hash rate of each streamed task, by job
+  const taskRates = useRef<Map<string, Map<string, number>>>(new Map());
 
   // Build query parameters from current state
   const buildQueryParams = useCallback(() => {
@@ -132,7 +147,49 @@ const Jobs: React.FC = () => {
     fetchJobs(true);
   }, [page, pageSize, filters]);
 
-  // Set up polling
+  // Stream the progress of the active jobs on this page
+  const activeJobIds = isPolling
+    ? jobs.filter(job => job.status === 'running' || job.status === 'pending').map(job => job.id)
+    : [];
+
+  const handleProgress = useCallback((message: JobProgressMessage) => {
+    // A job changed status or updates were dropped, the list needs a refetch
+    if (message.resync || message.jobs.some(update => update.status)) {
+      fetchJobs(false);
+      return;
+    }
+
+    const updates = new Map(message.jobs.map(update => [update.job_id, update]));
+    setJobs(prev => prev.map(job => {
+      const update = updates.get(job.id);
+      if (!update) {
+        return job;
+      }
+      let rates = taskRates.current.get(job.id);
+      if (!rates) {
+        rates = new Map();
+        taskRates.current.set(job.id, rates);
+      }
+      for (const task of update.tasks || []) {
+        if (task.status === 'running') {
+          rates.set(task.task_id, task.hash_rate);
+        } else {
+          rates.delete(task.task_id);
+        }
+      }
+      return {
+        ...job,
+        cracked_count: job.cracked_count + (update.cracked || 0),
+        total_speed: Array.from(rates.values()).reduce((sum, rate) => sum + rate, 0),
+      };
+    }));
+    setLastUpdateTime(new Date());
+  }, [fetchJobs]);
+
+  const isStreaming = useJobProgressStream(activeJobIds, handleProgress);
+
+  // Set up polling, only as a fallback for what the stream does not cover
+  // while it is connected
   useEffect(() => {
     if (!isPolling) {
       return;
@@ -146,7 +203,7 @@ const Jobs: React.FC = () => {
     // Set up new polling timer
     pollingTimer.current = setInterval(() => {
       fetchJobs(false); // Don't show loading indicator for polling updates
-    }, 5000);
+    }, isStreaming ? 30000 : 5000);
 
     // Cleanup on unmount or when polling is disabled
     return () => {
@@ -154,7 +211,7 @@ const Jobs: React.FC = () => {
         clearInterval(pollingTimer.current);
       }
     };
-  }, [fetchJobs, isPolling]);
+  }, [fetchJobs, isPolling, isStreaming]);
 
   // Cleanup on unmount
   useEffect(() => {
@@ -210,6 +267,56 @@ const Jobs: React.FC = () => {
     fetchJobs(true);
   };
 
+  // Load the saved job views of the user and their teams
+  useEffect(() => {
+    listSavedViews('jobs')
+      .then(setSavedViews)
+      .catch(err => console.error('Failed to load saved views:', err));
+  }, []);
+
+  // Apply a saved view's filters to the list
+  const handleSelectView = (viewId: string) => {
+    setSelectedViewId(viewId);
+    const view = savedViews.find(v => v.id === viewId);
+    if (!view) {
+      return;
+    }
+    const query = view.query;
+    const status = query.status ?? query['filter[status]'];
+    const priority = query.priority ?? query['filter[priority]'];
+    setFilters({
+      status: status || null,
+      priority: priority ? Number(priority) : null,
+      search: query.search ?? query['filter[search]'] ?? '',
+    });
+    setPage(1);
+  };
+
+  // Save the current filters as a private view
+  const handleSaveView = async () => {
+    const query: Record<string, string> = {};
+    if (filters.status) {
+      query.status = filters.status;
+    }
+    if (filters.priority !== null) {
+      query.priority = filters.priority.toString();
+    }
+    if (filters.search.trim()) {
+      query.search = filters.search.trim();
+    }
+
+    try {
+      const view = await createSavedView({ name: newViewName, resource: 'jobs', query });
+      setSavedViews(prev => [...prev, view].sort((a, b) => a.name.localeCompare(b.name)));
+      setSelectedViewId(view.id);
+      setSaveViewOpen(false);
+      setNewViewName('');
+      setSaveViewError('');
+    } catch (err: any) {
+      setSaveViewError(err.response?.data?.error || 'Failed to save view');
+    }
+  };
+
   const togglePolling = () => {
     setIsPolling(prev => !prev);
   };
@@ -237,7 +344,7 @@ const Jobs: React.FC = () => {
           {/* Polling Status */}
           <Chip
             icon={<RefreshIcon />}
-            label={isPolling ? 'Auto-refresh (5s)' : 'Auto-refresh OFF'}
+            label={isPolling ? (isStreaming ? 'Live' : 'Auto-refresh (5s)') : 'Auto-refresh OFF'}
             color={isPolling ? 'success' : 'default'}
             variant="outlined"
             size="small"
@@ -316,6 +423,30 @@ const Jobs: React.FC = () => {
                 <MenuItem value={5}>Maximum (5)</MenuItem>
               </Select>
             </FormControl>
+
+            {/* Saved Views */}
+            <FormControl size="small" sx={{ minWidth: 200 }}>
+              <InputLabel>Saved view</InputLabel>
+              <Select
+                value={selectedViewId}
+                label="Saved view"
+                onChange={(e) => handleSelectView(e.target.value as string)}
+              >
+                <MenuItem value="">None</MenuItem>
+                {savedViews.map(view => (
+                  <MenuItem key={view.id} value={view.id}>
+                    {view.name}{view.team_name ? ` (${view.team_name})` : ''}
+                  </MenuItem>
+                ))}
+              </Select>
+            </FormControl>
+            <Button
+              size="small"
+              startIcon={<BookmarkAddIcon />}
+              onClick={() => setSaveViewOpen(true)}
+            >
+              Save view
+            </Button>
           </Box>
 
           {/* Status Filter Buttons */}
@@ -405,6 +536,29 @@ const Jobs: React.FC = () => {
         title="Delete Finished Jobs"
         message="Are you sure you want to delete all finished jobs? This action cannot be undone."
       />
+
+      {/* Save View Dialog */}
+      <Dialog open={saveViewOpen} onClose={() => setSaveViewOpen(false)} maxWidth="xs" fullWidth>
+        <DialogTitle>Save view</DialogTitle>
+        <DialogContent>
+          {saveViewError && <Alert severity="error" sx={{ mb: 2 }}>{saveViewError}</Alert>}
+          <TextField
+            autoFocus
+            fullWidth
+            margin="dense"
+            label="Name"
+            value={newViewName}
+            onChange={(e) => setNewViewName(e.target.value)}
+            helperText="Saves the current status, priority and search filters"
+          />
+        </DialogContent>
+        <DialogActions>
+          <Button onClick={() => setSaveViewOpen(false)}>Cancel</Button>
+          <Button variant="contained" onClick={handleSaveView} disabled={!newViewName.trim()}>
+            Save
+          </Button>
+        </DialogActions>
+      </Dialog>
     </Box>
   );
 };
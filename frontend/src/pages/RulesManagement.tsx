@@ -57,6 +57,7 @@ import {
   Verified as VerifiedIcon
 } from '@mui/icons-material';
 import FileUpload from '../components/common/FileUpload';
+import DeleteFileDialog from '../components/common/DeleteFileDialog';
 import { Rule, RuleStatus, RuleType } from '../types/rules';
 import * as ruleService from '../services/rules';
 import { useSnackbar } from 'notistack';
@@ -79,6 +80,7 @@ export default function RulesManagement() {
   const { enqueueSnackbar } = useSnackbar();
   const [uploadDialogOpen, setUploadDialogOpen] = useState(false);
   const [selectedRuleType, setSelectedRuleType] = useState<RuleType>(RuleType.HASHCAT);
+  const [keepDuplicate, setKeepDuplicate] = useState(false);
   const [isLoading, setIsLoading] = useState(false);
   const [deleteDialogOpen, setDeleteDialogOpen] = useState(false);
   const [ruleToDelete, setRuleToDelete] = useState<{id: string, name: string} | null>(null);
@@ -111,6 +113,7 @@ export default function RulesManagement() {
       
       // Add the rule type to the form data
       formData.append('rule_type', selectedRuleType);
+      formData.append('on_duplicate', keepDuplicate ? 'keep' : 'alias');
       
       // Add required fields if not present
       if (!formData.has('name')) {
@@ -144,7 +147,12 @@ export default function RulesManagement() {
       console.debug('[Rule Upload] Upload successful:', response);
       
       // Check if the response indicates a duplicate rule
-      if (response.data.duplicate) {
+      if (response.data.near_duplicate) {
+        enqueueSnackbar(
+          `Rule "${response.data.name}" already exists with only comment, whitespace or line ending differences, the upload was aliased to it`,
+          { variant: 'info' }
+        );
+      } else if (response.data.duplicate) {
         enqueueSnackbar(`Rule "${response.data.name}" already exists`, { variant: 'info' });
       } else {
         enqueueSnackbar('Rule uploaded successfully', { variant: 'success' });
@@ -161,9 +169,9 @@ export default function RulesManagement() {
   };
 
   // Handle rule deletion
-  const handleDelete = async (id: string, name: string) => {
+  const handleDelete = async (id: string, name: string, force = false) => {
     try {
-      await ruleService.deleteRule(id);
+      await ruleService.deleteRule(id, force);
       enqueueSnackbar(`Rule "${name}" deleted successfully`, { variant: 'success' });
       fetchRules();
     } catch (err: any) {
@@ -183,6 +191,12 @@ export default function RulesManagement() {
     setDeleteDialogOpen(true);
   };
 
+  // Load what uses the rule being deleted
+  const loadDeleteReferences = useCallback(
+    () => ruleService.getRuleReferences(ruleToDelete?.id ?? '').then(res => res.data),
+    [ruleToDelete?.id]
+  );
+
   // Close delete confirmation dialog
   const closeDeleteDialog = () => {
     setDeleteDialogOpen(false);
@@ -563,6 +577,16 @@ export default function RulesManagement() {
                   <MenuItem value={RuleType.HASHCAT}>Hashcat</MenuItem>
                   <MenuItem value={RuleType.JOHN}>John the Ripper</MenuItem>
                 </Select>
+                <FormControlLabel
+                  control={
+                    <Checkbox
+                      checked={keepDuplicate}
+                      onChange={(e) => setKeepDuplicate(e.target.checked)}
+                    />
+                  }
+                  label="Keep a separate copy if a rule file only differing in comments, whitespace or line endings exists"
+                  sx={{ mt: 1 }}
+                />
               </FormControl>
             }
           />
@@ -627,32 +651,14 @@ export default function RulesManagement() {
       </Dialog>
 
       {/* Delete Dialog */}
-      <Dialog
+      <DeleteFileDialog
         open={deleteDialogOpen}
-        onClose={closeDeleteDialog}
-        aria-labelledby="delete-dialog-title"
-        maxWidth="sm"
-        fullWidth
-      >
-        <DialogTitle id="delete-dialog-title">Delete Rule</DialogTitle>
-        <DialogContent>
-          <Typography variant="body1">
-            Are you sure you want to delete rule "{ruleToDelete?.name}"?
-          </Typography>
-        </DialogContent>
-        <DialogActions>
-          <Button onClick={closeDeleteDialog}>
-            Cancel
-          </Button>
-          <Button 
-            onClick={() => ruleToDelete && handleDelete(ruleToDelete.id, ruleToDelete.name)} 
-            variant="contained" 
-            color="error"
-          >
-            Delete
-          </Button>
-        </DialogActions>
-      </Dialog>
+        kind="rule"
+        name={ruleToDelete?.name ?? ''}
+        loadReferences={loadDeleteReferences}
+        onCancel={closeDeleteDialog}
+        onDelete={force => ruleToDelete && handleDelete(ruleToDelete.id, ruleToDelete.name, force)}
+      />
     </Box>
   );
 } 
\ No newline at end of file
@@ -58,6 +58,7 @@ import {
   Verified as VerifiedIcon
 } from '@mui/icons-material';
 import FileUpload from '../components/common/FileUpload';
+import DeleteFileDialog from '../components/common/DeleteFileDialog';
 import { Wordlist, WordlistStatus, WordlistType } from '../types/wordlists';
 import * as wordlistService from '../services/wordlists';
 import { useSnackbar } from 'notistack';
@@ -81,6 +82,8 @@ export default function WordlistsManagement() {
   const { enqueueSnackbar } = useSnackbar();
   const [uploadDialogOpen, setUploadDialogOpen] = useState(false);
   const [selectedWordlistType, setSelectedWordlistType] = useState<WordlistType>(WordlistType.GENERAL);
+  const [normalizeEncoding, setNormalizeEncoding] = useState(false);
+  const [keepDuplicate, setKeepDuplicate] = useState(false);
   const [isLoading, setIsLoading] = useState(false);
   const [deleteDialogOpen, setDeleteDialogOpen] = useState(false);
   const [wordlistToDelete, setWordlistToDelete] = useState<{id: string, name: string} | null>(null);
@@ -110,9 +113,18 @@ export default function WordlistsManagement() {
   const handleUploadWordlist = async (formData: FormData) => {
     try {
       setIsLoading(true);
+
+      // The file is normalized while it is streamed, so the flag must come before it
+      if (normalizeEncoding) {
+        const ordered = new FormData();
+        ordered.append('normalize_encoding', 'true');
+        formData.forEach((value, key) => ordered.append(key, value));
+        formData = ordered;
+      }
       
       // Add the wordlist type to the form data
       formData.append('wordlist_type', selectedWordlistType);
+      formData.append('on_duplicate', keepDuplicate ? 'keep' : 'alias');
       
       // Add required fields if not present
       if (!formData.has('name')) {
@@ -154,10 +166,22 @@ export default function WordlistsManagement() {
         console.debug('[Wordlist Upload] Upload successful:', response);
         
         // Check if the response indicates a duplicate wordlist
-        if (response.data.duplicate) {
+        if (response.data.near_duplicate) {
+          enqueueSnackbar(
+            `Wordlist "${response.data.name}" already exists with only line ending or BOM differences, the upload was aliased to it`,
+            { variant: 'info' }
+          );
+        } else if (response.data.duplicate) {
           enqueueSnackbar(`Wordlist "${response.data.name}" already exists`, { variant: 'info' });
         } else {
           enqueueSnackbar('Wordlist uploaded successfully', { variant: 'success' });
+          const normalization = response.data.normalization;
+          if (normalization && (normalization.changed_lines > 0 || normalization.bom_stripped)) {
+            enqueueSnackbar(
+              `Encoding normalized from ${normalization.source_encoding}: ${normalization.changed_lines} of ${normalization.lines} lines changed`,
+              { variant: 'info' }
+            );
+          }
         }
         
         setUploadDialogOpen(false);
@@ -178,9 +202,9 @@ export default function WordlistsManagement() {
   };
 
   // Handle wordlist deletion
-  const handleDelete = async (id: string, name: string) => {
+  const handleDelete = async (id: string, name: string, force = false) => {
     try {
-      await wordlistService.deleteWordlist(id);
+      await wordlistService.deleteWordlist(id, force);
       enqueueSnackbar(`Wordlist "${name}" deleted successfully`, { variant: 'success' });
       fetchWordlists();
     } catch (err: any) {
@@ -200,6 +224,12 @@ export default function WordlistsManagement() {
     setDeleteDialogOpen(true);
   };
 
+  // Load what uses the wordlist being deleted
+  const loadDeleteReferences = useCallback(
+    () => wordlistService.getWordlistReferences(wordlistToDelete?.id ?? '').then(res => res.data),
+    [wordlistToDelete?.id]
+  );
+
   // Close delete confirmation dialog
   const closeDeleteDialog = () => {
     setDeleteDialogOpen(false);
@@ -601,6 +631,25 @@ export default function WordlistsManagement() {
                   <MenuItem value={WordlistType.TARGETED}>Targeted</MenuItem>
                   <MenuItem value={WordlistType.CUSTOM}>Custom</MenuItem>
                 </Select>
+                <FormControlLabel
+                  control={
+                    <Checkbox
+                      checked={normalizeEncoding}
+                      onChange={(e) => setNormalizeEncoding(e.target.checked)}
+                    />
+                  }
+                  label="Normalize encoding (UTF-16/latin-1 to UTF-8, strip BOM and CRLF)"
+                  sx={{ mt: 1 }}
+                />
+                <FormControlLabel
+                  control={
+                    <Checkbox
+                      checked={keepDuplicate}
+                      onChange={(e) => setKeepDuplicate(e.target.checked)}
+                    />
+                  }
+                  label="Keep a separate copy if a wordlist only differing in line endings or BOM exists"
+                />
               </FormControl>
             }
           />
@@ -667,30 +716,14 @@ export default function WordlistsManagement() {
       </Dialog>
 
       {/* Delete Confirmation Dialog */}
-      <Dialog
+      <DeleteFileDialog
         open={deleteDialogOpen}
-        onClose={closeDeleteDialog}
-        aria-labelledby="delete-dialog-title"
-        aria-describedby="delete-dialog-description"
-      >
-        <DialogTitle id="delete-dialog-title">Confirm Deletion</DialogTitle>
-        <DialogContent>
-          <Typography variant="body1" id="delete-dialog-description">
-            Are you sure you want to delete wordlist "{wordlistToDelete?.name}"? This action cannot be undone.
-          </Typography>
-        </DialogContent>
-        <DialogActions>
-          <Button onClick={closeDeleteDialog}>Cancel</Button>
-          <Button 
-            onClick={() => wordlistToDelete && handleDelete(wordlistToDelete.id, wordlistToDelete.name)} 
-            color="error" 
-            variant="contained"
-            autoFocus
-          >
-            Delete
-          </Button>
-        </DialogActions>
-      </Dialog>
+        kind="wordlist"
+        name={wordlistToDelete?.name ?? ''}
+        loadReferences={loadDeleteReferences}
+        onCancel={closeDeleteDialog}
+        onDelete={force => wordlistToDelete && handleDelete(wordlistToDelete.id, wordlistToDelete.name, force)}
+      />
     </Box>
   );
 } 
\ No newline at end of file
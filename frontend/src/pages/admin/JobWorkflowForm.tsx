@@ -23,7 +23,9 @@ import {
   Grid,
   Autocomplete,
   Chip,
-  Stack
+  Stack,
+  FormControlLabel,
+  Switch
 } from '@mui/material';
 import DeleteIcon from '@mui/icons-material/Delete';
 import AddIcon from '@mui/icons-material/Add';
@@ -64,6 +66,8 @@ const JobWorkflowFormPage: React.FC = () => {
   const [formData, setFormData] = useState<JobWorkflowFormData>({
     name: '',
     preset_job_ids: [],
+    inherit_priority: true,
+    priority_boost: 0,
     orderedJobs: []
   });
 
@@ -128,12 +132,16 @@ const JobWorkflowFormPage: React.FC = () => {
               setFormData({
                 name: workflow.name,
                 preset_job_ids: sortedSteps.map(step => step.preset_job_id),
+                inherit_priority: workflow.inherit_priority,
+                priority_boost: workflow.priority_boost,
                 orderedJobs
               });
             } else {
               setFormData({
                 name: workflow.name,
                 preset_job_ids: [],
+                inherit_priority: workflow.inherit_priority,
+                priority_boost: workflow.priority_boost,
                 orderedJobs: []
               });
             }
@@ -230,10 +238,12 @@ const JobWorkflowFormPage: React.FC = () => {
     setError(null);
     setSuccessMessage(null);
     
-    // Create request payload (only need name and preset_job_ids)
+    // Create request payload
     const payload: CreateWorkflowRequest = {
       name: formData.name,
-      preset_job_ids: formData.preset_job_ids
+      preset_job_ids: formData.preset_job_ids,
+      inherit_priority: formData.inherit_priority,
+      priority_boost: formData.priority_boost
     };
     
     try {
@@ -303,6 +313,29 @@ const JobWorkflowFormPage: React.FC = () => {
             required
             disabled={submitting}
           />
+
+          <Box mt={2} display="flex" alignItems="center" gap={3} flexWrap="wrap">
+            <FormControlLabel
+              control={
+                <Switch
+                  checked={formData.inherit_priority}
+                  onChange={(e) => setFormData(prev => ({ ...prev, inherit_priority: e.target.checked }))}
+                  disabled={submitting}
+                />
+              }
+              label="Follow-up steps inherit priority and max agents"
+            />
+            <TextField
+              label="Priority Boost"
+              type="number"
+              value={formData.priority_boost}
+              onChange={(e) => setFormData(prev => ({ ...prev, priority_boost: Math.max(0, parseInt(e.target.value, 10) || 0) }))}
+              disabled={submitting || !formData.inherit_priority}
+              inputProps={{ min: 0 }}
+              helperText="Added to the inherited priority of each follow-up step"
+              sx={{ width: 260 }}
+            />
+          </Box>
           
           <Box mt={3}>
             <Autocomplete
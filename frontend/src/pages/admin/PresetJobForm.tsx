@@ -29,6 +29,7 @@ import {
 } from '../../services/api';
 import { getMaxPriorityForUsers } from '../../services/systemSettings';
 import { getJobExecutionSettings } from '../../services/jobSettings';
+import MaskIncrementFields from '../../components/common/MaskIncrementFields';
 import { 
   PresetJob, 
   PresetJobInput, 
@@ -36,6 +37,7 @@ import {
   PresetJobApiData,
   AttackMode, 
   WordlistBasic, 
+  WordlistCollection,
   RuleBasic, 
   BinaryVersionBasic 
 } from '../../types/adminJobs';
@@ -55,6 +57,7 @@ const MenuProps = {
 const getInitialFormState = (defaultChunkDuration: number = 300): PresetJobFormData => ({
   name: '',
   wordlist_ids: [],
+  wordlist_collection_id: null,
   rule_ids: [],
   attack_mode: AttackMode.Straight,
   priority: '', // Empty string to show placeholder
@@ -62,6 +65,7 @@ const getInitialFormState = (defaultChunkDuration: number = 300): PresetJobFormD
   binary_version_id: 0,
   allow_high_priority_override: false,
   mask: '',
+  mask_increment: null,
   max_agents: 0
 });
 
@@ -122,6 +126,7 @@ const PresetJobFormPage: React.FC = () => {
   
   // Form options from API
   const [wordlists, setWordlists] = useState<WordlistBasic[]>([]);
+  const [collections, setCollections] = useState<WordlistCollection[]>([]);
   const [rules, setRules] = useState<RuleBasic[]>([]);
   const [binaryVersions, setBinaryVersions] = useState<BinaryVersionBasic[]>([]);
   
@@ -175,6 +180,7 @@ const PresetJobFormPage: React.FC = () => {
         }
 
         setWordlists(formDataResponse.wordlists);
+        setCollections(formDataResponse.wordlist_collections || []);
         setRules(formDataResponse.rules || []);
         setBinaryVersions(formDataResponse.binary_versions);
         
@@ -186,6 +192,7 @@ const PresetJobFormPage: React.FC = () => {
               name: presetJob.name,
               // Convert string UUIDs to numbers for form handling
               wordlist_ids: presetJob.wordlist_ids.map(id => parseInt(id)),
+              wordlist_collection_id: presetJob.wordlist_collection_id ?? null,
               rule_ids: presetJob.rule_ids.map(id => parseInt(id)),
               attack_mode: presetJob.attack_mode,
               priority: presetJob.priority,
@@ -193,6 +200,9 @@ const PresetJobFormPage: React.FC = () => {
               binary_version_id: presetJob.binary_version_id,
               allow_high_priority_override: presetJob.allow_high_priority_override,
               mask: presetJob.mask || '',
+              mask_increment: presetJob.mask_increment
+                ? { min: presetJob.mask_increment.min, max: presetJob.mask_increment.max }
+                : null,
               max_agents: presetJob.max_agents || 0
             });
 
@@ -290,6 +300,11 @@ const PresetJobFormPage: React.FC = () => {
       const updates: Partial<typeof formData> = {
         attack_mode: newAttackMode
       };
+
+      // Increments only apply to brute force masks
+      if (newAttackMode !== AttackMode.BruteForce) {
+        updates.mask_increment = null;
+      }
       
       // Reset wordlist selection based on attack mode
       if (newAttackMode === AttackMode.Straight || 
@@ -297,7 +312,11 @@ const PresetJobFormPage: React.FC = () => {
           newAttackMode === AttackMode.HybridMaskWordlist) {
         // For modes requiring exactly one wordlist, keep only the first selected if any
         updates.wordlist_ids = formData.wordlist_ids.length > 0 ? [formData.wordlist_ids[0]] : [];
-      } else if (newAttackMode === AttackMode.Combination) {
+      } else {
+        // Only single wordlist modes can run a collection
+        updates.wordlist_collection_id = null;
+      }
+      if (newAttackMode === AttackMode.Combination) {
         // For combination mode, initialize separate wordlist selectors
         if (formData.wordlist_ids.length > 0) {
           setFirstWordlist(formData.wordlist_ids[0].toString());
@@ -420,8 +439,8 @@ const PresetJobFormPage: React.FC = () => {
     // Attack mode specific validation
     switch (formData.attack_mode) {
       case AttackMode.Straight:
-        if (formData.wordlist_ids.length !== 1) {
-          setError('Straight mode requires exactly one wordlist');
+        if (formData.wordlist_ids.length !== 1 && formData.wordlist_collection_id === null) {
+          setError('Straight mode requires exactly one wordlist or a wordlist collection');
           return false;
         }
         break;
@@ -447,8 +466,8 @@ const PresetJobFormPage: React.FC = () => {
         
       case AttackMode.HybridWordlistMask:
       case AttackMode.HybridMaskWordlist:
-        if (formData.wordlist_ids.length !== 1) {
-          setError('This hybrid mode requires exactly one wordlist');
+        if (formData.wordlist_ids.length !== 1 && formData.wordlist_collection_id === null) {
+          setError('This hybrid mode requires exactly one wordlist or a wordlist collection');
           return false;
         }
         if (!formData.mask) {
@@ -534,6 +553,12 @@ const PresetJobFormPage: React.FC = () => {
   
   // Determine if wordlists should be disabled based on attack mode
   const isWordlistsDisabled = formData.attack_mode === AttackMode.BruteForce;
+
+  // A wordlist collection takes the place of the single wordlist of these modes
+  const supportsCollections = formData.attack_mode === AttackMode.Straight ||
+                              formData.attack_mode === AttackMode.HybridWordlistMask ||
+                              formData.attack_mode === AttackMode.HybridMaskWordlist;
+  const usesCollection = supportsCollections && formData.wordlist_collection_id !== null;
   
   // Determine if mask input should be shown
   const showMaskInput = formData.attack_mode === AttackMode.BruteForce || 
@@ -686,6 +711,12 @@ const PresetJobFormPage: React.FC = () => {
                 </span>
               }
             />
+            {formData.attack_mode === AttackMode.BruteForce && (
+              <MaskIncrementFields
+                value={formData.mask_increment}
+                onChange={(maskIncrement) => setFormData(prev => ({ ...prev, mask_increment: maskIncrement }))}
+              />
+            )}
           </Grid>
         )}
 
@@ -754,12 +785,42 @@ const PresetJobFormPage: React.FC = () => {
         ) : (
           /* Regular wordlist selection for other attack modes */
           <Grid item xs={12}>
+            {supportsCollections && collections.length > 0 && (
+              <FormControl fullWidth margin="normal">
+                <InputLabel id="wordlist-collection-label">Wordlist Collection</InputLabel>
+                <Select
+                  labelId="wordlist-collection-label"
+                  value={formData.wordlist_collection_id ?? ''}
+                  onChange={(e) => {
+                    const value = e.target.value as number | '';
+                    setFormData(prev => ({
+                      ...prev,
+                      wordlist_collection_id: value === '' ? null : value,
+                      wordlist_ids: value === '' ? prev.wordlist_ids : []
+                    }));
+                  }}
+                  input={<OutlinedInput label="Wordlist Collection" />}
+                >
+                  <MenuItem value="">
+                    <em>None, use a single wordlist</em>
+                  </MenuItem>
+                  {collections.map((collection) => (
+                    <MenuItem key={collection.id} value={collection.id}>
+                      {collection.name} ({collection.wordlist_ids.length} wordlists)
+                    </MenuItem>
+                  ))}
+                </Select>
+                <FormHelperText>
+                  Creates one job per wordlist of the collection as it is when jobs are created from this preset
+                </FormHelperText>
+              </FormControl>
+            )}
             <FormControl 
               fullWidth 
               margin="normal" 
-              required={!isWordlistsDisabled} 
-              error={!isWordlistsDisabled && formData.wordlist_ids.length !== getMaxWordlists()}
-              disabled={isWordlistsDisabled}
+              required={!isWordlistsDisabled && !usesCollection} 
+              error={!isWordlistsDisabled && !usesCollection && formData.wordlist_ids.length !== getMaxWordlists()}
+              disabled={isWordlistsDisabled || usesCollection}
             >
               <InputLabel id="wordlist-label">Wordlists</InputLabel>
               <Select
@@ -796,6 +857,8 @@ const PresetJobFormPage: React.FC = () => {
               <FormHelperText>
                 {isWordlistsDisabled ? 
                   'Wordlists not used in this attack mode' : 
+                  usesCollection ?
+                  'Wordlists come from the selected collection' :
                   `Select ${getMaxWordlists()} wordlist${getMaxWordlists() !== 1 ? 's' : ''}`
                 }
               </FormHelperText>
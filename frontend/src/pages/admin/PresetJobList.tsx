@@ -190,6 +190,11 @@ const PresetJobListPage: React.FC = () => {
                 >
                   <TableCell component="th" scope="row">
                     {job.name}
+                    {job.needs_review && (
+                      <Tooltip title="A wordlist or rule this preset used was deleted. Edit and save the preset to clear this.">
+                        <Chip label="Needs review" size="small" color="warning" sx={{ ml: 1 }} />
+                      </Tooltip>
+                    )}
                   </TableCell>
                   <TableCell>{formatAttackMode(job.attack_mode)}</TableCell>
                   <TableCell>{job.priority}</TableCell>
@@ -233,7 +238,7 @@ const PresetJobListPage: React.FC = () => {
                     )}
                   </TableCell>
                   <TableCell>{job.binary_version_name || job.binary_version_id}</TableCell>
-                  <TableCell>{job.wordlist_ids?.length || 0}</TableCell>
+                  <TableCell>{job.wordlist_collection_id ? 'Collection' : job.wordlist_ids?.length || 0}</TableCell>
                   <TableCell>{job.rule_ids?.length || 0}</TableCell>
                   <TableCell>{new Date(job.created_at).toLocaleString()}</TableCell>
                   <TableCell align="right">
@@ -0,0 +1,236 @@
+import React, { useState, useEffect, useCallback } from 'react';
+import {
+  Box,
+  Typography,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+  TablePagination,
+  Paper,
+  CircularProgress,
+  Alert,
+  Chip,
+  TextField,
+  MenuItem,
+  IconButton,
+  Tooltip
+} from '@mui/material';
+import RefreshIcon from '@mui/icons-material/Refresh';
+import { listSystemEvents, SystemEvent, SystemEventSeverity, SystemEventQuery } from '../../services/systemEvents';
+
+const severityColors: Record<SystemEventSeverity, 'info' | 'warning' | 'error'> = {
+  info: 'info',
+  warning: 'warning',
+  error: 'error',
+};
+
+const categories = ['agent', 'sync', 'scheduler', 'benchmark'];
+
+const timeRanges = [
+  { value: '1h', label: 'Last hour' },
+  { value: '24h', label: 'Last 24 hours' },
+  { value: '7d', label: 'Last 7 days' },
+  { value: '30d', label: 'Last 30 days' },
+  { value: '', label: 'All time' },
+];
+
+const SystemEventLogPage: React.FC = () => {
+  const [events, setEvents] = useState<SystemEvent[]>([]);
+  const [total, setTotal] = useState(0);
+  const [page, setPage] = useState(0);
+  const [pageSize, setPageSize] = useState(50);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  const [severity, setSeverity] = useState<SystemEventSeverity | ''>('');
+  const [category, setCategory] = useState('');
+  const [since, setSince] = useState('24h');
+  const [search, setSearch] = useState('');
+  const [entityId, setEntityId] = useState('');
+
+  const fetchEvents = useCallback(async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const query: SystemEventQuery = {
+        page: page + 1,
+        page_size: pageSize,
+        severity: severity || undefined,
+        category,
+        since,
+        q: search,
+        entity_id: entityId,
+      };
+      const result = await listSystemEvents(query);
+      setEvents(result.data);
+      setTotal(result.pagination.total);
+    } catch (err) {
+      console.error('Error fetching system events:', err);
+      setError('Failed to load system events. Please try again.');
+    } finally {
+      setLoading(false);
+    }
+  }, [page, pageSize, severity, category, since, search, entityId]);
+
+  useEffect(() => {
+    fetchEvents();
+  }, [fetchEvents]);
+
+  // Filters start over from the first page
+  const onFilterChange = <T,>(setter: (value: T) => void) => (value: T) => {
+    setter(value);
+    setPage(0);
+  };
+
+  return (
+    <Box sx={{ p: 3 }}>
+      <Box sx={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center', mb: 2 }}>
+        <Box>
+          <Typography variant="h4" component="h1">
+            System Events
+          </Typography>
+          <Typography variant="body2" color="text.secondary">
+            Agent connections, file syncs, scheduler problems and benchmark timeouts
+          </Typography>
+        </Box>
+        <Tooltip title="Refresh">
+          <IconButton onClick={fetchEvents}>
+            <RefreshIcon />
+          </IconButton>
+        </Tooltip>
+      </Box>
+
+      <Box sx={{ display: 'flex', gap: 2, flexWrap: 'wrap', mb: 2 }}>
+        <TextField
+          select
+          size="small"
+          label="Severity"
+          value={severity}
+          onChange={(e) => onFilterChange(setSeverity)(e.target.value as SystemEventSeverity | '')}
+          sx={{ minWidth: 140 }}
+        >
+          <MenuItem value="">All</MenuItem>
+          <MenuItem value="info">Info</MenuItem>
+          <MenuItem value="warning">Warning</MenuItem>
+          <MenuItem value="error">Error</MenuItem>
+        </TextField>
+        <TextField
+          select
+          size="small"
+          label="Category"
+          value={category}
+          onChange={(e) => onFilterChange(setCategory)(e.target.value)}
+          sx={{ minWidth: 140 }}
+        >
+          <MenuItem value="">All</MenuItem>
+          {categories.map((c) => (
+            <MenuItem key={c} value={c}>{c}</MenuItem>
+          ))}
+        </TextField>
+        <TextField
+          select
+          size="small"
+          label="Time range"
+          value={since}
+          onChange={(e) => onFilterChange(setSince)(e.target.value)}
+          sx={{ minWidth: 160 }}
+        >
+          {timeRanges.map((r) => (
+            <MenuItem key={r.value} value={r.value}>{r.label}</MenuItem>
+          ))}
+        </TextField>
+        <TextField
+          size="small"
+          label="Agent ID"
+          value={entityId}
+          onChange={(e) => onFilterChange(setEntityId)(e.target.value)}
+          sx={{ width: 120 }}
+        />
+        <TextField
+          size="small"
+          label="Search messages"
+          value={search}
+          onChange={(e) => onFilterChange(setSearch)(e.target.value)}
+          sx={{ flexGrow: 1, minWidth: 200 }}
+        />
+      </Box>
+
+      {error && (
+        <Alert severity="error" sx={{ mb: 2 }}>
+          {error}
+        </Alert>
+      )}
+
+      <TableContainer component={Paper}>
+        <Table size="small">
+          <TableHead>
+            <TableRow>
+              <TableCell>Time</TableCell>
+              <TableCell>Severity</TableCell>
+              <TableCell>Category</TableCell>
+              <TableCell>Event</TableCell>
+              <TableCell>Entity</TableCell>
+              <TableCell>Message</TableCell>
+            </TableRow>
+          </TableHead>
+          <TableBody>
+            {loading ? (
+              <TableRow>
+                <TableCell colSpan={6} align="center">
+                  <CircularProgress size={24} />
+                </TableCell>
+              </TableRow>
+            ) : events.length === 0 ? (
+              <TableRow>
+                <TableCell colSpan={6} align="center">
+                  No events match the filters
+                </TableCell>
+              </TableRow>
+            ) : (
+              events.map((event) => (
+                <TableRow key={event.id} hover>
+                  <TableCell sx={{ whiteSpace: 'nowrap' }}>
+                    {new Date(event.occurred_at).toLocaleString()}
+                  </TableCell>
+                  <TableCell>
+                    <Chip label={event.severity} color={severityColors[event.severity]} size="small" />
+                  </TableCell>
+                  <TableCell>{event.category}</TableCell>
+                  <TableCell>{event.event_type}</TableCell>
+                  <TableCell>
+                    {event.entity_type ? `${event.entity_type} ${event.entity_id}` : '-'}
+                  </TableCell>
+                  <TableCell>
+                    <Tooltip title={event.details ? JSON.stringify(event.details) : ''}>
+                      <span>{event.message}</span>
+                    </Tooltip>
+                  </TableCell>
+                </TableRow>
+              ))
+            )}
+          </TableBody>
+        </Table>
+      </TableContainer>
+
+      <TablePagination
+        rowsPerPageOptions={[25, 50, 100, 200]}
+        component="div"
+        count={total}
+        rowsPerPage={pageSize}
+        page={page}
+        onPageChange={(_, newPage) => setPage(newPage)}
+        onRowsPerPageChange={(e) => {
+          setPageSize(parseInt(e.target.value, 10));
+          setPage(0);
+        }}
+        showFirstButton
+        showLastButton
+      />
+    </Box>
+  );
+};
+
+export default SystemEventLogPage;
@@ -0,0 +1,282 @@
+import React, { useState, useEffect } from 'react';
+import {
+  Box,
+  Typography,
+  Button,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+  Paper,
+  IconButton,
+  CircularProgress,
+  Alert,
+  Tooltip,
+  Chip,
+  Dialog,
+  DialogTitle,
+  DialogContent,
+  DialogActions,
+  TextField,
+  Autocomplete
+} from '@mui/material';
+import AddIcon from '@mui/icons-material/Add';
+import EditIcon from '@mui/icons-material/Edit';
+import DeleteIcon from '@mui/icons-material/Delete';
+import {
+  listWordlistCollections,
+  createWordlistCollection,
+  updateWordlistCollection,
+  deleteWordlistCollection,
+  getPresetJobFormData
+} from '../../services/api';
+import { WordlistBasic, WordlistCollection } from '../../types/adminJobs';
+import { useConfirm } from '../../hooks';
+
+interface CollectionForm {
+  name: string;
+  description: string;
+  wordlists: WordlistBasic[];
+}
+
+const emptyForm: CollectionForm = { name: '', description: '', wordlists: [] };
+
+const WordlistCollectionListPage: React.FC = () => {
+  const [collections, setCollections] = useState<WordlistCollection[]>([]);
+  const [wordlists, setWordlists] = useState<WordlistBasic[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [deleteInProgress, setDeleteInProgress] = useState(false);
+
+  // Create/edit dialog, editing holds the collection being edited
+  const [dialogOpen, setDialogOpen] = useState(false);
+  const [editing, setEditing] = useState<WordlistCollection | null>(null);
+  const [form, setForm] = useState<CollectionForm>(emptyForm);
+  const [formError, setFormError] = useState<string | null>(null);
+  const [saving, setSaving] = useState(false);
+
+  const { ConfirmDialog, showConfirm } = useConfirm();
+
+  const fetchCollections = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const [data, formData] = await Promise.all([listWordlistCollections(), getPresetJobFormData()]);
+      setCollections(data);
+      setWordlists(formData.wordlists || []);
+    } catch (err) {
+      console.error('Error fetching wordlist collections:', err);
+      setError('Failed to load wordlist collections. Please try again.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    fetchCollections();
+  }, []);
+
+  const openDialog = (collection: WordlistCollection | null) => {
+    setEditing(collection);
+    setForm(collection
+      ? { name: collection.name, description: collection.description, wordlists: collection.wordlists || [] }
+      : emptyForm);
+    setFormError(null);
+    setDialogOpen(true);
+  };
+
+  const handleSave = async () => {
+    if (!form.name.trim()) {
+      setFormError('Name is required');
+      return;
+    }
+    if (form.wordlists.length === 0) {
+      setFormError('Select at least one wordlist');
+      return;
+    }
+
+    const data = {
+      name: form.name,
+      description: form.description,
+      wordlist_ids: form.wordlists.map(wordlist => wordlist.id)
+    };
+    try {
+      setSaving(true);
+      setFormError(null);
+      if (editing) {
+        await updateWordlistCollection(editing.id, data);
+      } else {
+        await createWordlistCollection(data);
+      }
+      setDialogOpen(false);
+      fetchCollections();
+    } catch (err: any) {
+      console.error('Error saving wordlist collection:', err);
+      setFormError(err.response?.data?.error || 'Failed to save wordlist collection.');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleDelete = async (collection: WordlistCollection) => {
+    const confirmed = await showConfirm(
+      'Delete Wordlist Collection',
+      `Are you sure you want to delete the collection "${collection.name}"? Collections used by preset jobs cannot be deleted.`
+    );
+    if (!confirmed) return;
+
+    try {
+      setDeleteInProgress(true);
+      await deleteWordlistCollection(collection.id);
+      setCollections(prev => prev.filter(c => c.id !== collection.id));
+    } catch (err: any) {
+      console.error('Error deleting wordlist collection:', err);
+      setError(err.response?.data?.error || 'Failed to delete wordlist collection. Please try again.');
+    } finally {
+      setDeleteInProgress(false);
+    }
+  };
+
+  return (
+    <Box sx={{ p: 3 }}>
+      <ConfirmDialog />
+
+      <Box display="flex" justifyContent="space-between" alignItems="center" mb={3}>
+        <Box>
+          <Typography variant="h4" gutterBottom>
+            Wordlist Collections
+          </Typography>
+          <Typography variant="body2" color="text.secondary">
+            Preset jobs using a collection create one job per wordlist, from the collection as it is when the jobs are created.
+          </Typography>
+        </Box>
+
+        <Button
+          variant="contained"
+          color="primary"
+          startIcon={<AddIcon />}
+          onClick={() => openDialog(null)}
+          disabled={loading || deleteInProgress}
+        >
+          Create Collection
+        </Button>
+      </Box>
+
+      {error && (
+        <Alert severity="error" sx={{ mb: 3 }} onClose={() => setError(null)}>
+          {error}
+        </Alert>
+      )}
+
+      {loading ? (
+        <Box display="flex" justifyContent="center" p={3}>
+          <CircularProgress />
+        </Box>
+      ) : (
+        <TableContainer component={Paper}>
+          <Table>
+            <TableHead>
+              <TableRow>
+                <TableCell>Name</TableCell>
+                <TableCell>Description</TableCell>
+                <TableCell>Wordlists</TableCell>
+                <TableCell>Last Updated</TableCell>
+                <TableCell align="right">Actions</TableCell>
+              </TableRow>
+            </TableHead>
+            <TableBody>
+              {collections.length === 0 ? (
+                <TableRow>
+                  <TableCell colSpan={5} align="center">
+                    <Typography variant="body1" py={2}>
+                      No wordlist collections found. Create a collection to reference several wordlists from one preset job.
+                    </Typography>
+                  </TableCell>
+                </TableRow>
+              ) : (
+                collections.map((collection) => (
+                  <TableRow key={collection.id}>
+                    <TableCell>{collection.name}</TableCell>
+                    <TableCell>{collection.description}</TableCell>
+                    <TableCell>
+                      <Box sx={{ display: 'flex', flexWrap: 'wrap', gap: 0.5 }}>
+                        {(collection.wordlists || []).map(wordlist => (
+                          <Chip key={wordlist.id} label={wordlist.name} size="small" />
+                        ))}
+                      </Box>
+                    </TableCell>
+                    <TableCell>{collection.updated_at ? new Date(collection.updated_at).toLocaleString() : ''}</TableCell>
+                    <TableCell align="right">
+                      <Tooltip title="Edit">
+                        <IconButton onClick={() => openDialog(collection)} disabled={deleteInProgress}>
+                          <EditIcon />
+                        </IconButton>
+                      </Tooltip>
+                      <Tooltip title="Delete">
+                        <IconButton onClick={() => handleDelete(collection)} disabled={deleteInProgress}>
+                          <DeleteIcon />
+                        </IconButton>
+                      </Tooltip>
+                    </TableCell>
+                  </TableRow>
+                ))
+              )}
+            </TableBody>
+          </Table>
+        </TableContainer>
+      )}
+
+      <Dialog open={dialogOpen} onClose={() => setDialogOpen(false)} maxWidth="sm" fullWidth>
+        <DialogTitle>{editing ? 'Edit Wordlist Collection' : 'Create Wordlist Collection'}</DialogTitle>
+        <DialogContent>
+          {formError && <Alert severity="error" sx={{ mb: 2 }}>{formError}</Alert>}
+          <TextField
+            autoFocus
+            margin="dense"
+            label="Name"
+            fullWidth
+            required
+            value={form.name}
+            onChange={(e) => setForm(prev => ({ ...prev, name: e.target.value }))}
+            placeholder="standard-big"
+          />
+          <TextField
+            margin="dense"
+            label="Description"
+            fullWidth
+            multiline
+            rows={2}
+            value={form.description}
+            onChange={(e) => setForm(prev => ({ ...prev, description: e.target.value }))}
+          />
+          <Autocomplete
+            multiple
+            options={wordlists}
+            getOptionLabel={(wordlist) => wordlist.name}
+            isOptionEqualToValue={(option, value) => option.id === value.id}
+            value={form.wordlists}
+            onChange={(_, value) => setForm(prev => ({ ...prev, wordlists: value }))}
+            renderInput={(params) => (
+              <TextField
+                {...params}
+                margin="dense"
+                label="Wordlists"
+                helperText="Jobs are created in this order"
+              />
+            )}
+          />
+        </DialogContent>
+        <DialogActions>
+          <Button onClick={() => setDialogOpen(false)} disabled={saving}>Cancel</Button>
+          <Button onClick={handleSave} variant="contained" disabled={saving}>
+            {saving ? <CircularProgress size={24} /> : 'Save'}
+          </Button>
+        </DialogActions>
+      </Dialog>
+    </Box>
+  );
+};
+
+export default WordlistCollectionListPage;
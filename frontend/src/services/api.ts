@@ -3,7 +3,7 @@
  */
 import axios from 'axios';
 import type { AxiosError } from 'axios';
-import { Client } from '../types/client'; // Moved import to top
+import { Client, ClientCostReport } from '../types/client'; // Moved import to top
 import { User, UserUpdateRequest, DisableUserRequest, ResetPasswordRequest, UserListResponse, UserDetailResponse, LoginAttemptsResponse, ActiveSessionsResponse, TerminateSessionResponse, TerminateAllSessionsResponse } from '../types/user';
 import { transformUserResponse, transformUserListResponse, transformLoginAttempt, transformActiveSession } from '../utils/userTransform';
 import {
@@ -15,9 +15,11 @@ import {
   PresetJobInput,
   PresetJobApiData,
   JobWorkflowFormDataResponse,
+  WordlistCollection,
 } from '../types/adminJobs';
 import { AgentSchedule, AgentScheduleDTO, AgentSchedulingInfo } from '../types/scheduling';
-import { AgentWithTask } from '../types/agent';
+import { AgentWithTask, AgentManagedConfig, AgentFileAudit } from '../types/agent';
+import { Annotations, SearchResult, SavedView, SavedViewRequest, SavedViewResource, JobCampaign } from '../types/jobs';
 
 // Use relative URLs for API endpoints to work through nginx proxy
 // This allows the application to work regardless of hostname/IP
@@ -302,6 +304,10 @@ export const updateClient = (id: string, clientData: Partial<Omit<Client, 'id' |
 // Delete a client
 export const deleteClient = (id: string) => api.delete<any>(`/api/clients/${id}`);
 
+// Get the GPU-hours and estimated cost of a client's jobs, optionally within a time range (RFC 3339)
+export const getClientCost = (id: string, from?: string, to?: string) =>
+  api.get<{data: ClientCostReport}>(`/api/clients/${id}/cost`, { params: { from, to } });
+
 // Legacy admin client APIs (deprecated - use the above instead)
 export const listAdminClients = listClients;
 export const getAdminClient = getClient;
@@ -470,6 +476,29 @@ export const deletePresetJob = async (id: string): Promise<void> => {
   await api.delete(`/api/admin/preset-jobs/${id}`);
 };
 
+// --- Admin: Wordlist Collections ---
+
+export type WordlistCollectionInput = Pick<WordlistCollection, 'name' | 'description' | 'wordlist_ids'>;
+
+export const listWordlistCollections = async (): Promise<WordlistCollection[]> => {
+  const response = await api.get<WordlistCollection[]>('/api/admin/wordlist-collections');
+  return response.data;
+};
+
+export const createWordlistCollection = async (data: WordlistCollectionInput): Promise<WordlistCollection> => {
+  const response = await api.post<WordlistCollection>('/api/admin/wordlist-collections', data);
+  return response.data;
+};
+
+export const updateWordlistCollection = async (id: number, data: WordlistCollectionInput): Promise<WordlistCollection> => {
+  const response = await api.put<WordlistCollection>(`/api/admin/wordlist-collections/${id}`, data);
+  return response.data;
+};
+
+export const deleteWordlistCollection = async (id: number): Promise<void> => {
+  await api.delete(`/api/admin/wordlist-collections/${id}`);
+};
+
 // --- Admin: Job Workflows ---
 
 export const listJobWorkflows = async (): Promise<JobWorkflow[]> => {
@@ -540,6 +569,31 @@ export const toggleAgentScheduling = async (agentId: number, enabled: boolean, t
   return response.data;
 };
 
+// Replace the settings pushed to an agent, connected agents apply them at once
+export const updateAgentManagedConfig = async (agentId: number, config: AgentManagedConfig): Promise<{ id: number; managed_config: AgentManagedConfig }> => {
+  const response = await api.put<{ id: number; managed_config: AgentManagedConfig }>(`/api/admin/agents/${agentId}/config`, config);
+  return response.data;
+};
+
+// Make an agent rehash its cached files, drifted files are corrected once it reports
+export const startAgentFileAudit = async (agentId: number): Promise<AgentFileAudit> => {
+  const response = await api.post<AgentFileAudit>(`/api/admin/agents/${agentId}/file-audit`);
+  return response.data;
+};
+
+// Get the latest file audit of an agent, null if it was never audited
+export const getAgentFileAudit = async (agentId: number): Promise<AgentFileAudit | null> => {
+  try {
+    const response = await api.get<AgentFileAudit>(`/api/admin/agents/${agentId}/file-audit`);
+    return response.data;
+  } catch (err: any) {
+    if (err.response?.status === 404) {
+      return null;
+    }
+    throw err;
+  }
+};
+
 // --- Job Details ---
 
 // Get detailed job information including tasks
@@ -551,6 +605,74 @@ export const getJobDetails = async (id: string): Promise<any> => {
   return response.data;
 };
 
+// Get the GPU-hours and estimated cost of a job per agent device
+export const getJobCost = async (id: string): Promise<any> => {
+  const url = `/api/jobs/${id}/cost`;
+  logApiCall('GET', url);
+  const response = await api.get(url);
+  logApiResponse('GET', url, response.data);
+  return response.data;
+};
+
+// Get the combined progress and results of a campaign's jobs
+export const getCampaign = async (id: string): Promise<JobCampaign> => {
+  const url = `/api/jobs/campaigns/${id}`;
+  logApiCall('GET', url);
+  const response = await api.get<JobCampaign>(url);
+  logApiResponse('GET', url, response.data);
+  return response.data;
+};
+
+// Replace the notes and tags of a job
+export const updateJobAnnotations = async (id: string, annotations: Annotations): Promise<Annotations> => {
+  const response = await api.put<Annotations>(`/api/jobs/${id}/annotations`, annotations);
+  return response.data;
+};
+
+// Rename a job; names are unique within the hashlist's client
+export const renameJob = async (id: string, name: string): Promise<{ id: string; name: string }> => {
+  const response = await api.put<{ id: string; name: string }>(`/api/jobs/${id}/name`, { name });
+  return response.data;
+};
+
+// Replace the notes and tags of a hashlist
+export const updateHashlistAnnotations = async (id: number, annotations: Annotations): Promise<Annotations> => {
+  const response = await api.put<Annotations>(`/api/hashlists/${id}/annotations`, annotations);
+  return response.data;
+};
+
+// Search jobs and hashlists by name, notes and tags
+export const searchJobsAndHashlists = async (
+  query: string,
+  tags: string[] = [],
+  type?: 'job' | 'hashlist'
+): Promise<SearchResult[]> => {
+  const response = await api.get<{ results: SearchResult[]; count: number }>('/api/search', {
+    params: { q: query || undefined, tags: tags.length ? tags.join(',') : undefined, type },
+  });
+  return response.data.results;
+};
+
+// Saved views of the job, task and hash lists visible to the current user
+export const listSavedViews = async (resource?: SavedViewResource): Promise<SavedView[]> => {
+  const response = await api.get<{ views: SavedView[] }>('/api/views', { params: { resource } });
+  return response.data.views;
+};
+
+export const createSavedView = async (view: SavedViewRequest): Promise<SavedView> => {
+  const response = await api.post<SavedView>('/api/views', view);
+  return response.data;
+};
+
+export const updateSavedView = async (id: string, view: SavedViewRequest): Promise<SavedView> => {
+  const response = await api.put<SavedView>(`/api/views/${id}`, view);
+  return response.data;
+};
+
+export const deleteSavedView = async (id: string): Promise<void> => {
+  await api.delete(`/api/views/${id}`);
+};
+
 // --- SSE Integration ---
 
 // Get the SSE endpoint URL for job streaming
@@ -52,4 +52,58 @@ export const getBinary = (id: number) => {
 
 export const setDefaultBinary = (id: number) => {
   return api.put<{ message: string }>(`/api/admin/binary/${id}/set-default`);
-}; 
\ No newline at end of file
+}; 
+export interface BinaryRolloutMetrics {
+  canary_tasks: number;
+  canary_failures: number;
+  baseline_tasks: number;
+  baseline_failures: number;
+  speed_profiles: number;
+  speed_ratio: number;
+  evaluated_at: string;
+}
+
+export interface BinaryRollout {
+  id: number;
+  binary_version_id: number;
+  previous_version_id: number;
+  canary_label: string;
+  observation_hours: number;
+  min_tasks: number;
+  max_failure_rate_increase: number;
+  max_speed_regression: number;
+  status: 'canary' | 'promoted' | 'rolled_back';
+  metrics?: BinaryRolloutMetrics;
+  decision_reason?: string;
+  decided_automatically: boolean;
+  started_at: string;
+  decided_at?: string;
+  binary_version_name?: string;
+  previous_version_name?: string;
+  canary_agents: number;
+}
+
+export interface StartRolloutRequest {
+  binary_version_id: number;
+  canary_label: string;
+  observation_hours?: number;
+  min_tasks?: number;
+  max_failure_rate_increase?: number;
+  max_speed_regression?: number;
+}
+
+export const listBinaryRollouts = () => {
+  return api.get<BinaryRollout[]>('/api/admin/binary-rollouts');
+};
+
+export const startBinaryRollout = (request: StartRolloutRequest) => {
+  return api.post<BinaryRollout>('/api/admin/binary-rollouts', request);
+};
+
+export const promoteBinaryRollout = (id: number, reason?: string) => {
+  return api.post<BinaryRollout>(`/api/admin/binary-rollouts/${id}/promote`, { reason });
+};
+
+export const rollBackBinaryRollout = (id: number, reason?: string) => {
+  return api.post<BinaryRollout>(`/api/admin/binary-rollouts/${id}/rollback`, { reason });
+};
@@ -14,6 +14,11 @@ export interface JobExecutionSettings {
   max_chunk_retry_attempts: number;
   jobs_per_page_default: number;
   reconnect_grace_period_minutes: number;
+  // Task staleness settings
+  task_heartbeat_timeout_minutes: number;
+  slow_hash_task_heartbeat_timeout_minutes: number;
+  task_heartbeat_max_grace_doublings: number;
+  agent_heartbeat_timeout_seconds: number;
   // Rule splitting settings
   rule_split_enabled: boolean;
   rule_split_threshold: number;
@@ -22,6 +27,18 @@ export interface JobExecutionSettings {
   rule_chunk_temp_dir: string;
   // Potfile settings
   potfile_enabled: boolean;
+  // Job naming settings
+  job_name_template: string;
+  // Default hashcat arguments rules
+  default_hashcat_args: DefaultHashcatArgsRule[];
+}
+
+// Adds hashcat arguments to every job matching all of its set conditions
+export interface DefaultHashcatArgsRule {
+  attack_mode?: number;
+  hash_class?: '' | 'fast' | 'slow';
+  min_rules?: number;
+  args: string;
 }
 
 export const getJobExecutionSettings = async (): Promise<JobExecutionSettings> => {
@@ -0,0 +1,43 @@
+import { api } from './api';
+
+export interface MaintenanceWindow {
+  id: number;
+  message: string;
+  drain_timeout_seconds: number;
+  started_at: string;
+  started_by?: string;
+  started_by_username?: string;
+  force_stopped_at?: string;
+  stopped_tasks?: number;
+  ended_at?: string;
+  ended_by?: string;
+  ended_by_username?: string;
+}
+
+export interface MaintenanceStatus {
+  active: boolean;
+  window?: MaintenanceWindow;
+}
+
+export const getMaintenanceStatus = async (): Promise<MaintenanceStatus> => {
+  const response = await api.get('/api/maintenance');
+  return response.data;
+};
+
+export const listMaintenanceWindows = async (): Promise<MaintenanceWindow[]> => {
+  const response = await api.get('/api/admin/maintenance');
+  return response.data.windows;
+};
+
+export const startMaintenance = async (message: string, drainTimeoutSeconds: number): Promise<MaintenanceWindow> => {
+  const response = await api.post('/api/admin/maintenance', {
+    message,
+    drain_timeout_seconds: drainTimeoutSeconds,
+  });
+  return response.data;
+};
+
+export const endMaintenance = async (): Promise<MaintenanceWindow> => {
+  const response = await api.delete('/api/admin/maintenance');
+  return response.data;
+};
@@ -3,6 +3,7 @@
  */
 import { api } from './api';
 import { Rule, RuleFilters, RuleUploadResponse } from '../types/rules';
+import { DirectoryMoveResponse, FileReferences, ResourceDirectory } from '../types/wordlists';
 
 // Get all rules with optional filtering
 export const getRules = (filters?: RuleFilters) => 
@@ -60,9 +61,13 @@ export const updateRule = (id: string, data: Partial<Rule>) =>
     withCredentials: true // Ensure cookies are sent with the request
   });
 
-// Delete a rule
-export const deleteRule = (id: string) => 
-  api.delete(`/api/rules/${id}`);
+// Delete a rule, force also removes it from the preset jobs using it
+export const deleteRule = (id: string, force = false) => 
+  api.delete(`/api/rules/${id}`, { params: force ? { force: true } : undefined });
+
+// Get the preset jobs and active jobs using a rule
+export const getRuleReferences = (id: string) =>
+  api.get<FileReferences>(`/api/rules/${id}/references`);
 
 // Enable/disable a rule
 export const toggleRuleStatus = (id: string, isEnabled: boolean) => 
@@ -77,4 +82,20 @@ export const verifyRule = (id: string, status: 'verified' | 'failed' | 'pending'
   api.post(`/api/rules/${id}/verify`, { 
     status, 
     rule_count: ruleCount 
-  }, { withCredentials: true }); 
\ No newline at end of file
+  }, { withCredentials: true });
+
+// Move a rule into another directory
+export const moveRule = (id: string, directory: string) =>
+  api.post<Rule>(`/api/rules/${id}/move`, { directory }, { withCredentials: true });
+
+// List the rule directories
+export const getRuleDirectories = () =>
+  api.get<ResourceDirectory[]>('/api/rules/directories');
+
+// Create a rule directory
+export const createRuleDirectory = (path: string) =>
+  api.post<ResourceDirectory>('/api/rules/directories', { path }, { withCredentials: true });
+
+// Rename or move a rule directory with its contents
+export const moveRuleDirectory = (from: string, to: string) =>
+  api.put<DirectoryMoveResponse>('/api/rules/directories', { from, to }, { withCredentials: true });
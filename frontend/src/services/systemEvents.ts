@@ -0,0 +1,44 @@
+import { api } from './api';
+
+export type SystemEventSeverity = 'info' | 'warning' | 'error';
+
+export interface SystemEvent {
+  id: number;
+  occurred_at: string;
+  severity: SystemEventSeverity;
+  category: string;
+  event_type: string;
+  message: string;
+  entity_type?: string;
+  entity_id?: string;
+  details?: Record<string, unknown>;
+}
+
+export interface SystemEventQuery {
+  page?: number;
+  page_size?: number;
+  severity?: SystemEventSeverity;
+  category?: string;
+  event_type?: string;
+  entity_type?: string;
+  entity_id?: string;
+  q?: string;
+  since?: string;
+  until?: string;
+}
+
+export interface SystemEventPage {
+  data: SystemEvent[];
+  pagination: {
+    page: number;
+    page_size: number;
+    total: number;
+    total_pages: number;
+  };
+}
+
+export const listSystemEvents = async (query: SystemEventQuery): Promise<SystemEventPage> => {
+  const params = Object.fromEntries(Object.entries(query).filter(([, value]) => value !== undefined && value !== ''));
+  const response = await api.get('/api/admin/system-events', { params });
+  return response.data;
+};
@@ -2,7 +2,7 @@
  * API services for wordlist management
  */
 import { api } from './api';
-import { Wordlist, WordlistFilters, WordlistUploadResponse } from '../types/wordlists';
+import { DirectoryMoveResponse, FileReferences, ResourceDirectory, Wordlist, WordlistFilters, WordlistUploadResponse } from '../types/wordlists';
 
 // Get all wordlists with optional filtering
 export const getWordlists = (filters?: WordlistFilters) => 
@@ -60,9 +60,13 @@ export const updateWordlist = (id: string, data: Partial<Wordlist>) =>
     withCredentials: true // Ensure cookies are sent with the request
   });
 
-// Delete a wordlist
-export const deleteWordlist = (id: string) => 
-  api.delete(`/api/wordlists/${id}`, { withCredentials: true });
+// Delete a wordlist, force also removes it from the preset jobs using it
+export const deleteWordlist = (id: string, force = false) => 
+  api.delete(`/api/wordlists/${id}`, { params: force ? { force: true } : undefined, withCredentials: true });
+
+// Get the preset jobs and active jobs using a wordlist
+export const getWordlistReferences = (id: string) =>
+  api.get<FileReferences>(`/api/wordlists/${id}/references`);
 
 // Verify a wordlist
 export const verifyWordlist = (id: string, status: 'verified' | 'failed' | 'pending', wordCount?: number) => 
@@ -81,4 +85,20 @@ export const downloadWordlist = (id: string) =>
 
 // Refresh wordlist metadata (MD5, word count, file size)
 export const refreshWordlist = (id: string) =>
-  api.post(`/api/wordlists/${id}/refresh`, {}, { withCredentials: true }); 
\ No newline at end of file
+  api.post(`/api/wordlists/${id}/refresh`, {}, { withCredentials: true });
+
+// Move a wordlist into another directory
+export const moveWordlist = (id: string, directory: string) =>
+  api.post<Wordlist>(`/api/wordlists/${id}/move`, { directory }, { withCredentials: true });
+
+// List the wordlist directories
+export const getWordlistDirectories = () =>
+  api.get<ResourceDirectory[]>('/api/wordlists/directories');
+
+// Create a wordlist directory
+export const createWordlistDirectory = (path: string) =>
+  api.post<ResourceDirectory>('/api/wordlists/directories', { path }, { withCredentials: true });
+
+// Rename or move a wordlist directory with its contents
+export const moveWordlistDirectory = (from: string, to: string) =>
+  api.put<DirectoryMoveResponse>('/api/wordlists/directories', { from, to }, { withCredentials: true });
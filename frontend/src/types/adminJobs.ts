@@ -30,6 +30,7 @@ export interface PresetJob {
   id: string; // uuid.UUID
   name: string;
   wordlist_ids: string[]; // UUIDs as strings to match backend
+  wordlist_collection_id?: number | null; // Expanded into one job per wordlist, used instead of wordlist_ids
   rule_ids: string[]; // UUIDs as strings to match backend
   attack_mode: AttackMode;
   priority: number;
@@ -41,26 +42,38 @@ export interface PresetJob {
   updated_at: string; // ISO 8601 date string
   binary_version_name?: string; // Optional, from JOIN
   mask?: string; // Mask pattern for mask-based attack modes
+  mask_increment?: MaskIncrement | null; // --increment bounds of a brute force mask
   keyspace?: number | null; // Pre-calculated keyspace
   max_agents: number; // Max agents allowed (0 = unlimited)
+  needs_review: boolean; // A wordlist or rule was force-deleted from the preset
+}
+
+// Corresponds to models.MaskIncrement: hashcat runs the mask once for each
+// length from min to max positions
+export interface MaskIncrement {
+  min: number;
+  max: number; // 0 runs up to the full mask length
+  keyspaces?: number[]; // Keyspace of each length, calculated by the backend
 }
 
 // Internal form state type for use in the UI - keeps IDs as numbers
 export interface PresetJobFormData {
   name: string;
   wordlist_ids: number[]; // IDs as numbers for form handling
+  wordlist_collection_id: number | null;
   rule_ids: number[]; // IDs as numbers for form handling
   attack_mode: AttackMode;
   priority: number | string; // Allow string for empty placeholder state
   chunk_size_seconds: number;
   binary_version_id: number;
   mask?: string; // Mask pattern for mask-based attack modes
+  mask_increment?: MaskIncrement | null; // --increment bounds of a brute force mask
   allow_high_priority_override: boolean;
   max_agents: number;
 }
 
 // API type for create/update operations - using string UUIDs
-export type PresetJobApiData = Omit<PresetJob, 'id' | 'created_at' | 'updated_at' | 'binary_version_name' | 'status_updates_enabled'>;
+export type PresetJobApiData = Omit<PresetJob, 'id' | 'created_at' | 'updated_at' | 'binary_version_name' | 'status_updates_enabled' | 'needs_review'>;
 
 // Corresponds to models.JobWorkflow
 export interface JobWorkflow {
@@ -68,6 +81,8 @@ export interface JobWorkflow {
   name: string;
   created_at: string; // ISO 8601 date string
   updated_at: string; // ISO 8601 date string
+  inherit_priority: boolean; // Follow-up steps inherit the priority and max agents of the step before them
+  priority_boost: number; // Added to the inherited priority of each follow-up step
   steps?: JobWorkflowStep[]; // Optional, included in GetByID
   has_high_priority_override?: boolean; // True if any step has high priority override
 }
@@ -103,6 +118,8 @@ export interface JobWorkflowFormDataResponse {
 export interface JobWorkflowFormData {
   name: string;
   preset_job_ids: string[]; // Array of preset job UUIDs
+  inherit_priority: boolean;
+  priority_boost: number;
   orderedJobs: PresetJobBasic[]; // For UI to manage order
 }
 
@@ -110,14 +127,29 @@ export interface JobWorkflowFormData {
 export interface CreateWorkflowRequest {
   name: string;
   preset_job_ids: string[]; // Array of preset job UUIDs
+  inherit_priority: boolean;
+  priority_boost: number;
 }
 
 // Alias for update, same structure
 export type UpdateWorkflowRequest = CreateWorkflowRequest;
 
+// Corresponds to models.WordlistCollection, a named set of wordlists that
+// preset jobs expand into one job per wordlist when jobs are created
+export interface WordlistCollection {
+  id: number;
+  name: string;
+  description: string;
+  wordlist_ids: number[];
+  wordlists?: WordlistBasic[]; // Members that still exist, in collection order
+  created_at?: string;
+  updated_at?: string;
+}
+
 // Corresponds to repository.PresetJobFormData
 export interface PresetJobFormDataResponse {
   wordlists: WordlistBasic[];
+  wordlist_collections: WordlistCollection[] | null;
   rules: RuleBasic[];
   binary_versions: BinaryVersionBasic[];
 }
@@ -46,6 +46,63 @@ export interface Agent {
         current_job_id?: string;
         [key: string]: any;
     };
+    performance?: AgentPerformance;
+    managedConfig?: AgentManagedConfig;
+}
+
+/**
+ * Agent settings managed from the backend and pushed to the agent. An unset
+ * field uses the global default or the agent's own setting.
+ */
+export interface AgentManagedConfig {
+    heartbeat_interval_seconds?: number;
+    max_concurrent_downloads?: number;
+    file_retention_days?: number;
+}
+
+/** One file found to differ during an agent file audit */
+export interface AgentFileAuditItem {
+    file_type: string;
+    name: string;
+    expected_md5?: string;
+    actual_md5?: string;
+    size?: number;
+}
+
+/**
+ * Report of an integrity audit of an agent's data directory. The agent
+ * rehashes every cached file and the backend corrects whatever drifted.
+ */
+export interface AgentFileAudit {
+    request_id: string;
+    agent_id: number;
+    status: 'scanning' | 'completed' | 'failed';
+    started_at: string;
+    completed_at?: string;
+    files_checked: number;
+    mismatched: AgentFileAuditItem[] | null;
+    missing: AgentFileAuditItem[] | null;
+    extra: AgentFileAuditItem[] | null;
+    downloads: number;
+    deletions: number;
+    error?: string;
+}
+
+/**
+ * Compares an agent's recent task speeds with its benchmarks. A score of 1
+ * means the tasks ran at benchmark speed.
+ */
+export interface AgentPerformance {
+    score: number;
+    tasks: number;
+    underperforming: boolean;
+    devices?: {
+        deviceId: number;
+        deviceName: string;
+        score: number;
+        tasks: number;
+        underperforming: boolean;
+    }[];
 }
 
 /**
@@ -138,6 +195,8 @@ export interface AgentDevice {
     device_name: string;
     device_type: string;
     enabled: boolean;
+    memory_total_mb?: number;
+    memory_free_mb?: number;
     created_at: string;
     updated_at: string;
 }
@@ -156,6 +215,10 @@ export interface ClaimVoucher {
     created_by_id: string;
     is_continuous: boolean;
     is_active: boolean;
+    max_uses?: number;
+    use_count: number;
+    expires_at?: string;
+    labels: string[];
     created_at: string;
     updated_at: string;
     used_at?: {
@@ -166,4 +229,20 @@ export interface ClaimVoucher {
         Int64: number;
         Valid: boolean;
     };
-} 
\ No newline at end of file
+}
+
+export interface ClaimVoucherUsage {
+    id: number;
+    voucherCode: string;
+    agentId?: number;
+    agent?: {
+        id: number;
+        name: string;
+        status: string;
+        labels: string[];
+    };
+    attemptedAt: string;
+    success: boolean;
+    errorMessage?: string;
+}
+ 
\ No newline at end of file
@@ -13,6 +13,7 @@ export interface AnalyticsReport {
   total_cracked: number;
   queue_position?: number;
   custom_patterns: string[];
+  password_policy?: PasswordPolicy;
   created_at: string;
   started_at?: string;
   completed_at?: string;
@@ -34,6 +35,8 @@ export interface AnalyticsData {
   top_passwords: TopPassword[];
   recommendations: Recommendation[];
   domain_analytics?: DomainAnalytics[];
+  policy_compliance?: PolicyComplianceStats;
+  account_correlation?: AccountCorrelationStats;
 }
 
 export interface DomainAnalytics {
@@ -50,6 +53,7 @@ export interface DomainAnalytics {
   custom_patterns: CustomPatternStats;
   strength_metrics: StrengthStats;
   top_passwords: TopPassword[];
+  policy_compliance?: PolicyComplianceStats;
 }
 
 export interface OverviewStats {
@@ -138,6 +142,36 @@ export interface UserOccurrence {
   hashlist_count: number;
 }
 
+export interface AccountCorrelationStats {
+  shared_accounts: number;
+  reused_accounts: number;
+  accounts: AccountCorrelation[];
+  reuse_chains: ReuseChain[];
+}
+
+export interface AccountCorrelation {
+  username: string;
+  occurrences: AccountOccurrence[];
+  hashlist_count: number;
+  password_reused: boolean;
+}
+
+export interface AccountOccurrence {
+  hashlist_id: number;
+  hashlist_name: string;
+  username: string;
+  domain: string;
+  cracked: boolean;
+  password?: string;
+}
+
+export interface ReuseChain {
+  password: string;
+  accounts: AccountOccurrence[];
+  hashlist_count: number;
+  account_count: number;
+}
+
 export interface TemporalStats {
   contains_year: CategoryCount;
   contains_month: CategoryCount;
@@ -197,6 +231,25 @@ export interface TopPassword {
   percentage: number;
 }
 
+export interface PasswordPolicy {
+  min_length: number;
+  min_character_classes: number;
+  require_lowercase: boolean;
+  require_uppercase: boolean;
+  require_numbers: boolean;
+  require_special: boolean;
+  disallow_dictionary_words: boolean;
+  dictionary_words?: string[];
+  disallow_username: boolean;
+}
+
+export interface PolicyComplianceStats {
+  policy: PasswordPolicy;
+  compliant: CategoryCount;
+  non_compliant: CategoryCount;
+  violations: Record<string, CategoryCount>;
+}
+
 export interface Recommendation {
   severity: 'CRITICAL' | 'HIGH' | 'MEDIUM' | 'INFO';
   count: number;
@@ -209,6 +262,7 @@ export interface CreateAnalyticsReportRequest {
   start_date: string;
   end_date: string;
   custom_patterns?: string[];
+  password_policy?: PasswordPolicy;
 }
 
 export interface QueueStatus {
@@ -11,4 +11,52 @@ export interface Client {
   createdAt?: string; // Assuming ISO string format
   updatedAt?: string; // Assuming ISO string format
   cracked_count?: number; // Count of cracked hashes for this client
-} 
\ No newline at end of file
+  contacts?: ClientContact[];
+  engagementStatus?: ClientEngagementStatus; // Closed engagements block new jobs
+  engagementStartDate?: string; // ISO string, date only is significant
+  engagementEndDate?: string;
+  scopeNotes?: string;
+  closedAt?: string; // Set by the server when the engagement is closed
+}
+
+export type ClientEngagementStatus = 'active' | 'closed';
+
+/**
+ * A person to reach at a client.
+ */
+export interface ClientContact {
+  name: string;
+  role?: string;
+  email?: string;
+  phone?: string;
+  primary: boolean;
+}
+
+/**
+ * GPU time spent on one of a client's jobs.
+ */
+export interface JobGPUUsage {
+  job_execution_id?: string; // Missing once the job has been deleted
+  job_name: string;
+  gpu_seconds: number;
+  gpu_hours: number;
+  cost: number;
+  first_used_at: string;
+  last_used_at: string;
+}
+
+/**
+ * GPU-hours and estimated cost of a client's jobs.
+ */
+export interface ClientCostReport {
+  client_id: string;
+  client_name: string;
+  engagement_status?: ClientEngagementStatus;
+  from?: string;
+  to?: string;
+  gpu_hour_cost: number;
+  gpu_seconds: number;
+  gpu_hours: number;
+  cost: number;
+  jobs: JobGPUUsage[];
+}
@@ -7,6 +7,9 @@ export interface HashType {
   processing_logic?: string | null;
   is_enabled: boolean;
   slow: boolean;
+  aliases: string[];
+  validation_regex?: string | null;
+  is_custom: boolean;
 }
 
 export interface HashTypeCreateRequest {
@@ -16,6 +19,8 @@ export interface HashTypeCreateRequest {
   example?: string | null;
   is_enabled: boolean;
   slow: boolean;
+  aliases?: string[];
+  validation_regex?: string | null;
 }
 
 export interface HashTypeUpdateRequest {
@@ -24,4 +29,6 @@ export interface HashTypeUpdateRequest {
   example?: string | null;
   is_enabled: boolean;
   slow: boolean;
-}
\ No newline at end of file
+  aliases?: string[];
+  validation_regex?: string | null;
+}
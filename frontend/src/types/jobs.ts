@@ -3,7 +3,7 @@
  */
 
 // Job status enum
-export type JobStatus = 'pending' | 'running' | 'completed' | 'failed' | 'cancelled';
+export type JobStatus = 'scheduled' | 'pending' | 'running' | 'completed' | 'failed' | 'cancelled' | 'superseded';
 
 // Job summary for list views
 export interface JobSummary {
@@ -168,13 +168,177 @@ export interface JobDetailsResponse {
   mask?: string;
   binary_version_id?: number;
   chunk_size_seconds?: number;
+  skip_benchmark?: boolean;
+  benchmark_duration_seconds?: number | null;
+  is_background?: boolean;
+  pinned_agent_ids?: number[];
+  excluded_agent_ids?: number[];
+  allow_cloud_burst?: boolean;
+  remaining_hash_count?: number;
+  start_at?: string;
+  depends_on_job_id?: string;
+  parent_hashlist_id?: number;
+  split_group?: SplitGroupProgress;
   status_updates_enabled?: boolean;
   allow_high_priority_override?: boolean;
   additional_args?: string;
   hash_type?: string;
+  notes?: string;
+  tags?: string[];
+  failure_summary?: JobFailureSummary;
+  hybrid_keyspace?: HybridKeyspace;
+  campaign_id?: string;
+}
+
+// One job submission run against several hashlists of the same hash type
+export interface JobCampaign {
+  id: string;
+  name: string;
+  hash_type_id: number;
+  hashlist_ids: number[];
+  created_by?: string;
+  created_at: string;
+  completed_jobs: number;
+  overall_progress_percent: number;
+  campaign_cracked_hashes: number; // Cracked by the campaign's jobs
+  total_hashes: number;
+  cracked_hashes: number; // Cracked in the hashlists by any job
+  jobs: {
+    job_id: string;
+    name: string;
+    hashlist_id: number;
+    status: string;
+    overall_progress_percent: number;
+    cracked_hashes: number;
+  }[];
+  hashlists: {
+    hashlist_id: number;
+    name: string;
+    total_hashes: number;
+    cracked_hashes: number;
+  }[];
+}
+
+// One side of the candidates of a hybrid attack, a wordlist or a mask
+export interface HybridKeyspaceSide {
+  source: 'wordlist' | 'mask';
+  keyspace: number;
+}
+
+// Keyspace breakdown of a hybrid (-a 6/7) attack
+export interface HybridKeyspace {
+  left: HybridKeyspaceSide;
+  right: HybridKeyspaceSide;
+  candidates: number;
+}
+
+// The jobs running one attack against the sub-lists of a split hashlist
+export interface SplitGroupProgress {
+  group_id: string;
+  parts: number;
+  completed_parts: number;
+  overall_progress_percent: number;
+  cracked_hashes: number;
+  jobs: {
+    job_id: string;
+    hashlist_id: number;
+    part_number: number;
+    status: string;
+    overall_progress_percent: number;
+    cracked_hashes: number;
+  }[];
+}
+
+// Failed task runs of a job rolled up by cause
+export interface JobFailureSummary {
+  total_failures: number;
+  summary: string;
+  causes: JobFailureCause[];
+}
+
+export interface JobFailureCause {
+  error_code: string;
+  label: string;
+  count: number;
+  remediation_hint: string;
+  agents: { agent_id: number | null; agent_name: string; count: number }[];
+  example_message?: string;
+}
+
+// Free-form notes and tags of a job or hashlist
+export interface Annotations {
+  notes: string;
+  tags: string[];
+}
+
+// A job or hashlist found by GET /api/search
+export interface SearchResult {
+  type: 'job' | 'hashlist';
+  id: string;
+  name: string;
+  notes: string;
+  tags: string[];
+  status: string;
+  hashlist_id: number;
+  created_at: string;
+  rank: number;
+}
+
+// Lists a saved view can filter
+export type SavedViewResource = 'jobs' | 'tasks' | 'hashes';
+
+// Named filter and sort parameters of a list, private or shared with a team.
+// Pass ?view=<id> to the list endpoint to apply it.
+export interface SavedView {
+  id: string;
+  user_id: string;
+  owner_username: string;
+  team_id?: string;
+  team_name?: string;
+  name: string;
+  description: string;
+  resource: SavedViewResource;
+  query: Record<string, string>;
+  created_at: string;
+  updated_at: string;
+}
+
+// Body of POST /api/views and PUT /api/views/{id}
+export interface SavedViewRequest {
+  name: string;
+  description?: string;
+  resource: SavedViewResource;
+  query: Record<string, string>;
+  team_id?: string | null;
 }
 
 // Job detail response
 export interface JobDetailResponse {
   job: JobDetail;
-}
\ No newline at end of file
+}
+// Latest progress of a task, as streamed by GET /api/jobs/stream
+export interface JobProgressTask {
+  task_id: string;
+  agent_id?: number;
+  status: string;
+  keyspace_processed: number;
+  effective_progress: number;
+  progress_percent: number;
+  hash_rate: number;
+  time_remaining?: number;
+}
+
+// What changed in a job during the last second
+export interface JobProgressUpdate {
+  job_id: string;
+  status?: JobStatus;
+  cracked?: number;
+  tasks?: JobProgressTask[];
+}
+
+// A "progress" event of the job stream. When resync is set, updates were
+// dropped and the jobs should be fetched again.
+export interface JobProgressMessage {
+  jobs: JobProgressUpdate[];
+  resync?: boolean;
+}
@@ -30,6 +30,7 @@ export interface Rule {
   updated_by?: string;
   last_verified_at?: string;
   tags?: string[];
+  aliases?: string[];
   is_enabled: boolean;
 }
 
@@ -39,6 +40,7 @@ export interface RuleUploadResponse {
   message: string;
   success: boolean;
   duplicate?: boolean;
+  near_duplicate?: boolean;
 }
 
 export interface RuleFilters {
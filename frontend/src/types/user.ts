@@ -70,8 +70,12 @@ export interface ProfileUpdate {
   newPassword?: string;
 }
 
+// How often completed jobs, cracks and agent incidents are emailed as one digest
+export type NotificationDigest = 'off' | 'hourly' | 'daily';
+
 export interface NotificationPreferences {
   notifyOnJobCompletion: boolean;
+  notificationDigest: NotificationDigest;
   emailConfigured: boolean;
 }
 
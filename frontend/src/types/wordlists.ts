@@ -33,6 +33,7 @@ export interface Wordlist {
   updated_by?: string;
   last_verified_at?: string;
   tags?: string[];
+  aliases?: string[];
   is_enabled: boolean;
   is_potfile?: boolean;
 }
@@ -43,6 +44,18 @@ export interface WordlistUploadResponse {
   message: string;
   success: boolean;
   duplicate?: boolean;
+  near_duplicate?: boolean;
+  normalization?: EncodingNormalization;
+}
+
+export interface EncodingNormalization {
+  source_encoding: string;
+  bom_stripped: boolean;
+  lines: number;
+  changed_lines: number;
+  crlf_lines: number;
+  latin1_lines: number;
+  changed_line_numbers?: number[];
 }
 
 export interface WordlistFilters {
@@ -51,4 +64,28 @@ export interface WordlistFilters {
   verification_status?: WordlistStatus;
   sortBy?: string;
   sortOrder?: 'asc' | 'desc';
-} 
\ No newline at end of file
+} 
+
+// A directory below the wordlists or rules root
+export interface ResourceDirectory {
+  path: string;
+  file_count: number;
+}
+
+export interface DirectoryMoveResponse {
+  path: string;
+  files_moved: number;
+}
+
+// A preset job or job that uses a wordlist or rule
+export interface FileReference {
+  id: string;
+  name: string;
+  status?: string;
+}
+
+// What uses a wordlist or rule, from /api/wordlists/{id}/references or /api/rules/{id}/references
+export interface FileReferences {
+  preset_jobs: FileReference[];
+  jobs: FileReference[];
+}